@@ -0,0 +1,146 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package addressbookcmd
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	app *application.Lux
+
+	listJSON bool
+)
+
+// NewCmd creates the addressbook command
+func NewCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "addressbook",
+		Short: "Label addresses and refer to them as @label",
+		Long: `The addressbook command keeps human-readable labels of addresses in
+~/.lux/addressbook.json: P-Chain, X-Chain and C-Chain bech32 addresses, with
+their chain prefix, and 0x EVM addresses.
+
+Commands taking an address also take @label, e.g. the --recipient of
+'lux key transfer' or the --to of 'lux network send', and show the label next
+to the addresses they print. A bech32 address matches the label of the same
+address on another chain.
+
+EXAMPLES:
+
+  lux addressbook add treasury P-lux1...
+  lux addressbook add deployer 0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC
+  lux key transfer --from c --to p --amount 10 --key mykey --recipient @treasury
+  lux addressbook list
+  lux addressbook remove deployer`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.AddCommand(newAddCmd())
+	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newRemoveCmd())
+	return cmd
+}
+
+func newAddCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "add <label> <address>",
+		Short:        "Label an address",
+		Long:         `Label a P-, X- or C- prefixed bech32 address, or a 0x EVM address.`,
+		Args:         cobrautils.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			book, err := app.LoadAddressBook()
+			if err != nil {
+				return err
+			}
+			e, err := book.Add(args[0], args[1])
+			if err != nil {
+				return err
+			}
+			if err := book.Save(); err != nil {
+				return err
+			}
+			app.RecordHistory(history.Entry{
+				Operation: "addressbook add",
+				Params:    map[string]string{"label": e.Label, "address": e.Address},
+			})
+			ux.Logger.GreenCheckmarkToUser("Labeled %s address %s as @%s", e.Kind, e.Address, e.Label)
+			return nil
+		},
+	}
+}
+
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the labeled addresses",
+		Args:  cobrautils.ExactArgs(0),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			book, err := app.LoadAddressBook()
+			if err != nil {
+				return err
+			}
+			entries := book.Entries()
+			if listJSON {
+				b, err := json.MarshalIndent(entries, "", "  ")
+				if err != nil {
+					return err
+				}
+				ux.Logger.PrintToUser("%s", b)
+				return nil
+			}
+			if len(entries) == 0 {
+				ux.Logger.PrintToUser("No addresses in %s, add one with 'lux addressbook add <label> <address>'", book.Path())
+				return nil
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.Header("Label", "Chain", "Address", "Added")
+			for _, e := range entries {
+				_ = table.Append([]string{"@" + e.Label, string(e.Kind), e.Address, e.AddedAt.Local().Format(time.DateTime)})
+			}
+			_ = table.Render()
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&listJSON, "json", false, "print the entries as JSON")
+	return cmd
+}
+
+func newRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "remove <label>",
+		Short:        "Delete the label of an address",
+		Args:         cobrautils.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			book, err := app.LoadAddressBook()
+			if err != nil {
+				return err
+			}
+			e, _ := book.Get(args[0])
+			if err := book.Remove(args[0]); err != nil {
+				return err
+			}
+			if err := book.Save(); err != nil {
+				return err
+			}
+			app.RecordHistory(history.Entry{
+				Operation: "addressbook remove",
+				Params:    map[string]string{"label": e.Label, "address": e.Address},
+			})
+			ux.Logger.GreenCheckmarkToUser("Removed label @%s of %s", e.Label, e.Address)
+			return nil
+		},
+	}
+}
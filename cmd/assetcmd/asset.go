@@ -0,0 +1,209 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package assetcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/luxfi/address"
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/key"
+	"github.com/luxfi/cli/pkg/xchain"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+	"github.com/luxfi/sdk/wallet/chain/x/builder"
+	"github.com/luxfi/sdk/wallet/primary"
+	"github.com/luxfi/utxo/secp256k1fx"
+	"github.com/spf13/cobra"
+)
+
+// txTimeout bounds building, issuing and confirming an X-Chain tx
+const txTimeout = 2 * time.Minute
+
+var (
+	app *application.Lux
+
+	networkType string
+	rpcURL      string
+	keyName     string
+)
+
+// NewCmd creates the asset command
+func NewCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "asset",
+		Short: "Create, mint and send X-Chain assets",
+		Long: `The asset command manages assets on the X-Chain: fixed-cap and variable-cap
+fungible tokens and NFTs.
+
+Transactions are signed with --key, a stored key. Without it the MNEMONIC
+environment variable is used, and on local networks the dev key.
+
+The network is the running local network unless --network is given.
+
+EXAMPLES:
+
+  # A fixed supply token
+  lux asset create "Test Token" --symbol TST --supply 1000000
+
+  # A token minted on demand, then minted
+  lux asset create "Reward Points" --symbol RWD --kind variable-cap
+  lux asset mint <asset-id> --amount 500 --to X-local1...
+
+  # An NFT collection, an NFT minted and sent
+  lux asset create "Art" --symbol ART --kind nft
+  lux asset mint <asset-id> --payload "ipfs://..."
+  lux asset send <asset-id> X-local1... --group 0`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.PersistentFlags().StringVar(&networkType, "network", "", "Network type: custom, devnet, testnet or mainnet (default: the running network)")
+	cmd.PersistentFlags().StringVar(&rpcURL, "rpc-url", "", "Base URL of the node to issue to (default: the network endpoint)")
+	cmd.PersistentFlags().StringVar(&keyName, "key", "", "Stored key to sign with (default: MNEMONIC, or the dev key on local networks)")
+	cmd.AddCommand(newCreateCmd())
+	cmd.AddCommand(newMintCmd())
+	cmd.AddCommand(newSendCmd())
+	return cmd
+}
+
+// target is the network the asset commands issue to
+type target struct {
+	networkType string
+	network     models.Network
+	networkID   uint32
+	endpoint    string
+}
+
+// resolveTarget returns the network of --network, served by the running
+// local network when it is of that type
+func resolveTarget() (target, error) {
+	t := target{networkType: networkType}
+	if t.networkType == "" {
+		if t.networkType = app.GetRunningNetworkType(); t.networkType == "" {
+			return t, errors.New("no network running, start one or pass --network")
+		}
+	}
+	switch t.networkType {
+	case "mainnet":
+		t.network = models.Mainnet
+	case "testnet":
+		t.network = models.Testnet
+	case "devnet":
+		t.network = models.Devnet
+	case "custom", "local":
+		t.network = models.Local
+	default:
+		return t, fmt.Errorf("unknown network %q, use custom, devnet, testnet or mainnet", t.networkType)
+	}
+	t.networkID = t.network.ID()
+	t.endpoint = t.network.Endpoint()
+	if state, err := app.LoadNetworkStateForType(t.networkType); err == nil && state != nil && state.Running {
+		if state.APIEndpoint != "" {
+			t.endpoint = state.APIEndpoint
+		}
+		if state.NetworkID != 0 {
+			t.networkID = state.NetworkID
+		}
+	}
+	if rpcURL != "" {
+		t.endpoint = rpcURL
+	}
+	t.endpoint = strings.TrimSuffix(t.endpoint, "/")
+	if t.endpoint == "" {
+		return t, fmt.Errorf("no endpoint for %s, start the network or pass --rpc-url", t.networkType)
+	}
+	return t, nil
+}
+
+// loadKey returns the signing key of --key, MNEMONIC or the local dev key
+func loadKey(t target) (*key.SoftKey, error) {
+	if keyName != "" {
+		keySet, err := key.LoadKeySet(keyName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load key '%s': %w", keyName, err)
+		}
+		if len(keySet.ECPrivateKey) == 0 {
+			return nil, fmt.Errorf("key '%s' has no EC private key", keyName)
+		}
+		return key.NewSoftFromBytes(t.networkID, keySet.ECPrivateKey)
+	}
+	if mnemonic := key.GetMnemonicFromEnv(); mnemonic != "" {
+		return key.NewSoftFromMnemonic(t.networkID, mnemonic)
+	}
+	if t.network == models.Local {
+		return key.NewSoftFromMnemonic(t.networkID, key.GetLightMnemonic())
+	}
+	return nil, fmt.Errorf("no key to sign with on %s, pass --key or set MNEMONIC", t.networkType)
+}
+
+// loadWallet returns the X-Chain wallet of the signing key on the target
+// network, with the address of the key
+func loadWallet(ctx context.Context) (*xchain.Wallet, ids.ShortID, error) {
+	t, err := resolveTarget()
+	if err != nil {
+		return nil, ids.ShortEmpty, err
+	}
+	sk, err := loadKey(t)
+	if err != nil {
+		return nil, ids.ShortEmpty, err
+	}
+	kc := secp256k1fx.NewKeychain(sk.Key())
+	wallet, err := xchain.NewWallet(ctx, t.endpoint, primary.NewKeychainAdapter(kc))
+	if err != nil {
+		return nil, ids.ShortEmpty, fmt.Errorf("failed to load the X-Chain wallet from %s: %w", t.endpoint, err)
+	}
+	return wallet, sk.Key().PublicKey().Address(), nil
+}
+
+// ownerOf returns the single owner of an output, the address or @label s,
+// or addr when s is empty
+func ownerOf(s string, addr ids.ShortID) (*secp256k1fx.OutputOwners, error) {
+	if s != "" {
+		s, err := app.ResolveAddress(s)
+		if err != nil {
+			return nil, err
+		}
+		chain, _, b, err := address.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid X-Chain address %q: %w", s, err)
+		}
+		if chain != builder.Alias {
+			return nil, fmt.Errorf("%q is not an X-Chain address", s)
+		}
+		if addr, err = ids.ToShortID(b); err != nil {
+			return nil, fmt.Errorf("invalid X-Chain address %q: %w", s, err)
+		}
+	}
+	return &secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{addr}}, nil
+}
+
+// formatAddress formats addr as an X-Chain address of the wallet network
+func formatAddress(wallet *xchain.Wallet, addr ids.ShortID) string {
+	s, err := address.Format(builder.Alias, wallet.HRP, addr.Bytes())
+	if err != nil {
+		return addr.String()
+	}
+	return s
+}
+
+// recordAsset records an issued asset operation in the history
+func recordAsset(operation string, params map[string]string, txID ids.ID) {
+	network := networkType
+	if t, err := resolveTarget(); err == nil {
+		network = t.network.String()
+	}
+	app.RecordHistory(history.Entry{
+		Operation: operation,
+		Network:   network,
+		Chain:     "X",
+		Params:    params,
+		Result:    map[string]string{"txID": txID.String()},
+	})
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package assetcmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/xchain"
+	"github.com/spf13/cobra"
+)
+
+var (
+	createSymbol       string
+	createKind         string
+	createDenomination uint8
+	createSupply       string
+	createGroups       uint32
+	createOwner        string
+)
+
+func newCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create [name]",
+		Short: "Create an X-Chain asset",
+		Long: `Create an asset on the X-Chain. The --kind of the asset is one of:
+
+  fixed-cap     the whole --supply is created with the asset
+  variable-cap  the owner mints the asset later, an initial --supply is optional
+  nft           the owner mints NFTs in --groups groups
+
+The owner, which holds the supply and mints the asset, is --owner or the
+signing key.
+
+EXAMPLES:
+
+  lux asset create "Test Token" --symbol TST --supply 1000000
+  lux asset create "Reward Points" --symbol RWD --kind variable-cap --denomination 2
+  lux asset create "Art" --symbol ART --kind nft --groups 3`,
+		Args:         cobra.ExactArgs(1),
+		RunE:         runCreate,
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&createSymbol, "symbol", "", "Symbol of the asset, at most 4 characters")
+	cmd.Flags().StringVar(&createKind, "kind", string(xchain.FixedCap), "Kind of asset: fixed-cap, variable-cap or nft")
+	cmd.Flags().Uint8Var(&createDenomination, "denomination", 9, "Number of decimals of the asset (0 for NFTs)")
+	cmd.Flags().StringVar(&createSupply, "supply", "", "Supply to create, required for fixed-cap assets")
+	cmd.Flags().Uint32Var(&createGroups, "groups", 1, "Number of NFT groups")
+	cmd.Flags().StringVar(&createOwner, "owner", "", "X-Chain address or @label owning the asset (default: the signing key)")
+	return cmd
+}
+
+func runCreate(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	kind, err := xchain.ParseAssetKind(createKind)
+	if err != nil {
+		return err
+	}
+	denomination := createDenomination
+	if kind == xchain.NFT && !cmd.Flags().Changed("denomination") {
+		denomination = 0
+	}
+	if err := xchain.ValidateAsset(kind, name, createSymbol, denomination); err != nil {
+		return err
+	}
+	var supply uint64
+	if createSupply != "" {
+		if kind == xchain.NFT {
+			return fmt.Errorf("NFT assets have no supply, mint NFTs with 'lux asset mint'")
+		}
+		if supply, err = xchain.ParseAmount(createSupply, denomination); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), txTimeout)
+	defer cancel()
+	wallet, addr, err := loadWallet(ctx)
+	if err != nil {
+		return err
+	}
+	owner, err := ownerOf(createOwner, addr)
+	if err != nil {
+		return err
+	}
+	initialState, err := xchain.InitialState(kind, owner, supply, createGroups)
+	if err != nil {
+		return err
+	}
+	utx, err := wallet.Builder.NewCreateAssetTx(name, createSymbol, denomination, initialState)
+	if err != nil {
+		return fmt.Errorf("failed to build the asset tx: %w", err)
+	}
+	tx, err := wallet.Issue(ctx, utx)
+	if err != nil {
+		return err
+	}
+
+	recordAsset("asset create", map[string]string{
+		"name":         name,
+		"symbol":       createSymbol,
+		"kind":         string(kind),
+		"denomination": strconv.Itoa(int(denomination)),
+		"supply":       xchain.FormatAmount(supply, denomination),
+	}, tx.ID())
+
+	ux.Logger.PrintToUser("✓ Created %s asset %s (%s)", kind, name, createSymbol)
+	ux.Logger.PrintToUser("  Asset ID: %s", tx.ID())
+	ux.Logger.PrintToUser("  Owner:    %s", app.AnnotateAddress(formatAddress(wallet, owner.Addrs[0])))
+	switch kind {
+	case xchain.NFT:
+		ux.Logger.PrintToUser("  Groups:   %d", createGroups)
+	default:
+		ux.Logger.PrintToUser("  Supply:   %s", xchain.FormatAmount(supply, denomination))
+	}
+	return nil
+}
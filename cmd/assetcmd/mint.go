@@ -0,0 +1,148 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package assetcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/xchain"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/utxo/nftfx"
+	"github.com/luxfi/utxo/secp256k1fx"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mintAmount      string
+	mintTo          string
+	mintPayload     string
+	mintPayloadFile string
+)
+
+func newMintCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mint [asset]",
+		Short: "Mint a variable-cap asset or an NFT",
+		Long: `Mint more of a variable-cap asset, or a new NFT of an NFT asset, with the
+minting rights of the signing key. The asset is its ID or alias.
+
+A variable-cap asset mints --amount; an NFT asset mints an NFT of the first
+group the key can mint, carrying --payload or the contents of --payload-file
+(at most 1 KiB). The minted tokens go to --to, or the signing key.
+
+EXAMPLES:
+
+  lux asset mint 2Z6j...qWr --amount 500 --to X-local1...
+  lux asset mint 2mcw...7Dd --payload "ipfs://bafy..."`,
+		Args:         cobra.ExactArgs(1),
+		RunE:         runMint,
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&mintAmount, "amount", "", "Amount to mint of a variable-cap asset")
+	cmd.Flags().StringVar(&mintTo, "to", "", "X-Chain address or @label receiving the minted tokens (default: the signing key)")
+	cmd.Flags().StringVar(&mintPayload, "payload", "", "Payload of the minted NFT")
+	cmd.Flags().StringVar(&mintPayloadFile, "payload-file", "", "File holding the payload of the minted NFT")
+	cmd.MarkFlagsMutuallyExclusive("payload", "payload-file")
+	cmd.MarkFlagsMutuallyExclusive("amount", "payload")
+	cmd.MarkFlagsMutuallyExclusive("amount", "payload-file")
+	return cmd
+}
+
+func runMint(_ *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), txTimeout)
+	defer cancel()
+	wallet, addr, err := loadWallet(ctx)
+	if err != nil {
+		return err
+	}
+	asset, err := wallet.Client.GetAssetDescription(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("unknown asset %s: %w", args[0], err)
+	}
+	owner, err := ownerOf(mintTo, addr)
+	if err != nil {
+		return err
+	}
+	nft, err := isNFT(ctx, wallet, asset.AssetID)
+	if err != nil {
+		return err
+	}
+
+	if nft {
+		payload := []byte(mintPayload)
+		if mintPayloadFile != "" {
+			if payload, err = os.ReadFile(mintPayloadFile); err != nil {
+				return err
+			}
+		}
+		if len(payload) > nftfx.MaxPayloadSize {
+			return fmt.Errorf("the payload is %d bytes, NFT payloads are at most %d", len(payload), nftfx.MaxPayloadSize)
+		}
+		utx, err := wallet.Builder.NewOperationTxMintNFT(asset.AssetID, payload, []*secp256k1fx.OutputOwners{owner})
+		if err != nil {
+			return fmt.Errorf("failed to build the mint tx: %w", err)
+		}
+		tx, err := wallet.Issue(ctx, utx)
+		if err != nil {
+			return err
+		}
+		recordAsset("asset mint", map[string]string{
+			"asset":       asset.AssetID.String(),
+			"to":          formatAddress(wallet, owner.Addrs[0]),
+			"payloadSize": strconv.Itoa(len(payload)),
+		}, tx.ID())
+		ux.Logger.PrintToUser("✓ Minted an NFT of %s to %s", asset.Name, app.AnnotateAddress(formatAddress(wallet, owner.Addrs[0])))
+		ux.Logger.PrintToUser("  TxID: %s", tx.ID())
+		return nil
+	}
+
+	if mintAmount == "" {
+		return fmt.Errorf("pass the --amount of %s to mint", asset.Name)
+	}
+	amount, err := xchain.ParseAmount(mintAmount, asset.Denomination)
+	if err != nil {
+		return err
+	}
+	utx, err := wallet.Builder.NewOperationTxMintFT(map[ids.ID]*secp256k1fx.TransferOutput{
+		asset.AssetID: {Amt: amount, OutputOwners: *owner},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build the mint tx: %w", err)
+	}
+	tx, err := wallet.Issue(ctx, utx)
+	if err != nil {
+		return err
+	}
+	recordAsset("asset mint", map[string]string{
+		"asset":  asset.AssetID.String(),
+		"to":     formatAddress(wallet, owner.Addrs[0]),
+		"amount": xchain.FormatAmount(amount, asset.Denomination),
+	}, tx.ID())
+	ux.Logger.PrintToUser("✓ Minted %s %s to %s", xchain.FormatAmount(amount, asset.Denomination), asset.Symbol, app.AnnotateAddress(formatAddress(wallet, owner.Addrs[0])))
+	ux.Logger.PrintToUser("  TxID: %s", tx.ID())
+	return nil
+}
+
+// isNFT reports whether the wallet holds NFTs or NFT minting rights of
+// assetID
+func isNFT(ctx context.Context, wallet *xchain.Wallet, assetID ids.ID) (bool, error) {
+	utxos, err := wallet.UTXOs(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, utxo := range utxos {
+		if utxo.AssetID() != assetID {
+			continue
+		}
+		switch utxo.Out.(type) {
+		case *nftfx.MintOutput, *nftfx.TransferOutput:
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,114 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package assetcmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/xchain"
+	"github.com/luxfi/math/set"
+	"github.com/luxfi/protocol/x/txs"
+	"github.com/luxfi/utxo"
+	"github.com/luxfi/utxo/secp256k1fx"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sendAmount string
+	sendGroup  uint32
+)
+
+func newSendCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "send [asset] [address]",
+		Short: "Send an X-Chain asset or NFT",
+		Long: `Send --amount of a fungible asset, or an NFT of --group of an NFT asset, to an
+X-Chain address, or the @label of one in the address book. The asset is its
+ID or alias, so LUX can be sent too.
+
+EXAMPLES:
+
+  lux asset send 2Z6j...qWr X-local1... --amount 25.5
+  lux asset send LUX X-local1... --amount 1
+  lux asset send 2mcw...7Dd X-local1... --group 0`,
+		Args:         cobra.ExactArgs(2),
+		RunE:         runSend,
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&sendAmount, "amount", "", "Amount to send of a fungible asset")
+	cmd.Flags().Uint32Var(&sendGroup, "group", 0, "Group of the NFT to send")
+	return cmd
+}
+
+func runSend(_ *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), txTimeout)
+	defer cancel()
+	wallet, addr, err := loadWallet(ctx)
+	if err != nil {
+		return err
+	}
+	asset, err := wallet.Client.GetAssetDescription(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("unknown asset %s: %w", args[0], err)
+	}
+	to, err := ownerOf(args[1], addr)
+	if err != nil {
+		return err
+	}
+	nft, err := isNFT(ctx, wallet, asset.AssetID)
+	if err != nil {
+		return err
+	}
+
+	var utx txs.UnsignedTx
+	sent := ""
+	if nft {
+		if sendAmount != "" {
+			return fmt.Errorf("%s is an NFT asset, pass the --group of the NFT to send instead of --amount", asset.Name)
+		}
+		utxos, err := wallet.UTXOs(ctx)
+		if err != nil {
+			return err
+		}
+		op, err := xchain.NFTTransferOperation(utxos, asset.AssetID, sendGroup, set.Of(addr), to, uint64(time.Now().Unix()))
+		if err != nil {
+			return err
+		}
+		if utx, err = wallet.Builder.NewOperationTx([]*txs.Operation{op}); err != nil {
+			return fmt.Errorf("failed to build the send tx: %w", err)
+		}
+		sent = fmt.Sprintf("an NFT of group %d of %s", sendGroup, asset.Name)
+	} else {
+		if sendAmount == "" {
+			return fmt.Errorf("pass the --amount of %s to send", asset.Name)
+		}
+		amount, err := xchain.ParseAmount(sendAmount, asset.Denomination)
+		if err != nil {
+			return err
+		}
+		if utx, err = wallet.Builder.NewBaseTx([]*utxo.TransferableOutput{{
+			Asset: utxo.Asset{ID: asset.AssetID},
+			Out:   &secp256k1fx.TransferOutput{Amt: amount, OutputOwners: *to},
+		}}); err != nil {
+			return fmt.Errorf("failed to build the send tx: %w", err)
+		}
+		sent = fmt.Sprintf("%s %s", xchain.FormatAmount(amount, asset.Denomination), asset.Symbol)
+	}
+
+	tx, err := wallet.Issue(ctx, utx)
+	if err != nil {
+		return err
+	}
+	recordAsset("asset send", map[string]string{
+		"asset":  asset.AssetID.String(),
+		"to":     formatAddress(wallet, to.Addrs[0]),
+		"amount": sent,
+	}, tx.ID())
+	ux.Logger.PrintToUser("✓ Sent %s to %s", sent, app.AnnotateAddress(formatAddress(wallet, to.Addrs[0])))
+	ux.Logger.PrintToUser("  TxID: %s", tx.ID())
+	return nil
+}
@@ -0,0 +1,269 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package backupcmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/backup"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/keychain"
+	"github.com/luxfi/cli/pkg/snapshot"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	app *application.Lux
+
+	toKMS   bool
+	fromKMS bool
+	kmsPath string
+	file    string
+	keep    int
+	version int
+	force   bool
+	dryRun  bool
+)
+
+// NewCmd creates the backup command
+func NewCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up and restore keys, chains and cluster configs",
+		Long: `Back up the state of the CLI home that can't be recreated: keys, chain
+definitions and configs, cluster configs and inventories, and node
+certificates. Network runs, snapshots and binaries are left out.
+
+With --to-kms each backup is stored as a new secret of the KMS server of
+LUX_KMS_ADDR (http://localhost:8200 by default, with the API key of
+LUX_KMS_API_KEY), which encrypts it with its default key. Backups are
+versioned: restore takes the latest one, or any older --version. They are
+kept under /lux-cli/backups/<home> so the homes of 'lux home' don't mix.
+
+Keys held by the macOS keychain or another OS key store are not files of
+the home and are not backed up.
+
+EXAMPLES:
+
+  # Back up to the KMS, keeping the last 10 backups
+  lux backup create --to-kms --keep 10
+
+  # Back up to a file
+  lux backup create --output lux-backup.tar.gz
+
+  # List the backups in the KMS
+  lux backup list
+
+  # Restore the latest backup on a new machine, or an older one
+  lux backup restore --from-kms
+  lux backup restore --from-kms --version 3 --dry-run
+
+  # Restore from a file, overwriting changed files
+  lux backup restore --file lux-backup.tar.gz --force`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.AddCommand(newCreateCmd())
+	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newRestoreCmd())
+	return cmd
+}
+
+func addKMSPathFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&kmsPath, "kms-path", "", "KMS secret path of the backups (default /lux-cli/backups/<home>)")
+}
+
+func newCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Back up keys, chains and cluster configs",
+		Args:  cobrautils.ExactArgs(0),
+		RunE:  createBackup,
+	}
+	cmd.Flags().BoolVar(&toKMS, "to-kms", false, "store the backup as a new secret version in the KMS")
+	cmd.Flags().StringVarP(&file, "output", "o", "", "write the backup to this file")
+	cmd.Flags().IntVar(&keep, "keep", 0, "with --to-kms, delete older backups so only this many remain (0 keeps all)")
+	addKMSPathFlag(cmd)
+	return cmd
+}
+
+func createBackup(cmd *cobra.Command, _ []string) error {
+	if toKMS == (file != "") {
+		return errors.New("specify one of --to-kms or --output")
+	}
+	if keep < 0 {
+		return fmt.Errorf("invalid --keep %d", keep)
+	}
+	archive, m, err := backup.Create(app.GetBaseDir(), backup.Paths, homeName())
+	if err != nil {
+		return fmt.Errorf("failed to create backup: %w", err)
+	}
+	if len(m.Files) == 0 {
+		return fmt.Errorf("nothing to back up in %s", app.GetBaseDir())
+	}
+	ux.Logger.PrintToUser("Backing up %d files (%s) of %s", len(m.Files), snapshot.FormatBytes(m.Bytes), app.GetBaseDir())
+
+	if file != "" {
+		if err := os.WriteFile(file, archive, 0o600); err != nil {
+			return err
+		}
+		ux.Logger.GreenCheckmarkToUser("Backup written to %s", file)
+		return nil
+	}
+	store := kmsStore()
+	v, err := store.Put(cmd.Context(), archive, m.Created)
+	if err != nil {
+		return fmt.Errorf("failed to store backup: %w", err)
+	}
+	ux.Logger.GreenCheckmarkToUser("Backup version %d stored in KMS %s as %s/%s", v.Number, store.URL, store.Path, v.Name)
+	if keep > 0 {
+		deleted, err := store.Prune(cmd.Context(), keep)
+		if err != nil {
+			return fmt.Errorf("failed to delete older backups: %w", err)
+		}
+		if deleted > 0 {
+			ux.Logger.PrintToUser("Deleted %d older backups, keeping %d", deleted, keep)
+		}
+	}
+	return nil
+}
+
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the backups stored in the KMS",
+		Args:  cobrautils.ExactArgs(0),
+		RunE:  listBackups,
+	}
+	addKMSPathFlag(cmd)
+	return cmd
+}
+
+func listBackups(cmd *cobra.Command, _ []string) error {
+	store := kmsStore()
+	versions, err := store.List(cmd.Context())
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		ux.Logger.PrintToUser("No backups in KMS %s%s", store.URL, store.Path)
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Version", "Created", "Secret")
+	for _, v := range versions {
+		_ = table.Append([]string{strconv.Itoa(v.Number), v.Created.Local().Format("2006-01-02 15:04:05"), v.Name})
+	}
+	return table.Render()
+}
+
+func newRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore keys, chains and cluster configs from a backup",
+		Long: `Restore a backup into the CLI home. Files of the home missing from the
+backup are left alone. When files of the home differ from the backup, they
+are listed and nothing is restored unless --force is given.`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: restoreBackup,
+	}
+	cmd.Flags().BoolVar(&fromKMS, "from-kms", false, "restore a backup stored in the KMS")
+	cmd.Flags().StringVar(&file, "file", "", "restore the backup of this file")
+	cmd.Flags().IntVar(&version, "version", 0, "with --from-kms, the backup version to restore (default latest)")
+	cmd.Flags().BoolVar(&force, "force", false, "overwrite files that differ from the backup")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be restored without writing anything")
+	addKMSPathFlag(cmd)
+	return cmd
+}
+
+func restoreBackup(cmd *cobra.Command, _ []string) error {
+	if fromKMS == (file != "") {
+		return errors.New("specify one of --from-kms or --file")
+	}
+	source := file
+	var archive []byte
+	if file != "" {
+		data, err := os.ReadFile(file) //nolint:gosec // G304: Reading the backup the user gave
+		if err != nil {
+			return err
+		}
+		archive = data
+	} else {
+		store := kmsStore()
+		v, data, err := store.Get(cmd.Context(), version)
+		if err != nil {
+			return err
+		}
+		archive = data
+		source = fmt.Sprintf("version %d (%s)", v.Number, v.Name)
+	}
+	m, err := backup.ReadManifest(archive)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Backup %s of home %s on %s, %s: %d files",
+		source, m.Home, m.Host, m.Created.Local().Format("2006-01-02 15:04:05"), len(m.Files))
+
+	conflicts, err := backup.Conflicts(app.GetBaseDir(), archive)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		for _, f := range m.Files {
+			ux.Logger.PrintToUser("  %s", f)
+		}
+		if len(conflicts) > 0 {
+			ux.Logger.PrintToUser("Would overwrite %d changed files with --force: %s", len(conflicts), strings.Join(conflicts, ", "))
+		}
+		return nil
+	}
+	if len(conflicts) > 0 && !force {
+		return fmt.Errorf("%d files of %s differ from the backup, restore with --force to overwrite them: %s",
+			len(conflicts), app.GetBaseDir(), strings.Join(conflicts, ", "))
+	}
+
+	lock, err := app.AcquireLock("backup restore")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+	restored, err := backup.Restore(app.GetBaseDir(), archive)
+	if err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "backup restore",
+		Params:    map[string]string{"source": source, "files": strconv.Itoa(len(restored))},
+	})
+	ux.Logger.GreenCheckmarkToUser("Restored %d files to %s", len(restored), app.GetBaseDir())
+	return nil
+}
+
+// kmsStore returns the KMS store of the backups of the home
+func kmsStore() *backup.KMSStore {
+	client := keychain.NewKMSClientFromEnv()
+	path := kmsPath
+	if path == "" {
+		path = backup.DefaultKMSPath + "/" + homeName()
+	}
+	return &backup.KMSStore{URL: client.URL, APIKey: client.APIKey, Path: path, Client: client.Client}
+}
+
+// homeName names the home in backups: its name, or its directory name when
+// given as a directory
+func homeName() string {
+	if app.Home.Name != "" {
+		return app.Home.Name
+	}
+	return filepath.Base(app.GetBaseDir())
+}
@@ -0,0 +1,481 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/contract"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/ethclient"
+	"github.com/luxfi/sdk/evm"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// aaFileName is the file of a chain recording its account abstraction
+	// deployments, by network
+	aaFileName = "aa.json"
+
+	// defaultBundlerPort is the port the local bundler serves on
+	defaultBundlerPort = 4337
+)
+
+var (
+	aaKeyName          string
+	aaEntryPointBin    string
+	aaPaymasterBin     string
+	aaPaymasterSigner  string
+	aaPaymasterDeposit uint64
+	aaBeneficiary      string
+	aaNoPaymaster      bool
+	aaBundler          bool
+	aaBundlerBin       string
+	aaBundlerPort      int
+	aaBundlerURL       string
+)
+
+// aaDeployment is the account abstraction infra of a chain on a network,
+// as a bundler connects to it
+type aaDeployment struct {
+	ChainID           string `json:"chainId"`
+	RPCURL            string `json:"rpcUrl"`
+	EntryPoint        string `json:"entryPoint"`
+	EntryPointVersion string `json:"entryPointVersion"`
+	Paymaster         string `json:"paymaster,omitempty"`
+	PaymasterSigner   string `json:"paymasterSigner,omitempty"`
+	Beneficiary       string `json:"beneficiary"`
+	BundlerURL        string `json:"bundlerUrl,omitempty"`
+}
+
+// aaBundlerRunFile records the local bundler of a chain
+type aaBundlerRunFile struct {
+	Pid       int       `json:"pid"`
+	Network   string    `json:"network"`
+	URL       string    `json:"url"`
+	StartedAt time.Time `json:"startedAt"`
+}
+
+// lux chain aa
+func newAACmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "aa",
+		Short: "Deploy ERC-4337 account abstraction infra to a chain",
+		Long:  `The aa command sets up ERC-4337 account abstraction on a deployed EVM chain.`,
+		RunE:  cobrautils.CommandSuiteUsage,
+	}
+	deployCmd := newAADeployCmd()
+	addNetworkFlags(deployCmd)
+	cmd.AddCommand(deployCmd)
+	cmd.AddCommand(newAAStopCmd())
+	return cmd
+}
+
+// lux chain aa deploy
+func newAADeployCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy [chainName]",
+		Short: "Deploy the EntryPoint and a paymaster, and optionally run a bundler",
+		Long: `The aa deploy command deploys the ERC-4337 v0.7 EntryPoint to a deployed EVM
+chain with a paymaster funded in the EntryPoint, optionally runs a local
+bundler, and writes the endpoints and the config a bundler needs to serve the
+chain to ~/.lux/chains/<chainName>/aa.json.
+
+The EntryPoint is deployed from the build artifact given with --entrypoint-bin
+(a hex file, or a hardhat or foundry JSON artifact) through the CREATE2
+deployer, with the canonical salt: the canonical build then lands at the
+canonical address, ` + contract.EntryPointV07Address + `. A chain already having
+the EntryPoint, for instance in its genesis, needs no artifact. A chain
+without the CREATE2 deployer gets the EntryPoint at a regular address.
+
+By default a simple paymaster sponsoring every user operation is deployed,
+owned by the key: calls from the owner are forwarded to the EntryPoint, to
+withdraw its deposit. Other paymasters are deployed from --paymaster-bin, with
+the EntryPoint and the --paymaster-signer as constructor arguments, as the
+v0.7 verifying paymaster takes them. --paymaster-deposit tokens are deposited
+for the paymaster, which pays for the operations it sponsors from them.
+
+With --bundler, the alto bundler (from the PATH, or --bundler-bin) is started
+in the background on --bundler-port, submitting the bundles with the key. Stop
+it with 'lux chain aa stop'. A bundler run elsewhere is recorded with
+--bundler-url.
+
+The key paying for the deploys is given with --key, and is the local key on
+local networks.
+
+EXAMPLES:
+
+  lux chain aa deploy mychain --devnet --entrypoint-bin ./EntryPoint.json
+
+  # Sponsor every operation with 10 tokens, served by a local bundler
+  lux chain aa deploy mychain --local --paymaster-deposit 10 --bundler
+
+  # Add a verifying paymaster with 100 tokens to sponsor operations
+  lux chain aa deploy mychain --testnet --key ops \
+    --paymaster-bin ./VerifyingPaymaster.json --paymaster-deposit 100`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: deployAA,
+	}
+	cmd.Flags().StringVar(&aaKeyName, "key", "", "key paying for the deploys (from ~/.lux/keys/), the local key on local networks")
+	cmd.Flags().StringVar(&aaEntryPointBin, "entrypoint-bin", "", "EntryPoint build artifact, when the chain has no EntryPoint yet")
+	cmd.Flags().StringVar(&aaPaymasterBin, "paymaster-bin", "", "paymaster build artifact to deploy")
+	cmd.Flags().StringVar(&aaPaymasterSigner, "paymaster-signer", "", "address signing the paymaster sponsorships (defaults to the key's)")
+	cmd.Flags().Uint64Var(&aaPaymasterDeposit, "paymaster-deposit", 0, "tokens to deposit in the EntryPoint for the paymaster")
+	cmd.Flags().StringVar(&aaBeneficiary, "beneficiary", "", "address the bundler collects the fees to (defaults to the key's)")
+	cmd.Flags().BoolVar(&aaNoPaymaster, "no-paymaster", false, "don't deploy the simple paymaster")
+	cmd.Flags().BoolVar(&aaBundler, "bundler", false, "run a local bundler for the chain")
+	cmd.Flags().StringVar(&aaBundlerBin, "bundler-bin", "alto", "alto bundler binary")
+	cmd.Flags().IntVar(&aaBundlerPort, "bundler-port", defaultBundlerPort, "port the local bundler serves on")
+	cmd.Flags().StringVar(&aaBundlerURL, "bundler-url", "", "endpoint of a bundler run elsewhere, to record")
+	return cmd
+}
+
+// lux chain aa stop
+func newAAStopCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop [chainName]",
+		Short: "Stop the local bundler of a chain",
+		Long:  `The aa stop command stops the bundler 'lux chain aa deploy --bundler' started.`,
+		Args:  cobrautils.ExactArgs(1),
+		RunE:  stopAABundler,
+	}
+}
+
+func deployAA(_ *cobra.Command, args []string) error {
+	chainName := args[0]
+	target := GetNetworkTarget()
+	network := targetNetwork(target)
+	for flag, address := range map[string]string{"--paymaster-signer": aaPaymasterSigner, "--beneficiary": aaBeneficiary} {
+		if address != "" && !common.IsHexAddress(address) {
+			return fmt.Errorf("invalid %s address %q", flag, address)
+		}
+	}
+	deployments, err := loadAADeployments(chainName)
+	if err != nil {
+		return err
+	}
+	deployment := deployments[network.Name()]
+	if aaPaymasterDeposit > 0 && aaNoPaymaster && aaPaymasterBin == "" && deployment.Paymaster == "" {
+		return errors.New("--paymaster-deposit needs a paymaster: don't use --no-paymaster")
+	}
+	if aaBundler && aaBundlerURL != "" {
+		return errors.New("--bundler and --bundler-url can't be used together")
+	}
+	var bundlerBin string
+	if aaBundler {
+		if pid, running := aaBundlerPID(chainName); running {
+			return fmt.Errorf("a bundler is already running for %s (PID %d), stop it with 'lux chain aa stop %s'", chainName, pid, chainName)
+		}
+		if bundlerBin, err = exec.LookPath(aaBundlerBin); err != nil {
+			return fmt.Errorf("bundler %s not found, install alto or give its path with --bundler-bin: %w", aaBundlerBin, err)
+		}
+	}
+	rpcURL, err := chainRPCEndpoint(chainName, target)
+	if err != nil {
+		return err
+	}
+	privateKey, err := signingKey(network, aaKeyName)
+	if err != nil {
+		return err
+	}
+	keyAddress, err := evm.PrivateKeyToAddress(privateKey)
+	if err != nil {
+		return err
+	}
+	client, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", rpcURL, err)
+	}
+	defer client.Close()
+	ctx, cancel := utils.GetAPILargeContext()
+	defer cancel()
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the chain ID from %s: %w", rpcURL, err)
+	}
+
+	deployment.ChainID = chainID.String()
+	deployment.RPCURL = rpcURL
+	deployment.EntryPointVersion = "v0.7"
+
+	entryPoint, err := deployEntryPoint(ctx, client, rpcURL, privateKey, deployment.EntryPoint)
+	if err != nil {
+		return err
+	}
+	deployment.EntryPoint = entryPoint.Hex()
+
+	switch {
+	case aaPaymasterBin != "":
+		bin, err := contract.ReadBytecode(aaPaymasterBin)
+		if err != nil {
+			return err
+		}
+		signer := keyAddress
+		if aaPaymasterSigner != "" {
+			signer = crypto.HexToAddress(aaPaymasterSigner)
+		}
+		paymaster, err := contract.DeployPaymaster(rpcURL, privateKey, bin, entryPoint, signer)
+		if err != nil {
+			return fmt.Errorf("failed to deploy the paymaster: %w", err)
+		}
+		ux.Logger.GreenCheckmarkToUser("Paymaster deployed at %s", paymaster.Hex())
+		deployment.Paymaster = paymaster.Hex()
+		deployment.PaymasterSigner = signer.Hex()
+	case deployment.Paymaster == "" && !aaNoPaymaster:
+		paymaster, err := contract.DeploySimplePaymaster(rpcURL, privateKey, entryPoint, keyAddress)
+		if err != nil {
+			return fmt.Errorf("failed to deploy the paymaster: %w", err)
+		}
+		ux.Logger.GreenCheckmarkToUser("Paymaster sponsoring every operation deployed at %s", paymaster.Hex())
+		deployment.Paymaster = paymaster.Hex()
+		deployment.PaymasterSigner = ""
+		if aaPaymasterDeposit == 0 {
+			ux.Logger.PrintToUser("  it pays from its EntryPoint deposit: fund it with --paymaster-deposit")
+		}
+	}
+	if aaPaymasterDeposit > 0 {
+		amount := new(big.Int).Mul(new(big.Int).SetUint64(aaPaymasterDeposit), new(big.Int).Exp(big.NewInt(10), big.NewInt(tokenDecimals), nil))
+		paymaster := crypto.HexToAddress(deployment.Paymaster)
+		if err := contract.DepositToEntryPoint(rpcURL, privateKey, entryPoint, paymaster, amount); err != nil {
+			return fmt.Errorf("failed to deposit for the paymaster: %w", err)
+		}
+		ux.Logger.GreenCheckmarkToUser("Deposited %d tokens for the paymaster", aaPaymasterDeposit)
+	}
+
+	deployment.Beneficiary = keyAddress.Hex()
+	if aaBeneficiary != "" {
+		deployment.Beneficiary = common.HexToAddress(aaBeneficiary).Hex()
+	}
+	switch {
+	case aaBundlerURL != "":
+		deployment.BundlerURL = aaBundlerURL
+	case aaBundler:
+		bundlerURL, err := startAABundler(chainName, network.Name(), bundlerBin, deployment, privateKey)
+		if err != nil {
+			return err
+		}
+		deployment.BundlerURL = bundlerURL
+	}
+	deployments[network.Name()] = deployment
+	if err := saveAADeployments(chainName, deployments); err != nil {
+		return err
+	}
+	printAADeployment(chainName, network.Name(), deployment)
+	return nil
+}
+
+// deployEntryPoint returns the EntryPoint of the chain, deploying it from
+// --entrypoint-bin when the chain has none at the canonical address or at
+// recorded, the address of a previous deploy
+func deployEntryPoint(
+	ctx context.Context,
+	client *ethclient.Client,
+	rpcURL string,
+	privateKey string,
+	recorded string,
+) (crypto.Address, error) {
+	for _, address := range []string{contract.EntryPointV07Address, recorded} {
+		if address == "" {
+			continue
+		}
+		code, err := client.CodeAt(ctx, common.HexToAddress(address), nil)
+		if err != nil {
+			return crypto.Address{}, err
+		}
+		if len(code) > 0 {
+			ux.Logger.PrintToUser("EntryPoint already deployed at %s", address)
+			return crypto.HexToAddress(address), nil
+		}
+	}
+	if aaEntryPointBin == "" {
+		return crypto.Address{}, fmt.Errorf("the chain has no EntryPoint at %s: give its build artifact with --entrypoint-bin", contract.EntryPointV07Address)
+	}
+	initCode, err := contract.ReadBytecode(aaEntryPointBin)
+	if err != nil {
+		return crypto.Address{}, err
+	}
+	entryPoint, _, err := contract.DeployCreate2(rpcURL, privateKey, common.HexToHash(contract.EntryPointV07Salt), initCode)
+	if errors.Is(err, contract.ErrNoCreate2Deployer) {
+		ux.Logger.PrintToUser("The chain has no CREATE2 deployer, the EntryPoint won't be at its canonical address")
+		entryPoint, err = contract.DeployContract(rpcURL, privateKey, []byte(common.Bytes2Hex(initCode)), "()")
+	}
+	if err != nil {
+		return crypto.Address{}, fmt.Errorf("failed to deploy the EntryPoint: %w", err)
+	}
+	ux.Logger.GreenCheckmarkToUser("EntryPoint deployed at %s", entryPoint.Hex())
+	if entryPoint != crypto.HexToAddress(contract.EntryPointV07Address) {
+		ux.Logger.PrintToUser("  not the canonical address %s: wallets and bundlers must be configured with it", contract.EntryPointV07Address)
+	}
+	return entryPoint, nil
+}
+
+// startAABundler runs the alto bundler in the background for the chain on
+// network, and returns its endpoint
+func startAABundler(chainName, networkName, bin string, d aaDeployment, privateKey string) (string, error) {
+	if err := os.MkdirAll(app.GetRunDir(), 0o750); err != nil {
+		return "", err
+	}
+	logPath := app.GetAABundlerLogPath(chainName)
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600) //nolint:gosec // G304: in the app's run directory
+	if err != nil {
+		return "", err
+	}
+	defer logFile.Close()
+	cmd := exec.Command( //nolint:gosec // G204: running the user's bundler
+		bin,
+		"--rpc-url", d.RPCURL,
+		"--entrypoints", d.EntryPoint,
+		"--port", strconv.Itoa(aaBundlerPort),
+		// the nodes don't serve the tracer the safe mode checks with
+		"--safe-mode", "false",
+	)
+	// the keys go in the environment, not to be listed with the process
+	key := "0x" + strings.TrimPrefix(privateKey, "0x")
+	cmd.Env = append(os.Environ(), "ALTO_EXECUTOR_PRIVATE_KEYS="+key, "ALTO_UTILITY_PRIVATE_KEY="+key)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	utils.DetachProcess(cmd)
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("failed to start the bundler: %w", err)
+	}
+	pid := cmd.Process.Pid
+	_ = cmd.Process.Release()
+	bundlerURL := fmt.Sprintf("http://127.0.0.1:%d/rpc", aaBundlerPort)
+	runBytes, err := json.Marshal(aaBundlerRunFile{Pid: pid, Network: networkName, URL: bundlerURL, StartedAt: time.Now()})
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(app.GetAABundlerRunPath(chainName), runBytes, application.WriteReadReadPerms); err != nil {
+		return "", err
+	}
+	ux.Logger.GreenCheckmarkToUser("Bundler started (PID %d) on %s, logging to %s", pid, bundlerURL, logPath)
+	return bundlerURL, nil
+}
+
+func readAABundlerRunFile(chainName string) (aaBundlerRunFile, error) {
+	var rf aaBundlerRunFile
+	runBytes, err := os.ReadFile(app.GetAABundlerRunPath(chainName))
+	if err != nil {
+		return rf, err
+	}
+	if err := json.Unmarshal(runBytes, &rf); err != nil {
+		return rf, err
+	}
+	return rf, nil
+}
+
+// aaBundlerPID returns the PID of the local bundler of the chain, and if
+// it is running
+func aaBundlerPID(chainName string) (int, bool) {
+	rf, err := readAABundlerRunFile(chainName)
+	if err != nil || rf.Pid == 0 {
+		return 0, false
+	}
+	return rf.Pid, utils.IsProcessRunning(rf.Pid)
+}
+
+func stopAABundler(_ *cobra.Command, args []string) error {
+	chainName := args[0]
+	rf, err := readAABundlerRunFile(chainName)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no bundler is running for %s", chainName)
+	}
+	if err != nil {
+		return err
+	}
+	if utils.IsProcessRunning(rf.Pid) {
+		proc, err := os.FindProcess(rf.Pid)
+		if err != nil {
+			return err
+		}
+		if err := utils.TerminateProcess(proc); err != nil {
+			return fmt.Errorf("failed to stop the bundler (PID %d): %w", rf.Pid, err)
+		}
+	}
+	if err := os.Remove(app.GetAABundlerRunPath(chainName)); err != nil {
+		return err
+	}
+	// the endpoint is gone with the bundler
+	deployments, err := loadAADeployments(chainName)
+	if err != nil {
+		return err
+	}
+	if d, ok := deployments[rf.Network]; ok && d.BundlerURL == rf.URL {
+		d.BundlerURL = ""
+		deployments[rf.Network] = d
+		if err := saveAADeployments(chainName, deployments); err != nil {
+			return err
+		}
+	}
+	ux.Logger.GreenCheckmarkToUser("Bundler of %s (PID %d) stopped", chainName, rf.Pid)
+	return nil
+}
+
+func aaDeploymentsPath(chainName string) string {
+	return filepath.Join(app.GetChainsDir(), chainName, aaFileName)
+}
+
+func loadAADeployments(chainName string) (map[string]aaDeployment, error) {
+	deployments := map[string]aaDeployment{}
+	raw, err := os.ReadFile(aaDeploymentsPath(chainName)) //nolint:gosec // G304: Reading from app's data directory
+	if errors.Is(err, os.ErrNotExist) {
+		return deployments, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &deployments); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", aaDeploymentsPath(chainName), err)
+	}
+	return deployments, nil
+}
+
+func saveAADeployments(chainName string, deployments map[string]aaDeployment) error {
+	raw, err := json.MarshalIndent(deployments, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(aaDeploymentsPath(chainName), raw, constants.WriteReadReadPerms)
+}
+
+func printAADeployment(chainName, networkName string, d aaDeployment) {
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Account abstraction on %s (%s):", chainName, networkName)
+	ux.Logger.PrintToUser("  RPC:          %s", d.RPCURL)
+	ux.Logger.PrintToUser("  Chain ID:     %s", d.ChainID)
+	ux.Logger.PrintToUser("  EntryPoint:   %s (%s)", d.EntryPoint, d.EntryPointVersion)
+	switch {
+	case d.Paymaster != "" && d.PaymasterSigner == "":
+		ux.Logger.PrintToUser("  Paymaster:    %s, sponsoring every operation", d.Paymaster)
+	case d.Paymaster != "":
+		ux.Logger.PrintToUser("  Paymaster:    %s, signer %s", d.Paymaster, d.PaymasterSigner)
+	}
+	ux.Logger.PrintToUser("  Beneficiary:  %s", d.Beneficiary)
+	if d.BundlerURL != "" {
+		ux.Logger.PrintToUser("  Bundler:      %s", d.BundlerURL)
+	}
+	ux.Logger.PrintToUser("")
+	if d.BundlerURL != "" {
+		ux.Logger.PrintToUser("Endpoints written to %s", aaDeploymentsPath(chainName))
+		return
+	}
+	ux.Logger.PrintToUser("Bundler config written to %s. To serve the chain with a bundler, e.g. alto:", aaDeploymentsPath(chainName))
+	ux.Logger.PrintToUser("  alto --rpc-url %s --entrypoints %s --executor-private-keys <key> --utility-private-key <key>", d.RPCURL, d.EntryPoint)
+}
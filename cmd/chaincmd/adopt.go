@@ -0,0 +1,285 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/luxfi/address"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/key"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+	"github.com/luxfi/sdk/platformvm"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	adoptChainID      string
+	adoptBlockchainID string
+	adoptForce        bool
+)
+
+func newAdoptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "adopt [blockchainName]",
+		Short: "Manage a chain created outside the CLI",
+		Long: `The adopt command fetches the owners, validators and blockchains of a chain
+(subnet) created outside the CLI from the P-Chain of the selected network,
+and writes the sidecar state the other chain and validator commands need to
+manage it.
+
+The sidecar is named after the blockchain unless a name is given. Chains
+validating several blockchains need --blockchain-id to pick one.
+
+Only public state is adopted: the genesis and chain config stay with whoever
+created the chain, and transactions still need signatures from the owners.
+
+EXAMPLES:
+
+  # Adopt a mainnet chain
+  lux chain adopt --chain-id 2sEFZDdQ4nN8rT3DZ4qC8fTpNaW4e1ZhMjTGBJvqR6aRM1dZqp --mainnet
+
+  # Pick the blockchain and the local name
+  lux chain adopt mychain --chain-id 2sEF...dZqp --blockchain-id 2ebC...6tt --testnet`,
+		Args: cobrautils.MaximumNArgs(1),
+		RunE: adoptChain,
+	}
+	cmd.Flags().StringVar(&adoptChainID, "chain-id", "", "ID of the chain (subnet) to adopt")
+	cmd.Flags().StringVar(&adoptChainID, "subnet-id", "", "alias for --chain-id")
+	_ = cmd.Flags().MarkHidden("subnet-id")
+	cmd.Flags().StringVar(&adoptBlockchainID, "blockchain-id", "", "blockchain to adopt, when the chain validates several")
+	cmd.Flags().BoolVar(&adoptForce, "force", false, "overwrite a sidecar already holding another deployment to the network")
+	return cmd
+}
+
+func adoptChain(_ *cobra.Command, args []string) error {
+	a, err := fetchAdoption(adoptChainID, adoptBlockchainID)
+	if err != nil {
+		return err
+	}
+	chainName := a.blockchain.Name
+	if len(args) > 0 {
+		chainName = args[0]
+	}
+	sc, err := a.sidecar(chainName, adoptForce)
+	if err != nil {
+		return err
+	}
+	if err := writeAdoptedSidecar(&sc); err != nil {
+		return err
+	}
+	if err := a.print(chainName, sc); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Genesis and chain config are not on the P-Chain: copy them to %s to run nodes for the chain", app.GetChainsDir())
+	return nil
+}
+
+// adoption is the P-Chain state of a chain created outside the CLI
+type adoption struct {
+	network    models.Network
+	endpoint   string
+	chainID    ids.ID
+	chainInfo  platformvm.GetNetClientResponse
+	blockchain platformvm.APIBlockchain
+	validators []platformvm.ClientPermissionlessValidator
+}
+
+// fetchAdoption reads chainID, and its blockchain blockchainID, from the
+// P-Chain of the selected network
+func fetchAdoption(chainIDStr, blockchainID string) (*adoption, error) {
+	if chainIDStr == "" {
+		return nil, fmt.Errorf("--chain-id is required")
+	}
+	chainID, err := ids.FromString(chainIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chain ID %q: %w", chainIDStr, err)
+	}
+	target := GetNetworkTarget()
+	a := &adoption{
+		network:  targetNetwork(target),
+		endpoint: networkEndpoint(target),
+		chainID:  chainID,
+	}
+	pClient := platformvm.NewClient(a.endpoint)
+
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	if a.chainInfo, err = pClient.GetNet(ctx, chainID); err != nil {
+		return nil, fmt.Errorf("failed to get chain %s from %s: %w", chainID, a.endpoint, err)
+	}
+	if a.blockchain, err = adoptedBlockchain(pClient, chainID, blockchainID); err != nil {
+		return nil, err
+	}
+	if a.validators, err = pClient.GetCurrentValidators(ctx, chainID, nil); err != nil {
+		return nil, fmt.Errorf("failed to get validators of %s: %w", chainID, err)
+	}
+	return a, nil
+}
+
+// sidecar returns the sidecar chainName with the adopted deployment on the
+// network, refusing to replace another deployment unless force is set
+func (a *adoption) sidecar(chainName string, force bool) (models.Sidecar, error) {
+	networkKey := a.network.String()
+	sc := models.Sidecar{Name: chainName, Chain: chainName}
+	if app.SidecarExists(chainName) {
+		var err error
+		if sc, err = app.LoadSidecar(chainName); err != nil {
+			return sc, err
+		}
+		deployed := sc.Networks[networkKey].BlockchainID
+		if deployed != ids.Empty && deployed != a.blockchain.ID && !force {
+			return sc, fmt.Errorf("%s already holds blockchain %s on %s: pick another name or use --force", chainName, deployed, networkKey)
+		}
+	}
+
+	sc.VMID = a.blockchain.VMID.String()
+	sc.VM = adoptedVMType(a.blockchain.VMID)
+	if sc.VM == models.CustomVM {
+		sc.ImportedVMID = a.blockchain.VMID.String()
+	}
+	sc.ChainID = a.chainID
+	sc.BlockchainID = a.blockchain.ID
+	sc.Sovereign = a.chainInfo.ConversionID != ids.Empty
+	validatorIDs := make([]string, 0, len(a.validators))
+	for _, v := range a.validators {
+		validatorIDs = append(validatorIDs, v.NodeID.String())
+	}
+	if sc.Networks == nil {
+		sc.Networks = map[string]models.NetworkData{}
+	}
+	networkData := sc.Networks[networkKey]
+	networkData.ChainID = a.chainID
+	networkData.BlockchainID = a.blockchain.ID
+	networkData.RPCEndpoints = []string{models.GetRPCEndpoint(a.endpoint, a.blockchain.ID.String())}
+	networkData.ValidatorIDs = validatorIDs
+	if sc.Sovereign && len(a.chainInfo.ManagerAddress) > 0 {
+		networkData.ValidatorManagerAddress = common.BytesToAddress(a.chainInfo.ManagerAddress).Hex()
+	}
+	sc.Networks[networkKey] = networkData
+	return sc, nil
+}
+
+// print shows the adopted chain, saved as chainName with sc
+func (a *adoption) print(chainName string, sc models.Sidecar) error {
+	ux.Logger.PrintToUser("Adopted %s on %s", chainName, a.network.String())
+	ux.Logger.PrintToUser("  Chain ID:      %s", a.chainID)
+	ux.Logger.PrintToUser("  Blockchain ID: %s (%s)", a.blockchain.ID, a.blockchain.Name)
+	ux.Logger.PrintToUser("  VM:            %s (%s)", sc.VM, a.blockchain.VMID)
+	if sc.Sovereign {
+		ux.Logger.PrintToUser("  Type:          sovereign L1, validator manager %s on %s", sc.Networks[a.network.String()].ValidatorManagerAddress, a.chainInfo.ManagerChainID)
+	}
+	if err := printAdoptedOwners(a.network, a.chainInfo); err != nil {
+		return err
+	}
+	printAdoptedValidators(a.validators)
+	return nil
+}
+
+// writeAdoptedSidecar creates or updates sc
+func writeAdoptedSidecar(sc *models.Sidecar) error {
+	var err error
+	if app.SidecarExists(sc.Name) {
+		err = app.UpdateSidecar(sc)
+	} else {
+		err = app.CreateSidecar(sc)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write sidecar for %s: %w", sc.Name, err)
+	}
+	return nil
+}
+
+// adoptedBlockchain returns the blockchain of chainID to adopt
+func adoptedBlockchain(pClient *platformvm.Client, chainID ids.ID, blockchainID string) (platformvm.APIBlockchain, error) {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	blockchains, err := pClient.GetBlockchains(ctx)
+	if err != nil {
+		return platformvm.APIBlockchain{}, fmt.Errorf("failed to get blockchains: %w", err)
+	}
+	return selectAdoptedBlockchain(blockchains, chainID, blockchainID)
+}
+
+// selectAdoptedBlockchain picks blockchainID, or the only blockchain, among
+// the blockchains validated by chainID
+func selectAdoptedBlockchain(blockchains []platformvm.APIBlockchain, chainID ids.ID, blockchainID string) (platformvm.APIBlockchain, error) {
+	var validated []platformvm.APIBlockchain
+	for _, b := range blockchains {
+		if b.NetID == chainID {
+			validated = append(validated, b)
+		}
+	}
+	if blockchainID != "" {
+		for _, b := range validated {
+			if b.ID.String() == blockchainID {
+				return b, nil
+			}
+		}
+		return platformvm.APIBlockchain{}, fmt.Errorf("blockchain %s is not validated by %s", blockchainID, chainID)
+	}
+	switch len(validated) {
+	case 0:
+		return platformvm.APIBlockchain{}, fmt.Errorf("chain %s validates no blockchains", chainID)
+	case 1:
+		return validated[0], nil
+	}
+	names := make([]string, len(validated))
+	for i, b := range validated {
+		names[i] = fmt.Sprintf("%s (%s)", b.ID, b.Name)
+	}
+	return platformvm.APIBlockchain{}, fmt.Errorf("chain %s validates several blockchains, pick one with --blockchain-id: %s", chainID, strings.Join(names, ", "))
+}
+
+func adoptedVMType(vmID ids.ID) models.VMType {
+	if vmID == constants.EVMID {
+		return models.EVM
+	}
+	if evmID, err := utils.VMID(LuxEVMName); err == nil && vmID == evmID {
+		return models.EVM
+	}
+	return models.CustomVM
+}
+
+func printAdoptedOwners(network models.Network, chainInfo platformvm.GetNetClientResponse) error {
+	if !chainInfo.IsPermissioned {
+		ux.Logger.PrintToUser("  Owners:        none (permissionless)")
+		return nil
+	}
+	hrp := key.GetHRP(network.ID())
+	owners := make([]string, 0, len(chainInfo.ControlKeys))
+	for _, addr := range chainInfo.ControlKeys {
+		owner, err := address.Format("P", hrp, addr[:])
+		if err != nil {
+			return err
+		}
+		owners = append(owners, owner)
+	}
+	ux.Logger.PrintToUser("  Owners:        %d of %s", chainInfo.Threshold, strings.Join(owners, ", "))
+	return nil
+}
+
+func printAdoptedValidators(validators []platformvm.ClientPermissionlessValidator) {
+	if len(validators) == 0 {
+		ux.Logger.PrintToUser("  Validators:    none")
+		return
+	}
+	ux.Logger.PrintToUser("")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Node ID", "Weight")
+	for _, v := range validators {
+		_ = table.Append([]string{v.NodeID.String(), strconv.FormatUint(v.Weight, 10)})
+	}
+	_ = table.Render()
+}
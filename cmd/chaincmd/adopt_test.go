@@ -0,0 +1,88 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"testing"
+
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+	"github.com/luxfi/sdk/platformvm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectAdoptedBlockchain(t *testing.T) {
+	chainID := ids.GenerateTestID()
+	other := platformvm.APIBlockchain{ID: ids.GenerateTestID(), NetID: ids.GenerateTestID(), Name: "other"}
+	first := platformvm.APIBlockchain{ID: ids.GenerateTestID(), NetID: chainID, Name: "first"}
+	second := platformvm.APIBlockchain{ID: ids.GenerateTestID(), NetID: chainID, Name: "second"}
+
+	tests := []struct {
+		name         string
+		blockchains  []platformvm.APIBlockchain
+		blockchainID string
+		want         platformvm.APIBlockchain
+		wantErr      string
+	}{
+		{
+			name:        "only blockchain",
+			blockchains: []platformvm.APIBlockchain{other, first},
+			want:        first,
+		},
+		{
+			name:        "no blockchain",
+			blockchains: []platformvm.APIBlockchain{other},
+			wantErr:     "validates no blockchains",
+		},
+		{
+			name:        "several blockchains",
+			blockchains: []platformvm.APIBlockchain{first, other, second},
+			wantErr:     "pick one with --blockchain-id",
+		},
+		{
+			name:         "picked blockchain",
+			blockchains:  []platformvm.APIBlockchain{first, other, second},
+			blockchainID: second.ID.String(),
+			want:         second,
+		},
+		{
+			name:         "picked blockchain of another chain",
+			blockchains:  []platformvm.APIBlockchain{first, other},
+			blockchainID: other.ID.String(),
+			wantErr:      "is not validated by",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectAdoptedBlockchain(tt.blockchains, chainID, tt.blockchainID)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAdoptedVMType(t *testing.T) {
+	require := require.New(t)
+	require.Equal(models.EVM, adoptedVMType(constants.EVMID))
+	evmID, err := utils.VMID(LuxEVMName)
+	require.NoError(err)
+	require.Equal(models.EVM, adoptedVMType(evmID))
+	require.Equal(models.CustomVM, adoptedVMType(ids.GenerateTestID()))
+}
+
+func TestAdoptSubnetIDAlias(t *testing.T) {
+	require := require.New(t)
+	t.Cleanup(func() { adoptChainID = "" })
+	cmd := newAdoptCmd()
+	chainID := ids.GenerateTestID().String()
+	require.NoError(cmd.ParseFlags([]string{"--subnet-id", chainID}))
+	require.Equal(chainID, adoptChainID)
+	require.True(cmd.Flags().Lookup("subnet-id").Hidden)
+}
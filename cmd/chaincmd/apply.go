@@ -0,0 +1,269 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/chain"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/validator"
+	"github.com/luxfi/cli/pkg/vm"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+)
+
+// CreateChainOptions are the settings of `lux chain create` for callers that
+// drive the CLI without parsing flags, like `lux network apply`.
+type CreateChainOptions struct {
+	Type        string // l1, l2 or l3
+	VM          string // evm (default), pars or custom
+	VMBinary    string // custom VM binary
+	Genesis     string // genesis file, generated when empty
+	ChainID     uint64
+	TokenName   string
+	TokenSymbol string
+	// Allocations maps addresses to balances in wei (decimal or 0x hex) and
+	// replaces the allocations of the generated genesis
+	Allocations map[string]string
+}
+
+// CreateChain creates a chain configuration the way `lux chain create` does,
+// using defaults instead of prompting for missing values.
+func CreateChain(chainName string, opts CreateChainOptions) error {
+	prevType, prevGenesis, prevBin := chainType, genesisFile, customVMBin
+	prevEVM, prevPars, prevCustom := useEVM, useParsVM, useCustomVM
+	prevChainID, prevTokenName, prevTokenSymbol := evmChainID, tokenName, tokenSymbol
+	defer func() {
+		chainType, genesisFile, customVMBin = prevType, prevGenesis, prevBin
+		useEVM, useParsVM, useCustomVM = prevEVM, prevPars, prevCustom
+		evmChainID, tokenName, tokenSymbol = prevChainID, prevTokenName, prevTokenSymbol
+	}()
+
+	chainType = opts.Type
+	if chainType == "" {
+		chainType = "l2"
+	}
+	genesisFile = opts.Genesis
+	customVMBin = opts.VMBinary
+	useEVM, useParsVM, useCustomVM = opts.VM == "" || opts.VM == "evm", opts.VM == "pars", opts.VM == "custom"
+
+	// Fill the values createChain would prompt for
+	evmChainID = opts.ChainID
+	if evmChainID == 0 {
+		evmChainID = 200200
+		if useParsVM {
+			evmChainID = vm.ParsDefaultChainID
+		}
+	}
+	tokenName, tokenSymbol = opts.TokenName, opts.TokenSymbol
+	if tokenName == "" {
+		tokenName = "TOKEN"
+	}
+	if tokenSymbol == "" {
+		tokenSymbol = "TKN"
+	}
+
+	if err := createChain(nil, []string{chainName}); err != nil {
+		return err
+	}
+	if len(opts.Allocations) == 0 {
+		return nil
+	}
+	return setGenesisAllocations(chainName, opts.Allocations)
+}
+
+// setGenesisAllocations replaces the alloc section of an EVM chain genesis
+func setGenesisAllocations(chainName string, allocations map[string]string) error {
+	genesisPath := filepath.Join(app.GetChainsDir(), chainName, constants.GenesisFileName)
+	data, err := os.ReadFile(genesisPath) //nolint:gosec // G304: Reading from app's data directory
+	if err != nil {
+		return err
+	}
+	var genesis map[string]interface{}
+	if err := json.Unmarshal(data, &genesis); err != nil {
+		return fmt.Errorf("invalid genesis format: %w", err)
+	}
+	alloc := map[string]interface{}{}
+	for address, balance := range allocations {
+		address = strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X")
+		alloc[address] = map[string]interface{}{"balance": balance}
+	}
+	genesis["alloc"] = alloc
+	data, err = json.MarshalIndent(genesis, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(genesisPath, data, constants.WriteReadReadPerms)
+}
+
+// SetChainWarp updates the Warp messaging and relayer settings of a chain
+func SetChainWarp(chainName string, enabled, relayer bool, relayerKey string) error {
+	sc, err := app.LoadSidecar(chainName)
+	if err != nil {
+		return err
+	}
+	sc.WarpEnabled = enabled
+	sc.RunRelayer = relayer
+	sc.TeleporterKey = relayerKey
+	return app.UpdateSidecar(&sc)
+}
+
+// ChainWarp returns the Warp messaging and relayer settings of a chain
+func ChainWarp(chainName string) (enabled, relayer bool, relayerKey string, err error) {
+	sc, err := app.LoadSidecar(chainName)
+	if err != nil {
+		return false, false, "", err
+	}
+	return sc.WarpEnabled, sc.RunRelayer, sc.TeleporterKey, nil
+}
+
+// NetworkFromName maps mainnet, testnet, devnet and local to their network
+func NetworkFromName(name string) (models.Network, error) {
+	switch name {
+	case "mainnet":
+		return models.Mainnet, nil
+	case "testnet":
+		return models.Testnet, nil
+	case "devnet":
+		return models.Devnet, nil
+	case "local":
+		return models.Local, nil
+	}
+	return models.Undefined, fmt.Errorf("unknown network %q, expected mainnet, testnet, devnet or local", name)
+}
+
+// DeployChain deploys a chain the way `lux chain deploy --<network>` does.
+// keyName pays for remote deployments, the environment key is used when empty.
+func DeployChain(chainName, networkName, keyName string) error {
+	network, err := NetworkFromName(networkName)
+	if err != nil {
+		return err
+	}
+	prevLocal, prevTestnet, prevMainnet, prevDevnet := deployLocal, deployTestnet, deployMainnet, deployDevnet
+	prevKey, prevTimeout, prevVersion := deployKeyName, deployTimeout, nodeVersion
+	defer func() {
+		deployLocal, deployTestnet, deployMainnet, deployDevnet = prevLocal, prevTestnet, prevMainnet, prevDevnet
+		deployKeyName, deployTimeout, nodeVersion = prevKey, prevTimeout, prevVersion
+	}()
+	deployLocal = network == models.Local
+	deployTestnet = network == models.Testnet
+	deployMainnet = network == models.Mainnet
+	deployDevnet = network == models.Devnet
+	deployKeyName = keyName
+	if deployTimeout == 0 {
+		deployTimeout = DefaultDeployTimeout
+	}
+	if nodeVersion == "" {
+		nodeVersion = "latest"
+	}
+	return deployChain(nil, []string{chainName})
+}
+
+// ChainDeployed reports whether the chain sidecar records a deployment to the network
+func ChainDeployed(chainName, networkName string) (bool, error) {
+	network, err := NetworkFromName(networkName)
+	if err != nil {
+		return false, err
+	}
+	sc, err := app.LoadSidecar(chainName)
+	if err != nil {
+		return false, err
+	}
+	_, ok := sc.Networks[network.String()]
+	return ok, nil
+}
+
+// deployedChainID returns the ID of the validator set of a deployed chain
+func deployedChainID(chainName string, network models.Network) (ids.ID, error) {
+	sc, err := app.LoadSidecar(chainName)
+	if err != nil {
+		return ids.Empty, err
+	}
+	data, ok := sc.Networks[network.String()]
+	if !ok || data.ChainID == ids.Empty {
+		return ids.Empty, fmt.Errorf("chain %s is not deployed to %s", chainName, network.String())
+	}
+	return data.ChainID, nil
+}
+
+// ChainValidators returns the NodeIDs currently validating a deployed chain
+func ChainValidators(chainName, networkName string) ([]string, error) {
+	network, err := NetworkFromName(networkName)
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := deployedChainID(chainName, network)
+	if err != nil {
+		return nil, err
+	}
+	validators, err := validator.GetCurrentValidators(network, chainID)
+	if err != nil {
+		return nil, err
+	}
+	nodeIDs := make([]string, 0, len(validators))
+	for _, v := range validators {
+		nodeIDs = append(nodeIDs, v.NodeID.String())
+	}
+	return nodeIDs, nil
+}
+
+// AddChainValidator adds nodeID as a validator of a deployed chain, paid and
+// authorized by keyName (the environment key when empty).
+func AddChainValidator(chainName, networkName, nodeIDStr string, weight uint64, duration time.Duration, keyName string) error {
+	network, err := NetworkFromName(networkName)
+	if err != nil {
+		return err
+	}
+	nodeID, err := ids.NodeIDFromString(nodeIDStr)
+	if err != nil {
+		return fmt.Errorf("invalid NodeID %s: %w", nodeIDStr, err)
+	}
+	chainID, err := deployedChainID(chainName, network)
+	if err != nil {
+		return err
+	}
+
+	prevKey := deployKeyName
+	defer func() { deployKeyName = prevKey }()
+	deployKeyName = keyName
+	kc, err := getDeployKeychain(network, network.ID())
+	if err != nil {
+		return fmt.Errorf("failed to get keychain: %w", err)
+	}
+	chainAuthKeys, err := kc.PChainFormattedStrAddresses()
+	if err != nil {
+		return err
+	}
+
+	deployer := chain.NewPublicDeployer(app, kc.UsesLedger, kc.Keychain, network)
+	// Start shortly in the future so the tx is still valid when accepted
+	start := time.Now().Add(constants.StakingStartLeadTime)
+	isFullySigned, _, remaining, err := deployer.AddValidator(chainAuthKeys, chainAuthKeys, chainID, nodeID, weight, start, duration)
+	if err != nil {
+		return err
+	}
+	if !isFullySigned {
+		return fmt.Errorf("adding validator %s requires more signatures from %s", nodeIDStr, strings.Join(remaining, ", "))
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "validator add",
+		Network:   network.String(),
+		Chain:     chainName,
+		Params: map[string]string{
+			"nodeID":   nodeIDStr,
+			"weight":   strconv.FormatUint(weight, 10),
+			"start":    start.UTC().Format(time.RFC3339),
+			"duration": duration.String(),
+		},
+	})
+	return nil
+}
@@ -77,14 +77,42 @@ CHAIN TYPES:
 CORE COMMANDS:
 
   create       Create a new blockchain configuration
+  genesis      Build EVM genesis files and reusable genesis templates
+  preset       Manage the genesis and chain config presets of create
   deploy       Deploy to local network, testnet, or mainnet
+  deploy-raw   Deploy a raw genesis and VM ID with the standard keychain
   list         List all configured blockchains
   describe     Show detailed blockchain information
   delete       Delete a blockchain configuration
+  adopt        Manage a chain created outside the CLI
+  import-deployed  Import a deployed chain with its genesis and validator manager
 
 DATA OPERATIONS:
 
-  import       Import blocks from RLP file to running chain
+  import            Import blocks from RLP file to running chain
+  export-data       Export blocks, receipts and state from a local chain database
+  export-artifacts  Export a signed bundle of what was launched, for auditors
+  verify-artifacts  Verify the signature and files of an artifacts bundle
+  supply            Report the native token supply, allocations and top holders
+
+UPGRADES:
+
+  upgrade          Generate, import and apply chain upgrades
+  upgrade-status   Show which upgrade activations are live on each chain
+  feeconfig        Show and tune the gas and fee config of an EVM chain
+
+ACCOUNT ABSTRACTION:
+
+  aa deploy    Deploy the ERC-4337 EntryPoint and a paymaster, emit the bundler config
+  relayer      Deploy an ERC-2771 trusted forwarder and relay gasless transactions
+
+TRANSACTIONS:
+
+  index start  Index a chain's blocks, transactions and logs locally
+  txs          List the latest indexed transactions
+  tx           Show an indexed transaction
+  account      Show an account's balance and indexed transactions
+  logs         List indexed event logs
 
 NETWORK FLAGS (for deployment):
 
@@ -138,11 +166,17 @@ NOTES:
 	createCmd := newCreateCmd()
 	addNetworkFlags(createCmd)
 	cmd.AddCommand(createCmd)
+	cmd.AddCommand(newGenesisCmd())
+	cmd.AddCommand(newPresetCmd())
 
 	deployCmd := newDeployCmd()
 	// Note: deploy already has network flags, skip adding duplicates
 	cmd.AddCommand(deployCmd)
 
+	deployRawCmd := newDeployRawCmd()
+	addNetworkFlags(deployRawCmd)
+	cmd.AddCommand(deployRawCmd)
+
 	listCmd := newListCmd()
 	addNetworkFlags(listCmd)
 	cmd.AddCommand(listCmd)
@@ -155,17 +189,57 @@ NOTES:
 	addNetworkFlags(deleteCmd)
 	cmd.AddCommand(deleteCmd)
 
+	adoptCmd := newAdoptCmd()
+	addNetworkFlags(adoptCmd)
+	cmd.AddCommand(adoptCmd)
+
+	importDeployedCmd := newImportDeployedCmd()
+	addNetworkFlags(importDeployedCmd)
+	cmd.AddCommand(importDeployedCmd)
+
 	// Data operations
 	importCmd := newImportCmd()
 	addNetworkFlags(importCmd)
 	cmd.AddCommand(importCmd)
+	exportDataCmd := newExportDataCmd()
+	addNetworkFlags(exportDataCmd)
+	cmd.AddCommand(exportDataCmd)
+	cmd.AddCommand(newExportArtifactsCmd())
+	cmd.AddCommand(newVerifyArtifactsCmd())
+	supplyCmd := newSupplyCmd()
+	addNetworkFlags(supplyCmd)
+	cmd.AddCommand(supplyCmd)
 
 	// Upgrade
 	cmd.AddCommand(upgradecmd.NewCmd(app))
+	upgradeStatusCmd := newUpgradeStatusCmd()
+	addNetworkFlags(upgradeStatusCmd)
+	cmd.AddCommand(upgradeStatusCmd)
+	cmd.AddCommand(newFeeConfigCmd())
+
+	// Account abstraction
+	cmd.AddCommand(newAACmd())
+	cmd.AddCommand(newRelayerCmd())
+
+	// Transaction index
+	cmd.AddCommand(newIndexCmd())
+	txsCmd := newTxsCmd()
+	addNetworkFlags(txsCmd)
+	cmd.AddCommand(txsCmd)
+	txCmd := newTxCmd()
+	addNetworkFlags(txCmd)
+	cmd.AddCommand(txCmd)
+	accountCmd := newAccountCmd()
+	addNetworkFlags(accountCmd)
+	cmd.AddCommand(accountCmd)
+	logsCmd := newLogsCmd()
+	addNetworkFlags(logsCmd)
+	cmd.AddCommand(logsCmd)
 
 	// Launch — full ecosystem deployment from chain.yaml
 	launchCmd := newLaunchCmd()
 	cmd.AddCommand(launchCmd)
+	cmd.AddCommand(newLaunchChecklistCmd())
 
 	return cmd
 }
@@ -14,8 +14,10 @@ import (
 	"strings"
 
 	"github.com/luxfi/cli/pkg/prompts"
+	"github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/cli/pkg/ux"
 	"github.com/luxfi/cli/pkg/vm"
+	"github.com/luxfi/cli/pkg/vmregistry"
 	"github.com/luxfi/constants"
 	"github.com/luxfi/evm/core"
 	"github.com/luxfi/sdk/models"
@@ -27,6 +29,8 @@ var (
 	sequencerType    string // lux, ethereum, op, external
 	forceCreate      bool
 	genesisFile      string
+	templateName     string
+	presetName       string
 	customVMBin      string
 	useEVM           bool
 	useCustomVM      bool
@@ -73,7 +77,9 @@ VM OPTIONS:
   --evm          Use Lux EVM (default)
   --pars         Use Pars VM (post-quantum messaging)
   --custom-vm    Use custom VM binary
-  --vm           Path to custom VM binary
+  --vm           Path to custom VM binary, or a VM installed with
+                 'lux vm install' as <name>[@version] (default: newest).
+                 Without --vm, the installed VMs are offered to pick from.
   --vm-version   Specific VM version (default: latest)
   --latest       Use latest VM version
 
@@ -81,6 +87,11 @@ GENESIS OPTIONS:
 
   --genesis           Path to custom genesis.json file
                       If not provided, generates default EVM genesis
+  --template          Genesis template from ~/.lux/templates/, built with
+                      'lux chain genesis wizard --save-template'
+  --preset            Genesis and chain config preset, as <name>[@v<version>]
+                      (default: newest version): high-throughput, low-latency,
+                      archival, gaming, or one saved with 'lux chain preset save'
   --evm-chain-id      EVM chain ID (default: 200200)
   --token-name        Native token name (default: TOKEN)
   --token-symbol      Native token symbol (default: TKN)
@@ -116,12 +127,22 @@ EXAMPLES:
   # Create with custom genesis
   lux chain create mychain --genesis=~/custom-genesis.json
 
+  # Create from a genesis template with its own chain ID
+  lux chain create mychain --template=gaming --evm-chain-id=12345
+
+  # Create from a preset, its genesis and chain config
+  lux chain create mygame --preset=gaming
+  lux chain create myindexer --preset=archival@v1
+
   # Create L3 on existing L2
   lux chain create myapp --type=l3
 
   # Overwrite existing configuration
   lux chain create mychain --force
 
+  # Create from a VM installed with 'lux vm install'
+  lux chain create mychain --custom-vm --vm myvm@v0.1.0
+
   # Create with pre-confirmations enabled
   lux chain create mychain --enable-preconfirm
 
@@ -139,6 +160,8 @@ OUTPUT:
   Creates two files in ~/.lux/chains/<chainName>/:
   - genesis.json    Blockchain genesis configuration
   - sidecar.json    Metadata (VM type, versions, deployment info)
+  and with --preset the chain config of the preset:
+  - config.json     EVM chain config (pruning, tx pool, APIs...)
 
 NEXT STEPS:
 
@@ -162,7 +185,9 @@ NOTES:
 	cmd.Flags().StringVar(&sequencerType, "sequencer", "lux", "Sequencer: lux, ethereum, op, external")
 	cmd.Flags().BoolVarP(&forceCreate, "force", "f", false, "Overwrite existing configuration")
 	cmd.Flags().StringVar(&genesisFile, "genesis", "", "Path to custom genesis file")
-	cmd.Flags().StringVar(&customVMBin, "vm", "", "Path to custom VM binary")
+	cmd.Flags().StringVar(&templateName, "template", "", "Genesis template to create the EVM genesis from")
+	cmd.Flags().StringVar(&presetName, "preset", "", "Preset to create the EVM genesis and chain config from, <name>[@v<version>]")
+	cmd.Flags().StringVar(&customVMBin, "vm", "", "Path to custom VM binary, or installed VM <name>[@version]")
 	cmd.Flags().BoolVar(&useEVM, "evm", false, "Use Lux EVM")
 	cmd.Flags().BoolVar(&useParsVM, "pars", false, "Use Pars VM (post-quantum messaging)")
 	cmd.Flags().BoolVar(&useCustomVM, "custom-vm", false, "Use custom VM")
@@ -210,13 +235,61 @@ func createChain(cmd *cobra.Command, args []string) error {
 	// Handle genesis
 	var chainGenesis []byte
 	var err error
-	if genesisFile != "" {
+	var templateChainID uint64
+	var chainConfig []byte
+	switch {
+	case templateName != "" && genesisFile != "":
+		return errors.New("--template and --genesis are mutually exclusive")
+	case presetName != "" && (templateName != "" || genesisFile != ""):
+		return errors.New("--preset, --template and --genesis are mutually exclusive")
+	case (templateName != "" || presetName != "") && vmType != models.EVM:
+		return errors.New("genesis templates and presets are only supported for EVM chains")
+	case presetName != "":
+		preset, err := vm.LoadPreset(app.GetBaseDir(), presetName)
+		if err != nil {
+			return err
+		}
+		template := preset.Genesis
+		if evmChainID != 0 {
+			template.ChainID = evmChainID
+		}
+		if len(template.Allocations) == 0 {
+			// fund the airdrop account, as the default genesis does
+			params := getGenesisParams()
+			balance, ok := new(big.Int).SetString(strings.TrimPrefix(params.airdropAmount, "0x"), 16)
+			if !ok {
+				return fmt.Errorf("invalid airdrop amount %s", params.airdropAmount)
+			}
+			template.Allocations = []vm.AllocationEntry{{Address: "0x" + params.airdropAddress, Balance: balance.String()}}
+		}
+		if chainGenesis, err = template.Genesis(); err != nil {
+			return fmt.Errorf("failed to generate genesis from preset %s: %w", preset.Ref(), err)
+		}
+		if chainConfig, err = preset.ChainConfigJSON(); err != nil {
+			return err
+		}
+		templateChainID = template.ChainID
+		ux.Logger.PrintToUser("Using preset %s: %s", preset.Ref(), preset.Description)
+	case templateName != "":
+		template, err := vm.LoadGenesisTemplate(app.GetBaseDir(), templateName)
+		if err != nil {
+			return err
+		}
+		if evmChainID != 0 {
+			template.ChainID = evmChainID
+		}
+		if chainGenesis, err = template.Genesis(); err != nil {
+			return fmt.Errorf("failed to generate genesis from template %s: %w", templateName, err)
+		}
+		templateChainID = template.ChainID
+		ux.Logger.PrintToUser("Using genesis template %s", templateName)
+	case genesisFile != "":
 		chainGenesis, err = os.ReadFile(genesisFile) //nolint:gosec // G304: User-specified genesis file
 		if err != nil {
 			return fmt.Errorf("failed to read genesis file: %w", err)
 		}
 		ux.Logger.PrintToUser("Importing genesis")
-	} else {
+	default:
 		// Generate default genesis based on VM type
 		switch vmType {
 		case models.ParsVM:
@@ -239,6 +312,9 @@ func createChain(cmd *cobra.Command, args []string) error {
 
 	// Resolve chain ID - prompt if not provided and interactive
 	resolvedChainID := evmChainID
+	if templateChainID != 0 {
+		resolvedChainID = templateChainID
+	}
 	defaultChainID := uint64(200200)
 	if vmType == models.ParsVM {
 		defaultChainID = vm.ParsDefaultChainID // 7070 for Pars
@@ -330,11 +406,22 @@ func createChain(cmd *cobra.Command, args []string) error {
 	}
 
 	// Handle custom VM
-	if useCustomVM && customVMBin != "" {
-		if err := vm.CopyCustomVM(app, chainName, customVMBin); err != nil {
-			return fmt.Errorf("failed to copy custom VM binary: %w", err)
+	if useCustomVM {
+		vmBin, installed, err := resolveCustomVM()
+		if err != nil {
+			return err
+		}
+		if vmBin != "" {
+			if err := vm.CopyCustomVM(app, chainName, vmBin); err != nil {
+				return fmt.Errorf("failed to copy custom VM binary: %w", err)
+			}
+			ux.Logger.PrintToUser("Copied custom VM binary")
+		}
+		if installed != nil {
+			sc.VMVersion = installed.Version
+			sc.RPCVersion = installed.RPCProtocol
+			ux.Logger.PrintToUser("Using installed VM %s (rpcchainvm %d)", installed.Ref(), installed.RPCProtocol)
 		}
-		ux.Logger.PrintToUser("Copied custom VM binary")
 	}
 
 	// Get VM version and RPC version if using EVM
@@ -360,6 +447,12 @@ func createChain(cmd *cobra.Command, args []string) error {
 	if err := os.WriteFile(genesisPath, chainGenesis, constants.WriteReadReadPerms); err != nil {
 		return fmt.Errorf("failed to write genesis: %w", err)
 	}
+	if chainConfig != nil {
+		chainConfigPath := filepath.Join(chainDir, constants.ChainConfigFile)
+		if err := os.WriteFile(chainConfigPath, chainConfig, constants.WriteReadReadPerms); err != nil {
+			return fmt.Errorf("failed to write chain config: %w", err)
+		}
+	}
 
 	// Write sidecar
 	if err := app.CreateSidecar(&sc); err != nil {
@@ -385,6 +478,51 @@ func createChain(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// resolveCustomVM returns the custom VM binary given by --vm, a path or a VM
+// installed in the VM registry, and the installed VM if it is one. Without
+// --vm, the installed VMs are offered when interactive.
+func resolveCustomVM() (string, *vmregistry.VM, error) {
+	registry := app.GetVMRegistry()
+	if customVMBin != "" {
+		if _, err := os.Stat(utils.GetRealFilePath(customVMBin)); err == nil {
+			return customVMBin, nil, nil
+		}
+		installed, err := registry.Get(vmregistry.ParseRef(customVMBin))
+		if err != nil {
+			return "", nil, fmt.Errorf("--vm %s is neither a binary nor an installed VM (see 'lux vm list --installed'): %w",
+				customVMBin, err)
+		}
+		return installed.BinaryPath(), installed, nil
+	}
+
+	if !prompts.IsInteractive() {
+		return "", nil, nil
+	}
+	vms, err := registry.List()
+	if err != nil || len(vms) == 0 {
+		return "", nil, err
+	}
+	const pathOption = "Enter a binary path"
+	options := make([]string, 0, len(vms)+1)
+	for _, v := range vms {
+		options = append(options, v.Ref())
+	}
+	options = append(options, pathOption)
+	choice, err := app.Prompt.CaptureList("Which installed VM should the chain run?", options)
+	if err != nil {
+		return "", nil, err
+	}
+	if choice == pathOption {
+		vmBin, err := app.Prompt.CaptureExistingFilepath("Enter path to custom VM binary")
+		return vmBin, nil, err
+	}
+	installed, err := registry.Get(vmregistry.ParseRef(choice))
+	if err != nil {
+		return "", nil, err
+	}
+	return installed.BinaryPath(), installed, nil
+}
+
 func validateChainName(name string) error {
 	if name == "" {
 		return errors.New("chain name cannot be empty")
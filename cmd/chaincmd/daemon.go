@@ -0,0 +1,31 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import "fmt"
+
+// DeployChain deploys chainName the way `lux chain deploy <chainName>
+// --<networkType>` does, with the key keyName when set. It is used by
+// callers that drive the CLI without parsing flags.
+func DeployChain(chainName, networkType, keyName string) error {
+	prevMainnet, prevTestnet, prevDevnet, prevLocal, prevKey := deployMainnet, deployTestnet, deployDevnet, deployLocal, deployKeyName
+	defer func() {
+		deployMainnet, deployTestnet, deployDevnet, deployLocal, deployKeyName = prevMainnet, prevTestnet, prevDevnet, prevLocal, prevKey
+	}()
+	deployMainnet, deployTestnet, deployDevnet, deployLocal = false, false, false, false
+	switch networkType {
+	case "mainnet":
+		deployMainnet = true
+	case "testnet":
+		deployTestnet = true
+	case "devnet":
+		deployDevnet = true
+	case "local", "":
+		deployLocal = true
+	default:
+		return fmt.Errorf("unknown network %q, expected mainnet, testnet, devnet or local", networkType)
+	}
+	deployKeyName = keyName
+	return deployChain(nil, []string{chainName})
+}
@@ -14,18 +14,25 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/luxfi/cli/pkg/application"
 	"github.com/luxfi/cli/pkg/binutils"
 	"github.com/luxfi/cli/pkg/chain"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/hooks"
 	"github.com/luxfi/cli/pkg/key"
 	"github.com/luxfi/cli/pkg/keychain"
 	"github.com/luxfi/cli/pkg/localnetworkinterface"
+	"github.com/luxfi/cli/pkg/status"
 	"github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/warp"
 	"github.com/luxfi/evm/core"
+	"github.com/luxfi/ids"
 	"github.com/luxfi/sdk/models"
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
@@ -40,6 +47,10 @@ const (
 	LuxEVMName = "Lux EVM"
 	// RemoteProbeTimeout is the timeout for probing a remote network endpoint
 	RemoteProbeTimeout = 30 * time.Second
+	// deploySteps is the number of progress steps of a deploy: load, target,
+	// the vm, compatibility and deploy steps of a local deploy or the
+	// keychain, chain and blockchain steps of a remote one, and warp
+	deploySteps = 6
 )
 
 var (
@@ -50,6 +61,9 @@ var (
 	nodeVersion   string
 	deployTimeout time.Duration
 	deployKeyName string
+	deployDryRun  bool
+
+	skipLaunchChecklist bool
 )
 
 func newDeployCmd() *cobra.Command {
@@ -91,6 +105,9 @@ OPTIONS:
 
   --node-version   Specific luxd version to use (default: latest)
   --key            Key name for remote network deployment (from ~/.lux/keys/)
+  --dry-run        Build the transactions and estimate fees without deploying
+  --skip-launch-checklist
+                   Deploy to testnet or mainnet despite failed launch checks
 
 EXAMPLES:
 
@@ -111,6 +128,9 @@ EXAMPLES:
   lux chain deploy mychain --mainnet
   lux chain deploy mychain -m
 
+  # Preview the P-chain transactions and fees of a remote deploy
+  lux chain deploy mychain --testnet --key mykey --dry-run
+
   # Deploy with specific node version
   lux chain deploy mychain --devnet --node-version v1.11.0
 
@@ -126,9 +146,11 @@ DEPLOYMENT PROCESS:
   Remote network:
   1. Validates chain configuration exists
   2. Probes remote endpoint (e.g., https://api.lux-dev.network)
-  3. Creates chain on P-chain via wallet transaction
-  4. Creates blockchain on P-chain via wallet transaction
-  5. Updates sidecar with deployment info
+  3. On testnet and mainnet, runs the launch checklist (see
+     'lux chain launch-checklist') and stops if a check fails
+  4. Creates chain on P-chain via wallet transaction
+  5. Creates blockchain on P-chain via wallet transaction
+  6. Updates sidecar with deployment info
 
 OUTPUT:
 
@@ -167,15 +189,33 @@ NOTES:
 	cmd.Flags().BoolVarP(&deployDevnet, "devnet", "d", false, "Deploy to devnet")
 	cmd.Flags().StringVar(&nodeVersion, "node-version", "latest", "Node version to use")
 	cmd.Flags().DurationVar(&deployTimeout, "timeout", DefaultDeployTimeout, "Maximum time to wait for chain deployment (e.g., 60s, 2m)")
-	cmd.Flags().StringVar(&deployKeyName, "key", "", "Key name for remote network deployment (from ~/.lux/keys/)")
+	cmd.Flags().StringVar(&deployKeyName, "key", "", "Key name for remote network deployment (from ~/.lux/keys/), kms://<keyId> or mpc://<walletId>")
+	cmd.Flags().BoolVar(&deployDryRun, "dry-run", false, "Build the deploy transactions and estimate fees without signing or broadcasting them")
+	cmd.Flags().BoolVar(&skipLaunchChecklist, "skip-launch-checklist", false, "Deploy to testnet or mainnet even if the launch checklist fails")
 
 	return cmd
 }
 
-func deployChain(cmd *cobra.Command, args []string) error {
+func deployChain(cmd *cobra.Command, args []string) (err error) {
 	chainName := args[0]
 
+	lock, err := app.AcquireLock("chain deploy " + chainName)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	progress := ux.Progress.Start("chain deploy", deploySteps)
+	defer func() {
+		if err != nil {
+			_ = progress.Fail(err)
+			return
+		}
+		progress.Done("Deployed %s", chainName)
+	}()
+
 	// Load sidecar
+	progress.Step("load", "Loading %s", chainName)
 	sc, err := app.LoadSidecar(chainName)
 	if err != nil {
 		err = fmt.Errorf("chain %s not found. Create it first with: lux chain create %s", chainName, chainName)
@@ -218,12 +258,33 @@ func deployChain(cmd *cobra.Command, args []string) error {
 
 	ux.Logger.PrintToUser("Deploying %s to %s", chainName, network.String())
 
-	// All deployments use the same flow - deploy to locally running network
-	if err := deployToNetwork(chainName, chainGenesis, &sc, network); err != nil {
-		ux.Logger.PrintError("%s", err)
-		return err
+	hc := hooks.Context{Network: strings.ToLower(network.String()), Chain: chainName, Args: args}
+	return app.RunWithHooks(hooks.Deploy, hc, func() error {
+		// All deployments use the same flow - deploy to locally running network
+		if err := deployToNetwork(progress, chainName, chainGenesis, &sc, network); err != nil {
+			ux.Logger.PrintError("%s", err)
+			return err
+		}
+		progress.Step("warp", "Syncing the Warp registries of %s", network.String())
+		reconcileWarp(network)
+		return nil
+	})
+}
+
+// reconcileWarp makes the chains already deployed to network and the new
+// one aware of each other's Warp messengers. Failures are not fatal to the
+// deploy: the sync can be retried with 'lux warp sync-registries'.
+func reconcileWarp(network models.Network) {
+	ux.Logger.PrintToUser("")
+	opts := warp.SyncOptions{RelayerConfigPath: app.GetLocalRelayerConfigPath()}
+	if err := warp.SyncRegistries(app, network, opts); err != nil {
+		ux.Logger.PrintToUser("Failed to sync the Warp registries: %s", err)
+		retry := "lux warp sync-registries"
+		if network != models.Local {
+			retry += " --" + strings.ToLower(network.String())
+		}
+		ux.Logger.PrintToUser("Retry with: %s", retry)
 	}
-	return nil
 }
 
 // verifyVMInstalled checks that the VM plugin is installed before deployment.
@@ -334,13 +395,12 @@ func probeRemoteEndpoint(endpoint string) bool {
 	// Use a client with short per-IP dial timeout and skip TLS verify.
 	// DNS may return multiple IPs where some are unreachable.
 	dialer := &net.Dialer{Timeout: 5 * time.Second}
-	client := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
-			DialContext:        dialer.DialContext,
-			ForceAttemptHTTP2:  true,
-		},
-	}
+	// The probe context bounds the total time, so attempts have no own timeout.
+	client := utils.DefaultRPCClient().WithTimeout(0).WithTransport(&http.Transport{
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+		DialContext:       dialer.DialContext,
+		ForceAttemptHTTP2: true,
+	})
 	resp, err := client.Do(req)
 	if err != nil {
 		ux.Logger.PrintToUser("  probe error: %v", err)
@@ -357,8 +417,9 @@ func isRemoteCapableNetwork(network models.Network) bool {
 	return network == models.Devnet || network == models.Testnet || network == models.Mainnet
 }
 
-func deployToNetwork(chainName string, chainGenesis []byte, sc *models.Sidecar, network models.Network) error {
+func deployToNetwork(progress *ux.ProgressOperation, chainName string, chainGenesis []byte, sc *models.Sidecar, network models.Network) error {
 	app.Log.Debug("Deploy to network", "network", network.String())
+	progress.Step("target", "Finding the %s network", network.String())
 
 	// Map deploy target to network type
 	// Default is "custom" (not "local" which is ambiguous - any network can run locally)
@@ -399,7 +460,7 @@ func deployToNetwork(chainName string, chainGenesis []byte, sc *models.Sidecar,
 			ux.Logger.PrintToUser("Probing remote %s endpoint: %s", targetType, remoteEndpoint)
 			if probeRemoteEndpoint(remoteEndpoint) {
 				ux.Logger.PrintToUser("Remote %s is alive at %s", targetType, remoteEndpoint)
-				return deployToRemoteNetwork(chainName, chainGenesis, sc, network, remoteEndpoint)
+				return deployToRemoteNetwork(progress, chainName, chainGenesis, sc, network, remoteEndpoint)
 			}
 			ux.Logger.PrintToUser("Remote endpoint %s is not reachable, falling back to local network", remoteEndpoint)
 		}
@@ -419,15 +480,16 @@ func deployToNetwork(chainName string, chainGenesis []byte, sc *models.Sidecar,
 		return fmt.Errorf("no %s network running. Start the network first with: %s", targetType, startHint)
 	}
 
-	return deployToLocalNetwork(chainName, chainGenesis, sc, network, networkState)
+	return deployToLocalNetwork(progress, chainName, chainGenesis, sc, network, networkState)
 }
 
 // deployToLocalNetwork deploys a chain to a locally-running network managed by the CLI's gRPC netrunner.
-func deployToLocalNetwork(chainName string, chainGenesis []byte, sc *models.Sidecar, network models.Network, networkState *application.NetworkState) error {
+func deployToLocalNetwork(progress *ux.ProgressOperation, chainName string, chainGenesis []byte, sc *models.Sidecar, network models.Network, networkState *application.NetworkState) error {
 	// Log gRPC port being used
 	app.Log.Debug("Using gRPC port from network state", "port", networkState.GRPCPort, "network", networkState.NetworkType)
 
 	// Preflight check: verify VM is installed before any network operations
+	progress.Step("vm", "Setting up the %s VM", getVMDisplayName(sc.VM))
 	if err := verifyVMInstalled(chainName, sc); err != nil {
 		return err
 	}
@@ -444,6 +506,16 @@ func deployToLocalNetwork(chainName string, chainGenesis []byte, sc *models.Side
 	vmID, _ := utils.VMID(vmName)
 	vmIDStr := vmID.String()
 
+	if deployDryRun {
+		// Local deploys go through netrunner and pay no fees
+		ux.Logger.PrintToUser("Dry run, nothing will be deployed:")
+		ux.Logger.PrintToUser("  Chain:    %s (%s, VM ID %s)", chainName, getVMDisplayName(sc.VM), vmIDStr)
+		ux.Logger.PrintToUser("  Genesis:  %d bytes", len(chainGenesis))
+		ux.Logger.PrintToUser("  Network:  %s via gRPC port %d", networkState.NetworkType, networkState.GRPCPort)
+		ux.Logger.PrintToUser("  Fees:     none, local networks are deployed through netrunner")
+		return nil
+	}
+
 	switch sc.VM {
 	case models.EVM:
 		// First check if EVM plugin already exists (linked or copied)
@@ -466,6 +538,7 @@ func deployToLocalNetwork(chainName string, chainGenesis []byte, sc *models.Side
 	}
 
 	// Check RPC version compatibility
+	progress.Step("compatibility", "Checking the RPC version of the nodes")
 	if sc.VM != models.CustomVM {
 		// Use app-aware status checker to detect the correct running network endpoint
 		nc := localnetworkinterface.NewStatusCheckerWithApp(app)
@@ -483,6 +556,7 @@ func deployToLocalNetwork(chainName string, chainGenesis []byte, sc *models.Side
 	genesisPath := app.GetGenesisPath(chainName)
 
 	// Deploy to locally-running network (works for local, testnet, mainnet started via CLI)
+	progress.Step("deploy", "Deploying %s to the local %s network", chainName, networkState.NetworkType)
 	chainID, blockchainID, err := deployer.DeployToLocalNetwork(chainName, chainGenesis, genesisPath)
 	if err != nil {
 		// Check if this is a DeploymentError (chain-specific failure)
@@ -515,13 +589,17 @@ func deployToLocalNetwork(chainName string, chainGenesis []byte, sc *models.Side
 	if err := app.UpdateSidecarNetworks(sc, network, chainID, blockchainID); err != nil {
 		return fmt.Errorf("failed to update sidecar: %w", err)
 	}
+	recordDeploy(chainName, chainGenesis, sc, network, chainID, blockchainID, map[string]string{
+		"target": "local " + networkState.NetworkType,
+	})
 	return nil
 }
 
 // deployToRemoteNetwork deploys a chain to a remote network via P-chain API transactions.
 // This is used when no local gRPC netrunner is running but the remote network is reachable.
-func deployToRemoteNetwork(chainName string, chainGenesis []byte, sc *models.Sidecar, network models.Network, endpoint string) error {
+func deployToRemoteNetwork(progress *ux.ProgressOperation, chainName string, chainGenesis []byte, sc *models.Sidecar, network models.Network, endpoint string) error {
 	ux.Logger.PrintToUser("Deploying to remote %s via P-chain API at %s", network.String(), endpoint)
+	progress.Step("keychain", "Loading the deploy key")
 
 	// Get keychain for signing P-chain transactions
 	networkID := network.ID()
@@ -536,16 +614,25 @@ func deployToRemoteNetwork(chainName string, chainGenesis []byte, sc *models.Sid
 		return fmt.Errorf("keychain has no addresses")
 	}
 
+	controlKeys, err := kc.PChainFormattedStrAddresses()
+	if err != nil {
+		return fmt.Errorf("failed to get P-chain addresses: %w", err)
+	}
+	skippedChecks, err := enforceLaunchChecklist(chainName, sc, network, controlKeys)
+	if err != nil {
+		return err
+	}
+
 	// Create the public deployer
 	deployer := chain.NewPublicDeployer(app, kc.UsesLedger, kc.Keychain, network)
+	if deployDryRun {
+		return previewRemoteDeploy(deployer, kc, chainName, chainGenesis, sc)
+	}
 
 	// Step 1: Create chain (P-chain transaction)
+	progress.Step("chain", "Creating chain on P-chain")
 	ux.Logger.PrintToUser("Creating chain on P-chain...")
-	controlKeys, err := kc.PChainFormattedStrAddresses()
-	if err != nil {
-		return fmt.Errorf("failed to get P-chain addresses: %w", err)
-	}
-	ux.Logger.PrintToUser("Control keys: %v", controlKeys)
+	ux.Logger.PrintToUser("Control keys: %v", app.AnnotateAddresses(controlKeys))
 
 	chainID, err := deployer.DeployChain(controlKeys, uint32(len(controlKeys)))
 	if err != nil {
@@ -554,6 +641,7 @@ func deployToRemoteNetwork(chainName string, chainGenesis []byte, sc *models.Sid
 	ux.Logger.PrintToUser("Chain created: %s", chainID.String())
 
 	// Step 2: Create blockchain (P-chain transaction)
+	progress.Step("blockchain", "Creating blockchain on chain %s", chainID.String())
 	ux.Logger.PrintToUser("Creating blockchain on chain %s...", chainID.String())
 	isFullySigned, blockchainID, _, _, err := deployer.DeployBlockchain(
 		controlKeys,
@@ -580,6 +668,70 @@ func deployToRemoteNetwork(chainName string, chainGenesis []byte, sc *models.Sid
 	if err := app.UpdateSidecarNetworks(sc, network, chainID, blockchainID); err != nil {
 		return fmt.Errorf("failed to update sidecar: %w", err)
 	}
+	params := map[string]string{
+		"target":      endpoint,
+		"controlKeys": strings.Join(controlKeys, ","),
+	}
+	if len(skippedChecks) > 0 {
+		params["skippedLaunchChecks"] = strings.Join(skippedChecks, ",")
+	}
+	recordDeploy(chainName, chainGenesis, sc, network, chainID, blockchainID, params)
+	return nil
+}
+
+// recordDeploy adds a deployment to the history, with the genesis digest so
+// it can later be matched against a genesis file
+func recordDeploy(chainName string, chainGenesis []byte, sc *models.Sidecar, network models.Network, chainID, blockchainID ids.ID, params map[string]string) {
+	params["vm"] = getVMDisplayName(sc.VM)
+	params["genesisSHA256"] = history.Digest(chainGenesis)
+	app.RecordHistory(history.Entry{
+		Operation: "chain deploy",
+		Network:   network.String(),
+		Chain:     chainName,
+		Params:    params,
+		Result: map[string]string{
+			"chainID":      chainID.String(),
+			"blockchainID": blockchainID.String(),
+		},
+	})
+}
+
+// previewRemoteDeploy builds the transactions of a remote deploy against the
+// current network state and prints what would be signed and broadcast
+func previewRemoteDeploy(deployer *chain.PublicDeployer, kc *keychain.Keychain, chainName string, chainGenesis []byte, sc *models.Sidecar) error {
+	controlKeys, err := kc.PChainFormattedStrAddresses()
+	if err != nil {
+		return fmt.Errorf("failed to get P-chain addresses: %w", err)
+	}
+	preview, err := deployer.PreviewDeploy(controlKeys, uint32(len(controlKeys)), chainName, chainGenesis)
+	if err != nil {
+		return fmt.Errorf("failed to build deploy transactions: %w", err)
+	}
+
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Dry run, nothing was signed or broadcast. A deploy would issue:")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("#", "Transaction", "Unsigned Size", "Fee")
+	for i, tx := range preview.Txs {
+		_ = table.Append([]string{
+			fmt.Sprintf("%d", i+1),
+			tx.Type,
+			fmt.Sprintf("%d bytes", tx.Size),
+			status.FormatNLUXToLUX(tx.Fee),
+		})
+	}
+	_ = table.Render()
+	ux.Logger.PrintToUser("Control keys:  %v (threshold %d)", app.AnnotateAddresses(controlKeys), len(controlKeys))
+	ux.Logger.PrintToUser("Gas price:     %d nLUX", preview.GasPrice)
+	ux.Logger.PrintToUser("Total fee:     %s", status.FormatNLUXToLUX(preview.TotalFee()))
+	ux.Logger.PrintToUser("P-chain funds: %s", status.FormatNLUXToLUX(preview.Balance))
+	if sc.Sovereign {
+		ux.Logger.PrintToUser("%s is an L1: deploy doesn't convert it, so no ConvertChainToL1Tx is included", chainName)
+	}
+	if preview.TotalFee() > preview.Balance {
+		return fmt.Errorf("insufficient funds: deploy needs %s, key holds %s",
+			status.FormatNLUXToLUX(preview.TotalFee()), status.FormatNLUXToLUX(preview.Balance))
+	}
 	return nil
 }
 
@@ -0,0 +1,179 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/chain"
+	"github.com/luxfi/cli/pkg/keychain"
+	"github.com/luxfi/cli/pkg/txutils"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rawGenesisPath     string
+	rawVMID            string
+	rawChainID         string
+	rawKeyName         string
+	rawUseLedger       bool
+	rawLedgerAddresses []string
+	rawForce           bool
+)
+
+func newDeployRawCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy-raw [blockchainName]",
+		Short: "Deploy a raw genesis and VM ID to the P-Chain",
+		Long: `The deploy-raw command creates a blockchain from a genesis file and a VM ID,
+without a chain configuration made by 'lux chain create'. It signs with the
+standard keychain: a stored key (--key), a ledger (--ledger) or, when none
+is given, the prompt for one.
+
+A new chain (subnet) owned by the signing key is created for the blockchain,
+unless --chain-id names an existing chain the key can sign for.
+
+The --vm-id is a VM ID, or a VM name the ID is derived from. The deployment
+is written to the sidecar of the blockchain, with the genesis, so the other
+chain and validator commands can manage it.
+
+EXAMPLES:
+
+  # Deploy an EVM genesis to testnet with a stored key
+  lux chain deploy-raw mychain --genesis genesis.json --vm-id "Lux EVM" --testnet --key mykey
+
+  # Add a blockchain of a custom VM to an existing chain with a ledger
+  lux chain deploy-raw myvm --genesis genesis.bin --vm-id tGas3T58KzdjcJ2iKSyiYsWiqYctRXaPTqBCA11BqEkNg8kPc \
+    --chain-id 2sEF...dZqp --mainnet --ledger`,
+		Args:         cobra.ExactArgs(1),
+		RunE:         deployRaw,
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&rawGenesisPath, "genesis", "", "genesis file of the blockchain")
+	cmd.Flags().StringVar(&rawVMID, "vm-id", "", "ID of the VM running the blockchain, or the VM name to derive it from")
+	cmd.Flags().StringVar(&rawChainID, "chain-id", "", "existing chain (subnet) to create the blockchain on (default: a new chain)")
+	cmd.Flags().StringVarP(&rawKeyName, "key", "k", "", "select the key to use")
+	cmd.Flags().BoolVarP(&rawUseLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet)")
+	cmd.Flags().StringSliceVar(&rawLedgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	cmd.Flags().BoolVar(&rawForce, "force", false, "overwrite a sidecar already holding another deployment to the network")
+	_ = cmd.MarkFlagRequired("genesis")
+	_ = cmd.MarkFlagRequired("vm-id")
+	return cmd
+}
+
+func deployRaw(_ *cobra.Command, args []string) error {
+	chainName := args[0]
+	if err := validateChainName(chainName); err != nil {
+		return err
+	}
+	genesis, err := os.ReadFile(rawGenesisPath)
+	if err != nil {
+		return fmt.Errorf("failed to read genesis: %w", err)
+	}
+	vmID, err := parseVMID(rawVMID)
+	if err != nil {
+		return err
+	}
+	target := GetNetworkTarget()
+	network := targetNetwork(target)
+	networkKey := network.String()
+
+	sc := models.Sidecar{Name: chainName, Chain: chainName}
+	if app.SidecarExists(chainName) {
+		if sc, err = app.LoadSidecar(chainName); err != nil {
+			return err
+		}
+		if deployed := sc.Networks[networkKey].BlockchainID; deployed != ids.Empty && !rawForce {
+			return fmt.Errorf("%s is already deployed on %s as %s: pick another name or use --force", chainName, networkKey, deployed)
+		}
+	}
+
+	kc, err := keychain.GetKeychainFromCmdLineFlags(app, "deploy "+chainName+" to "+networkKey, network, rawKeyName, false, rawUseLedger, rawLedgerAddresses, 0)
+	if err != nil {
+		return err
+	}
+	signers, err := kc.PChainFormattedStrAddresses()
+	if err != nil {
+		return fmt.Errorf("failed to get P-chain addresses: %w", err)
+	}
+	deployer := chain.NewPublicDeployer(app, kc.UsesLedger, kc.Keychain, network)
+
+	controlKeys := signers
+	var chainID ids.ID
+	if rawChainID != "" {
+		if chainID, err = ids.FromString(rawChainID); err != nil {
+			return fmt.Errorf("invalid chain ID %q: %w", rawChainID, err)
+		}
+		owners, err := txutils.GetChainOwners(network, chainID)
+		if err != nil {
+			return fmt.Errorf("failed to get the owners of %s: %w", chainID, err)
+		}
+		controlKeys = owners.ControlKeys
+	} else {
+		ux.Logger.PrintToUser("Creating chain on P-chain, owned by %s...", strings.Join(app.AnnotateAddresses(signers), ", "))
+		if chainID, err = deployer.DeployChain(signers, uint32(len(signers))); err != nil {
+			return fmt.Errorf("failed to create chain: %w", err)
+		}
+		ux.Logger.PrintToUser("Chain created: %s", chainID)
+	}
+
+	isFullySigned, blockchainID, _, remaining, err := deployer.DeployBlockchainWithVM(controlKeys, signers, chainID, chainName, vmID, genesis)
+	if err != nil {
+		return fmt.Errorf("failed to create blockchain: %w", err)
+	}
+	if !isFullySigned {
+		return fmt.Errorf("creating %s on %s requires more signatures from %s", chainName, chainID, strings.Join(app.AnnotateAddresses(remaining), ", "))
+	}
+
+	sc.VMID = vmID.String()
+	sc.VM = adoptedVMType(vmID)
+	if sc.VM == models.CustomVM {
+		sc.ImportedVMID = vmID.String()
+	}
+	sc.ChainID = chainID
+	sc.BlockchainID = blockchainID
+	if !app.SidecarExists(chainName) {
+		if err := app.CreateSidecar(&sc); err != nil {
+			return fmt.Errorf("blockchain %s was created, but writing its sidecar failed: %w", blockchainID, err)
+		}
+	}
+	if err := app.WriteGenesisFile(chainName, genesis); err != nil {
+		return fmt.Errorf("blockchain %s was created, but writing its genesis failed: %w", blockchainID, err)
+	}
+	if err := app.UpdateSidecarNetworks(&sc, network, chainID, blockchainID); err != nil {
+		return err
+	}
+	endpoint := networkEndpoint(target)
+	recordDeploy(chainName, genesis, &sc, network, chainID, blockchainID, map[string]string{
+		"target":      endpoint,
+		"vmID":        vmID.String(),
+		"controlKeys": strings.Join(controlKeys, ","),
+	})
+
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Blockchain deployed successfully!")
+	ux.Logger.PrintToUser("  Chain ID:      %s", chainID)
+	ux.Logger.PrintToUser("  Blockchain ID: %s", blockchainID)
+	ux.Logger.PrintToUser("  VM:            %s (%s)", sc.VM, vmID)
+	ux.Logger.PrintToUser("  RPC Endpoint:  %s", models.GetRPCEndpoint(endpoint, blockchainID.String()))
+	return nil
+}
+
+// parseVMID returns the VM ID s, or the ID derived from the VM name s
+func parseVMID(s string) (ids.ID, error) {
+	if vmID, err := ids.FromString(s); err == nil {
+		return vmID, nil
+	}
+	vmID, err := utils.VMID(s)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("invalid VM ID or name %q: %w", s, err)
+	}
+	return vmID, nil
+}
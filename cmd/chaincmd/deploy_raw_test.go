@@ -0,0 +1,29 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"testing"
+
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVMID(t *testing.T) {
+	require := require.New(t)
+	vmID := ids.GenerateTestID()
+	got, err := parseVMID(vmID.String())
+	require.NoError(err)
+	require.Equal(vmID, got)
+
+	evmID, err := utils.VMID(LuxEVMName)
+	require.NoError(err)
+	got, err = parseVMID(LuxEVMName)
+	require.NoError(err)
+	require.Equal(evmID, got)
+
+	_, err = parseVMID(string(make([]byte, 33)))
+	require.ErrorContains(err, "invalid VM ID or name")
+}
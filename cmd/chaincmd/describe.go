@@ -8,17 +8,41 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/luxfi/cli/pkg/cobrautils"
 	"github.com/luxfi/cli/pkg/ux"
 	"github.com/spf13/cobra"
 )
 
+var describeDeepFlag bool
+
 func newDescribeCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "describe [chainName]",
 		Short: "Show detailed information about a blockchain",
-		Args:  cobra.ExactArgs(1),
-		RunE:  describeChain,
+		Long: `The describe command shows the configuration of a blockchain as recorded
+locally: its VM, type, deployments and genesis.
+
+With --deep, it also inspects the deployment on the selected network and
+reports where it drifted from the local configuration:
+
+  - the blockchain and its validator set on the P-Chain, with weights and
+    balances
+  - the chain config the chain runs with, read from its RPC
+  - the validator manager contract: its owner, churn settings and pending
+    validator registrations and removals
+  - the Warp messenger contract and the relayer
+
+EXAMPLES:
+
+  lux chain describe mychain
+
+  lux chain describe mychain --deep --testnet`,
+		Args:        cobrautils.ExactArgs(1),
+		RunE:        describeChain,
+		Annotations: readOnlyAnnotation(),
 	}
+	cmd.Flags().BoolVar(&describeDeepFlag, "deep", false, "inspect the on-chain state on the selected network and report drift")
+	return cmd
 }
 
 func describeChain(cmd *cobra.Command, args []string) error {
@@ -68,5 +92,8 @@ func describeChain(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if describeDeepFlag {
+		return describeDeep(sc)
+	}
 	return nil
 }
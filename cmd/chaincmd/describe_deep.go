@@ -0,0 +1,330 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/contract"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/validator"
+	"github.com/luxfi/cli/pkg/warp/relayer"
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/ethclient"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+	"github.com/luxfi/sdk/platformvm"
+	"github.com/olekukonko/tablewriter"
+)
+
+// describeDeep prints the state of the deployment of sc to the target
+// network, as reported by the P-Chain, the chain RPC and its contracts, and
+// where it drifted from the sidecar
+func describeDeep(sc models.Sidecar) error {
+	target := GetNetworkTarget()
+	network := targetNetwork(target)
+	networkKey := network.String()
+	data, ok := sc.Networks[networkKey]
+	if !ok || data.BlockchainID == ids.Empty {
+		ux.Logger.PrintToUser("\n%s is not deployed to %s", sc.Name, networkKey)
+		return nil
+	}
+	ux.Logger.PrintToUser("\nOn-chain state on %s:", networkKey)
+	var drift []string
+
+	// P-Chain: blockchain, chain and validators
+	pClient := platformvm.NewClient(networkEndpoint(target))
+	ctx, cancel := utils.GetAPILargeContext()
+	defer cancel()
+	blockchains, err := pClient.GetBlockchains(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get blockchains from the P-Chain of %s: %w", networkKey, err)
+	}
+	found := false
+	for _, b := range blockchains {
+		if b.ID != data.BlockchainID {
+			continue
+		}
+		found = true
+		ux.Logger.PrintToUser("  Blockchain: %s (%s), VM %s", b.ID, b.Name, b.VMID)
+		if b.NetID != data.ChainID {
+			drift = append(drift, fmt.Sprintf("blockchain %s is validated by chain %s, the sidecar says %s", b.ID, b.NetID, data.ChainID))
+		}
+		if sc.VMID != "" && b.VMID.String() != sc.VMID {
+			drift = append(drift, fmt.Sprintf("blockchain %s runs VM %s, the sidecar says %s", b.ID, b.VMID, sc.VMID))
+		}
+	}
+	if !found {
+		drift = append(drift, fmt.Sprintf("blockchain %s is not on the P-Chain of %s", data.BlockchainID, networkKey))
+	}
+	var managerAddress []byte
+	if chainInfo, err := pClient.GetNet(ctx, data.ChainID); err != nil {
+		drift = append(drift, fmt.Sprintf("chain %s is not on the P-Chain of %s: %v", data.ChainID, networkKey, err))
+	} else if chainInfo.ConversionID != ids.Empty {
+		managerAddress = chainInfo.ManagerAddress
+		ux.Logger.PrintToUser("  Chain:      %s, sovereign L1 managed by %s on %s",
+			data.ChainID, common.BytesToAddress(chainInfo.ManagerAddress).Hex(), chainInfo.ManagerChainID)
+		if !sc.Sovereign {
+			drift = append(drift, fmt.Sprintf("chain %s was converted to an L1, the sidecar says it is not sovereign", data.ChainID))
+		}
+	} else {
+		ux.Logger.PrintToUser("  Chain:      %s, permissioned", data.ChainID)
+		if sc.Sovereign {
+			drift = append(drift, fmt.Sprintf("chain %s is not converted to an L1, the sidecar says it is sovereign", data.ChainID))
+		}
+	}
+	validators, err := pClient.GetCurrentValidators(ctx, data.ChainID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get the validators of %s: %w", data.ChainID, err)
+	}
+	printDeepValidators(validators)
+	nodeIDs := make([]ids.NodeID, 0, len(validators))
+	totalWeight := uint64(0)
+	for _, v := range validators {
+		nodeIDs = append(nodeIDs, v.NodeID)
+		totalWeight += v.Weight
+	}
+	drift = append(drift, validatorSetDrift(data.ValidatorIDs, nodeIDs)...)
+
+	// chain RPC: config and contracts
+	rpcURL, err := chainRPCEndpoint(sc.Name, target)
+	if err != nil {
+		return err
+	}
+	client, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", rpcURL, err)
+	}
+	defer client.Close()
+	var liveConfig map[string]interface{}
+	if err := client.Client().CallContext(ctx, &liveConfig, "eth_getChainConfig"); err != nil {
+		drift = append(drift, fmt.Sprintf("chain RPC %s is not reachable: %v", rpcURL, err))
+		printDrift(drift)
+		return nil
+	}
+	ux.Logger.PrintToUser("  RPC:        %s, EVM chain ID %v", rpcURL, liveConfig["chainId"])
+	if genesis, err := app.LoadRawGenesis(sc.Name); err == nil {
+		var genesisConfig struct {
+			Config map[string]interface{} `json:"config"`
+		}
+		if err := json.Unmarshal(genesis, &genesisConfig); err == nil && genesisConfig.Config != nil {
+			drift = append(drift, chainConfigDrift(genesisConfig.Config, liveConfig)...)
+		}
+	}
+	if sc.EVMChainID != "" && fmt.Sprint(liveConfig["chainId"]) != sc.EVMChainID {
+		drift = append(drift, fmt.Sprintf("EVM chain ID is %v, the sidecar says %s", liveConfig["chainId"], sc.EVMChainID))
+	}
+
+	if common.IsHexAddress(data.ValidatorManagerAddress) {
+		manager := common.HexToAddress(data.ValidatorManagerAddress)
+		if len(managerAddress) > 0 && !bytes.Equal(managerAddress, manager.Bytes()) {
+			drift = append(drift, fmt.Sprintf("the P-Chain validator manager is %s, the sidecar says %s",
+				common.BytesToAddress(managerAddress).Hex(), manager.Hex()))
+		}
+		if code, err := client.CodeAt(ctx, manager, nil); err != nil || len(code) == 0 {
+			drift = append(drift, fmt.Sprintf("no validator manager contract at %s", manager.Hex()))
+		} else {
+			drift = append(drift, describeValidatorManager(sc, data, rpcURL, client, manager, totalWeight)...)
+		}
+	}
+
+	// relayer
+	if common.IsHexAddress(data.TeleporterMessengerAddress) {
+		messenger := common.HexToAddress(data.TeleporterMessengerAddress)
+		if code, err := client.CodeAt(ctx, messenger, nil); err != nil || len(code) == 0 {
+			drift = append(drift, fmt.Sprintf("no Warp messenger contract at %s", messenger.Hex()))
+		} else {
+			ux.Logger.PrintToUser("  Messenger:  %s", messenger.Hex())
+		}
+	}
+	running, err := relayer.Running(app.GetLocalRelayerRunPath(network))
+	if err != nil {
+		return err
+	}
+	relayerConfig := app.GetLocalRelayerConfigPath()
+	switch config, err := os.ReadFile(relayerConfig); { //nolint:gosec // G304: Reading from app's data directory
+	case err != nil:
+		ux.Logger.PrintToUser("  Relayer:    no config at %s", relayerConfig)
+	case !bytes.Contains(config, []byte(data.BlockchainID.String())):
+		ux.Logger.PrintToUser("  Relayer:    %s does not route %s (running: %t)", relayerConfig, sc.Name, running)
+	default:
+		ux.Logger.PrintToUser("  Relayer:    %s routes %s (running: %t)", relayerConfig, sc.Name, running)
+	}
+
+	printDrift(drift)
+	return nil
+}
+
+// describeValidatorManager prints the state of the validator manager
+// contract and returns where it drifted from the sidecar and the P-Chain
+func describeValidatorManager(
+	sc models.Sidecar,
+	data models.NetworkData,
+	rpcURL string,
+	client *ethclient.Client,
+	manager common.Address,
+	pChainWeight uint64,
+) []string {
+	var drift []string
+	underlying := common.Address(validator.GetUnderlyingValidatorManager(rpcURL, crypto.Address(manager)))
+	if underlying != manager {
+		ux.Logger.PrintToUser("  Manager:    %s, staking manager on validator manager %s", manager.Hex(), underlying.Hex())
+	} else {
+		ux.Logger.PrintToUser("  Manager:    %s", manager.Hex())
+	}
+	if owner, err := contract.GetContractOwner(rpcURL, crypto.Address(manager)); err == nil {
+		ux.Logger.PrintToUser("    Owner:    %s", owner.Hex())
+		if sc.ValidatorManagerOwner != "" && !strings.EqualFold(owner.Hex(), sc.ValidatorManagerOwner) {
+			drift = append(drift, fmt.Sprintf("validator manager owner is %s, the sidecar says %s", owner.Hex(), sc.ValidatorManagerOwner))
+		}
+	}
+	ctx, cancel := utils.GetAPILargeContext()
+	defer cancel()
+	state, err := validator.GetManagerState(ctx, client, underlying)
+	if err != nil {
+		return append(drift, fmt.Sprintf("validator manager state unavailable: %v", err))
+	}
+	ux.Logger.PrintToUser("    Manages:  chain %s, total weight %d", state.ChainID, state.TotalWeight)
+	ux.Logger.PrintToUser("    Churn:    at most %d%% of the weight per %s, %d churned since %s",
+		state.MaximumChurnPercentage,
+		time.Duration(state.ChurnPeriodSeconds)*time.Second, //nolint:gosec // G115: churn periods are short
+		state.ChurnAmount,
+		time.Unix(int64(state.ChurnPeriodStart), 0).UTC().Format(time.RFC3339), //nolint:gosec // G115: unix time
+	)
+	if len(state.Pending) == 0 {
+		ux.Logger.PrintToUser("    Pending:  none")
+	} else {
+		ux.Logger.PrintToUser("    Pending:")
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header("Validation ID", "Node ID", "Status", "Weight")
+		for _, v := range state.Pending {
+			_ = table.Append([]string{v.ValidationID.String(), v.NodeID.String(), v.Status.String(), strconv.FormatUint(v.Weight, 10)})
+		}
+		_ = table.Render()
+	}
+	if state.ChainID != data.ChainID {
+		drift = append(drift, fmt.Sprintf("validator manager manages chain %s, the sidecar says %s", state.ChainID, data.ChainID))
+	}
+	if len(state.Pending) == 0 && state.TotalWeight != pChainWeight {
+		drift = append(drift, fmt.Sprintf("validator manager total weight is %d, the P-Chain says %d", state.TotalWeight, pChainWeight))
+	}
+	return drift
+}
+
+func printDeepValidators(validators []platformvm.ClientPermissionlessValidator) {
+	if len(validators) == 0 {
+		ux.Logger.PrintToUser("  Validators: none")
+		return
+	}
+	ux.Logger.PrintToUser("  Validators:")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Node ID", "Weight", "Balance (nLUX)", "Validation ID")
+	for _, v := range validators {
+		balance, validationID := "-", "-"
+		if v.Balance != nil {
+			balance = strconv.FormatUint(*v.Balance, 10)
+		}
+		if v.ValidationID != nil {
+			validationID = v.ValidationID.String()
+		}
+		_ = table.Append([]string{v.NodeID.String(), strconv.FormatUint(v.Weight, 10), balance, validationID})
+	}
+	_ = table.Render()
+}
+
+func printDrift(drift []string) {
+	if len(drift) == 0 {
+		ux.Logger.PrintToUser("\nNo drift between the sidecar and the network")
+		return
+	}
+	ux.Logger.PrintToUser("\nDrift between the sidecar and the network:")
+	for _, d := range drift {
+		ux.Logger.PrintToUser("  - %s", d)
+	}
+}
+
+// validatorSetDrift compares the validator node IDs recorded in the sidecar
+// with the current validators
+func validatorSetDrift(sidecarIDs []string, current []ids.NodeID) []string {
+	if len(sidecarIDs) == 0 {
+		return nil
+	}
+	recorded := make(map[string]bool, len(sidecarIDs))
+	for _, id := range sidecarIDs {
+		recorded[id] = true
+	}
+	var drift []string
+	for _, nodeID := range current {
+		if !recorded[nodeID.String()] {
+			drift = append(drift, fmt.Sprintf("validator %s is not in the sidecar", nodeID))
+		}
+		delete(recorded, nodeID.String())
+	}
+	missing := make([]string, 0, len(recorded))
+	for id := range recorded {
+		missing = append(missing, id)
+	}
+	sort.Strings(missing)
+	for _, id := range missing {
+		drift = append(drift, fmt.Sprintf("validator %s of the sidecar no longer validates", id))
+	}
+	return drift
+}
+
+// chainConfigDrift compares the genesis chain config with the one the chain
+// runs with. Only settings of the genesis are compared: nodes report
+// defaults and upgrades the genesis leaves out.
+func chainConfigDrift(genesis, live map[string]interface{}) []string {
+	keys := make([]string, 0, len(genesis))
+	for key := range genesis {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var drift []string
+	for _, key := range keys {
+		liveValue, ok := live[key]
+		if !ok {
+			continue
+		}
+		if !reflect.DeepEqual(normalizeJSON(genesis[key]), normalizeJSON(liveValue)) {
+			genesisJSON, _ := json.Marshal(genesis[key])
+			liveJSON, _ := json.Marshal(liveValue)
+			drift = append(drift, fmt.Sprintf("chain config %s is %s, the genesis says %s", key, liveJSON, genesisJSON))
+		}
+	}
+	return drift
+}
+
+// normalizeJSON drops the null fields of decoded JSON objects, which nodes
+// and genesis files write inconsistently
+func normalizeJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			if value != nil {
+				normalized[key] = normalizeJSON(value)
+			}
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, value := range v {
+			normalized[i] = normalizeJSON(value)
+		}
+		return normalized
+	default:
+		return v
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatorSetDrift(t *testing.T) {
+	require := require.New(t)
+	kept := ids.GenerateTestNodeID()
+	added := ids.GenerateTestNodeID()
+	removed := ids.GenerateTestNodeID()
+
+	require.Empty(validatorSetDrift(nil, []ids.NodeID{kept}))
+	require.Empty(validatorSetDrift([]string{kept.String()}, []ids.NodeID{kept}))
+	require.Equal([]string{
+		"validator " + added.String() + " is not in the sidecar",
+		"validator " + removed.String() + " of the sidecar no longer validates",
+	}, validatorSetDrift([]string{kept.String(), removed.String()}, []ids.NodeID{kept, added}))
+}
+
+func TestChainConfigDrift(t *testing.T) {
+	require := require.New(t)
+	var genesis, live map[string]interface{}
+	require.NoError(json.Unmarshal([]byte(`{
+		"chainId": 200200,
+		"feeConfig": {"gasLimit": 15000000, "minBaseFee": 25000000000},
+		"warpConfig": {"blockTimestamp": 0, "quorumNumerator": null},
+		"homesteadBlock": 0
+	}`), &genesis))
+	require.NoError(json.Unmarshal([]byte(`{
+		"chainId": 200200,
+		"feeConfig": {"gasLimit": 20000000, "minBaseFee": 25000000000},
+		"warpConfig": {"blockTimestamp": 0},
+		"cancunTime": 0
+	}`), &live))
+
+	require.Equal([]string{
+		`chain config feeConfig is {"gasLimit":20000000,"minBaseFee":25000000000}, the genesis says {"gasLimit":15000000,"minBaseFee":25000000000}`,
+	}, chainConfigDrift(genesis, live))
+	require.Empty(chainConfigDrift(genesis, genesis))
+}
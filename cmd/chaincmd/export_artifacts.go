@@ -0,0 +1,331 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/artifacts"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/receipt"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/ethclient"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportArtifactsOutput     string
+	exportArtifactsSkipChecks bool
+)
+
+// lux chain export-artifacts
+func newExportArtifactsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-artifacts <chainName>",
+		Short: "Export a signed bundle of what was launched, for auditors",
+		Long: `The export-artifacts command writes a single signed archive recording what
+was launched for a chain, for third-party auditors:
+
+  genesis.json   the genesis the chain was created with
+  config.json    the chain config, when it has one
+  sidecar.json   the CLI's record of the chain
+
+and, in its MANIFEST.json, for every network the chain is deployed to: the
+chain and blockchain IDs, RPC endpoints, the Warp messenger and registry
+and validator manager addresses, the deploy transaction IDs, and the
+verification status:
+
+  genesis        the genesis digest matches the one recorded by the deploy
+  chain-id       the RPC endpoint serves the EVM chain ID of the genesis
+  <contract>     code is deployed at each contract address
+
+--skip-checks leaves out the checks querying the RPC endpoints, when the
+networks can't be reached.
+
+The manifest lists the SHA-256 of each file and is signed with the ed25519
+key of this machine, the one signing receipts. Auditors check a bundle with
+'lux chain verify-artifacts' and compare the key fingerprint with the one
+the operator published.
+
+EXAMPLES:
+
+  lux chain export-artifacts mychain -o mychain-artifacts.tar.gz
+  lux chain verify-artifacts mychain-artifacts.tar.gz`,
+		Args:        cobrautils.ExactArgs(1),
+		RunE:        exportArtifacts,
+		Annotations: readOnlyAnnotation(),
+	}
+	cmd.Flags().StringVarP(&exportArtifactsOutput, "output", "o", "", "file to write the bundle to (default <chainName>-artifacts.tar.gz)")
+	cmd.Flags().BoolVar(&exportArtifactsSkipChecks, "skip-checks", false, "don't query the RPC endpoints to verify the deployments")
+	return cmd
+}
+
+// lux chain verify-artifacts
+func newVerifyArtifactsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify-artifacts <file>",
+		Short: "Verify the signature and files of an artifacts bundle",
+		Long: `The verify-artifacts command checks a bundle written by 'lux chain
+export-artifacts': the manifest is signed by the key it names and the files
+are exactly those of the manifest, unaltered. It prints the fingerprint of
+the signing key, to compare with the one the operator published, and the
+recorded deployments.
+
+EXAMPLES:
+
+  lux chain verify-artifacts mychain-artifacts.tar.gz`,
+		Args:        cobrautils.ExactArgs(1),
+		RunE:        verifyArtifacts,
+		Annotations: readOnlyAnnotation(),
+	}
+}
+
+func exportArtifacts(_ *cobra.Command, args []string) error {
+	chainName := args[0]
+	sc, err := app.LoadSidecar(chainName)
+	if err != nil {
+		return fmt.Errorf("chain %s not found: %w", chainName, err)
+	}
+	files := map[string][]byte{}
+	genesis, err := app.LoadRawGenesis(chainName)
+	if err != nil {
+		return fmt.Errorf("failed to load genesis: %w", err)
+	}
+	files[constants.GenesisFileName] = genesis
+	if app.ChainConfigExists(chainName) {
+		chainConfig, err := app.GetSDKApp().LoadRawLuxdChainConfig(chainName)
+		if err != nil {
+			return fmt.Errorf("failed to load chain config: %w", err)
+		}
+		files[constants.ChainConfigFile] = chainConfig
+	}
+	sidecar, err := os.ReadFile(app.GetSidecarPath(chainName)) //nolint:gosec // G304: Reading from app's data directory
+	if err != nil {
+		return fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	files[constants.SidecarFileName] = sidecar
+
+	entries, err := history.Load(app.GetHistoryPath())
+	if err != nil {
+		return fmt.Errorf("failed to load the history: %w", err)
+	}
+	m := &artifacts.Manifest{
+		Chain:     chainName,
+		VM:        getVMDisplayName(sc.VM),
+		VMID:      sc.VMID,
+		VMVersion: sc.VMVersion,
+		Created:   time.Now().UTC(),
+		CreatedBy: currentUser(),
+	}
+	networkNames := make([]string, 0, len(sc.Networks))
+	for name := range sc.Networks {
+		networkNames = append(networkNames, name)
+	}
+	sort.Strings(networkNames)
+	for _, name := range networkNames {
+		deploys := history.Select(entries, history.Filter{Operation: "chain deploy", Network: name, Chain: chainName})
+		m.Deployments = append(m.Deployments, artifactsDeployment(&sc, name, genesis, deploys))
+	}
+
+	key, err := receipt.LoadOrCreateSigningKey(filepath.Join(app.GetReceiptsDir(), receipt.SigningKeyFileName))
+	if err != nil {
+		return fmt.Errorf("failed to load the signing key: %w", err)
+	}
+	bundle, err := artifacts.Create(m, files, key)
+	if err != nil {
+		return err
+	}
+	output := exportArtifactsOutput
+	if output == "" {
+		output = chainName + "-artifacts.tar.gz"
+	}
+	if err := os.WriteFile(output, bundle, constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	printArtifactsDeployments(m)
+	ux.Logger.GreenCheckmarkToUser("Artifacts of %s written to %s", chainName, output)
+	ux.Logger.PrintToUser("Signed by key %s", m.Signature.KeyFingerprint)
+	return nil
+}
+
+// artifactsDeployment records the deployment of sc to the network name,
+// deploys being the history entries of its deploys, oldest first
+func artifactsDeployment(sc *models.Sidecar, name string, genesis []byte, deploys []history.Entry) artifacts.Deployment {
+	data := sc.Networks[name]
+	d := artifacts.Deployment{
+		Network:      name,
+		RPCEndpoints: data.RPCEndpoints,
+		Contracts:    map[string]string{},
+		TxIDs:        map[string]string{},
+	}
+	if data.ChainID != ids.Empty {
+		d.ChainID = data.ChainID.String()
+	}
+	if data.BlockchainID != ids.Empty {
+		d.BlockchainID = data.BlockchainID.String()
+	}
+	for contract, address := range map[string]string{
+		"warpMessenger":    data.TeleporterMessengerAddress,
+		"warpRegistry":     data.TeleporterRegistryAddress,
+		"validatorManager": data.ValidatorManagerAddress,
+	} {
+		if address != "" {
+			d.Contracts[contract] = address
+		}
+	}
+	if elastic, ok := sc.ElasticChain[name]; ok {
+		if elastic.PChainTXID != ids.Empty {
+			d.TxIDs["elasticChain"] = elastic.PChainTXID.String()
+		}
+		for txName, txID := range elastic.Txs {
+			d.TxIDs[txName] = txID.String()
+		}
+	}
+
+	checks := []artifacts.Check{}
+	if len(deploys) == 0 {
+		checks = append(checks, artifacts.Check{Name: "genesis", Status: artifacts.CheckUnknown, Detail: "no deploy in the history"})
+	} else {
+		last := deploys[len(deploys)-1]
+		d.DeployedAt = last.Time
+		d.DeployedBy = last.User
+		d.GenesisSHA256 = last.Params["genesisSHA256"]
+		for k, v := range last.Result {
+			d.TxIDs[k] = v
+		}
+		switch {
+		case d.GenesisSHA256 == "":
+			checks = append(checks, artifacts.Check{Name: "genesis", Status: artifacts.CheckUnknown, Detail: "the deploy recorded no genesis digest"})
+		case d.GenesisSHA256 == history.Digest(genesis):
+			checks = append(checks, artifacts.Check{Name: "genesis", Status: artifacts.CheckPass})
+		default:
+			checks = append(checks, artifacts.Check{Name: "genesis", Status: artifacts.CheckFail,
+				Detail: "genesis.json changed since the deploy, whose genesis had SHA-256 " + d.GenesisSHA256})
+		}
+	}
+	if !exportArtifactsSkipChecks && d.BlockchainID != "" {
+		checks = append(checks, rpcChecks(sc, name, genesis, d)...)
+	}
+	d.Checks = checks
+	return d
+}
+
+// rpcChecks verifies the deployment d on the RPC endpoint of the chain: the
+// EVM chain ID and the code of the contracts
+func rpcChecks(sc *models.Sidecar, name string, genesis []byte, d artifacts.Deployment) []artifacts.Check {
+	rpcURL := models.GetRPCEndpoint(models.GetNetworkFromSidecarNetworkName(name).Endpoint(), d.BlockchainID)
+	if len(d.RPCEndpoints) > 0 {
+		rpcURL = d.RPCEndpoints[0]
+	}
+	client, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		return []artifacts.Check{{Name: "rpc", Status: artifacts.CheckUnknown, Detail: err.Error()}}
+	}
+	defer client.Close()
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+
+	checks := []artifacts.Check{}
+	if sc.VM == models.EVM {
+		var g struct {
+			Config struct {
+				ChainID *big.Int `json:"chainId"`
+			} `json:"config"`
+		}
+		chainID, err := client.ChainID(ctx)
+		switch {
+		case err != nil:
+			return []artifacts.Check{{Name: "rpc", Status: artifacts.CheckUnknown, Detail: fmt.Sprintf("%s: %s", rpcURL, err)}}
+		case json.Unmarshal(genesis, &g) != nil || g.Config.ChainID == nil:
+			checks = append(checks, artifacts.Check{Name: "chain-id", Status: artifacts.CheckUnknown, Detail: "the genesis has no chain ID"})
+		case chainID.Cmp(g.Config.ChainID) == 0:
+			checks = append(checks, artifacts.Check{Name: "chain-id", Status: artifacts.CheckPass, Detail: chainID.String()})
+		default:
+			checks = append(checks, artifacts.Check{Name: "chain-id", Status: artifacts.CheckFail,
+				Detail: fmt.Sprintf("%s serves chain ID %s, the genesis has %s", rpcURL, chainID, g.Config.ChainID)})
+		}
+	}
+	contracts := make([]string, 0, len(d.Contracts))
+	for contract := range d.Contracts {
+		contracts = append(contracts, contract)
+	}
+	sort.Strings(contracts)
+	for _, contract := range contracts {
+		code, err := client.CodeAt(ctx, common.HexToAddress(d.Contracts[contract]), nil)
+		switch {
+		case err != nil:
+			checks = append(checks, artifacts.Check{Name: contract, Status: artifacts.CheckUnknown, Detail: err.Error()})
+		case len(code) == 0:
+			checks = append(checks, artifacts.Check{Name: contract, Status: artifacts.CheckFail, Detail: "no code at " + d.Contracts[contract]})
+		default:
+			checks = append(checks, artifacts.Check{Name: contract, Status: artifacts.CheckPass, Detail: fmt.Sprintf("%d bytes of code", len(code))})
+		}
+	}
+	return checks
+}
+
+func verifyArtifacts(_ *cobra.Command, args []string) error {
+	bundle, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	m, _, err := artifacts.Verify(bundle)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Artifacts of %s (%s), exported %s by %s", m.Chain, m.VM, m.Created.Format(time.RFC3339), m.CreatedBy)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("File", "Size", "SHA-256")
+	for _, f := range m.Files {
+		_ = table.Append([]string{f.Name, fmt.Sprintf("%d bytes", f.Size), f.SHA256})
+	}
+	_ = table.Render()
+	printArtifactsDeployments(m)
+	ux.Logger.GreenCheckmarkToUser("Bundle signed by key %s, files unaltered", m.Signature.KeyFingerprint)
+	return nil
+}
+
+func printArtifactsDeployments(m *artifacts.Manifest) {
+	if len(m.Deployments) == 0 {
+		ux.Logger.PrintToUser("%s is not deployed to any network", m.Chain)
+		return
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Network", "Blockchain ID", "Contracts", "Checks")
+	for _, d := range m.Deployments {
+		contracts := make([]string, 0, len(d.Contracts))
+		for contract, address := range d.Contracts {
+			contracts = append(contracts, contract+" "+address)
+		}
+		sort.Strings(contracts)
+		checks := make([]string, 0, len(d.Checks))
+		for _, c := range d.Checks {
+			checks = append(checks, fmt.Sprintf("%s %s", c.Name, strings.ToUpper(c.Status)))
+		}
+		_ = table.Append([]string{d.Network, d.BlockchainID, strings.Join(contracts, "\n"), strings.Join(checks, "\n")})
+	}
+	_ = table.Render()
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
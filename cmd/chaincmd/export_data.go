@@ -0,0 +1,202 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	ansi "github.com/k0kubun/go-ansi"
+	"github.com/luxfi/cli/pkg/chainexport"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/snapshot"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/ids"
+	progressbar "github.com/schollz/progressbar/v3"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportDataFormat string
+	exportDataRange  string
+	exportDataOutput string
+	exportDataState  string
+	exportDataDB     string
+	exportDataNode   string
+)
+
+func newExportDataCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-data <blockchainName>",
+		Short: "Export blocks, receipts and state from a local chain database",
+		Long: `The export-data command reads the blocks of an EVM chain straight from the
+database of a local node, for analytics pipelines or for importing into
+other execution clients.
+
+FORMATS:
+
+  rlp     RLP block stream, as written by 'geth export' and read by
+          'geth import', admin_importChain and 'lux chain import'
+  jsonl   One JSON object per line with the block's header, transactions
+          and receipts
+
+--state also writes every account of the state at the last block of the
+range as JSON lines. Nodes only keep recent state unless they run as
+archive nodes.
+
+The database is that of the chain on --node of the local network, unless
+--db points at another one (a copy or an unpacked snapshot). The database
+engine only admits one process: stop the network, or export from a copy.
+
+EXAMPLES:
+
+  # All blocks of mychain as an RLP stream
+  lux chain export-data mychain --devnet -o mychain.rlp
+
+  # Blocks 1000 to 2000 with receipts for analytics
+  lux chain export-data mychain --devnet --format jsonl --range 1000:2000 -o blocks.jsonl
+
+  # Blocks and the latest state from a copied database
+  lux chain export-data mychain --db ./chaindb/badgerdb -o mychain.rlp --state state.jsonl`,
+		Args:        cobrautils.ExactArgs(1),
+		RunE:        exportData,
+		Annotations: readOnlyAnnotation(),
+	}
+	cmd.Flags().StringVar(&exportDataFormat, "format", string(chainexport.FormatRLP), "block format: rlp or jsonl")
+	cmd.Flags().StringVar(&exportDataRange, "range", "0:latest", "blocks to export, <from>:<to> with 'latest' for the head block")
+	cmd.Flags().StringVarP(&exportDataOutput, "output", "o", "", "file to write blocks to (default <blockchainName>.<format>)")
+	cmd.Flags().StringVar(&exportDataState, "state", "", "also write the state at the last block of the range to this file")
+	cmd.Flags().StringVar(&exportDataDB, "db", "", "chain database directory (default: the local node's)")
+	cmd.Flags().StringVar(&exportDataNode, "node", "node1", "local node whose database to read")
+	return cmd
+}
+
+func exportData(_ *cobra.Command, args []string) error {
+	chainName := args[0]
+	format, err := chainexport.ParseFormat(exportDataFormat)
+	if err != nil {
+		return err
+	}
+	dbPath := exportDataDB
+	if dbPath == "" {
+		if dbPath, err = localChainDBPath(chainName, GetNetworkTarget()); err != nil {
+			return err
+		}
+	}
+	dbType, err := snapshot.DetectDBType(dbPath)
+	if err != nil {
+		return err
+	}
+	db, err := snapshot.OpenDB(dbType, dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open %s (%s), stop the network or export from a copy: %w", dbPath, dbType, err)
+	}
+	defer db.Close()
+	chainDB := chainexport.ChainDB(db)
+
+	head, err := chainexport.Head(chainDB)
+	if err != nil {
+		return fmt.Errorf("%s: %w", dbPath, err)
+	}
+	blocks, err := chainexport.ParseRange(exportDataRange, head)
+	if err != nil {
+		return err
+	}
+	output := exportDataOutput
+	if output == "" {
+		output = chainName + "." + string(format)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ux.Logger.PrintToUser("Exporting blocks %d to %d of %s from %s to %s", blocks.From, blocks.To, chainName, dbPath, output)
+	start := time.Now()
+	bar := progressbar.NewOptions64(int64(blocks.Len()), //nolint:gosec // G115: block counts fit in int64
+		progressbar.OptionSetWriter(ansi.NewAnsiStdout()),
+		progressbar.OptionSetWidth(30),
+		progressbar.OptionSetDescription("blocks"),
+		progressbar.OptionShowCount(),
+		progressbar.OptionShowIts(),
+		progressbar.OptionSetItsString("blocks"),
+		progressbar.OptionSetPredictTime(true))
+	err = writeExport(output, func(w *bufio.Writer) error {
+		return chainexport.ExportBlocks(ctx, chainDB, w, format, blocks, func(uint64) {
+			_ = bar.Add(1)
+		})
+	})
+	_ = bar.Finish()
+	fmt.Println()
+	if errors.Is(err, context.Canceled) {
+		return fmt.Errorf("interrupted, %s is incomplete", output)
+	}
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("✓ Exported %d blocks in %s", blocks.Len(), ux.FormatDuration(time.Since(start)))
+
+	if exportDataState != "" {
+		ux.Logger.PrintToUser("Exporting the state at block %d to %s", blocks.To, exportDataState)
+		if err := writeExport(exportDataState, func(w *bufio.Writer) error {
+			return chainexport.ExportState(chainDB, w, blocks.To)
+		}); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("✓ Exported the state")
+	}
+	return nil
+}
+
+// writeExport writes to path through a buffer, removing the file on failure
+func writeExport(path string, write func(*bufio.Writer) error) error {
+	f, err := os.Create(path) //nolint:gosec // G304: user-provided output path
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriterSize(f, 1<<20)
+	err = write(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+	}
+	return err
+}
+
+// localChainDBPath returns the database of chainName's deployment to target
+// on the local node exportDataNode
+func localChainDBPath(chainName string, target NetworkTarget) (string, error) {
+	sc, err := app.LoadSidecar(chainName)
+	if err != nil {
+		return "", err
+	}
+	networkKey := targetNetwork(target).String()
+	blockchainID := sc.Networks[networkKey].BlockchainID
+	if blockchainID == ids.Empty {
+		return "", fmt.Errorf("%s is not deployed to %s", chainName, networkKey)
+	}
+	runDir, err := utils.ReadDirLink(filepath.Join(app.GetBaseDir(), "runs", string(target), "current"))
+	if err != nil {
+		return "", fmt.Errorf("no local %s run found, use --db: %w", target, err)
+	}
+	matches, _ := filepath.Glob(filepath.Join(runDir, exportDataNode, "chainData", "network-*", blockchainID.String(), "db", "*"))
+	for _, path := range matches {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no database for %s (%s) on %s in %s, use --db", chainName, blockchainID, exportDataNode, runDir)
+}
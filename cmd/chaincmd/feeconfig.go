@@ -0,0 +1,460 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/luxfi/cli/cmd/chaincmd/upgradecmd"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/key"
+	"github.com/luxfi/cli/pkg/precompiles"
+	"github.com/luxfi/cli/pkg/prompts"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/vm"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/evm/commontype"
+	"github.com/luxfi/evm/params/extras"
+	"github.com/luxfi/evm/precompile/contracts/feemanager"
+	evmutils "github.com/luxfi/evm/utils"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/sdk/models"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+const (
+	feeConfigViaPrecompile = "precompile"
+	feeConfigViaUpgrade    = "upgrade"
+)
+
+var (
+	feeGasLimit                 uint64
+	feeTargetBlockRate          uint64
+	feeMinBaseFee               uint64
+	feeTargetGas                uint64
+	feeBaseFeeChangeDenominator uint64
+	feeMinBlockGasCost          uint64
+	feeMaxBlockGasCost          uint64
+	feeBlockGasCostStep         uint64
+	feeVia                      string
+	feeKeyName                  string
+	feeActivation               string
+	feeAdmins                   []string
+	feeForce                    bool
+)
+
+// lux chain feeconfig
+func newFeeConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "feeconfig",
+		Short: "Show and tune the gas and fee config of an EVM chain",
+		Long: `The feeconfig command shows and tunes the dynamic fee config of an EVM chain:
+gas limit, target gas, target block rate, min base fee, base fee change
+denominator and block gas costs.
+
+A deployed chain with the FeeManager precompile enabled is changed right away
+with a setFeeConfig transaction. Otherwise the change is scheduled as a
+FeeManager precompile upgrade in the chain upgrade file.`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+
+	showCmd := newFeeConfigShowCmd()
+	addNetworkFlags(showCmd)
+	cmd.AddCommand(showCmd)
+
+	setCmd := newFeeConfigSetCmd()
+	addNetworkFlags(setCmd)
+	cmd.AddCommand(setCmd)
+	return cmd
+}
+
+// lux chain feeconfig show
+func newFeeConfigShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show [chainName]",
+		Short: "Show the fee config of a chain",
+		Long: `The feeconfig show command prints the fee config of the chain genesis, the
+last one scheduled in the chain upgrade file and, when the chain is deployed
+to the selected network with the FeeManager precompile enabled, the one live
+on chain.
+
+EXAMPLES:
+
+  lux chain feeconfig show mychain --devnet`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: showFeeConfig,
+	}
+}
+
+// lux chain feeconfig set
+func newFeeConfigSetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set [chainName]",
+		Short: "Change the fee config of a chain",
+		Long: `The feeconfig set command changes the given fee config parameters, keeping the
+others from the current config.
+
+With --via precompile (the default) the new config is set on the deployed
+chain through the FeeManager precompile, signed by --key, which must be
+enabled on the precompile allow list. With --via upgrade a FeeManager
+precompile upgrade activating the new config at --activation is added to the
+chain upgrade file, to be rolled out to the validators.
+
+The new config is checked to be valid and for its target gas to be reachable
+at its target block rate. Parameters raised or lowered by 2x or more are
+reported, and have to be confirmed on Mainnet.
+
+EXAMPLES:
+
+  # Double the gas limit and target gas of a local chain
+  lux chain feeconfig set mychain --gas-limit 16000000 --target-gas 30000000 --devnet
+
+  # Schedule a higher min base fee on Mainnet
+  lux chain feeconfig set mychain --min-base-fee 50000000000 --via upgrade \
+    --activation "2026-01-15 16:00:00" --admin 0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC --mainnet`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: setFeeConfig,
+	}
+	cmd.Flags().Uint64Var(&feeGasLimit, "gas-limit", 0, "max gas per block")
+	cmd.Flags().Uint64Var(&feeTargetBlockRate, "target-block-rate", 0, "target seconds between blocks")
+	cmd.Flags().Uint64Var(&feeMinBaseFee, "min-base-fee", 0, "min base fee in wei")
+	cmd.Flags().Uint64Var(&feeTargetGas, "target-gas", 0, fmt.Sprintf("gas targeted over the %ds fee window", vm.FeeWindowSeconds))
+	cmd.Flags().Uint64Var(&feeBaseFeeChangeDenominator, "base-fee-change-denominator", 0, "divisor of the base fee change, higher changes it slower")
+	cmd.Flags().Uint64Var(&feeMinBlockGasCost, "min-block-gas-cost", 0, "min gas charged for producing a block")
+	cmd.Flags().Uint64Var(&feeMaxBlockGasCost, "max-block-gas-cost", 0, "max gas charged for producing a block")
+	cmd.Flags().Uint64Var(&feeBlockGasCostStep, "block-gas-cost-step", 0, "block gas cost change per second off the target block rate")
+	cmd.Flags().StringVar(&feeVia, "via", feeConfigViaPrecompile, "apply the change through the FeeManager 'precompile' or an 'upgrade'")
+	cmd.Flags().StringVar(&feeKeyName, "key", "", "key signing the precompile transaction (from ~/.lux/keys/), the local key on local networks")
+	cmd.Flags().StringVar(&feeActivation, "activation", "", "UTC activation time of the upgrade, in 'YYYY-MM-DD HH:MM:SS' format")
+	cmd.Flags().StringSliceVar(&feeAdmins, "admin", nil, "FeeManager admin addresses, or @labels, of the upgrade")
+	cmd.Flags().BoolVar(&feeForce, "force", false, "apply large Mainnet changes without confirmation")
+	return cmd
+}
+
+// chainFeeConfigs are the fee configs of a chain, nil when not set
+type chainFeeConfigs struct {
+	Genesis *commontype.FeeConfig
+	// Upgrade is the initial fee config of the last FeeManager upgrade
+	Upgrade *commontype.FeeConfig
+	Live    *commontype.FeeConfig
+	// FeeManagerEnabled tells if the FeeManager precompile is enabled once
+	// genesis and all upgrades are active
+	FeeManagerEnabled bool
+}
+
+// current returns the fee config the chain runs with, or will run with once
+// its upgrades are active
+func (c chainFeeConfigs) current() *commontype.FeeConfig {
+	switch {
+	case c.Live != nil:
+		return c.Live
+	case c.Upgrade != nil:
+		return c.Upgrade
+	default:
+		return c.Genesis
+	}
+}
+
+// loadChainFeeConfigs reads the fee configs of chainName, asking the chain
+// deployed to target for its live config
+func loadChainFeeConfigs(chainName string, target NetworkTarget) (chainFeeConfigs, error) {
+	var configs chainFeeConfigs
+	data, err := os.ReadFile(app.GetGenesisPath(chainName)) //nolint:gosec // G304: Reading from app's data directory
+	if err != nil {
+		return configs, err
+	}
+	var genesis struct {
+		Config struct {
+			FeeConfig  *commontype.FeeConfig `json:"feeConfig"`
+			FeeManager json.RawMessage       `json:"feeManagerConfig"`
+		} `json:"config"`
+	}
+	if err := json.Unmarshal(data, &genesis); err != nil {
+		return configs, fmt.Errorf("invalid genesis of %s: %w", chainName, err)
+	}
+	if genesis.Config.FeeConfig == nil {
+		return configs, fmt.Errorf("%s has no EVM fee config in its genesis", chainName)
+	}
+	configs.Genesis = genesis.Config.FeeConfig
+	configs.FeeManagerEnabled = len(genesis.Config.FeeManager) > 0 && string(genesis.Config.FeeManager) != "null"
+
+	upgrades, err := loadChainUpgrades(chainName)
+	if err != nil {
+		return configs, err
+	}
+	for _, u := range upgrades.PrecompileUpgrades {
+		if u.Key() != feemanager.ConfigKey {
+			continue
+		}
+		configs.FeeManagerEnabled = !u.IsDisabled()
+		if config, ok := u.Config.(*feemanager.Config); ok && config.InitialFeeConfig != nil {
+			configs.Upgrade = config.InitialFeeConfig
+		}
+	}
+
+	if rpcURL, err := chainRPCEndpoint(chainName, target); err == nil {
+		// fails when the chain is not running or the precompile not active
+		if live, err := precompiles.FeeManagerGetFeeConfig(rpcURL); err == nil {
+			configs.Live = live
+		}
+	}
+	return configs, nil
+}
+
+func showFeeConfig(_ *cobra.Command, args []string) error {
+	chainName := args[0]
+	target := GetNetworkTarget()
+	configs, err := loadChainFeeConfigs(chainName, target)
+	if err != nil {
+		return err
+	}
+
+	header := []string{"Parameter", "Genesis"}
+	columns := []*commontype.FeeConfig{configs.Genesis}
+	if configs.Upgrade != nil {
+		header = append(header, "Upgrade")
+		columns = append(columns, configs.Upgrade)
+	}
+	if configs.Live != nil {
+		header = append(header, "Live on "+targetNetwork(target).String())
+		columns = append(columns, configs.Live)
+	}
+	printFeeConfigTable(header, columns)
+
+	if configs.FeeManagerEnabled {
+		ux.Logger.PrintToUser("FeeManager precompile: enabled")
+	} else {
+		ux.Logger.PrintToUser("FeeManager precompile: disabled, change the fee config with --via upgrade")
+	}
+	if configs.Live == nil {
+		ux.Logger.PrintToUser("No live fee config on %s: chain not reachable or FeeManager not active", targetNetwork(target).String())
+	}
+	return nil
+}
+
+func setFeeConfig(cmd *cobra.Command, args []string) error {
+	chainName := args[0]
+	if feeVia != feeConfigViaPrecompile && feeVia != feeConfigViaUpgrade {
+		return fmt.Errorf("invalid --via %q: use %s or %s", feeVia, feeConfigViaPrecompile, feeConfigViaUpgrade)
+	}
+	target := GetNetworkTarget()
+	network := targetNetwork(target)
+	configs, err := loadChainFeeConfigs(chainName, target)
+	if err != nil {
+		return err
+	}
+
+	var current *commontype.FeeConfig
+	if feeVia == feeConfigViaPrecompile {
+		if configs.Live == nil {
+			return fmt.Errorf("no live fee config for %s on %s: the chain must be running with the FeeManager precompile active, or use --via upgrade", chainName, network.String())
+		}
+		current = configs.Live
+	} else {
+		if configs.FeeManagerEnabled {
+			return fmt.Errorf("the FeeManager precompile of %s is already enabled: use --via precompile", chainName)
+		}
+		current = configs.current()
+	}
+
+	updated, err := updatedFeeConfig(cmd, current)
+	if err != nil {
+		return err
+	}
+	if current.Equal(updated) {
+		ux.Logger.PrintToUser("The fee config of %s is unchanged", chainName)
+		return nil
+	}
+	if err := vm.CheckFeeConfig(updated); err != nil {
+		return fmt.Errorf("invalid fee config: %w", err)
+	}
+	printFeeConfigTable([]string{"Parameter", "Current", "New"}, []*commontype.FeeConfig{current, updated})
+
+	if changes := vm.LargeFeeConfigChanges(current, updated); len(changes) > 0 {
+		ux.Logger.PrintToUser("Warning: large fee config changes")
+		for _, change := range changes {
+			ux.Logger.PrintToUser("  - %s", change)
+		}
+		if network == models.Mainnet && !feeForce {
+			if !prompts.IsInteractive() {
+				return errors.New("confirmation required: use --force to apply large Mainnet changes")
+			}
+			confirm, err := app.Prompt.CaptureYesNo("Apply these changes on Mainnet?")
+			if err != nil {
+				return err
+			}
+			if !confirm {
+				ux.Logger.PrintToUser("Cancelled")
+				return nil
+			}
+		}
+	}
+
+	if feeVia == feeConfigViaUpgrade {
+		return scheduleFeeConfigUpgrade(chainName, updated)
+	}
+	privateKey, err := signingKey(network, feeKeyName)
+	if err != nil {
+		return err
+	}
+	rpcURL, err := chainRPCEndpoint(chainName, target)
+	if err != nil {
+		return err
+	}
+	if err := precompiles.FeeManagerSetFeeConfig(rpcURL, privateKey, updated); err != nil {
+		return fmt.Errorf("failed to set the fee config: %w", err)
+	}
+	ux.Logger.PrintToUser("Fee config of %s on %s updated", chainName, network.String())
+	return nil
+}
+
+// updatedFeeConfig returns current with the parameters set by flags
+func updatedFeeConfig(cmd *cobra.Command, current *commontype.FeeConfig) (*commontype.FeeConfig, error) {
+	updated := *current
+	params := []struct {
+		flag  string
+		value uint64
+		field **big.Int
+	}{
+		{"gas-limit", feeGasLimit, &updated.GasLimit},
+		{"min-base-fee", feeMinBaseFee, &updated.MinBaseFee},
+		{"target-gas", feeTargetGas, &updated.TargetGas},
+		{"base-fee-change-denominator", feeBaseFeeChangeDenominator, &updated.BaseFeeChangeDenominator},
+		{"min-block-gas-cost", feeMinBlockGasCost, &updated.MinBlockGasCost},
+		{"max-block-gas-cost", feeMaxBlockGasCost, &updated.MaxBlockGasCost},
+		{"block-gas-cost-step", feeBlockGasCostStep, &updated.BlockGasCostStep},
+	}
+	changed := false
+	for _, p := range params {
+		if cmd.Flags().Changed(p.flag) {
+			*p.field = new(big.Int).SetUint64(p.value)
+			changed = true
+		}
+	}
+	if cmd.Flags().Changed("target-block-rate") {
+		updated.TargetBlockRate = feeTargetBlockRate
+		changed = true
+	}
+	if !changed {
+		return nil, errors.New("no fee config parameter to set, see --help")
+	}
+	return &updated, nil
+}
+
+// scheduleFeeConfigUpgrade adds a FeeManager upgrade activating feeConfig to
+// the chain upgrade file
+func scheduleFeeConfigUpgrade(chainName string, feeConfig *commontype.FeeConfig) error {
+	var activation time.Time
+	var err error
+	if feeActivation != "" {
+		activation, err = time.Parse(constants.TimeParseLayout, feeActivation)
+		if err != nil {
+			return fmt.Errorf("invalid --activation %q: %w", feeActivation, err)
+		}
+		if !activation.After(time.Now()) {
+			return fmt.Errorf("--activation %s is not in the future", feeActivation)
+		}
+	} else {
+		activation, err = app.Prompt.CaptureFutureDate(
+			"Enter the block activation UTC datetime in 'YYYY-MM-DD HH:MM:SS' format", time.Now().Add(time.Minute).UTC())
+		if err != nil {
+			return err
+		}
+	}
+	adminAddrs, err := app.ResolveAddresses(feeAdmins)
+	if err != nil {
+		return err
+	}
+	admins := make([]common.Address, 0, len(adminAddrs))
+	for _, admin := range adminAddrs {
+		if !common.IsHexAddress(admin) {
+			return fmt.Errorf("invalid --admin address %q", admin)
+		}
+		admins = append(admins, common.HexToAddress(admin))
+	}
+
+	upgrades, err := loadChainUpgrades(chainName)
+	if err != nil {
+		return err
+	}
+	upgrades.PrecompileUpgrades = append(upgrades.PrecompileUpgrades, extras.PrecompileUpgrade{
+		Config: feemanager.NewConfig(
+			evmutils.NewUint64(uint64(activation.Unix())), //nolint:gosec // G115: activation is in the future
+			admins,
+			nil,
+			nil,
+			feeConfig,
+		),
+	})
+	upgradeBytes, err := json.Marshal(&upgrades)
+	if err != nil {
+		return err
+	}
+	if err := app.WriteUpgradeFile(chainName, upgradeBytes); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Fee config upgrade of %s activating at %s written to %s",
+		chainName, activation.UTC().Format(constants.TimeParseLayout), app.GetUpgradeBytesFilePath(chainName))
+	upgradecmd.PrintHowToApplyConfChangesMessage(chainName)
+	return nil
+}
+
+// signingKey returns the hex private key of keyName, or of the local key on
+// local networks when keyName is empty
+func signingKey(network models.Network, keyName string) (string, error) {
+	if keyName != "" {
+		k, err := key.LoadSoft(network.ID(), app.GetKeyPath(keyName))
+		if err != nil {
+			return "", fmt.Errorf("failed to load key %s: %w", keyName, err)
+		}
+		return k.PrivKeyHex(), nil
+	}
+	if network == models.Mainnet || network == models.Testnet {
+		return "", fmt.Errorf("--key is required on %s", network.String())
+	}
+	k, err := key.GetOrCreateLocalKey(network.ID())
+	if err != nil {
+		return "", err
+	}
+	return k.PrivKeyHex(), nil
+}
+
+func printFeeConfigTable(header []string, columns []*commontype.FeeConfig) {
+	rows := []struct {
+		name  string
+		value func(*commontype.FeeConfig) string
+	}{
+		{"gasLimit", func(c *commontype.FeeConfig) string { return bigString(c.GasLimit) }},
+		{"targetBlockRate", func(c *commontype.FeeConfig) string { return fmt.Sprintf("%ds", c.TargetBlockRate) }},
+		{"minBaseFee", func(c *commontype.FeeConfig) string { return bigString(c.MinBaseFee) }},
+		{"targetGas", func(c *commontype.FeeConfig) string { return bigString(c.TargetGas) }},
+		{"baseFeeChangeDenominator", func(c *commontype.FeeConfig) string { return bigString(c.BaseFeeChangeDenominator) }},
+		{"minBlockGasCost", func(c *commontype.FeeConfig) string { return bigString(c.MinBlockGasCost) }},
+		{"maxBlockGasCost", func(c *commontype.FeeConfig) string { return bigString(c.MaxBlockGasCost) }},
+		{"blockGasCostStep", func(c *commontype.FeeConfig) string { return bigString(c.BlockGasCostStep) }},
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header(header)
+	for _, row := range rows {
+		line := []string{row.name}
+		for _, column := range columns {
+			line = append(line, row.value(column))
+		}
+		_ = table.Append(line)
+	}
+	_ = table.Render()
+}
+
+func bigString(n *big.Int) string {
+	if n == nil {
+		return "-"
+	}
+	return n.String()
+}
@@ -0,0 +1,337 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/prompts"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/vm"
+	"github.com/luxfi/constants"
+	"github.com/spf13/cobra"
+)
+
+var (
+	genesisTemplateName    string
+	genesisChainID         uint64
+	genesisGasLimit        uint64
+	genesisTargetGas       uint64
+	genesisMinBaseFee      uint64
+	genesisTargetBlockRate uint64
+	genesisAllocs          []string
+	genesisAllocCSV        string
+	genesisMinterAdmins    []string
+	genesisAllowListAdmins []string
+	genesisFeeAdmins       []string
+	genesisOutput          string
+	genesisSaveTemplate    string
+)
+
+// lux chain genesis
+func newGenesisCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "genesis",
+		Short: "Build EVM genesis files and reusable genesis templates",
+		Long: `The genesis command builds EVM genesis files from prompts or flags, and manages
+named genesis templates stored in ~/.lux/templates/ that 'lux chain create
+--template' reuses.`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.AddCommand(newGenesisWizardCmd())
+	cmd.AddCommand(newGenesisTemplatesCmd())
+	return cmd
+}
+
+// lux chain genesis wizard
+func newGenesisWizardCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wizard",
+		Short: "Build an EVM genesis from prompts or flags",
+		Long: `The genesis wizard command builds an EVM genesis: chain ID, fee config, gas
+limits, allocations and the admins of the native minter, tx allow list and
+fee manager precompiles, which are activated at genesis when given admins.
+
+It starts from the default genesis settings, or from the template given with
+--template, then applies the flags. When interactive, the values not given
+as flags are prompted for, empty answers keeping the current value.
+
+Allocations are given in tokens of 18 decimals, as --alloc address=amount or
+imported from a CSV file of address,amount lines with --alloc-csv.
+
+The genesis is written to --output, or printed. With --save-template the
+settings are saved as a named template for 'lux chain create --template'.
+
+EXAMPLES:
+
+  # Build a genesis interactively
+  lux chain genesis wizard --output genesis.json
+
+  # Build a genesis from flags and save it as a template
+  lux chain genesis wizard --evm-chain-id 7777 --gas-limit 15000000 \
+    --alloc-csv allocations.csv --native-minter-admins 0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC \
+    --save-template gaming
+
+  # Create a chain from the template
+  lux chain create mygame --template gaming`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: genesisWizard,
+	}
+	cmd.Flags().StringVar(&genesisTemplateName, "template", "", "start from this genesis template")
+	cmd.Flags().Uint64Var(&genesisChainID, "evm-chain-id", 0, "EVM chain ID")
+	cmd.Flags().Uint64Var(&genesisGasLimit, "gas-limit", 0, "max gas per block")
+	cmd.Flags().Uint64Var(&genesisTargetGas, "target-gas", 0, fmt.Sprintf("gas targeted over the %ds fee window", vm.FeeWindowSeconds))
+	cmd.Flags().Uint64Var(&genesisMinBaseFee, "min-base-fee", 0, "min base fee in wei")
+	cmd.Flags().Uint64Var(&genesisTargetBlockRate, "target-block-rate", 0, "target seconds between blocks")
+	cmd.Flags().StringSliceVar(&genesisAllocs, "alloc", nil, "allocations as address=amount or @label=amount, in tokens")
+	cmd.Flags().StringVar(&genesisAllocCSV, "alloc-csv", "", "import allocations from a CSV file of address,amount lines")
+	cmd.Flags().StringSliceVar(&genesisMinterAdmins, "native-minter-admins", nil, "admins of the native minter precompile")
+	cmd.Flags().StringSliceVar(&genesisAllowListAdmins, "tx-allowlist-admins", nil, "admins of the tx allow list precompile")
+	cmd.Flags().StringSliceVar(&genesisFeeAdmins, "fee-manager-admins", nil, "admins of the fee manager precompile")
+	cmd.Flags().StringVar(&genesisOutput, "output", "", "write the genesis to this file instead of printing it")
+	cmd.Flags().StringVar(&genesisSaveTemplate, "save-template", "", "save the settings as this genesis template")
+	return cmd
+}
+
+// lux chain genesis templates
+func newGenesisTemplatesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "templates",
+		Short: "List the genesis templates",
+		Long: `The genesis templates command lists the genesis templates saved in
+~/.lux/templates/ with their chain ID, gas limit, allocations and enabled
+precompiles.`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: listGenesisTemplates,
+	}
+}
+
+func genesisWizard(cmd *cobra.Command, _ []string) (err error) {
+	progress := ux.Progress.Start("chain genesis wizard", 4)
+	defer func() {
+		if err != nil {
+			_ = progress.Fail(err)
+			return
+		}
+		progress.Done("Genesis built")
+	}()
+
+	progress.Step("settings", "Setting the chain ID and fee config")
+	template := vm.DefaultGenesisTemplate()
+	if genesisTemplateName != "" {
+		if template, err = vm.LoadGenesisTemplate(app.GetBaseDir(), genesisTemplateName); err != nil {
+			return err
+		}
+	}
+	flags := cmd.Flags()
+	interactive := prompts.IsInteractive()
+
+	uint64Settings := []struct {
+		flag   string
+		prompt string
+		value  uint64
+		set    func(uint64)
+		get    func() uint64
+	}{
+		{"evm-chain-id", "EVM chain ID", genesisChainID,
+			func(v uint64) { template.ChainID = v },
+			func() uint64 { return template.ChainID }},
+		{"gas-limit", "Gas limit per block", genesisGasLimit,
+			func(v uint64) { template.FeeConfig.GasLimit = new(big.Int).SetUint64(v) },
+			func() uint64 { return template.FeeConfig.GasLimit.Uint64() }},
+		{"target-gas", fmt.Sprintf("Target gas over %ds", vm.FeeWindowSeconds), genesisTargetGas,
+			func(v uint64) { template.FeeConfig.TargetGas = new(big.Int).SetUint64(v) },
+			func() uint64 { return template.FeeConfig.TargetGas.Uint64() }},
+		{"min-base-fee", "Min base fee in wei", genesisMinBaseFee,
+			func(v uint64) { template.FeeConfig.MinBaseFee = new(big.Int).SetUint64(v) },
+			func() uint64 { return template.FeeConfig.MinBaseFee.Uint64() }},
+		{"target-block-rate", "Target seconds between blocks", genesisTargetBlockRate,
+			func(v uint64) { template.FeeConfig.TargetBlockRate = v },
+			func() uint64 { return template.FeeConfig.TargetBlockRate }},
+	}
+	for _, s := range uint64Settings {
+		switch {
+		case flags.Changed(s.flag):
+			s.set(s.value)
+		case interactive:
+			answer, err := app.Prompt.CaptureStringAllowEmpty(fmt.Sprintf("%s (default: %d)", s.prompt, s.get()))
+			if err != nil {
+				return err
+			}
+			if answer = strings.TrimSpace(answer); answer != "" {
+				v, err := strconv.ParseUint(answer, 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid %s: %w", s.flag, err)
+				}
+				s.set(v)
+			}
+		}
+	}
+
+	progress.Step("allocations", "Setting the allocations")
+	allocs := genesisAllocs
+	allocCSV := genesisAllocCSV
+	if interactive && !flags.Changed("alloc") && !flags.Changed("alloc-csv") {
+		if allocCSV, err = app.Prompt.CaptureStringAllowEmpty("CSV file of address,amount allocations (empty to skip)"); err != nil {
+			return err
+		}
+		if allocCSV == "" {
+			answer, err := app.Prompt.CaptureStringAllowEmpty("Allocations as address=amount, comma separated (empty to skip)")
+			if err != nil {
+				return err
+			}
+			allocs = splitList(answer)
+		}
+	}
+	if allocCSV != "" || len(allocs) > 0 {
+		allocations, err := parseWizardAllocations(allocs, allocCSV)
+		if err != nil {
+			return err
+		}
+		template.Allocations = allocations
+	}
+
+	progress.Step("precompiles", "Setting the precompile admins")
+	adminSettings := []struct {
+		flag   string
+		prompt string
+		value  []string
+		admins *[]string
+	}{
+		{"native-minter-admins", "Native minter admins", genesisMinterAdmins, &template.NativeMinterAdmins},
+		{"tx-allowlist-admins", "Tx allow list admins", genesisAllowListAdmins, &template.TxAllowListAdmins},
+		{"fee-manager-admins", "Fee manager admins", genesisFeeAdmins, &template.FeeManagerAdmins},
+	}
+	for _, s := range adminSettings {
+		switch {
+		case flags.Changed(s.flag):
+			*s.admins = s.value
+		case interactive:
+			answer, err := app.Prompt.CaptureStringAllowEmpty(
+				fmt.Sprintf("%s, comma separated (empty to keep %q, 'none' to disable)", s.prompt, strings.Join(*s.admins, ",")),
+			)
+			if err != nil {
+				return err
+			}
+			switch answer = strings.TrimSpace(answer); answer {
+			case "":
+			case "none":
+				*s.admins = nil
+			default:
+				*s.admins = splitList(answer)
+			}
+		}
+	}
+
+	progress.Step("genesis", "Writing the genesis")
+	genesis, err := template.Genesis()
+	if err != nil {
+		return err
+	}
+	if genesisSaveTemplate != "" {
+		if err := vm.SaveGenesisTemplate(app.GetBaseDir(), genesisSaveTemplate, template); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Saved genesis template %s to %s", genesisSaveTemplate, vm.GenesisTemplatePath(app.GetBaseDir(), genesisSaveTemplate))
+	}
+	if genesisOutput == "" {
+		ux.Logger.PrintToUser("%s", genesis)
+		return nil
+	}
+	if err := os.WriteFile(genesisOutput, genesis, constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Genesis written to %s", genesisOutput)
+	return nil
+}
+
+// parseWizardAllocations returns the allocations of the CSV file csvPath
+// followed by allocs, given as address=amount, @label addresses resolved
+func parseWizardAllocations(allocs []string, csvPath string) ([]vm.AllocationEntry, error) {
+	var allocations []vm.AllocationEntry
+	if csvPath != "" {
+		f, err := os.Open(csvPath) //nolint:gosec // G304: User-specified allocations file
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if allocations, err = vm.ParseAllocationsCSV(f); err != nil {
+			return nil, fmt.Errorf("invalid allocations file %s: %w", csvPath, err)
+		}
+	}
+	for _, alloc := range allocs {
+		address, amount, ok := strings.Cut(alloc, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid allocation %q, expected address=amount", alloc)
+		}
+		balance, err := vm.ParseTokenAmount(amount)
+		if err != nil {
+			return nil, err
+		}
+		allocations = append(allocations, vm.AllocationEntry{Address: strings.TrimSpace(address), Balance: balance.String()})
+	}
+	for i := range allocations {
+		addr, err := app.ResolveAddress(allocations[i].Address)
+		if err != nil {
+			return nil, err
+		}
+		allocations[i].Address = addr
+	}
+	return allocations, nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func listGenesisTemplates(_ *cobra.Command, _ []string) error {
+	names, err := vm.ListGenesisTemplates(app.GetBaseDir())
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		ux.Logger.PrintToUser("No genesis templates. Create one with 'lux chain genesis wizard --save-template <name>'")
+		return nil
+	}
+	for _, name := range names {
+		template, err := vm.LoadGenesisTemplate(app.GetBaseDir(), name)
+		if err != nil {
+			ux.Logger.PrintToUser("%s: %s", name, err)
+			continue
+		}
+		var precompiles []string
+		if len(template.NativeMinterAdmins) > 0 {
+			precompiles = append(precompiles, "native minter")
+		}
+		if len(template.TxAllowListAdmins) > 0 {
+			precompiles = append(precompiles, "tx allow list")
+		}
+		if len(template.FeeManagerAdmins) > 0 {
+			precompiles = append(precompiles, "fee manager")
+		}
+		if len(precompiles) == 0 {
+			precompiles = []string{"none"}
+		}
+		ux.Logger.PrintToUser(
+			"%s: chain ID %d, gas limit %s, %d allocations, precompiles: %s",
+			name,
+			template.ChainID,
+			template.FeeConfig.GasLimit,
+			len(template.Allocations),
+			strings.Join(precompiles, ", "),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"fmt"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/contract"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/validator"
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/ethclient"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+	"github.com/spf13/cobra"
+)
+
+const (
+	validatorManagementPoA = "proof-of-authority"
+	validatorManagementPoS = "proof-of-stake"
+)
+
+var (
+	importDeployedChainID      string
+	importDeployedBlockchainID string
+	importDeployedRPC          string
+	importDeployedForce        bool
+)
+
+func newImportDeployedCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-deployed [blockchainName]",
+		Short: "Import a chain deployed with other tooling, with its genesis and validator manager",
+		Long: `The import-deployed command brings a chain deployed with other tooling
+under the management of the CLI. It reads the owners, validators and VM of
+the chain from the P-Chain, as 'lux chain adopt' does, and the genesis from
+the CreateChainTx of the blockchain. From the RPC of the blockchain it reads
+the EVM chain ID and, for L1s, whether the validator manager is Proof of
+Authority or Proof of Stake and who owns it.
+
+The sidecar and genesis are written under the blockchain name, or the name
+given, so the validator and upgrade commands can manage the chain. The RPC
+defaults to the one the network serves for the blockchain.
+
+EXAMPLES:
+
+  lux chain import-deployed --subnet-id 2sEF...dZqp --blockchain-id 2ebC...6tt \
+    --rpc https://rpc.mychain.network --mainnet
+
+  lux chain import-deployed mychain --chain-id 2sEF...dZqp --blockchain-id 2ebC...6tt --testnet`,
+		Args: cobrautils.MaximumNArgs(1),
+		RunE: importDeployed,
+	}
+	cmd.Flags().StringVar(&importDeployedChainID, "chain-id", "", "ID of the chain (subnet) to import")
+	cmd.Flags().StringVar(&importDeployedChainID, "subnet-id", "", "alias for --chain-id")
+	_ = cmd.Flags().MarkHidden("subnet-id")
+	cmd.Flags().StringVar(&importDeployedBlockchainID, "blockchain-id", "", "ID of the blockchain to import")
+	cmd.Flags().StringVar(&importDeployedRPC, "rpc", "", "RPC URL of the blockchain (default: the network RPC of the blockchain)")
+	cmd.Flags().BoolVar(&importDeployedForce, "force", false, "overwrite a sidecar already holding another deployment to the network")
+	_ = cmd.MarkFlagRequired("blockchain-id")
+	return cmd
+}
+
+func importDeployed(_ *cobra.Command, args []string) error {
+	a, err := fetchAdoption(importDeployedChainID, importDeployedBlockchainID)
+	if err != nil {
+		return err
+	}
+	chainName := a.blockchain.Name
+	if len(args) > 0 {
+		chainName = args[0]
+	}
+	sc, err := a.sidecar(chainName, importDeployedForce)
+	if err != nil {
+		return err
+	}
+	createChainTx, err := utils.GetBlockchainTx(a.endpoint, a.blockchain.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get the genesis of %s: %w", a.blockchain.ID, err)
+	}
+
+	networkKey := a.network.String()
+	networkData := sc.Networks[networkKey]
+	rpcURL := importDeployedRPC
+	if rpcURL == "" {
+		rpcURL = networkData.RPCEndpoints[0]
+	}
+	networkData.RPCEndpoints = []string{rpcURL}
+	sc.Networks[networkKey] = networkData
+	if sc.VM == models.EVM {
+		if sc.EVMChainID, err = evmChainID(rpcURL); err != nil {
+			return fmt.Errorf("failed to get the EVM chain ID from %s, pass the RPC of the blockchain with --rpc: %w", rpcURL, err)
+		}
+	}
+	if common.IsHexAddress(networkData.ValidatorManagerAddress) {
+		managerRPC := rpcURL
+		if a.chainInfo.ManagerChainID != a.blockchain.ID {
+			managerRPC = models.GetRPCEndpoint(a.endpoint, a.chainInfo.ManagerChainID.String())
+		}
+		if err := readValidatorManager(&sc, managerRPC, a.chainID, common.HexToAddress(networkData.ValidatorManagerAddress)); err != nil {
+			return err
+		}
+	}
+
+	if err := app.WriteGenesisFile(chainName, createChainTx.GenesisData); err != nil {
+		return fmt.Errorf("failed to write the genesis of %s: %w", chainName, err)
+	}
+	if err := writeAdoptedSidecar(&sc); err != nil {
+		return err
+	}
+	if err := a.print(chainName, sc); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("  Genesis:       %s (%d bytes)", app.GetGenesisPath(chainName), len(createChainTx.GenesisData))
+	ux.Logger.PrintToUser("  RPC:           %s", rpcURL)
+	if sc.EVMChainID != "" {
+		ux.Logger.PrintToUser("  EVM chain ID:  %s", sc.EVMChainID)
+	}
+	if sc.ValidatorManagement != "" {
+		ux.Logger.PrintToUser("  Management:    %s, owner %s", sc.ValidatorManagement, sc.ValidatorManagerOwner)
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Manage it with 'lux validator' and 'lux chain upgrade', as a chain deployed with the CLI.")
+	return nil
+}
+
+// evmChainID returns the EVM chain ID served at rpcURL
+func evmChainID(rpcURL string) (string, error) {
+	client, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return chainID.String(), nil
+}
+
+// readValidatorManager records in sc how the validator manager contract at
+// manager, which must manage chainID, manages the validators
+func readValidatorManager(sc *models.Sidecar, rpcURL string, chainID ids.ID, manager common.Address) error {
+	client, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", rpcURL, err)
+	}
+	defer client.Close()
+	underlying := common.Address(validator.GetUnderlyingValidatorManager(rpcURL, crypto.Address(manager)))
+	ctx, cancel := utils.GetAPILargeContext()
+	defer cancel()
+	state, err := validator.GetManagerState(ctx, client, underlying)
+	if err != nil {
+		return fmt.Errorf("failed to read validator manager %s: %w", manager.Hex(), err)
+	}
+	if state.ChainID != chainID {
+		return fmt.Errorf("validator manager %s manages chain %s, not %s", manager.Hex(), state.ChainID, chainID)
+	}
+	owner, err := contract.GetContractOwner(rpcURL, crypto.Address(manager))
+	if err != nil {
+		return fmt.Errorf("failed to read the owner of validator manager %s: %w", manager.Hex(), err)
+	}
+	setValidatorManagement(sc, underlying != manager, common.Address(owner))
+	return nil
+}
+
+// setValidatorManagement records in sc a Proof of Stake, or else Proof of
+// Authority, validator manager owned by owner
+func setValidatorManagement(sc *models.Sidecar, pos bool, owner common.Address) {
+	sc.PoS = pos
+	sc.ValidatorManagement = validatorManagementPoA
+	if pos {
+		sc.ValidatorManagement = validatorManagementPoS
+	}
+	sc.ValidatorManagerOwner = owner.Hex()
+}
@@ -0,0 +1,37 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/sdk/models"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetValidatorManagement(t *testing.T) {
+	require := require.New(t)
+	owner := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+
+	sc := models.Sidecar{}
+	setValidatorManagement(&sc, false, owner)
+	require.False(sc.PoS)
+	require.Equal(validatorManagementPoA, sc.ValidatorManagement)
+	require.Equal(owner.Hex(), sc.ValidatorManagerOwner)
+
+	setValidatorManagement(&sc, true, owner)
+	require.True(sc.PoS)
+	require.Equal(validatorManagementPoS, sc.ValidatorManagement)
+}
+
+func TestImportDeployedFlags(t *testing.T) {
+	require := require.New(t)
+	t.Cleanup(func() { importDeployedChainID = "" })
+	cmd := newImportDeployedCmd()
+	require.NoError(cmd.ParseFlags([]string{"--subnet-id", "chain"}))
+	require.Equal("chain", importDeployedChainID)
+	require.Equal([]string{"true"}, cmd.Flags().Lookup("blockchain-id").Annotations[cobra.BashCompOneRequiredFlag])
+}
@@ -0,0 +1,543 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/luxfi/cli/pkg/chainindex"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/config"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/database"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/ethclient"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	indexInterval time.Duration
+	indexReset    bool
+	indexAPI      string
+	indexChain    string
+	txsLimit      int
+	logsAddress   string
+	logsTopic     string
+	logsFromBlock uint64
+	logsToBlock   uint64
+)
+
+func newIndexCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "index",
+		Short: "Index the blocks, transactions and logs of deployed chains for local queries",
+		Long: `The index command keeps a local index of the blocks, transactions and logs of
+a deployed EVM chain, so they can be looked up with 'lux chain txs',
+'lux chain tx', 'lux chain account' and 'lux chain logs', or through the
+query API of the indexer, without running an explorer.
+
+Indexes live in ~/.lux/index/<network>/<chain>.`,
+		RunE:        cobrautils.CommandSuiteUsage,
+		Annotations: readOnlyAnnotation(),
+	}
+	startCmd := newIndexStartCmd()
+	addNetworkFlags(startCmd)
+	cmd.AddCommand(startCmd)
+	return cmd
+}
+
+func newIndexStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start <blockchainName>",
+		Short: "Index a chain's blocks until interrupted",
+		Long: `The index start command indexes the blocks of a deployed chain from genesis,
+then follows the chain head until interrupted. Restarting it resumes from
+the last indexed block.
+
+An index built from a chain that was since redeployed under the same name,
+or by an older version of the indexer, is refused. Use --reset to rebuild it.
+
+--api serves the index as JSON over HTTP while indexing:
+
+  GET /status                      what the index covers
+  GET /blocks?limit=               latest blocks
+  GET /blocks/{number}             one block
+  GET /txs?limit=                  latest transactions
+  GET /txs/{hash}                  one transaction
+  GET /accounts/{address}/txs      latest transactions of an account
+  GET /logs?address=&topic=&fromBlock=&toBlock=&limit=
+
+EXAMPLES:
+
+  # Index mychain on the local devnet
+  lux chain index start mychain --devnet
+
+  # Index and serve the query API
+  lux chain index start mychain --devnet --api 127.0.0.1:8095
+
+  # Rebuild the index after redeploying the chain
+  lux chain index start mychain --devnet --reset`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: indexStart,
+	}
+	addIndexerFlags(cmd, "")
+	return cmd
+}
+
+func addIndexerFlags(cmd *cobra.Command, defaultAPI string) {
+	cmd.Flags().DurationVar(&indexInterval, "interval", 2*time.Second, "how often to check for new blocks")
+	cmd.Flags().BoolVar(&indexReset, "reset", false, "discard the existing index and rebuild it from genesis")
+	cmd.Flags().StringVar(&indexAPI, "api", defaultAPI, "address to serve the query API on, empty to disable it")
+}
+
+func indexStart(_ *cobra.Command, args []string) error {
+	return runIndexer(args[0])
+}
+
+// runIndexer indexes chainName until interrupted, serving the query API
+// when --api is set
+func runIndexer(chainName string) error {
+	target := GetNetworkTarget()
+	rpcURL, err := chainRPCEndpoint(chainName, target)
+	if err != nil {
+		return err
+	}
+	path := chainindex.Path(app.GetBaseDir(), targetNetwork(target).String(), chainName)
+	if indexReset {
+		if err := chainindex.Reset(path); err != nil {
+			return err
+		}
+	}
+	client, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", rpcURL, err)
+	}
+	defer client.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if indexAPI != "" {
+		listener, err := net.Listen("tcp", indexAPI)
+		if err != nil {
+			return fmt.Errorf("failed to serve the query API: %w", err)
+		}
+		server := &http.Server{Handler: chainindex.Handler(path), ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			<-ctx.Done()
+			_ = server.Close()
+		}()
+		go func() {
+			_ = server.Serve(listener)
+		}()
+		ux.Logger.PrintToUser("Serving the query API on http://%s", listener.Addr())
+	}
+	ux.Logger.PrintToUser("Indexing %s from %s into %s (Ctrl+C to stop)", chainName, rpcURL, path)
+	err = chainindex.Run(ctx, path, client, indexInterval, func(meta *chainindex.Meta, n uint64) {
+		ux.Logger.PrintToUser("Indexed %d blocks, at block %d", n, meta.Height)
+	})
+	switch {
+	case errors.Is(err, chainindex.ErrOtherChain):
+		return fmt.Errorf("%w: the chain was redeployed, use --reset to rebuild the index", err)
+	case errors.Is(err, chainindex.ErrOldIndex):
+		return fmt.Errorf("%w, use --reset", err)
+	}
+	return err
+}
+
+func newTxsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "txs [blockchainName]",
+		Short: "List the latest indexed transactions",
+		Long: `The txs command lists the latest transactions of a chain indexed with
+'lux chain index start', newest first. Without a chain name it lists those
+of every indexed chain on the network.
+
+EXAMPLES:
+
+  lux chain txs mychain --devnet --limit 50`,
+		Args:        cobrautils.MaximumNArgs(1),
+		RunE:        listTxs,
+		Annotations: readOnlyAnnotation(),
+	}
+	cmd.Flags().IntVar(&txsLimit, "limit", 20, "maximum number of transactions to list per chain")
+	return cmd
+}
+
+func listTxs(_ *cobra.Command, args []string) error {
+	chainNames, err := indexedChains(args)
+	if err != nil {
+		return err
+	}
+	for i, chainName := range chainNames {
+		if i > 0 {
+			ux.Logger.PrintToUser("")
+		}
+		var txs []*chainindex.Tx
+		meta, err := withIndex(chainName, func(ix *chainindex.Index) (err error) {
+			txs, err = ix.Txs(txsLimit)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		printIndexHeader(chainName, meta)
+		if len(txs) == 0 {
+			ux.Logger.PrintToUser("No transactions indexed")
+			continue
+		}
+		if err := printTxs(txs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTxCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tx <hash>",
+		Short: "Show an indexed transaction",
+		Long: `The tx command shows a transaction indexed with 'lux chain index start',
+looking it up in every indexed chain on the network unless --chain is given.
+
+EXAMPLES:
+
+  lux chain tx 0x5c50...e9a1 --devnet`,
+		Args:        cobrautils.ExactArgs(1),
+		RunE:        showTx,
+		Annotations: readOnlyAnnotation(),
+	}
+	cmd.Flags().StringVar(&indexChain, "chain", "", "only look in this chain's index")
+	return cmd
+}
+
+func showTx(_ *cobra.Command, args []string) error {
+	hash, err := chainindex.ParseHash(args[0])
+	if err != nil {
+		return err
+	}
+	chainNames, err := indexedChains(chainFlagArgs())
+	if err != nil {
+		return err
+	}
+	for _, chainName := range chainNames {
+		var tx *chainindex.Tx
+		if _, err := withIndex(chainName, func(ix *chainindex.Index) (err error) {
+			tx, err = ix.Tx(hash)
+			return err
+		}); err != nil {
+			if errors.Is(err, database.ErrNotFound) {
+				continue
+			}
+			return err
+		}
+		printTx(chainName, tx)
+		return nil
+	}
+	return fmt.Errorf("transaction %s is not indexed: is 'lux chain index start' running for its chain?", hash)
+}
+
+func newAccountCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "account <address>",
+		Short: "Show an account's balance and indexed transactions",
+		Long: `The account command shows the balance of an address on each indexed chain
+of the network, when the chain is reachable, and the latest indexed
+transactions sent by, sent to or deploying it.
+
+EXAMPLES:
+
+  lux chain account 0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC --devnet`,
+		Args:        cobrautils.ExactArgs(1),
+		RunE:        showAccount,
+		Annotations: readOnlyAnnotation(),
+	}
+	cmd.Flags().StringVar(&indexChain, "chain", "", "only show this chain")
+	cmd.Flags().IntVar(&txsLimit, "limit", 20, "maximum number of transactions to list per chain")
+	return cmd
+}
+
+func showAccount(_ *cobra.Command, args []string) error {
+	if !common.IsHexAddress(args[0]) {
+		return fmt.Errorf("invalid address %q", args[0])
+	}
+	addr := common.HexToAddress(args[0])
+	chainNames, err := indexedChains(chainFlagArgs())
+	if err != nil {
+		return err
+	}
+	target := GetNetworkTarget()
+	for i, chainName := range chainNames {
+		if i > 0 {
+			ux.Logger.PrintToUser("")
+		}
+		var txs []*chainindex.Tx
+		meta, err := withIndex(chainName, func(ix *chainindex.Index) (err error) {
+			txs, err = ix.AccountTxs(addr, txsLimit)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		printIndexHeader(chainName, meta)
+		if balance, err := accountBalance(chainName, target, addr); err == nil {
+			ux.Logger.PrintToUser("Balance: %s wei", balance)
+		} else {
+			ux.Logger.PrintToUser("Balance: unavailable (%v)", err)
+		}
+		if len(txs) == 0 {
+			ux.Logger.PrintToUser("No transactions indexed")
+			continue
+		}
+		if err := printTxs(txs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs [blockchainName]",
+		Short: "List indexed event logs",
+		Long: `The logs command lists the latest event logs of a chain indexed with
+'lux chain index start', newest first, optionally only those of a contract
+or of an event, by its signature hash (the first topic). Without a chain
+name it lists those of every indexed chain on the network.
+
+EXAMPLES:
+
+  # Transfer events of a token
+  lux chain logs mychain --devnet --address 0x52C8...4F1a \
+    --topic 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef`,
+		Args:        cobrautils.MaximumNArgs(1),
+		RunE:        listLogs,
+		Annotations: readOnlyAnnotation(),
+	}
+	cmd.Flags().StringVar(&logsAddress, "address", "", "only list logs emitted by this contract")
+	cmd.Flags().StringVar(&logsTopic, "topic", "", "only list logs with this first topic")
+	cmd.Flags().Uint64Var(&logsFromBlock, "from-block", 0, "first block to list logs of")
+	cmd.Flags().Uint64Var(&logsToBlock, "to-block", 0, "last block to list logs of (default: the last indexed one)")
+	cmd.Flags().IntVar(&txsLimit, "limit", 20, "maximum number of logs to list per chain")
+	return cmd
+}
+
+func listLogs(_ *cobra.Command, args []string) error {
+	filter := chainindex.LogFilter{FromBlock: logsFromBlock, ToBlock: logsToBlock, Limit: txsLimit}
+	if logsAddress != "" {
+		if !common.IsHexAddress(logsAddress) {
+			return fmt.Errorf("invalid address %q", logsAddress)
+		}
+		addr := common.HexToAddress(logsAddress)
+		filter.Address = &addr
+	}
+	if logsTopic != "" {
+		topic, err := chainindex.ParseHash(logsTopic)
+		if err != nil {
+			return fmt.Errorf("invalid topic %q", logsTopic)
+		}
+		filter.Topic = &topic
+	}
+	chainNames, err := indexedChains(args)
+	if err != nil {
+		return err
+	}
+	for i, chainName := range chainNames {
+		if i > 0 {
+			ux.Logger.PrintToUser("")
+		}
+		var logs []*chainindex.Log
+		meta, err := withIndex(chainName, func(ix *chainindex.Index) (err error) {
+			logs, err = ix.Logs(filter)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		printIndexHeader(chainName, meta)
+		if len(logs) == 0 {
+			ux.Logger.PrintToUser("No logs indexed")
+			continue
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header("Block", "Tx", "Address", "Topic", "Topics")
+		for _, log := range logs {
+			topic := ""
+			if len(log.Topics) > 0 {
+				topic = log.Topics[0].Hex()
+			}
+			_ = table.Append([]string{
+				strconv.FormatUint(log.BlockNumber, 10),
+				log.TxHash.Hex(),
+				log.Address.Hex(),
+				topic,
+				strconv.Itoa(len(log.Topics)),
+			})
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func accountBalance(chainName string, target NetworkTarget, addr common.Address) (string, error) {
+	rpcURL, err := chainRPCEndpoint(chainName, target)
+	if err != nil {
+		return "", err
+	}
+	client, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	balance, err := client.BalanceAt(ctx, addr, nil)
+	if err != nil {
+		return "", err
+	}
+	return balance.String(), nil
+}
+
+// chainRPCEndpoint returns the RPC endpoint of chainName's deployment to
+// target, on the running node when there is one
+func chainRPCEndpoint(chainName string, target NetworkTarget) (string, error) {
+	network := targetNetwork(target)
+	sc, err := app.LoadSidecar(chainName)
+	if err != nil {
+		return "", err
+	}
+	blockchainID := sc.Networks[network.String()].BlockchainID
+	if blockchainID == ids.Empty {
+		return "", fmt.Errorf("%s is not deployed to %s", chainName, network.String())
+	}
+	return models.GetRPCEndpoint(networkEndpoint(target), blockchainID.String()), nil
+}
+
+// indexedChains returns the chains named in args, or every chain with an
+// index on the target network
+func indexedChains(args []string) ([]string, error) {
+	networkKey := targetNetwork(GetNetworkTarget()).String()
+	if len(args) > 0 {
+		if !chainindex.Exists(chainindex.Path(app.GetBaseDir(), networkKey, args[0])) {
+			return nil, fmt.Errorf("%s is not indexed on %s: use 'lux chain index start %s'", args[0], networkKey, args[0])
+		}
+		return args, nil
+	}
+	chainNames, err := deployedChainNames(networkKey)
+	if err != nil {
+		return nil, err
+	}
+	var indexed []string
+	for _, chainName := range chainNames {
+		if chainindex.Exists(chainindex.Path(app.GetBaseDir(), networkKey, chainName)) {
+			indexed = append(indexed, chainName)
+		}
+	}
+	if len(indexed) == 0 {
+		return nil, fmt.Errorf("no chains indexed on %s: use 'lux chain index start <blockchainName>'", networkKey)
+	}
+	return indexed, nil
+}
+
+// readOnlyAnnotation marks commands that leave the chain untouched, so they
+// run in read-only mode
+func readOnlyAnnotation() map[string]string {
+	return map[string]string{config.CapabilityAnnotation: config.CapabilityNone}
+}
+
+func chainFlagArgs() []string {
+	if indexChain == "" {
+		return nil
+	}
+	return []string{indexChain}
+}
+
+// withIndex runs f on chainName's index, waiting for a running indexer to
+// release it
+func withIndex(chainName string, f func(*chainindex.Index) error) (*chainindex.Meta, error) {
+	path := chainindex.Path(app.GetBaseDir(), targetNetwork(GetNetworkTarget()).String(), chainName)
+	db, err := chainindex.Open(context.Background(), path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	ix := chainindex.New(db)
+	meta, err := ix.Meta()
+	if err != nil {
+		return nil, err
+	}
+	return meta, f(ix)
+}
+
+func printIndexHeader(chainName string, meta *chainindex.Meta) {
+	if meta == nil {
+		ux.Logger.PrintToUser("%s: nothing indexed yet", chainName)
+		return
+	}
+	ux.Logger.PrintToUser("%s: indexed up to block %d at %s", chainName, meta.Height, meta.UpdatedAt.Local().Format("2006-01-02 15:04:05"))
+}
+
+func printTxs(txs []*chainindex.Tx) error {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Block", "Hash", "From", "To", "Value (wei)", "Status")
+	for _, tx := range txs {
+		_ = table.Append([]string{
+			strconv.FormatUint(tx.BlockNumber, 10),
+			tx.Hash.Hex(),
+			tx.From.Hex(),
+			txRecipient(tx),
+			tx.Value,
+			txStatus(tx),
+		})
+	}
+	return table.Render()
+}
+
+func printTx(chainName string, tx *chainindex.Tx) {
+	ux.Logger.PrintToUser("Chain:     %s", chainName)
+	ux.Logger.PrintToUser("Hash:      %s", tx.Hash.Hex())
+	ux.Logger.PrintToUser("Status:    %s", txStatus(tx))
+	ux.Logger.PrintToUser("Block:     %d (%s), index %d", tx.BlockNumber, tx.BlockHash.Hex(), tx.Index)
+	ux.Logger.PrintToUser("Time:      %s", tx.Time.Local().Format("2006-01-02 15:04:05"))
+	ux.Logger.PrintToUser("From:      %s", tx.From.Hex())
+	ux.Logger.PrintToUser("To:        %s", txRecipient(tx))
+	ux.Logger.PrintToUser("Value:     %s wei", tx.Value)
+	ux.Logger.PrintToUser("Nonce:     %d", tx.Nonce)
+	ux.Logger.PrintToUser("Gas:       %d used of %d, at %s wei", tx.GasUsed, tx.Gas, tx.GasPrice)
+	ux.Logger.PrintToUser("Logs:      %d", tx.Logs)
+	if tx.Input != "" {
+		ux.Logger.PrintToUser("Input:     %s", tx.Input)
+	}
+}
+
+func txRecipient(tx *chainindex.Tx) string {
+	switch {
+	case tx.ContractAddress != nil:
+		return "create " + tx.ContractAddress.Hex()
+	case tx.To != nil:
+		return tx.To.Hex()
+	}
+	return ""
+}
+
+func txStatus(tx *chainindex.Tx) string {
+	if tx.Succeeded() {
+		return "success"
+	}
+	return "reverted"
+}
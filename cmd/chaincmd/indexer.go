@@ -0,0 +1,72 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"errors"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/spf13/cobra"
+)
+
+// defaultIndexerAPI is where lux indexer serves its query API
+const defaultIndexerAPI = "127.0.0.1:8095"
+
+// NewIndexerCmd creates the lux indexer command, running the chain indexer
+// with its query API for a deployed chain
+func NewIndexerCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "indexer",
+		Short: "Index a deployed chain and serve its blocks, transactions and logs",
+		Long: `The indexer command follows a deployed EVM chain over RPC, indexes its blocks,
+transactions and event logs into a local database and serves them over a
+JSON query API, for basic explorer and analytics needs before a full
+indexer is deployed.
+
+It runs the same index as 'lux chain index start', which the 'lux chain
+txs', 'tx', 'account' and 'logs' commands query.`,
+		RunE:        cobrautils.CommandSuiteUsage,
+		Annotations: readOnlyAnnotation(),
+	}
+	startCmd := newIndexerStartCmd()
+	addNetworkFlags(startCmd)
+	cmd.AddCommand(startCmd)
+	return cmd
+}
+
+func newIndexerStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start --chain <blockchainName>",
+		Short: "Index a chain and serve the query API until interrupted",
+		Long: `The indexer start command indexes the blocks of a deployed chain from genesis,
+then follows the chain head until interrupted, serving the index on --api:
+
+  GET /status                      what the index covers
+  GET /blocks?limit=               latest blocks
+  GET /blocks/{number}             one block
+  GET /txs?limit=                  latest transactions
+  GET /txs/{hash}                  one transaction
+  GET /accounts/{address}/txs      latest transactions of an account
+  GET /logs?address=&topic=&fromBlock=&toBlock=&limit=
+
+EXAMPLES:
+
+  lux indexer start --chain mychain --devnet
+
+  # Transfer events of a token
+  curl 'http://127.0.0.1:8095/logs?address=0x52C8...4F1a&topic=0xddf2...b3ef'`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if indexChain == "" {
+				return errors.New("--chain is required")
+			}
+			return runIndexer(indexChain)
+		},
+	}
+	cmd.Flags().StringVar(&indexChain, "chain", "", "chain to index")
+	addIndexerFlags(cmd, defaultIndexerAPI)
+	return cmd
+}
@@ -0,0 +1,183 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/config"
+	"github.com/luxfi/cli/pkg/launchcheck"
+	"github.com/luxfi/cli/pkg/node"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/sdk/models"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// lux chain launch-checklist
+func newLaunchChecklistCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "launch-checklist [chainName]",
+		Short: "Check a chain is ready to launch on mainnet or testnet",
+		Long: `The launch-checklist command evaluates whether a chain is ready for a public
+launch:
+
+  non-ewoq allocations         the genesis funds no well-known development key
+  unique chain ID              the EVM chain ID is not used by another network
+                               or local chain
+  control key threshold        more than one control key must sign changes
+  validator count and regions  enough validators, spread over regions, in the
+                               clusters tracking the chain
+  warp enabled                 the genesis activates the Warp precompile
+  monitoring                   the clusters of the validators are monitored
+  fee config                   the genesis fee config is valid and its target
+                               gas reachable
+
+Failed checks block 'lux chain deploy' to mainnet and testnet until they
+are fixed or the deploy is run with --skip-launch-checklist. On testnet the
+control key, validator, warp and monitoring checks only warn. The control
+keys are those of the deploy key, so they are only checked on deploy.
+
+EXAMPLES:
+
+  lux chain launch-checklist mychain
+  lux chain launch-checklist mychain --testnet`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: launchChecklist,
+		// The checklist only reads
+		Annotations: map[string]string{config.CapabilityAnnotation: config.CapabilityNone},
+	}
+	cmd.Flags().BoolVarP(&mainnet, "mainnet", "m", false, "Check a mainnet launch (default)")
+	cmd.Flags().BoolVarP(&testnet, "testnet", "t", false, "Check a testnet launch")
+	return cmd
+}
+
+func launchChecklist(_ *cobra.Command, args []string) error {
+	chainName := args[0]
+	sc, err := app.LoadSidecar(chainName)
+	if err != nil {
+		return fmt.Errorf("chain %s not found: %w", chainName, err)
+	}
+	network := models.Mainnet
+	if testnet {
+		network = models.Testnet
+	}
+	results, err := evaluateLaunchChecklist(chainName, &sc, network, nil)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Launch checklist of %s on %s", chainName, network.String())
+	printLaunchChecklist(results)
+	if failed := launchcheck.Blocking(results); len(failed) > 0 {
+		return fmt.Errorf("%s is not ready for %s: %d checks failed", chainName, network.String(), len(failed))
+	}
+	ux.Logger.PrintToUser("%s is ready for %s", chainName, network.String())
+	return nil
+}
+
+// evaluateLaunchChecklist runs the launch checklist of chainName on network.
+// controlKeys are the keys the deploy makes owners of the chain, all of them
+// signing, or nil when not known yet.
+func evaluateLaunchChecklist(chainName string, sc *models.Sidecar, network models.Network, controlKeys []string) ([]launchcheck.Result, error) {
+	plan := launchcheck.Plan{
+		Chain:       chainName,
+		Mainnet:     network == models.Mainnet,
+		ControlKeys: controlKeys,
+		Threshold:   uint32(len(controlKeys)), //nolint:gosec // G115: a handful of keys
+	}
+	if sc.VM == models.EVM {
+		genesis, err := app.LoadRawGenesis(chainName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load genesis: %w", err)
+		}
+		plan.Genesis = genesis
+		if plan.ChainIDs, err = otherChainIDs(chainName); err != nil {
+			return nil, err
+		}
+	}
+	validators, monitored, err := node.GetChainValidators(app, network, chainName)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range validators {
+		plan.Validators = append(plan.Validators, launchcheck.Validator{NodeID: v.CloudID, Region: v.Region})
+	}
+	plan.Monitored = monitored
+	return launchcheck.Evaluate(plan)
+}
+
+// otherChainIDs returns the EVM chain IDs of the local chains other than
+// chainName
+func otherChainIDs(chainName string) (map[uint64]string, error) {
+	entries, err := os.ReadDir(app.GetChainsDir())
+	if err != nil {
+		return nil, err
+	}
+	chainIDs := map[uint64]string{}
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || entry.Name() == chainName {
+			continue
+		}
+		data, err := os.ReadFile(app.GetGenesisPath(entry.Name())) //nolint:gosec // G304: Reading from app's data directory
+		if err != nil {
+			continue
+		}
+		var genesis struct {
+			Config struct {
+				ChainID *big.Int `json:"chainId"`
+			} `json:"config"`
+		}
+		if json.Unmarshal(data, &genesis) == nil && genesis.Config.ChainID != nil && genesis.Config.ChainID.IsUint64() {
+			chainIDs[genesis.Config.ChainID.Uint64()] = entry.Name()
+		}
+	}
+	return chainIDs, nil
+}
+
+func printLaunchChecklist(results []launchcheck.Result) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Check", "Status", "Detail", "Fix")
+	for _, r := range results {
+		fix := ""
+		if r.Status == launchcheck.Fail || r.Status == launchcheck.Warn {
+			fix = r.Fix
+		}
+		_ = table.Append([]string{r.Name, strings.ToUpper(string(r.Status)), r.Detail, fix})
+	}
+	_ = table.Render()
+}
+
+// enforceLaunchChecklist blocks the deploy of chainName to testnet and
+// mainnet while the launch checklist fails, unless skipLaunchChecklist is
+// set. It returns the failed checks that were skipped.
+func enforceLaunchChecklist(chainName string, sc *models.Sidecar, network models.Network, controlKeys []string) ([]string, error) {
+	if network != models.Mainnet && network != models.Testnet {
+		return nil, nil
+	}
+	results, err := evaluateLaunchChecklist(chainName, sc, network, controlKeys)
+	if err != nil {
+		return nil, err
+	}
+	failed := launchcheck.Blocking(results)
+	if len(failed) == 0 {
+		return nil, nil
+	}
+	ux.Logger.PrintToUser("Launch checklist of %s on %s:", chainName, network.String())
+	printLaunchChecklist(results)
+	names := make([]string, 0, len(failed))
+	for _, r := range failed {
+		names = append(names, r.Name)
+	}
+	if !skipLaunchChecklist {
+		return nil, fmt.Errorf("%s is not ready for %s: %s failed. Fix them, or deploy anyway with --skip-launch-checklist",
+			chainName, network.String(), strings.Join(names, ", "))
+	}
+	ux.Logger.PrintToUser("Deploying despite the failed checks (--skip-launch-checklist)")
+	return names, nil
+}
@@ -0,0 +1,194 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/vm"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	presetFile        string
+	presetFrom        string
+	presetTemplate    string
+	presetChainConfig string
+	presetDescription string
+)
+
+// lux chain preset
+func newPresetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "preset",
+		Short: "Manage the genesis and chain config presets of 'lux chain create --preset'",
+		Long: `The preset command manages the presets 'lux chain create --preset' creates EVM
+chains from: a genesis (fee config, allocations, precompile admins) and the
+chain config.json of the chain, under a name.
+
+The CLI ships high-throughput, low-latency, archival and gaming. Presets
+saved with 'lux chain preset save' are stored in ~/.lux/presets/<name>/ as
+versions v1, v2...: saving a name again, a built-in one included, adds a
+version, and 'lux chain create --preset <name>' uses the newest unless
+given <name>@v<version>.
+
+EXAMPLES:
+
+  # List the presets
+  lux chain preset list
+
+  # Print a preset, to edit and save as your own
+  lux chain preset show gaming > mygame.json
+  lux chain preset save mygame --file mygame.json
+
+  # Save a version of a preset with another chain config
+  lux chain preset save archival --from archival --chain-config archive.json`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.AddCommand(newPresetListCmd())
+	cmd.AddCommand(newPresetShowCmd())
+	cmd.AddCommand(newPresetSaveCmd())
+	return cmd
+}
+
+// lux chain preset list
+func newPresetListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:         "list",
+		Short:       "List the presets and their versions",
+		Args:        cobrautils.ExactArgs(0),
+		RunE:        listPresets,
+		Annotations: readOnlyAnnotation(),
+	}
+}
+
+// lux chain preset show
+func newPresetShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <name>[@v<version>]",
+		Short: "Print a preset as JSON",
+		Long: `The preset show command prints a preset, the newest version unless given
+one, as the JSON 'lux chain preset save --file' takes.`,
+		Args:        cobrautils.ExactArgs(1),
+		RunE:        showPreset,
+		Annotations: readOnlyAnnotation(),
+	}
+}
+
+// lux chain preset save
+func newPresetSaveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "save <name>",
+		Short: "Save a new version of a preset",
+		Long: `The preset save command saves the next version of the preset name in
+~/.lux/presets/<name>/.
+
+The preset is read from --file, JSON as 'lux chain preset show' prints it, or
+built from the preset --from (default: the default genesis without chain
+config), its genesis replaced by the genesis template --template and its
+chain config by the JSON file --chain-config.`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: savePreset,
+	}
+	cmd.Flags().StringVar(&presetFile, "file", "", "read the preset from this JSON file")
+	cmd.Flags().StringVar(&presetFrom, "from", "", "start from this preset, <name>[@v<version>]")
+	cmd.Flags().StringVar(&presetTemplate, "template", "", "use the genesis of this genesis template")
+	cmd.Flags().StringVar(&presetChainConfig, "chain-config", "", "use the chain config of this JSON file")
+	cmd.Flags().StringVar(&presetDescription, "description", "", "describe the preset")
+	return cmd
+}
+
+func listPresets(_ *cobra.Command, _ []string) error {
+	presets, err := vm.ListPresets(app.GetBaseDir())
+	if err != nil {
+		return err
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Preset", "Version", "Source", "Gas Limit", "Block Rate", "Chain Config", "Description")
+	for _, p := range presets {
+		source := "saved"
+		if p.BuiltIn {
+			source = "built-in"
+		}
+		_ = table.Append([]string{
+			p.Name,
+			fmt.Sprintf("v%d", p.Version),
+			source,
+			p.Genesis.FeeConfig.GasLimit.String(),
+			fmt.Sprintf("%ds", p.Genesis.FeeConfig.TargetBlockRate),
+			fmt.Sprintf("%d settings", len(p.ChainConfig)),
+			p.Description,
+		})
+	}
+	_ = table.Render()
+	return nil
+}
+
+func showPreset(_ *cobra.Command, args []string) error {
+	preset, err := vm.LoadPreset(app.GetBaseDir(), args[0])
+	if err != nil {
+		return err
+	}
+	raw, err := json.MarshalIndent(preset, "", "  ")
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("%s", raw)
+	return nil
+}
+
+func savePreset(_ *cobra.Command, args []string) error {
+	var preset vm.Preset
+	switch {
+	case presetFile != "" && (presetFrom != "" || presetTemplate != "" || presetChainConfig != ""):
+		return fmt.Errorf("--file excludes --from, --template and --chain-config")
+	case presetFile != "":
+		raw, err := os.ReadFile(presetFile) //nolint:gosec // G304: User-specified preset file
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(raw, &preset); err != nil {
+			return fmt.Errorf("invalid preset file %s: %w", presetFile, err)
+		}
+	case presetFrom != "":
+		var err error
+		if preset, err = vm.LoadPreset(app.GetBaseDir(), presetFrom); err != nil {
+			return err
+		}
+	default:
+		preset.Genesis = vm.DefaultGenesisTemplate()
+	}
+	if presetTemplate != "" {
+		template, err := vm.LoadGenesisTemplate(app.GetBaseDir(), presetTemplate)
+		if err != nil {
+			return err
+		}
+		preset.Genesis = template
+	}
+	if presetChainConfig != "" {
+		raw, err := os.ReadFile(presetChainConfig) //nolint:gosec // G304: User-specified chain config
+		if err != nil {
+			return err
+		}
+		preset.ChainConfig = nil
+		if err := json.Unmarshal(raw, &preset.ChainConfig); err != nil {
+			return fmt.Errorf("invalid chain config %s: %w", presetChainConfig, err)
+		}
+	}
+	if presetDescription != "" {
+		preset.Description = presetDescription
+	}
+	preset.Name = strings.TrimSpace(args[0])
+	if err := vm.SavePreset(app.GetBaseDir(), &preset); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Saved preset %s to %s", preset.Ref(), vm.PresetPath(app.GetBaseDir(), preset.Name, preset.Version))
+	return nil
+}
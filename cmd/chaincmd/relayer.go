@@ -0,0 +1,350 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/contract"
+	"github.com/luxfi/cli/pkg/relayer"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/vm"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/sdk/evm"
+	"github.com/spf13/cobra"
+)
+
+// relayerFileName is the file of a chain recording its trusted forwarder
+// deployments, by network
+const relayerFileName = "relayer.json"
+
+var (
+	relayerKeyName      string
+	relayerForwarderBin string
+	relayerDomainName   string
+	relayerRelayerKey   string
+	relayerAmount       string
+	relayerMaxGas       uint64
+	relayerTargets      []string
+	relayerPort         int
+	relayerAddress      string
+)
+
+// relayerDeployment is the trusted forwarder of a chain on a network
+type relayerDeployment struct {
+	ChainID   string `json:"chainId"`
+	RPCURL    string `json:"rpcUrl"`
+	Forwarder string `json:"forwarder"`
+	// DomainName is the EIP-712 domain name requests are signed for
+	DomainName string `json:"domainName"`
+	Relayer    string `json:"relayer,omitempty"`
+}
+
+// lux chain relayer
+func newRelayerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "relayer",
+		Short: "Deploy a trusted forwarder and relay gasless transactions",
+		Long: `The relayer command offers gasless transactions on a deployed EVM chain: users
+sign ERC-2771 forward requests, and a relayer account pays for executing them
+through a trusted forwarder. Contracts accepting the forwarder as trusted see
+the signer as the sender.
+
+  deploy   Deploy the trusted forwarder
+  fund     Send tokens to the relayer account paying for the gas
+  serve    Relay forward requests received over HTTP`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	for _, sub := range []*cobra.Command{newRelayerDeployCmd(), newRelayerFundCmd(), newRelayerServeCmd()} {
+		addNetworkFlags(sub)
+		cmd.AddCommand(sub)
+	}
+	return cmd
+}
+
+// lux chain relayer deploy
+func newRelayerDeployCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy [chainName]",
+		Short: "Deploy the trusted forwarder",
+		Long: `The relayer deploy command deploys a trusted forwarder to a deployed EVM chain
+from the build artifact given with --forwarder-bin (a hex file, or a hardhat
+or foundry JSON artifact) of the OpenZeppelin ERC2771Forwarder, which takes
+the EIP-712 domain name of the requests as constructor argument.
+
+The forwarder is recorded in ~/.lux/chains/<chainName>/relayer.json, for
+'relayer fund' and 'relayer serve'. Contracts must be deployed with its
+address as their trusted forwarder.
+
+EXAMPLES:
+
+  lux chain relayer deploy mychain --devnet --forwarder-bin ./ERC2771Forwarder.json`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: deployRelayer,
+	}
+	cmd.Flags().StringVar(&relayerKeyName, "key", "", "key paying for the deploy (from ~/.lux/keys/), the local key on local networks")
+	cmd.Flags().StringVar(&relayerForwarderBin, "forwarder-bin", "", "ERC2771Forwarder build artifact")
+	cmd.Flags().StringVar(&relayerDomainName, "domain-name", "Forwarder", "EIP-712 domain name of the forward requests")
+	return cmd
+}
+
+// lux chain relayer fund
+func newRelayerFundCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fund [chainName]",
+		Short: "Send tokens to the relayer account",
+		Long: `The relayer fund command sends --amount tokens from --key to the relayer
+account, the address of --relayer-key, which pays for the gas of the relayed
+requests, and prints its balance.
+
+EXAMPLES:
+
+  lux chain relayer fund mychain --devnet --relayer-key relayer --amount 100`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: fundRelayer,
+	}
+	cmd.Flags().StringVar(&relayerKeyName, "key", "", "key sending the tokens (from ~/.lux/keys/), the local key on local networks")
+	cmd.Flags().StringVar(&relayerRelayerKey, "relayer-key", "", "key of the relayer account (from ~/.lux/keys/)")
+	cmd.Flags().StringVar(&relayerAmount, "amount", "10", "tokens to send")
+	return cmd
+}
+
+// lux chain relayer serve
+func newRelayerServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve [chainName]",
+		Short: "Relay forward requests received over HTTP",
+		Long: `The relayer serve command relays forward requests to the trusted forwarder of
+the chain in the foreground, paying for their gas with --relayer-key:
+
+  GET  /forwarder  the forwarder, the largest gas of a request and the
+                   allowed targets
+  POST /relay      relay {"from", "to", "gas", "deadline", "data",
+                   "signature"}, signed for the forwarder
+
+Requests are verified with the forwarder before being relayed. Requests over
+--max-gas, carrying a value, or calling a contract not in --allowed-targets,
+when given, are rejected.
+
+EXAMPLES:
+
+  lux chain relayer serve mychain --devnet --relayer-key relayer \
+    --allowed-targets 0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC`,
+		Args:         cobrautils.ExactArgs(1),
+		RunE:         serveRelayer,
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&relayerRelayerKey, "relayer-key", "", "key paying for the relayed requests (from ~/.lux/keys/), the local key on local networks")
+	cmd.Flags().Uint64Var(&relayerMaxGas, "max-gas", relayer.DefaultMaxGas, "largest gas of a relayed request")
+	cmd.Flags().StringSliceVar(&relayerTargets, "allowed-targets", nil, "contracts requests may call (default any)")
+	cmd.Flags().IntVar(&relayerPort, "port", 9810, "port to serve the relayer on")
+	cmd.Flags().StringVar(&relayerAddress, "address", "127.0.0.1", "address to listen on")
+	return cmd
+}
+
+func deployRelayer(_ *cobra.Command, args []string) error {
+	chainName := args[0]
+	target := GetNetworkTarget()
+	network := targetNetwork(target)
+	if relayerForwarderBin == "" {
+		return errors.New("give the ERC2771Forwarder build artifact with --forwarder-bin")
+	}
+	bin, err := contract.ReadBytecode(relayerForwarderBin)
+	if err != nil {
+		return err
+	}
+	deployments, err := loadRelayerDeployments(chainName)
+	if err != nil {
+		return err
+	}
+	rpcURL, err := chainRPCEndpoint(chainName, target)
+	if err != nil {
+		return err
+	}
+	privateKey, err := signingKey(network, relayerKeyName)
+	if err != nil {
+		return err
+	}
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	chainID, err := client.GetChainID()
+	if err != nil {
+		return fmt.Errorf("failed to get the chain ID from %s: %w", rpcURL, err)
+	}
+	forwarder, err := contract.DeployContract(rpcURL, privateKey, []byte(common.Bytes2Hex(bin)), "(string)", relayerDomainName)
+	if err != nil {
+		return fmt.Errorf("failed to deploy the forwarder: %w", err)
+	}
+	ux.Logger.GreenCheckmarkToUser("Trusted forwarder deployed at %s", forwarder.Hex())
+
+	deployment := deployments[network.Name()]
+	deployment.ChainID = chainID.String()
+	deployment.RPCURL = rpcURL
+	deployment.Forwarder = forwarder.Hex()
+	deployment.DomainName = relayerDomainName
+	deployments[network.Name()] = deployment
+	if err := saveRelayerDeployments(chainName, deployments); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Deploy contracts with %s as trusted forwarder, then fund the relayer with 'lux chain relayer fund'", forwarder.Hex())
+	return nil
+}
+
+func fundRelayer(_ *cobra.Command, args []string) error {
+	chainName := args[0]
+	target := GetNetworkTarget()
+	network := targetNetwork(target)
+	if relayerRelayerKey == "" {
+		return errors.New("give the key of the relayer account with --relayer-key")
+	}
+	amount, err := vm.ParseTokenAmount(relayerAmount)
+	if err != nil {
+		return err
+	}
+	if amount.Sign() == 0 {
+		return errors.New("--amount must be positive")
+	}
+	relayerPrivateKey, err := signingKey(network, relayerRelayerKey)
+	if err != nil {
+		return err
+	}
+	relayerAccount, err := evm.PrivateKeyToAddress(relayerPrivateKey)
+	if err != nil {
+		return err
+	}
+	privateKey, err := signingKey(network, relayerKeyName)
+	if err != nil {
+		return err
+	}
+	rpcURL, err := chainRPCEndpoint(chainName, target)
+	if err != nil {
+		return err
+	}
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if _, err := client.FundAddress(privateKey, relayerAccount.Hex(), amount); err != nil {
+		return fmt.Errorf("failed to fund the relayer: %w", err)
+	}
+	balance, err := client.GetAddressBalance(relayerAccount.Hex())
+	if err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Sent %s tokens to the relayer %s, its balance is %s tokens",
+		relayerAmount, relayerAccount.Hex(), formatTokens(balance))
+
+	deployments, err := loadRelayerDeployments(chainName)
+	if err != nil {
+		return err
+	}
+	if deployment, ok := deployments[network.Name()]; ok {
+		deployment.Relayer = relayerAccount.Hex()
+		deployments[network.Name()] = deployment
+		return saveRelayerDeployments(chainName, deployments)
+	}
+	return nil
+}
+
+func serveRelayer(_ *cobra.Command, args []string) error {
+	chainName := args[0]
+	network := targetNetwork(GetNetworkTarget())
+	deployments, err := loadRelayerDeployments(chainName)
+	if err != nil {
+		return err
+	}
+	deployment, ok := deployments[network.Name()]
+	if !ok {
+		return fmt.Errorf("no trusted forwarder deployed for %s on %s, deploy one with 'lux chain relayer deploy'", chainName, network.Name())
+	}
+	privateKey, err := signingKey(network, relayerRelayerKey)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	forwarder, err := relayer.NewEVMForwarder(ctx, deployment.RPCURL, deployment.Forwarder, privateKey)
+	if err != nil {
+		return err
+	}
+	defer forwarder.Close()
+	r, err := relayer.New(forwarder, relayerMaxGas, relayerTargets)
+	if err != nil {
+		return err
+	}
+	addr := net.JoinHostPort(relayerAddress, strconv.Itoa(relayerPort))
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	ux.Logger.PrintToUser("Relaying to the forwarder %s of %s on http://%s", deployment.Forwarder, chainName, addr)
+	ux.Logger.PrintToUser("  paid by %s, at most %d gas per request", forwarder.Relayer(), relayerMaxGas)
+	if targets := r.Targets(); len(targets) > 0 {
+		ux.Logger.PrintToUser("  calling only %s", strings.Join(targets, ", "))
+	}
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("relayer failed: %w", err)
+	case <-ctx.Done():
+		ux.Logger.PrintToUser("Shutting down relayer...")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
+
+func relayerDeploymentsPath(chainName string) string {
+	return filepath.Join(app.GetChainsDir(), chainName, relayerFileName)
+}
+
+func loadRelayerDeployments(chainName string) (map[string]relayerDeployment, error) {
+	deployments := map[string]relayerDeployment{}
+	raw, err := os.ReadFile(relayerDeploymentsPath(chainName)) //nolint:gosec // G304: Reading from app's data directory
+	if errors.Is(err, os.ErrNotExist) {
+		return deployments, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &deployments); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", relayerDeploymentsPath(chainName), err)
+	}
+	return deployments, nil
+}
+
+func saveRelayerDeployments(chainName string, deployments map[string]relayerDeployment) error {
+	raw, err := json.MarshalIndent(deployments, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(relayerDeploymentsPath(chainName), raw, constants.WriteReadReadPerms)
+}
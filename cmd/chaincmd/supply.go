@@ -0,0 +1,420 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/chainindex"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/evm/precompile/contracts/nativeminter"
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/common/math"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/ethclient"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// tokenDecimals are the decimals of the native token of EVM chains
+const tokenDecimals = 18
+
+var (
+	supplyTop          int
+	supplySampleBlocks uint64
+	supplyLocked       []string
+)
+
+// lux chain supply
+func newSupplyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "supply [chainName]",
+		Short: "Report the native token supply and allocation of a chain",
+		Long: `The supply command reports the native token supply of an EVM chain, for launch
+readiness reviews:
+
+  - the genesis allocations and their total
+  - when the chain is deployed to the selected network and reachable, its
+    current supply: the genesis total, plus the coins minted through the
+    NativeMinter precompile, minus the fees burned to the blackhole address
+  - the amounts staked in the Proof of Stake validator manager and locked in
+    the contracts of the genesis and the --locked addresses
+  - the top holders among the accounts of the chain index ('lux chain index
+    start') or, without one, among the genesis allocations and the senders
+    and recipients of the last --sample-blocks blocks
+
+EXAMPLES:
+
+  lux chain supply mychain --devnet
+
+  # Count a vesting contract as locked, and list the top 20 holders
+  lux chain supply mychain --mainnet --locked 0x9011E888251AB053B7bD1cdB598Db4f9DEd94714 --top 20`,
+		Args:        cobrautils.ExactArgs(1),
+		RunE:        showSupply,
+		Annotations: readOnlyAnnotation(),
+	}
+	cmd.Flags().IntVar(&supplyTop, "top", 10, "number of top holders to list")
+	cmd.Flags().Uint64Var(&supplySampleBlocks, "sample-blocks", 1000, "latest blocks to sample holders from when the chain is not indexed")
+	cmd.Flags().StringSliceVar(&supplyLocked, "locked", nil, "addresses, or @labels, whose balance is counted as locked")
+	return cmd
+}
+
+// genesisAllocation is a native token allocation of a chain genesis
+type genesisAllocation struct {
+	Address  common.Address
+	Balance  *big.Int
+	Contract bool
+}
+
+// holder is an account and its native token balance
+type holder struct {
+	Address common.Address
+	Balance *big.Int
+}
+
+// parseGenesisAllocations returns the allocations of an EVM genesis, largest
+// first
+func parseGenesisAllocations(genesisData []byte) ([]genesisAllocation, error) {
+	var genesis struct {
+		Alloc map[string]struct {
+			Balance string `json:"balance"`
+			Code    string `json:"code"`
+		} `json:"alloc"`
+	}
+	if err := json.Unmarshal(genesisData, &genesis); err != nil {
+		return nil, err
+	}
+	allocations := make([]genesisAllocation, 0, len(genesis.Alloc))
+	for addr, alloc := range genesis.Alloc {
+		if !common.IsHexAddress(addr) {
+			return nil, fmt.Errorf("invalid allocation address %q", addr)
+		}
+		balance := new(big.Int)
+		if alloc.Balance != "" {
+			var ok bool
+			if balance, ok = math.ParseBig256(alloc.Balance); !ok {
+				return nil, fmt.Errorf("invalid balance %q of allocation %s", alloc.Balance, addr)
+			}
+		}
+		code := strings.TrimPrefix(alloc.Code, "0x")
+		allocations = append(allocations, genesisAllocation{
+			Address:  common.HexToAddress(addr),
+			Balance:  balance,
+			Contract: code != "",
+		})
+	}
+	sort.Slice(allocations, func(i, j int) bool {
+		if c := allocations[i].Balance.Cmp(allocations[j].Balance); c != 0 {
+			return c > 0
+		}
+		return allocations[i].Address.Cmp(allocations[j].Address) < 0
+	})
+	return allocations, nil
+}
+
+// topHolders returns the n largest non zero balances, largest first
+func topHolders(balances map[common.Address]*big.Int, n int) []holder {
+	holders := make([]holder, 0, len(balances))
+	for addr, balance := range balances {
+		if balance.Sign() > 0 {
+			holders = append(holders, holder{Address: addr, Balance: balance})
+		}
+	}
+	sort.Slice(holders, func(i, j int) bool {
+		if c := holders[i].Balance.Cmp(holders[j].Balance); c != 0 {
+			return c > 0
+		}
+		return holders[i].Address.Cmp(holders[j].Address) < 0
+	})
+	if n >= 0 && len(holders) > n {
+		holders = holders[:n]
+	}
+	return holders
+}
+
+// formatTokens formats a wei amount in whole tokens
+func formatTokens(wei *big.Int) string {
+	if wei == nil {
+		return "-"
+	}
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(tokenDecimals), nil)
+	whole, frac := new(big.Int).QuoRem(new(big.Int).Abs(wei), unit, new(big.Int))
+	s := whole.String()
+	if frac.Sign() != 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%0*s", tokenDecimals, frac.String()), "0")
+	}
+	if wei.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// formatShare formats part as a percentage of total
+func formatShare(part, total *big.Int) string {
+	if part == nil || total == nil || total.Sign() <= 0 {
+		return "-"
+	}
+	share, _ := new(big.Rat).SetFrac(new(big.Int).Mul(part, big.NewInt(100)), total).Float64()
+	return fmt.Sprintf("%.2f%%", share)
+}
+
+func showSupply(_ *cobra.Command, args []string) error {
+	chainName := args[0]
+	if supplyTop < 0 {
+		return fmt.Errorf("invalid --top %d", supplyTop)
+	}
+	target := GetNetworkTarget()
+	network := targetNetwork(target)
+	lockedAddrs, err := app.ResolveAddresses(supplyLocked)
+	if err != nil {
+		return err
+	}
+	locked := make([]common.Address, 0, len(lockedAddrs))
+	for _, addr := range lockedAddrs {
+		if !common.IsHexAddress(addr) {
+			return fmt.Errorf("invalid --locked address %q", addr)
+		}
+		locked = append(locked, common.HexToAddress(addr))
+	}
+	sc, err := app.LoadSidecar(chainName)
+	if err != nil {
+		return err
+	}
+	symbol := sc.TokenSymbol
+	if symbol == "" {
+		symbol = "tokens"
+	}
+	genesisData, err := os.ReadFile(app.GetGenesisPath(chainName)) //nolint:gosec // G304: Reading from app's data directory
+	if err != nil {
+		return err
+	}
+	allocations, err := parseGenesisAllocations(genesisData)
+	if err != nil {
+		return fmt.Errorf("invalid genesis of %s: %w", chainName, err)
+	}
+	genesisSupply := new(big.Int)
+	for _, alloc := range allocations {
+		genesisSupply.Add(genesisSupply, alloc.Balance)
+	}
+
+	ux.Logger.PrintToUser("Genesis allocations of %s: %d accounts, %s %s", chainName, len(allocations), formatTokens(genesisSupply), symbol)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Address", "Balance ("+symbol+")", "Share", "Contract")
+	for i, alloc := range allocations {
+		if i == supplyTop {
+			break
+		}
+		contract := ""
+		if alloc.Contract {
+			contract = "yes"
+		}
+		_ = table.Append([]string{alloc.Address.Hex(), formatTokens(alloc.Balance), formatShare(alloc.Balance, genesisSupply), contract})
+	}
+	if err := table.Render(); err != nil {
+		return err
+	}
+	if len(allocations) > supplyTop {
+		ux.Logger.PrintToUser("... and %d more allocations", len(allocations)-supplyTop)
+	}
+
+	rpcURL, err := chainRPCEndpoint(chainName, target)
+	if err != nil {
+		ux.Logger.PrintToUser("No live supply: %v", err)
+		return nil
+	}
+	client, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", rpcURL, err)
+	}
+	defer client.Close()
+	balanceOf := func(addr common.Address) (*big.Int, error) {
+		ctx, cancel := utils.GetAPIContext()
+		defer cancel()
+		return client.BalanceAt(ctx, addr, nil)
+	}
+
+	burned, err := balanceOf(constants.BlackholeAddr)
+	if err != nil {
+		ux.Logger.PrintToUser("No live supply: %s is not reachable on %s: %v", chainName, network.String(), err)
+		return nil
+	}
+	minted, err := mintedSupply(client)
+	if err != nil {
+		return fmt.Errorf("failed to read the NativeMinter mints: %w", err)
+	}
+	current := new(big.Int).Sub(new(big.Int).Add(genesisSupply, minted), burned)
+
+	staked := new(big.Int)
+	if manager := sc.Networks[network.String()].ValidatorManagerAddress; sc.PoS && common.IsHexAddress(manager) {
+		if staked, err = balanceOf(common.HexToAddress(manager)); err != nil {
+			return err
+		}
+	}
+	for _, alloc := range allocations {
+		if alloc.Contract {
+			locked = append(locked, alloc.Address)
+		}
+	}
+	lockedSupply := new(big.Int)
+	seen := map[common.Address]bool{constants.BlackholeAddr: true}
+	for _, addr := range locked {
+		if seen[addr] {
+			continue
+		}
+		seen[addr] = true
+		balance, err := balanceOf(addr)
+		if err != nil {
+			return err
+		}
+		lockedSupply.Add(lockedSupply, balance)
+	}
+	circulating := new(big.Int).Sub(new(big.Int).Sub(current, staked), lockedSupply)
+
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Supply of %s on %s", chainName, network.String())
+	table = tablewriter.NewWriter(os.Stdout)
+	table.Header("", "Amount ("+symbol+")", "Share")
+	for _, row := range []struct {
+		name   string
+		amount *big.Int
+	}{
+		{"Genesis", genesisSupply},
+		{"Minted", minted},
+		{"Burned", burned},
+		{"Current supply", current},
+		{"Staked", staked},
+		{"Locked", lockedSupply},
+		{"Circulating", circulating},
+	} {
+		_ = table.Append([]string{row.name, formatTokens(row.amount), formatShare(row.amount, current)})
+	}
+	if err := table.Render(); err != nil {
+		return err
+	}
+
+	candidates, source, err := holderCandidates(chainName, client, allocations)
+	if err != nil {
+		return err
+	}
+	balances := make(map[common.Address]*big.Int, len(candidates))
+	for _, addr := range candidates {
+		if addr == constants.BlackholeAddr {
+			continue
+		}
+		if balances[addr], err = balanceOf(addr); err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Top holders of %s among %d accounts %s", chainName, len(balances), source)
+	table = tablewriter.NewWriter(os.Stdout)
+	table.Header("Address", "Balance ("+symbol+")", "Share")
+	for _, h := range topHolders(balances, supplyTop) {
+		_ = table.Append([]string{h.Address.Hex(), formatTokens(h.Balance), formatShare(h.Balance, current)})
+	}
+	return table.Render()
+}
+
+// mintedSupply sums the NativeCoinMinted events of the NativeMinter
+// precompile
+func mintedSupply(client *ethclient.Client) (*big.Int, error) {
+	ctx, cancel := utils.GetAPILargeContext()
+	defer cancel()
+	logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: big.NewInt(0),
+		Addresses: []common.Address{nativeminter.ContractAddress},
+		Topics:    [][]common.Hash{{nativeminter.NativeMinterABI.Events["NativeCoinMinted"].ID}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	minted := new(big.Int)
+	for _, log := range logs {
+		amount, err := nativeminter.UnpackNativeCoinMintedEventData(log.Data)
+		if err != nil {
+			return nil, err
+		}
+		minted.Add(minted, amount)
+	}
+	return minted, nil
+}
+
+// holderCandidates returns the accounts to look for top holders among: the
+// accounts of the chain index when there is one, otherwise the genesis
+// allocations and the accounts of the latest --sample-blocks blocks
+func holderCandidates(chainName string, client *ethclient.Client, allocations []genesisAllocation) ([]common.Address, string, error) {
+	networkKey := targetNetwork(GetNetworkTarget()).String()
+	if chainindex.Exists(chainindex.Path(app.GetBaseDir(), networkKey, chainName)) {
+		var accounts []common.Address
+		meta, err := withIndex(chainName, func(ix *chainindex.Index) (err error) {
+			accounts, err = ix.Accounts()
+			return err
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		if meta != nil {
+			for _, alloc := range allocations {
+				accounts = append(accounts, alloc.Address)
+			}
+			return uniqueAddresses(accounts), fmt.Sprintf("of the index up to block %d", meta.Height), nil
+		}
+	}
+
+	accounts := make([]common.Address, 0, len(allocations))
+	for _, alloc := range allocations {
+		accounts = append(accounts, alloc.Address)
+	}
+	ctx := context.Background()
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	from := uint64(0)
+	if head >= supplySampleBlocks {
+		from = head - supplySampleBlocks + 1
+	}
+	for number := from; number <= head && supplySampleBlocks > 0; number++ {
+		block, err := client.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return nil, "", err
+		}
+		for _, tx := range block.Transactions() {
+			if sender, err := types.Sender(signer, tx); err == nil {
+				accounts = append(accounts, sender)
+			}
+			if tx.To() != nil {
+				accounts = append(accounts, *tx.To())
+			}
+		}
+	}
+	return uniqueAddresses(accounts), fmt.Sprintf("of the genesis and blocks %d to %d (not indexed)", from, head), nil
+}
+
+func uniqueAddresses(addrs []common.Address) []common.Address {
+	seen := make(map[common.Address]bool, len(addrs))
+	unique := addrs[:0]
+	for _, addr := range addrs {
+		if !seen[addr] {
+			seen[addr] = true
+			unique = append(unique, addr)
+		}
+	}
+	return unique
+}
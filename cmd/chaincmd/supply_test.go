@@ -0,0 +1,58 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGenesisAllocations(t *testing.T) {
+	require := require.New(t)
+	allocations, err := parseGenesisAllocations([]byte(`{"alloc": {
+		"8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC": {"balance": "0x52B7D2DCC80CD2E4000000"},
+		"0x0100000000000000000000000000000000000005": {"balance": "1000", "code": "0x6080"},
+		"0x0100000000000000000000000000000000000006": {"code": "0x"}
+	}}`))
+	require.NoError(err)
+	require.Len(allocations, 3)
+	require.Equal(common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"), allocations[0].Address)
+	require.Equal("100000000", formatTokens(allocations[0].Balance))
+	require.False(allocations[0].Contract)
+	require.Equal(big.NewInt(1000), allocations[1].Balance)
+	require.True(allocations[1].Contract)
+	require.Zero(allocations[2].Balance.Sign())
+	require.False(allocations[2].Contract)
+
+	_, err = parseGenesisAllocations([]byte(`{"alloc": {"0x01": {"balance": "1"}}}`))
+	require.ErrorContains(err, "invalid allocation address")
+	_, err = parseGenesisAllocations([]byte(`{"alloc": {"0x0100000000000000000000000000000000000005": {"balance": "lots"}}}`))
+	require.ErrorContains(err, "invalid balance")
+}
+
+func TestTopHolders(t *testing.T) {
+	require := require.New(t)
+	a := common.HexToAddress("0xa")
+	b := common.HexToAddress("0xb")
+	c := common.HexToAddress("0xc")
+	balances := map[common.Address]*big.Int{
+		a: big.NewInt(5),
+		b: big.NewInt(0),
+		c: big.NewInt(7),
+	}
+	require.Equal([]holder{{c, big.NewInt(7)}, {a, big.NewInt(5)}}, topHolders(balances, 10))
+	require.Equal([]holder{{c, big.NewInt(7)}}, topHolders(balances, 1))
+}
+
+func TestFormatTokens(t *testing.T) {
+	require := require.New(t)
+	require.Equal("0", formatTokens(big.NewInt(0)))
+	require.Equal("0.000000000000000001", formatTokens(big.NewInt(1)))
+	require.Equal("-1.5", formatTokens(big.NewInt(-1_500_000_000_000_000_000)))
+	require.Equal("25.00%", formatShare(big.NewInt(1), big.NewInt(4)))
+	require.Equal("-", formatShare(big.NewInt(1), big.NewInt(0)))
+}
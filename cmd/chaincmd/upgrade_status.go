@@ -0,0 +1,257 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaincmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/upgradebundle"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/evm/params/extras"
+	"github.com/luxfi/geth/ethclient"
+	"github.com/luxfi/ids"
+	sdkinfo "github.com/luxfi/sdk/info"
+	"github.com/luxfi/sdk/models"
+	"github.com/luxfi/upgrade"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var upgradeStatusParallelism int
+
+func newUpgradeStatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade-status [chainName]",
+		Short: "Show which upgrade activations are live on deployed chains",
+		Long: `The upgrade-status command shows, for each chain deployed to the selected
+network, which network upgrades and chain upgrades (precompile and state
+upgrades from the chain upgrade file) are live.
+
+Network upgrade times are read from a running node, so they reflect any
+bundle applied with 'lux network start --upgrade-bundle'. An activation is
+live once the latest block of the chain is at or past its time.
+
+EXAMPLES:
+
+  # All chains deployed to the local devnet
+  lux chain upgrade-status --devnet
+
+  # A single chain
+  lux chain upgrade-status mychain --devnet`,
+		Args: cobrautils.MaximumNArgs(1),
+		RunE: upgradeStatus,
+	}
+	cmd.Flags().IntVar(&upgradeStatusParallelism, "parallel", utils.DefaultParallelism, "how many chain heads to fetch at once")
+	return cmd
+}
+
+func upgradeStatus(_ *cobra.Command, args []string) error {
+	target := GetNetworkTarget()
+	networkKey := targetNetwork(target).String()
+
+	endpoint := networkEndpoint(target)
+
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	upgrades, err := sdkinfo.NewClient(endpoint).Upgrades(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get network upgrades from %s, is the network running? %w", endpoint, err)
+	}
+
+	chainNames := args
+	if len(chainNames) == 0 {
+		if chainNames, err = deployedChainNames(networkKey); err != nil {
+			return err
+		}
+		if len(chainNames) == 0 {
+			ux.Logger.PrintToUser("No chains deployed to %s", networkKey)
+			return nil
+		}
+	}
+
+	// the heads are fetched concurrently, the chains printed in order
+	heads := make([]chainHead, len(chainNames))
+	err = utils.ForEachChain(chainNames, upgradeStatusParallelism,
+		func(name string) string { return name },
+		func(i int, name string) error {
+			sc, err := app.LoadSidecar(name)
+			if err != nil {
+				return err
+			}
+			heads[i].blockchainID = sc.Networks[networkKey].BlockchainID
+			if heads[i].blockchainID == ids.Empty {
+				return fmt.Errorf("not deployed to %s", networkKey)
+			}
+			heads[i].time, heads[i].err = chainHeadTime(endpoint, heads[i].blockchainID)
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+	for i, name := range chainNames {
+		if i > 0 {
+			ux.Logger.PrintToUser("")
+		}
+		if err := printChainUpgradeStatus(name, heads[i], *upgrades); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chainHead is the latest block time of a deployed chain, or why it could
+// not be fetched
+type chainHead struct {
+	blockchainID ids.ID
+	time         time.Time
+	err          error
+}
+
+// targetNetwork maps a chain command network target to its network
+func targetNetwork(target NetworkTarget) models.Network {
+	switch target {
+	case NetworkMainnet:
+		return models.Mainnet
+	case NetworkTestnet:
+		return models.Testnet
+	case NetworkDevnet:
+		return models.Devnet
+	default:
+		return models.Local
+	}
+}
+
+// networkEndpoint returns the API endpoint of target, the running node's
+// when there is one
+func networkEndpoint(target NetworkTarget) string {
+	if state, err := app.LoadNetworkStateForType(string(target)); err == nil && state != nil && state.Running && state.APIEndpoint != "" {
+		return state.APIEndpoint
+	}
+	return targetNetwork(target).Endpoint()
+}
+
+// deployedChainNames returns the chains with a deployment to networkKey
+func deployedChainNames(networkKey string) ([]string, error) {
+	entries, err := os.ReadDir(app.GetChainsDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || !app.SidecarExists(entry.Name()) {
+			continue
+		}
+		sc, err := app.LoadSidecar(entry.Name())
+		if err != nil {
+			continue
+		}
+		if sc.Networks[networkKey].BlockchainID != ids.Empty {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func printChainUpgradeStatus(chainName string, head chainHead, upgrades upgrade.Config) error {
+	// Activations are evaluated against the chain head, not the wall clock
+	headTime := head.time
+	if head.err != nil {
+		ux.Logger.PrintToUser("Chain %s (%s): head not reachable, using current time: %v", chainName, head.blockchainID, head.err)
+		headTime = time.Now()
+	} else {
+		ux.Logger.PrintToUser("Chain %s (%s), head at %s", chainName, head.blockchainID, headTime.Local().Format(constants.TimeParseLayout))
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Upgrade", "Activation", "Status")
+	var fromGenesis []string
+	for _, a := range upgradebundle.Activations(upgrades) {
+		if !a.Time.After(upgrade.InitiallyActiveTime) {
+			fromGenesis = append(fromGenesis, a.Name)
+			continue
+		}
+		_ = table.Append([]string{a.Name, formatActivation(a), a.Status(headTime)})
+	}
+
+	chainUpgrades, err := loadChainUpgrades(chainName)
+	if err != nil {
+		return err
+	}
+	for _, u := range chainUpgrades.PrecompileUpgrades {
+		name := u.Key()
+		if u.IsDisabled() {
+			name += " (disable)"
+		}
+		a := timestampActivation(name, u.Timestamp())
+		_ = table.Append([]string{a.Name, formatActivation(a), a.Status(headTime)})
+	}
+	for i, u := range chainUpgrades.StateUpgrades {
+		a := timestampActivation(fmt.Sprintf("stateUpgrade[%d]", i), u.BlockTimestamp)
+		_ = table.Append([]string{a.Name, formatActivation(a), a.Status(headTime)})
+	}
+	_ = table.Render()
+	if len(fromGenesis) > 0 {
+		ux.Logger.PrintToUser("Live from genesis: %s", strings.Join(fromGenesis, ", "))
+	}
+	return nil
+}
+
+func chainHeadTime(endpoint string, blockchainID ids.ID) (time.Time, error) {
+	client, err := ethclient.Dial(models.GetRPCEndpoint(endpoint, blockchainID.String()))
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer client.Close()
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(header.Time), 0), nil //nolint:gosec // G115: block timestamps fit in int64
+}
+
+// loadChainUpgrades reads the chain upgrade file, if any
+func loadChainUpgrades(chainName string) (extras.UpgradeConfig, error) {
+	var upgrades extras.UpgradeConfig
+	path := app.GetUpgradeBytesFilePath(chainName)
+	if !utils.FileExists(path) {
+		return upgrades, nil
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: Reading from app's data directory
+	if err != nil {
+		return upgrades, err
+	}
+	if err := json.Unmarshal(data, &upgrades); err != nil {
+		return upgrades, fmt.Errorf("invalid upgrade file %s: %w", path, err)
+	}
+	return upgrades, nil
+}
+
+func timestampActivation(name string, timestamp *uint64) upgradebundle.Activation {
+	if timestamp == nil {
+		return upgradebundle.Activation{Name: name, Time: upgrade.UnscheduledActivationTime}
+	}
+	return upgradebundle.Activation{Name: name, Time: time.Unix(int64(*timestamp), 0)} //nolint:gosec // G115: timestamps fit in int64
+}
+
+func formatActivation(a upgradebundle.Activation) string {
+	if !a.Scheduled() {
+		return "-"
+	}
+	return a.Time.Local().Format(constants.TimeParseLayout)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/luxfi/cli/pkg/binutils"
 	"github.com/luxfi/cli/pkg/chain"
 	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/history"
 	"github.com/luxfi/cli/pkg/localnet"
 	"github.com/luxfi/cli/pkg/prompts"
 	"github.com/luxfi/cli/pkg/utils"
@@ -55,7 +56,10 @@ var (
 	luxdChainConfigFlag       = "luxd-chain-config-dir"
 	luxdChainConfigDir        string
 
-	printManual bool
+	printManual   bool
+	clusterName   string
+	activateAt    string
+	healthTimeout time.Duration
 )
 
 // lux blockchain upgrade apply
@@ -79,6 +83,13 @@ In non-interactive mode (CI/scripts), use --force to skip confirmation prompts f
 timestamps in the past. The --luxd-chain-config-dir defaults to ~/.luxd/chains and
 will be used without confirmation prompts.
 
+With --cluster, the upgrade file is installed over ssh on every node of the
+cluster, and the nodes are restarted one at a time: each must be healthy and
+report the upgrades in its chain config before the next one restarts.
+--activate-at schedules the upgrades not applied yet to activate at a given
+time, leaving the nodes time to restart before activation. After restarting,
+every node is checked over RPC for loaded, pending and active upgrades.
+
 Examples:
   # Interactive mode
   lux blockchain upgrade apply mychain --local
@@ -87,7 +98,10 @@ Examples:
   lux blockchain upgrade apply mychain --testnet --luxd-chain-config-dir /path/to/chains --force
 
   # Print manual instructions (non-interactive friendly)
-  lux blockchain upgrade apply mychain --mainnet --print`,
+  lux blockchain upgrade apply mychain --mainnet --print
+
+  # Activate in 30 minutes on every node of a cluster, restarting them one at a time
+  lux blockchain upgrade apply mychain --cluster mycluster --activate-at +30m`,
 		RunE: applyCmd,
 		Args: cobrautils.ExactArgs(1),
 	}
@@ -99,6 +113,9 @@ Examples:
 	cmd.Flags().BoolVar(&printManual, "print", false, "Print manual config instructions (for public networks only, non-interactive friendly)")
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "Skip confirmation prompts (e.g., for timestamps in the past)")
 	cmd.Flags().StringVar(&luxdChainConfigDir, luxdChainConfigFlag, os.ExpandEnv(luxdChainConfigDirDefault), "Luxd chain config directory (e.g., ~/.luxd/chains)")
+	cmd.Flags().StringVar(&clusterName, "cluster", "", "Apply upgrade to the nodes of this cluster over ssh, restarting them one at a time")
+	cmd.Flags().StringVar(&activateAt, "activate-at", "", "Schedule the upgrades not applied yet to activate at this time: RFC3339, unix seconds or +<duration> from now")
+	cmd.Flags().DurationVar(&healthTimeout, "health-timeout", 5*time.Minute, "Max time to wait for a restarted node to be healthy with the upgrades loaded")
 
 	return cmd
 }
@@ -115,6 +132,15 @@ func applyCmd(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("unable to load sidecar: %w", err)
 	}
 
+	if activateAt != "" {
+		if err := scheduleUpgradeFile(blockchainName, activateAt); err != nil {
+			return err
+		}
+	}
+	if clusterName != "" {
+		return applyClusterUpgrade(blockchainName, clusterName, &sc)
+	}
+
 	networkToUpgrade, err := selectNetworkToUpgrade(sc, []string{})
 	if err != nil {
 		return err
@@ -238,8 +264,16 @@ func applyLocalNetworkUpgrade(blockchainName, networkKey string, sc *models.Side
 		if err := localnet.PrintEndpoints(app, ux.Logger.PrintToUser, blockchainName); err != nil {
 			return err
 		}
+		var uris []string
+		for _, nodeInfo := range clusterInfo.NodeInfos {
+			uris = append(uris, nodeInfo.GetUri())
+		}
+		if err := verifyLocalUpgrade(uris, blockchainID, strNetUpgrades); err != nil {
+			return err
+		}
 
 		writeLockFile(precmpUpgrades, blockchainName)
+		recordUpgradeApply(blockchainName, networkKey, blockchainID, map[string]string{"snapshot": snapName})
 		return nil
 	}
 
@@ -330,9 +364,24 @@ func applyPublicNetworkUpgrade(blockchainName, networkKey string, sc *models.Sid
 		return fmt.Errorf("failed to install the upgrades path at the provided destination: %w", err)
 	}
 	ux.Logger.PrintToUser("Successfully installed upgrade file")
+	recordUpgradeApply(blockchainName, networkKey, sc.Networks[networkKey].BlockchainID, map[string]string{"installedAt": destPath})
 	return nil
 }
 
+// recordUpgradeApply adds an applied upgrade file to the history
+func recordUpgradeApply(blockchainName, networkKey string, blockchainID ids.ID, params map[string]string) {
+	if upgradeBytes, err := app.ReadUpgradeFile(blockchainName); err == nil {
+		params["upgradeSHA256"] = history.Digest(upgradeBytes)
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "chain upgrade apply",
+		Network:   networkKey,
+		Chain:     blockchainName,
+		Params:    params,
+		Result:    map[string]string{"blockchainID": blockchainID.String()},
+	})
+}
+
 func validateUpgrade(blockchainName, networkKey string, sc *models.Sidecar, skipPrompting bool) ([]extras.PrecompileUpgrade, string, error) {
 	// if there's no entry in the Sidecar, we assume there hasn't been a deploy yet
 	if sc.NetworkDataIsEmpty() {
@@ -0,0 +1,195 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package upgradecmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path"
+	"sort"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/luxfi/cli/pkg/ansible"
+	"github.com/luxfi/cli/pkg/node"
+	"github.com/luxfi/cli/pkg/ssh"
+	"github.com/luxfi/cli/pkg/upgradebundle"
+	"github.com/luxfi/cli/pkg/upgraderollout"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+	"github.com/olekukonko/tablewriter"
+)
+
+const rolloutPollInterval = 5 * time.Second
+
+// scheduleUpgradeFile sets the activation time of the upgrades of
+// blockchainName that were not applied yet, as locked by the lock file
+func scheduleUpgradeFile(blockchainName, activateAt string) error {
+	if activateAt == upgradebundle.Never {
+		return errors.New("--activate-at never would leave the upgrades unscheduled")
+	}
+	at, err := upgradebundle.ParseActivation(activateAt, time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid --activate-at: %w", err)
+	}
+	if !at.After(time.Now()) {
+		return fmt.Errorf("--activate-at %s is in the past", at.Format(time.RFC3339))
+	}
+	file, err := app.ReadUpgradeFile(blockchainName)
+	if err != nil {
+		return err
+	}
+	locked := 0
+	lockFile, err := app.ReadLockUpgradeFile(blockchainName)
+	switch {
+	case err == nil:
+		lockedUpgrades, err := upgraderollout.ParseUpgrades(lockFile)
+		if err != nil {
+			return err
+		}
+		locked = len(lockedUpgrades)
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+	scheduled, err := upgraderollout.Schedule(file, locked, at)
+	if err != nil {
+		return err
+	}
+	if err := app.WriteUpgradeFile(blockchainName, scheduled); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Scheduled the new upgrades of %s to activate at %s", blockchainName, at.Local().Format(constants.TimeParseLayout))
+	return nil
+}
+
+// applyClusterUpgrade installs the upgrade file on the nodes of a cluster
+// over ssh and restarts them one at a time
+func applyClusterUpgrade(blockchainName, clusterName string, sc *models.Sidecar) error {
+	if err := node.CheckCluster(app, clusterName); err != nil {
+		return err
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	networkStr, _ := clusterConfig["network"].(string)
+	networkKey := models.NetworkFromString(networkStr).Name()
+	precmpUpgrades, strNetUpgrades, err := validateUpgrade(blockchainName, networkKey, sc, force)
+	if err != nil {
+		return err
+	}
+	blockchainID := sc.Networks[networkKey].BlockchainID
+
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	defer node.DisconnectHosts(hosts)
+	nodes := make([]upgraderollout.Node, len(hosts))
+	for i, host := range hosts {
+		nodes[i] = &clusterNode{host: host, blockchainID: blockchainID}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ux.Logger.PrintToUser("Rolling the upgrade of %s out to %d node(s) of cluster %s", blockchainName, len(nodes), clusterName)
+	statuses, err := upgraderollout.Rollout(ctx, nodes, []byte(strNetUpgrades), upgraderollout.Options{
+		HealthTimeout: healthTimeout,
+		PollInterval:  rolloutPollInterval,
+		Progress: func(name, step string) {
+			ux.Logger.PrintToUser("  %s: %s", name, step)
+		},
+	})
+	if err != nil {
+		ux.Logger.PrintToUser("Nodes restarted so far run the new upgrade file, fix the failing node and apply again")
+		return err
+	}
+	printRolloutStatus(statuses)
+	writeLockFile(precmpUpgrades, blockchainName)
+	recordUpgradeApply(blockchainName, networkKey, blockchainID, map[string]string{"cluster": clusterName})
+	return nil
+}
+
+// verifyLocalUpgrade checks that every local node loaded the upgrade file
+func verifyLocalUpgrade(uris []string, blockchainID ids.ID, upgradeFile string) error {
+	upgrades, err := upgraderollout.ParseUpgrades([]byte(upgradeFile))
+	if err != nil {
+		return err
+	}
+	statuses := map[string]upgraderollout.NodeStatus{}
+	var failed []string
+	for _, uri := range uris {
+		ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+		s, err := upgraderollout.Check(ctx, models.GetRPCEndpoint(uri, blockchainID.String()), upgrades)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to verify the upgrade on %s: %w", uri, err)
+		}
+		if !s.Loaded() {
+			failed = append(failed, uri)
+		}
+		statuses[uri] = s
+	}
+	printRolloutStatus(statuses)
+	if len(failed) > 0 {
+		return fmt.Errorf("upgrades not loaded by %v", failed)
+	}
+	return nil
+}
+
+func printRolloutStatus(statuses map[string]upgraderollout.NodeStatus) {
+	names := make([]string, 0, len(statuses))
+	for name := range statuses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	ux.Logger.PrintToUser("")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Node", "Active", "Pending", "Missing")
+	for _, name := range names {
+		s := statuses[name]
+		_ = table.Append([]string{name, strconv.Itoa(len(s.Active)), strconv.Itoa(len(s.Pending)), strconv.Itoa(len(s.Missing))})
+	}
+	_ = table.Render()
+}
+
+// clusterNode is a cluster host reached over ssh
+type clusterNode struct {
+	host         *models.Host
+	blockchainID ids.ID
+}
+
+func (n *clusterNode) Name() string {
+	return n.host.GetCloudID()
+}
+
+func (n *clusterNode) Install(file []byte) error {
+	chainDir := path.Join(constants.CloudNodeConfigPath, "chains", n.blockchainID.String())
+	if err := n.host.MkdirAll(chainDir, constants.SSHDirOpsTimeout); err != nil {
+		return err
+	}
+	return n.host.UploadBytes(file, path.Join(chainDir, constants.UpgradeFileName), constants.SSHFileOpsTimeout)
+}
+
+func (n *clusterNode) Restart(context.Context) error {
+	return ssh.RunSSHRestartNode(n.host)
+}
+
+func (n *clusterNode) Healthy(context.Context) (bool, error) {
+	unhealthy, err := node.GetUnhealthyNodes([]*models.Host{n.host})
+	if err != nil {
+		return false, err
+	}
+	return len(unhealthy) == 0, nil
+}
+
+func (n *clusterNode) RPCURL() string {
+	return models.GetRPCEndpoint(node.GetLuxdEndpoint(n.host.IP), n.blockchainID.String())
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package configcmd
+
+import (
+	"errors"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/config"
+	"github.com/luxfi/cli/pkg/receipt"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/spf13/cobra"
+)
+
+var (
+	auditKMSURL string
+	auditKMSKey string
+)
+
+// lux config audit command
+func newAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit [enable | disable]",
+		Short: "Issue signed receipts of mainnet operations",
+		Long: `In audit mode, every mainnet operation recorded in the history issues a
+receipt of its command, parameters, resulting IDs, signing keys and times,
+signed with a key of this machine and stored in ~/.lux/receipts.
+
+With --kms-url and --kms-key, receipts are also countersigned by a KMS key,
+authenticating with the API key in $` + receipt.KMSAPIKeyEnvVar + `.
+
+See 'lux receipt' to list, verify and export receipts.
+
+EXAMPLES:
+
+  lux config audit enable
+  lux config audit enable --kms-url https://kms.example.com --kms-key receipts
+  lux config audit disable`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: handleAuditSettings,
+		// "audit" reads as a read-only verb, but this changes the configuration
+		Annotations: map[string]string{config.CapabilityAnnotation: string(config.CapabilityConfig)},
+	}
+	cmd.Flags().StringVar(&auditKMSURL, "kms-url", "", "KMS to countersign receipts with")
+	cmd.Flags().StringVar(&auditKMSKey, "kms-key", "", "KMS key to countersign receipts with")
+	return cmd
+}
+
+func handleAuditSettings(_ *cobra.Command, args []string) error {
+	switch args[0] {
+	case constants.Enable:
+		if (auditKMSURL == "") != (auditKMSKey == "") {
+			return errors.New("--kms-url and --kms-key must be given together")
+		}
+		if err := app.Conf.SetConfigValue(receipt.ConfigKey, true); err != nil {
+			return err
+		}
+		if auditKMSURL != "" {
+			if err := app.Conf.SetConfigValue(receipt.KMSURLConfigKey, auditKMSURL); err != nil {
+				return err
+			}
+			if err := app.Conf.SetConfigValue(receipt.KMSKeyConfigKey, auditKMSKey); err != nil {
+				return err
+			}
+		}
+		ux.Logger.PrintToUser("Audit mode enabled: mainnet operations issue receipts in %s", app.GetReceiptsDir())
+		if kms := app.GetReceiptCountersigner(); kms != nil {
+			ux.Logger.PrintToUser("Receipts are countersigned by key %s of the KMS at %s", kms.KeyID, kms.URL)
+		}
+	case constants.Disable:
+		if err := app.Conf.SetConfigValue(receipt.ConfigKey, false); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Audit mode disabled")
+	default:
+		return errors.New("Invalid audit argument '" + args[0] + "'")
+	}
+	return nil
+}
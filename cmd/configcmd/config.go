@@ -27,8 +27,20 @@ func NewCmd(injectedApp *application.Lux) *cobra.Command {
 	app = injectedApp
 	// set user metrics collection preferences cmd
 	cmd.AddCommand(newMetricsCmd())
-	// validate luxd configuration files
+	// validate CLI, state and luxd configuration files
 	cmd.AddCommand(newLintCmd())
+	// print the JSON Schemas of config files
+	cmd.AddCommand(newSchemaCmd())
+	// manage named configuration profiles
+	cmd.AddCommand(newProfileCmd())
+	// block state-changing commands
+	cmd.AddCommand(newReadOnlyCmd())
+	// pin dependency versions to the cached version matrix
+	cmd.AddCommand(newOfflineCmd())
+	// shell commands and webhooks run around lifecycle commands
+	cmd.AddCommand(newHooksCmd())
+	// issue signed receipts of mainnet operations
+	cmd.AddCommand(newAuditCmd())
 
 	return cmd
 }
@@ -0,0 +1,94 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package configcmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/hooks"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// lux config hooks command
+func newHooksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hooks",
+		Short: "Show the hooks run around lifecycle commands",
+		Long: `Hooks are shell commands and webhooks run before and after lifecycle
+commands, to send notifications, invalidate caches or validate changes
+without forking the commands. They are set in the "hooks" section of
+~/.lux/cli.json, by event:
+
+  pre-<op>, post-<op>   <op>: ` + strings.Join(hooks.Operations, ", ") + `
+
+A hook is either {"run": "<shell command>"} or {"url": "<webhook>"}, with an
+optional "timeout" (default 5m) and, for webhooks, "headers" in which $VAR
+references are expanded from the environment.
+
+Hooks of an event run in order. A failing pre hook aborts the command; post
+hooks run whether the command succeeded or not, and fail a command that
+succeeded when they fail.
+
+The context (event, operation, network, chain, snapshot, args, time and,
+for post hooks, status and error) is passed to shell commands as JSON on
+stdin and as LUX_HOOK_EVENT, LUX_HOOK_OPERATION, LUX_HOOK_NETWORK,
+LUX_HOOK_CHAIN, LUX_HOOK_SNAPSHOT, LUX_HOOK_STATUS, LUX_HOOK_ERROR and
+LUX_HOOK_CONTEXT (the JSON), and POSTed to webhooks.
+
+EXAMPLE:
+
+  {
+    "hooks": {
+      "pre-deploy": [{"run": "./scripts/validate-genesis.sh", "timeout": "30s"}],
+      "post-deploy": [
+        {"url": "https://hooks.slack.com/services/...", "headers": {"Authorization": "Bearer $SLACK_TOKEN"}}
+      ],
+      "post-network-start": [{"run": "curl -s -X POST http://cache.internal/purge"}]
+    }
+  }`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the configured hooks",
+		Args:  cobrautils.ExactArgs(0),
+		RunE:  listHooks,
+	})
+	return cmd
+}
+
+func listHooks(_ *cobra.Command, _ []string) error {
+	configPath := app.GetHooksConfigPath()
+	c, err := hooks.Load(configPath)
+	if err != nil {
+		return err
+	}
+	if len(c) == 0 {
+		ux.Logger.PrintToUser("No hooks configured in %s, see 'lux config hooks --help'", configPath)
+		return nil
+	}
+	events := make([]string, 0, len(c))
+	for event := range c {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+	for _, event := range events {
+		ux.Logger.PrintToUser("%s:", event)
+		for i, h := range c[event] {
+			target := "run " + h.Run
+			if h.URL != "" {
+				target = "POST " + h.URL
+			}
+			timeout := hooks.DefaultTimeout.String()
+			if h.Timeout != "" {
+				timeout = h.Timeout
+			}
+			ux.Logger.PrintToUser("  %d. %s (timeout %s)", i+1, target, timeout)
+		}
+	}
+	return nil
+}
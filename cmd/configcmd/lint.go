@@ -4,15 +4,25 @@
 package configcmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/config"
+	"github.com/luxfi/cli/pkg/configschema"
+	"github.com/luxfi/cli/pkg/statefile"
+	"github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/config/spec"
+	"github.com/luxfi/constants"
 	"github.com/spf13/cobra"
 )
 
@@ -22,59 +32,250 @@ type LintResult struct {
 	Warnings []string
 }
 
-func newLintCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "lint <config-file.json>",
-		Short: "Validate luxd configuration file",
-		Long: `Validate a luxd configuration file for errors.
+// Kinds of files linted besides state files
+const (
+	lintKindCLI  = "cli"
+	lintKindNode = "node"
+)
+
+// nodeConfigKey is the section of the CLI config file holding luxd flags
+const nodeConfigKey = "node-config"
+
+// lintStateKinds are the kinds of state files linted
+var lintStateKinds = []statefile.Kind{
+	statefile.Sidecar,
+	statefile.NetworkState,
+	statefile.ClusterConfig,
+	statefile.ClustersConfig,
+}
 
-Reports:
+var lintKind string
+
+// lintFile is a file to lint, and its kind
+type lintFile struct {
+	path string
+	kind string
+}
+
+func newLintCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint [config-file.json]",
+		Short: "Validate CLI, state and luxd configuration files",
+		Long: `Validate configuration files for errors, reporting the path of each field
+at fault with a suggested fix.
+
+Without a file, lints the CLI config file (~/.lux/cli.json), its luxd
+node-config section included, and every state file of the CLI: sidecars,
+network states and cluster configs. With a file, lints it as the kind given
+by --kind, or told by its name, a luxd configuration file otherwise.
+
+CLI and state files are checked against their JSON Schemas, printed by
+'lux config schema'. luxd configuration files are checked against the
+authoritative flag spec from github.com/luxfi/config/spec, which is
+generated from the node's source of truth, reporting:
   - Unknown configuration keys (with typo suggestions)
   - Invalid value types (e.g., "abc" for a duration)
   - Deprecated keys (with replacement hints)
 
-Uses the authoritative flag spec from github.com/luxfi/config/spec,
-which is generated from the node's source of truth.
-
 Example:
-  lux config lint myconfig.json`,
-		Args: cobra.ExactArgs(1),
+  lux config lint
+  lux config lint myconfig.json
+  lux config lint ~/.lux/chains/mychain/sidecar.json`,
+		Args: cobrautils.MaximumNArgs(1),
 		RunE: runLint,
+		// lint must run on a broken config file, profiles included
+		Annotations: map[string]string{config.SkipProfileAnnotation: "true"},
 	}
+	cmd.Flags().StringVar(&lintKind, "kind", "", fmt.Sprintf("kind of the file: %s (default: told by its name)", strings.Join(lintKinds(), ", ")))
+	return cmd
 }
 
 func runLint(_ *cobra.Command, args []string) error {
-	configPath := args[0]
-
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return fmt.Errorf("failed to read config file: %w", err)
+	var files []lintFile
+	switch {
+	case len(args) == 1:
+		kind := lintKind
+		if kind == "" {
+			kind = detectKind(args[0])
+		} else if !slices.Contains(lintKinds(), kind) {
+			return fmt.Errorf("invalid --kind %q, expected one of %s", kind, strings.Join(lintKinds(), ", "))
+		}
+		files = []lintFile{{path: args[0], kind: kind}}
+	case lintKind != "":
+		return fmt.Errorf("--kind needs a file to lint")
+	default:
+		var err error
+		if files, err = defaultLintFiles(); err != nil {
+			return err
+		}
 	}
 
-	var config map[string]interface{}
-	if err := json.Unmarshal(data, &config); err != nil {
-		return fmt.Errorf("failed to parse JSON: %w", err)
+	errs, warnings := 0, 0
+	for _, f := range files {
+		result, err := lintPath(f)
+		if err != nil {
+			return err
+		}
+		errs += len(result.Errors)
+		warnings += len(result.Warnings)
+		if len(files) > 1 && len(result.Errors)+len(result.Warnings) > 0 {
+			fmt.Printf("%s (%s):\n", f.path, f.kind)
+		}
+
+		// Print results
+		for _, e := range result.Errors {
+			fmt.Printf("ERROR: %s\n", e)
+		}
+		for _, w := range result.Warnings {
+			fmt.Printf("WARN: %s\n", w)
+		}
 	}
 
-	result := lintConfig(config)
+	// Summary
+	if len(files) > 1 {
+		fmt.Printf("%d files, ", len(files))
+	}
+	fmt.Printf("%d errors, %d warnings\n", errs, warnings)
 
-	// Print results
-	for _, e := range result.Errors {
-		fmt.Printf("ERROR: %s\n", e)
+	if errs > 0 {
+		return fmt.Errorf("found %d errors in configuration files", errs)
 	}
-	for _, w := range result.Warnings {
-		fmt.Printf("WARN: %s\n", w)
+	return nil
+}
+
+// lintKinds returns the kinds of files lint checks
+func lintKinds() []string {
+	kinds := []string{lintKindCLI}
+	for _, kind := range lintStateKinds {
+		kinds = append(kinds, kindName(kind))
 	}
+	return append(kinds, lintKindNode)
+}
 
-	// Summary
-	fmt.Printf("%d errors, %d warnings\n", len(result.Errors), len(result.Warnings))
+// kindName returns the name of a kind of state file for --kind, as
+// network-state
+func kindName(kind statefile.Kind) string {
+	return strings.ReplaceAll(kind.Name, " ", "-")
+}
 
-	if len(result.Errors) > 0 {
-		os.Exit(1)
+// kindSchema returns the JSON Schema of the files of kind, nil for luxd
+// configuration files, checked against the flag spec
+func kindSchema(kind string) *configschema.Schema {
+	if kind == lintKindCLI {
+		return configschema.CLI()
+	}
+	for _, k := range lintStateKinds {
+		if kindName(k) == kind {
+			return application.StateFileSchema(k)
+		}
 	}
 	return nil
 }
 
+// detectKind returns the kind of the file at path told by its name, a
+// luxd configuration file when unknown
+func detectKind(path string) string {
+	base := filepath.Base(path)
+	switch {
+	case base == "cli.json":
+		return lintKindCLI
+	case base == constants.SidecarFileName:
+		return kindName(statefile.Sidecar)
+	case base == constants.LocalNetworkMetaFile || strings.HasSuffix(base, "_network_state.json"):
+		return kindName(statefile.NetworkState)
+	case base == constants.ClustersConfigFileName:
+		return kindName(statefile.ClustersConfig)
+	case base == "config.json" && filepath.Base(filepath.Dir(filepath.Dir(path))) == "clusters":
+		return kindName(statefile.ClusterConfig)
+	}
+	return lintKindNode
+}
+
+// defaultLintFiles returns the CLI config file, when it exists, and the
+// state files of the CLI
+func defaultLintFiles() ([]lintFile, error) {
+	var files []lintFile
+	if path := profileConfigPath(); utils.FileExists(path) {
+		files = append(files, lintFile{path: path, kind: lintKindCLI})
+	}
+	stateFiles, _, err := app.StateFiles()
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range stateFiles {
+		files = append(files, lintFile{path: f.Path, kind: kindName(f.Kind)})
+	}
+	return files, nil
+}
+
+// lintPath lints the file f
+func lintPath(f lintFile) (*LintResult, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	switch f.kind {
+	case lintKindNode:
+		return lintNodeConfig(data), nil
+	case lintKindCLI:
+		return lintCLIConfig(data), nil
+	}
+	return issuesResult(kindSchema(f.kind).Validate(data)), nil
+}
+
+// lintNodeConfig lints a luxd configuration file
+func lintNodeConfig(data []byte) *LintResult {
+	object := &configschema.Schema{Types: []string{configschema.TypeObject}}
+	if issues := object.Validate(data); len(issues) > 0 {
+		return issuesResult(issues)
+	}
+	var nodeConfig map[string]interface{}
+	if err := json.Unmarshal(data, &nodeConfig); err != nil {
+		return &LintResult{Errors: []string{err.Error()}}
+	}
+	return lintConfig(nodeConfig)
+}
+
+// lintCLIConfig lints the CLI config file, and its luxd node-config section
+func lintCLIConfig(data []byte) *LintResult {
+	if len(bytes.TrimSpace(data)) == 0 {
+		// an empty config file is no config
+		return &LintResult{}
+	}
+	result := issuesResult(configschema.CLI().Validate(data))
+	var cliConfig map[string]interface{}
+	if err := json.Unmarshal(data, &cliConfig); err != nil {
+		return result
+	}
+	for key, value := range cliConfig {
+		nodeConfig, ok := value.(map[string]interface{})
+		if !ok || !strings.EqualFold(key, nodeConfigKey) {
+			continue
+		}
+		nodeResult := lintConfig(nodeConfig)
+		for _, e := range nodeResult.Errors {
+			result.Errors = append(result.Errors, key+": "+e)
+		}
+		for _, w := range nodeResult.Warnings {
+			result.Warnings = append(result.Warnings, key+": "+w)
+		}
+	}
+	return result
+}
+
+// issuesResult returns the schema issues of a file as a lint result
+func issuesResult(issues []configschema.Issue) *LintResult {
+	result := &LintResult{}
+	for _, issue := range issues {
+		if issue.Warning {
+			result.Warnings = append(result.Warnings, issue.String())
+		} else {
+			result.Errors = append(result.Errors, issue.String())
+		}
+	}
+	return result
+}
+
 func lintConfig(config map[string]interface{}) *LintResult {
 	result := &LintResult{}
 	spec := spec.MustSpec()
@@ -273,3 +273,44 @@ func TestGetFlagType(t *testing.T) {
 		t.Error("not-a-key should not have a spec")
 	}
 }
+
+func TestDetectKind(t *testing.T) {
+	tests := map[string]string{
+		"/home/u/.lux/cli.json":                    "cli",
+		"/home/u/.lux/chains/mychain/sidecar.json": "sidecar",
+		"/home/u/.lux/local_network_meta.json":     "network-state",
+		"/home/u/.lux/testnet_network_state.json":  "network-state",
+		"/home/u/.lux/clusters.json":               "clusters-config",
+		"clusters/devnet/config.json":              "cluster-config",
+		"/home/u/.lux/chains/mychain/config.json":  "node",
+		"myconfig.json":                            "node",
+	}
+	for path, expected := range tests {
+		if kind := detectKind(path); kind != expected {
+			t.Errorf("detectKind(%q) = %q, expected %q", path, kind, expected)
+		}
+	}
+}
+
+func TestLintCLIConfig(t *testing.T) {
+	result := lintCLIConfig([]byte(`{
+  "metrics-enabled": "yes",
+  "offlne": true,
+  "node-config": {"log-levl": "info"}
+}`))
+	expectedErrors := []string{
+		`metrics-enabled: expected boolean, got string "yes"`,
+		`node-config: unknown key "log-levl" (did you mean "log-level"?)`,
+	}
+	if strings.Join(result.Errors, "\n") != strings.Join(expectedErrors, "\n") {
+		t.Errorf("Unexpected errors: %v", result.Errors)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0] != `offlne: unknown field, ignored (did you mean "offline"?)` {
+		t.Errorf("Unexpected warnings: %v", result.Warnings)
+	}
+
+	result = lintNodeConfig([]byte(`{"log-level": "info",}`))
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "remove the trailing comma") {
+		t.Errorf("Unexpected errors: %v", result.Errors)
+	}
+}
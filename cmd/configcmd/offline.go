@@ -0,0 +1,47 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package configcmd
+
+import (
+	"errors"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/dependencies"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/spf13/cobra"
+)
+
+// lux config offline command
+func newOfflineCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "offline [enable | disable]",
+		Short: "Pin luxd and EVM versions to the cached version matrix",
+		Long: `Put the CLI in offline mode: the luxd and EVM versions are resolved from the
+version matrix cached by 'lux update sync', however old, and never fetched
+from GitHub, nor is the CLI checked for updates. Commands needing a document
+the cache lacks fail, naming it. LUX_OFFLINE=true does the same for one
+command.`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: handleOfflineSettings,
+	}
+}
+
+func handleOfflineSettings(_ *cobra.Command, args []string) error {
+	switch args[0] {
+	case constants.Enable:
+		if err := app.Conf.SetConfigValue(dependencies.OfflineConfigKey, true); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Offline mode enabled: versions are pinned to %s", dependencies.VersionMatrixPath(app.GetBaseDir()))
+	case constants.Disable:
+		if err := app.Conf.SetConfigValue(dependencies.OfflineConfigKey, false); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Offline mode disabled")
+	default:
+		return errors.New("Invalid offline argument '" + args[0] + "'")
+	}
+	return nil
+}
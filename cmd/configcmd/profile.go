@@ -0,0 +1,280 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package configcmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/config"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	profileNetwork  string
+	profileKey      string
+	profileEndpoint string
+	profileRPC      string
+	profileSet      []string
+	profileUse      bool
+	profileReadOnly bool
+	profileCaps     []string
+)
+
+// lux config profile command
+func newProfileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Manage named configuration profiles",
+		Long: `Profiles scope the default network, key name, endpoints and other flag
+defaults, so switching between mainnet, testnet and several devnets does not
+require repeating flags.
+
+The profile is selected by, in order: the --profile flag, the LUX_PROFILE
+environment variable, and the active profile set with 'lux config profile use'.
+Flags given on the command line always take precedence over the profile.
+
+A profile can also restrict what its users may change: --read-only blocks
+every state-changing command, and --capabilities allows only the listed
+kinds of them (deploy, destroy, validators, transfer, keys, config).
+Read-only commands such as status, list and describe are always allowed.
+
+EXAMPLES:
+
+  lux config profile create devnet-a --network devnet --key ops --endpoint http://10.0.0.5:9650
+  lux config profile create main --network mainnet --key treasury --set ledger=true
+  lux config profile create dashboard --network mainnet --read-only
+  lux config profile create junior --network devnet --capabilities deploy,transfer
+  lux config profile use devnet-a
+  lux config profile list
+  lux --profile main network status
+  LUX_PROFILE=main lux validator list`,
+		RunE: cobrautils.CommandSuiteUsage,
+		// Profile flags such as --key describe the profile, they must not
+		// be filled from the active one
+		Annotations: map[string]string{config.SkipProfileAnnotation: "true"},
+	}
+	cmd.AddCommand(newProfileCreateCmd())
+	cmd.AddCommand(newProfileUseCmd())
+	cmd.AddCommand(newProfileListCmd())
+	cmd.AddCommand(newProfileDeleteCmd())
+	return cmd
+}
+
+func newProfileCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create or replace a profile",
+		Args:  cobrautils.ExactArgs(1),
+		RunE:  createProfile,
+	}
+	cmd.Flags().StringVar(&profileNetwork, "network", "", "default network (mainnet, testnet, devnet, local)")
+	cmd.Flags().StringVar(&profileKey, "key", "", "default key name")
+	cmd.Flags().StringVar(&profileEndpoint, "endpoint", "", "default node API endpoint")
+	cmd.Flags().StringVar(&profileRPC, "rpc", "", "default chain RPC endpoint")
+	cmd.Flags().StringArrayVar(&profileSet, "set", nil, "default for any other flag, as name=value (repeatable)")
+	cmd.Flags().BoolVar(&profileUse, "use", false, "make the profile active")
+	cmd.Flags().BoolVar(&profileReadOnly, "read-only", false, "block every state-changing command")
+	cmd.Flags().StringSliceVar(&profileCaps, "capabilities", nil, "only allow these kinds of state-changing commands: deploy, destroy, validators, transfer, keys, config")
+	return cmd
+}
+
+func newProfileUseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "use <name>",
+		Short: "Set the active profile",
+		Args:  cobrautils.ExactArgs(1),
+		RunE:  useProfile,
+	}
+}
+
+func newProfileListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List profiles",
+		Args:  cobrautils.ExactArgs(0),
+		RunE:  listProfiles,
+	}
+}
+
+func newProfileDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a profile",
+		Args:  cobrautils.ExactArgs(1),
+		RunE:  deleteProfile,
+	}
+}
+
+// profileConfigPath is the config file holding the profiles
+func profileConfigPath() string {
+	if path := app.Conf.GetConfigPath(); path != "" {
+		return path
+	}
+	return app.GetConfigPath()
+}
+
+func createProfile(_ *cobra.Command, args []string) error {
+	name := args[0]
+	if err := config.ValidateProfileName(name); err != nil {
+		return err
+	}
+	profile := config.Profile{
+		Network:  profileNetwork,
+		Key:      profileKey,
+		Endpoint: profileEndpoint,
+		RPC:      profileRPC,
+		ReadOnly: profileReadOnly,
+	}
+	if len(profileCaps) > 0 {
+		caps, err := config.ParseCapabilities(profileCaps)
+		if err != nil {
+			return err
+		}
+		profile.Capabilities = caps
+	}
+	for _, kv := range profileSet {
+		flagName, value, ok := strings.Cut(kv, "=")
+		flagName = strings.TrimPrefix(strings.TrimSpace(flagName), "--")
+		if !ok || flagName == "" {
+			return fmt.Errorf("invalid --set %q: expected name=value", kv)
+		}
+		if profile.Flags == nil {
+			profile.Flags = map[string]string{}
+		}
+		profile.Flags[flagName] = value
+	}
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+
+	path := profileConfigPath()
+	profiles, err := config.LoadProfiles(path)
+	if err != nil {
+		return err
+	}
+	_, exists := profiles.Profiles[name]
+	profiles.Profiles[name] = profile
+	if profileUse {
+		profiles.Active = name
+	}
+	if err := config.SaveProfiles(path, profiles); err != nil {
+		return err
+	}
+
+	if exists {
+		ux.Logger.PrintToUser("Profile %s updated", name)
+	} else {
+		ux.Logger.PrintToUser("Profile %s created", name)
+	}
+	if profileUse {
+		ux.Logger.PrintToUser("Active profile is now %s", name)
+	}
+	return nil
+}
+
+func useProfile(_ *cobra.Command, args []string) error {
+	name := args[0]
+	path := profileConfigPath()
+	profiles, err := config.LoadProfiles(path)
+	if err != nil {
+		return err
+	}
+	if _, ok := profiles.Profiles[name]; !ok {
+		return fmt.Errorf("profile %q not found, see 'lux config profile list'", name)
+	}
+	profiles.Active = name
+	if err := config.SaveProfiles(path, profiles); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Active profile is now %s", name)
+	if env := os.Getenv(config.ProfileEnvVar); env != "" && env != name {
+		ux.Logger.PrintToUser("Note: %s=%s overrides the active profile in this shell", config.ProfileEnvVar, env)
+	}
+	return nil
+}
+
+func listProfiles(_ *cobra.Command, _ []string) error {
+	profiles, err := config.LoadProfiles(profileConfigPath())
+	if err != nil {
+		return err
+	}
+	if len(profiles.Profiles) == 0 {
+		ux.Logger.PrintToUser("No profiles. Create one with 'lux config profile create <name>'")
+		return nil
+	}
+	// Show which profile applies to this shell, not only the stored one
+	current, _ := profiles.Resolve("")
+	for _, name := range profiles.Names() {
+		marker := " "
+		if name == current {
+			marker = "*"
+		}
+		ux.Logger.PrintToUser("%s %s%s", marker, name, describeProfile(profiles.Profiles[name]))
+	}
+	return nil
+}
+
+func describeProfile(p config.Profile) string {
+	var parts []string
+	if p.Network != "" {
+		parts = append(parts, "network="+p.Network)
+	}
+	if p.Key != "" {
+		parts = append(parts, "key="+p.Key)
+	}
+	if p.Endpoint != "" {
+		parts = append(parts, "endpoint="+p.Endpoint)
+	}
+	if p.RPC != "" {
+		parts = append(parts, "rpc="+p.RPC)
+	}
+	flagNames := make([]string, 0, len(p.Flags))
+	for name := range p.Flags {
+		flagNames = append(flagNames, name)
+	}
+	sort.Strings(flagNames)
+	for _, name := range flagNames {
+		parts = append(parts, name+"="+p.Flags[name])
+	}
+	if p.ReadOnly {
+		parts = append(parts, "read-only")
+	}
+	if len(p.Capabilities) > 0 {
+		caps := make([]string, len(p.Capabilities))
+		for i, c := range p.Capabilities {
+			caps[i] = string(c)
+		}
+		parts = append(parts, "capabilities="+strings.Join(caps, "+"))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "  (" + strings.Join(parts, ", ") + ")"
+}
+
+func deleteProfile(_ *cobra.Command, args []string) error {
+	name := args[0]
+	path := profileConfigPath()
+	profiles, err := config.LoadProfiles(path)
+	if err != nil {
+		return err
+	}
+	if _, ok := profiles.Profiles[name]; !ok {
+		return fmt.Errorf("profile %q not found", name)
+	}
+	delete(profiles.Profiles, name)
+	if profiles.Active == name {
+		profiles.Active = ""
+	}
+	if err := config.SaveProfiles(path, profiles); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Profile %s deleted", name)
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package configcmd
+
+import (
+	"errors"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/config"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/spf13/cobra"
+)
+
+// lux config read-only command
+func newReadOnlyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "read-only [enable | disable]",
+		Short: "Block state-changing commands",
+		Long: `Put the CLI in read-only mode: status, list, describe and other commands
+that only read keep working, while deploys, cleans, validator changes,
+transfers, key changes and configuration changes are blocked. Use it on
+shared operator machines and for dashboards using the CLI credentials.
+
+Profiles can restrict commands further, see 'lux config profile'.`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: handleReadOnlySettings,
+		// Lifting read-only mode must stay possible while it's on
+		Annotations: map[string]string{config.CapabilityAnnotation: config.CapabilityNone},
+	}
+}
+
+func handleReadOnlySettings(_ *cobra.Command, args []string) error {
+	switch args[0] {
+	case constants.Enable:
+		if err := app.Conf.SetConfigValue(config.ReadOnlyKey, true); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Read-only mode enabled: state-changing commands are blocked")
+	case constants.Disable:
+		if err := app.Conf.SetConfigValue(config.ReadOnlyKey, false); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Read-only mode disabled")
+	default:
+		return errors.New("Invalid read-only argument '" + args[0] + "'")
+	}
+	return nil
+}
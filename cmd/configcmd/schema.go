@@ -0,0 +1,54 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package configcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// lux config schema command
+func newSchemaCmd() *cobra.Command {
+	kinds := schemaKinds()
+	return &cobra.Command{
+		Use:   "schema <kind>",
+		Short: "Print the JSON Schema of a config file",
+		Long: fmt.Sprintf(`Print the JSON Schema of a kind of config file checked by 'lux config lint',
+for editors and other tools to validate and complete the file.
+
+Kinds: %s
+
+Example:
+  lux config schema cli > ~/.lux/cli.schema.json`, strings.Join(kinds, ", ")),
+		Args:        cobrautils.ExactArgs(1),
+		ValidArgs:   kinds,
+		RunE:        runSchema,
+		Annotations: map[string]string{config.CapabilityAnnotation: config.CapabilityNone},
+	}
+}
+
+func runSchema(_ *cobra.Command, args []string) error {
+	s := kindSchema(args[0])
+	if s == nil {
+		return fmt.Errorf("no schema for %q, expected one of %s", args[0], strings.Join(schemaKinds(), ", "))
+	}
+	out, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+// schemaKinds are the kinds of files with a JSON Schema, all but luxd
+// configuration files
+func schemaKinds() []string {
+	return slices.DeleteFunc(lintKinds(), func(kind string) bool { return kind == lintKindNode })
+}
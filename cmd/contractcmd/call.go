@@ -0,0 +1,174 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package contractcmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/luxfi/cli/cmd/flags"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/config"
+	"github.com/luxfi/cli/pkg/contract"
+	"github.com/luxfi/cli/pkg/networkoptions"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/evm/accounts/abi"
+	"github.com/luxfi/geth/common"
+	luxlog "github.com/luxfi/log"
+	"github.com/luxfi/sdk/models"
+	"github.com/spf13/cobra"
+)
+
+type ContractCallFlags struct {
+	Network         networkoptions.NetworkFlags
+	PrivateKeyFlags contract.PrivateKeyFlags
+	chainFlags      contract.ChainSpec
+	abiFile         string
+	rpcEndpoint     string
+	value           string
+}
+
+var callFlags ContractCallFlags
+
+// methodCall is a contract method call resolved from the command line
+type methodCall struct {
+	network     models.Network
+	rpcEndpoint string
+	address     common.Address
+	abi         *abi.ABI
+	method      abi.Method
+	args        []interface{}
+}
+
+// lux contract call
+func newCallCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "call <address> <method> [args...]",
+		Short: "Call a read-only contract method and print its return values",
+		Long: `Call a method of a deployed contract without sending a transaction, and
+print its decoded return values.
+
+The method is given by name, with --abi the contract ABI (a JSON ABI, or a
+hardhat or foundry artifact), or as its signature, with the return types
+after "->" or "returns". Arguments follow the method: integers in decimal
+or 0x hex, bytes in hex, and arrays and tuples as JSON arrays.
+
+Examples:
+  lux contract call 0x5FbDB2315678afecb367f032d93F642f64180aa3 \
+    "balanceOf(address)->(uint256)" 0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC --c-chain --local
+
+  lux contract call 0x5FbDB2315678afecb367f032d93F642f64180aa3 totalSupply \
+    --abi ./out/Token.sol/Token.json --blockchain mychain --testnet`,
+		RunE: callContract,
+		Args: cobrautils.MinimumNArgs(2),
+		// Calls only read
+		Annotations: map[string]string{config.CapabilityAnnotation: config.CapabilityNone},
+	}
+	addMethodCallFlags(cmd, &callFlags, "call the contract on %s")
+	return cmd
+}
+
+// addMethodCallFlags adds the flags locating the contract of a call
+func addMethodCallFlags(cmd *cobra.Command, callFlags *ContractCallFlags, goalFmt string) {
+	networkoptions.AddNetworkFlagsToCmd(cmd, &callFlags.Network, false, networkoptions.DefaultSupportedNetworkOptions)
+	// enabling blockchain names, C-Chain and blockchain IDs
+	callFlags.chainFlags.SetEnabled(true, true, false, false, true)
+	callFlags.chainFlags.AddToCmd(cmd, goalFmt)
+	flags.AddRPCFlagToCmd(cmd, app, &callFlags.rpcEndpoint)
+	cmd.Flags().StringVar(&callFlags.abiFile, "abi", "", "contract ABI file, or hardhat or foundry artifact")
+}
+
+func callContract(_ *cobra.Command, args []string) error {
+	call, err := resolveMethodCall(&callFlags, args)
+	if err != nil {
+		return err
+	}
+	out, err := contract.CallMethod(call.rpcEndpoint, call.address, call.abi, call.method.Name, call.args...)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", call.method.Sig, err)
+	}
+	if len(out) == 0 {
+		ux.Logger.PrintToUser("%s returned no values", call.method.Sig)
+		return nil
+	}
+	for i, output := range call.method.Outputs {
+		if i >= len(out) {
+			break
+		}
+		label := output.Type.String()
+		if output.Name != "" {
+			label = output.Name + " " + label
+		}
+		ux.Logger.PrintToUser("%s: %s", label, contract.FormatValue(out[i]))
+	}
+	return nil
+}
+
+// resolveMethodCall resolves the contract, method and arguments given by
+// args, and the RPC endpoint of the chain of the contract
+func resolveMethodCall(callFlags *ContractCallFlags, args []string) (*methodCall, error) {
+	call := &methodCall{}
+	if !common.IsHexAddress(args[0]) {
+		return nil, fmt.Errorf("invalid contract address %q", args[0])
+	}
+	call.address = common.HexToAddress(args[0])
+	var err error
+	if callFlags.abiFile != "" {
+		if call.abi, err = contract.LoadABI(callFlags.abiFile); err != nil {
+			return nil, err
+		}
+	}
+	if call.abi, call.method, err = contract.GetMethod(call.abi, args[1]); err != nil {
+		return nil, err
+	}
+	if call.args, err = contract.ParseArgs(call.method.Inputs, args[2:]); err != nil {
+		return nil, fmt.Errorf("%s: %w", call.method.Sig, err)
+	}
+
+	call.network, err = networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		callFlags.Network,
+		true,
+		false,
+		networkoptions.DefaultSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return nil, err
+	}
+	call.rpcEndpoint = callFlags.rpcEndpoint
+	if call.rpcEndpoint != "" {
+		return call, nil
+	}
+	if err := callFlags.chainFlags.CheckMutuallyExclusiveFields(); err != nil {
+		return nil, err
+	}
+	if !callFlags.chainFlags.Defined() {
+		if cancel, err := contract.PromptChain(
+			app,
+			call.network,
+			"Which chain is the contract on?",
+			"",
+			&callFlags.chainFlags,
+		); cancel || err != nil {
+			if err == nil {
+				err = errors.New("no chain selected")
+			}
+			return nil, err
+		}
+	}
+	call.rpcEndpoint, _, err = contract.GetBlockchainEndpoints(
+		app,
+		call.network,
+		callFlags.chainFlags,
+		true,
+		false,
+	)
+	if err != nil {
+		return nil, err
+	}
+	ux.Logger.PrintToUser(luxlog.Yellow.Wrap("RPC Endpoint: %s"), call.rpcEndpoint)
+	return call, nil
+}
@@ -17,7 +17,9 @@ func NewCmd(injectedApp *application.Lux) *cobra.Command {
 		Use:   "contract",
 		Short: "Manage smart contracts",
 		Long: `The contract command suite provides a collection of tools for deploying
-and interacting with smart contracts on Lux networks.`,
+and interacting with smart contracts on Lux networks: 'lux contract call'
+reads contract state and 'lux contract send' sends transactions calling
+contract methods.`,
 		RunE: cobrautils.CommandSuiteUsage,
 	}
 	app = injectedApp
@@ -25,5 +27,9 @@ and interacting with smart contracts on Lux networks.`,
 	cmd.AddCommand(newDeployCmd())
 	// contract initValidatorManager
 	cmd.AddCommand(newInitValidatorManagerCmd())
+	// contract call
+	cmd.AddCommand(newCallCmd())
+	// contract send
+	cmd.AddCommand(newSendCmd())
 	return cmd
 }
@@ -149,10 +149,11 @@ func initValidatorManager(_ *cobra.Command, args []string) error {
 	}
 	// Use network name as cluster identifier
 	clusterName := network.Name()
-	extraAggregatorPeers, err := blockchain.GetAggregatorExtraPeers(app, clusterName)
+	aggregatorPeerURIs, err := blockchain.GetAggregatorNetworkUris(app, clusterName)
 	if err != nil {
 		return err
 	}
+	aggregatorPeerURIs = append(aggregatorPeerURIs, initValidatorManagerFlags.SigAggFlags.AggregatorExtraEndpoints...)
 	aggregatorLogger, err := signatureaggregator.NewSignatureAggregatorLogger(
 		initValidatorManagerFlags.SigAggFlags.AggregatorLogLevel,
 		initValidatorManagerFlags.SigAggFlags.AggregatorLogToStdout,
@@ -190,15 +191,19 @@ func initValidatorManager(_ *cobra.Command, args []string) error {
 		OwnerAddress:        &ownerAddress,
 		RPC:                 initValidatorManagerFlags.RPC,
 	}
-	// Convert extraAggregatorPeers to []interface{}
-	extraPeers := make([]interface{}, len(extraAggregatorPeers))
-	for i, p := range extraAggregatorPeers {
+	// Convert aggregatorPeerURIs to []interface{}
+	extraPeers := make([]interface{}, len(aggregatorPeerURIs))
+	for i, p := range aggregatorPeerURIs {
 		extraPeers[i] = p
 	}
 	err = signatureaggregator.CreateSignatureAggregatorInstance(app, chainID.String(), network, extraPeers, aggregatorLogger, "latest")
 	if err != nil {
 		return err
 	}
+	if err := signatureaggregator.UpdateSignatureAggregatorPeers(app, network, aggregatorPeerURIs, aggregatorLogger); err != nil {
+		return err
+	}
+	aggregatorTimeout := initValidatorManagerFlags.SigAggFlags.AggregatorTimeout
 	signatureAggregatorEndpoint, err := signatureaggregator.GetSignatureAggregatorEndpoint(app, network)
 	if err != nil {
 		return err
@@ -206,16 +211,18 @@ func initValidatorManager(_ *cobra.Command, args []string) error {
 	switch {
 	case sc.ValidatorManagement == "proof-of-authority": // PoA
 		ux.Logger.PrintToUser(luxlog.Yellow.Wrap("Initializing Proof of Authority Validator Manager contract on blockchain %s"), blockchainName)
-		if err := validatormanagerSDK.SetupPoA(
-			aggregatorLogger, // Use aggregatorLogger instead of app.Log
-			netSDK,
-			network,
-			privateKey,
-			aggregatorLogger,
-			managerAddress,
-			sc.UseACP99,
-			signatureAggregatorEndpoint,
-		); err != nil {
+		if err := signatureaggregator.WatchAggregation(app, network, aggregatorTimeout, func() error {
+			return validatormanagerSDK.SetupPoA(
+				aggregatorLogger, // Use aggregatorLogger instead of app.Log
+				netSDK,
+				network,
+				privateKey,
+				aggregatorLogger,
+				managerAddress,
+				sc.UseACP99,
+				signatureAggregatorEndpoint,
+			)
+		}); err != nil {
 			return err
 		}
 		ux.Logger.GreenCheckmarkToUser("Proof of Authority Validator Manager contract successfully initialized on blockchain %s", blockchainName)
@@ -279,28 +286,30 @@ func initValidatorManager(_ *cobra.Command, args []string) error {
 		if !found {
 			return fmt.Errorf("could not find validator manager owner private key")
 		}
-		if err := validatormanagerSDK.SetupPoS(
-			aggregatorLogger, // Use aggregatorLogger instead of app.Log
-			netSDK,
-			network,
-			privateKey,
-			aggregatorLogger,
-			validatormanagerSDK.PoSParams{
-				MinimumStakeAmount:      big.NewInt(int64(initPOSManagerFlags.minimumStakeAmount)), //nolint:gosec // G115: Stake amounts are bounded
-				MaximumStakeAmount:      big.NewInt(int64(initPOSManagerFlags.maximumStakeAmount)), //nolint:gosec // G115: Stake amounts are bounded
-				MinimumStakeDuration:    initPOSManagerFlags.minimumStakeDuration,
-				MinimumDelegationFee:    initPOSManagerFlags.minimumDelegationFee,
-				MaximumStakeMultiplier:  initPOSManagerFlags.maximumStakeMultiplier,
-				WeightToValueFactor:     big.NewInt(int64(initPOSManagerFlags.weightToValueFactor)), //nolint:gosec // G115: Weight factor is bounded
-				RewardCalculatorAddress: initPOSManagerFlags.rewardCalculatorAddress,
-				UptimeBlockchainID:      blockchainID,
-			},
-			managerAddress,
-			validatormanagerSDK.SpecializationProxyContractAddress,
-			managerOwnerPrivateKey,
-			sc.UseACP99,
-			signatureAggregatorEndpoint,
-		); err != nil {
+		if err := signatureaggregator.WatchAggregation(app, network, aggregatorTimeout, func() error {
+			return validatormanagerSDK.SetupPoS(
+				aggregatorLogger, // Use aggregatorLogger instead of app.Log
+				netSDK,
+				network,
+				privateKey,
+				aggregatorLogger,
+				validatormanagerSDK.PoSParams{
+					MinimumStakeAmount:      big.NewInt(int64(initPOSManagerFlags.minimumStakeAmount)), //nolint:gosec // G115: Stake amounts are bounded
+					MaximumStakeAmount:      big.NewInt(int64(initPOSManagerFlags.maximumStakeAmount)), //nolint:gosec // G115: Stake amounts are bounded
+					MinimumStakeDuration:    initPOSManagerFlags.minimumStakeDuration,
+					MinimumDelegationFee:    initPOSManagerFlags.minimumDelegationFee,
+					MaximumStakeMultiplier:  initPOSManagerFlags.maximumStakeMultiplier,
+					WeightToValueFactor:     big.NewInt(int64(initPOSManagerFlags.weightToValueFactor)), //nolint:gosec // G115: Weight factor is bounded
+					RewardCalculatorAddress: initPOSManagerFlags.rewardCalculatorAddress,
+					UptimeBlockchainID:      blockchainID,
+				},
+				managerAddress,
+				validatormanagerSDK.SpecializationProxyContractAddress,
+				managerOwnerPrivateKey,
+				sc.UseACP99,
+				signatureAggregatorEndpoint,
+			)
+		}); err != nil {
 			return err
 		}
 		sidecar, err := app.LoadSidecar(blockchainName)
@@ -0,0 +1,115 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package contractcmd
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/contract"
+	"github.com/luxfi/cli/pkg/history"
+	cliprompts "github.com/luxfi/cli/pkg/prompts"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/sdk/prompts"
+	"github.com/spf13/cobra"
+)
+
+var sendFlags ContractCallFlags
+
+// lux contract send
+func newSendCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "send <address> <method> [args...]",
+		Short: "Send a transaction calling a contract method",
+		Long: `Sign and send a transaction calling a method of a deployed contract, wait
+for its receipt, and print the event logs it emitted, decoded with the
+contract ABI.
+
+The method and its arguments are given as for 'lux contract call'. The
+transaction is signed with --private-key, a CLI stored --key, or the
+--genesis-key of the chain, and --value native tokens, in wei, are sent
+with it.
+
+Examples:
+  lux contract send 0x5FbDB2315678afecb367f032d93F642f64180aa3 \
+    "transfer(address,uint256)" 0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC 1000 \
+    --c-chain --local --genesis-key
+
+  lux contract send 0x5FbDB2315678afecb367f032d93F642f64180aa3 deposit \
+    --abi ./out/Vault.sol/Vault.json --value 1000000000000000000 \
+    --blockchain mychain --testnet --key ops`,
+		RunE: sendContract,
+		Args: cobrautils.MinimumNArgs(2),
+	}
+	addMethodCallFlags(cmd, &sendFlags, "send the transaction to %s")
+	sendFlags.PrivateKeyFlags.AddToCmd(cmd, "to sign the transaction")
+	cmd.Flags().StringVar(&sendFlags.value, "value", "0", "native tokens to send with the call, in wei")
+	return cmd
+}
+
+func sendContract(_ *cobra.Command, args []string) error {
+	value, ok := new(big.Int).SetString(sendFlags.value, 10)
+	if !ok || value.Sign() < 0 {
+		return fmt.Errorf("invalid --value %q: expected an amount in wei", sendFlags.value)
+	}
+	call, err := resolveMethodCall(&sendFlags, args)
+	if err != nil {
+		return err
+	}
+	genesisPrivateKey := ""
+	if sendFlags.PrivateKeyFlags.GenesisKey {
+		if _, genesisPrivateKey, err = contract.GetEVMChainPrefundedKey(app, call.network, sendFlags.chainFlags); err != nil {
+			return err
+		}
+	}
+	privateKey, err := sendFlags.PrivateKeyFlags.GetPrivateKey(app, genesisPrivateKey)
+	if err != nil {
+		return err
+	}
+	if privateKey == "" {
+		if !cliprompts.IsInteractive() {
+			return errors.New("give the key signing the transaction with --private-key, --key or --genesis-key")
+		}
+		if privateKey, err = prompts.PromptPrivateKey(app.Prompt, "sign the transaction"); err != nil {
+			return err
+		}
+	}
+
+	tx, receipt, err := contract.SendMethod(call.rpcEndpoint, privateKey, call.address, call.abi, value, call.method.Name, call.args...)
+	if tx != nil {
+		ux.Logger.PrintToUser("Transaction: %s", tx.Hash().Hex())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to send %s: %w", call.method.Sig, err)
+	}
+	ux.Logger.PrintToUser("Included in block %s, gas used %d", receipt.BlockNumber, receipt.GasUsed)
+	for _, log := range contract.DecodeLogs(call.abi, receipt.Logs) {
+		if log.Event == "" {
+			ux.Logger.PrintToUser("Log from %s: %s", log.Address.Hex(), log.Raw)
+			continue
+		}
+		fields := make([]string, 0, len(log.Args))
+		for _, arg := range log.Args {
+			fields = append(fields, arg.Name+"="+arg.Value)
+		}
+		ux.Logger.PrintToUser("Event %s from %s: %s", log.Event, log.Address.Hex(), strings.Join(fields, " "))
+	}
+
+	chain, _ := contract.GetBlockchainDesc(sendFlags.chainFlags)
+	app.RecordHistory(history.Entry{
+		Operation: "contract send",
+		Network:   call.network.String(),
+		Chain:     chain,
+		Params: map[string]string{
+			"contract": call.address.Hex(),
+			"method":   call.method.Sig,
+			"value":    value.String(),
+		},
+		Result: map[string]string{"txID": tx.Hash().Hex()},
+	})
+	return nil
+}
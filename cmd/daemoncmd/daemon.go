@@ -0,0 +1,186 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package daemoncmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/luxfi/cli/cmd/chaincmd"
+	"github.com/luxfi/cli/cmd/networkcmd"
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/daemon"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/key"
+	"github.com/luxfi/cli/pkg/snapshot"
+	"github.com/luxfi/cli/pkg/status"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	app *application.Lux
+
+	listenAddr string
+)
+
+// NewCmd creates the daemon command
+func NewCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Serve the CLI's network and snapshot operations over gRPC",
+		Long: `The daemon command runs a local gRPC server exposing network control,
+status and snapshot operations, so IDE extensions and GUIs can drive the same
+code paths as the CLI without spawning a process for every call.
+
+By default the daemon listens on a unix socket in the CLI base directory
+(~/.lux/daemon.sock) that only the current user can access. Use
+--listen tcp://127.0.0.1:<port> to listen on TCP instead. The API has no
+authentication, so only loopback hosts are accepted. 'lux serve' serves the
+same API with token authentication, and over REST too.
+
+The service is lux.daemon.v1.Admin. Every method takes and returns a
+google.protobuf.Struct:
+
+  Status           {}                                       -> network status
+  StartNetwork     {"network": "mainnet"}                   -> {}
+  StopNetwork      {"network": "mainnet", "force": true}    -> {}
+  CreateSnapshot   {"name": "backup", "incremental": true}  -> {}
+  RestoreSnapshot  {"name": "backup"}                       -> {}
+  ListSnapshots    {}                                       -> {"snapshots": [...]}
+  DeployChain      {"chain": "mychain", "network": "testnet", "key": "ops"} -> {}
+  ListKeys         {}                                       -> {"keys": [...]}
+
+EXAMPLES:
+
+  lux daemon
+  lux daemon --listen tcp://127.0.0.1:8399`,
+		RunE:         runDaemon,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringVar(&listenAddr, "listen", "", "unix socket path or tcp://host:port (default: ~/.lux/daemon.sock)")
+
+	return cmd
+}
+
+func runDaemon(_ *cobra.Command, _ []string) error {
+	addr := listenAddr
+	if addr == "" {
+		addr = filepath.Join(app.GetBaseDir(), daemon.SocketName)
+	}
+
+	lis, err := daemon.Listen(addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	server := daemon.NewServer(&backend{}, "")
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Serve(lis)
+	}()
+	ux.Logger.PrintToUser("Lux daemon listening on %s", addr)
+	return waitForShutdown(server, errCh)
+}
+
+// waitForShutdown blocks until the process is interrupted, stopping server,
+// or until one of its listeners fails.
+func waitForShutdown(server *daemon.Server, errCh <-chan error) error {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	select {
+	case <-sigCh:
+		ux.Logger.PrintToUser("Shutting down...")
+		server.Stop()
+		return nil
+	case err := <-errCh:
+		server.Stop()
+		return err
+	}
+}
+
+// backend implements daemon.Backend with the functions behind the network
+// and snapshot commands.
+type backend struct{}
+
+func (*backend) Status(ctx context.Context) (any, error) {
+	return status.NewStatusService().GetStatus(ctx)
+}
+
+func (*backend) StartNetwork(_ context.Context, req daemon.NetworkRequest) error {
+	return networkcmd.StartNetworkType(req.Network)
+}
+
+func (*backend) StopNetwork(_ context.Context, req daemon.NetworkRequest) error {
+	return networkcmd.StopNetworkType(req.Network, req.Force)
+}
+
+func (*backend) CreateSnapshot(_ context.Context, req daemon.SnapshotRequest) error {
+	name := req.Name
+	if name == "" {
+		name = fmt.Sprintf("snapshot-%s", time.Now().Format("2006-01-02"))
+	}
+	return snapshot.NewSnapshotManager(app.GetBaseDir()).CreateSnapshot(name, req.Incremental)
+}
+
+func (*backend) RestoreSnapshot(_ context.Context, req daemon.SnapshotRequest) error {
+	if req.Name == "" {
+		return fmt.Errorf("snapshot name is required")
+	}
+	if err := snapshot.NewSnapshotManager(app.GetBaseDir()).RestoreSnapshot(req.Name); err != nil {
+		return err
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "snapshot restore",
+		Params:    map[string]string{"snapshot": req.Name},
+	})
+	return nil
+}
+
+func (*backend) ListSnapshots(context.Context) ([]daemon.SnapshotInfo, error) {
+	snapshots, err := snapshot.NewSnapshotManager(app.GetBaseDir()).ListSnapshots()
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]daemon.SnapshotInfo, 0, len(snapshots))
+	for _, s := range snapshots {
+		infos = append(infos, daemon.SnapshotInfo{
+			Name:        s.Name,
+			Size:        s.Size,
+			Incremental: s.Incremental,
+			Created:     s.Created.UTC().Format(time.RFC3339),
+		})
+	}
+	return infos, nil
+}
+
+func (*backend) DeployChain(_ context.Context, req daemon.DeployRequest) error {
+	return chaincmd.DeployChain(req.Chain, req.Network, req.Key)
+}
+
+func (*backend) ListKeys(context.Context) ([]daemon.KeyInfo, error) {
+	names, err := key.ListKeySets()
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]daemon.KeyInfo, 0, len(names))
+	for _, name := range names {
+		info := daemon.KeyInfo{Name: name}
+		if ks, err := key.LoadKeySetPublicOnly(name); err == nil {
+			info.Address = ks.ECAddress
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package daemoncmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/daemon"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultRESTAddr = "tcp://127.0.0.1:8398"
+	tokenEnvVar     = "LUX_SERVE_TOKEN"
+)
+
+var (
+	serveGRPCAddr string
+	serveRESTAddr string
+	serveToken    string
+)
+
+// NewServeCmd creates the serve command
+func NewServeCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the CLI's network, chain, snapshot and key operations over REST and gRPC",
+		Long: `The serve command runs the admin API of 'lux daemon' over both gRPC and
+REST, so dashboards, CI pipelines and GUIs can drive the CLI without shelling
+out to it.
+
+gRPC listens on --listen, by default the unix socket ~/.lux/daemon.sock, and
+REST on --rest, by default tcp://127.0.0.1:8398. TCP listeners only accept
+loopback hosts.
+
+Every call must send the header "Authorization: Bearer <token>", as gRPC
+metadata for gRPC calls. The token is --token, else $LUX_SERVE_TOKEN, else
+the one stored in ~/.lux/serve.token, which is created on first use and only
+readable by the current user.
+
+REST routes take and return the JSON objects of the gRPC methods listed in
+'lux daemon --help'; path values fill the fields of the same name:
+
+  GET  /v1/status                         Status
+  POST /v1/networks/{network}/start       StartNetwork
+  POST /v1/networks/{network}/stop        StopNetwork      {"force": true}
+  GET  /v1/snapshots                      ListSnapshots
+  POST /v1/snapshots                      CreateSnapshot   {"name": "backup", "incremental": true}
+  POST /v1/snapshots/{name}/restore       RestoreSnapshot
+  POST /v1/chains/{chain}/deploy          DeployChain      {"network": "testnet", "key": "ops"}
+  GET  /v1/keys                           ListKeys
+
+Errors are returned as {"error": "..."}, with status 401 for a missing or
+wrong token and 400 for invalid arguments.
+
+EXAMPLES:
+
+  lux serve
+  lux serve --rest tcp://127.0.0.1:9000 --listen tcp://127.0.0.1:9001
+
+  curl -H "Authorization: Bearer $(cat ~/.lux/serve.token)" \
+    -X POST http://127.0.0.1:8398/v1/networks/devnet/start`,
+		RunE:         runServe,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringVar(&serveGRPCAddr, "listen", "", "gRPC unix socket path or tcp://host:port (default: ~/.lux/daemon.sock)")
+	cmd.Flags().StringVar(&serveRESTAddr, "rest", defaultRESTAddr, "REST tcp://host:port or unix socket path")
+	cmd.Flags().StringVar(&serveToken, "token", "", "token callers must send (default: $LUX_SERVE_TOKEN, else ~/.lux/serve.token)")
+
+	return cmd
+}
+
+func runServe(_ *cobra.Command, _ []string) error {
+	token, err := serveAuthToken()
+	if err != nil {
+		return err
+	}
+	grpcAddr := serveGRPCAddr
+	if grpcAddr == "" {
+		grpcAddr = filepath.Join(app.GetBaseDir(), daemon.SocketName)
+	}
+	if grpcAddr == serveRESTAddr {
+		return errors.New("--listen and --rest must differ")
+	}
+
+	grpcLis, err := daemon.Listen(grpcAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", grpcAddr, err)
+	}
+	restLis, err := daemon.Listen(serveRESTAddr)
+	if err != nil {
+		_ = grpcLis.Close()
+		return fmt.Errorf("failed to listen on %s: %w", serveRESTAddr, err)
+	}
+
+	server := daemon.NewServer(&backend{}, token)
+	errCh := make(chan error, 2)
+	go func() {
+		errCh <- server.Serve(grpcLis)
+	}()
+	go func() {
+		errCh <- server.ServeREST(restLis)
+	}()
+	ux.Logger.PrintToUser("Lux admin API listening on %s (gRPC) and %s (REST)", grpcAddr, serveRESTAddr)
+	return waitForShutdown(server, errCh)
+}
+
+// serveAuthToken returns the token of --token, of the environment, or else
+// of the token file of the CLI base directory
+func serveAuthToken() (string, error) {
+	if serveToken != "" {
+		return serveToken, nil
+	}
+	if token := os.Getenv(tokenEnvVar); token != "" {
+		return token, nil
+	}
+	path := filepath.Join(app.GetBaseDir(), daemon.TokenName)
+	token, err := daemon.LoadOrCreateToken(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to load the token at %s: %w", path, err)
+	}
+	return token, nil
+}
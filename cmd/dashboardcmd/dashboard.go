@@ -0,0 +1,326 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dashboardcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/binutils"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/dashboard"
+	"github.com/luxfi/cli/pkg/hooks"
+	"github.com/luxfi/cli/pkg/nodelogs"
+	"github.com/luxfi/cli/pkg/snapshot"
+	"github.com/luxfi/cli/pkg/status"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/warp"
+	"github.com/luxfi/cli/pkg/warp/relayer"
+	"github.com/luxfi/sdk/models"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// recentSnapshots is how many of the latest snapshots are shown
+const recentSnapshots = 10
+
+// loadTimeout bounds a refresh of the dashboard
+const loadTimeout = 30 * time.Second
+
+// relayerNetworks are the networks whose relayer routes are shown: the local
+// one always, the others while their relayer runs on this machine
+var relayerNetworks = []models.Network{models.Local, models.Devnet, models.Testnet, models.Mainnet}
+
+var (
+	app *application.Lux
+
+	interval    time.Duration
+	blocks      uint64
+	stallBlocks uint64
+)
+
+// NewCmd creates the dashboard command
+func NewCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "dashboard",
+		Short: "Watch the nodes, validators, snapshots and relayer in a terminal UI",
+		Long: `The dashboard command shows the networks run on this machine on one
+screen, refreshed every --interval:
+
+  1 Nodes       version, peers, uptime, latency and health of every node
+  2 Validators  stake weight, and health after the local node running it
+  3 Snapshots   the latest snapshots of 'lux snapshot'
+  4 Relayer     the Warp routes of the local network, and of the networks
+                whose relayer runs here, as 'lux warp relayer status'
+
+KEYS:
+
+  tab, 1-4   focus a pane          ↑/↓, j/k   select a row
+  r          refresh now           R          restart the selected node
+  l          tail its logs         s          take a snapshot of its network
+  esc        leave the logs        q          quit
+
+The selected node is the one of the Nodes pane, or the node running the
+validator selected in the Validators pane. Nodes are restarted through the
+network runner, once confirmed with y. Snapshots run the snapshot-create
+hooks, as 'lux snapshot' does, and report their progress on the status line.
+
+EXAMPLES:
+
+  lux dashboard
+  lux dashboard --interval 10s --blocks 5000`,
+		Args:         cobrautils.ExactArgs(0),
+		RunE:         runDashboard,
+		SilenceUsage: true,
+	}
+	cmd.Flags().DurationVar(&interval, "interval", 5*time.Second, "time between refreshes")
+	cmd.Flags().Uint64Var(&blocks, "blocks", 1000, "source blocks to scan for the sent messages of relayer routes")
+	cmd.Flags().Uint64Var(&stallBlocks, "stall-blocks", 100, "source blocks a message may wait before its route is stalled")
+	return cmd
+}
+
+func runDashboard(_ *cobra.Command, _ []string) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid --interval %s, must be positive", interval)
+	}
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		return errors.New("the dashboard needs a terminal, use 'lux status' for plain output")
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	sources := dashboard.Sources{
+		Load:     load,
+		Restart:  restartNode,
+		Logs:     tailLogs,
+		Snapshot: takeSnapshot,
+	}
+	program := tea.NewProgram(dashboard.New(ctx, sources, interval), tea.WithAltScreen(), tea.WithContext(ctx))
+	// the output of the actions run goes to the status line, as it would
+	// draw over the dashboard
+	restore := ux.Logger.SetWriter(noticeWriter{program: program})
+	defer restore()
+	if _, err := program.Run(); err != nil && !errors.Is(err, tea.ErrProgramKilled) {
+		return err
+	}
+	return nil
+}
+
+// noticeWriter shows the lines written to it on the status line of the
+// dashboard
+type noticeWriter struct {
+	program *tea.Program
+}
+
+func (w noticeWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(string(p), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			w.program.Send(dashboard.Notice(line))
+		}
+	}
+	return len(p), nil
+}
+
+// load returns the state of the networks run on this machine
+func load(ctx context.Context) dashboard.State {
+	ctx, cancel := context.WithTimeout(ctx, loadTimeout)
+	defer cancel()
+	state := dashboard.State{Errors: map[dashboard.Pane]error{}}
+
+	result, err := status.NewStatusService().GetStatus(ctx)
+	switch {
+	case errors.Is(err, status.ErrNoNetwork):
+	case err != nil:
+		state.Errors[dashboard.NodesPane] = err
+		state.Errors[dashboard.ValidatorsPane] = err
+	default:
+		for _, network := range result.Networks {
+			nodes, validators := networkNodes(network)
+			state.Nodes = append(state.Nodes, nodes...)
+			state.Validators = append(state.Validators, validators...)
+		}
+	}
+
+	if state.Snapshots, err = loadSnapshots(); err != nil {
+		state.Errors[dashboard.SnapshotsPane] = err
+	}
+	if state.Routes, err = loadRoutes(ctx); err != nil {
+		state.Errors[dashboard.RelayerPane] = err
+	}
+	return state
+}
+
+// networkNodes returns the nodes and validators of network
+func networkNodes(network status.Network) ([]dashboard.Node, []dashboard.Validator) {
+	var (
+		nodes   []dashboard.Node
+		healthy = map[string]bool{}
+	)
+	for _, n := range network.Nodes {
+		nodes = append(nodes, dashboard.Node{
+			Network:   network.Name,
+			Name:      "node" + n.ID,
+			NodeID:    n.NodeID,
+			URL:       n.HTTPURL,
+			Version:   n.Version,
+			Peers:     n.PeerCount,
+			Uptime:    n.Uptime,
+			Healthy:   n.OK,
+			LatencyMS: n.LatencyMS,
+			Error:     n.LastError,
+		})
+		if n.NodeID != "" {
+			healthy[n.NodeID] = n.OK
+		}
+	}
+	validators := make([]dashboard.Validator, 0, len(network.Validators))
+	for _, v := range network.Validators {
+		health := dashboard.Unknown
+		if ok, local := healthy[v.NodeID]; local {
+			health = dashboard.Down
+			if ok {
+				health = dashboard.Healthy
+			}
+		}
+		validators = append(validators, dashboard.Validator{
+			Network: network.Name,
+			NodeID:  v.NodeID,
+			Weight:  v.StakeWeight,
+			Health:  health,
+		})
+	}
+	return nodes, validators
+}
+
+// loadSnapshots returns the latest snapshots, newest first
+func loadSnapshots() ([]dashboard.Snapshot, error) {
+	infos, err := snapshot.NewSnapshotManager(app.GetBaseDir()).ListSnapshots()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Created.After(infos[j].Created) })
+	if len(infos) > recentSnapshots {
+		infos = infos[:recentSnapshots]
+	}
+	snapshots := make([]dashboard.Snapshot, 0, len(infos))
+	for _, info := range infos {
+		snapshots = append(snapshots, dashboard.Snapshot{
+			Name:        info.Name,
+			Size:        snapshot.FormatBytes(info.Size),
+			Incremental: info.Incremental,
+			Created:     info.Created,
+		})
+	}
+	return snapshots, nil
+}
+
+// loadRoutes returns the relayer routes between the chains of
+// relayerNetworks that have a Warp messenger
+func loadRoutes(ctx context.Context) ([]dashboard.Route, error) {
+	var routes []dashboard.Route
+	opts := relayer.StatusOptions{Blocks: blocks, StallBlocks: stallBlocks}
+	for _, network := range relayerNetworks {
+		running, err := relayer.Running(app.GetLocalRelayerRunPath(network))
+		if err != nil {
+			return nil, err
+		}
+		if !running && network != models.Local {
+			continue
+		}
+		endpoints, closeEndpoints, err := warp.MessengerEndpoints(app, network)
+		if err != nil {
+			return nil, err
+		}
+		for _, source := range endpoints {
+			for _, dest := range endpoints {
+				if source.BlockchainID == dest.BlockchainID {
+					continue
+				}
+				route := dashboard.Route{Network: network.Name(), Name: source.Name + " -> " + dest.Name, Relayer: running}
+				s, err := relayer.CheckRoute(ctx, relayer.Route{Source: source, Destination: dest}, opts)
+				if err != nil {
+					route.Error = err.Error()
+					routes = append(routes, route)
+					continue
+				}
+				route.Health = string(s.Health)
+				route.Sent, route.Delivered, route.Pending = s.Sent, s.Delivered, s.Pending
+				if s.OldestPending != nil {
+					route.OldestPending = fmt.Sprintf("nonce %d, block %d", s.OldestPending.Nonce, s.OldestPending.BlockNumber)
+				}
+				routes = append(routes, route)
+			}
+		}
+		closeEndpoints()
+	}
+	return routes, nil
+}
+
+// restartNode restarts node through the network runner of its network
+func restartNode(ctx context.Context, node dashboard.Node) error {
+	cli, err := binutils.NewGRPCClient(binutils.WithNetworkType(node.Network))
+	if err != nil {
+		return fmt.Errorf("%s has no network runner to restart %s: %w", node.Network, node.Name, err)
+	}
+	defer func() { _ = cli.Close() }()
+	name := node.Name
+	resp, err := cli.Status(ctx)
+	if err != nil {
+		return err
+	}
+	// the runner may name the nodes apart from their run directory
+	for runnerName, info := range resp.GetClusterInfo().GetNodeInfos() {
+		if info.GetUri() == node.URL {
+			name = runnerName
+		}
+	}
+	if _, err := cli.RestartNode(ctx, name); err != nil {
+		return fmt.Errorf("failed to restart %s: %w", node.Name, err)
+	}
+	return nil
+}
+
+// tailLogs emits the recent lines of the logs of node, then follows them
+func tailLogs(ctx context.Context, node dashboard.Node, emit func(string)) error {
+	runDir, err := utils.ReadDirLink(filepath.Join(app.GetRunDir(), node.Network, "current"))
+	if err != nil {
+		return fmt.Errorf("no run of %s found: %w", node.Network, err)
+	}
+	source := nodelogs.Source{
+		Node: node.Name,
+		Tail: nodelogs.LocalTail(filepath.Join(runDir, node.Name, "logs")),
+	}
+	opts := nodelogs.Options{Lines: nodelogs.DefaultLines, Follow: true}
+	return nodelogs.Stream(ctx, []nodelogs.Source{source}, opts, func(l nodelogs.Line) {
+		emit(strings.TrimSuffix(l.File, ".log") + " | " + l.Text)
+	})
+}
+
+// takeSnapshot takes an incremental snapshot, as 'lux snapshot' does
+func takeSnapshot(_ context.Context, network string) (string, error) {
+	name := fmt.Sprintf("%s-%s", network, time.Now().Format("2006-01-02-150405"))
+	sm := snapshot.NewSnapshotManager(app.GetBaseDir())
+	hc := hooks.Context{Network: network, Snapshot: name}
+	if err := app.RunWithHooks(hooks.SnapshotCreate, hc, func() error {
+		if err := sm.CreateSnapshot(name, true); err != nil {
+			return fmt.Errorf("failed to create snapshot: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return "", err
+	}
+	return name, nil
+}
@@ -9,8 +9,11 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var app *application.Lux
+
 // NewCmd creates the dev command for local development
-func NewCmd(_ *application.Lux) *cobra.Command {
+func NewCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
 	cmd := &cobra.Command{
 		Use:   "dev",
 		Short: "Development environment commands",
@@ -14,10 +14,10 @@ import (
 	"strconv"
 	"regexp"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/cli/pkg/ux"
 	"github.com/luxfi/constants"
 	"github.com/spf13/cobra"
@@ -370,16 +370,16 @@ func stopOne(pidDir, name string) {
 	}
 
 	// Check if process is actually running
-	if err := process.Signal(syscall.Signal(0)); err != nil {
+	if !utils.IsProcessRunning(pid) {
 		ux.Logger.PrintToUser("%s (PID %d): not running", name, pid)
 		removePIDFile(pidDir, name)
 		return
 	}
 
 	ux.Logger.PrintToUser("Stopping %s (PID %d)...", name, pid)
-	if err := process.Signal(syscall.SIGTERM); err != nil {
+	if err := utils.TerminateProcess(process); err != nil {
 		ux.Logger.PrintToUser("%s: SIGTERM failed: %v, trying SIGKILL", name, err)
-		_ = process.Signal(syscall.SIGKILL)
+		_ = process.Kill()
 		removePIDFile(pidDir, name)
 		return
 	}
@@ -396,7 +396,7 @@ func stopOne(pidDir, name string) {
 		ux.Logger.GreenCheckmarkToUser("%s stopped", name)
 	case <-time.After(shutdownTimeout):
 		ux.Logger.PrintToUser("%s: shutdown timeout, sending SIGKILL", name)
-		_ = process.Signal(syscall.SIGKILL)
+		_ = process.Kill()
 		_, _ = process.Wait()
 	}
 
@@ -539,7 +539,7 @@ func defaultConfig() *StackConfig {
 }
 
 func stackBaseDir() string {
-	return filepath.Join(os.Getenv("HOME"), constants.BaseDirName, constants.DevDir)
+	return filepath.Join(app.GetBaseDir(), constants.DevDir)
 }
 
 func configPath() string {
@@ -650,7 +650,7 @@ func resolveBinary(name string) (string, error) {
 
 	// For luxd, also check the standard CLI install location
 	if name == "luxd" {
-		cliBin := filepath.Join(os.Getenv("HOME"), constants.BaseDirName, constants.LuxCliBinDir, "luxd")
+		cliBin := utils.UserHomePath(constants.BaseDirName, constants.LuxCliBinDir, "luxd")
 		if _, err := os.Stat(cliBin); err == nil {
 			return cliBin, nil
 		}
@@ -667,7 +667,7 @@ func spawnProcessWithEnv(binary string, args []string, logFile *os.File, pidDir,
 	cmd := exec.Command(binary, args...) //nolint:gosec // G204: Running configured dev binaries
 	cmd.Stdout = logFile
 	cmd.Stderr = logFile
-	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true} // Detach from parent process group
+	utils.DetachProcess(cmd) // Detach from parent process group
 
 	if len(extraEnv) > 0 {
 		cmd.Env = append(os.Environ(), extraEnv...)
@@ -731,11 +731,7 @@ func isRunning(pidDir, name string) bool {
 }
 
 func processAlive(pid int) bool {
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false
-	}
-	return process.Signal(syscall.Signal(0)) == nil
+	return utils.IsProcessRunning(pid)
 }
 
 // processUptime returns approximate uptime based on PID file mtime.
@@ -773,7 +769,7 @@ func waitForHealth(httpPort int) error {
 	ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
 	defer cancel()
 
-	client := &http.Client{Timeout: 2 * time.Second}
+	client := utils.NewRPCClient(2*time.Second, utils.PollRetryPolicy())
 	ticker := time.NewTicker(healthInterval)
 	defer ticker.Stop()
 
@@ -120,7 +120,7 @@ func startDevNode(*cobra.Command, []string) error {
 	}
 
 	// Data directories - use constants for consistent paths
-	baseDir := filepath.Join(os.Getenv("HOME"), constants.BaseDirName)
+	baseDir := app.GetBaseDir()
 	dataDir := filepath.Join(baseDir, constants.DevDir)
 	dbDir := filepath.Join(dataDir, "db")
 	logDir := filepath.Join(dataDir, "logs")
@@ -8,9 +8,11 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 
+	"github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/cli/pkg/ux"
 	"github.com/spf13/cobra"
 )
@@ -34,13 +36,13 @@ func stopDevNode(*cobra.Command, []string) error {
 	ux.Logger.PrintToUser("Stopping Lux dev node...")
 
 	// Try to find PID file first
-	pidFile := filepath.Join(os.Getenv("HOME"), ".lux", "dev", "luxd.pid")
+	pidFile := filepath.Join(stackBaseDir(), "luxd.pid")
 	if pidData, err := os.ReadFile(pidFile); err == nil { //nolint:gosec // G304: Reading from app's data directory
 		pid, err := strconv.Atoi(strings.TrimSpace(string(pidData)))
 		if err == nil {
 			process, err := os.FindProcess(pid)
 			if err == nil {
-				if err := process.Signal(os.Interrupt); err == nil {
+				if err := utils.InterruptProcess(process); err == nil {
 					ux.Logger.PrintToUser("Sent interrupt signal to PID %d", pid)
 					_ = os.Remove(pidFile)
 					return nil
@@ -49,6 +51,13 @@ func stopDevNode(*cobra.Command, []string) error {
 		}
 	}
 
+	// pkill is not available on Windows, where the PID file is the only way
+	// to find the dev node
+	if runtime.GOOS == "windows" {
+		ux.Logger.PrintToUser("No dev node running")
+		return nil
+	}
+
 	// Fallback: use pkill (only for luxd, and only in dev context)
 	cmd := exec.Command("pkill", "-f", "luxd.*--dev")
 	output, err := cmd.CombinedOutput()
@@ -0,0 +1,175 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package discovercmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/config"
+	"github.com/luxfi/cli/pkg/discovery"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	app *application.Lux
+
+	browseTimeout time.Duration
+	localOnly     bool
+	jsonOutput    bool
+)
+
+// NewCmd creates the discover command
+func NewCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "discover",
+		Short: "Find the networks running on this machine and the LAN",
+		Long: `Networks started with 'lux network start' are advertised, while they run,
+in the well-known directory ` + discovery.DefaultDir() + `, one JSON file per
+network, readable by every user and tool of the machine.
+
+The discover command lists the networks advertised there by any user, and
+those answering an mDNS query for ` + discovery.ServiceType + ` on
+the LAN, served by 'lux discover advertise'. Endpoints of networks on other
+machines are only reachable if their nodes listen beyond loopback.
+
+EXAMPLES:
+
+  # Networks of this machine and the LAN
+  lux discover
+
+  # Only this machine, as JSON for a wallet or explorer
+  lux discover --local-only --json
+
+  # Answer the mDNS queries of the LAN for the networks of this machine
+  lux discover advertise`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: discover,
+		// Discovery only reads advertisements
+		Annotations: map[string]string{config.CapabilityAnnotation: config.CapabilityNone},
+	}
+	cmd.Flags().DurationVar(&browseTimeout, "timeout", 2*time.Second, "how long to wait for mDNS answers")
+	cmd.Flags().BoolVar(&localOnly, "local-only", false, "only list the networks of this machine")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "print the networks as JSON")
+	cmd.AddCommand(newAdvertiseCmd())
+	return cmd
+}
+
+func discover(cmd *cobra.Command, _ []string) error {
+	networks, err := discovery.List(discovery.DefaultDir())
+	if err != nil {
+		return err
+	}
+	if !localOnly {
+		lan, err := discovery.Browse(cmd.Context(), browseTimeout)
+		if err != nil {
+			ux.Logger.PrintToUser("Warning: mDNS discovery failed: %s", err)
+		}
+		networks = discovery.Merge(networks, lan)
+	}
+	if jsonOutput {
+		type discovered struct {
+			discovery.Network
+			Source    string `json:"source"`
+			Reachable bool   `json:"reachable"`
+		}
+		out := make([]discovered, 0, len(networks))
+		for _, n := range networks {
+			out = append(out, discovered{Network: n, Source: n.Source, Reachable: reachable(n.RPC)})
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	if len(networks) == 0 {
+		ux.Logger.PrintToUser("No running networks found")
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Network", "ID", "RPC", "User", "Host", "Started", "Status")
+	for _, n := range networks {
+		status := "up"
+		if !reachable(n.RPC) {
+			status = "unreachable"
+		}
+		_ = table.Append([]string{
+			n.Name,
+			strconv.FormatUint(uint64(n.NetworkID), 10),
+			n.RPC,
+			n.User,
+			n.Host,
+			n.Started.Local().Format(time.RFC3339),
+			status,
+		})
+	}
+	return table.Render()
+}
+
+func newAdvertiseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "advertise",
+		Short: "Answer the mDNS queries of the LAN for the networks of this machine",
+		Long: `The advertise command answers, in the foreground, the mDNS queries for
+` + discovery.ServiceType + ` with the networks of this machine
+advertised in ` + discovery.DefaultDir() + `, so that 'lux discover',
+'dns-sd -B ` + discovery.ServiceType + `' or 'avahi-browse' find them
+on the LAN.`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			host, _ := os.Hostname()
+			ux.Logger.PrintToUser("Advertising the networks of %s over mDNS, press Ctrl+C to stop", host)
+			return discovery.Serve(ctx, func() []discovery.Network {
+				networks, err := discovery.List(discovery.DefaultDir())
+				if err != nil {
+					return nil
+				}
+				local := networks[:0]
+				for _, n := range networks {
+					if n.Host == host && reachable(n.RPC) {
+						local = append(local, n)
+					}
+				}
+				return local
+			})
+		},
+		// Advertising only publishes the endpoints of running networks
+		Annotations: map[string]string{config.CapabilityAnnotation: config.CapabilityNone},
+	}
+}
+
+// reachable reports whether the endpoint rpc accepts connections
+func reachable(rpc string) bool {
+	u, err := url.Parse(rpc)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "80")
+	}
+	conn, err := net.DialTimeout("tcp", host, 500*time.Millisecond)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
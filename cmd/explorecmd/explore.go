@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -171,7 +172,7 @@ func stopExplorer() error {
 		return nil
 	}
 
-	if err := p.Signal(os.Interrupt); err != nil {
+	if err := utils.InterruptProcess(p); err != nil {
 		fmt.Printf("Failed to stop explorer (PID %d): %v\n", pid, err)
 		return nil
 	}
@@ -270,11 +271,7 @@ func removePID() {
 }
 
 func isRunning(pid int) bool {
-	p, err := os.FindProcess(pid)
-	if err != nil {
-		return false
-	}
-	return p.Signal(nil) == nil
+	return utils.IsProcessRunning(pid)
 }
 
 func openURL(url string) {
@@ -0,0 +1,127 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package feescmd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/luxfi/cli/pkg/config"
+	"github.com/luxfi/cli/pkg/feeestimate"
+	"github.com/luxfi/cli/pkg/status"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	estimateOp          string
+	estimateValidators  int
+	estimateBalance     float64
+	estimateGenesisPath string
+)
+
+func newEstimateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "estimate",
+		Short: "Estimate the fees and validator balances of a P-Chain operation",
+		Long: `Estimate what --op costs at the current dynamic fee state of the P-Chain:
+
+  create-chain   CreateNetworkTx and CreateChainTx of a new chain
+  convert-l1     ConvertChainToL1Tx with --validators bootstrap validators
+  add-validator  a RegisterL1ValidatorTx for each of --validators validators
+
+The fees are those of txs paid by a single key, at the gas price wallets
+build txs with. L1 validators lock --balance LUX each, and pay the
+continuous validator fee from it every second; the estimate shows that fee
+per day and how long the balance lasts at the current price.
+
+EXAMPLES:
+
+  lux fees estimate --op create-chain --genesis genesis.json
+  lux fees estimate --op convert-l1 --validators 5 --balance 10 --network testnet`,
+		Args:         cobra.NoArgs,
+		RunE:         runEstimate,
+		SilenceUsage: true,
+		Annotations:  map[string]string{config.CapabilityAnnotation: config.CapabilityNone},
+	}
+	cmd.Flags().StringVar(&estimateOp, "op", "", "Operation to estimate: create-chain, convert-l1 or add-validator (required)")
+	cmd.Flags().IntVar(&estimateValidators, "validators", 1, "Number of L1 validators converted or added")
+	cmd.Flags().Float64Var(
+		&estimateBalance,
+		"balance",
+		float64(constants.BootstrapValidatorBalanceNanoLUX)/float64(constants.Lux),
+		"Balance of each L1 validator in LUX",
+	)
+	cmd.Flags().StringVar(&estimateGenesisPath, "genesis", "", fmt.Sprintf("Genesis file of the chain created (default: a %d byte genesis)", feeestimate.DefaultGenesisSize))
+	_ = cmd.MarkFlagRequired("op")
+	return cmd
+}
+
+func runEstimate(_ *cobra.Command, _ []string) error {
+	op := feeestimate.Op(estimateOp)
+	if !slices.Contains(feeestimate.Ops, op) {
+		return fmt.Errorf("unknown operation %q, use create-chain, convert-l1 or add-validator", estimateOp)
+	}
+	if estimateBalance < 0 || estimateBalance*float64(constants.Lux) > math.MaxUint64 {
+		return fmt.Errorf("invalid balance %g", estimateBalance)
+	}
+	opts := feeestimate.Options{
+		Validators: estimateValidators,
+		Balance:    uint64(estimateBalance * float64(constants.Lux)),
+	}
+	if estimateGenesisPath != "" {
+		info, err := os.Stat(estimateGenesisPath)
+		if err != nil {
+			return err
+		}
+		opts.GenesisSize = int(info.Size())
+	}
+	name, endpoint, err := resolveEndpoint()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	state, err := feeestimate.Fetch(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	e, err := state.Estimate(op, opts)
+	if err != nil {
+		return err
+	}
+
+	ux.Logger.PrintToUser("Network:   %s (%s)", name, endpoint)
+	ux.Logger.PrintToUser("Gas price: %d nLUX", state.GasPrice)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Transaction", "Count", "Fee Each", "Fee")
+	for _, tx := range e.Txs {
+		_ = table.Append([]string{
+			tx.Type,
+			fmt.Sprintf("%d", tx.Count),
+			status.FormatNLUXToLUX(tx.Fee),
+			status.FormatNLUXToLUX(uint64(tx.Count) * tx.Fee),
+		})
+	}
+	_ = table.Render()
+	ux.Logger.PrintToUser("Total fee:          %s", status.FormatNLUXToLUX(e.TotalFee()))
+	if e.Validators > 0 {
+		ux.Logger.PrintToUser("Validator balance:  %s each, %s for %d validator(s)",
+			status.FormatNLUXToLUX(e.Balance), status.FormatNLUXToLUX(uint64(e.Validators)*e.Balance), e.Validators)
+		ux.Logger.PrintToUser("Continuous fee:     %s per validator per day (%d nLUX per second)",
+			status.FormatNLUXToLUX(e.ValidatorFeePerDay), state.ValidatorFeePrice)
+		if lasts := e.BalanceLasts(); lasts > 0 {
+			ux.Logger.PrintToUser("Balance lasts:      %.1f days at the current price", lasts.Hours()/24)
+		}
+	}
+	ux.Logger.PrintToUser("Required:           %s", status.FormatNLUXToLUX(e.Required()))
+	return nil
+}
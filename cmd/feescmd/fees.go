@@ -0,0 +1,85 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package feescmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/sdk/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	app *application.Lux
+
+	networkType string
+	rpcURL      string
+)
+
+// NewCmd creates the fees command
+func NewCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "fees",
+		Short: "Estimate P-Chain fees and L1 validator balances",
+		Long: `The fees command estimates what P-Chain operations cost from the live
+dynamic fee state of the network: the fees of their transactions, the
+balance each L1 validator locks, and the continuous fee L1 validators pay
+from that balance.
+
+The network is the running local network unless --network is given.
+
+EXAMPLES:
+
+  # Fees of creating a chain on mainnet
+  lux fees estimate --op create-chain --network mainnet
+
+  # Fees and balances of converting it to an L1 with 5 validators
+  lux fees estimate --op convert-l1 --validators 5 --network mainnet`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.PersistentFlags().StringVar(&networkType, "network", "", "Network type: custom, devnet, testnet or mainnet (default: the running network, else mainnet)")
+	cmd.PersistentFlags().StringVar(&rpcURL, "rpc-url", "", "Base URL of the node to query (default: the network endpoint)")
+	cmd.AddCommand(newEstimateCmd())
+	return cmd
+}
+
+// resolveEndpoint returns the endpoint of the network of --network, served
+// by the running local network when it is of that type
+func resolveEndpoint() (string, string, error) {
+	name := networkType
+	if name == "" {
+		if name = app.GetRunningNetworkType(); name == "" {
+			name = "mainnet"
+		}
+	}
+	var network models.Network
+	switch name {
+	case "mainnet":
+		network = models.Mainnet
+	case "testnet":
+		network = models.Testnet
+	case "devnet":
+		network = models.Devnet
+	case "custom", "local":
+		network = models.Local
+	default:
+		return "", "", fmt.Errorf("unknown network %q, use custom, devnet, testnet or mainnet", name)
+	}
+	endpoint := network.Endpoint()
+	if state, err := app.LoadNetworkStateForType(name); err == nil && state != nil && state.Running && state.APIEndpoint != "" {
+		endpoint = state.APIEndpoint
+	}
+	if rpcURL != "" {
+		endpoint = rpcURL
+	}
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if endpoint == "" {
+		return "", "", fmt.Errorf("no endpoint for %s, start the network or pass --rpc-url", name)
+	}
+	return name, endpoint, nil
+}
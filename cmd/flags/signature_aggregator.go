@@ -5,6 +5,7 @@ package flags
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/pflag"
 
@@ -14,13 +15,23 @@ import (
 )
 
 const (
-	aggregatorLogLevelFlag    = "aggregator-log-level"
-	aggregatorLogToStdoutFlag = "aggregator-log-to-stdout"
+	aggregatorLogLevelFlag       = "aggregator-log-level"
+	aggregatorLogToStdoutFlag    = "aggregator-log-to-stdout"
+	aggregatorExtraEndpointsFlag = "aggregator-extra-endpoints"
+	aggregatorTimeoutFlag        = "aggregator-timeout"
+
+	// DefaultAggregatorTimeout bounds an operation waiting on signatures
+	DefaultAggregatorTimeout = 5 * time.Minute
 )
 
 type SignatureAggregatorFlags struct {
 	AggregatorLogLevel    string
 	AggregatorLogToStdout bool
+	// AggregatorExtraEndpoints are node APIs the aggregator reaches
+	// validators through, besides the nodes of the network
+	AggregatorExtraEndpoints []string
+	// AggregatorTimeout bounds the wait for the signatures of an operation
+	AggregatorTimeout time.Duration
 }
 
 func validateSignatureAggregatorFlags(sigAggFlags SignatureAggregatorFlags) error {
@@ -61,5 +72,7 @@ func AddSignatureAggregatorFlagsToCmd(cmd *cobra.Command, sigAggFlags *Signature
 			return sigAggPreRun(cmd, args)
 		}
 		set.BoolVar(&sigAggFlags.AggregatorLogToStdout, aggregatorLogToStdoutFlag, false, "use stdout for signature aggregator logs")
+		set.StringSliceVar(&sigAggFlags.AggregatorExtraEndpoints, aggregatorExtraEndpointsFlag, nil, "API URIs of more validator nodes for the signature aggregator to reach")
+		set.DurationVar(&sigAggFlags.AggregatorTimeout, aggregatorTimeoutFlag, DefaultAggregatorTimeout, "time limit of signature aggregation, 0 for none")
 	})
 }
@@ -0,0 +1,148 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package historycmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	app *application.Lux
+
+	operation    string
+	networkName  string
+	chainName    string
+	since        string
+	limit        int
+	exportFile   string
+	exportFormat string
+)
+
+// NewCmd creates the history command
+func NewCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show the changelog of deploys, validator changes, upgrades and restores",
+		Long: `The history command shows the local changelog of state-changing
+operations: chain deploys, validator changes, upgrade applications and
+snapshot restores. Each entry records when it ran, who ran it, the command
+line, its parameters (such as the genesis SHA-256 of a deploy) and the
+resulting IDs.
+
+The changelog is append-only and lives at ~/.lux/history.jsonl.
+
+EXAMPLES:
+
+  # Latest operations
+  lux history
+
+  # Deploys of a chain
+  lux history --operation "chain deploy" --chain mychain
+
+  # Everything on devnet in the last week
+  lux history --network devnet --since 168h
+
+  # Export the full changelog
+  lux history --export history.csv --format csv`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: showHistory,
+	}
+	cmd.Flags().StringVar(&operation, "operation", "", "only show operations starting with this, e.g. chain or \"validator add\"")
+	cmd.Flags().StringVar(&networkName, "network", "", "only show operations on this network")
+	cmd.Flags().StringVar(&chainName, "chain", "", "only show operations on this chain")
+	cmd.Flags().StringVar(&since, "since", "", "only show operations since a duration ago (24h) or a date (2006-01-02 or RFC3339)")
+	cmd.Flags().IntVar(&limit, "limit", 50, "show at most this many of the latest operations (0 for all)")
+	cmd.Flags().StringVar(&exportFile, "export", "", "write the selected operations to this file (- for stdout) instead of a table")
+	cmd.Flags().StringVar(&exportFormat, "format", "json", "export format: json or csv")
+	return cmd
+}
+
+func showHistory(_ *cobra.Command, _ []string) error {
+	entries, err := history.Load(app.GetHistoryPath())
+	if err != nil {
+		return err
+	}
+	filter := history.Filter{
+		Operation: operation,
+		Network:   networkName,
+		Chain:     chainName,
+	}
+	if since != "" {
+		if filter.Since, err = parseSince(since, time.Now()); err != nil {
+			return err
+		}
+	}
+	entries = history.Select(entries, filter)
+
+	if exportFile != "" {
+		return export(entries)
+	}
+
+	if len(entries) == 0 {
+		ux.Logger.PrintToUser("No recorded operations")
+		return nil
+	}
+	if limit > 0 && len(entries) > limit {
+		ux.Logger.PrintToUser("Showing the latest %d of %d operations (use --limit 0 for all)", limit, len(entries))
+		entries = entries[len(entries)-limit:]
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Time", "User", "Operation", "Network", "Chain", "Details")
+	for _, e := range entries {
+		details := strings.TrimSpace(history.FormatFields(e.Params) + " " + history.FormatFields(e.Result))
+		_ = table.Append([]string{
+			e.Time.Local().Format(constants.TimeParseLayout),
+			e.User,
+			e.Operation,
+			e.Network,
+			e.Chain,
+			details,
+		})
+	}
+	return table.Render()
+}
+
+func export(entries []history.Entry) error {
+	if exportFile == "-" {
+		return history.Export(os.Stdout, entries, exportFormat)
+	}
+	f, err := os.Create(exportFile) //nolint:gosec // G304: User-specified export file
+	if err != nil {
+		return err
+	}
+	if err := history.Export(f, entries, exportFormat); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Exported %d operations to %s", len(entries), exportFile)
+	return nil
+}
+
+// parseSince parses a duration before now or a date
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: expected a duration like 24h or a date like 2006-01-02", value)
+}
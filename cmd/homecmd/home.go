@@ -0,0 +1,216 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package homecmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/config"
+	"github.com/luxfi/cli/pkg/home"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	app *application.Lux
+
+	force bool
+)
+
+// NewCmd creates the home command
+func NewCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "home",
+		Short: "Manage isolated CLI homes, one per project",
+		Long: `A CLI home holds keys, chains, clusters, local network runs, snapshots,
+history and the lock of mutating commands. By default every command uses
+~/.lux; named homes in ~/.lux/homes/<name> keep projects apart, so they
+don't share keys or collide on network state.
+
+The home of a command is, in order: --home, LUX_HOME, the home selected
+with 'lux home switch', or ~/.lux. --home and LUX_HOME take a home name,
+created on first use, or a directory. Installed binaries and the cli.json
+config are shared by all homes.
+
+EXAMPLES:
+
+  # Create a home and make it the default
+  lux home create project-a
+  lux home switch project-a
+
+  # Run one command in another home
+  lux --home project-b network start --devnet
+  LUX_HOME=./.lux-home lux chain list
+
+  # Which home is in use, and all of them
+  lux home current
+  lux home list
+
+  # Back to ~/.lux
+  lux home switch default`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newCurrentCmd())
+	cmd.AddCommand(newCreateCmd())
+	cmd.AddCommand(newSwitchCmd())
+	cmd.AddCommand(newRemoveCmd())
+	return cmd
+}
+
+func newListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the CLI homes",
+		Args:  cobrautils.ExactArgs(0),
+		RunE:  listHomes,
+	}
+}
+
+func listHomes(_ *cobra.Command, _ []string) error {
+	root, err := home.Root()
+	if err != nil {
+		return err
+	}
+	names, err := home.List(root)
+	if err != nil {
+		return err
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("", "Name", "Directory")
+	inUse := false
+	for _, name := range names {
+		mark := ""
+		if home.Dir(root, name) == app.GetBaseDir() {
+			mark, inUse = "*", true
+		}
+		_ = table.Append([]string{mark, name, home.Dir(root, name)})
+	}
+	if !inUse {
+		_ = table.Append([]string{"*", "", app.GetBaseDir()})
+	}
+	return table.Render()
+}
+
+func newCurrentCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:         "current",
+		Short:       "Show the CLI home in use",
+		Args:        cobrautils.ExactArgs(0),
+		RunE:        currentHome,
+		Annotations: map[string]string{config.CapabilityAnnotation: config.CapabilityNone},
+	}
+}
+
+func currentHome(_ *cobra.Command, _ []string) error {
+	name := app.Home.Name
+	if name == "" {
+		name = "(directory)"
+	}
+	ux.Logger.PrintToUser("%s: %s", name, app.GetBaseDir())
+	switch app.Home.Source {
+	case home.SourceFlag:
+		ux.Logger.PrintToUser("Selected with --home")
+	case home.SourceEnv:
+		ux.Logger.PrintToUser("Selected with %s", home.EnvVar)
+	case home.SourceSwitch:
+		ux.Logger.PrintToUser("Selected with 'lux home switch'")
+	}
+	return nil
+}
+
+func newCreateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:         "create <name>",
+		Short:       "Create a CLI home",
+		Args:        cobrautils.ExactArgs(1),
+		RunE:        createHome,
+		Annotations: map[string]string{config.CapabilityAnnotation: string(config.CapabilityConfig)},
+	}
+}
+
+func createHome(_ *cobra.Command, args []string) error {
+	root, err := home.Root()
+	if err != nil {
+		return err
+	}
+	dir, err := home.Create(root, args[0])
+	if err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Created home %s in %s", args[0], dir)
+	ux.Logger.PrintToUser("Use it with 'lux home switch %s' or --home %s", args[0], args[0])
+	return nil
+}
+
+func newSwitchCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "switch <name>",
+		Short: "Make a CLI home the one used by default",
+		Long: `Make a CLI home the one used by commands run without --home or
+LUX_HOME. 'default' switches back to ~/.lux. Networks running in the home
+switched from keep running; switch back to stop them.`,
+		Args:        cobrautils.ExactArgs(1),
+		RunE:        switchHome,
+		Annotations: map[string]string{config.CapabilityAnnotation: string(config.CapabilityConfig)},
+	}
+}
+
+func switchHome(_ *cobra.Command, args []string) error {
+	root, err := home.Root()
+	if err != nil {
+		return err
+	}
+	if err := home.Switch(root, args[0]); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Switched to home %s (%s)", args[0], home.Dir(root, args[0]))
+	if app.Home.Source == home.SourceEnv {
+		ux.Logger.PrintToUser("%s is set in this shell and still selects %s", home.EnvVar, app.GetBaseDir())
+	}
+	return nil
+}
+
+func newRemoveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Delete a CLI home and everything in it",
+		Long: `Delete a named CLI home with its keys, chains, clusters and network
+runs. The default home and the one switched to can't be removed.`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: removeHome,
+	}
+	cmd.Flags().BoolVar(&force, "force", false, "remove without asking")
+	return cmd
+}
+
+func removeHome(_ *cobra.Command, args []string) error {
+	root, err := home.Root()
+	if err != nil {
+		return err
+	}
+	name := args[0]
+	if home.Dir(root, name) == app.GetBaseDir() {
+		return fmt.Errorf("home %s is in use by this command", name)
+	}
+	if !force {
+		ok, err := app.Prompt.CaptureYesNo(fmt.Sprintf("Delete home %s and all its keys in %s?", name, home.Dir(root, name)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+	}
+	if err := home.Remove(root, name); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Removed home %s", name)
+	return nil
+}
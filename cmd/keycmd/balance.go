@@ -0,0 +1,260 @@
+// Copyright (C) 2022-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keycmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luxfi/address"
+	"github.com/luxfi/cli/pkg/balances"
+	"github.com/luxfi/cli/pkg/key"
+	"github.com/luxfi/cli/pkg/status"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/ids"
+	sdkinfo "github.com/luxfi/sdk/info"
+	"github.com/luxfi/sdk/models"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	balanceNetwork   string
+	balanceRPCURL    string
+	balanceAllChains bool
+	balanceTimeout   time.Duration
+)
+
+func newBalanceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "balance <name|address|@label>",
+		Short: "Show the balances of a key or address across chains",
+		Long: `Show the native balances of a key set or an address on the P-Chain, X-Chain
+and C-Chain, queried concurrently.
+
+With --all-chains, the other native chains and every L1 deployed to the
+network are queried too, and the balances are totaled per token. Chains that
+cannot be reached are listed below the table.
+
+The argument is the name of a key set, a P-/X-Chain address, which only
+queries the P-Chain and X-Chain, or a 0x address, which only queries the EVM
+chains. Addresses can be given as the @label of the address book.
+
+Example:
+  lux key balance validator1
+  lux key balance validator1 --all-chains
+  lux key balance P-lux1... --network mainnet
+  lux key balance 0x9011E888251AB053B7bD1cdB598Db4f9DEd94714 --all-chains`,
+		Args: cobra.ExactArgs(1),
+		RunE: runBalance,
+	}
+
+	cmd.Flags().StringVar(&balanceNetwork, "network", "", "Network type: custom, devnet, testnet or mainnet (default: the running network)")
+	cmd.Flags().StringVar(&balanceRPCURL, "rpc-url", "", "Base URL of the node to query (default: the running network endpoint)")
+	cmd.Flags().BoolVar(&balanceAllChains, "all-chains", false, "Query all native chains and deployed L1s, and total the balances")
+	cmd.Flags().DurationVar(&balanceTimeout, "timeout", 10*time.Second, "Timeout of the queries")
+
+	return cmd
+}
+
+func runBalance(_ *cobra.Command, args []string) error {
+	networkType, network, baseURL := resolveEndpoint(balanceNetwork, balanceRPCURL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), balanceTimeout)
+	defer cancel()
+
+	arg, err := app.ResolveAddress(args[0])
+	if err != nil {
+		return err
+	}
+	account, err := balanceAccount(ctx, arg, baseURL, network)
+	if err != nil {
+		return err
+	}
+	chains := balanceChains(baseURL, network)
+	results := balances.Query(ctx, &http.Client{Timeout: balanceTimeout}, chains, account)
+
+	ux.Logger.PrintToUser("Balances of %s on %s (%s)", args[0], networkType, baseURL)
+	return renderBalances(results)
+}
+
+// resolveEndpoint returns the network of networkType, the running network
+// by default, and the base URL of its node: rpcURL, the endpoint of the
+// running network or the public endpoint
+func resolveEndpoint(networkType, rpcURL string) (string, models.Network, string) {
+	if networkType == "" {
+		if networkType = app.GetRunningNetworkType(); networkType == "" {
+			networkType = "custom"
+		}
+	}
+	network := balanceSDKNetwork(networkType)
+	baseURL := rpcURL
+	if baseURL == "" {
+		baseURL = network.Endpoint()
+		if state, err := app.LoadNetworkStateForType(networkType); err == nil && state != nil && state.Running && state.APIEndpoint != "" {
+			baseURL = state.APIEndpoint
+		}
+	}
+	return networkType, network, strings.TrimSuffix(baseURL, "/")
+}
+
+// renderBalances prints the balances queried in a table, totaled with
+// --all-chains, and the chains that could not be queried below it
+func renderBalances(results []balances.Balance) error {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Chain", "Token", "Balance", "Address")
+	var unreachable []string
+	book, bookErr := app.LoadAddressBook()
+	for _, b := range results {
+		if b.Err != nil {
+			unreachable = append(unreachable, fmt.Sprintf("%s (%s)", b.Chain.Name, b.Err))
+			continue
+		}
+		addr := b.Address
+		if bookErr == nil {
+			addr = book.Annotate(addr)
+		}
+		_ = table.Append([]string{b.Chain.Name, b.Chain.Symbol, balances.Format(b.Amount, b.Chain.Kind.Decimals()), addr})
+	}
+	if balanceAllChains {
+		for _, t := range balances.Totals(results) {
+			_ = table.Append([]string{fmt.Sprintf("Total (%d chains)", t.Chains), t.Symbol, balances.Format(t.Amount, t.Decimals), ""})
+		}
+	}
+	if err := table.Render(); err != nil {
+		return err
+	}
+	if len(unreachable) > 0 {
+		ux.Logger.PrintToUser("Not queried: %s", strings.Join(unreachable, ", "))
+	}
+	return nil
+}
+
+// balanceAccount resolves arg, a key set name or an address, to the
+// addresses to query
+func balanceAccount(ctx context.Context, arg, baseURL string, network models.Network) (balances.Account, error) {
+	if strings.HasPrefix(arg, "0x") && len(arg) == 42 {
+		return balances.Account{EVM: arg}, nil
+	}
+	if _, hrp, addr, err := address.Parse(arg); err == nil {
+		return bech32Account(hrp, addr)
+	}
+	if hrp, addr, err := address.ParseBech32(arg); err == nil {
+		return bech32Account(hrp, addr)
+	}
+
+	keySet, err := key.LoadKeySet(arg)
+	if err != nil {
+		return balances.Account{}, fmt.Errorf("'%s' is not an address, and failed to load key '%s': %w", arg, arg, err)
+	}
+	if len(keySet.ECPrivateKey) == 0 {
+		return balances.Account{}, fmt.Errorf("key '%s' has no EC private key", arg)
+	}
+	networkID := network.ID()
+	if id, err := sdkinfo.NewClient(baseURL).GetNetworkID(ctx); err == nil {
+		networkID = id
+	}
+	sk, err := key.NewSoftFromBytes(networkID, keySet.ECPrivateKey)
+	if err != nil {
+		return balances.Account{}, err
+	}
+	account, err := bech32Account(key.GetHRP(networkID), sk.Key().PublicKey().Address().Bytes())
+	if err != nil {
+		return balances.Account{}, err
+	}
+	account.EVM = sk.C()
+	return account, nil
+}
+
+func bech32Account(hrp string, addr []byte) (balances.Account, error) {
+	pAddr, err := address.Format("P", hrp, addr)
+	if err != nil {
+		return balances.Account{}, err
+	}
+	xAddr, err := address.Format("X", hrp, addr)
+	if err != nil {
+		return balances.Account{}, err
+	}
+	return balances.Account{P: pAddr, X: xAddr}, nil
+}
+
+// balanceChains returns the chains to query: the P-Chain, X-Chain and
+// C-Chain, or with --all-chains every native chain and the L1s deployed to
+// network
+func balanceChains(baseURL string, network models.Network) []balances.Chain {
+	var chains []balances.Chain
+	for _, ep := range status.NativeChainEndpoints(baseURL) {
+		c := balances.Chain{
+			Name:   strings.ToUpper(ep.ChainAlias),
+			Kind:   balances.EVM,
+			URL:    ep.URL,
+			Symbol: balances.NativeSymbol,
+		}
+		switch ep.ChainAlias {
+		case "p":
+			c.Kind = balances.PChain
+		case "x":
+			c.Kind = balances.XChain
+		case "c":
+		default:
+			if !balanceAllChains {
+				continue
+			}
+		}
+		chains = append(chains, c)
+	}
+	if !balanceAllChains {
+		return chains
+	}
+
+	names, err := app.GetSidecarNames()
+	if err != nil {
+		ux.Logger.PrintToUser("Failed to list the deployed chains: %s", err)
+		return chains
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sc, err := app.LoadSidecar(name)
+		if err != nil || sc.VM != models.EVM {
+			continue
+		}
+		deployment, ok := sc.Networks[network.Name()]
+		if !ok {
+			continue
+		}
+		url := ""
+		switch {
+		case len(deployment.RPCEndpoints) > 0:
+			url = deployment.RPCEndpoints[0]
+		case deployment.BlockchainID != ids.Empty:
+			url = models.GetRPCEndpoint(baseURL, deployment.BlockchainID.String())
+		default:
+			continue
+		}
+		symbol := sc.TokenSymbol
+		if symbol == "" {
+			symbol = name
+		}
+		chains = append(chains, balances.Chain{Name: name, Kind: balances.EVM, URL: url, Symbol: symbol})
+	}
+	return chains
+}
+
+func balanceSDKNetwork(networkType string) models.Network {
+	switch networkType {
+	case "mainnet":
+		return models.Mainnet
+	case "testnet":
+		return models.Testnet
+	case "devnet":
+		return models.Devnet
+	default:
+		return models.Local
+	}
+}
@@ -8,15 +8,22 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/luxfi/cli/pkg/key"
+	"github.com/luxfi/cli/pkg/models"
+	"github.com/luxfi/cli/pkg/prompts"
+	"github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/geth/accounts/keystore"
 	"github.com/spf13/cobra"
 )
 
 var (
 	exportMnemonic bool
 	exportOutput   string
+	exportFormat   string
+	exportPassword string
 )
 
 func newExportCmd() *cobra.Command {
@@ -27,18 +34,36 @@ func newExportCmd() *cobra.Command {
 
 By default, exports public keys. Use --mnemonic to export the seed phrase.
 
-WARNING: Exporting the mnemonic exposes your private keys!
+Use --format to export the EC private key in a standard format other
+wallets and tools accept:
+  hex       0x prefixed hex, as MetaMask imports it
+  keystore  Ethereum keystore v3 JSON, encrypted with a password
+  pem       SEC 1 "EC PRIVATE KEY" PEM on secp256k1, as openssl writes it
+  mnemonic  the seed phrase alone (key sets only)
+
+The private key is taken from the soft key ~/.lux/keys/<name>.pk used by
+--key flags if it exists, otherwise from the key set ~/.lux/keys/<name>/.
+
+The keystore password can be provided via --password, the KEY_PASSWORD
+environment variable or an interactive prompt.
+
+WARNING: Exporting the mnemonic or private key exposes your private keys!
 
 Examples:
   lux key export validator1                    # Export public keys
   lux key export validator1 --mnemonic         # Export mnemonic (DANGER!)
-  lux key export validator1 -o keys.json       # Export to file`,
+  lux key export validator1 -o keys.json       # Export to file
+  lux key export validator1 --format hex       # Private key for MetaMask (DANGER!)
+  lux key export validator1 --format keystore -o validator1.json
+  lux key export validator1 --format pem -o validator1.pem`,
 		Args: cobra.ExactArgs(1),
 		RunE: runExport,
 	}
 
 	cmd.Flags().BoolVar(&exportMnemonic, "mnemonic", false, "Export mnemonic phrase (DANGEROUS!)")
 	cmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Output file (default: stdout)")
+	cmd.Flags().StringVar(&exportFormat, "format", "", "Export the private key as hex, keystore, pem or mnemonic")
+	cmd.Flags().StringVarP(&exportPassword, "password", "p", "", "Password encrypting the keystore (--format keystore)")
 
 	return cmd
 }
@@ -46,6 +71,10 @@ Examples:
 func runExport(_ *cobra.Command, args []string) error {
 	name := args[0]
 
+	if exportFormat != "" {
+		return exportPrivateKey(name)
+	}
+
 	keySet, err := key.LoadKeySet(name)
 	if err != nil {
 		return fmt.Errorf("failed to load key set '%s': %w", name, err)
@@ -104,6 +133,10 @@ func runExport(_ *cobra.Command, args []string) error {
 		)
 	}
 
+	return writeExport(output)
+}
+
+func writeExport(output string) error {
 	if exportOutput != "" {
 		if err := os.WriteFile(exportOutput, []byte(output), 0o600); err != nil {
 			return fmt.Errorf("failed to write output file: %w", err)
@@ -115,3 +148,96 @@ func runExport(_ *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// exportPrivateKey exports the EC private key of name in exportFormat
+func exportPrivateKey(name string) error {
+	if exportFormat == key.FormatMnemonic {
+		keysDir, err := key.GetKeysDir()
+		if err != nil {
+			return fmt.Errorf("failed to get keys directory: %w", err)
+		}
+		data, err := os.ReadFile(filepath.Join(keysDir, name, key.MnemonicFile)) //nolint:gosec // G304: Reading from user's key directory
+		if err != nil {
+			return fmt.Errorf("failed to read mnemonic of key set '%s': %w", name, err)
+		}
+		return writeExport(strings.TrimSpace(string(data)))
+	}
+
+	privKey, err := loadPrivateKey(name)
+	if err != nil {
+		return err
+	}
+
+	var output string
+	switch exportFormat {
+	case key.FormatHex:
+		output = key.EncodeHex(privKey)
+	case key.FormatKeystore:
+		password, err := formatPassword(exportPassword)
+		if err != nil {
+			return err
+		}
+		data, err := key.EncodeKeystore(privKey, password, keystore.StandardScryptN, keystore.StandardScryptP)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt keystore: %w", err)
+		}
+		output = string(data)
+	case key.FormatPEM:
+		data, err := key.EncodePEM(privKey)
+		if err != nil {
+			return fmt.Errorf("failed to encode PEM: %w", err)
+		}
+		output = strings.TrimSpace(string(data))
+	default:
+		return fmt.Errorf("unsupported format %q: expected %s, %s, %s or %s",
+			exportFormat, key.FormatHex, key.FormatKeystore, key.FormatPEM, key.FormatMnemonic)
+	}
+
+	if exportOutput == "" && exportFormat != key.FormatKeystore {
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("WARNING: This exposes your private key!")
+		ux.Logger.PrintToUser("")
+	}
+	return writeExport(output)
+}
+
+// loadPrivateKey loads the EC private key of the soft key name, falling
+// back to the key set name
+func loadPrivateKey(name string) ([]byte, error) {
+	keyPath := app.GetKeyPath(name)
+	if utils.FileExists(keyPath) {
+		sk, err := key.LoadSoft(models.NewLocalNetwork().ID(), keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load key '%s': %w", name, err)
+		}
+		return sk.Raw(), nil
+	}
+	keySet, err := key.LoadKeySet(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load key set '%s': %w", name, err)
+	}
+	return keySet.ECPrivateKey, nil
+}
+
+// formatPassword returns the keystore password from the flag, the
+// KEY_PASSWORD environment variable or a prompt
+func formatPassword(flagPassword string) (string, error) {
+	password := flagPassword
+	if password == "" {
+		password = key.GetPasswordFromEnv()
+	}
+	if password == "" {
+		if !prompts.IsInteractive() {
+			return "", fmt.Errorf("keystore password required: use --password or set KEY_PASSWORD environment variable")
+		}
+		var err error
+		password, err = app.Prompt.CaptureString("Keystore password")
+		if err != nil {
+			return "", fmt.Errorf("failed to read password: %w", err)
+		}
+	}
+	if password == "" {
+		return "", fmt.Errorf("keystore password required")
+	}
+	return password, nil
+}
@@ -5,33 +5,71 @@ package keycmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/luxfi/cli/pkg/key"
+	"github.com/luxfi/cli/pkg/models"
+	"github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/cli/pkg/ux"
 	"github.com/spf13/cobra"
 )
 
+var (
+	importFormat   string
+	importFile     string
+	importPassword string
+	importCoinType string
+	importIndex    uint32
+)
+
 func newImportCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "import <name>",
-		Short: "Import key set from mnemonic",
+		Short: "Import key set from mnemonic, or a private key in a standard format",
 		Long: `Import a key set by recovering from a mnemonic phrase.
 
 Derives all key types (EC, BLS, Ringtail, ML-DSA) from the mnemonic.
 
-Example:
-  lux key import validator1`,
+Use --format to instead import a single EC private key exported by another
+wallet or tool, saved as the soft key ~/.lux/keys/<name>.pk that --key
+flags use:
+  hex       hex private key, with or without 0x prefix (MetaMask)
+  keystore  Ethereum keystore v3 JSON, decrypted with a password
+  pem       SEC 1 "EC PRIVATE KEY" or PKCS#8 "PRIVATE KEY" PEM on secp256k1
+  mnemonic  one account of a mnemonic, at m/44'/<coin type>'/0'/0/<index>
+
+The key is read from --file, or prompted for. The keystore password can be
+provided via --password, the KEY_PASSWORD environment variable or an
+interactive prompt.
+
+Examples:
+  lux key import validator1                                  # Key set from mnemonic
+  lux key import deployer --format hex                       # Paste a MetaMask private key
+  lux key import deployer --format keystore --file UTC--2024-...
+  lux key import deployer --format pem --file deployer.pem
+  lux key import deployer --format mnemonic --index 2        # Third MetaMask account
+  lux key import deployer --format mnemonic --coin-type lux  # Lux wallet account`,
 		Args: cobra.ExactArgs(1),
 		RunE: runImport,
 	}
 
+	cmd.Flags().StringVar(&importFormat, "format", "", "Import a private key as hex, keystore, pem or mnemonic")
+	cmd.Flags().StringVarP(&importFile, "file", "f", "", "Read the key from this file instead of prompting")
+	cmd.Flags().StringVarP(&importPassword, "password", "p", "", "Password decrypting the keystore (--format keystore)")
+	cmd.Flags().StringVar(&importCoinType, "coin-type", "eth", "BIP-44 coin type of the account, eth (60) or lux (9000) (--format mnemonic)")
+	cmd.Flags().Uint32Var(&importIndex, "index", 0, "Account index (--format mnemonic)")
+
 	return cmd
 }
 
 func runImport(_ *cobra.Command, args []string) error {
 	name := args[0]
 
+	if importFormat != "" {
+		return importPrivateKey(name)
+	}
+
 	// Check if key set already exists
 	existing, err := key.ListKeySets()
 	if err != nil {
@@ -74,3 +112,110 @@ func runImport(_ *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// importPrivateKey imports a private key in importFormat as the soft key name
+func importPrivateKey(name string) error {
+	keyPath := app.GetKeyPath(name)
+	if utils.FileExists(keyPath) {
+		return fmt.Errorf("key '%s' already exists at %s", name, keyPath)
+	}
+
+	var privKey []byte
+	switch importFormat {
+	case key.FormatHex:
+		input, err := readImportInput("Private key")
+		if err != nil {
+			return err
+		}
+		privKey, err = key.DecodeHex(string(input))
+		if err != nil {
+			return err
+		}
+	case key.FormatKeystore:
+		if importFile == "" {
+			return fmt.Errorf("--file is required for keystore import")
+		}
+		input, err := readImportInput("")
+		if err != nil {
+			return err
+		}
+		password, err := formatPassword(importPassword)
+		if err != nil {
+			return err
+		}
+		privKey, err = key.DecodeKeystore(input, password)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt keystore: %w", err)
+		}
+	case key.FormatPEM:
+		if importFile == "" {
+			return fmt.Errorf("--file is required for PEM import")
+		}
+		input, err := readImportInput("")
+		if err != nil {
+			return err
+		}
+		privKey, err = key.DecodePEM(input)
+		if err != nil {
+			return err
+		}
+	case key.FormatMnemonic:
+		var coinType uint32
+		switch strings.ToLower(importCoinType) {
+		case "eth", "60":
+			coinType = key.EthCoinType
+		case "lux", "9000":
+			coinType = key.LuxCoinType
+		default:
+			return fmt.Errorf("unsupported coin type %q: expected eth or lux", importCoinType)
+		}
+		input, err := readImportInput("Mnemonic")
+		if err != nil {
+			return err
+		}
+		mnemonic := strings.TrimSpace(string(input))
+		if !key.ValidateMnemonic(mnemonic) {
+			return fmt.Errorf("invalid mnemonic phrase")
+		}
+		privKey, err = key.DeriveMnemonicPrivateKey(mnemonic, coinType, importIndex)
+		if err != nil {
+			return fmt.Errorf("failed to derive key: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported format %q: expected %s, %s, %s or %s",
+			importFormat, key.FormatHex, key.FormatKeystore, key.FormatPEM, key.FormatMnemonic)
+	}
+
+	sk, err := key.NewSoftFromBytes(models.NewLocalNetwork().ID(), privKey)
+	if err != nil {
+		return err
+	}
+	if err := sk.Save(keyPath); err != nil {
+		return fmt.Errorf("failed to save key: %w", err)
+	}
+
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Key '%s' imported successfully!", name)
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("C-Chain Address: %s", sk.C())
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Use --key %s to sign with it.", name)
+
+	return nil
+}
+
+// readImportInput reads the key from --file, or prompts for it
+func readImportInput(prompt string) ([]byte, error) {
+	if importFile != "" {
+		data, err := os.ReadFile(importFile) //nolint:gosec // G304: User-specified key file
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", importFile, err)
+		}
+		return data, nil
+	}
+	input, err := app.Prompt.CaptureString(prompt)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(input), nil
+}
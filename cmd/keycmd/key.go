@@ -18,9 +18,11 @@ var app *application.Lux
 //   - lux key create <name>     - Generate new key set from mnemonic
 //   - lux key list              - List all key sets
 //   - lux key show <name>       - Show key set details and addresses
+//   - lux key balance <name>    - Show balances across chains
+//   - lux key transfer          - Move LUX between the P-Chain, X-Chain and C-Chain
 //   - lux key delete <name>     - Delete a key set
-//   - lux key export <name>     - Export key set (mnemonic or individual keys)
-//   - lux key import <name>     - Import key set from mnemonic
+//   - lux key export <name>     - Export key set (mnemonic, or private key as hex, keystore or PEM)
+//   - lux key import <name>     - Import key set from mnemonic, or a hex, keystore or PEM private key
 //   - lux key lock [name]       - Lock a key (clear from memory)
 //   - lux key unlock <name>     - Unlock a key for use
 //   - lux key backend           - Manage key storage backends
@@ -53,8 +55,12 @@ Examples:
   lux key derive -n 5 --show             # Show derived addresses without saving
   lux key list                           # List all key sets
   lux key show validator1                # Show public keys and addresses
+  lux key balance validator1 --all-chains  # Balances on every chain, totaled
+  lux key transfer --from p --to c --amount 5 --key validator1  # P-Chain to C-Chain
   lux key delete validator1              # Delete key set
   lux key export validator1              # Export mnemonic (DANGER!)
+  lux key export validator1 --format keystore -o v1.json  # Keystore v3 for other wallets
+  lux key import deployer --format pem --file k.pem      # Import an openssl PEM key
   lux key lock validator1                # Lock key (clear from memory)
   lux key lock --all                     # Lock all keys
   lux key unlock validator1              # Unlock key for use
@@ -70,6 +76,8 @@ Examples:
 	cmd.AddCommand(newCreateCmd())
 	cmd.AddCommand(newListCmd())
 	cmd.AddCommand(newShowCmd())
+	cmd.AddCommand(newBalanceCmd())
+	cmd.AddCommand(newTransferCmd())
 	cmd.AddCommand(newDeleteCmd())
 	cmd.AddCommand(newExportCmd())
 	cmd.AddCommand(newImportCmd())
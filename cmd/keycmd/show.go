@@ -61,7 +61,7 @@ func runShow(_ *cobra.Command, args []string) error {
 
 	// EC key info
 	ux.Logger.PrintToUser("EC (secp256k1) - Transaction Signing:")
-	ux.Logger.PrintToUser("  Address:    %s", keySet.ECAddress)
+	ux.Logger.PrintToUser("  Address:    %s", app.AnnotateAddress(keySet.ECAddress))
 	ux.Logger.PrintToUser("  Public Key: %s", hex.EncodeToString(keySet.ECPublicKey))
 	if showExport && len(keySet.ECPrivateKey) > 0 {
 		ux.Logger.PrintToUser("  Private Key: 0x%s", hex.EncodeToString(keySet.ECPrivateKey))
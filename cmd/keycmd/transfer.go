@@ -0,0 +1,274 @@
+// Copyright (C) 2022-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keycmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/luxfi/address"
+	"github.com/luxfi/cli/pkg/balances"
+	"github.com/luxfi/cli/pkg/crosschain"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/key"
+	"github.com/luxfi/cli/pkg/keychain"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/xchain"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/ids"
+	sdkinfo "github.com/luxfi/sdk/info"
+	"github.com/luxfi/sdk/models"
+	"github.com/spf13/cobra"
+)
+
+// luxDenomination is the number of decimals of LUX on the P-Chain and
+// X-Chain, amounts being transferred in nLUX
+const luxDenomination = 9
+
+var (
+	transferFrom            string
+	transferTo              string
+	transferAmount          string
+	transferKey             string
+	transferRecipient       string
+	transferUseLedger       bool
+	transferLedgerAddresses []string
+	transferNetwork         string
+	transferRPCURL          string
+	transferTimeout         time.Duration
+)
+
+func newTransferCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transfer",
+		Short: "Move LUX between the P-Chain, X-Chain and C-Chain",
+		Long: `Move LUX between the chains of the primary network. An ExportTx on the
+--from chain puts the amount in the shared memory of the --to chain, then an
+ImportTx there spends it to the recipient, by default the address of the key.
+Each tx is waited for until accepted, and the balances of the key are shown
+once the LUX arrived. The fee of the import is paid from the amount.
+
+Transfers are signed with --key, a stored key or a kms://<keyId> key of the
+KMS server of LUX_KMS_ADDR, or with a ledger. Without either, MNEMONIC is
+used, and on local networks the dev key. On mainnet a ledger is used unless
+--key is given.
+
+A ledger signs the UTXOs of the P-Chain and X-Chain and the imports of the
+C-Chain, but not exports from the C-Chain, which are signed by an EVM key.
+Importing to the C-Chain with a ledger takes a --recipient 0x address.
+
+If the import fails, the exported LUX stays in the shared memory of the
+destination chain, and is imported by the next transfer to it.
+
+Example:
+  lux key transfer --from p --to c --amount 5 --key mykey
+  lux key transfer --from c --to x --amount 1.5 --key mykey --recipient X-lux1...
+  lux key transfer --from p --to c --amount 5 --key mykey --recipient @deployer
+  lux key transfer --from p --to x --amount 100 --ledger --network mainnet
+  lux key transfer --from x --to p --amount 10 --key kms://3f2a... --network mainnet`,
+		Args:         cobra.NoArgs,
+		RunE:         runTransfer,
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringVar(&transferFrom, "from", "", "Chain to export from: p, x or c")
+	cmd.Flags().StringVar(&transferTo, "to", "", "Chain to import to: p, x or c")
+	cmd.Flags().StringVar(&transferAmount, "amount", "", "Amount of LUX to transfer")
+	cmd.Flags().StringVar(&transferKey, "key", "", "Stored key or kms://<keyId> to sign with (default: MNEMONIC, or the dev key on local networks)")
+	cmd.Flags().StringVar(&transferRecipient, "recipient", "", "Address or @label receiving the LUX on the --to chain (default: the address of the key)")
+	cmd.Flags().BoolVarP(&transferUseLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet without --key)")
+	cmd.Flags().StringSliceVar(&transferLedgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	cmd.Flags().StringVar(&transferNetwork, "network", "", "Network type: custom, devnet, testnet or mainnet (default: the running network)")
+	cmd.Flags().StringVar(&transferRPCURL, "rpc-url", "", "Base URL of the node to issue to (default: the running network endpoint)")
+	cmd.Flags().DurationVar(&transferTimeout, "timeout", 2*time.Minute, "Timeout of the export and import")
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+	_ = cmd.MarkFlagRequired("amount")
+
+	return cmd
+}
+
+func runTransfer(_ *cobra.Command, _ []string) error {
+	from, err := crosschain.ParseChain(transferFrom)
+	if err != nil {
+		return err
+	}
+	to, err := crosschain.ParseChain(transferTo)
+	if err != nil {
+		return err
+	}
+	if from == to {
+		return fmt.Errorf("--from and --to are both the %s-Chain", from)
+	}
+	amount, err := xchain.ParseAmount(transferAmount, luxDenomination)
+	if err != nil {
+		return err
+	}
+	recipient, err := app.ResolveAddress(transferRecipient)
+	if err != nil {
+		return err
+	}
+	if transferKey != "" && (transferUseLedger || len(transferLedgerAddresses) > 0) {
+		return keychain.ErrMutuallyExlusiveKeySource
+	}
+
+	networkType, network, baseURL := resolveEndpoint(transferNetwork, transferRPCURL)
+	ctx, cancel := context.WithTimeout(context.Background(), transferTimeout)
+	defer cancel()
+	networkID := network.ID()
+	if id, err := sdkinfo.NewClient(baseURL).GetNetworkID(ctx); err == nil {
+		networkID = id
+	}
+
+	keys, account, err := transferKeys(network, networkID)
+	if err != nil {
+		return err
+	}
+	t := crosschain.Transfer{From: from, To: to, Amount: amount, Recipient: keys.Owner}
+	if to == crosschain.C {
+		switch {
+		case recipient != "":
+			if !common.IsHexAddress(recipient) {
+				return fmt.Errorf("invalid C-Chain address %q", recipient)
+			}
+			t.EVMRecipient = common.HexToAddress(recipient)
+		case account.EVM != "":
+			t.EVMRecipient = common.HexToAddress(account.EVM)
+		default:
+			return errors.New("importing to the C-Chain with a ledger takes a --recipient 0x address")
+		}
+		if account.EVM == "" {
+			account.EVM = t.EVMRecipient.Hex()
+		}
+	} else if recipient != "" {
+		if t.Recipient, err = recipientAddress(recipient); err != nil {
+			return err
+		}
+	}
+
+	ux.Logger.PrintToUser("Transferring %s LUX from the %s-Chain to the %s-Chain on %s (%s)",
+		xchain.FormatAmount(amount, luxDenomination), from, to, networkType, baseURL)
+	if recipient != "" {
+		ux.Logger.PrintToUser("  Recipient:   %s", app.AnnotateAddress(recipient))
+	}
+	result, err := crosschain.Execute(ctx, baseURL, keys, t)
+	if result.ExportTxID != ids.Empty {
+		ux.Logger.PrintToUser("  Export TxID: %s", result.ExportTxID)
+	}
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("  Import TxID: %s", result.ImportTxID)
+	ux.Logger.GreenCheckmarkToUser("Transferred %s LUX from the %s-Chain to the %s-Chain",
+		xchain.FormatAmount(amount, luxDenomination), from, to)
+	app.RecordHistory(history.Entry{
+		Operation: "key transfer",
+		Network:   network.String(),
+		Chain:     string(from) + "->" + string(to),
+		Params: map[string]string{
+			"amount": transferAmount,
+			"key":    transferKey,
+		},
+		Result: map[string]string{
+			"exportTxID": result.ExportTxID.String(),
+			"importTxID": result.ImportTxID.String(),
+		},
+	})
+
+	chains := balanceChains(baseURL, network)
+	results := balances.Query(ctx, &http.Client{Timeout: balanceTimeout}, chains, account)
+	ux.Logger.PrintToUser("Balances after the transfer")
+	return renderBalances(results)
+}
+
+// transferKeys returns the keys signing the transfer, from --key, a
+// ledger, MNEMONIC or the local dev key, and their account
+func transferKeys(network models.Network, networkID uint32) (crosschain.Keys, balances.Account, error) {
+	useLedger := transferUseLedger || len(transferLedgerAddresses) > 0 ||
+		(network == models.Mainnet && transferKey == "")
+	if useLedger {
+		kc, err := keychain.GetKeychain(app, false, true, transferLedgerAddresses, "", network, 0)
+		if err != nil {
+			return crosschain.Keys{}, balances.Account{}, err
+		}
+		// index 0 of the ledger, always loaded, owns the exported UTXOs
+		addrs, err := kc.Ledger.GetAddresses([]uint32{0})
+		if err != nil {
+			return crosschain.Keys{}, balances.Account{}, err
+		}
+		account, err := bech32Account(key.GetHRP(networkID), addrs[0].Bytes())
+		if err != nil {
+			return crosschain.Keys{}, balances.Account{}, err
+		}
+		return crosschain.Keys{Keychain: kc.Keychain, Owner: addrs[0]}, account, nil
+	}
+
+	if keychain.IsKMSKey(transferKey) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		kc, err := keychain.NewKMSKeychain(ctx, transferKey)
+		if err != nil {
+			return crosschain.Keys{}, balances.Account{}, err
+		}
+		owner := kc.Signer.Address()
+		account, err := bech32Account(key.GetHRP(networkID), owner.Bytes())
+		if err != nil {
+			return crosschain.Keys{}, balances.Account{}, err
+		}
+		account.EVM = kc.Signer.EthAddress().Hex()
+		return crosschain.Keys{Keychain: kc, Eth: kc, Owner: owner}, account, nil
+	}
+
+	var (
+		sk  *key.SoftKey
+		err error
+	)
+	switch {
+	case transferKey != "":
+		keySet, err := key.LoadKeySet(transferKey)
+		if err != nil {
+			return crosschain.Keys{}, balances.Account{}, fmt.Errorf("failed to load key '%s': %w", transferKey, err)
+		}
+		if len(keySet.ECPrivateKey) == 0 {
+			return crosschain.Keys{}, balances.Account{}, fmt.Errorf("key '%s' has no EC private key", transferKey)
+		}
+		sk, err = key.NewSoftFromBytes(networkID, keySet.ECPrivateKey)
+		if err != nil {
+			return crosschain.Keys{}, balances.Account{}, err
+		}
+	case key.GetMnemonicFromEnv() != "":
+		if sk, err = key.NewSoftFromMnemonic(networkID, key.GetMnemonicFromEnv()); err != nil {
+			return crosschain.Keys{}, balances.Account{}, err
+		}
+	case network == models.Local:
+		if sk, err = key.NewSoftFromMnemonic(networkID, key.GetLightMnemonic()); err != nil {
+			return crosschain.Keys{}, balances.Account{}, err
+		}
+	default:
+		return crosschain.Keys{}, balances.Account{}, fmt.Errorf("no key to sign with on %s, pass --key or --ledger, or set MNEMONIC", network.Name())
+	}
+	keys := crosschain.SoftKeys(sk)
+	account, err := bech32Account(key.GetHRP(networkID), keys.Owner.Bytes())
+	if err != nil {
+		return crosschain.Keys{}, balances.Account{}, err
+	}
+	account.EVM = sk.C()
+	return keys, account, nil
+}
+
+// recipientAddress parses a P-Chain or X-Chain address, with or without its
+// chain prefix
+func recipientAddress(s string) (ids.ShortID, error) {
+	if _, _, addr, err := address.Parse(s); err == nil {
+		return ids.ToShortID(addr)
+	}
+	_, addr, err := address.ParseBech32(s)
+	if err != nil {
+		return ids.ShortEmpty, fmt.Errorf("invalid address %q: %w", s, err)
+	}
+	return ids.ToShortID(addr)
+}
@@ -0,0 +1,146 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package kmscmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/kms"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	auditDataDir   string
+	auditKeyID     string
+	auditOperation string
+	auditSince     string
+	auditUntil     string
+	auditLimit     int
+	auditJSON      bool
+	auditVerify    bool
+)
+
+func newAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Query the KMS audit log",
+		Long: `Query the append-only KMS audit log.
+
+Every key generation, encryption, decryption, signature and secret access is
+recorded in <data-dir>/audit.jsonl. Each entry carries the hash of the previous
+one, so the log can be verified for tampering with --verify.
+
+Operations:
+  key.generate, key.delete, key.rotate, encrypt, decrypt, sign,
+  secret.create, secret.read, secret.update, secret.delete, secret.reencrypt
+
+Time filters accept RFC3339 timestamps or durations relative to now (e.g. 24h).
+
+A running server exposes the same data at GET /v1/audit.
+
+Examples:
+  lux kms audit
+  lux kms audit --key-id 3f2a... --operation sign
+  lux kms audit --since 24h --json
+  lux kms audit --verify`,
+		Args: cobra.NoArgs,
+		RunE: runAudit,
+	}
+
+	cmd.Flags().StringVar(&auditDataDir, "data-dir", "", "Data directory (default: ~/.lux/kms)")
+	cmd.Flags().StringVar(&auditKeyID, "key-id", "", "Only show events for this key ID")
+	cmd.Flags().StringVar(&auditOperation, "operation", "", "Only show events for this operation")
+	cmd.Flags().StringVar(&auditSince, "since", "", "Only show events at or after this time")
+	cmd.Flags().StringVar(&auditUntil, "until", "", "Only show events at or before this time")
+	cmd.Flags().IntVar(&auditLimit, "limit", 0, "Only show the most recent N events")
+	cmd.Flags().BoolVar(&auditJSON, "json", false, "Output events as JSON")
+	cmd.Flags().BoolVar(&auditVerify, "verify", false, "Verify the hash chain of the whole log")
+
+	return cmd
+}
+
+func runAudit(_ *cobra.Command, _ []string) error {
+	dataDir, err := resolveDataDir(auditDataDir)
+	if err != nil {
+		return err
+	}
+
+	events, err := kms.ReadAuditLog(filepath.Join(dataDir, kms.AuditFileName))
+	if err != nil {
+		return err
+	}
+
+	if auditVerify {
+		if err := kms.VerifyAuditChain(events); err != nil {
+			return err
+		}
+		ux.Logger.GreenCheckmarkToUser("Audit log verified: %d events, hash chain intact", len(events))
+		return nil
+	}
+
+	filter := kms.AuditFilter{
+		KeyID:     auditKeyID,
+		Operation: kms.AuditOperation(auditOperation),
+		Limit:     auditLimit,
+	}
+	if filter.Since, err = parseAuditTime(auditSince); err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	if filter.Until, err = parseAuditTime(auditUntil); err != nil {
+		return fmt.Errorf("invalid --until: %w", err)
+	}
+
+	events = kms.FilterAuditEvents(events, filter)
+
+	if auditJSON {
+		data, err := json.MarshalIndent(events, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(events) == 0 {
+		ux.Logger.PrintToUser("No audit events found")
+		return nil
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Seq", "Time", "Operation", "Key ID", "Secret ID", "Result")
+	for _, e := range events {
+		result := "ok"
+		if !e.Success {
+			result = "error: " + e.Error
+		}
+		_ = table.Append([]string{
+			fmt.Sprintf("%d", e.Seq),
+			e.Time.Local().Format(time.RFC3339),
+			string(e.Operation),
+			e.KeyID,
+			e.SecretID,
+			result,
+		})
+	}
+	return table.Render()
+}
+
+// parseAuditTime parses an RFC3339 timestamp or a duration before now.
+func parseAuditTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -24,6 +24,10 @@ var (
 	serverDataDir string
 	serverAPIKey  string
 	serverInMem   bool
+	serverTLSCert string
+	serverTLSKey  string
+	serverTLSAuto bool
+	serverCAFile  string
 
 	// Key flags
 	keyName        string
@@ -47,7 +51,7 @@ func NewCmd() *cobra.Command {
 		Long: `Key Management Service (KMS) for managing cryptographic keys and secrets.
 
 The KMS provides:
-  - Key generation (AES-256, RSA, ECDSA, Ed25519)
+  - Key generation (AES-256, RSA, ECDSA, Ed25519, secp256k1)
   - Encryption/decryption operations
   - Digital signatures
   - Secret management
@@ -80,12 +84,14 @@ API:
 Available subcommands:
   server  - Manage the KMS server
   key     - Key management operations
-  secret  - Secret management operations`,
+  secret  - Secret management operations
+  audit   - Query the audit log`,
 	}
 
 	cmd.AddCommand(newServerCmd())
 	cmd.AddCommand(newKeyCmd())
 	cmd.AddCommand(newSecretCmd())
+	cmd.AddCommand(newAuditCmd())
 
 	return cmd
 }
@@ -123,7 +129,13 @@ Examples:
   lux kms server start --in-memory
 
   # Start with API key authentication
-  lux kms server start --api-key your-secret-key`,
+  lux kms server start --api-key your-secret-key
+
+  # Serve HTTPS with a self-signed localhost certificate
+  lux kms server start --tls-auto
+
+  # Serve HTTPS and require client certificates (mTLS)
+  lux kms server start --tls-cert server.crt --tls-key server.key --client-ca ca.crt`,
 		RunE: runServerStart,
 	}
 
@@ -131,19 +143,33 @@ Examples:
 	cmd.Flags().StringVar(&serverDataDir, "data-dir", "", "Data directory (default: ~/.lux/kms)")
 	cmd.Flags().StringVar(&serverAPIKey, "api-key", "", "API key for authentication")
 	cmd.Flags().BoolVar(&serverInMem, "in-memory", false, "Use in-memory storage (data lost on restart)")
+	cmd.Flags().StringVar(&serverTLSCert, "tls-cert", "", "TLS certificate file (enables HTTPS)")
+	cmd.Flags().StringVar(&serverTLSKey, "tls-key", "", "TLS private key file")
+	cmd.Flags().BoolVar(&serverTLSAuto, "tls-auto", false, "Generate and use a self-signed certificate for localhost")
+	cmd.Flags().StringVar(&serverCAFile, "client-ca", "", "CA certificate used to verify client certificates (enables mTLS)")
+	cmd.MarkFlagsRequiredTogether("tls-cert", "tls-key")
+	cmd.MarkFlagsMutuallyExclusive("tls-auto", "tls-cert")
 
 	return cmd
 }
 
+// resolveDataDir returns dir, or ~/.lux/kms when dir is empty.
+func resolveDataDir(dir string) (string, error) {
+	if dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".lux", "kms"), nil
+}
+
 func runServerStart(cmd *cobra.Command, args []string) error {
 	// Determine data directory
-	dataDir := serverDataDir
-	if dataDir == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return fmt.Errorf("failed to get home directory: %w", err)
-		}
-		dataDir = filepath.Join(home, ".lux", "kms")
+	dataDir, err := resolveDataDir(serverDataDir)
+	if err != nil {
+		return err
 	}
 
 	// Create data directory if it doesn't exist
@@ -173,12 +199,24 @@ func runServerStart(cmd *cobra.Command, args []string) error {
 	}
 	defer kmsInstance.Close()
 
+	// Resolve TLS certificate
+	tlsCert, tlsKey := serverTLSCert, serverTLSKey
+	if serverTLSAuto {
+		tlsCert, tlsKey, err = kms.EnsureLocalhostCert(dataDir)
+		if err != nil {
+			return fmt.Errorf("failed to generate TLS certificate: %w", err)
+		}
+	}
+
 	// Create server
 	serverConfig := &kms.ServerConfig{
 		Addr:          serverAddr,
 		APIKey:        serverAPIKey,
 		EnableMPC:     true,
 		EnableSecrets: true,
+		TLSCertFile:   tlsCert,
+		TLSKeyFile:    tlsKey,
+		ClientCAFile:  serverCAFile,
 	}
 
 	server := kms.NewServer(kmsInstance, serverConfig)
@@ -205,6 +243,12 @@ func runServerStart(cmd *cobra.Command, args []string) error {
 	if serverAPIKey != "" {
 		ux.Logger.PrintToUser("API key authentication enabled")
 	}
+	if serverConfig.TLSEnabled() {
+		ux.Logger.PrintToUser("TLS enabled (certificate: %s)", tlsCert)
+		if serverCAFile != "" {
+			ux.Logger.PrintToUser("Client certificate verification enabled (CA: %s)", serverCAFile)
+		}
+	}
 	ux.Logger.PrintToUser("")
 	ux.Logger.PrintToUser("API Endpoints:")
 	ux.Logger.PrintToUser("  Health: GET /health")
@@ -273,6 +317,7 @@ func newKeyCmd() *cobra.Command {
 	cmd.AddCommand(newKeyCreateCmd())
 	cmd.AddCommand(newKeyListCmd())
 	cmd.AddCommand(newKeyDeleteCmd())
+	cmd.AddCommand(newKeyRotateCmd())
 
 	return cmd
 }
@@ -289,6 +334,7 @@ Supported key types:
   - ecdsa-p256    : ECDSA P-256 curve
   - ecdsa-p384    : ECDSA P-384 curve
   - ed25519       : EdDSA Ed25519
+  - secp256k1     : Lux and EVM transaction signing, as --key kms://<keyId>
 
 Usage types:
   - encrypt-decrypt : For encryption operations
@@ -296,7 +342,8 @@ Usage types:
 
 Examples:
   lux kms key create --name mykey --type aes-256-gcm
-  lux kms key create --name signing --type ecdsa-p256 --usage sign-verify`,
+  lux kms key create --name signing --type ecdsa-p256 --usage sign-verify
+  lux kms key create --name treasury --type secp256k1 --usage sign-verify`,
 		RunE: runKeyCreate,
 	}
 
@@ -0,0 +1,98 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package kmscmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/kms"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rotateServer       string
+	rotateAPIKey       string
+	rotateReencryptNow bool
+)
+
+func newKeyRotateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate [keyID]",
+		Short: "Rotate a key to a new version",
+		Long: `Rotate an encryption key of a running KMS server to a new key version.
+
+New encryptions use the new version. The old versions are kept, so data
+encrypted before the rotation still decrypts. Secrets encrypted with the key
+are re-encrypted with the new version the next time they are read, or all at
+once with --reencrypt-now.
+
+Only encrypt-decrypt keys can be rotated. The rotation and every re-encrypted
+secret are recorded in the audit log (key.rotate, secret.reencrypt).
+
+The server exposes the same operation at POST /v1/kms/keys/{id}/rotate.
+
+Examples:
+  lux kms key rotate 3f2a...
+  lux kms key rotate 3f2a... --reencrypt-now
+  lux kms key rotate 3f2a... --server https://kms.example.com:8200 --api-key $KMS_API_KEY`,
+		Args: cobra.ExactArgs(1),
+		RunE: runKeyRotate,
+	}
+
+	cmd.Flags().StringVar(&rotateServer, "server", "http://localhost:8200", "KMS server address")
+	cmd.Flags().StringVar(&rotateAPIKey, "api-key", "", "API key of the server")
+	cmd.Flags().BoolVar(&rotateReencryptNow, "reencrypt-now", false, "Re-encrypt all secrets of the key now instead of on their next read")
+
+	return cmd
+}
+
+func runKeyRotate(_ *cobra.Command, args []string) error {
+	keyID := args[0]
+	body, err := json.Marshal(map[string]bool{"reencryptNow": rotateReencryptNow})
+	if err != nil {
+		return err
+	}
+	url := strings.TrimSuffix(rotateServer, "/") + "/v1/kms/keys/" + keyID + "/rotate"
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if rotateAPIKey != "" {
+		req.Header.Set("X-API-Key", rotateAPIKey)
+	}
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the KMS server at %s (start it with: lux kms server start): %w", rotateServer, err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Key         kms.KmsKey `json:"key"`
+		Reencrypted int        `json:"reencrypted"`
+		Error       string     `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("invalid response from the KMS server: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to rotate key %s: %s", keyID, result.Error)
+	}
+
+	ux.Logger.PrintToUser("Rotated key %s (%s) to version %d", result.Key.ID, result.Key.Name, result.Key.Version)
+	if rotateReencryptNow {
+		ux.Logger.PrintToUser("Re-encrypted %d secrets", result.Reencrypted)
+	} else {
+		ux.Logger.PrintToUser("Secrets are re-encrypted with the new version when next read")
+	}
+	return nil
+}
@@ -96,7 +96,7 @@ NON-INTERACTIVE MODE:
   --destination    Destination layer name
   --token-type     Token type (native, erc20, nft)
   --amount         Amount to transfer
-  --recipient      Recipient address
+  --recipient      Recipient address, or @label of the address book
   --yes            Confirm transfer without prompting
 
 EXAMPLES:
@@ -182,6 +182,10 @@ EXAMPLES:
 					return err
 				}
 			}
+			recipientAddr, err := app.ResolveAddress(recipientAddr)
+			if err != nil {
+				return err
+			}
 
 			// Display transfer summary
 			ux.Logger.PrintToUser("")
@@ -190,7 +194,7 @@ EXAMPLES:
 			ux.Logger.PrintToUser("  To: %s", destination)
 			ux.Logger.PrintToUser("  Token: %s", tokenType)
 			ux.Logger.PrintToUser("  Amount: %s", amount)
-			ux.Logger.PrintToUser("  Recipient: %s", recipientAddr)
+			ux.Logger.PrintToUser("  Recipient: %s", app.AnnotateAddress(recipientAddr))
 			ux.Logger.PrintToUser("")
 
 			// Confirm transfer
@@ -228,7 +232,7 @@ EXAMPLES:
 	cmd.Flags().StringVar(&bridgeDest, "destination", "", "Destination layer name")
 	cmd.Flags().StringVar(&bridgeTokenType, "token-type", "", "Token type (native, erc20, nft)")
 	cmd.Flags().StringVar(&bridgeAmount, "amount", "", "Amount to transfer")
-	cmd.Flags().StringVar(&bridgeRecipient, "recipient", "", "Recipient address")
+	cmd.Flags().StringVar(&bridgeRecipient, "recipient", "", "Recipient address or @label")
 	cmd.Flags().BoolVarP(&bridgeConfirm, "yes", "y", false, "Confirm transfer without prompting")
 
 	return cmd
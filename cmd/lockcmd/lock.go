@@ -0,0 +1,114 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lockcmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	app *application.Lux
+
+	force bool
+)
+
+// NewCmd creates the lock command
+func NewCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "lock",
+		Short: "Show or break the lock held by mutating commands",
+		Long: `Commands that mutate run directories and sidecars take a lock in
+~/.lux/cli.lock while they run, so two of them can't corrupt each other's
+state: network start, stop and clean, chain deploy and snapshot restore.
+
+A command finding the lock held fails right away, or waits for it with
+--wait (10 minutes, or the given duration). Locks left behind by a CLI that
+died are detected and taken over.
+
+EXAMPLES:
+
+  # Who holds the lock
+  lux lock status
+
+  # Wait for the running command instead of failing
+  lux network start --devnet --wait
+  lux chain deploy mychain --wait 30m
+
+  # Remove a lock held on another host through a shared ~/.lux
+  lux lock break --force`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.AddCommand(newStatusCmd())
+	cmd.AddCommand(newBreakCmd())
+	return cmd
+}
+
+func newStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show the command holding the lock",
+		Args:  cobrautils.ExactArgs(0),
+		RunE:  lockStatus,
+	}
+}
+
+func lockStatus(_ *cobra.Command, _ []string) error {
+	lock, err := app.ReadLock()
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		ux.Logger.PrintToUser("Not locked")
+		return nil
+	}
+	ux.Logger.PrintToUser("Locked by %s", lock.Operation)
+	ux.Logger.PrintToUser("  Owner: %s", lock.Owner)
+	ux.Logger.PrintToUser("  PID:   %d on %s", lock.PID, lock.Host)
+	ux.Logger.PrintToUser("  Since: %s (%s ago)", lock.CreatedAt.Local().Format(time.RFC3339),
+		time.Since(lock.CreatedAt).Round(time.Second))
+	if lock.Stale() {
+		ux.Logger.PrintToUser("The process holding it is gone: the next command takes it over")
+	}
+	return nil
+}
+
+func newBreakCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "break",
+		Short: "Remove the lock",
+		Long: `Remove the lock, for a lock left behind by a CLI that died or one that
+can't be checked, such as a lock taken on another host. Breaking the lock of
+a running command requires --force, and lets another command run alongside
+it.`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: breakLock,
+	}
+	cmd.Flags().BoolVar(&force, "force", false, "remove the lock even if its command is still running")
+	return cmd
+}
+
+func breakLock(_ *cobra.Command, _ []string) error {
+	lock, err := app.ReadLock()
+	if err == nil && lock != nil && !lock.Stale() && !force {
+		return fmt.Errorf("lock held by %s (PID %d on %s) may still be running, use --force to remove it anyway",
+			lock.Operation, lock.PID, lock.Host)
+	}
+	lock, err = app.BreakLock()
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		ux.Logger.PrintToUser("Removed the lock, if any")
+		return nil
+	}
+	ux.Logger.PrintToUser("Removed the lock %s held for %s", lock.Owner, lock.Operation)
+	return nil
+}
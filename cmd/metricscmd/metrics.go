@@ -0,0 +1,132 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metricscmd
+
+import (
+	"strings"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/metrics"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Lux
+
+// NewCmd creates the metrics command
+func NewCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "metrics",
+		Short: "Inspect and control the usage metrics of the CLI",
+		Long: `The metrics command shows and sets what the CLI collects about its usage.
+
+Every command run is first recorded in a local store, ~/.lux/metrics, that
+'lux metrics report' shows. Events are only sent once you opt in with
+'lux metrics enable'. After 'lux metrics disable' nothing is recorded nor
+sent. Single commands, and their subcommands, can be opted out of with
+'lux metrics disable <command>'.
+
+LUX_METRICS=false opts out for one command or a whole environment,
+whatever the config says.
+
+EXAMPLES:
+
+  lux metrics status
+  lux metrics report
+  lux metrics disable key
+  lux metrics enable`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.AddCommand(newStatusCmd())
+	cmd.AddCommand(newEnableCmd())
+	cmd.AddCommand(newDisableCmd())
+	cmd.AddCommand(newReportCmd())
+	return cmd
+}
+
+func newStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether usage metrics are recorded and sent",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			consent, source := metrics.GetConsent(app)
+			switch consent {
+			case metrics.Enabled:
+				ux.Logger.PrintToUser("Usage metrics: enabled, recorded locally and sent (%s)", source)
+			case metrics.Disabled:
+				ux.Logger.PrintToUser("Usage metrics: disabled, nothing recorded nor sent (%s)", source)
+			default:
+				ux.Logger.PrintToUser("Usage metrics: not opted in, recorded locally only")
+			}
+			if disabled := metrics.DisabledCommands(app); len(disabled) > 0 {
+				ux.Logger.PrintToUser("Not tracked:   %s", strings.Join(disabled, ", "))
+			}
+			store := metrics.NewStore(app.GetBaseDir())
+			events, err := store.Events()
+			if err != nil {
+				return err
+			}
+			ux.Logger.PrintToUser("Local store:   %s (%d events)", store.Path(), len(events))
+			return nil
+		},
+	}
+}
+
+func newEnableCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "enable [command...]",
+		Short: "Opt in to sending usage metrics, or opt commands back in",
+		Long: `Opt in to sending usage metrics. With commands, opt those commands back in
+to tracking, after 'lux metrics disable <command>'.`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				if err := metrics.SetEnabled(app, true); err != nil {
+					return err
+				}
+				ux.Logger.PrintToUser("Thank you for opting in Lux CLI usage metrics collection")
+				return nil
+			}
+			for _, command := range args {
+				if err := metrics.SetCommandDisabled(app, command, false); err != nil {
+					return err
+				}
+				ux.Logger.GreenCheckmarkToUser("%s is tracked again", command)
+			}
+			return nil
+		},
+	}
+}
+
+func newDisableCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "disable [command...]",
+		Short: "Opt out of usage metrics, or out of tracking single commands",
+		Long: `Opt out of usage metrics: nothing is recorded nor sent anymore. With
+commands, opt only those commands, and their subcommands, out of tracking.
+
+EXAMPLES:
+
+  lux metrics disable
+  lux metrics disable key "chain deploy"`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				if err := metrics.SetEnabled(app, false); err != nil {
+					return err
+				}
+				ux.Logger.PrintToUser("Lux CLI usage metrics will no longer be collected")
+				return nil
+			}
+			for _, command := range args {
+				if err := metrics.SetCommandDisabled(app, command, true); err != nil {
+					return err
+				}
+				ux.Logger.GreenCheckmarkToUser("%s is no longer tracked", command)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metricscmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/luxfi/cli/pkg/metrics"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportEvents bool
+	reportClear  bool
+)
+
+func newReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Show the usage metrics collected locally",
+		Long: `Show the usage metrics of the local store: how often each command ran,
+failed and was sent. --events prints each event as JSON, with the exact
+properties it is sent with.`,
+		Args: cobra.NoArgs,
+		RunE: runReport,
+	}
+	cmd.Flags().BoolVar(&reportEvents, "events", false, "print every event, with the properties it is sent with")
+	cmd.Flags().BoolVar(&reportClear, "clear", false, "delete the events of the local store")
+	return cmd
+}
+
+func runReport(_ *cobra.Command, _ []string) error {
+	store := metrics.NewStore(app.GetBaseDir())
+	if reportClear {
+		if err := store.Clear(); err != nil {
+			return err
+		}
+		ux.Logger.GreenCheckmarkToUser("Deleted the events of %s", store.Path())
+		return nil
+	}
+	events, err := store.Events()
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		ux.Logger.PrintToUser("No usage metrics collected in %s", store.Path())
+		return nil
+	}
+	if reportEvents {
+		for _, e := range events {
+			b, err := json.Marshal(struct {
+				Time       time.Time              `json:"time"`
+				Sent       bool                   `json:"sent"`
+				Properties map[string]interface{} `json:"properties"`
+			}{e.Time, e.Sent, e.Properties()})
+			if err != nil {
+				return err
+			}
+			ux.Logger.PrintToUser("%s", b)
+		}
+		return nil
+	}
+
+	ux.Logger.PrintToUser("%d events in %s, since %s", len(events), store.Path(), events[0].Time.Local().Format(time.DateTime))
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Command", "Runs", "Failed", "Sent", "Last Run")
+	for _, s := range metrics.Summarize(events) {
+		_ = table.Append([]string{
+			s.Command,
+			fmt.Sprintf("%d", s.Runs),
+			fmt.Sprintf("%d", s.Failures),
+			fmt.Sprintf("%d", s.Sent),
+			s.Last.Local().Format(time.DateTime),
+		})
+	}
+	_ = table.Render()
+	return nil
+}
@@ -0,0 +1,229 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migratecmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	ansi "github.com/k0kubun/go-ansi"
+	"github.com/luxfi/cli/pkg/migrate"
+	"github.com/luxfi/cli/pkg/snapshot"
+	"github.com/luxfi/cli/pkg/ux"
+	progressbar "github.com/schollz/progressbar/v3"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dbSource     string
+	dbTarget     string
+	dbSourceType string
+	dbTargetType string
+	dbCheckpoint string
+	dbPrefixLen  int
+	dbRestart    bool
+	dbVerify     bool
+)
+
+func newDBCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Copy a node database into another database engine",
+		Long: `Copy every key of a node database into a target database, optionally of a
+different engine.
+
+The source engine is detected from its files unless --source-type is given.
+Progress is saved to a checkpoint file after every batch, so an interrupted
+migration resumes where it stopped when run again with the same flags. Use
+--restart to ignore an existing checkpoint.
+
+Keys are grouped by their first --prefix-len bytes for progress reporting
+and for --verify, which compares per-prefix key counts between source and
+target once the copy has finished.
+
+PebbleDB and LevelDB require a CLI built with the pebbledb or leveldb build tag.`,
+		RunE: migrateDB,
+	}
+
+	cmd.Flags().StringVar(&dbSource, "source", "", "source database directory")
+	cmd.Flags().StringVar(&dbTarget, "target", "", "target database directory")
+	cmd.Flags().StringVar(&dbSourceType, "source-type", "", "source engine: badgerdb, pebbledb or leveldb (default: detect)")
+	cmd.Flags().StringVar(&dbTargetType, "target-type", string(snapshot.BadgerDB), "target engine: badgerdb, pebbledb or leveldb")
+	cmd.Flags().StringVar(&dbCheckpoint, "checkpoint", "", "checkpoint file (default: under ~/.lux/migrate)")
+	cmd.Flags().IntVar(&dbPrefixLen, "prefix-len", migrate.DefaultPrefixLen, "key prefix length used to group progress and verification")
+	cmd.Flags().BoolVar(&dbRestart, "restart", false, "ignore an existing checkpoint and start over")
+	cmd.Flags().BoolVar(&dbVerify, "verify", false, "compare per-prefix key counts between source and target after copying")
+	_ = cmd.MarkFlagRequired("source")
+	_ = cmd.MarkFlagRequired("target")
+
+	return cmd
+}
+
+func migrateDB(_ *cobra.Command, _ []string) error {
+	if dbPrefixLen < 0 {
+		return fmt.Errorf("--prefix-len must not be negative")
+	}
+	source, err := filepath.Abs(dbSource)
+	if err != nil {
+		return err
+	}
+	target, err := filepath.Abs(dbTarget)
+	if err != nil {
+		return err
+	}
+	if source == target {
+		return fmt.Errorf("source and target must be different directories")
+	}
+
+	var sourceType snapshot.DBType
+	if dbSourceType == "" {
+		sourceType, err = snapshot.DetectDBType(source)
+	} else {
+		sourceType, err = snapshot.ParseDBType(dbSourceType)
+	}
+	if err != nil {
+		return err
+	}
+	targetType, err := snapshot.ParseDBType(dbTargetType)
+	if err != nil {
+		return err
+	}
+
+	checkpointPath := dbCheckpoint
+	if checkpointPath == "" {
+		sum := sha256.Sum256([]byte(source + "\x00" + target))
+		checkpointPath = filepath.Join(app.GetBaseDir(), "migrate", hex.EncodeToString(sum[:8])+".json")
+	}
+
+	cp := &migrate.Checkpoint{
+		Source:     source,
+		Target:     target,
+		SourceType: string(sourceType),
+		TargetType: string(targetType),
+		PrefixLen:  dbPrefixLen,
+	}
+	if !dbRestart {
+		existing, err := migrate.LoadCheckpoint(checkpointPath)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			if err := existing.Matches(cp); err != nil {
+				return fmt.Errorf("%w, use --restart or --checkpoint", err)
+			}
+			cp = existing
+			ux.Logger.PrintToUser("Resuming migration after %d keys (checkpoint %s)", cp.Copied, checkpointPath)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	src, err := snapshot.OpenDB(sourceType, source)
+	if err != nil {
+		return fmt.Errorf("failed to open source %s: %w", sourceType, err)
+	}
+	defer src.Close()
+
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		return fmt.Errorf("failed to create target directory: %w", err)
+	}
+	dst, err := snapshot.OpenDB(targetType, target)
+	if err != nil {
+		return fmt.Errorf("failed to open target %s: %w", targetType, err)
+	}
+	defer dst.Close()
+
+	ux.Logger.PrintToUser("Migrating %s (%s) -> %s (%s)", source, sourceType, target, targetType)
+	ux.Logger.PrintToUser("Counting source keys...")
+	counts, err := migrate.CountPrefixes(ctx, src, dbPrefixLen)
+	if err != nil {
+		return fmt.Errorf("failed to count source keys: %w", err)
+	}
+	var total uint64
+	for _, c := range counts {
+		total += c.Count
+	}
+	ux.Logger.PrintToUser("Found %d keys in %d prefixes", total, len(counts))
+
+	start := time.Now()
+	copiedBefore := cp.Copied
+	err = migrate.Copy(ctx, src, dst, counts, cp, checkpointPath, migrate.DefaultBatchSize, &prefixProgress{})
+	if errors.Is(err, context.Canceled) {
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("Interrupted after %d keys, run the same command again to resume", cp.Copied)
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("migration failed after %d keys, run the same command again to resume: %w", cp.Copied, err)
+	}
+	ux.Logger.PrintToUser("✓ Copied %d keys in %s", cp.Copied-copiedBefore, ux.FormatDuration(time.Since(start)))
+
+	if dbVerify {
+		ux.Logger.PrintToUser("Verifying key counts...")
+		mismatches, err := migrate.Verify(ctx, src, dst, dbPrefixLen)
+		if err != nil {
+			return err
+		}
+		if len(mismatches) > 0 {
+			for _, m := range mismatches {
+				ux.Logger.PrintToUser("  prefix %s: source %d, target %d", m.Prefix, m.Source, m.Target)
+			}
+			return fmt.Errorf("verification failed: %d prefixes differ", len(mismatches))
+		}
+		ux.Logger.PrintToUser("✓ Key counts match for all %d prefixes", len(counts))
+	}
+
+	// The migration is complete, a stale checkpoint would only skip keys next time
+	if err := os.Remove(checkpointPath); err != nil && !os.IsNotExist(err) {
+		ux.Logger.PrintToUser("Warning: failed to remove checkpoint %s: %v", checkpointPath, err)
+	}
+	return nil
+}
+
+// prefixProgress renders one progress bar with ETA per key prefix.
+type prefixProgress struct {
+	bar *progressbar.ProgressBar
+}
+
+func (p *prefixProgress) Start(prefix migrate.PrefixCount, remaining uint64) {
+	p.bar = progressbar.NewOptions64(int64(remaining),
+		progressbar.OptionSetWriter(ansi.NewAnsiStdout()),
+		progressbar.OptionEnableColorCodes(true),
+		progressbar.OptionSetWidth(30),
+		progressbar.OptionSetDescription(fmt.Sprintf("prefix %-8s", prefix.PrefixHex())),
+		progressbar.OptionShowCount(),
+		progressbar.OptionShowIts(),
+		progressbar.OptionSetItsString("keys"),
+		progressbar.OptionSetPredictTime(true),
+		progressbar.OptionSetTheme(progressbar.Theme{
+			Saucer:        "[green]=[reset]",
+			SaucerHead:    "[green]>[reset]",
+			SaucerPadding: " ",
+			BarStart:      "[",
+			BarEnd:        "]",
+		}))
+}
+
+func (p *prefixProgress) Add(n int) {
+	if p.bar != nil {
+		_ = p.bar.Add(n)
+	}
+}
+
+func (p *prefixProgress) Finish() {
+	if p.bar != nil {
+		_ = p.bar.Finish()
+		fmt.Println()
+		p.bar = nil
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migratecmd
+
+import (
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Lux
+
+// NewCmd creates the top-level migrate command
+func NewCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate node data between database engines",
+		Long: `The migrate command copies node data between storage engines.
+
+USAGE:
+
+  # Copy a LevelDB database into a new BadgerDB database
+  lux migrate db --source ~/.luxd/db/mainnet/db --target ./badgerdb
+
+  # Resume an interrupted migration (picks up the checkpoint automatically)
+  lux migrate db --source ~/.luxd/db/mainnet/db --target ./badgerdb
+
+  # Compare key counts between source and target after copying
+  lux migrate db --source ~/.luxd/db/mainnet/db --target ./badgerdb --verify`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newDBCmd())
+
+	return cmd
+}
@@ -10,12 +10,15 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/luxfi/cli/pkg/application"
 	"github.com/luxfi/cli/pkg/mpc"
 	"github.com/luxfi/cli/pkg/ux"
 	"github.com/spf13/cobra"
 )
 
 var (
+	app *application.Lux
+
 	// Node flags
 	nodeThreshold  int
 	nodeTotalNodes int
@@ -24,7 +27,8 @@ var (
 )
 
 // NewCmd creates the mpc command.
-func NewCmd() *cobra.Command {
+func NewCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
 	cmd := &cobra.Command{
 		Use:   "mpc",
 		Short: "Manage MPC nodes and wallets",
@@ -54,8 +58,11 @@ QUICK START:
   # Check status
   lux mpc node status
 
-  # Create a wallet
-  lux mpc wallet create --name "Treasury"
+  # Create a 2-of-3 wallet in the KMS of LUX_KMS_ADDR
+  lux mpc wallet create --name Treasury --threshold 2 --parties 3
+
+  # Deploy with it, each party approving with 'lux mpc sign approve'
+  lux chain deploy mychain --mainnet --key mpc://<wallet-id>
 
   # Stop the network
   lux mpc node stop
@@ -483,136 +490,6 @@ func runNodeList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// newWalletCmd creates the wallet management command group.
-func newWalletCmd() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "wallet",
-		Short: "Manage MPC wallets",
-		Long: `Commands for managing MPC wallets and their key shares.
-
-Examples:
-  # List wallets
-  lux mpc wallet list
-
-  # Create a new wallet
-  lux mpc wallet create --name "Treasury" --threshold 2 --parties 3
-
-  # Show wallet details
-  lux mpc wallet show <wallet-id>`,
-	}
-
-	cmd.AddCommand(newWalletListCmd())
-	cmd.AddCommand(newWalletCreateCmd())
-	cmd.AddCommand(newWalletShowCmd())
-	cmd.AddCommand(newWalletExportCmd())
-
-	return cmd
-}
-
-func newWalletListCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "list",
-		Short: "List wallets",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			ux.Logger.PrintToUser("No wallets found. Create one with 'lux mpc wallet create'")
-			return nil
-		},
-	}
-}
-
-func newWalletCreateCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "create",
-		Short: "Create a new wallet",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			// Wallet creation requires a running MPC network with DKG ceremony.
-			ux.Logger.PrintToUser("Wallet creation requires running MPC nodes")
-			ux.Logger.PrintToUser("Start nodes first with 'lux mpc node start'")
-			return fmt.Errorf("no running MPC network found")
-		},
-	}
-}
-
-func newWalletShowCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "show <wallet-id>",
-		Short: "Show wallet details",
-		Args:  cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			return fmt.Errorf("wallet not found: %s", args[0])
-		},
-	}
-}
-
-func newWalletExportCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "export <wallet-id>",
-		Short: "Export wallet public key",
-		Args:  cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			return fmt.Errorf("wallet not found: %s", args[0])
-		},
-	}
-}
-
-// newSignCmd creates the signing command group.
-func newSignCmd() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "sign",
-		Short: "Threshold signing operations",
-		Long: `Commands for threshold signing operations.
-
-Examples:
-  # Initiate a signing request
-  lux mpc sign request --wallet <wallet-id> --message "0x..."
-
-  # Approve a signing request
-  lux mpc sign approve <request-id>
-
-  # Check signing status
-  lux mpc sign status <request-id>`,
-	}
-
-	cmd.AddCommand(newSignRequestCmd())
-	cmd.AddCommand(newSignApproveCmd())
-	cmd.AddCommand(newSignStatusCmd())
-
-	return cmd
-}
-
-func newSignRequestCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "request",
-		Short: "Initiate a signing request",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			ux.Logger.PrintToUser("Signing requires running MPC nodes and an existing wallet")
-			return nil
-		},
-	}
-}
-
-func newSignApproveCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "approve <request-id>",
-		Short: "Approve a signing request",
-		Args:  cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			return fmt.Errorf("signing request not found: %s", args[0])
-		},
-	}
-}
-
-func newSignStatusCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "status <request-id>",
-		Short: "Check signing status",
-		Args:  cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			return fmt.Errorf("signing request not found: %s", args[0])
-		},
-	}
-}
-
 // Helper functions
 
 func getNodeManager() *mpc.NodeManager {
@@ -0,0 +1,207 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package mpccmd
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/keychain"
+	"github.com/luxfi/cli/pkg/kms"
+	"github.com/luxfi/cli/pkg/threshold"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	signWallet string
+	signHash   string
+	signShares []string
+	signYes    bool
+)
+
+// newSignCmd creates the signing command group.
+func newSignCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign",
+		Short: "Threshold signing operations",
+		Long: `Commands for threshold signing operations.
+
+A signature of an MPC wallet is a signing request of the KMS that
+--threshold of its parties approve, each with its share file. The requester
+combines their partial signatures into the signature of the wallet.
+
+Deploys and other txs signed with --key mpc://<wallet-id> open a request
+for each tx and wait for the approvals. Shares listed in LUX_MPC_SHARES
+approve them from the requesting machine.
+
+Examples:
+  # Sign a digest, approving with a local share
+  lux mpc sign request --wallet <wallet-id> --hash 0x... --share alice.share.json
+
+  # Approve a signing request
+  lux mpc sign approve <request-id> --share bob.share.json
+
+  # Check signing status
+  lux mpc sign status <request-id>`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+
+	cmd.AddCommand(newSignRequestCmd())
+	cmd.AddCommand(newSignApproveCmd())
+	cmd.AddCommand(newSignStatusCmd())
+
+	return cmd
+}
+
+func newSignRequestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "request",
+		Short: "Sign a digest with a wallet",
+		Long: `Open a signing request for a 32-byte digest, wait for the parties to
+approve it, and print the [r || s || v] signature of the wallet.`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: runSignRequest,
+	}
+	cmd.Flags().StringVar(&signWallet, "wallet", "", "wallet ID")
+	cmd.Flags().StringVar(&signHash, "hash", "", "hex digest to sign")
+	cmd.Flags().StringSliceVar(&signShares, "share", nil, "share files approving the request from this machine")
+	return cmd
+}
+
+func runSignRequest(_ *cobra.Command, _ []string) error {
+	if signWallet == "" {
+		return fmt.Errorf("--wallet is required")
+	}
+	digest, err := hex.DecodeString(strings.TrimPrefix(signHash, "0x"))
+	if err != nil || len(digest) != 32 {
+		return fmt.Errorf("invalid --hash %q, expected a 32-byte hex digest", signHash)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), mpcTimeout)
+	defer cancel()
+	signer, err := keychain.NewMPCSigner(ctx, keychain.NewKMSClientFromEnv(), signWallet)
+	if err != nil {
+		return err
+	}
+	for _, file := range signShares {
+		share, err := keychain.LoadMPCShare(file)
+		if err != nil {
+			return err
+		}
+		signer.Shares = append(signer.Shares, share)
+	}
+	signer.OnRequest = func(request *kms.MPCSigningRequest) {
+		ux.Logger.PrintToUser("Signing request %s needs %d approvals, approve it with:", request.ID, request.RequiredSigs)
+		ux.Logger.PrintToUser("  lux mpc sign approve %s --share <share file>", request.ID)
+	}
+	signer.OnProgress = func(request *kms.MPCSigningRequest) {
+		ux.Logger.PrintToUser("  %d of %d approvals", request.CollectedSigs, request.RequiredSigs)
+	}
+	sig, err := signer.SignHash(digest)
+	if err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Signed by wallet %s", signer.Wallet().Name)
+	fmt.Println("0x" + hex.EncodeToString(sig))
+	return nil
+}
+
+func newSignApproveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "approve <request-id>",
+		Short: "Approve a signing request",
+		Long: `Approve a signing request with the share of a party: its partial signature
+of the request's digest is submitted to the KMS.`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: runSignApprove,
+	}
+	cmd.Flags().StringSliceVar(&signShares, "share", nil, "share file of the approving party")
+	cmd.Flags().BoolVarP(&signYes, "yes", "y", false, "Skip confirmation prompt")
+	return cmd
+}
+
+func runSignApprove(_ *cobra.Command, args []string) error {
+	if len(signShares) == 0 {
+		return fmt.Errorf("--share is required")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), mpcTimeout)
+	defer cancel()
+	client := keychain.NewKMSClientFromEnv()
+	request, err := client.MPCSigningRequest(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	wallet, err := client.MPCWallet(ctx, request.WalletID)
+	if err != nil {
+		return err
+	}
+	printSigningRequest(request, wallet)
+	if request.Status == kms.SigningStatusComplete || request.Status == kms.SigningStatusExpired {
+		return fmt.Errorf("signing request %s is %s", request.ID, request.Status)
+	}
+	shares := make([]*threshold.Share, len(signShares))
+	for i, file := range signShares {
+		if shares[i], err = keychain.LoadMPCShare(file); err != nil {
+			return err
+		}
+	}
+	if !signYes {
+		ok, err := app.Prompt.CaptureYesNo(fmt.Sprintf("Approve signing digest 0x%x with wallet %s?", request.Message, wallet.Name))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), mpcTimeout)
+	defer cancel()
+	for _, share := range shares {
+		if request, err = keychain.ApproveMPCRequest(ctx, client, share, request); err != nil {
+			return err
+		}
+		ux.Logger.GreenCheckmarkToUser("Approved as %s: %d of %d approvals", share.NodeID, request.CollectedSigs, request.RequiredSigs)
+	}
+	return nil
+}
+
+func newSignStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status <request-id>",
+		Short: "Check signing status",
+		Args:  cobrautils.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), mpcTimeout)
+			defer cancel()
+			client := keychain.NewKMSClientFromEnv()
+			request, err := client.MPCSigningRequest(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			wallet, err := client.MPCWallet(ctx, request.WalletID)
+			if err != nil {
+				return err
+			}
+			printSigningRequest(request, wallet)
+			if request.FinalSignature != nil {
+				ux.Logger.PrintToUser("Signature: 0x%x", request.FinalSignature)
+			}
+			return nil
+		},
+	}
+}
+
+func printSigningRequest(request *kms.MPCSigningRequest, wallet *kms.MPCWallet) {
+	ux.Logger.PrintToUser("Request:   %s", request.ID)
+	ux.Logger.PrintToUser("Wallet:    %s (%s)", wallet.Name, wallet.ID)
+	ux.Logger.PrintToUser("Digest:    0x%x", request.Message)
+	for k, v := range request.Metadata {
+		ux.Logger.PrintToUser("%-10s %s", k+":", v)
+	}
+	ux.Logger.PrintToUser("Status:    %s, %d of %d approvals, expires %s",
+		request.Status, request.CollectedSigs, request.RequiredSigs, request.ExpiresAt.Local().Format("15:04:05"))
+}
@@ -0,0 +1,313 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package mpccmd
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luxfi/address"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/key"
+	"github.com/luxfi/cli/pkg/keychain"
+	"github.com/luxfi/cli/pkg/kms"
+	"github.com/luxfi/cli/pkg/threshold"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/crypto/secp256k1"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultPresignatures = 256
+	mpcTimeout           = 30 * time.Second
+)
+
+var (
+	walletName          string
+	walletThreshold     int
+	walletParties       int
+	walletParticipants  []string
+	walletPresignatures int
+	walletOutputDir     string
+)
+
+// newWalletCmd creates the wallet management command group.
+func newWalletCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wallet",
+		Short: "Manage MPC wallets",
+		Long: `Commands for managing MPC wallets and their key shares.
+
+Wallets are kept by the KMS server of LUX_KMS_ADDR (http://localhost:8200
+by default, with the API key of LUX_KMS_API_KEY), which tracks their
+parties and signing requests. Each party holds a share file and approves
+signing requests with it; the KMS never sees a share.
+
+Examples:
+  # List wallets
+  lux mpc wallet list
+
+  # Create a new 2-of-3 wallet
+  lux mpc wallet create --name Treasury --threshold 2 --parties 3
+
+  # Show wallet details
+  lux mpc wallet show <wallet-id>`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+
+	cmd.AddCommand(newWalletListCmd())
+	cmd.AddCommand(newWalletCreateCmd())
+	cmd.AddCommand(newWalletShowCmd())
+	cmd.AddCommand(newWalletExportCmd())
+
+	return cmd
+}
+
+func newWalletListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List wallets",
+		Args:  cobrautils.ExactArgs(0),
+		RunE:  runWalletList,
+	}
+}
+
+func runWalletList(_ *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), mpcTimeout)
+	defer cancel()
+	wallets, err := keychain.NewKMSClientFromEnv().MPCWallets(ctx)
+	if err != nil {
+		return err
+	}
+	if len(wallets) == 0 {
+		ux.Logger.PrintToUser("No wallets found. Create one with 'lux mpc wallet create'")
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("ID", "Name", "Threshold", "Status", "Signatures Left", "EVM Address")
+	for _, w := range wallets {
+		left, evm := "-", "-"
+		if w.Presignatures > 0 {
+			left = strconv.Itoa(w.Presignatures - w.PresignaturesUsed)
+		}
+		if pub, err := secp256k1.ToPublicKey(w.PublicKey); err == nil {
+			evm = fmt.Sprintf("0x%x", pub.EthAddress())
+		}
+		_ = table.Append([]string{w.ID, w.Name, fmt.Sprintf("%d of %d", w.Threshold, w.TotalParties), string(w.Status), left, evm})
+	}
+	return table.Render()
+}
+
+func newWalletCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a new wallet",
+		Long: `Create a threshold ECDSA wallet in the KMS and deal its shares.
+
+The key and its presignatures are generated here, split into one share
+file per party, and forgotten: this machine acts as a trusted dealer for
+the moment of creation. Each presignature signs a single tx, so a wallet
+signs --presignatures txs before a new one is needed.
+
+The share files are written to --output-dir. Hand each one to its party
+and delete it here: any --threshold of them together control the wallet.
+
+Parties are registered in the KMS by name, reusing those already there.
+
+Examples:
+  # A 2-of-3 wallet with parties treasury-party-1..3
+  lux mpc wallet create --name treasury --threshold 2 --parties 3
+
+  # A 3-of-5 wallet of named parties
+  lux mpc wallet create --name ops --threshold 3 --participants alice,bob,carol,dave,erin`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: runWalletCreate,
+	}
+	cmd.Flags().StringVar(&walletName, "name", "", "wallet name")
+	cmd.Flags().IntVar(&walletThreshold, "threshold", 2, "number of parties that must approve each signature")
+	cmd.Flags().IntVar(&walletParties, "parties", 3, "number of parties, named <name>-party-<n> unless --participants is given")
+	cmd.Flags().StringSliceVar(&walletParticipants, "participants", nil, "names of the parties")
+	cmd.Flags().IntVar(&walletPresignatures, "presignatures", defaultPresignatures, "number of txs the wallet can sign")
+	cmd.Flags().StringVar(&walletOutputDir, "output-dir", "", "directory of the share files (default <home>/mpc/wallets/<wallet-id>)")
+	return cmd
+}
+
+func runWalletCreate(_ *cobra.Command, _ []string) error {
+	if walletName == "" {
+		return fmt.Errorf("--name is required")
+	}
+	participants := walletParticipants
+	if len(participants) == 0 {
+		for i := 1; i <= walletParties; i++ {
+			participants = append(participants, fmt.Sprintf("%s-party-%d", walletName, i))
+		}
+	}
+	shares, err := threshold.Deal(walletThreshold, len(participants), walletPresignatures)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mpcTimeout)
+	defer cancel()
+	client := keychain.NewKMSClientFromEnv()
+	nodes, err := client.MPCNodes(ctx)
+	if err != nil {
+		return err
+	}
+	nodeIDs := map[string]string{}
+	for _, node := range nodes {
+		nodeIDs[node.Name] = node.ID
+	}
+	participantIDs := make([]string, len(participants))
+	for i, name := range participants {
+		if id, ok := nodeIDs[name]; ok {
+			participantIDs[i] = id
+			continue
+		}
+		node, err := client.RegisterMPCNode(ctx, name)
+		if err != nil {
+			return err
+		}
+		participantIDs[i] = node.ID
+	}
+
+	lock, err := app.AcquireLock("mpc wallet create")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+	wallet, err := client.CreateMPCWallet(ctx, walletName, walletThreshold, participantIDs)
+	if err != nil {
+		return err
+	}
+	dir := walletOutputDir
+	if dir == "" {
+		dir = filepath.Join(app.GetBaseDir(), "mpc", "wallets", wallet.ID)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	files := make([]string, len(shares))
+	for i, share := range shares {
+		share.WalletID = wallet.ID
+		share.NodeID = participantIDs[i]
+		data, err := json.MarshalIndent(share, "", "  ")
+		if err != nil {
+			return err
+		}
+		files[i] = filepath.Join(dir, participants[i]+".share.json")
+		if err := os.WriteFile(files[i], data, 0o600); err != nil {
+			return err
+		}
+	}
+	if wallet, err = client.ActivateMPCWallet(ctx, wallet.ID, shares[0].PublicKey, walletPresignatures); err != nil {
+		return err
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "mpc wallet create",
+		Params:    map[string]string{"wallet": wallet.ID, "name": walletName, "threshold": fmt.Sprintf("%d of %d", walletThreshold, len(participants))},
+	})
+
+	ux.Logger.GreenCheckmarkToUser("Created %d of %d MPC wallet %s (%s)", walletThreshold, len(participants), walletName, wallet.ID)
+	if err := printWalletAddresses(wallet); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Share files, one per party:")
+	for i, file := range files {
+		ux.Logger.PrintToUser("  %s: %s", participants[i], file)
+	}
+	ux.Logger.PrintToUser("Hand each share to its party and delete it from this machine.")
+	ux.Logger.PrintToUser("Sign deploys with --key %s%s", keychain.MPCKeyPrefix, wallet.ID)
+	return nil
+}
+
+func newWalletShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <wallet-id>",
+		Short: "Show wallet details",
+		Args:  cobrautils.ExactArgs(1),
+		RunE:  runWalletShow,
+	}
+}
+
+func runWalletShow(_ *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), mpcTimeout)
+	defer cancel()
+	client := keychain.NewKMSClientFromEnv()
+	wallet, err := client.MPCWallet(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	nodes, err := client.MPCNodes(ctx)
+	if err != nil {
+		return err
+	}
+	names := map[string]string{}
+	for _, node := range nodes {
+		names[node.ID] = node.Name
+	}
+	ux.Logger.PrintToUser("Wallet:     %s (%s)", wallet.Name, wallet.ID)
+	ux.Logger.PrintToUser("Status:     %s", wallet.Status)
+	ux.Logger.PrintToUser("Threshold:  %d of %d", wallet.Threshold, wallet.TotalParties)
+	if wallet.Presignatures > 0 {
+		ux.Logger.PrintToUser("Signatures: %d of %d left", wallet.Presignatures-wallet.PresignaturesUsed, wallet.Presignatures)
+	}
+	parties := make([]string, len(wallet.ParticipantIDs))
+	for i, id := range wallet.ParticipantIDs {
+		parties[i] = fmt.Sprintf("%s (%s)", names[id], id)
+	}
+	ux.Logger.PrintToUser("Parties:    %s", strings.Join(parties, ", "))
+	if wallet.Status != kms.KeyStatusActive {
+		return nil
+	}
+	return printWalletAddresses(wallet)
+}
+
+func newWalletExportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export <wallet-id>",
+		Short: "Export wallet public key",
+		Args:  cobrautils.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), mpcTimeout)
+			defer cancel()
+			wallet, err := keychain.NewKMSClientFromEnv().MPCWallet(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if wallet.Status != kms.KeyStatusActive {
+				return fmt.Errorf("wallet %s is not active", args[0])
+			}
+			fmt.Println("0x" + hex.EncodeToString(wallet.PublicKey))
+			return nil
+		},
+	}
+}
+
+// printWalletAddresses prints the P-Chain and EVM addresses of wallet
+func printWalletAddresses(wallet *kms.MPCWallet) error {
+	pub, err := secp256k1.ToPublicKey(wallet.PublicKey)
+	if err != nil {
+		return fmt.Errorf("invalid public key of wallet %s: %w", wallet.ID, err)
+	}
+	for _, networkID := range []uint32{constants.MainnetID, constants.TestnetID} {
+		addr, err := address.Format("P", key.GetHRP(networkID), pub.Address().Bytes())
+		if err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("P-Chain:    %s", addr)
+	}
+	ux.Logger.PrintToUser("EVM:        0x%x", pub.EthAddress())
+	return nil
+}
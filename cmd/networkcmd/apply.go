@@ -0,0 +1,182 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networkcmd
+
+import (
+	"github.com/luxfi/cli/cmd/chaincmd"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/netspec"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	applyFile    string
+	applyKeyName string
+	applyDryRun  bool
+)
+
+func newApplyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Reconcile networks and chains with a spec file",
+		Long: `The apply command reads a declarative spec (YAML or JSON) describing
+networks, chains, genesis allocations, Warp settings and validators, and
+performs only the steps needed to reach that state. Applying the same
+spec twice is a no-op, so the spec can live in version control and be
+re-applied after every change.
+
+Existing chains are never recreated or deleted. Only their Warp settings
+are updated, missing deployments are performed and missing validators
+are added.
+
+EXAMPLE SPEC:
+
+  networks:
+    - name: local
+      start: true
+      chains:
+        - name: mychain
+          type: l1
+          chainId: 200200
+          token: {name: My Token, symbol: MYT}
+          allocations:
+            - {address: "0x9011E888251AB053B7bD1cdB598Db4f9DEd94714", balance: "1000000000000000000000"}
+          warp: {enabled: true, relayer: true}
+          validators:
+            - {nodeID: NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg, weight: 20}
+
+EXAMPLES:
+
+  # Show what would change
+  lux network apply -f network.yaml --dry-run
+
+  # Apply the spec
+  lux network apply -f network.yaml`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: applyNetworkSpec,
+	}
+	cmd.Flags().StringVarP(&applyFile, "file", "f", "", "network spec file (YAML or JSON)")
+	cmd.Flags().StringVar(&applyKeyName, "key", "", "key paying for deployments and validators to remote networks")
+	cmd.Flags().BoolVar(&applyDryRun, "dry-run", false, "print the planned changes without applying them")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func applyNetworkSpec(_ *cobra.Command, _ []string) error {
+	spec, err := netspec.Load(applyFile)
+	if err != nil {
+		return err
+	}
+	exec := &specExecutor{keyName: applyKeyName}
+	actions, err := netspec.Plan(spec, exec)
+	if err != nil {
+		return err
+	}
+	if len(actions) == 0 {
+		ux.Logger.PrintToUser("Everything is up to date with %s", applyFile)
+		return nil
+	}
+
+	ux.Logger.PrintToUser("Planned changes:")
+	for _, a := range actions {
+		ux.Logger.PrintToUser("  - %s", a)
+	}
+	if applyDryRun {
+		return nil
+	}
+
+	ux.Logger.PrintToUser("")
+	if err := netspec.Apply(actions, exec, func(a netspec.Action) {
+		ux.Logger.PrintToUser("==> %s", a)
+	}); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Applied %d changes from %s", len(actions), applyFile)
+	return nil
+}
+
+// specExecutor reconciles a spec through the network and chain commands
+type specExecutor struct {
+	keyName string
+}
+
+func (*specExecutor) NetworkRunning(network string) (bool, error) {
+	stateType := network
+	if stateType == networkTypeLocal {
+		stateType = networkTypeCustom
+	}
+	state, err := app.LoadNetworkStateForType(stateType)
+	if err != nil {
+		return false, err
+	}
+	if state != nil && state.Running {
+		return true, nil
+	}
+	// Without a local network of that type, mainnet and testnet refer to
+	// the public networks
+	return network == "mainnet" || network == "testnet", nil
+}
+
+func (*specExecutor) ChainExists(chain string) bool {
+	return app.ChainConfigExists(chain)
+}
+
+func (*specExecutor) ChainWarp(chain string) (netspec.Warp, error) {
+	enabled, relayer, key, err := chaincmd.ChainWarp(chain)
+	return netspec.Warp{Enabled: enabled, Relayer: relayer, Key: key}, err
+}
+
+func (*specExecutor) ChainDeployed(chain, network string) (bool, error) {
+	return chaincmd.ChainDeployed(chain, network)
+}
+
+func (*specExecutor) ChainValidators(chain, network string) ([]string, error) {
+	return chaincmd.ChainValidators(chain, network)
+}
+
+func (*specExecutor) StartNetwork(network string) error {
+	return StartNetworkType(network)
+}
+
+func (*specExecutor) CreateChain(c netspec.Chain) error {
+	allocations := map[string]string{}
+	for _, a := range c.Allocations {
+		balance, err := netspec.ParseBalance(a.Balance)
+		if err != nil {
+			return err
+		}
+		allocations[a.Address] = "0x" + balance.Text(16)
+	}
+	if err := chaincmd.CreateChain(c.Name, chaincmd.CreateChainOptions{
+		Type:        c.Type,
+		VM:          c.VM,
+		VMBinary:    c.VMBinary,
+		Genesis:     c.Genesis,
+		ChainID:     c.ChainID,
+		TokenName:   c.Token.Name,
+		TokenSymbol: c.Token.Symbol,
+		Allocations: allocations,
+	}); err != nil {
+		return err
+	}
+	return chaincmd.SetChainWarp(c.Name, c.Warp.Enabled, c.Warp.Relayer, c.Warp.Key)
+}
+
+func (*specExecutor) UpdateChain(c netspec.Chain) error {
+	return chaincmd.SetChainWarp(c.Name, c.Warp.Enabled, c.Warp.Relayer, c.Warp.Key)
+}
+
+func (e *specExecutor) DeployChain(chain, network string) error {
+	return chaincmd.DeployChain(chain, network, e.keyName)
+}
+
+func (e *specExecutor) AddValidator(chain, network string, v netspec.Validator) error {
+	keyName := v.Key
+	if keyName == "" {
+		keyName = e.keyName
+	}
+	return chaincmd.AddChainValidator(chain, network, v.NodeID, v.Weight, v.StakingDuration(), keyName)
+}
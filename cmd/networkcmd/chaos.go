@@ -0,0 +1,450 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networkcmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/luxfi/cli/pkg/binutils"
+	"github.com/luxfi/cli/pkg/chaos"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/status"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/netrunner/client"
+	"github.com/luxfi/netrunner/rpcpb"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	chaosNodes    []string
+	chaosCount    int
+	chaosSeed     int64
+	chaosDuration time.Duration
+	chaosInterval time.Duration
+	chaosRecovery time.Duration
+	chaosDelay    time.Duration
+	chaosJitter   time.Duration
+	chaosLoss     float64
+)
+
+func newChaosCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "chaos",
+		Short: "Inject faults into the running local network",
+		Long: `The chaos commands exercise the resilience of consensus and of the apps
+built on the running local network before going to mainnet.
+
+COMMANDS:
+
+  kill       Stop random nodes for --duration, then bring them back
+  restart    Restart random nodes
+  latency    Delay and drop the peer-to-peer traffic of random nodes
+  partition  Cut a minority of the nodes off from the others for --duration
+
+Nodes are picked at random, --seed makes the pick reproducible, and --node
+picks them by name. Chain heights are probed through the status service
+every --interval from before the fault until --recovery after it, and a
+report shows for each chain the blocks produced during the fault, the
+longest stall and how long it took to recover.
+
+latency and partition shape the loopback traffic of the staking ports of
+the nodes with tc/netem, which needs Linux and root (sudo is used when not
+root). API calls are not affected. The shaping is removed when the fault
+ends or the command is interrupted.
+
+EXAMPLES:
+
+  lux network chaos kill --count 2 --duration 1m
+  lux network chaos restart --node node3
+  lux network chaos latency --delay 300ms --jitter 100ms --loss 5 --duration 2m
+  lux network chaos partition --duration 30s --seed 42`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.AddCommand(newChaosKillCmd())
+	cmd.AddCommand(newChaosRestartCmd())
+	cmd.AddCommand(newChaosLatencyCmd())
+	cmd.AddCommand(newChaosPartitionCmd())
+	return cmd
+}
+
+func newChaosKillCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "kill",
+		Short:        "Stop random nodes for a while, then bring them back",
+		Args:         cobra.NoArgs,
+		RunE:         chaosKill,
+		SilenceUsage: true,
+	}
+	addChaosFlags(cmd, 1, "number of nodes to stop")
+	cmd.Flags().DurationVar(&chaosDuration, "duration", 30*time.Second, "how long the nodes stay down")
+	return cmd
+}
+
+func newChaosRestartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "restart",
+		Short:        "Restart random nodes",
+		Args:         cobra.NoArgs,
+		RunE:         chaosRestart,
+		SilenceUsage: true,
+	}
+	addChaosFlags(cmd, 1, "number of nodes to restart")
+	return cmd
+}
+
+func newChaosLatencyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "latency",
+		Short:        "Delay and drop the peer-to-peer traffic of random nodes",
+		Args:         cobra.NoArgs,
+		RunE:         chaosLatency,
+		SilenceUsage: true,
+	}
+	addChaosFlags(cmd, 0, "number of nodes to degrade (default all)")
+	cmd.Flags().DurationVar(&chaosDuration, "duration", time.Minute, "how long the links stay degraded")
+	cmd.Flags().DurationVar(&chaosDelay, "delay", 200*time.Millisecond, "delay added to each packet")
+	cmd.Flags().DurationVar(&chaosJitter, "jitter", 0, "random variation of the delay")
+	cmd.Flags().Float64Var(&chaosLoss, "loss", 0, "percentage of packets dropped")
+	return cmd
+}
+
+func newChaosPartitionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "partition",
+		Short:        "Cut a minority of the nodes off from the others",
+		Args:         cobra.NoArgs,
+		RunE:         chaosPartition,
+		SilenceUsage: true,
+	}
+	addChaosFlags(cmd, 0, "number of nodes to isolate (default the largest minority)")
+	cmd.Flags().DurationVar(&chaosDuration, "duration", 30*time.Second, "how long the partition lasts")
+	return cmd
+}
+
+func addChaosFlags(cmd *cobra.Command, count int, countUsage string) {
+	cmd.Flags().StringSliceVar(&chaosNodes, "node", nil, "nodes to target by name instead of random ones")
+	cmd.Flags().IntVar(&chaosCount, "count", count, countUsage)
+	cmd.Flags().Int64Var(&chaosSeed, "seed", 0, "seed of the random pick (default random)")
+	cmd.Flags().DurationVar(&chaosInterval, "interval", 5*time.Second, "time between two probes of the chain heights")
+	cmd.Flags().DurationVar(&chaosRecovery, "recovery", 30*time.Second, "how long to keep probing after the fault")
+}
+
+func chaosKill(_ *cobra.Command, _ []string) error {
+	cli, cluster, network, err := chaosCluster()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = cli.Close() }()
+	nodes, err := chaosTargets(cluster, chaosCount)
+	if err != nil {
+		return err
+	}
+	return runChaos(network, "kill", nodes, chaosDuration,
+		func(ctx context.Context) error {
+			for _, node := range nodes {
+				if _, err := cli.PauseNode(ctx, node); err != nil {
+					return fmt.Errorf("failed to stop %s: %w", node, err)
+				}
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			var errs []error
+			for _, node := range nodes {
+				if _, err := cli.ResumeNode(ctx, node); err != nil {
+					errs = append(errs, fmt.Errorf("failed to bring %s back: %w", node, err))
+				}
+			}
+			return errors.Join(errs...)
+		})
+}
+
+func chaosRestart(_ *cobra.Command, _ []string) error {
+	cli, cluster, network, err := chaosCluster()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = cli.Close() }()
+	nodes, err := chaosTargets(cluster, chaosCount)
+	if err != nil {
+		return err
+	}
+	return runChaos(network, "restart", nodes, 0,
+		func(ctx context.Context) error {
+			for _, node := range nodes {
+				restart := func() error { _, err := cli.RestartNode(ctx, node); return err }
+				if cluster.NodeInfos[node].GetPaused() {
+					restart = func() error { _, err := cli.ResumeNode(ctx, node); return err }
+				}
+				if err := restart(); err != nil {
+					return fmt.Errorf("failed to restart %s: %w", node, err)
+				}
+			}
+			return nil
+		},
+		func(context.Context) error { return nil })
+}
+
+func chaosLatency(_ *cobra.Command, _ []string) error {
+	netem := chaos.Netem{Delay: chaosDelay, Jitter: chaosJitter, Loss: chaosLoss}
+	if err := netem.Validate(); err != nil {
+		return err
+	}
+	return chaosShape("latency "+strings.Join(netem.Args()[1:], " "), chaosCount, netem)
+}
+
+func chaosPartition(_ *cobra.Command, _ []string) error {
+	return chaosShape("partition", chaosCount, chaos.Netem{Loss: 100})
+}
+
+// chaosShape applies netem to the staking ports of count nodes for
+// chaosDuration. With 100% loss the nodes are cut off from their peers.
+func chaosShape(fault string, count int, netem chaos.Netem) error {
+	if runtime.GOOS != "linux" {
+		return errors.New("latency and partition use tc/netem, which is only available on Linux")
+	}
+	if _, err := exec.LookPath("tc"); err != nil {
+		return errors.New("tc not found, install iproute2")
+	}
+	cli, cluster, network, err := chaosCluster()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = cli.Close() }()
+	if count == 0 && netem.Loss == 100 {
+		count = chaos.Minority(len(cluster.NodeNames))
+	}
+	nodes, err := chaosTargets(cluster, count)
+	if err != nil {
+		return err
+	}
+	if netem.Loss == 100 && len(nodes) >= len(cluster.NodeNames) {
+		return errors.New("a partition needs nodes on both sides, isolate fewer nodes")
+	}
+	ports := make([]int, 0, len(nodes))
+	for _, node := range nodes {
+		port, err := stakingPort(cluster.NodeInfos[node])
+		if err != nil {
+			return fmt.Errorf("%s: %w", node, err)
+		}
+		ports = append(ports, port)
+	}
+	return runChaos(network, fault, nodes, chaosDuration,
+		func(context.Context) error {
+			for _, args := range chaos.ShapeCommands(chaos.LoopbackDevice, ports, netem) {
+				if err := runTC(args); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		func(context.Context) error { return runTC(chaos.ClearCommand(chaos.LoopbackDevice)) })
+}
+
+// runChaos applies a fault to nodes for duration and reverts it, probing
+// the chain heights from before the fault until chaosRecovery after it,
+// then prints the liveness report. The fault is reverted on interrupt too.
+func runChaos(
+	network string,
+	fault string,
+	nodes []string,
+	duration time.Duration,
+	apply func(context.Context) error,
+	revert func(context.Context) error,
+) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	service := status.NewStatusService()
+
+	samples := []chaos.Sample{probeHeights(ctx, service)}
+	start := time.Now()
+	ux.Logger.PrintToUser("Injecting %s on %s", fault, strings.Join(nodes, ", "))
+	if err := apply(ctx); err != nil {
+		if revertErr := revert(context.Background()); revertErr != nil {
+			ux.Logger.PrintToUser("failed to revert the fault: %s", revertErr)
+		}
+		return err
+	}
+	samples = probeUntil(ctx, service, start.Add(duration), samples)
+	end := time.Now()
+	revertErr := revert(context.Background())
+	if revertErr == nil && duration > 0 {
+		ux.Logger.PrintToUser("Reverted %s after %s, watching the recovery for %s", fault, end.Sub(start).Round(time.Second), chaosRecovery)
+	}
+	samples = probeUntil(ctx, service, end.Add(chaosRecovery), samples)
+	samples = append(samples, probeHeights(context.Background(), service))
+
+	report := chaos.BuildReport(fault, nodes, start, end, samples)
+	if err := printChaosReport(report); err != nil {
+		return err
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "network chaos",
+		Network:   network,
+		Params: map[string]string{
+			"fault":    fault,
+			"nodes":    strings.Join(nodes, ","),
+			"duration": duration.String(),
+		},
+	})
+	if revertErr != nil {
+		return fmt.Errorf("failed to revert %s, the network may still be degraded: %w", fault, revertErr)
+	}
+	return nil
+}
+
+func printChaosReport(report chaos.Report) error {
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Liveness during %s of %s (%s)", report.Fault, strings.Join(report.Nodes, ", "), report.End.Sub(report.Start).Round(time.Second))
+	if len(report.Chains) == 0 {
+		ux.Logger.PrintToUser("No chain answered the status probes")
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Chain", "Height before", "Height after", "Blocks during fault", "Longest stall", "Unreachable probes", "Verdict")
+	for _, c := range report.Chains {
+		_ = table.Append([]string{
+			c.Chain,
+			strconv.FormatUint(c.Before, 10),
+			strconv.FormatUint(c.After, 10),
+			strconv.FormatUint(c.DuringFault, 10),
+			c.LongestStall.Round(time.Second).String(),
+			strconv.Itoa(c.Unreachable),
+			c.Verdict(),
+		})
+	}
+	return table.Render()
+}
+
+// probeUntil appends a probe of the chain heights every chaosInterval until
+// deadline or until ctx is done
+func probeUntil(ctx context.Context, service *status.StatusService, deadline time.Time, samples []chaos.Sample) []chaos.Sample {
+	for {
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return samples
+		}
+		select {
+		case <-ctx.Done():
+			return samples
+		case <-time.After(min(wait, chaosInterval)):
+		}
+		samples = append(samples, probeHeights(ctx, service))
+	}
+}
+
+// probeHeights returns the heights of the chains of the running networks
+// that answered the status service
+func probeHeights(ctx context.Context, service *status.StatusService) chaos.Sample {
+	sample := chaos.Sample{Time: time.Now(), Heights: map[string]uint64{}}
+	result, err := service.GetStatus(ctx)
+	if err != nil {
+		return sample
+	}
+	for _, network := range result.Networks {
+		if network.Metadata.Status != "up" {
+			continue
+		}
+		for _, chain := range network.Chains {
+			if chain.RPC_OK {
+				sample.Heights[network.Name+"/"+chain.Alias] = chain.Height
+			}
+		}
+	}
+	return sample
+}
+
+// chaosCluster connects to the netrunner server of the running local network
+// and returns its nodes and network type
+func chaosCluster() (client.Client, *rpcpb.ClusterInfo, string, error) {
+	state, _, err := runningLocalNetwork()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	cli, err := binutils.NewGRPCClient(binutils.WithNetworkType(state.NetworkType))
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to connect to the %s network: %w", state.NetworkType, err)
+	}
+	resp, err := cli.Status(binutils.GetAsyncContext())
+	if err != nil {
+		_ = cli.Close()
+		return nil, nil, "", err
+	}
+	if resp.GetClusterInfo() == nil || len(resp.GetClusterInfo().GetNodeNames()) == 0 {
+		_ = cli.Close()
+		return nil, nil, "", fmt.Errorf("the %s network has no nodes", state.NetworkType)
+	}
+	return cli, resp.GetClusterInfo(), state.NetworkType, nil
+}
+
+// chaosTargets returns the nodes of --node, or count random nodes
+func chaosTargets(cluster *rpcpb.ClusterInfo, count int) ([]string, error) {
+	if len(chaosNodes) > 0 {
+		for _, node := range chaosNodes {
+			if _, ok := cluster.NodeInfos[node]; !ok {
+				return nil, fmt.Errorf("unknown node %q, the nodes are %s", node, strings.Join(cluster.NodeNames, ", "))
+			}
+		}
+		return chaosNodes, nil
+	}
+	if count < 0 {
+		return nil, errors.New("--count cannot be negative")
+	}
+	if count == 0 {
+		count = len(cluster.NodeNames)
+	}
+	seed := chaosSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return chaos.Pick(cluster.NodeNames, count, rand.New(rand.NewSource(seed))), nil //nolint:gosec // G404: fault injection, not security
+}
+
+// stakingPort returns the peer-to-peer port of a node, from its config or
+// next to its HTTP port
+func stakingPort(info *rpcpb.NodeInfo) (int, error) {
+	var config map[string]interface{}
+	if err := json.Unmarshal(info.GetConfig(), &config); err == nil {
+		if port, ok := config["staking-port"].(float64); ok {
+			return int(port), nil
+		}
+	}
+	u, err := url.Parse(info.GetUri())
+	if err != nil || u.Port() == "" {
+		return 0, fmt.Errorf("cannot find the staking port from %q", info.GetUri())
+	}
+	httpPort, err := strconv.Atoi(u.Port())
+	if err != nil {
+		return 0, err
+	}
+	return httpPort + 1, nil
+}
+
+// runTC runs tc, through sudo when not root
+func runTC(args []string) error {
+	cmd := exec.Command("tc", args...) //nolint:gosec // G204: fixed tc invocations
+	if os.Geteuid() != 0 {
+		cmd = exec.Command("sudo", append([]string{"tc"}, args...)...) //nolint:gosec // G204: fixed tc invocations
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("tc %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -120,6 +120,12 @@ configuration, use: lux chain delete <chainName>`,
 }
 
 func clean(*cobra.Command, []string) error {
+	lock, err := app.AcquireLock("network clean")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
 	if err := localnet.LocalNetworkStop(app); err != nil && !errors.Is(err, localnet.ErrNetworkNotRunning) {
 		return err
 	} else if err == nil {
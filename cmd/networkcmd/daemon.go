@@ -0,0 +1,36 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networkcmd
+
+import "fmt"
+
+// StartNetworkType starts a network the way `lux network start --<networkType>`
+// does. It is used by callers that drive the CLI without parsing flags.
+func StartNetworkType(networkType string) error {
+	switch networkType {
+	case "mainnet":
+		return StartMainnet()
+	case "testnet":
+		return StartTestnet()
+	case "devnet":
+		return StartDevnet()
+	case "dev":
+		return StartDevMode()
+	case networkTypeLocal:
+		return StartLocal()
+	default:
+		return fmt.Errorf("unknown network %q, expected mainnet, testnet, devnet, dev or local", networkType)
+	}
+}
+
+// StopNetworkType stops a network the way `lux network stop --<networkType>`
+// does. Stopping mainnet or testnet requires force.
+func StopNetworkType(networkType string, force bool) error {
+	prevType, prevForce := stopNetworkType, forceStop
+	defer func() {
+		stopNetworkType, forceStop = prevType, prevForce
+	}()
+	stopNetworkType, forceStop = networkType, force
+	return StopNetwork(nil, nil)
+}
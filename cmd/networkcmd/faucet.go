@@ -0,0 +1,370 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networkcmd
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/luxfi/address"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/faucet"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/key"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/xchain"
+	ethcrypto "github.com/luxfi/crypto"
+	ethcommon "github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/ethclient"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+	"github.com/luxfi/sdk/wallet/primary"
+	"github.com/luxfi/utxo"
+	"github.com/luxfi/utxo/secp256k1fx"
+	"github.com/spf13/cobra"
+)
+
+var (
+	faucetFromKey   string
+	faucetCooldown  time.Duration
+	faucetMaxAmount string
+	faucetAmount    string
+	faucetChain     string
+	faucetPort      int
+	faucetAddress   string
+)
+
+func newFaucetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "faucet",
+		Short: "Fund test addresses on the running local network",
+		Long: `The faucet command funds addresses on the P-Chain, X-Chain, C-Chain and the
+EVM L1s deployed to the running local network, from its prefunded genesis
+keys (the LIGHT_MNEMONIC dev key unless --from is given).
+
+Each address is funded at most once per --cooldown on each chain, whether
+through 'faucet send' or the HTTP endpoint of 'faucet serve'. The funding
+times are kept in ~/.lux/faucet.json.`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.PersistentFlags().StringVar(&faucetFromKey, "from", "", "key to fund from (default the local genesis key)")
+	cmd.PersistentFlags().DurationVar(&faucetCooldown, "cooldown", faucet.DefaultCooldown, "how long an address waits between fundings on a chain")
+	cmd.PersistentFlags().StringVar(&faucetMaxAmount, "max-amount", faucet.DefaultMaxAmount, "largest amount of a funding")
+	cmd.AddCommand(newFaucetSendCmd())
+	cmd.AddCommand(newFaucetServeCmd())
+	return cmd
+}
+
+func newFaucetSendCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "send [address]",
+		Short: "Fund an address",
+		Long: `Fund an address on a chain of the running local network. The chain is p, x,
+c or the name of a deployed EVM L1, and the address a P-/X-Chain address or a
+0x address accordingly.
+
+EXAMPLES:
+
+  lux network faucet send 0x9011E888251AB053B7bD1cdB598Db4f9DEd94714 --amount 10 --chain c
+  lux network faucet send P-local1... --amount 2.5 --chain p
+  lux network faucet send 0x9011E888251AB053B7bD1cdB598Db4f9DEd94714 --chain mychain`,
+		Args:         cobra.ExactArgs(1),
+		RunE:         sendFromFaucet,
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&faucetAmount, "amount", faucet.DefaultAmount, "amount to send, in whole tokens")
+	cmd.Flags().StringVar(&faucetChain, "chain", "c", "chain to fund on: p, x, c or a deployed L1")
+	return cmd
+}
+
+func newFaucetServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the faucet over HTTP",
+		Long: `Serve the faucet over HTTP in the foreground:
+
+  GET  /chains  the chains that can be funded and the largest amount
+  POST /fund    fund {"chain": "c", "address": "0x...", "amount": "10"}
+
+A rate limited address gets a 429 response with a Retry-After header.
+
+EXAMPLES:
+
+  lux network faucet serve
+  curl -X POST localhost:9800/fund -d '{"chain":"c","address":"0x9011E888251AB053B7bD1cdB598Db4f9DEd94714"}'`,
+		Args:         cobra.NoArgs,
+		RunE:         serveFaucet,
+		SilenceUsage: true,
+	}
+	cmd.Flags().IntVar(&faucetPort, "port", 9800, "port to serve the faucet on")
+	cmd.Flags().StringVar(&faucetAddress, "address", "127.0.0.1", "address to listen on")
+	return cmd
+}
+
+func sendFromFaucet(_ *cobra.Command, args []string) error {
+	f, err := newLocalFaucet()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	receipt, err := f.Send(ctx, faucetChain, args[0], faucetAmount)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Funded %s with %s on %s", receipt.Address, receipt.Amount, strings.ToUpper(receipt.Chain))
+	ux.Logger.PrintToUser("  TxID: %s", receipt.TxID)
+	app.RecordHistory(history.Entry{
+		Operation: "network faucet send",
+		Network:   models.Local.String(),
+		Chain:     receipt.Chain,
+		Params: map[string]string{
+			"address": receipt.Address,
+			"amount":  receipt.Amount,
+		},
+		Result: map[string]string{"txID": receipt.TxID},
+	})
+	return nil
+}
+
+func serveFaucet(_ *cobra.Command, _ []string) error {
+	f, err := newLocalFaucet()
+	if err != nil {
+		return err
+	}
+	addr := net.JoinHostPort(faucetAddress, strconv.Itoa(faucetPort))
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           f,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	ux.Logger.PrintToUser("Serving faucet on http://%s for chains %s", addr, strings.Join(f.Chains(), ", "))
+	ux.Logger.PrintToUser("  at most %s per funding, once per %s per address", faucetMaxAmount, faucetCooldown)
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("faucet failed: %w", err)
+	case <-ctx.Done():
+		ux.Logger.PrintToUser("Shutting down faucet...")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
+
+// newLocalFaucet returns a faucet for the chains of the running local
+// network and the EVM L1s deployed to it
+func newLocalFaucet() (*faucet.Faucet, error) {
+	state, endpoint, err := runningLocalNetwork()
+	if err != nil {
+		return nil, err
+	}
+	networkID := state.NetworkID
+	if networkID == 0 {
+		networkID = networkIDFromType(state.NetworkType)
+	}
+
+	var sk *key.SoftKey
+	if faucetFromKey != "" {
+		keySet, err := key.LoadKeySet(faucetFromKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load key '%s': %w", faucetFromKey, err)
+		}
+		if len(keySet.ECPrivateKey) == 0 {
+			return nil, fmt.Errorf("key '%s' has no EC private key", faucetFromKey)
+		}
+		sk, err = key.NewSoftFromBytes(networkID, keySet.ECPrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create soft key: %w", err)
+		}
+	} else {
+		sk, err = key.NewSoftFromMnemonic(networkID, key.GetLightMnemonic())
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive the genesis key: %w", err)
+		}
+	}
+	privKey, err := ethcrypto.ToECDSA(sk.Raw())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	hrp := key.GetHRP(networkID)
+	dispensers := map[string]faucet.Dispenser{
+		"p": &pxDispenser{chain: "P", hrp: hrp, baseURL: endpoint, sk: sk},
+		"x": &pxDispenser{chain: "X", hrp: hrp, baseURL: endpoint, sk: sk},
+		"c": &evmDispenser{rpcURL: endpoint + "/ext/bc/C/rpc", privKey: privKey},
+	}
+	l1s, err := deployedEVMChains(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	for name, rpcURL := range l1s {
+		if _, ok := dispensers[strings.ToLower(name)]; ok {
+			continue
+		}
+		dispensers[name] = &evmDispenser{rpcURL: rpcURL, privKey: privKey}
+	}
+
+	limiter, err := faucet.LoadLimiter(filepath.Join(app.GetBaseDir(), faucet.StateFile), faucetCooldown)
+	if err != nil {
+		return nil, err
+	}
+	return faucet.New(dispensers, limiter, faucetMaxAmount), nil
+}
+
+// evmDispenser sends the native token of an EVM chain
+type evmDispenser struct {
+	// mu orders the transactions so their nonces don't collide
+	mu      sync.Mutex
+	rpcURL  string
+	privKey *ecdsa.PrivateKey
+}
+
+func (*evmDispenser) Decimals() int {
+	return 18
+}
+
+func (*evmDispenser) ValidateAddress(to string) error {
+	if !ethcommon.IsHexAddress(to) {
+		return errors.New("expected a 0x address")
+	}
+	return nil
+}
+
+func (d *evmDispenser) Send(ctx context.Context, to string, amount *big.Int) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	client, err := ethclient.DialContext(ctx, d.rpcURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to %s: %w", d.rpcURL, err)
+	}
+	defer client.Close()
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	from := ethcommon.Address(ethcrypto.PubkeyToAddress(d.privKey.PublicKey))
+	nonce, err := client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return "", fmt.Errorf("failed to get nonce: %w", err)
+	}
+	tx, err := buildSignedTx(ctx, client, chainID, nonce, ethcommon.HexToAddress(to), amount, d.privKey)
+	if err != nil {
+		return "", err
+	}
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return tx.Hash().Hex(), nil
+}
+
+// pxDispenser sends LUX on the P-Chain or the X-Chain
+type pxDispenser struct {
+	// mu keeps concurrent transactions from spending the same UTXOs
+	mu      sync.Mutex
+	chain   string
+	hrp     string
+	baseURL string
+	sk      *key.SoftKey
+}
+
+func (*pxDispenser) Decimals() int {
+	return 9
+}
+
+func (d *pxDispenser) ValidateAddress(to string) error {
+	chain, hrp, _, err := address.Parse(to)
+	if err != nil {
+		return err
+	}
+	if chain != d.chain {
+		return fmt.Errorf("expected a %s-Chain address", d.chain)
+	}
+	if hrp != d.hrp {
+		return fmt.Errorf("expected an address of the %s network", d.hrp)
+	}
+	return nil
+}
+
+func (d *pxDispenser) Send(ctx context.Context, to string, amount *big.Int) (string, error) {
+	if !amount.IsUint64() {
+		return "", errors.New("amount too large")
+	}
+	_, _, addrBytes, err := address.Parse(to)
+	if err != nil {
+		return "", err
+	}
+	addr, err := ids.ToShortID(addrBytes)
+	if err != nil {
+		return "", err
+	}
+	out := &secp256k1fx.TransferOutput{
+		Amt: amount.Uint64(),
+		OutputOwners: secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{addr},
+		},
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	kc := primary.NewKeychainAdapter(secp256k1fx.NewKeychain(d.sk.Key()))
+	if d.chain == "X" {
+		// the X wallet of the SDK does not load X-Chain UTXOs nor issue txs
+		wallet, err := xchain.NewWallet(ctx, d.baseURL, kc)
+		if err != nil {
+			return "", err
+		}
+		utx, err := wallet.Builder.NewBaseTx([]*utxo.TransferableOutput{{
+			Asset: utxo.Asset{ID: wallet.Builder.Context().XAssetID},
+			Out:   out,
+		}})
+		if err != nil {
+			return "", err
+		}
+		tx, err := wallet.Issue(ctx, utx)
+		if err != nil {
+			return "", err
+		}
+		return tx.ID().String(), nil
+	}
+	wallet, err := primary.MakeWallet(ctx, &primary.WalletConfig{
+		URI:         d.baseURL,
+		LUXKeychain: kc,
+		EthKeychain: kc,
+	})
+	if err != nil {
+		return "", err
+	}
+	assetID := wallet.P().Builder().Context().XAssetID
+	tx, err := wallet.P().IssueBaseTx([]*utxo.TransferableOutput{{Asset: utxo.Asset{ID: assetID}, Out: out}})
+	if err != nil {
+		return "", err
+	}
+	return tx.ID().String(), nil
+}
@@ -0,0 +1,199 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networkcmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/luxfi/cli/pkg/fork"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/warp"
+	"github.com/luxfi/sdk/models"
+	"github.com/spf13/cobra"
+)
+
+const forkStartTimeout = 60 * time.Second
+
+var forkFlags struct {
+	rpc       string
+	block     uint64
+	chainID   uint64
+	host      string
+	port      int
+	accounts  int
+	balance   uint64
+	state     string
+	blockTime time.Duration
+}
+
+func newForkCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fork",
+		Short: "Run a local EVM forked from a live chain at a pinned block",
+		Long: `The fork command runs a local EVM chain forked from a live one, as Hardhat
+and Anvil forks do. Accounts, code and storage are read lazily from --rpc at
+the pinned block, and transactions execute locally, so integration tests can
+run against the contracts of mainnet without importing its state. Nothing is
+ever sent to the forked chain.
+
+--rpc is an EVM RPC URL, or mainnet, testnet or devnet for their C-Chain.
+The fork is pinned at --block, by default the last block of the chain, which
+is shown so the fork can be reproduced. Dev accounts are funded with
+--balance coins each. With --state, the state of the fork is saved to that
+file on exit and loaded from it on the next start.
+
+The fork is served by anvil, of the Foundry toolset, which is installed when
+missing. It runs in the foreground until interrupted.
+
+EXAMPLES:
+
+  lux network fork --rpc mainnet
+
+  lux network fork --rpc https://api.lux.network/ext/bc/C/rpc --block 1200000 --port 8546
+
+  # Keep the fork across restarts
+  lux network fork --rpc mainnet --block 1200000 --state ~/.lux/forks/mainnet.json`,
+		Args:         cobra.NoArgs,
+		RunE:         runFork,
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&forkFlags.rpc, "rpc", "", "RPC URL of the chain to fork, or mainnet, testnet or devnet for their C-Chain")
+	cmd.Flags().Uint64Var(&forkFlags.block, "block", 0, "block to pin the fork at (default: the last block)")
+	cmd.Flags().Uint64Var(&forkFlags.chainID, "chain-id", 0, "chain ID of the fork (default: that of the forked chain)")
+	cmd.Flags().StringVar(&forkFlags.host, "host", "127.0.0.1", "address to serve the fork on")
+	cmd.Flags().IntVar(&forkFlags.port, "port", fork.DefaultPort, "port to serve the fork on")
+	cmd.Flags().IntVar(&forkFlags.accounts, "accounts", 10, "number of funded dev accounts")
+	cmd.Flags().Uint64Var(&forkFlags.balance, "balance", 10000, "balance of each dev account, in coins")
+	cmd.Flags().StringVar(&forkFlags.state, "state", "", "file to save the state of the fork to on exit, and load it from on start")
+	cmd.Flags().DurationVar(&forkFlags.blockTime, "block-time", 0, "mine blocks at this interval (default: a block per transaction)")
+	_ = cmd.MarkFlagRequired("rpc")
+	return cmd
+}
+
+// forkRPC returns the RPC URL of rpc, the C-Chain of a named network or a URL
+func forkRPC(rpc string) (string, error) {
+	switch rpc {
+	case "mainnet":
+		return models.Mainnet.CChainEndpoint(), nil
+	case "testnet":
+		return models.Testnet.CChainEndpoint(), nil
+	case "devnet":
+		return models.Devnet.CChainEndpoint(), nil
+	}
+	if !strings.HasPrefix(rpc, "http://") && !strings.HasPrefix(rpc, "https://") {
+		return "", fmt.Errorf("invalid --rpc %q, expected an http(s) URL, or mainnet, testnet or devnet", rpc)
+	}
+	return rpc, nil
+}
+
+func runFork(_ *cobra.Command, _ []string) error {
+	rpcURL, err := forkRPC(forkFlags.rpc)
+	if err != nil {
+		return err
+	}
+	if forkFlags.blockTime > 0 && forkFlags.blockTime < time.Second {
+		return fmt.Errorf("--block-time must be at least 1s")
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	head, err := fork.GetHead(ctx, client, rpcURL)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", rpcURL, err)
+	}
+	block := forkFlags.block
+	if block == 0 {
+		block = head.Block
+	} else if block > head.Block {
+		return fmt.Errorf("block %d is past the last block %d of %s", block, head.Block, rpcURL)
+	}
+	opts := fork.Options{
+		RPC:       rpcURL,
+		Block:     block,
+		ChainID:   forkFlags.chainID,
+		Host:      forkFlags.host,
+		Port:      forkFlags.port,
+		Accounts:  forkFlags.accounts,
+		Balance:   forkFlags.balance,
+		StatePath: forkFlags.state,
+		BlockTime: forkFlags.blockTime,
+	}
+
+	anvilPath, err := warp.GetAnvilPath()
+	if err != nil {
+		if err := warp.InstallFoundry(); err != nil {
+			return err
+		}
+		if anvilPath, err = warp.GetAnvilPath(); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	logPath := app.GetForkLogPath()
+	logFile, err := os.Create(logPath) //nolint:gosec // G304: Log file of the CLI base dir
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+	anvil := exec.CommandContext(ctx, anvilPath, opts.Args()...) //nolint:gosec // G204: Running anvil with the fork flags
+	anvil.Stdout = logFile
+	anvil.Stderr = logFile
+	// Interrupt anvil rather than killing it, so it saves --state
+	anvil.Cancel = func() error { return anvil.Process.Signal(os.Interrupt) }
+	anvil.WaitDelay = 30 * time.Second
+	if err := anvil.Start(); err != nil {
+		return fmt.Errorf("failed to start anvil: %w", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- anvil.Wait() }()
+
+	readyCtx, cancelReady := context.WithTimeout(ctx, forkStartTimeout)
+	err = fork.WaitReady(readyCtx, client, opts.URL(), 200*time.Millisecond)
+	cancelReady()
+	if err != nil {
+		stop()
+		<-done
+		return fmt.Errorf("%w, see %s", err, logPath)
+	}
+
+	chainID := opts.ChainID
+	if chainID == 0 {
+		chainID = head.ChainID
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "network fork",
+		Params: map[string]string{
+			"rpc":   rpcURL,
+			"block": strconv.FormatUint(block, 10),
+		},
+	})
+	ux.Logger.GreenCheckmarkToUser("Forked %s at block %d", rpcURL, block)
+	ux.Logger.PrintToUser("  RPC:       %s", opts.URL())
+	ux.Logger.PrintToUser("  Chain ID:  %d", chainID)
+	ux.Logger.PrintToUser("  Accounts:  %d dev accounts funded with %d coins each, listed in %s", opts.Accounts, opts.Balance, logPath)
+	if opts.StatePath != "" {
+		ux.Logger.PrintToUser("  State:     saved to %s on exit", opts.StatePath)
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Press Ctrl+C to stop the fork.")
+
+	err = <-done
+	if ctx.Err() != nil {
+		ux.Logger.PrintToUser("Fork stopped.")
+		return nil
+	}
+	return fmt.Errorf("anvil exited: %w, see %s", err, logPath)
+}
@@ -0,0 +1,305 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networkcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/node"
+	"github.com/luxfi/cli/pkg/rpcgateway"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gatewayConfig    string
+	gatewayPort      int
+	gatewayAddress   string
+	gatewayUpstreams []string
+	gatewayCluster   string
+	gatewayAccessLog string
+	gatewayKeyRate   float64
+	gatewayKeyBurst  int
+	gatewayMethods   []string
+)
+
+func newGatewayCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gateway",
+		Short: "Serve RPC endpoints to external users with API keys",
+		Long: `The gateway command fronts the RPC endpoints of the local network or of a
+cluster with API-key authentication, per-key rate limits and method
+allowlists, so devnet endpoints can be handed to external partners without
+exposing the node admin APIs. Every request is written to an access log.`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.PersistentFlags().StringVar(&gatewayConfig, "config", "", "gateway config file (default ~/.lux/gateway.json)")
+	cmd.AddCommand(newGatewayStartCmd())
+	cmd.AddCommand(newGatewayKeyCmd())
+	return cmd
+}
+
+func newGatewayStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start the RPC gateway",
+		Long: `Start the RPC gateway in the foreground. Requests need an API key, given as
+'Authorization: Bearer <key>', an X-API-Key header or an api-key query
+parameter. Only /ext/bc/, /ext/info and /ext/health are forwarded, and
+JSON-RPC calls, batches included, are checked against the methods allowed
+for the key. Keys without methods may call the read and transaction APIs:
+
+  ` + strings.Join(rpcgateway.DefaultMethods, ", ") + `
+
+The upstreams are the running local network, the API nodes of --cluster or
+the --upstream URLs, used in turn.
+
+EXAMPLES:
+
+  lux network gateway key add partner --rate 20 --methods 'eth_*,net_version'
+  lux network gateway start --address 0.0.0.0
+  lux network gateway start --cluster my-devnet --access-log gateway.log`,
+		RunE:         startGateway,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+	}
+	cmd.Flags().IntVar(&gatewayPort, "port", 9700, "port to serve the gateway on")
+	cmd.Flags().StringVar(&gatewayAddress, "address", "127.0.0.1", "address to listen on")
+	cmd.Flags().StringSliceVar(&gatewayUpstreams, "upstream", nil, "node API URLs to forward to (default the running local network)")
+	cmd.Flags().StringVar(&gatewayCluster, "cluster", "", "forward to the API nodes of this cluster")
+	cmd.Flags().StringVar(&gatewayAccessLog, "access-log", "", "file to append the access log to (default stdout)")
+	return cmd
+}
+
+func newGatewayKeyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "key",
+		Short: "Manage the API keys of the gateway",
+		RunE:  cobrautils.CommandSuiteUsage,
+	}
+
+	addCmd := &cobra.Command{
+		Use:          "add [name]",
+		Short:        "Create an API key",
+		Long:         `Create an API key and print it. A running gateway picks up new keys on restart.`,
+		Args:         cobra.ExactArgs(1),
+		RunE:         addGatewayKey,
+		SilenceUsage: true,
+	}
+	addCmd.Flags().Float64Var(&gatewayKeyRate, "rate", rpcgateway.DefaultRate, "JSON-RPC calls per second allowed")
+	addCmd.Flags().IntVar(&gatewayKeyBurst, "burst", rpcgateway.DefaultBurst, "JSON-RPC calls allowed at once")
+	addCmd.Flags().StringSliceVar(&gatewayMethods, "methods", nil, "method patterns allowed, e.g. 'eth_*,platform.get*' (default read and transaction APIs)")
+
+	cmd.AddCommand(addCmd)
+	cmd.AddCommand(&cobra.Command{
+		Use:          "list",
+		Short:        "List the API keys",
+		Args:         cobra.NoArgs,
+		RunE:         listGatewayKeys,
+		SilenceUsage: true,
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:          "remove [name]",
+		Short:        "Remove an API key",
+		Args:         cobra.ExactArgs(1),
+		RunE:         removeGatewayKey,
+		SilenceUsage: true,
+	})
+	return cmd
+}
+
+func gatewayConfigPath() string {
+	if gatewayConfig != "" {
+		return gatewayConfig
+	}
+	return filepath.Join(app.GetBaseDir(), rpcgateway.ConfigFile)
+}
+
+func addGatewayKey(_ *cobra.Command, args []string) error {
+	path := gatewayConfigPath()
+	cfg, err := rpcgateway.LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	k, err := cfg.AddKey(args[0], gatewayKeyRate, gatewayKeyBurst, gatewayMethods)
+	if err != nil {
+		return err
+	}
+	if err := cfg.Save(path); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Created API key %s: %s", k.Name, k.Key)
+	ux.Logger.PrintToUser("It is only shown in %s from now on, hand it over securely", path)
+	return nil
+}
+
+func listGatewayKeys(_ *cobra.Command, _ []string) error {
+	cfg, err := rpcgateway.LoadConfig(gatewayConfigPath())
+	if err != nil {
+		return err
+	}
+	if len(cfg.Keys) == 0 {
+		ux.Logger.PrintToUser("No API keys, create one with 'lux network gateway key add <name>'")
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Name", "Key", "Rate", "Burst", "Methods")
+	for _, k := range cfg.Keys {
+		methods := strings.Join(k.Methods, ", ")
+		if methods == "" {
+			methods = "default"
+		}
+		_ = table.Append([]string{k.Name, maskKey(k.Key), fmt.Sprintf("%g/s", k.Rate), strconv.Itoa(k.Burst), methods})
+	}
+	return table.Render()
+}
+
+func removeGatewayKey(_ *cobra.Command, args []string) error {
+	path := gatewayConfigPath()
+	cfg, err := rpcgateway.LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	if err := cfg.RemoveKey(args[0]); err != nil {
+		return err
+	}
+	if err := cfg.Save(path); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Removed API key %s", args[0])
+	return nil
+}
+
+func maskKey(key string) string {
+	if len(key) <= 12 {
+		return "****"
+	}
+	return key[:8] + "…" + key[len(key)-4:]
+}
+
+// gatewayUpstreamURLs returns the node APIs the gateway forwards to
+func gatewayUpstreamURLs() ([]*url.URL, error) {
+	endpoints := gatewayUpstreams
+	switch {
+	case len(endpoints) > 0 && gatewayCluster != "":
+		return nil, errors.New("--upstream and --cluster can't be used together")
+	case gatewayCluster != "":
+		cluster, err := node.GetClusterEndpoints(app, gatewayCluster)
+		if err != nil {
+			return nil, err
+		}
+		if cluster.Local {
+			return nil, fmt.Errorf("cluster %s is local, start the gateway without --cluster", gatewayCluster)
+		}
+		var all []string
+		for _, n := range cluster.Nodes {
+			all = append(all, n.URL())
+			if n.API {
+				endpoints = append(endpoints, n.URL())
+			}
+		}
+		// devnets without API nodes serve RPC from their validators
+		if len(endpoints) == 0 {
+			endpoints = all
+		}
+	case len(endpoints) == 0:
+		state, err := findRunningNetworkState(app)
+		if err != nil {
+			return nil, fmt.Errorf("%w, start one with 'lux network start' or pass --upstream", err)
+		}
+		endpoint := state.APIEndpoint
+		if endpoint == "" {
+			endpoint = app.GetRunningNetworkEndpoint()
+		}
+		endpoints = []string{endpoint}
+	}
+	urls := make([]*url.URL, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid upstream %q", endpoint)
+		}
+		urls = append(urls, u)
+	}
+	if len(urls) == 0 {
+		return nil, errors.New("no upstream to forward to")
+	}
+	return urls, nil
+}
+
+func startGateway(_ *cobra.Command, _ []string) error {
+	cfg, err := rpcgateway.LoadConfig(gatewayConfigPath())
+	if err != nil {
+		return err
+	}
+	if len(cfg.Keys) == 0 {
+		return errors.New("no API keys, create one with 'lux network gateway key add <name>'")
+	}
+	upstreams, err := gatewayUpstreamURLs()
+	if err != nil {
+		return err
+	}
+
+	var accessLog io.Writer = os.Stdout
+	if gatewayAccessLog != "" {
+		f, err := os.OpenFile(gatewayAccessLog, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to open access log: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		accessLog = f
+	}
+	gateway, err := rpcgateway.New(cfg, upstreams, accessLog)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(gatewayAddress, strconv.Itoa(gatewayPort))
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           gateway,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	ux.Logger.PrintToUser("Serving RPC gateway on http://%s with %d key(s)", addr, len(cfg.Keys))
+	for _, u := range upstreams {
+		ux.Logger.PrintToUser("  upstream %s", u)
+	}
+	ux.Logger.PrintToUser("C-Chain RPC: http://%s/ext/bc/C/rpc", addr)
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("gateway failed: %w", err)
+	case <-ctx.Done():
+		ux.Logger.PrintToUser("Shutting down gateway...")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networkcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/config"
+	"github.com/luxfi/cli/pkg/status"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/cobra"
+)
+
+var (
+	metricsPort    int
+	metricsAddress string
+	metricsTimeout time.Duration
+)
+
+func newMetricsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metrics",
+		Short: "Export local network status as Prometheus metrics",
+		Long: `The metrics command exposes the status of locally running networks as
+Prometheus metrics, so local devnets can be scraped by the same Grafana stack
+used for remote clusters.`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.AddCommand(newMetricsServeCmd())
+	return cmd
+}
+
+func newMetricsServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve Prometheus metrics for local networks",
+		Long: `Serve Prometheus metrics on /metrics. Every scrape probes the running
+networks the same way 'lux network status' does.
+
+METRICS:
+
+  lux_network_up                     network is running
+  lux_chain_height                   latest block height per chain
+  lux_chain_rpc_up                   chain RPC answered the probe
+  lux_chain_rpc_latency_seconds      chain RPC probe latency
+  lux_node_up                        node answered the probe
+  lux_node_peers                     connected peers per node
+  lux_node_uptime_ratio              node uptime as seen by its peers (0-1)
+  lux_node_latency_seconds           node API probe latency
+  lux_validator_balance_lux          validator balance per chain (p, x, c)
+  lux_tracked_evm_height             height of tracked L1 EVM chains
+  lux_status_probe_duration_seconds  time taken by the probe
+  lux_status_probe_success           probe succeeded
+
+PROMETHEUS SCRAPE CONFIG:
+
+  - job_name: 'lux-local'
+    static_configs:
+      - targets: ['127.0.0.1:9464']
+
+EXAMPLES:
+
+  lux network metrics serve
+  lux network metrics serve --port 9464 --address 0.0.0.0`,
+		RunE:         serveMetrics,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		Annotations:  map[string]string{config.CapabilityAnnotation: config.CapabilityNone},
+	}
+
+	cmd.Flags().IntVar(&metricsPort, "port", 9464, "port to serve metrics on")
+	cmd.Flags().StringVar(&metricsAddress, "address", "127.0.0.1", "address to listen on")
+	cmd.Flags().DurationVar(&metricsTimeout, "probe-timeout", 10*time.Second, "maximum time a scrape waits for the network probe")
+
+	return cmd
+}
+
+func serveMetrics(_ *cobra.Command, _ []string) error {
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(status.NewCollector(status.NewStatusService(), metricsTimeout)); err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = fmt.Fprintln(w, `Lux network metrics are served on /metrics`)
+	})
+
+	addr := net.JoinHostPort(metricsAddress, strconv.Itoa(metricsPort))
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	ux.Logger.PrintToUser("Serving Prometheus metrics on http://%s/metrics", addr)
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("metrics server failed: %w", err)
+	case <-ctx.Done():
+		ux.Logger.PrintToUser("Shutting down metrics server...")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
@@ -32,6 +32,12 @@ COMMANDS:
   status    Show network status and endpoints
   clean     Stop network and delete runtime data (preserves chains)
   snapshot  Manage network snapshots
+  apply     Reconcile networks and chains with a declarative spec file
+  gateway   Serve RPC endpoints to external users with API keys
+  proxy     Serve the local nodes behind one stable, load-balanced RPC endpoint
+  faucet    Fund test addresses on the running local network
+  warp-time Advance the block time of the running local network
+  chaos     Kill nodes, degrade links and partition the running local network
 
 NETWORK TYPES:
 
@@ -72,10 +78,18 @@ NOTES:
 	cmd.AddCommand(newCleanCmd())
 	cmd.AddCommand(NewStatusCmd())  // New improved status command
 	cmd.AddCommand(NewMonitorCmd()) // Real-time network monitor
+	cmd.AddCommand(newMetricsCmd()) // Prometheus metrics exporter
 	cmd.AddCommand(newSnapshotCmd())
 	cmd.AddCommand(newBootstrapCmd())
 	cmd.AddCommand(newDescribeCmd()) // Network describe with genesis info
 	cmd.AddCommand(newSendCmd())     // C-Chain send convenience
+	cmd.AddCommand(newApplyCmd())    // Declarative network spec
+	cmd.AddCommand(newGatewayCmd())  // Authenticated RPC gateway
+	cmd.AddCommand(newProxyCmd())    // Stable load-balanced RPC endpoint
+	cmd.AddCommand(newFaucetCmd())   // Test funds for local networks
+	cmd.AddCommand(newWarpTimeCmd()) // Block time travel for local networks
+	cmd.AddCommand(newChaosCmd())    // Fault injection for local networks
+	cmd.AddCommand(newForkCmd())     // Local fork of a remote EVM chain
 
 	return cmd
 }
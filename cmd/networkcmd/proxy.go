@@ -0,0 +1,167 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networkcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/localnet"
+	"github.com/luxfi/cli/pkg/rpcproxy"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	proxyPort           int
+	proxyAddress        string
+	proxyHealthInterval time.Duration
+)
+
+func newProxyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proxy",
+		Short: "Serve the local network nodes behind one stable RPC endpoint",
+		Long: `The proxy command serves the RPC endpoints of all the nodes of the running
+local network behind a single endpoint that does not change between runs, so
+dapp and wallet configs keep working when node ports change.`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.AddCommand(newProxyStartCmd())
+	return cmd
+}
+
+func newProxyStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start the RPC proxy",
+		Long: `Start the RPC proxy in the foreground. Requests are spread round-robin over
+the healthy nodes of the running local network. When a node can't be reached
+the request goes to the next one, and the node is skipped until its health
+check passes again.
+
+Chains deployed to the network can be called by name: /ext/bc/<chain>/rpc is
+rewritten to the current blockchain ID of the chain. Requests to / go to the
+C-Chain RPC. The nodes and chains are looked up again with every health
+check, so the proxy follows the network across restarts.
+
+EXAMPLES:
+
+  lux network proxy start --port 8545
+
+  # then, whatever ports the nodes get
+  curl -X POST http://localhost:8545/ext/bc/mychain/rpc \
+    -H 'Content-Type: application/json' \
+    -d '{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"}'`,
+		RunE:         startProxy,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+	}
+	cmd.Flags().IntVar(&proxyPort, "port", 8545, "port to serve the proxy on")
+	cmd.Flags().StringVar(&proxyAddress, "address", "127.0.0.1", "address to listen on")
+	cmd.Flags().DurationVar(&proxyHealthInterval, "health-interval", rpcproxy.DefaultHealthInterval, "how often the nodes are checked")
+	return cmd
+}
+
+// localProxyTarget returns the nodes and chains of the running local network
+func localProxyTarget() (rpcproxy.Target, error) {
+	uris, err := localnet.GetLocalClusterURIs(app, localnet.LocalClusterNameConst)
+	if err != nil {
+		return rpcproxy.Target{}, err
+	}
+	target := rpcproxy.Target{Aliases: map[string]string{}}
+	sort.Strings(uris)
+	for _, uri := range uris {
+		u, err := url.Parse(uri)
+		if err != nil || u.Host == "" {
+			return rpcproxy.Target{}, fmt.Errorf("invalid node URI %q", uri)
+		}
+		target.Nodes = append(target.Nodes, u)
+	}
+	chains, err := localnet.GetLocalNetworkBlockchainsInfo(app)
+	if err != nil {
+		return rpcproxy.Target{}, err
+	}
+	for _, chain := range chains {
+		if chain.Name != "" {
+			target.Aliases[chain.Name] = chain.BlockchainID
+		}
+	}
+	return target, nil
+}
+
+func startProxy(_ *cobra.Command, _ []string) error {
+	if proxyHealthInterval <= 0 {
+		return errors.New("--health-interval must be positive")
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	proxy := rpcproxy.New(localProxyTarget)
+	if err := proxy.Refresh(ctx); err != nil {
+		return fmt.Errorf("%w, start one with 'lux network start'", err)
+	}
+	proxy.OnHealthChange = func(node string, healthy bool) {
+		if healthy {
+			ux.Logger.PrintToUser("Node %s is back up", node)
+		} else {
+			ux.Logger.PrintToUser("Node %s is down, skipping it", node)
+		}
+	}
+	go proxy.Run(ctx, proxyHealthInterval)
+
+	addr := net.JoinHostPort(proxyAddress, strconv.Itoa(proxyPort))
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           proxy,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+
+	ux.Logger.PrintToUser("Serving RPC proxy on http://%s", addr)
+	for _, node := range proxy.Status() {
+		health := "healthy"
+		if !node.Healthy {
+			health = "down"
+		}
+		ux.Logger.PrintToUser("  node %s (%s)", node.URL, health)
+	}
+	ux.Logger.PrintToUser("C-Chain RPC: http://%s/ext/bc/C/rpc", addr)
+	aliases := proxy.Aliases()
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ux.Logger.PrintToUser("%s RPC: http://%s/ext/bc/%s/rpc", name, addr, name)
+	}
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("proxy failed: %w", err)
+	case <-ctx.Done():
+		ux.Logger.PrintToUser("Shutting down proxy...")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
@@ -54,7 +54,7 @@ Notes:
 	}
 
 	cmd.Flags().Float64Var(&sendAmount, "amount", 0, "Amount to send in LUX (required)")
-	cmd.Flags().StringVar(&sendTo, "to", "", "Destination address (C-Chain hex address)")
+	cmd.Flags().StringVar(&sendTo, "to", "", "Destination address (C-Chain hex address or @label of the address book)")
 	cmd.Flags().StringVar(&sendFromKey, "from", "", "Key name to use for signing (default: MNEMONIC account 0)")
 	cmd.Flags().StringVar(&sendSourceChain, "source", "C", "Source chain (only C supported)")
 	cmd.Flags().StringVar(&sendDestChain, "dest", "C", "Destination chain (only C supported)")
@@ -72,8 +72,12 @@ func runSend(_ *cobra.Command, _ []string) error {
 	if sendTo == "" {
 		return fmt.Errorf("destination address required (--to)")
 	}
-	if !ethcommon.IsHexAddress(sendTo) {
-		return fmt.Errorf("invalid C-Chain address: %s", sendTo)
+	to, err := app.ResolveAddress(sendTo)
+	if err != nil {
+		return err
+	}
+	if !ethcommon.IsHexAddress(to) {
+		return fmt.Errorf("invalid C-Chain address: %s", to)
 	}
 	if strings.ToUpper(sendSourceChain) != "C" || strings.ToUpper(sendDestChain) != "C" {
 		return fmt.Errorf("only C->C transfers are supported right now")
@@ -109,7 +113,7 @@ func runSend(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
-	toAddr := ethcommon.HexToAddress(sendTo)
+	toAddr := ethcommon.HexToAddress(to)
 	valueWei, err := luxToWei(sendAmount)
 	if err != nil {
 		return err
@@ -151,8 +155,8 @@ func runSend(_ *cobra.Command, _ []string) error {
 
 	ux.Logger.PrintToUser("")
 	ux.Logger.PrintToUser("C-Chain transfer submitted")
-	ux.Logger.PrintToUser("  From:   %s", fromAddr.Hex())
-	ux.Logger.PrintToUser("  To:     %s", toAddr.Hex())
+	ux.Logger.PrintToUser("  From:   %s", app.AnnotateAddress(fromAddr.Hex()))
+	ux.Logger.PrintToUser("  To:     %s", app.AnnotateAddress(toAddr.Hex()))
 	ux.Logger.PrintToUser("  Amount: %.6f LUX", sendAmount)
 	ux.Logger.PrintToUser("  TxID:   %s", tx.Hash().Hex())
 	ux.Logger.PrintToUser("")
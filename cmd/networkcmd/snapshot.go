@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/luxfi/cli/pkg/binutils"
+	"github.com/luxfi/cli/pkg/history"
 	"github.com/luxfi/cli/pkg/snapshot"
 	"github.com/luxfi/cli/pkg/ux"
 	"github.com/spf13/cobra"
@@ -452,7 +453,14 @@ func createAdvancedSnapshot(cmd *cobra.Command, args []string, incremental bool)
 func restoreAdvancedSnapshot(cmd *cobra.Command, args []string) error {
 	snapshotName := args[0]
 	manager := snapshot.NewSnapshotManager(app.GetBaseDir())
-	return manager.RestoreSnapshot(snapshotName)
+	if err := manager.RestoreSnapshot(snapshotName); err != nil {
+		return err
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "snapshot restore",
+		Params:    map[string]string{"snapshot": snapshotName},
+	})
+	return nil
 }
 
 func squashAdvancedSnapshot(cmd *cobra.Command, args []string) error {
@@ -16,7 +16,9 @@ import (
 	"github.com/luxfi/cli/pkg/application"
 	"github.com/luxfi/cli/pkg/binutils"
 	"github.com/luxfi/cli/pkg/chain"
+	"github.com/luxfi/cli/pkg/hooks"
 	"github.com/luxfi/cli/pkg/key"
+	"github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/cli/pkg/ux"
 	"github.com/luxfi/constants"
 	"github.com/luxfi/netrunner/client"
@@ -45,6 +47,9 @@ var (
 	// K8s deployment flags
 	k8sCluster string // K8s cluster context name (enables K8s deployment)
 	k8sImage   string // Docker image for K8s deployment
+
+	// devNode is the node started by --dev, which runs in the foreground
+	devNode *exec.Cmd
 )
 
 // StartFlags contains configuration for starting a network
@@ -240,6 +245,7 @@ OPTIONS:
   --snapshot-name     Resume from named snapshot
   --port              Base port for APIs (overrides defaults)
   --profile           Consensus profile: standard, fast, turbo (default: auto)
+  --upgrade-bundle    Upgrade bundle (JSON) applied to every node (devnet only)
 
 EXAMPLES:
 
@@ -263,6 +269,11 @@ EXAMPLES:
   export LIGHT_MNEMONIC="light light light light light light light light light light light energy"
   lux network start --dev --num-validators=3
 
+  # Test an upgrade: activate etna 10 minutes after start on every node
+  echo '{"network": {"etna": "+10m"}}' > acp99.json
+  lux network start --devnet --upgrade-bundle acp99.json
+  lux chain upgrade-status --devnet
+
   # Use custom luxd binary
   lux network start --devnet --node-path ~/work/lux/node/build/luxd
 
@@ -298,6 +309,7 @@ TYPICAL WORKFLOW:
 	cmd.Flags().IntVar(&numValidators, "num-validators", constants.LocalNetworkNumNodes, "number of validators to start")
 	cmd.Flags().IntVar(&portBase, "port", 9630, "base port for node APIs (each node uses 2 ports: HTTP and staking)")
 	cmd.Flags().StringVar(&profile, "profile", "", "performance profile: standard, fast, turbo (default: per-network)")
+	cmd.Flags().StringVar(&upgradeBundle, "upgrade-bundle", "", "upgrade bundle with network and chain upgrade activations applied to every node (devnet only)")
 	// BadgerDB flags
 	cmd.Flags().StringVar(&dbEngine, "db-backend", "", "database backend to use (pebble, leveldb, or badgerdb)")
 	cmd.Flags().StringVar(&archiveDir, "archive-path", "", "path to BadgerDB archive database (enables dual-database mode)")
@@ -336,6 +348,47 @@ func StartNetwork(*cobra.Command, []string) error {
 		return fmt.Errorf("cannot use multiple network flags together (--mainnet, --testnet, --devnet, --local, --dev)")
 	}
 
+	lock, err := app.AcquireLock("network start")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	if err := app.RunWithHooks(hooks.NetworkStart, hooks.Context{Network: startNetworkName()}, startNetwork); err != nil {
+		return err
+	}
+	if devNode != nil {
+		// The dev node runs in the foreground, let other commands run meanwhile
+		_ = lock.Release()
+		return devNode.Wait()
+	}
+	return nil
+}
+
+// startNetworkName names the network the flags select for hooks
+func startNetworkName() string {
+	switch {
+	case mainnet:
+		return "mainnet"
+	case testnet:
+		return "testnet"
+	case devnet:
+		return "devnet"
+	case localMode:
+		return "local"
+	case devMode:
+		return "dev"
+	default:
+		return ""
+	}
+}
+
+func startNetwork() error {
+	// Upgrade bundles are passed to the nodes started by netrunner
+	if upgradeBundle != "" && (localMode || devMode || k8sCluster != "") {
+		return fmt.Errorf("--upgrade-bundle is only supported with --devnet")
+	}
+
 	// --local: K8s operator-native localnet (no netrunner)
 	if localMode {
 		return StartLocal()
@@ -365,7 +418,12 @@ func StartNetwork(*cobra.Command, []string) error {
 			return StartDevNetwork()
 		}
 		// Single node dev mode with K=1 consensus
-		return StartDevMode()
+		node, err := startDevNode()
+		if err != nil {
+			return err
+		}
+		devNode = node
+		return nil
 	}
 
 	// If mainnet, testnet, or devnet flag is set, delegate to the appropriate function
@@ -397,6 +455,11 @@ func startPublicNetwork(cfg networkConfig) error {
 	ux.Logger.PrintToUser("Starting Lux %s with %d validator nodes...", cfg.networkName, numValidators)
 	ux.Logger.PrintToUser("Network ID: %d", cfg.networkID)
 
+	upgradeNodeConfig, upgradeOpts, err := upgradeBundleConfig(cfg.networkID)
+	if err != nil {
+		return err
+	}
+
 	localNodePath, err := findNodeBinary()
 	if err != nil {
 		return err
@@ -451,6 +514,7 @@ func startPublicNetwork(cfg networkConfig) error {
 	globalNodeConfig := fmt.Sprintf(`{
 		"network-id": %d,
 		%s
+		%s
 		"db-type": "badgerdb",
 		"sybil-protection-enabled": true,
 		"network-allow-private-ips": true,
@@ -486,7 +550,7 @@ func startPublicNetwork(cfg networkConfig) error {
 		"network-health-max-time-since-msg-sent": "5s",
 		"network-health-max-time-since-msg-received": "5s",
 		"network-outbound-connection-timeout": "500ms"
-	}`, cfg.networkID, importChainDataConfig, trackChainsValue,
+	}`, cfg.networkID, importChainDataConfig, upgradeNodeConfig, trackChainsValue,
 		prof.ConsensusFrontierPollFreq,
 		prof.HealthCheckFrequency,
 		prof.HealthCheckAveragerHalflife,
@@ -535,6 +599,7 @@ func startPublicNetwork(cfg networkConfig) error {
 		client.WithDynamicPorts(false),
 		client.WithCustomNodeConfigs(customNodeConfigs),
 	}
+	opts = append(opts, upgradeOpts...)
 
 	// Build chain configs (mainnet-specific feature, but harmless for testnet)
 	cfgMgr := chain.NewManager(app)
@@ -628,6 +693,7 @@ func startPublicNetwork(cfg networkConfig) error {
 	if err := app.SaveNetworkState(networkState); err != nil {
 		ux.Logger.PrintToUser("Warning: failed to save network state: %v", err)
 	}
+	recordUpgradeBundle(cfg.networkName)
 	ux.Logger.PrintToUser("gRPC server: localhost:%d", grpcPorts.Server)
 
 	return nil
@@ -729,12 +795,22 @@ func StartDevNetwork() error {
 // This runs luxd directly (not through netrunner) for maximum simplicity
 // luxd's built-in --dev flag enables: single-node consensus, no sybil protection, instant blocks
 func StartDevMode() error {
+	node, err := startDevNode()
+	if err != nil {
+		return err
+	}
+	// Wait for the node process to keep running
+	return node.Wait()
+}
+
+// startDevNode starts the dev mode node and returns it once healthy
+func startDevNode() (*exec.Cmd, error) {
 	ux.Logger.PrintToUser("Starting Lux dev mode (single node, K=1 consensus)...")
 	ux.Logger.PrintToUser("All chains enabled: C-Chain, P-Chain, X-Chain")
 
 	localNodePath, err := findNodeBinary()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Dev mode uses port 8545 by default (anvil/hardhat compatible)
@@ -744,7 +820,7 @@ func StartDevMode() error {
 	}
 
 	// Set up data directory
-	dataDir := filepath.Join(os.Getenv("HOME"), ".lux", "devnet")
+	dataDir := filepath.Join(app.GetBaseDir(), "devnet")
 	dbDir := filepath.Join(dataDir, "db")
 	logDir := filepath.Join(dataDir, "logs")
 
@@ -758,7 +834,7 @@ func StartDevMode() error {
 
 	// Ensure directories exist
 	if err := os.MkdirAll(logDir, 0o750); err != nil {
-		return fmt.Errorf("failed to create log directory: %w", err)
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
 	}
 
 	ux.Logger.PrintToUser("Using luxd binary: %s", localNodePath)
@@ -793,7 +869,7 @@ func StartDevMode() error {
 
 	// Start the node in the background
 	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to start luxd: %w", err)
+		return nil, fmt.Errorf("failed to start luxd: %w", err)
 	}
 
 	ux.Logger.PrintToUser("luxd started (PID: %d)", cmd.Process.Pid)
@@ -811,7 +887,7 @@ func StartDevMode() error {
 	for {
 		select {
 		case <-healthCtx.Done():
-			return fmt.Errorf("timeout waiting for node to become healthy after %s: %w", healthTimeout, healthCtx.Err())
+			return nil, fmt.Errorf("timeout waiting for node to become healthy after %s: %w", healthTimeout, healthCtx.Err())
 		case <-ticker.C:
 			resp, err := http.Get(healthURL)
 			if err != nil {
@@ -839,9 +915,7 @@ healthy:
 	ux.Logger.PrintToUser("Logs: %s", logDir)
 	ux.Logger.PrintToUser("\nDev network is ready for use!")
 	ux.Logger.PrintToUser("To stop: pkill luxd")
-
-	// Wait for the node process to keep running
-	return cmd.Wait()
+	return cmd, nil
 }
 
 // isPortBaseFlagSet checks if --port-base was explicitly set by user
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/luxfi/cli/pkg/binutils"
+	"github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/cli/pkg/ux"
 	"github.com/luxfi/netrunner/server"
 	"github.com/spf13/cobra"
@@ -285,7 +286,7 @@ func getNodeVersion(uri string) (string, map[string]string, error) {
 	req.Header.Set("Content-Type", "application/json")
 
 	// Short timeout for local info check
-	client := &http.Client{Timeout: 2 * time.Second}
+	client := utils.DefaultRPCClient().WithTimeout(2 * time.Second).WithPolicy(utils.ProbeRetryPolicy())
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", nil, err
@@ -330,7 +331,7 @@ func getCChainHeight(uri string) (string, error) {
 	req.Header.Set("Content-Type", "application/json")
 
 	// Short timeout for local check
-	client := &http.Client{Timeout: 2 * time.Second}
+	client := utils.DefaultRPCClient().WithTimeout(2 * time.Second).WithPolicy(utils.ProbeRetryPolicy())
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", err
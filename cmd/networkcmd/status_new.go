@@ -8,8 +8,10 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/luxfi/cli/pkg/status"
+	"github.com/luxfi/cli/pkg/ux"
 	"github.com/spf13/cobra"
 )
 
@@ -18,6 +20,10 @@ var (
 	statusCompact bool
 	statusOutput  string
 	statusVerbose bool
+
+	statusWatch          bool
+	statusInterval       time.Duration
+	statusStallIntervals int
 )
 
 // NewStatusCmd returns the improved status command.
@@ -33,14 +39,30 @@ OVERVIEW:
   Displays network health, validator nodes, endpoints, and custom chains.
   Uses clean, structured output suitable for scripting and human reading.
 
+WATCH MODE:
+
+  --watch              Re-probe at --interval and redraw a live dashboard of
+                       chain heights, blocks produced, peers and latencies
+  --interval           Time between probes in watch mode (default: 5s)
+  --stall-intervals    Exit non-zero once a chain has not advanced for this
+                       many consecutive probes (default: 0, disabled). Idle
+                       P, X and C chains produce no blocks, so only set it
+                       for networks with steady traffic
+
 FORMAT OPTIONS:
 
   --format full     Show full detailed status (default)
   --format summary  Show only network summary
   --format chains   Show only chain status
   --format nodes    Show only node status
+  --format gpu      Show only GPU devices, utilization and accelerated chains
   --compact         Use compact output format
 
+VALIDATOR WATCHDOGS:
+
+  Nodes supervised by 'lux node watchdog' are listed after the networks,
+  with their restarts, sync status, validator fee balance and open alerts.
+
 EXAMPLES:
 
   # Show full status
@@ -52,6 +74,13 @@ EXAMPLES:
   # Show compact summary
   lux network status-new --compact
 
+  # Refresh every 10s, fail if a chain makes no progress for 6 refreshes
+  lux network status --watch --interval 10s --stall-intervals 6
+
+The JSON output (--output json) sums up the GPUs of each network under
+GPU, with predicates for schedulers: Available, AllNodes, Healthy, the
+MaxUtilization of the busiest GPU and the accelerated Chains.
+
 OUTPUT FORMAT:
 
   status  mainnet  up   grpc=8369  nodes=5  vms=1  controller=on
@@ -71,15 +100,22 @@ OUTPUT FORMAT:
 		SilenceUsage: true,
 	}
 
-	cmd.Flags().StringVar(&statusFormat, "format", "full", "output format (full, summary, chains, nodes)")
+	cmd.Flags().StringVar(&statusFormat, "format", "full", "output format (full, summary, chains, nodes, gpu)")
 	cmd.Flags().BoolVar(&statusCompact, "compact", false, "use compact output format")
 	cmd.Flags().StringVarP(&statusOutput, "output", "o", "text", "output format (text, json, yaml, wide)")
 	cmd.Flags().BoolVar(&statusVerbose, "verbose", false, "show verbose progress information")
+	cmd.Flags().BoolVar(&statusWatch, "watch", false, "re-probe periodically and show a live dashboard")
+	cmd.Flags().DurationVar(&statusInterval, "interval", 5*time.Second, "time between probes in watch mode")
+	cmd.Flags().IntVar(&statusStallIntervals, "stall-intervals", 0, "in watch mode, exit non-zero when a chain has not advanced for this many probes (0 disables)")
 
 	return cmd
 }
 
 func runStatusNew(cmd *cobra.Command, args []string) error {
+	if statusWatch {
+		return watchStatus()
+	}
+
 	// Create progress tracker
 	progress := status.NewProgressTracker(os.Stderr)
 
@@ -105,15 +141,24 @@ func runStatusNew(cmd *cobra.Command, args []string) error {
 	// Get status
 	ctx := context.Background()
 	result, err := service.GetStatus(ctx)
+	watchdogs, wdErr := app.LoadWatchdogStates()
+	if wdErr != nil {
+		ux.Logger.PrintToUser("failed to read the validator watchdogs: %s", wdErr)
+	}
 	if err != nil {
 		if statusVerbose {
 			progress.FailStep("Network status check", err)
 		}
-		if errors.Is(err, status.ErrNoNetwork) {
+		if !errors.Is(err, status.ErrNoNetwork) {
+			return fmt.Errorf("failed to get status: %w", err)
+		}
+		// a local bootstrap validator of a public L1 runs without a local network
+		if len(watchdogs) == 0 {
 			return fmt.Errorf("no network running")
 		}
-		return fmt.Errorf("failed to get status: %w", err)
+		result = &status.StatusResult{Timestamp: time.Now()}
 	}
+	result.Watchdogs = watchdogs
 
 	// Create formatter
 	formatter := status.NewStatusFormatter(os.Stdout)
@@ -127,6 +172,7 @@ func runStatusNew(cmd *cobra.Command, args []string) error {
 	case "wide":
 		// Wide format - currently maps to full network status
 		formatter.FormatNetworkStatus(result)
+		formatter.FormatWatchdogs(result)
 	case "text":
 		fallthrough
 	default:
@@ -138,6 +184,8 @@ func runStatusNew(cmd *cobra.Command, args []string) error {
 			formatter.FormatChainStatus(result)
 		case "nodes":
 			formatter.FormatNodeStatus(result)
+		case "gpu":
+			formatter.FormatGPU(result)
 		case "full":
 			fallthrough
 		default:
@@ -151,6 +199,7 @@ func runStatusNew(cmd *cobra.Command, args []string) error {
 				formatter.FormatNetworkStatus(result)
 			}
 		}
+		formatter.FormatWatchdogs(result)
 	}
 
 	return nil
@@ -0,0 +1,87 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networkcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/luxfi/cli/pkg/status"
+	"golang.org/x/term"
+)
+
+// watchStatus probes the networks every statusInterval and redraws the watch
+// dashboard until interrupted, or fails once a chain has stopped advancing
+// for statusStallIntervals probes.
+func watchStatus() error {
+	if statusInterval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	if statusStallIntervals < 0 {
+		return fmt.Errorf("--stall-intervals must not be negative")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	service := status.NewStatusService()
+	formatter := status.NewStatusFormatter(os.Stdout)
+	tracker := status.NewHeightTracker()
+	redraw := term.IsTerminal(int(os.Stdout.Fd())) && statusOutput != "json"
+
+	ticker := time.NewTicker(statusInterval)
+	defer ticker.Stop()
+
+	for {
+		probeCtx, cancel := context.WithTimeout(ctx, statusInterval+30*time.Second)
+		result, err := service.GetStatus(probeCtx)
+		cancel()
+		if ctx.Err() != nil {
+			fmt.Println("\nWatch stopped by user.")
+			return nil
+		}
+
+		if redraw {
+			fmt.Print("\033[2J\033[H") // ANSI escape codes to clear screen and move cursor to top-left
+		}
+
+		var stalled []status.ChainProgress
+		switch {
+		case errors.Is(err, status.ErrNoNetwork):
+			fmt.Printf("%s  no network running\n", time.Now().Format("2006-01-02 15:04:05"))
+		case err != nil:
+			fmt.Printf("%s  failed to get status: %v\n", time.Now().Format("2006-01-02 15:04:05"), err)
+		case statusOutput == "json":
+			progress := tracker.Observe(result)
+			stalled = status.Stalled(progress, statusStallIntervals)
+			if err := formatter.FormatJSON(result); err != nil {
+				return fmt.Errorf("failed to format JSON: %w", err)
+			}
+		default:
+			progress := tracker.Observe(result)
+			stalled = status.Stalled(progress, statusStallIntervals)
+			fmt.Printf("LUX Network Status (refresh: %s) - %s\n\n", statusInterval, time.Now().Format("2006-01-02 15:04:05"))
+			formatter.FormatWatch(result, progress)
+		}
+
+		if len(stalled) > 0 {
+			for _, p := range stalled {
+				fmt.Fprintf(os.Stderr, "%s %s-chain stalled at height %d for %d intervals\n", p.Network, p.Chain, p.Height, p.Stalled)
+			}
+			return fmt.Errorf("%d chain(s) stopped advancing", len(stalled))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			fmt.Println("\nWatch stopped by user.")
+			return nil
+		}
+	}
+}
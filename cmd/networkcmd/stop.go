@@ -11,7 +11,9 @@ import (
 	"strings"
 
 	"github.com/luxfi/cli/pkg/binutils"
+	"github.com/luxfi/cli/pkg/hooks"
 	"github.com/luxfi/cli/pkg/snapshot"
+	"github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/cli/pkg/ux"
 	"github.com/luxfi/constants"
 	"github.com/luxfi/netrunner/local"
@@ -132,6 +134,12 @@ func StopNetwork(*cobra.Command, []string) error {
 		}
 	}
 
+	lock, err := app.AcquireLock("network stop")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
 	// Get all running networks
 	runningNetworks := app.GetAllRunningNetworks()
 	devRunning := isDevModeRunning()
@@ -203,23 +211,31 @@ func StopNetwork(*cobra.Command, []string) error {
 		return nil
 	}
 
-	ux.Logger.PrintToUser("Stopping network: %s", stopNetworkType)
+	return app.RunWithHooks(hooks.NetworkStop, hooks.Context{Network: stopNetworkType}, func() error {
+		return stopRunningNetwork(stopNetworkType)
+	})
+}
+
+// stopRunningNetwork saves a snapshot of the running network networkType,
+// then stops it
+func stopRunningNetwork(networkType string) error {
+	ux.Logger.PrintToUser("Stopping network: %s", networkType)
 
 	// Create hot snapshot via gRPC → admin.snapshot API while network is still running
 	// This is the primary method - uses native BadgerDB incremental backup
-	if err := saveNetworkForType(stopNetworkType); err != nil {
+	if err := saveNetworkForType(networkType); err != nil {
 		ux.Logger.PrintToUser("Warning: failed to save snapshot: %v", err)
 	}
 
-	if killErr := binutils.KillgRPCServerProcessForNetwork(app, stopNetworkType); killErr != nil {
+	if killErr := binutils.KillgRPCServerProcessForNetwork(app, networkType); killErr != nil {
 		app.Log.Warn("failed killing server process", "error", killErr)
 		ux.Logger.PrintToUser("Warning: failed to shutdown server gracefully: %v", killErr)
 	} else {
-		ux.Logger.PrintToUser("Server (%s) shutdown gracefully", stopNetworkType)
+		ux.Logger.PrintToUser("Server (%s) shutdown gracefully", networkType)
 	}
 
 	// Clear network-specific state when stopping
-	if clearErr := app.ClearNetworkStateForType(stopNetworkType); clearErr != nil {
+	if clearErr := app.ClearNetworkStateForType(networkType); clearErr != nil {
 		app.Log.Warn("failed to clear network state", "error", clearErr)
 	}
 
@@ -245,7 +261,7 @@ func StopNetwork(*cobra.Command, []string) error {
 
 // isDevModeRunning checks if a dev mode node is currently running
 func isDevModeRunning() bool {
-	pidFile := filepath.Join(os.Getenv("HOME"), constants.BaseDirName, constants.DevDir, "luxd.pid")
+	pidFile := filepath.Join(app.GetBaseDir(), constants.DevDir, "luxd.pid")
 	pidData, err := os.ReadFile(pidFile) //nolint:gosec // G304: Reading PID file from app's directory
 	if err != nil {
 		return false
@@ -256,10 +272,7 @@ func isDevModeRunning() bool {
 		return false
 	}
 
-	// Check if process exists using os.FindProcess
-	// Note: On Unix, FindProcess always succeeds, but we verify by checking /proc
-	// On Windows, we check if we can open the process
-	return isProcessRunning(pid)
+	return utils.IsProcessRunning(pid)
 }
 
 func saveNetworkForType(networkType string) error {
@@ -0,0 +1,112 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networkcmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/upgradebundle"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/netrunner/client"
+	"github.com/luxfi/sdk/models"
+	"github.com/luxfi/upgrade"
+)
+
+// upgradeBundle is the --upgrade-bundle file applied on network start
+var upgradeBundle string
+
+// upgradeBundleConfig loads the --upgrade-bundle file and returns the global
+// node config entry and the netrunner options that apply it. Every node gets
+// the same network upgrade schedule and the same chain upgrade files.
+func upgradeBundleConfig(networkID uint32) (string, []client.OpOption, error) {
+	if upgradeBundle == "" {
+		return "", nil, nil
+	}
+	// Nodes refuse upgrade overrides on networks with a fixed schedule
+	switch networkID {
+	case constants.MainnetID, constants.TestnetID, constants.LocalID:
+		return "", nil, fmt.Errorf("--upgrade-bundle can't be used with %s, its upgrade schedule is fixed: use --devnet", constants.NetworkName(networkID))
+	}
+
+	bundle, err := upgradebundle.Load(upgradeBundle)
+	if err != nil {
+		return "", nil, err
+	}
+	nodeConfig := ""
+	if len(bundle.Network) > 0 {
+		upgrades, err := bundle.NetworkUpgrades(time.Now())
+		if err != nil {
+			return "", nil, err
+		}
+		content, err := upgradebundle.EncodeNetworkUpgrades(upgrades)
+		if err != nil {
+			return "", nil, err
+		}
+		nodeConfig = fmt.Sprintf(`"upgrade-file-content": %q,`, content)
+
+		ux.Logger.PrintToUser("Network upgrades from %s:", upgradeBundle)
+		for _, a := range upgradebundle.Activations(upgrades) {
+			if a.Scheduled() && a.Time.After(upgrade.InitiallyActiveTime) {
+				ux.Logger.PrintToUser("  %-18s %s", a.Name, a.Time.Local().Format(constants.TimeParseLayout))
+			}
+		}
+	}
+
+	networkKey := models.NetworkFromNetworkID(networkID).String()
+	chainNames := make([]string, 0, len(bundle.Chains))
+	for name := range bundle.Chains {
+		chainNames = append(chainNames, name)
+	}
+	sort.Strings(chainNames)
+	upgradeConfigs := map[string]string{}
+	for _, name := range chainNames {
+		if !app.ChainConfigExists(name) {
+			return "", nil, fmt.Errorf("upgrade bundle: chain %s does not exist", name)
+		}
+		// The bundle becomes the chain upgrade file, as with 'lux chain upgrade import'
+		if err := app.WriteUpgradeFile(name, bundle.Chains[name]); err != nil {
+			return "", nil, err
+		}
+		sc, err := app.LoadSidecar(name)
+		if err != nil {
+			return "", nil, err
+		}
+		blockchainID := sc.Networks[networkKey].BlockchainID
+		if blockchainID == ids.Empty {
+			ux.Logger.PrintToUser("Chain %s is not deployed to %s yet, restart the network with the bundle after deploying it", name, networkKey)
+			continue
+		}
+		upgradeConfigs[blockchainID.String()] = string(bundle.Chains[name])
+		ux.Logger.PrintToUser("Applying upgrade file of chain %s (%s)", name, blockchainID)
+	}
+
+	var opts []client.OpOption
+	if len(upgradeConfigs) > 0 {
+		opts = append(opts, client.WithUpgradeConfigs(upgradeConfigs))
+	}
+	return nodeConfig, opts, nil
+}
+
+// recordUpgradeBundle adds the --upgrade-bundle a network started with to
+// the history
+func recordUpgradeBundle(networkName string) {
+	if upgradeBundle == "" {
+		return
+	}
+	params := map[string]string{"bundle": upgradeBundle}
+	if data, err := os.ReadFile(upgradeBundle); err == nil { //nolint:gosec // G304: User-specified bundle file
+		params["bundleSHA256"] = history.Digest(data)
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "network upgrade-bundle",
+		Network:   networkName,
+		Params:    params,
+	})
+}
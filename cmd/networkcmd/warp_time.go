@@ -0,0 +1,197 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networkcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/localnet"
+	"github.com/luxfi/cli/pkg/timewarp"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+const warpTimeTimeout = 60 * time.Second
+
+var (
+	warpTimeAdvance time.Duration
+	warpTimeChains  []string
+)
+
+func newWarpTimeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "warp-time",
+		Short: "Advance the block time of the running local network",
+		Long: `The warp-time command moves the block time of the C-Chain and the EVM L1s
+deployed to the running local network forward, so that vesting schedules,
+staking epochs and auction ends of contracts can be tested without waiting
+in real time.
+
+Each chain is asked for the offset through the dev namespace of its node
+(evm_increaseTime, as in Anvil and Hardhat), then mines a block at the new
+time. The block time of each chain before and after is shown; a chain whose
+VM ignores the offset is reported as failed. Time only moves forward, and
+the P-Chain and X-Chain keep following the clock of the nodes.
+
+EXAMPLES:
+
+  lux network warp-time --advance 72h
+  lux network warp-time --advance 30m --chain mychain`,
+		Args:         cobra.NoArgs,
+		RunE:         warpTime,
+		SilenceUsage: true,
+	}
+	cmd.Flags().DurationVar(&warpTimeAdvance, "advance", 0, "how far to move the block time forward, e.g. 72h")
+	cmd.Flags().StringSliceVar(&warpTimeChains, "chain", nil, "chains to advance: c or a deployed EVM L1 (default all)")
+	_ = cmd.MarkFlagRequired("advance")
+	return cmd
+}
+
+func warpTime(_ *cobra.Command, _ []string) error {
+	if warpTimeAdvance < time.Second {
+		return errors.New("--advance must be at least 1s")
+	}
+	state, endpoint, err := runningLocalNetwork()
+	if err != nil {
+		return err
+	}
+	chains, err := warpTimeTargets(endpoint)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), warpTimeTimeout)
+	defer cancel()
+	results := timewarp.Advance(ctx, &http.Client{Timeout: warpTimeTimeout}, chains, warpTimeAdvance)
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Chain", "Block time before", "Block time after", "Status")
+	advanced := map[string]string{}
+	var failed []string
+	for _, r := range results {
+		before, after, status := "-", "-", "advanced"
+		if !r.Before.IsZero() {
+			before = r.Before.UTC().Format(time.RFC3339)
+		}
+		if !r.After.IsZero() {
+			after = r.After.UTC().Format(time.RFC3339)
+		}
+		if r.Err != nil {
+			status = r.Err.Error()
+			failed = append(failed, r.Chain.Name)
+		} else {
+			advanced[r.Chain.Name] = after
+		}
+		_ = table.Append([]string{r.Chain.Name, before, after, status})
+	}
+	if err := table.Render(); err != nil {
+		return err
+	}
+
+	if len(advanced) > 0 {
+		app.RecordHistory(history.Entry{
+			Operation: "network warp-time",
+			Network:   state.NetworkType,
+			Params:    map[string]string{"advance": warpTimeAdvance.String()},
+			Result:    advanced,
+		})
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to advance %s", strings.Join(failed, ", "))
+	}
+	ux.Logger.GreenCheckmarkToUser("Advanced the block time of %d chains by %s", len(results), warpTimeAdvance)
+	return nil
+}
+
+// warpTimeTargets returns the chains of --chain, by default the C-Chain and
+// every EVM L1 deployed to the local network of endpoint
+func warpTimeTargets(endpoint string) ([]timewarp.Chain, error) {
+	l1s, err := deployedEVMChains(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	all := map[string]string{"c": endpoint + "/ext/bc/C/rpc"}
+	for name, rpcURL := range l1s {
+		if _, ok := all[strings.ToLower(name)]; !ok {
+			all[name] = rpcURL
+		}
+	}
+	names := warpTimeChains
+	if len(names) == 0 {
+		for name := range all {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	chains := make([]timewarp.Chain, 0, len(names))
+	for _, name := range names {
+		rpcURL, ok := all[name]
+		if !ok {
+			rpcURL, ok = all[strings.ToLower(name)]
+		}
+		if !ok {
+			return nil, fmt.Errorf("%q is not the C-Chain or an EVM L1 deployed to the local network", name)
+		}
+		chains = append(chains, timewarp.Chain{Name: name, URL: rpcURL})
+	}
+	return chains, nil
+}
+
+// runningLocalNetwork returns the state and the API endpoint of the running
+// local network
+func runningLocalNetwork() (*application.NetworkState, string, error) {
+	running, err := localnet.LocalNetworkIsRunning(app)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to check network status: %w", err)
+	}
+	if !running {
+		return nil, "", errors.New("no local network running, start one with 'lux network start'")
+	}
+	state, err := findRunningNetworkState(app)
+	if err != nil {
+		return nil, "", err
+	}
+	endpoint := state.APIEndpoint
+	if endpoint == "" {
+		endpoint = app.GetRunningNetworkEndpoint()
+	}
+	if endpoint == "" {
+		return nil, "", errors.New("could not determine network endpoint")
+	}
+	return state, strings.TrimSuffix(endpoint, "/"), nil
+}
+
+// deployedEVMChains returns the RPC endpoints of the EVM L1s deployed to the
+// local network of endpoint, by chain name
+func deployedEVMChains(endpoint string) (map[string]string, error) {
+	names, err := app.GetSidecarNames()
+	if err != nil {
+		return nil, err
+	}
+	chains := map[string]string{}
+	for _, name := range names {
+		sc, err := app.LoadSidecar(name)
+		if err != nil || sc.VM != models.EVM {
+			continue
+		}
+		deployment, ok := sc.Networks[models.Local.String()]
+		if !ok || deployment.BlockchainID == ids.Empty {
+			continue
+		}
+		chains[name] = models.GetRPCEndpoint(endpoint, deployment.BlockchainID.String())
+	}
+	return chains, nil
+}
@@ -0,0 +1,359 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nodecmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/accesslist"
+	"github.com/luxfi/cli/pkg/cloud/aws"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/node"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	accessCIDR        string
+	accessPort        string
+	accessDescription string
+	accessAWSProfile  string
+	accessNoSync      bool
+	accessDryRun      bool
+	accessTakeOver    bool
+)
+
+func newAccessCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "access",
+		Short: "Manage which networks may reach the nodes of a cluster",
+		Long: `The access command manages the IP restrictions of a cloud cluster: the CIDRs
+allowed to reach the ssh, api and p2p ports, or any other port, of its nodes.
+
+The list of a cluster is kept in ~/.lux/clusters/<cluster>/access.json and the
+security groups of the nodes are synced to it. Only the ports the list has
+been given rules for are managed: once a port has a rule, every other ingress
+rule of that port is revoked, e.g. 0.0.0.0/0 on the api port, while the rules
+of the other ports are left alone. A port stays managed when its last rule is
+removed, so the nodes are then closed on it.
+
+Adding the first rule of a port refuses to revoke the ingress rules the
+security groups already have on it, e.g. your own ssh access, unless
+--take-over is given. Add those CIDRs to the list first to keep them.
+
+EXAMPLES:
+
+  lux node access add my-cluster --cidr 203.0.113.0/24 --port api --description partner
+  lux node access add my-cluster --cidr 198.51.100.7 --port ssh
+  lux node access remove my-cluster --cidr 203.0.113.0/24 --port api
+  lux node access list my-cluster
+  lux node access sync my-cluster --dry-run`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.PersistentFlags().StringVar(&accessAWSProfile, "aws-profile", "default", "AWS profile to use for the security groups")
+
+	addCmd := &cobra.Command{
+		Use:          "add [clusterName]",
+		Short:        "Allow a CIDR to reach a port of the nodes",
+		Args:         cobra.ExactArgs(1),
+		RunE:         addAccessRule,
+		SilenceUsage: true,
+	}
+	addCmd.Flags().StringVar(&accessDescription, "description", "", "what the rule is for")
+	addCmd.Flags().BoolVar(&accessTakeOver, "take-over", false, "revoke the existing ingress rules of a port not managed yet")
+	removeCmd := &cobra.Command{
+		Use:          "remove [clusterName]",
+		Short:        "Stop allowing a CIDR to reach a port of the nodes",
+		Args:         cobra.ExactArgs(1),
+		RunE:         removeAccessRule,
+		SilenceUsage: true,
+	}
+	for _, c := range []*cobra.Command{addCmd, removeCmd} {
+		c.Flags().StringVar(&accessCIDR, "cidr", "", "IPv4 address or CIDR")
+		c.Flags().StringVar(&accessPort, "port", "api", "port number, or one of ssh, api, p2p")
+		c.Flags().BoolVar(&accessNoSync, "no-sync", false, "only update the list, sync the security groups later")
+		_ = c.MarkFlagRequired("cidr")
+	}
+	syncCmd := &cobra.Command{
+		Use:          "sync [clusterName]",
+		Short:        "Sync the security groups of the nodes to the list",
+		Args:         cobra.ExactArgs(1),
+		RunE:         syncAccessRules,
+		SilenceUsage: true,
+	}
+	syncCmd.Flags().BoolVar(&accessDryRun, "dry-run", false, "show the changes without applying them")
+
+	cmd.AddCommand(addCmd)
+	cmd.AddCommand(removeCmd)
+	cmd.AddCommand(&cobra.Command{
+		Use:          "list [clusterName]",
+		Short:        "List the access rules of a cluster",
+		Args:         cobra.ExactArgs(1),
+		RunE:         listAccessRules,
+		SilenceUsage: true,
+	})
+	cmd.AddCommand(syncCmd)
+	return cmd
+}
+
+func addAccessRule(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	return updateAccessList(clusterName, func(l *accesslist.List, port int32) (string, error) {
+		firstRule := !l.Manages(port)
+		r, err := l.Add(accessCIDR, port, accessDescription)
+		if err != nil {
+			return "", err
+		}
+		if firstRule && !accessTakeOver {
+			revoked, err := takenOverRules(clusterName, l, port)
+			if err != nil {
+				return "", err
+			}
+			if len(revoked) > 0 {
+				return "", fmt.Errorf("port %s isn't managed yet and allowing %s would revoke %s: add those CIDRs first, or use --take-over to revoke them",
+					accesslist.PortName(port), r.CIDR, strings.Join(revoked, ", "))
+			}
+		}
+		return "Allowed " + r.String(), nil
+	})
+}
+
+// takenOverRules returns the ingress rules of port the security groups of
+// clusterName have but l doesn't, which managing the port revokes
+func takenOverRules(clusterName string, l *accesslist.List, port int32) ([]string, error) {
+	firewalls, err := clusterFirewalls(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	var revoked []string
+	for _, fw := range firewalls {
+		rules, err := fw.Rules(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to get the rules: %w", fw.Name(), err)
+		}
+		for _, r := range rules {
+			known := slices.ContainsFunc(l.Rules, func(o accesslist.Rule) bool { return o.CIDR == r.CIDR && o.Port == r.Port })
+			if r.Port == port && !known {
+				revoked = append(revoked, fmt.Sprintf("%s on %s", r.CIDR, fw.Name()))
+			}
+		}
+	}
+	return revoked, nil
+}
+
+func removeAccessRule(_ *cobra.Command, args []string) error {
+	return updateAccessList(args[0], func(l *accesslist.List, port int32) (string, error) {
+		r, err := l.Remove(accessCIDR, port)
+		if err != nil {
+			return "", err
+		}
+		return "Removed " + r.String(), nil
+	})
+}
+
+// updateAccessList applies update to the list of clusterName, then syncs the
+// security groups unless --no-sync
+func updateAccessList(clusterName string, update func(*accesslist.List, int32) (string, error)) error {
+	if err := node.CheckCluster(app, clusterName); err != nil {
+		return err
+	}
+	port, err := accesslist.ParsePort(accessPort)
+	if err != nil {
+		return err
+	}
+	path := app.GetClusterAccessListPath(clusterName)
+	l, err := accesslist.Load(path)
+	if err != nil {
+		return err
+	}
+	msg, err := update(l, port)
+	if err != nil {
+		return err
+	}
+	if err := l.Save(path); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("%s for cluster %s", msg, clusterName)
+	if accessNoSync {
+		ux.Logger.PrintToUser("Run 'lux node access sync %s' to apply it", clusterName)
+		return nil
+	}
+	return syncAccessList(clusterName, l, false)
+}
+
+func listAccessRules(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if err := node.CheckCluster(app, clusterName); err != nil {
+		return err
+	}
+	l, err := accesslist.Load(app.GetClusterAccessListPath(clusterName))
+	if err != nil {
+		return err
+	}
+	if len(l.Rules) == 0 {
+		if len(l.ManagedPorts()) == 0 {
+			ux.Logger.PrintToUser("Cluster %s has no access rules, its security groups are managed by hand", clusterName)
+			return nil
+		}
+		names := make([]string, 0, len(l.ManagedPorts()))
+		for _, port := range l.ManagedPorts() {
+			names = append(names, accesslist.PortName(port))
+		}
+		ux.Logger.PrintToUser("Cluster %s has no access rules, its %s ports are closed", clusterName, strings.Join(names, ", "))
+		return nil
+	}
+	rules := l.Rules
+	sort.SliceStable(rules, func(i, j int) bool { return rules[i].Port < rules[j].Port })
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Port", "CIDR", "Description")
+	for _, r := range rules {
+		_ = table.Append([]string{fmt.Sprintf("%s (%d)", accesslist.PortName(r.Port), r.Port), r.CIDR, r.Description})
+	}
+	return table.Render()
+}
+
+func syncAccessRules(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if err := node.CheckCluster(app, clusterName); err != nil {
+		return err
+	}
+	l, err := accesslist.Load(app.GetClusterAccessListPath(clusterName))
+	if err != nil {
+		return err
+	}
+	if len(l.ManagedPorts()) == 0 {
+		return fmt.Errorf("cluster %s has no access rules, add one with 'lux node access add'", clusterName)
+	}
+	return syncAccessList(clusterName, l, accessDryRun)
+}
+
+// syncAccessList syncs the security groups of the nodes of clusterName to l
+func syncAccessList(clusterName string, l *accesslist.List, dryRun bool) error {
+	firewalls, err := clusterFirewalls(clusterName)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	for _, fw := range firewalls {
+		changes, err := accesslist.Sync(ctx, l, fw, dryRun)
+		printAccessChanges(changes, dryRun)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printAccessChanges(changes accesslist.Changes, dryRun bool) {
+	prefix := ""
+	if dryRun {
+		prefix = "[dry-run] "
+	}
+	if len(changes.Added) == 0 && len(changes.Removed) == 0 {
+		ux.Logger.PrintToUser("%s%s is in sync", prefix, changes.Firewall)
+		return
+	}
+	for _, r := range changes.Added {
+		ux.Logger.PrintToUser("%s%s: allow  %s", prefix, changes.Firewall, r)
+	}
+	for _, r := range changes.Removed {
+		ux.Logger.PrintToUser("%s%s: revoke %s", prefix, changes.Firewall, r)
+	}
+}
+
+// nodeCloudConfig is the part of a node config locating its firewall
+type nodeCloudConfig struct {
+	Region        string `json:"region"`
+	SecurityGroup string `json:"securityGroup"`
+	CloudService  string `json:"cloudService"`
+}
+
+// clusterFirewalls returns the security groups of the nodes of clusterName,
+// each once
+func clusterFirewalls(clusterName string) ([]accesslist.Firewall, error) {
+	cloudIDs, err := node.GetClusterNodes(app, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	var firewalls []accesslist.Firewall
+	seen := map[string]bool{}
+	clouds := map[string]*aws.AwsCloud{}
+	for _, cloudID := range cloudIDs {
+		raw, err := app.LoadClusterNodeConfig(clusterName, cloudID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the config of node %s: %w", cloudID, err)
+		}
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+		var nc nodeCloudConfig
+		if err := json.Unmarshal(data, &nc); err != nil {
+			return nil, err
+		}
+		if nc.CloudService != "" && nc.CloudService != constants.AWSCloudService {
+			ux.Logger.PrintToUser("Skipping node %s: only the security groups of %s nodes are synced", cloudID, constants.AWSCloudService)
+			continue
+		}
+		if nc.Region == "" || nc.SecurityGroup == "" {
+			return nil, fmt.Errorf("node %s has no region or security group in its config", cloudID)
+		}
+		key := nc.Region + "/" + nc.SecurityGroup
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		cloud, ok := clouds[nc.Region]
+		if !ok {
+			cloud, err = aws.NewAwsCloud(accessAWSProfile, nc.Region)
+			if err != nil {
+				return nil, err
+			}
+			clouds[nc.Region] = cloud
+		}
+		firewalls = append(firewalls, &securityGroup{cloud: cloud, region: nc.Region, name: nc.SecurityGroup})
+	}
+	return firewalls, nil
+}
+
+// securityGroup is the AWS security group of cluster nodes
+type securityGroup struct {
+	cloud  *aws.AwsCloud
+	region string
+	name   string
+	id     string
+}
+
+func (sg *securityGroup) Name() string {
+	return fmt.Sprintf("%s (%s)", sg.name, sg.region)
+}
+
+func (sg *securityGroup) Rules(context.Context) ([]accesslist.Rule, error) {
+	id, ingress, err := sg.cloud.GetSecurityGroupIngress(sg.name)
+	if err != nil {
+		return nil, err
+	}
+	sg.id = id
+	rules := make([]accesslist.Rule, len(ingress))
+	for i, r := range ingress {
+		rules[i] = accesslist.Rule{CIDR: r.CIDR, Port: r.Port}
+	}
+	return rules, nil
+}
+
+func (sg *securityGroup) Allow(_ context.Context, r accesslist.Rule) error {
+	return sg.cloud.AddSecurityGroupRule(sg.id, "ingress", "tcp", r.CIDR, r.Port)
+}
+
+func (sg *securityGroup) Revoke(_ context.Context, r accesslist.Rule) error {
+	return sg.cloud.DeleteSecurityGroupRule(sg.id, "ingress", "tcp", r.CIDR, r.Port)
+}
@@ -0,0 +1,256 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nodecmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/ansible"
+	"github.com/luxfi/cli/pkg/backup"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/keychain"
+	"github.com/luxfi/cli/pkg/node"
+	"github.com/luxfi/cli/pkg/snapshot"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportOut    string
+	exportKMSKey string
+	importForce  bool
+	importDryRun bool
+)
+
+// lux node export-cluster
+func newExportClusterCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-cluster <clusterName>",
+		Short: "Export a cloud cluster so it can be administered from another machine",
+		Long: `The export-cluster command bundles what the CLI home holds of a cloud
+cluster, and can't be recreated, in one file: its entry of clusters.json,
+~/.lux/clusters/<cluster>/ (config, ansible and monitoring inventories, node
+configs, access list), the staking certs and keys of its nodes in
+~/.lux/nodes/, and the ssh certs its inventories use.
+
+The file holds the private keys of the nodes. With --kms-key it is encrypted
+by that encrypt-decrypt key of the KMS server of LUX_KMS_ADDR
+(http://localhost:8200 by default, with the API key of LUX_KMS_API_KEY), and
+'lux node import-cluster' decrypts it with the same server.
+
+EXAMPLES:
+
+  lux node export-cluster my-cluster --out my-cluster.tar
+  lux node export-cluster my-cluster --out my-cluster.tar --kms-key <keyId>`,
+		Args:         cobrautils.ExactArgs(1),
+		RunE:         exportCluster,
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVarP(&exportOut, "out", "o", "", "write the export to this file (required)")
+	cmd.Flags().StringVar(&exportKMSKey, "kms-key", "", "encrypt the export with this KMS key")
+	_ = cmd.MarkFlagRequired("out")
+	return cmd
+}
+
+// lux node import-cluster
+func newImportClusterCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-cluster <file>",
+		Short: "Import a cloud cluster exported by 'lux node export-cluster'",
+		Long: `The import-cluster command adds the cluster of an export to the CLI home, so
+the node commands administer it from this machine. Paths of the exporting
+home in its inventories and configs, the ssh certs among them, are moved to
+this home. Exports encrypted with --kms-key are decrypted by the KMS server
+of LUX_KMS_ADDR.
+
+When files of the home, or the clusters.json entry of the cluster, differ
+from the export, they are listed and nothing is imported unless --force is
+given.
+
+EXAMPLES:
+
+  lux node import-cluster my-cluster.tar --dry-run
+  lux node import-cluster my-cluster.tar --force`,
+		Args:         cobrautils.ExactArgs(1),
+		RunE:         importCluster,
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVar(&importForce, "force", false, "overwrite files that differ from the export")
+	cmd.Flags().BoolVar(&importDryRun, "dry-run", false, "show what would be imported without writing anything")
+	return cmd
+}
+
+func exportCluster(cmd *cobra.Command, args []string) error {
+	clusterName := args[0]
+	nodes, err := node.GetClusterNodes(app, clusterName)
+	if err != nil {
+		return err
+	}
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return err
+	}
+	clusters, _ := clustersConfig["clusters"].(map[string]interface{})
+	config, err := json.Marshal(clusters[clusterName])
+	if err != nil {
+		return err
+	}
+
+	paths := []string{filepath.Join("clusters", clusterName)}
+	for _, nodeName := range nodes {
+		paths = append(paths, filepath.Join("nodes", nodeName))
+	}
+	certs, err := clusterSSHCerts(clusterName)
+	if err != nil {
+		return err
+	}
+	paths = append(paths, certs...)
+
+	home := app.Home.Name
+	if home == "" {
+		home = filepath.Base(app.GetBaseDir())
+	}
+	archive, m, err := backup.CreateCluster(app.GetBaseDir(), paths, home, clusterName, config)
+	if err != nil {
+		return fmt.Errorf("failed to export cluster %s: %w", clusterName, err)
+	}
+	ux.Logger.PrintToUser("Exporting cluster %s: %d nodes, %d files (%s)", clusterName, len(nodes), len(m.Files), snapshot.FormatBytes(m.Bytes))
+
+	data := archive
+	if exportKMSKey != "" {
+		client := keychain.NewKMSClientFromEnv()
+		ciphertext, err := client.Encrypt(cmd.Context(), exportKMSKey, archive)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt the export: %w", err)
+		}
+		if data, err = backup.Seal(exportKMSKey, ciphertext); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Encrypted with key %s of KMS %s", exportKMSKey, client.URL)
+	}
+	if err := os.WriteFile(exportOut, data, 0o600); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Cluster %s exported to %s", clusterName, exportOut)
+	if exportKMSKey == "" {
+		ux.Logger.PrintToUser("The export holds the private keys of the nodes unencrypted: keep it safe, or export with --kms-key")
+	}
+	return nil
+}
+
+// clusterSSHCerts returns the ssh certs of the home the inventories of
+// clusterName use, relative to the home
+func clusterSSHCerts(clusterName string) ([]string, error) {
+	seen := map[string]bool{}
+	certs := []string{}
+	for _, dir := range []string{app.GetAnsibleInventoryDirPath(clusterName), app.GetMonitoringInventoryDir(clusterName)} {
+		hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(dir)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, host := range hosts {
+			if host.SSHPrivateKeyPath == "" || seen[host.SSHPrivateKeyPath] {
+				continue
+			}
+			seen[host.SSHPrivateKeyPath] = true
+			rel, err := filepath.Rel(app.GetBaseDir(), host.SSHPrivateKeyPath)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				ux.Logger.PrintToUser("Skipping ssh cert %s of %s, outside of %s: copy it to the new machine yourself",
+					host.SSHPrivateKeyPath, host.NodeID, app.GetBaseDir())
+				continue
+			}
+			certs = append(certs, rel)
+		}
+	}
+	return certs, nil
+}
+
+func importCluster(cmd *cobra.Command, args []string) error {
+	source := args[0]
+	data, err := os.ReadFile(source) //nolint:gosec // G304: Reading the export the user gave
+	if err != nil {
+		return err
+	}
+	archive, err := openSealed(cmd.Context(), data)
+	if err != nil {
+		return err
+	}
+	m, err := backup.ReadClusterManifest(archive)
+	if err != nil {
+		return err
+	}
+	clusterName := m.Cluster.Name
+	ux.Logger.PrintToUser("Cluster %s exported from %s on %s, %s: %d files",
+		clusterName, m.Cluster.BaseDir, m.Host, m.Created.Local().Format("2006-01-02 15:04:05"), len(m.Files))
+
+	clustersConfig := map[string]interface{}{}
+	if app.ClustersConfigExists() {
+		if clustersConfig, err = app.LoadClustersConfig(); err != nil {
+			return err
+		}
+	}
+	clusters, _ := clustersConfig["clusters"].(map[string]interface{})
+	if clusters == nil {
+		clusters = map[string]interface{}{}
+	}
+	var config interface{}
+	if err := json.Unmarshal(m.Cluster.ConfigIn(app.GetBaseDir()), &config); err != nil {
+		return fmt.Errorf("invalid config of cluster %s in the export: %w", clusterName, err)
+	}
+
+	conflicts, err := backup.ClusterConflicts(app.GetBaseDir(), archive)
+	if err != nil {
+		return err
+	}
+	if current, ok := clusters[clusterName]; ok && !reflect.DeepEqual(current, config) {
+		conflicts = append(conflicts, constants.ClustersConfigFileName)
+	}
+	if importDryRun {
+		for _, f := range m.Files {
+			ux.Logger.PrintToUser("  %s", f)
+		}
+		if len(conflicts) > 0 {
+			ux.Logger.PrintToUser("Would overwrite %d changed files with --force: %s", len(conflicts), strings.Join(conflicts, ", "))
+		}
+		return nil
+	}
+	if len(conflicts) > 0 && !importForce {
+		return fmt.Errorf("%d files of %s differ from the export, import with --force to overwrite them: %s",
+			len(conflicts), app.GetBaseDir(), strings.Join(conflicts, ", "))
+	}
+
+	lock, err := app.AcquireLock("node import-cluster")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+	restored, err := backup.RestoreCluster(app.GetBaseDir(), archive)
+	if err != nil {
+		return fmt.Errorf("failed to import cluster %s: %w", clusterName, err)
+	}
+	clusters[clusterName] = config
+	clustersConfig["clusters"] = clusters
+	if err := app.SaveClustersConfig(clustersConfig); err != nil {
+		return err
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "node import-cluster",
+		Params:    map[string]string{"cluster": clusterName, "source": source, "files": strconv.Itoa(len(restored))},
+	})
+	ux.Logger.GreenCheckmarkToUser("Imported cluster %s, %d files, to %s", clusterName, len(restored), app.GetBaseDir())
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nodecmd
+
+import (
+	"fmt"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/node"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var configDryRun bool
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Detect and fix config drift on the nodes of a cluster",
+		Long: `The config command compares the config files of the chains a cloud cluster
+tracks, as kept in ~/.lux, with their copy on every node of the cluster:
+
+  - the chain node config, merged into the node config (node.json)
+  - the chain config (chains/<chainID>.json)
+  - the blockchain config (chains/<blockchainID>/config.json)
+  - the network upgrades (chains/chains/<blockchainID>/upgrade.json)
+
+Only the settings of the chain node config are compared in the node config,
+as the rest of it is rendered per node.
+
+diff reports the settings a node's copy misses, adds or changes. sync uploads
+the local files to the nodes that drifted and restarts their node.
+
+SECRETS:
+
+Config values may reference secrets of the KMS server of LUX_KMS_ADDR as
+kms://secret/<path>/<name>. Keys held in plain text, such as
+staking-signer-key-file-content or account-private-key, are stored in the
+KMS under /chains/<chain>/<kind> by sync and replaced by such references.
+Nodes keep the config with its references, as <file>.tmpl, and the file
+links to its rendering in /dev/shm/lux-cli, so raw keys never reach their
+disk. A reboot clears the rendering; diff then reports the file missing
+and sync renders it again.
+
+EXAMPLES:
+
+  lux node config diff my-cluster
+  lux node config sync my-cluster --dry-run
+  lux node config sync my-cluster`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:          "diff [clusterName]",
+		Short:        "Show where the config of the nodes drifted from the local one",
+		Args:         cobra.ExactArgs(1),
+		RunE:         diffConfig,
+		SilenceUsage: true,
+	})
+	syncCmd := &cobra.Command{
+		Use:          "sync [clusterName]",
+		Short:        "Push the local config to the nodes that drifted and restart them",
+		Args:         cobra.ExactArgs(1),
+		RunE:         syncConfig,
+		SilenceUsage: true,
+	}
+	syncCmd.Flags().BoolVar(&configDryRun, "dry-run", false, "show the drift without fixing it")
+	cmd.AddCommand(syncCmd)
+	return cmd
+}
+
+func diffConfig(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	drifts, err := node.DiffClusterConfig(app, clusterName)
+	if err != nil {
+		return err
+	}
+	printConfigDrifts(clusterName, drifts)
+	if len(drifts) > 0 {
+		ux.Logger.PrintToUser("Run 'lux node config sync %s' to push the local config", clusterName)
+	}
+	return nil
+}
+
+func syncConfig(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if configDryRun {
+		drifts, err := node.DiffClusterConfig(app, clusterName)
+		if err != nil {
+			return err
+		}
+		printConfigDrifts(clusterName, drifts)
+		return nil
+	}
+	drifts, err := node.SyncClusterConfig(app, clusterName)
+	if err != nil {
+		return err
+	}
+	printConfigDrifts(clusterName, drifts)
+	restarted := map[string]bool{}
+	for _, d := range drifts {
+		restarted[d.Host.NodeID] = true
+	}
+	if len(restarted) > 0 {
+		ux.Logger.GreenCheckmarkToUser("Pushed the local config to %d node(s) and restarted them", len(restarted))
+	}
+	return nil
+}
+
+func printConfigDrifts(clusterName string, drifts []node.ConfigDrift) {
+	if len(drifts) == 0 {
+		ux.Logger.PrintToUser("The nodes of cluster %s are in sync with the local config", clusterName)
+		return
+	}
+	host := ""
+	for _, d := range drifts {
+		if d.Host.NodeID != host {
+			host = d.Host.NodeID
+			ux.Logger.PrintToUser("%s:", d.Host.GetCloudID())
+		}
+		name := fmt.Sprintf("%s %s config %s", d.File.Chain, d.File.Kind, d.File.RemotePath)
+		if d.Missing {
+			ux.Logger.PrintToUser("  %s: missing", name)
+			continue
+		}
+		ux.Logger.PrintToUser("  %s:", name)
+		for _, change := range d.Changes {
+			ux.Logger.PrintToUser("    %s", change)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nodecmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/luxfi/cli/pkg/ansible"
+	"github.com/luxfi/cli/pkg/cloud/aws"
+	"github.com/luxfi/cli/pkg/cloud/gcp"
+	"github.com/luxfi/cli/pkg/clusterdns"
+	"github.com/luxfi/cli/pkg/node"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/sdk/models"
+	"github.com/spf13/cobra"
+)
+
+const (
+	route53Provider  = "route53"
+	cloudDNSProvider = "clouddns"
+	proxyContainer   = "lux-caddy"
+)
+
+var (
+	dnsDomain         string
+	dnsProvider       string
+	dnsHostedZone     string
+	dnsAWSProfile     string
+	dnsGCPProject     string
+	dnsGCPCredentials string
+	acmeEmail         string
+)
+
+func newDNSCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dns [clusterName]",
+		Short: "Give the API nodes of a cluster stable https URLs",
+		Long: `Register DNS records for the API nodes of a cloud cluster in Route53 or
+Cloud DNS and serve their RPC over https, with certificates obtained from
+Let's Encrypt over ACME by a Caddy reverse proxy on each node.
+
+The first API node serves https://rpc.<cluster>.<domain> and the others
+https://rpc-<n>.<cluster>.<domain>. Devnets without API nodes expose their
+validators. The URLs are recorded in the cluster YAML, and running the
+command again updates the records after nodes changed.
+
+Ports 80 and 443 of the nodes must be reachable for ACME to issue the
+certificates.
+
+EXAMPLES:
+
+  lux node dns my-devnet --domain example.com --dns-provider route53 --acme-email ops@example.com
+  lux node dns my-devnet --domain example.com --dns-provider clouddns --gcp-project my-project`,
+		Args:         cobra.ExactArgs(1),
+		RunE:         setupClusterDNS,
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&dnsDomain, "domain", "", "DNS zone to create the records in, e.g. example.com")
+	cmd.Flags().StringVar(&dnsProvider, "dns-provider", route53Provider, "DNS provider of the zone (route53, clouddns)")
+	cmd.Flags().StringVar(&dnsHostedZone, "hosted-zone", "", "Route53 hosted zone ID (default looked up from --domain)")
+	cmd.Flags().StringVar(&dnsAWSProfile, "aws-profile", "default", "AWS profile to use for Route53")
+	cmd.Flags().StringVar(&dnsGCPProject, "gcp-project", "", "GCP project of the Cloud DNS zone")
+	cmd.Flags().StringVar(&dnsGCPCredentials, "gcp-credentials", "", "GCP service account key file (default application credentials)")
+	cmd.Flags().StringVar(&acmeEmail, "acme-email", "", "contact email of the ACME account, notified about expiring certificates")
+	_ = cmd.MarkFlagRequired("domain")
+	return cmd
+}
+
+func setupClusterDNS(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	dns, err := newDNSProvider(ctx)
+	if err != nil {
+		return err
+	}
+	cluster, err := node.GetClusterEndpoints(app, clusterName)
+	if err != nil {
+		return err
+	}
+	if cluster.Local {
+		return fmt.Errorf("cluster %s is local, DNS is only set up for cloud clusters", clusterName)
+	}
+	var apiNodes, allNodes []clusterdns.Node
+	for _, n := range cluster.Nodes {
+		allNodes = append(allNodes, clusterdns.Node{CloudID: n.CloudID, IP: n.IP})
+		if n.API {
+			apiNodes = append(apiNodes, clusterdns.Node{CloudID: n.CloudID, IP: n.IP})
+		}
+	}
+	if len(apiNodes) == 0 {
+		apiNodes = allNodes
+	}
+
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	defer node.DisconnectHosts(hosts)
+	proxies := make(map[string]clusterdns.Proxy, len(hosts))
+	for _, host := range hosts {
+		proxies[host.GetCloudID()] = &caddyProxy{host: host}
+	}
+
+	opts := clusterdns.Options{
+		Cluster:      clusterName,
+		Domain:       dnsDomain,
+		Email:        acmeEmail,
+		UpstreamPort: constants.LuxdAPIPort,
+		Progress: func(name, step string) {
+			ux.Logger.PrintToUser("  %s: %s", name, step)
+		},
+	}
+	ux.Logger.PrintToUser("Setting up https endpoints for %d node(s) of %s...", len(apiNodes), clusterName)
+	endpoints, err := clusterdns.Setup(ctx, opts, apiNodes, dns, proxies)
+	if len(endpoints) > 0 {
+		rpc := clusterdns.ClusterRPC{
+			URL:       endpoints[0].URL(),
+			Domain:    dnsDomain,
+			Provider:  dnsProvider,
+			Endpoints: endpoints,
+		}
+		if recordErr := clusterdns.Record(app.GetClusterYAMLFilePath(clusterName), rpc); recordErr != nil {
+			ux.Logger.RedXToUser("failed to record the endpoints in the cluster YAML: %s", recordErr)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	ux.Logger.PrintToUser("")
+	for _, e := range endpoints {
+		ux.Logger.PrintToUser("  %s  C-Chain RPC: %s/ext/bc/C/rpc", e.CloudID, e.URL())
+	}
+	ux.Logger.PrintToUser("\nCertificates are issued on the first https request, once the records propagated.")
+	ux.Logger.PrintToUser("Endpoints recorded in %s", app.GetClusterYAMLFilePath(clusterName))
+	return nil
+}
+
+func newDNSProvider(ctx context.Context) (clusterdns.Provider, error) {
+	switch dnsProvider {
+	case route53Provider:
+		return aws.NewRoute53DNS(ctx, dnsAWSProfile, dnsDomain, dnsHostedZone)
+	case cloudDNSProvider:
+		if dnsGCPProject == "" {
+			return nil, fmt.Errorf("--dns-provider %s needs the --gcp-project of the zone", cloudDNSProvider)
+		}
+		return gcp.NewCloudDNS(ctx, dnsGCPProject, dnsGCPCredentials, dnsDomain)
+	default:
+		return nil, fmt.Errorf("unknown DNS provider %q, use %s or %s", dnsProvider, route53Provider, cloudDNSProvider)
+	}
+}
+
+// caddyProxy runs Caddy in docker on a cluster node, next to luxd
+type caddyProxy struct {
+	host *models.Host
+}
+
+func (p *caddyProxy) Install(_ context.Context, config []byte) error {
+	remoteDir := utils.GetRemoteComposeServicePath("caddy")
+	if err := p.host.MkdirAll(remoteDir, constants.SSHFileOpsTimeout); err != nil {
+		return err
+	}
+	remoteConfig := utils.GetRemoteComposeServicePath("caddy", "Caddyfile")
+	if err := p.host.UploadBytes(config, remoteConfig, constants.SSHFileOpsTimeout); err != nil {
+		return err
+	}
+	script := fmt.Sprintf(
+		"docker rm -f %[1]s >/dev/null 2>&1; docker run -d --name %[1]s --restart unless-stopped --network host -v %[2]s:/etc/caddy/Caddyfile:ro -v %[1]s-data:/data %[3]s",
+		proxyContainer, remoteConfig, clusterdns.ProxyImage,
+	)
+	if output, err := p.host.Command(script, nil, constants.SSHLongRunningScriptTimeout); err != nil {
+		return fmt.Errorf("%w: %s", err, output)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nodecmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/config"
+	"github.com/luxfi/cli/pkg/node"
+	"github.com/luxfi/cli/pkg/status"
+	"github.com/spf13/cobra"
+)
+
+var gpuOutput string
+
+func newGPUCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gpu [clusterName]",
+		Short: "Show the GPU of each node and the chains it accelerates",
+		Long: `Reports the GPU acceleration of the nodes of the running local networks,
+or of a cluster, as read from the gpu check of their health API:
+
+  - device, driver and backend (cuda, metal)
+  - utilization and memory
+  - the chains each node runs on its GPU
+  - whether the GPU passes its health check
+
+With --output json, each network carries a GPU summary for schedulers:
+Available, AllNodes, Healthy, the MaxUtilization of the busiest GPU and
+the accelerated Chains. The same summary is in 'lux network status -o json'.
+
+EXAMPLES:
+  lux node gpu
+  lux node gpu my-cluster
+  lux node gpu my-cluster --output json`,
+		Args:         cobrautils.MaximumNArgs(1),
+		RunE:         runGPU,
+		SilenceUsage: true,
+		Annotations:  map[string]string{config.CapabilityAnnotation: config.CapabilityNone},
+	}
+	cmd.Flags().StringVarP(&gpuOutput, "output", "o", "text", "output format (text, json)")
+	return cmd
+}
+
+func runGPU(_ *cobra.Command, args []string) error {
+	if gpuOutput != "text" && gpuOutput != "json" {
+		return fmt.Errorf("invalid --output %q, expected text or json", gpuOutput)
+	}
+	result, err := gpuStatus(context.Background(), args)
+	if err != nil {
+		if errors.Is(err, status.ErrNoNetwork) {
+			return fmt.Errorf("no network running")
+		}
+		return err
+	}
+
+	formatter := status.NewStatusFormatter(os.Stdout)
+	if gpuOutput == "json" {
+		return formatter.FormatJSON(status.NewGPUReport(result))
+	}
+	formatter.FormatGPU(result)
+	return nil
+}
+
+// gpuStatus probes the nodes of the cluster named in args, or of the local
+// networks
+func gpuStatus(ctx context.Context, args []string) (*status.StatusResult, error) {
+	service := status.NewStatusService()
+	if len(args) == 0 {
+		return service.GetStatus(ctx)
+	}
+	clusterName := args[0]
+	endpoints, err := node.GetClusterEndpoints(app, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if endpoints.Local {
+		result, err := service.GetStatus(ctx)
+		if err != nil {
+			return nil, err
+		}
+		probed := localClusterNetwork(result, endpoints.Network)
+		if probed == nil {
+			return nil, status.ErrNoNetwork
+		}
+		result.Networks = []status.Network{*probed}
+		return result, nil
+	}
+	urls := make([]string, 0, len(endpoints.Nodes))
+	for _, n := range endpoints.Nodes {
+		urls = append(urls, n.URL())
+	}
+	probed, err := service.ProbeEndpoints(ctx, clusterName, urls)
+	if err != nil {
+		return nil, err
+	}
+	return &status.StatusResult{Networks: []status.Network{*probed}, Timestamp: time.Now()}, nil
+}
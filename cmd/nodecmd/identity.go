@@ -0,0 +1,430 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nodecmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/ansible"
+	"github.com/luxfi/cli/pkg/backup"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/keychain"
+	"github.com/luxfi/cli/pkg/node"
+	"github.com/luxfi/cli/pkg/nodeidentity"
+	"github.com/luxfi/cli/pkg/ssh"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+	"github.com/spf13/cobra"
+)
+
+const (
+	localIdentityTarget = "local"
+	// encryptWithPassphrase is the --encrypt-with value sealing backups with a
+	// passphrase instead of a KMS key
+	encryptWithPassphrase = "passphrase"
+	// identityPassphraseEnvVar holds the passphrase, instead of prompting
+	identityPassphraseEnvVar = "LUX_IDENTITY_PASSPHRASE"
+	identityRestartTimeout   = 3 * time.Minute
+)
+
+// cloudIdentityFiles are the staking files of cloud nodes, on the hosts and
+// in the CLI home
+var cloudIdentityFiles = nodeidentity.Files{
+	Cert:   constants.StakerCertFileName,
+	Key:    constants.StakerKeyFileName,
+	BLSKey: constants.BLSKeyFileName,
+}
+
+var (
+	identityEncryptWith string
+	identityOut         string
+	identityStakingDir  string
+	identityNodeID      string
+	identityHost        string
+	identityEndpoint    string
+	identityForce       bool
+)
+
+// lux node identity
+func newIdentityCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "identity",
+		Short: "Back up and restore the staking identity of nodes",
+		Long: `The identity command backs up the staking cert, staking key and BLS key of
+nodes, which their NodeID and stake belong to, and restores them on a
+replacement host. Backups are always encrypted: with a key of the KMS server
+of LUX_KMS_ADDR, or with a passphrase.`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.AddCommand(newIdentityBackupCmd())
+	cmd.AddCommand(newIdentityRestoreCmd())
+	return cmd
+}
+
+// lux node identity backup
+func newIdentityBackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup <clusterName|local>",
+		Short: "Back up the staking identity of the nodes of a cluster, or of this machine",
+		Long: `The backup command saves the staking identity of nodes in one encrypted file:
+of each node of a cloud cluster, read from the hosts over ssh, or of the
+luxd of this machine with 'local', read from --staking-dir.
+
+--encrypt-with is the encrypt-decrypt key of the KMS server of LUX_KMS_ADDR
+to encrypt the backup with, or 'passphrase' to encrypt it with a passphrase,
+read from LUX_IDENTITY_PASSPHRASE or prompted for.
+
+EXAMPLES:
+
+  lux node identity backup my-cluster --encrypt-with <keyId> --out my-cluster-identity.json
+  lux node identity backup local --encrypt-with passphrase --out node-identity.json`,
+		Args:         cobrautils.ExactArgs(1),
+		RunE:         backupIdentity,
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&identityEncryptWith, "encrypt-with", "", "KMS key to encrypt the backup with, or 'passphrase' (required)")
+	cmd.Flags().StringVarP(&identityOut, "out", "o", "", "write the backup to this file (required)")
+	cmd.Flags().StringVar(&identityStakingDir, "staking-dir", defaultStakingDir(), "staking directory of the local node")
+	_ = cmd.MarkFlagRequired("encrypt-with")
+	_ = cmd.MarkFlagRequired("out")
+	return cmd
+}
+
+// lux node identity restore
+func newIdentityRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <clusterName|local> <file>",
+		Short: "Restore a staking identity on a cluster host, or on this machine",
+		Long: `The restore command installs a staking identity of a backup on a host of a
+cloud cluster, restarts its luxd and checks the node reports the NodeID of
+the identity. With 'local' it installs the identity in --staking-dir, and
+checks the node of --endpoint reports its NodeID when given.
+
+--node-id picks the identity when the backup holds several, and --host the
+host of the cluster when it has several. A host or staking directory
+holding another identity is only overwritten with --force: it may be a
+live validator.
+
+EXAMPLES:
+
+  lux node identity restore my-cluster my-cluster-identity.json --host i-0b1c2d3e --node-id NodeID-7Xhw2...
+  lux node identity restore local node-identity.json --endpoint http://127.0.0.1:9630`,
+		Args:         cobrautils.ExactArgs(2),
+		RunE:         restoreIdentity,
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&identityNodeID, "node-id", "", "NodeID of the identity to restore, when the backup holds several")
+	cmd.Flags().StringVar(&identityHost, "host", "", "cloud ID of the cluster host to restore on, when the cluster has several")
+	cmd.Flags().StringVar(&identityStakingDir, "staking-dir", defaultStakingDir(), "staking directory of the local node")
+	cmd.Flags().StringVar(&identityEndpoint, "endpoint", "", "API of the local node to check the NodeID of, once restarted")
+	cmd.Flags().BoolVar(&identityForce, "force", false, "overwrite a different identity")
+	return cmd
+}
+
+func defaultStakingDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".luxd", "staking")
+}
+
+func backupIdentity(cmd *cobra.Command, args []string) error {
+	source := args[0]
+	var identities []nodeidentity.Identity
+	if source == localIdentityTarget {
+		i, err := nodeidentity.Read(localIdentityTarget, identityStakingDir, nodeidentity.LocalFiles)
+		if err != nil {
+			return fmt.Errorf("failed to read the identity of the local node: %w", err)
+		}
+		identities = append(identities, *i)
+	} else {
+		hosts, err := identityHosts(source)
+		if err != nil {
+			return err
+		}
+		defer node.DisconnectHosts(hosts)
+		for _, host := range hosts {
+			i, err := readHostIdentity(host)
+			if err != nil {
+				return fmt.Errorf("failed to read the identity of %s: %w", host.GetCloudID(), err)
+			}
+			identities = append(identities, *i)
+		}
+	}
+
+	data, err := nodeidentity.Marshal(source, identities)
+	if err != nil {
+		return err
+	}
+	if data, err = sealIdentityBackup(cmd.Context(), data); err != nil {
+		return err
+	}
+	if err := os.WriteFile(identityOut, data, 0o600); err != nil {
+		return err
+	}
+	for _, i := range identities {
+		ux.Logger.PrintToUser("  %s  %s", i.NodeID, i.Node)
+	}
+	ux.Logger.GreenCheckmarkToUser("Backed up %d node identities of %s to %s", len(identities), source, identityOut)
+	return nil
+}
+
+func restoreIdentity(cmd *cobra.Command, args []string) error {
+	target, source := args[0], args[1]
+	data, err := os.ReadFile(source) //nolint:gosec // G304: Reading the backup the user gave
+	if err != nil {
+		return err
+	}
+	if data, err = openSealed(cmd.Context(), data); err != nil {
+		return err
+	}
+	b, err := nodeidentity.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	i, err := b.Find(identityNodeID)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Restoring %s, backed up from %s of %s on %s", i.NodeID, i.Node, b.Source, b.Created.Local().Format("2006-01-02 15:04:05"))
+
+	restoredOn := target
+	if target == localIdentityTarget {
+		err = restoreLocalIdentity(i)
+	} else {
+		restoredOn, err = restoreHostIdentity(target, i)
+	}
+	if err != nil {
+		return err
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "node identity restore",
+		Params:    map[string]string{"target": restoredOn, "nodeID": i.NodeID.String(), "source": source},
+	})
+	return nil
+}
+
+// identityHosts returns the hosts of the cloud cluster clusterName
+func identityHosts(clusterName string) ([]*models.Host, error) {
+	endpoints, err := node.GetClusterEndpoints(app, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if endpoints.Local {
+		return nil, fmt.Errorf("cluster %s runs on this machine, use 'local' instead", clusterName)
+	}
+	return ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+}
+
+// readHostIdentity reads the identity of the luxd of host over ssh
+func readHostIdentity(host *models.Host) (*nodeidentity.Identity, error) {
+	contents := make([][]byte, 0, 3)
+	for _, name := range []string{cloudIdentityFiles.Cert, cloudIdentityFiles.Key, cloudIdentityFiles.BLSKey} {
+		b, err := host.ReadFileBytes(path.Join(constants.CloudNodeStakingPath, name), constants.SSHFileOpsTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		contents = append(contents, b)
+	}
+	return nodeidentity.New(host.GetCloudID(), contents[0], contents[1], contents[2])
+}
+
+func restoreLocalIdentity(i *nodeidentity.Identity) error {
+	current, err := nodeidentity.Read(localIdentityTarget, identityStakingDir, nodeidentity.LocalFiles)
+	switch {
+	case err == nil && current.NodeID != i.NodeID && !identityForce:
+		return fmt.Errorf("%s holds the identity of %s, restore with --force to overwrite it", identityStakingDir, current.NodeID)
+	case err != nil && !errors.Is(err, fs.ErrNotExist) && !identityForce:
+		return fmt.Errorf("%s holds unreadable staking files, restore with --force to overwrite them: %w", identityStakingDir, err)
+	}
+	if err := i.Write(identityStakingDir, nodeidentity.LocalFiles); err != nil {
+		return err
+	}
+	restored, err := nodeidentity.Read(localIdentityTarget, identityStakingDir, nodeidentity.LocalFiles)
+	if err != nil {
+		return err
+	}
+	if restored.NodeID != i.NodeID {
+		return fmt.Errorf("restored files derive NodeID %s, not %s", restored.NodeID, i.NodeID)
+	}
+	ux.Logger.GreenCheckmarkToUser("Restored %s to %s", i.NodeID, identityStakingDir)
+	if identityEndpoint == "" {
+		ux.Logger.PrintToUser("Restart luxd, and check it reports %s with --endpoint", i.NodeID)
+		return nil
+	}
+	nodeID, _, _, err := utils.GetNodeID(identityEndpoint)
+	if err != nil {
+		return fmt.Errorf("failed to get the NodeID of %s: %w", identityEndpoint, err)
+	}
+	if nodeID != i.NodeID.String() {
+		return fmt.Errorf("node of %s reports %s, not %s: restart luxd with the staking files of %s", identityEndpoint, nodeID, i.NodeID, identityStakingDir)
+	}
+	ux.Logger.GreenCheckmarkToUser("Node of %s reports %s", identityEndpoint, nodeID)
+	return nil
+}
+
+// restoreHostIdentity installs i on a host of clusterName, and the CLI home
+// copy of its staking files, and returns the host
+func restoreHostIdentity(clusterName string, i *nodeidentity.Identity) (string, error) {
+	hosts, err := identityHosts(clusterName)
+	if err != nil {
+		return "", err
+	}
+	defer node.DisconnectHosts(hosts)
+	host, err := pickIdentityHost(hosts, identityHost)
+	if err != nil {
+		return "", err
+	}
+	cloudID := host.GetCloudID()
+	if cert, err := host.ReadFileBytes(path.Join(constants.CloudNodeStakingPath, cloudIdentityFiles.Cert), constants.SSHFileOpsTimeout); err == nil {
+		if current, err := utils.ToNodeID(cert); err == nil && current != i.NodeID && !identityForce {
+			return "", fmt.Errorf("%s runs %s, restore with --force to overwrite it", cloudID, current)
+		}
+	}
+
+	if err := host.MkdirAll(constants.CloudNodeStakingPath, constants.SSHDirOpsTimeout); err != nil {
+		return "", err
+	}
+	for name, b := range map[string][]byte{
+		cloudIdentityFiles.Cert:   i.StakerCert,
+		cloudIdentityFiles.Key:    i.StakerKey,
+		cloudIdentityFiles.BLSKey: i.BLSKey,
+	} {
+		if err := host.UploadBytes(b, path.Join(constants.CloudNodeStakingPath, name), constants.SSHFileOpsTimeout); err != nil {
+			return "", fmt.Errorf("failed to upload %s to %s: %w", name, cloudID, err)
+		}
+	}
+	// later deploys upload the staking files of the CLI home
+	if err := i.Write(app.GetNodeInstanceDirPath(cloudID), cloudIdentityFiles); err != nil {
+		return "", err
+	}
+	ux.Logger.PrintToUser("Installed %s on %s, restarting luxd", i.NodeID, cloudID)
+	if err := ssh.RunSSHRestartNode(host); err != nil {
+		return "", fmt.Errorf("failed to restart luxd on %s: %w", cloudID, err)
+	}
+	nodeID, err := waitHostNodeID(host)
+	if err != nil {
+		return "", fmt.Errorf("luxd of %s doesn't report its NodeID: %w", cloudID, err)
+	}
+	if nodeID != i.NodeID {
+		return "", fmt.Errorf("luxd of %s reports %s, not %s", cloudID, nodeID, i.NodeID)
+	}
+	ux.Logger.GreenCheckmarkToUser("%s runs %s", cloudID, nodeID)
+	return clusterName + "/" + cloudID, nil
+}
+
+// pickIdentityHost returns the host of cloudID, or the only host when
+// cloudID is empty
+func pickIdentityHost(hosts []*models.Host, cloudID string) (*models.Host, error) {
+	cloudIDs := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		if cloudID != "" && host.GetCloudID() == cloudID {
+			return host, nil
+		}
+		cloudIDs = append(cloudIDs, host.GetCloudID())
+	}
+	if cloudID == "" && len(hosts) == 1 {
+		return hosts[0], nil
+	}
+	if cloudID == "" {
+		return nil, fmt.Errorf("cluster has %d hosts, pick one with --host: %s", len(hosts), strings.Join(cloudIDs, ", "))
+	}
+	return nil, fmt.Errorf("no host %s in the cluster, its hosts are %s", cloudID, strings.Join(cloudIDs, ", "))
+}
+
+// waitHostNodeID returns the NodeID the luxd of host reports once it
+// answers again
+func waitHostNodeID(host *models.Host) (ids.NodeID, error) {
+	deadline := time.Now().Add(identityRestartTimeout)
+	for {
+		resp, err := ssh.RunSSHGetNodeID(host)
+		if err == nil {
+			var nodeID ids.NodeID
+			if nodeID, err = node.ParseNodeID(resp); err == nil {
+				return nodeID, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return ids.EmptyNodeID, err
+		}
+		time.Sleep(constants.SSHSleepBetweenChecks)
+	}
+}
+
+// sealIdentityBackup encrypts data as --encrypt-with says
+func sealIdentityBackup(ctx context.Context, data []byte) ([]byte, error) {
+	if identityEncryptWith == encryptWithPassphrase {
+		passphrase, err := identityPassphrase(true)
+		if err != nil {
+			return nil, err
+		}
+		return backup.SealWithPassphrase(passphrase, data)
+	}
+	client := keychain.NewKMSClientFromEnv()
+	ciphertext, err := client.Encrypt(ctx, identityEncryptWith, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt the backup: %w", err)
+	}
+	ux.Logger.PrintToUser("Encrypted with key %s of KMS %s", identityEncryptWith, client.URL)
+	return backup.Seal(identityEncryptWith, ciphertext)
+}
+
+// openSealed returns the archive of data, a backup or export decrypted with
+// the KMS key or passphrase it was sealed with
+func openSealed(ctx context.Context, data []byte) ([]byte, error) {
+	sealed, ok, err := backup.Unseal(data)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return data, nil
+	}
+	if sealed.ByPassphrase() {
+		passphrase, err := identityPassphrase(false)
+		if err != nil {
+			return nil, err
+		}
+		return sealed.OpenWithPassphrase(passphrase)
+	}
+	archive, err := keychain.NewKMSClientFromEnv().Decrypt(ctx, sealed.KMSKey, sealed.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt with key %s: %w", sealed.KMSKey, err)
+	}
+	return archive, nil
+}
+
+// identityPassphrase returns the passphrase of LUX_IDENTITY_PASSPHRASE, or
+// prompts for it, twice when confirm
+func identityPassphrase(confirm bool) (string, error) {
+	if passphrase := os.Getenv(identityPassphraseEnvVar); passphrase != "" {
+		return passphrase, nil
+	}
+	passphrase, err := app.Prompt.CaptureString("Passphrase")
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", errors.New("passphrase required")
+	}
+	if confirm {
+		again, err := app.Prompt.CaptureString("Confirm passphrase")
+		if err != nil {
+			return "", err
+		}
+		if again != passphrase {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
@@ -0,0 +1,29 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nodecmd
+
+import (
+	"testing"
+
+	"github.com/luxfi/sdk/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPickIdentityHost(t *testing.T) {
+	require := require.New(t)
+	first := &models.Host{NodeID: "aws_node_i-0a1"}
+	second := &models.Host{NodeID: "aws_node_i-0b2"}
+
+	host, err := pickIdentityHost([]*models.Host{first}, "")
+	require.NoError(err)
+	require.Equal(first, host)
+	host, err = pickIdentityHost([]*models.Host{first, second}, "i-0b2")
+	require.NoError(err)
+	require.Equal(second, host)
+
+	_, err = pickIdentityHost([]*models.Host{first, second}, "")
+	require.ErrorContains(err, "pick one with --host: i-0a1, i-0b2")
+	_, err = pickIdentityHost([]*models.Host{first, second}, "i-0c3")
+	require.ErrorContains(err, "no host i-0c3")
+}
@@ -6,9 +6,13 @@ package nodecmd
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
+	"github.com/luxfi/cli/pkg/binutils"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -29,6 +33,11 @@ const (
 	containerName   = "luxd"
 	healthPath      = "/ext/health"
 	defaultHTTPPort = int32(9630)
+
+	// archLabel is the well-known node label holding the node's GOARCH
+	archLabel = "kubernetes.io/arch"
+	// defaultArch is assumed when neither the StatefulSet nor its pods say otherwise
+	defaultArch = "amd64"
 )
 
 // resolveNamespace returns the k8s namespace from flags.
@@ -119,6 +128,57 @@ func podImage(ctx context.Context, client *kubernetes.Clientset, namespace, podN
 	return "", fmt.Errorf("container %s not found in pod %s", containerName, podName)
 }
 
+// podArchs returns the pods of the StatefulSet grouped by the architecture of
+// the node each one is scheduled on. Unscheduled pods are skipped.
+func podArchs(ctx context.Context, client *kubernetes.Clientset, namespace string, replicas int32) (map[string][]string, error) {
+	archs := map[string][]string{}
+	for i := int32(0); i < replicas; i++ {
+		podName := fmt.Sprintf("%s-%d", statefulSetName, i)
+		pod, err := client.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil || pod.Spec.NodeName == "" {
+			continue
+		}
+		node, err := client.CoreV1().Nodes().Get(ctx, pod.Spec.NodeName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get node %s of pod %s: %w", pod.Spec.NodeName, podName, err)
+		}
+		if arch := node.Labels[archLabel]; arch != "" {
+			archs[arch] = append(archs[arch], podName)
+		}
+	}
+	return archs, nil
+}
+
+// resolveClusterArch returns the single architecture luxd runs on. An arch
+// nodeSelector on the pod template wins; otherwise all scheduled pods must
+// agree, since the init container installs one plugin build for every pod.
+func resolveClusterArch(nodeSelector map[string]string, archs map[string][]string) (string, error) {
+	if arch, ok := nodeSelector[archLabel]; ok {
+		arch = binutils.NormalizeArch(arch)
+		if !binutils.ArchSupported(arch) {
+			return "", fmt.Errorf("unsupported architecture %q in nodeSelector", arch)
+		}
+		return arch, nil
+	}
+	switch len(archs) {
+	case 0:
+		return defaultArch, nil
+	case 1:
+		for arch := range archs {
+			arch = binutils.NormalizeArch(arch)
+			if !binutils.ArchSupported(arch) {
+				return "", fmt.Errorf("unsupported node architecture %q", arch)
+			}
+			return arch, nil
+		}
+	}
+	var parts []string
+	for _, arch := range slices.Sorted(maps.Keys(archs)) {
+		parts = append(parts, fmt.Sprintf("%s (%s)", arch, strings.Join(archs[arch], ", ")))
+	}
+	return "", fmt.Errorf("pods run on mixed architectures: %s; pin the StatefulSet with a %s nodeSelector", strings.Join(parts, ", "), archLabel)
+}
+
 // int32Ptr returns a pointer to an int32 value.
 func int32Ptr(i int32) *int32 {
 	return &i
@@ -181,3 +181,51 @@ func TestFormatDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveClusterArch(t *testing.T) {
+	tests := []struct {
+		name         string
+		nodeSelector map[string]string
+		archs        map[string][]string
+		want         string
+		wantErr      bool
+	}{
+		{
+			name: "no scheduled pods defaults to amd64",
+			want: "amd64",
+		},
+		{
+			name:  "all pods on arm64",
+			archs: map[string][]string{"arm64": {"luxd-0", "luxd-1"}},
+			want:  "arm64",
+		},
+		{
+			name:         "nodeSelector wins",
+			nodeSelector: map[string]string{archLabel: "arm64"},
+			archs:        map[string][]string{"amd64": {"luxd-0"}, "arm64": {"luxd-1"}},
+			want:         "arm64",
+		},
+		{
+			name:    "mixed architectures = error",
+			archs:   map[string][]string{"amd64": {"luxd-0"}, "arm64": {"luxd-1"}},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported architecture = error",
+			archs:   map[string][]string{"s390x": {"luxd-0"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveClusterArch(tt.nodeSelector, tt.archs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveClusterArch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveClusterArch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
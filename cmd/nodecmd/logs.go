@@ -6,42 +6,233 @@ package nodecmd
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/luxfi/cli/pkg/node"
+	"github.com/luxfi/cli/pkg/nodelogs"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 	corev1 "k8s.io/api/core/v1"
 )
 
+// localLogsTarget selects the nodes of the local network
+const localLogsTarget = "local"
+
+// logColors are the ANSI colors the nodes are told apart by
+var logColors = []string{"36", "33", "35", "32", "34", "31", "96", "93", "95", "92"}
+
 var (
-	follow    bool
-	tailLines int64
+	follow      bool
+	tailLines   int64
+	logsChains  []string
+	logsFilter  string
+	logsSince   string
+	logsNoColor bool
 )
 
 func newLogsCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "logs [pod-name]",
-		Short: "Stream logs from a luxd pod",
-		Long: `Streams logs from a specific luxd pod in the StatefulSet.
+		Use:   "logs [local|clusterName|pod-name]",
+		Short: "Stream and merge the logs of luxd nodes",
+		Long: `Streams the luxd and chain logs of nodes.
+
+With 'local', the logs of every node of the local network are read from its
+run directory: the running network, or else the last one run. With the name
+of a cloud cluster, they are read from every node of the cluster over ssh.
+The lines of all nodes are merged in time order, prefixed with the node and
+log file and colored per node; with --follow they are shown as they come.
 
-If no pod name is given, defaults to luxd-0.
+--chain selects the logs of chains: c, p, x, main for luxd itself, or a
+deployed chain name. Every log is read by default.
+
+With --mainnet, --testnet, --devnet or --namespace the logs of a luxd pod
+of the StatefulSet are streamed instead, luxd-0 when no pod name is given.
 
 EXAMPLES:
+  lux node logs local --follow --chain mychain
+  lux node logs my-cluster --since 10m --filter 'ERROR|WARN'
+  lux node logs my-cluster --chain c --chain main -f
   lux node logs --mainnet
   lux node logs --mainnet luxd-2
   lux node logs --mainnet luxd-0 -f
   lux node logs --testnet --tail 100`,
-		Args: cobra.MaximumNArgs(1),
-		RunE: runLogs,
+		Args:         cobra.MaximumNArgs(1),
+		RunE:         runLogs,
+		SilenceUsage: true,
 	}
 
 	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "follow log output")
 	cmd.Flags().Int64Var(&tailLines, "tail", 200, "number of recent lines to show")
+	cmd.Flags().StringSliceVar(&logsChains, "chain", nil, "only show the logs of these chains: c, p, x, main or a chain name")
+	cmd.Flags().StringVar(&logsFilter, "filter", "", "only show the lines matching this regular expression")
+	cmd.Flags().StringVar(&logsSince, "since", "", "only show the lines since a duration ago, e.g. 10m, or an RFC 3339 time")
+	cmd.Flags().BoolVar(&logsNoColor, "no-color", false, "don't color the lines of each node")
 
 	return cmd
 }
 
-func runLogs(_ *cobra.Command, args []string) error {
+func runLogs(cmd *cobra.Command, args []string) error {
+	filter, err := logsRegexp()
+	if err != nil {
+		return err
+	}
+	since := time.Time{}
+	if logsSince != "" {
+		if since, err = nodelogs.ParseSince(logsSince, time.Now()); err != nil {
+			return err
+		}
+	}
+	if flagNamespace != "" || flagMainnet || flagTestnet || flagDevnet {
+		return runPodLogs(args, filter, since)
+	}
+	if len(args) == 0 {
+		return errors.New("specify 'local', a cluster name, or one of --mainnet, --testnet, --devnet or --namespace")
+	}
+
+	var (
+		network models.Network
+		sources []nodelogs.Source
+	)
+	if args[0] == localLogsTarget {
+		network = models.Local
+		if sources, err = localLogSources(); err != nil {
+			return err
+		}
+	} else {
+		var disconnect func()
+		if network, sources, disconnect, err = node.ClusterLogSources(app, args[0]); err != nil {
+			return err
+		}
+		defer disconnect()
+	}
+	files, err := chainLogFiles(network, logsChains)
+	if err != nil {
+		return err
+	}
+	opts := nodelogs.Options{
+		Files:  files,
+		Lines:  int(tailLines),
+		Follow: follow,
+		Since:  since,
+		Match:  filter,
+	}
+	// --since reaches back further than the default tail
+	if logsSince != "" && !cmd.Flags().Changed("tail") {
+		opts.Lines = 0
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	color := !logsNoColor && term.IsTerminal(int(os.Stdout.Fd()))
+	colors := map[string]string{}
+	width := 0
+	for i, s := range sources {
+		colors[s.Node] = logColors[i%len(logColors)]
+		width = max(width, len(s.Node))
+	}
+	return nodelogs.Stream(ctx, sources, opts, func(l nodelogs.Line) {
+		prefix := fmt.Sprintf("%-*s %s |", width, l.Node, strings.TrimSuffix(l.File, ".log"))
+		if color {
+			prefix = "\033[" + colors[l.Node] + "m" + prefix + "\033[0m"
+		}
+		fmt.Println(prefix, l.Text)
+	})
+}
+
+func logsRegexp() (*regexp.Regexp, error) {
+	if logsFilter == "" {
+		return nil, nil
+	}
+	filter, err := regexp.Compile(logsFilter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --filter: %w", err)
+	}
+	return filter, nil
+}
+
+// localLogSources returns the log sources of the nodes of the running local
+// network, or else of the last one run
+func localLogSources() ([]nodelogs.Source, error) {
+	runDir := ""
+	var latest time.Time
+	for _, networkType := range []string{"dev", "custom", "devnet", "testnet", "mainnet"} {
+		dir, err := utils.ReadDirLink(filepath.Join(app.GetRunDir(), networkType, "current"))
+		if err != nil {
+			continue
+		}
+		if app.IsNetworkTypeRunning(networkType) {
+			runDir = dir
+			break
+		}
+		if info, err := os.Stat(dir); err == nil && info.ModTime().After(latest) {
+			runDir, latest = dir, info.ModTime()
+		}
+	}
+	if runDir == "" {
+		return nil, errors.New("no local network run found, start one with 'lux network start'")
+	}
+	logDirs, err := filepath.Glob(filepath.Join(runDir, "node*", "logs"))
+	if err != nil {
+		return nil, err
+	}
+	if len(logDirs) == 0 {
+		return nil, fmt.Errorf("no node logs in %s", runDir)
+	}
+	sort.Strings(logDirs)
+	sources := make([]nodelogs.Source, 0, len(logDirs))
+	for _, dir := range logDirs {
+		sources = append(sources, nodelogs.Source{
+			Node: filepath.Base(filepath.Dir(dir)),
+			Tail: nodelogs.LocalTail(dir),
+		})
+	}
+	return sources, nil
+}
+
+// chainLogFiles returns the log files of chains on network, every log file
+// when chains is empty
+func chainLogFiles(network models.Network, chains []string) ([]string, error) {
+	files := make([]string, 0, len(chains))
+	for _, chain := range chains {
+		switch strings.ToLower(chain) {
+		case "main":
+			files = append(files, "main.log")
+			continue
+		case "c", "p", "x":
+			files = append(files, strings.ToUpper(chain)+".log")
+			continue
+		}
+		if !app.SidecarExists(chain) {
+			return nil, fmt.Errorf("unknown chain %q: use c, p, x, main or a chain name", chain)
+		}
+		sc, err := app.LoadSidecar(chain)
+		if err != nil {
+			return nil, err
+		}
+		blockchainID := sc.Networks[network.String()].BlockchainID
+		if blockchainID == ids.Empty {
+			return nil, fmt.Errorf("%s is not deployed to %s", chain, network.Name())
+		}
+		files = append(files, blockchainID.String()+".log")
+	}
+	return files, nil
+}
+
+// runPodLogs streams the logs of a luxd pod of the StatefulSet
+func runPodLogs(args []string, filter *regexp.Regexp, since time.Time) error {
 	namespace, err := resolveNamespace()
 	if err != nil {
 		return err
@@ -64,6 +255,10 @@ func runLogs(_ *cobra.Command, args []string) error {
 		Follow:    follow,
 		TailLines: &tailLines,
 	}
+	if !since.IsZero() {
+		seconds := int64(time.Since(since).Seconds()) + 1
+		opts.SinceSeconds = &seconds
+	}
 
 	req := client.CoreV1().Pods(namespace).GetLogs(podName, opts)
 	stream, err := req.Stream(ctx)
@@ -76,6 +271,9 @@ func runLogs(_ *cobra.Command, args []string) error {
 	// Increase buffer for long log lines
 	scanner.Buffer(make([]byte, 0, 64*1024), 256*1024)
 	for scanner.Scan() {
+		if filter != nil && !filter.MatchString(scanner.Text()) {
+			continue
+		}
 		fmt.Println(scanner.Text())
 	}
 	if err := scanner.Err(); err != nil && err != io.EOF {
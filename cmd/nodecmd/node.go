@@ -21,12 +21,24 @@ func NewCmd(injectedApp *application.Lux) *cobra.Command {
 
 LOCAL COMMANDS:
   link        Symlink a luxd binary to ~/.lux/bin/luxd
+  portal      Serve a web page with cluster status, RPC URLs and join steps
+  logs        Merge and follow the logs of the local network or a cloud cluster
+  watchdog    Supervise the local luxd of an L1 bootstrap validator
+  gpu         Show the GPU of each node and the chains it accelerates
+
+CLOUD CLUSTER COMMANDS:
+  dns         Register DNS records and https endpoints for the API nodes
+  access      Manage the CIDRs allowed to reach the nodes and sync security groups
+  config      Diff the chain configs of the nodes against the local ones and sync them
+  export-cluster  Bundle the inventories, node keys and metadata of a cluster in a file
+  import-cluster  Administer an exported cluster from this machine
+  identity    Back up the staking identity of nodes and restore it on a replacement host
 
 KUBERNETES COMMANDS (via Helm chart):
   deploy      Deploy/update luxd via Helm (single source of truth)
   upgrade     Rolling upgrade with zero downtime (partition-based)
   status      Show pod status, images, and health
-  logs        Stream logs from a luxd pod
+  logs        Stream logs from a luxd pod (with a network flag)
   rollback    Revert to previous StatefulSet revision
 
 The deploy command uses the canonical Helm chart at ~/work/lux/devops/charts/lux/
@@ -39,6 +51,26 @@ Use --context to target a specific kubeconfig context.
 EXAMPLES:
   # Local
   lux node link --auto
+  lux node portal my-cluster
+  lux node gpu my-cluster --output json
+
+  # Stable https RPC URLs for a cloud cluster
+  lux node dns my-cluster --domain example.com --acme-email ops@example.com
+
+  # Only let a partner network reach the RPC of a cloud cluster
+  lux node access add my-cluster --cidr 203.0.113.0/24 --port api
+
+  # Find and fix chain configs hand-edited on the nodes of a cloud cluster
+  lux node config diff my-cluster
+  lux node config sync my-cluster
+
+  # Move the administration of a cloud cluster to a new workstation
+  lux node export-cluster my-cluster --out my-cluster.tar --kms-key <keyId>
+  lux node import-cluster my-cluster.tar
+
+  # Keep the NodeIDs of a cloud cluster when a host is lost
+  lux node identity backup my-cluster --encrypt-with <keyId> --out identity.json
+  lux node identity restore my-cluster identity.json --host <cloudID> --node-id <NodeID>
 
   # Deploy via Helm (uses canonical chart + values-{network}.yaml)
   lux node deploy --mainnet
@@ -47,10 +79,15 @@ EXAMPLES:
   # Zero-downtime upgrade (partition-based, per-pod health checks)
   lux node upgrade --mainnet --image ghcr.io/luxfi/node:v1.23.6
 
+  # Rolling upgrade of a cloud cluster over ssh, two nodes at a time
+  lux node upgrade --rolling --cluster my-cluster --version v1.23.6 --max-unavailable 2
+
   # Check status
   lux node status --mainnet
 
   # Stream logs
+  lux node logs local --follow --chain mychain
+  lux node logs my-cluster --since 10m --filter ERROR
   lux node logs --mainnet luxd-0 -f
 
   # Rollback
@@ -60,6 +97,17 @@ EXAMPLES:
 
 	// Local commands
 	cmd.AddCommand(newLinkCmd())
+	cmd.AddCommand(newPortalCmd())
+	cmd.AddCommand(newWatchdogCmd())
+	cmd.AddCommand(newGPUCmd())
+
+	// Cloud cluster commands
+	cmd.AddCommand(newDNSCmd())
+	cmd.AddCommand(newAccessCmd())
+	cmd.AddCommand(newConfigCmd())
+	cmd.AddCommand(newExportClusterCmd())
+	cmd.AddCommand(newImportClusterCmd())
+	cmd.AddCommand(newIdentityCmd())
 
 	// K8s commands
 	deployCmdObj := newDeployCmd()
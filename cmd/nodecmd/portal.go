@@ -0,0 +1,146 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nodecmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/node"
+	"github.com/luxfi/cli/pkg/portal"
+	"github.com/luxfi/cli/pkg/status"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	portalPort    int
+	portalAddress string
+	portalRefresh time.Duration
+)
+
+func newPortalCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "portal <clusterName>",
+		Short: "Serve a local web page with cluster status and join instructions",
+		Long: `Serves a small web UI for a cluster, handy for demos and workshops:
+
+  - cluster and per-node status (health, version, peers, uptime)
+  - chain heights
+  - RPC URLs with QR codes for wallets and phones
+  - instructions to join the cluster as a validator
+
+The same data is available as JSON on /api/info.
+
+Bind to 0.0.0.0 with --address to share the page with others on the network.
+
+EXAMPLES:
+  lux node portal my-cluster
+  lux node portal my-cluster --address 0.0.0.0 --port 8080`,
+		Args:         cobrautils.ExactArgs(1),
+		RunE:         runPortal,
+		SilenceUsage: true,
+	}
+	cmd.Flags().IntVar(&portalPort, "port", 8090, "port to serve the portal on")
+	cmd.Flags().StringVar(&portalAddress, "address", "127.0.0.1", "address to listen on")
+	cmd.Flags().DurationVar(&portalRefresh, "refresh", 15*time.Second, "how often the page reloads (0 disables)")
+	return cmd
+}
+
+func runPortal(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	endpoints, err := node.GetClusterEndpoints(app, clusterName)
+	if err != nil {
+		return err
+	}
+	if !endpoints.Local && len(endpoints.Nodes) == 0 {
+		return fmt.Errorf("no nodes found in cluster %s", clusterName)
+	}
+
+	addr := net.JoinHostPort(portalAddress, strconv.Itoa(portalPort))
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           portal.NewHandler(portalSource(clusterName, endpoints), portalRefresh),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	ux.Logger.PrintToUser("Serving portal for cluster %s on http://%s", clusterName, addr)
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("portal server failed: %w", err)
+	case <-ctx.Done():
+		ux.Logger.PrintToUser("Shutting down portal...")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
+
+// portalSource probes the cluster nodes on every page load. Local clusters
+// are read from the local network status instead.
+func portalSource(clusterName string, endpoints *node.ClusterEndpoints) portal.Source {
+	service := status.NewStatusService()
+	urls := make([]string, 0, len(endpoints.Nodes))
+	for _, n := range endpoints.Nodes {
+		urls = append(urls, n.URL())
+	}
+	return func(ctx context.Context) (*portal.Info, error) {
+		if !endpoints.Local {
+			probed, err := service.ProbeEndpoints(ctx, clusterName, urls)
+			if err != nil {
+				return nil, err
+			}
+			return portal.NewInfo(clusterName, endpoints.Network, probed), nil
+		}
+		result, err := service.GetStatus(ctx)
+		if err != nil {
+			return nil, err
+		}
+		probed := localClusterNetwork(result, endpoints.Network)
+		if probed == nil {
+			return nil, status.ErrNoNetwork
+		}
+		return portal.NewInfo(clusterName, probed.Name, probed), nil
+	}
+}
+
+// localClusterNetwork picks the running local network of the cluster,
+// falling back to any running one
+func localClusterNetwork(result *status.StatusResult, network string) *status.Network {
+	var fallback *status.Network
+	for i := range result.Networks {
+		n := &result.Networks[i]
+		if n.Metadata.Status != "up" {
+			continue
+		}
+		if network != "" && strings.Contains(strings.ToLower(network), strings.ToLower(n.Name)) {
+			return n
+		}
+		if fallback == nil {
+			fallback = n
+		}
+	}
+	return fallback
+}
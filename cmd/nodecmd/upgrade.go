@@ -24,6 +24,13 @@ var (
 	healthTimeout time.Duration
 	dryRun        bool
 	forceUpgrade  bool
+
+	// --rolling upgrades of cloud clusters over ssh
+	rollingUpgrade bool
+	upgradeCluster string
+	upgradeVersion string
+	maxUnavailable int
+	patchOS        bool
 )
 
 func newUpgradeCmd() *cobra.Command {
@@ -45,26 +52,45 @@ PROCESS:
   5. After each pod restart: waits for readiness + stability period
   6. If any pod fails health check, stops and prints rollback command
 
+With --rolling, upgrades the hosts of a cloud cluster over ssh instead,
+--max-unavailable nodes at a time. Each node is drained (luxd stopped
+gracefully, leaving validation), optionally has its OS patched, is moved
+to the luxd --version and must be bootstrapped and healthy again before
+the next nodes are touched. Nodes already running the version are skipped,
+so a halted upgrade resumes where it stopped.
+
 EXAMPLES:
   lux node upgrade --mainnet --image ghcr.io/luxfi/node:v1.23.5
   lux node upgrade --testnet --image ghcr.io/luxfi/node:v1.23.5 --stability-wait 60s
-  lux node upgrade --devnet --image ghcr.io/luxfi/node:v1.23.5 --dry-run`,
+  lux node upgrade --devnet --image ghcr.io/luxfi/node:v1.23.5 --dry-run
+  lux node upgrade --rolling --cluster my-cluster --version v1.23.6 --max-unavailable 2
+  lux node upgrade --rolling --cluster my-cluster --version v1.23.6 --patch-os`,
 		RunE: runUpgrade,
 	}
 
-	cmd.Flags().StringVar(&upgradeImage, "image", "", "new container image (required)")
+	cmd.Flags().StringVar(&upgradeImage, "image", "", "new container image (required without --rolling)")
 	cmd.Flags().StringVar(&upgradeEvmVer, "evm-version", "", "EVM plugin version to update in init container")
 	cmd.Flags().DurationVar(&stabilityWait, "stability-wait", 30*time.Second, "wait time after pod ready before proceeding")
 	cmd.Flags().DurationVar(&healthTimeout, "health-timeout", 5*time.Minute, "max time to wait for a pod to become ready")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would happen without making changes")
 	cmd.Flags().BoolVar(&forceUpgrade, "force", false, "proceed even if image is the same")
-
-	_ = cmd.MarkFlagRequired("image")
+	cmd.Flags().BoolVar(&rollingUpgrade, "rolling", false, "upgrade the hosts of a cloud cluster over ssh")
+	cmd.Flags().StringVar(&upgradeCluster, "cluster", "", "cluster to upgrade with --rolling")
+	cmd.Flags().StringVar(&upgradeVersion, "version", "", "luxd version to upgrade to with --rolling, e.g. v1.23.6")
+	cmd.Flags().IntVar(&maxUnavailable, "max-unavailable", 1, "nodes that may be down at once with --rolling")
+	cmd.Flags().BoolVar(&patchOS, "patch-os", false, "apply OS updates to every node with --rolling, rebooting when needed")
 
 	return cmd
 }
 
 func runUpgrade(_ *cobra.Command, _ []string) error {
+	if rollingUpgrade {
+		return runRollingUpgrade()
+	}
+	if upgradeImage == "" {
+		return fmt.Errorf("required flag \"image\" not set")
+	}
+
 	namespace, err := resolveNamespace()
 	if err != nil {
 		return err
@@ -105,6 +131,21 @@ func runUpgrade(_ *cobra.Command, _ []string) error {
 	ux.Logger.PrintToUser("  Target image:  %s", upgradeImage)
 	ux.Logger.PrintToUser("  Stability:     %s per pod", stabilityWait)
 
+	// The EVM plugin is a native binary, its build must match the nodes
+	arch := ""
+	archs, archErr := podArchs(ctx, client, namespace, replicas)
+	if archErr == nil {
+		arch, archErr = resolveClusterArch(sts.Spec.Template.Spec.NodeSelector, archs)
+	}
+	switch {
+	case archErr != nil && upgradeEvmVer != "":
+		return fmt.Errorf("cannot select EVM plugin build: %w", archErr)
+	case archErr != nil:
+		ux.Logger.PrintToUser("  Warning:       %v", archErr)
+	default:
+		ux.Logger.PrintToUser("  Architecture:  %s", arch)
+	}
+
 	if currentImage == upgradeImage && !forceUpgrade {
 		ux.Logger.PrintToUser("\nImage is already %s — nothing to do (use --force to override)", upgradeImage)
 		return nil
@@ -132,7 +173,7 @@ func runUpgrade(_ *cobra.Command, _ []string) error {
 
 	// Step 3: Update EVM plugin version in init container if specified
 	if upgradeEvmVer != "" {
-		if err := patchEvmInitContainer(ctx, client, namespace, upgradeEvmVer); err != nil {
+		if err := patchEvmInitContainer(ctx, client, namespace, upgradeEvmVer, arch); err != nil {
 			ux.Logger.PrintToUser("Warning: failed to update EVM init container: %v", err)
 		}
 	}
@@ -230,14 +271,19 @@ func patchImage(ctx context.Context, client *kubernetes.Clientset, namespace, im
 	return err
 }
 
+// evmPluginURL returns the download URL of the EVM plugin release build for arch.
+func evmPluginURL(evmVersion, arch string) string {
+	return fmt.Sprintf("https://github.com/luxfi/evm/releases/download/%s/evm-plugin-linux-%s", evmVersion, arch)
+}
+
 // patchEvmInitContainer updates the EVM plugin download URL in the init container.
-func patchEvmInitContainer(ctx context.Context, client *kubernetes.Clientset, namespace, evmVersion string) error {
+func patchEvmInitContainer(ctx context.Context, client *kubernetes.Clientset, namespace, evmVersion, arch string) error {
 	sts, err := client.AppsV1().StatefulSets(namespace).Get(ctx, statefulSetName, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
 
-	pluginURL := fmt.Sprintf("https://github.com/luxfi/evm/releases/download/%s/evm-plugin-linux-amd64", evmVersion)
+	pluginURL := evmPluginURL(evmVersion, arch)
 	for i, ic := range sts.Spec.Template.Spec.InitContainers {
 		if ic.Name == "init-plugins" {
 			sts.Spec.Template.Spec.InitContainers[i].Args = []string{
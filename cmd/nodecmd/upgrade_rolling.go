@@ -0,0 +1,202 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nodecmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/luxfi/cli/pkg/ansible"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/node"
+	"github.com/luxfi/cli/pkg/noderollout"
+	"github.com/luxfi/cli/pkg/ssh"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/sdk/models"
+	"github.com/olekukonko/tablewriter"
+)
+
+const (
+	rollingPollInterval = 10 * time.Second
+	// patchOSScript applies the pending package updates non-interactively
+	patchOSScript      = "sudo apt-get update -q && sudo DEBIAN_FRONTEND=noninteractive apt-get -y -q -o Dpkg::Options::=--force-confold upgrade"
+	rebootRequiredFile = "/var/run/reboot-required"
+)
+
+// runRollingUpgrade upgrades luxd on the hosts of a cluster over ssh, a few
+// nodes at a time
+func runRollingUpgrade() error {
+	if upgradeCluster == "" {
+		return errors.New("--rolling needs the --cluster to upgrade")
+	}
+	if upgradeVersion == "" {
+		return errors.New("--rolling needs the luxd --version to upgrade to")
+	}
+	if err := node.CheckCluster(app, upgradeCluster); err != nil {
+		return err
+	}
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(upgradeCluster))
+	if err != nil {
+		return err
+	}
+	defer node.DisconnectHosts(hosts)
+	nodes := make([]noderollout.Node, len(hosts))
+	for i, host := range hosts {
+		nodes[i] = &clusterHost{host: host}
+	}
+	opts := noderollout.Options{
+		Version:        upgradeVersion,
+		MaxUnavailable: maxUnavailable,
+		PatchOS:        patchOS,
+		HealthTimeout:  healthTimeout,
+		PollInterval:   rollingPollInterval,
+		Progress: func(name, step string) {
+			ux.Logger.PrintToUser("  %s: %s", name, step)
+		},
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ux.Logger.PrintToUser("Rolling upgrade plan:")
+	ux.Logger.PrintToUser("  Cluster:         %s", upgradeCluster)
+	ux.Logger.PrintToUser("  Nodes:           %d", len(nodes))
+	ux.Logger.PrintToUser("  Target version:  %s", upgradeVersion)
+	ux.Logger.PrintToUser("  Max unavailable: %d", maxUnavailable)
+	ux.Logger.PrintToUser("  OS patching:     %t", patchOS)
+	if dryRun {
+		todo, done, err := noderollout.Plan(ctx, nodes, opts)
+		if err != nil {
+			return err
+		}
+		for _, r := range done {
+			ux.Logger.PrintToUser("  [dry-run] %s already runs %s", r.Node, r.Version)
+		}
+		for i, n := range todo {
+			ux.Logger.PrintToUser("  [dry-run] batch %d: drain %s → upgrade → wait bootstrapped and healthy", i/maxUnavailable+1, n.Name())
+		}
+		return nil
+	}
+
+	ux.Logger.PrintToUser("\nStarting rolling upgrade...")
+	results, err := noderollout.Rollout(ctx, nodes, opts)
+	printRollingResults(results)
+	recordRollingUpgrade(results, err)
+	if err != nil {
+		ux.Logger.PrintToUser("Fix the failing node(s) and run the upgrade again, upgraded nodes are skipped")
+		return err
+	}
+	ux.Logger.PrintToUser("\nUpgrade complete. All %d nodes of %s run %s", len(nodes), upgradeCluster, upgradeVersion)
+	return nil
+}
+
+func printRollingResults(results []noderollout.Result) {
+	if len(results) == 0 {
+		return
+	}
+	ux.Logger.PrintToUser("")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Node", "From", "Version", "Took")
+	for _, r := range results {
+		took := r.Duration.Round(time.Second).String()
+		if r.Skipped {
+			took = "already upgraded"
+		}
+		_ = table.Append([]string{r.Node, r.From, r.Version, took})
+	}
+	_ = table.Render()
+}
+
+// recordRollingUpgrade records the nodes the rollout upgraded, also when it
+// stopped at a failing node
+func recordRollingUpgrade(results []noderollout.Result, rolloutErr error) {
+	upgraded := []string{}
+	for _, r := range results {
+		if !r.Skipped {
+			upgraded = append(upgraded, r.Node)
+		}
+	}
+	if len(upgraded) == 0 {
+		return
+	}
+	result := map[string]string{"upgraded": strings.Join(upgraded, ",")}
+	if rolloutErr != nil {
+		result["error"] = rolloutErr.Error()
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "node upgrade",
+		Params: map[string]string{
+			"cluster":        upgradeCluster,
+			"version":        upgradeVersion,
+			"maxUnavailable": strconv.Itoa(maxUnavailable),
+			"patchOS":        strconv.FormatBool(patchOS),
+		},
+		Result: result,
+	})
+}
+
+// clusterHost is a cluster node reached over ssh, running luxd with docker
+// compose
+type clusterHost struct {
+	host *models.Host
+}
+
+func (h *clusterHost) Name() string {
+	return h.host.GetCloudID()
+}
+
+// Drain stops luxd, which shuts down gracefully on the stop signal
+func (h *clusterHost) Drain(context.Context) error {
+	return ssh.RunSSHStopNode(h.host)
+}
+
+// Patch upgrades the OS packages, rebooting when they require it
+func (h *clusterHost) Patch(context.Context) error {
+	if output, err := h.host.Command(patchOSScript, nil, constants.SSHLongRunningScriptTimeout); err != nil {
+		return fmt.Errorf("%w: %s", err, output)
+	}
+	rebootRequired, err := h.host.FileExists(rebootRequiredFile)
+	if err != nil || !rebootRequired {
+		return err
+	}
+	// the connection drops with the reboot
+	_, _ = h.host.Command("sudo systemctl reboot", nil, constants.SSHScriptTimeout)
+	_ = h.host.Disconnect()
+	h.host.Connection = nil
+	time.Sleep(constants.SSHSleepBetweenChecks)
+	return h.host.WaitForSSHShell(constants.SSHLongRunningScriptTimeout)
+}
+
+// Upgrade moves the compose service to version and restarts it
+func (h *clusterHost) Upgrade(_ context.Context, version string) error {
+	return ssh.RunSSHUpgradeLuxgo(h.host, version)
+}
+
+func (h *clusterHost) Status(context.Context) (noderollout.Status, error) {
+	var s noderollout.Status
+	notBootstrapped, err := node.GetNotBootstrappedNodes([]*models.Host{h.host})
+	if err != nil {
+		return s, err
+	}
+	s.Bootstrapped = len(notBootstrapped) == 0
+	unhealthy, err := node.GetUnhealthyNodes([]*models.Host{h.host})
+	if err != nil {
+		return s, err
+	}
+	s.Healthy = len(unhealthy) == 0
+	resp, err := ssh.RunSSHCheckLuxdVersion(h.host)
+	if err != nil {
+		return s, err
+	}
+	s.Version, err = node.ParseLuxdVersion(resp)
+	return s, err
+}
@@ -0,0 +1,351 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nodecmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/models"
+	"github.com/luxfi/cli/pkg/status"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/watchdog"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/ids"
+	"github.com/spf13/cobra"
+)
+
+const watchdogStopTimeout = time.Minute
+
+var (
+	watchdogNetwork    string
+	watchdogName       string
+	watchdogLuxdPath   string
+	watchdogLuxdArgs   string
+	watchdogHTTPPort   int
+	watchdogMinBalance uint64
+	watchdogInterval   time.Duration
+)
+
+func newWatchdogCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watchdog",
+		Short: "Supervise the local luxd of an L1 bootstrap validator",
+		Long: `When this machine is a bootstrap validator of a deployed L1, a watchdog
+runs its luxd and keeps it healthy:
+
+  - restarts luxd when it crashes, with exponential backoff
+  - restarts it with the validator set of the L1 in --track-chains, so the
+    blockchain is always tracked again
+  - alerts when the node stops being bootstrapped on the P-Chain or the L1,
+    or when the fee balance of its validator drops below --min-balance
+
+Watchdogs, their restarts and open alerts are shown by 'lux status' and
+'lux node watchdog status'. Alerts are also written to the watchdog log.
+
+EXAMPLES:
+  lux node watchdog start mychain --network testnet
+  lux node watchdog start mychain --network mainnet --min-balance 5000000000 \
+    --luxd-args "--data-dir=/data/luxd --public-ip=203.0.113.7"
+  lux node watchdog status
+  lux node watchdog stop mychain`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.AddCommand(newWatchdogStartCmd())
+	cmd.AddCommand(newWatchdogStopCmd())
+	cmd.AddCommand(newWatchdogStatusCmd())
+	cmd.AddCommand(newWatchdogRunCmd())
+	return cmd
+}
+
+func newWatchdogStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "start <chainName>",
+		Short:        "Start luxd under a watchdog for a deployed L1",
+		Args:         cobrautils.ExactArgs(1),
+		RunE:         runWatchdogStart,
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&watchdogNetwork, "network", "", "network the L1 is deployed to (mainnet, testnet, devnet or local)")
+	cmd.Flags().StringVar(&watchdogName, "name", "", "name of the watchdog (default: the chain name)")
+	cmd.Flags().StringVar(&watchdogLuxdPath, "luxd-path", "", "luxd binary (default: luxd in $PATH or ~/.lux/bin)")
+	cmd.Flags().StringVar(&watchdogLuxdArgs, "luxd-args", "", "extra flags for luxd, e.g. \"--data-dir=/data/luxd\"")
+	cmd.Flags().IntVar(&watchdogHTTPPort, "http-port", 9630, "HTTP port of the supervised luxd")
+	cmd.Flags().Uint64Var(&watchdogMinBalance, "min-balance", 1_000_000_000, "alert when the validator fee balance drops below this amount, in nLUX")
+	cmd.Flags().DurationVar(&watchdogInterval, "interval", watchdog.DefaultInterval, "time between two health checks")
+	return cmd
+}
+
+func newWatchdogStopCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "stop <name>",
+		Short:        "Stop a watchdog and the luxd it supervises",
+		Args:         cobrautils.ExactArgs(1),
+		RunE:         runWatchdogStop,
+		SilenceUsage: true,
+	}
+}
+
+func newWatchdogStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "status",
+		Short:        "Show the watchdogs of this machine and their alerts",
+		Args:         cobra.NoArgs,
+		RunE:         runWatchdogStatus,
+		SilenceUsage: true,
+	}
+}
+
+// newWatchdogRunCmd is the supervisor process spawned by start
+func newWatchdogRunCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "run <name>",
+		Short:        "Run a watchdog in the foreground",
+		Hidden:       true,
+		Args:         cobrautils.ExactArgs(1),
+		RunE:         runWatchdog,
+		SilenceUsage: true,
+	}
+}
+
+func runWatchdogStart(_ *cobra.Command, args []string) error {
+	chainName := args[0]
+	network, err := watchdogNetworkFromName(watchdogNetwork)
+	if err != nil {
+		return err
+	}
+	name := watchdogName
+	if name == "" {
+		name = chainName
+	}
+	dir := app.GetWatchdogDir()
+	if state, err := watchdog.LoadState(dir, name); err == nil && !state.Stopped && utils.IsProcessRunning(state.PID) {
+		return fmt.Errorf("watchdog %s is already running (pid %d)", name, state.PID)
+	}
+
+	sc, err := app.LoadSidecar(chainName)
+	if err != nil {
+		return err
+	}
+	data, ok := sc.Networks[network.String()]
+	if !ok || data.ChainID == ids.Empty {
+		return fmt.Errorf("chain %s is not deployed to %s", chainName, network.String())
+	}
+	validationIDs := map[string]string{}
+	for _, v := range data.BootstrapValidators {
+		if v.ValidationID != "" {
+			validationIDs[v.NodeID] = v.ValidationID
+		}
+	}
+	if len(validationIDs) == 0 {
+		ux.Logger.PrintToUser("No validation IDs recorded for the bootstrap validators of %s, the fee balance won't be monitored", chainName)
+	}
+
+	luxdPath, err := watchdogLuxdBinary(watchdogLuxdPath)
+	if err != nil {
+		return err
+	}
+	nodeURL := fmt.Sprintf("http://127.0.0.1:%d", watchdogHTTPPort)
+	pChainURL := network.Endpoint()
+	if network == models.Local {
+		pChainURL = nodeURL
+	}
+	luxdArgs := []string{
+		"--network-id=" + network.NetworkIDFlagValue(),
+		"--http-port=" + strconv.Itoa(watchdogHTTPPort),
+	}
+	cfg := watchdog.Config{
+		Name:          name,
+		Chain:         chainName,
+		Network:       network.String(),
+		LuxdPath:      luxdPath,
+		LuxdArgs:      append(luxdArgs, strings.Fields(watchdogLuxdArgs)...),
+		NodeURL:       nodeURL,
+		PChainURL:     pChainURL,
+		ChainID:       data.ChainID.String(),
+		BlockchainID:  data.BlockchainID.String(),
+		ValidationIDs: validationIDs,
+		MinBalance:    watchdogMinBalance,
+		Interval:      watchdogInterval,
+	}
+	if err := watchdog.SaveConfig(dir, cfg); err != nil {
+		return fmt.Errorf("failed to save the watchdog config: %w", err)
+	}
+
+	pid, err := spawnWatchdog(name)
+	if err != nil {
+		return err
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "node watchdog start",
+		Network:   network.String(),
+		Chain:     chainName,
+		Params:    map[string]string{"name": name, "luxd": luxdPath, "minBalance": strconv.FormatUint(watchdogMinBalance, 10)},
+	})
+	ux.Logger.GreenCheckmarkToUser("Watchdog %s started (pid %d), supervising luxd for %s on %s", name, pid, chainName, network.String())
+	ux.Logger.PrintToUser("Logs: %s", app.GetWatchdogLogPath(name))
+	ux.Logger.PrintToUser("Check it with 'lux status' or 'lux node watchdog status'")
+	return nil
+}
+
+// spawnWatchdog starts `lux node watchdog run <name>` detached from the
+// CLI, logging to the watchdog log
+func spawnWatchdog(name string) (int, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return 0, err
+	}
+	logFile, err := os.OpenFile(app.GetWatchdogLogPath(name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600) //nolint:gosec // G304: path is within the watchdog dir
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = logFile.Close() }()
+	cmd := exec.Command(exe, "node", "watchdog", "run", name) //nolint:gosec // G204: re-running this CLI
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	utils.DetachProcess(cmd)
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start the watchdog: %w", err)
+	}
+	pid := cmd.Process.Pid
+	// Release the process so it survives CLI exit
+	_ = cmd.Process.Release()
+	return pid, nil
+}
+
+func runWatchdogStop(_ *cobra.Command, args []string) error {
+	name := args[0]
+	dir := app.GetWatchdogDir()
+	state, err := watchdog.LoadState(dir, name)
+	if err != nil {
+		return err
+	}
+	if state.Stopped || !utils.IsProcessRunning(state.PID) {
+		ux.Logger.PrintToUser("Watchdog %s is not running", name)
+		return nil
+	}
+	proc, err := os.FindProcess(state.PID)
+	if err != nil {
+		return err
+	}
+	if err := utils.TerminateProcess(proc); err != nil {
+		return fmt.Errorf("failed to stop watchdog %s: %w", name, err)
+	}
+	// the watchdog stops luxd gracefully before exiting
+	deadline := time.Now().Add(watchdogStopTimeout)
+	for utils.IsProcessRunning(state.PID) {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("watchdog %s (pid %d) did not stop within %s", name, state.PID, watchdogStopTimeout)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "node watchdog stop",
+		Network:   state.Network,
+		Chain:     state.Chain,
+		Params:    map[string]string{"name": name},
+	})
+	ux.Logger.GreenCheckmarkToUser("Watchdog %s and its luxd stopped", name)
+	return nil
+}
+
+func runWatchdogStatus(_ *cobra.Command, _ []string) error {
+	states, err := app.LoadWatchdogStates()
+	if err != nil {
+		return err
+	}
+	if len(states) == 0 {
+		ux.Logger.PrintToUser("No watchdogs. Start one with 'lux node watchdog start <chainName> --network <network>'")
+		return nil
+	}
+	status.NewStatusFormatter(os.Stdout).FormatWatchdogs(&status.StatusResult{Watchdogs: states})
+	return nil
+}
+
+func runWatchdog(_ *cobra.Command, args []string) error {
+	name := args[0]
+	dir := app.GetWatchdogDir()
+	cfg, err := watchdog.LoadConfig(dir, name)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Printf("%s watchdog %s: supervising %s %s\n", time.Now().Format(time.RFC3339), name, cfg.LuxdPath, strings.Join(cfg.Args(), " "))
+	supervisor := &watchdog.Supervisor{
+		Config:    cfg,
+		StatePath: watchdog.StatePath(dir, name),
+		Start:     startLuxd(cfg.LuxdPath),
+		Prober:    watchdog.NewRPCProber(cfg.NodeURL, cfg.PChainURL),
+		Log:       os.Stdout,
+	}
+	return supervisor.Run(ctx)
+}
+
+// luxdProcess is a luxd started by a watchdog, logging to its log
+type luxdProcess struct {
+	cmd *exec.Cmd
+}
+
+func (p luxdProcess) PID() int    { return p.cmd.Process.Pid }
+func (p luxdProcess) Wait() error { return p.cmd.Wait() }
+func (p luxdProcess) Stop() error { return utils.InterruptProcess(p.cmd.Process) }
+
+func startLuxd(luxdPath string) watchdog.StartFunc {
+	return func(args []string) (watchdog.Process, error) {
+		cmd := exec.Command(luxdPath, args...) //nolint:gosec // G204: configured luxd binary
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Start(); err != nil {
+			return nil, err
+		}
+		return luxdProcess{cmd: cmd}, nil
+	}
+}
+
+// watchdogLuxdBinary returns luxdPath, or luxd from $PATH or the CLI bin
+// dir when unset
+func watchdogLuxdBinary(luxdPath string) (string, error) {
+	if luxdPath != "" {
+		if _, err := os.Stat(luxdPath); err != nil {
+			return "", fmt.Errorf("luxd not found at %s", luxdPath)
+		}
+		return luxdPath, nil
+	}
+	if p, err := exec.LookPath("luxd"); err == nil {
+		return p, nil
+	}
+	cliBin := utils.UserHomePath(constants.BaseDirName, constants.LuxCliBinDir, "luxd")
+	if _, err := os.Stat(cliBin); err == nil {
+		return cliBin, nil
+	}
+	return "", errors.New("luxd not found in $PATH or ~/.lux/bin, set --luxd-path or run 'lux node link'")
+}
+
+func watchdogNetworkFromName(name string) (models.Network, error) {
+	switch name {
+	case "mainnet":
+		return models.Mainnet, nil
+	case "testnet":
+		return models.Testnet, nil
+	case "devnet":
+		return models.Devnet, nil
+	case "local":
+		return models.Local, nil
+	case "":
+		return models.Undefined, errors.New("--network is required (mainnet, testnet, devnet or local)")
+	}
+	return models.Undefined, fmt.Errorf("unknown network %q, expected mainnet, testnet, devnet or local", name)
+}
@@ -8,12 +8,15 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strconv"
 	"time"
 
 	"github.com/luxfi/cli/cmd/networkcmd"
 	"github.com/luxfi/cli/pkg/application"
 	"github.com/luxfi/cli/pkg/chain"
 	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/feeestimate"
+	"github.com/luxfi/cli/pkg/history"
 	"github.com/luxfi/cli/pkg/keychain"
 	"github.com/luxfi/cli/pkg/networkoptions"
 	cliprompts "github.com/luxfi/cli/pkg/prompts"
@@ -183,8 +186,15 @@ func addValidator(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("illegal weight, must be greater than or equal to %d: %d", uint64(1000000000000), weight)
 	}
 
-	// Estimate fee based on network type and transaction complexity
-	fee := estimateAddValidatorFee(network)
+	// the keychain must hold the fee at the current fee state of the network
+	feeState, err := feeestimate.ForNetwork(network)
+	if err != nil {
+		return err
+	}
+	fee, err := feeState.PrimaryValidatorFee()
+	if err != nil {
+		return err
+	}
 	kc, err := keychain.GetKeychain(app, false, useLedger, ledgerAddresses, keyName, network, fee)
 	if err != nil {
 		return err
@@ -215,8 +225,23 @@ func addValidator(_ *cobra.Command, _ []string) error {
 	// For primary network, use AddValidator with empty chain ID
 	// AddValidator returns (bool, *txs.Tx, []string, error)
 	// The popBytes and recipientAddr are used for PoS validators, but primary network uses the simpler model
-	_, _, _, err = deployer.AddValidator(nil, nil, ids.Empty, nodeID, weight, start, duration)
-	return err
+	isFullySigned, _, _, err := deployer.AddValidator(nil, nil, ids.Empty, nodeID, weight, start, duration)
+	if err != nil || !isFullySigned {
+		return err
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "validator add",
+		Network:   network.String(),
+		Chain:     "primary",
+		Params: map[string]string{
+			"nodeID":        nodeID.String(),
+			"weight":        strconv.FormatUint(weight, 10),
+			"start":         start.UTC().Format(time.RFC3339),
+			"duration":      duration.String(),
+			"delegationFee": strconv.FormatUint(uint64(delegationFee), 10),
+		},
+	})
+	return nil
 }
 
 func getDelegationFeeOption(app *application.Lux, network models.Network) (uint32, error) {
@@ -253,20 +278,3 @@ func getDelegationFeeOption(app *application.Lux, network models.Network) (uint3
 	}
 	return defaultFee, nil
 }
-
-func estimateAddValidatorFee(network models.Network) uint64 {
-	const baseFee = 1_000_000 // 0.001 LUX base fee
-	switch network.Kind() {
-	case models.Mainnet:
-		if keyName == "" && !useLedger {
-			useLedger = true
-		}
-		return baseFee * 2 // Higher fee for mainnet
-	case models.Testnet:
-		return baseFee
-	case models.Local:
-		return 0 // No fee for local networks
-	default:
-		return baseFee
-	}
-}
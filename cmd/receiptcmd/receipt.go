@@ -0,0 +1,234 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package receiptcmd
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/config"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/receipt"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	app *application.Lux
+
+	verifyKMS  bool
+	exportFile string
+)
+
+// NewCmd creates the receipt command
+func NewCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "receipt",
+		Short: "List, verify and export the signed receipts of mainnet operations",
+		Long: `In audit mode ('lux config audit enable'), every mainnet operation
+recorded in the history (deploys, validator changes, upgrades, restores)
+issues a receipt: the command, its parameters, the resulting transaction
+and chain IDs, the addresses of the keys it signed with and its times.
+
+Receipts are signed with an ed25519 key of this machine
+(~/.lux/receipts/signing.key) and, when a KMS is configured, countersigned
+by a KMS key. They are stored in ~/.lux/receipts/<id>.json, for
+change-management and compliance records.
+
+EXAMPLES:
+
+  # Receipts issued on this machine
+  lux receipt list
+
+  # Check a receipt was not altered, and its KMS countersignature
+  lux receipt verify 20260301T100000Z-1a2b3c4d --kms
+
+  # Export every receipt for the change-management system
+  lux receipt export --output receipts.json`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newShowCmd())
+	cmd.AddCommand(newVerifyCmd())
+	cmd.AddCommand(newExportCmd())
+	return cmd
+}
+
+func newListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the receipts issued on this machine",
+		Args:  cobrautils.ExactArgs(0),
+		RunE:  listReceipts,
+	}
+}
+
+func listReceipts(_ *cobra.Command, _ []string) error {
+	receipts, err := receipt.List(app.GetReceiptsDir())
+	if err != nil {
+		return err
+	}
+	if len(receipts) == 0 {
+		if !app.AuditMode() {
+			ux.Logger.PrintToUser("No receipts: audit mode is off, see 'lux config audit'")
+			return nil
+		}
+		ux.Logger.PrintToUser("No receipts")
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("ID", "Issued", "Operation", "Chain", "Result", "Countersigned")
+	for _, r := range receipts {
+		countersigned := "no"
+		if r.Countersignature != nil {
+			countersigned = r.Countersignature.KeyID
+		}
+		_ = table.Append([]string{
+			r.ID,
+			r.IssuedAt.Local().Format(constants.TimeParseLayout),
+			r.Operation,
+			r.Chain,
+			history.FormatFields(r.TxIDs),
+			countersigned,
+		})
+	}
+	return table.Render()
+}
+
+func newShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <id | file>",
+		Short: "Print a receipt",
+		Args:  cobrautils.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			r, err := loadReceipt(args[0])
+			if err != nil {
+				return err
+			}
+			data, err := json.MarshalIndent(r, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		},
+	}
+}
+
+func newVerifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify <id | file>",
+		Short: "Check a receipt was not altered since it was signed",
+		Long: `Check the signature of a receipt, given by ID or as an exported file, and
+tell whether it was signed by this machine. With --kms, also have the KMS
+that countersigned it check its countersignature, authenticating with the
+API key in $` + receipt.KMSAPIKeyEnvVar + `.`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: verifyReceipt,
+	}
+	cmd.Flags().BoolVar(&verifyKMS, "kms", false, "also check the KMS countersignature")
+	return cmd
+}
+
+func verifyReceipt(_ *cobra.Command, args []string) error {
+	r, err := loadReceipt(args[0])
+	if err != nil {
+		return err
+	}
+	if err := r.Verify(); err != nil {
+		return err
+	}
+	signer := "another machine"
+	key, err := receipt.LoadSigningKey(filepath.Join(app.GetReceiptsDir(), receipt.SigningKeyFileName))
+	if err == nil && receipt.Fingerprint(key.Public().(ed25519.PublicKey)) == r.Signature.KeyFingerprint {
+		signer = "this machine"
+	}
+	ux.Logger.PrintToUser("Receipt %s is intact, signed by %s (%s)", r.ID, signer, r.Signature.KeyFingerprint)
+
+	switch {
+	case r.Countersignature == nil:
+		ux.Logger.PrintToUser("Not countersigned by a KMS")
+	case verifyKMS:
+		kms := receipt.NewKMSCountersigner(r.Countersignature.KMS, r.Countersignature.KeyID, os.Getenv(receipt.KMSAPIKeyEnvVar))
+		if err := kms.VerifyCountersignature(context.Background(), r); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Countersignature checked by the KMS at %s (key %s)", r.Countersignature.KMS, r.Countersignature.KeyID)
+	default:
+		ux.Logger.PrintToUser("Countersigned by the KMS at %s (key %s), check it with --kms",
+			r.Countersignature.KMS, r.Countersignature.KeyID)
+	}
+	return nil
+}
+
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export [id...]",
+		Short: "Export receipts as a JSON array",
+		Long: `Export the given receipts, or all of them, as a JSON array to --output or
+stdout.`,
+		Args: cobra.ArbitraryArgs,
+		RunE: exportReceipts,
+		// Exporting only writes the given file
+		Annotations: map[string]string{config.CapabilityAnnotation: config.CapabilityNone},
+	}
+	cmd.Flags().StringVarP(&exportFile, "output", "o", "-", "file to write the receipts to (- for stdout)")
+	return cmd
+}
+
+func exportReceipts(_ *cobra.Command, args []string) error {
+	receipts := []*receipt.Receipt{}
+	if len(args) == 0 {
+		all, err := receipt.List(app.GetReceiptsDir())
+		if err != nil {
+			return err
+		}
+		receipts = append(receipts, all...)
+	}
+	for _, arg := range args {
+		r, err := loadReceipt(arg)
+		if err != nil {
+			return err
+		}
+		receipts = append(receipts, r)
+	}
+	data, err := json.MarshalIndent(receipts, "", "  ")
+	if err != nil {
+		return err
+	}
+	if exportFile == "-" {
+		fmt.Println(string(data))
+		return nil
+	}
+	if err := os.WriteFile(exportFile, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Exported %d receipts to %s", len(receipts), exportFile)
+	return nil
+}
+
+// loadReceipt loads the receipt of an ID, or an exported receipt file
+func loadReceipt(idOrPath string) (*receipt.Receipt, error) {
+	if strings.HasSuffix(idOrPath, ".json") {
+		if _, err := os.Stat(idOrPath); err == nil {
+			return receipt.Load(idOrPath)
+		}
+	}
+	r, err := receipt.Load(filepath.Join(app.GetReceiptsDir(), idOrPath+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no receipt %s, see 'lux receipt list'", idOrPath)
+	}
+	return r, err
+}
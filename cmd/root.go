@@ -12,25 +12,38 @@ import (
 	"strings"
 	"time"
 
+	"github.com/luxfi/cli/cmd/addressbookcmd"
 	"github.com/luxfi/cli/cmd/ammcmd"
+	"github.com/luxfi/cli/cmd/assetcmd"
 	"github.com/luxfi/cli/cmd/configcmd"
 	"github.com/luxfi/log/level"
 
 	"github.com/luxfi/cli/cmd/backendcmd"
+	"github.com/luxfi/cli/cmd/backupcmd"
 	"github.com/luxfi/cli/cmd/chaincmd"
 	"github.com/luxfi/cli/cmd/contractcmd"
+	"github.com/luxfi/cli/cmd/daemoncmd"
+	"github.com/luxfi/cli/cmd/dashboardcmd"
 	"github.com/luxfi/cli/cmd/devcmd"
 	"github.com/luxfi/cli/cmd/explorecmd"
 	"github.com/luxfi/cli/cmd/dexcmd"
+	"github.com/luxfi/cli/cmd/discovercmd"
+	"github.com/luxfi/cli/cmd/feescmd"
 	"github.com/luxfi/cli/cmd/gpucmd"
+	"github.com/luxfi/cli/cmd/historycmd"
+	"github.com/luxfi/cli/cmd/homecmd"
 	"github.com/luxfi/cli/cmd/keycmd"
 	"github.com/luxfi/cli/cmd/kmscmd"
 	"github.com/luxfi/cli/cmd/linkcmd"
+	"github.com/luxfi/cli/cmd/lockcmd"
+	"github.com/luxfi/cli/cmd/metricscmd"
+	"github.com/luxfi/cli/cmd/migratecmd"
 	"github.com/luxfi/cli/cmd/mpccmd"
 	"github.com/luxfi/cli/cmd/netrunnercmd"
 	"github.com/luxfi/cli/cmd/networkcmd"
 	"github.com/luxfi/cli/cmd/nodecmd"
 	"github.com/luxfi/cli/cmd/primarycmd"
+	"github.com/luxfi/cli/cmd/receiptcmd"
 	"github.com/luxfi/cli/cmd/rpccmd"
 	aicli "github.com/luxfi/ai/cli"
 	fhecli "github.com/luxfi/fhe/cli"
@@ -38,6 +51,9 @@ import (
 	tuicli "github.com/luxfi/tui/cli"
 	"github.com/luxfi/cli/cmd/selfcmd"
 	"github.com/luxfi/cli/cmd/snapshotcmd"
+	"github.com/luxfi/cli/cmd/stakingcmd"
+	"github.com/luxfi/cli/cmd/statecmd"
+	"github.com/luxfi/cli/cmd/testcmd"
 	"github.com/luxfi/cli/cmd/updatecmd"
 	"github.com/luxfi/cli/cmd/validatorcmd"
 	"github.com/luxfi/cli/cmd/vmcmd"
@@ -46,7 +62,10 @@ import (
 	"github.com/luxfi/cli/internal/migrations"
 	"github.com/luxfi/cli/pkg/application"
 	"github.com/luxfi/cli/pkg/config"
+	"github.com/luxfi/cli/pkg/dependencies"
+	"github.com/luxfi/cli/pkg/home"
 	"github.com/luxfi/cli/pkg/lpmintegration"
+	"github.com/luxfi/cli/pkg/metrics"
 	"github.com/luxfi/cli/pkg/prompts"
 	"github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/cli/pkg/ux"
@@ -64,11 +83,15 @@ var (
 	logLevel       string
 	Version        = "1.22.5"
 	cfgFile        string
+	profileName    string
+	homeFlag       string
 	skipCheck      bool
 	nonInteractive bool
 	verboseFlag    bool
 	debugFlag      bool
 	quietFlag      bool
+	lockWait       time.Duration
+	progressFormat string
 )
 
 func NewRootCmd() *cobra.Command {
@@ -133,6 +156,10 @@ For detailed command help, use: lux <command> --help`,
 	rootCmd.CompletionOptions.HiddenDefaultCmd = true
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.lux/cli.json)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "",
+		"config profile to use for default network, key and endpoints (also LUX_PROFILE)")
+	rootCmd.PersistentFlags().StringVar(&homeFlag, "home", "",
+		"CLI home holding keys, chains, clusters and networks: a name from 'lux home list' or a directory (also LUX_HOME)")
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "ERROR", "log level for the application")
 	rootCmd.PersistentFlags().BoolVar(&skipCheck, constants.SkipUpdateFlag, false, "skip check for new versions")
 	rootCmd.PersistentFlags().BoolVar(&nonInteractive, "non-interactive", false,
@@ -140,6 +167,11 @@ For detailed command help, use: lux <command> --help`,
 	rootCmd.PersistentFlags().Bool("verbose", false, "Show verbose output (info level logs)")
 	rootCmd.PersistentFlags().Bool("debug", false, "Show debug output (debug level logs)")
 	rootCmd.PersistentFlags().Bool("quiet", false, "Show only errors (quiet mode)")
+	rootCmd.PersistentFlags().DurationVar(&lockWait, "wait", 0,
+		"wait up to this long for another running network, deploy or restore command to finish (10m if no duration given)")
+	rootCmd.PersistentFlags().Lookup("wait").NoOptDefVal = application.DefaultLockWait.String()
+	rootCmd.PersistentFlags().StringVar(&progressFormat, "progress", "text",
+		"progress output of long commands: text, or json to also stream progress events to stderr as NDJSON")
 
 	// add sub commands
 	rootCmd.AddCommand(devcmd.NewCmd(app))        // dev (local dev environment)
@@ -147,8 +179,17 @@ For detailed command help, use: lux <command> --help`,
 	rootCmd.AddCommand(networkcmd.NewCmd(app))    // network (local network management)
 	rootCmd.AddCommand(networkcmd.NewStatusCmd()) // status alias (new version)
 	rootCmd.AddCommand(snapshotcmd.NewCmd(app))   // snapshot (native incremental backups)
+	rootCmd.AddCommand(backupcmd.NewCmd(app))     // backup (keys, chains and cluster configs, to the KMS)
+	rootCmd.AddCommand(historycmd.NewCmd(app))    // history (changelog of state-changing operations)
+	rootCmd.AddCommand(receiptcmd.NewCmd(app))    // receipt (signed receipts of mainnet operations)
+	rootCmd.AddCommand(statecmd.NewCmd(app))      // state (remote state shared by a team)
+	rootCmd.AddCommand(lockcmd.NewCmd(app))       // lock (lock held by mutating commands)
+	rootCmd.AddCommand(homecmd.NewCmd(app))       // home (isolated CLI homes per project)
+	rootCmd.AddCommand(discovercmd.NewCmd(app))   // discover (networks running on this machine and the LAN)
+	rootCmd.AddCommand(dashboardcmd.NewCmd(app))  // dashboard (terminal UI of the nodes, validators, snapshots and relayer)
 	rootCmd.AddCommand(primarycmd.NewCmd(app))
 	rootCmd.AddCommand(chaincmd.NewCmd(app)) // unified chain command (l1/l2/l3)
+	rootCmd.AddCommand(chaincmd.NewIndexerCmd(app)) // indexer (chain indexer with query API)
 
 	// add transaction command
 
@@ -179,9 +220,24 @@ For detailed command help, use: lux <command> --help`,
 	// add validator command
 	rootCmd.AddCommand(validatorcmd.NewCmd(app))
 
+	// add staking command (reward estimates and stake end times)
+	rootCmd.AddCommand(stakingcmd.NewCmd(app))
+
+	// add fees command (P-Chain fee and L1 validator balance estimates)
+	rootCmd.AddCommand(feescmd.NewCmd(app))
+
+	// add metrics command (usage metrics opt-in and local report)
+	rootCmd.AddCommand(metricscmd.NewCmd(app))
+
+	// add asset command (X-Chain assets and NFTs)
+	rootCmd.AddCommand(assetcmd.NewCmd(app))
+
 	// add key management command
 	rootCmd.AddCommand(keycmd.NewCmd(app))
 
+	// add addressbook command (labeled addresses, referred to as @label)
+	rootCmd.AddCommand(addressbookcmd.NewCmd(app))
+
 	// add vm management command
 	rootCmd.AddCommand(vmcmd.NewCmd(app))
 
@@ -189,11 +245,18 @@ For detailed command help, use: lux <command> --help`,
 	rootCmd.AddCommand(nodecmd.NewCmd(app))
 
 	// add mpc management command (threshold signing wallets)
-	rootCmd.AddCommand(mpccmd.NewCmd())
+	rootCmd.AddCommand(mpccmd.NewCmd(app))
 
 	// add kms management command (key management service)
 	rootCmd.AddCommand(kmscmd.NewCmd())
 
+	// add migrate command (database engine migration)
+	rootCmd.AddCommand(migratecmd.NewCmd(app))
+
+	// add daemon command (gRPC admin API for IDEs and GUIs)
+	rootCmd.AddCommand(daemoncmd.NewCmd(app))
+	rootCmd.AddCommand(daemoncmd.NewServeCmd(app))
+
 	// add netrunner management command
 	rootCmd.AddCommand(netrunnercmd.NewCmd(app))
 
@@ -218,6 +281,9 @@ For detailed command help, use: lux <command> --help`,
 	// add rpc command for direct RPC calls
 	rootCmd.AddCommand(rpccmd.NewCmd(app))
 
+	// add test command (end-to-end smoke tests of chains)
+	rootCmd.AddCommand(testcmd.NewCmd(app))
+
 	// add hidden backend command (base)
 	rootCmd.AddCommand(backendcmd.NewCmd(app))
 
@@ -230,10 +296,11 @@ For detailed command help, use: lux <command> --help`,
 }
 
 func createApp(cmd *cobra.Command, _ []string) error {
-	baseDir, err := setupEnv()
+	h, err := setupEnv()
 	if err != nil {
 		return err
 	}
+	baseDir := h.Dir
 	log, err := setupLogging(baseDir)
 	if err != nil {
 		return err
@@ -280,10 +347,22 @@ func createApp(cmd *cobra.Command, _ []string) error {
 		_ = os.Setenv(prompts.EnvNonInteractive, "1")
 	}
 
+	// Progress events of deploys, snapshots and other long commands are
+	// published on the ux bus; --progress json streams them for GUIs and CI
+	switch progressFormat {
+	case "text":
+	case "json":
+		ux.Progress.Subscribe(ux.NewJSONProgressWriter(os.Stderr))
+	default:
+		return fmt.Errorf("invalid --progress %q, expected text or json", progressFormat)
+	}
+
 	// Interactive by default on TTY, non-interactive when:
 	// NON_INTERACTIVE=1, CI=1, --non-interactive flag, or stdin is piped
 	prompter := prompts.NewPrompterForMode(nonInteractive)
 	app.Setup(baseDir, log, cf, prompter, application.NewDownloader())
+	app.LockWait = lockWait
+	app.Home = h
 
 	// Setup LPM, skip if running a hidden command
 	if !cmd.Hidden {
@@ -300,6 +379,10 @@ func createApp(cmd *cobra.Command, _ []string) error {
 
 	initConfig()
 
+	if err := applyProfile(cmd); err != nil {
+		return err
+	}
+
 	if err := migrations.RunMigrations(app); err != nil {
 		return err
 	}
@@ -321,8 +404,8 @@ func createApp(cmd *cobra.Command, _ []string) error {
 // checkForUpdates evaluates first if the user is maybe wanting to skip the update check
 // if there's no skip, it runs the update check
 func checkForUpdates(cmd *cobra.Command, app *application.Lux) error {
-	// If skip-update-check is enabled (via flag or config), skip silently
-	if skipCheck {
+	// If skip-update-check is enabled (via flag or config), or offline, skip silently
+	if skipCheck || dependencies.Offline(app) {
 		return nil
 	}
 
@@ -367,25 +450,35 @@ func checkForUpdates(cmd *cobra.Command, app *application.Lux) error {
 }
 
 func handleTracking(cmd *cobra.Command, _ []string) {
-	utils.HandleTracking(cmd, app, nil)
+	app.Cmd = cmd
+	metrics.HandleTracking(app, nil, nil)
 }
 
-func setupEnv() (string, error) {
-	// Set base dir
-	usr, err := user.Current()
+func setupEnv() (home.Home, error) {
+	// Set base dir: --home, LUX_HOME, 'lux home switch', or ~/.lux
+	root, err := home.Root()
 	if err != nil {
 		// no logger here yet
-		fmt.Printf("unable to get system user %s\n", err)
-		return "", err
+		fmt.Printf("unable to get user home dir %s\n", err)
+		return home.Home{}, err
+	}
+	h, err := home.Select(root, homeFlag)
+	if err != nil {
+		fmt.Printf("failed selecting the CLI home: %s\n", err)
+		return home.Home{}, err
+	}
+	baseDir := h.Dir
+	// processes started by the CLI use the same home
+	if err := os.Setenv(home.EnvVar, baseDir); err != nil {
+		return home.Home{}, err
 	}
-	baseDir := filepath.Join(usr.HomeDir, constants.BaseDirName)
 
 	// Create base dir if it doesn't exist
 	err = os.MkdirAll(baseDir, 0o750)
 	if err != nil {
 		// no logger here yet
 		fmt.Printf("failed creating the basedir %s: %s\n", baseDir, err)
-		return "", err
+		return home.Home{}, err
 	}
 
 	// Create snapshots dir if it doesn't exist
@@ -429,7 +522,7 @@ func setupEnv() (string, error) {
 		os.Exit(1)
 	}
 
-	return baseDir, nil
+	return h, nil
 }
 
 func setupLogging(baseDir string) (luxlog.Logger, error) {
@@ -480,9 +573,9 @@ func initConfig() {
 		viper.SetConfigFile(cfgFile)
 	} else {
 		// Search for config in ~/.lux/ directory
-		home, err := os.UserHomeDir()
+		usrHome, err := os.UserHomeDir()
 		cobra.CheckErr(err)
-		luxDir := filepath.Join(home, constants.BaseDirName) // ~/.lux/
+		luxDir := filepath.Join(usrHome, constants.BaseDirName) // ~/.lux/
 		viper.AddConfigPath(luxDir)
 		viper.SetConfigType(constants.DefaultConfigFileType)
 		viper.SetConfigName(constants.DefaultConfigFileName) // cli.json
@@ -509,6 +602,49 @@ func initConfig() {
 	// No config file is normal - most users don't have one, so we silently continue
 }
 
+// applyProfile uses the selected config profile as defaults for the flags of
+// cmd. Priority: flags > profile > defaults. It also blocks cmd when the
+// read-only setting or the profile's capabilities don't allow it.
+func applyProfile(cmd *cobra.Command) error {
+	skip := false
+	for c := cmd; c != nil; c = c.Parent() {
+		if _, ok := c.Annotations[config.SkipProfileAnnotation]; ok {
+			skip = true
+		}
+	}
+	configPath := app.Conf.GetConfigPath()
+	if configPath == "" {
+		configPath = app.GetConfigPath()
+	}
+	profiles, err := config.LoadProfiles(configPath)
+	if err != nil {
+		if !skip {
+			return err
+		}
+		// Commands fixing the config, such as lint, must run on a broken one
+		app.Log.Warn("failed to load profiles", "error", err)
+		profiles = &config.Profiles{}
+	}
+	name, explicit := profiles.Resolve(profileName)
+	profile, ok := profiles.Profiles[name]
+	if name != "" && !ok {
+		if explicit && !skip {
+			return fmt.Errorf("profile %q not found, see 'lux config profile list'", name)
+		}
+		// A stale active profile must not block commands, including the
+		// ones needed to fix it
+		app.Log.Warn("active profile not found", "profile", name)
+	}
+	if err := config.CheckAccess(cmd, app.Conf.GetConfigBoolValue(config.ReadOnlyKey), name, profile); err != nil {
+		return err
+	}
+	if !ok || skip {
+		return nil
+	}
+	app.Log.Debug("using profile", "profile", name)
+	return config.ApplyFlagDefaults(cmd.Flags(), profile.FlagDefaults())
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -0,0 +1,42 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/luxfi/cli/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOnlyCommandsAllowed(t *testing.T) {
+	root := NewRootCmd()
+	readOnly := config.Profile{ReadOnly: true}
+	for _, path := range []string{
+		"network status",
+		"network metrics serve",
+		"staking estimate",
+		"staking schedule",
+		"warp message trace",
+		"warp relayer status",
+		"rpc call",
+		"rpc subscribe",
+		"key balance",
+		"chain list",
+		"chain txs",
+		"chain index start",
+	} {
+		cmd, _, err := root.Find(strings.Fields(path))
+		require.NoError(t, err, path)
+		require.Equal(t, path, strings.TrimPrefix(cmd.CommandPath(), "lux "))
+		require.NoError(t, config.CheckAccess(cmd, true, "", config.Profile{}), path)
+		require.NoError(t, config.CheckAccess(cmd, false, "dashboard", readOnly), path)
+	}
+	for _, path := range []string{"chain deploy", "network start", "network faucet send"} {
+		cmd, _, err := root.Find(strings.Fields(path))
+		require.NoError(t, err, path)
+		require.ErrorIs(t, config.CheckAccess(cmd, true, "", config.Profile{}), config.ErrNotAllowed, path)
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpccmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/balances"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/config"
+	"github.com/luxfi/cli/pkg/feeoracle"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/xchain"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// gweiDecimals is the number of decimals of gwei amounts in wei
+const gweiDecimals = 9
+
+type feeOracleFlags struct {
+	network string
+	chain   string
+	rpcURL  string
+	blocks  int
+	minTip  string
+	port    int
+	address string
+}
+
+func (f *feeOracleFlags) addTo(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.network, "network", "", "Network type: custom, devnet, testnet or mainnet (default: the running network)")
+	cmd.Flags().StringVar(&f.chain, "chain", "c", "Chain alias or deployed chain name")
+	cmd.Flags().StringVar(&f.rpcURL, "rpc-url", "", "RPC URL of the chain instead of its endpoint on the network")
+	cmd.Flags().IntVar(&f.blocks, "blocks", feeoracle.DefaultBlocks, "How many recent blocks to suggest fees from")
+	cmd.Flags().StringVar(&f.minTip, "min-tip", "0", "Smallest priority fee suggested, in gwei")
+}
+
+// oracle returns the fee oracle of the chain of the flags
+func (f *feeOracleFlags) oracle(app *application.Lux) (*feeoracle.Oracle, error) {
+	minTip, err := xchain.ParseAmount(f.minTip, gweiDecimals)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --min-tip: %w", err)
+	}
+	if f.blocks < 1 || f.blocks > 1024 {
+		return nil, errors.New("--blocks must be between 1 and 1024")
+	}
+	url := f.rpcURL
+	if url == "" {
+		networkType := f.network
+		if networkType == "" {
+			if networkType = app.GetRunningNetworkType(); networkType == "" {
+				networkType = "custom"
+			}
+		}
+		if url, err = chainRPCURL(app, networkType, f.chain); err != nil {
+			return nil, err
+		}
+	}
+	return &feeoracle.Oracle{
+		URL:            url,
+		Client:         &http.Client{Timeout: 10 * time.Second},
+		Blocks:         f.blocks,
+		MinPriorityFee: new(big.Int).SetUint64(minTip),
+	}, nil
+}
+
+func newSuggestFeesCmd(app *application.Lux) *cobra.Command {
+	flags := &feeOracleFlags{}
+	cmd := &cobra.Command{
+		Use:   "suggest-fees",
+		Short: "Suggest EIP-1559 fees for an EVM chain",
+		Long: `Suggest the max priority fee and max fee per gas of slow, standard and fast
+transactions on an EVM chain, from the eth_feeHistory of its recent blocks.
+
+The tip of each speed is the median over the recent non-empty blocks of the
+10th, 50th and 90th percentile tip. Empty blocks, the norm on a fresh chain,
+are ignored rather than pulling the tips to zero; with none left the tip is
+--min-tip. The max fee covers a doubling of the next base fee.
+
+Example:
+  lux rpc suggest-fees --chain mychain
+  lux rpc suggest-fees --network testnet --chain c --min-tip 1
+`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			oracle, err := flags.oracle(app)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			defer cancel()
+			s, err := oracle.Suggest(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get the fee history of %s: %w", oracle.URL, err)
+			}
+			ux.Logger.PrintToUser("Fees on %s after block %d, %.0f%% of the gas of the last %d blocks used",
+				flags.chain, s.Block, s.Utilization*100, flags.blocks)
+			ux.Logger.PrintToUser("Next base fee: %s gwei", balances.Format(s.BaseFee, gweiDecimals))
+			table := tablewriter.NewWriter(os.Stdout)
+			table.Header("Speed", "Max Priority Fee (gwei)", "Max Fee (gwei)")
+			for _, t := range s.Tiers {
+				_ = table.Append([]string{
+					t.Speed,
+					balances.Format(t.MaxPriorityFeePerGas, gweiDecimals),
+					balances.Format(t.MaxFeePerGas, gweiDecimals),
+				})
+			}
+			return table.Render()
+		},
+		Annotations: map[string]string{config.CapabilityAnnotation: config.CapabilityNone},
+	}
+	flags.addTo(cmd)
+	return cmd
+}
+
+func newFeeOracleCmd(app *application.Lux) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "feeoracle",
+		Short: "Serve fee suggestions for an EVM chain",
+		Long: `Serve the fee suggestions of 'lux rpc suggest-fees' over HTTP, for wallets
+pointing at a chain whose fee market confuses their estimator.`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.AddCommand(newFeeOracleStartCmd(app))
+	return cmd
+}
+
+func newFeeOracleStartCmd(app *application.Lux) *cobra.Command {
+	flags := &feeOracleFlags{}
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Serve fee suggestions over HTTP",
+		Long: `Serve the fee suggestions of a chain over HTTP in the foreground:
+
+  GET  /fees  the base fee and the fees of slow, standard and fast txs
+  POST /      JSON-RPC eth_maxPriorityFeePerGas, eth_gasPrice and
+              lux_suggestFees
+
+Fees are in wei, as hex quantities, and refreshed at most every 2s.
+
+Example:
+  lux rpc feeoracle start --chain mychain
+  curl localhost:9660/fees
+`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			oracle, err := flags.oracle(app)
+			if err != nil {
+				return err
+			}
+			return serveFeeOracle(oracle, net.JoinHostPort(flags.address, strconv.Itoa(flags.port)), flags.chain)
+		},
+		Annotations: map[string]string{config.CapabilityAnnotation: config.CapabilityNone},
+	}
+	flags.addTo(cmd)
+	cmd.Flags().IntVar(&flags.port, "port", 9660, "Port to serve the fee oracle on")
+	cmd.Flags().StringVar(&flags.address, "address", "127.0.0.1", "Address to listen on")
+	return cmd
+}
+
+func serveFeeOracle(oracle *feeoracle.Oracle, addr, chain string) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           oracle,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	ux.Logger.PrintToUser("Serving fee suggestions for %s (%s) on http://%s", chain, oracle.URL, addr)
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("fee oracle failed: %w", err)
+	case <-ctx.Done():
+		ux.Logger.PrintToUser("Shutting down fee oracle...")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
+
+// chainRPCURL returns the RPC endpoint of chain on the network of
+// networkType, served by the running node when there is one
+func chainRPCURL(app *application.Lux, networkType, chain string) (string, error) {
+	network := sdkNetwork(networkType)
+	baseURL := network.Endpoint()
+	if state, err := app.LoadNetworkStateForType(networkType); err == nil && state != nil && state.Running && state.APIEndpoint != "" {
+		baseURL = state.APIEndpoint
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	switch chain {
+	case "c", "a", "b", "d", "g", "k", "q", "t", "z":
+		return models.GetRPCEndpoint(baseURL, strings.ToUpper(chain)), nil
+	}
+	if !app.SidecarExists(chain) {
+		return "", fmt.Errorf("unknown chain %q: use a primary chain alias or a deployed chain name", chain)
+	}
+	sc, err := app.LoadSidecar(chain)
+	if err != nil {
+		return "", err
+	}
+	deployment := sc.Networks[network.Name()]
+	if len(deployment.RPCEndpoints) > 0 {
+		return deployment.RPCEndpoints[0], nil
+	}
+	if deployment.BlockchainID == ids.Empty {
+		return "", fmt.Errorf("%s is not deployed to %s", chain, network.Name())
+	}
+	return models.GetRPCEndpoint(baseURL, deployment.BlockchainID.String()), nil
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/config"
 	"github.com/spf13/cobra"
 )
 
@@ -34,12 +35,22 @@ Examples:
   lux rpc call --method platform.createBlockchain \
     --params '{"vmID":"...", "name":"mychain", "genesis":"..."}' \
     --endpoint http://localhost:9630/ext/bc/P
+
+  # Check the C-Chain WebSocket endpoint with a newHeads subscription
+  lux rpc subscribe --chain c --topic newHeads
+
+  # Suggest fees for an L1, or serve them to wallets
+  lux rpc suggest-fees --chain mychain
+  lux rpc feeoracle start --chain mychain
 `,
 		RunE: nil,
 	}
 
 	cmd.AddCommand(newCallCmd())
 	cmd.AddCommand(newTransferCmd(app))
+	cmd.AddCommand(newSubscribeCmd(app))
+	cmd.AddCommand(newSuggestFeesCmd(app))
+	cmd.AddCommand(newFeeOracleCmd(app))
 	return cmd
 }
 
@@ -120,6 +131,7 @@ func newCallCmd() *cobra.Command {
 			fmt.Println(string(prettyJSON))
 			return nil
 		},
+		Annotations: map[string]string{config.CapabilityAnnotation: config.CapabilityNone},
 	}
 
 	cmd.Flags().StringVar(&method, "method", "", "RPC method to call (required)")
@@ -0,0 +1,184 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpccmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/config"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/wsprobe"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+	"github.com/spf13/cobra"
+)
+
+type subscribeFlags struct {
+	network   string
+	chain     string
+	topic     string
+	wsURL     string
+	addresses []string
+	duration  time.Duration
+	interval  time.Duration
+}
+
+func newSubscribeCmd(app *application.Lux) *cobra.Command {
+	flags := &subscribeFlags{}
+	cmd := &cobra.Command{
+		Use:   "subscribe",
+		Short: "Check a chain's WebSocket endpoint with a live subscription",
+		Long: `Subscribe to a chain's WebSocket endpoint and hold the subscription open,
+measuring how long events take to arrive after their block was produced and
+how many of the events the chain produced were never delivered.
+
+The chain is a primary network EVM chain alias (c, a, b, d, ...) or the name
+of a chain deployed to the network. The result is recorded and shown by
+'lux status', and the command fails when the subscription broke or missed
+events.
+
+Topics:
+  newHeads  new block headers
+  logs      contract logs, optionally of --address only
+
+Example:
+  lux rpc subscribe --chain c --topic newHeads --duration 5m
+  lux rpc subscribe --network testnet --chain mychain --topic logs --address 0x0200000000000000000000000000000000000005
+`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runSubscribe(app, flags)
+		},
+		Annotations: map[string]string{config.CapabilityAnnotation: config.CapabilityNone},
+	}
+
+	cmd.Flags().StringVar(&flags.network, "network", "", "Network type: custom, devnet, testnet or mainnet (default: the running network)")
+	cmd.Flags().StringVar(&flags.chain, "chain", "c", "Chain alias or deployed chain name")
+	cmd.Flags().StringVar(&flags.topic, "topic", wsprobe.TopicNewHeads, "Subscription topic: "+strings.Join(wsprobe.Topics, " or "))
+	cmd.Flags().StringVar(&flags.wsURL, "ws-url", "", "WebSocket URL to check instead of the chain's endpoint")
+	cmd.Flags().StringSliceVar(&flags.addresses, "address", nil, "Contract address to restrict a logs subscription to (repeatable)")
+	cmd.Flags().DurationVar(&flags.duration, "duration", time.Minute, "How long to hold the subscription open")
+	cmd.Flags().DurationVar(&flags.interval, "interval", 10*time.Second, "How often to report progress, 0 to disable")
+
+	return cmd
+}
+
+func runSubscribe(app *application.Lux, flags *subscribeFlags) error {
+	networkType := flags.network
+	if networkType == "" {
+		if networkType = app.GetRunningNetworkType(); networkType == "" {
+			networkType = "custom"
+		}
+	}
+	var addresses []common.Address
+	for _, addr := range flags.addresses {
+		if !common.IsHexAddress(addr) {
+			return fmt.Errorf("invalid address %q", addr)
+		}
+		addresses = append(addresses, common.HexToAddress(addr))
+	}
+	url := flags.wsURL
+	if url == "" {
+		var err error
+		if url, err = chainWSURL(app, networkType, flags.chain); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	dialCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	client, err := wsprobe.Dial(dialCtx, url)
+	cancel()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ux.Logger.PrintToUser("Subscribing to %s on %s for %s (Ctrl+C to stop early)", flags.topic, url, flags.duration)
+	result, err := wsprobe.Probe(ctx, client, wsprobe.Options{
+		Topic:     flags.topic,
+		Duration:  flags.duration,
+		Addresses: addresses,
+		Interval:  flags.interval,
+		Progress: func(r wsprobe.Result) {
+			ux.Logger.PrintToUser("  %s: %d events, latency avg %s max %s",
+				r.Duration.Round(time.Second), r.Events, r.LatencyAvg.Round(time.Millisecond), r.LatencyMax.Round(time.Millisecond))
+		},
+	})
+	if err != nil {
+		return err
+	}
+	result.Network = networkType
+	result.Chain = flags.chain
+	result.URL = url
+	if err := wsprobe.Save(filepath.Join(app.GetBaseDir(), wsprobe.ResultsFile), result); err != nil {
+		return fmt.Errorf("failed to record the result: %w", err)
+	}
+
+	ux.Logger.PrintToUser("Subscription held %s, subscribed in %s", result.Duration.Round(time.Second), result.SubscribeLatency.Round(time.Millisecond))
+	ux.Logger.PrintToUser("  events:  %d delivered, %d missed (%.1f%% dropped)", result.Events, result.Missed, result.DropRate()*100)
+	ux.Logger.PrintToUser("  latency: avg %s, max %s", result.LatencyAvg.Round(time.Millisecond), result.LatencyMax.Round(time.Millisecond))
+	switch {
+	case result.Error != "":
+		return fmt.Errorf("subscription to %s failed: %s", url, result.Error)
+	case result.Missed > 0:
+		return fmt.Errorf("subscription to %s missed %d of %d events", url, result.Missed, result.Events+result.Missed)
+	}
+	ux.Logger.PrintToUser("✓ WebSocket subscription healthy")
+	return nil
+}
+
+// chainWSURL returns the WebSocket endpoint of chain on the network of
+// networkType, served by the running node when there is one
+func chainWSURL(app *application.Lux, networkType, chain string) (string, error) {
+	network := sdkNetwork(networkType)
+	baseURL := network.Endpoint()
+	if state, err := app.LoadNetworkStateForType(networkType); err == nil && state != nil && state.Running && state.APIEndpoint != "" {
+		baseURL = state.APIEndpoint
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	switch chain {
+	case "c", "a", "b", "d", "g", "k", "q", "t", "z":
+		return wsprobe.WSURL(models.GetWSEndpoint(baseURL, strings.ToUpper(chain))), nil
+	}
+	if !app.SidecarExists(chain) {
+		return "", fmt.Errorf("unknown chain %q: use a primary chain alias or a deployed chain name", chain)
+	}
+	sc, err := app.LoadSidecar(chain)
+	if err != nil {
+		return "", err
+	}
+	deployment := sc.Networks[network.Name()]
+	if len(deployment.WSEndpoints) > 0 {
+		return deployment.WSEndpoints[0], nil
+	}
+	if deployment.BlockchainID == ids.Empty {
+		return "", fmt.Errorf("%s is not deployed to %s", chain, network.Name())
+	}
+	return wsprobe.WSURL(models.GetWSEndpoint(baseURL, deployment.BlockchainID.String())), nil
+}
+
+// sdkNetwork maps a network state type to its network
+func sdkNetwork(networkType string) models.Network {
+	switch networkType {
+	case "mainnet":
+		return models.Mainnet
+	case "testnet":
+		return models.Testnet
+	case "devnet":
+		return models.Devnet
+	default:
+		return models.Local
+	}
+}
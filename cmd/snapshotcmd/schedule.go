@@ -0,0 +1,401 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshotcmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/hooks"
+	"github.com/luxfi/cli/pkg/snapshot"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/spf13/cobra"
+)
+
+// schedulerRunFile records the snapshot scheduler running
+type schedulerRunFile struct {
+	Pid       int       `json:"pid"`
+	StartedAt time.Time `json:"startedAt"`
+}
+
+func newScheduleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Take snapshots automatically on cron schedules",
+		Long: `The schedule command takes snapshots at the times of cron expressions, without
+an external cron setup.
+
+Schedules are run by the snapshot scheduler, a background lux process started
+when a schedule is added and stopped when the last one is removed. It records
+the last success and failure of every schedule, shown by 'lux snapshot list'
+and 'lux snapshot schedule list'.
+
+Cron expressions have five fields: minute, hour, day of month, month and day
+of week, with lists (1,15), ranges (1-5) and steps (*/6). The @hourly, @daily,
+@weekly, @monthly and @yearly shorthands are accepted too. Times are local.
+
+EXAMPLES:
+
+  # Incremental snapshot of the running network every 6 hours
+  lux snapshot schedule add "0 */6 * * *" --incremental
+
+  # Full mainnet snapshot every night at 2:30
+  lux snapshot schedule add "30 2 * * *" --mainnet
+
+  lux snapshot schedule list
+  lux snapshot schedule remove 1
+
+  # Run the scheduler in the foreground, e.g. as a systemd service
+  lux snapshot schedule run`,
+	}
+	cmd.AddCommand(newScheduleAddCmd())
+	cmd.AddCommand(newScheduleListCmd())
+	cmd.AddCommand(newScheduleRemoveCmd())
+	cmd.AddCommand(newScheduleStartCmd())
+	cmd.AddCommand(newScheduleStopCmd())
+	cmd.AddCommand(newScheduleRunCmd())
+	return cmd
+}
+
+func newScheduleAddCmd() *cobra.Command {
+	var incremental, noStart bool
+	cmd := &cobra.Command{
+		Use:   "add [cron]",
+		Short: "Schedule snapshots at the times of a cron expression",
+		Long: `Schedule snapshots at the times of a cron expression, and start the snapshot
+scheduler if it is not running.
+
+Snapshots are full unless --incremental is given, and are named
+<network>-<date>-<hour><minute>. Without a network flag, the network running
+when the schedule falls due is snapshotted; runs fail when none, or several,
+are running.
+
+EXAMPLES:
+
+  lux snapshot schedule add "0 */6 * * *" --incremental
+  lux snapshot schedule add @daily --testnet`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			sm := snapshot.NewSnapshotManager(app.GetBaseDir())
+			s, err := sm.AddSchedule(args[0], flagNetwork(), incremental)
+			if err != nil {
+				return err
+			}
+			ux.Logger.GreenCheckmarkToUser("Snapshot schedule %s added, next run at %s", s.ID, formatScheduleTime(s.Next(time.Now())))
+			if noStart {
+				return nil
+			}
+			if pid, running := schedulerPID(); running {
+				ux.Logger.PrintToUser("The snapshot scheduler (PID %d) picks it up.", pid)
+				return nil
+			}
+			return startScheduler()
+		},
+	}
+	cmd.Flags().BoolVar(&incremental, "incremental", false, "take incremental snapshots instead of full ones")
+	cmd.Flags().BoolVar(&noStart, "no-start", false, "do not start the snapshot scheduler")
+	cmd.Flags().BoolVar(&snapshotMainnet, "mainnet", false, "snapshot mainnet network")
+	cmd.Flags().BoolVar(&snapshotTestnet, "testnet", false, "snapshot testnet network")
+	cmd.Flags().BoolVar(&snapshotDevnet, "devnet", false, "snapshot devnet network")
+	return cmd
+}
+
+func newScheduleListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List snapshot schedules and how their last runs went",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			sm := snapshot.NewSnapshotManager(app.GetBaseDir())
+			schedules, err := sm.Schedules()
+			if err != nil {
+				return err
+			}
+			if len(schedules) == 0 {
+				ux.Logger.PrintToUser("No snapshot schedules.")
+				ux.Logger.PrintToUser("Add one with: lux snapshot schedule add \"0 */6 * * *\" --incremental")
+				return nil
+			}
+			printSchedules(schedules)
+			return nil
+		},
+	}
+}
+
+func newScheduleRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove [id]",
+		Short: "Remove a snapshot schedule",
+		Long: `Remove a snapshot schedule. The snapshot scheduler is stopped when no
+schedule is left.
+
+EXAMPLES:
+
+  lux snapshot schedule remove 1`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			sm := snapshot.NewSnapshotManager(app.GetBaseDir())
+			if err := sm.RemoveSchedule(args[0]); err != nil {
+				return err
+			}
+			ux.Logger.GreenCheckmarkToUser("Snapshot schedule %s removed", args[0])
+			schedules, err := sm.Schedules()
+			if err != nil || len(schedules) > 0 {
+				return err
+			}
+			if _, running := schedulerPID(); running {
+				return stopScheduler()
+			}
+			return nil
+		},
+	}
+}
+
+func newScheduleStartCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "start",
+		Short: "Start the snapshot scheduler in the background",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if pid, running := schedulerPID(); running {
+				ux.Logger.PrintToUser("The snapshot scheduler is already running (PID %d).", pid)
+				return nil
+			}
+			return startScheduler()
+		},
+	}
+}
+
+func newScheduleStopCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the snapshot scheduler",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if _, running := schedulerPID(); !running {
+				ux.Logger.PrintToUser("The snapshot scheduler is not running.")
+				return nil
+			}
+			return stopScheduler()
+		},
+	}
+}
+
+func newScheduleRunCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "run",
+		Short: "Run the snapshot scheduler in the foreground",
+		Long: `Run the snapshot scheduler in the foreground until interrupted, taking the
+snapshots of the schedules as they fall due. 'lux snapshot schedule start'
+runs it in the background; run it directly to supervise it with systemd or
+launchd.
+
+Schedules are reread every minute, so adding or removing them needs no
+restart. A schedule falling due while another snapshot is being taken is
+skipped until its next time.`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runScheduler()
+		},
+	}
+}
+
+// schedulerPID returns the process of the snapshot scheduler, and whether
+// it is running
+func schedulerPID() (int, bool) {
+	runBytes, err := os.ReadFile(app.GetSnapshotSchedulerRunPath())
+	if err != nil {
+		return 0, false
+	}
+	var rf schedulerRunFile
+	if json.Unmarshal(runBytes, &rf) != nil || rf.Pid == 0 {
+		return 0, false
+	}
+	return rf.Pid, utils.IsProcessRunning(rf.Pid)
+}
+
+// startScheduler runs 'lux snapshot schedule run' in the background,
+// logging to the scheduler log
+func startScheduler() error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(app.GetRunDir(), 0o750); err != nil {
+		return err
+	}
+	logPath := app.GetSnapshotSchedulerLogPath()
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600) //nolint:gosec // G304: in the app's run directory
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+	cmd := exec.Command(self, "snapshot", "schedule", "run", "--"+constants.SkipUpdateFlag) //nolint:gosec // G204: running this CLI
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	utils.DetachProcess(cmd)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start the snapshot scheduler: %w", err)
+	}
+	pid := cmd.Process.Pid
+	_ = cmd.Process.Release()
+	ux.Logger.GreenCheckmarkToUser("Snapshot scheduler started (PID %d), logging to %s", pid, logPath)
+	return nil
+}
+
+func stopScheduler() error {
+	pid, _ := schedulerPID()
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	if err := utils.TerminateProcess(proc); err != nil {
+		return fmt.Errorf("failed to stop the snapshot scheduler (PID %d): %w", pid, err)
+	}
+	ux.Logger.GreenCheckmarkToUser("Snapshot scheduler (PID %d) stopped", pid)
+	return nil
+}
+
+func runScheduler() error {
+	if pid, running := schedulerPID(); running && pid != os.Getpid() {
+		return fmt.Errorf("the snapshot scheduler is already running (PID %d)", pid)
+	}
+	runBytes, err := json.Marshal(schedulerRunFile{Pid: os.Getpid(), StartedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(app.GetRunDir(), 0o750); err != nil {
+		return err
+	}
+	if err := os.WriteFile(app.GetSnapshotSchedulerRunPath(), runBytes, application.WriteReadReadPerms); err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(app.GetSnapshotSchedulerRunPath()) }()
+	// wait for commands holding the lock, e.g. a network start, rather
+	// than failing the run
+	if app.LockWait == 0 {
+		app.LockWait = application.DefaultLockWait
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	sm := snapshot.NewSnapshotManager(app.GetBaseDir())
+	ux.Logger.PrintToUser("Snapshot scheduler running (Ctrl+C to stop)")
+	for {
+		next := time.Now().Truncate(time.Minute).Add(time.Minute)
+		select {
+		case <-ctx.Done():
+			ux.Logger.PrintToUser("Snapshot scheduler stopped")
+			return nil
+		case <-time.After(time.Until(next)):
+		}
+		if err := sm.RunDueSchedules(next, takeScheduledSnapshot); err != nil {
+			ux.Logger.PrintToUser("Failed to run the snapshot schedules: %v", err)
+		}
+	}
+}
+
+// takeScheduledSnapshot takes the snapshot of schedule falling due at
+func takeScheduledSnapshot(s *snapshot.Schedule, at time.Time) (string, error) {
+	name, err := createScheduledSnapshot(s, at)
+	if err != nil {
+		ux.Logger.PrintToUser("Scheduled snapshot %s failed: %v", s.ID, err)
+	}
+	return name, err
+}
+
+func createScheduledSnapshot(s *snapshot.Schedule, at time.Time) (string, error) {
+	running := app.GetAllRunningNetworks()
+	network := s.Network
+	switch {
+	case network != "" && !slices.Contains(running, network):
+		return "", fmt.Errorf("%s is not running", network)
+	case network != "":
+	case len(running) == 0:
+		return "", errors.New("no network running")
+	case len(running) > 1:
+		return "", fmt.Errorf("multiple networks running (%s): schedule one with --<network>", strings.Join(running, ", "))
+	default:
+		network = running[0]
+	}
+	name := fmt.Sprintf("%s-%s", network, at.Format("2006-01-02-1504"))
+
+	lock, err := app.AcquireLock("snapshot schedule " + s.ID)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = lock.Release() }()
+
+	sm := snapshot.NewSnapshotManager(app.GetBaseDir())
+	hc := hooks.Context{Network: network, Snapshot: name}
+	if err := app.RunWithHooks(hooks.SnapshotCreate, hc, func() error {
+		return sm.CreateSnapshot(name, s.Incremental)
+	}); err != nil {
+		return "", err
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "snapshot schedule",
+		Network:   network,
+		Params:    map[string]string{"schedule": s.ID, "cron": s.Cron, "snapshot": name},
+	})
+	ux.Logger.PrintToUser("Scheduled snapshot %s took %s", s.ID, name)
+	return name, nil
+}
+
+// printSchedules prints schedules, how their last runs went and whether
+// the scheduler runs them
+func printSchedules(schedules []*snapshot.Schedule) {
+	now := time.Now()
+	ux.Logger.PrintToUser("%-4s %-16s %-10s %-12s %-17s %-17s %s", "ID", "CRON", "NETWORK", "TYPE", "NEXT RUN", "LAST SUCCESS", "LAST FAILURE")
+	ux.Logger.PrintToUser("%-4s %-16s %-10s %-12s %-17s %-17s %s", "--", "----", "-------", "----", "--------", "------------", "------------")
+	for _, s := range schedules {
+		network := s.Network
+		if network == "" {
+			network = "running"
+		}
+		snapType := "full"
+		if s.Incremental {
+			snapType = "incremental"
+		}
+		ux.Logger.PrintToUser("%-4s %-16s %-10s %-12s %-17s %-17s %s",
+			s.ID,
+			s.Cron,
+			network,
+			snapType,
+			formatScheduleTime(s.Next(now)),
+			formatScheduleTime(s.LastSuccess),
+			formatScheduleTime(s.LastFailure))
+	}
+	for _, s := range schedules {
+		if s.LastError != "" && s.LastFailure.After(s.LastSuccess) {
+			ux.Logger.PrintToUser("Schedule %s last failed: %s", s.ID, s.LastError)
+		}
+	}
+	ux.Logger.PrintToUser("")
+	if pid, running := schedulerPID(); running {
+		ux.Logger.PrintToUser("Snapshot scheduler running (PID %d), logging to %s", pid, app.GetSnapshotSchedulerLogPath())
+	} else {
+		ux.Logger.PrintToUser("Snapshot scheduler not running: start it with 'lux snapshot schedule start'")
+	}
+}
+
+func formatScheduleTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
@@ -4,11 +4,14 @@
 package snapshotcmd
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/hooks"
 	"github.com/luxfi/cli/pkg/snapshot"
 	"github.com/luxfi/cli/pkg/ux"
 	"github.com/spf13/cobra"
@@ -30,6 +33,10 @@ This uses BadgerDB's native backup API for:
   - Fast restore times
   - Smaller backup sizes (zstd compressed)
 
+Nodes running PebbleDB or LevelDB are detected automatically and always get
+full key/value snapshots. Those engines require a CLI built with the pebbledb
+or leveldb build tag.
+
 USAGE:
 
   # Create snapshot of running network (auto-detects which network)
@@ -51,6 +58,16 @@ USAGE:
   # List available snapshots
   lux snapshot list
 
+  # Show the keys changed between two snapshots
+  lux snapshot diff mainnet-2026-01-18 mainnet-2026-01-19
+
+  # Publish a snapshot to IPFS and restore it elsewhere by CID
+  lux snapshot publish mainnet-2026-01-19
+  lux snapshot restore --cid <cid>
+
+  # Take an incremental snapshot every 6 hours
+  lux snapshot schedule add "0 */6 * * *" --incremental
+
 INCREMENTAL BACKUPS:
 
   By default, snapshots are incremental - they only include data that changed
@@ -66,7 +83,10 @@ INCREMENTAL BACKUPS:
 	// Subcommands
 	cmd.AddCommand(newRestoreCmd())
 	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newDiffCmd())
+	cmd.AddCommand(newPublishCmd())
 	cmd.AddCommand(newCleanCmd())
+	cmd.AddCommand(newScheduleCmd())
 
 	// Flags for main snapshot command
 	cmd.Flags().StringVar(&snapshotName, "name", "", "snapshot name (default: <network>-<date>)")
@@ -84,18 +104,32 @@ var (
 	snapshotMainnet bool
 	snapshotTestnet bool
 	snapshotDevnet  bool
+	ipfsAPI         string
+	ipfsGateway     string
+	restoreCID      string
+
+	restoreMap           []string
+	restoreNetwork       string
+	restoreSourceNetwork string
 )
 
+// flagNetwork returns the network selected by --mainnet, --testnet or
+// --devnet, or empty when none is
+func flagNetwork() string {
+	switch {
+	case snapshotMainnet:
+		return "mainnet"
+	case snapshotTestnet:
+		return "testnet"
+	case snapshotDevnet:
+		return "devnet"
+	}
+	return ""
+}
+
 func createSnapshot(cmd *cobra.Command, args []string) error {
 	// Determine network type
-	networkType := ""
-	if snapshotMainnet {
-		networkType = "mainnet"
-	} else if snapshotTestnet {
-		networkType = "testnet"
-	} else if snapshotDevnet {
-		networkType = "devnet"
-	}
+	networkType := flagNetwork()
 
 	// Auto-detect if not specified
 	if networkType == "" {
@@ -128,8 +162,14 @@ func createSnapshot(cmd *cobra.Command, args []string) error {
 
 	// Create snapshot using native backup
 	sm := snapshot.NewSnapshotManager(app.GetBaseDir())
-	if err := sm.CreateSnapshot(snapshotName, !fullBackup); err != nil {
-		return fmt.Errorf("failed to create snapshot: %w", err)
+	hc := hooks.Context{Network: networkType, Snapshot: snapshotName, Args: args}
+	if err := app.RunWithHooks(hooks.SnapshotCreate, hc, func() error {
+		if err := sm.CreateSnapshot(snapshotName, !fullBackup); err != nil {
+			return fmt.Errorf("failed to create snapshot: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return err
 	}
 
 	// Get snapshot info
@@ -156,24 +196,65 @@ func newRestoreCmd() *cobra.Command {
 The network must be stopped before restoring. After restore, start the
 network with 'lux network start'.
 
+With --cid, the snapshot is first fetched from an IPFS gateway, checking the
+checksum of every file against the published index, and saved locally under
+the given name, or the CID when none is given.
+
 EXAMPLES:
 
   # Restore from snapshot
   lux snapshot restore my-backup
 
   # Restore mainnet snapshot
-  lux snapshot restore mainnet-2026-01-19 --mainnet`,
-		Args: cobra.ExactArgs(1),
+  lux snapshot restore mainnet-2026-01-19 --mainnet
+
+  # Fetch a snapshot published to IPFS and restore it
+  lux snapshot restore mainnet-2026-01-19 --cid <cid> --gateway https://ipfs.io
+
+  # Restore node3 of a 5 node mainnet snapshot into a 1 node devnet
+  lux snapshot restore mainnet-2026-01-19 --source-network mainnet --network-name devnet --map node3=node1
+
+REMAPPING:
+
+  By default every network of the snapshot restores into the network of the
+  same name, and every node into the node of the same number. --network-name
+  restores into another network, whose current run must exist, and
+  --source-network picks the network of the snapshot to restore when it holds
+  several. --map <snapshot node>=<target node> restores a node into another
+  one; with --map only the mapped nodes are restored. Nodes the target run
+  lacks are skipped.`,
+		Args: cobra.RangeArgs(0, 1),
 		RunE: restoreSnapshot,
 	}
 	cmd.Flags().BoolVar(&snapshotMainnet, "mainnet", false, "restore to mainnet")
 	cmd.Flags().BoolVar(&snapshotTestnet, "testnet", false, "restore to testnet")
 	cmd.Flags().BoolVar(&snapshotDevnet, "devnet", false, "restore to devnet")
+	cmd.Flags().StringVar(&restoreCID, "cid", "", "fetch the snapshot published to IPFS with this CID")
+	cmd.Flags().StringVar(&ipfsGateway, "gateway", snapshot.DefaultIPFSGateway, "IPFS gateway to fetch from with --cid")
+	cmd.Flags().StringSliceVar(&restoreMap, "map", nil, "restore a node of the snapshot into another node, as node3=node1 (repeatable)")
+	cmd.Flags().StringVar(&restoreNetwork, "network-name", "", "restore into this network instead of the one the snapshot was taken from")
+	cmd.Flags().StringVar(&restoreSourceNetwork, "source-network", "", "restore only this network of the snapshot")
 	return cmd
 }
 
 func restoreSnapshot(cmd *cobra.Command, args []string) error {
-	name := args[0]
+	name := restoreCID
+	if len(args) > 0 {
+		name = args[0]
+	}
+	if name == "" {
+		return fmt.Errorf("snapshot name or --cid is required")
+	}
+	nodeMap, err := snapshot.ParseNodeMap(restoreMap)
+	if err != nil {
+		return err
+	}
+
+	lock, err := app.AcquireLock("snapshot restore " + name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
 
 	// Check no network is running
 	runningNetworks := app.GetAllRunningNetworks()
@@ -182,12 +263,45 @@ func restoreSnapshot(cmd *cobra.Command, args []string) error {
 			strings.Join(runningNetworks, ", "))
 	}
 
+	sm := snapshot.NewSnapshotManager(app.GetBaseDir())
+	hc := hooks.Context{Snapshot: name, Args: args}
+	return app.RunWithHooks(hooks.SnapshotRestore, hc, func() error {
+		return restoreFrom(cmd.Context(), sm, name, snapshot.RestoreOptions{
+			SourceNetwork: restoreSourceNetwork,
+			Network:       restoreNetwork,
+			NodeMap:       nodeMap,
+		})
+	})
+}
+
+// restoreFrom restores the snapshot name as opts remap it, fetching it first
+// with --cid
+func restoreFrom(ctx context.Context, sm *snapshot.SnapshotManager, name string, opts snapshot.RestoreOptions) error {
+	if restoreCID != "" {
+		ux.Logger.PrintToUser("Fetching snapshot %s from %s...", restoreCID, ipfsGateway)
+		p := snapshot.NewIPFSPublisher(snapshot.DefaultIPFSAPI, ipfsGateway)
+		index, err := sm.FetchSnapshot(ctx, p, restoreCID, name)
+		if err != nil {
+			return fmt.Errorf("failed to fetch snapshot: %w", err)
+		}
+		ux.Logger.PrintToUser("Fetched %d files of snapshot '%s'.", len(index.Files), index.Snapshot)
+	}
+
 	ux.Logger.PrintToUser("Restoring from snapshot: %s", name)
 
-	sm := snapshot.NewSnapshotManager(app.GetBaseDir())
-	if err := sm.RestoreSnapshot(name); err != nil {
+	if err := sm.RestoreSnapshotWithOptions(name, opts); err != nil {
 		return fmt.Errorf("failed to restore snapshot: %w", err)
 	}
+	app.RecordHistory(history.Entry{
+		Operation: "snapshot restore",
+		Params: map[string]string{
+			"snapshot":       name,
+			"cid":            restoreCID,
+			"source-network": opts.SourceNetwork,
+			"network-name":   opts.Network,
+			"map":            strings.Join(restoreMap, ","),
+		},
+	})
 
 	ux.Logger.PrintToUser("Snapshot restored successfully.")
 	ux.Logger.PrintToUser("Start the network with: lux network start")
@@ -198,7 +312,7 @@ func restoreSnapshot(cmd *cobra.Command, args []string) error {
 func newListCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "list",
-		Short: "List available snapshots",
+		Short: "List available snapshots and snapshot schedules",
 		RunE:  listSnapshots,
 	}
 }
@@ -210,29 +324,135 @@ func listSnapshots(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to list snapshots: %w", err)
 	}
 
+	schedules, err := sm.Schedules()
+	if err != nil {
+		return err
+	}
+
 	if len(snapshots) == 0 {
 		ux.Logger.PrintToUser("No snapshots found.")
 		ux.Logger.PrintToUser("Create one with: lux snapshot")
-		return nil
+	} else {
+		ux.Logger.PrintToUser("Available snapshots:")
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("%-30s %-12s %-12s %s", "NAME", "SIZE", "TYPE", "DATE")
+		ux.Logger.PrintToUser("%-30s %-12s %-12s %s", "----", "----", "----", "----")
+
+		for _, s := range snapshots {
+			snapType := "full"
+			if s.Incremental {
+				snapType = "incremental"
+			}
+			ux.Logger.PrintToUser("%-30s %-12s %-12s %s",
+				s.Name,
+				snapshot.FormatBytes(s.Size),
+				snapType,
+				s.Created.Format("2006-01-02 15:04"))
+		}
 	}
 
-	ux.Logger.PrintToUser("Available snapshots:")
-	ux.Logger.PrintToUser("")
-	ux.Logger.PrintToUser("%-30s %-12s %-12s %s", "NAME", "SIZE", "TYPE", "DATE")
-	ux.Logger.PrintToUser("%-30s %-12s %-12s %s", "----", "----", "----", "----")
+	// schedule status
+	if len(schedules) > 0 {
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("Snapshot schedules:")
+		ux.Logger.PrintToUser("")
+		printSchedules(schedules)
+	}
+
+	return nil
+}
+
+func newDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff [a] [b]",
+		Short: "Show the keys changed between two snapshots",
+		Long: `Show, for each database of two snapshots, the number of keys added, modified
+and deleted from snapshot a to snapshot b per key namespace: blocks, receipts,
+state and other. Use it to check what changed between two backups before
+rolling back.
+
+Both snapshots are streamed from their chunks. BadgerDB databases are first
+restored to temporary directories, which need as much free disk space as the
+restored databases.
+
+EXAMPLES:
+
+  lux snapshot diff mainnet-2026-01-18 mainnet-2026-01-19`,
+		Args: cobra.ExactArgs(2),
+		RunE: diffSnapshots,
+	}
+}
+
+func diffSnapshots(_ *cobra.Command, args []string) error {
+	sm := snapshot.NewSnapshotManager(app.GetBaseDir())
+	diffs, err := sm.Diff(args[0], args[1])
+	if err != nil {
+		return fmt.Errorf("failed to diff snapshots: %w", err)
+	}
+	if len(diffs) == 0 {
+		ux.Logger.PrintToUser("No databases found in %s and %s.", args[0], args[1])
+		return nil
+	}
 
-	for _, s := range snapshots {
-		snapType := "full"
-		if s.Incremental {
-			snapType = "incremental"
+	for _, d := range diffs {
+		ux.Logger.PrintToUser("")
+		switch {
+		case d.OnlyIn != "":
+			ux.Logger.PrintToUser("%s/%s (only in %s)", d.Network, d.Entry, d.OnlyIn)
+		case !d.Changed():
+			ux.Logger.PrintToUser("%s/%s: unchanged", d.Network, d.Entry)
+			continue
+		default:
+			ux.Logger.PrintToUser("%s/%s", d.Network, d.Entry)
+		}
+		ux.Logger.PrintToUser("  %-10s %12s %12s %12s", "NAMESPACE", "ADDED", "MODIFIED", "DELETED")
+		for _, ns := range d.Namespaces {
+			ux.Logger.PrintToUser("  %-10s %12d %12d %12d", ns.Namespace, ns.Added, ns.Modified, ns.Deleted)
 		}
-		ux.Logger.PrintToUser("%-30s %-12s %-12s %s",
-			s.Name,
-			snapshot.FormatBytes(s.Size),
-			snapType,
-			s.Created.Format("2006-01-02 15:04"))
 	}
+	return nil
+}
+
+func newPublishCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "publish [name]",
+		Short: "Publish a snapshot to IPFS",
+		Long: `Publish a snapshot to IPFS through the RPC API of an IPFS (Kubo) node. The
+chunks and manifests of the snapshot, and an index of their checksums, are
+added and pinned as one directory, and its CID is printed.
+
+Nodes bootstrap from the snapshot with 'lux snapshot restore --cid <cid>',
+fetching it from any gateway. Keep the publishing node online, or pin the CID
+on a pinning service, for the snapshot to stay available.
+
+EXAMPLES:
+
+  lux snapshot publish mainnet-2026-01-19
 
+  lux snapshot publish mainnet-2026-01-19 --ipfs-api http://10.0.0.5:5001`,
+		Args: cobra.ExactArgs(1),
+		RunE: publishSnapshot,
+	}
+	cmd.Flags().StringVar(&ipfsAPI, "ipfs-api", snapshot.DefaultIPFSAPI, "RPC API of the IPFS node to publish with")
+	return cmd
+}
+
+func publishSnapshot(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	ux.Logger.PrintToUser("Publishing snapshot '%s' to IPFS via %s...", name, ipfsAPI)
+	sm := snapshot.NewSnapshotManager(app.GetBaseDir())
+	cid, err := sm.Publish(cmd.Context(), snapshot.NewIPFSPublisher(ipfsAPI, snapshot.DefaultIPFSGateway), name)
+	if err != nil {
+		return fmt.Errorf("failed to publish snapshot: %w", err)
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "snapshot publish",
+		Params:    map[string]string{"snapshot": name, "cid": cid},
+	})
+
+	ux.Logger.PrintToUser("Snapshot published.")
+	ux.Logger.PrintToUser("  CID: %s", cid)
+	ux.Logger.PrintToUser("Restore it with: lux snapshot restore %s --cid %s", name, cid)
 	return nil
 }
 
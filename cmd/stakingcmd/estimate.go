@@ -0,0 +1,93 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package stakingcmd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/luxfi/cli/pkg/config"
+	"github.com/luxfi/cli/pkg/staking"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/protocol/p/reward"
+	"github.com/luxfi/sdk/platformvm"
+	"github.com/spf13/cobra"
+)
+
+var (
+	estimateAmount   float64
+	estimateDuration time.Duration
+	estimateSupply   float64
+)
+
+func newEstimateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "estimate",
+		Short: "Estimate the reward of a validator stake",
+		Long: `Estimate the reward of staking --amount LUX for --duration as a primary
+network validator. The current supply is read with platform.getCurrentSupply
+and the reward is computed with the reward parameters of the network, as the
+P-Chain does when the stake ends, assuming the uptime requirement is met.
+
+The reward shrinks as the supply grows towards its cap, so the estimate only
+holds for stakes starting now.
+
+EXAMPLES:
+
+  lux staking estimate --amount 2000 --duration 8760h --network mainnet
+  lux staking estimate --amount 5000 --duration 336h --supply 1500000000000`,
+		Args:         cobra.NoArgs,
+		RunE:         runEstimate,
+		SilenceUsage: true,
+		Annotations:  map[string]string{config.CapabilityAnnotation: config.CapabilityNone},
+	}
+	cmd.Flags().Float64Var(&estimateAmount, "amount", 0, "Amount to stake in LUX (required)")
+	cmd.Flags().DurationVar(&estimateDuration, "duration", staking.Year, "How long to stake")
+	cmd.Flags().Float64Var(&estimateSupply, "supply", 0, "Current supply in LUX to assume instead of querying the network")
+	_ = cmd.MarkFlagRequired("amount")
+	return cmd
+}
+
+func runEstimate(_ *cobra.Command, _ []string) error {
+	if estimateAmount <= 0 || estimateAmount*float64(constants.Lux) > math.MaxUint64 {
+		return fmt.Errorf("invalid amount %g", estimateAmount)
+	}
+	t, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	params := staking.NetworkParams(t.networkID)
+
+	supply := uint64(estimateSupply * float64(constants.Lux))
+	supplySource := "--supply"
+	if estimateSupply <= 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		supply, _, err = platformvm.NewClient(t.endpoint).GetCurrentSupply(ctx, constants.PrimaryNetworkID)
+		if err != nil {
+			return fmt.Errorf("failed to get the current supply from %s: %w", t.endpoint, err)
+		}
+		supplySource = "platform.getCurrentSupply"
+	}
+
+	e, err := staking.EstimateReward(params, uint64(estimateAmount*float64(constants.Lux)), supply, estimateDuration)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Network:          %s (%s)", t.networkType, t.endpoint)
+	ux.Logger.PrintToUser("Stake:            %s LUX for %s (%.1f days)", formatLUX(e.Amount), e.Duration, e.Duration.Hours()/24)
+	ux.Logger.PrintToUser("Current supply:   %s LUX (%s)", formatLUX(e.CurrentSupply), supplySource)
+	ux.Logger.PrintToUser("Supply cap:       %s LUX", formatLUX(params.Reward.SupplyCap))
+	ux.Logger.PrintToUser("Consumption rate: %.2f%% to %.2f%% over %s",
+		float64(params.Reward.MinConsumptionRate)*100/reward.PercentDenominator,
+		float64(params.Reward.MaxConsumptionRate)*100/reward.PercentDenominator,
+		params.Reward.MintingPeriod)
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Expected reward:  %s LUX", formatLUX(e.Reward))
+	ux.Logger.PrintToUser("Effective APR:    %.2f%%", e.APR)
+	return nil
+}
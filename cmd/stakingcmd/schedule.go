@@ -0,0 +1,158 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package stakingcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/config"
+	"github.com/luxfi/cli/pkg/node"
+	"github.com/luxfi/cli/pkg/staking"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/platformvm"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var scheduleNodeIDs []string
+
+func newScheduleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Show when the stakes of the known validators end",
+		Long: `Show the primary network stakes of the validators known locally, the next
+ending first: the nodes of the running local network, the validator nodes of
+the clusters on the network and the validators recorded for deployed chains.
+Nodes that are not validating the primary network are listed last.
+
+EXAMPLES:
+
+  lux staking schedule
+  lux staking schedule --network mainnet --node-id NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg`,
+		Args:         cobra.NoArgs,
+		RunE:         runSchedule,
+		SilenceUsage: true,
+		Annotations:  map[string]string{config.CapabilityAnnotation: config.CapabilityNone},
+	}
+	cmd.Flags().StringSliceVar(&scheduleNodeIDs, "node-id", nil, "Other node to include (repeatable)")
+	return cmd
+}
+
+func runSchedule(_ *cobra.Command, _ []string) error {
+	t, err := resolveTarget()
+	if err != nil {
+		return err
+	}
+	known := knownNodes(t)
+	if len(known) == 0 {
+		return fmt.Errorf("no validators known for %s, pass them with --node-id", t.networkType)
+	}
+	nodeIDs := make([]ids.NodeID, 0, len(known))
+	for _, s := range known.NodeIDs() {
+		nodeID, err := ids.NodeIDFromString(s)
+		if err != nil {
+			return fmt.Errorf("invalid node ID %q of %s: %w", s, strings.Join(known[s], ", "), err)
+		}
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	validators, err := platformvm.NewClient(t.endpoint).GetCurrentValidators(ctx, constants.PrimaryNetworkID, nodeIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get the current validators from %s: %w", t.endpoint, err)
+	}
+	current := make([]staking.Stake, 0, len(validators))
+	for _, v := range validators {
+		s := staking.Stake{
+			NodeID: v.NodeID.String(),
+			Start:  time.Unix(int64(v.StartTime), 0),
+			End:    time.Unix(int64(v.EndTime), 0),
+			Weight: v.Weight,
+		}
+		if v.PotentialReward != nil {
+			s.PotentialReward = *v.PotentialReward
+		}
+		current = append(current, s)
+	}
+
+	ux.Logger.PrintToUser("Primary network stakes on %s (%s)", t.networkType, t.endpoint)
+	now := time.Now()
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Node ID", "Known From", "Stake (LUX)", "End", "Remaining", "Potential Reward (LUX)")
+	for _, s := range staking.Schedule(known, current) {
+		if !s.Validating() {
+			_ = table.Append([]string{s.NodeID, strings.Join(s.Sources, ", "), "-", "not validating", "-", "-"})
+			continue
+		}
+		_ = table.Append([]string{
+			s.NodeID,
+			strings.Join(s.Sources, ", "),
+			formatLUX(s.Weight),
+			s.End.Local().Format(time.RFC3339),
+			s.End.Sub(now).Round(time.Minute).String(),
+			formatLUX(s.PotentialReward),
+		})
+	}
+	return table.Render()
+}
+
+// knownNodes returns the nodes known locally on the network of t: the nodes
+// of the running local network, the validator nodes of its clusters, the
+// validators of deployed chains and --node-id
+func knownNodes(t target) staking.KnownNodes {
+	known := staking.KnownNodes{}
+	if t.state != nil {
+		for _, v := range t.state.Validators {
+			known.Add(v.NodeID, "local "+t.networkType)
+		}
+	}
+
+	if app.ClustersConfigExists() {
+		if clustersConfig, err := app.LoadClustersConfig(); err == nil {
+			clusters, _ := clustersConfig["clusters"].(map[string]interface{})
+			names := make([]string, 0, len(clusters))
+			for name := range clusters {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				cluster, err := node.GetClusterEndpoints(app, name)
+				if err != nil || cluster.Local || !strings.Contains(strings.ToLower(cluster.Network), t.networkType) {
+					continue
+				}
+				for _, n := range cluster.Nodes {
+					if !n.API {
+						known.Add(n.NodeID, "cluster "+name)
+					}
+				}
+			}
+		}
+	}
+
+	if names, err := app.GetSidecarNames(); err == nil {
+		sort.Strings(names)
+		for _, name := range names {
+			sc, err := app.LoadSidecar(name)
+			if err != nil {
+				continue
+			}
+			for _, nodeID := range sc.Networks[t.network.String()].ValidatorIDs {
+				known.Add(nodeID, "chain "+name)
+			}
+		}
+	}
+
+	for _, nodeID := range scheduleNodeIDs {
+		known.Add(nodeID, "--node-id")
+	}
+	return known
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package stakingcmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/sdk/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	app *application.Lux
+
+	networkType string
+	rpcURL      string
+)
+
+// NewCmd creates the staking command
+func NewCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "staking",
+		Short: "Estimate staking rewards and follow validator stake end times",
+		Long: `The staking command helps plan primary network validation: it estimates the
+reward of a stake from the current supply and the reward parameters of the
+network, and shows when the stakes of the validators known locally end.
+
+The network is the running local network unless --network is given.
+
+EXAMPLES:
+
+  # Reward of 2000 LUX staked for a year on mainnet
+  lux staking estimate --amount 2000 --duration 8760h --network mainnet
+
+  # When the stakes of the local, cluster and chain validators end
+  lux staking schedule`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.PersistentFlags().StringVar(&networkType, "network", "", "Network type: custom, devnet, testnet or mainnet (default: the running network, else mainnet)")
+	cmd.PersistentFlags().StringVar(&rpcURL, "rpc-url", "", "Base URL of the node to query (default: the network endpoint)")
+	cmd.AddCommand(newEstimateCmd())
+	cmd.AddCommand(newScheduleCmd())
+	return cmd
+}
+
+// target is the network the staking commands query
+type target struct {
+	networkType string
+	network     models.Network
+	networkID   uint32
+	endpoint    string
+	state       *application.NetworkState
+}
+
+// resolveTarget returns the network of --network, served by the running
+// local network when it is of that type
+func resolveTarget() (target, error) {
+	t := target{networkType: networkType}
+	if t.networkType == "" {
+		if t.networkType = app.GetRunningNetworkType(); t.networkType == "" {
+			t.networkType = "mainnet"
+		}
+	}
+	switch t.networkType {
+	case "mainnet":
+		t.network = models.Mainnet
+	case "testnet":
+		t.network = models.Testnet
+	case "devnet":
+		t.network = models.Devnet
+	case "custom", "local":
+		t.network = models.Local
+	default:
+		return t, fmt.Errorf("unknown network %q, use custom, devnet, testnet or mainnet", t.networkType)
+	}
+	t.networkID = t.network.ID()
+	t.endpoint = t.network.Endpoint()
+	if state, err := app.LoadNetworkStateForType(t.networkType); err == nil && state != nil && state.Running {
+		t.state = state
+		if state.APIEndpoint != "" {
+			t.endpoint = state.APIEndpoint
+		}
+		if state.NetworkID != 0 {
+			t.networkID = state.NetworkID
+		}
+	}
+	if rpcURL != "" {
+		t.endpoint = rpcURL
+	}
+	t.endpoint = strings.TrimSuffix(t.endpoint, "/")
+	if t.endpoint == "" {
+		return t, fmt.Errorf("no endpoint for %s, start the network or pass --rpc-url", t.networkType)
+	}
+	return t, nil
+}
+
+// formatLUX formats an amount in the P-Chain unit as LUX
+func formatLUX(amount uint64) string {
+	return fmt.Sprintf("%.5f", float64(amount)/float64(constants.Lux))
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statecmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/statefile"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var repair bool
+
+func newFsckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fsck",
+		Short: "Check local state files, and repair them with --repair",
+		Long: `Check the sidecars, cluster configs and network states of ~/.lux for
+files truncated or corrupted by a crash, files written before state files
+were versioned, and temporary files left over by interrupted writes.
+
+With --repair, legacy files are upgraded to the current schema, corrupted
+files are restored from the backup kept by their last write, or moved aside
+to <file>.corrupt when there is none, and left over temporary files are
+removed. Files written by a newer CLI are reported but never changed.`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: fsck,
+	}
+	cmd.Flags().BoolVar(&repair, "repair", false, "repair the problems found")
+	return cmd
+}
+
+func fsck(_ *cobra.Command, _ []string) error {
+	if repair {
+		lock, err := app.AcquireLock("state fsck")
+		if err != nil {
+			return err
+		}
+		defer func() { _ = lock.Release() }()
+	}
+
+	files, dirs, err := app.StateFiles()
+	if err != nil {
+		return err
+	}
+	var findings []statefile.Finding
+	for _, f := range files {
+		findings = append(findings, statefile.Check(f))
+	}
+	for _, dir := range dirs {
+		leftovers, err := statefile.Leftovers(dir)
+		if err != nil {
+			return err
+		}
+		findings = append(findings, leftovers...)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("File", "Kind", "Status", "Action")
+	problems, unrepaired := 0, 0
+	for _, f := range findings {
+		if f.Status == statefile.StatusOK {
+			continue
+		}
+		problems++
+		action := "run with --repair"
+		switch {
+		case f.Status == statefile.StatusNewer:
+			unrepaired++
+			action = "upgrade the CLI"
+		case f.Status == statefile.StatusUnreadable:
+			unrepaired++
+			action = f.Err.Error()
+		case repair:
+			if action, err = statefile.Repair(f); err != nil {
+				unrepaired++
+				action = "failed: " + err.Error()
+			}
+		}
+		rel, err := filepath.Rel(app.GetBaseDir(), f.Path)
+		if err != nil {
+			rel = f.Path
+		}
+		_ = table.Append([]string{rel, f.Kind.Name, string(f.Status), action})
+	}
+
+	if problems == 0 {
+		ux.Logger.GreenCheckmarkToUser("%d state files checked, no problems found", len(files))
+		return nil
+	}
+	if err := table.Render(); err != nil {
+		return err
+	}
+	switch {
+	case !repair:
+		return fmt.Errorf("%d problems found in %d state files, run 'lux state fsck --repair'", problems, len(files))
+	case unrepaired > 0:
+		return fmt.Errorf("%d of %d problems could not be repaired", unrepaired, problems)
+	}
+	ux.Logger.GreenCheckmarkToUser("Repaired %d problems", problems)
+	return nil
+}
@@ -0,0 +1,260 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statecmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/remotestate"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	app *application.Lux
+
+	branch   string
+	region   string
+	endpoint string
+	profile  string
+	force    bool
+)
+
+// NewCmd creates the state command
+func NewCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Share chain sidecars, cluster configs and history through remote state",
+		Long: `The state command shares the state a team operates on together through a
+remote backend, so every operator of a devnet works from the same chain
+sidecars and genesis, cluster configs and history ledger instead of each
+holding a divergent ~/.lux.
+
+The backend is an S3 bucket (s3://bucket/prefix, or any S3-compatible store
+with --endpoint) or a git repository (git+ssh://, git+https://, git@host:repo
+or any URL ending in .git). Keys are never shared.
+
+Pulls and pushes lock the remote while they run. Files changed only
+remotely are pulled, files changed only locally are pushed, and files
+changed on both sides are reported as conflicts until one side is picked
+with --force. The history ledger is append-only and always merges.
+
+Local state files are written atomically and versioned. fsck checks them
+after a crash, and repairs truncated or legacy files.
+
+EXAMPLES:
+
+  # Share state through an S3 bucket
+  lux state init s3://team-lux-state/devnet --region us-east-1
+
+  # Or through a git repository
+  lux state init git@github.com:team/lux-state.git --branch devnet
+
+  # What differs from the remote
+  lux state status
+
+  # Take the team's changes, then publish your own
+  lux state pull
+  lux state push
+
+  # Remove a lock left behind by a crashed CLI
+  lux state unlock
+
+  # Find and repair state files truncated by a crash
+  lux state fsck --repair`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.AddCommand(newInitCmd())
+	cmd.AddCommand(newStatusCmd())
+	cmd.AddCommand(newPullCmd())
+	cmd.AddCommand(newPushCmd())
+	cmd.AddCommand(newUnlockCmd())
+	cmd.AddCommand(newFsckCmd())
+	return cmd
+}
+
+func newInitCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "init <url>",
+		Short: "Configure the remote state backend",
+		Long: `Configure the S3 bucket or git repository remote state is shared through.
+Nothing is synced until the next pull or push.`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: initState,
+	}
+	cmd.Flags().StringVar(&branch, "branch", remotestate.DefaultBranch, "branch of a git backend")
+	cmd.Flags().StringVar(&region, "region", "", "region of an S3 backend")
+	cmd.Flags().StringVar(&endpoint, "endpoint", "", "endpoint of an S3-compatible backend such as MinIO or R2")
+	cmd.Flags().StringVar(&profile, "profile", "", "AWS profile of an S3 backend")
+	return cmd
+}
+
+func initState(_ *cobra.Command, args []string) error {
+	c := &remotestate.Config{URL: args[0]}
+	if c.IsGit() {
+		c.Branch = branch
+	} else {
+		c.Region = region
+		c.Endpoint = endpoint
+		c.Profile = profile
+	}
+	if _, err := remotestate.Open(context.Background(), app.GetBaseDir(), c); err != nil {
+		return err
+	}
+	if err := remotestate.SaveConfig(app.GetBaseDir(), c); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Remote state backend set to %s", c.URL)
+	ux.Logger.PrintToUser("Use 'lux state pull' to take the shared state, 'lux state push' to publish yours")
+	return nil
+}
+
+func newStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show what differs between local and remote state",
+		Args:  cobrautils.ExactArgs(0),
+		RunE:  showStatus,
+	}
+}
+
+func showStatus(_ *cobra.Command, _ []string) error {
+	ctx := context.Background()
+	syncer, err := newSyncer(ctx)
+	if err != nil {
+		return err
+	}
+	manifest, changes, err := syncer.Status(ctx)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Remote: %s", syncer.Store)
+	if manifest.Revision > 0 {
+		ux.Logger.PrintToUser("Revision %d, pushed by %s at %s", manifest.Revision, manifest.UpdatedBy, manifest.UpdatedAt.Local().Format("2006-01-02 15:04:05"))
+	} else {
+		ux.Logger.PrintToUser("Nothing pushed yet")
+	}
+	lock, err := remotestate.ReadLock(ctx, syncer.Store)
+	if err != nil {
+		return err
+	}
+	if lock != nil {
+		ux.Logger.PrintToUser("%s", (&remotestate.LockedError{Lock: lock}).Error())
+	}
+	if len(changes) == 0 {
+		ux.Logger.PrintToUser("Local state is in sync")
+		return nil
+	}
+	return printChanges(changes)
+}
+
+func newPullCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pull",
+		Short: "Take remote state changes",
+		Long: `Take the files changed remotely since the last sync. Files also changed
+locally are conflicts: pull fails without changing anything unless --force
+is given, which overwrites the local versions.`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runSync((*remotestate.Syncer).Pull)
+		},
+	}
+	cmd.Flags().BoolVar(&force, "force", false, "overwrite conflicting local changes with the remote versions")
+	return cmd
+}
+
+func newPushCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "push",
+		Short: "Publish local state changes",
+		Long: `Publish the files changed locally since the last sync. Files also changed
+remotely are conflicts: push fails without publishing anything unless
+--force is given, which overwrites the remote versions. Remote changes to
+other files are left for the next pull.`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runSync((*remotestate.Syncer).Push)
+		},
+	}
+	cmd.Flags().BoolVar(&force, "force", false, "overwrite conflicting remote changes with the local versions")
+	return cmd
+}
+
+func runSync(op func(*remotestate.Syncer, context.Context) (*remotestate.Result, error)) error {
+	ctx := context.Background()
+	syncer, err := newSyncer(ctx)
+	if err != nil {
+		return err
+	}
+	syncer.Force = force
+	result, err := op(syncer, ctx)
+	if err != nil {
+		return err
+	}
+	if len(result.Changes) == 0 {
+		ux.Logger.PrintToUser("Local state is in sync with revision %d", result.Revision)
+		return nil
+	}
+	if err := printChanges(result.Changes); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Synced %d files with revision %d", len(result.Changes), result.Revision)
+	return nil
+}
+
+func newUnlockCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unlock",
+		Short: "Remove the remote state lock",
+		Long: `Remove the remote state lock, for a lock left behind by a CLI that died
+during a pull or push. Locks also expire on their own after 10 minutes.`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: unlock,
+	}
+}
+
+func unlock(_ *cobra.Command, _ []string) error {
+	ctx := context.Background()
+	syncer, err := newSyncer(ctx)
+	if err != nil {
+		return err
+	}
+	lock, err := remotestate.BreakLock(ctx, syncer.Store)
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		ux.Logger.PrintToUser("Remote state is not locked")
+		return nil
+	}
+	ux.Logger.PrintToUser("Removed the lock %s held for %s", lock.Owner, lock.Operation)
+	return nil
+}
+
+func newSyncer(ctx context.Context) (*remotestate.Syncer, error) {
+	c, err := remotestate.LoadConfig(app.GetBaseDir())
+	if err != nil {
+		return nil, err
+	}
+	store, err := remotestate.Open(ctx, app.GetBaseDir(), c)
+	if err != nil {
+		return nil, err
+	}
+	return &remotestate.Syncer{BaseDir: app.GetBaseDir(), Store: store}, nil
+}
+
+func printChanges(changes []remotestate.Change) error {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("File", "Change", "Action")
+	for _, c := range changes {
+		_ = table.Append([]string{c.Path, c.Describe(), string(c.Action)})
+	}
+	return table.Render()
+}
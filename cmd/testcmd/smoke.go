@@ -0,0 +1,285 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package testcmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/contract"
+	"github.com/luxfi/cli/pkg/smoke"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// tempNetworkType is the network started by --temp
+const tempNetworkType = "devnet"
+
+var (
+	smokeNetwork    string
+	smokeTxs        int
+	smokeTimeout    time.Duration
+	smokeJUnit      string
+	smokeTemp       bool
+	smokePort       int
+	smokeNodePath   string
+	privateKeyFlags contract.PrivateKeyFlags
+)
+
+// lux test smoke
+func newSmokeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "smoke <blockchainName>",
+		Aliases: []string{"run"},
+		Short:   "Smoke test a deployed chain",
+		Long: `The smoke command runs the standard end-to-end checks against an EVM chain
+and prints a pass/fail report:
+
+  rpc              the RPC endpoint answers with the chain ID of the genesis
+  send txs         --txs transfers are accepted and the balances add up
+  deploy ERC-20    a token contract deploys and holds its supply
+  warp round-trip  a Warp message sent by the chain is signed by its
+                   validators (skipped when the genesis has no warpConfig)
+
+The chain is tested where it is deployed on --network, the running network
+by default. With --temp it is deployed instead to a devnet started for the
+test in a throwaway CLI home, which is stopped and removed afterwards: the
+chain configuration is tested without touching your networks.
+
+The checks are paid by --private-key, --key or, by default, the key the
+genesis funds. The command fails when a check fails, and --junit writes the
+report for CI.
+
+EXAMPLES:
+
+  lux test smoke mychain
+  lux test smoke mychain --network testnet --key deployer
+  lux test smoke mychain --temp --junit smoke.xml`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: runSmoke,
+	}
+	cmd.Flags().StringVar(&smokeNetwork, "network", "", "Network type: custom, devnet, testnet or mainnet (default: the running network)")
+	cmd.Flags().IntVar(&smokeTxs, "txs", 10, "number of txs sent by the send txs check")
+	cmd.Flags().DurationVar(&smokeTimeout, "timeout", 2*time.Minute, "time limit of each check")
+	cmd.Flags().StringVar(&smokeJUnit, "junit", "", "write the report to this file as JUnit XML")
+	cmd.Flags().BoolVar(&smokeTemp, "temp", false, "deploy the chain to a temporary devnet for the test")
+	cmd.Flags().IntVar(&smokePort, "port", 9700, "base port of the temporary devnet nodes (with --temp)")
+	cmd.Flags().StringVar(&smokeNodePath, "node-path", "", "path to the luxd binary of the temporary devnet (with --temp)")
+	privateKeyFlags.AddToCmd(cmd, "to pay for the checks")
+	return cmd
+}
+
+func runSmoke(_ *cobra.Command, args []string) error {
+	chainName := args[0]
+	sc, err := app.LoadSidecar(chainName)
+	if err != nil {
+		return fmt.Errorf("chain %s not found: %w", chainName, err)
+	}
+	if sc.VM != models.EVM {
+		return fmt.Errorf("%s is a %s chain, smoke tests support EVM chains", chainName, sc.VM)
+	}
+	if smokeTxs < 1 {
+		return fmt.Errorf("--txs must be at least 1")
+	}
+	genesisData, err := app.LoadRawGenesis(chainName)
+	if err != nil {
+		return fmt.Errorf("failed to load genesis: %w", err)
+	}
+	var genesis struct {
+		Config struct {
+			ChainID    *big.Int        `json:"chainId"`
+			WarpConfig json.RawMessage `json:"warpConfig"`
+		} `json:"config"`
+	}
+	if err := json.Unmarshal(genesisData, &genesis); err != nil {
+		return fmt.Errorf("invalid genesis of %s: %w", chainName, err)
+	}
+
+	target, networkType := app, smokeNetwork
+	if smokeTemp {
+		if smokeNetwork != "" {
+			return fmt.Errorf("--temp and --network are mutually exclusive")
+		}
+		tempApp, cleanup, err := startTempEnvironment(chainName)
+		defer cleanup()
+		if err != nil {
+			return err
+		}
+		target, networkType = tempApp, tempNetworkType
+	} else if networkType == "" {
+		if networkType = app.GetRunningNetworkType(); networkType == "" {
+			networkType = "custom"
+		}
+	}
+	network := sdkNetwork(networkType)
+	rpcURL, err := chainRPCURL(target, networkType, chainName)
+	if err != nil {
+		return err
+	}
+	privateKey, err := smokePrivateKey(target, network, chainName)
+	if err != nil {
+		return err
+	}
+
+	ux.Logger.PrintToUser("Smoke testing %s on %s at %s", chainName, network.Name(), rpcURL)
+	checks := smoke.EVMChecks(smoke.EVMTarget{
+		RPCURL:     rpcURL,
+		ChainID:    genesis.Config.ChainID,
+		PrivateKey: privateKey,
+		Txs:        smokeTxs,
+		Warp:       len(genesis.Config.WarpConfig) > 0 && string(genesis.Config.WarpConfig) != "null",
+	})
+	results := smoke.Run(context.Background(), checks, smokeTimeout, func(r smoke.Result) {
+		ux.Logger.PrintToUser("  %-16s %s", r.Name, strings.ToUpper(string(r.Status)))
+	})
+	printSmokeResults(results)
+	if smokeJUnit != "" {
+		f, err := os.Create(smokeJUnit)
+		if err != nil {
+			return err
+		}
+		err = smoke.WriteJUnit(f, chainName, results)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", smokeJUnit, err)
+		}
+	}
+	if failed := smoke.Failed(results); len(failed) > 0 {
+		return fmt.Errorf("%d of %d smoke tests of %s failed", len(failed), len(results), chainName)
+	}
+	ux.Logger.GreenCheckmarkToUser("%s passed its smoke tests", chainName)
+	return nil
+}
+
+// smokePrivateKey returns the key of the flags paying for the checks,
+// the key the genesis funds when none is given
+func smokePrivateKey(target *application.Lux, network models.Network, chainName string) (string, error) {
+	if privateKeyFlags.PrivateKey == "" && privateKeyFlags.KeyName == "" {
+		privateKeyFlags.GenesisKey = true
+	}
+	genesisPrivateKey := ""
+	if privateKeyFlags.GenesisKey {
+		var err error
+		_, genesisPrivateKey, err = contract.GetEVMChainPrefundedKey(target, network, contract.ChainSpec{BlockchainName: chainName})
+		if err != nil {
+			return "", fmt.Errorf("failed to find the key funded by the genesis of %s, give one with --key: %w", chainName, err)
+		}
+	}
+	return privateKeyFlags.GetPrivateKey(target, genesisPrivateKey)
+}
+
+// startTempEnvironment starts a devnet in a throwaway CLI home and deploys
+// chainName to it. It returns the application of the home, and the cleanup
+// stopping the devnet and removing the home.
+func startTempEnvironment(chainName string) (*application.Lux, func(), error) {
+	if state, err := app.LoadNetworkStateForType(tempNetworkType); err == nil && state != nil && state.Running {
+		return nil, func() {}, fmt.Errorf("a %s is already running: smoke test its chains without --temp, or stop it first", tempNetworkType)
+	}
+	dir, err := os.MkdirTemp("", "lux-smoke-")
+	if err != nil {
+		return nil, func() {}, err
+	}
+	tempApp := application.New()
+	tempApp.Setup(dir, app.Log, app.Conf, app.CliPrompt, nil)
+	started := false
+	cleanup := func() {
+		if started {
+			ux.Logger.PrintToUser("Stopping the temporary %s", tempNetworkType)
+			if err := runLux(dir, "network", "stop", "--"+tempNetworkType); err != nil {
+				ux.Logger.PrintToUser("Failed to stop the temporary %s: %s", tempNetworkType, err)
+			}
+		}
+		_ = os.RemoveAll(dir)
+	}
+	chainDir := filepath.Join(tempApp.GetChainsDir(), chainName)
+	if err := os.CopyFS(chainDir, os.DirFS(filepath.Join(app.GetChainsDir(), chainName))); err != nil {
+		return nil, cleanup, fmt.Errorf("failed to copy %s to %s: %w", chainName, dir, err)
+	}
+
+	ux.Logger.PrintToUser("Starting a temporary %s in %s", tempNetworkType, dir)
+	startArgs := []string{"network", "start", "--" + tempNetworkType, "--port", strconv.Itoa(smokePort)}
+	if smokeNodePath != "" {
+		startArgs = append(startArgs, "--node-path", smokeNodePath)
+	}
+	started = true
+	if err := runLux(dir, startArgs...); err != nil {
+		return nil, cleanup, fmt.Errorf("failed to start the temporary %s: %w", tempNetworkType, err)
+	}
+	if err := runLux(dir, "chain", "deploy", chainName, "--"+tempNetworkType); err != nil {
+		return nil, cleanup, fmt.Errorf("failed to deploy %s to the temporary %s: %w", chainName, tempNetworkType, err)
+	}
+	return tempApp, cleanup, nil
+}
+
+// runLux runs this CLI with args in the CLI home dir
+func runLux(dir string, args ...string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(self, append([]string{"--home", dir, "--non-interactive"}, args...)...) //nolint:gosec // G204: runs this CLI
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// sdkNetwork returns the network of a network type
+func sdkNetwork(networkType string) models.Network {
+	switch networkType {
+	case "mainnet":
+		return models.Mainnet
+	case "testnet":
+		return models.Testnet
+	case "devnet":
+		return models.Devnet
+	default:
+		return models.Local
+	}
+}
+
+// chainRPCURL returns the RPC endpoint of chainName deployed to the network
+// of networkType, on the running node of target when there is one
+func chainRPCURL(target *application.Lux, networkType, chainName string) (string, error) {
+	network := sdkNetwork(networkType)
+	baseURL := network.Endpoint()
+	if state, err := target.LoadNetworkStateForType(networkType); err == nil && state != nil && state.Running && state.APIEndpoint != "" {
+		baseURL = state.APIEndpoint
+	}
+	sc, err := target.LoadSidecar(chainName)
+	if err != nil {
+		return "", err
+	}
+	deployment := sc.Networks[network.Name()]
+	if len(deployment.RPCEndpoints) > 0 {
+		return deployment.RPCEndpoints[0], nil
+	}
+	if deployment.BlockchainID == ids.Empty {
+		return "", fmt.Errorf("%s is not deployed to %s: deploy it with 'lux chain deploy %s', or test it with --temp", chainName, network.Name(), chainName)
+	}
+	return models.GetRPCEndpoint(strings.TrimSuffix(baseURL, "/"), deployment.BlockchainID.String()), nil
+}
+
+func printSmokeResults(results []smoke.Result) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Check", "Status", "Time", "Detail")
+	for _, r := range results {
+		_ = table.Append([]string{r.Name, strings.ToUpper(string(r.Status)), r.Duration.Round(time.Millisecond).String(), r.Detail})
+	}
+	_ = table.Render()
+}
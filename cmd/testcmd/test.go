@@ -0,0 +1,36 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package testcmd provides the test commands, which check deployed chains
+// end to end.
+package testcmd
+
+import (
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Lux
+
+// NewCmd creates the test command suite
+func NewCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Run end-to-end tests against your chains",
+		Long: `The test command suite checks chains end to end, the way their users
+exercise them, with a pass/fail report CI can gate on.
+
+EXAMPLES:
+
+  # Smoke test a chain deployed to the running network
+  lux test smoke mychain
+
+  # Smoke test a chain in a throwaway devnet, with a JUnit report for CI
+  lux test smoke mychain --temp --junit smoke.xml`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	cmd.AddCommand(newSmokeCmd())
+	return cmd
+}
@@ -26,6 +26,8 @@ func NewCmd(injectedApp *application.Lux) *cobra.Command {
 		},
 	}
 	app = injectedApp
+	// transaction build
+	cmd.AddCommand(newTransactionBuildCmd())
 	// chain upgrade vm
 	cmd.AddCommand(newTransactionSignCmd())
 	// chain upgrade generate
@@ -0,0 +1,107 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package transactioncmd
+
+import (
+	"github.com/luxfi/cli/pkg/txutils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/ids"
+	"github.com/spf13/cobra"
+)
+
+var (
+	outputTxPath string
+	forceWrite   bool
+)
+
+// lux transaction build
+func newTransactionBuildCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "build [chainName]",
+		Short: "prepare a transaction for offline signing",
+		Long: `The transaction build command prepares a multisig transaction to be signed on
+a machine without network access.
+
+It looks up the chain control keys on the P-Chain and writes the transaction
+as JSON, with the hash every signer signs and the addresses that still have
+to sign it. Move the file to the offline machine, sign it there with
+'lux transaction sign --offline --key-file', and bring it back to commit it
+with 'lux transaction commit'.
+
+EXAMPLES:
+
+  # online: prepare the transaction written by a multisig deploy
+  lux transaction build mychain --input-tx-filepath tx.hex --output-tx-filepath tx.json
+
+  # offline: sign it
+  lux transaction sign --offline --key-file signer.pk --input-tx-filepath tx.json
+
+  # online: submit it
+  lux transaction commit mychain --input-tx-filepath tx.json`,
+		RunE:         buildTx,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringVar(&inputTxPath, inputTxPathFlag, "", "Path to the transaction to prepare")
+	cmd.Flags().StringVar(&outputTxPath, "output-tx-filepath", "", "Path to write the transaction for offline signing to")
+	cmd.Flags().BoolVar(&forceWrite, "force", false, "overwrite the output file if it exists")
+	return cmd
+}
+
+func buildTx(_ *cobra.Command, args []string) error {
+	var err error
+	if inputTxPath == "" {
+		inputTxPath, err = app.Prompt.CaptureExistingFilepath("What is the path to the transaction to prepare for offline signing?")
+		if err != nil {
+			return err
+		}
+	}
+	if outputTxPath == "" {
+		outputTxPath, err = app.Prompt.CaptureNewFilepath("What is the path to write the transaction for offline signing to?")
+		if err != nil {
+			return err
+		}
+	}
+	tx, err := txutils.LoadFromDisk(inputTxPath)
+	if err != nil {
+		return err
+	}
+	network, err := txutils.GetNetwork(tx)
+	if err != nil {
+		return err
+	}
+
+	chainName := args[0]
+	sc, err := app.LoadSidecar(chainName)
+	if err != nil {
+		return err
+	}
+	chainID := sc.Networks[network.String()].ChainID
+	if chainID == ids.Empty {
+		return errNoChainID
+	}
+	_, controlKeys, _, err := txutils.GetOwners(network, chainID)
+	if err != nil {
+		return err
+	}
+
+	offline, err := txutils.NewOfflineTx(tx, chainName, chainID, controlKeys)
+	if err != nil {
+		return err
+	}
+	if err := txutils.SaveOfflineTx(offline, outputTxPath, forceWrite); err != nil {
+		return err
+	}
+
+	ux.Logger.PrintToUser("%s transaction for %s on %s written to %s", offline.TxType, chainName, offline.Network, outputTxPath)
+	ux.Logger.PrintToUser("Signing hash: %s", offline.SigningHash)
+	ux.Logger.PrintToUser("Remaining signers:")
+	for _, addr := range app.AnnotateAddresses(offline.Remaining()) {
+		ux.Logger.PrintToUser("  - %s", addr)
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Sign it offline with: lux transaction sign --offline --key-file <key> --input-tx-filepath %s", outputTxPath)
+	return nil
+}
@@ -69,7 +69,7 @@ func commitTx(_ *cobra.Command, args []string) error {
 		signedCount := len(chainAuthKeys) - len(remainingChainAuthKeys)
 		ux.Logger.PrintToUser("%d of %d required signatures have been signed.", signedCount, len(chainAuthKeys))
 		ux.Logger.PrintToUser("Remaining signers for %s:", chainName)
-		for _, addr := range remainingChainAuthKeys {
+		for _, addr := range app.AnnotateAddresses(remainingChainAuthKeys) {
 			ux.Logger.PrintToUser("  - %s", addr)
 		}
 		ux.Logger.PrintToUser("Transaction file: %s", inputTxPath)
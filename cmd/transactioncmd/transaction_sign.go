@@ -5,8 +5,10 @@ package transactioncmd
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/luxfi/cli/pkg/chain"
+	"github.com/luxfi/cli/pkg/key"
 	keychainpkg "github.com/luxfi/cli/pkg/keychain"
 	"github.com/luxfi/cli/pkg/prompts"
 	"github.com/luxfi/cli/pkg/txutils"
@@ -23,20 +25,28 @@ var (
 	keyName         string
 	useLedger       bool
 	ledgerAddresses []string
+	offlineSign     bool
+	keyFile         string
 
 	errNoChainID                  = errors.New("failed to find the chain ID for this chain, has it been deployed/created on this network?")
 	errMutuallyExclusiveKeyLedger = errors.New("--key and --ledger/--ledger-addrs are mutually exclusive")
 	errStoredKeyOnMainnet         = errors.New("--key is not available for mainnet operations")
+	errOfflineKeyFile             = errors.New("--offline signing requires --key-file")
+	errMissingChainName           = errors.New("the chain name is required, unless signing --offline")
 )
 
 // lux transaction sign
 func newTransactionSignCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "sign [chainName]",
-		Short:        "sign a transaction",
-		Long:         "The transaction sign command signs a multisig transaction.",
+		Use:   "sign [chainName]",
+		Short: "sign a transaction",
+		Long: `The transaction sign command signs a multisig transaction.
+
+With --offline it signs a transaction prepared by 'lux transaction build'
+with the private key in --key-file, without any network access, so the key
+never has to be on a connected machine. The chain name is not needed then.`,
 		RunE:         signTx,
-		Args:         cobra.ExactArgs(1),
+		Args:         cobra.RangeArgs(0, 1),
 		SilenceUsage: true,
 	}
 
@@ -44,6 +54,8 @@ func newTransactionSignCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use [testnet only]")
 	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on testnet)")
 	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	cmd.Flags().BoolVar(&offlineSign, "offline", false, "sign a transaction prepared by 'lux transaction build' without network access")
+	cmd.Flags().StringVar(&keyFile, "key-file", "", "private key file to sign --offline with")
 	return cmd
 }
 
@@ -55,6 +67,12 @@ func signTx(_ *cobra.Command, args []string) error {
 			return err
 		}
 	}
+	if offlineSign {
+		return signTxOffline()
+	}
+	if len(args) == 0 {
+		return errMissingChainName
+	}
 	tx, err := txutils.LoadFromDisk(inputTxPath)
 	if err != nil {
 		return err
@@ -130,7 +148,7 @@ func signTx(_ *cobra.Command, args []string) error {
 			ux.Logger.PrintToUser("There are no required chain auth keys present in the wallet")
 			ux.Logger.PrintToUser("")
 			ux.Logger.PrintToUser("Expected one of:")
-			for _, addr := range remainingChainAuthKeys {
+			for _, addr := range app.AnnotateAddresses(remainingChainAuthKeys) {
 				ux.Logger.PrintToUser("  %s", addr)
 			}
 			return nil
@@ -153,7 +171,7 @@ func signTx(_ *cobra.Command, args []string) error {
 	ux.Logger.PrintToUser("%d of %d required signatures have been signed.", signedCount, len(chainAuthKeys))
 	if len(remainingChainAuthKeys) > 0 {
 		ux.Logger.PrintToUser("Remaining signers:")
-		for _, addr := range remainingChainAuthKeys {
+		for _, addr := range app.AnnotateAddresses(remainingChainAuthKeys) {
 			ux.Logger.PrintToUser("  - %s", addr)
 		}
 	} else {
@@ -162,3 +180,52 @@ func signTx(_ *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// signTxOffline signs a transaction prepared by lux transaction build with a
+// key file, without network access
+func signTxOffline() error {
+	if keyFile == "" {
+		return errOfflineKeyFile
+	}
+	offline, err := txutils.LoadOfflineTx(inputTxPath)
+	if err != nil {
+		return err
+	}
+	// the addresses are derived for the network of the tx when signing
+	sk, err := key.LoadSoft(models.NewLocalNetwork().ID(), keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load key file: %w", err)
+	}
+
+	ux.Logger.PrintToUser("Signing %s transaction for %s on %s", offline.TxType, offline.ChainName, offline.Network)
+	ux.Logger.PrintToUser("Signing hash: %s", offline.SigningHash)
+	signed, err := offline.Sign(sk.Key())
+	if errors.Is(err, txutils.ErrNoOfflineSigner) {
+		ux.Logger.PrintToUser("The key is not one of the remaining signers")
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("Expected one of:")
+		for _, addr := range offline.Remaining() {
+			ux.Logger.PrintToUser("  %s", addr)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := txutils.SaveOfflineTx(offline, inputTxPath, true); err != nil {
+		return err
+	}
+
+	remaining := offline.Remaining()
+	ux.Logger.PrintToUser("%d signatures added, %d of %d required signatures have been signed.", signed, len(offline.Signers)-len(remaining), len(offline.Signers))
+	if len(remaining) > 0 {
+		ux.Logger.PrintToUser("Remaining signers:")
+		for _, addr := range app.AnnotateAddresses(remaining) {
+			ux.Logger.PrintToUser("  - %s", addr)
+		}
+	} else {
+		ux.Logger.PrintToUser("Transaction is fully signed. Commit it from an online machine:")
+		ux.Logger.PrintToUser("  lux transaction commit %s --input-tx-filepath %s", offline.ChainName, inputTxPath)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package updatecmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/dependencies"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/sdk/models"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// lux update check
+func newCheckCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "check",
+		Short: "Show the cached luxd and EVM version matrix",
+		Long: `The check command shows the version matrix the CLI resolves luxd and EVM
+versions from, as cached in ~/.lux/version-matrix.json: when each document
+was fetched, whether it is older than the cache TTL, and the versions it
+gives. Nothing is fetched.`,
+		Args:         cobrautils.ExactArgs(0),
+		RunE:         checkMatrix,
+		SilenceUsage: true,
+	}
+}
+
+// lux update sync
+func newSyncCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "sync",
+		Short: "Fetch the luxd and EVM version matrix into the cache",
+		Long: `The sync command fetches every document of the version matrix again, even
+offline, and caches it: the CLI dependency versions, the luxd RPC
+compatibility, and the latest luxd release and pre-release. Run it before
+going offline, or in CI before the jobs that deploy.`,
+		Args:         cobrautils.ExactArgs(0),
+		RunE:         syncMatrix,
+		SilenceUsage: true,
+	}
+}
+
+func checkMatrix(_ *cobra.Command, _ []string) error {
+	m, err := dependencies.LoadVersionMatrix(app.GetBaseDir())
+	if err != nil {
+		return err
+	}
+	printMatrix(m)
+	return nil
+}
+
+func syncMatrix(_ *cobra.Command, _ []string) error {
+	m, failed, err := dependencies.SyncVersionMatrix(app)
+	if err != nil {
+		return err
+	}
+	printMatrix(m)
+	if len(failed) > 0 {
+		keys := make([]string, 0, len(failed))
+		for key := range failed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			ux.Logger.PrintToUser("Failed to fetch %s: %s", dependencies.SourceName(key), failed[key])
+		}
+		return fmt.Errorf("failed to fetch %d of the version matrix documents", len(failed))
+	}
+	ux.Logger.GreenCheckmarkToUser("Version matrix cached in %s", dependencies.VersionMatrixPath(app.GetBaseDir()))
+	return nil
+}
+
+func printMatrix(m *dependencies.VersionMatrix) {
+	mode := "online, cached documents are fetched again after " + dependencies.VersionMatrixTTL.String()
+	if dependencies.Offline(app) {
+		mode = "offline, versions are pinned to the cached documents"
+	}
+	ux.Logger.PrintToUser("Version matrix %s (%s)", dependencies.VersionMatrixPath(app.GetBaseDir()), mode)
+	if len(m.Entries) == 0 {
+		ux.Logger.PrintToUser("Nothing cached yet: run 'lux update sync'")
+		return
+	}
+	now := time.Now()
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Source", "Fetched", "Status")
+	for _, key := range m.Keys() {
+		entry := m.Entries[key]
+		status := "fresh"
+		if entry.Stale(now) {
+			status = "stale"
+		}
+		_ = table.Append([]string{
+			dependencies.SourceName(key),
+			fmt.Sprintf("%s ago", now.Sub(entry.Fetched).Round(time.Minute)),
+			status,
+		})
+	}
+	_ = table.Render()
+
+	entry, ok := m.Entries[constants.CLILatestDependencyURL]
+	if !ok {
+		return
+	}
+	var deps models.CLIDependencyMap
+	if err := json.Unmarshal([]byte(entry.Data), &deps); err != nil {
+		ux.Logger.PrintToUser("Invalid CLI dependency versions in the cache: %s", err)
+		return
+	}
+	ux.Logger.PrintToUser("RPC protocol %d, EVM %s", deps.RPC, deps.EVM)
+	networks := make([]string, 0, len(deps.Luxd))
+	for network := range deps.Luxd {
+		networks = append(networks, network)
+	}
+	sort.Strings(networks)
+	table = tablewriter.NewWriter(os.Stdout)
+	table.Header("Network", "Latest luxd", "Minimum luxd")
+	for _, network := range networks {
+		v := deps.Luxd[network]
+		_ = table.Append([]string{network, v.LatestVersion, orNone(v.MinimumVersion)})
+	}
+	_ = table.Render()
+}
+
+func orNone(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "none"
+	}
+	return s
+}
@@ -32,9 +32,21 @@ var (
 func NewCmd(injectedApp *application.Lux, version string) *cobra.Command {
 	app = injectedApp
 	cmd := &cobra.Command{
-		Use:          "update",
-		Short:        "Check for latest updates of Lux CLI",
-		Long:         `Check if an update is available, and prompt the user to install it`,
+		Use:   "update",
+		Short: "Check for latest updates of Lux CLI",
+		Long: `Check if an update is available, and prompt the user to install it.
+
+The luxd and EVM versions the CLI deploys are resolved from a version matrix
+fetched from GitHub and cached in ~/.lux/version-matrix.json for a day.
+'lux update check' shows the cache and 'lux update sync' refreshes it. With
+'lux config offline enable', or LUX_OFFLINE=true for one command, versions
+are pinned to the cache and nothing is fetched.
+
+EXAMPLES:
+
+  lux update --confirm
+  lux update sync
+  lux update check`,
 		RunE:         runUpdate,
 		Args:         cobra.ExactArgs(0),
 		SilenceUsage: true,
@@ -42,6 +54,8 @@ func NewCmd(injectedApp *application.Lux, version string) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVarP(&yes, "confirm", "c", false, "Assume yes for installation")
+	cmd.AddCommand(newCheckCmd())
+	cmd.AddCommand(newSyncCmd())
 	return cmd
 }
 
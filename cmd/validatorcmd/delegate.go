@@ -0,0 +1,740 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/luxfi/cli/cmd/flags"
+	"github.com/luxfi/cli/pkg/chain"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/contract"
+	"github.com/luxfi/cli/pkg/faucet"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/keychain"
+	"github.com/luxfi/cli/pkg/networkoptions"
+	cliprompts "github.com/luxfi/cli/pkg/prompts"
+	"github.com/luxfi/cli/pkg/ux"
+	clivalidator "github.com/luxfi/cli/pkg/validator"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/ethclient"
+	"github.com/luxfi/ids"
+	sdkcontract "github.com/luxfi/sdk/contract"
+	"github.com/luxfi/sdk/evm"
+	"github.com/luxfi/sdk/models"
+	"github.com/luxfi/sdk/prompts"
+	"github.com/luxfi/sdk/validator"
+	sdkvalidatormanager "github.com/luxfi/sdk/validatormanager"
+	"github.com/luxfi/warp"
+	"github.com/spf13/cobra"
+)
+
+var (
+	delegateAmount     string
+	delegationIDStr    string
+	rewardRecipientStr string
+	forceUndelegation  bool
+	delegatorKeyFlags  contract.PrivateKeyFlags
+)
+
+// lux validator delegate
+func NewDelegateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delegate",
+		Short: "Delegates stake to a validator of a Proof of Stake L1",
+		Long: `This command delegates stake to a validator of a Proof of Stake L1.
+
+It initiates the delegation on the L1 staking manager contract, paying the
+stake in the native token or, for an ERC20 staking manager, approving the
+token transfer first. It then gets the resulting warp message signed by the
+L1 validators, registers the new validator weight on P-Chain with a
+SetL1ValidatorWeightTx, and completes the delegation on the contract with
+the P-Chain acknowledgement.
+
+The delegation is signed with the delegator key, --delegator-key,
+--delegator-private-key or --delegator-genesis-key, which owns it and, unless
+--reward-recipient is given, receives its rewards. The P-Chain fee is paid
+with --key. An interrupted delegation resumes from the --initiate-tx-hash of
+its first step.
+
+Delegation needs an ACP-99 staking manager.
+
+EXAMPLES:
+
+  lux validator delegate --l1 mychain --node-id NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg \
+    --amount 100 --delegator-key alice --key ops --testnet`,
+		RunE: delegate,
+		Args: cobrautils.ExactArgs(0),
+	}
+
+	// Network flags handled at higher level to avoid conflicts
+	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to pay the P-Chain fee with [testnet/devnet only]")
+	cmd.Flags().StringVar(&l1, "l1", "", "name of L1")
+	cmd.Flags().StringVar(&nodeIDStr, "node-id", "", "node ID of the validator to delegate to")
+	cmd.Flags().StringVar(&delegateAmount, "amount", "", "stake to delegate, in whole tokens")
+	cmd.Flags().StringVar(&rewardRecipientStr, "reward-recipient", "", "address receiving the delegation rewards (default the delegator)")
+	cmd.Flags().StringVar(&initiateTxHash, "initiate-tx-hash", "", "tx hash of an already initiated delegation")
+	addDelegatorKeyFlags(cmd, "to delegate with")
+	flags.AddRPCFlagToCmd(cmd, app, &validatorManagerRPC)
+	sigAggGroup := flags.AddSignatureAggregatorFlagsToCmd(cmd, &sigAggFlags)
+	cmd.SetHelpFunc(flags.WithGroupedHelp([]flags.GroupedFlags{sigAggGroup}))
+	return cmd
+}
+
+// lux validator undelegate
+func NewUndelegateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "undelegate",
+		Short: "Ends a delegation to a validator of a Proof of Stake L1",
+		Long: `This command ends a delegation to a validator of a Proof of Stake L1,
+returning the stake and paying the delegation rewards.
+
+It initiates the removal of the delegation on the L1 staking manager
+contract, with an uptime proof of the validator signed by the L1 validators
+so the delegation earns its rewards. It then gets the resulting warp message
+signed, registers the new validator weight on P-Chain with a
+SetL1ValidatorWeightTx, and completes the removal on the contract with the
+P-Chain acknowledgement, which pays out the stake and the rewards.
+
+With --force the removal proceeds even when the validator uptime doesn't
+earn rewards. Delegations to validators that are already removed end at
+once. An interrupted removal resumes from the --initiate-tx-hash of its
+first step, or with 'lux validator rewards claim'.
+
+EXAMPLES:
+
+  lux validator undelegate --l1 mychain --delegator-key alice --key ops --testnet`,
+		RunE: undelegate,
+		Args: cobrautils.ExactArgs(0),
+	}
+
+	// Network flags handled at higher level to avoid conflicts
+	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to pay the P-Chain fee with [testnet/devnet only]")
+	cmd.Flags().StringVar(&l1, "l1", "", "name of L1")
+	cmd.Flags().StringVar(&delegationIDStr, "delegation-id", "", "ID of the delegation to end (default: prompt among the delegator's)")
+	cmd.Flags().StringVar(&initiateTxHash, "initiate-tx-hash", "", "tx hash of an already initiated removal")
+	cmd.Flags().BoolVar(&forceUndelegation, "force", false, "end the delegation even if the validator uptime does not earn rewards")
+	addDelegatorKeyFlags(cmd, "owning the delegation")
+	flags.AddRPCFlagToCmd(cmd, app, &validatorManagerRPC)
+	sigAggGroup := flags.AddSignatureAggregatorFlagsToCmd(cmd, &sigAggFlags)
+	cmd.SetHelpFunc(flags.WithGroupedHelp([]flags.GroupedFlags{sigAggGroup}))
+	return cmd
+}
+
+func addDelegatorKeyFlags(cmd *cobra.Command, goal string) {
+	delegatorKeyFlags.SetFlagNames("delegator-private-key", "delegator-key", "delegator-genesis-key")
+	delegatorKeyFlags.AddToCmd(cmd, goal)
+}
+
+// stakingManager is the ACP-99 staking manager of an L1 and what delegation
+// flows read it with
+type stakingManager struct {
+	*l1ValidatorManager
+	network      models.Network
+	client       *ethclient.Client
+	chainID      ids.ID
+	blockchainID ids.ID
+}
+
+// loadStakingManager finds the staking manager of the Proof of Stake l1 on
+// network, connecting to its RPC and, to sign warp messages, setting up the
+// signature aggregator
+func loadStakingManager(network models.Network, l1 string, sign bool) (*stakingManager, error) {
+	manager, err := newL1ValidatorManager(network, l1)
+	if err != nil {
+		return nil, err
+	}
+	if !manager.pos {
+		return nil, fmt.Errorf("%s is not a Proof of Stake L1: its validators take no delegations", l1)
+	}
+	if !manager.useACP99 {
+		return nil, fmt.Errorf("delegating on %s needs an ACP-99 staking manager", l1)
+	}
+	if sign {
+		if err := manager.startAggregator(network); err != nil {
+			return nil, err
+		}
+	}
+	chainID, err := sdkcontract.GetNetworkID(app.GetSDKApp(), network, manager.chainSpec)
+	if err != nil {
+		return nil, err
+	}
+	blockchainID, err := sdkcontract.GetBlockchainID(app.GetSDKApp(), network, manager.chainSpec)
+	if err != nil {
+		return nil, err
+	}
+	client, err := ethclient.Dial(manager.rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", manager.rpcURL, err)
+	}
+	return &stakingManager{
+		l1ValidatorManager: manager,
+		network:            network,
+		client:             client,
+		chainID:            chainID,
+		blockchainID:       blockchainID,
+	}, nil
+}
+
+func (m *stakingManager) contractAddress() common.Address {
+	return common.HexToAddress(m.address)
+}
+
+// tokenDecimals returns the decimals of the token the manager stakes
+func (m *stakingManager) tokenDecimals(ctx context.Context) (common.Address, bool, uint8, error) {
+	token, isERC20, err := clivalidator.StakingToken(ctx, m.client, m.contractAddress())
+	if err != nil || !isERC20 {
+		return token, isERC20, clivalidator.NativeTokenDecimals, err
+	}
+	decimals, err := clivalidator.TokenDecimals(ctx, m.client, token)
+	return token, isERC20, decimals, err
+}
+
+// delegatorKey returns the private key and address of the delegator, from
+// the delegator key flags or prompted
+func delegatorKey(network models.Network, l1 string) (string, common.Address, error) {
+	genesisPrivateKey := ""
+	if delegatorKeyFlags.GenesisKey {
+		var err error
+		if _, genesisPrivateKey, err = contract.GetEVMChainPrefundedKey(app, network, contract.ChainSpec{BlockchainName: l1}); err != nil {
+			return "", common.Address{}, err
+		}
+	}
+	privateKey, err := delegatorKeyFlags.GetPrivateKey(app, genesisPrivateKey)
+	if err != nil {
+		return "", common.Address{}, err
+	}
+	if privateKey == "" {
+		if !cliprompts.IsInteractive() {
+			return "", common.Address{}, errors.New("give the delegator key with --delegator-key, --delegator-private-key or --delegator-genesis-key")
+		}
+		if privateKey, err = prompts.PromptPrivateKey(app.Prompt, "sign as the delegator"); err != nil {
+			return "", common.Address{}, err
+		}
+	}
+	address, err := evm.PrivateKeyToAddress(privateKey)
+	if err != nil {
+		return "", common.Address{}, err
+	}
+	return privateKey, common.Address(address), nil
+}
+
+// pChainDeployer returns the deployer paying the P-Chain fee with --key
+func pChainDeployer(network models.Network) (*chain.PublicDeployer, error) {
+	fee, err := l1ValidatorTxFee(network)
+	if err != nil {
+		return nil, err
+	}
+	kc, err := keychain.GetKeychainFromCmdLineFlags(
+		app,
+		constants.PayTxsFeesMsg,
+		network,
+		keyName,
+		useLocalKey,
+		useLedger,
+		ledgerAddresses,
+		fee,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return chain.NewPublicDeployer(app, useLedger, kc.Keychain, network), nil
+}
+
+// initiateReceipt returns the receipt of the first step of a flow, already
+// sent as --initiate-tx-hash
+func (m *stakingManager) initiateReceipt(ctx context.Context) (*types.Receipt, error) {
+	receipt, err := m.client.TransactionReceipt(ctx, common.HexToHash(initiateTxHash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the receipt of %s: %w", initiateTxHash, err)
+	}
+	ux.Logger.PrintToUser("Resuming from tx %s", initiateTxHash)
+	return receipt, nil
+}
+
+// registerWeight gets the validator weight message of receipt signed by the
+// L1 validators, registers it on P-Chain, and returns the signed P-Chain
+// acknowledgement
+func (m *stakingManager) registerWeight(deployer *chain.PublicDeployer, receipt *types.Receipt) (*warp.Message, error) {
+	unsignedMessage, err := evm.ExtractWarpMessageFromReceipt(receipt)
+	if err != nil {
+		return nil, err
+	}
+	var signedMessage *warp.Message
+	if err := m.watchAggregation(m.network, func() error {
+		var err error
+		signedMessage, err = sdkvalidatormanager.GetL1ValidatorWeightMessage(
+			m.network,
+			m.aggregatorLogger,
+			unsignedMessage,
+			m.chainID,
+			m.blockchainID,
+			crypto.Address{},
+			ids.Empty,
+			0,
+			0,
+			m.aggregatorEndpoint,
+		)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	txID, err := deployer.SetL1ValidatorWeight(signedMessage.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	ux.Logger.PrintToUser("SetL1ValidatorWeightTx ID: %s", txID)
+	return m.pChainAcknowledgement(signedMessage, ids.Empty, 0, 0)
+}
+
+// pChainAcknowledgement gets the P-Chain acknowledgement of the weight
+// change of signedMessage signed, or, without it, of the weight change of
+// validationID to weight at nonce
+func (m *stakingManager) pChainAcknowledgement(signedMessage *warp.Message, validationID ids.ID, nonce uint64, weight uint64) (*warp.Message, error) {
+	var ack *warp.Message
+	err := m.watchAggregation(m.network, func() error {
+		var err error
+		ack, err = sdkvalidatormanager.GetPChainL1ValidatorWeightMessage(
+			m.network,
+			m.aggregatorLogger,
+			0,
+			m.chainID,
+			signedMessage,
+			validationID,
+			nonce,
+			weight,
+			m.aggregatorEndpoint,
+		)
+		return err
+	})
+	return ack, err
+}
+
+// complete sends method, completing the registration or removal of
+// delegationID with the P-Chain acknowledgement ack
+func (m *stakingManager) complete(privateKey string, method string, delegationID ids.ID, ack *warp.Message) error {
+	tx, _, err := contract.TxToMethodWithWarpMessage(
+		m.rpcURL,
+		false,
+		crypto.Address{},
+		privateKey,
+		crypto.HexToAddress(m.address),
+		ack,
+		big.NewInt(0),
+		"complete delegation "+delegationID.String(),
+		sdkvalidatormanager.ErrorSignatureToError,
+		method+"(bytes32,uint32)",
+		delegationID,
+		uint32(0),
+	)
+	if err != nil {
+		return evm.TransactionError(tx, err, "failure completing delegation %s", delegationID)
+	}
+	return nil
+}
+
+func delegate(_ *cobra.Command, _ []string) error {
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		globalNetworkFlags,
+		true,
+		false,
+		networkoptions.DefaultSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	l1Name, found, cancel, err := selectL1Validator(network, l1, nodeIDStr)
+	if err != nil {
+		return err
+	}
+	if cancel {
+		return nil
+	}
+	manager, err := loadStakingManager(network, l1Name, true)
+	if err != nil {
+		return err
+	}
+	defer manager.client.Close()
+	privateKey, delegator, err := delegatorKey(network, l1Name)
+	if err != nil {
+		return err
+	}
+	deployer, err := pChainDeployer(network)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var (
+		receipt *types.Receipt
+		amount  = "resumed"
+	)
+	if initiateTxHash != "" {
+		if receipt, err = manager.initiateReceipt(ctx); err != nil {
+			return err
+		}
+	} else {
+		var stake *big.Int
+		if receipt, stake, err = initiateDelegation(ctx, manager, privateKey, delegator, found.ValidationID); err != nil {
+			return err
+		}
+		amount = stake.String()
+	}
+	delegationID, err := clivalidator.DelegationIDFromLogs(receipt.Logs)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Delegation ID: %s", delegationID)
+
+	ack, err := manager.registerWeight(deployer, receipt)
+	if err != nil {
+		return err
+	}
+	if err := manager.complete(privateKey, "completeDelegatorRegistration", delegationID, ack); err != nil {
+		return err
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "validator delegate",
+		Network:   network.String(),
+		Chain:     l1Name,
+		Params: map[string]string{
+			"validationID": found.ValidationID.String(),
+			"nodeID":       found.NodeID.String(),
+			"delegator":    delegator.Hex(),
+			"amount":       amount,
+		},
+		Result: map[string]string{"delegationID": delegationID.String()},
+	})
+	ux.Logger.GreenCheckmarkToUser("Delegated to node %s on %s, delegation ID %s", found.NodeID, l1Name, delegationID)
+	return nil
+}
+
+// initiateDelegation initiates the delegation of delegator to validationID,
+// approving the staking manager to transfer ERC20 stake first. It returns
+// the receipt and the stake, in the smallest unit of the staking token.
+func initiateDelegation(ctx context.Context, manager *stakingManager, privateKey string, delegator common.Address, validationID ids.ID) (*types.Receipt, *big.Int, error) {
+	token, isERC20, decimals, err := manager.tokenDecimals(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if delegateAmount == "" {
+		if delegateAmount, err = app.Prompt.CaptureString("How many tokens to delegate?"); err != nil {
+			return nil, nil, err
+		}
+	}
+	stake, err := faucet.ParseAmount(delegateAmount, int(decimals))
+	if err != nil {
+		return nil, nil, err
+	}
+	rewardRecipient := delegator
+	if rewardRecipientStr != "" {
+		if !common.IsHexAddress(rewardRecipientStr) {
+			return nil, nil, fmt.Errorf("invalid reward recipient %q", rewardRecipientStr)
+		}
+		rewardRecipient = common.HexToAddress(rewardRecipientStr)
+	}
+
+	var (
+		tx      *types.Transaction
+		receipt *types.Receipt
+	)
+	if isERC20 {
+		allowance, err := clivalidator.TokenAllowance(ctx, manager.client, token, delegator, manager.contractAddress())
+		if err != nil {
+			return nil, nil, err
+		}
+		if allowance.Cmp(stake) < 0 {
+			ux.Logger.PrintToUser("Approving the staking manager to transfer %s tokens of %s", delegateAmount, token.Hex())
+			tx, _, err = contract.TxToMethod(
+				manager.rpcURL,
+				false,
+				crypto.Address{},
+				privateKey,
+				crypto.Address(token),
+				nil,
+				"approve the delegation stake",
+				nil,
+				"approve(address, uint256)->(bool)",
+				manager.contractAddress(),
+				stake,
+			)
+			if err != nil {
+				return nil, nil, evm.TransactionError(tx, err, "failure approving the stake")
+			}
+		}
+		tx, receipt, err = contract.TxToMethod(
+			manager.rpcURL,
+			false,
+			crypto.Address{},
+			privateKey,
+			crypto.HexToAddress(manager.address),
+			nil,
+			"initiate delegator registration",
+			sdkvalidatormanager.ErrorSignatureToError,
+			"initiateDelegatorRegistration(bytes32,uint256,address)->(bytes32)",
+			validationID,
+			stake,
+			rewardRecipient,
+		)
+	} else {
+		tx, receipt, err = contract.TxToMethod(
+			manager.rpcURL,
+			false,
+			crypto.Address{},
+			privateKey,
+			crypto.HexToAddress(manager.address),
+			stake,
+			"initiate delegator registration",
+			sdkvalidatormanager.ErrorSignatureToError,
+			"initiateDelegatorRegistration(bytes32,address)->(bytes32)",
+			validationID,
+			rewardRecipient,
+		)
+	}
+	if err != nil {
+		return nil, nil, evm.TransactionError(tx, err, "failure initiating the delegation")
+	}
+	ux.Logger.PrintToUser("Delegation of %s tokens initiated. InitiateTxHash: %s", delegateAmount, tx.Hash())
+	return receipt, stake, nil
+}
+
+func undelegate(_ *cobra.Command, _ []string) error {
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		globalNetworkFlags,
+		true,
+		false,
+		networkoptions.DefaultSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	l1Name, err := selectL1(network, l1)
+	if err != nil || l1Name == "" {
+		return err
+	}
+	manager, err := loadStakingManager(network, l1Name, true)
+	if err != nil {
+		return err
+	}
+	defer manager.client.Close()
+	privateKey, delegator, err := delegatorKey(network, l1Name)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	delegation, err := selectDelegation(ctx, manager, delegator)
+	if err != nil {
+		return err
+	}
+	deployer, err := pChainDeployer(network)
+	if err != nil {
+		return err
+	}
+
+	var receipt *types.Receipt
+	if initiateTxHash != "" {
+		if receipt, err = manager.initiateReceipt(ctx); err != nil {
+			return err
+		}
+	} else {
+		if delegation.Status != clivalidator.ActiveDelegatorStatus {
+			return fmt.Errorf("delegation %s is %s: only active delegations can end. Complete a pending removal with 'lux validator rewards claim'",
+				delegation.DelegationID, delegation.Status)
+		}
+		if receipt, err = initiateUndelegation(manager, privateKey, delegation); err != nil {
+			return err
+		}
+	}
+	if _, err := evm.ExtractWarpMessageFromReceipt(receipt); err != nil {
+		// the validator is removed: the delegation ended at once
+		recordUndelegation(network, l1Name, delegation)
+		ux.Logger.GreenCheckmarkToUser("Delegation %s ended, its validator being removed", delegation.DelegationID)
+		return nil
+	}
+	ack, err := manager.registerWeight(deployer, receipt)
+	if err != nil {
+		return err
+	}
+	if err := manager.complete(privateKey, "completeDelegatorRemoval", delegation.DelegationID, ack); err != nil {
+		return err
+	}
+	recordUndelegation(network, l1Name, delegation)
+	ux.Logger.GreenCheckmarkToUser("Delegation %s ended, stake and rewards paid", delegation.DelegationID)
+	return nil
+}
+
+// initiateUndelegation initiates the removal of delegation, with an uptime
+// proof while its validator is active
+func initiateUndelegation(manager *stakingManager, privateKey string, delegation clivalidator.Delegation) (*types.Receipt, error) {
+	method := "initiateDelegatorRemoval(bytes32,bool,uint32)"
+	if forceUndelegation {
+		method = "forceInitiateDelegatorRemoval(bytes32,bool,uint32)"
+	}
+	validators, err := validator.GetCurrentValidators(manager.network, manager.chainID)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		tx      *types.Transaction
+		receipt *types.Receipt
+	)
+	active := false
+	for _, v := range validators {
+		if v.ValidationID != delegation.ValidationID {
+			continue
+		}
+		active = true
+		uptimeSec, err := sdkvalidatormanager.GetL1ValidatorUptimeSeconds(manager.rpcURL, v.NodeID)
+		if err != nil {
+			return nil, fmt.Errorf("failure getting the uptime of node %s via %s: %w", v.NodeID, manager.rpcURL, err)
+		}
+		ux.Logger.PrintToUser("Using uptime of node %s: %ds", v.NodeID, uptimeSec)
+		var uptimeProof *warp.Message
+		if err := manager.watchAggregation(manager.network, func() error {
+			var err error
+			uptimeProof, err = sdkvalidatormanager.GetUptimeProofMessage(
+				manager.network,
+				manager.aggregatorLogger,
+				0,
+				manager.chainID,
+				manager.blockchainID,
+				delegation.ValidationID,
+				uptimeSec,
+				manager.aggregatorEndpoint,
+			)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+		tx, receipt, err = contract.TxToMethodWithWarpMessage(
+			manager.rpcURL,
+			false,
+			crypto.Address{},
+			privateKey,
+			crypto.HexToAddress(manager.address),
+			uptimeProof,
+			big.NewInt(0),
+			"initiate delegator removal with uptime proof",
+			sdkvalidatormanager.ErrorSignatureToError,
+			method,
+			delegation.DelegationID,
+			true,
+			uint32(0),
+		)
+		if err != nil {
+			return nil, evm.TransactionError(tx, err, "failure initiating the removal of delegation %s", delegation.DelegationID)
+		}
+	}
+	if !active {
+		tx, receipt, err = contract.TxToMethod(
+			manager.rpcURL,
+			false,
+			crypto.Address{},
+			privateKey,
+			crypto.HexToAddress(manager.address),
+			big.NewInt(0),
+			"initiate delegator removal",
+			sdkvalidatormanager.ErrorSignatureToError,
+			method,
+			delegation.DelegationID,
+			false,
+			uint32(0),
+		)
+		if err != nil {
+			return nil, evm.TransactionError(tx, err, "failure initiating the removal of delegation %s", delegation.DelegationID)
+		}
+	}
+	ux.Logger.PrintToUser("Removal of delegation %s initiated. InitiateTxHash: %s", delegation.DelegationID, tx.Hash())
+	return receipt, nil
+}
+
+func recordUndelegation(network models.Network, l1Name string, delegation clivalidator.Delegation) {
+	app.RecordHistory(history.Entry{
+		Operation: "validator undelegate",
+		Network:   network.String(),
+		Chain:     l1Name,
+		Params: map[string]string{
+			"validationID": delegation.ValidationID.String(),
+			"delegationID": delegation.DelegationID.String(),
+			"delegator":    delegation.Owner.Hex(),
+		},
+	})
+}
+
+// selectDelegation returns the delegation of --delegation-id, or prompts for
+// one of the delegations of delegator
+func selectDelegation(ctx context.Context, manager *stakingManager, delegator common.Address) (clivalidator.Delegation, error) {
+	if delegationIDStr != "" {
+		delegationID, err := ids.FromString(delegationIDStr)
+		if err != nil {
+			return clivalidator.Delegation{}, fmt.Errorf("invalid delegation ID %q: %w", delegationIDStr, err)
+		}
+		delegation, err := clivalidator.GetDelegation(ctx, manager.client, manager.contractAddress(), delegationID)
+		if err != nil {
+			return clivalidator.Delegation{}, err
+		}
+		if delegation.Owner != delegator {
+			return clivalidator.Delegation{}, fmt.Errorf("delegation %s is owned by %s, not by the delegator key %s", delegationID, delegation.Owner.Hex(), delegator.Hex())
+		}
+		return delegation, nil
+	}
+	delegations, err := clivalidator.GetDelegations(ctx, manager.client, manager.contractAddress(), delegator)
+	if err != nil {
+		return clivalidator.Delegation{}, err
+	}
+	switch len(delegations) {
+	case 0:
+		return clivalidator.Delegation{}, fmt.Errorf("%s has no delegation on this L1", delegator.Hex())
+	case 1:
+		return delegations[0], nil
+	}
+	options := make([]string, 0, len(delegations))
+	for _, d := range delegations {
+		options = append(options, d.DelegationID.String())
+	}
+	selected, err := app.Prompt.CaptureList("Choose the delegation", options)
+	if err != nil {
+		return clivalidator.Delegation{}, err
+	}
+	for _, d := range delegations {
+		if d.DelegationID.String() == selected {
+			return d, nil
+		}
+	}
+	return clivalidator.Delegation{}, fmt.Errorf("no delegation %s", selected)
+}
+
+// selectL1 returns l1, or prompts for a sovereign L1. It returns "" when the
+// user cancels.
+func selectL1(network models.Network, l1 string) (string, error) {
+	if l1 != "" {
+		return l1, nil
+	}
+	chainSpec := sdkcontract.ChainSpec{}
+	chainSpec.SetEnabled(
+		true,  // prompt blockchain name
+		false, // do not prompt for PChain
+		false, // do not prompt for XChain
+		false, // do not prompt for CChain
+		false, // do not prompt blockchain ID
+	)
+	chainSpec.OnlySOV = true
+	cancel, err := sdkcontract.PromptChain(app.GetSDKApp(), network, "Choose the L1", "", &chainSpec)
+	if err != nil || cancel {
+		return "", err
+	}
+	return chainSpec.BlockchainName, nil
+}
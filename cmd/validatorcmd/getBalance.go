@@ -117,72 +117,9 @@ func getNodeValidationID(
 	}
 	switch option {
 	case l1ListOption:
-		chainSpec := contract.ChainSpec{
-			BlockchainName: l1,
-		}
-		chainSpec.SetEnabled(
-			true,  // prompt blockchain name
-			false, // do not prompt for PChain
-			false, // do not prompt for XChain
-			false, // do not prompt for CChain
-			true,  // prompt blockchain ID
-		)
-		chainSpec.OnlySOV = true
-		if l1 == "" {
-			if cancel, err := contract.PromptChain(
-				app.GetSDKApp(),
-				network,
-				"Choose the L1",
-				"",
-				&chainSpec,
-			); err != nil {
-				return ids.Empty, false, err
-			} else if cancel {
-				return ids.Empty, true, nil
-			}
-			l1 = chainSpec.BlockchainName
-		}
-		sc, err := app.LoadSidecar(l1)
-		if err != nil {
-			return ids.Empty, false, fmt.Errorf("failed to load sidecar: %w", err)
-		}
-		if !sc.Sovereign {
-			return ids.Empty, false, fmt.Errorf("lux validator commands are only applicable to sovereign L1s")
-		}
-		if sc.Networks[network.Name()].ValidatorManagerAddress == "" {
-			return ids.Empty, false, fmt.Errorf("unable to find Validator Manager address")
-		}
-		chainID, err := contract.GetNetworkID(app.GetSDKApp(), network, chainSpec)
-		if err != nil {
-			return ids.Empty, false, err
-		}
-		validators, err := validator.GetCurrentValidators(network, chainID)
-		if err != nil {
-			return ids.Empty, false, err
-		}
-		if len(validators) == 0 {
-			return ids.Empty, false, fmt.Errorf("l1 has no validators")
-		}
-		if nodeIDStr == "" {
-			nodeIDStrs := sdkutils.Map(validators, func(v validator.CurrentValidatorInfo) string { return v.NodeID.String() })
-			sort.Strings(nodeIDStrs)
-			// CaptureListWithSize returns a slice, we want the first (and only) element
-			selected, err := app.Prompt.CaptureListWithSize("Choose Node ID of the validator", nodeIDStrs, 1)
-			if err != nil {
-				return ids.Empty, false, err
-			}
-			if len(selected) == 0 {
-				return ids.Empty, false, fmt.Errorf("no node ID selected")
-			}
-			nodeIDStr = selected[0]
-		}
-		nodeID, err := ids.NodeIDFromString(nodeIDStr)
-		if err != nil {
-			return ids.Empty, false, err
-		}
-		found := utils.Find(validators, func(v validator.CurrentValidatorInfo) bool { return v.NodeID == nodeID })
-		if found == nil {
-			return ids.Empty, false, fmt.Errorf("node %s not found among L1 validators", nodeID.String())
+		_, found, cancel, err := selectL1Validator(network, l1, nodeIDStr)
+		if err != nil || cancel {
+			return ids.Empty, cancel, err
 		}
 		validationID = found.ValidationID
 	case validationIDOption:
@@ -195,3 +132,81 @@ func getNodeValidationID(
 	}
 	return validationID, false, nil
 }
+
+// selectL1Validator returns the L1 and the validator of nodeIDStr on it,
+// prompting for the ones not given. It also returns a boolean that is true if
+// the user cancels operations during prompting
+func selectL1Validator(
+	network models.Network,
+	l1 string,
+	nodeIDStr string,
+) (string, *validator.CurrentValidatorInfo, bool, error) {
+	chainSpec := contract.ChainSpec{
+		BlockchainName: l1,
+	}
+	chainSpec.SetEnabled(
+		true,  // prompt blockchain name
+		false, // do not prompt for PChain
+		false, // do not prompt for XChain
+		false, // do not prompt for CChain
+		true,  // prompt blockchain ID
+	)
+	chainSpec.OnlySOV = true
+	if l1 == "" {
+		if cancel, err := contract.PromptChain(
+			app.GetSDKApp(),
+			network,
+			"Choose the L1",
+			"",
+			&chainSpec,
+		); err != nil {
+			return "", nil, false, err
+		} else if cancel {
+			return "", nil, true, nil
+		}
+		l1 = chainSpec.BlockchainName
+	}
+	sc, err := app.LoadSidecar(l1)
+	if err != nil {
+		return "", nil, false, fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	if !sc.Sovereign {
+		return "", nil, false, fmt.Errorf("lux validator commands are only applicable to sovereign L1s")
+	}
+	if sc.Networks[network.Name()].ValidatorManagerAddress == "" {
+		return "", nil, false, fmt.Errorf("unable to find Validator Manager address")
+	}
+	chainID, err := contract.GetNetworkID(app.GetSDKApp(), network, chainSpec)
+	if err != nil {
+		return "", nil, false, err
+	}
+	validators, err := validator.GetCurrentValidators(network, chainID)
+	if err != nil {
+		return "", nil, false, err
+	}
+	if len(validators) == 0 {
+		return "", nil, false, fmt.Errorf("l1 has no validators")
+	}
+	if nodeIDStr == "" {
+		nodeIDStrs := sdkutils.Map(validators, func(v validator.CurrentValidatorInfo) string { return v.NodeID.String() })
+		sort.Strings(nodeIDStrs)
+		// CaptureListWithSize returns a slice, we want the first (and only) element
+		selected, err := app.Prompt.CaptureListWithSize("Choose Node ID of the validator", nodeIDStrs, 1)
+		if err != nil {
+			return "", nil, false, err
+		}
+		if len(selected) == 0 {
+			return "", nil, false, fmt.Errorf("no node ID selected")
+		}
+		nodeIDStr = selected[0]
+	}
+	nodeID, err := ids.NodeIDFromString(nodeIDStr)
+	if err != nil {
+		return "", nil, false, err
+	}
+	found := utils.Find(validators, func(v validator.CurrentValidatorInfo) bool { return v.NodeID == nodeID })
+	if found == nil {
+		return "", nil, false, fmt.Errorf("node %s not found among L1 validators", nodeID.String())
+	}
+	return l1, found, false, nil
+}
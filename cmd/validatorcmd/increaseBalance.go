@@ -10,6 +10,8 @@ import (
 	"github.com/luxfi/cli/pkg/blockchain"
 	"github.com/luxfi/cli/pkg/chain"
 	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/feeestimate"
+	"github.com/luxfi/cli/pkg/history"
 	"github.com/luxfi/cli/pkg/keychain"
 	"github.com/luxfi/cli/pkg/networkoptions"
 	"github.com/luxfi/cli/pkg/utils"
@@ -31,11 +33,12 @@ var (
 
 func NewIncreaseBalanceCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "increaseBalance",
-		Short: "Increases current balance of validator on P-Chain",
-		Long:  `This command increases the validator P-Chain balance`,
-		RunE:  increaseBalance,
-		Args:  cobrautils.ExactArgs(0),
+		Use:     "increaseBalance",
+		Aliases: []string{"increase-balance"},
+		Short:   "Increases current balance of validator on P-Chain",
+		Long:    `This command increases the validator P-Chain balance with an IncreaseL1ValidatorBalanceTx`,
+		RunE:    increaseBalance,
+		Args:    cobrautils.ExactArgs(0),
 	}
 
 	// Network flags handled at higher level to avoid conflicts
@@ -72,8 +75,10 @@ func increaseBalance(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("the specified node is not a L1 validator")
 	}
 
-	// Estimate fee based on network and transaction complexity
-	fee := estimateIncreaseBalanceFee(network)
+	fee, err := l1ValidatorTxFee(network)
+	if err != nil {
+		return err
+	}
 	kc, err := keychain.GetKeychainFromCmdLineFlags(
 		app,
 		constants.PayTxsFeesMsg,
@@ -112,6 +117,15 @@ func increaseBalance(_ *cobra.Command, _ []string) error {
 	if err := deployer.IncreaseValidatorPChainBalance(validationID, balance); err != nil {
 		return fmt.Errorf("failed to increase validator balance: %w", err)
 	}
+	app.RecordHistory(history.Entry{
+		Operation: "validator increase-balance",
+		Network:   network.String(),
+		Chain:     l1,
+		Params: map[string]string{
+			"validationID": validationID.String(),
+			"amount":       fmt.Sprintf("%.9f LUX", float64(balance)/float64(constants.Lux)),
+		},
+	})
 
 	// add a delay to safely retrieve updated balance (to avoid issues when connecting to a different API node)
 	time.Sleep(5 * time.Second)
@@ -125,20 +139,12 @@ func increaseBalance(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
-// estimateIncreaseBalanceFee estimates the transaction fee for increasing validator balance
-func estimateIncreaseBalanceFee(network models.Network) uint64 {
-	// Base fee in nLUX (1 LUX = 1e9 nLUX)
-	const baseFee = 1_000_000 // 0.001 LUX base fee
-
-	// Adjust fee based on network
-	switch network {
-	case models.Mainnet:
-		return baseFee * 2 // Higher fee for mainnet
-	case models.Testnet:
-		return baseFee // Standard fee for testnet
-	case models.Local:
-		return 0 // No fee for local network
-	default:
-		return baseFee
+// l1ValidatorTxFee returns the fee of the P-Chain L1 validator txs at the
+// current fee state of network
+func l1ValidatorTxFee(network models.Network) (uint64, error) {
+	state, err := feeestimate.ForNetwork(network)
+	if err != nil {
+		return 0, err
 	}
+	return state.L1ValidatorTxFee()
 }
@@ -0,0 +1,503 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorcmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/cmd/flags"
+	"github.com/luxfi/cli/pkg/chain"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/dependencies"
+	"github.com/luxfi/cli/pkg/feeestimate"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/keychain"
+	"github.com/luxfi/cli/pkg/networkoptions"
+	"github.com/luxfi/cli/pkg/onboarding"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+	sdkvalidatormanager "github.com/luxfi/sdk/validatormanager"
+	warpMessage "github.com/luxfi/sdk/validatormanager/warp"
+	"github.com/luxfi/warp"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// invitesFileName is the file of an L1 recording the invites issued for
+	// it, by ID
+	invitesFileName = "invites.json"
+
+	// registrationExpiry is how long the RegisterL1Validator message of an
+	// accepted node is valid for the P-Chain
+	registrationExpiry = 24 * time.Hour
+)
+
+var (
+	inviteNodeCount     int
+	inviteWeight        uint64
+	inviteBalance       float64
+	inviteExpires       time.Duration
+	inviteLuxdVersion   string
+	inviteBootstrapIPs  []string
+	inviteBootstrapIDs  []string
+	inviteOutput        string
+	acceptDelegationFee uint16
+	acceptStakeDuration time.Duration
+)
+
+// lux validator invite
+func NewInviteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "invite <l1>",
+		Short: "Generates an onboarding bundle for external validator operators",
+		Long: `This command generates a self-contained bundle inviting a third-party
+operator to run validators of an L1, to hand over to them:
+
+  INVITE.json         the L1, its chain, blockchain and VM IDs, the expected
+                      luxd and VM versions and the bootstrap peers
+  README.md           the instructions to set up and register the nodes
+  node.json           the luxd config tracking the L1
+  chains/<id>/        the chain config and upgrades, for --chain-config-dir
+  genesis.json        the genesis of the chain
+  registration.json   the template the operator fills with the NodeID and BLS
+                      proof of possession of each node, and returns
+
+The invite registers up to --node-count nodes with --weight and a P-Chain
+balance of --balance LUX each, until it --expires. It is recorded on this
+machine, for 'lux validator accept' to register the nodes of the filled
+registration.json.
+
+The expected luxd version is the latest supporting the RPC protocol of the
+chain, unless given with --luxd-version. The nodes bootstrap from the
+--bootstrap-ips and --bootstrap-ids peers, usually the current validators,
+and otherwise from the default bootstrappers of the network.
+
+EXAMPLES:
+
+  lux validator invite mychain --node-count 3 --testnet \
+    --bootstrap-ips 203.0.113.1:9631 --bootstrap-ids NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg`,
+		RunE: invite,
+		Args: cobrautils.ExactArgs(1),
+	}
+
+	// Network flags handled at higher level to avoid conflicts
+	cmd.Flags().IntVar(&inviteNodeCount, "node-count", 1, "number of nodes the operator may register")
+	cmd.Flags().Uint64Var(&inviteWeight, "weight", constants.DefaultStakeWeight, "weight of each node")
+	cmd.Flags().Float64Var(&inviteBalance, "balance", 0.1, "P-Chain balance of each node, in LUX, paying its continuous fee")
+	cmd.Flags().DurationVar(&inviteExpires, "expires", 7*24*time.Hour, "how long the invite can be accepted")
+	cmd.Flags().StringVar(&inviteLuxdVersion, "luxd-version", "", "luxd version the nodes run (default the latest for the chain's RPC protocol)")
+	cmd.Flags().StringSliceVar(&inviteBootstrapIPs, "bootstrap-ips", nil, "IP:port of the peers the nodes bootstrap from")
+	cmd.Flags().StringSliceVar(&inviteBootstrapIDs, "bootstrap-ids", nil, "NodeIDs of the --bootstrap-ips peers")
+	cmd.Flags().StringVarP(&inviteOutput, "output", "o", "", "file to write the bundle to (default <l1>-invite-<id>.tar.gz)")
+	return cmd
+}
+
+// lux validator accept
+func NewAcceptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "accept <registration>",
+		Short: "Registers the validators an invited operator returned",
+		Long: `This command registers as L1 validators the nodes of a registration returned
+by an operator invited with 'lux validator invite': the filled
+registration.json, the bundle directory holding it, or a bundle archive.
+
+The registration must answer an unexpired invite of this machine, for at
+most its remaining nodes, and the proof of possession of each BLS key is
+checked. Each node is then registered as in the validator manager flows:
+the registration is initiated on the L1 Validator Manager contract, the
+resulting warp message signed by the L1 validators is registered on P-Chain
+with a RegisterL1ValidatorTx paying the node balance, and the registration
+is completed on the contract with the P-Chain acknowledgement. The balance
+and the P-Chain fees are paid with --key, whose address owns the remaining
+balance of the nodes.
+
+Proof of Stake L1s stake the weight of each node from the Validator Manager
+owner, for --stake-duration.
+
+EXAMPLES:
+
+  lux validator accept ./registration.json --key ops`,
+		RunE: accept,
+		Args: cobrautils.ExactArgs(1),
+	}
+
+	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to pay the balance and the P-Chain fees with [testnet/devnet only]")
+	cmd.Flags().Uint16Var(&acceptDelegationFee, "delegation-fee", 100, "delegation fee of Proof of Stake nodes, in basis points (100 is 1%)")
+	cmd.Flags().DurationVar(&acceptStakeDuration, "stake-duration", 0, "stake duration of Proof of Stake nodes")
+	flags.AddRPCFlagToCmd(cmd, app, &validatorManagerRPC)
+	sigAggGroup := flags.AddSignatureAggregatorFlagsToCmd(cmd, &sigAggFlags)
+	cmd.SetHelpFunc(flags.WithGroupedHelp([]flags.GroupedFlags{sigAggGroup}))
+	return cmd
+}
+
+func invite(_ *cobra.Command, args []string) error {
+	l1Name := args[0]
+	if inviteNodeCount < 1 {
+		return errors.New("--node-count must be at least 1")
+	}
+	if inviteWeight == 0 {
+		return errors.New("--weight must be positive")
+	}
+	if inviteExpires <= 0 {
+		return errors.New("--expires must be positive")
+	}
+	if len(inviteBootstrapIPs) != len(inviteBootstrapIDs) {
+		return errors.New("--bootstrap-ips and --bootstrap-ids must list the same peers")
+	}
+	for _, nodeID := range inviteBootstrapIDs {
+		if _, err := ids.NodeIDFromString(nodeID); err != nil {
+			return fmt.Errorf("invalid bootstrap ID %q: %w", nodeID, err)
+		}
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		globalNetworkFlags,
+		true,
+		false,
+		networkoptions.DefaultSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	sc, err := app.LoadSidecar(l1Name)
+	if err != nil {
+		return fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	if !sc.Sovereign {
+		return fmt.Errorf("lux validator commands are only applicable to sovereign L1s")
+	}
+	networkData := sc.Networks[network.Name()]
+	if networkData.BlockchainID == ids.Empty {
+		return fmt.Errorf("%s is not deployed to %s", l1Name, network.Name())
+	}
+	if networkData.ValidatorManagerAddress == "" {
+		return fmt.Errorf("unable to find Validator Manager address")
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	inv := &onboarding.Invite{
+		ID:           hex.EncodeToString(id),
+		L1:           l1Name,
+		Network:      network.Name(),
+		NetworkID:    network.ID(),
+		ChainID:      networkData.ChainID.String(),
+		BlockchainID: networkData.BlockchainID.String(),
+		VMID:         inviteVMID(sc),
+		VMVersion:    sc.VMVersion,
+		LuxdVersion:  inviteLuxdVersion,
+		RPCVersion:   sc.RPCVersion,
+		BootstrapIPs: inviteBootstrapIPs,
+		BootstrapIDs: inviteBootstrapIDs,
+		NodeCount:    inviteNodeCount,
+		Weight:       inviteWeight,
+		Balance:      uint64(inviteBalance * float64(constants.Lux)),
+		Created:      now,
+		Expires:      now.Add(inviteExpires),
+	}
+	if inv.LuxdVersion == "" && sc.RPCVersion != 0 {
+		if inv.LuxdVersion, err = dependencies.GetLatestLuxdByProtocolVersion(app, sc.RPCVersion); err != nil {
+			ux.Logger.PrintToUser("Failed to look up the luxd version for RPC protocol %d, the operator is told to use one supporting it: %v", sc.RPCVersion, err)
+		}
+	}
+
+	files := map[string][]byte{}
+	if app.GenesisExists(l1Name) {
+		if files[constants.GenesisFileName], err = app.LoadRawGenesis(l1Name); err != nil {
+			return fmt.Errorf("failed to load genesis: %w", err)
+		}
+	}
+	chainDir := filepath.ToSlash(filepath.Join(onboarding.ChainsDir, inv.BlockchainID))
+	if app.LuxdChainConfigExists(l1Name) {
+		if files[chainDir+"/"+constants.ChainConfigFile], err = app.LoadRawLuxdChainConfig(l1Name); err != nil {
+			return fmt.Errorf("failed to load chain config: %w", err)
+		}
+	}
+	if app.NetworkUpgradeExists(l1Name) {
+		if files[chainDir+"/upgrade.json"], err = app.LoadRawNetworkUpgrades(l1Name); err != nil {
+			return fmt.Errorf("failed to load network upgrades: %w", err)
+		}
+	}
+	bundle, err := onboarding.Create(inv, files)
+	if err != nil {
+		return err
+	}
+	output := inviteOutput
+	if output == "" {
+		output = fmt.Sprintf("%s-invite-%s.tar.gz", l1Name, inv.ID)
+	}
+	if err := os.WriteFile(output, bundle, constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	invites, err := loadInvites(l1Name)
+	if err != nil {
+		return err
+	}
+	invites[inv.ID] = inv
+	if err := saveInvites(l1Name, invites); err != nil {
+		return err
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "validator invite",
+		Network:   network.String(),
+		Chain:     l1Name,
+		Params: map[string]string{
+			"invite":    inv.ID,
+			"nodeCount": fmt.Sprintf("%d", inv.NodeCount),
+			"weight":    fmt.Sprintf("%d", inv.Weight),
+		},
+	})
+
+	ux.Logger.GreenCheckmarkToUser("Invite %s for %d node(s) of %s written to %s", inv.ID, inv.NodeCount, l1Name, output)
+	ux.Logger.PrintToUser("  luxd:    %s", valueOr(inv.LuxdVersion, fmt.Sprintf("supporting RPC protocol %d", inv.RPCVersion)))
+	ux.Logger.PrintToUser("  expires: %s", inv.Expires.Format(time.RFC3339))
+	if len(inv.BootstrapIPs) == 0 && network != models.Mainnet && network != models.Testnet {
+		ux.Logger.PrintToUser("No bootstrap peers given: the nodes can't find the %s nodes, give them with --bootstrap-ips and --bootstrap-ids", network.Name())
+	}
+	ux.Logger.PrintToUser("Hand the bundle to the operator, and register the nodes they return with 'lux validator accept <registration>'")
+	return nil
+}
+
+func accept(_ *cobra.Command, args []string) error {
+	reg, err := onboarding.ReadRegistration(args[0])
+	if err != nil {
+		return err
+	}
+	invites, err := loadInvites(reg.L1)
+	if err != nil {
+		return err
+	}
+	inv, ok := invites[reg.Invite]
+	if !ok {
+		return fmt.Errorf("%w: invite %s of %s was not issued from this machine", onboarding.ErrInvalidRegistration, reg.Invite, reg.L1)
+	}
+	validators, err := reg.Validators(inv, time.Now())
+	if err != nil {
+		return err
+	}
+	network := models.GetNetworkFromSidecarNetworkName(inv.Network)
+	if network == models.Undefined {
+		return fmt.Errorf("unsupported network %q", inv.Network)
+	}
+	manager, err := loadL1ValidatorManager(network, inv.L1)
+	if err != nil {
+		return err
+	}
+	if manager.pos && acceptStakeDuration <= 0 {
+		return errors.New("--stake-duration is required to register Proof of Stake validators")
+	}
+	feeState, err := feeestimate.ForNetwork(network)
+	if err != nil {
+		return err
+	}
+	estimate, err := feeState.Estimate(feeestimate.AddValidator, feeestimate.Options{Validators: len(validators), Balance: inv.Balance})
+	if err != nil {
+		return err
+	}
+	kc, err := keychain.GetKeychainFromCmdLineFlags(
+		app,
+		constants.PayTxsFeesMsg,
+		network,
+		keyName,
+		useLocalKey,
+		useLedger,
+		ledgerAddresses,
+		estimate.Required(),
+	)
+	if err != nil {
+		return err
+	}
+	addresses := kc.Addresses().List()
+	if len(addresses) == 0 {
+		return fmt.Errorf("no addresses available in keychain")
+	}
+	owner := warpMessage.PChainOwner{Threshold: 1, Addresses: addresses[:1]}
+	deployer := chain.NewPublicDeployer(app, useLedger, kc.Keychain, network)
+
+	operator := valueOr(reg.Operator, "the operator")
+	ux.Logger.PrintToUser("Registering %d node(s) of %s on %s for invite %s", len(validators), operator, inv.L1, inv.ID)
+	ctx := context.Background()
+	for _, v := range validators {
+		if err := registerInvitedValidator(ctx, network, manager, deployer, inv, v, owner); err != nil {
+			return fmt.Errorf("failed to register node %s: %w", v.NodeID, err)
+		}
+		inv.Accepted = append(inv.Accepted, v.NodeID.String())
+		if err := saveInvites(inv.L1, invites); err != nil {
+			return err
+		}
+		if err := recordL1Validator(network, inv.L1, v.NodeID); err != nil {
+			return err
+		}
+		app.RecordHistory(history.Entry{
+			Operation: "validator accept",
+			Network:   network.String(),
+			Chain:     inv.L1,
+			Params: map[string]string{
+				"invite":   inv.ID,
+				"operator": reg.Operator,
+				"nodeID":   v.NodeID.String(),
+				"weight":   fmt.Sprintf("%d", inv.Weight),
+			},
+		})
+		ux.Logger.GreenCheckmarkToUser("Node %s is a validator of %s", v.NodeID, inv.L1)
+	}
+	if inv.Remaining() > 0 {
+		ux.Logger.PrintToUser("Invite %s registers %d more node(s) until %s", inv.ID, inv.Remaining(), inv.Expires.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// registerInvitedValidator registers v as a validator of the L1 of inv,
+// with the weight and balance of the invite
+func registerInvitedValidator(
+	ctx context.Context,
+	network models.Network,
+	manager *l1ValidatorManager,
+	deployer *chain.PublicDeployer,
+	inv *onboarding.Invite,
+	v onboarding.Validator,
+	owner warpMessage.PChainOwner,
+) error {
+	ux.Logger.PrintToUser("Registering node %s with weight %d", v.NodeID, inv.Weight)
+	var (
+		signedMessage *warp.Message
+		validationID  ids.ID
+	)
+	err := manager.watchAggregation(network, func() error {
+		var err error
+		signedMessage, validationID, _, err = sdkvalidatormanager.InitValidatorRegistration(
+			ctx,
+			app.GetSDKApp(),
+			network,
+			manager.rpcURL,
+			manager.chainSpec,
+			false,
+			manager.ownerAddress,
+			manager.ownerPrivateKey,
+			v.NodeID,
+			v.Signer.PublicKey[:],
+			uint64(time.Now().Add(registrationExpiry).Unix()),
+			owner,
+			owner,
+			inv.Weight,
+			manager.aggregatorLogger,
+			manager.pos,
+			acceptDelegationFee,
+			acceptStakeDuration,
+			crypto.HexToAddress(manager.ownerAddress),
+			manager.address,
+			manager.useACP99,
+			"",
+			manager.aggregatorEndpoint,
+		)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	txID, err := deployer.RegisterL1Validator(inv.Balance, v.Signer.ProofOfPossession, signedMessage.Bytes())
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("RegisterL1ValidatorTx ID: %s", txID)
+	return manager.watchAggregation(network, func() error {
+		_, err := sdkvalidatormanager.FinishValidatorRegistration(
+			ctx,
+			app.GetSDKApp(),
+			network,
+			manager.rpcURL,
+			manager.chainSpec,
+			false,
+			manager.ownerAddress,
+			manager.ownerPrivateKey,
+			validationID,
+			manager.aggregatorLogger,
+			manager.address,
+			manager.aggregatorEndpoint,
+		)
+		return err
+	})
+}
+
+// recordL1Validator adds nodeID to the validators of l1 on network in its
+// sidecar
+func recordL1Validator(network models.Network, l1 string, nodeID ids.NodeID) error {
+	sc, err := app.LoadSidecar(l1)
+	if err != nil {
+		return err
+	}
+	networkData := sc.Networks[network.Name()]
+	networkData.ValidatorIDs = append(networkData.ValidatorIDs, nodeID.String())
+	sc.Networks[network.Name()] = networkData
+	return app.UpdateSidecar(&sc)
+}
+
+// inviteVMID returns the ID of the VM the nodes of sc run
+func inviteVMID(sc models.Sidecar) string {
+	if vmID, _ := sc.GetVMID(); vmID != "" {
+		return vmID
+	}
+	// the VMID is computed from the VM name, the chain name for custom VMs
+	vmName := string(models.EVM)
+	if sc.VM == models.CustomVM {
+		vmName = sc.Name
+	}
+	vmID, err := utils.VMID(vmName)
+	if err != nil {
+		return ""
+	}
+	return vmID.String()
+}
+
+func valueOr(value, fallback string) string {
+	if strings.TrimSpace(value) == "" {
+		return fallback
+	}
+	return value
+}
+
+func invitesPath(l1 string) string {
+	return filepath.Join(app.GetChainsDir(), l1, invitesFileName)
+}
+
+func loadInvites(l1 string) (map[string]*onboarding.Invite, error) {
+	invites := map[string]*onboarding.Invite{}
+	raw, err := os.ReadFile(invitesPath(l1)) //nolint:gosec // G304: Reading from app's data directory
+	if errors.Is(err, os.ErrNotExist) {
+		return invites, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &invites); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", invitesPath(l1), err)
+	}
+	return invites, nil
+}
+
+func saveInvites(l1 string, invites map[string]*onboarding.Invite) error {
+	raw, err := json.MarshalIndent(invites, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(invitesPath(l1), raw, constants.WriteReadReadPerms)
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorcmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/luxfi/cli/cmd/flags"
+	"github.com/luxfi/cli/pkg/blockchain"
+	"github.com/luxfi/cli/pkg/signatureaggregator"
+	luxlog "github.com/luxfi/log"
+	"github.com/luxfi/sdk/contract"
+	"github.com/luxfi/sdk/models"
+	"github.com/luxfi/sdk/prompts"
+)
+
+var (
+	validatorManagerRPC string
+	initiateTxHash      string
+	sigAggFlags         flags.SignatureAggregatorFlags
+)
+
+// l1ValidatorManager is the validator manager contract of an L1 deployed to
+// a network, with what its weight change and removal flows need
+type l1ValidatorManager struct {
+	chainSpec          contract.ChainSpec
+	rpcURL             string
+	address            string
+	ownerAddress       string
+	ownerPrivateKey    string
+	pos                bool
+	useACP99           bool
+	aggregatorLogger   luxlog.Logger
+	aggregatorEndpoint string
+	aggregatorTimeout  time.Duration
+}
+
+// loadL1ValidatorManager finds the validator manager of l1 on network, the
+// key of its owner, and the signature aggregator of the network
+func loadL1ValidatorManager(network models.Network, l1 string) (*l1ValidatorManager, error) {
+	manager, err := newL1ValidatorManager(network, l1)
+	if err != nil {
+		return nil, err
+	}
+	if err := manager.startAggregator(network); err != nil {
+		return nil, err
+	}
+	found, _, _, ownerPrivateKey, err := contract.SearchForManagedKey(
+		app.GetSDKApp(),
+		network,
+		manager.ownerAddress,
+		true,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		ownerPrivateKey, err = prompts.PromptPrivateKey(
+			app.Prompt,
+			fmt.Sprintf("sign the Validator Manager transactions as its owner %s?", manager.ownerAddress),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	manager.ownerPrivateKey = ownerPrivateKey
+	return manager, nil
+}
+
+// newL1ValidatorManager finds the validator manager of l1 on network, for
+// flows signed by others than its owner
+func newL1ValidatorManager(network models.Network, l1 string) (*l1ValidatorManager, error) {
+	sc, err := app.LoadSidecar(l1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	manager := &l1ValidatorManager{
+		chainSpec:    contract.ChainSpec{BlockchainName: l1},
+		rpcURL:       validatorManagerRPC,
+		address:      sc.Networks[network.Name()].ValidatorManagerAddress,
+		ownerAddress: sc.ValidatorManagerOwner,
+		pos:          sc.PoS,
+		useACP99:     sc.UseACP99,
+	}
+	if manager.address == "" {
+		return nil, fmt.Errorf("unable to find Validator Manager address")
+	}
+	if manager.rpcURL == "" {
+		manager.rpcURL, _, err = contract.GetBlockchainEndpoints(
+			app.GetSDKApp(),
+			network,
+			manager.chainSpec,
+			true,
+			false,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return manager, nil
+}
+
+// startAggregator sets up the signature aggregator of network to sign the
+// warp messages of the validator manager
+func (m *l1ValidatorManager) startAggregator(network models.Network) error {
+	clusterName := network.Name()
+	aggregatorPeerURIs, err := blockchain.GetAggregatorNetworkUris(app, clusterName)
+	if err != nil {
+		return err
+	}
+	aggregatorPeerURIs = append(aggregatorPeerURIs, sigAggFlags.AggregatorExtraEndpoints...)
+	m.aggregatorLogger, err = signatureaggregator.NewSignatureAggregatorLogger(
+		sigAggFlags.AggregatorLogLevel,
+		sigAggFlags.AggregatorLogToStdout,
+		app.GetAggregatorLogDir(clusterName),
+	)
+	if err != nil {
+		return err
+	}
+	chainID, err := contract.GetNetworkID(app.GetSDKApp(), network, m.chainSpec)
+	if err != nil {
+		return err
+	}
+	extraPeers := make([]interface{}, len(aggregatorPeerURIs))
+	for i, p := range aggregatorPeerURIs {
+		extraPeers[i] = p
+	}
+	if err := signatureaggregator.CreateSignatureAggregatorInstance(app, chainID.String(), network, extraPeers, m.aggregatorLogger, "latest"); err != nil {
+		return err
+	}
+	if err := signatureaggregator.UpdateSignatureAggregatorPeers(app, network, aggregatorPeerURIs, m.aggregatorLogger); err != nil {
+		return err
+	}
+	m.aggregatorTimeout = sigAggFlags.AggregatorTimeout
+	m.aggregatorEndpoint, err = signatureaggregator.GetSignatureAggregatorEndpoint(app, network)
+	return err
+}
+
+// watchAggregation runs fn, which has the signature aggregator of network
+// sign, printing its progress and failing after the aggregator timeout
+func (m *l1ValidatorManager) watchAggregation(network models.Network, fn func() error) error {
+	return signatureaggregator.WatchAggregation(app, network, m.aggregatorTimeout, fn)
+}
@@ -0,0 +1,235 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorcmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/luxfi/cli/cmd/flags"
+	"github.com/luxfi/cli/pkg/chain"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/keychain"
+	"github.com/luxfi/cli/pkg/networkoptions"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/ids"
+	sdkvalidatormanager "github.com/luxfi/sdk/validatormanager"
+	"github.com/luxfi/warp"
+	"github.com/spf13/cobra"
+)
+
+var (
+	uptimeSec    uint64
+	forceRemoval bool
+	disableOnly  bool
+)
+
+func NewRemoveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Removes a validator from an L1",
+		Long: `This command removes a validator from an L1.
+
+It initiates the removal on the L1 Validator Manager contract (with an uptime
+proof for Proof of Stake validators), gets the resulting warp message signed
+by the L1 validators, sets the validator weight to 0 on P-Chain with a
+SetL1ValidatorWeightTx, and completes the removal on the contract with the
+P-Chain acknowledgement. An interrupted removal resumes from the contract
+state, or from the --initiate-tx-hash of its first step.
+
+With --disable the validator is only disabled on P-Chain with a
+DisableL1ValidatorTx, signed by its deactivation owner, which returns its
+remaining balance without going through the Validator Manager contract.`,
+		RunE: removeValidator,
+		Args: cobrautils.ExactArgs(0),
+	}
+
+	// Network flags handled at higher level to avoid conflicts
+	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to pay the P-Chain fee with [testnet/devnet only]")
+	cmd.Flags().StringVar(&l1, "l1", "", "name of L1")
+	cmd.Flags().StringVar(&nodeIDStr, "node-id", "", "node ID of the validator")
+	cmd.Flags().StringVar(&initiateTxHash, "initiate-tx-hash", "", "tx hash of an already initiated removal")
+	cmd.Flags().Uint64Var(&uptimeSec, "uptime", 0, "(PoS only) validator uptime in seconds, queried from the L1 when not given")
+	cmd.Flags().BoolVar(&forceRemoval, "force", false, "(PoS only) remove the validator even if its uptime does not earn rewards")
+	cmd.Flags().BoolVar(&disableOnly, "disable", false, "only disable the validator on P-Chain, returning its remaining balance")
+	flags.AddRPCFlagToCmd(cmd, app, &validatorManagerRPC)
+	sigAggGroup := flags.AddSignatureAggregatorFlagsToCmd(cmd, &sigAggFlags)
+	cmd.SetHelpFunc(flags.WithGroupedHelp([]flags.GroupedFlags{sigAggGroup}))
+	return cmd
+}
+
+func removeValidator(_ *cobra.Command, _ []string) error {
+	if err := validateRemoveFlags(); err != nil {
+		return err
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		globalNetworkFlags,
+		true,
+		false,
+		networkoptions.DefaultSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+
+	l1Name, found, cancel, err := selectL1Validator(network, l1, nodeIDStr)
+	if err != nil {
+		return err
+	}
+	if cancel {
+		return nil
+	}
+	fee, err := l1ValidatorTxFee(network)
+	if err != nil {
+		return err
+	}
+	kc, err := keychain.GetKeychainFromCmdLineFlags(
+		app,
+		constants.PayTxsFeesMsg,
+		network,
+		keyName,
+		useLocalKey,
+		useLedger,
+		ledgerAddresses,
+		fee,
+	)
+	if err != nil {
+		return err
+	}
+	deployer := chain.NewPublicDeployer(app, useLedger, kc.Keychain, network)
+
+	if disableOnly {
+		if err := issueRemovalTx(deployer, true, found.ValidationID, nil); err != nil {
+			return err
+		}
+		recordRemoval(network.String(), l1Name, found.ValidationID.String(), found.NodeID.String(), "disable")
+		ux.Logger.GreenCheckmarkToUser("Node %s disabled on P-Chain", found.NodeID)
+		return nil
+	}
+
+	manager, err := loadL1ValidatorManager(network, l1Name)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	ux.Logger.PrintToUser("Removing node %s from %s", found.NodeID, l1Name)
+	var (
+		signedMessage *warp.Message
+		validationID  ids.ID
+	)
+	err = manager.watchAggregation(network, func() error {
+		var err error
+		signedMessage, validationID, _, err = sdkvalidatormanager.InitValidatorRemoval(
+			ctx,
+			app.GetSDKApp(),
+			network,
+			manager.rpcURL,
+			manager.chainSpec,
+			false,
+			manager.ownerAddress,
+			manager.ownerPrivateKey,
+			found.NodeID,
+			manager.aggregatorLogger,
+			manager.pos,
+			uptimeSec,
+			forceRemoval,
+			manager.address,
+			manager.useACP99,
+			initiateTxHash,
+			manager.aggregatorEndpoint,
+		)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := issueRemovalTx(deployer, false, validationID, signedMessage.Bytes()); err != nil {
+		return err
+	}
+
+	if err := manager.watchAggregation(network, func() error {
+		_, err := sdkvalidatormanager.FinishValidatorRemoval(
+			ctx,
+			app.GetSDKApp(),
+			network,
+			manager.rpcURL,
+			manager.chainSpec,
+			false,
+			manager.ownerAddress,
+			manager.ownerPrivateKey,
+			validationID,
+			manager.aggregatorLogger,
+			manager.address,
+			manager.useACP99,
+			manager.aggregatorEndpoint,
+		)
+		return err
+	}); err != nil {
+		return err
+	}
+	recordRemoval(network.String(), l1Name, validationID.String(), found.NodeID.String(), "validator manager")
+	ux.Logger.GreenCheckmarkToUser("Node %s removed from %s", found.NodeID, l1Name)
+	return nil
+}
+
+// validateRemoveFlags rejects the flags of the Validator Manager removal
+// when the validator is only disabled on P-Chain
+func validateRemoveFlags() error {
+	if !disableOnly {
+		return nil
+	}
+	switch {
+	case initiateTxHash != "":
+		return errors.New("--initiate-tx-hash resumes a Validator Manager removal and cannot be used with --disable")
+	case uptimeSec != 0 || forceRemoval:
+		return errors.New("--uptime and --force apply to Proof of Stake removals and cannot be used with --disable")
+	}
+	return nil
+}
+
+// l1ValidatorTxIssuer issues the P-Chain txs of a validator removal, as
+// chain.PublicDeployer does
+type l1ValidatorTxIssuer interface {
+	SetL1ValidatorWeight(message []byte) (ids.ID, error)
+	DisableL1Validator(validationID ids.ID) (ids.ID, error)
+}
+
+// issueRemovalTx removes a validator on P-Chain: with disable through a
+// DisableL1ValidatorTx, otherwise by setting its weight to 0 with the signed
+// message of the Validator Manager
+func issueRemovalTx(issuer l1ValidatorTxIssuer, disable bool, validationID ids.ID, message []byte) error {
+	if disable {
+		txID, err := issuer.DisableL1Validator(validationID)
+		if err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("DisableL1ValidatorTx ID: %s", txID)
+		return nil
+	}
+	txID, err := issuer.SetL1ValidatorWeight(message)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("SetL1ValidatorWeightTx ID: %s", txID)
+	return nil
+}
+
+func recordRemoval(network, l1Name, validationID, nodeID, method string) {
+	app.RecordHistory(history.Entry{
+		Operation: "validator remove",
+		Network:   network,
+		Chain:     l1Name,
+		Params: map[string]string{
+			"validationID": validationID,
+			"nodeID":       nodeID,
+			"method":       method,
+		},
+	})
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorcmd
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/ids"
+	luxlog "github.com/luxfi/log"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTxIssuer records the removal txs instead of issuing them on P-Chain
+type fakeTxIssuer struct {
+	disabled []ids.ID
+	weights  [][]byte
+	err      error
+}
+
+func (f *fakeTxIssuer) SetL1ValidatorWeight(message []byte) (ids.ID, error) {
+	if f.err != nil {
+		return ids.Empty, f.err
+	}
+	f.weights = append(f.weights, message)
+	return ids.GenerateTestID(), nil
+}
+
+func (f *fakeTxIssuer) DisableL1Validator(validationID ids.ID) (ids.ID, error) {
+	if f.err != nil {
+		return ids.Empty, f.err
+	}
+	f.disabled = append(f.disabled, validationID)
+	return ids.GenerateTestID(), nil
+}
+
+func TestRemoveCmdFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name: "validator manager removal",
+			args: []string{"--l1", "mychain", "--uptime", "3600", "--force", "--initiate-tx-hash", "0x01"},
+		},
+		{
+			name: "disable",
+			args: []string{"--l1", "mychain", "--disable"},
+		},
+		{
+			name:    "disable resuming a removal",
+			args:    []string{"--disable", "--initiate-tx-hash", "0x01"},
+			wantErr: "--initiate-tx-hash",
+		},
+		{
+			name:    "disable with uptime",
+			args:    []string{"--disable", "--uptime", "3600"},
+			wantErr: "--uptime and --force",
+		},
+		{
+			name:    "disable with force",
+			args:    []string{"--disable", "--force"},
+			wantErr: "--uptime and --force",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			// a new command resets the flag variables to their defaults
+			cmd := NewRemoveCmd()
+			cmd.SetOut(io.Discard)
+			require.NoError(cmd.Args(cmd, nil))
+			require.Error(cmd.Args(cmd, []string{"node"}))
+			require.NoError(cmd.ParseFlags(tt.args))
+			err := validateRemoveFlags()
+			if tt.wantErr != "" {
+				require.ErrorContains(err, tt.wantErr)
+				return
+			}
+			require.NoError(err)
+		})
+	}
+}
+
+func TestIssueRemovalTx(t *testing.T) {
+	require := require.New(t)
+	ux.NewUserLog(luxlog.NewNoOpLogger(), io.Discard)
+	validationID := ids.GenerateTestID()
+	message := []byte("signed warp message")
+
+	issuer := &fakeTxIssuer{}
+	require.NoError(issueRemovalTx(issuer, true, validationID, nil))
+	require.Equal([]ids.ID{validationID}, issuer.disabled)
+	require.Empty(issuer.weights)
+
+	issuer = &fakeTxIssuer{}
+	require.NoError(issueRemovalTx(issuer, false, validationID, message))
+	require.Equal([][]byte{message}, issuer.weights)
+	require.Empty(issuer.disabled)
+
+	errIssue := errors.New("insufficient funds")
+	issuer = &fakeTxIssuer{err: errIssue}
+	require.ErrorIs(issueRemovalTx(issuer, true, validationID, nil), errIssue)
+	require.ErrorIs(issueRemovalTx(issuer, false, validationID, message), errIssue)
+}
@@ -0,0 +1,284 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorcmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/luxfi/cli/cmd/flags"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/networkoptions"
+	"github.com/luxfi/cli/pkg/ux"
+	clivalidator "github.com/luxfi/cli/pkg/validator"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+	"github.com/luxfi/sdk/validator"
+	sdkvalidatormanager "github.com/luxfi/sdk/validatormanager"
+	"github.com/spf13/cobra"
+)
+
+var delegatorAddressStr string
+
+// lux validator rewards
+func NewRewardsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rewards",
+		Short: "Shows the delegations and pending rewards of a delegator",
+		Long: `This command shows the delegations of a delegator with the staking manager
+of a Proof of Stake L1: their validator, status, stake and pending reward.
+
+The staking manager computes the reward of a delegation when its removal is
+initiated, with 'lux validator undelegate'. Rewards of delegations pending
+removal are paid with 'lux validator rewards claim'.
+
+The delegator is the delegator key, or --address.
+
+EXAMPLES:
+
+  lux validator rewards --l1 mychain --delegator-key alice --testnet
+  lux validator rewards --l1 mychain --address 0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC --testnet`,
+		RunE: rewards,
+		Args: cobrautils.ExactArgs(0),
+	}
+
+	// Network flags handled at higher level to avoid conflicts
+	cmd.Flags().StringVar(&l1, "l1", "", "name of L1")
+	cmd.Flags().StringVar(&delegatorAddressStr, "address", "", "address, or @label, of the delegator")
+	addDelegatorKeyFlags(cmd, "as the delegator")
+	flags.AddRPCFlagToCmd(cmd, app, &validatorManagerRPC)
+	cmd.AddCommand(newRewardsClaimCmd())
+	return cmd
+}
+
+// lux validator rewards claim
+func newRewardsClaimCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "claim",
+		Short: "Claims the stake and rewards of delegations pending removal",
+		Long: `This command completes the removal of the delegations of a delegator that are
+pending removal, paying out their stake and rewards to their reward
+recipient.
+
+A removal is pending when it was initiated but not completed, as when
+'lux validator undelegate' is interrupted after the P-Chain weight change.
+It completes with the P-Chain acknowledgement of the current validator
+weight, signed by the P-Chain validators.
+
+EXAMPLES:
+
+  lux validator rewards claim --l1 mychain --delegator-key alice --testnet`,
+		RunE: claimRewards,
+		Args: cobrautils.ExactArgs(0),
+	}
+
+	// Network flags handled at higher level to avoid conflicts
+	cmd.Flags().StringVar(&l1, "l1", "", "name of L1")
+	cmd.Flags().StringVar(&delegationIDStr, "delegation-id", "", "ID of the delegation to claim (default: all the delegator's pending removal)")
+	addDelegatorKeyFlags(cmd, "owning the delegations")
+	flags.AddRPCFlagToCmd(cmd, app, &validatorManagerRPC)
+	sigAggGroup := flags.AddSignatureAggregatorFlagsToCmd(cmd, &sigAggFlags)
+	cmd.SetHelpFunc(flags.WithGroupedHelp([]flags.GroupedFlags{sigAggGroup}))
+	return cmd
+}
+
+func rewards(_ *cobra.Command, _ []string) error {
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		globalNetworkFlags,
+		true,
+		false,
+		networkoptions.DefaultSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	l1Name, err := selectL1(network, l1)
+	if err != nil || l1Name == "" {
+		return err
+	}
+	manager, err := loadStakingManager(network, l1Name, false)
+	if err != nil {
+		return err
+	}
+	defer manager.client.Close()
+	var delegator common.Address
+	if delegatorAddressStr != "" {
+		addr, err := app.ResolveAddress(delegatorAddressStr)
+		if err != nil {
+			return err
+		}
+		if !common.IsHexAddress(addr) {
+			return fmt.Errorf("invalid delegator address %q", addr)
+		}
+		delegator = common.HexToAddress(addr)
+	} else if _, delegator, err = delegatorKey(network, l1Name); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	delegations, err := clivalidator.GetDelegations(ctx, manager.client, manager.contractAddress(), delegator)
+	if err != nil {
+		return err
+	}
+	if len(delegations) == 0 {
+		ux.Logger.PrintToUser("%s has no delegation on %s", delegator.Hex(), l1Name)
+		return nil
+	}
+	_, _, decimals, err := manager.tokenDecimals(ctx)
+	if err != nil {
+		return err
+	}
+	nodeIDs, err := validatorNodeIDs(manager.network, manager.chainID)
+	if err != nil {
+		return err
+	}
+
+	t := ux.DefaultTable(
+		fmt.Sprintf("%s Delegations of %s", l1Name, delegator.Hex()),
+		[]string{"Delegation ID", "Node ID", "Status", "Weight", "Stake", "Pending Reward", "Reward Recipient"},
+	)
+	for _, d := range delegations {
+		stake, err := clivalidator.WeightToValue(ctx, manager.client, manager.contractAddress(), d.Weight)
+		if err != nil {
+			return err
+		}
+		nodeID, ok := nodeIDs[d.ValidationID]
+		if !ok {
+			nodeID = "removed"
+		}
+		reward := clivalidator.FormatTokenAmount(d.Reward, decimals)
+		if d.Status != clivalidator.PendingRemovedDelegatorStatus {
+			reward = "at removal"
+		}
+		_ = t.Append([]string{
+			d.DelegationID.String(),
+			nodeID,
+			d.Status.String(),
+			fmt.Sprintf("%d", d.Weight),
+			clivalidator.FormatTokenAmount(stake, decimals),
+			reward,
+			d.RewardRecipient.Hex(),
+		})
+	}
+	_ = t.Render()
+	return nil
+}
+
+// validatorNodeIDs maps the validation IDs of the current validators of
+// chainID to their node IDs
+func validatorNodeIDs(network models.Network, chainID ids.ID) (map[ids.ID]string, error) {
+	validators, err := validator.GetCurrentValidators(network, chainID)
+	if err != nil {
+		return nil, err
+	}
+	nodeIDs := make(map[ids.ID]string, len(validators))
+	for _, v := range validators {
+		nodeIDs[v.ValidationID] = v.NodeID.String()
+	}
+	return nodeIDs, nil
+}
+
+func claimRewards(_ *cobra.Command, _ []string) error {
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		globalNetworkFlags,
+		true,
+		false,
+		networkoptions.DefaultSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	l1Name, err := selectL1(network, l1)
+	if err != nil || l1Name == "" {
+		return err
+	}
+	manager, err := loadStakingManager(network, l1Name, true)
+	if err != nil {
+		return err
+	}
+	defer manager.client.Close()
+	privateKey, delegator, err := delegatorKey(network, l1Name)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var delegations []clivalidator.Delegation
+	if delegationIDStr != "" {
+		delegation, err := selectDelegation(ctx, manager, delegator)
+		if err != nil {
+			return err
+		}
+		if delegation.Status != clivalidator.PendingRemovedDelegatorStatus {
+			return fmt.Errorf("delegation %s is %s, not pending removal: end it with 'lux validator undelegate'", delegation.DelegationID, delegation.Status)
+		}
+		delegations = append(delegations, delegation)
+	} else {
+		all, err := clivalidator.GetDelegations(ctx, manager.client, manager.contractAddress(), delegator)
+		if err != nil {
+			return err
+		}
+		for _, d := range all {
+			if d.Status == clivalidator.PendingRemovedDelegatorStatus {
+				delegations = append(delegations, d)
+			}
+		}
+	}
+	if len(delegations) == 0 {
+		ux.Logger.PrintToUser("%s has no delegation pending removal on %s: end a delegation with 'lux validator undelegate'", delegator.Hex(), l1Name)
+		return nil
+	}
+	_, _, decimals, err := manager.tokenDecimals(ctx)
+	if err != nil {
+		return err
+	}
+	validators, err := validator.GetCurrentValidators(network, manager.chainID)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range delegations {
+		// acknowledge the current weight: the validator is gone, or the
+		// removal already changed its weight on P-Chain
+		weight := uint64(0)
+		for _, v := range validators {
+			if v.ValidationID == d.ValidationID {
+				weight = uint64(v.Weight)
+			}
+		}
+		nonce, err := sdkvalidatormanager.GetValidatorNonce(ctx, manager.rpcURL, d.ValidationID)
+		if err != nil {
+			return err
+		}
+		ack, err := manager.pChainAcknowledgement(nil, d.ValidationID, nonce, weight)
+		if err != nil {
+			return err
+		}
+		if err := manager.complete(privateKey, "completeDelegatorRemoval", d.DelegationID, ack); err != nil {
+			return err
+		}
+		app.RecordHistory(history.Entry{
+			Operation: "validator rewards claim",
+			Network:   network.String(),
+			Chain:     l1Name,
+			Params: map[string]string{
+				"validationID": d.ValidationID.String(),
+				"delegationID": d.DelegationID.String(),
+				"delegator":    delegator.Hex(),
+			},
+			Result: map[string]string{"reward": d.Reward.String()},
+		})
+		ux.Logger.GreenCheckmarkToUser("Delegation %s completed, reward of %s paid to %s",
+			d.DelegationID, clivalidator.FormatTokenAmount(d.Reward, decimals), d.RewardRecipient.Hex())
+	}
+	return nil
+}
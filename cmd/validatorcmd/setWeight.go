@@ -0,0 +1,196 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/luxfi/cli/cmd/flags"
+	"github.com/luxfi/cli/pkg/chain"
+	"github.com/luxfi/cli/pkg/cobrautils"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/keychain"
+	"github.com/luxfi/cli/pkg/networkoptions"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/ids"
+	sdkvalidatormanager "github.com/luxfi/sdk/validatormanager"
+	"github.com/luxfi/warp"
+	"github.com/spf13/cobra"
+)
+
+var weight uint64
+
+func NewSetWeightCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-weight",
+		Short: "Changes the weight of an L1 validator",
+		Long: `This command changes the weight of a Proof of Authority L1 validator.
+
+It initiates the weight update on the L1 Validator Manager contract, gets the
+resulting warp message signed by the L1 validators, registers the new weight
+on P-Chain with a SetL1ValidatorWeightTx, and completes the update on the
+contract with the P-Chain acknowledgement.
+
+An interrupted update resumes from the contract state, or from the
+--initiate-tx-hash of its first step.`,
+		RunE: setWeight,
+		Args: cobrautils.ExactArgs(0),
+	}
+
+	// Network flags handled at higher level to avoid conflicts
+	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to pay the P-Chain fee with [testnet/devnet only]")
+	cmd.Flags().StringVar(&l1, "l1", "", "name of L1")
+	cmd.Flags().StringVar(&nodeIDStr, "node-id", "", "node ID of the validator")
+	cmd.Flags().Uint64Var(&weight, "weight", 0, "new weight of the validator")
+	cmd.Flags().StringVar(&initiateTxHash, "initiate-tx-hash", "", "tx hash of an already initiated weight update")
+	flags.AddRPCFlagToCmd(cmd, app, &validatorManagerRPC)
+	sigAggGroup := flags.AddSignatureAggregatorFlagsToCmd(cmd, &sigAggFlags)
+	cmd.SetHelpFunc(flags.WithGroupedHelp([]flags.GroupedFlags{sigAggGroup}))
+	return cmd
+}
+
+func setWeight(_ *cobra.Command, _ []string) error {
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		globalNetworkFlags,
+		true,
+		false,
+		networkoptions.DefaultSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+
+	l1Name, found, cancel, err := selectL1Validator(network, l1, nodeIDStr)
+	if err != nil {
+		return err
+	}
+	if cancel {
+		return nil
+	}
+	if weight == 0 {
+		weight, err = app.Prompt.CaptureUint64("What is the new weight of the validator?")
+		if err != nil {
+			return err
+		}
+	}
+	if changed, err := checkNewWeight(weight, uint64(found.Weight)); err != nil {
+		return err
+	} else if !changed {
+		ux.Logger.PrintToUser("The weight of node %s is already %d", found.NodeID, weight)
+		return nil
+	}
+
+	manager, err := loadL1ValidatorManager(network, l1Name)
+	if err != nil {
+		return err
+	}
+	if manager.pos {
+		return errors.New("the weight of Proof of Stake validators follows their stake and delegations")
+	}
+	fee, err := l1ValidatorTxFee(network)
+	if err != nil {
+		return err
+	}
+	kc, err := keychain.GetKeychainFromCmdLineFlags(
+		app,
+		constants.PayTxsFeesMsg,
+		network,
+		keyName,
+		useLocalKey,
+		useLedger,
+		ledgerAddresses,
+		fee,
+	)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	ux.Logger.PrintToUser("Changing the weight of node %s on %s from %d to %d", found.NodeID, l1Name, found.Weight, weight)
+	var (
+		signedMessage *warp.Message
+		validationID  ids.ID
+	)
+	err = manager.watchAggregation(network, func() error {
+		var err error
+		signedMessage, validationID, _, err = sdkvalidatormanager.InitValidatorWeightChange(
+			ctx,
+			ux.Logger.PrintToUser,
+			app.GetSDKApp(),
+			network,
+			manager.rpcURL,
+			manager.chainSpec,
+			false,
+			manager.ownerAddress,
+			manager.ownerPrivateKey,
+			found.NodeID,
+			manager.aggregatorLogger,
+			manager.address,
+			weight,
+			initiateTxHash,
+			manager.aggregatorEndpoint,
+		)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	deployer := chain.NewPublicDeployer(app, useLedger, kc.Keychain, network)
+	txID, err := deployer.SetL1ValidatorWeight(signedMessage.Bytes())
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("SetL1ValidatorWeightTx ID: %s", txID)
+
+	if err := manager.watchAggregation(network, func() error {
+		_, err := sdkvalidatormanager.FinishValidatorWeightChange(
+			ctx,
+			app.GetSDKApp(),
+			network,
+			manager.rpcURL,
+			manager.chainSpec,
+			false,
+			manager.ownerAddress,
+			manager.ownerPrivateKey,
+			validationID,
+			manager.aggregatorLogger,
+			manager.address,
+			signedMessage,
+			weight,
+			manager.aggregatorEndpoint,
+		)
+		return err
+	}); err != nil {
+		return err
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "validator set-weight",
+		Network:   network.String(),
+		Chain:     l1Name,
+		Params: map[string]string{
+			"validationID": validationID.String(),
+			"nodeID":       found.NodeID.String(),
+			"weight":       fmt.Sprintf("%d", weight),
+		},
+	})
+	ux.Logger.GreenCheckmarkToUser("Weight of node %s set to %d", found.NodeID, weight)
+	return nil
+}
+
+// checkNewWeight validates the new weight of a validator, reporting whether
+// it differs from its current weight. A weight of 0 would remove the
+// validator, which goes through the removal of the Validator Manager.
+func checkNewWeight(newWeight, currentWeight uint64) (bool, error) {
+	if newWeight == 0 {
+		return false, errors.New("a weight of 0 removes the validator: use 'lux validator remove'")
+	}
+	return newWeight != currentWeight, nil
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatorcmd
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetWeightCmdArgs(t *testing.T) {
+	require := require.New(t)
+	cmd := NewSetWeightCmd()
+	cmd.SetOut(io.Discard)
+
+	require.NoError(cmd.Args(cmd, nil))
+	require.Error(cmd.Args(cmd, []string{"node"}))
+
+	require.NoError(cmd.ParseFlags([]string{"--l1", "mychain", "--node-id", "NodeID-1", "--weight", "40"}))
+	require.Equal("mychain", l1)
+	require.Equal("NodeID-1", nodeIDStr)
+	require.Equal(uint64(40), weight)
+	require.Error(cmd.ParseFlags([]string{"--weight", "-1"}))
+}
+
+func TestCheckNewWeight(t *testing.T) {
+	require := require.New(t)
+
+	changed, err := checkNewWeight(40, 20)
+	require.NoError(err)
+	require.True(changed)
+
+	changed, err = checkNewWeight(20, 20)
+	require.NoError(err)
+	require.False(changed)
+
+	_, err = checkNewWeight(0, 20)
+	require.ErrorContains(err, "lux validator remove")
+}
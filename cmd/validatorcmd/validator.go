@@ -15,9 +15,11 @@ var app *application.Lux
 func NewCmd(injectedApp *application.Lux) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "validator",
-		Short: "Manage P-Chain validator balance",
-		Long: `The validator command suite provides a collection of tools for managing validator
-balance on P-Chain.
+		Short: "Manage L1 validators on P-Chain",
+		Long: `The validator command suite provides a collection of tools for managing L1
+validators on P-Chain: their balance, their weight and their removal, the
+onboarding of nodes run by external operators, and for Proof of Stake L1s the
+delegations to them and their rewards.
 
 Validator's balance is used to pay for continuous fee to the P-Chain. When this Balance reaches 0, 
 the validator will be considered inactive and will no longer participate in validating the L1`,
@@ -30,5 +32,19 @@ the validator will be considered inactive and will no longer participate in vali
 	cmd.AddCommand(NewGetBalanceCmd())
 	// validator increaseBalance
 	cmd.AddCommand(NewIncreaseBalanceCmd())
+	// validator set-weight
+	cmd.AddCommand(NewSetWeightCmd())
+	// validator remove
+	cmd.AddCommand(NewRemoveCmd())
+	// validator delegate
+	cmd.AddCommand(NewDelegateCmd())
+	// validator undelegate
+	cmd.AddCommand(NewUndelegateCmd())
+	// validator rewards
+	cmd.AddCommand(NewRewardsCmd())
+	// validator invite
+	cmd.AddCommand(NewInviteCmd())
+	// validator accept
+	cmd.AddCommand(NewAcceptCmd())
 	return cmd
 }
@@ -0,0 +1,36 @@
+// Copyright (C) 2022-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vmcmd
+
+import (
+	"fmt"
+
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func newIDCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "id <name>",
+		Short: "Print the VM ID derived from a VM name",
+		Long: `Print the VM ID derived from a VM name.
+
+The VM ID is the name padded to 32 bytes and CB58 encoded, so names are
+limited to 32 bytes. It is the name of the VM plugin binary in the plugins
+directory.
+
+Examples:
+  lux vm id "Lux EVM"
+  lux vm id myvm`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			vmID, err := utils.VMID(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Println(vmID)
+			return nil
+		},
+	}
+}
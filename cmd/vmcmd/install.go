@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -17,8 +18,11 @@ import (
 	"github.com/luxfi/cli/pkg/binutils"
 	"github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/vm"
+	"github.com/luxfi/cli/pkg/vmregistry"
 	luxconfig "github.com/luxfi/config"
 	"github.com/luxfi/constants"
+	"github.com/luxfi/ids"
 	"github.com/spf13/cobra"
 )
 
@@ -27,34 +31,62 @@ const (
 	orgLuxfi  = "luxfi"
 )
 
-var installVersion string
+var (
+	installVersion string
+	installID      string
+	installName    string
+	installCluster string
+	installForce   bool
+)
 
 func newInstallCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "install <org/name>[@version]",
-		Short: "Install a VM plugin from GitHub releases",
-		Long: `Install a VM plugin from GitHub releases.
+		Use:   "install <org/name>[@version] | <binary> | <url>",
+		Short: "Install a VM plugin",
+		Long: `Install a VM plugin from GitHub releases, a local binary or a URL.
 
-Downloads the latest (or specified) version from GitHub releases and installs it
-to ~/.lux/plugins/packages/<org>/<name>/<version>/.
+Installed VMs are kept in the VM registry, ~/.lux/vms/<name>/<version>/,
+with their version, rpcchainvm protocol version and source, and can be
+used by 'lux chain create --custom-vm --vm <name>[@version]'.
 
 Package format: <org>/<name> or <org>/<name>@<version>
+  Downloads the latest (or specified) version from GitHub releases and
+  installs it to ~/.lux/plugins/packages/<org>/<name>/<version>/.
+
+Binary or URL:
+  Installs the binary, or the binary downloaded from the URL (a plain binary,
+  .tar.gz or .zip), to the registry as --name (default: the --id VM name, or
+  the binary name) at --version (default: local).
+  With --id, it is also installed as the plugin of that VM ID (or VM name)
+  for the local network nodes and every local cluster, and on every host of
+  --cluster. The rpcchainvm protocol version of the binary must match the
+  one of the running nodes, unless --force is given.
 
 Examples:
   lux vm install luxfi/evm           # Install latest
   lux vm install luxfi/evm@v1.0.0    # Install specific version
-  lux vm install myuser/myvm         # Install from any org`,
+  lux vm install myuser/myvm         # Install from any org
+  lux vm install ./build/myvm --name myvm --version v0.1.0
+  lux vm install ./build/myvm --id myvm
+  lux vm install https://example.com/myvm_linux_amd64.tar.gz --id myvm --cluster mycluster`,
 		Args: cobra.ExactArgs(1),
 		RunE: runInstall,
 	}
 
-	cmd.Flags().StringVarP(&installVersion, "version", "v", "", "Version to install (default: latest)")
+	cmd.Flags().StringVarP(&installVersion, "version", "v", "", "Version to install (default: latest, or local for a binary or URL)")
+	cmd.Flags().StringVar(&installID, "id", "", "VM ID, or VM name, to install a binary or URL as the plugin of")
+	cmd.Flags().StringVar(&installName, "name", "", "Name to register a binary or URL as in the VM registry")
+	cmd.Flags().StringVar(&installCluster, "cluster", "", "Also install a binary or URL on the hosts of this cluster")
+	cmd.Flags().BoolVar(&installForce, "force", false, "Install even if the rpcchainvm protocol version does not match the nodes'")
 
 	return cmd
 }
 
 func runInstall(_ *cobra.Command, args []string) error {
 	pkgRef := args[0]
+	if isBinarySource(pkgRef) {
+		return installBinary(pkgRef)
+	}
 
 	// Parse org/name[@version]
 	var org, name, version string
@@ -163,15 +195,200 @@ func runInstall(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to install plugin: %w", err)
 	}
 
+	installed, err := registerVM(binaryPath, vmregistry.VM{
+		Name:    name,
+		Version: version,
+		VMID:    vmID.String(),
+		Source:  fmt.Sprintf("%s/%s@%s", org, name, version),
+	})
+	if err != nil {
+		return err
+	}
+
 	ux.Logger.PrintToUser("Plugin installed successfully:")
 	ux.Logger.PrintToUser("  Package:  %s/%s@%s", org, name, version)
 	ux.Logger.PrintToUser("  VMID:     %s", vmID.String())
 	ux.Logger.PrintToUser("  Location: %s", pm.PackagePath(org, name, version))
 	ux.Logger.PrintToUser("  Active:   %s", pm.ActivePath(vmID.String()))
+	ux.Logger.PrintToUser("  Registry: %s", installed.BinaryPath())
 
 	return nil
 }
 
+// registerVM installs binaryPath to the VM registry as v, recording the
+// rpcchainvm protocol version it speaks
+func registerVM(binaryPath string, v vmregistry.VM) (*vmregistry.VM, error) {
+	rpcVersion, err := vm.GetVMBinaryProtocolVersion(binaryPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the rpcchainvm protocol version: %w", err)
+	}
+	v.RPCProtocol = rpcVersion
+	installed, err := app.GetVMRegistry().Install(binaryPath, v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install %s to the VM registry: %w", v.Name, err)
+	}
+	return installed, nil
+}
+
+// registryName returns the name a binary is registered as: --name, the VM
+// name given by --id, or the binary name
+func registryName(binaryPath string) string {
+	if installName != "" {
+		return installName
+	}
+	if installID != "" {
+		if _, err := ids.FromString(installID); err != nil {
+			return installID
+		}
+	}
+	return filepath.Base(binaryPath)
+}
+
+// isBinarySource tells a binary path or URL from a package reference
+func isBinarySource(source string) bool {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return true
+	}
+	info, err := os.Stat(utils.GetRealFilePath(source))
+	return err == nil && !info.IsDir()
+}
+
+// installBinary installs a binary path or URL to the VM registry and, with
+// --id, as the plugin of --id on the local nodes and the hosts of --cluster
+func installBinary(source string) error {
+	if installCluster != "" && installID == "" {
+		return errors.New("--id is required to install a binary or URL on a cluster")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "lux-vm-install-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+	binaryPath, err := fetchBinary(source, tmpDir)
+	if err != nil {
+		return err
+	}
+
+	name := registryName(binaryPath)
+	var vmID ids.ID
+	if installID != "" {
+		vmID, err = resolveVMID(installID)
+	} else {
+		vmID, err = utils.VMID(name)
+	}
+	if err != nil {
+		return err
+	}
+	entry := vmregistry.VM{
+		Name:    name,
+		Version: installVersion,
+		VMID:    vmID.String(),
+		Source:  source,
+	}
+	if installID == "" {
+		installed, err := registerVM(binaryPath, entry)
+		if err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("VM installed successfully:")
+		ux.Logger.PrintToUser("  Name:        %s", installed.Ref())
+		ux.Logger.PrintToUser("  VMID:        %s", installed.VMID)
+		ux.Logger.PrintToUser("  rpcchainvm:  %d", installed.RPCProtocol)
+		ux.Logger.PrintToUser("  Location:    %s", installed.BinaryPath())
+		ux.Logger.PrintToUser("Create a chain with it with: lux chain create <chainName> --custom-vm --vm %s", installed.Ref())
+		return nil
+	}
+
+	locations := localPluginLocations()
+	if installCluster != "" {
+		clusterLocations, err := clusterPluginLocations(installCluster)
+		if err != nil {
+			return err
+		}
+		locations = append(locations, clusterLocations...)
+	}
+
+	rpcVersion, incompatible, err := incompatibleLocations(binaryPath, locations)
+	if err != nil {
+		return err
+	}
+	if len(incompatible) > 0 {
+		if !installForce {
+			return fmt.Errorf("the VM speaks rpcchainvm %d, incompatible with the nodes of %s: use --force to install anyway",
+				rpcVersion, strings.Join(incompatible, ", "))
+		}
+		ux.Logger.PrintToUser("Warning: the VM speaks rpcchainvm %d, incompatible with the nodes of %s",
+			rpcVersion, strings.Join(incompatible, ", "))
+	}
+
+	for _, location := range locations {
+		if err := location.install(binaryPath, vmID); err != nil {
+			return fmt.Errorf("failed to install the plugin to %s: %w", location.Name, err)
+		}
+		ux.Logger.PrintToUser("Installed to %s: %s", location.Name, location.pluginPath(vmID.String()))
+	}
+	installed, err := registerVM(binaryPath, entry)
+	if err != nil {
+		return err
+	}
+
+	ux.Logger.PrintToUser("Plugin installed successfully:")
+	ux.Logger.PrintToUser("  Name:        %s", installed.Ref())
+	ux.Logger.PrintToUser("  VMID:        %s", vmID)
+	ux.Logger.PrintToUser("  rpcchainvm:  %d", rpcVersion)
+	ux.Logger.PrintToUser("Running nodes pick it up with: lux vm reload")
+	return nil
+}
+
+// fetchBinary returns the path of the executable at source, downloading and
+// extracting it to tmpDir if source is a URL
+func fetchBinary(source, tmpDir string) (string, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		binaryPath, err := filepath.Abs(utils.GetRealFilePath(source))
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve path: %w", err)
+		}
+		info, err := os.Stat(binaryPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to stat binary: %w", err)
+		}
+		if info.Mode()&0o111 == 0 {
+			return "", fmt.Errorf("binary is not executable: %s", binaryPath)
+		}
+		return binaryPath, nil
+	}
+
+	ux.Logger.PrintToUser("Downloading from %s...", source)
+	data, err := application.NewDownloader().Download(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to download: %w", err)
+	}
+	name := path.Base(source)
+	for _, ext := range []string{".tar.gz", ".tgz", ".zip"} {
+		if !strings.HasSuffix(name, ext) {
+			continue
+		}
+		archiveExt := "tar.gz"
+		if ext == ".zip" {
+			archiveExt = "zip"
+		}
+		if err := binutils.InstallArchive(archiveExt, data, tmpDir); err != nil {
+			return "", fmt.Errorf("failed to extract archive: %w", err)
+		}
+		binaryPath, err := findBinary(tmpDir, strings.TrimSuffix(name, ext))
+		if err != nil {
+			return "", fmt.Errorf("failed to find binary in archive: %w", err)
+		}
+		return binaryPath, nil
+	}
+	binaryPath := filepath.Join(tmpDir, name)
+	if err := os.WriteFile(binaryPath, data, constants.DefaultPerms755); err != nil {
+		return "", fmt.Errorf("failed to write binary: %w", err)
+	}
+	return binaryPath, nil
+}
+
 // getDownloadURL builds the download URL for a package
 func getDownloadURL(org, name, version string) (string, string) {
 	goarch := runtime.GOARCH
@@ -0,0 +1,163 @@
+// Copyright (C) 2022-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vmcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/vmregistry"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// PluginInfo is a VM plugin installed at a plugins directory
+type PluginInfo struct {
+	Location string `json:"location"`
+	VMID     string `json:"vmid"`
+	Name     string `json:"name,omitempty"`
+	Path     string `json:"path"`
+}
+
+var (
+	listCluster   string
+	listJSON      bool
+	listInstalled bool
+)
+
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the VM plugins of the local nodes and cluster hosts",
+		Long: `List the VM plugins installed for the local network nodes, every local
+cluster and, with --cluster, every host of a cluster.
+
+The name is shown for the well-known VMs and the VMs of the chains of this
+machine.
+
+With --installed, list the VMs of the VM registry (~/.lux/vms) instead, with
+their version, rpcchainvm protocol version and source.
+
+Examples:
+  lux vm list
+  lux vm list --installed
+  lux vm list --cluster mycluster --json`,
+		Args: cobra.NoArgs,
+		RunE: runList,
+	}
+
+	cmd.Flags().StringVar(&listCluster, "cluster", "", "Also list the plugins of the hosts of this cluster")
+	cmd.Flags().BoolVar(&listJSON, "json", false, "Output in JSON format")
+	cmd.Flags().BoolVar(&listInstalled, "installed", false, "List the VMs of the VM registry")
+
+	return cmd
+}
+
+func runList(_ *cobra.Command, _ []string) error {
+	if listInstalled {
+		return listRegistry()
+	}
+	locations := localPluginLocations()
+	if listCluster != "" {
+		clusterLocations, err := clusterPluginLocations(listCluster)
+		if err != nil {
+			return err
+		}
+		locations = append(locations, clusterLocations...)
+	}
+
+	plugins, err := listPlugins(locations, knownVMNames())
+	if err != nil {
+		return err
+	}
+
+	if listJSON {
+		data, err := json.MarshalIndent(plugins, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(plugins) == 0 {
+		ux.Logger.PrintToUser("No VM plugins installed.")
+		ux.Logger.PrintToUser("Use 'lux vm install' or 'lux vm link' to add one.")
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Location", "VMID", "Name", "Path")
+	for _, plugin := range plugins {
+		_ = table.Append([]string{plugin.Location, plugin.VMID, plugin.Name, plugin.Path})
+	}
+	_ = table.Render()
+	return nil
+}
+
+// listPlugins returns the plugins installed at locations, naming those in names
+func listPlugins(locations []pluginLocation, names map[string]string) ([]PluginInfo, error) {
+	plugins := []PluginInfo{}
+	for _, location := range locations {
+		vmIDs, err := location.plugins()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the plugins of %s: %w", location.Name, err)
+		}
+		sort.Strings(vmIDs)
+		for _, vmID := range vmIDs {
+			plugins = append(plugins, PluginInfo{
+				Location: location.Name,
+				VMID:     vmID,
+				Name:     names[vmID],
+				Path:     location.pluginPath(vmID),
+			})
+		}
+	}
+	return plugins, nil
+}
+
+// listRegistry lists the VMs of the VM registry
+func listRegistry() error {
+	vms, err := app.GetVMRegistry().List()
+	if err != nil {
+		return err
+	}
+
+	if listJSON {
+		if vms == nil {
+			vms = []*vmregistry.VM{}
+		}
+		data, err := json.MarshalIndent(vms, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(vms) == 0 {
+		ux.Logger.PrintToUser("No VMs installed in %s.", app.GetVMRegistry().Dir())
+		ux.Logger.PrintToUser("Use 'lux vm install' to add one.")
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Name", "Version", "VMID", "RPC", "Source", "Installed")
+	for _, v := range vms {
+		_ = table.Append([]string{
+			v.Name,
+			v.Version,
+			v.VMID,
+			strconv.Itoa(v.RPCProtocol),
+			v.Source,
+			v.InstalledAt.Local().Format(time.DateTime),
+		})
+	}
+	_ = table.Render()
+	return nil
+}
@@ -0,0 +1,197 @@
+// Copyright (C) 2022-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vmcmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/ansible"
+	"github.com/luxfi/cli/pkg/localnet"
+	"github.com/luxfi/cli/pkg/localnetworkinterface"
+	"github.com/luxfi/cli/pkg/node"
+	"github.com/luxfi/cli/pkg/remoteconfig"
+	"github.com/luxfi/cli/pkg/ssh"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/vm"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+)
+
+// pluginLocation is a plugins directory VMs are installed to and listed from,
+// on this machine or on a cluster host
+type pluginLocation struct {
+	Name string
+	Dir  string
+	Host *models.Host
+}
+
+// localPluginLocations returns the plugins directory of the local network
+// nodes and those of the local clusters
+func localPluginLocations() []pluginLocation {
+	locations := []pluginLocation{{Name: "local", Dir: app.GetCurrentPluginsDir()}}
+	clusters, _ := localnet.GetLocalClusters(app)
+	for _, cluster := range clusters {
+		dir := filepath.Join(localnet.GetLocalClusterDir(app, cluster), "plugins")
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			locations = append(locations, pluginLocation{Name: "local/" + cluster, Dir: dir})
+		}
+	}
+	return locations
+}
+
+// clusterPluginLocations returns the plugins directory of each host of a
+// cluster
+func clusterPluginLocations(clusterName string) ([]pluginLocation, error) {
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the hosts of cluster %s: %w", clusterName, err)
+	}
+	locations := make([]pluginLocation, 0, len(hosts))
+	for _, host := range hosts {
+		locations = append(locations, pluginLocation{
+			Name: clusterName + "/" + host.GetCloudID(),
+			Dir:  remoteconfig.GetRemoteLuxPluginDir(),
+			Host: host,
+		})
+	}
+	return locations, nil
+}
+
+// pluginPath returns the path of the plugin of vmID at the location
+func (l pluginLocation) pluginPath(vmID string) string {
+	if l.Host == nil {
+		return filepath.Join(l.Dir, vmID)
+	}
+	return path.Join(l.Dir, vmID)
+}
+
+// install copies binaryPath to the location as the plugin of vmID
+func (l pluginLocation) install(binaryPath string, vmID ids.ID) error {
+	dst := l.pluginPath(vmID.String())
+	if l.Host == nil {
+		if err := os.MkdirAll(l.Dir, constants.DefaultPerms755); err != nil {
+			return err
+		}
+		// a linked plugin is a symlink, replace it rather than write through it
+		if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err := utils.FileCopy(binaryPath, dst); err != nil {
+			return err
+		}
+		return os.Chmod(dst, constants.DefaultPerms755)
+	}
+	if _, err := l.Host.Command("mkdir -p "+l.Dir, nil, constants.SSHDirOpsTimeout); err != nil {
+		return err
+	}
+	if err := l.Host.Upload(binaryPath, dst, constants.SSHLongRunningScriptTimeout); err != nil {
+		return err
+	}
+	_, err := l.Host.Command("chmod +x "+dst, nil, constants.SSHFileOpsTimeout)
+	return err
+}
+
+// plugins returns the VM IDs installed at the location
+func (l pluginLocation) plugins() ([]string, error) {
+	if l.Host == nil {
+		entries, err := os.ReadDir(l.Dir)
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		vmIDs := []string{}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				vmIDs = append(vmIDs, entry.Name())
+			}
+		}
+		return vmIDs, nil
+	}
+	out, err := l.Host.Command(fmt.Sprintf("ls -1 %s 2>/dev/null || true", l.Dir), nil, constants.SSHDirOpsTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// resolveVMID returns the VM ID s, or the ID derived from the VM name s
+func resolveVMID(s string) (ids.ID, error) {
+	if vmID, err := ids.FromString(s); err == nil {
+		return vmID, nil
+	}
+	vmID, err := utils.VMID(s)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("invalid VM ID or name %q: %w", s, err)
+	}
+	return vmID, nil
+}
+
+// incompatibleLocations returns the locations whose node speaks another
+// rpcchainvm protocol version than the VM binary. Locations without a
+// reachable node are skipped.
+func incompatibleLocations(binaryPath string, locations []pluginLocation) (int, []string, error) {
+	vmVersion, err := vm.GetVMBinaryProtocolVersion(binaryPath)
+	if err != nil {
+		return 0, nil, err
+	}
+	incompatible := []string{}
+	for _, location := range locations {
+		var nodeVersion int
+		if location.Host == nil {
+			// only the local network exposes a node to ask
+			if location.Name != "local" {
+				continue
+			}
+			_, version, running, err := localnetworkinterface.NewStatusCheckerWithApp(app).GetCurrentNetworkVersion()
+			if err != nil {
+				return 0, nil, err
+			}
+			if !running {
+				continue
+			}
+			nodeVersion = version
+		} else {
+			resp, err := ssh.RunSSHCheckLuxdVersion(location.Host)
+			if err != nil {
+				return 0, nil, fmt.Errorf("failed to get the node version of %s: %w", location.Name, err)
+			}
+			_, version, err := node.ParseLuxdOutput(resp)
+			if err != nil {
+				return 0, nil, fmt.Errorf("failed to get the node version of %s: %w", location.Name, err)
+			}
+			nodeVersion = int(version)
+		}
+		if nodeVersion != vmVersion {
+			incompatible = append(incompatible, fmt.Sprintf("%s (rpcchainvm %d)", location.Name, nodeVersion))
+		}
+	}
+	return vmVersion, incompatible, nil
+}
+
+// knownVMNames maps the VM IDs of the chains of this machine and the
+// well-known VMs to their names
+func knownVMNames() map[string]string {
+	names := map[string]string{}
+	if evmID, err := utils.VMID("Lux EVM"); err == nil {
+		names[evmID.String()] = "Lux EVM"
+	}
+	chainNames, _ := app.GetSidecarNames()
+	for _, chainName := range chainNames {
+		sc, err := app.LoadSidecar(chainName)
+		if err != nil {
+			continue
+		}
+		if vmID, _ := sc.GetVMID(); vmID != "" {
+			names[vmID] = chainName
+		}
+	}
+	return names
+}
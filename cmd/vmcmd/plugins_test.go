@@ -0,0 +1,126 @@
+// Copyright (C) 2022-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vmcmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/ids"
+)
+
+func TestResolveVMID(t *testing.T) {
+	evmID, err := utils.VMID("Lux EVM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vmID := ids.GenerateTestID()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    ids.ID
+		wantErr bool
+	}{
+		{name: "VM ID", input: vmID.String(), want: vmID},
+		{name: "VM name", input: "Lux EVM", want: evmID},
+		{name: "too long name", input: "this-is-a-very-long-vm-name-that-exceeds-32-bytes", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveVMID(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("resolveVMID() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveVMID() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveVMID() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsBinarySource(t *testing.T) {
+	binary := filepath.Join(t.TempDir(), "myvm")
+	if err := os.WriteFile(binary, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]bool{
+		"luxfi/evm":                        false,
+		"luxfi/evm@v1.0.0":                 false,
+		"https://example.com/myvm.tar.gz":  true,
+		"http://example.com/myvm":          true,
+		binary:                             true,
+		filepath.Dir(binary):               false,
+		filepath.Join(t.TempDir(), "nope"): false,
+	}
+	for source, want := range tests {
+		if got := isBinarySource(source); got != want {
+			t.Errorf("isBinarySource(%q) = %v, want %v", source, got, want)
+		}
+	}
+}
+
+func TestInstallAndListPlugins(t *testing.T) {
+	binary := filepath.Join(t.TempDir(), "myvm")
+	if err := os.WriteFile(binary, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	vmID := ids.GenerateTestID()
+	local := pluginLocation{Name: "local", Dir: filepath.Join(t.TempDir(), "plugins")}
+	cluster := pluginLocation{Name: "local/c1", Dir: t.TempDir()}
+
+	// a linked plugin is replaced, not written through
+	linked := filepath.Join(t.TempDir(), "linked")
+	if err := os.WriteFile(linked, []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(linked, cluster.pluginPath(vmID.String())); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, location := range []pluginLocation{local, cluster} {
+		if err := location.install(binary, vmID); err != nil {
+			t.Fatalf("install to %s: %v", location.Name, err)
+		}
+		info, err := os.Lstat(location.pluginPath(vmID.String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !info.Mode().IsRegular() || info.Mode()&0o111 == 0 {
+			t.Errorf("plugin at %s is not an executable file: %s", location.Name, info.Mode())
+		}
+	}
+	if data, _ := os.ReadFile(linked); string(data) != "old" {
+		t.Errorf("linked binary was overwritten")
+	}
+
+	plugins, err := listPlugins([]pluginLocation{local, cluster}, map[string]string{vmID.String(): "myvm"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plugins) != 2 {
+		t.Fatalf("listPlugins() returned %d plugins, want 2", len(plugins))
+	}
+	for i, location := range []pluginLocation{local, cluster} {
+		want := PluginInfo{Location: location.Name, VMID: vmID.String(), Name: "myvm", Path: location.pluginPath(vmID.String())}
+		if plugins[i] != want {
+			t.Errorf("listPlugins()[%d] = %+v, want %+v", i, plugins[i], want)
+		}
+	}
+
+	// a missing directory has no plugins
+	plugins, err = listPlugins([]pluginLocation{{Name: "missing", Dir: filepath.Join(t.TempDir(), "nope")}}, nil)
+	if err != nil || len(plugins) != 0 {
+		t.Errorf("listPlugins() of a missing directory = %v, %v", plugins, err)
+	}
+}
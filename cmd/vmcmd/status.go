@@ -27,9 +27,8 @@ var jsonOutput bool
 
 func newStatusCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "status",
-		Aliases: []string{"list", "ls"},
-		Short:   "Show all linked VMs",
+		Use:   "status",
+		Short: "Show all linked VMs",
 		Long: `Show all linked VMs in the plugins directory.
 
 Displays VMID, name (if known), target path, and whether the target exists.
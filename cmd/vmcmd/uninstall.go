@@ -0,0 +1,42 @@
+// Copyright (C) 2022-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vmcmd
+
+import (
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/vmregistry"
+	"github.com/spf13/cobra"
+)
+
+func newUninstallCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "uninstall <name>[@version]",
+		Short: "Remove a VM from the VM registry",
+		Long: `Remove a version of a VM, or all its versions, from the VM registry
+(~/.lux/vms).
+
+Chains created from the VM keep their copy of the binary, and the plugins
+installed for the nodes are left in place.
+
+Examples:
+  lux vm uninstall myvm@v0.1.0
+  lux vm uninstall myvm`,
+		Args: cobra.ExactArgs(1),
+		RunE: runUninstall,
+	}
+
+	return cmd
+}
+
+func runUninstall(_ *cobra.Command, args []string) error {
+	name, version := vmregistry.ParseRef(args[0])
+	removed, err := app.GetVMRegistry().Uninstall(name, version)
+	if err != nil {
+		return err
+	}
+	for _, v := range removed {
+		ux.Logger.PrintToUser("Uninstalled %s", v.Ref())
+	}
+	return nil
+}
@@ -10,8 +10,11 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var app *application.Lux
+
 // NewCmd creates the vm command suite.
-func NewCmd(_ *application.Lux) *cobra.Command {
+func NewCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
 	cmd := &cobra.Command{
 		Use:   "vm",
 		Short: "Manage VM plugins",
@@ -20,18 +23,28 @@ func NewCmd(_ *application.Lux) *cobra.Command {
 VM plugins are stored as symlinks in ~/.lux/plugins/<vmid>.
 The VMID is calculated from the VM name (padded to 32 bytes, CB58 encoded).
 
+Installed VMs are also kept in the VM registry, ~/.lux/vms/<name>/<version>/,
+from which chains can be created.
+
 Examples:
+  lux vm id "Lux EVM"
   lux vm link lux-evm --path ~/work/lux/evm/build/evm
+  lux vm install ./build/myvm --id myvm --cluster mycluster
+  lux vm list --installed
+  lux vm uninstall myvm@v0.1.0
   lux vm status
   lux vm unlink lux-evm
   lux vm reload`,
 		RunE: cobrautils.CommandSuiteUsage,
 	}
 
+	cmd.AddCommand(newIDCmd())
 	cmd.AddCommand(newInstallCmd())
+	cmd.AddCommand(newListCmd())
 	cmd.AddCommand(newLinkCmd())
 	cmd.AddCommand(newStatusCmd())
 	cmd.AddCommand(newUnlinkCmd())
+	cmd.AddCommand(newUninstallCmd())
 	cmd.AddCommand(newReloadCmd())
 
 	return cmd
@@ -0,0 +1,489 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warpcmd
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/faucet"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/vm"
+	"github.com/luxfi/cli/pkg/warp"
+	"github.com/luxfi/cli/pkg/warp/relayer"
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/contract"
+	"github.com/luxfi/sdk/evm"
+	"github.com/luxfi/sdk/models"
+	"github.com/spf13/cobra"
+)
+
+// cChainName names the C-Chain as a bridge endpoint
+const cChainName = "c-chain"
+
+// bridgeRegistrationTimeout is how long deploy waits for the relayer to
+// deliver the registration of the remote to the home
+const bridgeRegistrationTimeout = 2 * time.Minute
+
+// bridgeChain is a chain a bridge is deployed to
+type bridgeChain struct {
+	warp.BridgeEndpoint
+	spec       contract.ChainSpec
+	registry   crypto.Address
+	privateKey string
+}
+
+func newBridgeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bridge",
+		Short: "Deploy token bridges between chains and move tokens over them",
+		Long: `A bridge moves an ERC-20, or the native token, of its home chain to a remote
+chain over Warp: a token home contract holds the bridged tokens as
+collateral, and a token remote contract mints their ERC-20 representation.
+
+  deploy    Deploy and register the home and remote contracts of a bridge
+  transfer  Move tokens over a deployed bridge`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newBridgeDeployCmd())
+	cmd.AddCommand(newBridgeTransferCmd())
+	return cmd
+}
+
+func newBridgeDeployCmd() *cobra.Command {
+	var (
+		from, to, token  string
+		symbol           string
+		relayerAddress   string
+		relayerAmount    string
+		contractsVersion string
+		privateKeyFlags  contract.PrivateKeyFlags
+	)
+	cmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "Deploy a token bridge between two chains",
+		Long: `Deploy a bridge of --token, an ERC-20 address or "native", from its home chain
+--from to the remote chain --to, both deployed with a Warp messenger and
+registry, or the C-Chain ("c-chain").
+
+The token home contract is deployed to --from, wrapping the native token
+first for a native bridge, and an ERC-20 token remote to --to. The remote is
+registered with the home through the messenger, and the relayer delivering
+their messages, the one of the local relayer config unless --relayer-address
+is given, is sent --relayer-amount tokens on both chains.
+
+The bridge is recorded in ~/.lux/chains/<chain>/bridges.json of its chains,
+for 'lux warp bridge transfer'.
+
+Example:
+  lux warp bridge deploy --from chaina --to chainb --token native --genesis-key
+  lux warp bridge deploy --from c-chain --to mychain --testnet --key ops \
+    --token 0x5FbDB2315678afecb367f032d93F642f64180aa3 --relayer-address 0x8db9...`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if token != warp.NativeToken && !common.IsHexAddress(token) {
+				return fmt.Errorf("invalid --token %q: expected an ERC-20 address or %q", token, warp.NativeToken)
+			}
+			from, to = bridgeChainName(from), bridgeChainName(to)
+			if from == to {
+				return errors.New("--from and --to must be different chains")
+			}
+			relayerFunds, err := vm.ParseTokenAmount(relayerAmount)
+			if err != nil {
+				return err
+			}
+			network := targetNetwork()
+			home, err := loadBridgeChain(network, from, privateKeyFlags)
+			if err != nil {
+				return err
+			}
+			remote, err := loadBridgeChain(network, to, privateKeyFlags)
+			if err != nil {
+				return err
+			}
+			bridge, err := deployBridge(home, remote, token, symbol, contractsVersion)
+			if err != nil {
+				return err
+			}
+			if bridge.Relayer, err = fundBridgeRelayer(home, remote, relayerAddress, relayerFunds); err != nil {
+				return err
+			}
+			for _, c := range []bridgeChain{home, remote} {
+				if c.Chain == cChainName {
+					continue
+				}
+				if err := warp.AddBridge(bridgesPath(c.Chain), network.Name(), bridge); err != nil {
+					return err
+				}
+			}
+			app.RecordHistory(history.Entry{
+				Operation: "warp bridge deploy",
+				Network:   network.String(),
+				Chain:     from,
+				Params:    map[string]string{"from": from, "to": to, "token": token},
+				Result:    map[string]string{"home": bridge.Home.Address, "remote": bridge.Remote.Address},
+			})
+			ux.Logger.GreenCheckmarkToUser("Bridge of %s deployed: home %s on %s, remote %s on %s",
+				bridge.Symbol, bridge.Home.Address, from, bridge.Remote.Address, to)
+			return nil
+		},
+	}
+	addNetworkFlags(cmd)
+	privateKeyFlags.AddToCmd(cmd, "to pay for the deploys")
+	cmd.Flags().StringVar(&from, "from", "", "home chain of the token")
+	cmd.Flags().StringVar(&to, "to", "", "remote chain to bridge the token to")
+	cmd.Flags().StringVar(&token, "token", "", `ERC-20 to bridge, or "native"`)
+	cmd.Flags().StringVar(&symbol, "symbol", "WLUX", "symbol of the wrapped native token of a native bridge")
+	cmd.Flags().StringVar(&relayerAddress, "relayer-address", "", "account of the relayer (default the local relayer's)")
+	cmd.Flags().StringVar(&relayerAmount, "relayer-amount", "10", "tokens to send to the relayer on each chain")
+	cmd.Flags().StringVar(&contractsVersion, "contracts-version", "", "version of the token bridge contracts (default latest)")
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+	_ = cmd.MarkFlagRequired("token")
+	return cmd
+}
+
+func newBridgeTransferCmd() *cobra.Command {
+	var (
+		from, to, token string
+		amount          string
+		recipient       string
+		privateKeyFlags contract.PrivateKeyFlags
+	)
+	cmd := &cobra.Command{
+		Use:   "transfer",
+		Short: "Move tokens over a deployed bridge",
+		Long: `Send --amount tokens, in whole tokens, from --from to --recipient on --to
+over the bridge deployed between the chains with 'lux warp bridge deploy',
+from the home to the remote chain or back. --token, the bridged ERC-20,
+"native" or the token symbol, picks among several bridges of the chains.
+
+Example:
+  lux warp bridge transfer --from chaina --to chainb --amount 100 --genesis-key
+  lux warp bridge transfer --from chainb --to chaina --token WLUX --amount 1.5 \
+    --recipient 0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC --key ops`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			network := targetNetwork()
+			from, to = bridgeChainName(from), bridgeChainName(to)
+			bridge, err := findBridge(network, from, to, token)
+			if err != nil {
+				return err
+			}
+			source, destination, _ := bridge.Route(from, to)
+			value, err := faucet.ParseAmount(amount, int(bridge.Decimals))
+			if err != nil {
+				return err
+			}
+			if recipient, err = app.ResolveAddress(recipient); err != nil {
+				return err
+			}
+			sender, err := loadBridgeChain(network, from, privateKeyFlags)
+			if err != nil {
+				return err
+			}
+			if recipient == "" {
+				account, err := evm.PrivateKeyToAddress(sender.privateKey)
+				if err != nil {
+					return err
+				}
+				recipient = account.Hex()
+			}
+			if !common.IsHexAddress(recipient) {
+				return fmt.Errorf("invalid --recipient %q", recipient)
+			}
+			destinationID, err := ids.FromString(destination.BlockchainID)
+			if err != nil {
+				return err
+			}
+			receipt, sendReceipt, err := warp.Send(
+				source.RPCURL,
+				crypto.HexToAddress(source.Address),
+				sender.privateKey,
+				destinationID,
+				crypto.HexToAddress(destination.Address),
+				crypto.HexToAddress(recipient),
+				value,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to send %s %s to %s: %w", amount, bridge.Symbol, to, err)
+			}
+			if sendReceipt != nil {
+				receipt = sendReceipt
+			}
+			app.RecordHistory(history.Entry{
+				Operation: "warp bridge transfer",
+				Network:   network.String(),
+				Chain:     from,
+				Params:    map[string]string{"to": to, "token": bridge.Token, "amount": amount, "recipient": recipient},
+				Result:    map[string]string{"txID": receipt.TxHash.Hex()},
+			})
+			ux.Logger.GreenCheckmarkToUser("Sent %s %s from %s to %s on %s in %s",
+				amount, bridge.Symbol, from, app.AnnotateAddress(recipient), to, receipt.TxHash.Hex())
+			ux.Logger.PrintToUser("The relayer delivers them to %s, follow them with 'lux warp message trace --tx %s'", to, receipt.TxHash.Hex())
+			return nil
+		},
+	}
+	addNetworkFlags(cmd)
+	privateKeyFlags.AddToCmd(cmd, "to send the tokens")
+	cmd.Flags().StringVar(&from, "from", "", "chain to send the tokens from")
+	cmd.Flags().StringVar(&to, "to", "", "chain to send the tokens to")
+	cmd.Flags().StringVar(&token, "token", "", `bridged ERC-20, "native" or token symbol (default the only bridge of the chains)`)
+	cmd.Flags().StringVar(&amount, "amount", "", "tokens to send")
+	cmd.Flags().StringVar(&recipient, "recipient", "", "account or @label receiving the tokens (default the sender)")
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+	_ = cmd.MarkFlagRequired("amount")
+	return cmd
+}
+
+// loadBridgeChain locates the chain name, c-chain or a deployed chain, on
+// network and the key paying for transactions on it
+func loadBridgeChain(network models.Network, name string, privateKeyFlags contract.PrivateKeyFlags) (bridgeChain, error) {
+	c := bridgeChain{BridgeEndpoint: warp.BridgeEndpoint{Chain: name}}
+	if name == cChainName {
+		c.spec.CChain = true
+	} else {
+		c.spec.BlockchainName = name
+	}
+	sdkApp := app.GetSDKApp()
+	blockchainID, err := contract.GetBlockchainID(sdkApp, network, c.spec)
+	if err != nil {
+		return c, fmt.Errorf("%s: %w", name, err)
+	}
+	c.BlockchainID = blockchainID.String()
+	if c.RPCURL, _, err = contract.GetBlockchainEndpoints(sdkApp, network, c.spec, false, false); err != nil {
+		return c, err
+	}
+	if c.RPCURL == "" {
+		c.RPCURL = models.GetRPCEndpoint(network.Endpoint(), c.BlockchainID)
+	}
+	registry, _, err := contract.GetWarpInfo(sdkApp, network, c.spec, false, false, false)
+	if err != nil {
+		return c, err
+	}
+	if registry == "" {
+		return c, fmt.Errorf("%s has no Warp registry on %s, deploy the messenger and run 'lux warp sync-registries' first", name, network.Name())
+	}
+	c.registry = crypto.HexToAddress(registry)
+	c.privateKey, err = chainPrivateKey(network, c.spec, privateKeyFlags)
+	return c, err
+}
+
+// chainPrivateKey returns the key of privateKeyFlags paying for transactions
+// on the chain of spec
+func chainPrivateKey(network models.Network, spec contract.ChainSpec, privateKeyFlags contract.PrivateKeyFlags) (string, error) {
+	sdkApp := app.GetSDKApp()
+	genesisPrivateKey := ""
+	if privateKeyFlags.GenesisKey {
+		var err error
+		if _, genesisPrivateKey, err = contract.GetEVMChainPrefundedKey(sdkApp, network, spec); err != nil {
+			return "", err
+		}
+	}
+	privateKey, err := privateKeyFlags.GetPrivateKey(sdkApp, genesisPrivateKey)
+	if err != nil {
+		return "", err
+	}
+	if privateKey == "" {
+		return "", errors.New("give the key paying for the transactions with --private-key, --key or --genesis-key")
+	}
+	return privateKey, nil
+}
+
+// deployBridge deploys the token home of token to home and an ERC-20 token
+// remote to remote, and registers the remote with the home
+func deployBridge(home, remote bridgeChain, token, symbol, contractsVersion string) (warp.Bridge, error) {
+	bridge := warp.Bridge{Token: token, Home: home.BridgeEndpoint, Remote: remote.BridgeEndpoint}
+	if !warp.FoundryIsInstalled() {
+		if err := warp.InstallFoundry(); err != nil {
+			return bridge, err
+		}
+	}
+	if err := warp.DownloadRepo(app, contractsVersion); err != nil {
+		return bridge, err
+	}
+	if err := warp.BuildContracts(app); err != nil {
+		return bridge, err
+	}
+	srcDir, err := warp.RepoDir(app)
+	if err != nil {
+		return bridge, err
+	}
+	homeManager, err := evm.PrivateKeyToAddress(home.privateKey)
+	if err != nil {
+		return bridge, err
+	}
+	remoteManager, err := evm.PrivateKeyToAddress(remote.privateKey)
+	if err != nil {
+		return bridge, err
+	}
+
+	var (
+		homeAddress crypto.Address
+		name        string
+	)
+	if token == warp.NativeToken {
+		wrapped, err := warp.DeployWrappedNativeToken(srcDir, home.RPCURL, home.privateKey, symbol)
+		if err != nil {
+			return bridge, fmt.Errorf("failed to deploy the wrapped native token to %s: %w", home.Chain, err)
+		}
+		bridge.WrappedToken = wrapped.Hex()
+		bridge.Symbol, name, bridge.Decimals = symbol, symbol, 18
+		ux.Logger.PrintToUser("Wrapped native token deployed to %s at %s", home.Chain, wrapped.Hex())
+		homeAddress, err = warp.DeployNativeHome(srcDir, home.RPCURL, home.privateKey, home.registry, crypto.Address(homeManager), wrapped)
+		if err != nil {
+			return bridge, fmt.Errorf("failed to deploy the token home to %s: %w", home.Chain, err)
+		}
+	} else {
+		tokenAddress := crypto.HexToAddress(token)
+		bridge.Token = tokenAddress.Hex()
+		if bridge.Symbol, name, bridge.Decimals, err = warp.GetTokenParams(home.RPCURL, tokenAddress); err != nil {
+			return bridge, fmt.Errorf("%s is not an ERC-20 on %s: %w", token, home.Chain, err)
+		}
+		homeAddress, err = warp.DeployERC20Home(srcDir, home.RPCURL, home.privateKey, home.registry, crypto.Address(homeManager), tokenAddress, bridge.Decimals)
+		if err != nil {
+			return bridge, fmt.Errorf("failed to deploy the token home to %s: %w", home.Chain, err)
+		}
+	}
+	bridge.Home.Address = homeAddress.Hex()
+	ux.Logger.PrintToUser("Token home deployed to %s at %s", home.Chain, homeAddress.Hex())
+
+	homeID, err := ids.FromString(home.BlockchainID)
+	if err != nil {
+		return bridge, err
+	}
+	remoteAddress, err := warp.DeployERC20Remote(
+		srcDir,
+		remote.RPCURL,
+		remote.privateKey,
+		remote.registry,
+		crypto.Address(remoteManager),
+		homeID,
+		homeAddress,
+		bridge.Decimals,
+		name,
+		bridge.Symbol,
+		bridge.Decimals,
+	)
+	if err != nil {
+		return bridge, fmt.Errorf("failed to deploy the token remote to %s: %w", remote.Chain, err)
+	}
+	bridge.Remote.Address = remoteAddress.Hex()
+	ux.Logger.PrintToUser("Token remote deployed to %s at %s", remote.Chain, remoteAddress.Hex())
+
+	if err := warp.RegisterRemote(remote.RPCURL, remote.privateKey, remoteAddress); err != nil {
+		return bridge, fmt.Errorf("failed to register the token remote: %w", err)
+	}
+	remoteID, err := ids.FromString(remote.BlockchainID)
+	if err != nil {
+		return bridge, err
+	}
+	deadline := time.Now().Add(bridgeRegistrationTimeout)
+	for {
+		registered, err := warp.TokenHomeGetRegisteredRemote(home.RPCURL, homeAddress, remoteID, remoteAddress)
+		if err == nil && registered.Registered {
+			ux.Logger.PrintToUser("Token remote registered with the token home")
+			break
+		}
+		if time.Now().After(deadline) {
+			ux.Logger.PrintToUser("The registration of the token remote was not delivered to %s yet: check the relayer with 'lux warp relayer status'", home.Chain)
+			break
+		}
+		time.Sleep(2 * time.Second)
+	}
+	return bridge, nil
+}
+
+// fundBridgeRelayer sends amount tokens on the chains of a bridge to the
+// relayer delivering its messages, and returns the relayer account
+func fundBridgeRelayer(home, remote bridgeChain, relayerAddress string, amount *big.Int) (string, error) {
+	if relayerAddress == "" {
+		remoteID, err := ids.FromString(remote.BlockchainID)
+		if err != nil {
+			return "", err
+		}
+		key, err := relayer.AccountKey(app.GetLocalRelayerConfigPath(), remoteID)
+		if err != nil {
+			return "", err
+		}
+		if key == "" {
+			ux.Logger.PrintToUser("No relayer config delivers to %s: fund the relayer of the bridge on both chains", remote.Chain)
+			return "", nil
+		}
+		account, err := evm.PrivateKeyToAddress(key)
+		if err != nil {
+			return "", err
+		}
+		relayerAddress = account.Hex()
+	}
+	if !common.IsHexAddress(relayerAddress) {
+		return "", fmt.Errorf("invalid --relayer-address %q", relayerAddress)
+	}
+	if amount.Sign() == 0 {
+		return relayerAddress, nil
+	}
+	for _, c := range []bridgeChain{home, remote} {
+		client, err := evm.GetClient(c.RPCURL)
+		if err != nil {
+			return "", err
+		}
+		_, err = client.FundAddress(c.privateKey, relayerAddress, amount)
+		client.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to fund the relayer on %s: %w", c.Chain, err)
+		}
+		ux.Logger.PrintToUser("Relayer %s funded on %s", relayerAddress, c.Chain)
+	}
+	return relayerAddress, nil
+}
+
+// findBridge returns the bridge of token deployed between the chains from
+// and to on network
+func findBridge(network models.Network, from, to, token string) (warp.Bridge, error) {
+	var found []warp.Bridge
+	for _, chain := range []string{from, to} {
+		if chain == cChainName {
+			continue
+		}
+		bridges, err := warp.LoadBridges(bridgesPath(chain))
+		if err != nil {
+			return warp.Bridge{}, err
+		}
+		if found = warp.FindBridges(bridges[network.Name()], from, to, token); len(found) > 0 {
+			break
+		}
+	}
+	switch len(found) {
+	case 0:
+		return warp.Bridge{}, fmt.Errorf("no bridge between %s and %s on %s, deploy one with 'lux warp bridge deploy'", from, to, network.Name())
+	case 1:
+		return found[0], nil
+	}
+	tokens := make([]string, 0, len(found))
+	for _, b := range found {
+		tokens = append(tokens, b.Symbol+" ("+b.Token+")")
+	}
+	return warp.Bridge{}, fmt.Errorf("several bridges between %s and %s, pick one with --token: %s", from, to, strings.Join(tokens, ", "))
+}
+
+// bridgeChainName returns the chain name given for a bridge endpoint, with
+// the C-Chain spelled cChainName
+func bridgeChainName(name string) string {
+	if strings.EqualFold(name, cChainName) {
+		return cChainName
+	}
+	return name
+}
+
+func bridgesPath(chainName string) string {
+	return warp.BridgesPath(filepath.Join(app.GetChainsDir(), chainName))
+}
@@ -0,0 +1,601 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warpcmd
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/chainindex"
+	"github.com/luxfi/cli/pkg/config"
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/sigagg"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/warp"
+	"github.com/luxfi/cli/pkg/warp/relayer"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/crypto"
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/ethclient"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/contract"
+	"github.com/luxfi/sdk/evm"
+	"github.com/luxfi/sdk/models"
+	luxWarp "github.com/luxfi/warp"
+	"github.com/spf13/cobra"
+)
+
+func newMessageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "message",
+		Short: "Inspect Warp messages and deliver them without the relayer",
+		Long: `Follow Warp messages, or send and deliver them through the messenger
+contracts directly, without the relayer, to debug their delivery.
+
+  trace    Follow a message from its source to its destination
+  send     Send a message through the messenger of a chain
+  receive  Deliver a message, aggregating its signatures in the CLI`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newMessageTraceCmd())
+	cmd.AddCommand(newMessageSendCmd())
+	cmd.AddCommand(newMessageReceiveCmd())
+	return cmd
+}
+
+func newMessageTraceCmd() *cobra.Command {
+	var (
+		txHash    string
+		chainName string
+	)
+	cmd := &cobra.Command{
+		Use:   "trace",
+		Short: "Follow a Warp message from its source to its destination",
+		Long: `Follow the Warp messages a transaction sent: from the source receipt,
+through the relayer log, to their delivery on the destination chain.
+
+The source chain is found among the chains deployed to the network unless
+--chain names it.
+
+Example:
+  lux warp message trace --tx 0x5c50...
+  lux warp message trace --tx 0x5c50... --chain mychain --testnet`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			hash, err := chainindex.ParseHash(txHash)
+			if err != nil {
+				return err
+			}
+			return traceMessage(cmd.Context(), hash, chainName)
+		},
+		Annotations: map[string]string{config.CapabilityAnnotation: config.CapabilityNone},
+	}
+	addNetworkFlags(cmd)
+	cmd.Flags().StringVar(&txHash, "tx", "", "hash of the transaction that sent the message")
+	cmd.Flags().StringVar(&chainName, "chain", "", "name of the source chain")
+	_ = cmd.MarkFlagRequired("tx")
+	return cmd
+}
+
+func traceMessage(ctx context.Context, txHash common.Hash, chainName string) error {
+	network := targetNetwork()
+	endpoints, closeEndpoints, err := warp.MessengerEndpoints(app, network)
+	if err != nil {
+		return err
+	}
+	defer closeEndpoints()
+	byID := make(map[common.Hash]relayer.Endpoint, len(endpoints))
+	for _, e := range endpoints {
+		byID[e.BlockchainID] = e
+	}
+	lookup := func(id common.Hash) (relayer.Endpoint, bool) {
+		e, ok := byID[id]
+		return e, ok
+	}
+	logPath := app.GetLocalRelayerLogPath(network)
+
+	for _, source := range endpoints {
+		if chainName != "" && source.Name != chainName {
+			continue
+		}
+		traces, err := relayer.Trace(ctx, source, txHash, lookup, logPath)
+		if errors.Is(err, ethereum.NotFound) && chainName == "" {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		printTraces(source, txHash, traces)
+		return nil
+	}
+	if chainName != "" {
+		return fmt.Errorf("%s has no Warp messenger deployed to %s", chainName, network.Name())
+	}
+	return fmt.Errorf("transaction %s not found on the chains with a Warp messenger deployed to %s", txHash.Hex(), network.Name())
+}
+
+func printTraces(source relayer.Endpoint, txHash common.Hash, traces []relayer.MessageTrace) {
+	for _, t := range traces {
+		ux.Logger.PrintToUser("Message %s", t.ID.Hex())
+		ux.Logger.PrintToUser("  Sent:        %s block %d, tx %s, nonce %d", source.Name, t.BlockNumber, txHash.Hex(), t.Nonce)
+		destination := t.Destination
+		if destination == "" {
+			destination = t.DestinationBlockchainID.Hex() + " (not deployed from this CLI)"
+		}
+		ux.Logger.PrintToUser("  Destination: %s", destination)
+		if len(t.RelayerLog) == 0 {
+			ux.Logger.PrintToUser("  Relayer log: no entries")
+		} else {
+			ux.Logger.PrintToUser("  Relayer log:")
+			for _, line := range t.RelayerLog {
+				ux.Logger.PrintToUser("    %s", line)
+			}
+		}
+		switch {
+		case t.Destination == "":
+			ux.Logger.PrintToUser("  Delivery:    unknown")
+		case t.Delivery == nil:
+			ux.Logger.PrintToUser("  Delivery:    pending")
+		default:
+			outcome := "executed"
+			if !t.Delivery.Executed {
+				outcome = "execution failed, can be retried"
+			}
+			ux.Logger.PrintToUser("  Delivery:    %s block %d, tx %s, by %s (%s)",
+				t.Destination, t.Delivery.BlockNumber, t.Delivery.TxHash.Hex(), t.Delivery.Deliverer.Hex(), outcome)
+		}
+		ux.Logger.PrintToUser("")
+	}
+}
+
+// defaultRequiredGasLimit is the gas a sent message reserves for its
+// execution on the destination
+const defaultRequiredGasLimit = 100_000
+
+// messengerChain is a chain with a Warp messenger, as the source or the
+// destination of a message
+type messengerChain struct {
+	relayer.Endpoint
+	spec     contract.ChainSpec
+	rpcURL   string
+	subnetID ids.ID
+	client   *ethclient.Client
+}
+
+// feeInfo is the relayer fee of a message
+type feeInfo struct {
+	FeeTokenAddress crypto.Address
+	Amount          *big.Int
+}
+
+// messageInput is the TeleporterMessageInput of sendCrossChainMessage
+type messageInput struct {
+	DestinationBlockchainID [32]byte
+	DestinationAddress      crypto.Address
+	FeeInfo                 feeInfo
+	RequiredGasLimit        *big.Int
+	AllowedRelayerAddresses []crypto.Address
+	Message                 []byte
+}
+
+func newMessageSendCmd() *cobra.Command {
+	var (
+		from, to           string
+		payload            string
+		destinationAddress string
+		requiredGasLimit   uint64
+		allowedRelayers    []string
+		privateKeyFlags    contract.PrivateKeyFlags
+	)
+	cmd := &cobra.Command{
+		Use:   "send",
+		Short: "Send a Warp message through the messenger of a chain",
+		Long: `Send --payload, as hex, from --from to --destination-address on --to, calling
+sendCrossChainMessage on the Warp messenger of --from directly. Both chains
+are deployed with a Warp messenger, or the C-Chain ("c-chain").
+
+The message pays no relayer fee. It is delivered by the relayer, or by
+'lux warp message receive' with the message ID printed. Without
+--destination-address the message is received but its execution fails,
+which is enough to debug delivery.
+
+Example:
+  lux warp message send --from chaina --to chainb --payload 0xdeadbeef --genesis-key
+  lux warp message send --from c-chain --to mychain --testnet --key ops \
+    --payload 0x01 --destination-address 0x5FbDB2315678afecb367f032d93F642f64180aa3`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			data, err := hex.DecodeString(strings.TrimPrefix(payload, "0x"))
+			if err != nil {
+				return fmt.Errorf("invalid --payload hex: %w", err)
+			}
+			if destinationAddress != "" && !common.IsHexAddress(destinationAddress) {
+				return fmt.Errorf("invalid --destination-address %q", destinationAddress)
+			}
+			relayers := make([]crypto.Address, 0, len(allowedRelayers))
+			for _, r := range allowedRelayers {
+				if !common.IsHexAddress(r) {
+					return fmt.Errorf("invalid --allowed-relayer %q", r)
+				}
+				relayers = append(relayers, crypto.HexToAddress(r))
+			}
+			from, to = bridgeChainName(from), bridgeChainName(to)
+			if from == to {
+				return errors.New("--from and --to must be different chains")
+			}
+			network := targetNetwork()
+			source, err := loadMessengerChain(network, from)
+			if err != nil {
+				return err
+			}
+			defer source.client.Close()
+			dest, err := loadMessengerChain(network, to)
+			if err != nil {
+				return err
+			}
+			defer dest.client.Close()
+			privateKey, err := chainPrivateKey(network, source.spec, privateKeyFlags)
+			if err != nil {
+				return err
+			}
+			return sendMessage(source, dest, privateKey, messageInput{
+				DestinationBlockchainID: dest.BlockchainID,
+				DestinationAddress:      crypto.HexToAddress(destinationAddress),
+				FeeInfo:                 feeInfo{Amount: big.NewInt(0)},
+				RequiredGasLimit:        new(big.Int).SetUint64(requiredGasLimit),
+				AllowedRelayerAddresses: relayers,
+				Message:                 data,
+			})
+		},
+	}
+	addNetworkFlags(cmd)
+	privateKeyFlags.AddToCmd(cmd, "to send the message")
+	cmd.Flags().StringVar(&from, "from", "", "chain to send the message from")
+	cmd.Flags().StringVar(&to, "to", "", "chain to send the message to")
+	cmd.Flags().StringVar(&payload, "payload", "", "message payload, as hex")
+	cmd.Flags().StringVar(&destinationAddress, "destination-address", "", "contract the message is delivered to on --to")
+	cmd.Flags().Uint64Var(&requiredGasLimit, "required-gas-limit", defaultRequiredGasLimit, "gas reserved for the execution of the message")
+	cmd.Flags().StringSliceVar(&allowedRelayers, "allowed-relayer", nil, "account allowed to deliver the message (repeatable, default any)")
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+	_ = cmd.MarkFlagRequired("payload")
+	return cmd
+}
+
+func sendMessage(source, dest messengerChain, privateKey string, input messageInput) error {
+	tx, receipt, err := contract.TxToMethod(
+		source.rpcURL,
+		false,
+		crypto.Address{},
+		privateKey,
+		crypto.Address(source.Messenger),
+		nil,
+		"send cross chain message",
+		nil,
+		"sendCrossChainMessage((bytes32, address, (address, uint256), uint256, [address], bytes))->(bytes32)",
+		input,
+	)
+	if err != nil {
+		return evm.TransactionError(tx, err, "failed to send the message from %s", source.Name)
+	}
+	messages := relayer.SentMessages(receipt, source.Messenger)
+	if len(messages) == 0 {
+		return fmt.Errorf("transaction %s sent no Warp message through %s", receipt.TxHash.Hex(), source.Messenger.Hex())
+	}
+	m := messages[0]
+	app.RecordHistory(history.Entry{
+		Operation: "warp message send",
+		Network:   targetNetwork().String(),
+		Chain:     source.Name,
+		Params:    map[string]string{"to": dest.Name, "payload": "0x" + hex.EncodeToString(input.Message)},
+		Result:    map[string]string{"messageID": m.ID.Hex(), "txID": receipt.TxHash.Hex()},
+	})
+	ux.Logger.GreenCheckmarkToUser("Message %s sent from %s to %s in %s, nonce %d",
+		m.ID.Hex(), source.Name, dest.Name, receipt.TxHash.Hex(), m.Nonce)
+	ux.Logger.PrintToUser("Deliver it without the relayer with 'lux warp message receive --message-id %s'", m.ID.Hex())
+	return nil
+}
+
+func newMessageReceiveCmd() *cobra.Command {
+	var (
+		messageID       string
+		from, to        string
+		rewardAddress   string
+		peerURIs        []string
+		apiPort         int
+		quorum          uint64
+		privateKeyFlags contract.PrivateKeyFlags
+	)
+	cmd := &cobra.Command{
+		Use:   "receive",
+		Short: "Deliver a Warp message, aggregating its signatures in the CLI",
+		Long: `Deliver message --message-id to its destination chain without the relayer:
+the Warp message is read from the transaction that sent it, signed by the
+validators of the source chain, with the signatures aggregated by the CLI,
+and passed to receiveCrossChainMessage on the messenger of the destination.
+
+The source chain is found among the chains deployed to the network unless
+--from names it, and the destination from the message unless --to names it.
+Validators are reached at --peer-uri, by default the nodes of the local
+network or the public API node of other networks, and at their public IP on
+--api-port when one of these nodes is connected to them.
+
+A message already delivered is not delivered again.
+
+Example:
+  lux warp message receive --message-id 0x3f2a... --genesis-key
+  lux warp message receive --message-id 0x3f2a... --from c-chain --to mychain --testnet --key ops`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			idBytes := common.FromHex(strings.TrimSpace(messageID))
+			if len(idBytes) != common.HashLength {
+				return fmt.Errorf("invalid --message-id %q", messageID)
+			}
+			if rewardAddress != "" && !common.IsHexAddress(rewardAddress) {
+				return fmt.Errorf("invalid --reward-address %q", rewardAddress)
+			}
+			network := targetNetwork()
+			if len(peerURIs) == 0 {
+				var err error
+				if peerURIs, err = defaultPeerURIs(network); err != nil {
+					return err
+				}
+			}
+			return receiveMessage(cmd.Context(), network, common.BytesToHash(idBytes), bridgeChainName(from), bridgeChainName(to),
+				rewardAddress, privateKeyFlags, peerURIs, apiPort, quorum)
+		},
+	}
+	addNetworkFlags(cmd)
+	privateKeyFlags.AddToCmd(cmd, "to deliver the message")
+	cmd.Flags().StringVar(&messageID, "message-id", "", "ID of the message to deliver")
+	cmd.Flags().StringVar(&from, "from", "", "chain that sent the message")
+	cmd.Flags().StringVar(&to, "to", "", "chain the message goes to")
+	cmd.Flags().StringVar(&rewardAddress, "reward-address", "", "account credited the relayer fee (default the deliverer)")
+	cmd.Flags().StringSliceVar(&peerURIs, "peer-uri", nil, "API URI of a validator node (repeatable)")
+	cmd.Flags().IntVar(&apiPort, "api-port", 9630, "API port of the validators found through the peers")
+	cmd.Flags().Uint64Var(&quorum, "quorum", sigagg.DefaultQuorumPercentage, "stake percentage that must sign")
+	_ = cmd.MarkFlagRequired("message-id")
+	return cmd
+}
+
+func receiveMessage(
+	ctx context.Context,
+	network models.Network,
+	id common.Hash,
+	from, to string,
+	rewardAddress string,
+	privateKeyFlags contract.PrivateKeyFlags,
+	peerURIs []string,
+	apiPort int,
+	quorum uint64,
+) error {
+	from, to, m, err := messageRoute(ctx, network, id, from, to)
+	if err != nil {
+		return err
+	}
+	source, err := loadMessengerChain(network, from)
+	if err != nil {
+		return err
+	}
+	defer source.client.Close()
+	dest, err := loadMessengerChain(network, to)
+	if err != nil {
+		return err
+	}
+	defer dest.client.Close()
+	if dest.BlockchainID != m.DestinationBlockchainID {
+		return fmt.Errorf("message %s goes to %s, not to %s", id.Hex(), m.DestinationBlockchainID.Hex(), to)
+	}
+	ux.Logger.PrintToUser("Message %s sent from %s to %s in %s, nonce %d", id.Hex(), from, to, m.TxHash.Hex(), m.Nonce)
+
+	if delivery, err := relayer.FindDelivery(ctx, dest.Endpoint, id); err != nil {
+		return err
+	} else if delivery != nil {
+		ux.Logger.PrintToUser("Already delivered: %s block %d, tx %s, by %s",
+			to, delivery.BlockNumber, delivery.TxHash.Hex(), delivery.Deliverer.Hex())
+		return nil
+	}
+	privateKey, err := chainPrivateKey(network, dest.spec, privateKeyFlags)
+	if err != nil {
+		return err
+	}
+	if rewardAddress == "" {
+		deliverer, err := evm.PrivateKeyToAddress(privateKey)
+		if err != nil {
+			return err
+		}
+		rewardAddress = deliverer.Hex()
+	}
+
+	receipt, err := source.client.TransactionReceipt(ctx, m.TxHash)
+	if err != nil {
+		return fmt.Errorf("failed to get receipt of %s on %s: %w", m.TxHash.Hex(), from, err)
+	}
+	warpLog, err := relayer.WarpLog(receipt, source.Messenger, id)
+	if err != nil {
+		return err
+	}
+	unsigned := evm.GetWarpMessagesFromLogs([]*types.Log{warpLog})
+	if len(unsigned) == 0 {
+		return fmt.Errorf("invalid Warp message for %s in %s", id.Hex(), m.TxHash.Hex())
+	}
+	signed, err := aggregateSignatures(ctx, unsigned[0], source.subnetID, peerURIs, apiPort, quorum)
+	if err != nil {
+		return err
+	}
+
+	tx, _, err := contract.TxToMethodWithWarpMessage(
+		dest.rpcURL,
+		false,
+		crypto.Address{},
+		privateKey,
+		crypto.Address(dest.Messenger),
+		signed,
+		big.NewInt(0),
+		"receive cross chain message",
+		nil,
+		"receiveCrossChainMessage(uint32, address)",
+		uint32(0),
+		crypto.HexToAddress(rewardAddress),
+	)
+	if err != nil {
+		return evm.TransactionError(tx, err, "failed to deliver message %s to %s", id.Hex(), to)
+	}
+	delivery, err := relayer.FindDelivery(ctx, dest.Endpoint, id)
+	if err != nil {
+		return err
+	}
+	if delivery == nil {
+		return fmt.Errorf("%s did not receive message %s in %s", to, id.Hex(), tx.Hash().Hex())
+	}
+	app.RecordHistory(history.Entry{
+		Operation: "warp message receive",
+		Network:   network.String(),
+		Chain:     to,
+		Params:    map[string]string{"from": from, "messageID": id.Hex()},
+		Result:    map[string]string{"txID": delivery.TxHash.Hex()},
+	})
+	outcome := "executed"
+	if !delivery.Executed {
+		outcome = "execution failed, can be retried"
+	}
+	ux.Logger.GreenCheckmarkToUser("Message %s delivered to %s in %s (%s)", id.Hex(), to, delivery.TxHash.Hex(), outcome)
+	return nil
+}
+
+// messageRoute finds the chains message id goes from and to: the source
+// among the chains with a Warp messenger deployed to network unless from
+// names it, and the destination from the message unless to names it
+func messageRoute(ctx context.Context, network models.Network, id common.Hash, from, to string) (string, string, relayer.Message, error) {
+	endpoints, closeEndpoints, err := warp.MessengerEndpoints(app, network)
+	if err != nil {
+		return "", "", relayer.Message{}, err
+	}
+	defer closeEndpoints()
+	sources := endpoints
+	if from != "" {
+		source, err := loadMessengerChain(network, from)
+		if err != nil {
+			return "", "", relayer.Message{}, err
+		}
+		defer source.client.Close()
+		sources = []relayer.Endpoint{source.Endpoint}
+	}
+	var (
+		m     relayer.Message
+		found bool
+	)
+	for _, source := range sources {
+		m, err = relayer.FindMessage(ctx, source, id)
+		if errors.Is(err, ethereum.NotFound) && from == "" {
+			continue
+		}
+		if err != nil {
+			return "", "", relayer.Message{}, err
+		}
+		from, found = source.Name, true
+		break
+	}
+	if !found {
+		return "", "", relayer.Message{}, fmt.Errorf("message %s not found on the chains with a Warp messenger deployed to %s, give the source chain with --from", id.Hex(), network.Name())
+	}
+	if to == "" {
+		for _, e := range endpoints {
+			if e.BlockchainID == m.DestinationBlockchainID {
+				to = e.Name
+			}
+		}
+		if to == "" {
+			return "", "", relayer.Message{}, fmt.Errorf("message %s goes to %s, not deployed from this CLI: give the destination chain with --to", id.Hex(), m.DestinationBlockchainID.Hex())
+		}
+	}
+	return from, to, m, nil
+}
+
+// aggregateSignatures has the validators of subnetID sign msg, reached
+// through peerURIs, until quorum percent of their stake signed
+func aggregateSignatures(
+	ctx context.Context,
+	msg *luxWarp.UnsignedMessage,
+	subnetID ids.ID,
+	peerURIs []string,
+	apiPort int,
+	quorum uint64,
+) (*luxWarp.Message, error) {
+	pchain, err := sigagg.NewPChainValidators(ctx, peerURIs[0])
+	if err != nil {
+		return nil, err
+	}
+	defer pchain.Close()
+	validators, err := pchain.Validators(ctx, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	peers, failed := sigagg.ConnectPeers(ctx, peerURIs)
+	defer peers.Close()
+	for uri, err := range failed {
+		ux.Logger.PrintToUser("Warning: %s did not respond: %s", uri, err)
+	}
+	nodeIDs := make([]ids.NodeID, 0, len(validators))
+	for _, v := range validators {
+		nodeIDs = append(nodeIDs, v.NodeID)
+	}
+	peers.Discover(ctx, nodeIDs, apiPort)
+	ux.Logger.PrintToUser("Aggregating the signatures of %d validators of %s", len(validators), subnetID)
+	signed, err := sigagg.New(pchain, peers, 1).Aggregate(ctx, msg, nil, subnetID, quorum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate the signatures, diagnose with 'lux warp sig-agg diagnose --subnet-id %s --message 0x%x': %w",
+			subnetID, msg.Bytes(), err)
+	}
+	return signed, nil
+}
+
+// loadMessengerChain locates the chain name, c-chain or a deployed chain, on
+// network with its Warp messenger and the subnet whose validators sign its
+// messages, and connects to it
+func loadMessengerChain(network models.Network, name string) (messengerChain, error) {
+	c := messengerChain{Endpoint: relayer.Endpoint{Name: name}, subnetID: constants.PrimaryNetworkID}
+	if name == cChainName {
+		c.spec.CChain = true
+	} else {
+		c.spec.BlockchainName = name
+		sc, err := app.LoadSidecar(name)
+		if err != nil {
+			return c, fmt.Errorf("failed to load sidecar: %w", err)
+		}
+		c.subnetID = sc.Networks[network.Name()].ChainID
+	}
+	sdkApp := app.GetSDKApp()
+	blockchainID, err := contract.GetBlockchainID(sdkApp, network, c.spec)
+	if err != nil {
+		return c, fmt.Errorf("%s: %w", name, err)
+	}
+	c.BlockchainID = common.Hash(blockchainID)
+	if c.rpcURL, _, err = contract.GetBlockchainEndpoints(sdkApp, network, c.spec, false, false); err != nil {
+		return c, err
+	}
+	if c.rpcURL == "" {
+		c.rpcURL = models.GetRPCEndpoint(network.Endpoint(), blockchainID.String())
+	}
+	_, messenger, err := contract.GetWarpInfo(sdkApp, network, c.spec, false, false, false)
+	if err != nil {
+		return c, err
+	}
+	if !common.IsHexAddress(messenger) {
+		return c, fmt.Errorf("%s has no Warp messenger on %s", name, network.Name())
+	}
+	c.Messenger = common.HexToAddress(messenger)
+	if c.client, err = ethclient.Dial(c.rpcURL); err != nil {
+		return c, fmt.Errorf("failed to connect to %s at %s: %w", name, c.rpcURL, err)
+	}
+	c.Client = c.client
+	return c, nil
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warpcmd
+
+import (
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/warp"
+	"github.com/luxfi/sdk/contract"
+	"github.com/spf13/cobra"
+)
+
+func newSyncRegistriesCmd() *cobra.Command {
+	var (
+		privateKeyFlags contract.PrivateKeyFlags
+		skipRelayer     bool
+		parallelism     int
+	)
+	cmd := &cobra.Command{
+		Use:   "sync-registries",
+		Short: "Register every messenger version in every chain's Warp registry",
+		Long: `Make the chains deployed to a network aware of each other's Warp messengers.
+
+Every messenger version used or registered on a chain is registered in the
+registries of the other chains it is deployed to, and the chains and their
+messengers are added to the relayer config. Chains deployed with the genesis
+predeployed messenger and registry are picked up automatically.
+
+A registry only accepts versions signed by the validators of its own chain.
+The first sync stages the registrations in the chain config; restart the
+chain's nodes with it, then sync again with a key to sign and send them.
+
+Chains are dialed and synced --parallel at a time; the failures of all of
+them are reported together.
+
+Example:
+  lux warp sync-registries --key mykey
+  lux warp sync-registries --testnet --genesis-key`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			network := targetNetwork()
+			opts := warp.SyncOptions{Parallelism: parallelism}
+			if privateKeyFlags.PrivateKey != "" || privateKeyFlags.KeyName != "" || privateKeyFlags.GenesisKey {
+				opts.PrivateKey = func(chainName string) (string, error) {
+					genesisPrivateKey := ""
+					if privateKeyFlags.GenesisKey {
+						var err error
+						_, genesisPrivateKey, err = contract.GetEVMChainPrefundedKey(
+							app.GetSDKApp(),
+							network,
+							contract.ChainSpec{BlockchainName: chainName},
+						)
+						if err != nil {
+							return "", err
+						}
+					}
+					return privateKeyFlags.GetPrivateKey(app.GetSDKApp(), genesisPrivateKey)
+				}
+			}
+			if !skipRelayer {
+				opts.RelayerConfigPath = app.GetLocalRelayerConfigPath()
+			}
+			return warp.SyncRegistries(app, network, opts)
+		},
+	}
+	addNetworkFlags(cmd)
+	privateKeyFlags.AddToCmd(cmd, "to pay for the registrations")
+	cmd.Flags().BoolVar(&skipRelayer, "skip-relayer", false, "do not update the relayer config")
+	cmd.Flags().IntVar(&parallelism, "parallel", utils.DefaultParallelism, "how many chains to sync at once")
+	return cmd
+}
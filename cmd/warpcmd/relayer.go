@@ -0,0 +1,142 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warpcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/warp"
+	"github.com/luxfi/cli/pkg/warp/relayer"
+	"github.com/luxfi/sdk/models"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// Network target flags
+var (
+	mainnet bool
+	testnet bool
+	devnet  bool
+)
+
+func addNetworkFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&mainnet, "mainnet", "m", false, "Target mainnet")
+	cmd.Flags().BoolVarP(&testnet, "testnet", "t", false, "Target testnet")
+	cmd.Flags().BoolVarP(&devnet, "devnet", "d", false, "Target devnet")
+}
+
+// targetNetwork returns the network the flags select, the local one by default
+func targetNetwork() models.Network {
+	switch {
+	case mainnet:
+		return models.Mainnet
+	case testnet:
+		return models.Testnet
+	case devnet:
+		return models.Devnet
+	default:
+		return models.Local
+	}
+}
+
+func newRelayerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "relayer",
+		Short: "Inspect the Warp message relayer",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newRelayerStatusCmd())
+	return cmd
+}
+
+func newRelayerStatusCmd() *cobra.Command {
+	var (
+		blocks      uint64
+		stallBlocks uint64
+	)
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the health of every relayer route",
+		Long: `Show the health of the routes between the chains deployed to a network.
+
+For every pair of chains with a Warp messenger, the messages the source chain
+sent in its last --blocks blocks are checked for delivery on the destination.
+A route is stalled when a message waits longer than --stall-blocks source
+blocks.
+
+Example:
+  lux warp relayer status
+  lux warp relayer status --testnet --blocks 5000`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return relayerStatus(cmd.Context(), blocks, stallBlocks)
+		},
+	}
+	addNetworkFlags(cmd)
+	cmd.Flags().Uint64Var(&blocks, "blocks", 1000, "source blocks to scan for sent messages")
+	cmd.Flags().Uint64Var(&stallBlocks, "stall-blocks", 100, "source blocks a message may wait before its route is stalled")
+	return cmd
+}
+
+func relayerStatus(ctx context.Context, blocks, stallBlocks uint64) error {
+	network := targetNetwork()
+	endpoints, closeEndpoints, err := warp.MessengerEndpoints(app, network)
+	if err != nil {
+		return err
+	}
+	defer closeEndpoints()
+	if len(endpoints) < 2 {
+		return fmt.Errorf("relayer routes need two chains with a Warp messenger deployed to %s, found %d", network.Name(), len(endpoints))
+	}
+
+	running, err := relayer.Running(app.GetLocalRelayerRunPath(network))
+	if err != nil {
+		return err
+	}
+	state := "not running"
+	if running {
+		state = "running"
+	}
+	ux.Logger.PrintToUser("Relayer on %s: %s", network.Name(), state)
+	ux.Logger.PrintToUser("Log: %s", app.GetLocalRelayerLogPath(network))
+	ux.Logger.PrintToUser("")
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Route", "Health", "Sent", "Delivered", "Pending", "Last Delivered Nonce", "Oldest Pending")
+	opts := relayer.StatusOptions{Blocks: blocks, StallBlocks: stallBlocks}
+	for _, source := range endpoints {
+		for _, dest := range endpoints {
+			if source.BlockchainID == dest.BlockchainID {
+				continue
+			}
+			route := source.Name + " -> " + dest.Name
+			s, err := relayer.CheckRoute(ctx, relayer.Route{Source: source, Destination: dest}, opts)
+			if err != nil {
+				_ = table.Append([]string{route, "error: " + err.Error(), "", "", "", "", ""})
+				continue
+			}
+			lastNonce, oldest := "-", "-"
+			if s.Delivered > 0 {
+				lastNonce = strconv.FormatUint(s.LastDeliveredNonce, 10)
+			}
+			if s.OldestPending != nil {
+				oldest = fmt.Sprintf("nonce %d, block %d", s.OldestPending.Nonce, s.OldestPending.BlockNumber)
+			}
+			_ = table.Append([]string{
+				route, string(s.Health), strconv.Itoa(s.Sent), strconv.Itoa(s.Delivered), strconv.Itoa(s.Pending), lastNonce, oldest,
+			})
+		}
+	}
+	_ = table.Render()
+	if !running {
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("Pending messages are not delivered while the relayer is down")
+	}
+	return nil
+}
@@ -0,0 +1,343 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warpcmd
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/luxfi/cli/pkg/localnet"
+	"github.com/luxfi/cli/pkg/sigagg"
+	"github.com/luxfi/cli/pkg/signatureaggregator"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+	"github.com/luxfi/warp"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newSigAggCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sig-agg",
+		Short: "Run and diagnose the signature aggregator service",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newSigAggServeCmd())
+	cmd.AddCommand(newSigAggDiagnoseCmd())
+	return cmd
+}
+
+func newSigAggServeCmd() *cobra.Command {
+	var (
+		port         int
+		peerURIs     []string
+		cacheSize    int
+		fetchTimeout time.Duration
+		retries      int
+	)
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve signature aggregation over HTTP until stopped",
+		Long: `Run a persistent signature aggregator for a network.
+
+The service keeps its connections to the validators open and caches their
+BLS signatures by message hash, so repeated operations, like validator
+manager changes, sign from the cache instead of asking every validator
+again. Commands needing aggregated signatures use the service of their
+network.
+
+Validators are reached through the node APIs at --peer-uri, by default the
+nodes of the local network, or the public API node of other networks.
+Commands add the nodes of their cluster and their --aggregator-extra-endpoints
+through POST /peers. A validator whose request fails or passes --fetch-timeout
+is asked again up to --retries times, through the public IP its peers know
+it by when that differs from the failed endpoint.
+
+API:
+  POST /aggregate-signatures   aggregate signatures over a message
+  GET  /health                 whether the service is up
+  GET  /stats                  cache and signature counters
+  GET  /progress               per-validator progress of the aggregations
+  POST /peers                  reach more validators: {"uris": [...]}
+
+Example:
+  lux warp sig-agg serve
+  lux warp sig-agg serve --testnet --peer-uri http://10.0.0.1:9630 --peer-uri http://10.0.0.2:9630`,
+		RunE: func(*cobra.Command, []string) error {
+			return serveSigAgg(port, peerURIs, cacheSize, fetchTimeout, retries)
+		},
+	}
+	addNetworkFlags(cmd)
+	cmd.Flags().IntVar(&port, "port", signatureaggregator.DefaultSignatureAggregatorPort, "port to serve the API on")
+	cmd.Flags().StringSliceVar(&peerURIs, "peer-uri", nil, "API URI of a validator node (repeatable)")
+	cmd.Flags().IntVar(&cacheSize, "cache-size", sigagg.DefaultCacheSize, "messages to cache signatures for")
+	cmd.Flags().DurationVar(&fetchTimeout, "fetch-timeout", sigagg.DefaultFetchTimeout, "time limit of each signature request to a validator")
+	cmd.Flags().IntVar(&retries, "retries", sigagg.DefaultRetries, "times a failed signature request is retried")
+	return cmd
+}
+
+func serveSigAgg(port int, peerURIs []string, cacheSize int, fetchTimeout time.Duration, retries int) error {
+	network := targetNetwork()
+	if len(peerURIs) == 0 {
+		var err error
+		if peerURIs, err = defaultPeerURIs(network); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	peers, err := sigagg.NewPeers(ctx, peerURIs)
+	if err != nil {
+		return err
+	}
+	defer peers.Close()
+	validators, err := sigagg.NewPChainValidators(ctx, peerURIs[0])
+	if err != nil {
+		return err
+	}
+	defer validators.Close()
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return fmt.Errorf("failed to serve the signature aggregator: %w", err)
+	}
+	aggregator := sigagg.New(validators, peers, cacheSize)
+	aggregator.FetchTimeout = fetchTimeout
+	aggregator.Retries = retries
+	server := &http.Server{Handler: sigagg.Handler(aggregator), ReadHeaderTimeout: 10 * time.Second}
+	if err := signatureaggregator.WriteServiceRunFile(app, network, port); err != nil {
+		_ = listener.Close()
+		return err
+	}
+	defer signatureaggregator.RemoveServiceRunFile(app, network)
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	ux.Logger.PrintToUser("Signature aggregator for %s serving on http://%s with %d peer(s) (Ctrl+C to stop)", network.Name(), listener.Addr(), len(peers.NodeIDs()))
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	stats := aggregator.Stats()
+	ux.Logger.PrintToUser("Stopped after %d aggregations: %d signatures from cache, %d fetched, %d failed",
+		stats.Aggregations, stats.CacheHits, stats.Fetched, stats.Failed)
+	return nil
+}
+
+// defaultPeerURIs returns the nodes of the local network, or the API node
+// of other networks
+func defaultPeerURIs(network models.Network) ([]string, error) {
+	if network != models.Local {
+		return []string{network.Endpoint()}, nil
+	}
+	uris, err := localnet.GetLocalClusterURIs(app, localnet.LocalClusterNameConst)
+	if err != nil {
+		return nil, fmt.Errorf("no local network to aggregate signatures for: %w", err)
+	}
+	if len(uris) == 0 {
+		return nil, errors.New("the local network has no nodes")
+	}
+	return uris, nil
+}
+
+func newSigAggDiagnoseCmd() *cobra.Command {
+	var (
+		validationID string
+		subnetID     string
+		message      string
+		peerURIs     []string
+		apiPort      int
+		quorum       uint64
+	)
+	cmd := &cobra.Command{
+		Use:   "diagnose",
+		Short: "Show which validators stall a signature aggregation",
+		Long: `Diagnose a signature aggregation that does not reach its quorum, such as
+the one of a validator registration or of an L1 conversion.
+
+The L1 of --validation-id is looked up on the P-Chain; a registration not
+on the P-Chain yet needs --subnet-id instead. Every validator of the L1 is
+contacted through its info API, to check it responds and runs with the BLS
+key registered on the P-Chain. With --message, the unsigned Warp message
+hex, validators are also asked to sign it.
+
+Validators are reached at --peer-uri, by default the nodes of the local
+network or the public API node of other networks, and at their public IP
+on --api-port when one of these nodes is connected to them.
+
+The report gives the stake weight collected against the weight the quorum
+requires, and the validators to fix to reach it.
+
+Example:
+  lux warp sig-agg diagnose --mainnet --validation-id 2Z4Uq...
+  lux warp sig-agg diagnose --testnet --subnet-id 2FaBx... --message 0x0000...`,
+		Args: cobra.NoArgs,
+		RunE: func(*cobra.Command, []string) error {
+			return diagnoseSigAgg(validationID, subnetID, message, peerURIs, apiPort, quorum)
+		},
+	}
+	addNetworkFlags(cmd)
+	cmd.Flags().StringVar(&validationID, "validation-id", "", "validation ID of the validator being registered")
+	cmd.Flags().StringVar(&subnetID, "subnet-id", "", "subnet ID of the L1 whose validators sign")
+	cmd.Flags().StringVar(&message, "message", "", "unsigned Warp message to have the validators sign, as hex")
+	cmd.Flags().StringSliceVar(&peerURIs, "peer-uri", nil, "API URI of a validator node (repeatable)")
+	cmd.Flags().IntVar(&apiPort, "api-port", 9630, "API port of the validators found through the peers")
+	cmd.Flags().Uint64Var(&quorum, "quorum", sigagg.DefaultQuorumPercentage, "stake percentage that must sign")
+	return cmd
+}
+
+func diagnoseSigAgg(validationIDStr, subnetIDStr, messageHex string, peerURIs []string, apiPort int, quorum uint64) error {
+	if validationIDStr == "" && subnetIDStr == "" {
+		return errors.New("give --validation-id or --subnet-id")
+	}
+	var msg *warp.UnsignedMessage
+	if messageHex != "" {
+		msgBytes, err := hex.DecodeString(strings.TrimPrefix(messageHex, "0x"))
+		if err != nil {
+			return fmt.Errorf("invalid message hex: %w", err)
+		}
+		if msg, err = warp.ParseUnsignedMessage(msgBytes); err != nil {
+			return fmt.Errorf("invalid message: %w", err)
+		}
+	}
+	network := targetNetwork()
+	if len(peerURIs) == 0 {
+		var err error
+		if peerURIs, err = defaultPeerURIs(network); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := utils.GetAPILargeContext()
+	defer cancel()
+	pchain, err := sigagg.NewPChainValidators(ctx, peerURIs[0])
+	if err != nil {
+		return err
+	}
+	defer pchain.Close()
+	subnetID, err := diagnosedSubnet(ctx, pchain, validationIDStr, subnetIDStr)
+	if err != nil {
+		return err
+	}
+	validators, err := pchain.Validators(ctx, subnetID)
+	if err != nil {
+		return err
+	}
+
+	peers, failed := sigagg.ConnectPeers(ctx, peerURIs)
+	defer peers.Close()
+	nodeIDs := make([]ids.NodeID, 0, len(validators))
+	for _, v := range validators {
+		nodeIDs = append(nodeIDs, v.NodeID)
+	}
+	peers.Discover(ctx, nodeIDs, apiPort)
+	d, err := sigagg.Diagnose(ctx, validators, peers, msg, quorum)
+	if err != nil {
+		return err
+	}
+
+	for uri, err := range failed {
+		ux.Logger.PrintToUser("Warning: %s did not respond: %s", uri, err)
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	header := []any{"Node ID", "Weight", "Responded", "BLS Key"}
+	if d.MessageChecked {
+		header = append(header, "Signed")
+	}
+	table.Header(append(header, "Problem")...)
+	for _, n := range d.Nodes {
+		uri, _ := peers.URI(n.NodeID)
+		row := []string{
+			n.NodeID.String(),
+			strconv.FormatUint(n.Weight, 10),
+			yesNo(n.Responded, uri),
+			keyState(n),
+		}
+		if d.MessageChecked {
+			row = append(row, yesNo(n.Signed, ""))
+		}
+		_ = table.Append(append(row, n.Problem))
+	}
+	_ = table.Render()
+
+	collected := "ready to sign"
+	if d.MessageChecked {
+		collected = "signed"
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Stake weight %s: %d of %d, %d required (%d%%)",
+		collected, d.CollectedWeight, d.TotalWeight, d.RequiredWeight, d.QuorumPercentage)
+	if d.QuorumReached() {
+		if d.MessageChecked {
+			ux.Logger.PrintToUser("The quorum is reached: check the aggregator service with 'lux warp sig-agg serve'")
+		} else {
+			ux.Logger.PrintToUser("The quorum can be reached: check the validators sign the message with --message")
+		}
+		return nil
+	}
+	ux.Logger.PrintToUser("The quorum is not reached. To reach it, fix:")
+	for _, n := range d.ToFix() {
+		ux.Logger.PrintToUser("  %s (weight %d): %s", n.NodeID, n.Weight, n.Fix)
+	}
+	return nil
+}
+
+// diagnosedSubnet returns the subnet of the validation ID, or the given one
+func diagnosedSubnet(ctx context.Context, pchain *sigagg.PChainValidators, validationIDStr, subnetIDStr string) (ids.ID, error) {
+	if subnetIDStr != "" {
+		subnetID, err := ids.FromString(subnetIDStr)
+		if err != nil {
+			return ids.Empty, fmt.Errorf("invalid subnet ID: %w", err)
+		}
+		return subnetID, nil
+	}
+	validationID, err := ids.FromString(validationIDStr)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("invalid validation ID: %w", err)
+	}
+	l1Validator, err := pchain.L1Validator(ctx, validationID)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("%w: a registration not on the P-Chain yet needs --subnet-id", err)
+	}
+	ux.Logger.PrintToUser("Validation %s of node %s on L1 %s", validationID, l1Validator.NodeID, l1Validator.SubnetID)
+	ux.Logger.PrintToUser("")
+	return l1Validator.SubnetID, nil
+}
+
+func yesNo(ok bool, detail string) string {
+	if !ok {
+		return "no"
+	}
+	if detail != "" {
+		return "yes, " + detail
+	}
+	return "yes"
+}
+
+func keyState(n sigagg.NodeDiagnosis) string {
+	switch {
+	case !n.Responded:
+		return "-"
+	case n.KeyMatches:
+		return "registered"
+	default:
+		return "mismatch"
+	}
+}
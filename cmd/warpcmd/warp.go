@@ -13,8 +13,11 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var app *application.Lux
+
 // NewCmd creates the warp command for the Lux CLI
-func NewCmd(_ *application.Lux) *cobra.Command {
+func NewCmd(injectedApp *application.Lux) *cobra.Command {
+	app = injectedApp
 	cmd := &cobra.Command{
 		Use:   "warp",
 		Short: "Cross-chain messaging protocol operations",
@@ -24,10 +27,15 @@ This command provides tools for creating, signing, verifying, and relaying
 cross-chain messages between Lux networks.
 
 Commands:
-  create    Create a new cross-chain message
-  sign      Sign a message with validator key
-  verify    Verify a signed message
-  relay     Start message relayer`,
+  create           Create a new cross-chain message
+  sign             Sign a message with validator key
+  verify           Verify a signed message
+  relay            Start message relayer
+  relayer          Show relayer route health
+  message          Trace, send and deliver messages without the relayer
+  sync-registries  Register every messenger version in every chain's registry
+  sig-agg          Run and diagnose the signature aggregator service
+  bridge           Deploy token bridges between chains and transfer over them`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
 		},
@@ -37,6 +45,11 @@ Commands:
 	cmd.AddCommand(newSignCmd())
 	cmd.AddCommand(newVerifyCmd())
 	cmd.AddCommand(newRelayCmd())
+	cmd.AddCommand(newRelayerCmd())
+	cmd.AddCommand(newMessageCmd())
+	cmd.AddCommand(newSyncRegistriesCmd())
+	cmd.AddCommand(newSigAggCmd())
+	cmd.AddCommand(newBridgeCmd())
 
 	return cmd
 }
@@ -0,0 +1,242 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package accesslist manages which networks may reach the ports of the nodes
+// of a cluster. The list kept for a cluster is the source of truth, and the
+// firewalls of the nodes, e.g. their security groups, are synced to it.
+// Only the ports the list manages, those it was given a rule for, are
+// synced, so the rules of the other ports, e.g. monitoring, are left alone.
+// A port stays managed once its last rule is removed, so removing a rule
+// always revokes it.
+package accesslist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/luxfi/constants"
+)
+
+// FileName is the name of the access list in the cluster directory
+const FileName = "access.json"
+
+var (
+	ErrRuleExists   = errors.New("rule already exists")
+	ErrRuleNotFound = errors.New("rule not found")
+
+	// Ports are the named node ports
+	Ports = map[string]int32{
+		"ssh": constants.SSHTCPPort,
+		"api": constants.LuxdAPIPort,
+		"p2p": constants.LuxdP2PPort,
+	}
+)
+
+// Rule allows CIDR to reach Port over tcp
+type Rule struct {
+	CIDR        string `json:"cidr"`
+	Port        int32  `json:"port"`
+	Description string `json:"description,omitempty"`
+}
+
+func (r Rule) String() string {
+	return fmt.Sprintf("%s → %s", r.CIDR, PortName(r.Port))
+}
+
+func (r Rule) same(o Rule) bool {
+	return r.CIDR == o.CIDR && r.Port == o.Port
+}
+
+// List is the access list of a cluster
+type List struct {
+	Rules []Rule `json:"rules"`
+	// Ports are the managed ports, kept when their last rule is removed
+	Ports []int32 `json:"ports,omitempty"`
+}
+
+// Load reads the list at path, returning an empty one when there is no such
+// file
+func Load(path string) (*List, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &List{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var l List
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("invalid access list %s: %w", path, err)
+	}
+	return &l, nil
+}
+
+// Save writes the list to path
+func (l *List) Save(path string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Add allows cidr to reach port
+func (l *List) Add(cidr string, port int32, description string) (Rule, error) {
+	cidr, err := NormalizeCIDR(cidr)
+	if err != nil {
+		return Rule{}, err
+	}
+	r := Rule{CIDR: cidr, Port: port, Description: description}
+	if slices.ContainsFunc(l.Rules, r.same) {
+		return Rule{}, fmt.Errorf("%w: %s", ErrRuleExists, r)
+	}
+	l.Rules = append(l.Rules, r)
+	if !slices.Contains(l.Ports, port) {
+		l.Ports = append(l.Ports, port)
+		slices.Sort(l.Ports)
+	}
+	return r, nil
+}
+
+// Remove stops allowing cidr to reach port
+func (l *List) Remove(cidr string, port int32) (Rule, error) {
+	cidr, err := NormalizeCIDR(cidr)
+	if err != nil {
+		return Rule{}, err
+	}
+	r := Rule{CIDR: cidr, Port: port}
+	i := slices.IndexFunc(l.Rules, r.same)
+	if i < 0 {
+		return Rule{}, fmt.Errorf("%w: %s", ErrRuleNotFound, r)
+	}
+	r = l.Rules[i]
+	l.Rules = slices.Delete(l.Rules, i, i+1)
+	return r, nil
+}
+
+// Manages reports whether the list manages port
+func (l *List) Manages(port int32) bool {
+	return slices.Contains(l.ManagedPorts(), port)
+}
+
+// ManagedPorts returns the ports the list manages, sorted. Lists saved
+// before the ports were kept manage the ports they have rules for.
+func (l *List) ManagedPorts() []int32 {
+	ports := slices.Clone(l.Ports)
+	for _, r := range l.Rules {
+		if !slices.Contains(ports, r.Port) {
+			ports = append(ports, r.Port)
+		}
+	}
+	slices.Sort(ports)
+	return ports
+}
+
+// Diff returns the rules to add to and to remove from a firewall having
+// current to match the list, on the ports the list manages
+func (l *List) Diff(current []Rule) (add, remove []Rule) {
+	managed := l.ManagedPorts()
+	for _, r := range l.Rules {
+		if !slices.ContainsFunc(current, r.same) {
+			add = append(add, r)
+		}
+	}
+	for _, r := range current {
+		if slices.Contains(managed, r.Port) && !slices.ContainsFunc(l.Rules, r.same) {
+			remove = append(remove, r)
+		}
+	}
+	return add, remove
+}
+
+// Firewall is the firewall of some nodes of the cluster
+type Firewall interface {
+	Name() string
+	// Rules returns the tcp ingress rules of single ports
+	Rules(ctx context.Context) ([]Rule, error)
+	Allow(ctx context.Context, r Rule) error
+	Revoke(ctx context.Context, r Rule) error
+}
+
+// Changes are the rules a sync added and removed
+type Changes struct {
+	Firewall string
+	Added    []Rule
+	Removed  []Rule
+}
+
+// Sync makes the firewall match the list. The rules are added before the
+// stale ones are removed, so replacing a rule never locks out its users.
+// With dryRun, the changes are only computed.
+func Sync(ctx context.Context, l *List, fw Firewall, dryRun bool) (Changes, error) {
+	changes := Changes{Firewall: fw.Name()}
+	current, err := fw.Rules(ctx)
+	if err != nil {
+		return changes, fmt.Errorf("%s: failed to get the rules: %w", fw.Name(), err)
+	}
+	add, remove := l.Diff(current)
+	if dryRun {
+		changes.Added, changes.Removed = add, remove
+		return changes, nil
+	}
+	for _, r := range add {
+		if err := fw.Allow(ctx, r); err != nil {
+			return changes, fmt.Errorf("%s: failed to allow %s: %w", fw.Name(), r, err)
+		}
+		changes.Added = append(changes.Added, r)
+	}
+	for _, r := range remove {
+		if err := fw.Revoke(ctx, r); err != nil {
+			return changes, fmt.Errorf("%s: failed to revoke %s: %w", fw.Name(), r, err)
+		}
+		changes.Removed = append(changes.Removed, r)
+	}
+	return changes, nil
+}
+
+// NormalizeCIDR returns the canonical form of an IPv4 CIDR, turning a single
+// IP into a /32
+func NormalizeCIDR(s string) (string, error) {
+	cidr := strings.TrimSpace(s)
+	if !strings.Contains(cidr, "/") {
+		cidr += "/32"
+	}
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil || ip.To4() == nil {
+		return "", fmt.Errorf("invalid IPv4 address or CIDR %q", s)
+	}
+	return ipNet.String(), nil
+}
+
+// ParsePort parses a port number or one of the names of Ports
+func ParsePort(s string) (int32, error) {
+	if port, ok := Ports[strings.ToLower(s)]; ok {
+		return port, nil
+	}
+	port, err := strconv.ParseUint(s, 10, 16)
+	if err != nil || port == 0 {
+		return 0, fmt.Errorf("invalid port %q, use a number or one of ssh, api, p2p", s)
+	}
+	return int32(port), nil
+}
+
+// PortName returns the name of port, or its number
+func PortName(port int32) string {
+	for name, p := range Ports {
+		if p == port {
+			return name
+		}
+	}
+	return strconv.Itoa(int(port))
+}
@@ -0,0 +1,162 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package accesslist
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFirewall struct {
+	rules     []Rule
+	calls     []string
+	revokeErr error
+}
+
+func (f *fakeFirewall) Name() string {
+	return "sg-1"
+}
+
+func (f *fakeFirewall) Rules(context.Context) ([]Rule, error) {
+	return slices.Clone(f.rules), nil
+}
+
+func (f *fakeFirewall) Allow(_ context.Context, r Rule) error {
+	f.calls = append(f.calls, "allow "+r.String())
+	f.rules = append(f.rules, r)
+	return nil
+}
+
+func (f *fakeFirewall) Revoke(_ context.Context, r Rule) error {
+	if f.revokeErr != nil {
+		return f.revokeErr
+	}
+	f.calls = append(f.calls, "revoke "+r.String())
+	f.rules = slices.DeleteFunc(f.rules, r.same)
+	return nil
+}
+
+func TestList(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), FileName)
+
+	l, err := Load(path)
+	require.NoError(err)
+	require.Empty(l.Rules)
+
+	r, err := l.Add("10.1.2.3/8", 9630, "office")
+	require.NoError(err)
+	require.Equal("10.0.0.0/8", r.CIDR)
+	_, err = l.Add("10.0.0.0/8", 9630, "")
+	require.ErrorIs(err, ErrRuleExists)
+	_, err = l.Add("10.0.0.0/8", 22, "")
+	require.NoError(err)
+	r, err = l.Add("1.2.3.4", 9651, "")
+	require.NoError(err)
+	require.Equal("1.2.3.4/32", r.CIDR)
+	_, err = l.Add("not-an-ip", 22, "")
+	require.ErrorContains(err, "invalid IPv4 address or CIDR")
+	_, err = l.Add("2001:db8::/32", 22, "")
+	require.ErrorContains(err, "invalid IPv4 address or CIDR")
+	require.Equal([]int32{22, 9630, 9651}, l.ManagedPorts())
+	require.NoError(l.Save(path))
+
+	l, err = Load(path)
+	require.NoError(err)
+	require.Len(l.Rules, 3)
+	r, err = l.Remove("10.0.0.0/8", 9630)
+	require.NoError(err)
+	require.Equal("office", r.Description)
+	_, err = l.Remove("10.0.0.0/8", 9630)
+	require.ErrorIs(err, ErrRuleNotFound)
+}
+
+func TestSync(t *testing.T) {
+	require := require.New(t)
+	l := &List{Rules: []Rule{
+		{CIDR: "10.0.0.0/8", Port: 9630},
+		{CIDR: "1.2.3.4/32", Port: 22},
+	}}
+	fw := &fakeFirewall{rules: []Rule{
+		{CIDR: "0.0.0.0/0", Port: 9630},
+		{CIDR: "1.2.3.4/32", Port: 22},
+		// not managed by the list
+		{CIDR: "0.0.0.0/0", Port: 23101},
+	}}
+
+	changes, err := Sync(context.Background(), l, fw, true)
+	require.NoError(err)
+	require.Equal([]Rule{{CIDR: "10.0.0.0/8", Port: 9630}}, changes.Added)
+	require.Equal([]Rule{{CIDR: "0.0.0.0/0", Port: 9630}}, changes.Removed)
+	require.Empty(fw.calls)
+
+	changes, err = Sync(context.Background(), l, fw, false)
+	require.NoError(err)
+	require.Equal("sg-1", changes.Firewall)
+	require.Equal([]string{"allow 10.0.0.0/8 → api", "revoke 0.0.0.0/0 → api"}, fw.calls)
+	require.ElementsMatch([]Rule{
+		{CIDR: "1.2.3.4/32", Port: 22},
+		{CIDR: "0.0.0.0/0", Port: 23101},
+		{CIDR: "10.0.0.0/8", Port: 9630},
+	}, fw.rules)
+
+	// in sync
+	changes, err = Sync(context.Background(), l, fw, false)
+	require.NoError(err)
+	require.Empty(changes.Added)
+	require.Empty(changes.Removed)
+
+	fw.rules = append(fw.rules, Rule{CIDR: "5.5.5.5/32", Port: 22})
+	fw.revokeErr = errors.New("permission denied")
+	_, err = Sync(context.Background(), l, fw, false)
+	require.ErrorContains(err, "sg-1: failed to revoke 5.5.5.5/32 → ssh: permission denied")
+}
+
+func TestRemoveLastRuleOfPort(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), FileName)
+	l := &List{}
+	_, err := l.Add("10.0.0.0/8", 9630, "")
+	require.NoError(err)
+	fw := &fakeFirewall{rules: []Rule{{CIDR: "0.0.0.0/0", Port: 22}}}
+	_, err = Sync(context.Background(), l, fw, false)
+	require.NoError(err)
+
+	_, err = l.Remove("10.0.0.0/8", 9630)
+	require.NoError(err)
+	require.NoError(l.Save(path))
+	l, err = Load(path)
+	require.NoError(err)
+	require.Empty(l.Rules)
+	require.True(l.Manages(9630))
+	require.False(l.Manages(22))
+
+	changes, err := Sync(context.Background(), l, fw, false)
+	require.NoError(err)
+	require.Equal([]Rule{{CIDR: "10.0.0.0/8", Port: 9630}}, changes.Removed)
+	require.Equal([]Rule{{CIDR: "0.0.0.0/0", Port: 22}}, fw.rules)
+}
+
+func TestManagedPortsOfOldList(t *testing.T) {
+	l := &List{Rules: []Rule{{CIDR: "10.0.0.0/8", Port: 9651}, {CIDR: "1.2.3.4/32", Port: 22}}}
+	require.Equal(t, []int32{22, 9651}, l.ManagedPorts())
+}
+
+func TestParsePort(t *testing.T) {
+	require := require.New(t)
+	port, err := ParsePort("API")
+	require.NoError(err)
+	require.Equal(int32(9630), port)
+	port, err = ParsePort("9090")
+	require.NoError(err)
+	require.Equal(int32(9090), port)
+	require.Equal("9090", PortName(port))
+	_, err = ParsePort("70000")
+	require.ErrorContains(err, "invalid port")
+}
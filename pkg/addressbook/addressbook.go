@@ -0,0 +1,231 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package addressbook keeps human-readable labels of P-Chain, X-Chain,
+// C-Chain and EVM addresses in ~/.lux, so commands take @label wherever they
+// take an address and show the label next to the addresses they print.
+package addressbook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luxfi/address"
+)
+
+// FileName is the address book file in the CLI base dir
+const FileName = "addressbook.json"
+
+// RefPrefix marks a reference to a label where an address is expected
+const RefPrefix = "@"
+
+// Kind is the kind of chain an address belongs to
+type Kind string
+
+const (
+	P   Kind = "P"
+	X   Kind = "X"
+	C   Kind = "C"
+	EVM Kind = "EVM"
+)
+
+// ErrUnknownLabel is returned for a label not in the address book
+var ErrUnknownLabel = errors.New("no such label in the address book")
+
+var (
+	labelRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	evmRegexp   = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+)
+
+// Entry is a labeled address
+type Entry struct {
+	Label   string    `json:"label"`
+	Address string    `json:"address"`
+	Kind    Kind      `json:"kind"`
+	AddedAt time.Time `json:"addedAt"`
+}
+
+// Book is an address book stored at a path
+type Book struct {
+	path    string
+	entries map[string]Entry
+}
+
+// Load reads the address book at path. A missing address book is empty.
+func Load(path string) (*Book, error) {
+	b := &Book{path: path, entries: map[string]Entry{}}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: Reading from app's data directory
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return b, nil
+		}
+		return nil, err
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid address book %s: %w", path, err)
+	}
+	for _, e := range entries {
+		b.entries[e.Label] = e
+	}
+	return b, nil
+}
+
+// Path returns the file of b
+func (b *Book) Path() string {
+	return b.path
+}
+
+// Save writes b to its file
+func (b *Book) Save() error {
+	data, err := json.MarshalIndent(b.Entries(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(b.path), 0o750); err != nil {
+		return err
+	}
+	tmp := b.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, b.path)
+}
+
+// Add labels addr, which must be a P-, X- or C- prefixed bech32 address or a
+// 0x EVM address. Labels and addresses are unique.
+func (b *Book) Add(label, addr string) (Entry, error) {
+	label = strings.TrimPrefix(label, RefPrefix)
+	if !labelRegexp.MatchString(label) {
+		return Entry{}, fmt.Errorf("invalid label %q, expected letters, digits, '.', '_' and '-'", label)
+	}
+	if e, ok := b.entries[label]; ok {
+		return Entry{}, fmt.Errorf("label %s is already used for %s", label, e.Address)
+	}
+	kind, err := ParseKind(addr)
+	if err != nil {
+		return Entry{}, err
+	}
+	if other, ok := b.LabelOf(addr); ok {
+		return Entry{}, fmt.Errorf("%s is already labeled %s", addr, other)
+	}
+	e := Entry{Label: label, Address: addr, Kind: kind, AddedAt: time.Now().UTC()}
+	b.entries[label] = e
+	return e, nil
+}
+
+// Remove deletes label
+func (b *Book) Remove(label string) error {
+	label = strings.TrimPrefix(label, RefPrefix)
+	if _, ok := b.entries[label]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownLabel, label)
+	}
+	delete(b.entries, label)
+	return nil
+}
+
+// Entries returns the entries of b, by label
+func (b *Book) Entries() []Entry {
+	entries := make([]Entry, 0, len(b.entries))
+	for _, e := range b.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Label < entries[j].Label })
+	return entries
+}
+
+// Get returns the entry of label
+func (b *Book) Get(label string) (Entry, bool) {
+	e, ok := b.entries[strings.TrimPrefix(label, RefPrefix)]
+	return e, ok
+}
+
+// Resolve returns the address ref stands for: the address labeled by an
+// @label reference, or ref itself
+func (b *Book) Resolve(ref string) (string, error) {
+	if !IsRef(ref) {
+		return ref, nil
+	}
+	e, ok := b.Get(ref)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownLabel, ref)
+	}
+	return e.Address, nil
+}
+
+// ResolveAll resolves each of refs
+func (b *Book) ResolveAll(refs []string) ([]string, error) {
+	addrs := make([]string, len(refs))
+	for i, ref := range refs {
+		addr, err := b.Resolve(ref)
+		if err != nil {
+			return nil, err
+		}
+		addrs[i] = addr
+	}
+	return addrs, nil
+}
+
+// LabelOf returns the label of addr. A bech32 address matches the entry of
+// the same address on another chain, the key owning both.
+func (b *Book) LabelOf(addr string) (string, bool) {
+	key := normalize(addr)
+	if key == "" {
+		return "", false
+	}
+	for _, e := range b.Entries() {
+		if normalize(e.Address) == key {
+			return e.Label, true
+		}
+	}
+	return "", false
+}
+
+// Annotate returns addr followed by its label, when it has one
+func (b *Book) Annotate(addr string) string {
+	if label, ok := b.LabelOf(addr); ok {
+		return fmt.Sprintf("%s (%s%s)", addr, RefPrefix, label)
+	}
+	return addr
+}
+
+// IsRef reports whether s is an @label reference
+func IsRef(s string) bool {
+	return strings.HasPrefix(s, RefPrefix)
+}
+
+// ParseKind returns the kind of addr, a P-, X- or C- prefixed bech32
+// address or a 0x EVM address
+func ParseKind(addr string) (Kind, error) {
+	if evmRegexp.MatchString(addr) {
+		return EVM, nil
+	}
+	chain, _, _, err := address.Parse(addr)
+	if err == nil {
+		switch kind := Kind(strings.ToUpper(chain)); kind {
+		case P, X, C:
+			return kind, nil
+		}
+	}
+	return "", fmt.Errorf("invalid address %q, expected a P-, X- or C- prefixed bech32 address or a 0x EVM address", addr)
+}
+
+// normalize returns the key addr is matched on: the lowercase hex of an EVM
+// address, or the bech32 part of a chain address
+func normalize(addr string) string {
+	addr = strings.TrimSpace(addr)
+	if evmRegexp.MatchString(addr) {
+		return strings.ToLower(addr)
+	}
+	if _, rest, ok := strings.Cut(addr, "-"); ok {
+		return rest
+	}
+	return addr
+}
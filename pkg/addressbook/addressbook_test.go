@@ -0,0 +1,71 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package addressbook
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/luxfi/address"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressBook(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), FileName)
+	b, err := Load(path)
+	require.NoError(err)
+	require.Empty(b.Entries())
+
+	raw := make([]byte, 20)
+	raw[19] = 1
+	pAddr, err := address.Format("P", "lux", raw)
+	require.NoError(err)
+	xAddr, err := address.Format("X", "lux", raw)
+	require.NoError(err)
+	evmAddr := "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"
+
+	e, err := b.Add("treasury", pAddr)
+	require.NoError(err)
+	require.Equal(P, e.Kind)
+	e, err = b.Add("@deployer", evmAddr)
+	require.NoError(err)
+	require.Equal(EVM, e.Kind)
+	require.Equal("deployer", e.Label)
+
+	_, err = b.Add("treasury", evmAddr)
+	require.ErrorContains(err, "already used")
+	_, err = b.Add("other", xAddr)
+	require.ErrorContains(err, "already labeled treasury")
+	_, err = b.Add("bad label", evmAddr)
+	require.ErrorContains(err, "invalid label")
+	_, err = b.Add("bad", "lux1abc")
+	require.ErrorContains(err, "invalid address")
+	require.NoError(b.Save())
+
+	b, err = Load(path)
+	require.NoError(err)
+	require.Len(b.Entries(), 2)
+	require.Equal("deployer", b.Entries()[0].Label)
+
+	addr, err := b.Resolve("@treasury")
+	require.NoError(err)
+	require.Equal(pAddr, addr)
+	addr, err = b.Resolve(evmAddr)
+	require.NoError(err)
+	require.Equal(evmAddr, addr)
+	_, err = b.Resolve("@nobody")
+	require.ErrorIs(err, ErrUnknownLabel)
+	addrs, err := b.ResolveAll([]string{"@deployer", pAddr})
+	require.NoError(err)
+	require.Equal([]string{evmAddr, pAddr}, addrs)
+
+	require.Equal(xAddr+" (@treasury)", b.Annotate(xAddr))
+	require.Equal("0x8db97c7cece249c2b98bdc0226cc4c2a57bf52fc (@deployer)", b.Annotate("0x8db97c7cece249c2b98bdc0226cc4c2a57bf52fc"))
+	require.Equal("0x0000000000000000000000000000000000000001", b.Annotate("0x0000000000000000000000000000000000000001"))
+
+	require.NoError(b.Remove("@treasury"))
+	require.ErrorIs(b.Remove("treasury"), ErrUnknownLabel)
+	require.Equal(xAddr, b.Annotate(xAddr))
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package application
+
+import (
+	"path/filepath"
+
+	"github.com/luxfi/cli/pkg/addressbook"
+)
+
+// GetAddressBookPath returns the address book of labeled addresses
+// (~/.lux/addressbook.json)
+func (app *Lux) GetAddressBookPath() string {
+	return filepath.Join(app.GetBaseDir(), addressbook.FileName)
+}
+
+// LoadAddressBook reads the address book
+func (app *Lux) LoadAddressBook() (*addressbook.Book, error) {
+	return addressbook.Load(app.GetAddressBookPath())
+}
+
+// ResolveAddress returns the address of an @label reference of the address
+// book, or ref itself when it is not one
+func (app *Lux) ResolveAddress(ref string) (string, error) {
+	if !addressbook.IsRef(ref) {
+		return ref, nil
+	}
+	book, err := app.LoadAddressBook()
+	if err != nil {
+		return "", err
+	}
+	return book.Resolve(ref)
+}
+
+// ResolveAddresses resolves the @label references of refs
+func (app *Lux) ResolveAddresses(refs []string) ([]string, error) {
+	addrs := make([]string, len(refs))
+	for i, ref := range refs {
+		addr, err := app.ResolveAddress(ref)
+		if err != nil {
+			return nil, err
+		}
+		addrs[i] = addr
+	}
+	return addrs, nil
+}
+
+// AnnotateAddress returns addr followed by its label in the address book,
+// when it has one. An unreadable address book annotates nothing.
+func (app *Lux) AnnotateAddress(addr string) string {
+	book, err := app.LoadAddressBook()
+	if err != nil {
+		return addr
+	}
+	return book.Annotate(addr)
+}
+
+// AnnotateAddresses annotates each of addrs with its label
+func (app *Lux) AnnotateAddresses(addrs []string) []string {
+	annotated := make([]string, len(addrs))
+	book, err := app.LoadAddressBook()
+	for i, addr := range addrs {
+		annotated[i] = addr
+		if err == nil {
+			annotated[i] = book.Annotate(addr)
+		}
+	}
+	return annotated
+}
@@ -8,10 +8,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/luxfi/cli/pkg/config"
+	"github.com/luxfi/cli/pkg/home"
 	"github.com/luxfi/cli/pkg/prompts"
+	"github.com/luxfi/cli/pkg/statefile"
 	"github.com/luxfi/cli/pkg/types"
+	"github.com/luxfi/cli/pkg/vmregistry"
 	"github.com/luxfi/constants"
 	luxlog "github.com/luxfi/log"
 	sdkapp "github.com/luxfi/sdk/application"
@@ -27,6 +31,12 @@ type Lux struct {
 	*sdkapp.Lux                  // Embed SDK's Lux type
 	CliPrompt   prompts.Prompter // CLI-specific prompter
 	Conf        *config.Config   // CLI-specific config
+	LockWait    time.Duration    // how long mutating commands wait for the lock (--wait)
+	Home        home.Home        // CLI home in use (--home, LUX_HOME or 'lux home switch')
+
+	// signingKeys are the addresses of the keys the command signed with,
+	// for its receipt
+	signingKeys []string
 }
 
 func New() *Lux {
@@ -65,6 +75,40 @@ func (app *Lux) GetAggregatorLogDir(clusterName string) string {
 	return filepath.Join(app.GetBaseDir(), "aggregator", "logs")
 }
 
+// GetAggregatorRunPath returns the run file of the signature aggregator
+// service of a network
+func (app *Lux) GetAggregatorRunPath(networkName string) string {
+	return filepath.Join(app.GetRunDir(), fmt.Sprintf("aggregator-%s.run", networkName))
+}
+
+// GetSnapshotSchedulerRunPath returns the run file of the snapshot
+// scheduler
+func (app *Lux) GetSnapshotSchedulerRunPath() string {
+	return filepath.Join(app.GetRunDir(), "snapshot-scheduler.run")
+}
+
+// GetSnapshotSchedulerLogPath returns the log of the snapshot scheduler
+func (app *Lux) GetSnapshotSchedulerLogPath() string {
+	return filepath.Join(app.GetRunDir(), "snapshot-scheduler.log")
+}
+
+// GetAABundlerRunPath returns the run file of the local ERC-4337 bundler
+// of a chain
+func (app *Lux) GetAABundlerRunPath(chainName string) string {
+	return filepath.Join(app.GetRunDir(), "aa-bundler-"+chainName+".run")
+}
+
+// GetAABundlerLogPath returns the log of the local ERC-4337 bundler of a
+// chain
+func (app *Lux) GetAABundlerLogPath(chainName string) string {
+	return filepath.Join(app.GetRunDir(), "aa-bundler-"+chainName+".log")
+}
+
+// GetForkLogPath returns the log of the local fork of a remote EVM chain
+func (app *Lux) GetForkLogPath() string {
+	return filepath.Join(app.GetRunDir(), "fork.log")
+}
+
 // Adapter types to bridge CLI and SDK interfaces
 // promptAdapter wraps CLI's Prompter to implement SDK's Prompter interface
 type promptAdapter struct {
@@ -155,6 +199,11 @@ func (app *Lux) GetWarpRelayerBinDir() string {
 	return filepath.Join(app.GetBaseDir(), "bin", "warp-relayer")
 }
 
+// GetVMRegistry returns the registry of installed VMs (~/.lux/vms)
+func (app *Lux) GetVMRegistry() *vmregistry.Registry {
+	return vmregistry.New(filepath.Join(app.GetBaseDir(), constants.VMDir))
+}
+
 func (app *Lux) GetMonitoringDashboardDir() string {
 	return filepath.Join(app.GetBaseDir(), "monitoring", "dashboards")
 }
@@ -344,14 +393,9 @@ func (app *Lux) GetBasePath() string {
 // GetClusterConfig loads cluster configuration from disk
 func (app *Lux) GetClusterConfig(clusterName string) (map[string]interface{}, error) {
 	clusterConfigPath := filepath.Join(app.GetBaseDir(), "clusters", clusterName, "config.json")
-	data, err := os.ReadFile(clusterConfigPath) //nolint:gosec // G304: Reading from app's data directory
-	if err != nil {
-		return nil, err
-	}
-
 	var config map[string]interface{}
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, err
+	if _, err := statefile.ReadJSON(clusterConfigPath, statefile.ClusterConfig, &config); err != nil {
+		return nil, withFsckHint(err)
 	}
 
 	return config, nil
@@ -367,22 +411,13 @@ func (app *Lux) SetClusterConfig(clusterName string, config map[string]interface
 		return err
 	}
 
-	data, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(clusterConfigPath, data, WriteReadReadPerms)
+	return statefile.WriteJSON(clusterConfigPath, statefile.ClusterConfig, config, WriteReadReadPerms)
 }
 
 // SaveClustersConfig saves the clusters configuration
 func (app *Lux) SaveClustersConfig(config map[string]interface{}) error {
 	clustersPath := filepath.Join(app.GetBaseDir(), constants.ClustersConfigFileName)
-	data, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(clustersPath, data, WriteReadReadPerms)
+	return statefile.WriteJSON(clustersPath, statefile.ClustersConfig, config, WriteReadReadPerms)
 }
 
 // LoadClusterNodeConfig loads node configuration for a cluster
@@ -428,6 +463,11 @@ func (app *Lux) GetClusterYAMLFilePath(clusterName string) string {
 	return filepath.Join(app.GetBaseDir(), "clusters", clusterName, constants.ClusterYAMLFileName)
 }
 
+// GetClusterAccessListPath returns the path to a cluster's access list
+func (app *Lux) GetClusterAccessListPath(clusterName string) string {
+	return filepath.Join(app.GetBaseDir(), "clusters", clusterName, "access.json")
+}
+
 // All the SDK methods are now provided by embedded type
 // These duplicate SDK functionality and should be removed
 
@@ -500,26 +540,20 @@ func (app *Lux) SaveNetworkState(state *NetworkState) error {
 	} else {
 		statePath = app.GetNetworkStateFile()
 	}
-	data, err := json.MarshalIndent(state, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal network state: %w", err)
-	}
-	if err := os.WriteFile(statePath, data, WriteReadReadPerms); err != nil {
-		return fmt.Errorf("failed to write network state: %w", err)
+	if err := statefile.WriteJSON(statePath, statefile.NetworkState, state, WriteReadReadPerms); err != nil {
+		return err
 	}
+	app.advertiseNetwork(state)
 	return nil
 }
 
 // SaveNetworkStateForType saves network state to the network-specific state file
 func (app *Lux) SaveNetworkStateForType(networkType string, state *NetworkState) error {
 	statePath := app.GetNetworkStateFileForType(networkType)
-	data, err := json.MarshalIndent(state, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal network state: %w", err)
-	}
-	if err := os.WriteFile(statePath, data, WriteReadReadPerms); err != nil {
-		return fmt.Errorf("failed to write network state: %w", err)
+	if err := statefile.WriteJSON(statePath, statefile.NetworkState, state, WriteReadReadPerms); err != nil {
+		return err
 	}
+	app.advertiseNetwork(state)
 	return nil
 }
 
@@ -527,17 +561,12 @@ func (app *Lux) SaveNetworkStateForType(networkType string, state *NetworkState)
 // For network-specific state, use LoadNetworkStateForType
 func (app *Lux) LoadNetworkState() (*NetworkState, error) {
 	statePath := app.GetNetworkStateFile()
-	data, err := os.ReadFile(statePath) //nolint:gosec // G304: Reading from app's data directory
-	if err != nil {
+	var state NetworkState
+	if _, err := statefile.ReadJSON(statePath, statefile.NetworkState, &state); err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil // No state file = no running network
 		}
-		return nil, fmt.Errorf("failed to read network state: %w", err)
-	}
-
-	var state NetworkState
-	if err := json.Unmarshal(data, &state); err != nil {
-		return nil, fmt.Errorf("failed to parse network state: %w", err)
+		return nil, fmt.Errorf("failed to read network state: %w", withFsckHint(err))
 	}
 	return &state, nil
 }
@@ -545,17 +574,12 @@ func (app *Lux) LoadNetworkState() (*NetworkState, error) {
 // LoadNetworkStateForType loads the network state from the network-specific state file
 func (app *Lux) LoadNetworkStateForType(networkType string) (*NetworkState, error) {
 	statePath := app.GetNetworkStateFileForType(networkType)
-	data, err := os.ReadFile(statePath) //nolint:gosec // G304: Reading from app's data directory
-	if err != nil {
+	var state NetworkState
+	if _, err := statefile.ReadJSON(statePath, statefile.NetworkState, &state); err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil // No state file = no running network of this type
 		}
-		return nil, fmt.Errorf("failed to read network state: %w", err)
-	}
-
-	var state NetworkState
-	if err := json.Unmarshal(data, &state); err != nil {
-		return nil, fmt.Errorf("failed to parse network state: %w", err)
+		return nil, fmt.Errorf("failed to read network state: %w", withFsckHint(err))
 	}
 	return &state, nil
 }
@@ -576,6 +600,7 @@ func (app *Lux) ClearNetworkStateForType(networkType string) error {
 	if err := os.Remove(statePath); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to remove network state: %w", err)
 	}
+	app.withdrawNetwork(networkType)
 	return nil
 }
 
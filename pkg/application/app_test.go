@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/luxfi/cli/pkg/statefile"
 	"github.com/luxfi/constants"
 	"github.com/luxfi/ids"
 	luxlog "github.com/luxfi/log"
@@ -277,6 +278,39 @@ func Test_genesisExists(t *testing.T) {
 	require.NoError(err)
 }
 
+func TestStateFiles(t *testing.T) {
+	require := require.New(t)
+	ap := newTestApp(t)
+
+	sc := &models.Sidecar{Name: chainName1, VM: models.EVM}
+	require.NoError(ap.CreateSidecar(sc))
+	require.NoError(ap.UpdateSidecar(sc))
+	require.NoError(ap.SaveNetworkState(&NetworkState{Running: true}))
+
+	files, dirs, err := ap.StateFiles()
+	require.NoError(err)
+	require.ElementsMatch([]statefile.File{
+		{Path: ap.GetSidecarPath(chainName1), Kind: statefile.Sidecar},
+		{Path: ap.GetNetworkStateFile(), Kind: statefile.NetworkState},
+	}, files)
+	require.Contains(dirs, filepath.Dir(ap.GetSidecarPath(chainName1)))
+	for _, f := range files {
+		require.Equal(statefile.StatusOK, statefile.Check(f).Status)
+	}
+
+	// a sidecar truncated by a crash points to fsck, which restores it
+	sidecarPath := ap.GetSidecarPath(chainName1)
+	require.NoError(os.WriteFile(sidecarPath, []byte(`{"Name": "TE`), 0o600))
+	_, err = ap.LoadSidecar(chainName1)
+	require.ErrorIs(err, statefile.ErrCorrupt)
+	require.ErrorContains(err, "lux state fsck")
+	_, err = statefile.Repair(statefile.Check(statefile.File{Path: sidecarPath, Kind: statefile.Sidecar}))
+	require.NoError(err)
+	control, err := ap.LoadSidecar(chainName1)
+	require.NoError(err)
+	require.Equal(*sc, control)
+}
+
 func newTestApp(t *testing.T) *Lux {
 	tempDir := t.TempDir()
 	app := New()
@@ -0,0 +1,33 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package application
+
+import (
+	"github.com/luxfi/cli/pkg/discovery"
+	"go.uber.org/zap"
+)
+
+// advertiseNetwork advertises the network of state, while it runs, in the
+// well-known directory of the machine, for 'lux discover' and other tools.
+// Other tools finding it is a convenience, so a failure is logged.
+func (app *Lux) advertiseNetwork(state *NetworkState) {
+	if state.NetworkType == "" {
+		return
+	}
+	if !state.Running {
+		app.withdrawNetwork(state.NetworkType)
+		return
+	}
+	n := discovery.NewNetwork(state.NetworkType, state.NetworkID, state.APIEndpoint)
+	if err := discovery.Publish(discovery.DefaultDir(), n); err != nil {
+		app.Log.Warn("failed to advertise the running network", zap.String("network", state.NetworkType), zap.Error(err))
+	}
+}
+
+// withdrawNetwork removes the advertisement of the network networkType
+func (app *Lux) withdrawNetwork(networkType string) {
+	if err := discovery.Withdraw(discovery.DefaultDir(), networkType); err != nil {
+		app.Log.Warn("failed to withdraw the network advertisement", zap.String("network", networkType), zap.Error(err))
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package application
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/sdk/models"
+	"go.uber.org/zap"
+)
+
+// GetHistoryPath returns the changelog of state-changing operations
+// (~/.lux/history.jsonl)
+func (app *Lux) GetHistoryPath() string {
+	return filepath.Join(app.GetBaseDir(), history.FileName)
+}
+
+// RecordHistory appends e to the changelog and, in audit mode, issues the
+// receipt of mainnet operations. The operation already happened, so a
+// failure to record it is logged rather than returned.
+func (app *Lux) RecordHistory(e history.Entry) {
+	e = history.Stamp(e)
+	if err := history.Append(app.GetHistoryPath(), e); err != nil {
+		app.Log.Warn("failed to record operation in history! This is non-critical but is logged", zap.Error(err))
+	}
+	if !app.AuditMode() || !strings.EqualFold(e.Network, models.Mainnet.String()) {
+		return
+	}
+	r, path, err := app.IssueReceipt(context.Background(), e)
+	if r != nil {
+		ux.Logger.PrintToUser("Receipt %s: %s", r.ID, path)
+	}
+	if err != nil {
+		ux.Logger.PrintToUser("Warning: %s", err)
+		app.Log.Warn("failed to issue operation receipt", zap.Error(err))
+	}
+}
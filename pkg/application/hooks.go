@@ -0,0 +1,29 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package application
+
+import (
+	"context"
+
+	"github.com/luxfi/cli/pkg/hooks"
+)
+
+// GetHooksConfigPath returns the config file hooks are read from, the one
+// given with --config if any
+func (app *Lux) GetHooksConfigPath() string {
+	if app.Conf != nil && app.Conf.GetConfigPath() != "" {
+		return app.Conf.GetConfigPath()
+	}
+	return app.GetConfigPath()
+}
+
+// RunWithHooks runs op between the pre and post hooks of operation
+// registered in the CLI config file
+func (app *Lux) RunWithHooks(operation string, hc hooks.Context, op func() error) error {
+	c, err := hooks.Load(app.GetHooksConfigPath())
+	if err != nil {
+		return err
+	}
+	return c.Wrap(context.Background(), operation, hc, op)
+}
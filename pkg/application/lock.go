@@ -0,0 +1,228 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package application
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/ux"
+)
+
+const (
+	// LockFileName is the lock held in the base dir (~/.lux/cli.lock) by
+	// commands mutating run directories and sidecars
+	LockFileName = "cli.lock"
+
+	// DefaultLockWait is how long --wait waits for the lock when given no
+	// duration
+	DefaultLockWait = 10 * time.Minute
+
+	lockPollInterval = 500 * time.Millisecond
+)
+
+// Lock is held by a CLI running a mutating command, such as network start
+// or deploy, so two of them don't corrupt the same run directories
+type Lock struct {
+	ID        string    `json:"id"`
+	PID       int       `json:"pid"`
+	Host      string    `json:"host"`
+	Owner     string    `json:"owner"`
+	Operation string    `json:"operation"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	path string
+	// nested locks are taken by a command already holding the lock, and
+	// leave it to the outer one to release
+	nested   bool
+	released bool
+}
+
+// LockedError is returned when another CLI holds the lock
+type LockedError struct {
+	Lock *Lock
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("another lux command is running: %s by %s (PID %d) since %s. "+
+		"Wait for it with --wait, or remove a stale lock with 'lux lock break'",
+		e.Lock.Operation, e.Lock.Owner, e.Lock.PID, e.Lock.CreatedAt.Local().Format(time.RFC3339))
+}
+
+// Stale tells if the CLI holding l died without releasing it. Locks taken
+// on another host, through a shared base dir, are never considered stale.
+func (l *Lock) Stale() bool {
+	return l.Host == lockHost() && !utils.IsProcessRunning(l.PID)
+}
+
+// Release removes l if it is still held, and is a no-op after the first call
+func (l *Lock) Release() error {
+	if l == nil || l.nested || l.released {
+		return nil
+	}
+	l.released = true
+	held, err := readLock(l.path)
+	if err != nil || held == nil || held.ID != l.ID {
+		return err
+	}
+	return os.Remove(l.path)
+}
+
+// GetLockPath returns the lock of mutating commands (~/.lux/cli.lock)
+func (app *Lux) GetLockPath() string {
+	return filepath.Join(app.GetBaseDir(), LockFileName)
+}
+
+// ReadLock returns the lock held, or nil when none is
+func (app *Lux) ReadLock() (*Lock, error) {
+	return readLock(app.GetLockPath())
+}
+
+// AcquireLock takes the lock for operation. Locks left behind by a dead CLI
+// are taken over. While another CLI holds it, AcquireLock waits up to
+// LockWait (--wait) for it to be released, then fails with a LockedError.
+// Taking the lock again from the process holding it succeeds.
+func (app *Lux) AcquireLock(operation string) (*Lock, error) {
+	deadline := time.Now().Add(app.LockWait)
+	waiting := false
+	for {
+		l, err := tryLock(app.GetLockPath(), operation)
+		var lockedErr *LockedError
+		if !errors.As(err, &lockedErr) || !time.Now().Before(deadline) {
+			return l, err
+		}
+		if !waiting {
+			waiting = true
+			ux.Logger.PrintToUser("Waiting for %s by %s (PID %d) to finish...",
+				lockedErr.Lock.Operation, lockedErr.Lock.Owner, lockedErr.Lock.PID)
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// BreakLock removes whatever lock is held and returns it, for locks left
+// behind by a CLI that died. A corrupt lock is removed too, and returned as
+// nil.
+func (app *Lux) BreakLock() (*Lock, error) {
+	held, _ := app.ReadLock()
+	if err := os.Remove(app.GetLockPath()); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return held, nil
+}
+
+func readLock(path string) (*Lock, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: Reading the app's lock file
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	l := &Lock{path: path}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("invalid lock %s, remove it with 'lux lock break': %w", path, err)
+	}
+	return l, nil
+}
+
+func tryLock(path, operation string) (*Lock, error) {
+	held, err := readLock(path)
+	if err != nil {
+		return nil, err
+	}
+	if held != nil {
+		if held.PID == os.Getpid() && held.Host == lockHost() {
+			held.nested = true
+			return held, nil
+		}
+		if !held.Stale() {
+			return nil, &LockedError{Lock: held}
+		}
+		if err := removeStaleLock(path, held); err != nil {
+			return nil, err
+		}
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	l := &Lock{
+		ID:        hex.EncodeToString(id),
+		PID:       os.Getpid(),
+		Host:      lockHost(),
+		Owner:     lockOwner(),
+		Operation: operation,
+		CreatedAt: time.Now().UTC(),
+		path:      path,
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	// the lock is written aside then linked in place, which fails if it
+	// exists, so it is taken atomically and never read half written
+	tmpPath := path + "." + l.ID
+	if err := os.WriteFile(tmpPath, data, WriteReadReadPerms); err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmpPath)
+	if err := os.Link(tmpPath, path); err != nil {
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		held, err := readLock(path)
+		if err != nil {
+			return nil, err
+		}
+		if held == nil {
+			return tryLock(path, operation)
+		}
+		return nil, &LockedError{Lock: held}
+	}
+	return l, nil
+}
+
+// removeStaleLock removes the lock stale unless another CLI replaced it
+// meanwhile. The lock is moved aside first so a lock taken since is put back
+// rather than removed.
+func removeStaleLock(path string, stale *Lock) error {
+	asidePath := path + ".stale." + stale.ID
+	if err := os.Rename(path, asidePath); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	aside, err := readLock(asidePath)
+	if err == nil && aside != nil && aside.ID != stale.ID {
+		// put back the lock taken since; if yet another one took its place,
+		// that one wins
+		_ = os.Link(asidePath, path)
+	}
+	return os.Remove(asidePath)
+}
+
+func lockHost() string {
+	host, _ := os.Hostname()
+	return host
+}
+
+// lockOwner names the operator running the CLI, as user@host
+func lockOwner() string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	return name + "@" + lockHost()
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestLock makes the lock look held by the process pid
+func writeTestLock(t *testing.T, app *Lux, pid int) {
+	t.Helper()
+	data, err := json.Marshal(&Lock{
+		ID:        "other",
+		PID:       pid,
+		Host:      lockHost(),
+		Owner:     "other@host",
+		Operation: "deploy",
+		CreatedAt: time.Now(),
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(app.GetLockPath(), data, WriteReadReadPerms))
+}
+
+func TestAcquireLock(t *testing.T) {
+	require := require.New(t)
+	app := newTestApp(t)
+
+	l, err := app.AcquireLock("network start")
+	require.NoError(err)
+	held, err := app.ReadLock()
+	require.NoError(err)
+	require.Equal(l.ID, held.ID)
+	require.Equal(os.Getpid(), held.PID)
+	require.Equal("network start", held.Operation)
+
+	// a nested command leaves the lock to the outer one
+	nested, err := app.AcquireLock("network stop")
+	require.NoError(err)
+	require.NoError(nested.Release())
+	held, err = app.ReadLock()
+	require.NoError(err)
+	require.NotNil(held)
+
+	require.NoError(l.Release())
+	require.NoError(l.Release())
+	held, err = app.ReadLock()
+	require.NoError(err)
+	require.Nil(held)
+}
+
+func TestAcquireLockHeld(t *testing.T) {
+	require := require.New(t)
+	app := newTestApp(t)
+	writeTestLock(t, app, os.Getppid())
+
+	_, err := app.AcquireLock("network start")
+	var lockedErr *LockedError
+	require.True(errors.As(err, &lockedErr))
+	require.Equal("deploy", lockedErr.Lock.Operation)
+	require.False(lockedErr.Lock.Stale())
+
+	// --wait takes the lock once released
+	app.LockWait = 5 * time.Second
+	go func() {
+		time.Sleep(2 * lockPollInterval)
+		_ = os.Remove(app.GetLockPath())
+	}()
+	l, err := app.AcquireLock("network start")
+	require.NoError(err)
+	require.NoError(l.Release())
+}
+
+func TestAcquireLockStale(t *testing.T) {
+	require := require.New(t)
+	app := newTestApp(t)
+	dead := exec.Command(os.Args[0], "-test.run=^$")
+	require.NoError(dead.Run())
+	writeTestLock(t, app, dead.Process.Pid)
+
+	held, err := app.ReadLock()
+	require.NoError(err)
+	require.True(held.Stale())
+	l, err := app.AcquireLock("network start")
+	require.NoError(err)
+	require.Equal(os.Getpid(), l.PID)
+	require.NoError(l.Release())
+}
+
+func TestBreakLock(t *testing.T) {
+	require := require.New(t)
+	app := newTestApp(t)
+
+	held, err := app.BreakLock()
+	require.NoError(err)
+	require.Nil(held)
+
+	writeTestLock(t, app, os.Getppid())
+	held, err = app.BreakLock()
+	require.NoError(err)
+	require.Equal("other", held.ID)
+	_, err = os.Stat(app.GetLockPath())
+	require.True(os.IsNotExist(err))
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/receipt"
+)
+
+// GetReceiptsDir returns where receipts of mainnet operations are stored
+// (~/.lux/receipts)
+func (app *Lux) GetReceiptsDir() string {
+	return filepath.Join(app.GetBaseDir(), receipt.DirName)
+}
+
+// AuditMode tells if mainnet operations issue receipts
+func (app *Lux) AuditMode() bool {
+	return app.Conf != nil && app.Conf.GetConfigBoolValue(receipt.ConfigKey)
+}
+
+// GetReceiptCountersigner returns the KMS countersigning receipts, or nil
+// when none is configured
+func (app *Lux) GetReceiptCountersigner() *receipt.KMSCountersigner {
+	if app.Conf == nil {
+		return nil
+	}
+	kmsURL := app.Conf.GetConfigStringValue(receipt.KMSURLConfigKey)
+	if kmsURL == "" {
+		return nil
+	}
+	return receipt.NewKMSCountersigner(kmsURL, app.Conf.GetConfigStringValue(receipt.KMSKeyConfigKey),
+		os.Getenv(receipt.KMSAPIKeyEnvVar))
+}
+
+// AddSigningKeys records the addresses of keys the command signs with, so
+// its receipt names them
+func (app *Lux) AddSigningKeys(addresses ...string) {
+	for _, addr := range addresses {
+		if !slices.Contains(app.signingKeys, addr) {
+			app.signingKeys = append(app.signingKeys, addr)
+		}
+	}
+}
+
+// IssueReceipt signs the receipt of the operation e with the key of this
+// machine, has it countersigned by the KMS if one is configured, and stores
+// it in the receipts directory. A receipt the KMS failed to countersign is
+// stored all the same, and returned with the error.
+func (app *Lux) IssueReceipt(ctx context.Context, e history.Entry) (*receipt.Receipt, string, error) {
+	key, err := receipt.LoadOrCreateSigningKey(filepath.Join(app.GetReceiptsDir(), receipt.SigningKeyFileName))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load the receipt signing key: %w", err)
+	}
+	r := receipt.FromEntry(e, app.signingKeys)
+	if err := r.Sign(key); err != nil {
+		return nil, "", err
+	}
+	var countersignErr error
+	if kms := app.GetReceiptCountersigner(); kms != nil {
+		if err := kms.Countersign(ctx, r); err != nil {
+			countersignErr = fmt.Errorf("failed to countersign receipt %s, it is only signed locally: %w", r.ID, err)
+		}
+	}
+	path, err := receipt.Save(app.GetReceiptsDir(), r)
+	if err != nil {
+		return nil, "", err
+	}
+	return r, path, countersignErr
+}
@@ -0,0 +1,143 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package application
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/luxfi/cli/pkg/configschema"
+	"github.com/luxfi/cli/pkg/statefile"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+)
+
+// The sidecar writers below shadow those of the SDK, which write in place,
+// so that sidecars are written atomically and versioned like the rest of
+// the state of the CLI.
+
+// CreateSidecar writes the sidecar of a new chain
+func (app *Lux) CreateSidecar(sc *models.Sidecar) error {
+	if sc.TokenName == "" {
+		sc.TokenName = constants.DefaultTokenName
+	}
+	sidecarPath := app.GetSidecarPath(sc.Name)
+	if err := os.MkdirAll(filepath.Dir(sidecarPath), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	return app.UpdateSidecar(sc)
+}
+
+// LoadSidecar loads the sidecar of chainName
+func (app *Lux) LoadSidecar(chainName string) (models.Sidecar, error) {
+	var sc models.Sidecar
+	_, err := statefile.ReadJSON(app.GetSidecarPath(chainName), statefile.Sidecar, &sc)
+	if sc.TokenName == "" {
+		sc.TokenName = constants.DefaultTokenName
+	}
+	return sc, withFsckHint(err)
+}
+
+// UpdateSidecar writes sc, stamped with the current sidecar version
+func (app *Lux) UpdateSidecar(sc *models.Sidecar) error {
+	// only apply the version on a write
+	sc.Version = constants.SidecarVersion
+	return app.WriteSidecarFile(sc)
+}
+
+// WriteSidecarFile writes sc as is
+func (app *Lux) WriteSidecarFile(sc *models.Sidecar) error {
+	return statefile.WriteJSON(app.GetSidecarPath(sc.Name), statefile.Sidecar, sc, WriteReadReadPerms)
+}
+
+// UpdateSidecarNetworks records the chain and blockchain IDs of sc on network
+func (app *Lux) UpdateSidecarNetworks(
+	sc *models.Sidecar,
+	network models.Network,
+	chainID ids.ID,
+	blockchainID ids.ID,
+) error {
+	if sc.Networks == nil {
+		sc.Networks = make(map[string]models.NetworkData)
+	}
+	sc.Networks[network.String()] = models.NetworkData{
+		ChainID:      chainID,
+		BlockchainID: blockchainID,
+		RPCVersion:   sc.RPCVersion,
+	}
+	if err := app.UpdateSidecar(sc); err != nil {
+		return fmt.Errorf("creation of chains and chain was successful, but failed to update sidecar: %w", err)
+	}
+	return nil
+}
+
+// StateFileSchema returns the schema of the state files of kind, derived
+// from the type they are read into
+func StateFileSchema(kind statefile.Kind) *configschema.Schema {
+	switch kind {
+	case statefile.Sidecar:
+		return statefile.Schema(&models.Sidecar{})
+	case statefile.NetworkState:
+		return statefile.Schema(&NetworkState{})
+	}
+	// cluster configs are read into maps
+	return statefile.Schema(&map[string]interface{}{})
+}
+
+// StateFiles returns the JSON state files of the base dir written through
+// statefile: network states, the clusters config, cluster configs and
+// sidecars, and the directories holding them
+func (app *Lux) StateFiles() ([]statefile.File, []string, error) {
+	baseDir := app.GetBaseDir()
+	patterns := []struct {
+		glob string
+		kind statefile.Kind
+	}{
+		{filepath.Join(baseDir, constants.LocalNetworkMetaFile), statefile.NetworkState},
+		{filepath.Join(baseDir, "*_network_state.json"), statefile.NetworkState},
+		{filepath.Join(baseDir, constants.ClustersConfigFileName), statefile.ClustersConfig},
+		{filepath.Join(baseDir, "clusters", "*", "config.json"), statefile.ClusterConfig},
+		{filepath.Join(app.GetChainDir(), "*", constants.SidecarFileName), statefile.Sidecar},
+	}
+	var files []statefile.File
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern.glob)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, match := range matches {
+			files = append(files, statefile.File{Path: match, Kind: pattern.kind})
+		}
+	}
+
+	dirs := []string{baseDir}
+	for _, parent := range []string{filepath.Join(baseDir, "clusters"), app.GetChainDir()} {
+		entries, err := os.ReadDir(parent)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				dirs = append(dirs, filepath.Join(parent, entry.Name()))
+			}
+		}
+	}
+	sort.Strings(dirs)
+	return files, dirs, nil
+}
+
+// withFsckHint points to lux state fsck when err is a corrupted state file
+func withFsckHint(err error) error {
+	if errors.Is(err, statefile.ErrCorrupt) {
+		return fmt.Errorf("%w (run 'lux state fsck --repair')", err)
+	}
+	return err
+}
@@ -0,0 +1,45 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package application
+
+import (
+	"path/filepath"
+
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/watchdog"
+)
+
+// GetWatchdogDir returns the directory of the configs, states and logs of
+// the bootstrap validator watchdogs
+func (app *Lux) GetWatchdogDir() string {
+	return filepath.Join(app.GetRunDir(), "watchdogs")
+}
+
+// GetWatchdogLogPath returns the log file of a watchdog and its luxd
+func (app *Lux) GetWatchdogLogPath(name string) string {
+	return filepath.Join(app.GetWatchdogDir(), name+".log")
+}
+
+// LoadWatchdogStates returns the states of the watchdogs of this machine.
+// The node of a watchdog whose process died without shutting down is no
+// longer supervised, which is reported as a crash alert.
+func (app *Lux) LoadWatchdogStates() ([]watchdog.State, error) {
+	states, err := watchdog.ListStates(app.GetWatchdogDir())
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range states {
+		if s.Stopped || utils.IsProcessRunning(s.PID) {
+			continue
+		}
+		states[i].Stopped = true
+		states[i].Running = false
+		states[i].Alerts = []watchdog.Alert{{
+			Kind:    watchdog.AlertCrash,
+			Message: "watchdog process exited, luxd is no longer supervised",
+			Since:   s.UpdatedAt,
+		}}
+	}
+	return states, nil
+}
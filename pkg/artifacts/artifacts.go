@@ -0,0 +1,270 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package artifacts bundles the record of a chain launch for auditors: the
+// genesis, chain config and sidecar the chain was launched from, where it is
+// deployed, its contract addresses and transaction IDs, and how that was
+// checked, in one archive signed by the operator machine.
+package artifacts
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/luxfi/cli/pkg/receipt"
+)
+
+const (
+	// ManifestName is the first entry of a bundle, describing and signing
+	// the others
+	ManifestName = "MANIFEST.json"
+
+	formatVersion = 1
+)
+
+// ErrInvalidBundle is returned for a bundle whose files or manifest don't
+// match its signature
+var ErrInvalidBundle = errors.New("invalid artifacts bundle")
+
+// Check statuses
+const (
+	CheckPass    = "pass"
+	CheckFail    = "fail"
+	CheckUnknown = "unknown"
+)
+
+// Check is a verification of a deployment and its outcome
+type Check struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Deployment is where the chain is deployed on one network
+type Deployment struct {
+	Network      string   `json:"network"`
+	ChainID      string   `json:"chainID,omitempty"`
+	BlockchainID string   `json:"blockchainID,omitempty"`
+	RPCEndpoints []string `json:"rpcEndpoints,omitempty"`
+	// Contracts are the addresses of the system contracts, by name
+	Contracts map[string]string `json:"contracts,omitempty"`
+	// TxIDs are the transaction and resulting IDs of the deploy
+	TxIDs      map[string]string `json:"txIDs,omitempty"`
+	DeployedAt time.Time         `json:"deployedAt,omitempty"`
+	DeployedBy string            `json:"deployedBy,omitempty"`
+	// GenesisSHA256 is the digest of the genesis the chain was deployed
+	// with, as recorded by the deploy
+	GenesisSHA256 string  `json:"genesisSHA256,omitempty"`
+	Checks        []Check `json:"checks,omitempty"`
+}
+
+// File is a file of a bundle
+type File struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest describes a bundle: its files, with their digests, and the
+// deployments of the chain. It is signed, so the files can't be altered.
+type Manifest struct {
+	Version     int          `json:"version"`
+	Chain       string       `json:"chain"`
+	VM          string       `json:"vm,omitempty"`
+	VMID        string       `json:"vmID,omitempty"`
+	VMVersion   string       `json:"vmVersion,omitempty"`
+	Created     time.Time    `json:"created"`
+	CreatedBy   string       `json:"createdBy,omitempty"`
+	Files       []File       `json:"files"`
+	Deployments []Deployment `json:"deployments"`
+
+	Signature *receipt.Signature `json:"signature,omitempty"`
+}
+
+// Payload returns the content of m that is signed: m without its
+// signature, as JSON
+func (m *Manifest) Payload() ([]byte, error) {
+	unsigned := *m
+	unsigned.Signature = nil
+	return json.Marshal(unsigned)
+}
+
+// Create returns the bundle of m and files, by name: a gzipped tar of the
+// manifest, completed with the digests of files and signed with key, then
+// the files
+func Create(m *Manifest, files map[string][]byte, key ed25519.PrivateKey) ([]byte, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		if name == ManifestName {
+			return nil, fmt.Errorf("%s is reserved for the manifest", ManifestName)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	m.Version = formatVersion
+	m.Files = make([]File, 0, len(names))
+	for _, name := range names {
+		m.Files = append(m.Files, File{Name: name, Size: int64(len(files[name])), SHA256: digest(files[name])})
+	}
+	if err := m.sign(key); err != nil {
+		return nil, err
+	}
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	if err := writeEntry(tw, ManifestName, manifest, m.Created); err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if err := writeEntry(tw, name, files[name], m.Created); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Verify checks the bundle: its manifest is signed by the key it names, and
+// it holds exactly the files of the manifest, unaltered. It returns the
+// manifest and the files. Whether the signing key is trusted is up to the
+// caller, by its fingerprint.
+func Verify(bundle []byte) (*Manifest, map[string][]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(bundle))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+	}
+	defer func() { _ = gz.Close() }()
+	tr := tar.NewReader(gz)
+	var m *Manifest
+	files := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+		}
+		if hdr.Name != ManifestName {
+			files[hdr.Name] = data
+			continue
+		}
+		m = &Manifest{}
+		if err := json.Unmarshal(data, m); err != nil {
+			return nil, nil, fmt.Errorf("%w: invalid manifest: %w", ErrInvalidBundle, err)
+		}
+	}
+	if m == nil {
+		return nil, nil, fmt.Errorf("%w: no manifest", ErrInvalidBundle)
+	}
+	if err := m.verify(); err != nil {
+		return nil, nil, err
+	}
+	listed := map[string]bool{}
+	for _, f := range m.Files {
+		listed[f.Name] = true
+		data, ok := files[f.Name]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s is missing", ErrInvalidBundle, f.Name)
+		}
+		if digest(data) != f.SHA256 || int64(len(data)) != f.Size {
+			return nil, nil, fmt.Errorf("%w: %s has been modified", ErrInvalidBundle, f.Name)
+		}
+	}
+	for name := range files {
+		if !listed[name] {
+			return nil, nil, fmt.Errorf("%w: %s is not in the manifest", ErrInvalidBundle, name)
+		}
+	}
+	return m, files, nil
+}
+
+func (m *Manifest) sign(key ed25519.PrivateKey) error {
+	pub, ok := key.Public().(ed25519.PublicKey)
+	if !ok {
+		return errors.New("invalid signing key")
+	}
+	payload, err := m.Payload()
+	if err != nil {
+		return err
+	}
+	m.Signature = &receipt.Signature{
+		Algorithm:      receipt.Algorithm,
+		PublicKey:      base64.StdEncoding.EncodeToString(pub),
+		KeyFingerprint: receipt.Fingerprint(pub),
+		Value:          base64.StdEncoding.EncodeToString(ed25519.Sign(key, payload)),
+	}
+	return nil
+}
+
+func (m *Manifest) verify() error {
+	if m.Signature == nil {
+		return fmt.Errorf("%w: manifest is not signed", ErrInvalidBundle)
+	}
+	if m.Signature.Algorithm != receipt.Algorithm {
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidBundle, m.Signature.Algorithm)
+	}
+	pub, err := base64.StdEncoding.DecodeString(m.Signature.PublicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("%w: invalid public key", ErrInvalidBundle)
+	}
+	if receipt.Fingerprint(pub) != m.Signature.KeyFingerprint {
+		return fmt.Errorf("%w: key fingerprint does not match the public key", ErrInvalidBundle)
+	}
+	sig, err := base64.StdEncoding.DecodeString(m.Signature.Value)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+	}
+	payload, err := m.Payload()
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(pub, payload, sig) {
+		return fmt.Errorf("%w: the manifest has been modified", ErrInvalidBundle)
+	}
+	return nil
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0o644,
+		Size:     int64(len(data)),
+		ModTime:  modTime,
+		Typeflag: tar.TypeReg,
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,105 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package artifacts
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/luxfi/cli/pkg/receipt"
+	"github.com/stretchr/testify/require"
+)
+
+func testBundle(t *testing.T) ([]byte, ed25519.PrivateKey) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	m := &Manifest{
+		Chain:   "mychain",
+		VM:      "EVM",
+		Created: time.Date(2026, time.March, 1, 10, 0, 0, 0, time.UTC),
+		Deployments: []Deployment{{
+			Network:      "Mainnet",
+			BlockchainID: "2Xyz",
+			Contracts:    map[string]string{"validatorManager": "0x0FEEDC0DE0000000000000000000000000000000"},
+			Checks:       []Check{{Name: "genesis", Status: CheckPass}},
+		}},
+	}
+	bundle, err := Create(m, map[string][]byte{
+		"genesis.json": []byte(`{"config":{}}`),
+		"sidecar.json": []byte(`{"Name":"mychain"}`),
+	}, key)
+	require.NoError(t, err)
+	return bundle, key
+}
+
+func TestCreateVerify(t *testing.T) {
+	require := require.New(t)
+	bundle, key := testBundle(t)
+
+	m, files, err := Verify(bundle)
+	require.NoError(err)
+	require.Equal("mychain", m.Chain)
+	require.Equal(receipt.Fingerprint(key.Public().(ed25519.PublicKey)), m.Signature.KeyFingerprint)
+	require.Len(m.Files, 2)
+	require.Equal("genesis.json", m.Files[0].Name)
+	require.Equal(`{"config":{}}`, string(files["genesis.json"]))
+	require.Equal(CheckPass, m.Deployments[0].Checks[0].Status)
+
+	_, err = Create(&Manifest{}, map[string][]byte{ManifestName: nil}, key)
+	require.ErrorContains(err, "reserved")
+}
+
+func TestVerifyRefusesTampering(t *testing.T) {
+	require := require.New(t)
+	bundle, _ := testBundle(t)
+
+	// rewrite the bundle, changing the entry name with edit
+	rewrite := func(name string, edit func([]byte) []byte) []byte {
+		gz, err := gzip.NewReader(bytes.NewReader(bundle))
+		require.NoError(err)
+		tr := tar.NewReader(gz)
+		var buf bytes.Buffer
+		out := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(out)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(err)
+			data, err := io.ReadAll(tr)
+			require.NoError(err)
+			if hdr.Name == name {
+				data = edit(data)
+			}
+			if data != nil {
+				require.NoError(writeEntry(tw, hdr.Name, data, hdr.ModTime))
+			}
+		}
+		require.NoError(tw.Close())
+		require.NoError(out.Close())
+		return buf.Bytes()
+	}
+
+	_, _, err := Verify(rewrite("genesis.json", func([]byte) []byte { return []byte(`{"config":{"chainId":1}}`) }))
+	require.ErrorIs(err, ErrInvalidBundle)
+	require.ErrorContains(err, "genesis.json has been modified")
+
+	_, _, err = Verify(rewrite("sidecar.json", func([]byte) []byte { return nil }))
+	require.ErrorContains(err, "sidecar.json is missing")
+
+	_, _, err = Verify(rewrite(ManifestName, func(data []byte) []byte {
+		return bytes.Replace(data, []byte("2Xyz"), []byte("2Abc"), 1)
+	}))
+	require.ErrorContains(err, "the manifest has been modified")
+
+	_, _, err = Verify([]byte("not a bundle"))
+	require.ErrorIs(err, ErrInvalidBundle)
+}
@@ -0,0 +1,274 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package backup archives the state of a CLI home that can't be recreated:
+// keys, chain definitions and cluster configs, so it can be restored on
+// another machine.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ManifestName is the first entry of an archive
+const ManifestName = "MANIFEST.json"
+
+// MaxSize is the largest archive created, so a stray chain database doesn't
+// end up in a secret
+const MaxSize = 16 << 20
+
+// Paths are the files and directories of a home that are backed up,
+// relative to it: keys, chain definitions and configs, cluster configs and
+// inventories, and node certificates
+var Paths = []string{"keys", "chains", "clusters.json", "clusters", "nodes"}
+
+// Manifest describes an archive
+type Manifest struct {
+	Created time.Time `json:"created"`
+	Host    string    `json:"host"`
+	Home    string    `json:"home"`
+	Files   []string  `json:"files"`
+	Bytes   int64     `json:"bytes"`
+	// Cluster is set on the archives of a single cluster
+	Cluster *ClusterInfo `json:"cluster,omitempty"`
+}
+
+// Create archives the files of paths in baseDir as a gzipped tar. Missing
+// paths are skipped, as are symlinks and other non-regular files.
+func Create(baseDir string, paths []string, home string) ([]byte, *Manifest, error) {
+	host, _ := os.Hostname()
+	return create(baseDir, paths, &Manifest{Created: time.Now().UTC(), Host: host, Home: home})
+}
+
+func create(baseDir string, paths []string, m *Manifest) ([]byte, *Manifest, error) {
+	modes := map[string]fs.FileMode{}
+	for _, p := range paths {
+		err := filepath.WalkDir(filepath.Join(baseDir, p), func(file string, d fs.DirEntry, err error) error {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(baseDir, file)
+			if err != nil {
+				return err
+			}
+			m.Files = append(m.Files, filepath.ToSlash(rel))
+			m.Bytes += info.Size()
+			modes[filepath.ToSlash(rel)] = info.Mode().Perm()
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	sort.Strings(m.Files)
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := writeEntry(tw, ManifestName, 0o600, manifest); err != nil {
+		return nil, nil, err
+	}
+	for _, name := range m.Files {
+		data, err := os.ReadFile(filepath.Join(baseDir, filepath.FromSlash(name))) //nolint:gosec // G304: Reading from app's data directory
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := writeEntry(tw, name, int64(modes[name]), data); err != nil {
+			return nil, nil, err
+		}
+		if buf.Len() > MaxSize {
+			return nil, nil, fmt.Errorf("backup is larger than %d MiB at %s", MaxSize>>20, name)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, nil, err
+	}
+	if buf.Len() > MaxSize {
+		return nil, nil, fmt.Errorf("backup is larger than %d MiB", MaxSize>>20)
+	}
+	return buf.Bytes(), m, nil
+}
+
+func writeEntry(tw *tar.Writer, name string, mode int64, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     mode,
+		Size:     int64(len(data)),
+		ModTime:  time.Now(),
+		Typeflag: tar.TypeReg,
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// ReadManifest returns the manifest of archive
+func ReadManifest(archive []byte) (*Manifest, error) {
+	var m *Manifest
+	err := walk(archive, func(hdr *tar.Header, r io.Reader) error {
+		if hdr.Name != ManifestName {
+			return nil
+		}
+		m = &Manifest{}
+		if err := json.NewDecoder(r).Decode(m); err != nil {
+			return fmt.Errorf("invalid backup manifest: %w", err)
+		}
+		return errStop
+	})
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, errors.New("invalid backup: no manifest")
+	}
+	return m, nil
+}
+
+// Conflicts returns the files of archive that exist in baseDir with other
+// contents, which Restore would overwrite
+func Conflicts(baseDir string, archive []byte) ([]string, error) {
+	return conflicts(baseDir, archive, nil)
+}
+
+// conflicts is Conflicts with the entries of archive passed through
+// rewrite, when not nil
+func conflicts(baseDir string, archive []byte, rewrite func([]byte) []byte) ([]string, error) {
+	var conflicts []string
+	err := walk(archive, func(hdr *tar.Header, r io.Reader) error {
+		if hdr.Name == ManifestName {
+			return nil
+		}
+		file, err := target(baseDir, hdr.Name)
+		if err != nil {
+			return err
+		}
+		current, err := os.ReadFile(file) //nolint:gosec // G304: Reading from app's data directory
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if rewrite != nil {
+			data = rewrite(data)
+		}
+		if !bytes.Equal(current, data) {
+			conflicts = append(conflicts, hdr.Name)
+		}
+		return nil
+	})
+	return conflicts, err
+}
+
+// Restore writes the files of archive to baseDir, overwriting those that
+// exist. Files of baseDir not in archive are left alone.
+func Restore(baseDir string, archive []byte) ([]string, error) {
+	return restore(baseDir, archive, nil)
+}
+
+// restore is Restore with the entries of archive passed through rewrite,
+// when not nil
+func restore(baseDir string, archive []byte, rewrite func([]byte) []byte) ([]string, error) {
+	var restored []string
+	err := walk(archive, func(hdr *tar.Header, r io.Reader) error {
+		if hdr.Name == ManifestName {
+			return nil
+		}
+		file, err := target(baseDir, hdr.Name)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(file), 0o750); err != nil {
+			return err
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if rewrite != nil {
+			data = rewrite(data)
+		}
+		mode := fs.FileMode(hdr.Mode).Perm() & 0o700
+		if mode == 0 {
+			mode = 0o600
+		}
+		if err := os.WriteFile(file, data, mode); err != nil {
+			return err
+		}
+		restored = append(restored, hdr.Name)
+		return nil
+	})
+	return restored, err
+}
+
+// target returns the path of the archive entry name in baseDir, refusing
+// names that would escape it
+func target(baseDir, name string) (string, error) {
+	clean := path.Clean(name)
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("invalid backup entry %q", name)
+	}
+	return filepath.Join(baseDir, filepath.FromSlash(clean)), nil
+}
+
+var errStop = errors.New("stop")
+
+// walk calls fn for the regular files of archive, until it returns errStop
+func walk(archive []byte, fn func(*tar.Header, io.Reader) error) error {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return fmt.Errorf("invalid backup: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid backup: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := fn(hdr, tr); err != nil {
+			if errors.Is(err, errStop) {
+				return nil
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestCreateRestore(t *testing.T) {
+	require := require.New(t)
+	home := t.TempDir()
+	writeFile(t, filepath.Join(home, "keys", "ops.pk"), "secret key")
+	writeFile(t, filepath.Join(home, "chains", "mychain", "sidecar.json"), "{}")
+	writeFile(t, filepath.Join(home, "clusters.json"), `{"clusters":{}}`)
+	// run state is not backed up
+	writeFile(t, filepath.Join(home, "runs", "dev", "db"), "data")
+
+	archive, m, err := Create(home, Paths, "default")
+	require.NoError(err)
+	require.Equal([]string{"chains/mychain/sidecar.json", "clusters.json", "keys/ops.pk"}, m.Files)
+	read, err := ReadManifest(archive)
+	require.NoError(err)
+	require.Equal(m.Files, read.Files)
+	require.Equal("default", read.Home)
+
+	// restoring to a new home writes every file
+	other := t.TempDir()
+	conflicts, err := Conflicts(other, archive)
+	require.NoError(err)
+	require.Empty(conflicts)
+	restored, err := Restore(other, archive)
+	require.NoError(err)
+	require.Equal(m.Files, restored)
+	data, err := os.ReadFile(filepath.Join(other, "keys", "ops.pk"))
+	require.NoError(err)
+	require.Equal("secret key", string(data))
+	info, err := os.Stat(filepath.Join(other, "keys", "ops.pk"))
+	require.NoError(err)
+	require.Equal(os.FileMode(0o600), info.Mode().Perm())
+
+	// files changed since are reported before being overwritten
+	writeFile(t, filepath.Join(other, "keys", "ops.pk"), "other key")
+	conflicts, err = Conflicts(other, archive)
+	require.NoError(err)
+	require.Equal([]string{"keys/ops.pk"}, conflicts)
+}
+
+func TestRestoreRefusesEscapes(t *testing.T) {
+	require := require.New(t)
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	require.NoError(writeEntry(tw, "../escape", 0o600, []byte("x")))
+	require.NoError(tw.Close())
+	require.NoError(gz.Close())
+
+	_, err := Restore(t.TempDir(), buf.Bytes())
+	require.ErrorContains(err, "invalid backup entry")
+	_, err = ReadManifest(buf.Bytes())
+	require.ErrorContains(err, "no manifest")
+	_, err = ReadManifest([]byte("not a backup"))
+	require.ErrorContains(err, "invalid backup")
+}
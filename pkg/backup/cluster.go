@@ -0,0 +1,88 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package backup
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+)
+
+// ClusterInfo describes the cluster of a cluster archive
+type ClusterInfo struct {
+	Name string `json:"name"`
+	// BaseDir is the home the cluster was exported from. Inventories and
+	// configs hold absolute paths of it, ssh certs among them, which are
+	// moved to the home the cluster is imported in.
+	BaseDir string `json:"baseDir"`
+	// Config is the entry of the cluster in clusters.json
+	Config json.RawMessage `json:"config,omitempty"`
+}
+
+// ConfigIn returns the clusters.json entry of the cluster, with the paths
+// of the exporting home in it moved to baseDir
+func (c *ClusterInfo) ConfigIn(baseDir string) json.RawMessage {
+	if rewrite := rebase(c.BaseDir, baseDir); rewrite != nil {
+		return rewrite(c.Config)
+	}
+	return c.Config
+}
+
+// CreateCluster archives the files of paths in baseDir as Create does, for
+// the cluster name whose clusters.json entry is config
+func CreateCluster(baseDir string, paths []string, home, name string, config json.RawMessage) ([]byte, *Manifest, error) {
+	host, _ := os.Hostname()
+	return create(baseDir, paths, &Manifest{
+		Created: time.Now().UTC(),
+		Host:    host,
+		Home:    home,
+		Cluster: &ClusterInfo{Name: name, BaseDir: baseDir, Config: config},
+	})
+}
+
+// ReadClusterManifest returns the manifest of the cluster archive
+func ReadClusterManifest(archive []byte) (*Manifest, error) {
+	m, err := ReadManifest(archive)
+	if err != nil {
+		return nil, err
+	}
+	if m.Cluster == nil || m.Cluster.Name == "" {
+		return nil, errors.New("not a cluster export: the archive has no cluster")
+	}
+	return m, nil
+}
+
+// ClusterConflicts returns the files of the cluster archive that exist in
+// baseDir with other contents, which RestoreCluster would overwrite
+func ClusterConflicts(baseDir string, archive []byte) ([]string, error) {
+	m, err := ReadClusterManifest(archive)
+	if err != nil {
+		return nil, err
+	}
+	return conflicts(baseDir, archive, rebase(m.Cluster.BaseDir, baseDir))
+}
+
+// RestoreCluster writes the files of the cluster archive to baseDir as
+// Restore does, with the paths of the exporting home in them moved to
+// baseDir
+func RestoreCluster(baseDir string, archive []byte) ([]string, error) {
+	m, err := ReadClusterManifest(archive)
+	if err != nil {
+		return nil, err
+	}
+	return restore(baseDir, archive, rebase(m.Cluster.BaseDir, baseDir))
+}
+
+// rebase returns the rewrite moving the paths of from to to, nil when they
+// are the same
+func rebase(from, to string) func([]byte) []byte {
+	if from == "" || from == to {
+		return nil
+	}
+	return func(data []byte) []byte {
+		return bytes.ReplaceAll(data, []byte(from), []byte(to))
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package backup
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterRestoreMovesHome(t *testing.T) {
+	require := require.New(t)
+	home := t.TempDir()
+	cert := filepath.Join(home, "ssh", "ops.pem")
+	inventory := "node1 ansible_host=1.2.3.4 ansible_ssh_private_key_file=" + cert + "\n"
+	writeFile(t, cert, "ssh key")
+	writeFile(t, filepath.Join(home, "clusters", "prod", "ansible", "inventories", "hosts"), inventory)
+	writeFile(t, filepath.Join(home, "nodes", "node1", "staking", "staker.key"), "staking key")
+
+	config := json.RawMessage(`{"network":"mainnet","nodes":["node1"]}`)
+	archive, m, err := CreateCluster(home, []string{"clusters/prod", "nodes/node1", "ssh/ops.pem"}, "default", "prod", config)
+	require.NoError(err)
+	require.Equal([]string{"clusters/prod/ansible/inventories/hosts", "nodes/node1/staking/staker.key", "ssh/ops.pem"}, m.Files)
+	read, err := ReadClusterManifest(archive)
+	require.NoError(err)
+	require.Equal("prod", read.Cluster.Name)
+	require.Equal(home, read.Cluster.BaseDir)
+	require.JSONEq(string(config), string(read.Cluster.Config))
+	require.JSONEq(string(config), string(read.Cluster.ConfigIn(t.TempDir())))
+
+	// the inventory of another home points to its own copy of the cert
+	other := t.TempDir()
+	conflicts, err := ClusterConflicts(other, archive)
+	require.NoError(err)
+	require.Empty(conflicts)
+	restored, err := RestoreCluster(other, archive)
+	require.NoError(err)
+	require.Equal(m.Files, restored)
+	data, err := os.ReadFile(filepath.Join(other, "clusters", "prod", "ansible", "inventories", "hosts"))
+	require.NoError(err)
+	require.Equal("node1 ansible_host=1.2.3.4 ansible_ssh_private_key_file="+filepath.Join(other, "ssh", "ops.pem")+"\n", string(data))
+
+	// importing again changes nothing, until the inventory is edited
+	conflicts, err = ClusterConflicts(other, archive)
+	require.NoError(err)
+	require.Empty(conflicts)
+	writeFile(t, filepath.Join(other, "clusters", "prod", "ansible", "inventories", "hosts"), "edited")
+	conflicts, err = ClusterConflicts(other, archive)
+	require.NoError(err)
+	require.Equal([]string{"clusters/prod/ansible/inventories/hosts"}, conflicts)
+
+	// a full backup is not a cluster export
+	full, _, err := Create(home, Paths, "default")
+	require.NoError(err)
+	_, err = RestoreCluster(other, full)
+	require.ErrorContains(err, "not a cluster export")
+}
+
+func TestSealed(t *testing.T) {
+	require := require.New(t)
+	archive, _, err := Create(t.TempDir(), Paths, "default")
+	require.NoError(err)
+	_, ok, err := Unseal(archive)
+	require.NoError(err)
+	require.False(ok)
+
+	data, err := Seal("key-id", []byte("ciphertext"))
+	require.NoError(err)
+	sealed, ok, err := Unseal(data)
+	require.NoError(err)
+	require.True(ok)
+	require.Equal("key-id", sealed.KMSKey)
+	require.Equal("ciphertext", string(sealed.Ciphertext))
+
+	_, _, err = Unseal([]byte(`{"format":"other"}`))
+	require.ErrorContains(err, "neither an archive")
+}
@@ -0,0 +1,195 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultKMSPath is the secret path of the backups of a home, under
+	// which each backup is a secret of its own
+	DefaultKMSPath = "/lux-cli/backups"
+
+	// SecretPrefix starts the name of every backup secret, followed by its
+	// creation time, so names sort in creation order
+	SecretPrefix = "lux-backup-"
+
+	nameTimeFormat = "20060102T150405Z"
+)
+
+// Version is a backup stored in the KMS. Versions are numbered from 1, the
+// oldest backup.
+type Version struct {
+	Number  int
+	Name    string
+	Created time.Time
+}
+
+// KMSStore keeps backups as secrets of a KMS server, as served by 'lux kms
+// server start', which encrypts them with its default key
+type KMSStore struct {
+	URL    string
+	APIKey string
+	Path   string
+	Client *http.Client
+}
+
+// SecretName returns the name of the secret of a backup created at t
+func SecretName(t time.Time) string {
+	return SecretPrefix + t.UTC().Format(nameTimeFormat)
+}
+
+// Put stores archive as a new backup and returns its version
+func (s *KMSStore) Put(ctx context.Context, archive []byte, created time.Time) (*Version, error) {
+	name := SecretName(created)
+	versions, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		if v.Name == name {
+			return nil, fmt.Errorf("backup %s already exists", name)
+		}
+	}
+	req := map[string]string{
+		"secretPath":  s.Path,
+		"secretValue": base64.StdEncoding.EncodeToString(archive),
+		"type":        "shared",
+	}
+	if err := s.do(ctx, http.MethodPost, name, "", req, nil); err != nil {
+		return nil, err
+	}
+	if versions, err = s.List(ctx); err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		if v.Name == name {
+			return &v, nil
+		}
+	}
+	return nil, fmt.Errorf("backup %s not found after storing it", name)
+}
+
+// List returns the backups, oldest first
+func (s *KMSStore) List(ctx context.Context) ([]Version, error) {
+	var resp struct {
+		Secrets []struct {
+			SecretKey string `json:"secretKey"`
+		} `json:"secrets"`
+	}
+	if err := s.do(ctx, http.MethodGet, "", "", nil, &resp); err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, sec := range resp.Secrets {
+		if strings.HasPrefix(sec.SecretKey, SecretPrefix) {
+			names = append(names, sec.SecretKey)
+		}
+	}
+	sort.Strings(names)
+	versions := make([]Version, 0, len(names))
+	for i, name := range names {
+		created, _ := time.Parse(nameTimeFormat, strings.TrimPrefix(name, SecretPrefix))
+		versions = append(versions, Version{Number: i + 1, Name: name, Created: created})
+	}
+	return versions, nil
+}
+
+// Get returns the archive of backup version number, the latest when 0
+func (s *KMSStore) Get(ctx context.Context, number int) (*Version, []byte, error) {
+	versions, err := s.List(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(versions) == 0 {
+		return nil, nil, fmt.Errorf("no backups in KMS path %s", s.Path)
+	}
+	if number == 0 {
+		number = len(versions)
+	}
+	if number < 1 || number > len(versions) {
+		return nil, nil, fmt.Errorf("no backup version %d in KMS path %s, there are %d", number, s.Path, len(versions))
+	}
+	v := versions[number-1]
+	var resp struct {
+		Secret struct {
+			SecretValue string `json:"secretValue"`
+		} `json:"secret"`
+	}
+	if err := s.do(ctx, http.MethodGet, v.Name, "/value", nil, &resp); err != nil {
+		return nil, nil, err
+	}
+	archive, err := base64.StdEncoding.DecodeString(resp.Secret.SecretValue)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid backup %s: %w", v.Name, err)
+	}
+	return &v, archive, nil
+}
+
+// Prune deletes the oldest backups so only keep remain, returning how many
+// were deleted
+func (s *KMSStore) Prune(ctx context.Context, keep int) (int, error) {
+	versions, err := s.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for i := 0; i < len(versions)-keep; i++ {
+		if err := s.do(ctx, http.MethodDelete, versions[i].Name, "", nil, nil); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+func (s *KMSStore) do(ctx context.Context, method, name, suffix string, body, result interface{}) error {
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			return err
+		}
+	}
+	endpoint := s.URL + "/v1/secrets"
+	if name != "" {
+		endpoint += "/" + url.PathEscape(name) + suffix
+	}
+	if method != http.MethodPost {
+		endpoint += "?" + url.Values{"secretPath": {s.Path}}.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, &reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.APIKey != "" {
+		req.Header.Set("X-API-Key", s.APIKey)
+	}
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the KMS server at %s: %w", s.URL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		return fmt.Errorf("KMS secrets %s %s: %s (status %d)", method, s.Path, errResp.Error, resp.StatusCode)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package backup
+
+import (
+	"context"
+	"crypto/rand"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/luxfi/cli/pkg/kms"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKMSStore(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	rootKey := make([]byte, 32)
+	_, err := rand.Read(rootKey)
+	require.NoError(err)
+	k, err := kms.New(&kms.Config{RootKey: rootKey, InMemory: true})
+	require.NoError(err)
+	defer func() { _ = k.Close() }()
+	cfg := kms.DefaultServerConfig()
+	cfg.APIKey = "secret"
+	server := httptest.NewServer(kms.NewServer(k, cfg).Handler())
+	defer server.Close()
+
+	store := &KMSStore{URL: server.URL, APIKey: "secret", Path: DefaultKMSPath + "/default", Client: server.Client()}
+	created := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	v, err := store.Put(ctx, []byte("first"), created)
+	require.NoError(err)
+	require.Equal(1, v.Number)
+	_, err = store.Put(ctx, []byte("again"), created)
+	require.ErrorContains(err, "already exists")
+	v, err = store.Put(ctx, []byte("second"), created.Add(time.Hour))
+	require.NoError(err)
+	require.Equal(2, v.Number)
+	require.Equal(created.Add(time.Hour), v.Created)
+
+	// the latest backup by default, or any older version
+	v, archive, err := store.Get(ctx, 0)
+	require.NoError(err)
+	require.Equal(2, v.Number)
+	require.Equal("second", string(archive))
+	_, archive, err = store.Get(ctx, 1)
+	require.NoError(err)
+	require.Equal("first", string(archive))
+	_, _, err = store.Get(ctx, 3)
+	require.ErrorContains(err, "no backup version 3")
+
+	// the backups of other homes are apart
+	other := &KMSStore{URL: server.URL, APIKey: "secret", Path: DefaultKMSPath + "/other", Client: server.Client()}
+	versions, err := other.List(ctx)
+	require.NoError(err)
+	require.Empty(versions)
+
+	deleted, err := store.Prune(ctx, 1)
+	require.NoError(err)
+	require.Equal(1, deleted)
+	versions, err = store.List(ctx)
+	require.NoError(err)
+	require.Len(versions, 1)
+	require.Equal(SecretName(created.Add(time.Hour)), versions[0].Name)
+
+	store.APIKey = "wrong"
+	_, err = store.List(ctx)
+	require.ErrorContains(err, "status 401")
+}
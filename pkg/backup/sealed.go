@@ -0,0 +1,121 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package backup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// SealedFormat marks the archives encrypted with a KMS key or a passphrase
+const SealedFormat = "lux-backup-sealed"
+
+// argon2id parameters of the keys passphrases are turned into
+const (
+	argon2Time    = 3
+	argon2Memory  = 64 * 1024
+	argon2Threads = 4
+	argon2KeyLen  = 32
+	saltLen       = 16
+)
+
+var ErrPassphrase = errors.New("wrong passphrase or corrupted backup")
+
+// Sealed is an archive encrypted with a key of a KMS server, which only
+// that server can decrypt, or with a key derived from a passphrase
+type Sealed struct {
+	Format string `json:"format"`
+	KMSKey string `json:"kmsKey,omitempty"`
+	// Salt and Nonce are those of the AES-GCM key derived from the
+	// passphrase with argon2id, set on archives sealed by passphrase
+	Salt  []byte `json:"salt,omitempty"`
+	Nonce []byte `json:"nonce,omitempty"`
+	// Ciphertext is the archive as encrypted by the KMS or the passphrase
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Seal returns the file of the archive encrypted as ciphertext by the KMS
+// key keyID
+func Seal(keyID string, ciphertext []byte) ([]byte, error) {
+	return json.MarshalIndent(Sealed{Format: SealedFormat, KMSKey: keyID, Ciphertext: ciphertext}, "", "  ")
+}
+
+// SealWithPassphrase returns the file of archive encrypted with passphrase
+func SealWithPassphrase(passphrase string, archive []byte) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errors.New("empty passphrase")
+	}
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := passphraseCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(Sealed{
+		Format:     SealedFormat,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, archive, nil),
+	}, "", "  ")
+}
+
+// Unseal returns the sealed archive of data, or false when data is a plain
+// archive
+func Unseal(data []byte) (*Sealed, bool, error) {
+	if len(data) == 0 || data[0] != '{' {
+		return nil, false, nil
+	}
+	var s Sealed
+	if err := json.Unmarshal(data, &s); err != nil || s.Format != SealedFormat {
+		return nil, false, errors.New("invalid backup: neither an archive nor sealed by a KMS or passphrase")
+	}
+	if (s.KMSKey == "" && len(s.Salt) == 0) || len(s.Ciphertext) == 0 {
+		return nil, false, errors.New("invalid sealed backup: no KMS key, salt or ciphertext")
+	}
+	return &s, true, nil
+}
+
+// ByPassphrase tells if s was sealed with a passphrase rather than a KMS key
+func (s *Sealed) ByPassphrase() bool {
+	return s.KMSKey == ""
+}
+
+// OpenWithPassphrase returns the archive s seals with passphrase
+func (s *Sealed) OpenWithPassphrase(passphrase string) ([]byte, error) {
+	if !s.ByPassphrase() {
+		return nil, errors.New("backup sealed by KMS key " + s.KMSKey + ", not a passphrase")
+	}
+	gcm, err := passphraseCipher(passphrase, s.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Nonce) != gcm.NonceSize() {
+		return nil, errors.New("invalid sealed backup: bad nonce")
+	}
+	archive, err := gcm.Open(nil, s.Nonce, s.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrPassphrase
+	}
+	return archive, nil
+}
+
+func passphraseCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key := argon2.IDKey([]byte(passphrase), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package backup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealWithPassphrase(t *testing.T) {
+	require := require.New(t)
+	archive := []byte("staker keys")
+
+	data, err := SealWithPassphrase("correct horse", archive)
+	require.NoError(err)
+	require.NotContains(string(data), "staker keys")
+	sealed, ok, err := Unseal(data)
+	require.NoError(err)
+	require.True(ok)
+	require.True(sealed.ByPassphrase())
+
+	opened, err := sealed.OpenWithPassphrase("correct horse")
+	require.NoError(err)
+	require.Equal(archive, opened)
+	_, err = sealed.OpenWithPassphrase("wrong")
+	require.ErrorIs(err, ErrPassphrase)
+
+	// each seal has its own salt and nonce
+	again, err := SealWithPassphrase("correct horse", archive)
+	require.NoError(err)
+	require.NotEqual(data, again)
+
+	_, err = SealWithPassphrase("", archive)
+	require.Error(err)
+}
+
+func TestUnsealKMS(t *testing.T) {
+	require := require.New(t)
+	data, err := Seal("key-1", []byte("ciphertext"))
+	require.NoError(err)
+	sealed, ok, err := Unseal(data)
+	require.NoError(err)
+	require.True(ok)
+	require.False(sealed.ByPassphrase())
+	_, err = sealed.OpenWithPassphrase("any")
+	require.ErrorContains(err, "KMS key key-1")
+
+	_, ok, err = Unseal([]byte{0x1f, 0x8b})
+	require.NoError(err)
+	require.False(ok)
+	_, _, err = Unseal([]byte(`{"format":"lux-backup-sealed","ciphertext":"AA=="}`))
+	require.Error(err)
+}
@@ -0,0 +1,248 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package balances queries the native balance of an account on the chains of
+// the primary network and on deployed L1s, concurrently, and totals them per
+// token.
+package balances
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// NativeSymbol is the symbol of the token of the primary network
+const NativeSymbol = "LUX"
+
+// Kind is the API a chain serves balances over
+type Kind int
+
+const (
+	// PChain serves platform.getBalance, in nLUX
+	PChain Kind = iota
+	// XChain serves avm.getBalance, in nLUX
+	XChain
+	// EVM serves eth_getBalance, in wei
+	EVM
+)
+
+// Decimals returns the decimals of the native amounts of the chains of kind
+func (k Kind) Decimals() int {
+	if k == EVM {
+		return 18
+	}
+	return 9
+}
+
+// Chain is a chain to query
+type Chain struct {
+	Name   string
+	Kind   Kind
+	URL    string
+	Symbol string
+}
+
+// Account is the addresses of an account. The chains of a kind the account
+// has no address for are not queried.
+type Account struct {
+	// P and X are bech32 addresses, e.g. P-lux1...
+	P, X string
+	// EVM is a 0x address
+	EVM string
+}
+
+func (a Account) address(kind Kind) string {
+	switch kind {
+	case PChain:
+		return a.P
+	case XChain:
+		return a.X
+	default:
+		return a.EVM
+	}
+}
+
+// Balance is the balance of the account on a chain. Err is set when the
+// chain could not be queried.
+type Balance struct {
+	Chain   Chain
+	Address string
+	Amount  *big.Int
+	Err     error
+}
+
+// Query queries the balance of account on each chain concurrently. The
+// balances are in the order of chains.
+func Query(ctx context.Context, client *http.Client, chains []Chain, account Account) []Balance {
+	var targets []Chain
+	for _, c := range chains {
+		if account.address(c.Kind) != "" {
+			targets = append(targets, c)
+		}
+	}
+	balances := make([]Balance, len(targets))
+	var wg sync.WaitGroup
+	for i, c := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			addr := account.address(c.Kind)
+			amount, err := queryBalance(ctx, client, c, addr)
+			balances[i] = Balance{Chain: c, Address: addr, Amount: amount, Err: err}
+		}()
+	}
+	wg.Wait()
+	return balances
+}
+
+func queryBalance(ctx context.Context, client *http.Client, c Chain, addr string) (*big.Int, error) {
+	switch c.Kind {
+	case PChain:
+		var result struct {
+			Balance string `json:"balance"`
+		}
+		if err := call(ctx, client, c.URL, "platform.getBalance", map[string]interface{}{"addresses": []string{addr}}, &result); err != nil {
+			return nil, err
+		}
+		return parseAmount(result.Balance, 10)
+	case XChain:
+		var result struct {
+			Balance string `json:"balance"`
+		}
+		if err := call(ctx, client, c.URL, "avm.getBalance", map[string]interface{}{"address": addr, "assetID": NativeSymbol}, &result); err != nil {
+			return nil, err
+		}
+		return parseAmount(result.Balance, 10)
+	default:
+		var result string
+		if err := call(ctx, client, c.URL, "eth_getBalance", []interface{}{addr, "latest"}, &result); err != nil {
+			return nil, err
+		}
+		return parseAmount(strings.TrimPrefix(result, "0x"), 16)
+	}
+}
+
+func parseAmount(s string, base int) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(s, base)
+	if !ok {
+		return nil, fmt.Errorf("invalid balance %q", s)
+	}
+	return amount, nil
+}
+
+func call(ctx context.Context, client *http.Client, url, method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: status %d", method, resp.StatusCode)
+	}
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", method, err)
+	}
+	if response.Error != nil {
+		return fmt.Errorf("%s: %s", method, response.Error.Message)
+	}
+	if len(response.Result) == 0 {
+		return errors.New(method + ": empty result")
+	}
+	return json.Unmarshal(response.Result, result)
+}
+
+// Total is the sum of the balances of a token, in its smallest unit across
+// the chains holding it
+type Total struct {
+	Symbol   string
+	Amount   *big.Int
+	Decimals int
+	Chains   int
+}
+
+// Totals sums the queried balances per token. Amounts of a token with
+// different decimals, e.g. LUX on the P-Chain and the C-Chain, are scaled to
+// the largest. The native token comes first, then the others by symbol.
+func Totals(balances []Balance) []Total {
+	bySymbol := map[string]*Total{}
+	for _, b := range balances {
+		if b.Err != nil {
+			continue
+		}
+		t, ok := bySymbol[b.Chain.Symbol]
+		if !ok {
+			t = &Total{Symbol: b.Chain.Symbol, Amount: new(big.Int)}
+			bySymbol[b.Chain.Symbol] = t
+		}
+		t.Decimals = max(t.Decimals, b.Chain.Kind.Decimals())
+	}
+	for _, b := range balances {
+		if b.Err != nil {
+			continue
+		}
+		t := bySymbol[b.Chain.Symbol]
+		t.Amount.Add(t.Amount, scale(b.Amount, t.Decimals-b.Chain.Kind.Decimals()))
+		t.Chains++
+	}
+	totals := make([]Total, 0, len(bySymbol))
+	for _, t := range bySymbol {
+		totals = append(totals, *t)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if (totals[i].Symbol == NativeSymbol) != (totals[j].Symbol == NativeSymbol) {
+			return totals[i].Symbol == NativeSymbol
+		}
+		return totals[i].Symbol < totals[j].Symbol
+	})
+	return totals
+}
+
+func scale(amount *big.Int, decimals int) *big.Int {
+	factor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	return new(big.Int).Mul(amount, factor)
+}
+
+// Format formats amount, in units of 10^-decimals, without dropping digits,
+// e.g. 1500000000 with 9 decimals is "1.5"
+func Format(amount *big.Int, decimals int) string {
+	s := new(big.Int).Abs(amount).String()
+	if len(s) <= decimals {
+		s = strings.Repeat("0", decimals-len(s)+1) + s
+	}
+	whole, frac := s[:len(s)-decimals], strings.TrimRight(s[len(s)-decimals:], "0")
+	if amount.Sign() < 0 {
+		whole = "-" + whole
+	}
+	if frac == "" {
+		return whole
+	}
+	return whole + "." + frac
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package balances
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery(t *testing.T) {
+	require := require.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		require.NoError(json.NewDecoder(r.Body).Decode(&req))
+		switch r.URL.Path {
+		case "/ext/bc/P":
+			require.Equal("platform.getBalance", req.Method)
+			require.JSONEq(`{"addresses":["P-lux1abc"]}`, string(req.Params))
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"balance":"1500000000","unlocked":"1500000000"}}`))
+		case "/ext/bc/X":
+			require.Equal("avm.getBalance", req.Method)
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"balance":"250000000"}}`))
+		case "/ext/bc/C/rpc":
+			require.Equal("eth_getBalance", req.Method)
+			require.JSONEq(`["0x01","latest"]`, string(req.Params))
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0xde0b6b3a7640000"}`))
+		case "/ext/bc/zoo/rpc":
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x2a"}`))
+		case "/ext/bc/broken/rpc":
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"not bootstrapped"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	chains := []Chain{
+		{Name: "P", Kind: PChain, URL: server.URL + "/ext/bc/P", Symbol: NativeSymbol},
+		{Name: "X", Kind: XChain, URL: server.URL + "/ext/bc/X", Symbol: NativeSymbol},
+		{Name: "C", Kind: EVM, URL: server.URL + "/ext/bc/C/rpc", Symbol: NativeSymbol},
+		{Name: "Q", Kind: EVM, URL: server.URL + "/ext/bc/Q/rpc", Symbol: NativeSymbol},
+		{Name: "zoo", Kind: EVM, URL: server.URL + "/ext/bc/zoo/rpc", Symbol: "ZOO"},
+		{Name: "broken", Kind: EVM, URL: server.URL + "/ext/bc/broken/rpc", Symbol: "BRK"},
+	}
+	balances := Query(t.Context(), server.Client(), chains, Account{P: "P-lux1abc", X: "X-lux1abc", EVM: "0x01"})
+	require.Len(balances, 6)
+	require.Equal("P", balances[0].Chain.Name)
+	require.Equal(big.NewInt(1_500_000_000), balances[0].Amount)
+	require.Equal("X-lux1abc", balances[1].Address)
+	require.Equal(big.NewInt(250_000_000), balances[1].Amount)
+	require.Equal("1000000000000000000", balances[2].Amount.String())
+	require.ErrorContains(balances[3].Err, "status 404")
+	require.Equal(big.NewInt(42), balances[4].Amount)
+	require.ErrorContains(balances[5].Err, "eth_getBalance: not bootstrapped")
+
+	totals := Totals(balances)
+	require.Len(totals, 2)
+	require.Equal(NativeSymbol, totals[0].Symbol)
+	require.Equal(3, totals[0].Chains)
+	require.Equal("2.75", Format(totals[0].Amount, totals[0].Decimals))
+	require.Equal("ZOO", totals[1].Symbol)
+	require.Equal("0.000000000000000042", Format(totals[1].Amount, totals[1].Decimals))
+
+	// an EVM address only queries the EVM chains
+	balances = Query(t.Context(), server.Client(), chains, Account{EVM: "0x01"})
+	require.Len(balances, 4)
+	require.Equal("C", balances[0].Chain.Name)
+}
+
+func TestFormat(t *testing.T) {
+	require := require.New(t)
+	require.Equal("0", Format(big.NewInt(0), 9))
+	require.Equal("1", Format(big.NewInt(1_000_000_000), 9))
+	require.Equal("0.000000001", Format(big.NewInt(1), 9))
+	require.Equal("12.3", Format(big.NewInt(12_300_000_000), 9))
+	require.Equal("-0.5", Format(big.NewInt(-500), 3))
+}
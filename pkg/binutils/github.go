@@ -53,6 +53,10 @@ func NewLuxdDownloader() GithubDownloader {
 func (nodeDownloader) GetDownloadURL(version string, installer Installer) (string, string, error) {
 	// NOTE: if any of the underlying URLs change (github changes, release file names, etc.) this fails
 	goarch, goos := installer.GetArch()
+	goarch = NormalizeArch(goarch)
+	if (goos == linux || goos == darwin) && !ArchSupported(goarch) {
+		return "", "", fmt.Errorf("architecture not supported: %s", goarch)
+	}
 
 	var nodeURL string
 	var ext string
@@ -102,6 +106,10 @@ func NewEVMDownloader() GithubDownloader {
 func (evmDownloader) GetDownloadURL(version string, installer Installer) (string, string, error) {
 	// NOTE: if any of the underlying URLs change (github changes, release file names, etc.) this fails
 	goarch, goos := installer.GetArch()
+	goarch = NormalizeArch(goarch)
+	if (goos == linux || goos == darwin) && !ArchSupported(goarch) {
+		return "", "", fmt.Errorf("architecture not supported: %s", goarch)
+	}
 
 	var evmURL string
 	ext := tarExtension
@@ -141,6 +149,10 @@ func NewNetrunnerDownloader() GithubDownloader {
 
 func (netrunnerDownloader) GetDownloadURL(version string, installer Installer) (string, string, error) {
 	goarch, goos := installer.GetArch()
+	goarch = NormalizeArch(goarch)
+	if (goos == linux || goos == darwin) && !ArchSupported(goarch) {
+		return "", "", fmt.Errorf("architecture not supported: %s", goarch)
+	}
 
 	var netrunnerURL string
 	ext := tarExtension
@@ -96,6 +96,22 @@ func TestGetDownloadURL_EVM(t *testing.T) {
 			expectedExt: tarExtension,
 			expectedErr: nil,
 		},
+		{
+			version:     "v1.18.5",
+			goarch:      "aarch64",
+			goos:        "linux",
+			expectedURL: "https://github.com/luxfi/evm/releases/download/v1.18.5/evm_1.18.5_linux_arm64.tar.gz",
+			expectedExt: tarExtension,
+			expectedErr: nil,
+		},
+		{
+			version:     "v1.18.5",
+			goarch:      "riscv64",
+			goos:        "linux",
+			expectedURL: "",
+			expectedExt: "",
+			expectedErr: errors.New("architecture not supported: riscv64"),
+		},
 		{
 			version:     "v1.2.3",
 			goarch:      "riscv",
@@ -4,7 +4,14 @@
 package binutils
 
 import (
+	"os/exec"
 	"runtime"
+	"strings"
+)
+
+const (
+	amd64 = "amd64"
+	arm64 = "arm64"
 )
 
 // Installer provides system architecture information.
@@ -20,5 +27,36 @@ func NewInstaller() Installer {
 }
 
 func (installerImpl) GetArch() (string, string) {
+	// An amd64 CLI running under Rosetta on Apple Silicon should still
+	// install native arm64 node binaries
+	if runtime.GOOS == darwin && runtime.GOARCH == amd64 && runningUnderRosetta() {
+		return arm64, runtime.GOOS
+	}
 	return runtime.GOARCH, runtime.GOOS
 }
+
+func runningUnderRosetta() bool {
+	out, err := exec.Command("sysctl", "-n", "sysctl.proc_translated").Output()
+	return err == nil && strings.TrimSpace(string(out)) == "1"
+}
+
+// NormalizeArch maps the architecture names reported by uname, dpkg and
+// cloud providers (x86_64, aarch64, ...) to the GOARCH names used in release
+// assets. Unknown names are returned lowercased.
+func NormalizeArch(arch string) string {
+	arch = strings.ToLower(strings.TrimSpace(arch))
+	switch arch {
+	case "x86_64", "x86-64", "x64", amd64:
+		return amd64
+	case "aarch64", "arm64", "armv8", "armv8l", "arm64e":
+		return arm64
+	default:
+		return arch
+	}
+}
+
+// ArchSupported reports whether release binaries are published for arch.
+func ArchSupported(arch string) bool {
+	arch = NormalizeArch(arch)
+	return arch == amd64 || arch == arm64
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/luxfi/cli/pkg/application"
 	"github.com/luxfi/cli/pkg/binpaths"
+	"github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/cli/pkg/ux"
 	"github.com/luxfi/constants"
 	"github.com/luxfi/filesystem/perms"
@@ -374,7 +375,7 @@ func KillgRPCServerProcessForNetwork(app *application.Lux, networkType string) e
 	if err != nil {
 		return fmt.Errorf("could not find process with pid %d: %w", pid, err)
 	}
-	if err := proc.Signal(os.Interrupt); err != nil {
+	if err := utils.InterruptProcess(proc); err != nil {
 		return fmt.Errorf("failed killing process with pid %d: %w", pid, err)
 	}
 
@@ -83,6 +83,8 @@ func InstallBinary(
 			"invalid version string. Must be semantic version ex: v1.7.14: %s", version)
 	}
 
+	baseBinDir, installDir = archBinDirs(baseBinDir, installDir, installer)
+
 	binChecker := NewBinaryChecker()
 
 	exists, err := binChecker.ExistsWithVersion(baseBinDir, binPrefix, version)
@@ -100,3 +102,24 @@ func InstallBinary(
 
 	return binDir, err
 }
+
+// archBinDirs returns the cache and install directories for the binaries of
+// installer's platform. Binaries for the local platform keep the historical
+// layout; binaries for other platforms (e.g. arm64 cloud hosts managed from an
+// amd64 laptop) are cached under a <goos>-<goarch> subdirectory so the two
+// never overwrite each other.
+func archBinDirs(baseBinDir, installDir string, installer Installer) (string, string) {
+	goarch, goos := installer.GetArch()
+	goarch = NormalizeArch(goarch)
+	localArch, localOS := NewInstaller().GetArch()
+	if goarch == localArch && goos == localOS {
+		return baseBinDir, installDir
+	}
+
+	archDir := filepath.Join(baseBinDir, goos+"-"+goarch)
+	rel, err := filepath.Rel(baseBinDir, installDir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return archDir, filepath.Join(installDir, goos+"-"+goarch)
+	}
+	return archDir, filepath.Join(archDir, rel)
+}
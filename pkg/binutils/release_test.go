@@ -217,3 +217,29 @@ func Test_installEVMWithVersion_MultipleCoinstalls(t *testing.T) {
 	require.NoError(err)
 	require.Equal(binary2, installedBin2)
 }
+
+func Test_archBinDirs(t *testing.T) {
+	require := require.New(t)
+	localArch, localOS := NewInstaller().GetArch()
+
+	local := &mocks.Installer{}
+	local.On("GetArch").Return(localArch, localOS)
+	base, install := archBinDirs("/bin", "/bin/evm-v1.0.0", local)
+	require.Equal("/bin", base)
+	require.Equal("/bin/evm-v1.0.0", install)
+
+	foreignArch := "arm64"
+	if localArch == foreignArch {
+		foreignArch = "x86_64"
+	}
+	foreign := &mocks.Installer{}
+	foreign.On("GetArch").Return(foreignArch, "linux")
+	dir := "linux-" + NormalizeArch(foreignArch)
+	base, install = archBinDirs("/bin", "/bin/evm-v1.0.0", foreign)
+	require.Equal(filepath.Join("/bin", dir), base)
+	require.Equal(filepath.Join("/bin", dir, "evm-v1.0.0"), install)
+
+	base, install = archBinDirs("/bin", "/bin", foreign)
+	require.Equal(filepath.Join("/bin", dir), base)
+	require.Equal(filepath.Join("/bin", dir), install)
+}
@@ -247,10 +247,12 @@ func importFromXChain(wallet primary.Wallet, owner *secp256k1fx.OutputOwners) er
 	pWallet := wallet.P()
 	xChainID := ids.FromStringOrPanic("2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM") // X-Chain ID
 	_, cancel := context.WithTimeout(context.Background(), constants.DefaultWalletCreationTimeout)
-	_, err := pWallet.IssueImportTx(
-		xChainID,
-		owner,
-	)
+	_, err := issuePChainTx(func() (*txs.Tx, error) {
+		return pWallet.IssueImportTx(
+			xChainID,
+			owner,
+		)
+	})
 	defer cancel()
 	return err
 }
@@ -310,12 +312,14 @@ func IssueTransformChainTx(
 	}
 
 	_, cancel := context.WithTimeout(context.Background(), constants.DefaultConfirmTxTimeout)
-	transformChainTxID, err := wallet.P().IssueTransformChainTx(elasticChainConfig.ChainID, chainAssetID,
-		elasticChainConfig.InitialSupply, elasticChainConfig.MaxSupply, elasticChainConfig.MinConsumptionRate,
-		elasticChainConfig.MaxConsumptionRate, elasticChainConfig.MinValidatorStake, elasticChainConfig.MaxValidatorStake,
-		elasticChainConfig.MinStakeDuration, elasticChainConfig.MaxStakeDuration, elasticChainConfig.MinDelegationFee,
-		elasticChainConfig.MinDelegatorStake, elasticChainConfig.MaxValidatorWeightFactor, elasticChainConfig.UptimeRequirement,
-	)
+	transformChainTxID, err := issuePChainTx(func() (*txs.Tx, error) {
+		return wallet.P().IssueTransformChainTx(elasticChainConfig.ChainID, chainAssetID,
+			elasticChainConfig.InitialSupply, elasticChainConfig.MaxSupply, elasticChainConfig.MinConsumptionRate,
+			elasticChainConfig.MaxConsumptionRate, elasticChainConfig.MinValidatorStake, elasticChainConfig.MaxValidatorStake,
+			elasticChainConfig.MinStakeDuration, elasticChainConfig.MaxStakeDuration, elasticChainConfig.MinDelegationFee,
+			elasticChainConfig.MinDelegatorStake, elasticChainConfig.MaxValidatorWeightFactor, elasticChainConfig.UptimeRequirement,
+		)
+	})
 	defer cancel()
 	if err != nil {
 		return ids.Empty, ids.Empty, err
@@ -368,22 +372,24 @@ func IssueAddPermissionlessValidatorTx(
 		},
 	}
 	_, cancel := context.WithTimeout(context.Background(), constants.DefaultConfirmTxTimeout)
-	txID, err := wallet.P().IssueAddPermissionlessValidatorTx(
-		&txs.ChainValidator{
-			Validator: txs.Validator{
-				NodeID: nodeID,
-				Start:  startTime,
-				End:    endTime,
-				Wght:   stakeAmount,
+	txID, err := issuePChainTx(func() (*txs.Tx, error) {
+		return wallet.P().IssueAddPermissionlessValidatorTx(
+			&txs.ChainValidator{
+				Validator: txs.Validator{
+					NodeID: nodeID,
+					Start:  startTime,
+					End:    endTime,
+					Wght:   stakeAmount,
+				},
+				Chain: chainID,
 			},
-			Chain: chainID,
-		},
-		&signer.Empty{},
-		assetID,
-		owner,
-		&secp256k1fx.OutputOwners{},
-		reward.PercentDenominator,
-	)
+			&signer.Empty{},
+			assetID,
+			owner,
+			&secp256k1fx.OutputOwners{},
+			reward.PercentDenominator,
+		)
+	})
 	defer cancel()
 	if err != nil {
 		return ids.Empty, err
@@ -1037,7 +1043,9 @@ func IssueRemoveChainValidatorTx(kc keychain.Keychain, chainID ids.ID, nodeID id
 		return ids.Empty, err
 	}
 
-	tx, err := wallet.P().IssueRemoveChainValidatorTx(nodeID, chainID)
+	tx, err := issuePChainTx(func() (*txs.Tx, error) {
+		return wallet.P().IssueRemoveChainValidatorTx(nodeID, chainID)
+	})
 	if err != nil {
 		return ids.Empty, err
 	}
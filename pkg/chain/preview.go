@@ -0,0 +1,168 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/luxfi/address"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/netrunner/utils"
+	"github.com/luxfi/protocol/p/txs"
+	"github.com/luxfi/sdk/wallet/chain/p"
+	pbuilder "github.com/luxfi/sdk/wallet/chain/p/builder"
+	"github.com/luxfi/sdk/wallet/primary"
+	"github.com/luxfi/sdk/wallet/primary/common"
+	lux "github.com/luxfi/utxo"
+	"github.com/luxfi/utxo/secp256k1fx"
+)
+
+// TxPreview describes a transaction a deploy would sign and broadcast
+type TxPreview struct {
+	Type string
+	ID   ids.ID
+	Size int
+	// Fee is the LUX burned by the transaction, in nLUX
+	Fee uint64
+}
+
+// DeployPreview is the outcome of building the deploy transactions without
+// signing or issuing them
+type DeployPreview struct {
+	Txs []TxPreview
+	// Balance is the spendable P-chain LUX of the keychain, in nLUX
+	Balance uint64
+	// GasPrice is the dynamic fee gas price the fees were computed with
+	GasPrice uint64
+}
+
+// TotalFee returns the sum of the fees of all transactions
+func (d *DeployPreview) TotalFee() uint64 {
+	var total uint64
+	for _, tx := range d.Txs {
+		total += tx.Fee
+	}
+	return total
+}
+
+// PreviewDeploy builds the CreateNetworkTx and CreateChainTx that DeployChain
+// and DeployBlockchain would issue, against the current UTXOs and dynamic fee
+// state of the network. Nothing is signed or broadcast.
+func (d *PublicDeployer) PreviewDeploy(
+	controlKeys []string,
+	threshold uint32,
+	chain string,
+	genesis []byte,
+) (*DeployPreview, error) {
+	api, err := d.apiEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	owners, err := address.ParseToIDs(controlKeys)
+	if err != nil {
+		return nil, fmt.Errorf("failure parsing control keys: %w", err)
+	}
+	vmID, err := utils.VMID(chain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create VM ID from %s: %w", chain, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.RequestTimeout)
+	defer cancel()
+	addrs := d.kc.Addresses()
+	state, err := primary.FetchState(ctx, api, addrs)
+	if err != nil {
+		return nil, err
+	}
+	// A private backend so accepting the previewed txs doesn't touch any wallet
+	backend := p.NewBackend(state.PCTX, common.NewChainUTXOs(constants.PlatformChainID, state.UTXOs), map[ids.ID]*txs.Tx{})
+	builder := pbuilder.New(addrs, state.PCTX, backend)
+
+	balances, err := builder.GetBalance()
+	if err != nil {
+		return nil, err
+	}
+	preview := &DeployPreview{
+		Balance:  balances[state.PCTX.XAssetID],
+		GasPrice: uint64(state.PCTX.GasPrice),
+	}
+
+	createNetworkTx, err := builder.NewCreateNetworkTx(&secp256k1fx.OutputOwners{
+		Addrs:     owners,
+		Threshold: threshold,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build CreateNetworkTx: %w", err)
+	}
+	networkTx, err := previewTx(ctx, backend, state.PCTX.XAssetID, "CreateNetworkTx", createNetworkTx, &createNetworkTx.BaseTx.BaseTx)
+	if err != nil {
+		return nil, err
+	}
+	preview.Txs = append(preview.Txs, networkTx)
+
+	createChainTx, err := builder.NewCreateChainTx(
+		networkTx.ID,
+		genesis,
+		vmID,
+		nil,
+		chain,
+		d.getMultisigTxOptions(owners)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build CreateChainTx: %w", err)
+	}
+	chainTx, err := previewTx(ctx, backend, state.PCTX.XAssetID, "CreateChainTx", createChainTx, &createChainTx.BaseTx.BaseTx)
+	if err != nil {
+		return nil, err
+	}
+	preview.Txs = append(preview.Txs, chainTx)
+	return preview, nil
+}
+
+// previewTx serializes an unsigned tx and accepts it into backend, so the
+// txs built after it spend its change and see the network it creates
+func previewTx(
+	ctx context.Context,
+	backend p.Backend,
+	assetID ids.ID,
+	txType string,
+	unsignedTx txs.UnsignedTx,
+	baseTx *lux.BaseTx,
+) (TxPreview, error) {
+	tx := &txs.Tx{Unsigned: unsignedTx}
+	if err := tx.Initialize(txs.Codec); err != nil {
+		return TxPreview{}, err
+	}
+	if err := backend.AcceptTx(ctx, tx); err != nil {
+		return TxPreview{}, err
+	}
+	return TxPreview{
+		Type: txType,
+		ID:   tx.ID(),
+		Size: len(tx.Bytes()),
+		Fee:  burnedAmount(baseTx.Ins, baseTx.Outs, assetID),
+	}, nil
+}
+
+// burnedAmount returns the amount of assetID consumed by ins and not
+// returned by outs, which is the fee paid by the tx
+func burnedAmount(ins []*lux.TransferableInput, outs []*lux.TransferableOutput, assetID ids.ID) uint64 {
+	var consumed, produced uint64
+	for _, in := range ins {
+		if in.AssetID() == assetID {
+			consumed += in.In.Amount()
+		}
+	}
+	for _, out := range outs {
+		if out.AssetID() == assetID {
+			produced += out.Out.Amount()
+		}
+	}
+	if produced > consumed {
+		return 0
+	}
+	return consumed - produced
+}
@@ -0,0 +1,33 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	lux "github.com/luxfi/utxo"
+	"github.com/luxfi/utxo/secp256k1fx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBurnedAmount(t *testing.T) {
+	luxAssetID := ids.GenerateTestID()
+	otherAssetID := ids.GenerateTestID()
+	ins := []*lux.TransferableInput{
+		{Asset: lux.Asset{ID: luxAssetID}, In: &secp256k1fx.TransferInput{Amt: 700}},
+		{Asset: lux.Asset{ID: luxAssetID}, In: &secp256k1fx.TransferInput{Amt: 300}},
+		{Asset: lux.Asset{ID: otherAssetID}, In: &secp256k1fx.TransferInput{Amt: 50}},
+	}
+	outs := []*lux.TransferableOutput{
+		{Asset: lux.Asset{ID: luxAssetID}, Out: &secp256k1fx.TransferOutput{Amt: 875}},
+		{Asset: lux.Asset{ID: otherAssetID}, Out: &secp256k1fx.TransferOutput{Amt: 50}},
+	}
+	require.Equal(t, uint64(125), burnedAmount(ins, outs, luxAssetID))
+	require.Zero(t, burnedAmount(ins, outs, otherAssetID))
+	require.Zero(t, burnedAmount(nil, outs, luxAssetID))
+
+	preview := DeployPreview{Txs: []TxPreview{{Fee: 125}, {Fee: 1000}}}
+	require.Equal(t, uint64(1125), preview.TotalFee())
+}
@@ -23,8 +23,10 @@ import (
 	keychainwrapper "github.com/luxfi/cli/pkg/keychain"
 	climodels "github.com/luxfi/cli/pkg/models"
 	"github.com/luxfi/cli/pkg/txutils"
+	cliutils "github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/cli/pkg/ux"
 	"github.com/luxfi/constants"
+	"github.com/luxfi/crypto/bls"
 	ethcommon "github.com/luxfi/geth/common"
 	"github.com/luxfi/ids"
 	"github.com/luxfi/keychain"
@@ -32,6 +34,7 @@ import (
 	"github.com/luxfi/netrunner/utils"
 	"github.com/luxfi/sdk/models"
 	"github.com/luxfi/sdk/wallet/chain/c"
+	"github.com/luxfi/sdk/wallet/chain/p"
 	"github.com/luxfi/sdk/wallet/primary"
 	"github.com/luxfi/sdk/wallet/primary/common"
 	"github.com/luxfi/utxo/secp256k1fx"
@@ -47,6 +50,9 @@ type PublicDeployer struct {
 	kc          keychain.Keychain
 	network     models.Network
 	app         *application.Lux
+	// pWallet loads the P-Chain wallet of the L1 validator txs, defaulting
+	// to the wallet of the keychain on network
+	pWallet func() (p.Wallet, error)
 }
 
 // NewPublicDeployer creates a new PublicDeployer instance.
@@ -192,7 +198,9 @@ func (d *PublicDeployer) ImportFromXChain(
 	}
 	xChainID := ids.FromStringOrPanic("2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM") // X-Chain ID
 
-	tx, err := wallet.P().IssueImportTx(xChainID, owner)
+	tx, err := issuePChainTx(func() (*txs.Tx, error) {
+		return wallet.P().IssueImportTx(xChainID, owner)
+	})
 	if err == nil {
 		ux.Logger.PrintToUser("Import from X Chain Transaction successful, transaction ID: %s", tx.ID())
 		ux.Logger.PrintToUser("Now transforming net into elastic net ...")
@@ -324,6 +332,23 @@ func (d *PublicDeployer) DeployBlockchain(
 	chainID ids.ID,
 	chain string,
 	genesis []byte,
+) (bool, ids.ID, *txs.Tx, []string, error) {
+	vmID, err := utils.VMID(chain)
+	if err != nil {
+		return false, ids.Empty, nil, nil, fmt.Errorf("failed to create VM ID from %s: %w", chain, err)
+	}
+	return d.DeployBlockchainWithVM(controlKeys, chainAuthKeysStrs, chainID, chain, vmID, genesis)
+}
+
+// DeployBlockchainWithVM creates the blockchain chain of chainID running vmID,
+// for VMs whose ID is not derived from the chain name.
+func (d *PublicDeployer) DeployBlockchainWithVM(
+	controlKeys []string,
+	chainAuthKeysStrs []string,
+	chainID ids.ID,
+	chain string,
+	vmID ids.ID,
+	genesis []byte,
 ) (bool, ids.ID, *txs.Tx, []string, error) {
 	ux.Logger.PrintToUser("Now creating blockchain...")
 
@@ -332,11 +357,6 @@ func (d *PublicDeployer) DeployBlockchain(
 		return false, ids.Empty, nil, nil, err
 	}
 
-	vmID, err := utils.VMID(chain)
-	if err != nil {
-		return false, ids.Empty, nil, nil, fmt.Errorf("failed to create VM ID from %s: %w", chain, err)
-	}
-
 	chainAuthKeys, err := address.ParseToIDs(chainAuthKeysStrs)
 	if err != nil {
 		return false, ids.Empty, nil, nil, fmt.Errorf("failure parsing chain auth keys: %w", err)
@@ -376,13 +396,26 @@ func (d *PublicDeployer) Commit(
 	if err != nil {
 		return ids.Empty, err
 	}
-	err = wallet.P().IssueTx(tx)
-	if err != nil {
+	if _, err := issuePChainTx(func() (*txs.Tx, error) {
+		return tx, wallet.P().IssueTx(tx)
+	}); err != nil {
 		return ids.Empty, err
 	}
 	return tx.ID(), nil
 }
 
+// issuePChainTx issues a P-Chain transaction with issue and, once it is
+// accepted, purges the RPC caches since validator sets, balances and owners
+// may have changed
+func issuePChainTx(issue func() (*txs.Tx, error)) (*txs.Tx, error) {
+	tx, err := issue()
+	if err != nil {
+		return nil, err
+	}
+	cliutils.PurgeRPCCaches()
+	return tx, nil
+}
+
 // Sign signs a transaction with the wallet's keys.
 func (d *PublicDeployer) Sign(
 	tx *txs.Tx,
@@ -414,23 +447,29 @@ func (d *PublicDeployer) Sign(
 	return nil
 }
 
-func (d *PublicDeployer) loadWallet(preloadTxs ...ids.ID) (primary.Wallet, error) {
-	ctx := context.Background()
-	ux.Logger.PrintToUser("loadWallet: starting...")
-
-	var api string
+// apiEndpoint returns the node API the deployer issues transactions to
+func (d *PublicDeployer) apiEndpoint() (string, error) {
 	switch d.network {
 	case models.Testnet:
-		api = constants.TestnetAPIEndpoint
+		return constants.TestnetAPIEndpoint, nil
 	case models.Mainnet:
-		api = constants.MainnetAPIEndpoint
+		return constants.MainnetAPIEndpoint, nil
 	case models.Devnet:
-		api = constants.DevnetAPIEndpoint
+		return constants.DevnetAPIEndpoint, nil
 	case models.Local:
 		// used for E2E testing of public related paths
-		api = constants.LocalAPIEndpoint
-	default:
-		return nil, fmt.Errorf("unsupported public network")
+		return constants.LocalAPIEndpoint, nil
+	}
+	return "", fmt.Errorf("unsupported public network")
+}
+
+func (d *PublicDeployer) loadWallet(preloadTxs ...ids.ID) (primary.Wallet, error) {
+	ctx := context.Background()
+	ux.Logger.PrintToUser("loadWallet: starting...")
+
+	api, err := d.apiEndpoint()
+	if err != nil {
+		return nil, err
 	}
 	ux.Logger.PrintToUser("loadWallet: using API endpoint %s", api)
 
@@ -475,6 +514,18 @@ func (d *PublicDeployer) loadWallet(preloadTxs ...ids.ID) (primary.Wallet, error
 	return wallet, nil
 }
 
+// pChainWallet returns the P-Chain wallet the L1 validator txs are issued with
+func (d *PublicDeployer) pChainWallet() (p.Wallet, error) {
+	if d.pWallet != nil {
+		return d.pWallet()
+	}
+	wallet, err := d.loadWallet()
+	if err != nil {
+		return nil, err
+	}
+	return wallet.P(), nil
+}
+
 func (d *PublicDeployer) getMultisigTxOptions(chainAuthKeys []ids.ShortID) []common.Option {
 	options := []common.Option{}
 	walletAddr := d.kc.Addresses().List()[0]
@@ -681,7 +732,9 @@ func (d *PublicDeployer) createChainTx(controlKeys []string, threshold uint32, w
 		ux.Logger.PrintToUser("*** Please sign CreateChain transaction on the ledger device *** ")
 	}
 	ux.Logger.PrintToUser("createNetworkTx: calling IssueCreateNetworkTx...")
-	tx, err := wallet.P().IssueCreateNetworkTx(owners, opts...)
+	tx, err := issuePChainTx(func() (*txs.Tx, error) {
+		return wallet.P().IssueCreateNetworkTx(owners, opts...)
+	})
 	if err != nil {
 		ux.Logger.PrintToUser("createNetworkTx: IssueCreateNetworkTx error: %v", err)
 		return ids.Empty, err
@@ -764,44 +817,93 @@ func ValidateChainNameAndGetChains(chainName string) error {
 	return nil
 }
 
-// IncreaseValidatorPChainBalance increases a validator's balance on P-chain.
+// IncreaseValidatorPChainBalance increases the balance an L1 validator pays
+// the P-Chain continuous fee from.
 func (d *PublicDeployer) IncreaseValidatorPChainBalance(
-	_ ids.ID, // validationID reserved for future use
+	validationID ids.ID,
 	balance uint64,
 ) error {
-	wallet, err := d.loadWallet()
+	pWallet, err := d.pChainWallet()
 	if err != nil {
 		return err
 	}
-
-	// Create a base transaction to transfer funds to increase validator balance
-	// Use the network's native asset ID (LUX)
-	luxAssetID := ids.Empty
-	if d.network.ID() == constants.MainnetID || d.network.ID() == constants.TestnetID {
-		luxAssetID = ids.Empty // Native asset on mainnet/testnet
+	if d.usingLedger {
+		ux.Logger.PrintToUser("*** Please sign tx hash on the ledger device *** ")
+	}
+	tx, err := issuePChainTx(func() (*txs.Tx, error) {
+		return pWallet.IssueIncreaseL1ValidatorBalanceTx(validationID, balance)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to increase validator balance: %w", err)
 	}
+	ux.Logger.PrintToUser("Increased validator balance by %d nLUX", balance)
+	ux.Logger.PrintToUser("Transaction ID: %s", tx.ID())
+	return nil
+}
 
-	outputs := []*lux.TransferableOutput{
-		{
-			Asset: lux.Asset{ID: luxAssetID},
-			Out: &secp256k1fx.TransferOutput{
-				Amt: balance,
-				OutputOwners: secp256k1fx.OutputOwners{
-					Threshold: 1,
-					Addrs:     []ids.ShortID{},
-				},
-			},
-		},
+// SetL1ValidatorWeight issues a SetL1ValidatorWeightTx carrying the signed
+// L1ValidatorWeight warp message of a validator manager, which also removes
+// the validator when the weight is 0.
+func (d *PublicDeployer) SetL1ValidatorWeight(message []byte) (ids.ID, error) {
+	pWallet, err := d.pChainWallet()
+	if err != nil {
+		return ids.Empty, err
+	}
+	if d.usingLedger {
+		ux.Logger.PrintToUser("*** Please sign tx hash on the ledger device *** ")
 	}
+	tx, err := issuePChainTx(func() (*txs.Tx, error) {
+		return pWallet.IssueSetL1ValidatorWeightTx(message)
+	})
+	if err != nil {
+		return ids.Empty, fmt.Errorf("failed to set L1 validator weight: %w", err)
+	}
+	return tx.ID(), nil
+}
 
-	tx, err := wallet.P().IssueBaseTx(outputs)
+// RegisterL1Validator issues a RegisterL1ValidatorTx carrying the signed
+// RegisterL1Validator warp message of a validator manager, adding the
+// validator to the L1 with balance, in nLUX, to pay its continuous fee. The
+// proof of possession is of the BLS key of the message.
+func (d *PublicDeployer) RegisterL1Validator(
+	balance uint64,
+	proofOfPossession [bls.SignatureLen]byte,
+	message []byte,
+) (ids.ID, error) {
+	pWallet, err := d.pChainWallet()
+	if err != nil {
+		return ids.Empty, err
+	}
+	if d.usingLedger {
+		ux.Logger.PrintToUser("*** Please sign tx hash on the ledger device *** ")
+	}
+	tx, err := issuePChainTx(func() (*txs.Tx, error) {
+		return pWallet.IssueRegisterL1ValidatorTx(balance, proofOfPossession, message)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to create balance increase transaction: %w", err)
+		return ids.Empty, fmt.Errorf("failed to register L1 validator: %w", err)
 	}
+	return tx.ID(), nil
+}
 
-	ux.Logger.PrintToUser("Increased validator balance by %d nLUX", balance)
-	ux.Logger.PrintToUser("Transaction ID: %s", tx.ID())
-	return nil
+// DisableL1Validator issues a DisableL1ValidatorTx, returning the remaining
+// balance of the validator to its owner. The keychain must hold the
+// validator's deactivation owner key.
+func (d *PublicDeployer) DisableL1Validator(validationID ids.ID) (ids.ID, error) {
+	pWallet, err := d.pChainWallet()
+	if err != nil {
+		return ids.Empty, err
+	}
+	if d.usingLedger {
+		ux.Logger.PrintToUser("*** Please sign tx hash on the ledger device *** ")
+	}
+	tx, err := issuePChainTx(func() (*txs.Tx, error) {
+		return pWallet.IssueDisableL1ValidatorTx(validationID)
+	})
+	if err != nil {
+		return ids.Empty, fmt.Errorf("failed to disable L1 validator: %w", err)
+	}
+	return tx.ID(), nil
 }
 
 // GetDefaultChainAirdropKeyInfo returns the default airdrop key information for a chain.
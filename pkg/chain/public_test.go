@@ -0,0 +1,139 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	cliutils "github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/protocol/p/txs"
+	"github.com/luxfi/sdk/wallet/chain/p"
+	"github.com/luxfi/sdk/wallet/primary/common"
+	"github.com/luxfi/utxo/secp256k1fx"
+)
+
+// fakePWallet issues the L1 validator txs without a network, recording the
+// last one. The other p.Wallet methods are not used by these paths.
+type fakePWallet struct {
+	p.Wallet
+	issued *txs.Tx
+	err    error
+}
+
+func (w *fakePWallet) issue(unsignedTx txs.UnsignedTx) (*txs.Tx, error) {
+	if w.err != nil {
+		return nil, w.err
+	}
+	tx := &txs.Tx{Unsigned: unsignedTx}
+	if err := tx.Initialize(txs.Codec); err != nil {
+		return nil, err
+	}
+	w.issued = tx
+	return tx, nil
+}
+
+func (w *fakePWallet) IssueIncreaseL1ValidatorBalanceTx(validationID ids.ID, balance uint64, _ ...common.Option) (*txs.Tx, error) {
+	return w.issue(&txs.IncreaseL1ValidatorBalanceTx{ValidationID: validationID, Balance: balance})
+}
+
+func (w *fakePWallet) IssueSetL1ValidatorWeightTx(message []byte, _ ...common.Option) (*txs.Tx, error) {
+	return w.issue(&txs.SetL1ValidatorWeightTx{Message: message})
+}
+
+func (w *fakePWallet) IssueRegisterL1ValidatorTx(balance uint64, proofOfPossession [bls.SignatureLen]byte, message []byte, _ ...common.Option) (*txs.Tx, error) {
+	return w.issue(&txs.RegisterL1ValidatorTx{Balance: balance, ProofOfPossession: proofOfPossession, Message: message})
+}
+
+func (w *fakePWallet) IssueDisableL1ValidatorTx(validationID ids.ID, _ ...common.Option) (*txs.Tx, error) {
+	return w.issue(&txs.DisableL1ValidatorTx{ValidationID: validationID, DisableAuth: &secp256k1fx.Input{}})
+}
+
+func newFakePublicDeployer(wallet *fakePWallet) *PublicDeployer {
+	return &PublicDeployer{
+		pWallet: func() (p.Wallet, error) { return wallet, nil },
+	}
+}
+
+func TestL1ValidatorTxs(t *testing.T) {
+	require := setupTest(t)
+	validationID := ids.GenerateTestID()
+	message := []byte("signed warp message")
+	cache := cliutils.NewTTLCache[string, int](time.Minute)
+
+	wallet := &fakePWallet{}
+	d := newFakePublicDeployer(wallet)
+
+	cache.Set("validators", 1)
+	require.NoError(d.IncreaseValidatorPChainBalance(validationID, 5))
+	increaseTx, ok := wallet.issued.Unsigned.(*txs.IncreaseL1ValidatorBalanceTx)
+	require.True(ok)
+	require.Equal(validationID, increaseTx.ValidationID)
+	require.Equal(uint64(5), increaseTx.Balance)
+	_, cached := cache.Get("validators")
+	require.False(cached)
+
+	cache.Set("validators", 1)
+	txID, err := d.SetL1ValidatorWeight(message)
+	require.NoError(err)
+	require.Equal(wallet.issued.ID(), txID)
+	weightTx, ok := wallet.issued.Unsigned.(*txs.SetL1ValidatorWeightTx)
+	require.True(ok)
+	require.Equal(message, []byte(weightTx.Message))
+	_, cached = cache.Get("validators")
+	require.False(cached)
+
+	var pop [bls.SignatureLen]byte
+	pop[0] = 1
+	txID, err = d.RegisterL1Validator(7, pop, message)
+	require.NoError(err)
+	require.Equal(wallet.issued.ID(), txID)
+	registerTx, ok := wallet.issued.Unsigned.(*txs.RegisterL1ValidatorTx)
+	require.True(ok)
+	require.Equal(uint64(7), registerTx.Balance)
+	require.Equal(pop, registerTx.ProofOfPossession)
+
+	cache.Set("validators", 1)
+	txID, err = d.DisableL1Validator(validationID)
+	require.NoError(err)
+	require.Equal(wallet.issued.ID(), txID)
+	disableTx, ok := wallet.issued.Unsigned.(*txs.DisableL1ValidatorTx)
+	require.True(ok)
+	require.Equal(validationID, disableTx.ValidationID)
+	_, cached = cache.Get("validators")
+	require.False(cached)
+}
+
+func TestL1ValidatorTxsFailure(t *testing.T) {
+	require := setupTest(t)
+	errIssue := errors.New("insufficient funds")
+	cache := cliutils.NewTTLCache[string, int](time.Minute)
+	cache.Set("validators", 1)
+
+	wallet := &fakePWallet{err: errIssue}
+	d := newFakePublicDeployer(wallet)
+
+	err := d.IncreaseValidatorPChainBalance(ids.GenerateTestID(), 5)
+	require.ErrorIs(err, errIssue)
+	require.ErrorContains(err, "failed to increase validator balance")
+	_, err = d.SetL1ValidatorWeight([]byte("message"))
+	require.ErrorIs(err, errIssue)
+	require.ErrorContains(err, "failed to set L1 validator weight")
+	_, err = d.DisableL1Validator(ids.GenerateTestID())
+	require.ErrorIs(err, errIssue)
+	require.ErrorContains(err, "failed to disable L1 validator")
+	require.Nil(wallet.issued)
+
+	// Nothing changed on P-Chain, the cached results are still valid
+	_, cached := cache.Get("validators")
+	require.True(cached)
+
+	errLoad := errors.New("no API endpoint")
+	d = &PublicDeployer{pWallet: func() (p.Wallet, error) { return nil, errLoad }}
+	_, err = d.DisableL1Validator(ids.GenerateTestID())
+	require.ErrorIs(err, errLoad)
+}
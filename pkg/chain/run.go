@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/utils"
 )
 
 // RunManager manages network run directories with stable symlinks
@@ -31,24 +32,18 @@ func (r *RunManager) ProfileDir() string {
 	return filepath.Join(r.app.GetBaseDir(), "runs", r.profile)
 }
 
-// CurrentLink returns the path to the "current" symlink
+// CurrentLink returns the path to the "current" link. It is a symlink, or a
+// plain file holding the run directory name where symlinks are unavailable.
 func (r *RunManager) CurrentLink() string {
 	return filepath.Join(r.ProfileDir(), "current")
 }
 
 // CurrentRunDir returns the actual run directory that "current" points to
 func (r *RunManager) CurrentRunDir() (string, error) {
-	linkPath := r.CurrentLink()
-	target, err := os.Readlink(linkPath)
+	target, err := utils.ReadDirLink(r.CurrentLink())
 	if err != nil {
 		return "", fmt.Errorf("no current run: %w", err)
 	}
-
-	// If relative, resolve against profile dir
-	if !filepath.IsAbs(target) {
-		target = filepath.Join(r.ProfileDir(), target)
-	}
-
 	return target, nil
 }
 
@@ -67,12 +62,7 @@ func (r *RunManager) EnsureRunDir(fresh, newRun bool) (string, error) {
 
 	// Reuse current target unless explicitly starting a new run
 	if !newRun {
-		if target, err := os.Readlink(currentLink); err == nil {
-			absPath := target
-			if !filepath.IsAbs(absPath) {
-				absPath = filepath.Join(base, target)
-			}
-
+		if absPath, err := utils.ReadDirLink(currentLink); err == nil {
 			if fresh {
 				// Wipe and recreate
 				_ = os.RemoveAll(absPath)
@@ -106,30 +96,12 @@ func (r *RunManager) SetCurrent(runDir string) error {
 
 // updateCurrentLink atomically updates the current symlink
 func (r *RunManager) updateCurrentLink(runDir string) error {
-	base := r.ProfileDir()
-	currentLink := r.CurrentLink()
-
 	// Get relative path for cleaner symlink
-	relPath, err := filepath.Rel(base, runDir)
+	relPath, err := filepath.Rel(r.ProfileDir(), runDir)
 	if err != nil {
 		relPath = runDir // Fall back to absolute
 	}
-
-	// Create temp symlink then rename for atomicity
-	tmpLink := filepath.Join(base, ".current_tmp")
-	_ = os.Remove(tmpLink)
-
-	if err := os.Symlink(relPath, tmpLink); err != nil {
-		return fmt.Errorf("failed to create temp symlink: %w", err)
-	}
-
-	// Remove existing and rename temp to current
-	_ = os.Remove(currentLink)
-	if err := os.Rename(tmpLink, currentLink); err != nil {
-		return fmt.Errorf("failed to update current symlink: %w", err)
-	}
-
-	return nil
+	return utils.WriteDirLink(r.CurrentLink(), relPath)
 }
 
 // NodeDir returns the directory for a specific node in the current run
@@ -206,11 +178,7 @@ func EnsureNetworkRunDir(baseRunsDir, network string) (string, error) {
 	}
 
 	// Reuse current target if it exists
-	if target, err := os.Readlink(currentLink); err == nil {
-		absPath := target
-		if !filepath.IsAbs(absPath) {
-			absPath = filepath.Join(base, target)
-		}
+	if absPath, err := utils.ReadDirLink(currentLink); err == nil {
 		return absPath, nil
 	}
 
@@ -222,16 +190,8 @@ func EnsureNetworkRunDir(baseRunsDir, network string) (string, error) {
 	}
 
 	// Atomically update current symlink
-	tmpLink := filepath.Join(base, ".current_tmp")
-	_ = os.Remove(tmpLink)
-
-	if err := os.Symlink(runName, tmpLink); err != nil {
-		return "", fmt.Errorf("failed to create temp symlink: %w", err)
-	}
-
-	_ = os.Remove(currentLink)
-	if err := os.Rename(tmpLink, currentLink); err != nil {
-		return "", fmt.Errorf("failed to update current symlink: %w", err)
+	if err := utils.WriteDirLink(currentLink, runName); err != nil {
+		return "", err
 	}
 
 	return runDir, nil
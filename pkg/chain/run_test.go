@@ -0,0 +1,40 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsureNetworkRunDirReusesCurrent(t *testing.T) {
+	require := require.New(t)
+	runsDir := t.TempDir()
+
+	runDir, err := EnsureNetworkRunDir(runsDir, "local")
+	require.NoError(err)
+	require.DirExists(runDir)
+
+	again, err := EnsureNetworkRunDir(runsDir, "local")
+	require.NoError(err)
+	require.Equal(runDir, again)
+}
+
+func TestEnsureNetworkRunDirLinkFile(t *testing.T) {
+	require := require.New(t)
+	runsDir := t.TempDir()
+
+	// Where symlinks are unavailable "current" is a file holding the run name
+	base := filepath.Join(runsDir, "local")
+	runDir := filepath.Join(base, "run_20250101_000000")
+	require.NoError(os.MkdirAll(runDir, 0o750))
+	require.NoError(os.WriteFile(filepath.Join(base, "current"), []byte("run_20250101_000000\n"), 0o600))
+
+	got, err := EnsureNetworkRunDir(runsDir, "local")
+	require.NoError(err)
+	require.Equal(runDir, got)
+}
@@ -0,0 +1,219 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package chainexport exports the blocks, receipts and state of an EVM chain
+// from its database, for analytics pipelines or for importing into other
+// execution clients.
+//
+// Blocks are exported as an RLP stream, the format 'geth export' writes and
+// admin_importChain reads, or as JSON lines holding each block's header,
+// transactions and receipts. State is exported as JSON lines, one account
+// per line, as 'geth dump --iterative' writes.
+package chainexport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/luxfi/database"
+	"github.com/luxfi/database/prefixdb"
+	evmdatabase "github.com/luxfi/evm/plugin/evm/database"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/rawdb"
+	"github.com/luxfi/geth/core/state"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/ethdb"
+	"github.com/luxfi/geth/params"
+	"github.com/luxfi/geth/rlp"
+	"github.com/luxfi/geth/triedb"
+	"github.com/luxfi/geth/triedb/pathdb"
+)
+
+// Format is an export format
+type Format string
+
+const (
+	// FormatRLP is a stream of RLP encoded blocks
+	FormatRLP Format = "rlp"
+	// FormatJSONL is one JSON object per line
+	FormatJSONL Format = "jsonl"
+)
+
+// ethDBPrefix is where the EVM keeps its chain in the chain database
+var ethDBPrefix = []byte("ethdb")
+
+var errNoHead = errors.New("no head block: not an EVM chain database")
+
+// ParseFormat parses an export format name
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatRLP, FormatJSONL:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown format %q: must be rlp or jsonl", s)
+}
+
+// ChainDB returns the chain an EVM keeps in db, the database of its
+// chainData directory
+func ChainDB(db database.Database) ethdb.Database {
+	return rawdb.NewDatabase(evmdatabase.WrapDatabase(prefixdb.NewNested(ethDBPrefix, db)))
+}
+
+// Head returns the number of the last accepted block
+func Head(db ethdb.Database) (uint64, error) {
+	hash := rawdb.ReadHeadBlockHash(db)
+	if hash == (common.Hash{}) {
+		return 0, errNoHead
+	}
+	number, ok := rawdb.ReadHeaderNumber(db, hash)
+	if !ok {
+		return 0, fmt.Errorf("head block %s has no header", hash)
+	}
+	return number, nil
+}
+
+// Range is an inclusive range of block numbers
+type Range struct {
+	From uint64
+	To   uint64
+}
+
+// Len is the number of blocks in r
+func (r Range) Len() uint64 {
+	return r.To - r.From + 1
+}
+
+// ParseRange parses <from>:<to>, where either bound may be a block number,
+// "latest" or empty (genesis and head respectively). A single bound selects
+// one block.
+func ParseRange(s string, head uint64) (Range, error) {
+	parse := func(bound string, empty uint64) (uint64, error) {
+		switch bound = strings.TrimSpace(bound); bound {
+		case "":
+			return empty, nil
+		case "latest":
+			return head, nil
+		}
+		n, err := strconv.ParseUint(bound, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid block number %q", bound)
+		}
+		return n, nil
+	}
+	fromStr, toStr, isRange := strings.Cut(s, ":")
+	from, err := parse(fromStr, 0)
+	if err != nil {
+		return Range{}, err
+	}
+	to := from
+	if isRange {
+		if to, err = parse(toStr, head); err != nil {
+			return Range{}, err
+		}
+	}
+	switch {
+	case from > to:
+		return Range{}, fmt.Errorf("invalid range %q: %d is after %d", s, from, to)
+	case to > head:
+		return Range{}, fmt.Errorf("invalid range %q: the head block is %d", s, head)
+	}
+	return Range{From: from, To: to}, nil
+}
+
+// blockLine is a block as exported to JSON lines
+type blockLine struct {
+	Header       *types.Header      `json:"header"`
+	Transactions types.Transactions `json:"transactions"`
+	Receipts     types.Receipts     `json:"receipts"`
+}
+
+// ExportBlocks writes the canonical blocks of r to w, calling progress after
+// each block
+func ExportBlocks(ctx context.Context, db ethdb.Database, w io.Writer, format Format, r Range, progress func(number uint64)) error {
+	var config *params.ChainConfig
+	if genesis := rawdb.ReadCanonicalHash(db, 0); genesis != (common.Hash{}) {
+		config = rawdb.ReadChainConfig(db, genesis)
+	}
+	encoder := json.NewEncoder(w)
+	for number := r.From; number <= r.To; number++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		hash := rawdb.ReadCanonicalHash(db, number)
+		if hash == (common.Hash{}) {
+			return fmt.Errorf("block %d is not in the database", number)
+		}
+		block := rawdb.ReadBlock(db, hash, number)
+		if block == nil {
+			return fmt.Errorf("block %d (%s) is missing its header or body", number, hash)
+		}
+		switch format {
+		case FormatRLP:
+			if err := rlp.Encode(w, block); err != nil {
+				return err
+			}
+		case FormatJSONL:
+			line := blockLine{
+				Header:       block.Header(),
+				Transactions: block.Transactions(),
+				Receipts:     readReceipts(db, block, config),
+			}
+			if err := encoder.Encode(line); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown format %q", format)
+		}
+		if progress != nil {
+			progress(number)
+		}
+	}
+	return nil
+}
+
+// readReceipts returns the receipts of block with their derived fields when
+// the chain config is known
+func readReceipts(db ethdb.Database, block *types.Block, config *params.ChainConfig) types.Receipts {
+	var receipts types.Receipts
+	if config != nil {
+		receipts = rawdb.ReadReceipts(db, block.Hash(), block.NumberU64(), block.Time(), config)
+	}
+	if receipts == nil {
+		receipts = rawdb.ReadRawReceipts(db, block.Hash(), block.NumberU64())
+	}
+	if receipts == nil {
+		receipts = types.Receipts{}
+	}
+	return receipts
+}
+
+// ExportState writes every account of the state at block number to w as
+// JSON lines. Archive nodes keep the state of every block, others only
+// recent ones.
+func ExportState(db ethdb.Database, w io.Writer, number uint64) error {
+	hash := rawdb.ReadCanonicalHash(db, number)
+	if hash == (common.Hash{}) {
+		return fmt.Errorf("block %d is not in the database", number)
+	}
+	header := rawdb.ReadHeader(db, hash, number)
+	if header == nil {
+		return fmt.Errorf("block %d (%s) is missing its header", number, hash)
+	}
+	config := triedb.HashDefaults
+	if rawdb.ReadStateScheme(db) == rawdb.PathScheme {
+		config = &triedb.Config{PathDB: pathdb.ReadOnly}
+	}
+	tdb := triedb.NewDatabase(db, config)
+	defer tdb.Close()
+	statedb, err := state.New(header.Root, state.NewDatabase(tdb, nil))
+	if err != nil {
+		return fmt.Errorf("state of block %d is not available, it may have been pruned: %w", number, err)
+	}
+	statedb.IterativeDump(&state.DumpConfig{}, json.NewEncoder(w))
+	return nil
+}
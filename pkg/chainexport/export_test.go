@@ -0,0 +1,144 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chainexport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/luxfi/database/memdb"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/rawdb"
+	"github.com/luxfi/geth/core/state"
+	"github.com/luxfi/geth/core/tracing"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/ethdb"
+	"github.com/luxfi/geth/rlp"
+	"github.com/luxfi/geth/triedb"
+	"github.com/stretchr/testify/require"
+)
+
+var funded = common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+
+// newChain writes a genesis block funding one account and n empty blocks
+func newChain(t *testing.T, n int) ethdb.Database {
+	t.Helper()
+	require := require.New(t)
+	db := ChainDB(memdb.New())
+
+	tdb := triedb.NewDatabase(db, nil)
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(tdb, nil))
+	require.NoError(err)
+	statedb.SetBalance(funded, uint256.NewInt(1000), tracing.BalanceChangeUnspecified)
+	root, err := statedb.Commit(0, false, false)
+	require.NoError(err)
+	require.NoError(tdb.Commit(root, false))
+
+	parent := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0), Root: root})
+	for i := 0; ; i++ {
+		rawdb.WriteBlock(db, parent)
+		rawdb.WriteCanonicalHash(db, parent.Hash(), parent.NumberU64())
+		rawdb.WriteReceipts(db, parent.Hash(), parent.NumberU64(), nil)
+		rawdb.WriteHeadBlockHash(db, parent.Hash())
+		if i == n {
+			return db
+		}
+		parent = types.NewBlockWithHeader(&types.Header{
+			Number:     new(big.Int).Add(parent.Number(), common.Big1),
+			ParentHash: parent.Hash(),
+			Root:       root,
+			Time:       parent.Time() + 1,
+		})
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Range
+		err  bool
+	}{
+		{in: "0:latest", want: Range{0, 10}},
+		{in: ":", want: Range{0, 10}},
+		{in: "3:5", want: Range{3, 5}},
+		{in: "7:", want: Range{7, 10}},
+		{in: "4", want: Range{4, 4}},
+		{in: "latest", want: Range{10, 10}},
+		{in: "5:3", err: true},
+		{in: "0:11", err: true},
+		{in: "a:3", err: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRange(tt.in, 10)
+		if tt.err {
+			require.Error(t, err, tt.in)
+			continue
+		}
+		require.NoError(t, err, tt.in)
+		require.Equal(t, tt.want, got, tt.in)
+	}
+}
+
+func TestExportBlocks(t *testing.T) {
+	require := require.New(t)
+	db := newChain(t, 5)
+	head, err := Head(db)
+	require.NoError(err)
+	require.Equal(uint64(5), head)
+
+	var out bytes.Buffer
+	var exported []uint64
+	err = ExportBlocks(context.Background(), db, &out, FormatRLP, Range{From: 2, To: 4}, func(n uint64) {
+		exported = append(exported, n)
+	})
+	require.NoError(err)
+	require.Equal([]uint64{2, 3, 4}, exported)
+
+	stream := rlp.NewStream(&out, 0)
+	for want := uint64(2); want <= 4; want++ {
+		var block types.Block
+		require.NoError(stream.Decode(&block))
+		require.Equal(want, block.NumberU64())
+		require.Equal(rawdb.ReadCanonicalHash(db, want), block.Hash())
+	}
+	require.ErrorIs(stream.Decode(new(types.Block)), io.EOF)
+
+	out.Reset()
+	require.NoError(ExportBlocks(context.Background(), db, &out, FormatJSONL, Range{From: 0, To: 5}, nil))
+	scanner := bufio.NewScanner(&out)
+	var lines int
+	for ; scanner.Scan(); lines++ {
+		var line struct {
+			Header   types.Header      `json:"header"`
+			Receipts []json.RawMessage `json:"receipts"`
+		}
+		require.NoError(json.Unmarshal(scanner.Bytes(), &line))
+		require.Equal(uint64(lines), line.Header.Number.Uint64())
+		require.NotNil(line.Receipts)
+	}
+	require.Equal(6, lines)
+
+	err = ExportBlocks(context.Background(), db, &out, FormatJSONL, Range{From: 5, To: 6}, nil)
+	require.ErrorContains(err, "block 6 is not in the database")
+
+	_, err = Head(ChainDB(memdb.New()))
+	require.ErrorIs(err, errNoHead)
+}
+
+func TestExportState(t *testing.T) {
+	require := require.New(t)
+	db := newChain(t, 1)
+
+	var out bytes.Buffer
+	require.NoError(ExportState(db, &out, 1))
+	require.Contains(out.String(), `"balance":"1000"`)
+
+	require.Error(ExportState(db, &out, 2))
+}
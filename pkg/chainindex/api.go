@@ -0,0 +1,204 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chainindex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/luxfi/database"
+	"github.com/luxfi/geth/common"
+)
+
+// defaultLimit bounds list queries without a limit parameter
+const defaultLimit = 20
+
+// maxLimit bounds every list query
+const maxLimit = 1000
+
+// errBadRequest marks errors in the query parameters
+var errBadRequest = errors.New("bad request")
+
+// Handler serves the query API of the index at path:
+//
+//	GET /status                      what the index covers
+//	GET /blocks?limit=               latest blocks
+//	GET /blocks/{number}             one block
+//	GET /txs?limit=                  latest transactions
+//	GET /txs/{hash}                  one transaction
+//	GET /accounts/{address}/txs      latest transactions of an account
+//	GET /logs?address=&topic=&fromBlock=&toBlock=&limit=
+//
+// Each request opens the index, so the indexer keeps syncing in between.
+func Handler(path string) http.Handler {
+	return NewHandler(func(ctx context.Context) (database.Database, error) {
+		return Open(ctx, path)
+	})
+}
+
+// NewHandler serves the query API of the index open returns
+func NewHandler(open func(context.Context) (database.Database, error)) http.Handler {
+	api := &api{open: open}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", api.handle(func(ix *Index, _ *http.Request) (any, error) {
+		return ix.Meta()
+	}))
+	mux.HandleFunc("GET /blocks", api.handle(func(ix *Index, r *http.Request) (any, error) {
+		limit, err := limitParam(r)
+		if err != nil {
+			return nil, err
+		}
+		return ix.Blocks(limit)
+	}))
+	mux.HandleFunc("GET /blocks/{number}", api.handle(func(ix *Index, r *http.Request) (any, error) {
+		number, err := uintParam("number", r.PathValue("number"))
+		if err != nil {
+			return nil, err
+		}
+		return ix.Block(number)
+	}))
+	mux.HandleFunc("GET /txs", api.handle(func(ix *Index, r *http.Request) (any, error) {
+		limit, err := limitParam(r)
+		if err != nil {
+			return nil, err
+		}
+		return ix.Txs(limit)
+	}))
+	mux.HandleFunc("GET /txs/{hash}", api.handle(func(ix *Index, r *http.Request) (any, error) {
+		hash, err := ParseHash(r.PathValue("hash"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errBadRequest, err)
+		}
+		return ix.Tx(hash)
+	}))
+	mux.HandleFunc("GET /accounts/{address}/txs", api.handle(func(ix *Index, r *http.Request) (any, error) {
+		addr, err := addressParam(r.PathValue("address"))
+		if err != nil {
+			return nil, err
+		}
+		limit, err := limitParam(r)
+		if err != nil {
+			return nil, err
+		}
+		return ix.AccountTxs(addr, limit)
+	}))
+	mux.HandleFunc("GET /logs", api.handle(func(ix *Index, r *http.Request) (any, error) {
+		filter, err := logFilterParams(r)
+		if err != nil {
+			return nil, err
+		}
+		return ix.Logs(filter)
+	}))
+	return mux
+}
+
+type api struct {
+	// mu serializes requests, the database admits a single open at a time
+	mu   sync.Mutex
+	open func(context.Context) (database.Database, error)
+}
+
+// handle runs query on the index and writes its result as JSON
+func (a *api) handle(query func(*Index, *http.Request) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := a.query(r, query)
+		switch {
+		case errors.Is(err, errBadRequest):
+			writeError(w, http.StatusBadRequest, err)
+		case errors.Is(err, database.ErrNotFound):
+			writeError(w, http.StatusNotFound, err)
+		case err != nil:
+			writeError(w, http.StatusInternalServerError, err)
+		default:
+			writeJSON(w, http.StatusOK, result)
+		}
+	}
+}
+
+func (a *api) query(r *http.Request, query func(*Index, *http.Request) (any, error)) (any, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	db, err := a.open(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return query(New(db), r)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func limitParam(r *http.Request) (int, error) {
+	s := r.URL.Query().Get("limit")
+	if s == "" {
+		return defaultLimit, nil
+	}
+	limit, err := strconv.Atoi(s)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("%w: invalid limit %q", errBadRequest, s)
+	}
+	return min(limit, maxLimit), nil
+}
+
+func uintParam(name, s string) (uint64, error) {
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid %s %q", errBadRequest, name, s)
+	}
+	return n, nil
+}
+
+func addressParam(s string) (common.Address, error) {
+	if !common.IsHexAddress(s) {
+		return common.Address{}, fmt.Errorf("%w: invalid address %q", errBadRequest, s)
+	}
+	return common.HexToAddress(s), nil
+}
+
+func logFilterParams(r *http.Request) (LogFilter, error) {
+	q := r.URL.Query()
+	filter := LogFilter{}
+	var err error
+	if filter.Limit, err = limitParam(r); err != nil {
+		return filter, err
+	}
+	if s := q.Get("address"); s != "" {
+		addr, err := addressParam(s)
+		if err != nil {
+			return filter, err
+		}
+		filter.Address = &addr
+	}
+	if s := q.Get("topic"); s != "" {
+		topic, err := ParseHash(s)
+		if err != nil {
+			return filter, fmt.Errorf("%w: invalid topic %q", errBadRequest, s)
+		}
+		filter.Topic = &topic
+	}
+	if s := q.Get("fromBlock"); s != "" {
+		if filter.FromBlock, err = uintParam("fromBlock", s); err != nil {
+			return filter, err
+		}
+	}
+	if s := q.Get("toBlock"); s != "" {
+		if filter.ToBlock, err = uintParam("toBlock", s); err != nil {
+			return filter, err
+		}
+	}
+	return filter, nil
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chainindex
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/database"
+	"github.com/luxfi/database/memdb"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// sharedDB outlives the handler closing it after each request
+type sharedDB struct {
+	database.Database
+}
+
+func (sharedDB) Close() error { return nil }
+
+func TestHandler(t *testing.T) {
+	require := require.New(t)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	token := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+	topic := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	chain := newFakeChain(7777, 0)
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(chain.chainID), &types.LegacyTx{
+		To: &token, Gas: 50000, GasPrice: big.NewInt(25),
+	})
+	require.NoError(err)
+	chain.addBlock([]*types.Transaction{tx}, []*types.Receipt{{
+		Status: types.ReceiptStatusSuccessful, GasUsed: 30000,
+		Logs: []*types.Log{{Address: token, Topics: []common.Hash{topic}}},
+	}})
+
+	db := sharedDB{memdb.New()}
+	_, err = New(db).Sync(context.Background(), chain, 0)
+	require.NoError(err)
+	server := httptest.NewServer(NewHandler(func(context.Context) (database.Database, error) {
+		return db, nil
+	}))
+	defer server.Close()
+
+	get := func(path string, wantStatus int, v any) {
+		resp, err := http.Get(server.URL + path) //nolint:noctx // test server
+		require.NoError(err)
+		defer resp.Body.Close()
+		require.Equal(wantStatus, resp.StatusCode, path)
+		require.NoError(json.NewDecoder(resp.Body).Decode(v), path)
+	}
+
+	var meta Meta
+	get("/status", http.StatusOK, &meta)
+	require.Equal(uint64(1), meta.Height)
+
+	var blocks []Block
+	get("/blocks?limit=1", http.StatusOK, &blocks)
+	require.Len(blocks, 1)
+	require.Equal(uint64(1), blocks[0].Number)
+
+	var block Block
+	get("/blocks/0", http.StatusOK, &block)
+	require.Equal(chain.blocks[0].Hash(), block.Hash)
+
+	var txs []Tx
+	get("/txs", http.StatusOK, &txs)
+	require.Len(txs, 1)
+	get("/accounts/"+token.Hex()+"/txs", http.StatusOK, &txs)
+	require.Len(txs, 1)
+
+	var got Tx
+	get("/txs/"+tx.Hash().Hex(), http.StatusOK, &got)
+	require.Equal(tx.Hash(), got.Hash)
+
+	var logs []Log
+	get("/logs?address="+token.Hex()+"&topic="+topic.Hex()+"&fromBlock=1", http.StatusOK, &logs)
+	require.Len(logs, 1)
+	require.Equal(tx.Hash(), logs[0].TxHash)
+
+	var apiErr map[string]string
+	get("/txs/"+common.Hash{1}.Hex(), http.StatusNotFound, &apiErr)
+	get("/blocks/latest", http.StatusBadRequest, &apiErr)
+	get("/logs?limit=0", http.StatusBadRequest, &apiErr)
+	require.Contains(apiErr["error"], "invalid limit")
+}
@@ -0,0 +1,514 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package chainindex indexes the blocks, transactions and logs of an EVM
+// chain into a local database, so deployments can be debugged and queried
+// without running an explorer.
+//
+// Lux chains have instant finality, so blocks are indexed once and never
+// rolled back. The index remembers the genesis hash of the chain it was
+// built from and refuses to mix in blocks of a redeployed chain.
+package chainindex
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/luxfi/database"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/rpc"
+)
+
+var (
+	// ErrOtherChain is returned when the index was built from another
+	// chain, typically one deployed before under the same name
+	ErrOtherChain = errors.New("index belongs to another chain")
+	// ErrOldIndex is returned when the index was built by an older version
+	// of the indexer, lacking records the current one keeps
+	ErrOldIndex = errors.New("index built by an older version")
+
+	metaKey          = []byte("meta")
+	txPrefix         = []byte("t/")
+	blockPrefix      = []byte("b/")
+	accountPrefix    = []byte("a/")
+	headerPrefix     = []byte("h/")
+	logPrefix        = []byte("l/")
+	logAddressPrefix = []byte("e/")
+	logTopicPrefix   = []byte("o/")
+)
+
+// version is the layout of the index: 2 added blocks and logs
+const version = 2
+
+// Client is the part of an EVM RPC client the indexer uses, as implemented
+// by ethclient.Client
+type Client interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	BlockReceipts(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]*types.Receipt, error)
+}
+
+// Meta describes the indexed chain
+type Meta struct {
+	Version     int         `json:"version"`
+	ChainID     string      `json:"chainID"`
+	GenesisHash common.Hash `json:"genesisHash"`
+	// Height is the last indexed block
+	Height    uint64    `json:"height"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Tx is an indexed transaction
+type Tx struct {
+	Hash            common.Hash     `json:"hash"`
+	BlockNumber     uint64          `json:"blockNumber"`
+	BlockHash       common.Hash     `json:"blockHash"`
+	Index           uint            `json:"index"`
+	Time            time.Time       `json:"time"`
+	From            common.Address  `json:"from"`
+	To              *common.Address `json:"to,omitempty"`
+	Value           string          `json:"value"`
+	Nonce           uint64          `json:"nonce"`
+	Gas             uint64          `json:"gas"`
+	GasPrice        string          `json:"gasPrice"`
+	GasUsed         uint64          `json:"gasUsed"`
+	Status          uint64          `json:"status"`
+	ContractAddress *common.Address `json:"contractAddress,omitempty"`
+	Input           string          `json:"input,omitempty"`
+	Logs            int             `json:"logs"`
+}
+
+// Succeeded reports whether the transaction executed without reverting
+func (t *Tx) Succeeded() bool {
+	return t.Status == types.ReceiptStatusSuccessful
+}
+
+// Block is an indexed block
+type Block struct {
+	Number     uint64         `json:"number"`
+	Hash       common.Hash    `json:"hash"`
+	ParentHash common.Hash    `json:"parentHash"`
+	Time       time.Time      `json:"time"`
+	Coinbase   common.Address `json:"coinbase"`
+	GasUsed    uint64         `json:"gasUsed"`
+	GasLimit   uint64         `json:"gasLimit"`
+	BaseFee    string         `json:"baseFee,omitempty"`
+	Txs        int            `json:"txs"`
+}
+
+// Log is an indexed event log
+type Log struct {
+	Address     common.Address `json:"address"`
+	Topics      []common.Hash  `json:"topics"`
+	Data        string         `json:"data,omitempty"`
+	BlockNumber uint64         `json:"blockNumber"`
+	TxHash      common.Hash    `json:"txHash"`
+	TxIndex     uint           `json:"txIndex"`
+	// Index is the position of the log in the block
+	Index uint      `json:"logIndex"`
+	Time  time.Time `json:"time"`
+}
+
+// Index stores the blocks, transactions and logs of one chain
+type Index struct {
+	db database.Database
+}
+
+// New returns the index kept in db
+func New(db database.Database) *Index {
+	return &Index{db: db}
+}
+
+// Meta returns what the index covers, or nil for an empty index
+func (ix *Index) Meta() (*Meta, error) {
+	data, err := ix.db.Get(metaKey)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m Meta
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid index metadata: %w", err)
+	}
+	return &m, nil
+}
+
+// Sync indexes the blocks after the last indexed one up to the chain head,
+// at most maxBlocks of them (0 for all), and returns how many it indexed
+func (ix *Index) Sync(ctx context.Context, client Client, maxBlocks uint64) (uint64, error) {
+	meta, err := ix.Meta()
+	if err != nil {
+		return 0, err
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	genesis, err := client.BlockByNumber(ctx, big.NewInt(0))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get genesis block: %w", err)
+	}
+	next := uint64(0)
+	if meta == nil {
+		meta = &Meta{Version: version, ChainID: chainID.String(), GenesisHash: genesis.Hash()}
+	} else {
+		if meta.Version != version {
+			return 0, fmt.Errorf("%w: rebuild it from genesis", ErrOldIndex)
+		}
+		if meta.ChainID != chainID.String() || meta.GenesisHash != genesis.Hash() {
+			return 0, fmt.Errorf("%w: indexed chain %s with genesis %s, endpoint serves chain %s with genesis %s",
+				ErrOtherChain, meta.ChainID, meta.GenesisHash, chainID, genesis.Hash())
+		}
+		next = meta.Height + 1
+	}
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get chain head: %w", err)
+	}
+	if maxBlocks > 0 && head >= next+maxBlocks {
+		head = next + maxBlocks - 1
+	}
+
+	signer := types.LatestSignerForChainID(chainID)
+	var indexed uint64
+	for height := next; height <= head; height++ {
+		if err := ctx.Err(); err != nil {
+			return indexed, err
+		}
+		if err := ix.indexBlock(ctx, client, signer, height, meta); err != nil {
+			return indexed, fmt.Errorf("failed to index block %d: %w", height, err)
+		}
+		indexed++
+	}
+	return indexed, nil
+}
+
+// indexBlock writes the transactions of block height and advances meta to
+// it, atomically
+func (ix *Index) indexBlock(ctx context.Context, client Client, signer types.Signer, height uint64, meta *Meta) error {
+	block, err := client.BlockByNumber(ctx, new(big.Int).SetUint64(height))
+	if err != nil {
+		return err
+	}
+	var receipts []*types.Receipt
+	if len(block.Transactions()) > 0 {
+		receipts, err = client.BlockReceipts(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(height))) //nolint:gosec // G115: block heights fit in int64
+		if err != nil {
+			return err
+		}
+		if len(receipts) != len(block.Transactions()) {
+			return fmt.Errorf("got %d receipts for %d transactions", len(receipts), len(block.Transactions()))
+		}
+	}
+
+	batch := ix.db.NewBatch()
+	if err := putJSON(batch, concat(headerPrefix, positionKey(height, 0)), blockRecord(block)); err != nil {
+		return err
+	}
+	var logIndex uint
+	for i, tx := range block.Transactions() {
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return fmt.Errorf("failed to recover sender of %s: %w", tx.Hash(), err)
+		}
+		receipt := receipts[i]
+		record := Tx{
+			Hash:        tx.Hash(),
+			BlockNumber: height,
+			BlockHash:   block.Hash(),
+			Index:       uint(i),                                 //nolint:gosec // G115: tx index is non-negative
+			Time:        time.Unix(int64(block.Time()), 0).UTC(), //nolint:gosec // G115: block timestamps fit in int64
+			From:        from,
+			To:          tx.To(),
+			Value:       tx.Value().String(),
+			Nonce:       tx.Nonce(),
+			Gas:         tx.Gas(),
+			GasPrice:    effectiveGasPrice(tx, receipt).String(),
+			GasUsed:     receipt.GasUsed,
+			Status:      receipt.Status,
+			Logs:        len(receipt.Logs),
+		}
+		if receipt.ContractAddress != (common.Address{}) {
+			contract := receipt.ContractAddress
+			record.ContractAddress = &contract
+		}
+		if data := tx.Data(); len(data) > 0 {
+			record.Input = "0x" + common.Bytes2Hex(data)
+		}
+		position := positionKey(height, uint(i)) //nolint:gosec // G115: tx index is non-negative
+		if err := putJSON(batch, concat(txPrefix, tx.Hash().Bytes()), record); err != nil {
+			return err
+		}
+		if err := batch.Put(concat(blockPrefix, position), tx.Hash().Bytes()); err != nil {
+			return err
+		}
+		for _, addr := range record.accounts() {
+			if err := batch.Put(concat(accountPrefix, addr.Bytes(), position), tx.Hash().Bytes()); err != nil {
+				return err
+			}
+		}
+		for _, log := range receipt.Logs {
+			if err := putLog(batch, record, log, logIndex); err != nil {
+				return err
+			}
+			logIndex++
+		}
+	}
+
+	meta.Height = height
+	meta.UpdatedAt = time.Now().UTC()
+	if err := putJSON(batch, metaKey, meta); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+func blockRecord(block *types.Block) Block {
+	record := Block{
+		Number:     block.NumberU64(),
+		Hash:       block.Hash(),
+		ParentHash: block.ParentHash(),
+		Time:       time.Unix(int64(block.Time()), 0).UTC(), //nolint:gosec // G115: block timestamps fit in int64
+		Coinbase:   block.Coinbase(),
+		GasUsed:    block.GasUsed(),
+		GasLimit:   block.GasLimit(),
+		Txs:        len(block.Transactions()),
+	}
+	if block.BaseFee() != nil {
+		record.BaseFee = block.BaseFee().String()
+	}
+	return record
+}
+
+// putLog writes log, emitted by tx at position index of its block, and
+// lists it under its address and first topic
+func putLog(batch database.Batch, tx Tx, log *types.Log, index uint) error {
+	record := Log{
+		Address:     log.Address,
+		Topics:      log.Topics,
+		BlockNumber: tx.BlockNumber,
+		TxHash:      tx.Hash,
+		TxIndex:     tx.Index,
+		Index:       index,
+		Time:        tx.Time,
+	}
+	if record.Topics == nil {
+		record.Topics = []common.Hash{}
+	}
+	if len(log.Data) > 0 {
+		record.Data = "0x" + common.Bytes2Hex(log.Data)
+	}
+	position := positionKey(tx.BlockNumber, index)
+	if err := putJSON(batch, concat(logPrefix, position), record); err != nil {
+		return err
+	}
+	if err := batch.Put(concat(logAddressPrefix, log.Address.Bytes(), position), position); err != nil {
+		return err
+	}
+	if len(log.Topics) > 0 {
+		return batch.Put(concat(logTopicPrefix, log.Topics[0].Bytes(), position), position)
+	}
+	return nil
+}
+
+func putJSON(batch database.Batch, key []byte, v any) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return batch.Put(key, value)
+}
+
+// accounts are the addresses a transaction is listed under
+func (t *Tx) accounts() []common.Address {
+	accounts := []common.Address{t.From}
+	if t.To != nil && *t.To != t.From {
+		accounts = append(accounts, *t.To)
+	}
+	if t.ContractAddress != nil {
+		accounts = append(accounts, *t.ContractAddress)
+	}
+	return accounts
+}
+
+func effectiveGasPrice(tx *types.Transaction, receipt *types.Receipt) *big.Int {
+	if receipt.EffectiveGasPrice != nil {
+		return receipt.EffectiveGasPrice
+	}
+	return tx.GasPrice()
+}
+
+// Tx returns the indexed transaction hash, or database.ErrNotFound
+func (ix *Index) Tx(hash common.Hash) (*Tx, error) {
+	data, err := ix.db.Get(concat(txPrefix, hash.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	var t Tx
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("invalid indexed transaction %s: %w", hash, err)
+	}
+	return &t, nil
+}
+
+// Txs returns the latest indexed transactions, newest first
+func (ix *Index) Txs(limit int) ([]*Tx, error) {
+	return ix.list(blockPrefix, limit)
+}
+
+// AccountTxs returns the latest indexed transactions sent by, sent to or
+// creating addr, newest first
+func (ix *Index) AccountTxs(addr common.Address, limit int) ([]*Tx, error) {
+	return ix.list(concat(accountPrefix, addr.Bytes()), limit)
+}
+
+// Accounts returns every address with indexed transactions, in address
+// order
+func (ix *Index) Accounts() ([]common.Address, error) {
+	it := ix.db.NewIteratorWithPrefix(accountPrefix)
+	defer it.Release()
+	var accounts []common.Address
+	for it.Next() {
+		key := it.Key()[len(accountPrefix):]
+		if len(key) < common.AddressLength {
+			return nil, fmt.Errorf("invalid indexed account key %x", it.Key())
+		}
+		addr := common.BytesToAddress(key[:common.AddressLength])
+		if len(accounts) == 0 || accounts[len(accounts)-1] != addr {
+			accounts = append(accounts, addr)
+		}
+	}
+	return accounts, it.Error()
+}
+
+func (ix *Index) list(prefix []byte, limit int) ([]*Tx, error) {
+	it := ix.db.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+	var txs []*Tx
+	for it.Next() && (limit <= 0 || len(txs) < limit) {
+		t, err := ix.Tx(common.BytesToHash(it.Value()))
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, t)
+	}
+	return txs, it.Error()
+}
+
+// Block returns the indexed block number, or database.ErrNotFound
+func (ix *Index) Block(number uint64) (*Block, error) {
+	data, err := ix.db.Get(concat(headerPrefix, positionKey(number, 0)))
+	if err != nil {
+		return nil, err
+	}
+	var b Block
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("invalid indexed block %d: %w", number, err)
+	}
+	return &b, nil
+}
+
+// Blocks returns the latest indexed blocks, newest first
+func (ix *Index) Blocks(limit int) ([]*Block, error) {
+	it := ix.db.NewIteratorWithPrefix(headerPrefix)
+	defer it.Release()
+	var blocks []*Block
+	for it.Next() && (limit <= 0 || len(blocks) < limit) {
+		var b Block
+		if err := json.Unmarshal(it.Value(), &b); err != nil {
+			return nil, fmt.Errorf("invalid indexed block: %w", err)
+		}
+		blocks = append(blocks, &b)
+	}
+	return blocks, it.Error()
+}
+
+// LogFilter selects indexed logs
+type LogFilter struct {
+	// Address, when set, selects the logs emitted by a contract
+	Address *common.Address
+	// Topic, when set, selects the logs with this first topic, the event
+	// signature hash
+	Topic     *common.Hash
+	FromBlock uint64
+	// ToBlock is the last block to include, 0 for the last indexed one
+	ToBlock uint64
+	Limit   int
+}
+
+// Logs returns the latest indexed logs matching filter, newest first
+func (ix *Index) Logs(filter LogFilter) ([]*Log, error) {
+	prefix := logPrefix
+	switch {
+	case filter.Address != nil:
+		prefix = concat(logAddressPrefix, filter.Address.Bytes())
+	case filter.Topic != nil:
+		prefix = concat(logTopicPrefix, filter.Topic.Bytes())
+	}
+	start := prefix
+	if filter.ToBlock > 0 {
+		start = concat(prefix, positionKey(filter.ToBlock, math.MaxUint))
+	}
+	it := ix.db.NewIteratorWithStartAndPrefix(start, prefix)
+	defer it.Release()
+	var logs []*Log
+	for it.Next() && (filter.Limit <= 0 || len(logs) < filter.Limit) {
+		position := it.Key()[len(prefix):]
+		if positionHeight(position) < filter.FromBlock {
+			break
+		}
+		data := it.Value()
+		if !bytes.Equal(prefix, logPrefix) {
+			var err error
+			if data, err = ix.db.Get(concat(logPrefix, position)); err != nil {
+				return nil, err
+			}
+		}
+		var log Log
+		if err := json.Unmarshal(data, &log); err != nil {
+			return nil, fmt.Errorf("invalid indexed log: %w", err)
+		}
+		if filter.Topic != nil && (len(log.Topics) == 0 || log.Topics[0] != *filter.Topic) {
+			continue
+		}
+		logs = append(logs, &log)
+	}
+	return logs, it.Error()
+}
+
+// ParseHash parses a 0x prefixed 32 byte hash
+func ParseHash(s string) (common.Hash, error) {
+	b := common.FromHex(strings.TrimSpace(s))
+	if len(b) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid transaction hash %q", s)
+	}
+	return common.BytesToHash(b), nil
+}
+
+// positionKey orders transactions and logs newest first
+func positionKey(height uint64, index uint) []byte {
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key, math.MaxUint64-height)
+	binary.BigEndian.PutUint64(key[8:], math.MaxUint64-uint64(index))
+	return key
+}
+
+func positionHeight(key []byte) uint64 {
+	return math.MaxUint64 - binary.BigEndian.Uint64(key)
+}
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
@@ -0,0 +1,217 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chainindex
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/database"
+	"github.com/luxfi/database/memdb"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChain serves blocks built in memory
+type fakeChain struct {
+	chainID  *big.Int
+	blocks   []*types.Block
+	receipts map[uint64][]*types.Receipt
+}
+
+func newFakeChain(chainID int64, genesisExtra byte) *fakeChain {
+	genesis := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0), Extra: []byte{genesisExtra}})
+	return &fakeChain{
+		chainID:  big.NewInt(chainID),
+		blocks:   []*types.Block{genesis},
+		receipts: map[uint64][]*types.Receipt{},
+	}
+}
+
+func (c *fakeChain) addBlock(txs []*types.Transaction, receipts []*types.Receipt) {
+	number := uint64(len(c.blocks))
+	header := &types.Header{
+		Number:     new(big.Int).SetUint64(number),
+		ParentHash: c.blocks[number-1].Hash(),
+		Time:       1700000000 + number,
+	}
+	c.blocks = append(c.blocks, types.NewBlockWithHeader(header).WithBody(types.Body{Transactions: txs}))
+	c.receipts[number] = receipts
+}
+
+func (c *fakeChain) ChainID(context.Context) (*big.Int, error) { return c.chainID, nil }
+
+func (c *fakeChain) BlockNumber(context.Context) (uint64, error) {
+	return uint64(len(c.blocks) - 1), nil
+}
+
+func (c *fakeChain) BlockByNumber(_ context.Context, number *big.Int) (*types.Block, error) {
+	if number.Uint64() >= uint64(len(c.blocks)) {
+		return nil, errors.New("not found")
+	}
+	return c.blocks[number.Uint64()], nil
+}
+
+func (c *fakeChain) BlockReceipts(_ context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]*types.Receipt, error) {
+	number, _ := blockNrOrHash.Number()
+	return c.receipts[uint64(number)], nil
+}
+
+func TestSync(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	sender := common.Address(crypto.PubkeyToAddress(key.PublicKey))
+	recipient := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	contract := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+
+	chain := newFakeChain(7777, 0)
+	signer := types.LatestSignerForChainID(chain.chainID)
+	sign := func(nonce uint64, to *common.Address, data []byte) *types.Transaction {
+		tx, err := types.SignNewTx(key, signer, &types.LegacyTx{
+			Nonce: nonce, To: to, Value: big.NewInt(1000), Gas: 21000, GasPrice: big.NewInt(25), Data: data,
+		})
+		require.NoError(err)
+		return tx
+	}
+
+	transferTopic := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	ownerTopic := common.HexToHash("0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0")
+
+	transfer := sign(0, &recipient, nil)
+	chain.addBlock([]*types.Transaction{transfer}, []*types.Receipt{{
+		Status: types.ReceiptStatusSuccessful, GasUsed: 21000,
+		Logs: []*types.Log{{Address: recipient, Topics: []common.Hash{transferTopic}, Data: []byte{1}}},
+	}})
+	chain.addBlock(nil, nil)
+	deploy := sign(1, nil, []byte{0x60, 0x80})
+	failed := sign(2, &recipient, nil)
+	chain.addBlock([]*types.Transaction{deploy, failed}, []*types.Receipt{
+		{Status: types.ReceiptStatusSuccessful, GasUsed: 50000, ContractAddress: contract, Logs: []*types.Log{
+			{Address: contract, Topics: []common.Hash{ownerTopic}},
+			{Address: contract, Topics: []common.Hash{transferTopic}},
+		}},
+		{Status: types.ReceiptStatusFailed, GasUsed: 21000},
+	})
+
+	ix := New(memdb.New())
+	meta, err := ix.Meta()
+	require.NoError(err)
+	require.Nil(meta)
+
+	// maxBlocks bounds a round
+	n, err := ix.Sync(ctx, chain, 2)
+	require.NoError(err)
+	require.Equal(uint64(2), n)
+	n, err = ix.Sync(ctx, chain, 0)
+	require.NoError(err)
+	require.Equal(uint64(2), n)
+	n, err = ix.Sync(ctx, chain, 0)
+	require.NoError(err)
+	require.Zero(n)
+
+	meta, err = ix.Meta()
+	require.NoError(err)
+	require.Equal(uint64(3), meta.Height)
+	require.Equal("7777", meta.ChainID)
+
+	txs, err := ix.Txs(0)
+	require.NoError(err)
+	require.Len(txs, 3)
+	require.Equal(failed.Hash(), txs[0].Hash)
+	require.Equal(deploy.Hash(), txs[1].Hash)
+	require.Equal(transfer.Hash(), txs[2].Hash)
+	require.False(txs[0].Succeeded())
+
+	got, err := ix.Tx(deploy.Hash())
+	require.NoError(err)
+	require.Equal(sender, got.From)
+	require.Nil(got.To)
+	require.Equal(&contract, got.ContractAddress)
+	require.Equal("0x6080", got.Input)
+	require.Equal(uint64(3), got.BlockNumber)
+	require.Equal(uint(0), got.Index)
+	require.Equal("25", got.GasPrice)
+
+	_, err = ix.Tx(common.Hash{1})
+	require.ErrorIs(err, database.ErrNotFound)
+
+	txs, err = ix.AccountTxs(recipient, 1)
+	require.NoError(err)
+	require.Len(txs, 1)
+	require.Equal(failed.Hash(), txs[0].Hash)
+	txs, err = ix.AccountTxs(sender, 0)
+	require.NoError(err)
+	require.Len(txs, 3)
+	txs, err = ix.AccountTxs(contract, 0)
+	require.NoError(err)
+	require.Len(txs, 1)
+	accounts, err := ix.Accounts()
+	require.NoError(err)
+	require.ElementsMatch([]common.Address{sender, recipient, contract}, accounts)
+
+	blocks, err := ix.Blocks(2)
+	require.NoError(err)
+	require.Len(blocks, 2)
+	require.Equal(uint64(3), blocks[0].Number)
+	require.Equal(2, blocks[0].Txs)
+	require.Equal(chain.blocks[2].Hash(), blocks[1].Hash)
+	block, err := ix.Block(0)
+	require.NoError(err)
+	require.Equal(chain.blocks[0].Hash(), block.Hash)
+	_, err = ix.Block(4)
+	require.ErrorIs(err, database.ErrNotFound)
+
+	logs, err := ix.Logs(LogFilter{})
+	require.NoError(err)
+	require.Len(logs, 3)
+	require.Equal(uint(1), logs[0].Index)
+	require.Equal(transferTopic, logs[0].Topics[0])
+	require.Equal(deploy.Hash(), logs[1].TxHash)
+	require.Equal(uint64(1), logs[2].BlockNumber)
+	require.Equal("0x01", logs[2].Data)
+
+	logs, err = ix.Logs(LogFilter{Topic: &transferTopic})
+	require.NoError(err)
+	require.Len(logs, 2)
+	logs, err = ix.Logs(LogFilter{Address: &contract, Topic: &transferTopic})
+	require.NoError(err)
+	require.Len(logs, 1)
+	require.Equal(contract, logs[0].Address)
+	logs, err = ix.Logs(LogFilter{Topic: &transferTopic, ToBlock: 2})
+	require.NoError(err)
+	require.Len(logs, 1)
+	require.Equal(uint64(1), logs[0].BlockNumber)
+	logs, err = ix.Logs(LogFilter{FromBlock: 2, Limit: 1})
+	require.NoError(err)
+	require.Len(logs, 1)
+	require.Equal(uint64(3), logs[0].BlockNumber)
+
+	// a redeployed chain doesn't mix with the index
+	_, err = ix.Sync(ctx, newFakeChain(7777, 1), 0)
+	require.ErrorIs(err, ErrOtherChain)
+}
+
+func TestSyncOldIndex(t *testing.T) {
+	db := memdb.New()
+	require.NoError(t, db.Put(metaKey, []byte(`{"chainID":"7777","height":3}`)))
+	_, err := New(db).Sync(context.Background(), newFakeChain(7777, 0), 0)
+	require.ErrorIs(t, err, ErrOldIndex)
+}
+
+func TestParseHash(t *testing.T) {
+	hash := common.HexToHash("0x1234")
+	got, err := ParseHash(hash.Hex())
+	require.NoError(t, err)
+	require.Equal(t, hash, got)
+	_, err = ParseHash("0x1234")
+	require.Error(t, err)
+}
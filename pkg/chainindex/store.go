@@ -0,0 +1,118 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chainindex
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/luxfi/database"
+	"github.com/luxfi/database/badgerdb"
+)
+
+// Dir is the directory in the CLI base dir holding the indexes, one per
+// network and chain
+const Dir = "index"
+
+// openTimeout bounds how long Open waits for a running indexer to release
+// the database between sync rounds
+const openTimeout = 10 * time.Second
+
+// Path is where the index of chainName on network lives
+func Path(baseDir, network, chainName string) string {
+	return filepath.Join(baseDir, Dir, network, chainName)
+}
+
+// Exists reports whether an index has been built at path
+func Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Open opens the index database at path. The database admits a single
+// process at a time: the indexer only holds it while syncing, so Open keeps
+// retrying while it is busy.
+func Open(ctx context.Context, path string) (database.Database, error) {
+	if err := os.MkdirAll(path, 0o750); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, openTimeout)
+	defer cancel()
+	for {
+		db, err := badgerdb.New(path, nil, "", nil)
+		if err == nil {
+			return db, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to open index %s: %w", path, err)
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+// Reset removes the index at path
+func Reset(path string) error {
+	return os.RemoveAll(path)
+}
+
+// Run keeps the index at path in sync with client until ctx is done,
+// checking for new blocks every interval. The database is held only while
+// syncing so queries can run in between. progress is called after every
+// round that indexed blocks.
+func Run(ctx context.Context, path string, client Client, interval time.Duration, progress func(*Meta, uint64)) error {
+	for {
+		if err := syncOnce(ctx, path, client, progress); err != nil && ctx.Err() == nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// syncBatch bounds the blocks indexed per round, so a long catch-up still
+// releases the database regularly
+const syncBatch = 1000
+
+func syncOnce(ctx context.Context, path string, client Client, progress func(*Meta, uint64)) error {
+	db, err := Open(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	ix := New(db)
+	for {
+		n, err := ix.Sync(ctx, client, syncBatch)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return nil
+		}
+		meta, err := ix.Meta()
+		if err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(meta, n)
+		}
+		if n < syncBatch {
+			return nil
+		}
+		// release the database between batches
+		if err := db.Close(); err != nil {
+			return err
+		}
+		if db, err = Open(ctx, path); err != nil {
+			return err
+		}
+		ix = New(db)
+	}
+}
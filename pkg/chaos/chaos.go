@@ -0,0 +1,145 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package chaos injects faults into the running local network, killing
+// nodes, degrading the links between them with tc/netem and partitioning
+// them, and measures the impact on liveness from the chain heights probed
+// before, during and after the fault.
+package chaos
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// Pick returns count nodes chosen at random with rng, sorted by name. All
+// nodes are returned when count is not lower than their number.
+func Pick(nodes []string, count int, rng *rand.Rand) []string {
+	picked := append([]string(nil), nodes...)
+	if count < len(picked) {
+		rng.Shuffle(len(picked), func(i, j int) { picked[i], picked[j] = picked[j], picked[i] })
+		picked = picked[:max(count, 0)]
+	}
+	sort.Strings(picked)
+	return picked
+}
+
+// Minority returns the largest number of nodes that can be cut off from n
+// nodes while the others keep a majority
+func Minority(n int) int {
+	return max((n-1)/2, 1)
+}
+
+// Sample is one probe of the chain heights. Chains that could not be
+// reached are missing from Heights.
+type Sample struct {
+	Time    time.Time
+	Heights map[string]uint64
+}
+
+// ChainLiveness is the impact of a fault on one chain
+type ChainLiveness struct {
+	Chain  string
+	Before uint64
+	After  uint64
+	// DuringFault is the number of blocks produced while the fault was active
+	DuringFault uint64
+	// LongestStall is the longest time the chain went without a new block
+	LongestStall time.Duration
+	// Recovery is how long after the end of the fault the chain produced its
+	// first block, when Recovered
+	Recovery  time.Duration
+	Recovered bool
+	// Unreachable is the number of probes in which the chain did not answer
+	Unreachable int
+}
+
+// Verdict summarizes the liveness of the chain during the fault
+func (c ChainLiveness) Verdict() string {
+	switch {
+	case c.DuringFault > 0:
+		return "live"
+	case c.Recovered:
+		return fmt.Sprintf("halted, recovered %s after the fault", c.Recovery.Round(time.Second))
+	default:
+		return "halted, not recovered"
+	}
+}
+
+// Report is the liveness impact of a fault
+type Report struct {
+	Fault  string
+	Nodes  []string
+	Start  time.Time
+	End    time.Time
+	Chains []ChainLiveness
+}
+
+// BuildReport measures the liveness of each chain probed in samples, taken
+// in order, for a fault active from start to end. The first sample should
+// be taken before start, and samples should continue after end to observe
+// the recovery.
+func BuildReport(fault string, nodes []string, start, end time.Time, samples []Sample) Report {
+	report := Report{Fault: fault, Nodes: nodes, Start: start, End: end}
+	chains := map[string]bool{}
+	for _, s := range samples {
+		for chain := range s.Heights {
+			chains[chain] = true
+		}
+	}
+	names := make([]string, 0, len(chains))
+	for chain := range chains {
+		names = append(names, chain)
+	}
+	sort.Strings(names)
+	for _, chain := range names {
+		report.Chains = append(report.Chains, chainLiveness(chain, start, end, samples))
+	}
+	return report
+}
+
+func chainLiveness(chain string, start, end time.Time, samples []Sample) ChainLiveness {
+	c := ChainLiveness{Chain: chain}
+	var (
+		height      uint64
+		seen        bool
+		lastAdvance time.Time
+		atFaultEnd  uint64
+	)
+	for i, s := range samples {
+		if i == 0 {
+			lastAdvance = s.Time
+		}
+		if stall := s.Time.Sub(lastAdvance); stall > c.LongestStall {
+			c.LongestStall = stall
+		}
+		h, ok := s.Heights[chain]
+		switch {
+		case !ok:
+			c.Unreachable++
+		case !seen:
+			height, seen = h, true
+			lastAdvance = s.Time
+		case h > height:
+			if !s.Time.Before(start) && !s.Time.After(end) {
+				c.DuringFault += h - height
+			}
+			if s.Time.After(end) && !c.Recovered && h > atFaultEnd {
+				c.Recovered = true
+				c.Recovery = s.Time.Sub(end)
+			}
+			height = h
+			lastAdvance = s.Time
+		}
+		if !s.Time.After(start) {
+			c.Before = height
+		}
+		if !s.Time.After(end) {
+			atFaultEnd = height
+		}
+	}
+	c.After = height
+	return c
+}
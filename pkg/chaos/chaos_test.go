@@ -0,0 +1,85 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaos
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPick(t *testing.T) {
+	require := require.New(t)
+	nodes := []string{"node5", "node1", "node3", "node2", "node4"}
+
+	picked := Pick(nodes, 2, rand.New(rand.NewSource(1))) //nolint:gosec // G404: test
+	require.Len(picked, 2)
+	require.IsIncreasing(picked)
+	require.Equal(picked, Pick(nodes, 2, rand.New(rand.NewSource(1)))) //nolint:gosec // G404: test
+
+	require.Equal([]string{"node1", "node2", "node3", "node4", "node5"}, Pick(nodes, 10, rand.New(rand.NewSource(1)))) //nolint:gosec // G404: test
+	require.Equal("node5", nodes[0])
+}
+
+func TestMinority(t *testing.T) {
+	require := require.New(t)
+	require.Equal(1, Minority(2))
+	require.Equal(1, Minority(3))
+	require.Equal(2, Minority(5))
+}
+
+func TestBuildReport(t *testing.T) {
+	require := require.New(t)
+	t0 := time.Unix(1_700_000_000, 0)
+	at := func(s int) time.Time { return t0.Add(time.Duration(s) * time.Second) }
+	samples := []Sample{
+		{Time: at(0), Heights: map[string]uint64{"local/c": 10, "local/p": 5}},
+		{Time: at(5), Heights: map[string]uint64{"local/c": 12, "local/p": 5}},
+		{Time: at(10), Heights: map[string]uint64{"local/c": 12}},
+		{Time: at(15), Heights: map[string]uint64{"local/c": 12, "local/p": 5}},
+		{Time: at(20), Heights: map[string]uint64{"local/c": 12, "local/p": 5}},
+		{Time: at(25), Heights: map[string]uint64{"local/c": 15, "local/p": 5}},
+	}
+	report := BuildReport("partition", []string{"node1"}, at(2), at(17), samples)
+	require.Len(report.Chains, 2)
+
+	c := report.Chains[0]
+	require.Equal("local/c", c.Chain)
+	require.Equal(uint64(10), c.Before)
+	require.Equal(uint64(15), c.After)
+	require.Equal(uint64(2), c.DuringFault)
+	require.Equal(20*time.Second, c.LongestStall)
+	require.True(c.Recovered)
+	require.Equal(8*time.Second, c.Recovery)
+	require.Equal("live", c.Verdict())
+
+	p := report.Chains[1]
+	require.Equal("local/p", p.Chain)
+	require.Zero(p.DuringFault)
+	require.False(p.Recovered)
+	require.Equal(1, p.Unreachable)
+	require.Equal(25*time.Second, p.LongestStall)
+	require.Equal("halted, not recovered", p.Verdict())
+}
+
+func TestNetem(t *testing.T) {
+	require := require.New(t)
+	n := Netem{Delay: 200 * time.Millisecond, Jitter: 50 * time.Millisecond, Loss: 2.5}
+	require.NoError(n.Validate())
+	require.Equal([]string{"netem", "delay", "200ms", "50ms", "loss", "2.5%"}, n.Args())
+	require.Equal([]string{"netem", "loss", "100%"}, Netem{Loss: 100}.Args())
+
+	require.Error(Netem{}.Validate())
+	require.Error(Netem{Jitter: time.Millisecond}.Validate())
+	require.Error(Netem{Loss: 101}.Validate())
+
+	cmds := ShapeCommands(LoopbackDevice, []int{9651}, Netem{Loss: 100})
+	require.Len(cmds, 4)
+	require.Equal([]string{"qdisc", "add", "dev", "lo", "parent", "1:4", "handle", "40:", "netem", "loss", "100%"}, cmds[1])
+	require.Contains(cmds[2], "sport")
+	require.Contains(cmds[3], "dport")
+	require.Equal([]string{"qdisc", "del", "dev", "lo", "root"}, ClearCommand(LoopbackDevice))
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaos
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// LoopbackDevice carries the traffic between the nodes of the local network
+const LoopbackDevice = "lo"
+
+// Netem is the link degradation applied by netem
+type Netem struct {
+	Delay  time.Duration
+	Jitter time.Duration
+	// Loss is the percentage of packets dropped, 100 cuts the link
+	Loss float64
+}
+
+// Validate checks that n degrades the link and is accepted by netem
+func (n Netem) Validate() error {
+	switch {
+	case n.Delay < 0 || n.Jitter < 0:
+		return errors.New("delay and jitter cannot be negative")
+	case n.Jitter > 0 && n.Delay == 0:
+		return errors.New("jitter needs a delay")
+	case n.Loss < 0 || n.Loss > 100:
+		return errors.New("loss must be a percentage between 0 and 100")
+	case n.Delay == 0 && n.Loss == 0:
+		return errors.New("set a delay or a loss")
+	}
+	return nil
+}
+
+// Args returns the netem parameters of n
+func (n Netem) Args() []string {
+	args := []string{"netem"}
+	if n.Delay > 0 {
+		args = append(args, "delay", strconv.FormatInt(n.Delay.Milliseconds(), 10)+"ms")
+		if n.Jitter > 0 {
+			args = append(args, strconv.FormatInt(n.Jitter.Milliseconds(), 10)+"ms")
+		}
+	}
+	if n.Loss > 0 {
+		args = append(args, "loss", strconv.FormatFloat(n.Loss, 'f', -1, 64)+"%")
+	}
+	return args
+}
+
+// ShapeCommands returns the tc invocations applying n to the traffic from
+// and to ports on dev. The packets of these ports go to a netem band of a
+// prio qdisc, all other traffic, such as the API calls of the CLI, is left
+// untouched.
+func ShapeCommands(dev string, ports []int, n Netem) [][]string {
+	cmds := [][]string{
+		{"qdisc", "add", "dev", dev, "root", "handle", "1:", "prio", "bands", "4"},
+		append([]string{"qdisc", "add", "dev", dev, "parent", "1:4", "handle", "40:"}, n.Args()...),
+	}
+	for _, port := range ports {
+		for _, dir := range []string{"sport", "dport"} {
+			cmds = append(cmds, []string{
+				"filter", "add", "dev", dev, "parent", "1:0", "protocol", "ip", "prio", "1",
+				"u32", "match", "ip", dir, strconv.Itoa(port), "0xffff", "flowid", "1:4",
+			})
+		}
+	}
+	return cmds
+}
+
+// ClearCommand returns the tc invocation removing the shaping of dev
+func ClearCommand(dev string) []string {
+	return []string{"qdisc", "del", "dev", dev, "root"}
+}
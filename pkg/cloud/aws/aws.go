@@ -41,25 +41,8 @@ type AwsCloud struct {
 
 // NewAwsCloud creates an AWS cloud
 func NewAwsCloud(awsProfile, region string) (*AwsCloud, error) {
-	var (
-		cfg aws.Config
-		err error
-	)
 	ctx := context.Background()
-	if os.Getenv("AWS_ACCESS_KEY_ID") != "" {
-		// Load session from env variables
-		cfg, err = config.LoadDefaultConfig(
-			ctx,
-			config.WithRegion(region),
-		)
-	} else {
-		// Load session from profile in config file
-		cfg, err = config.LoadDefaultConfig(
-			ctx,
-			config.WithRegion(region),
-			config.WithSharedConfigProfile(awsProfile),
-		)
-	}
+	cfg, err := loadConfig(ctx, awsProfile, region)
 	if err != nil {
 		return nil, err
 	}
@@ -69,6 +52,24 @@ func NewAwsCloud(awsProfile, region string) (*AwsCloud, error) {
 	}, nil
 }
 
+// loadConfig loads the credentials from the env variables when set, from
+// awsProfile otherwise
+func loadConfig(ctx context.Context, awsProfile, region string) (aws.Config, error) {
+	if os.Getenv("AWS_ACCESS_KEY_ID") != "" {
+		// Load session from env variables
+		return config.LoadDefaultConfig(
+			ctx,
+			config.WithRegion(region),
+		)
+	}
+	// Load session from profile in config file
+	return config.LoadDefaultConfig(
+		ctx,
+		config.WithRegion(region),
+		config.WithSharedConfigProfile(awsProfile),
+	)
+}
+
 // CreateSecurityGroup creates a security group
 func (c *AwsCloud) CreateSecurityGroup(groupName, description string) (string, error) {
 	createSGOutput, err := c.ec2Client.CreateSecurityGroup(c.ctx, &ec2.CreateSecurityGroupInput{
@@ -99,6 +100,34 @@ func (c *AwsCloud) CheckSecurityGroupExists(sgName string) (bool, types.Security
 	return true, sg.SecurityGroups[0], nil
 }
 
+// IngressRule is a tcp ingress rule of a single port
+type IngressRule struct {
+	CIDR string
+	Port int32
+}
+
+// GetSecurityGroupIngress returns the ID of the given security group and its
+// IPv4 tcp ingress rules of single ports
+func (c *AwsCloud) GetSecurityGroupIngress(sgName string) (string, []IngressRule, error) {
+	exists, sg, err := c.CheckSecurityGroupExists(sgName)
+	if err != nil {
+		return "", nil, err
+	}
+	if !exists {
+		return "", nil, fmt.Errorf("security group %s not found", sgName)
+	}
+	var rules []IngressRule
+	for _, perm := range sg.IpPermissions {
+		if aws.ToString(perm.IpProtocol) != "tcp" || perm.FromPort == nil || perm.ToPort == nil || *perm.FromPort != *perm.ToPort {
+			continue
+		}
+		for _, ipRange := range perm.IpRanges {
+			rules = append(rules, IngressRule{CIDR: aws.ToString(ipRange.CidrIp), Port: *perm.FromPort})
+		}
+	}
+	return aws.ToString(sg.GroupId), rules, nil
+}
+
 // AddSecurityGroupRule adds a rule to the given security group
 func (c *AwsCloud) AddSecurityGroupRule(groupID, direction, protocol, ip string, port int32) error {
 	if !strings.Contains(ip, "/") {
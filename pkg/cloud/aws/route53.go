@@ -0,0 +1,156 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aws
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+const (
+	route53Endpoint = "https://route53.amazonaws.com/2013-04-01"
+	// route53 is a global service signed in us-east-1
+	route53Region = "us-east-1"
+	route53NS     = "https://route53.amazonaws.com/doc/2013-04-01/"
+)
+
+// Route53DNS manages the A records of a Route53 hosted zone
+type Route53DNS struct {
+	cfg      aws.Config
+	client   *http.Client
+	endpoint string
+	zoneID   string
+}
+
+// NewRoute53DNS returns the Route53 records manager of the hosted zone of
+// domain. zoneID is looked up when empty.
+func NewRoute53DNS(ctx context.Context, awsProfile, domain, zoneID string) (*Route53DNS, error) {
+	cfg, err := loadConfig(ctx, awsProfile, route53Region)
+	if err != nil {
+		return nil, err
+	}
+	return newRoute53DNS(ctx, cfg, route53Endpoint, domain, zoneID)
+}
+
+func newRoute53DNS(ctx context.Context, cfg aws.Config, endpoint, domain, zoneID string) (*Route53DNS, error) {
+	r := &Route53DNS{
+		cfg:      cfg,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		endpoint: endpoint,
+		zoneID:   strings.TrimPrefix(zoneID, "/hostedzone/"),
+	}
+	if r.zoneID != "" {
+		return r, nil
+	}
+	var err error
+	r.zoneID, err = r.findZone(ctx, domain)
+	return r, err
+}
+
+// findZone returns the ID of the hosted zone of domain
+func (r *Route53DNS) findZone(ctx context.Context, domain string) (string, error) {
+	name := strings.TrimSuffix(domain, ".") + "."
+	query := url.Values{"dnsname": {name}, "maxitems": {"1"}}
+	var resp struct {
+		HostedZones []struct {
+			ID   string `xml:"Id"`
+			Name string `xml:"Name"`
+		} `xml:"HostedZones>HostedZone"`
+	}
+	if err := r.do(ctx, http.MethodGet, "/hostedzonesbyname?"+query.Encode(), nil, &resp); err != nil {
+		return "", err
+	}
+	if len(resp.HostedZones) == 0 || resp.HostedZones[0].Name != name {
+		return "", fmt.Errorf("no Route53 hosted zone for %s", domain)
+	}
+	return strings.TrimPrefix(resp.HostedZones[0].ID, "/hostedzone/"), nil
+}
+
+// UpsertA points fqdn at ips, replacing the records it had
+func (r *Route53DNS) UpsertA(ctx context.Context, fqdn string, ips []string, ttl int64) error {
+	type resourceRecord struct {
+		Value string `xml:"Value"`
+	}
+	type change struct {
+		Action          string           `xml:"Action"`
+		Name            string           `xml:"ResourceRecordSet>Name"`
+		Type            string           `xml:"ResourceRecordSet>Type"`
+		TTL             int64            `xml:"ResourceRecordSet>TTL"`
+		ResourceRecords []resourceRecord `xml:"ResourceRecordSet>ResourceRecords>ResourceRecord"`
+	}
+	req := struct {
+		XMLName xml.Name `xml:"ChangeResourceRecordSetsRequest"`
+		XMLNS   string   `xml:"xmlns,attr"`
+		Comment string   `xml:"ChangeBatch>Comment"`
+		Changes []change `xml:"ChangeBatch>Changes>Change"`
+	}{
+		XMLNS:   route53NS,
+		Comment: "lux cluster RPC endpoint",
+	}
+	c := change{Action: "UPSERT", Name: fqdn, Type: "A", TTL: ttl}
+	for _, ip := range ips {
+		c.ResourceRecords = append(c.ResourceRecords, resourceRecord{Value: ip})
+	}
+	req.Changes = []change{c}
+	body, err := xml.Marshal(req)
+	if err != nil {
+		return err
+	}
+	return r.do(ctx, http.MethodPost, "/hostedzone/"+r.zoneID+"/rrset", append([]byte(xml.Header), body...), nil)
+}
+
+// do sends a signed request to the Route53 API, decoding the XML response
+// into out when not nil
+func (r *Route53DNS) do(ctx context.Context, method, path string, body []byte, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, r.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/xml")
+	}
+	creds, err := r.cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get AWS credentials: %w", err)
+	}
+	hash := sha256.Sum256(body)
+	if err := v4.NewSigner().SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "route53", route53Region, time.Now()); err != nil {
+		return err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		var apiErr struct {
+			Code    string `xml:"Error>Code"`
+			Message string `xml:"Error>Message"`
+		}
+		if xml.Unmarshal(data, &apiErr) == nil && apiErr.Code != "" {
+			return fmt.Errorf("route53: %s: %s", apiErr.Code, apiErr.Message)
+		}
+		return fmt.Errorf("route53: %s", resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return xml.Unmarshal(data, out)
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aws
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoute53UpsertA(t *testing.T) {
+	require := require.New(t)
+	var changes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Contains(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/")
+		switch {
+		case r.URL.Path == "/hostedzonesbyname":
+			if r.URL.Query().Get("dnsname") != "example.com." {
+				_, _ = io.WriteString(w, `<ListHostedZonesByNameResponse><HostedZones/></ListHostedZonesByNameResponse>`)
+				return
+			}
+			_, _ = io.WriteString(w, `<ListHostedZonesByNameResponse><HostedZones><HostedZone><Id>/hostedzone/Z123</Id><Name>example.com.</Name></HostedZone></HostedZones></ListHostedZonesByNameResponse>`)
+		case r.URL.Path == "/hostedzone/Z123/rrset":
+			body, err := io.ReadAll(r.Body)
+			require.NoError(err)
+			changes = append(changes, string(body))
+			_, _ = io.WriteString(w, `<ChangeResourceRecordSetsResponse/>`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `<ErrorResponse><Error><Code>NoSuchHostedZone</Code><Message>No hosted zone found</Message></Error></ErrorResponse>`)
+		}
+	}))
+	defer server.Close()
+
+	cfg := aws.Config{Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil
+	})}
+	r, err := newRoute53DNS(context.Background(), cfg, server.URL, "example.com", "")
+	require.NoError(err)
+	require.NoError(r.UpsertA(context.Background(), "rpc.devnet.example.com", []string{"1.1.1.1"}, 300))
+	require.Len(changes, 1)
+	require.True(strings.HasPrefix(changes[0], "<?xml"))
+	require.Contains(changes[0], "<Action>UPSERT</Action><ResourceRecordSet><Name>rpc.devnet.example.com</Name><Type>A</Type><TTL>300</TTL><ResourceRecords><ResourceRecord><Value>1.1.1.1</Value>")
+
+	_, err = newRoute53DNS(context.Background(), cfg, server.URL, "other.com", "")
+	require.ErrorContains(err, "no Route53 hosted zone for other.com")
+
+	r.zoneID = "missing"
+	err = r.UpsertA(context.Background(), "rpc.devnet.example.com", []string{"1.1.1.1"}, 300)
+	require.ErrorContains(err, "NoSuchHostedZone: No hosted zone found")
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	dns "google.golang.org/api/dns/v1"
+	"google.golang.org/api/option"
+)
+
+// CloudDNS manages the A records of a Cloud DNS managed zone
+type CloudDNS struct {
+	service   *dns.Service
+	projectID string
+	zone      string
+}
+
+// NewCloudDNS returns the Cloud DNS records manager of the managed zone of
+// domain in projectID. The default credentials are used when
+// credentialsFile is empty.
+func NewCloudDNS(ctx context.Context, projectID, credentialsFile, domain string) (*CloudDNS, error) {
+	var opts []option.ClientOption
+	if credentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(credentialsFile))
+	}
+	service, err := dns.NewService(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSuffix(domain, ".") + "."
+	zones, err := service.ManagedZones.List(projectID).DnsName(name).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	if len(zones.ManagedZones) == 0 {
+		return nil, fmt.Errorf("no Cloud DNS managed zone for %s in project %s", domain, projectID)
+	}
+	return &CloudDNS{
+		service:   service,
+		projectID: projectID,
+		zone:      zones.ManagedZones[0].Name,
+	}, nil
+}
+
+// UpsertA points fqdn at ips, replacing the records it had
+func (c *CloudDNS) UpsertA(ctx context.Context, fqdn string, ips []string, ttl int64) error {
+	name := strings.TrimSuffix(fqdn, ".") + "."
+	rrset := &dns.ResourceRecordSet{
+		Name:    name,
+		Type:    "A",
+		Ttl:     ttl,
+		Rrdatas: ips,
+	}
+	existing, err := c.service.ResourceRecordSets.List(c.projectID, c.zone).Name(name).Type("A").Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	if len(existing.Rrsets) > 0 {
+		_, err = c.service.ResourceRecordSets.Patch(c.projectID, c.zone, name, "A", rrset).Context(ctx).Do()
+		return err
+	}
+	_, err = c.service.ResourceRecordSets.Create(c.projectID, c.zone, rrset).Context(ctx).Do()
+	return err
+}
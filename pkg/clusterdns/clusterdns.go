@@ -0,0 +1,239 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package clusterdns gives the API nodes of a cluster stable https URLs:
+// each node gets a DNS record under the cluster domain, e.g.
+// rpc.<cluster>.example.com, and a reverse proxy on the node terminates TLS
+// with a certificate obtained over ACME, forwarding to the local luxd API.
+package clusterdns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultTTL is the TTL of the records, short so replacing a node is
+	// picked up quickly
+	DefaultTTL = 300
+	// ProxyImage is the reverse proxy run on the API nodes. Caddy obtains and
+	// renews the certificates on its own.
+	ProxyImage = "caddy:2"
+
+	yamlKey = "rpc"
+)
+
+var (
+	ErrNoAPINodes = errors.New("cluster has no API nodes to expose")
+
+	invalidLabelChars = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+// Provider manages the records of a DNS zone
+type Provider interface {
+	// UpsertA points fqdn at ips, replacing the records it had
+	UpsertA(ctx context.Context, fqdn string, ips []string, ttl int64) error
+}
+
+// Proxy is the TLS terminating reverse proxy of a node
+type Proxy interface {
+	// Install runs the proxy with config, replacing a running one
+	Install(ctx context.Context, config []byte) error
+}
+
+// Node is an API node of the cluster
+type Node struct {
+	CloudID string
+	IP      string
+}
+
+// Endpoint is where a node is reachable over https
+type Endpoint struct {
+	Host    string `yaml:"host"`
+	CloudID string `yaml:"node"`
+	IP      string `yaml:"ip"`
+}
+
+// URL returns the https URL of the endpoint
+func (e Endpoint) URL() string {
+	return "https://" + e.Host
+}
+
+// Options describe the endpoints to set up
+type Options struct {
+	Cluster string
+	// Domain is the zone the records are created in, e.g. example.com
+	Domain string
+	// Email is the ACME account contact, notified about expiring certificates
+	Email string
+	// UpstreamPort is the luxd API port the proxy forwards to
+	UpstreamPort int
+	TTL          int64
+	// Progress, when set, reports each step
+	Progress func(node, step string)
+}
+
+// Endpoints names the endpoints of nodes: the first one serves the stable
+// rpc.<cluster>.<domain> and the others rpc-<n>.<cluster>.<domain>, so each
+// node can obtain its own certificate
+func Endpoints(cluster, domain string, nodes []Node) ([]Endpoint, error) {
+	if len(nodes) == 0 {
+		return nil, ErrNoAPINodes
+	}
+	label := Label(cluster)
+	if label == "" {
+		return nil, fmt.Errorf("cluster name %q has no characters valid in a DNS name", cluster)
+	}
+	domain = strings.Trim(strings.ToLower(domain), ".")
+	if domain == "" || !strings.Contains(domain, ".") {
+		return nil, fmt.Errorf("invalid domain %q", domain)
+	}
+	endpoints := make([]Endpoint, len(nodes))
+	for i, node := range nodes {
+		prefix := "rpc"
+		if i > 0 {
+			prefix = fmt.Sprintf("rpc-%d", i+1)
+		}
+		endpoints[i] = Endpoint{
+			Host:    fmt.Sprintf("%s.%s.%s", prefix, label, domain),
+			CloudID: node.CloudID,
+			IP:      node.IP,
+		}
+	}
+	return endpoints, nil
+}
+
+// Label turns name into a DNS label
+func Label(name string) string {
+	label := invalidLabelChars.ReplaceAllString(strings.ToLower(name), "-")
+	label = strings.Trim(label, "-")
+	if len(label) > 63 {
+		label = strings.TrimRight(label[:63], "-")
+	}
+	return label
+}
+
+var caddyfileTemplate = template.Must(template.New("Caddyfile").Parse(`{
+{{- if .Email }}
+	email {{ .Email }}
+{{- end }}
+}
+
+{{ .Host }} {
+	encode gzip
+	reverse_proxy 127.0.0.1:{{ .Port }}
+}
+`))
+
+// Caddyfile returns the proxy config serving host over https in front of the
+// luxd API on port
+func Caddyfile(host, email string, port int) ([]byte, error) {
+	var b strings.Builder
+	err := caddyfileTemplate.Execute(&b, struct {
+		Host  string
+		Email string
+		Port  int
+	}{host, email, port})
+	return []byte(b.String()), err
+}
+
+// Setup creates the records of the endpoints and installs their proxies. It
+// stops at the first failing node, returning the endpoints set up so far.
+func Setup(ctx context.Context, opts Options, nodes []Node, dns Provider, proxies map[string]Proxy) ([]Endpoint, error) {
+	endpoints, err := Endpoints(opts.Cluster, opts.Domain, nodes)
+	if err != nil {
+		return nil, err
+	}
+	progress := opts.Progress
+	if progress == nil {
+		progress = func(string, string) {}
+	}
+	ttl := opts.TTL
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+	done := make([]Endpoint, 0, len(endpoints))
+	for _, e := range endpoints {
+		proxy, ok := proxies[e.CloudID]
+		if !ok {
+			return done, fmt.Errorf("%s: no proxy for the node", e.CloudID)
+		}
+		progress(e.CloudID, fmt.Sprintf("pointing %s at %s", e.Host, e.IP))
+		if err := dns.UpsertA(ctx, e.Host, []string{e.IP}, ttl); err != nil {
+			return done, fmt.Errorf("%s: failed to create DNS record %s: %w", e.CloudID, e.Host, err)
+		}
+		config, err := Caddyfile(e.Host, opts.Email, opts.UpstreamPort)
+		if err != nil {
+			return done, err
+		}
+		progress(e.CloudID, "starting TLS proxy")
+		if err := proxy.Install(ctx, config); err != nil {
+			return done, fmt.Errorf("%s: failed to install TLS proxy: %w", e.CloudID, err)
+		}
+		done = append(done, e)
+	}
+	return done, nil
+}
+
+// ClusterRPC is what the cluster YAML records about the endpoints
+type ClusterRPC struct {
+	// URL is the stable https URL of the cluster
+	URL       string     `yaml:"url"`
+	Domain    string     `yaml:"domain"`
+	Provider  string     `yaml:"dnsProvider"`
+	Endpoints []Endpoint `yaml:"endpoints"`
+}
+
+// Record writes rpc to the cluster YAML at path, keeping the rest of it
+func Record(path string, rpc ClusterRPC) error {
+	doc := map[string]any{}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("invalid cluster YAML %s: %w", path, err)
+		}
+		if doc == nil {
+			doc = map[string]any{}
+		}
+	}
+	doc[yamlKey] = rpc
+	data, err = yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Load reads the endpoints recorded in the cluster YAML at path, returning
+// nil when there are none
+func Load(path string) (*ClusterRPC, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		RPC *ClusterRPC `yaml:"rpc"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid cluster YAML %s: %w", path, err)
+	}
+	return doc.RPC, nil
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package clusterdns
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDNS struct {
+	records map[string][]string
+	err     error
+}
+
+func (d *fakeDNS) UpsertA(_ context.Context, fqdn string, ips []string, _ int64) error {
+	if d.err != nil {
+		return d.err
+	}
+	d.records[fqdn] = ips
+	return nil
+}
+
+type fakeProxy struct {
+	config []byte
+}
+
+func (p *fakeProxy) Install(_ context.Context, config []byte) error {
+	p.config = config
+	return nil
+}
+
+func TestEndpoints(t *testing.T) {
+	require := require.New(t)
+	nodes := []Node{{CloudID: "i-1", IP: "1.1.1.1"}, {CloudID: "i-2", IP: "2.2.2.2"}}
+
+	endpoints, err := Endpoints("My_Devnet", "Example.com.", nodes)
+	require.NoError(err)
+	require.Equal([]Endpoint{
+		{Host: "rpc.my-devnet.example.com", CloudID: "i-1", IP: "1.1.1.1"},
+		{Host: "rpc-2.my-devnet.example.com", CloudID: "i-2", IP: "2.2.2.2"},
+	}, endpoints)
+	require.Equal("https://rpc.my-devnet.example.com", endpoints[0].URL())
+
+	_, err = Endpoints("devnet", "example.com", nil)
+	require.ErrorIs(err, ErrNoAPINodes)
+	_, err = Endpoints("___", "example.com", nodes)
+	require.ErrorContains(err, "no characters valid")
+	_, err = Endpoints("devnet", "localhost", nodes)
+	require.ErrorContains(err, "invalid domain")
+}
+
+func TestSetup(t *testing.T) {
+	require := require.New(t)
+	nodes := []Node{{CloudID: "i-1", IP: "1.1.1.1"}, {CloudID: "i-2", IP: "2.2.2.2"}}
+	proxies := map[string]Proxy{"i-1": &fakeProxy{}, "i-2": &fakeProxy{}}
+	dns := &fakeDNS{records: map[string][]string{}}
+	opts := Options{Cluster: "devnet", Domain: "example.com", Email: "ops@example.com", UpstreamPort: 9630}
+
+	endpoints, err := Setup(context.Background(), opts, nodes, dns, proxies)
+	require.NoError(err)
+	require.Len(endpoints, 2)
+	require.Equal([]string{"2.2.2.2"}, dns.records["rpc-2.devnet.example.com"])
+	config := string(proxies["i-1"].(*fakeProxy).config)
+	require.Contains(config, "email ops@example.com")
+	require.Contains(config, "rpc.devnet.example.com {")
+	require.Contains(config, "reverse_proxy 127.0.0.1:9630")
+
+	dns.err = errors.New("zone not found")
+	endpoints, err = Setup(context.Background(), opts, nodes, dns, proxies)
+	require.ErrorContains(err, "i-1: failed to create DNS record rpc.devnet.example.com: zone not found")
+	require.Empty(endpoints)
+}
+
+func TestRecord(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), "cluster.yaml")
+
+	rpc, err := Load(path)
+	require.NoError(err)
+	require.Nil(rpc)
+
+	require.NoError(os.WriteFile(path, []byte("network: devnet\nnodes:\n  - i-1\n"), 0o600))
+	want := ClusterRPC{
+		URL:       "https://rpc.devnet.example.com",
+		Domain:    "example.com",
+		Provider:  "route53",
+		Endpoints: []Endpoint{{Host: "rpc.devnet.example.com", CloudID: "i-1", IP: "1.1.1.1"}},
+	}
+	require.NoError(Record(path, want))
+
+	rpc, err = Load(path)
+	require.NoError(err)
+	require.Equal(&want, rpc)
+	data, err := os.ReadFile(path)
+	require.NoError(err)
+	require.Contains(string(data), "network: devnet")
+}
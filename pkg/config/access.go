@@ -0,0 +1,162 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// ReadOnlyKey is the config setting putting the whole CLI in read-only
+	// mode, for shared operator machines and dashboards
+	ReadOnlyKey = "read-only"
+
+	// CapabilityAnnotation sets the capability a command needs, overriding
+	// the one inferred from its name. CapabilityNone marks commands that
+	// never change anything.
+	CapabilityAnnotation = "lux/capability"
+	CapabilityNone       = "none"
+)
+
+// Capability is a kind of state-changing command a profile may be granted
+type Capability string
+
+const (
+	// CapabilityDeploy covers creating, deploying, starting, applying and
+	// upgrading networks and chains, and anything else not covered below
+	CapabilityDeploy Capability = "deploy"
+	// CapabilityDestroy covers stopping, cleaning, deleting and restoring
+	CapabilityDestroy Capability = "destroy"
+	// CapabilityValidators covers validator set changes
+	CapabilityValidators Capability = "validators"
+	// CapabilityTransfer covers moving funds
+	CapabilityTransfer Capability = "transfer"
+	// CapabilityKeys covers creating, importing, exporting and deleting keys
+	CapabilityKeys Capability = "keys"
+	// CapabilityConfig covers changing the CLI configuration
+	CapabilityConfig Capability = "config"
+)
+
+// Capabilities are all capabilities
+var Capabilities = []Capability{
+	CapabilityDeploy, CapabilityDestroy, CapabilityValidators,
+	CapabilityTransfer, CapabilityKeys, CapabilityConfig,
+}
+
+// ErrNotAllowed is returned for commands the config doesn't allow
+var ErrNotAllowed = errors.New("not allowed")
+
+var (
+	// readVerbs name commands that only read
+	readVerbs = []string{
+		"algorithms", "audit", "balance", "completion", "describe", "explore",
+		"getbalance", "help", "history", "info", "inspect", "lint", "list",
+		"logs", "ls", "markets", "pnl", "pools", "portal", "positions", "print",
+		"protocols", "quote", "schemes", "show", "status", "tokens",
+		"upgrade-status", "verify", "version",
+	}
+	destroyVerbs = []string{
+		"clean", "delete", "destroy", "down", "remove", "restore", "rm",
+		"rollback", "stop",
+	}
+	transferVerbs = []string{
+		"approve", "bridge", "deposit", "distribute", "increase-balance",
+		"increasebalance", "send", "swap", "transfer", "withdraw",
+	}
+	keyGroups = []string{"key", "keys", "kms", "mpc"}
+)
+
+// ParseCapabilities parses capability names
+func ParseCapabilities(names []string) ([]Capability, error) {
+	caps := make([]Capability, 0, len(names))
+	for _, name := range names {
+		c := Capability(strings.ToLower(strings.TrimSpace(name)))
+		if !slices.Contains(Capabilities, c) {
+			return nil, fmt.Errorf("invalid capability %q: must be one of %s", name, joinCapabilities(Capabilities))
+		}
+		if !slices.Contains(caps, c) {
+			caps = append(caps, c)
+		}
+	}
+	return caps, nil
+}
+
+// RequiredCapability returns the capability the command at path, the
+// command names below the root, needs. Commands that only read need none.
+func RequiredCapability(path []string) (Capability, bool) {
+	if len(path) == 0 {
+		return "", false
+	}
+	leaf := strings.ToLower(path[len(path)-1])
+	group := strings.ToLower(path[0])
+	switch {
+	case slices.Contains(readVerbs, leaf) || len(path) == 1 && group == "config":
+		return "", false
+	case group == "config":
+		return CapabilityConfig, true
+	case slices.Contains(keyGroups, group):
+		return CapabilityKeys, true
+	case slices.ContainsFunc(path, func(name string) bool {
+		return strings.Contains(strings.ToLower(name), "validator")
+	}):
+		return CapabilityValidators, true
+	case slices.Contains(destroyVerbs, leaf):
+		return CapabilityDestroy, true
+	case slices.Contains(transferVerbs, leaf):
+		return CapabilityTransfer, true
+	}
+	return CapabilityDeploy, true
+}
+
+// CheckAccess returns ErrNotAllowed when cmd changes state while the CLI is
+// in read-only mode, or needs a capability the profile doesn't grant.
+// Commands run with --dry-run only preview, so they're always allowed.
+func CheckAccess(cmd *cobra.Command, readOnly bool, profileName string, profile Profile) error {
+	if f := cmd.Flags().Lookup("dry-run"); f != nil && f.Value.String() == "true" {
+		return nil
+	}
+	var (
+		path       []string
+		capability Capability
+		mutates    bool
+		annotated  bool
+	)
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		path = append([]string{c.Name()}, path...)
+		if value, ok := c.Annotations[CapabilityAnnotation]; ok && !annotated {
+			annotated = true
+			capability, mutates = Capability(value), value != CapabilityNone
+		}
+	}
+	if !annotated {
+		capability, mutates = RequiredCapability(path)
+	}
+	if !mutates {
+		return nil
+	}
+	command := strings.Join(append([]string{cmd.Root().Name()}, path...), " ")
+	switch {
+	case readOnly:
+		return fmt.Errorf("%w: '%s' changes state and the CLI is in read-only mode ('lux config read-only disable' lifts it)", ErrNotAllowed, command)
+	case profile.ReadOnly:
+		return fmt.Errorf("%w: '%s' changes state and profile %q is read-only", ErrNotAllowed, command, profileName)
+	case len(profile.Capabilities) > 0 && !slices.Contains(profile.Capabilities, capability):
+		return fmt.Errorf("%w: '%s' needs the %s capability, profile %q only grants %s",
+			ErrNotAllowed, command, capability, profileName, joinCapabilities(profile.Capabilities))
+	}
+	return nil
+}
+
+func joinCapabilities(caps []Capability) string {
+	names := make([]string, len(caps))
+	for i, c := range caps {
+		names[i] = string(c)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiredCapability(t *testing.T) {
+	tests := []struct {
+		path       []string
+		capability Capability
+		mutates    bool
+	}{
+		{path: []string{"network", "status"}},
+		{path: []string{"chain", "list"}},
+		{path: []string{"chain", "describe"}},
+		{path: []string{"config"}},
+		{path: []string{"config", "profile", "list"}},
+		{path: []string{"chain", "deploy"}, capability: CapabilityDeploy, mutates: true},
+		{path: []string{"network", "start"}, capability: CapabilityDeploy, mutates: true},
+		{path: []string{"network", "clean"}, capability: CapabilityDestroy, mutates: true},
+		{path: []string{"snapshot", "restore"}, capability: CapabilityDestroy, mutates: true},
+		{path: []string{"validator", "add"}, capability: CapabilityValidators, mutates: true},
+		{path: []string{"chain", "addValidator"}, capability: CapabilityValidators, mutates: true},
+		{path: []string{"validator", "increaseBalance"}, capability: CapabilityValidators, mutates: true},
+		{path: []string{"network", "send"}, capability: CapabilityTransfer, mutates: true},
+		{path: []string{"key", "export"}, capability: CapabilityKeys, mutates: true},
+		{path: []string{"config", "profile", "create"}, capability: CapabilityConfig, mutates: true},
+	}
+	for _, tt := range tests {
+		capability, mutates := RequiredCapability(tt.path)
+		require.Equal(t, tt.capability, capability, tt.path)
+		require.Equal(t, tt.mutates, mutates, tt.path)
+	}
+}
+
+func TestCheckAccess(t *testing.T) {
+	require := require.New(t)
+	newCmd := func(path ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "lux"}
+		for _, name := range path {
+			child := &cobra.Command{Use: name}
+			cmd.AddCommand(child)
+			cmd = child
+		}
+		return cmd
+	}
+
+	deploy := newCmd("chain", "deploy")
+	status := newCmd("network", "status")
+	clean := newCmd("network", "clean")
+
+	require.NoError(CheckAccess(deploy, false, "", Profile{}))
+	require.NoError(CheckAccess(status, true, "", Profile{}))
+	err := CheckAccess(deploy, true, "", Profile{})
+	require.ErrorIs(err, ErrNotAllowed)
+	require.Contains(err.Error(), "'lux chain deploy'")
+	require.ErrorIs(CheckAccess(deploy, false, "dashboard", Profile{ReadOnly: true}), ErrNotAllowed)
+
+	junior := Profile{Capabilities: []Capability{CapabilityDeploy}}
+	require.NoError(CheckAccess(deploy, false, "junior", junior))
+	require.NoError(CheckAccess(status, false, "junior", junior))
+	err = CheckAccess(clean, false, "junior", junior)
+	require.ErrorIs(err, ErrNotAllowed)
+	require.Contains(err.Error(), "needs the destroy capability")
+
+	// --dry-run only previews
+	deploy.Flags().Bool("dry-run", false, "")
+	require.NoError(deploy.Flags().Set("dry-run", "true"))
+	require.NoError(CheckAccess(deploy, true, "", Profile{}))
+
+	// Annotations override the name of the command or a parent
+	watch := newCmd("chain", "watch")
+	watch.Parent().Annotations = map[string]string{CapabilityAnnotation: CapabilityNone}
+	require.NoError(CheckAccess(watch, true, "", Profile{}))
+	watch.Annotations = map[string]string{CapabilityAnnotation: string(CapabilityDestroy)}
+	require.ErrorIs(CheckAccess(watch, false, "junior", junior), ErrNotAllowed)
+}
+
+func TestParseCapabilities(t *testing.T) {
+	caps, err := ParseCapabilities([]string{"deploy", " Transfer", "deploy"})
+	require.NoError(t, err)
+	require.Equal(t, []Capability{CapabilityDeploy, CapabilityTransfer}, caps)
+
+	_, err = ParseCapabilities([]string{"root"})
+	require.ErrorContains(t, err, "invalid capability")
+
+	require.Error(t, Profile{Capabilities: []Capability{"root"}}.Validate())
+}
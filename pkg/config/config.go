@@ -38,6 +38,10 @@ func (*Config) GetConfigStringValue(key string) string {
 	return viper.GetString(key)
 }
 
+func (*Config) GetConfigStringSliceValue(key string) []string {
+	return viper.GetStringSlice(key)
+}
+
 func (*Config) ConfigValueIsSet(key string) bool {
 	return viper.IsSet(key)
 }
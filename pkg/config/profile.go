@@ -0,0 +1,238 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/luxfi/cli/pkg/configschema"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// ProfileEnvVar selects a profile when --profile is not given
+	ProfileEnvVar = "LUX_PROFILE"
+
+	// SkipProfileAnnotation marks commands, and their subcommands, whose
+	// flags must not be defaulted from the profile
+	SkipProfileAnnotation = "lux/skip-profile"
+
+	profilesKey      = "profiles"
+	activeProfileKey = "active-profile"
+)
+
+// networkFlags are the mutually exclusive network selection flags a
+// profile's network sets.
+var networkFlags = []string{"mainnet", "testnet", "devnet", "local"}
+
+var profileNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// Profile scopes defaults for one environment, e.g. mainnet or one of
+// several devnets. Its values are used as defaults for the flags of the same
+// name, so anything given on the command line still wins.
+type Profile struct {
+	// Network is one of mainnet, testnet, devnet or local
+	Network string `json:"network,omitempty"`
+	// Key is the default key name (--key)
+	Key string `json:"key,omitempty"`
+	// Endpoint is the default node API endpoint (--endpoint)
+	Endpoint string `json:"endpoint,omitempty"`
+	// RPC is the default chain RPC endpoint (--rpc)
+	RPC string `json:"rpc,omitempty"`
+	// Flags holds defaults for any other flag, by flag name
+	Flags map[string]string `json:"flags,omitempty"`
+	// ReadOnly blocks every command that changes state
+	ReadOnly bool `json:"readOnly,omitempty"`
+	// Capabilities, when set, are the only kinds of state-changing commands
+	// the profile allows
+	Capabilities []Capability `json:"capabilities,omitempty"`
+}
+
+// Profiles is the profile section of the CLI config file.
+type Profiles struct {
+	Active   string
+	Profiles map[string]Profile
+}
+
+// ValidateProfileName checks name can be used as a profile name.
+func ValidateProfileName(name string) error {
+	if !profileNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+// Validate checks the profile values.
+func (p Profile) Validate() error {
+	if p.Network != "" && !slices.Contains(networkFlags, p.Network) {
+		return fmt.Errorf("invalid network %q: must be one of mainnet, testnet, devnet, local", p.Network)
+	}
+	for _, c := range p.Capabilities {
+		if !slices.Contains(Capabilities, c) {
+			return fmt.Errorf("invalid capability %q: must be one of %s", c, joinCapabilities(Capabilities))
+		}
+	}
+	return nil
+}
+
+// FlagDefaults returns the flag defaults the profile defines, by flag name.
+func (p Profile) FlagDefaults() map[string]string {
+	defaults := make(map[string]string, len(p.Flags)+4)
+	for name, value := range p.Flags {
+		defaults[name] = value
+	}
+	if p.Network != "" {
+		defaults[p.Network] = "true"
+	}
+	if p.Key != "" {
+		defaults["key"] = p.Key
+	}
+	if p.Endpoint != "" {
+		defaults["endpoint"] = p.Endpoint
+	}
+	if p.RPC != "" {
+		defaults["rpc"] = p.RPC
+	}
+	return defaults
+}
+
+// Names returns the profile names in sorted order.
+func (ps *Profiles) Names() []string {
+	names := make([]string, 0, len(ps.Profiles))
+	for name := range ps.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve returns the name of the profile to use: flagValue (--profile),
+// then the LUX_PROFILE environment variable, then the active profile of the
+// config file. explicit reports whether the user asked for it on this
+// invocation, in which case a missing profile is an error.
+func (ps *Profiles) Resolve(flagValue string) (name string, explicit bool) {
+	if flagValue != "" {
+		return flagValue, true
+	}
+	if env := os.Getenv(ProfileEnvVar); env != "" {
+		return env, true
+	}
+	return ps.Active, false
+}
+
+// LoadProfiles reads the profile section of the config file at configPath. A
+// missing file yields no profiles.
+func LoadProfiles(configPath string) (*Profiles, error) {
+	ps := &Profiles{Profiles: map[string]Profile{}}
+	raw, err := readConfigMap(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if data, ok := raw[profilesKey]; ok {
+		if err := json.Unmarshal(data, &ps.Profiles); err != nil {
+			return nil, invalidSection(configPath, profilesKey, err)
+		}
+	}
+	if data, ok := raw[activeProfileKey]; ok {
+		if err := json.Unmarshal(data, &ps.Active); err != nil {
+			return nil, invalidSection(configPath, activeProfileKey, err)
+		}
+	}
+	return ps, nil
+}
+
+// SaveProfiles writes the profile section into the config file at configPath,
+// keeping all other settings of the file.
+func SaveProfiles(configPath string, ps *Profiles) error {
+	raw, err := readConfigMap(configPath)
+	if err != nil {
+		return err
+	}
+	if len(ps.Profiles) == 0 {
+		delete(raw, profilesKey)
+	} else if raw[profilesKey], err = json.Marshal(ps.Profiles); err != nil {
+		return err
+	}
+	if ps.Active == "" {
+		delete(raw, activeProfileKey)
+	} else if raw[activeProfileKey], err = json.Marshal(ps.Active); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(configPath, data, 0o644) //nolint:gosec // G306: Config file is not secret
+}
+
+func readConfigMap(configPath string) (map[string]json.RawMessage, error) {
+	raw := map[string]json.RawMessage{}
+	data, err := os.ReadFile(configPath) //nolint:gosec // G304: Reading the CLI config file
+	if os.IsNotExist(err) {
+		return raw, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return raw, nil
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", configPath, configschema.CLI().Explain(data, "", err))
+	}
+	return raw, nil
+}
+
+// invalidSection returns the error of the key section of the config file at
+// configPath failing to load with err, naming the fields to fix
+func invalidSection(configPath, key string, err error) error {
+	if data, readErr := os.ReadFile(configPath); readErr == nil { //nolint:gosec // G304: Reading the CLI config file
+		err = configschema.CLI().Explain(data, key, err)
+	}
+	return fmt.Errorf("invalid %q in %s: %w", key, configPath, err)
+}
+
+// ApplyFlagDefaults sets the flags in defaults that exist in fs and were not
+// given on the command line. A network default is skipped when any network
+// flag was given.
+func ApplyFlagDefaults(fs *pflag.FlagSet, defaults map[string]string) error {
+	networkChosen := false
+	for _, name := range networkFlags {
+		if f := fs.Lookup(name); f != nil && f.Changed {
+			networkChosen = true
+		}
+	}
+
+	names := make([]string, 0, len(defaults))
+	for name := range defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		f := fs.Lookup(name)
+		if f == nil || f.Changed {
+			continue
+		}
+		if networkChosen && slices.Contains(networkFlags, name) {
+			continue
+		}
+		// fs.Set marks the flag changed, so commands checking Changed treat
+		// profile values like given ones
+		if err := fs.Set(name, defaults[name]); err != nil {
+			return fmt.Errorf("invalid profile value for --%s: %w", name, err)
+		}
+	}
+	return nil
+}
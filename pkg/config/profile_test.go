@@ -0,0 +1,97 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfilesRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cli.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"metricsEnabled": true}`), 0o600))
+
+	ps, err := LoadProfiles(path)
+	require.NoError(t, err)
+	require.Empty(t, ps.Profiles)
+
+	ps.Profiles["devnet-a"] = Profile{Network: "devnet", Key: "ops", Flags: map[string]string{"ledger": "true"}}
+	ps.Active = "devnet-a"
+	require.NoError(t, SaveProfiles(path, ps))
+
+	loaded, err := LoadProfiles(path)
+	require.NoError(t, err)
+	require.Equal(t, ps, loaded)
+
+	// Other settings of the config file are kept
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"metricsEnabled": true`)
+}
+
+func TestLoadProfilesInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cli.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"profiles": {"ops": {"network": "devnet", "flags": {"ledger": true}}}}`), 0o600))
+	_, err := LoadProfiles(path)
+	require.ErrorContains(t, err, `profiles.ops.flags.ledger: expected string, got boolean true (quote it: "true")`)
+
+	require.NoError(t, os.WriteFile(path, []byte("{\"profiles\": {},}"), 0o600))
+	_, err = LoadProfiles(path)
+	require.ErrorContains(t, err, "invalid JSON at line 1, column 17")
+	require.ErrorContains(t, err, "remove the trailing comma")
+}
+
+func TestProfilesResolve(t *testing.T) {
+	ps := &Profiles{Active: "stored"}
+
+	t.Setenv(ProfileEnvVar, "")
+	name, explicit := ps.Resolve("")
+	require.Equal(t, "stored", name)
+	require.False(t, explicit)
+
+	t.Setenv(ProfileEnvVar, "env")
+	name, explicit = ps.Resolve("")
+	require.Equal(t, "env", name)
+	require.True(t, explicit)
+
+	name, _ = ps.Resolve("flag")
+	require.Equal(t, "flag", name)
+}
+
+func TestApplyFlagDefaults(t *testing.T) {
+	newFlags := func() *pflag.FlagSet {
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		fs.String("key", "", "")
+		fs.String("endpoint", "", "")
+		fs.Bool("mainnet", false, "")
+		fs.Bool("devnet", false, "")
+		return fs
+	}
+	profile := Profile{Network: "devnet", Key: "ops", Endpoint: "http://10.0.0.5:9650", RPC: "http://unused"}
+
+	fs := newFlags()
+	require.NoError(t, ApplyFlagDefaults(fs, profile.FlagDefaults()))
+	key, _ := fs.GetString("key")
+	require.Equal(t, "ops", key)
+	devnet, _ := fs.GetBool("devnet")
+	require.True(t, devnet)
+
+	// Flags given on the command line win, including another network
+	fs = newFlags()
+	require.NoError(t, fs.Parse([]string{"--key", "mine", "--mainnet"}))
+	require.NoError(t, ApplyFlagDefaults(fs, profile.FlagDefaults()))
+	key, _ = fs.GetString("key")
+	require.Equal(t, "mine", key)
+	devnet, _ = fs.GetBool("devnet")
+	require.False(t, devnet)
+	endpoint, _ := fs.GetString("endpoint")
+	require.Equal(t, "http://10.0.0.5:9650", endpoint)
+
+	fs = newFlags()
+	require.Error(t, ApplyFlagDefaults(fs, map[string]string{"mainnet": "maybe"}))
+}
@@ -0,0 +1,23 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package configschema
+
+import (
+	_ "embed"
+	"encoding/json"
+)
+
+//go:embed schemas/cli.schema.json
+var cliSchema []byte
+
+// CLI returns the schema of the CLI config file, cli.json
+func CLI() *Schema {
+	s := &Schema{}
+	if err := json.Unmarshal(cliSchema, s); err != nil {
+		panic(err)
+	}
+	// viper reads top level keys regardless of case
+	s.caseInsensitive = true
+	return s
+}
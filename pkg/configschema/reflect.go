@@ -0,0 +1,178 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package configschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"math"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	bigIntType      = reflect.TypeOf(big.Int{})
+	timeType        = reflect.TypeOf(time.Time{})
+)
+
+// FromValue returns the schema of the JSON encoding of v, as read by
+// encoding/json into a value of its type. Fields with custom unmarshaling
+// are strings when they also unmarshal from text, such as IDs, and any
+// value otherwise.
+func FromValue(v interface{}) *Schema {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	s := fromType(t, map[reflect.Type]bool{})
+	s.Dialect = Dialect
+	return s
+}
+
+func fromType(t reflect.Type, visiting map[reflect.Type]bool) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+	s := typeSchema(t, visiting)
+	if nullable && len(s.Types) > 0 {
+		s.Types = append(s.Types, TypeNull)
+	}
+	return s
+}
+
+func typeSchema(t reflect.Type, visiting map[reflect.Type]bool) *Schema {
+	switch {
+	case t == bigIntType:
+		return &Schema{Types: []string{TypeInteger}}
+	case t == timeType:
+		return &Schema{Types: []string{TypeString}, Format: "date-time"}
+	case reflect.PointerTo(t).Implements(textUnmarshaler):
+		return &Schema{Types: []string{TypeString}}
+	case reflect.PointerTo(t).Implements(jsonUnmarshaler):
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Types: []string{TypeBoolean}}
+	case reflect.String:
+		return &Schema{Types: []string{TypeString}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bits := float64(t.Bits())
+		return &Schema{
+			Types:   []string{TypeInteger},
+			Minimum: ptr(-math.Pow(2, bits-1)),
+			Maximum: ptr(math.Pow(2, bits-1) - 1),
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{
+			Types:   []string{TypeInteger},
+			Minimum: ptr(0),
+			Maximum: ptr(math.Pow(2, float64(t.Bits())) - 1),
+		}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Types: []string{TypeNumber}}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// base64
+			return &Schema{Types: []string{TypeString, TypeNull}}
+		}
+		return &Schema{Types: []string{TypeArray, TypeNull}, Items: fromType(t.Elem(), visiting)}
+	case reflect.Array:
+		return &Schema{Types: []string{TypeArray}, Items: fromType(t.Elem(), visiting)}
+	case reflect.Map:
+		return &Schema{Types: []string{TypeObject, TypeNull}, AdditionalProperties: fromType(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			// recursive types are checked one level deep
+			return &Schema{}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		s := &Schema{
+			Types:           []string{TypeObject},
+			Properties:      map[string]*Schema{},
+			Closed:          true,
+			caseInsensitive: true,
+		}
+		addFields(s, t, visiting)
+		return s
+	}
+	// interfaces, and anything encoding/json can't decode into
+	return &Schema{}
+}
+
+// addFields adds the properties of the fields of struct t to s, including
+// the fields of its embedded structs
+func addFields(s *Schema, t reflect.Type, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, tagged := jsonName(field)
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && !tagged {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				addFields(s, ft, visiting)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		p := fromType(field.Type, visiting)
+		if quoted(field) {
+			// the ,string option quotes numbers and booleans
+			p = &Schema{Types: []string{TypeString}}
+		}
+		s.Properties[name] = p
+	}
+}
+
+// jsonName returns the key of field in JSON, and whether it is named by a
+// tag
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "-", true
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return field.Name, false
+	}
+	return name, true
+}
+
+// quoted reports whether field is a number or boolean encoded as a string
+func quoted(field reflect.StructField) bool {
+	_, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if !slices.Contains(strings.Split(options, ","), "string") {
+		return false
+	}
+	switch field.Type.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func ptr(f float64) *float64 {
+	return &f
+}
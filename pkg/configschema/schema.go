@@ -0,0 +1,213 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package configschema describes the JSON config files of the CLI, such as
+// cli.json, sidecars and network states, with JSON Schemas, and validates
+// files against them. Validation reports every field that doesn't match,
+// with its path in the file and a suggested fix, rather than the first
+// unmarshal error.
+package configschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Dialect is the JSON Schema version of the schemas
+const Dialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSON types of a Schema
+const (
+	TypeNull    = "null"
+	TypeBoolean = "boolean"
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeArray   = "array"
+	TypeObject  = "object"
+)
+
+// FormatDuration is the format of strings holding a Go duration, such as
+// 30s or 1h
+const FormatDuration = "duration"
+
+// ErrInvalid is returned for files that don't match their schema
+var ErrInvalid = errors.New("invalid config")
+
+// Schema is a JSON Schema, of the keywords needed to describe the config
+// files of the CLI
+type Schema struct {
+	Dialect     string `json:"$schema,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Types the value can be, any when empty
+	Types      []string           `json:"-"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	// AdditionalProperties is the schema of the object keys that aren't
+	// properties. When nil, Closed objects report them as unknown.
+	AdditionalProperties *Schema `json:"-"`
+	// Closed objects only have their properties. Unknown keys are warnings,
+	// as they are ignored on load and may be added by a newer CLI.
+	Closed  bool          `json:"-"`
+	Items   *Schema       `json:"items,omitempty"`
+	Enum    []interface{} `json:"enum,omitempty"`
+	Pattern string        `json:"pattern,omitempty"`
+	Format  string        `json:"format,omitempty"`
+	Minimum *float64      `json:"minimum,omitempty"`
+	Maximum *float64      `json:"maximum,omitempty"`
+
+	// caseInsensitive objects match keys to properties regardless of case,
+	// as encoding/json does for structs
+	caseInsensitive bool
+}
+
+type schemaJSON Schema
+
+// MarshalJSON writes the types as type, a string or a list, and Closed
+// as additionalProperties false
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	out := struct {
+		*schemaJSON
+		Type                 interface{} `json:"type,omitempty"`
+		AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+	}{schemaJSON: (*schemaJSON)(s)}
+	switch len(s.Types) {
+	case 0:
+	case 1:
+		out.Type = s.Types[0]
+	default:
+		out.Type = s.Types
+	}
+	switch {
+	case s.AdditionalProperties != nil:
+		out.AdditionalProperties = s.AdditionalProperties
+	case s.Closed:
+		out.AdditionalProperties = false
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON reads the type and additionalProperties keywords
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	in := struct {
+		*schemaJSON
+		Type                 json.RawMessage `json:"type"`
+		AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	}{schemaJSON: (*schemaJSON)(s)}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	if len(in.Type) > 0 {
+		var t string
+		if err := json.Unmarshal(in.Type, &t); err == nil {
+			s.Types = []string{t}
+		} else if err := json.Unmarshal(in.Type, &s.Types); err != nil {
+			return fmt.Errorf("invalid type %s", in.Type)
+		}
+	}
+	if len(in.AdditionalProperties) > 0 {
+		var allowed bool
+		if err := json.Unmarshal(in.AdditionalProperties, &allowed); err == nil {
+			s.Closed = !allowed
+		} else if err := json.Unmarshal(in.AdditionalProperties, &s.AdditionalProperties); err != nil {
+			return fmt.Errorf("invalid additionalProperties %s", in.AdditionalProperties)
+		}
+	}
+	return nil
+}
+
+// property returns the schema of the key of object s, and the name of the
+// property matched
+func (s *Schema) property(key string) (*Schema, string) {
+	if p, ok := s.Properties[key]; ok {
+		return p, key
+	}
+	if s.caseInsensitive {
+		for name, p := range s.Properties {
+			if strings.EqualFold(name, key) {
+				return p, name
+			}
+		}
+	}
+	return nil, ""
+}
+
+// Issue is a field of a file not matching its schema
+type Issue struct {
+	// Path of the field in the file, as in networks.Testnet.RPCVersion,
+	// empty for the whole file
+	Path    string
+	Message string
+	// Fix suggests how to fix the field, if anything
+	Fix string
+	// Warning issues don't keep the file from loading
+	Warning bool
+}
+
+func (i Issue) String() string {
+	msg := i.Message
+	if i.Path != "" {
+		msg = i.Path + ": " + msg
+	}
+	if i.Fix != "" {
+		msg += " (" + i.Fix + ")"
+	}
+	return msg
+}
+
+// Errors returns the issues that aren't warnings
+func Errors(issues []Issue) []Issue {
+	var errs []Issue
+	for _, issue := range issues {
+		if !issue.Warning {
+			errs = append(errs, issue)
+		}
+	}
+	return errs
+}
+
+// ValidationError lists the issues keeping a file from loading
+type ValidationError struct {
+	Issues []Issue
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		lines[i] = issue.String()
+	}
+	return strings.Join(lines, "; ")
+}
+
+func (*ValidationError) Is(target error) bool {
+	return target == ErrInvalid
+}
+
+// Check validates data against s, returning a ValidationError of the issues
+// that aren't warnings, if any
+func (s *Schema) Check(data []byte) error {
+	if errs := Errors(s.Validate(data)); len(errs) > 0 {
+		return &ValidationError{Issues: errs}
+	}
+	return nil
+}
+
+// Explain returns a ValidationError of the issues of data against s at
+// path, or within it, in place of err, the error loading the field at path
+// of data, when there are any. An empty path is the whole document.
+func (s *Schema) Explain(data []byte, path string, err error) error {
+	var errs []Issue
+	for _, issue := range Errors(s.Validate(data)) {
+		if path == "" || issue.Path == path ||
+			strings.HasPrefix(issue.Path, path+".") || strings.HasPrefix(issue.Path, path+"[") {
+			errs = append(errs, issue)
+		}
+	}
+	if len(errs) == 0 {
+		return err
+	}
+	return &ValidationError{Issues: errs}
+}
@@ -0,0 +1,176 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package configschema
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testID unmarshals from text, as IDs do
+type testID [4]byte
+
+func (id *testID) UnmarshalText(text []byte) error {
+	if len(text) != 4 {
+		return errors.New("invalid ID")
+	}
+	copy(id[:], text)
+	return nil
+}
+
+type testNetwork struct {
+	ChainID      testID
+	RPCVersion   int
+	RPCEndpoints []string
+}
+
+type testSidecar struct {
+	Name      string
+	Networks  map[string]testNetwork
+	Sovereign bool   `json:"sovereign"`
+	Weight    uint8  `json:"weight,omitempty"`
+	Fee       uint64 `json:"fee,string"`
+	Created   time.Time
+	Ignored   string                 `json:"-"`
+	Extra     map[string]interface{} `json:"extra,omitempty"`
+}
+
+func issueStrings(issues []Issue) []string {
+	strs := make([]string, len(issues))
+	for i, issue := range issues {
+		strs[i] = issue.String()
+	}
+	return strs
+}
+
+func TestValidateFromValue(t *testing.T) {
+	require := require.New(t)
+	s := FromValue(&testSidecar{})
+	require.Equal([]string{TypeString}, s.Properties["fee"].Types)
+	require.NotContains(s.Properties, "Ignored")
+
+	valid := `{
+  "name": "mychain",
+  "Networks": {"Testnet": {"ChainID": "abcd", "RPCVersion": 42, "RPCEndpoints": null}},
+  "sovereign": true,
+  "fee": "1000",
+  "Created": "2026-01-02T15:04:05Z",
+  "extra": {"anything": [1, "two"]},
+  "schemaVersion": 1
+}`
+	issues := s.Validate([]byte(valid))
+	require.Equal([]string{`schemaVersion: unknown field, ignored`}, issueStrings(issues))
+	require.True(issues[0].Warning)
+	require.NoError(s.Check([]byte(valid)))
+
+	invalid := `{
+  "Name": 7,
+  "Networks": {"Testnet": {"ChainID": 12, "RPCVersion": "42", "RPCEndpoints": "http://127.0.0.1:9630"}},
+  "sovereign": "true",
+  "weight": 300,
+  "Netwroks": {}
+}`
+	require.Equal([]string{
+		`Name: expected string, got integer 7 (quote it: "7")`,
+		`Networks.Testnet.ChainID: expected string, got integer 12 (quote it: "12")`,
+		`Networks.Testnet.RPCEndpoints: expected array or null, got string "http://127.0.0.1:9630" (use a list: ["http://127.0.0.1:9630"])`,
+		`Networks.Testnet.RPCVersion: expected integer, got string "42" (remove the quotes: 42)`,
+		`Netwroks: unknown field, ignored (did you mean "Networks"?)`,
+		`sovereign: expected boolean, got string "true" (remove the quotes: true)`,
+		`weight: 300 is above the maximum of 255`,
+	}, issueStrings(s.Validate([]byte(invalid))))
+
+	err := s.Check([]byte(invalid))
+	require.ErrorIs(err, ErrInvalid)
+	var validationErr *ValidationError
+	require.True(errors.As(err, &validationErr))
+	require.Len(validationErr.Issues, 6)
+}
+
+func TestValidateSyntax(t *testing.T) {
+	s := FromValue(&testSidecar{})
+	tests := []struct {
+		data    string
+		message string
+		fix     string
+	}{
+		{"{\n  \"Name\": \"a\",\n}", "invalid JSON at line 3, column 1", "remove the trailing comma"},
+		{"{\n  Name: \"a\"\n}", "invalid JSON at line 2, column 3", "quote the key with double quotes"},
+		{`{"Name": "a" "sovereign": true}`, "invalid JSON at line 1, column 14", "add the missing comma before it"},
+		{`{'Name': "a"}`, "invalid JSON at line 1, column 2", "use double quotes for strings and keys"},
+		{`{"Name": "a", "Networks": {`, "the file is empty or truncated", "restore it from its .bak backup, or close the open brackets"},
+		{`{"Name": "a"} {}`, "unexpected data after the document at line 1, column 15", "a file holds a single JSON value, remove what follows it"},
+	}
+	for _, test := range tests {
+		issues := s.Validate([]byte(test.data))
+		require.Len(t, issues, 1, test.data)
+		require.Contains(t, issues[0].Message, test.message, test.data)
+		require.Equal(t, test.fix, issues[0].Fix, test.data)
+		require.False(t, issues[0].Warning)
+	}
+}
+
+func TestCLI(t *testing.T) {
+	require := require.New(t)
+	s := CLI()
+	valid := `{
+  "metrics-enabled": true,
+  "SnapshotsAutoSaveEnabled": false,
+  "skipupdatecheck": true,
+  "profiles": {"prod": {"network": "mainnet", "key": "ops", "capabilities": ["validators"], "flags": {"rpc": "https://api.lux.network"}}},
+  "active-profile": "prod",
+  "hooks": {"post-deploy": [{"url": "https://example.com/hook", "timeout": "30s"}]},
+  "node-config": {"log-level": "info"}
+}`
+	require.Equal([]string{
+		`skipupdatecheck: unknown field, ignored (did you mean "skip-update-check"?)`,
+	}, issueStrings(s.Validate([]byte(valid))))
+
+	invalid := `{
+  "read-only": "yes",
+  "profiles": {"prod": {"network": "mainet", "capabilities": "deploy"}},
+  "hooks": {"pre-deploy": [{"run": "make check", "timeout": "5 minutes"}]}
+}`
+	require.Equal([]string{
+		`hooks.pre-deploy[0].timeout: "5 minutes" is not a duration (use a number and a unit, such as 30s, 5m or 1h)`,
+		`profiles.prod.capabilities: expected array, got string "deploy" (use a list: ["deploy"])`,
+		`profiles.prod.network: "mainet" is not one of "mainnet", "testnet", "devnet", "local" (did you mean "mainnet"?)`,
+		`read-only: expected boolean, got string "yes"`,
+	}, issueStrings(s.Validate([]byte(invalid))))
+
+	loadErr := errors.New("json: cannot unmarshal string into Go value of type []string")
+	err := s.Explain([]byte(invalid), "profiles", loadErr)
+	require.ErrorIs(err, ErrInvalid)
+	require.Equal(
+		`profiles.prod.capabilities: expected array, got string "deploy" (use a list: ["deploy"]); `+
+			`profiles.prod.network: "mainet" is not one of "mainnet", "testnet", "devnet", "local" (did you mean "mainnet"?)`,
+		err.Error(),
+	)
+	require.Equal(loadErr, s.Explain([]byte(valid), "profiles", loadErr))
+}
+
+func TestSchemaJSON(t *testing.T) {
+	require := require.New(t)
+	raw, err := json.Marshal(FromValue(&testSidecar{}))
+	require.NoError(err)
+	var doc map[string]interface{}
+	require.NoError(json.Unmarshal(raw, &doc))
+	require.Equal(Dialect, doc["$schema"])
+	require.Equal("object", doc["type"])
+	require.Equal(false, doc["additionalProperties"])
+	networks := doc["properties"].(map[string]interface{})["Networks"].(map[string]interface{})
+	require.Equal([]interface{}{"object", "null"}, networks["type"])
+	require.Contains(networks["additionalProperties"], "properties")
+
+	// a schema reads back as written
+	s := &Schema{}
+	require.NoError(json.Unmarshal(raw, s))
+	require.True(s.Closed)
+	require.Equal([]string{TypeObject, TypeNull}, s.Properties["Networks"].Types)
+	require.NotNil(s.Properties["Networks"].AdditionalProperties)
+}
@@ -0,0 +1,381 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package configschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validate returns the issues of the JSON document data against s: its
+// syntax error, or every field not matching its schema
+func (s *Schema) Validate(data []byte) []Issue {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return []Issue{syntaxIssue(data, err)}
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		line, column := position(data, decoder.InputOffset())
+		return []Issue{{
+			Message: fmt.Sprintf("unexpected data after the document at line %d, column %d", line, column),
+			Fix:     "a file holds a single JSON value, remove what follows it",
+		}}
+	}
+	var issues []Issue
+	s.validate(v, "", &issues)
+	return issues
+}
+
+// syntaxIssue reports where data isn't valid JSON
+func syntaxIssue(data []byte, err error) Issue {
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return Issue{
+				Message: "the file is empty or truncated",
+				Fix:     "restore it from its .bak backup, or close the open brackets",
+			}
+		}
+		return Issue{Message: "invalid JSON: " + err.Error()}
+	}
+	offset := syntaxErr.Offset
+	line, column := position(data, offset)
+	issue := Issue{Message: fmt.Sprintf("invalid JSON at line %d, column %d: %s", line, column, syntaxErr)}
+	// the offset is past the unexpected byte
+	at := byte(0)
+	if offset > 0 && int(offset) <= len(data) {
+		at = data[offset-1]
+	}
+	before := bytes.TrimRight(data[:max(offset-1, 0)], " \t\r\n")
+	switch {
+	case (at == '}' || at == ']') && bytes.HasSuffix(before, []byte(",")):
+		issue.Fix = "remove the trailing comma"
+	case at == '\'':
+		issue.Fix = "use double quotes for strings and keys"
+	case at == '/' || at == '#':
+		issue.Fix = "JSON has no comments, remove them"
+	case strings.Contains(syntaxErr.Error(), "unexpected end"):
+		issue.Fix = "the file is truncated: restore it from its .bak backup, or close the open brackets"
+	case isKeyStart(at) && (bytes.HasSuffix(before, []byte("{")) || bytes.HasSuffix(before, []byte(","))):
+		issue.Fix = "quote the key with double quotes"
+	case at == '"' && !bytes.HasSuffix(before, []byte(",")) && !bytes.HasSuffix(before, []byte("{")) && !bytes.HasSuffix(before, []byte("[")):
+		issue.Fix = "add the missing comma before it"
+	}
+	return issue
+}
+
+func isKeyStart(b byte) bool {
+	return b == '_' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// position returns the 1-based line and column of offset in data
+func position(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	line, column := 1, 1
+	for _, b := range data[:max(offset-1, 0)] {
+		if b == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return line, column
+}
+
+func (s *Schema) validate(v interface{}, path string, issues *[]Issue) {
+	if s == nil {
+		return
+	}
+	got := jsonType(v)
+	if len(s.Types) > 0 && !s.allows(got) {
+		*issues = append(*issues, Issue{
+			Path:    path,
+			Message: strings.TrimSpace(fmt.Sprintf("expected %s, got %s %s", strings.Join(s.Types, " or "), got, brief(v))),
+			Fix:     s.typeFix(v),
+		})
+		return
+	}
+	if len(s.Enum) > 0 && !s.inEnum(v) {
+		issue := Issue{Path: path, Message: fmt.Sprintf("%s is not one of %s", brief(v), enumList(s.Enum))}
+		if str, ok := v.(string); ok {
+			if match := closest(str, enumStrings(s.Enum)); match != "" {
+				issue.Fix = fmt.Sprintf("did you mean %q?", match)
+			}
+		}
+		*issues = append(*issues, issue)
+		return
+	}
+
+	switch v := v.(type) {
+	case string:
+		if s.Pattern != "" {
+			if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(v) {
+				*issues = append(*issues, Issue{Path: path, Message: fmt.Sprintf("%q does not match %s", v, s.Pattern)})
+			}
+		}
+		if s.Format == FormatDuration {
+			if _, err := time.ParseDuration(v); err != nil {
+				*issues = append(*issues, Issue{
+					Path:    path,
+					Message: fmt.Sprintf("%q is not a duration", v),
+					Fix:     "use a number and a unit, such as 30s, 5m or 1h",
+				})
+			}
+		}
+	case json.Number:
+		s.validateRange(v, path, issues)
+	case []interface{}:
+		for i, item := range v {
+			s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), issues)
+		}
+	case map[string]interface{}:
+		s.validateObject(v, path, issues)
+	}
+}
+
+func (s *Schema) validateObject(obj map[string]interface{}, path string, issues *[]Issue) {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			*issues = append(*issues, Issue{
+				Path:    join(path, name),
+				Message: "missing required field",
+				Fix:     fmt.Sprintf("add %q", name),
+			})
+		}
+	}
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if p, _ := s.property(key); p != nil {
+			p.validate(obj[key], join(path, key), issues)
+			continue
+		}
+		switch {
+		case s.AdditionalProperties != nil:
+			s.AdditionalProperties.validate(obj[key], join(path, key), issues)
+		case s.Closed:
+			issue := Issue{Path: join(path, key), Message: "unknown field, ignored", Warning: true}
+			names := make([]string, 0, len(s.Properties))
+			for name := range s.Properties {
+				names = append(names, name)
+			}
+			if match := closest(key, names); match != "" {
+				issue.Fix = fmt.Sprintf("did you mean %q?", match)
+			}
+			*issues = append(*issues, issue)
+		}
+	}
+}
+
+func (s *Schema) validateRange(n json.Number, path string, issues *[]Issue) {
+	f, ok := new(big.Float).SetString(n.String())
+	if !ok {
+		return
+	}
+	if s.Minimum != nil && f.Cmp(big.NewFloat(*s.Minimum)) < 0 {
+		*issues = append(*issues, Issue{Path: path, Message: fmt.Sprintf("%s is below the minimum of %s", n, formatFloat(*s.Minimum))})
+	}
+	if s.Maximum != nil && f.Cmp(big.NewFloat(*s.Maximum)) > 0 {
+		*issues = append(*issues, Issue{Path: path, Message: fmt.Sprintf("%s is above the maximum of %s", n, formatFloat(*s.Maximum))})
+	}
+}
+
+// allows reports whether values of the JSON type t match the types of s
+func (s *Schema) allows(t string) bool {
+	return slices.Contains(s.Types, t) || t == TypeInteger && slices.Contains(s.Types, TypeNumber)
+}
+
+// typeFix suggests how to turn v into a value of the types of s
+func (s *Schema) typeFix(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		trimmed := strings.TrimSpace(v)
+		if b := strings.ToLower(trimmed); (b == "true" || b == "false") && s.allows(TypeBoolean) {
+			return "remove the quotes: " + b
+		}
+		if n := json.Number(trimmed); s.allows(jsonType(n)) && isNumber(trimmed) {
+			return "remove the quotes: " + trimmed
+		}
+		if s.allows(TypeArray) {
+			return fmt.Sprintf("use a list: [%q]", v)
+		}
+	case json.Number:
+		if s.allows(TypeString) {
+			return fmt.Sprintf("quote it: %q", v.String())
+		}
+		if s.allows(TypeInteger) {
+			return "use a whole number"
+		}
+	case bool:
+		if s.allows(TypeString) {
+			return fmt.Sprintf("quote it: %q", strconv.FormatBool(v))
+		}
+	case nil:
+		return "remove the field to use the default"
+	}
+	if s.allows(TypeArray) && jsonType(v) != TypeArray && jsonType(v) != TypeObject {
+		return fmt.Sprintf("use a list: [%s]", brief(v))
+	}
+	return ""
+}
+
+func (s *Schema) inEnum(v interface{}) bool {
+	for _, e := range s.Enum {
+		if fmt.Sprint(normalize(e)) == fmt.Sprint(normalize(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize returns numbers as json.Number, as decoded from a document
+func normalize(v interface{}) interface{} {
+	if f, ok := v.(float64); ok {
+		return json.Number(strconv.FormatFloat(f, 'f', -1, 64))
+	}
+	return v
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+// jsonType returns the JSON type of a decoded value, integer for whole
+// numbers
+func jsonType(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return TypeNull
+	case bool:
+		return TypeBoolean
+	case string:
+		return TypeString
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return TypeInteger
+		}
+		if _, ok := new(big.Int).SetString(v.String(), 10); ok {
+			return TypeInteger
+		}
+		return TypeNumber
+	case []interface{}:
+		return TypeArray
+	case map[string]interface{}:
+		return TypeObject
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// brief formats v for a message, eliding long values
+func brief(v interface{}) string {
+	var s string
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		s = strconv.Quote(v)
+	case []interface{}, map[string]interface{}:
+		return ""
+	default:
+		s = fmt.Sprint(v)
+	}
+	if len(s) > 40 {
+		s = s[:37] + "..."
+	}
+	return s
+}
+
+func enumStrings(enum []interface{}) []string {
+	var strs []string
+	for _, e := range enum {
+		if s, ok := e.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
+
+func enumList(enum []interface{}) string {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		if s, ok := e.(string); ok {
+			values[i] = strconv.Quote(s)
+		} else {
+			values[i] = fmt.Sprint(e)
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// closest returns the candidate nearest to s, if near enough to be a typo
+// or a difference of case or separators
+func closest(s string, candidates []string) string {
+	best, bestDistance := "", -1
+	for _, c := range candidates {
+		if simplify(c) == simplify(s) {
+			return c
+		}
+		d := distance(strings.ToLower(s), strings.ToLower(c))
+		if d <= max(2, len(c)/4) && (bestDistance < 0 || d < bestDistance) {
+			best, bestDistance = c, d
+		}
+	}
+	return best
+}
+
+// simplify drops the case and separators of a key
+func simplify(s string) string {
+	return strings.NewReplacer("-", "", "_", "", ".", "").Replace(strings.ToLower(s))
+}
+
+// distance is the Levenshtein distance of a and b
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
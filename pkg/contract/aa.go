@@ -0,0 +1,236 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package contract
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/luxfi/crypto"
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/sdk/evm"
+)
+
+const (
+	// EntryPointV07Address is the address of the ERC-4337 v0.7 EntryPoint
+	// on every EVM chain it is deployed to with its canonical build
+	EntryPointV07Address = "0x0000000071727De22E5E9d8BAf0edAc6f37da032"
+	// EntryPointV07Salt is the salt the canonical EntryPoint is deployed
+	// with through the CREATE2 deployer
+	EntryPointV07Salt = "0x90d8084deab30c2a37c45e8d47f49f2f7965183cb6990a98943ef94940681de3"
+	// Create2DeployerAddress is the deterministic deployment proxy, which
+	// deploys its calldata's init code with its first 32 bytes as salt
+	Create2DeployerAddress = "0x4e59b44847b379578588920cA78FbF26c0B4956C"
+)
+
+// simplePaymasterBin is the init code of a paymaster sponsoring every user
+// operation, hand assembled from contracts/src/SimplePaymaster.asm
+//
+//go:embed contracts/bin/SimplePaymaster.bin
+var simplePaymasterBin []byte
+
+// ErrNoCreate2Deployer is returned when the chain has no deterministic
+// deployment proxy
+var ErrNoCreate2Deployer = errors.New("the CREATE2 deployer " + Create2DeployerAddress + " is not deployed to the chain")
+
+// ReadBytecode reads the contract init code of a build artifact: a hex
+// string, or the JSON artifact of hardhat or foundry
+func ReadBytecode(path string) ([]byte, error) {
+	raw, err := os.ReadFile(path) //nolint:gosec // G304: Reading user provided artifact
+	if err != nil {
+		return nil, err
+	}
+	code := strings.TrimSpace(string(raw))
+	if strings.HasPrefix(code, "{") {
+		var artifact struct {
+			Bytecode json.RawMessage `json:"bytecode"`
+		}
+		if err := json.Unmarshal(raw, &artifact); err != nil {
+			return nil, fmt.Errorf("invalid artifact %s: %w", path, err)
+		}
+		var foundry struct {
+			Object string `json:"object"`
+		}
+		if err := json.Unmarshal(artifact.Bytecode, &code); err != nil {
+			if err := json.Unmarshal(artifact.Bytecode, &foundry); err != nil {
+				return nil, fmt.Errorf("artifact %s has no bytecode", path)
+			}
+			code = foundry.Object
+		}
+	}
+	code = strings.TrimPrefix(code, "0x")
+	if code == "" || !isHex(code) {
+		return nil, fmt.Errorf("artifact %s has no bytecode", path)
+	}
+	return common.FromHex(code), nil
+}
+
+// Create2Address returns the address the CREATE2 deployer deploys initCode
+// to with salt
+func Create2Address(salt common.Hash, initCode []byte) crypto.Address {
+	return crypto.CreateAddress2(crypto.HexToAddress(Create2DeployerAddress), salt, crypto.Keccak256(initCode))
+}
+
+// DeployCreate2 deploys initCode with salt through the CREATE2 deployer,
+// unless a contract is already deployed to its address
+func DeployCreate2(
+	rpcURL string,
+	privateKey string,
+	salt common.Hash,
+	initCode []byte,
+) (crypto.Address, bool, error) {
+	address := Create2Address(salt, initCode)
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return crypto.Address{}, false, err
+	}
+	defer client.Close()
+	if deployed, err := client.ContractAlreadyDeployed(address.Hex()); err != nil {
+		return crypto.Address{}, false, err
+	} else if deployed {
+		return address, false, nil
+	}
+	if deployed, err := client.ContractAlreadyDeployed(Create2DeployerAddress); err != nil {
+		return crypto.Address{}, false, err
+	} else if !deployed {
+		return crypto.Address{}, false, ErrNoCreate2Deployer
+	}
+	key, err := crypto.HexToECDSA(privateKey)
+	if err != nil {
+		return crypto.Address{}, false, err
+	}
+	from := common.Address(crypto.PubkeyToAddress(key.PublicKey))
+	deployer := common.HexToAddress(Create2DeployerAddress)
+	data := append(salt.Bytes(), initCode...)
+	gasFeeCap, gasTipCap, nonce, err := client.CalculateTxParams(from.Hex())
+	if err != nil {
+		return crypto.Address{}, false, err
+	}
+	gas, err := client.EstimateGasLimit(ethereum.CallMsg{From: from, To: &deployer, Data: data})
+	if err != nil {
+		return crypto.Address{}, false, err
+	}
+	chainID, err := client.GetChainID()
+	if err != nil {
+		return crypto.Address{}, false, err
+	}
+	tx, err := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		To:        &deployer,
+		Gas:       gas,
+		GasFeeCap: gasFeeCap,
+		GasTipCap: gasTipCap,
+		Data:      data,
+	}), types.LatestSignerForChainID(chainID), key)
+	if err != nil {
+		return crypto.Address{}, false, err
+	}
+	if err := client.SendTransaction(tx); err != nil {
+		return crypto.Address{}, false, err
+	}
+	if _, success, err := client.WaitForTransaction(tx); err != nil {
+		return crypto.Address{}, false, err
+	} else if !success {
+		return crypto.Address{}, false, ErrFailedReceiptStatus
+	}
+	return address, true, nil
+}
+
+// DeployPaymaster deploys a paymaster taking the EntryPoint and the signer
+// of its sponsorships as constructor arguments, as the v0.7 verifying
+// paymaster does
+func DeployPaymaster(
+	rpcURL string,
+	privateKey string,
+	bin []byte,
+	entryPoint crypto.Address,
+	signer crypto.Address,
+) (crypto.Address, error) {
+	return DeployContract(
+		rpcURL,
+		privateKey,
+		[]byte(common.Bytes2Hex(bin)),
+		"(address, address)",
+		entryPoint,
+		signer,
+	)
+}
+
+// DeploySimplePaymaster deploys a paymaster sponsoring every user operation
+// its deposit in entryPoint pays for. Calls from owner are forwarded to the
+// EntryPoint, for the owner to withdraw the deposit or stake.
+func DeploySimplePaymaster(
+	rpcURL string,
+	privateKey string,
+	entryPoint crypto.Address,
+	owner crypto.Address,
+) (crypto.Address, error) {
+	return DeployContract(
+		rpcURL,
+		privateKey,
+		simplePaymasterBin,
+		"(address, address)",
+		entryPoint,
+		owner,
+	)
+}
+
+// GetEntryPointDeposit returns the deposit of account in entryPoint, which
+// pays for the user operations it sponsors
+func GetEntryPointDeposit(
+	rpcURL string,
+	entryPoint crypto.Address,
+	account crypto.Address,
+) (*big.Int, error) {
+	out, err := CallToMethod(
+		rpcURL,
+		entryPoint,
+		"balanceOf(address)->(uint256)",
+		account,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return GetSmartContractCallResult[*big.Int]("balanceOf", out)
+}
+
+// DepositToEntryPoint adds amount to the deposit of account in entryPoint
+func DepositToEntryPoint(
+	rpcURL string,
+	privateKey string,
+	entryPoint crypto.Address,
+	account crypto.Address,
+	amount *big.Int,
+) error {
+	_, _, err := TxToMethod(
+		rpcURL,
+		false,
+		crypto.Address{},
+		privateKey,
+		entryPoint,
+		amount,
+		"deposit to entry point",
+		nil,
+		"depositTo(address)",
+		account,
+	)
+	return err
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return len(s)%2 == 0
+}
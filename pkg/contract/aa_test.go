@@ -0,0 +1,51 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package contract
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadBytecode(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	tests := map[string]string{
+		"hex":     "0x6080604052\n",
+		"hardhat": `{"contractName": "EntryPoint", "bytecode": "0x6080604052"}`,
+		"foundry": `{"bytecode": {"object": "0x6080604052", "linkReferences": {}}}`,
+	}
+	for name, content := range tests {
+		path := filepath.Join(dir, name)
+		require.NoError(os.WriteFile(path, []byte(content), 0o600))
+		code, err := ReadBytecode(path)
+		require.NoError(err, name)
+		require.Equal([]byte{0x60, 0x80, 0x60, 0x40, 0x52}, code, name)
+	}
+
+	for name, content := range map[string]string{
+		"empty":       "",
+		"not hex":     "0x60zz",
+		"no bytecode": `{"abi": []}`,
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(os.WriteFile(path, []byte(content), 0o600))
+		_, err := ReadBytecode(path)
+		require.Error(err, name)
+	}
+}
+
+func TestSimplePaymasterBin(t *testing.T) {
+	require := require.New(t)
+	code := common.FromHex(string(simplePaymasterBin))
+	// the constructor returns the runtime following it
+	const constructorSize, runtimeSize = 0x21, 0x4c
+	require.Len(code, constructorSize+runtimeSize)
+	// validatePaymasterUserOp of the v0.7 IPaymaster
+	require.Contains(string(code[constructorSize:]), string([]byte{0x63, 0x52, 0xb7, 0x51, 0x2c}))
+}
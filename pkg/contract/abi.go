@@ -0,0 +1,496 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package contract
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/luxfi/evm/accounts/abi"
+	"github.com/luxfi/evm/accounts/abi/bind"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/common/hexutil"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/sdk/evm"
+)
+
+// LoadABI reads a contract ABI file: a JSON ABI, or the JSON artifact of
+// hardhat or foundry
+func LoadABI(path string) (*abi.ABI, error) {
+	raw, err := os.ReadFile(path) //nolint:gosec // G304: Reading user provided ABI
+	if err != nil {
+		return nil, err
+	}
+	raw = bytes.TrimSpace(raw)
+	if bytes.HasPrefix(raw, []byte("{")) {
+		var artifact struct {
+			ABI json.RawMessage `json:"abi"`
+		}
+		if err := json.Unmarshal(raw, &artifact); err != nil || len(artifact.ABI) == 0 {
+			return nil, fmt.Errorf("artifact %s has no abi", path)
+		}
+		raw = artifact.ABI
+	}
+	contractABI, err := abi.JSON(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ABI %s: %w", path, err)
+	}
+	return &contractABI, nil
+}
+
+// GetMethod returns the method of a call given either as a signature, like
+// "transfer(address,uint256)" or "balanceOf(address)->(uint256)", or by
+// name from contractABI
+func GetMethod(contractABI *abi.ABI, method string) (*abi.ABI, abi.Method, error) {
+	if strings.Contains(method, "(") {
+		name, methodABI, err := signatureABI(method)
+		if err != nil {
+			return nil, abi.Method{}, fmt.Errorf("invalid method signature %q: %w", method, err)
+		}
+		contractABI = methodABI
+		method = name
+	}
+	if contractABI == nil {
+		return nil, abi.Method{}, fmt.Errorf("method %q needs an ABI: give its signature, like %s(address), or an ABI file", method, method)
+	}
+	m, ok := contractABI.Methods[method]
+	if !ok {
+		return nil, abi.Method{}, fmt.Errorf("the ABI has no method %q", method)
+	}
+	return contractABI, m, nil
+}
+
+// signatureABI returns the ABI of the method of a Solidity signature, its
+// outputs following "->" or "returns"
+func signatureABI(signature string) (string, *abi.ABI, error) {
+	signature = strings.TrimSpace(signature)
+	index := strings.Index(signature, "(")
+	name := strings.TrimSpace(signature[:index])
+	inputs, rest, err := splitParenthesis(signature[index:])
+	if err != nil {
+		return "", nil, err
+	}
+	rest = strings.TrimSpace(rest)
+	rest = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(rest, "->"), "returns"))
+	outputs := ""
+	if rest != "" {
+		if outputs, rest, err = splitParenthesis(rest); err != nil {
+			return "", nil, err
+		}
+		if strings.TrimSpace(rest) != "" {
+			return "", nil, fmt.Errorf("unexpected %q", rest)
+		}
+	}
+	inputArgs, err := signatureArgs(inputs)
+	if err != nil {
+		return "", nil, err
+	}
+	outputArgs, err := signatureArgs(outputs)
+	if err != nil {
+		return "", nil, err
+	}
+	spec, err := json.Marshal([]map[string]interface{}{{
+		"type":            "function",
+		"name":            name,
+		"inputs":          inputArgs,
+		"outputs":         outputArgs,
+		"stateMutability": "payable",
+	}})
+	if err != nil {
+		return "", nil, err
+	}
+	methodABI, err := abi.JSON(bytes.NewReader(spec))
+	if err != nil {
+		return "", nil, err
+	}
+	return name, &methodABI, nil
+}
+
+// splitParenthesis returns the content of the parenthesis s starts with,
+// and what follows them
+func splitParenthesis(s string) (string, string, error) {
+	if !strings.HasPrefix(s, "(") {
+		return "", "", fmt.Errorf("expected %q to start with (", s)
+	}
+	depth := 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return s[1:i], s[i+1:], nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("unbalanced parenthesis in %q", s)
+}
+
+// signatureArgs returns the ABI arguments of a comma separated list of
+// types, each optionally followed by a name. Tuples are parenthesized.
+func signatureArgs(s string) ([]map[string]interface{}, error) {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if strings.TrimSpace(s) != "" {
+		parts = append(parts, s[start:])
+	}
+	args := make([]map[string]interface{}, 0, len(parts))
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		arg := map[string]interface{}{}
+		if strings.HasPrefix(part, "(") {
+			inner, rest, err := splitParenthesis(part)
+			if err != nil {
+				return nil, err
+			}
+			components, err := signatureArgs(inner)
+			if err != nil {
+				return nil, err
+			}
+			// the ABI needs named tuple fields to build their Go types
+			for j, component := range components {
+				if component["name"] == "" {
+					component["name"] = fmt.Sprintf("field%d", j)
+				}
+			}
+			arg["components"] = components
+			part = "tuple" + strings.TrimSpace(rest)
+		}
+		fields := strings.Fields(part)
+		switch len(fields) {
+		case 1:
+			arg["type"], arg["name"] = fields[0], ""
+		case 2:
+			arg["type"], arg["name"] = fields[0], fields[1]
+		default:
+			return nil, fmt.Errorf("invalid argument %d: %q", i+1, part)
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+// ParseArgs converts the command line values of the inputs of a method to
+// the Go values the ABI packs. Arrays and tuples are given as JSON arrays.
+func ParseArgs(inputs abi.Arguments, values []string) ([]interface{}, error) {
+	if len(values) != len(inputs) {
+		return nil, fmt.Errorf("expected %d arguments, got %d", len(inputs), len(values))
+	}
+	args := make([]interface{}, 0, len(values))
+	for i, input := range inputs {
+		v, err := parseValue(input.Type, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %d (%s %s): %w", i+1, input.Type, input.Name, err)
+		}
+		args = append(args, v.Interface())
+	}
+	return args, nil
+}
+
+func parseValue(t abi.Type, raw interface{}) (reflect.Value, error) {
+	if t.T == abi.SliceTy || t.T == abi.ArrayTy || t.T == abi.TupleTy {
+		elems, err := parseList(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return parseComposite(t, elems)
+	}
+	var s string
+	switch x := raw.(type) {
+	case string:
+		s = strings.TrimSpace(x)
+	case json.Number:
+		s = x.String()
+	case bool:
+		s = strconv.FormatBool(x)
+	default:
+		return reflect.Value{}, fmt.Errorf("expected a %s, got %v", t, raw)
+	}
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		return parseInt(t, s)
+	case abi.BoolTy:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("expected true or false, got %q", s)
+		}
+		return reflect.ValueOf(b), nil
+	case abi.StringTy:
+		return reflect.ValueOf(s), nil
+	case abi.AddressTy:
+		if !common.IsHexAddress(s) {
+			return reflect.Value{}, fmt.Errorf("invalid address %q", s)
+		}
+		return reflect.ValueOf(common.HexToAddress(s)), nil
+	case abi.BytesTy, abi.FixedBytesTy:
+		b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("expected hex bytes, got %q", s)
+		}
+		if t.T == abi.BytesTy {
+			return reflect.ValueOf(b), nil
+		}
+		if len(b) != t.Size {
+			return reflect.Value{}, fmt.Errorf("expected %d bytes, got %d", t.Size, len(b))
+		}
+		v := reflect.New(t.GetType()).Elem()
+		reflect.Copy(v, reflect.ValueOf(b))
+		return v, nil
+	}
+	return reflect.Value{}, fmt.Errorf("type %s is not supported", t)
+}
+
+func parseList(raw interface{}) ([]interface{}, error) {
+	switch x := raw.(type) {
+	case []interface{}:
+		return x, nil
+	case string:
+		decoder := json.NewDecoder(strings.NewReader(x))
+		decoder.UseNumber()
+		var elems []interface{}
+		if err := decoder.Decode(&elems); err != nil {
+			return nil, fmt.Errorf("expected a JSON array, like [\"0x...\", 1], got %q", x)
+		}
+		return elems, nil
+	}
+	return nil, fmt.Errorf("expected an array, got %v", raw)
+}
+
+func parseComposite(t abi.Type, elems []interface{}) (reflect.Value, error) {
+	var v reflect.Value
+	switch t.T {
+	case abi.SliceTy:
+		v = reflect.MakeSlice(t.GetType(), len(elems), len(elems))
+	case abi.ArrayTy:
+		if len(elems) != t.Size {
+			return reflect.Value{}, fmt.Errorf("expected %d elements, got %d", t.Size, len(elems))
+		}
+		v = reflect.New(t.GetType()).Elem()
+	case abi.TupleTy:
+		if len(elems) != len(t.TupleElems) {
+			return reflect.Value{}, fmt.Errorf("expected %d tuple fields, got %d", len(t.TupleElems), len(elems))
+		}
+		v = reflect.New(t.GetType()).Elem()
+		for i, elem := range elems {
+			field, err := parseValue(*t.TupleElems[i], elem)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("field %d: %w", i+1, err)
+			}
+			v.Field(i).Set(field)
+		}
+		return v, nil
+	}
+	for i, elem := range elems {
+		e, err := parseValue(*t.Elem, elem)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("element %d: %w", i+1, err)
+		}
+		v.Index(i).Set(e)
+	}
+	return v, nil
+}
+
+func parseInt(t abi.Type, s string) (reflect.Value, error) {
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("invalid integer %q", s)
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(t.Size)) //nolint:gosec // G115: ABI sizes are at most 256
+	if t.T == abi.IntTy {
+		limit.Rsh(limit, 1)
+	}
+	switch {
+	case t.T == abi.UintTy && n.Sign() < 0:
+		return reflect.Value{}, fmt.Errorf("%s is negative", s)
+	case n.Cmp(limit) >= 0, new(big.Int).Neg(n).Cmp(limit) > 0:
+		return reflect.Value{}, fmt.Errorf("%s overflows %s", s, t)
+	}
+	typ := t.GetType()
+	if typ.Kind() == reflect.Ptr {
+		return reflect.ValueOf(n), nil
+	}
+	v := reflect.New(typ).Elem()
+	if t.T == abi.UintTy {
+		v.SetUint(n.Uint64())
+	} else {
+		v.SetInt(n.Int64())
+	}
+	return v, nil
+}
+
+// FormatValue prints a value unpacked from the ABI: addresses and bytes as
+// hex, arrays as [a, b] and tuples as (a, b)
+func FormatValue(value interface{}) string {
+	switch x := value.(type) {
+	case common.Address:
+		return x.Hex()
+	case *big.Int:
+		return x.String()
+	case []byte:
+		return hexutil.Encode(x)
+	case string:
+		return x
+	case nil:
+		return ""
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			for i := range b {
+				b[i] = byte(v.Index(i).Uint())
+			}
+			return hexutil.Encode(b)
+		}
+		elems := make([]string, v.Len())
+		for i := range elems {
+			elems[i] = FormatValue(v.Index(i).Interface())
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	case reflect.Struct:
+		fields := make([]string, v.NumField())
+		for i := range fields {
+			fields[i] = FormatValue(v.Field(i).Interface())
+		}
+		return "(" + strings.Join(fields, ", ") + ")"
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return FormatValue(v.Elem().Interface())
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// NamedValue is a formatted argument of a decoded event
+type NamedValue struct {
+	Name  string
+	Value string
+}
+
+// DecodedLog is an event log decoded with a contract ABI
+type DecodedLog struct {
+	Address common.Address
+	// Event is the event signature, empty when the ABI doesn't have it
+	Event string
+	Args  []NamedValue
+	// Raw is the log data of events the ABI doesn't have, or fails to
+	// decode
+	Raw string
+}
+
+// DecodeLogs decodes logs with the events of contractABI
+func DecodeLogs(contractABI *abi.ABI, logs []*types.Log) []DecodedLog {
+	decoded := make([]DecodedLog, 0, len(logs))
+	for _, log := range logs {
+		d := DecodedLog{Address: log.Address, Raw: hexutil.Encode(log.Data)}
+		if len(log.Topics) == 0 || contractABI == nil {
+			decoded = append(decoded, d)
+			continue
+		}
+		event, err := contractABI.EventByID(log.Topics[0])
+		if err != nil {
+			decoded = append(decoded, d)
+			continue
+		}
+		values := map[string]interface{}{}
+		var indexed abi.Arguments
+		for _, input := range event.Inputs {
+			if input.Indexed {
+				indexed = append(indexed, input)
+			}
+		}
+		if event.Inputs.UnpackIntoMap(values, log.Data) != nil ||
+			abi.ParseTopicsIntoMap(values, indexed, log.Topics[1:]) != nil {
+			decoded = append(decoded, d)
+			continue
+		}
+		d.Event, d.Raw = event.Sig, ""
+		for _, input := range event.Inputs {
+			d.Args = append(d.Args, NamedValue{Name: input.Name, Value: FormatValue(values[input.Name])})
+		}
+		decoded = append(decoded, d)
+	}
+	return decoded
+}
+
+// CallMethod calls the read-only method of the contract at contractAddress
+// and returns its unpacked outputs
+func CallMethod(
+	rpcURL string,
+	contractAddress common.Address,
+	contractABI *abi.ABI,
+	method string,
+	args ...interface{},
+) ([]interface{}, error) {
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	contract := bind.NewBoundContract(contractAddress, *contractABI, client.EthClient, client.EthClient, client.EthClient)
+	var out []interface{}
+	if err := contract.Call(&bind.CallOpts{}, &out, method, args...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SendMethod signs a transaction calling method of the contract at
+// contractAddress with privateKey, sending value with it, and waits for its
+// receipt
+func SendMethod(
+	rpcURL string,
+	privateKey string,
+	contractAddress common.Address,
+	contractABI *abi.ABI,
+	value *big.Int,
+	method string,
+	args ...interface{},
+) (*types.Transaction, *types.Receipt, error) {
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer client.Close()
+	txOpts, err := client.GetTxOptsWithSigner(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	txOpts.Value = value
+	contract := bind.NewBoundContract(contractAddress, *contractABI, client.EthClient, client.EthClient, client.EthClient)
+	tx, err := contract.Transact(txOpts, method, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	receipt, success, err := client.WaitForTransaction(tx)
+	if err != nil {
+		return tx, nil, err
+	}
+	if !success {
+		return tx, receipt, ErrFailedReceiptStatus
+	}
+	return tx, receipt, nil
+}
@@ -0,0 +1,147 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package contract
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+const testERC20ABI = `[
+	{"type": "function", "name": "transfer", "stateMutability": "nonpayable",
+	 "inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}],
+	 "outputs": [{"name": "", "type": "bool"}]},
+	{"type": "event", "name": "Transfer", "anonymous": false,
+	 "inputs": [{"name": "from", "type": "address", "indexed": true},
+	            {"name": "to", "type": "address", "indexed": true},
+	            {"name": "value", "type": "uint256", "indexed": false}]}
+]`
+
+func TestLoadABI(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"abi.json":      testERC20ABI,
+		"artifact.json": `{"contractName": "Token", "abi": ` + testERC20ABI + `}`,
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(os.WriteFile(path, []byte(content), 0o600))
+		contractABI, err := LoadABI(path)
+		require.NoError(err, name)
+		require.Contains(contractABI.Methods, "transfer", name)
+		require.Contains(contractABI.Events, "Transfer", name)
+	}
+	path := filepath.Join(dir, "bytecode.json")
+	require.NoError(os.WriteFile(path, []byte(`{"bytecode": "0x6080"}`), 0o600))
+	_, err := LoadABI(path)
+	require.ErrorContains(err, "has no abi")
+}
+
+func TestGetMethod(t *testing.T) {
+	require := require.New(t)
+	_, m, err := GetMethod(nil, "balanceOf(address)->(uint256)")
+	require.NoError(err)
+	require.Equal("balanceOf(address)", m.Sig)
+	require.Len(m.Outputs, 1)
+
+	_, m, err = GetMethod(nil, "transfer(address,uint256)")
+	require.NoError(err)
+	require.Equal([]byte{0xa9, 0x05, 0x9c, 0xbb}, m.ID)
+
+	_, m, err = GetMethod(nil, "submit((uint8,address)[] items, uint256 nonce) returns (bool ok)")
+	require.NoError(err)
+	require.Equal("submit((uint8,address)[],uint256)", m.Sig)
+	require.Equal("ok", m.Outputs[0].Name)
+
+	_, _, err = GetMethod(nil, "transfer")
+	require.ErrorContains(err, "needs an ABI")
+	_, _, err = GetMethod(nil, "transfer(address,uint256")
+	require.ErrorContains(err, "unbalanced parenthesis")
+}
+
+func TestParseArgs(t *testing.T) {
+	require := require.New(t)
+	_, m, err := GetMethod(nil, "f(address,uint256,int8,bool,string,bytes,bytes2,uint64[],(uint8,address))")
+	require.NoError(err)
+	addr := "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"
+	args, err := ParseArgs(m.Inputs, []string{
+		addr, "0x10", "-128", "true", "hello", "0xabcd", "0x0102", "[1, 2]", `[7, "` + addr + `"]`,
+	})
+	require.NoError(err)
+	require.Equal(common.HexToAddress(addr), args[0])
+	require.Equal(big.NewInt(16), args[1])
+	require.Equal(int8(-128), args[2])
+	require.Equal(true, args[3])
+	require.Equal("hello", args[4])
+	require.Equal([]byte{0xab, 0xcd}, args[5])
+	require.Equal([2]byte{1, 2}, args[6])
+	require.Equal([]uint64{1, 2}, args[7])
+	require.Equal("(7, "+addr+")", FormatValue(args[8]))
+
+	// the arguments pack
+	_, err = m.Inputs.Pack(args...)
+	require.NoError(err)
+
+	for _, values := range [][]string{
+		{"0x1234", "1", "0", "true", "", "0x", "0x0102", "[]", `[0, "` + addr + `"]`},
+		{addr, "-1", "0", "true", "", "0x", "0x0102", "[]", `[0, "` + addr + `"]`},
+		{addr, "1", "128", "true", "", "0x", "0x0102", "[]", `[0, "` + addr + `"]`},
+		{addr, "1", "0", "yes?", "", "0x", "0x0102", "[]", `[0, "` + addr + `"]`},
+		{addr, "1", "0", "true", "", "0x", "0x01", "[]", `[0, "` + addr + `"]`},
+		{addr, "1", "0", "true", "", "0x", "0x0102", "1,2", `[0, "` + addr + `"]`},
+		{addr, "1", "0", "true", "", "0x", "0x0102", "[]", `[256, "` + addr + `"]`},
+		{addr},
+	} {
+		_, err := ParseArgs(m.Inputs, values)
+		require.Error(err, values)
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	require := require.New(t)
+	require.Equal("1000", FormatValue(big.NewInt(1000)))
+	require.Equal("0x0102", FormatValue([]byte{1, 2}))
+	require.Equal("0x0102", FormatValue([2]byte{1, 2}))
+	require.Equal("[1, 2]", FormatValue([]uint64{1, 2}))
+	require.Equal("true", FormatValue(true))
+	require.Equal("[1, 2]", FormatValue([]*big.Int{big.NewInt(1), big.NewInt(2)}))
+}
+
+func TestDecodeLogs(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "abi.json")
+	require.NoError(os.WriteFile(path, []byte(testERC20ABI), 0o600))
+	contractABI, err := LoadABI(path)
+	require.NoError(err)
+
+	from := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	token := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	data := common.LeftPadBytes(big.NewInt(5).Bytes(), 32)
+	logs := []*types.Log{
+		{
+			Address: token,
+			Topics:  []common.Hash{contractABI.Events["Transfer"].ID, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+			Data:    data,
+		},
+		{Address: token, Topics: []common.Hash{{0x01}}, Data: []byte{0xff}},
+	}
+	decoded := DecodeLogs(contractABI, logs)
+	require.Len(decoded, 2)
+	require.Equal("Transfer(address,address,uint256)", decoded[0].Event)
+	require.Equal([]NamedValue{
+		{Name: "from", Value: from.Hex()},
+		{Name: "to", Value: to.Hex()},
+		{Name: "value", Value: "5"},
+	}, decoded[0].Args)
+	require.Empty(decoded[1].Event)
+	require.Equal("0xff", decoded[1].Raw)
+}
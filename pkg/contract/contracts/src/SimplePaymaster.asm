@@ -0,0 +1,96 @@
+// (c) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// SimplePaymaster is an ERC-4337 v0.7 paymaster sponsoring every user
+// operation, for development chains. It is hand assembled to
+// ../bin/SimplePaymaster.bin, as the bytecode below.
+//
+// Storage: slot 0 is the EntryPoint, slot 1 the owner.
+//
+// validatePaymasterUserOp accepts any operation: it returns an empty context,
+// so postOp is never called, and a validation data of 0, and reads no storage
+// so the paymaster needs no stake. Any other call from the owner is forwarded
+// with its value to the EntryPoint, for the owner to withdrawTo, addStake or,
+// with no calldata, deposit for the paymaster. Other calls revert.
+
+// constructor(address entryPoint, address owner)
+00  6040        PUSH1 0x40              // size of the constructor arguments
+02  6040        PUSH1 0x40
+04  38          CODESIZE
+05  03          SUB                     // arguments are the last 64 bytes of code
+06  6000        PUSH1 0x00
+08  39          CODECOPY                // mem[0:64] = entryPoint, owner
+09  6000        PUSH1 0x00
+0b  51          MLOAD
+0c  6000        PUSH1 0x00
+0e  55          SSTORE                  // slot 0 = entryPoint
+0f  6020        PUSH1 0x20
+11  51          MLOAD
+12  6001        PUSH1 0x01
+14  55          SSTORE                  // slot 1 = owner
+15  604c        PUSH1 0x4c              // runtime size
+17  6021        PUSH1 0x21              // runtime offset
+19  6000        PUSH1 0x00
+1b  39          CODECOPY
+1c  604c        PUSH1 0x4c
+1e  6000        PUSH1 0x00
+20  f3          RETURN
+
+// runtime
+00  6000        PUSH1 0x00
+02  35          CALLDATALOAD
+03  60e0        PUSH1 0xe0
+05  1c          SHR                     // selector
+06  6352b7512c  PUSH4 0x52b7512c        // validatePaymasterUserOp((address,uint256,bytes,bytes,bytes32,uint256,bytes32,bytes,bytes),bytes32,uint256)
+0b  14          EQ
+0c  6041        PUSH1 validate
+0e  57          JUMPI
+0f  6001        PUSH1 0x01
+11  54          SLOAD
+12  33          CALLER
+13  14          EQ
+14  601b        PUSH1 forward
+16  57          JUMPI
+17  6000        PUSH1 0x00
+19  80          DUP1
+1a  fd          REVERT                  // not the owner
+
+forward:
+1b  5b          JUMPDEST
+1c  36          CALLDATASIZE
+1d  6000        PUSH1 0x00
+1f  6000        PUSH1 0x00
+21  37          CALLDATACOPY            // mem[0:] = calldata
+22  6000        PUSH1 0x00              // retSize
+24  6000        PUSH1 0x00              // retOffset
+26  36          CALLDATASIZE            // argsSize
+27  6000        PUSH1 0x00              // argsOffset
+29  34          CALLVALUE
+2a  6000        PUSH1 0x00
+2c  54          SLOAD                   // entryPoint
+2d  5a          GAS
+2e  f1          CALL
+2f  3d          RETURNDATASIZE
+30  6000        PUSH1 0x00
+32  6000        PUSH1 0x00
+34  3e          RETURNDATACOPY
+35  603c        PUSH1 forwarded
+37  57          JUMPI
+38  3d          RETURNDATASIZE
+39  6000        PUSH1 0x00
+3b  fd          REVERT                  // bubble the EntryPoint revert
+
+forwarded:
+3c  5b          JUMPDEST
+3d  3d          RETURNDATASIZE
+3e  6000        PUSH1 0x00
+40  f3          RETURN
+
+validate:
+41  5b          JUMPDEST
+42  6040        PUSH1 0x40
+44  6000        PUSH1 0x00
+46  52          MSTORE                  // offset of the context
+47  6060        PUSH1 0x60              // (offset, validationData = 0, context length = 0)
+49  6000        PUSH1 0x00
+4b  f3          RETURN
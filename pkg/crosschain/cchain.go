@@ -0,0 +1,249 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crosschain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/luxfi/address"
+	apitypes "github.com/luxfi/api/types"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/coreth/plugin/evm/atomic"
+	"github.com/luxfi/formatting"
+	gethcommon "github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/ethclient"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/rpc"
+	sdkinfo "github.com/luxfi/sdk/info"
+	"github.com/luxfi/sdk/platformvm"
+	"github.com/luxfi/sdk/wallet/chain/c"
+	"github.com/luxfi/utxo"
+	"github.com/luxfi/utxo/secp256k1fx"
+)
+
+const (
+	// utxoPageSize is the number of atomic UTXOs fetched per lux.getUTXOs
+	// call
+	utxoPageSize = 1024
+
+	// pollInterval is how often the status of an issued atomic tx is checked
+	pollInterval = 500 * time.Millisecond
+)
+
+var ErrTxNotAccepted = errors.New("transaction not accepted")
+
+// cChain builds, signs and issues the atomic txs of keys on the C-Chain,
+// through the lux API of a node
+type cChain struct {
+	requester rpc.EndpointRequester
+	context   *c.Context
+	eth       *ethclient.Client
+	keys      Keys
+	// utxos are the UTXOs of keys exported to the C-Chain, by source chain
+	utxos map[ids.ID]map[ids.ID]*utxo.UTXO
+}
+
+func newCChain(ctx context.Context, baseURL string, keys Keys) (*cChain, error) {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	luxAssetID, err := platformvm.NewClient(baseURL).GetStakingAssetID(ctx, constants.PrimaryNetworkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the LUX asset ID: %w", err)
+	}
+	cctx, err := c.NewContextFromClients(ctx, sdkinfo.NewClient(baseURL), luxAssetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the C-Chain context: %w", err)
+	}
+	eth, err := ethclient.DialContext(ctx, baseURL+"/ext/bc/"+c.Alias+"/rpc")
+	if err != nil {
+		return nil, err
+	}
+	return &cChain{
+		requester: rpc.NewEndpointRequester(baseURL + "/ext/bc/" + c.Alias + "/lux"),
+		context:   cctx,
+		eth:       eth,
+		keys:      keys,
+		utxos:     map[ids.ID]map[ids.ID]*utxo.UTXO{},
+	}, nil
+}
+
+// loadUTXOs fetches the UTXOs of the keys exported to the C-Chain by source
+func (ch *cChain) loadUTXOs(ctx context.Context, source Chain) error {
+	hrp := constants.GetHRP(ch.context.NetworkID)
+	var addrs []string
+	for addr := range ch.keys.Keychain.Addresses() {
+		s, err := address.Format(string(source), hrp, addr.Bytes())
+		if err != nil {
+			return err
+		}
+		addrs = append(addrs, s)
+	}
+	utxos := map[ids.ID]*utxo.UTXO{}
+	var start apitypes.Index
+	for {
+		reply := &apitypes.GetUTXOsReply{}
+		if err := ch.requester.SendRequest(ctx, "lux.getUTXOs", &apitypes.GetUTXOsArgs{
+			Addresses:   addrs,
+			SourceChain: string(source),
+			Limit:       utxoPageSize,
+			StartIndex:  start,
+			Encoding:    formatting.Hex,
+		}, reply); err != nil {
+			return fmt.Errorf("failed to get the UTXOs exported to the C-Chain: %w", err)
+		}
+		for _, s := range reply.UTXOs {
+			b, err := formatting.Decode(formatting.Hex, s)
+			if err != nil {
+				return fmt.Errorf("failed to decode UTXO: %w", err)
+			}
+			u := &utxo.UTXO{}
+			if _, err := atomic.Codec.Unmarshal(b, u); err != nil {
+				return fmt.Errorf("failed to parse UTXO: %w", err)
+			}
+			utxos[u.InputID()] = u
+		}
+		if len(reply.UTXOs) < utxoPageSize {
+			break
+		}
+		start = reply.EndIndex
+	}
+	ch.utxos[source.ID()] = utxos
+	return nil
+}
+
+// UTXOs implements c.BuilderBackend
+func (ch *cChain) UTXOs(_ context.Context, sourceChainID ids.ID) ([]*utxo.UTXO, error) {
+	utxos := make([]*utxo.UTXO, 0, len(ch.utxos[sourceChainID]))
+	for _, u := range ch.utxos[sourceChainID] {
+		utxos = append(utxos, u)
+	}
+	return utxos, nil
+}
+
+// Balance implements c.BuilderBackend
+func (ch *cChain) Balance(ctx context.Context, addr gethcommon.Address) (*big.Int, error) {
+	return ch.eth.BalanceAt(ctx, addr, nil)
+}
+
+// Nonce implements c.BuilderBackend
+func (ch *cChain) Nonce(ctx context.Context, addr gethcommon.Address) (uint64, error) {
+	return ch.eth.PendingNonceAt(ctx, addr)
+}
+
+// GetUTXO implements c.SignerBackend
+func (ch *cChain) GetUTXO(_ context.Context, chainID, utxoID ids.ID) (*utxo.UTXO, error) {
+	u, ok := ch.utxos[chainID][utxoID]
+	if !ok {
+		return nil, fmt.Errorf("UTXO %s not found", utxoID)
+	}
+	return u, nil
+}
+
+func (ch *cChain) builder() c.Builder {
+	return c.NewBuilder(ch.keys.Keychain.Addresses(), ethKeychain(ch.keys).EthAddresses(), ch.context, ch)
+}
+
+// baseFee returns the base fee of the last block, or the suggested gas
+// price before London
+func (ch *cChain) baseFee(ctx context.Context) (*big.Int, error) {
+	header, err := ch.eth.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if header.BaseFee != nil {
+		return header.BaseFee, nil
+	}
+	return ch.eth.SuggestGasPrice(ctx)
+}
+
+// issue signs utx, issues it and waits for it to be accepted
+func (ch *cChain) issue(ctx context.Context, utx c.UnsignedAtomicTx) (ids.ID, error) {
+	tx, err := c.SignUnsignedAtomic(ctx, c.NewSigner(ch.keys.Keychain, ethKeychain(ch.keys), ch), utx)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("failed to sign tx: %w", err)
+	}
+	encoded, err := formatting.Encode(formatting.Hex, tx.SignedBytes())
+	if err != nil {
+		return ids.Empty, err
+	}
+	reply := &apitypes.JSONTxID{}
+	if err := ch.requester.SendRequest(ctx, "lux.issueTx", &apitypes.FormattedTx{
+		Tx:       encoded,
+		Encoding: formatting.Hex,
+	}, reply); err != nil {
+		return ids.Empty, fmt.Errorf("failed to issue tx: %w", err)
+	}
+	return reply.TxID, ch.awaitTxAccepted(ctx, reply.TxID)
+}
+
+// awaitTxAccepted polls the status of the atomic tx txID until it is
+// accepted, rejected or ctx is done
+func (ch *cChain) awaitTxAccepted(ctx context.Context, txID ids.ID) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		reply := &struct {
+			Status string `json:"status"`
+		}{}
+		err := ch.requester.SendRequest(ctx, "lux.getAtomicTxStatus", &apitypes.JSONTxID{TxID: txID}, reply)
+		if err == nil {
+			switch reply.Status {
+			case "Accepted":
+				return nil
+			case "Dropped", "Rejected":
+				return fmt.Errorf("%w: %s is %s", ErrTxNotAccepted, txID, reply.Status)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("%w: %s: %w", ErrTxNotAccepted, txID, err)
+			}
+			return fmt.Errorf("%w: %s is %s", ErrTxNotAccepted, txID, reply.Status)
+		case <-ticker.C:
+		}
+	}
+}
+
+func exportFromC(ctx context.Context, baseURL string, keys Keys, to Chain, amount uint64, owner *secp256k1fx.OutputOwners) (ids.ID, error) {
+	ch, err := newCChain(ctx, baseURL, keys)
+	if err != nil {
+		return ids.Empty, err
+	}
+	baseFee, err := ch.baseFee(ctx)
+	if err != nil {
+		return ids.Empty, err
+	}
+	utx, err := ch.builder().NewExportTx(to.ID(), []*secp256k1fx.TransferOutput{{
+		Amt:          amount,
+		OutputOwners: *owner,
+	}}, baseFee)
+	if err != nil {
+		return ids.Empty, err
+	}
+	return ch.issue(ctx, utx)
+}
+
+func importToC(ctx context.Context, baseURL string, keys Keys, from Chain, recipient gethcommon.Address) (ids.ID, error) {
+	ch, err := newCChain(ctx, baseURL, keys)
+	if err != nil {
+		return ids.Empty, err
+	}
+	if err := ch.loadUTXOs(ctx, from); err != nil {
+		return ids.Empty, err
+	}
+	baseFee, err := ch.baseFee(ctx)
+	if err != nil {
+		return ids.Empty, err
+	}
+	utx, err := ch.builder().NewImportTx(from.ID(), recipient, baseFee)
+	if err != nil {
+		return ids.Empty, err
+	}
+	return ch.issue(ctx, utx)
+}
@@ -0,0 +1,176 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package crosschain moves LUX between the P-Chain, X-Chain and C-Chain of
+// the primary network: an ExportTx on the source chain puts the amount in
+// the shared memory of the destination chain, and an ImportTx there spends
+// it to the recipient.
+package crosschain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/key"
+	"github.com/luxfi/constants"
+	gethcommon "github.com/luxfi/geth/common"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/keychain"
+	"github.com/luxfi/math/set"
+	"github.com/luxfi/sdk/wallet/chain/c"
+	"github.com/luxfi/sdk/wallet/primary"
+	"github.com/luxfi/utxo/secp256k1fx"
+)
+
+// Chain is a chain of the primary network LUX is transferred between
+type Chain string
+
+const (
+	P Chain = "P"
+	X Chain = "X"
+	C Chain = "C"
+)
+
+var ErrNoEVMKey = errors.New("exports from the C-Chain are signed by an EVM key, which a ledger does not provide")
+
+// ParseChain returns the chain of s: p, x or c, with an optional -chain
+// suffix, in any case
+func ParseChain(s string) (Chain, error) {
+	switch Chain(strings.TrimSuffix(strings.ToUpper(s), "-CHAIN")) {
+	case P:
+		return P, nil
+	case X:
+		return X, nil
+	case C:
+		return C, nil
+	}
+	return "", fmt.Errorf("unknown chain %q, use P, X or C", s)
+}
+
+// ID returns the blockchain ID of the chain
+func (ch Chain) ID() ids.ID {
+	switch ch {
+	case P:
+		return constants.PlatformChainID
+	case X:
+		return constants.XChainID
+	case C:
+		return constants.CChainID
+	}
+	return ids.Empty
+}
+
+// Keys signs the txs of a transfer
+type Keys struct {
+	// Keychain signs the UTXOs of the P-Chain and X-Chain, and the atomic
+	// UTXOs imported by the C-Chain. It is a soft key or a ledger.
+	Keychain keychain.Keychain
+	// Eth signs the exports of the C-Chain, nil for a ledger
+	Eth c.EthKeychain
+	// Owner is the address of Keychain owning the exported UTXOs
+	Owner ids.ShortID
+}
+
+// SoftKeys returns the keys of sk, which signs every tx of a transfer
+func SoftKeys(sk *key.SoftKey) Keys {
+	adapter := primary.NewKeychainAdapter(secp256k1fx.NewKeychain(sk.Key()))
+	return Keys{Keychain: adapter, Eth: adapter, Owner: sk.Key().PublicKey().Address()}
+}
+
+// Transfer is an amount of LUX moved from a chain to another
+type Transfer struct {
+	From Chain
+	To   Chain
+	// Amount is in nLUX. The fee of the import is paid from it.
+	Amount uint64
+	// Recipient receives the LUX imported by the P-Chain or X-Chain
+	Recipient ids.ShortID
+	// EVMRecipient receives the LUX imported by the C-Chain
+	EVMRecipient gethcommon.Address
+}
+
+// Result are the txs of a transfer, both accepted
+type Result struct {
+	ExportTxID ids.ID
+	ImportTxID ids.ID
+}
+
+// Execute exports t.Amount from t.From and imports it to t.To, through the
+// node of baseURL, waiting for each tx to be accepted. When the import
+// fails, the exported LUX stays in the shared memory of t.To and is
+// imported by the next transfer to that chain, or by Import.
+func Execute(ctx context.Context, baseURL string, keys Keys, t Transfer) (Result, error) {
+	if t.From == t.To {
+		return Result{}, fmt.Errorf("the source and destination chains are both the %s-Chain", t.From)
+	}
+	if t.Amount == 0 {
+		return Result{}, errors.New("the amount must be positive")
+	}
+	if t.From == C && keys.Eth == nil {
+		return Result{}, ErrNoEVMKey
+	}
+	owner := &secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{keys.Owner}}
+
+	var (
+		result Result
+		err    error
+	)
+	switch t.From {
+	case P:
+		result.ExportTxID, err = exportFromP(ctx, baseURL, keys, t.To, t.Amount, owner)
+	case X:
+		result.ExportTxID, err = exportFromX(ctx, baseURL, keys, t.To, t.Amount, owner)
+	case C:
+		result.ExportTxID, err = exportFromC(ctx, baseURL, keys, t.To, t.Amount, owner)
+	default:
+		return result, fmt.Errorf("unknown chain %q", t.From)
+	}
+	if err != nil {
+		return result, fmt.Errorf("%s-Chain export failed: %w", t.From, err)
+	}
+	result.ImportTxID, err = Import(ctx, baseURL, keys, t)
+	return result, err
+}
+
+// Import imports to t.To all the UTXOs of keys exported by t.From, the
+// amount of t being ignored
+func Import(ctx context.Context, baseURL string, keys Keys, t Transfer) (ids.ID, error) {
+	var (
+		txID ids.ID
+		err  error
+	)
+	switch t.To {
+	case P:
+		txID, err = importToP(ctx, baseURL, keys, t.From, t.Recipient)
+	case X:
+		txID, err = importToX(ctx, baseURL, keys, t.From, t.Recipient)
+	case C:
+		txID, err = importToC(ctx, baseURL, keys, t.From, t.EVMRecipient)
+	default:
+		return ids.Empty, fmt.Errorf("unknown chain %q", t.To)
+	}
+	if err != nil {
+		return txID, fmt.Errorf("%s-Chain import failed: %w", t.To, err)
+	}
+	return txID, nil
+}
+
+// noEthKeychain is the EVM keychain of a ledger, which only signs UTXOs
+type noEthKeychain struct{}
+
+func (noEthKeychain) GetEth(gethcommon.Address) (keychain.Signer, bool) {
+	return nil, false
+}
+
+func (noEthKeychain) EthAddresses() set.Set[gethcommon.Address] {
+	return set.NewSet[gethcommon.Address](0)
+}
+
+func ethKeychain(keys Keys) c.EthKeychain {
+	if keys.Eth == nil {
+		return noEthKeychain{}
+	}
+	return keys.Eth
+}
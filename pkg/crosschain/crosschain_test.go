@@ -0,0 +1,58 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crosschain
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/luxfi/constants"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChain(t *testing.T) {
+	require := require.New(t)
+	for s, want := range map[string]Chain{"p": P, "X": X, "c-chain": C, "P-Chain": P} {
+		ch, err := ParseChain(s)
+		require.NoError(err)
+		require.Equal(want, ch)
+	}
+	_, err := ParseChain("q")
+	require.ErrorContains(err, "unknown chain")
+	require.Equal(constants.CChainID, C.ID())
+
+	_, err = Execute(context.Background(), "", Keys{}, Transfer{From: P, To: P, Amount: 1})
+	require.ErrorContains(err, "both the P-Chain")
+	_, err = Execute(context.Background(), "", Keys{}, Transfer{From: C, To: X, Amount: 1})
+	require.ErrorIs(err, ErrNoEVMKey)
+}
+
+func TestAwaitTxAccepted(t *testing.T) {
+	require := require.New(t)
+	statuses := []string{"Processing", "Accepted", "Dropped"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		require.NoError(json.NewDecoder(r.Body).Decode(&req))
+		require.Equal("lux.getAtomicTxStatus", req.Method)
+		status := statuses[0]
+		statuses = statuses[1:]
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": map[string]string{"status": status}})
+	}))
+	defer server.Close()
+
+	ch := &cChain{requester: rpc.NewEndpointRequester(server.URL)}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(ch.awaitTxAccepted(ctx, ids.GenerateTestID()))
+	require.ErrorIs(ch.awaitTxAccepted(ctx, ids.GenerateTestID()), ErrTxNotAccepted)
+}
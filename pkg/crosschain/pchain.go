@@ -0,0 +1,74 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crosschain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/luxfi/constants"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/keychain"
+	ptxs "github.com/luxfi/protocol/p/txs"
+	"github.com/luxfi/sdk/wallet/chain/p"
+	pbuilder "github.com/luxfi/sdk/wallet/chain/p/builder"
+	psigner "github.com/luxfi/sdk/wallet/chain/p/signer"
+	"github.com/luxfi/sdk/wallet/primary"
+	"github.com/luxfi/sdk/wallet/primary/common"
+	"github.com/luxfi/utxo"
+	"github.com/luxfi/utxo/secp256k1fx"
+)
+
+// newPWallet returns the P-Chain wallet of kc, loaded with the UTXOs of the
+// P-Chain and, unless it is the P-Chain, those exported to it by
+// sourceChainID. The wallet waits for the txs it issues to be accepted.
+func newPWallet(ctx context.Context, baseURL string, kc keychain.Keychain, sourceChainID ids.ID) (p.Wallet, *pbuilder.Context, error) {
+	addrs := kc.Addresses()
+	state, err := primary.FetchState(ctx, baseURL, addrs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch the P-Chain UTXOs: %w", err)
+	}
+	if sourceChainID != constants.PlatformChainID {
+		if err := primary.AddAllUTXOs(ctx, state.UTXOs, state.PClient, ptxs.Codec, sourceChainID, constants.PlatformChainID, addrs.List()); err != nil {
+			return nil, nil, fmt.Errorf("failed to fetch the UTXOs exported to the P-Chain: %w", err)
+		}
+	}
+	backend := p.NewBackend(state.PCTX, common.NewChainUTXOs(constants.PlatformChainID, state.UTXOs), nil)
+	wallet := p.NewWallet(
+		p.NewClient(state.PClient, backend),
+		pbuilder.New(addrs, state.PCTX, backend),
+		psigner.New(kc, backend),
+	)
+	return wallet, state.PCTX, nil
+}
+
+func exportFromP(ctx context.Context, baseURL string, keys Keys, to Chain, amount uint64, owner *secp256k1fx.OutputOwners) (ids.ID, error) {
+	wallet, pctx, err := newPWallet(ctx, baseURL, keys.Keychain, constants.PlatformChainID)
+	if err != nil {
+		return ids.Empty, err
+	}
+	tx, err := wallet.IssueExportTx(to.ID(), []*utxo.TransferableOutput{{
+		Asset: utxo.Asset{ID: pctx.XAssetID},
+		Out:   &secp256k1fx.TransferOutput{Amt: amount, OutputOwners: *owner},
+	}}, common.WithContext(ctx))
+	if err != nil {
+		return ids.Empty, err
+	}
+	return tx.ID(), nil
+}
+
+func importToP(ctx context.Context, baseURL string, keys Keys, from Chain, recipient ids.ShortID) (ids.ID, error) {
+	wallet, _, err := newPWallet(ctx, baseURL, keys.Keychain, from.ID())
+	if err != nil {
+		return ids.Empty, err
+	}
+	tx, err := wallet.IssueImportTx(from.ID(), &secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     []ids.ShortID{recipient},
+	}, common.WithContext(ctx))
+	if err != nil {
+		return ids.Empty, err
+	}
+	return tx.ID(), nil
+}
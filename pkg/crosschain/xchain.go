@@ -0,0 +1,54 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crosschain
+
+import (
+	"context"
+
+	"github.com/luxfi/cli/pkg/xchain"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/utxo"
+	"github.com/luxfi/utxo/secp256k1fx"
+)
+
+func exportFromX(ctx context.Context, baseURL string, keys Keys, to Chain, amount uint64, owner *secp256k1fx.OutputOwners) (ids.ID, error) {
+	wallet, err := xchain.NewWallet(ctx, baseURL, keys.Keychain)
+	if err != nil {
+		return ids.Empty, err
+	}
+	utx, err := wallet.Builder.NewExportTx(to.ID(), []*utxo.TransferableOutput{{
+		Asset: utxo.Asset{ID: wallet.Builder.Context().XAssetID},
+		Out:   &secp256k1fx.TransferOutput{Amt: amount, OutputOwners: *owner},
+	}})
+	if err != nil {
+		return ids.Empty, err
+	}
+	tx, err := wallet.Issue(ctx, utx)
+	if err != nil {
+		return ids.Empty, err
+	}
+	return tx.ID(), nil
+}
+
+func importToX(ctx context.Context, baseURL string, keys Keys, from Chain, recipient ids.ShortID) (ids.ID, error) {
+	wallet, err := xchain.NewWallet(ctx, baseURL, keys.Keychain)
+	if err != nil {
+		return ids.Empty, err
+	}
+	if err := wallet.ImportUTXOs(ctx, from.ID()); err != nil {
+		return ids.Empty, err
+	}
+	utx, err := wallet.Builder.NewImportTx(from.ID(), &secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     []ids.ShortID{recipient},
+	})
+	if err != nil {
+		return ids.Empty, err
+	}
+	tx, err := wallet.Issue(ctx, utx)
+	if err != nil {
+		return ids.Empty, err
+	}
+	return tx.ID(), nil
+}
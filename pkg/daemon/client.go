@@ -0,0 +1,125 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package daemon
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Client calls the admin API of a running daemon.
+type Client struct {
+	conn  *grpc.ClientConn
+	token string
+}
+
+// Dial connects to the daemon at addr, using the same address forms as
+// Listen. Calls carry token, when set.
+func Dial(addr, token string) (*Client, error) {
+	target := addr
+	if hostPort, ok := strings.CutPrefix(addr, "tcp://"); ok {
+		target = hostPort
+	} else if !strings.HasPrefix(addr, "unix://") {
+		target = "unix://" + addr
+	}
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, token: token}, nil
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Status returns the raw network status as decoded JSON.
+func (c *Client) Status(ctx context.Context) (map[string]any, error) {
+	out, err := c.invoke(ctx, MethodStatus, Empty{})
+	if err != nil {
+		return nil, err
+	}
+	return out.AsMap(), nil
+}
+
+// StartNetwork starts a network through the daemon.
+func (c *Client) StartNetwork(ctx context.Context, req NetworkRequest) error {
+	_, err := c.invoke(ctx, MethodStartNetwork, req)
+	return err
+}
+
+// StopNetwork stops a network through the daemon.
+func (c *Client) StopNetwork(ctx context.Context, req NetworkRequest) error {
+	_, err := c.invoke(ctx, MethodStopNetwork, req)
+	return err
+}
+
+// CreateSnapshot snapshots all running networks.
+func (c *Client) CreateSnapshot(ctx context.Context, req SnapshotRequest) error {
+	_, err := c.invoke(ctx, MethodCreateSnapshot, req)
+	return err
+}
+
+// RestoreSnapshot restores a snapshot.
+func (c *Client) RestoreSnapshot(ctx context.Context, req SnapshotRequest) error {
+	_, err := c.invoke(ctx, MethodRestoreSnapshot, req)
+	return err
+}
+
+// ListSnapshots lists stored snapshots.
+func (c *Client) ListSnapshots(ctx context.Context) ([]SnapshotInfo, error) {
+	out, err := c.invoke(ctx, MethodListSnapshots, Empty{})
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Snapshots []SnapshotInfo `json:"snapshots"`
+	}
+	if err := fromStruct(out, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Snapshots, nil
+}
+
+// DeployChain deploys a chain through the daemon.
+func (c *Client) DeployChain(ctx context.Context, req DeployRequest) error {
+	_, err := c.invoke(ctx, MethodDeployChain, req)
+	return err
+}
+
+// ListKeys lists stored key sets.
+func (c *Client) ListKeys(ctx context.Context) ([]KeyInfo, error) {
+	out, err := c.invoke(ctx, MethodListKeys, Empty{})
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Keys []KeyInfo `json:"keys"`
+	}
+	if err := fromStruct(out, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Keys, nil
+}
+
+func (c *Client) invoke(ctx context.Context, method string, req any) (*structpb.Struct, error) {
+	in, err := toStruct(req)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.token)
+	}
+	out := new(structpb.Struct)
+	if err := c.conn.Invoke(ctx, "/"+ServiceName+"/"+method, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
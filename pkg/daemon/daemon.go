@@ -0,0 +1,389 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package daemon
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ServiceName is the fully qualified gRPC service name of the admin API.
+const ServiceName = "lux.daemon.v1.Admin"
+
+// SocketName is the name of the default unix socket under the CLI base dir.
+const SocketName = "daemon.sock"
+
+// TokenName is the name of the file under the CLI base dir holding the token
+// of the admin API, for clients on the same machine to read.
+const TokenName = "serve.token"
+
+// Method names of the admin API. Every method takes and returns a
+// google.protobuf.Struct, so any gRPC client can call it without generated
+// stubs; the fields are those of the request and response types below.
+const (
+	MethodStatus          = "Status"
+	MethodStartNetwork    = "StartNetwork"
+	MethodStopNetwork     = "StopNetwork"
+	MethodCreateSnapshot  = "CreateSnapshot"
+	MethodRestoreSnapshot = "RestoreSnapshot"
+	MethodListSnapshots   = "ListSnapshots"
+	MethodDeployChain     = "DeployChain"
+	MethodListKeys        = "ListKeys"
+)
+
+// NetworkRequest selects a network: mainnet, testnet, devnet or local.
+type NetworkRequest struct {
+	Network string `json:"network"`
+	// Force allows stopping mainnet and testnet, like `lux network stop --force`.
+	Force bool `json:"force,omitempty"`
+}
+
+// SnapshotRequest names a snapshot to create or restore.
+type SnapshotRequest struct {
+	Name        string `json:"name"`
+	Incremental bool   `json:"incremental,omitempty"`
+}
+
+// DeployRequest names a chain to deploy, and where.
+type DeployRequest struct {
+	Chain string `json:"chain"`
+	// Network is mainnet, testnet, devnet or local, the default.
+	Network string `json:"network,omitempty"`
+	// Key is the key paying for deploys to remote networks.
+	Key string `json:"key,omitempty"`
+}
+
+// KeyInfo describes a stored key set, without its secrets.
+type KeyInfo struct {
+	Name    string `json:"name"`
+	Address string `json:"address,omitempty"`
+}
+
+// SnapshotInfo describes a stored snapshot.
+type SnapshotInfo struct {
+	Name        string `json:"name"`
+	Size        int64  `json:"size"`
+	Incremental bool   `json:"incremental"`
+	Created     string `json:"created"`
+}
+
+// Empty is used for methods without parameters or results.
+type Empty struct{}
+
+// Backend performs the operations behind the admin API. The CLI implements it
+// with the same functions its commands use.
+type Backend interface {
+	// Status returns the status of all running networks as a JSON-encodable value.
+	Status(ctx context.Context) (any, error)
+	StartNetwork(ctx context.Context, req NetworkRequest) error
+	StopNetwork(ctx context.Context, req NetworkRequest) error
+	CreateSnapshot(ctx context.Context, req SnapshotRequest) error
+	RestoreSnapshot(ctx context.Context, req SnapshotRequest) error
+	ListSnapshots(ctx context.Context) ([]SnapshotInfo, error)
+	DeployChain(ctx context.Context, req DeployRequest) error
+	ListKeys(ctx context.Context) ([]KeyInfo, error)
+}
+
+// Server serves the admin API over gRPC, and over REST with Handler.
+type Server struct {
+	backend Backend
+	token   string
+	methods map[string]method
+	grpc    *grpc.Server
+	http    *http.Server
+
+	// mu serializes mutating operations, the CLI code paths behind them
+	// share package-level state
+	mu sync.Mutex
+}
+
+// method is a call of the admin API, taking and returning JSON objects.
+type method func(ctx context.Context, in *structpb.Struct) (any, error)
+
+// NewServer creates an admin API server for backend. When token is set,
+// every call must carry it as a bearer token in its authorization header or
+// metadata.
+func NewServer(backend Backend, token string) *Server {
+	s := &Server{
+		backend: backend,
+		token:   token,
+	}
+	s.methods = s.newMethods()
+	s.grpc = grpc.NewServer(grpc.UnaryInterceptor(s.authorizeGRPC))
+	s.grpc.RegisterService(s.serviceDesc(), s)
+	s.http = &http.Server{Handler: s.Handler(), ReadHeaderTimeout: readHeaderTimeout}
+	return s
+}
+
+// ErrNotLoopback is returned when asked to listen on TCP beyond the local
+// machine. Anyone reaching the admin API, or holding its token, controls the
+// networks.
+var ErrNotLoopback = errors.New("the daemon only listens on loopback addresses")
+
+// Listen opens the listener for addr. Addresses of the form tcp://host:port
+// listen on TCP, on loopback hosts only; anything else is treated as a unix
+// socket path.
+func Listen(addr string) (net.Listener, error) {
+	if hostPort, ok := strings.CutPrefix(addr, "tcp://"); ok {
+		host, _, err := net.SplitHostPort(hostPort)
+		if err != nil {
+			return nil, err
+		}
+		if !isLoopback(host) {
+			return nil, fmt.Errorf("%w, not %q", ErrNotLoopback, host)
+		}
+		return net.Listen("tcp", hostPort)
+	}
+	path := strings.TrimPrefix(addr, "unix://")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	// A socket left behind by a crashed daemon would make Listen fail
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	lis, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		_ = lis.Close()
+		return nil, err
+	}
+	return lis, nil
+}
+
+// LoadOrCreateToken returns the token stored at path, creating a random one
+// only the current user can read when there is none.
+func LoadOrCreateToken(path string) (string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: Reading the token file of the CLI base dir
+	if err == nil && len(strings.TrimSpace(string(data))) > 0 {
+		return strings.TrimSpace(string(data)), nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(token+"\n"), 0o600); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Serve serves the admin API over gRPC on lis until Stop is called.
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpc.Serve(lis)
+}
+
+// ServeREST serves the admin API over REST on lis until Stop is called.
+func (s *Server) ServeREST(lis net.Listener) error {
+	if err := s.http.Serve(lis); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Stop waits for in-flight calls and stops the server.
+func (s *Server) Stop() {
+	_ = s.http.Shutdown(context.Background())
+	s.grpc.GracefulStop()
+}
+
+// ErrUnauthenticated is returned for calls without the token of the server.
+var ErrUnauthenticated = errors.New("missing or invalid token")
+
+// authorized tells if the authorization header value carries the token
+func (s *Server) authorized(authorization string) bool {
+	if s.token == "" {
+		return true
+	}
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+func (s *Server) authorizeGRPC(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if s.token == "" {
+		return handler(ctx, req)
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("authorization"); len(values) == 0 || !s.authorized(values[0]) {
+		return nil, status.Error(codes.Unauthenticated, ErrUnauthenticated.Error())
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) newMethods() map[string]method {
+	return map[string]method{
+		MethodStatus: func(ctx context.Context, _ *structpb.Struct) (any, error) {
+			return s.backend.Status(ctx)
+		},
+		MethodStartNetwork: func(ctx context.Context, in *structpb.Struct) (any, error) {
+			var req NetworkRequest
+			if err := fromStruct(in, &req); err != nil {
+				return nil, err
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return Empty{}, s.backend.StartNetwork(ctx, req)
+		},
+		MethodStopNetwork: func(ctx context.Context, in *structpb.Struct) (any, error) {
+			var req NetworkRequest
+			if err := fromStruct(in, &req); err != nil {
+				return nil, err
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return Empty{}, s.backend.StopNetwork(ctx, req)
+		},
+		MethodCreateSnapshot: func(ctx context.Context, in *structpb.Struct) (any, error) {
+			var req SnapshotRequest
+			if err := fromStruct(in, &req); err != nil {
+				return nil, err
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return Empty{}, s.backend.CreateSnapshot(ctx, req)
+		},
+		MethodRestoreSnapshot: func(ctx context.Context, in *structpb.Struct) (any, error) {
+			var req SnapshotRequest
+			if err := fromStruct(in, &req); err != nil {
+				return nil, err
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return Empty{}, s.backend.RestoreSnapshot(ctx, req)
+		},
+		MethodListSnapshots: func(ctx context.Context, _ *structpb.Struct) (any, error) {
+			snapshots, err := s.backend.ListSnapshots(ctx)
+			return map[string]any{"snapshots": snapshots}, err
+		},
+		MethodDeployChain: func(ctx context.Context, in *structpb.Struct) (any, error) {
+			var req DeployRequest
+			if err := fromStruct(in, &req); err != nil {
+				return nil, err
+			}
+			if req.Chain == "" {
+				return nil, fmt.Errorf("%w: chain is required", errInvalidArgument)
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return Empty{}, s.backend.DeployChain(ctx, req)
+		},
+		MethodListKeys: func(ctx context.Context, _ *structpb.Struct) (any, error) {
+			keys, err := s.backend.ListKeys(ctx)
+			return map[string]any{"keys": keys}, err
+		},
+	}
+}
+
+func (s *Server) serviceDesc() *grpc.ServiceDesc {
+	desc := &grpc.ServiceDesc{
+		ServiceName: ServiceName,
+		HandlerType: (*any)(nil),
+		Metadata:    "lux/daemon/v1/admin",
+	}
+	for name, call := range s.methods {
+		desc.Methods = append(desc.Methods, grpc.MethodDesc{
+			MethodName: name,
+			Handler:    unaryHandler(ServiceName+"/"+name, call),
+		})
+	}
+	return desc
+}
+
+func unaryHandler(
+	fullMethod string,
+	call method,
+) func(any, context.Context, func(any) error, grpc.UnaryServerInterceptor) (any, error) {
+	return func(_ any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		in := new(structpb.Struct)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		handle := func(ctx context.Context, req any) (any, error) {
+			result, err := call(ctx, req.(*structpb.Struct))
+			if err != nil {
+				return nil, toStatus(err)
+			}
+			return toStruct(result)
+		}
+		if interceptor == nil {
+			return handle(ctx, in)
+		}
+		info := &grpc.UnaryServerInfo{FullMethod: "/" + fullMethod}
+		return interceptor(ctx, in, info, handle)
+	}
+}
+
+var errInvalidArgument = errors.New("invalid argument")
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, errInvalidArgument):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.Error(codes.Unknown, err.Error())
+	}
+}
+
+// toStruct converts a JSON-encodable value into a protobuf Struct.
+func toStruct(v any) (*structpb.Struct, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("result must encode as a JSON object: %w", err)
+	}
+	return structpb.NewStruct(m)
+}
+
+// fromStruct decodes a protobuf Struct into v.
+func fromStruct(s *structpb.Struct, v any) error {
+	data, err := json.Marshal(s.AsMap())
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidArgument, err)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package daemon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeBackend struct {
+	started  []string
+	stopped  []NetworkRequest
+	created  []SnapshotRequest
+	restored []string
+	deployed []DeployRequest
+}
+
+func (b *fakeBackend) Status(context.Context) (any, error) {
+	return map[string]any{"networks": []string{"mainnet"}}, nil
+}
+
+func (b *fakeBackend) StartNetwork(_ context.Context, req NetworkRequest) error {
+	if req.Network == "bogus" {
+		return errors.New("unknown network")
+	}
+	b.started = append(b.started, req.Network)
+	return nil
+}
+
+func (b *fakeBackend) StopNetwork(_ context.Context, req NetworkRequest) error {
+	b.stopped = append(b.stopped, req)
+	return nil
+}
+
+func (b *fakeBackend) CreateSnapshot(_ context.Context, req SnapshotRequest) error {
+	b.created = append(b.created, req)
+	return nil
+}
+
+func (b *fakeBackend) RestoreSnapshot(_ context.Context, req SnapshotRequest) error {
+	b.restored = append(b.restored, req.Name)
+	return nil
+}
+
+func (b *fakeBackend) ListSnapshots(context.Context) ([]SnapshotInfo, error) {
+	return []SnapshotInfo{{Name: "backup", Size: 42, Incremental: true, Created: "2025-01-02T03:04:05Z"}}, nil
+}
+
+func (b *fakeBackend) DeployChain(_ context.Context, req DeployRequest) error {
+	b.deployed = append(b.deployed, req)
+	return nil
+}
+
+func (b *fakeBackend) ListKeys(context.Context) ([]KeyInfo, error) {
+	return []KeyInfo{{Name: "ops", Address: "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"}}, nil
+}
+
+func startTestServer(t *testing.T, backend Backend, token string) *Client {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "luxd")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, SocketName)
+	lis, err := Listen(path)
+	require.NoError(t, err)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	server := NewServer(backend, token)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	client, err := Dial(path, token)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestAdminAPI(t *testing.T) {
+	require := require.New(t)
+	backend := &fakeBackend{}
+	client := startTestServer(t, backend, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	st, err := client.Status(ctx)
+	require.NoError(err)
+	require.Equal([]any{"mainnet"}, st["networks"])
+
+	require.NoError(client.StartNetwork(ctx, NetworkRequest{Network: "devnet"}))
+	require.Equal([]string{"devnet"}, backend.started)
+
+	require.NoError(client.StopNetwork(ctx, NetworkRequest{Network: "mainnet", Force: true}))
+	require.Equal([]NetworkRequest{{Network: "mainnet", Force: true}}, backend.stopped)
+
+	require.NoError(client.CreateSnapshot(ctx, SnapshotRequest{Name: "backup", Incremental: true}))
+	require.Equal([]SnapshotRequest{{Name: "backup", Incremental: true}}, backend.created)
+
+	require.NoError(client.RestoreSnapshot(ctx, SnapshotRequest{Name: "backup"}))
+	require.Equal([]string{"backup"}, backend.restored)
+
+	snapshots, err := client.ListSnapshots(ctx)
+	require.NoError(err)
+	require.Equal([]SnapshotInfo{{Name: "backup", Size: 42, Incremental: true, Created: "2025-01-02T03:04:05Z"}}, snapshots)
+
+	require.NoError(client.DeployChain(ctx, DeployRequest{Chain: "mychain", Network: "devnet"}))
+	require.Equal([]DeployRequest{{Chain: "mychain", Network: "devnet"}}, backend.deployed)
+	err = client.DeployChain(ctx, DeployRequest{Network: "devnet"})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+
+	keys, err := client.ListKeys(ctx)
+	require.NoError(err)
+	require.Equal([]KeyInfo{{Name: "ops", Address: "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"}}, keys)
+}
+
+func TestAdminAPIToken(t *testing.T) {
+	require := require.New(t)
+	backend := &fakeBackend{}
+	client := startTestServer(t, backend, "secret")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	require.NoError(client.StartNetwork(ctx, NetworkRequest{Network: "devnet"}))
+	client.token = "wrong"
+	err := client.StartNetwork(ctx, NetworkRequest{Network: "testnet"})
+	require.Equal(codes.Unauthenticated, status.Code(err))
+	client.token = ""
+	_, err = client.Status(ctx)
+	require.Equal(codes.Unauthenticated, status.Code(err))
+	require.Equal([]string{"devnet"}, backend.started)
+}
+
+func TestLoadOrCreateToken(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), TokenName)
+	token, err := LoadOrCreateToken(path)
+	require.NoError(err)
+	require.Len(token, 64)
+	info, err := os.Stat(path)
+	require.NoError(err)
+	require.Equal(os.FileMode(0o600), info.Mode().Perm())
+
+	again, err := LoadOrCreateToken(path)
+	require.NoError(err)
+	require.Equal(token, again)
+}
+
+func TestAdminAPIErrors(t *testing.T) {
+	require := require.New(t)
+	client := startTestServer(t, &fakeBackend{}, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := client.StartNetwork(ctx, NetworkRequest{Network: "bogus"})
+	require.Equal(codes.Unknown, status.Code(err))
+	require.Contains(err.Error(), "unknown network")
+
+	// A field of the wrong type is rejected before reaching the backend
+	_, err = client.invoke(ctx, MethodStartNetwork, map[string]any{"network": 1})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func TestListenLoopbackOnly(t *testing.T) {
+	for _, addr := range []string{"tcp://0.0.0.0:0", "tcp://:0", "tcp://192.0.2.1:0", "tcp://[::]:0"} {
+		_, err := Listen(addr)
+		require.ErrorIs(t, err, ErrNotLoopback, addr)
+	}
+	for _, addr := range []string{"tcp://127.0.0.1:0", "tcp://localhost:0"} {
+		lis, err := Listen(addr)
+		require.NoError(t, err, addr)
+		require.NoError(t, lis.Close())
+	}
+}
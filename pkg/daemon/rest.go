@@ -0,0 +1,108 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	readHeaderTimeout = 10 * time.Second
+	// maxRequestSize bounds the JSON body of REST calls
+	maxRequestSize = 1 << 20
+)
+
+// route maps a REST route to a method of the admin API. The path values of
+// the route are set as the fields of the same name of the request.
+type route struct {
+	pattern string
+	method  string
+}
+
+// routes are the REST routes of the admin API. Request and response bodies
+// are the JSON objects of the gRPC methods.
+var routes = []route{
+	{"GET /v1/status", MethodStatus},
+	{"POST /v1/networks/{network}/start", MethodStartNetwork},
+	{"POST /v1/networks/{network}/stop", MethodStopNetwork},
+	{"GET /v1/snapshots", MethodListSnapshots},
+	{"POST /v1/snapshots", MethodCreateSnapshot},
+	{"POST /v1/snapshots/{name}/restore", MethodRestoreSnapshot},
+	{"POST /v1/chains/{chain}/deploy", MethodDeployChain},
+	{"GET /v1/keys", MethodListKeys},
+}
+
+// Handler returns the REST handler of the admin API, running the same
+// methods as its gRPC service.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	for _, r := range routes {
+		call := s.methods[r.method]
+		mux.HandleFunc(r.pattern, func(w http.ResponseWriter, req *http.Request) {
+			if !s.authorized(req.Header.Get("Authorization")) {
+				writeError(w, http.StatusUnauthorized, ErrUnauthenticated)
+				return
+			}
+			in, err := restRequest(req)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+			result, err := call(req.Context(), in)
+			if err != nil {
+				writeError(w, httpStatus(err), err)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(result)
+		})
+	}
+	return mux
+}
+
+// restRequest returns the request of a REST call: its JSON body, with the
+// path values of its route
+func restRequest(req *http.Request) (*structpb.Struct, error) {
+	fields := map[string]any{}
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxRequestSize))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return nil, fmt.Errorf("%w: body must be a JSON object: %v", errInvalidArgument, err)
+		}
+	}
+	for _, name := range []string{"network", "name", "chain"} {
+		if value := req.PathValue(name); value != "" {
+			fields[name] = value
+		}
+	}
+	return structpb.NewStruct(fields)
+}
+
+func httpStatus(err error) int {
+	switch {
+	case errors.Is(err, errInvalidArgument):
+		return http.StatusBadRequest
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package daemon
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRESTAPI(t *testing.T) {
+	require := require.New(t)
+	backend := &fakeBackend{}
+	server := httptest.NewServer(NewServer(backend, "secret").Handler())
+	defer server.Close()
+
+	call := func(method, path, token, body string) (int, map[string]any) {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		require.NoError(err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := server.Client().Do(req)
+		require.NoError(err)
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(err)
+		var out map[string]any
+		require.NoError(json.Unmarshal(data, &out))
+		return resp.StatusCode, out
+	}
+
+	code, out := call(http.MethodGet, "/v1/status", "secret", "")
+	require.Equal(http.StatusOK, code)
+	require.Equal([]any{"mainnet"}, out["networks"])
+
+	// path values and the body make the request of the method
+	code, _ = call(http.MethodPost, "/v1/networks/mainnet/stop", "secret", `{"force": true}`)
+	require.Equal(http.StatusOK, code)
+	require.Equal([]NetworkRequest{{Network: "mainnet", Force: true}}, backend.stopped)
+	code, _ = call(http.MethodPost, "/v1/chains/mychain/deploy", "secret", `{"network": "testnet", "key": "ops"}`)
+	require.Equal(http.StatusOK, code)
+	require.Equal([]DeployRequest{{Chain: "mychain", Network: "testnet", Key: "ops"}}, backend.deployed)
+	code, _ = call(http.MethodPost, "/v1/snapshots/backup/restore", "secret", "")
+	require.Equal(http.StatusOK, code)
+	require.Equal([]string{"backup"}, backend.restored)
+
+	code, out = call(http.MethodGet, "/v1/keys", "secret", "")
+	require.Equal(http.StatusOK, code)
+	require.Len(out["keys"], 1)
+
+	code, out = call(http.MethodPost, "/v1/networks/bogus/start", "secret", "")
+	require.Equal(http.StatusInternalServerError, code)
+	require.Equal("unknown network", out["error"])
+	code, _ = call(http.MethodPost, "/v1/snapshots", "secret", `{"incremental": "yes"}`)
+	require.Equal(http.StatusBadRequest, code)
+
+	code, out = call(http.MethodPost, "/v1/networks/devnet/start", "wrong", "")
+	require.Equal(http.StatusUnauthorized, code)
+	require.Equal(ErrUnauthenticated.Error(), out["error"])
+	code, _ = call(http.MethodGet, "/v1/status", "", "")
+	require.Equal(http.StatusUnauthorized, code)
+	require.Empty(backend.started)
+}
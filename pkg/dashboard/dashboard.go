@@ -0,0 +1,619 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package dashboard is the terminal UI of lux dashboard. It shows the nodes,
+// validators, recent snapshots and relayer routes of the networks run on this
+// machine on one screen, refreshed periodically, with keys to restart a node,
+// tail its logs and take a snapshot.
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Pane is a section of the dashboard
+type Pane int
+
+const (
+	NodesPane Pane = iota
+	ValidatorsPane
+	SnapshotsPane
+	RelayerPane
+	paneCount
+)
+
+var paneTitles = [paneCount]string{"Nodes", "Validators", "Snapshots", "Relayer"}
+
+// String returns the title of p
+func (p Pane) String() string {
+	return paneTitles[p]
+}
+
+// Health of the nodes and validators
+const (
+	Healthy = "healthy"
+	Down    = "down"
+	// Unknown is the health of the validators that no node of this machine
+	// runs
+	Unknown = "unknown"
+)
+
+// maxLogLines is how many of the lines of the tailed logs are kept
+const maxLogLines = 1000
+
+// keyHelp lists the keys of the dashboard
+const keyHelp = "tab/1-4 pane  ↑/↓ select  r refresh  R restart node  l logs  s snapshot  q quit"
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	paneStyle     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("8"))
+	focusedStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	faintStyle    = lipgloss.NewStyle().Faint(true)
+	greenStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	yellowStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	redStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
+
+// Node is a node of a network run on this machine
+type Node struct {
+	Network string
+	// Name is the name of the node in its network, e.g. node1
+	Name      string
+	NodeID    string
+	URL       string
+	Version   string
+	Peers     int
+	Uptime    string
+	Healthy   bool
+	LatencyMS int
+	Error     string
+}
+
+// Validator is a validator of a network
+type Validator struct {
+	Network string
+	NodeID  string
+	Weight  uint64
+	// Health is Healthy or Down, after the node running the validator, or
+	// Unknown when no node of this machine runs it
+	Health string
+}
+
+// Snapshot is a snapshot of the networks
+type Snapshot struct {
+	Name        string
+	Size        string
+	Incremental bool
+	Created     time.Time
+}
+
+// Route is a relayer route between two chains of a network
+type Route struct {
+	Network string
+	// Name is the route, e.g. zoo -> hanzo
+	Name string
+	// Relayer tells if the relayer of the network runs
+	Relayer bool
+	// Health is healthy, idle or stalled
+	Health        string
+	Sent          int
+	Delivered     int
+	Pending       int
+	OldestPending string
+	Error         string
+}
+
+// State is what the dashboard shows
+type State struct {
+	Nodes      []Node
+	Validators []Validator
+	Snapshots  []Snapshot
+	Routes     []Route
+	// Errors are why panes could not be loaded
+	Errors map[Pane]error
+}
+
+// Sources load what the dashboard shows and run the actions of its keys.
+// They are called off the UI goroutine.
+type Sources struct {
+	// Load returns the current state
+	Load func(ctx context.Context) State
+	// Restart restarts node
+	Restart func(ctx context.Context, node Node) error
+	// Logs calls emit with the recent log lines of node, then with the new
+	// ones until ctx is done
+	Logs func(ctx context.Context, node Node, emit func(line string)) error
+	// Snapshot takes a snapshot of network and returns its name
+	Snapshot func(ctx context.Context, network string) (string, error)
+}
+
+// Notice is shown on the status line of the dashboard, e.g. the progress of
+// the action running. Send it with tea.Program.Send.
+type Notice string
+
+// Model is the bubbletea model of the dashboard
+type Model struct {
+	ctx      context.Context
+	sources  Sources
+	interval time.Duration
+
+	state   State
+	updated time.Time
+	loading bool
+	// refreshes counts the loads, so that the ticks scheduled before a
+	// manual refresh are dropped
+	refreshes int
+
+	pane   Pane
+	cursor [paneCount]int
+	width  int
+	height int
+
+	notice string
+	// busy is the action running, as actions run one at a time
+	busy string
+	// confirm is the node to restart once confirmed
+	confirm *Node
+	logs    *logView
+}
+
+// logView shows the logs of a node, in place of the panes
+type logView struct {
+	node   Node
+	lines  []string
+	stream chan string
+	cancel context.CancelFunc
+	// err is why the stream ended, set before stream is closed
+	err error
+}
+
+type (
+	loadedMsg struct {
+		state State
+		at    time.Time
+	}
+	tickMsg struct {
+		refresh int
+	}
+	actionMsg struct {
+		notice string
+		err    error
+	}
+	logLineMsg struct {
+		view *logView
+		line string
+	}
+	logEndMsg struct {
+		view *logView
+	}
+)
+
+// New returns the dashboard of sources, refreshed every interval. The loads
+// and actions it runs are canceled with ctx.
+func New(ctx context.Context, sources Sources, interval time.Duration) Model {
+	return Model{ctx: ctx, sources: sources, interval: interval, loading: true}
+}
+
+// Init loads the state
+func (m Model) Init() tea.Cmd {
+	return m.load()
+}
+
+func (m Model) load() tea.Cmd {
+	return func() tea.Msg {
+		return loadedMsg{state: m.sources.Load(m.ctx), at: time.Now()}
+	}
+}
+
+// refresh loads the state again
+func (m Model) refresh() (tea.Model, tea.Cmd) {
+	m.loading = true
+	m.refreshes++
+	return m, m.load()
+}
+
+// Update handles the keys and the results of the loads and actions
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case loadedMsg:
+		m.state, m.updated, m.loading = msg.state, msg.at, false
+		for p := Pane(0); p < paneCount; p++ {
+			m.cursor[p] = max(0, min(m.cursor[p], m.rows(p)-1))
+		}
+		refresh := m.refreshes
+		return m, tea.Tick(m.interval, func(time.Time) tea.Msg {
+			return tickMsg{refresh: refresh}
+		})
+	case tickMsg:
+		if msg.refresh == m.refreshes && !m.loading {
+			return m.refresh()
+		}
+	case Notice:
+		m.notice = string(msg)
+	case actionMsg:
+		m.busy = ""
+		m.notice = msg.notice
+		if msg.err != nil {
+			m.notice = "Error: " + msg.err.Error()
+		}
+		if !m.loading {
+			return m.refresh()
+		}
+	case logLineMsg:
+		if msg.view != m.logs {
+			return m, nil
+		}
+		m.logs.lines = append(m.logs.lines, msg.line)
+		if len(m.logs.lines) > maxLogLines {
+			m.logs.lines = m.logs.lines[len(m.logs.lines)-maxLogLines:]
+		}
+		return m, m.logs.next()
+	case logEndMsg:
+		if msg.view == m.logs && msg.view.err != nil && !errors.Is(msg.view.err, context.Canceled) {
+			m.logs.lines = append(m.logs.lines, "Error: "+msg.view.err.Error())
+		}
+	case tea.KeyMsg:
+		return m.key(msg.String())
+	}
+	return m, nil
+}
+
+func (m Model) key(key string) (tea.Model, tea.Cmd) {
+	if key == "ctrl+c" {
+		if m.logs != nil {
+			m.logs.cancel()
+		}
+		return m, tea.Quit
+	}
+	if m.confirm != nil {
+		node := *m.confirm
+		m.confirm = nil
+		if key != "y" {
+			m.notice = "Restart canceled"
+			return m, nil
+		}
+		return m.run(fmt.Sprintf("Restarting %s of %s", node.Name, node.Network), func(ctx context.Context) (string, error) {
+			return fmt.Sprintf("Restarted %s of %s", node.Name, node.Network), m.sources.Restart(ctx, node)
+		})
+	}
+	if m.logs != nil {
+		switch key {
+		case "esc", "q", "l":
+			m.logs.cancel()
+			m.logs = nil
+		}
+		return m, nil
+	}
+
+	switch key {
+	case "q":
+		return m, tea.Quit
+	case "tab", "right":
+		m.pane = (m.pane + 1) % paneCount
+	case "shift+tab", "left":
+		m.pane = (m.pane + paneCount - 1) % paneCount
+	case "1", "2", "3", "4":
+		m.pane = Pane(key[0] - '1')
+	case "up", "k":
+		m.cursor[m.pane] = max(0, m.cursor[m.pane]-1)
+	case "down", "j":
+		m.cursor[m.pane] = max(0, min(m.cursor[m.pane]+1, m.rows(m.pane)-1))
+	case "r":
+		if !m.loading {
+			return m.refresh()
+		}
+	case "R":
+		node, ok := m.selectedNode()
+		switch {
+		case !ok:
+			m.notice = "Select a node to restart"
+		case m.busy != "":
+			m.notice = m.busy + ", wait for it to finish"
+		default:
+			m.confirm = &node
+		}
+	case "l":
+		node, ok := m.selectedNode()
+		if !ok {
+			m.notice = "Select a node to tail the logs of"
+			return m, nil
+		}
+		return m.tailLogs(node)
+	case "s":
+		node, ok := m.selectedNode()
+		if !ok && len(m.state.Nodes) > 0 {
+			node, ok = m.state.Nodes[0], true
+		}
+		if !ok {
+			m.notice = "No network running to snapshot"
+			return m, nil
+		}
+		return m.run("Taking a snapshot of "+node.Network, func(ctx context.Context) (string, error) {
+			name, err := m.sources.Snapshot(ctx, node.Network)
+			return fmt.Sprintf("Snapshot %s taken", name), err
+		})
+	}
+	return m, nil
+}
+
+// selectedNode returns the node selected in the nodes pane, or the node
+// running the validator selected in the validators pane
+func (m Model) selectedNode() (Node, bool) {
+	if m.pane == ValidatorsPane && len(m.state.Validators) > 0 {
+		v := m.state.Validators[m.cursor[ValidatorsPane]]
+		for _, node := range m.state.Nodes {
+			if node.Network == v.Network && node.NodeID == v.NodeID {
+				return node, true
+			}
+		}
+		return Node{}, false
+	}
+	if len(m.state.Nodes) == 0 {
+		return Node{}, false
+	}
+	return m.state.Nodes[m.cursor[NodesPane]], true
+}
+
+// run runs action, unless another one is running
+func (m Model) run(busy string, action func(ctx context.Context) (string, error)) (tea.Model, tea.Cmd) {
+	if m.busy != "" {
+		m.notice = m.busy + ", wait for it to finish"
+		return m, nil
+	}
+	m.busy = busy
+	m.notice = busy + "..."
+	ctx := m.ctx
+	return m, func() tea.Msg {
+		notice, err := action(ctx)
+		return actionMsg{notice: notice, err: err}
+	}
+}
+
+func (m Model) tailLogs(node Node) (tea.Model, tea.Cmd) {
+	ctx, cancel := context.WithCancel(m.ctx)
+	view := &logView{node: node, stream: make(chan string, 64), cancel: cancel}
+	m.logs = view
+	go func() {
+		view.err = m.sources.Logs(ctx, node, func(line string) {
+			select {
+			case view.stream <- line:
+			case <-ctx.Done():
+			}
+		})
+		close(view.stream)
+	}()
+	return m, view.next()
+}
+
+// next waits for the next line of the logs
+func (v *logView) next() tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-v.stream
+		if !ok {
+			return logEndMsg{view: v}
+		}
+		return logLineMsg{view: v, line: line}
+	}
+}
+
+// rows returns the number of rows of p
+func (m Model) rows(p Pane) int {
+	switch p {
+	case NodesPane:
+		return len(m.state.Nodes)
+	case ValidatorsPane:
+		return len(m.state.Validators)
+	case SnapshotsPane:
+		return len(m.state.Snapshots)
+	default:
+		return len(m.state.Routes)
+	}
+}
+
+// View renders the panes, or the logs being tailed
+func (m Model) View() string {
+	if m.logs != nil {
+		return m.logsView()
+	}
+	header := titleStyle.Render("Lux dashboard")
+	if m.updated.IsZero() {
+		header += faintStyle.Render("  loading...")
+	} else {
+		header += faintStyle.Render(fmt.Sprintf("  updated %s, every %s", m.updated.Format("15:04:05"), m.interval))
+	}
+	lines := []string{header}
+	// each pane has a blank line, a title and a column header, and the
+	// footer a blank line, the status line and the key help
+	limit := 0
+	if m.height > 0 {
+		limit = max(1, (m.height-1-3*int(paneCount)-3)/int(paneCount))
+	}
+	for p := Pane(0); p < paneCount; p++ {
+		lines = append(lines, "")
+		lines = append(lines, m.paneView(p, limit)...)
+	}
+	status := m.notice
+	if m.confirm != nil {
+		status = fmt.Sprintf("Restart %s of %s? Press y to confirm", m.confirm.Name, m.confirm.Network)
+	}
+	lines = append(lines, "", status, faintStyle.Render(keyHelp))
+	return m.fit(lines)
+}
+
+// paneView renders the title and the rows of p, at most limit of them
+// around the cursor when limit is set
+func (m Model) paneView(p Pane, limit int) []string {
+	style := paneStyle
+	if p == m.pane {
+		style = focusedStyle
+	}
+	lines := []string{style.Render(fmt.Sprintf("%d %s", p+1, p))}
+	if err := m.state.Errors[p]; err != nil {
+		lines = append(lines, redStyle.Render("  Error: "+err.Error()))
+	}
+	header, rows, health := m.table(p)
+	if len(rows) == 0 {
+		if m.state.Errors[p] == nil {
+			lines = append(lines, faintStyle.Render("  none"))
+		}
+		return lines
+	}
+	cells := columns(append([][]string{header}, rows...))
+	lines = append(lines, faintStyle.Render("  "+cells[0]))
+	start, end := 0, len(rows)
+	if limit > 0 && len(rows) > limit {
+		start = max(0, min(m.cursor[p]-limit/2, len(rows)-limit))
+		end = start + limit
+	}
+	for i := start; i < end; i++ {
+		if p == m.pane && i == m.cursor[p] {
+			lines = append(lines, "> "+selectedStyle.Render(cells[i+1]))
+			continue
+		}
+		lines = append(lines, "  "+healthStyle(health[i]).Render(cells[i+1]))
+	}
+	return lines
+}
+
+// table returns the column header and the rows of p, with the health of
+// each row
+func (m Model) table(p Pane) ([]string, [][]string, []string) {
+	var (
+		rows   [][]string
+		health []string
+	)
+	switch p {
+	case NodesPane:
+		for _, n := range m.state.Nodes {
+			h := Healthy
+			if !n.Healthy {
+				h = Down
+			}
+			state := h
+			if n.Error != "" {
+				state += ": " + n.Error
+			}
+			rows = append(rows, []string{
+				n.Network, n.Name, n.NodeID, n.Version, strconv.Itoa(n.Peers), n.Uptime, fmt.Sprintf("%dms", n.LatencyMS), state,
+			})
+			health = append(health, h)
+		}
+		return []string{"Network", "Node", "Node ID", "Version", "Peers", "Uptime", "Latency", "Health"}, rows, health
+	case ValidatorsPane:
+		for _, v := range m.state.Validators {
+			rows = append(rows, []string{v.Network, v.NodeID, strconv.FormatUint(v.Weight, 10), v.Health})
+			health = append(health, v.Health)
+		}
+		return []string{"Network", "Node ID", "Weight", "Health"}, rows, health
+	case SnapshotsPane:
+		for _, s := range m.state.Snapshots {
+			kind := "full"
+			if s.Incremental {
+				kind = "incremental"
+			}
+			rows = append(rows, []string{s.Name, s.Size, kind, s.Created.Local().Format("2006-01-02 15:04")})
+			health = append(health, "")
+		}
+		return []string{"Name", "Size", "Kind", "Created"}, rows, health
+	default:
+		for _, r := range m.state.Routes {
+			relayer := "running"
+			if !r.Relayer {
+				relayer = "stopped"
+			}
+			if r.Error != "" {
+				rows = append(rows, []string{r.Network, r.Name, relayer, "error: " + r.Error, "", "", "", ""})
+				health = append(health, "error")
+				continue
+			}
+			oldest := r.OldestPending
+			if oldest == "" {
+				oldest = "-"
+			}
+			rows = append(rows, []string{
+				r.Network, r.Name, relayer, r.Health, strconv.Itoa(r.Sent), strconv.Itoa(r.Delivered), strconv.Itoa(r.Pending), oldest,
+			})
+			// pending messages are not delivered while the relayer is down
+			if !r.Relayer && r.Pending > 0 {
+				health = append(health, Down)
+				continue
+			}
+			health = append(health, r.Health)
+		}
+		return []string{"Network", "Route", "Relayer", "Health", "Sent", "Delivered", "Pending", "Oldest Pending"}, rows, health
+	}
+}
+
+// logsView renders the last lines of the logs being tailed
+func (m Model) logsView() string {
+	lines := []string{
+		titleStyle.Render(fmt.Sprintf("Logs of %s of %s", m.logs.node.Name, m.logs.node.Network)) +
+			faintStyle.Render("  esc to go back"),
+	}
+	tail := m.logs.lines
+	if m.height > 1 && len(tail) > m.height-1 {
+		tail = tail[len(tail)-(m.height-1):]
+	}
+	return m.fit(append(lines, tail...))
+}
+
+// fit joins lines, cut to the width of the terminal
+func (m Model) fit(lines []string) string {
+	if m.width > 0 {
+		cut := lipgloss.NewStyle().MaxWidth(m.width)
+		for i, line := range lines {
+			lines[i] = cut.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// columns joins the cells of each row, padded to the width of their column
+func columns(rows [][]string) []string {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], lipgloss.Width(cell))
+		}
+	}
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		var b strings.Builder
+		for i, cell := range row {
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-lipgloss.Width(cell)+2))
+			}
+		}
+		lines = append(lines, b.String())
+	}
+	return lines
+}
+
+// healthStyle colors the rows of the given health
+func healthStyle(health string) lipgloss.Style {
+	switch health {
+	case "":
+		return lipgloss.NewStyle()
+	case Healthy:
+		return greenStyle
+	case Unknown, "idle":
+		return yellowStyle
+	default:
+		return redStyle
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+)
+
+var testState = State{
+	Nodes: []Node{
+		{Network: "local", Name: "node1", NodeID: "NodeID-A", Version: "luxd/1.23.5", Peers: 4, Uptime: "2h5m", LatencyMS: 3, Healthy: true},
+		{Network: "local", Name: "node2", NodeID: "NodeID-B", Version: "luxd/1.23.5", Error: "connection refused"},
+	},
+	Validators: []Validator{
+		{Network: "local", NodeID: "NodeID-B", Weight: 2000, Health: Down},
+		{Network: "local", NodeID: "NodeID-C", Weight: 1000, Health: Unknown},
+	},
+	Routes: []Route{
+		{Network: "local", Name: "zoo -> hanzo", Health: "stalled", Sent: 3, Delivered: 1, Pending: 2, OldestPending: "nonce 2, block 40"},
+	},
+	Errors: map[Pane]error{SnapshotsPane: errors.New("permission denied")},
+}
+
+// send updates m with msgs
+func send(m tea.Model, msgs ...tea.Msg) tea.Model {
+	for _, msg := range msgs {
+		m, _ = m.Update(msg)
+	}
+	return m
+}
+
+func keys(names ...string) []tea.Msg {
+	msgs := make([]tea.Msg, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case "tab":
+			msgs = append(msgs, tea.KeyMsg{Type: tea.KeyTab})
+		case "down":
+			msgs = append(msgs, tea.KeyMsg{Type: tea.KeyDown})
+		case "esc":
+			msgs = append(msgs, tea.KeyMsg{Type: tea.KeyEsc})
+		default:
+			msgs = append(msgs, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name)})
+		}
+	}
+	return msgs
+}
+
+func loaded(sources Sources) tea.Model {
+	m := New(context.Background(), sources, time.Second)
+	return send(m, m.Init()())
+}
+
+func TestView(t *testing.T) {
+	require := require.New(t)
+	m := loaded(Sources{Load: func(context.Context) State { return testState }})
+	view := m.View()
+	require.Contains(view, "> local    node1  NodeID-A  luxd/1.23.5  4      2h5m    3ms      healthy")
+	require.Contains(view, "  local    node2  NodeID-B  luxd/1.23.5  0              0ms      down: connection refused")
+	require.Contains(view, "  local    NodeID-C  1000    unknown")
+	require.Contains(view, "  Error: permission denied")
+	require.Contains(view, "  local    zoo -> hanzo  stopped  stalled  3     1          2        nonce 2, block 40")
+
+	// the cursor moves in the focused pane, within its rows
+	m = send(m, keys("down", "down", "2", "down", "down")...)
+	view = m.View()
+	require.Contains(view, "  local    node1")
+	require.Contains(view, "  local    node2")
+	require.Contains(view, "> local    NodeID-C")
+}
+
+func TestRefresh(t *testing.T) {
+	require := require.New(t)
+	loads := 0
+	m := New(context.Background(), Sources{Load: func(context.Context) State {
+		loads++
+		return testState
+	}}, time.Minute)
+	m2, cmd := m.Update(m.Init()())
+	require.NotNil(cmd)
+	require.Equal(1, loads)
+
+	// a manual refresh drops the tick scheduled before it
+	m2, cmd = m2.Update(keys("r")[0])
+	m2 = send(m2, cmd())
+	require.Equal(2, loads)
+	_, cmd = m2.Update(tickMsg{refresh: 0})
+	require.Nil(cmd)
+	_, cmd = m2.Update(tickMsg{refresh: 1})
+	require.NotNil(cmd)
+}
+
+func TestRestart(t *testing.T) {
+	require := require.New(t)
+	var restarted []string
+	m := loaded(Sources{
+		Load: func(context.Context) State { return testState },
+		Restart: func(_ context.Context, node Node) error {
+			restarted = append(restarted, node.Name)
+			return nil
+		},
+	})
+
+	// restarts are confirmed
+	m = send(m, keys("down", "R")...)
+	require.Contains(m.View(), "Restart node2 of local? Press y to confirm")
+	m = send(m, keys("n")...)
+	require.Contains(m.View(), "Restart canceled")
+
+	m, cmd := m.Update(keys("R")[0])
+	require.Nil(cmd)
+	m, cmd = m.Update(keys("y")[0])
+	require.Contains(m.View(), "Restarting node2 of local...")
+	m, cmd = m.Update(cmd())
+	require.Equal([]string{"node2"}, restarted)
+	require.Contains(m.View(), "Restarted node2 of local")
+	require.NotNil(cmd, "the state is loaded again")
+
+	// the node of the selected validator is restarted
+	m = send(m, keys("2", "R", "y")...)
+	require.Contains(m.View(), "Restarting node2 of local...")
+	m = send(m, keys("down", "R")...)
+	require.Contains(m.View(), "Select a node to restart")
+}
+
+func TestSnapshot(t *testing.T) {
+	require := require.New(t)
+	m := loaded(Sources{
+		Load: func(context.Context) State { return testState },
+		Snapshot: func(_ context.Context, network string) (string, error) {
+			return "", errors.New("no space left on device")
+		},
+	})
+	m, cmd := m.Update(keys("s")[0])
+	require.Contains(m.View(), "Taking a snapshot of local...")
+	m = send(m, Notice("Snapshotted local/node1 main DB"))
+	require.Contains(m.View(), "Snapshotted local/node1 main DB")
+
+	// one action runs at a time
+	m = send(m, keys("s")...)
+	require.Contains(m.View(), "Taking a snapshot of local, wait for it to finish")
+	m = send(m, cmd())
+	require.Contains(m.View(), "Error: no space left on device")
+}
+
+func TestLogs(t *testing.T) {
+	require := require.New(t)
+	m := loaded(Sources{
+		Load: func(context.Context) State { return testState },
+		Logs: func(_ context.Context, node Node, emit func(string)) error {
+			emit("[10-16|09:00:00.000] INFO " + node.Name + " started")
+			emit("[10-16|09:00:01.000] WARN " + node.Name + " peer dropped")
+			return errors.New("log file removed")
+		},
+	})
+	m, cmd := m.Update(keys("l")[0])
+	for cmd != nil {
+		m, cmd = m.Update(cmd())
+	}
+	view := m.View()
+	require.Contains(view, "Logs of node1 of local")
+	require.Contains(view, "INFO node1 started")
+	require.Contains(view, "WARN node1 peer dropped")
+	require.Contains(view, "Error: log file removed")
+
+	m = send(m, keys("esc")...)
+	require.Contains(m.View(), "Lux dashboard")
+}
@@ -9,7 +9,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/luxfi/cli/pkg/chain"
 	"github.com/luxfi/cli/pkg/ux"
@@ -55,8 +57,8 @@ func GetLatestLuxdByProtocolVersion(app *application.Lux, rpcVersion int) (strin
 func GetLatestCLISupportedDependencyVersion(app *application.Lux, dependencyName string, network models.Network, rpcVersion *int) (string, error) {
 	var parsedDependency models.CLIDependencyMap
 
-	// Try to load from remote URL first
-	dependencyBytes, err := app.Downloader.Download(constants.CLILatestDependencyURL)
+	// Try to load from remote URL first, through the version matrix cache
+	dependencyBytes, err := download(app, constants.CLILatestDependencyURL)
 	if err != nil {
 		// Try to load from local min-version.json file (in CLI repo or executable directory)
 		localPath := findLocalMinVersionFile()
@@ -134,7 +136,7 @@ func findLocalMinVersionFile() string {
 
 // GetLuxdVersionsForRPC returns list of compatible lux go versions for a specified rpcVersion
 func GetLuxdVersionsForRPC(app *application.Lux, rpcVersion int, url string) ([]string, error) {
-	compatibilityBytes, err := app.Downloader.Download(url)
+	compatibilityBytes, err := download(app, url)
 	if err != nil {
 		return nil, err
 	}
@@ -145,8 +147,14 @@ func GetLuxdVersionsForRPC(app *application.Lux, rpcVersion int, url string) ([]
 	}
 
 	eligibleVersions, ok := parsedCompat[strconv.Itoa(rpcVersion)]
-	if !ok {
-		return nil, ErrNoLuxdVersion
+	if !ok || len(eligibleVersions) == 0 {
+		rpcs := make([]string, 0, len(parsedCompat))
+		for rpc := range parsedCompat {
+			rpcs = append(rpcs, rpc)
+		}
+		sort.Strings(rpcs)
+		return nil, fmt.Errorf("%w: %s lists no luxd version for RPC protocol %d, only for %s",
+			ErrNoLuxdVersion, url, rpcVersion, strings.Join(rpcs, ", "))
 	}
 
 	// versions are not necessarily sorted, so we need to sort them, tho this puts them in ascending order
@@ -158,12 +166,14 @@ func GetLuxdVersionsForRPC(app *application.Lux, rpcVersion int, url string) ([]
 // with latest version in first index
 func GetAvailableLuxdVersions(app *application.Lux, rpcVersion int, url string) ([]string, error) {
 	eligibleVersions, err := GetLuxdVersionsForRPC(app, rpcVersion, url)
+	if errors.Is(err, ErrNoLuxdVersion) {
+		return nil, err
+	}
 	if err != nil {
-		return nil, ErrNoLuxdVersion
+		return nil, fmt.Errorf("%w: %w", ErrNoLuxdVersion, err)
 	}
 	// get latest luxd release to make sure we're not picking a release currently in progress but not available for download
-	releaseURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/latest", constants.LuxOrg, constants.LuxdRepoName)
-	latestLuxdVersion, err := app.Downloader.GetLatestReleaseVersion(releaseURL)
+	latestLuxdVersion, err := latestRelease(app, luxdReleaseURL())
 	if err != nil {
 		return nil, err
 	}
@@ -175,7 +185,8 @@ func GetAvailableLuxdVersions(app *application.Lux, rpcVersion int, url string)
 		}
 	}
 	if len(availableVersions) == 0 {
-		return nil, ErrNoLuxdVersion
+		return nil, fmt.Errorf("%w: the luxd versions of RPC protocol %d, %s, are all newer than the latest luxd release %s",
+			ErrNoLuxdVersion, rpcVersion, strings.Join(eligibleVersions, ", "), latestLuxdVersion)
 	}
 	return availableVersions, nil
 }
@@ -199,8 +210,7 @@ func GetLuxdVersion(app *application.Lux, luxdVersion LuxdVersionSettings, netwo
 	if err != nil {
 		return "", err
 	}
-	preReleaseURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases", constants.LuxOrg, constants.LuxdRepoName)
-	latestPreReleaseVersion, err := app.Downloader.GetLatestPreReleaseVersion(preReleaseURL)
+	latestPreReleaseVersion, err := latestPreRelease(app, luxdReleasesURL())
 	if err != nil {
 		return "", err
 	}
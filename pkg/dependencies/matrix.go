@@ -0,0 +1,241 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dependencies
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+)
+
+const (
+	// VersionMatrixFile is the file of the base dir caching the documents
+	// luxd and EVM versions are resolved from
+	VersionMatrixFile = "version-matrix.json"
+	// VersionMatrixTTL is how long cached documents are used before being
+	// fetched again
+	VersionMatrixTTL = 24 * time.Hour
+	// OfflineConfigKey is the config setting pinning versions to the
+	// cached documents, which are then never fetched
+	OfflineConfigKey = "offline"
+	// OfflineEnvVar set to true pins versions to the cache for one command
+	OfflineEnvVar = "LUX_OFFLINE"
+
+	releasePrefix    = "release:"
+	preReleasePrefix = "prerelease:"
+)
+
+// ErrNotCached is returned offline for documents missing from the cache
+var ErrNotCached = errors.New("not in the version matrix cache")
+
+// MatrixEntry is a cached document of the version matrix
+type MatrixEntry struct {
+	Fetched time.Time `json:"fetched"`
+	Data    string    `json:"data"`
+}
+
+// Stale tells whether e is older than VersionMatrixTTL
+func (e MatrixEntry) Stale(now time.Time) bool {
+	return now.Sub(e.Fetched) > VersionMatrixTTL
+}
+
+// VersionMatrix caches the documents compatible versions are resolved
+// from: the CLI dependency versions, the luxd RPC compatibility, and the
+// latest luxd releases. Entries are keyed by the URL they come from, with
+// release: or prerelease: in front of the release URLs.
+type VersionMatrix struct {
+	Entries map[string]MatrixEntry `json:"entries"`
+}
+
+// VersionMatrixPath returns the path of the version matrix of baseDir
+func VersionMatrixPath(baseDir string) string {
+	return filepath.Join(baseDir, VersionMatrixFile)
+}
+
+// LoadVersionMatrix returns the version matrix of baseDir, empty when it
+// has none
+func LoadVersionMatrix(baseDir string) (*VersionMatrix, error) {
+	m := &VersionMatrix{Entries: map[string]MatrixEntry{}}
+	data, err := os.ReadFile(VersionMatrixPath(baseDir)) //nolint:gosec // G304: Reading from app's data directory
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("invalid version matrix %s, remove it or run 'lux update sync': %w", VersionMatrixPath(baseDir), err)
+	}
+	if m.Entries == nil {
+		m.Entries = map[string]MatrixEntry{}
+	}
+	return m, nil
+}
+
+// Save writes m to baseDir
+func (m *VersionMatrix) Save(baseDir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(VersionMatrixPath(baseDir), data, constants.WriteReadReadPerms)
+}
+
+// Keys returns the keys of the entries of m, sorted
+func (m *VersionMatrix) Keys() []string {
+	keys := make([]string, 0, len(m.Entries))
+	for k := range m.Entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Offline tells whether versions are pinned to the version matrix cache,
+// by 'lux config offline enable' or LUX_OFFLINE
+func Offline(app *application.Lux) bool {
+	if v, err := strconv.ParseBool(os.Getenv(OfflineEnvVar)); err == nil {
+		return v
+	}
+	return app.Conf != nil && app.Conf.GetConfigBoolValue(OfflineConfigKey)
+}
+
+// matrixSources are the documents 'lux update sync' fetches
+func matrixSources() []string {
+	return []string{
+		constants.CLILatestDependencyURL,
+		constants.LuxdCompatibilityURL,
+		releasePrefix + luxdReleaseURL(),
+		preReleasePrefix + luxdReleasesURL(),
+	}
+}
+
+func luxdReleaseURL() string {
+	return fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/latest", constants.LuxOrg, constants.LuxdRepoName)
+}
+
+func luxdReleasesURL() string {
+	return fmt.Sprintf("https://api.github.com/repos/%s/%s/releases", constants.LuxOrg, constants.LuxdRepoName)
+}
+
+// fetchSource fetches the document of the matrix key
+func fetchSource(app *application.Lux, key string) (string, error) {
+	switch {
+	case strings.HasPrefix(key, releasePrefix):
+		return app.Downloader.GetLatestReleaseVersion(strings.TrimPrefix(key, releasePrefix))
+	case strings.HasPrefix(key, preReleasePrefix):
+		return app.Downloader.GetLatestPreReleaseVersion(strings.TrimPrefix(key, preReleasePrefix))
+	default:
+		data, err := app.Downloader.Download(key)
+		return string(data), err
+	}
+}
+
+// SyncVersionMatrix fetches every document of the version matrix again and
+// caches those it got. It returns the matrix and the errors of the others,
+// by key.
+func SyncVersionMatrix(app *application.Lux) (*VersionMatrix, map[string]error, error) {
+	m, err := LoadVersionMatrix(app.GetBaseDir())
+	if err != nil {
+		m = &VersionMatrix{Entries: map[string]MatrixEntry{}}
+	}
+	failed := map[string]error{}
+	for _, key := range matrixSources() {
+		data, err := fetchSource(app, key)
+		if err != nil {
+			failed[key] = err
+			continue
+		}
+		m.Entries[key] = MatrixEntry{Fetched: time.Now().UTC(), Data: data}
+	}
+	if len(failed) < len(matrixSources()) {
+		if err := m.Save(app.GetBaseDir()); err != nil {
+			return nil, nil, err
+		}
+	}
+	return m, failed, nil
+}
+
+// cached returns the document of the matrix key, from the cache unless it
+// is older than VersionMatrixTTL. Offline, only cached documents are used,
+// however old. When fetching fails, the cached document is used, stale.
+// Without a base dir nothing is cached.
+func cached(app *application.Lux, key string) (string, error) {
+	baseDir := app.GetBaseDir()
+	if baseDir == "" {
+		return fetchSource(app, key)
+	}
+	m, err := LoadVersionMatrix(baseDir)
+	if err != nil {
+		return "", err
+	}
+	entry, ok := m.Entries[key]
+	if Offline(app) {
+		if !ok {
+			return "", fmt.Errorf("offline: %s is %w, run 'lux update sync' while online, or 'lux config offline disable'", SourceName(key), ErrNotCached)
+		}
+		return entry.Data, nil
+	}
+	if ok && !entry.Stale(time.Now()) {
+		return entry.Data, nil
+	}
+	data, err := fetchSource(app, key)
+	if err != nil {
+		if !ok {
+			return "", err
+		}
+		ux.Logger.PrintToUser("Failed to fetch %s, using the version matrix cached %s ago: %s",
+			SourceName(key), time.Since(entry.Fetched).Round(time.Minute), err)
+		return entry.Data, nil
+	}
+	m.Entries[key] = MatrixEntry{Fetched: time.Now().UTC(), Data: data}
+	if err := m.Save(baseDir); err != nil {
+		return "", err
+	}
+	return data, nil
+}
+
+// SourceName describes the version matrix key for users
+func SourceName(key string) string {
+	switch {
+	case strings.HasPrefix(key, releasePrefix):
+		return "the latest release of " + strings.TrimPrefix(key, releasePrefix)
+	case strings.HasPrefix(key, preReleasePrefix):
+		return "the latest pre-release of " + strings.TrimPrefix(key, preReleasePrefix)
+	default:
+		return key
+	}
+}
+
+// download returns the document of url through the version matrix cache
+func download(app *application.Lux, url string) ([]byte, error) {
+	data, err := cached(app, url)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(data), nil
+}
+
+// latestRelease returns the latest release of releaseURL through the
+// version matrix cache
+func latestRelease(app *application.Lux, releaseURL string) (string, error) {
+	return cached(app, releasePrefix+releaseURL)
+}
+
+// latestPreRelease returns the latest pre-release of releasesURL through
+// the version matrix cache
+func latestPreRelease(app *application.Lux, releasesURL string) (string, error) {
+	return cached(app, preReleasePrefix+releasesURL)
+}
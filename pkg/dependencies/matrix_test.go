@@ -0,0 +1,90 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dependencies
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/luxfi/cli/internal/mocks"
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/constants"
+	luxlog "github.com/luxfi/log"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionMatrixCache(t *testing.T) {
+	require := require.New(t)
+	ux.NewUserLog(luxlog.NewNoOpLogger(), io.Discard)
+	t.Setenv(OfflineEnvVar, "")
+	app := application.New()
+	app.Setup(t.TempDir(), luxlog.NewNoOpLogger(), nil, nil, nil)
+
+	online := &mocks.Downloader{}
+	online.On("Download", constants.LuxdCompatibilityURL).Return(testLuxdCompat, nil)
+	online.On("GetLatestReleaseVersion", mock.Anything).Return("v1.9.1", nil)
+	app.Downloader = online
+
+	// fetched once, then served from the cache
+	for i := 0; i < 2; i++ {
+		versions, err := GetLuxdVersionsForRPC(app, 19, constants.LuxdCompatibilityURL)
+		require.NoError(err)
+		require.Equal([]string{"v1.9.2"}, versions)
+	}
+	online.AssertNumberOfCalls(t, "Download", 1)
+
+	// the versions of an RPC are all excluded by the latest release
+	_, err := GetAvailableLuxdVersions(app, 19, constants.LuxdCompatibilityURL)
+	require.ErrorIs(err, ErrNoLuxdVersion)
+	require.ErrorContains(err, "v1.9.2, are all newer than the latest luxd release v1.9.1")
+	_, err = GetAvailableLuxdVersions(app, 20, constants.LuxdCompatibilityURL)
+	require.ErrorIs(err, ErrNoLuxdVersion)
+	require.ErrorContains(err, "no luxd version for RPC protocol 20, only for 17, 18, 19")
+
+	// stale entries are used when fetching fails
+	m, err := LoadVersionMatrix(app.GetBaseDir())
+	require.NoError(err)
+	entry := m.Entries[constants.LuxdCompatibilityURL]
+	entry.Fetched = time.Now().Add(-2 * VersionMatrixTTL)
+	m.Entries[constants.LuxdCompatibilityURL] = entry
+	require.NoError(m.Save(app.GetBaseDir()))
+	failing := &mocks.Downloader{}
+	failing.On("Download", mock.Anything).Return(nil, errors.New("no network"))
+	app.Downloader = failing
+	versions, err := GetLuxdVersionsForRPC(app, 18, constants.LuxdCompatibilityURL)
+	require.NoError(err)
+	require.Equal([]string{"v1.9.1"}, versions)
+	_, err = download(app, constants.CLILatestDependencyURL)
+	require.ErrorContains(err, "no network")
+
+	// offline, only the cache is used, however old
+	t.Setenv(OfflineEnvVar, "true")
+	require.True(Offline(app))
+	offline := &mocks.Downloader{}
+	app.Downloader = offline
+	versions, err = GetLuxdVersionsForRPC(app, 17, constants.LuxdCompatibilityURL)
+	require.NoError(err)
+	require.Equal([]string{"v1.8.0", "v1.9.0"}, versions)
+	_, err = download(app, constants.CLILatestDependencyURL)
+	require.ErrorIs(err, ErrNotCached)
+	require.ErrorContains(err, "lux update sync")
+	offline.AssertNotCalled(t, "Download", mock.Anything)
+
+	// sync fetches everything again
+	t.Setenv(OfflineEnvVar, "false")
+	app.Downloader = online
+	online.On("Download", constants.CLILatestDependencyURL).Return(testCLICompat, nil)
+	online.On("GetLatestPreReleaseVersion", mock.Anything).Return("v1.9.2-rc.1", nil)
+	m, failed, err := SyncVersionMatrix(app)
+	require.NoError(err)
+	require.Empty(failed)
+	require.Len(m.Keys(), 4)
+	for _, key := range m.Keys() {
+		require.False(m.Entries[key].Stale(time.Now()), key)
+	}
+}
@@ -16,7 +16,7 @@ import (
 )
 
 func CheckVersionIsOverMin(app *application.Lux, dependencyName string, network models.Network, version string) error {
-	dependencyBytes, err := app.Downloader.Download(constants.CLILatestDependencyURL)
+	dependencyBytes, err := download(app, constants.CLILatestDependencyURL)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,143 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package discovery advertises the networks started on a machine, so that
+// wallets, explorers and other CLIs find them: in a well-known directory
+// shared by the users of the machine, and over mDNS on the LAN.
+package discovery
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DirName is the directory of the temp dir advertising running networks
+const DirName = "lux-networks"
+
+// Network is a running network advertised to other tools
+type Network struct {
+	// Name is the network type: mainnet, testnet, devnet or custom
+	Name      string `json:"name"`
+	NetworkID uint32 `json:"networkId"`
+	// RPC is the API endpoint of the network, e.g. http://127.0.0.1:9630
+	RPC     string    `json:"rpc"`
+	User    string    `json:"user"`
+	Host    string    `json:"host"`
+	Started time.Time `json:"started"`
+	// Source is where the network was found: a file or an mDNS responder.
+	// It is not advertised.
+	Source string `json:"-"`
+}
+
+// Instance returns the name the network is advertised under
+func (n Network) Instance() string {
+	return unsafeChars.ReplaceAllString(n.User+"-"+n.Host+"-"+n.Name, "-")
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// DefaultDir returns the well-known directory of the machine advertising
+// running networks
+func DefaultDir() string {
+	return filepath.Join(os.TempDir(), DirName)
+}
+
+// NewNetwork returns the advertisement of the network name of networkID
+// served at rpc, started now by the current user
+func NewNetwork(name string, networkID uint32, rpc string) Network {
+	n := Network{Name: name, NetworkID: networkID, RPC: rpc, Started: time.Now().UTC()}
+	if u, err := user.Current(); err == nil {
+		n.User = u.Username
+	}
+	n.Host, _ = os.Hostname()
+	return n
+}
+
+// Publish advertises n in dir. The directory is shared by the users of the
+// machine, each advertisement stays writable by its owner only.
+func Publish(dir string, n Network) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:gosec // G301: readable by the other tools of the machine
+		return err
+	}
+	// Sticky like /tmp, every user adds files but only removes theirs
+	if info, err := os.Stat(dir); err == nil && info.Mode()&os.ModeSticky == 0 {
+		_ = os.Chmod(dir, 0o777|os.ModeSticky) //nolint:gosec // G302: shared directory, sticky
+	}
+	raw, err := json.MarshalIndent(n, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, n.Instance()+".json"), raw, 0o644) //nolint:gosec // G306: readable by the other tools of the machine
+}
+
+// Withdraw removes the advertisement of the network name of the current
+// user from dir
+func Withdraw(dir string, name string) error {
+	n := NewNetwork(name, 0, "")
+	err := os.Remove(filepath.Join(dir, n.Instance()+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// List returns the networks advertised in dir, by instance name
+func List(dir string) ([]Network, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var networks []Network
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		raw, err := os.ReadFile(path) //nolint:gosec // G304: Reading advertisements of the well-known directory
+		if err != nil {
+			continue
+		}
+		var n Network
+		if err := json.Unmarshal(raw, &n); err != nil || n.RPC == "" {
+			continue
+		}
+		n.Source = path
+		networks = append(networks, n)
+	}
+	sortNetworks(networks)
+	return networks, nil
+}
+
+// Merge returns the networks of lists, the first occurrence of an instance
+// winning
+func Merge(lists ...[]Network) []Network {
+	seen := map[string]bool{}
+	var merged []Network
+	for _, networks := range lists {
+		for _, n := range networks {
+			if seen[n.Instance()] {
+				continue
+			}
+			seen[n.Instance()] = true
+			merged = append(merged, n)
+		}
+	}
+	sortNetworks(merged)
+	return merged
+}
+
+func sortNetworks(networks []Network) {
+	sort.Slice(networks, func(i, j int) bool {
+		return networks[i].Instance() < networks[j].Instance()
+	})
+}
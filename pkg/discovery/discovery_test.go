@@ -0,0 +1,80 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package discovery
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func testNetwork(name string, port string) Network {
+	return Network{
+		Name:      name,
+		NetworkID: 1337,
+		RPC:       "http://127.0.0.1:" + port,
+		User:      "alice",
+		Host:      "laptop.lan",
+		Started:   time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestPublishList(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	networks, err := List(dir)
+	require.NoError(err)
+	require.Empty(networks)
+
+	n := NewNetwork("devnet", 3, "http://127.0.0.1:9650")
+	require.NoError(Publish(dir, n))
+	require.NoError(Publish(dir, testNetwork("testnet", "9640")))
+	networks, err = List(dir)
+	require.NoError(err)
+	require.Len(networks, 2)
+	require.Equal("alice-laptop-lan-testnet", networks[0].Instance())
+	require.Equal(n.RPC, networks[1].RPC)
+
+	require.NoError(Withdraw(dir, "devnet"))
+	require.NoError(Withdraw(dir, "devnet"))
+	networks, err = List(dir)
+	require.NoError(err)
+	require.Len(networks, 1)
+
+	merged := Merge(networks, []Network{testNetwork("testnet", "1"), testNetwork("mainnet", "9630")})
+	require.Len(merged, 2)
+	require.Equal("9640", merged[1].RPC[len(merged[1].RPC)-4:])
+}
+
+func TestResponse(t *testing.T) {
+	require := require.New(t)
+	msg, err := buildQuery()
+	require.NoError(err)
+	q, ok := parseQuery(msg)
+	require.True(ok)
+	require.True(q.unicast)
+
+	networks := []Network{testNetwork("mainnet", "9630"), testNetwork("testnet", "9640")}
+	resp, err := buildResponse(7, &q.question, networks, "laptop.lan", []net.IP{net.IPv4(192, 168, 1, 20)})
+	require.NoError(err)
+	_, ok = parseQuery(resp)
+	require.False(ok)
+
+	var p dnsmessage.Parser
+	h, err := p.Start(resp)
+	require.NoError(err)
+	require.Equal(uint16(7), h.ID)
+
+	found := parseResponse(resp, net.IPv4(192, 168, 1, 20))
+	require.Len(found, 2)
+	require.Equal("http://192.168.1.20:9630", found[0].RPC)
+	require.Equal(networks[0].Started, found[0].Started)
+	require.Equal(networks[1].Instance(), found[1].Instance())
+
+	found = parseResponse(resp, net.IPv4(127, 0, 0, 1))
+	require.Equal("http://127.0.0.1:9640", found[1].RPC)
+}
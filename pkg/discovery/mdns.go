@@ -0,0 +1,377 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// ServiceType is the DNS-SD service type networks are advertised as
+const ServiceType = "_lux-network._tcp.local."
+
+const (
+	mdnsPort = 5353
+	// recordTTL is the time to live of the advertised records, in seconds
+	recordTTL = 120
+	// unicastResponse is the class bit of a question asking for a unicast
+	// response, and of a record flushing the caches, in mDNS
+	unicastResponse = 1 << 15
+	maxPacketSize   = 9000
+)
+
+var mdnsGroup = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: mdnsPort}
+
+// Serve answers the mDNS queries for ServiceType with the networks returns,
+// until ctx is done
+func Serve(ctx context.Context, networks func() []Network) error {
+	conn, err := net.ListenMulticastUDP("udp4", nil, mdnsGroup)
+	if err != nil {
+		return fmt.Errorf("failed to listen for mDNS queries: %w", err)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+	host, _ := os.Hostname()
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, src, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		query, ok := parseQuery(buf[:n])
+		if !ok {
+			continue
+		}
+		advertised := networks()
+		if len(advertised) == 0 {
+			continue
+		}
+		// Queries not sent from the mDNS port come from plain DNS
+		// resolvers, expecting a direct answer to their question
+		dst, question, id := mdnsGroup, (*dnsmessage.Question)(nil), uint16(0)
+		if src.Port != mdnsPort {
+			dst, question, id = src, &query.question, query.id
+		} else if query.unicast {
+			dst = src
+		}
+		resp, err := buildResponse(id, question, advertised, host, hostAddresses())
+		if err != nil {
+			return err
+		}
+		_, _ = conn.WriteToUDP(resp, dst)
+	}
+}
+
+// Browse queries the LAN for the networks advertised over mDNS, collecting
+// the answers for wait
+func Browse(ctx context.Context, wait time.Duration) ([]Network, error) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4zero})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+	query, err := buildQuery()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.WriteToUDP(query, mdnsGroup); err != nil {
+		return nil, fmt.Errorf("failed to send the mDNS query: %w", err)
+	}
+	if err := conn.SetReadDeadline(time.Now().Add(wait)); err != nil {
+		return nil, err
+	}
+	found := map[string]Network{}
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, src, err := conn.ReadFromUDP(buf)
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			break
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+		for _, network := range parseResponse(buf[:n], src.IP) {
+			found[network.Instance()] = network
+		}
+	}
+	networks := make([]Network, 0, len(found))
+	for _, n := range found {
+		networks = append(networks, n)
+	}
+	sortNetworks(networks)
+	return networks, nil
+}
+
+// query is an mDNS query for ServiceType
+type query struct {
+	id       uint16
+	question dnsmessage.Question
+	unicast  bool
+}
+
+// parseQuery returns the question of msg for ServiceType, if it asks for
+// it
+func parseQuery(msg []byte) (query, bool) {
+	var p dnsmessage.Parser
+	h, err := p.Start(msg)
+	if err != nil || h.Response {
+		return query{}, false
+	}
+	questions, err := p.AllQuestions()
+	if err != nil {
+		return query{}, false
+	}
+	for _, q := range questions {
+		if q.Type != dnsmessage.TypePTR && q.Type != dnsmessage.TypeALL {
+			continue
+		}
+		if !strings.EqualFold(q.Name.String(), ServiceType) {
+			continue
+		}
+		unicast := q.Class&unicastResponse != 0
+		q.Class &^= unicastResponse
+		return query{id: h.ID, question: q, unicast: unicast}, true
+	}
+	return query{}, false
+}
+
+func buildQuery() ([]byte, error) {
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{})
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(dnsmessage.Question{
+		Name:  dnsmessage.MustNewName(ServiceType),
+		Type:  dnsmessage.TypePTR,
+		Class: dnsmessage.ClassINET | unicastResponse,
+	}); err != nil {
+		return nil, err
+	}
+	return b.Finish()
+}
+
+// buildResponse answers a query for ServiceType with networks: a PTR
+// record per network, and the SRV and TXT records describing it, and the
+// A records of host
+func buildResponse(id uint16, question *dnsmessage.Question, networks []Network, host string, addrs []net.IP) ([]byte, error) {
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: id, Response: true, Authoritative: true})
+	b.EnableCompression()
+	if question != nil {
+		if err := b.StartQuestions(); err != nil {
+			return nil, err
+		}
+		if err := b.Question(*question); err != nil {
+			return nil, err
+		}
+	}
+	service := dnsmessage.MustNewName(ServiceType)
+	target, err := dnsmessage.NewName(unsafeChars.ReplaceAllString(host, "-") + ".local.")
+	if err != nil {
+		return nil, err
+	}
+	instances := make([]dnsmessage.Name, 0, len(networks))
+	for _, n := range networks {
+		instance, err := dnsmessage.NewName(n.Instance() + "." + ServiceType)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, instance)
+	}
+
+	if err := b.StartAnswers(); err != nil {
+		return nil, err
+	}
+	for _, instance := range instances {
+		if err := b.PTRResource(resourceHeader(service, false), dnsmessage.PTRResource{PTR: instance}); err != nil {
+			return nil, err
+		}
+	}
+	if err := b.StartAdditionals(); err != nil {
+		return nil, err
+	}
+	for i, n := range networks {
+		if err := b.SRVResource(resourceHeader(instances[i], true), dnsmessage.SRVResource{
+			Target: target,
+			Port:   rpcPort(n.RPC),
+		}); err != nil {
+			return nil, err
+		}
+		if err := b.TXTResource(resourceHeader(instances[i], true), dnsmessage.TXTResource{TXT: txtRecords(n)}); err != nil {
+			return nil, err
+		}
+	}
+	for _, addr := range addrs {
+		ip4 := addr.To4()
+		if ip4 == nil {
+			continue
+		}
+		if err := b.AResource(resourceHeader(target, true), dnsmessage.AResource{A: [4]byte(ip4)}); err != nil {
+			return nil, err
+		}
+	}
+	return b.Finish()
+}
+
+func resourceHeader(name dnsmessage.Name, unique bool) dnsmessage.ResourceHeader {
+	class := dnsmessage.ClassINET
+	if unique {
+		class |= unicastResponse
+	}
+	return dnsmessage.ResourceHeader{Name: name, Class: class, TTL: recordTTL}
+}
+
+// parseResponse returns the networks described by the TXT records of the
+// mDNS response msg, sent by src. Loopback endpoints of networks advertised
+// by another machine are rewritten to the address of the machine.
+func parseResponse(msg []byte, src net.IP) []Network {
+	var p dnsmessage.Parser
+	h, err := p.Start(msg)
+	if err != nil || !h.Response {
+		return nil
+	}
+	if err := p.SkipAllQuestions(); err != nil {
+		return nil
+	}
+	answers, err := p.AllAnswers()
+	if err != nil {
+		return nil
+	}
+	if err := p.SkipAllAuthorities(); err != nil {
+		return nil
+	}
+	additionals, err := p.AllAdditionals()
+	if err != nil {
+		return nil
+	}
+	var networks []Network
+	for _, r := range append(answers, additionals...) {
+		txt, ok := r.Body.(*dnsmessage.TXTResource)
+		if !ok || !strings.HasSuffix(strings.ToLower(r.Header.Name.String()), ServiceType) {
+			continue
+		}
+		n, ok := parseTXT(txt.TXT)
+		if !ok {
+			continue
+		}
+		if !src.IsLoopback() {
+			n.RPC = rewriteLoopback(n.RPC, src)
+		}
+		n.Source = "mDNS " + src.String()
+		networks = append(networks, n)
+	}
+	return networks
+}
+
+func txtRecords(n Network) []string {
+	return []string{
+		"name=" + n.Name,
+		"networkid=" + strconv.FormatUint(uint64(n.NetworkID), 10),
+		"rpc=" + n.RPC,
+		"user=" + n.User,
+		"host=" + n.Host,
+		"started=" + n.Started.UTC().Format(time.RFC3339),
+	}
+}
+
+func parseTXT(records []string) (Network, bool) {
+	var n Network
+	for _, record := range records {
+		key, value, _ := strings.Cut(record, "=")
+		switch strings.ToLower(key) {
+		case "name":
+			n.Name = value
+		case "networkid":
+			id, _ := strconv.ParseUint(value, 10, 32)
+			n.NetworkID = uint32(id)
+		case "rpc":
+			n.RPC = value
+		case "user":
+			n.User = value
+		case "host":
+			n.Host = value
+		case "started":
+			n.Started, _ = time.Parse(time.RFC3339, value)
+		}
+	}
+	return n, n.Name != "" && n.RPC != ""
+}
+
+// rpcPort returns the port of the endpoint rpc
+func rpcPort(rpc string) uint16 {
+	u, err := url.Parse(rpc)
+	if err != nil {
+		return 0
+	}
+	port, _ := strconv.ParseUint(u.Port(), 10, 16)
+	return uint16(port)
+}
+
+// rewriteLoopback replaces the loopback host of the endpoint rpc with ip
+func rewriteLoopback(rpc string, ip net.IP) string {
+	u, err := url.Parse(rpc)
+	if err != nil {
+		return rpc
+	}
+	host := u.Hostname()
+	if host != "localhost" {
+		if hostIP := net.ParseIP(host); hostIP == nil || !hostIP.IsLoopback() {
+			return rpc
+		}
+	}
+	if port := u.Port(); port != "" {
+		u.Host = net.JoinHostPort(ip.String(), port)
+	} else {
+		u.Host = ip.String()
+	}
+	return u.String()
+}
+
+// hostAddresses returns the non-loopback IPv4 addresses of the machine
+func hostAddresses() []net.IP {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil
+	}
+	var ips []net.IP
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() || ipNet.IP.To4() == nil {
+			continue
+		}
+		ips = append(ips, ipNet.IP)
+	}
+	return ips
+}
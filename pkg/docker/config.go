@@ -5,7 +5,7 @@ package docker
 
 import (
 	"os"
-	"path/filepath"
+	"path"
 	"strings"
 
 	"github.com/luxfi/cli/pkg/remoteconfig"
@@ -42,10 +42,10 @@ func prepareLuxgoConfig(
 		luxdConf.BootstrapIDs = strings.Join(luxdConfig.BootstrapIDs, ",")
 	}
 	if luxdConfig.GenesisPath != "" {
-		luxdConf.GenesisPath = filepath.Join(constants.DockerNodeConfigPath, constants.GenesisFileName)
+		luxdConf.GenesisPath = path.Join(constants.DockerNodeConfigPath, constants.GenesisFileName)
 	}
 	if luxdConfig.UpgradePath != "" {
-		luxdConf.UpgradePath = filepath.Join(constants.DockerNodeConfigPath, constants.UpgradeFileName)
+		luxdConf.UpgradePath = path.Join(constants.DockerNodeConfigPath, constants.UpgradeFileName)
 	}
 	nodeConf, err := remoteconfig.RenderLuxNodeConfig(luxdConf)
 	if err != nil {
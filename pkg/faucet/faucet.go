@@ -0,0 +1,283 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package faucet funds addresses on the chains of a local network from its
+// prefunded keys. Each address is funded at most once per cooldown on each
+// chain, and the amount of a request is capped.
+package faucet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/luxfi/geth/common"
+)
+
+const (
+	// StateFile is the name of the file recording when addresses were funded
+	StateFile = "faucet.json"
+	// DefaultAmount is the amount sent when a request has none
+	DefaultAmount = "10"
+	// DefaultMaxAmount is the largest amount of a request
+	DefaultMaxAmount = "100"
+	// DefaultCooldown is how long an address waits between fundings
+	DefaultCooldown = time.Hour
+)
+
+var (
+	ErrUnknownChain   = errors.New("unknown chain")
+	ErrInvalidAmount  = errors.New("invalid amount")
+	ErrInvalidAddress = errors.New("invalid address")
+	ErrSendFailed     = errors.New("failed to fund")
+)
+
+// RateLimitError is returned when an address was funded less than a
+// cooldown ago
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("address was funded recently, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+// Dispenser sends the native token of a chain
+type Dispenser interface {
+	// Decimals returns the decimals of the amounts sent
+	Decimals() int
+	// ValidateAddress checks that to is an address of the chain
+	ValidateAddress(to string) error
+	// Send sends amount, in the smallest unit, to to and returns the ID of
+	// the transaction
+	Send(ctx context.Context, to string, amount *big.Int) (string, error)
+}
+
+// Limiter records when addresses were last funded. With a path, the records
+// are saved after each change so they outlive the process.
+type Limiter struct {
+	mu       sync.Mutex
+	path     string
+	cooldown time.Duration
+	last     map[string]time.Time
+}
+
+// LoadLimiter returns a limiter with the records saved at path, if any. An
+// empty path keeps the records in memory.
+func LoadLimiter(path string, cooldown time.Duration) (*Limiter, error) {
+	l := &Limiter{path: path, cooldown: cooldown, last: map[string]time.Time{}}
+	if path == "" {
+		return l, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &l.last); err != nil {
+		return nil, fmt.Errorf("invalid faucet state %s: %w", path, err)
+	}
+	return l, nil
+}
+
+// Reserve records key as funded at now, or returns a RateLimitError when it
+// was funded less than a cooldown ago
+func (l *Limiter) Reserve(key string, now time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if last, ok := l.last[key]; ok {
+		if wait := l.cooldown - now.Sub(last); wait > 0 {
+			return &RateLimitError{RetryAfter: wait}
+		}
+	}
+	l.last[key] = now
+	return l.save()
+}
+
+// Release drops the record of key, after a failed funding
+func (l *Limiter) Release(key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.last, key)
+	return l.save()
+}
+
+func (l *Limiter) save() error {
+	if l.path == "" {
+		return nil
+	}
+	// expired records are dropped so the file does not grow forever
+	for key, last := range l.last {
+		if time.Since(last) > l.cooldown {
+			delete(l.last, key)
+		}
+	}
+	data, err := json.MarshalIndent(l.last, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(l.path, data, 0o600)
+}
+
+// Receipt is a funding
+type Receipt struct {
+	Chain   string `json:"chain"`
+	Address string `json:"address"`
+	Amount  string `json:"amount"`
+	TxID    string `json:"txID"`
+}
+
+// Faucet funds addresses on its chains
+type Faucet struct {
+	dispensers map[string]Dispenser
+	limiter    *Limiter
+	maxAmount  string
+	now        func() time.Time
+}
+
+// New returns a faucet sending with the dispensers of the chains, named
+// e.g. p, x, c or an L1, at most maxAmount per request
+func New(dispensers map[string]Dispenser, limiter *Limiter, maxAmount string) *Faucet {
+	normalized := make(map[string]Dispenser, len(dispensers))
+	for chain, d := range dispensers {
+		normalized[strings.ToLower(chain)] = d
+	}
+	return &Faucet{dispensers: normalized, limiter: limiter, maxAmount: maxAmount, now: time.Now}
+}
+
+// Chains returns the names of the chains of the faucet, sorted
+func (f *Faucet) Chains() []string {
+	chains := make([]string, 0, len(f.dispensers))
+	for chain := range f.dispensers {
+		chains = append(chains, chain)
+	}
+	sort.Strings(chains)
+	return chains
+}
+
+// Send sends amount, in whole tokens e.g. "2.5", to the address to on chain
+func (f *Faucet) Send(ctx context.Context, chain, to, amount string) (Receipt, error) {
+	chain = strings.ToLower(chain)
+	d, ok := f.dispensers[chain]
+	if !ok {
+		return Receipt{}, fmt.Errorf("%w %q, use one of %s", ErrUnknownChain, chain, strings.Join(f.Chains(), ", "))
+	}
+	if amount == "" {
+		amount = DefaultAmount
+	}
+	value, err := ParseAmount(amount, d.Decimals())
+	if err != nil {
+		return Receipt{}, err
+	}
+	maxValue, err := ParseAmount(f.maxAmount, d.Decimals())
+	if err != nil {
+		return Receipt{}, err
+	}
+	if value.Cmp(maxValue) > 0 {
+		return Receipt{}, fmt.Errorf("%w: at most %s per request", ErrInvalidAmount, f.maxAmount)
+	}
+	if err := d.ValidateAddress(to); err != nil {
+		return Receipt{}, fmt.Errorf("%w %q: %w", ErrInvalidAddress, to, err)
+	}
+
+	key := limiterKey(chain, to)
+	if err := f.limiter.Reserve(key, f.now()); err != nil {
+		return Receipt{}, err
+	}
+	txID, err := d.Send(ctx, to, value)
+	if err != nil {
+		_ = f.limiter.Release(key)
+		return Receipt{}, fmt.Errorf("%w %s on %s: %w", ErrSendFailed, to, chain, err)
+	}
+	return Receipt{Chain: chain, Address: to, Amount: amount, TxID: txID}, nil
+}
+
+// limiterKey returns the key rate limiting to on chain. EVM addresses are
+// checksummed so they're limited once, with or without 0x and whatever their
+// case.
+func limiterKey(chain, to string) string {
+	if common.IsHexAddress(to) {
+		return chain + "/" + common.HexToAddress(to).Hex()
+	}
+	return chain + "/" + strings.ToLower(to)
+}
+
+// ParseAmount converts amount, in whole tokens, to the smallest unit of a
+// token with decimals, without rounding
+func ParseAmount(amount string, decimals int) (*big.Int, error) {
+	whole, frac, _ := strings.Cut(strings.TrimSpace(amount), ".")
+	if len(frac) > decimals {
+		return nil, fmt.Errorf("%w %q: more than %d decimals", ErrInvalidAmount, amount, decimals)
+	}
+	value, ok := new(big.Int).SetString(whole+frac+strings.Repeat("0", decimals-len(frac)), 10)
+	if !ok || value.Sign() <= 0 || strings.ContainsAny(whole+frac, "+-") {
+		return nil, fmt.Errorf("%w %q", ErrInvalidAmount, amount)
+	}
+	return value, nil
+}
+
+// request is the body of a funding request
+type request struct {
+	Chain   string `json:"chain"`
+	Address string `json:"address"`
+	Amount  string `json:"amount"`
+}
+
+// ServeHTTP serves GET /chains, listing the chains and the largest amount,
+// and POST /fund, funding the address of the JSON body
+func (f *Faucet) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/chains" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"chains": f.Chains(), "maxAmount": f.maxAmount})
+	case r.URL.Path == "/fund" && r.Method == http.MethodPost:
+		var req request
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		if req.Chain == "" {
+			req.Chain = "c"
+		}
+		receipt, err := f.Send(r.Context(), req.Chain, req.Address, req.Amount)
+		var rateLimited *RateLimitError
+		switch {
+		case errors.As(err, &rateLimited):
+			w.Header().Set("Retry-After", fmt.Sprintf("%.0f", rateLimited.RetryAfter.Seconds()))
+			writeError(w, http.StatusTooManyRequests, err.Error())
+		case errors.Is(err, ErrUnknownChain), errors.Is(err, ErrInvalidAmount), errors.Is(err, ErrInvalidAddress):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, ErrSendFailed):
+			writeError(w, http.StatusBadGateway, err.Error())
+		case err != nil:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		default:
+			writeJSON(w, http.StatusOK, receipt)
+		}
+	default:
+		writeError(w, http.StatusNotFound, "use GET /chains or POST /fund")
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
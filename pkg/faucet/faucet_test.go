@@ -0,0 +1,158 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package faucet
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDispenser struct {
+	decimals int
+	sent     []string
+	err      error
+}
+
+func (d *fakeDispenser) Decimals() int {
+	return d.decimals
+}
+
+func (d *fakeDispenser) ValidateAddress(to string) error {
+	if !strings.HasPrefix(to, "0x") {
+		return errors.New("not a 0x address")
+	}
+	return nil
+}
+
+func (d *fakeDispenser) Send(_ context.Context, to string, amount *big.Int) (string, error) {
+	if d.err != nil {
+		return "", d.err
+	}
+	d.sent = append(d.sent, to+" "+amount.String())
+	return "0xtx", nil
+}
+
+func TestLimiterKeyOfEVMAddress(t *testing.T) {
+	require := require.New(t)
+	addr := "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"
+	key := limiterKey("c", addr)
+	require.Equal(key, limiterKey("c", strings.ToLower(addr)))
+	require.Equal(key, limiterKey("c", strings.TrimPrefix(addr, "0x")))
+	require.Equal(key, limiterKey("c", "0X"+strings.ToUpper(addr[2:])))
+	require.NotEqual(key, limiterKey("l1", addr))
+	require.Equal("p/p-lux1abc", limiterKey("p", "P-lux1abc"))
+}
+
+func TestSend(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), StateFile)
+	limiter, err := LoadLimiter(path, time.Hour)
+	require.NoError(err)
+	c := &fakeDispenser{decimals: 18}
+	p := &fakeDispenser{decimals: 9}
+	f := New(map[string]Dispenser{"C": c, "p": p}, limiter, DefaultMaxAmount)
+	now := time.Now()
+	f.now = func() time.Time { return now }
+
+	require.Equal([]string{"c", "p"}, f.Chains())
+	receipt, err := f.Send(context.Background(), "c", "0xabc", "")
+	require.NoError(err)
+	require.Equal(Receipt{Chain: "c", Address: "0xabc", Amount: DefaultAmount, TxID: "0xtx"}, receipt)
+	require.Equal([]string{"0xabc 10000000000000000000"}, c.sent)
+
+	// the address is rate limited on that chain, whatever its case
+	_, err = f.Send(context.Background(), "C", "0xABC", "1")
+	var rateLimited *RateLimitError
+	require.ErrorAs(err, &rateLimited)
+	require.Equal(time.Hour, rateLimited.RetryAfter)
+	_, err = f.Send(context.Background(), "p", "0xabc", "2.5")
+	require.NoError(err)
+	require.Equal([]string{"0xabc 2500000000"}, p.sent)
+
+	// the records outlive the process
+	limiter, err = LoadLimiter(path, time.Hour)
+	require.NoError(err)
+	f = New(map[string]Dispenser{"c": c}, limiter, DefaultMaxAmount)
+	f.now = func() time.Time { return now.Add(30 * time.Minute) }
+	_, err = f.Send(context.Background(), "c", "0xabc", "1")
+	require.ErrorAs(err, &rateLimited)
+	require.Equal(30*time.Minute, rateLimited.RetryAfter)
+	f.now = func() time.Time { return now.Add(time.Hour) }
+	_, err = f.Send(context.Background(), "c", "0xabc", "1")
+	require.NoError(err)
+
+	_, err = f.Send(context.Background(), "x", "0xabc", "1")
+	require.ErrorIs(err, ErrUnknownChain)
+	_, err = f.Send(context.Background(), "c", "0xdef", "101")
+	require.ErrorIs(err, ErrInvalidAmount)
+	_, err = f.Send(context.Background(), "c", "lux1def", "1")
+	require.ErrorIs(err, ErrInvalidAddress)
+
+	// a failed funding does not count against the address
+	c.err = errors.New("insufficient funds")
+	_, err = f.Send(context.Background(), "c", "0xdef", "1")
+	require.ErrorIs(err, ErrSendFailed)
+	c.err = nil
+	_, err = f.Send(context.Background(), "c", "0xdef", "1")
+	require.NoError(err)
+}
+
+func TestParseAmount(t *testing.T) {
+	require := require.New(t)
+	v, err := ParseAmount("1.5", 9)
+	require.NoError(err)
+	require.Equal(big.NewInt(1_500_000_000), v)
+	v, err = ParseAmount(".000000001", 9)
+	require.NoError(err)
+	require.Equal(big.NewInt(1), v)
+	for _, bad := range []string{"", "0", "-1", "+1", "1e9", "0.0000000001", "abc"} {
+		_, err = ParseAmount(bad, 9)
+		require.ErrorIs(err, ErrInvalidAmount, bad)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	require := require.New(t)
+	limiter, err := LoadLimiter("", time.Hour)
+	require.NoError(err)
+	c := &fakeDispenser{decimals: 18}
+	server := httptest.NewServer(New(map[string]Dispenser{"c": c}, limiter, "5"))
+	defer server.Close()
+
+	post := func(body string) (*http.Response, string) {
+		resp, err := http.Post(server.URL+"/fund", "application/json", strings.NewReader(body))
+		require.NoError(err)
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(err)
+		return resp, string(data)
+	}
+
+	resp, body := post(`{"address":"0xabc","amount":"5"}`)
+	require.Equal(http.StatusOK, resp.StatusCode)
+	require.JSONEq(`{"chain":"c","address":"0xabc","amount":"5","txID":"0xtx"}`, body)
+	resp, _ = post(`{"address":"0xabc","amount":"1"}`)
+	require.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	require.Equal("3600", resp.Header.Get("Retry-After"))
+	resp, body = post(`{"address":"0xdef","amount":"6"}`)
+	require.Equal(http.StatusBadRequest, resp.StatusCode)
+	require.Contains(body, "at most 5 per request")
+	resp, _ = post(`not json`)
+	require.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "/chains")
+	require.NoError(err)
+	defer resp.Body.Close()
+	require.Equal(http.StatusOK, resp.StatusCode)
+}
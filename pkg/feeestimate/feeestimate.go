@@ -0,0 +1,318 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package feeestimate estimates the P-Chain fees of chain and validator
+// operations from the dynamic fee state of a network, and the continuous fee
+// L1 validators pay from their balance.
+package feeestimate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/luxfi/constants"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/protocol/p/signer"
+	"github.com/luxfi/protocol/p/txs"
+	"github.com/luxfi/protocol/p/txs/fee"
+	"github.com/luxfi/protocol/p/warp"
+	"github.com/luxfi/protocol/p/warp/message"
+	"github.com/luxfi/sdk/models"
+	"github.com/luxfi/sdk/platformvm"
+	"github.com/luxfi/sdk/wallet/chain/p"
+	lux "github.com/luxfi/utxo"
+	"github.com/luxfi/utxo/secp256k1fx"
+	"github.com/luxfi/vm/components/gas"
+)
+
+// Op is an operation whose fees are estimated
+type Op string
+
+const (
+	// CreateChain creates a network and its chain
+	CreateChain Op = "create-chain"
+	// ConvertL1 converts a network to an L1 with its bootstrap validators
+	ConvertL1 Op = "convert-l1"
+	// AddValidator registers validators with an L1
+	AddValidator Op = "add-validator"
+)
+
+// Ops are the operations Estimate supports
+var Ops = []Op{CreateChain, ConvertL1, AddValidator}
+
+const (
+	// Day is the period continuous fees are reported over
+	Day = 24 * time.Hour
+
+	// DefaultGenesisSize is the genesis size, in bytes, assumed for the
+	// CreateChainTx when none is given
+	DefaultGenesisSize = 4096
+
+	// registerL1ValidatorPayloadSize is the size of the AddressedCall of a
+	// RegisterL1Validator message with one address per owner
+	registerL1ValidatorPayloadSize = 220
+	// l1ValidatorWeightPayloadSize is the size of the AddressedCall of an
+	// L1ValidatorWeight message
+	l1ValidatorWeightPayloadSize = 94
+	// messageSigners is the number of validators assumed to sign the Warp
+	// messages of L1 validator txs. Each signer weighs little on the fee.
+	messageSigners = 16
+)
+
+var (
+	ErrUnknownOp  = errors.New("unknown operation")
+	ErrValidators = errors.New("invalid number of validators")
+)
+
+// State is the dynamic fee state of the P-Chain
+type State struct {
+	// Weights turn the complexity of a tx into gas
+	Weights gas.Dimensions
+	// GasPrice is the price of gas in nLUX, with the margin wallets build
+	// txs with
+	GasPrice gas.Price
+	// ValidatorFeePrice is what each L1 validator pays per second from its
+	// balance, in nLUX
+	ValidatorFeePrice gas.Price
+}
+
+// Fetch returns the fee state of the P-Chain served at uri
+func Fetch(ctx context.Context, uri string) (*State, error) {
+	// the context wallets build txs with, so fees are estimated as paid
+	pctx, err := p.NewContextFromURI(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the fee state of %s: %w", uri, err)
+	}
+	_, validatorFeePrice, _, err := platformvm.NewClient(uri).GetValidatorFeeState(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the L1 validator fee state of %s: %w", uri, err)
+	}
+	return &State{
+		Weights:           pctx.ComplexityWeights,
+		GasPrice:          pctx.GasPrice,
+		ValidatorFeePrice: validatorFeePrice,
+	}, nil
+}
+
+// ForNetwork returns the fee state of the P-Chain of network
+func ForNetwork(network models.Network) (*State, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), constants.RequestTimeout)
+	defer cancel()
+	return Fetch(ctx, network.Endpoint())
+}
+
+// Options describe the operation estimated
+type Options struct {
+	// Validators is the number of validators converted or added
+	Validators int
+	// Balance is the initial balance of each validator, in nLUX
+	Balance uint64
+	// GenesisSize is the size of the genesis of the chain created, in bytes
+	GenesisSize int
+}
+
+// TxFee is the fee of the txs of one type an operation issues
+type TxFee struct {
+	Type  string
+	Count int
+	// Fee is the fee of each tx, in nLUX
+	Fee uint64
+}
+
+// Estimate is the expected cost of an operation
+type Estimate struct {
+	Op  Op
+	Txs []TxFee
+	// Validators is the number of L1 validators the operation adds
+	Validators int
+	// Balance is the initial balance of each validator, in nLUX
+	Balance uint64
+	// ValidatorFeePerDay is what each L1 validator pays per day from its
+	// balance at the current price, in nLUX
+	ValidatorFeePerDay uint64
+}
+
+// TotalFee returns the fees of all the txs of the operation
+func (e *Estimate) TotalFee() uint64 {
+	var total uint64
+	for _, tx := range e.Txs {
+		total += uint64(tx.Count) * tx.Fee
+	}
+	return total
+}
+
+// Required returns the LUX the operation spends: its fees and the balances
+// of its validators
+func (e *Estimate) Required() uint64 {
+	return e.TotalFee() + uint64(e.Validators)*e.Balance
+}
+
+// BalanceLasts returns how long the balance of a validator pays its
+// continuous fee at the current price. It is zero when the fee is free.
+func (e *Estimate) BalanceLasts() time.Duration {
+	if e.ValidatorFeePerDay == 0 {
+		return 0
+	}
+	return time.Duration(float64(e.Balance) / float64(e.ValidatorFeePerDay) * float64(Day))
+}
+
+// Estimate returns the cost of op at the fee state s
+func (s *State) Estimate(op Op, opts Options) (*Estimate, error) {
+	e := &Estimate{Op: op}
+	switch op {
+	case CreateChain:
+		genesisSize := opts.GenesisSize
+		if genesisSize <= 0 {
+			genesisSize = DefaultGenesisSize
+		}
+		networkFee, err := s.txFee(&txs.CreateNetworkTx{BaseTx: baseTx(), Owner: owner()})
+		if err != nil {
+			return nil, err
+		}
+		chainFee, err := s.txFee(&txs.CreateChainTx{
+			BaseTx:         baseTx(),
+			BlockchainName: "chain",
+			GenesisData:    make([]byte, genesisSize),
+			ChainAuth:      auth(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		e.Txs = []TxFee{
+			{Type: "CreateNetworkTx", Count: 1, Fee: networkFee},
+			{Type: "CreateChainTx", Count: 1, Fee: chainFee},
+		}
+		return e, nil
+	case ConvertL1, AddValidator:
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownOp, op)
+	}
+
+	if opts.Validators <= 0 {
+		return nil, fmt.Errorf("%w %d, %s needs at least one", ErrValidators, opts.Validators, op)
+	}
+	e.Validators = opts.Validators
+	e.Balance = opts.Balance
+	e.ValidatorFeePerDay = uint64(s.ValidatorFeePrice) * uint64(Day/time.Second)
+	if op == ConvertL1 {
+		validators := make([]*txs.ConvertChainToL1Validator, opts.Validators)
+		for i := range validators {
+			validators[i] = &txs.ConvertChainToL1Validator{
+				NodeID:                make([]byte, ids.NodeIDLen),
+				Balance:               opts.Balance,
+				Signer:                signer.ProofOfPossession{},
+				RemainingBalanceOwner: pChainOwner(),
+				DeactivationOwner:     pChainOwner(),
+			}
+		}
+		convertFee, err := s.txFee(&txs.ConvertChainToL1Tx{
+			BaseTx:     baseTx(),
+			Address:    make([]byte, 20),
+			Validators: validators,
+			ChainAuth:  auth(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		e.Txs = []TxFee{{Type: "ConvertChainToL1Tx", Count: 1, Fee: convertFee}}
+		return e, nil
+	}
+	msg, err := warpMessage(registerL1ValidatorPayloadSize)
+	if err != nil {
+		return nil, err
+	}
+	registerFee, err := s.txFee(&txs.RegisterL1ValidatorTx{BaseTx: baseTx(), Balance: opts.Balance, Message: msg})
+	if err != nil {
+		return nil, err
+	}
+	e.Txs = []TxFee{{Type: "RegisterL1ValidatorTx", Count: opts.Validators, Fee: registerFee}}
+	return e, nil
+}
+
+// L1ValidatorTxFee returns the fee of the costliest tx changing an existing
+// L1 validator: IncreaseL1ValidatorBalanceTx, SetL1ValidatorWeightTx or
+// DisableL1ValidatorTx
+func (s *State) L1ValidatorTxFee() (uint64, error) {
+	msg, err := warpMessage(l1ValidatorWeightPayloadSize)
+	if err != nil {
+		return 0, err
+	}
+	var highest uint64
+	for _, tx := range []txs.UnsignedTx{
+		&txs.IncreaseL1ValidatorBalanceTx{BaseTx: baseTx()},
+		&txs.SetL1ValidatorWeightTx{BaseTx: baseTx(), Message: msg},
+		&txs.DisableL1ValidatorTx{BaseTx: baseTx(), DisableAuth: auth()},
+	} {
+		txFee, err := s.txFee(tx)
+		if err != nil {
+			return 0, err
+		}
+		if txFee > highest {
+			highest = txFee
+		}
+	}
+	return highest, nil
+}
+
+// PrimaryValidatorFee returns the fee of an AddPermissionlessValidatorTx
+// adding a primary network validator
+func (s *State) PrimaryValidatorFee() (uint64, error) {
+	return s.txFee(&txs.AddPermissionlessValidatorTx{
+		BaseTx:                baseTx(),
+		Signer:                &signer.ProofOfPossession{},
+		StakeOuts:             []*lux.TransferableOutput{output()},
+		ValidatorRewardsOwner: owner(),
+		DelegatorRewardsOwner: owner(),
+	})
+}
+
+// txFee returns the fee of tx, as the P-Chain computes it
+func (s *State) txFee(tx txs.UnsignedTx) (uint64, error) {
+	return fee.NewDynamicCalculator(s.Weights, s.GasPrice).CalculateFee(tx)
+}
+
+// The txs estimated spend one UTXO of the payer and return its change, each
+// owned by one address and signed once, as when a single key pays the fees.
+
+func baseTx() txs.BaseTx {
+	return txs.BaseTx{BaseTx: lux.BaseTx{
+		Ins: []*lux.TransferableInput{{
+			In: &secp256k1fx.TransferInput{Input: secp256k1fx.Input{SigIndices: []uint32{0}}},
+		}},
+		Outs: []*lux.TransferableOutput{output()},
+	}}
+}
+
+func output() *lux.TransferableOutput {
+	return &lux.TransferableOutput{Out: &secp256k1fx.TransferOutput{OutputOwners: *owner()}}
+}
+
+func owner() *secp256k1fx.OutputOwners {
+	return &secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{{}}}
+}
+
+func pChainOwner() message.PChainOwner {
+	return message.PChainOwner{Threshold: 1, Addresses: []ids.ShortID{{}}}
+}
+
+func auth() *secp256k1fx.Input {
+	return &secp256k1fx.Input{SigIndices: []uint32{0}}
+}
+
+// warpMessage returns a Warp message with a payload of payloadSize bytes,
+// signed by messageSigners validators
+func warpMessage(payloadSize int) ([]byte, error) {
+	unsignedMsg, err := warp.NewUnsignedMessage(0, ids.Empty, make([]byte, payloadSize))
+	if err != nil {
+		return nil, err
+	}
+	signers := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), messageSigners), big.NewInt(1))
+	msg, err := warp.NewMessage(unsignedMsg, &warp.BitSetSignature{Signers: signers.Bytes()})
+	if err != nil {
+		return nil, err
+	}
+	return msg.Bytes(), nil
+}
@@ -0,0 +1,80 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package feeestimate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luxfi/constants"
+	"github.com/luxfi/vm/components/gas"
+	"github.com/stretchr/testify/require"
+)
+
+var testState = &State{
+	Weights:           gas.Dimensions{gas.Bandwidth: 1, gas.DBRead: 1_000, gas.DBWrite: 1_000, gas.Compute: 4},
+	GasPrice:          2,
+	ValidatorFeePrice: 512,
+}
+
+func TestEstimateCreateChain(t *testing.T) {
+	require := require.New(t)
+	e, err := testState.Estimate(CreateChain, Options{})
+	require.NoError(err)
+	require.Len(e.Txs, 2)
+	require.Equal("CreateNetworkTx", e.Txs[0].Type)
+	require.Equal("CreateChainTx", e.Txs[1].Type)
+	require.NotZero(e.Txs[0].Fee)
+	require.Equal(e.TotalFee(), e.Required())
+	require.Zero(e.BalanceLasts())
+
+	// every genesis byte is paid for at the gas price
+	bigger, err := testState.Estimate(CreateChain, Options{GenesisSize: DefaultGenesisSize + 1000})
+	require.NoError(err)
+	require.Equal(e.Txs[1].Fee+1000*2, bigger.Txs[1].Fee)
+}
+
+func TestEstimateValidators(t *testing.T) {
+	require := require.New(t)
+	one, err := testState.Estimate(ConvertL1, Options{Validators: 1, Balance: constants.Lux})
+	require.NoError(err)
+	three, err := testState.Estimate(ConvertL1, Options{Validators: 3, Balance: constants.Lux})
+	require.NoError(err)
+	require.Equal("ConvertChainToL1Tx", three.Txs[0].Type)
+	require.Greater(three.TotalFee(), one.TotalFee())
+	require.Equal(three.TotalFee()+3*constants.Lux, three.Required())
+
+	// each validator pays the validator fee price every second
+	require.Equal(uint64(512*24*60*60), three.ValidatorFeePerDay)
+	require.InDelta(22.6, three.BalanceLasts().Hours()/24, 0.01)
+
+	add, err := testState.Estimate(AddValidator, Options{Validators: 3, Balance: constants.Lux})
+	require.NoError(err)
+	require.Equal("RegisterL1ValidatorTx", add.Txs[0].Type)
+	require.Equal(3, add.Txs[0].Count)
+	require.Equal(3*add.Txs[0].Fee, add.TotalFee())
+
+	_, err = testState.Estimate(AddValidator, Options{})
+	require.ErrorIs(err, ErrValidators)
+	_, err = testState.Estimate("remove-validator", Options{Validators: 1})
+	require.ErrorIs(err, ErrUnknownOp)
+}
+
+func TestValidatorTxFees(t *testing.T) {
+	require := require.New(t)
+	l1Fee, err := testState.L1ValidatorTxFee()
+	require.NoError(err)
+	require.NotZero(l1Fee)
+	primaryFee, err := testState.PrimaryValidatorFee()
+	require.NoError(err)
+	require.NotZero(primaryFee)
+
+	// fees scale with the gas price
+	doubled := *testState
+	doubled.GasPrice *= 2
+	doubledFee, err := doubled.L1ValidatorTxFee()
+	require.NoError(err)
+	require.Equal(2*l1Fee, doubledFee)
+	require.Equal(time.Duration(0), (&Estimate{}).BalanceLasts())
+}
@@ -0,0 +1,245 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package feeoracle suggests EIP-1559 fees for an EVM chain from the
+// eth_feeHistory of its recent blocks. Fresh chains have mostly empty blocks,
+// whose zero priority fees throw off the estimators of wallets; the oracle
+// ignores them and falls back to a minimum tip.
+package feeoracle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBlocks is how many recent blocks fees are suggested from
+	DefaultBlocks = 20
+	// DefaultTTL is how long a suggestion is served before the fee history
+	// is fetched again
+	DefaultTTL = 2 * time.Second
+)
+
+// baseFeeMultiplier is how much the base fee may grow before a suggested
+// max fee is too low, 2 covering six full blocks in a row
+const baseFeeMultiplier = 2
+
+// Speed is a confirmation speed and the priority fee percentile of recent
+// blocks it pays
+type Speed struct {
+	Name       string
+	Percentile float64
+}
+
+// Speeds are the tiers of a suggestion
+var Speeds = []Speed{
+	{Name: "slow", Percentile: 10},
+	{Name: "standard", Percentile: 50},
+	{Name: "fast", Percentile: 90},
+}
+
+// Tier is the suggested fees of a speed, in wei
+type Tier struct {
+	Speed                string
+	MaxPriorityFeePerGas *big.Int
+	MaxFeePerGas         *big.Int
+}
+
+// Suggestion is the fees suggested after a block
+type Suggestion struct {
+	// Block is the latest block of the fee history
+	Block uint64
+	// BaseFee is the base fee of the next block, in wei
+	BaseFee *big.Int
+	// Utilization is the average gas used ratio of the recent blocks
+	Utilization float64
+	// Tiers are in the order of Speeds
+	Tiers []Tier
+}
+
+// Tier returns the tier of speed, the standard one when unknown
+func (s *Suggestion) Tier(speed string) Tier {
+	for _, t := range s.Tiers {
+		if t.Speed == speed {
+			return t
+		}
+	}
+	return s.Tiers[1]
+}
+
+// Oracle suggests the fees of the chain at URL, caching them for TTL
+type Oracle struct {
+	URL    string
+	Client *http.Client
+	// Blocks is how many recent blocks fees are suggested from,
+	// DefaultBlocks when 0
+	Blocks int
+	// MinPriorityFee is the smallest tip suggested, in wei
+	MinPriorityFee *big.Int
+	// TTL is how long a suggestion is cached, DefaultTTL when 0
+	TTL time.Duration
+
+	mu       sync.Mutex
+	cached   *Suggestion
+	cachedAt time.Time
+}
+
+// Suggest returns the fees suggested from the recent blocks of the chain
+func (o *Oracle) Suggest(ctx context.Context) (*Suggestion, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	ttl := o.TTL
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+	if o.cached != nil && time.Since(o.cachedAt) < ttl {
+		return o.cached, nil
+	}
+	blocks := o.Blocks
+	if blocks == 0 {
+		blocks = DefaultBlocks
+	}
+	percentiles := make([]float64, len(Speeds))
+	for i, s := range Speeds {
+		percentiles[i] = s.Percentile
+	}
+	var history feeHistory
+	if err := call(ctx, o.Client, o.URL, "eth_feeHistory", []interface{}{"0x" + strconv.FormatInt(int64(blocks), 16), "latest", percentiles}, &history); err != nil {
+		return nil, err
+	}
+	suggestion, err := suggest(history, o.MinPriorityFee)
+	if err != nil {
+		return nil, err
+	}
+	o.cached, o.cachedAt = suggestion, time.Now()
+	return suggestion, nil
+}
+
+// feeHistory is the reply of eth_feeHistory, quantities being hex
+type feeHistory struct {
+	OldestBlock   string     `json:"oldestBlock"`
+	BaseFeePerGas []string   `json:"baseFeePerGas"`
+	GasUsedRatio  []float64  `json:"gasUsedRatio"`
+	Reward        [][]string `json:"reward"`
+}
+
+// suggest derives the tiers of Speeds from history. The tip of a tier is
+// the median over the non-empty blocks of its percentile, at least minTip.
+func suggest(history feeHistory, minTip *big.Int) (*Suggestion, error) {
+	if minTip == nil {
+		minTip = new(big.Int)
+	}
+	if len(history.BaseFeePerGas) == 0 {
+		return nil, errors.New("empty fee history, the chain has no London blocks")
+	}
+	// the last base fee is the one of the next block
+	baseFee, err := parseQuantity(history.BaseFeePerGas[len(history.BaseFeePerGas)-1])
+	if err != nil {
+		return nil, err
+	}
+	oldest, err := parseQuantity(history.OldestBlock)
+	if err != nil {
+		return nil, err
+	}
+	s := &Suggestion{BaseFee: baseFee, Block: oldest.Uint64()}
+	if n := len(history.GasUsedRatio); n > 0 {
+		s.Block += uint64(n - 1)
+		var total float64
+		for _, ratio := range history.GasUsedRatio {
+			total += ratio
+		}
+		s.Utilization = total / float64(n)
+	}
+
+	for i, speed := range Speeds {
+		var tips []*big.Int
+		for block, rewards := range history.Reward {
+			if (block < len(history.GasUsedRatio) && history.GasUsedRatio[block] == 0) || i >= len(rewards) {
+				continue
+			}
+			tip, err := parseQuantity(rewards[i])
+			if err != nil {
+				return nil, err
+			}
+			tips = append(tips, tip)
+		}
+		tip := median(tips)
+		if tip.Cmp(minTip) < 0 {
+			tip = new(big.Int).Set(minTip)
+		}
+		maxFee := new(big.Int).Mul(baseFee, big.NewInt(baseFeeMultiplier))
+		s.Tiers = append(s.Tiers, Tier{
+			Speed:                speed.Name,
+			MaxPriorityFeePerGas: tip,
+			MaxFeePerGas:         maxFee.Add(maxFee, tip),
+		})
+	}
+	return s, nil
+}
+
+func median(values []*big.Int) *big.Int {
+	if len(values) == 0 {
+		return new(big.Int)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Cmp(values[j]) < 0 })
+	return values[len(values)/2]
+}
+
+func parseQuantity(s string) (*big.Int, error) {
+	q, ok := new(big.Int).SetString(strings.TrimPrefix(s, "0x"), 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid quantity %q", s)
+	}
+	return q, nil
+}
+
+func call(ctx context.Context, client *http.Client, url, method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: status %d", method, resp.StatusCode)
+	}
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", method, err)
+	}
+	if response.Error != nil {
+		return fmt.Errorf("%s: %s", method, response.Error.Message)
+	}
+	if len(response.Result) == 0 {
+		return errors.New(method + ": empty result")
+	}
+	return json.Unmarshal(response.Result, result)
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package feeoracle
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggest(t *testing.T) {
+	require := require.New(t)
+	history := feeHistory{
+		OldestBlock:   "0x10",
+		BaseFeePerGas: []string{"0x5d21dba00", "0x5d21dba00", "0x5d21dba00", "0x6fc23ac00"},
+		GasUsedRatio:  []float64{0, 0.5, 0.1},
+		Reward: [][]string{
+			{"0x0", "0x0", "0x0"},
+			{"0x1", "0x64", "0x3e8"},
+			{"0x2", "0xc8", "0x7d0"},
+		},
+	}
+	s, err := suggest(history, big.NewInt(2))
+	require.NoError(err)
+	require.Equal(uint64(0x12), s.Block)
+	require.Equal(big.NewInt(30_000_000_000), s.BaseFee)
+	require.InDelta(0.2, s.Utilization, 0.001)
+	require.Len(s.Tiers, 3)
+
+	// the empty first block is ignored, the tips floored at 2
+	require.Equal(big.NewInt(2), s.Tier("slow").MaxPriorityFeePerGas)
+	require.Equal(big.NewInt(200), s.Tier("standard").MaxPriorityFeePerGas)
+	require.Equal(big.NewInt(2000), s.Tier("fast").MaxPriorityFeePerGas)
+	require.Equal(big.NewInt(60_000_000_200), s.Tier("standard").MaxFeePerGas)
+
+	// a fresh chain with only empty blocks gets the minimum tip
+	history.GasUsedRatio = []float64{0, 0, 0}
+	s, err = suggest(history, big.NewInt(7))
+	require.NoError(err)
+	require.Equal(big.NewInt(7), s.Tier("fast").MaxPriorityFeePerGas)
+
+	_, err = suggest(feeHistory{}, nil)
+	require.ErrorContains(err, "empty fee history")
+}
+
+func TestServe(t *testing.T) {
+	require := require.New(t)
+	calls := 0
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var req struct {
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}
+		require.NoError(json.NewDecoder(r.Body).Decode(&req))
+		require.Equal("eth_feeHistory", req.Method)
+		require.Equal("0x14", req.Params[0])
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": feeHistory{
+			OldestBlock:   "0x1",
+			BaseFeePerGas: []string{"0x64", "0x64"},
+			GasUsedRatio:  []float64{0.5},
+			Reward:        [][]string{{"0x1", "0x2", "0x3"}},
+		}})
+	}))
+	defer node.Close()
+
+	oracle := httptest.NewServer(&Oracle{URL: node.URL, Client: http.DefaultClient})
+	defer oracle.Close()
+
+	resp, err := http.Get(oracle.URL + "/fees")
+	require.NoError(err)
+	var fees jsonSuggestion
+	require.NoError(json.NewDecoder(resp.Body).Decode(&fees))
+	_ = resp.Body.Close()
+	require.Equal("0x64", fees.BaseFee)
+	require.Equal(jsonTier{MaxPriorityFeePerGas: "0x3", MaxFeePerGas: "0xcb"}, fees.Tiers["fast"])
+
+	for method, want := range map[string]string{
+		"eth_maxPriorityFeePerGas": `"0x2"`,
+		"eth_gasPrice":             `"0x66"`,
+	} {
+		resp, err := http.Post(oracle.URL, "application/json",
+			strings.NewReader(`{"jsonrpc":"2.0","id":7,"method":"`+method+`"}`))
+		require.NoError(err)
+		var reply struct {
+			ID     int             `json:"id"`
+			Result json.RawMessage `json:"result"`
+		}
+		require.NoError(json.NewDecoder(resp.Body).Decode(&reply))
+		_ = resp.Body.Close()
+		require.Equal(7, reply.ID)
+		require.JSONEq(want, string(reply.Result))
+	}
+	// the suggestion is cached
+	require.Equal(1, calls)
+
+	o := &Oracle{URL: node.URL, Client: http.DefaultClient, TTL: -1}
+	_, err = o.Suggest(context.Background())
+	require.NoError(err)
+	require.Equal(2, calls)
+}
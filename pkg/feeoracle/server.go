@@ -0,0 +1,112 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package feeoracle
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+)
+
+// jsonTier is a Tier as served, fees being hex quantities in wei
+type jsonTier struct {
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas"`
+	MaxFeePerGas         string `json:"maxFeePerGas"`
+}
+
+// jsonSuggestion is a Suggestion as served
+type jsonSuggestion struct {
+	Block       string              `json:"block"`
+	BaseFee     string              `json:"baseFeePerGas"`
+	Utilization float64             `json:"utilization"`
+	Tiers       map[string]jsonTier `json:"tiers"`
+}
+
+func toJSON(s *Suggestion) jsonSuggestion {
+	j := jsonSuggestion{
+		Block:       "0x" + new(big.Int).SetUint64(s.Block).Text(16),
+		BaseFee:     quantity(s.BaseFee),
+		Utilization: s.Utilization,
+		Tiers:       map[string]jsonTier{},
+	}
+	for _, t := range s.Tiers {
+		j.Tiers[t.Speed] = jsonTier{
+			MaxPriorityFeePerGas: quantity(t.MaxPriorityFeePerGas),
+			MaxFeePerGas:         quantity(t.MaxFeePerGas),
+		}
+	}
+	return j
+}
+
+func quantity(q *big.Int) string {
+	return "0x" + q.Text(16)
+}
+
+// ServeHTTP serves the suggestions of the oracle:
+//
+//	GET  /fees  the suggestion of each speed
+//	POST /      JSON-RPC eth_maxPriorityFeePerGas, eth_gasPrice and
+//	            lux_suggestFees, for wallets set to use the oracle
+func (o *Oracle) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	switch {
+	case r.Method == http.MethodOptions:
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && r.URL.Path == "/fees":
+		s, err := o.Suggest(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		writeJSON(w, toJSON(s))
+	case r.Method == http.MethodPost && r.URL.Path == "/":
+		o.serveRPC(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (o *Oracle) serveRPC(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+	}
+	response := map[string]interface{}{"jsonrpc": "2.0"}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response["error"] = rpcError{Code: -32700, Message: "parse error"}
+		writeJSON(w, response)
+		return
+	}
+	response["id"] = req.ID
+	s, err := o.Suggest(r.Context())
+	if err != nil {
+		response["error"] = rpcError{Code: -32000, Message: err.Error()}
+		writeJSON(w, response)
+		return
+	}
+	standard := s.Tier("standard")
+	switch req.Method {
+	case "eth_maxPriorityFeePerGas":
+		response["result"] = quantity(standard.MaxPriorityFeePerGas)
+	case "eth_gasPrice":
+		// a legacy tx pays its gas price in full, tip included
+		response["result"] = quantity(new(big.Int).Add(s.BaseFee, standard.MaxPriorityFeePerGas))
+	case "lux_suggestFees":
+		response["result"] = toJSON(s)
+	default:
+		response["error"] = rpcError{Code: -32601, Message: "the method " + req.Method + " does not exist"}
+	}
+	writeJSON(w, response)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
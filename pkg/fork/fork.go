@@ -0,0 +1,163 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package fork runs a local EVM forked from a remote chain, as Hardhat and
+// Anvil forks do: accounts, code and storage are read lazily from the RPC of
+// the chain at a pinned block, while transactions execute locally. Contracts
+// of mainnet can be tested against without importing its state.
+//
+// The fork is served by anvil, of the Foundry toolset.
+package fork
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPort is the port of the fork RPC, that of Anvil and Hardhat
+const DefaultPort = 8545
+
+// Options configure a fork
+type Options struct {
+	// RPC is the endpoint of the forked chain
+	RPC string
+	// Block is the block of the forked chain the fork is pinned at
+	Block uint64
+	// ChainID of the fork, the one of the forked chain when 0
+	ChainID uint64
+	Host    string
+	Port    int
+	// Accounts is the number of funded dev accounts, holding Balance coins
+	// each
+	Accounts int
+	Balance  uint64
+	// StatePath is the file the state of the fork is loaded from, when it
+	// exists, and saved to on exit. Empty keeps the state in memory.
+	StatePath string
+	// BlockTime mines blocks at that interval, instead of one block per
+	// transaction
+	BlockTime time.Duration
+}
+
+// Args returns the anvil arguments serving the fork of o
+func (o Options) Args() []string {
+	args := []string{
+		"--fork-url", o.RPC,
+		"--fork-block-number", strconv.FormatUint(o.Block, 10),
+		"--host", o.Host,
+		"--port", strconv.Itoa(o.Port),
+		"--accounts", strconv.Itoa(o.Accounts),
+		"--balance", strconv.FormatUint(o.Balance, 10),
+	}
+	if o.ChainID != 0 {
+		args = append(args, "--chain-id", strconv.FormatUint(o.ChainID, 10))
+	}
+	if o.StatePath != "" {
+		args = append(args, "--state", o.StatePath)
+	}
+	if o.BlockTime > 0 {
+		args = append(args, "--block-time", strconv.FormatUint(uint64(o.BlockTime/time.Second), 10))
+	}
+	return args
+}
+
+// URL returns the RPC endpoint of the fork
+func (o Options) URL() string {
+	return fmt.Sprintf("http://%s:%d", o.Host, o.Port)
+}
+
+// Head is the chain ID and last block of a chain
+type Head struct {
+	ChainID uint64
+	Block   uint64
+}
+
+// GetHead returns the chain ID and last block of the chain at url
+func GetHead(ctx context.Context, client *http.Client, url string) (Head, error) {
+	chainID, err := callUint(ctx, client, url, "eth_chainId")
+	if err != nil {
+		return Head{}, err
+	}
+	block, err := callUint(ctx, client, url, "eth_blockNumber")
+	if err != nil {
+		return Head{}, err
+	}
+	return Head{ChainID: chainID, Block: block}, nil
+}
+
+// WaitReady waits until the chain at url answers, polling every interval
+func WaitReady(ctx context.Context, client *http.Client, url string, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := callUint(ctx, client, url, "eth_chainId"); err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("fork at %s did not start: %w", url, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// callUint calls method, which takes no params and returns a hex quantity
+func callUint(ctx context.Context, client *http.Client, url, method string) (uint64, error) {
+	var quantity string
+	if err := call(ctx, client, url, method, &quantity); err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseUint(strings.TrimPrefix(quantity, "0x"), 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid quantity %q", method, quantity)
+	}
+	return v, nil
+}
+
+func call(ctx context.Context, client *http.Client, url, method string, result interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  []interface{}{},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: status %d", method, resp.StatusCode)
+	}
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", method, err)
+	}
+	if response.Error != nil {
+		return fmt.Errorf("%s: %s", method, response.Error.Message)
+	}
+	if len(response.Result) == 0 {
+		return errors.New(method + ": empty result")
+	}
+	return json.Unmarshal(response.Result, result)
+}
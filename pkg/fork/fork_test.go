@@ -0,0 +1,74 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fork
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgs(t *testing.T) {
+	require := require.New(t)
+	o := Options{
+		RPC:      "https://api.lux.network/ext/bc/C/rpc",
+		Block:    1234,
+		Host:     "127.0.0.1",
+		Port:     DefaultPort,
+		Accounts: 10,
+		Balance:  10000,
+	}
+	require.Equal([]string{
+		"--fork-url", "https://api.lux.network/ext/bc/C/rpc",
+		"--fork-block-number", "1234",
+		"--host", "127.0.0.1",
+		"--port", "8545",
+		"--accounts", "10",
+		"--balance", "10000",
+	}, o.Args())
+	require.Equal("http://127.0.0.1:8545", o.URL())
+
+	o.ChainID = 31337
+	o.StatePath = "/tmp/fork.json"
+	o.BlockTime = 2 * time.Second
+	require.Equal([]string{"--chain-id", "31337", "--state", "/tmp/fork.json", "--block-time", "2"}, o.Args()[12:])
+}
+
+func TestGetHead(t *testing.T) {
+	require := require.New(t)
+	results := map[string]string{"eth_chainId": "0x17871", "eth_blockNumber": "0x4d2"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		result, ok := results[req.Method]
+		if !ok {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]string{"message": "method not found"}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"result": result})
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	head, err := GetHead(ctx, server.Client(), server.URL)
+	require.NoError(err)
+	require.Equal(Head{ChainID: 96369, Block: 1234}, head)
+	require.NoError(WaitReady(ctx, server.Client(), server.URL, 10*time.Millisecond))
+
+	delete(results, "eth_blockNumber")
+	_, err = GetHead(ctx, server.Client(), server.URL)
+	require.ErrorContains(err, "eth_blockNumber: method not found")
+
+	short, cancelShort := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelShort()
+	require.ErrorContains(WaitReady(short, server.Client(), "http://127.0.0.1:1", 10*time.Millisecond), "did not start")
+}
@@ -0,0 +1,243 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package history keeps the append-only changelog of state-changing CLI
+// operations: deploys, validator changes, upgrades and restores.
+package history
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FileName is the changelog file in the CLI base dir
+const FileName = "history.jsonl"
+
+// Entry is one recorded operation
+type Entry struct {
+	Time time.Time `json:"time"`
+	User string    `json:"user"`
+	Host string    `json:"host"`
+	// Command is the command line that ran the operation, secrets redacted
+	Command string `json:"command"`
+	// Operation names what changed, e.g. "chain deploy"
+	Operation string            `json:"operation"`
+	Network   string            `json:"network,omitempty"`
+	Chain     string            `json:"chain,omitempty"`
+	Params    map[string]string `json:"params,omitempty"`
+	Result    map[string]string `json:"result,omitempty"`
+}
+
+// Append adds e to the changelog at path, filling in the time, user, host
+// and command line when unset. Entries are never rewritten.
+func Append(path string, e Entry) error {
+	line, err := json.Marshal(Stamp(e))
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) //nolint:gosec // G304: Writing to app's data directory
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Stamp returns e with the time, user, host and command line filled in when
+// unset
+func Stamp(e Entry) Entry {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	if e.User == "" {
+		if u, err := user.Current(); err == nil {
+			e.User = u.Username
+		}
+	}
+	if e.Host == "" {
+		e.Host, _ = os.Hostname()
+	}
+	if e.Command == "" {
+		e.Command = CommandLine(os.Args)
+	}
+	return e
+}
+
+// Load reads all entries of the changelog at path, oldest first. A missing
+// changelog has no entries.
+func Load(path string) ([]Entry, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: Reading from app's data directory
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// Filter selects entries. Empty fields match everything.
+type Filter struct {
+	// Operation matches entries whose operation starts with it, so "chain"
+	// matches both "chain deploy" and "chain upgrade apply"
+	Operation string
+	Network   string
+	Chain     string
+	Since     time.Time
+}
+
+// Match reports whether e passes the filter
+func (f Filter) Match(e Entry) bool {
+	switch {
+	case f.Operation != "" && !strings.HasPrefix(e.Operation, f.Operation):
+		return false
+	case f.Network != "" && !strings.EqualFold(e.Network, f.Network):
+		return false
+	case f.Chain != "" && e.Chain != f.Chain:
+		return false
+	case !f.Since.IsZero() && e.Time.Before(f.Since):
+		return false
+	}
+	return true
+}
+
+// Select returns the entries matching f
+func Select(entries []Entry, f Filter) []Entry {
+	var selected []Entry
+	for _, e := range entries {
+		if f.Match(e) {
+			selected = append(selected, e)
+		}
+	}
+	return selected
+}
+
+// Export writes entries as a JSON array or as CSV with the params and
+// results flattened into key=value lists
+func Export(w io.Writer, entries []Entry, format string) error {
+	switch format {
+	case "json":
+		if entries == nil {
+			entries = []Entry{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"time", "user", "host", "operation", "network", "chain", "params", "result", "command"}); err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if err := cw.Write([]string{
+				e.Time.UTC().Format(time.RFC3339),
+				e.User,
+				e.Host,
+				e.Operation,
+				e.Network,
+				e.Chain,
+				FormatFields(e.Params),
+				FormatFields(e.Result),
+				e.Command,
+			}); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+	return fmt.Errorf("unsupported export format %q: use json or csv", format)
+}
+
+// FormatFields renders a map as sorted key=value pairs
+func FormatFields(fields map[string]string) string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+fields[k])
+	}
+	return strings.Join(pairs, " ")
+}
+
+// Digest returns the hex SHA-256 of data, used to record which genesis or
+// upgrade file an operation used without storing it
+func Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// sensitiveFlags are flag name fragments whose values are never recorded
+var sensitiveFlags = []string{"password", "secret", "private-key", "privkey", "mnemonic", "api-key", "auth-token", "access-token"}
+
+// CommandLine joins args into the recorded command line, replacing the
+// values of sensitive flags with ***
+func CommandLine(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	out := []string{"lux"}
+	redactNext := false
+	for _, arg := range args[1:] {
+		if redactNext {
+			out = append(out, "***")
+			redactNext = false
+			continue
+		}
+		if strings.HasPrefix(arg, "-") && isSensitiveFlag(arg) {
+			if name, _, ok := strings.Cut(arg, "="); ok {
+				out = append(out, name+"=***")
+			} else {
+				out = append(out, arg)
+				redactNext = true
+			}
+			continue
+		}
+		out = append(out, arg)
+	}
+	return strings.Join(out, " ")
+}
+
+func isSensitiveFlag(arg string) bool {
+	name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+	name = strings.ToLower(name)
+	for _, s := range sensitiveFlags {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
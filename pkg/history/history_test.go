@@ -0,0 +1,80 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendLoad(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), FileName)
+
+	entries, err := Load(path)
+	require.NoError(err)
+	require.Empty(entries)
+
+	deployedAt := time.Date(2026, time.March, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(Append(path, Entry{
+		Time:      deployedAt,
+		Operation: "chain deploy",
+		Network:   "Devnet",
+		Chain:     "mychain",
+		Params:    map[string]string{"genesisSHA256": Digest([]byte("{}"))},
+		Result:    map[string]string{"blockchainID": "2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM"},
+	}))
+	require.NoError(Append(path, Entry{Operation: "snapshot restore", Params: map[string]string{"snapshot": "backup"}}))
+
+	entries, err = Load(path)
+	require.NoError(err)
+	require.Len(entries, 2)
+	require.Equal(deployedAt, entries[0].Time)
+	require.Equal("mychain", entries[0].Chain)
+	require.False(entries[1].Time.IsZero())
+	require.NotEmpty(entries[1].Command)
+
+	require.Len(Select(entries, Filter{Operation: "chain"}), 1)
+	require.Len(Select(entries, Filter{Network: "devnet"}), 1)
+	require.Len(Select(entries, Filter{Since: deployedAt.Add(time.Hour)}), 1)
+	require.Len(Select(entries, Filter{}), 2)
+}
+
+func TestExport(t *testing.T) {
+	require := require.New(t)
+	entries := []Entry{{
+		Time:      time.Date(2026, time.March, 1, 10, 0, 0, 0, time.UTC),
+		User:      "alice",
+		Operation: "validator add",
+		Params:    map[string]string{"weight": "20", "nodeID": "NodeID-1"},
+	}}
+
+	var out bytes.Buffer
+	require.NoError(Export(&out, entries, "json"))
+	var decoded []Entry
+	require.NoError(json.Unmarshal(out.Bytes(), &decoded))
+	require.Equal(entries, decoded)
+
+	out.Reset()
+	require.NoError(Export(&out, entries, "csv"))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(lines, 2)
+	require.Contains(lines[1], "nodeID=NodeID-1 weight=20")
+
+	require.Error(Export(&out, entries, "xml"))
+}
+
+func TestCommandLine(t *testing.T) {
+	require.Equal(t,
+		"lux key import k --password *** --private-key=*** --key mykey",
+		CommandLine([]string{"/usr/bin/lux", "key", "import", "k", "--password", "hunter2", "--private-key=0xabc", "--key", "mykey"}),
+	)
+	require.Empty(t, CommandLine(nil))
+}
@@ -0,0 +1,239 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package home selects the CLI home: the directory holding keys, chains,
+// clusters and local network state. Named homes live side by side in
+// ~/.lux/homes, so projects don't share, or collide on, one global state.
+package home
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// EnvVar selects the home, by name or directory, when --home is not
+	// given. The CLI sets it to the selected directory, so the processes it
+	// starts use the same home.
+	EnvVar = "LUX_HOME"
+
+	// DefaultName is the home at the root of ~/.lux
+	DefaultName = "default"
+
+	// HomesDir is the directory of the named homes in ~/.lux
+	HomesDir = "homes"
+	// CurrentFile records the home selected by 'lux home switch'
+	CurrentFile = "current-home"
+
+	// SourceFlag, SourceEnv, SourceSwitch and SourceDefault tell what
+	// selected a home
+	SourceFlag    = "flag"
+	SourceEnv     = "env"
+	SourceSwitch  = "switch"
+	SourceDefault = "default"
+
+	baseDirName = ".lux"
+)
+
+var nameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// Home is a CLI home
+type Home struct {
+	// Name is empty for a home given as a directory
+	Name string
+	Dir  string
+	// Source tells what selected the home, one of the Source constants
+	Source string
+}
+
+// Root returns ~/.lux, the default home and the parent of the named ones
+func Root() (string, error) {
+	usrHome, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(usrHome, baseDirName), nil
+}
+
+// ValidateName checks name can be used as a home name
+func ValidateName(name string) error {
+	if !nameRegex.MatchString(name) {
+		return fmt.Errorf("invalid home name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+// Dir returns the directory of the home name in root
+func Dir(root, name string) string {
+	if name == DefaultName {
+		return root
+	}
+	return filepath.Join(root, HomesDir, name)
+}
+
+// Select returns the home of flag, else of LUX_HOME, else the one switched
+// to, else the default one. flag and LUX_HOME hold a home name or, when
+// they contain a path separator or start with '~' or '.', a directory.
+func Select(root, flag string) (Home, error) {
+	switch {
+	case flag != "":
+		return fromSelector(root, flag, SourceFlag)
+	case os.Getenv(EnvVar) != "":
+		return fromSelector(root, os.Getenv(EnvVar), SourceEnv)
+	}
+	name, err := Current(root)
+	if err != nil {
+		return Home{}, err
+	}
+	if name == DefaultName {
+		return Home{Name: DefaultName, Dir: root, Source: SourceDefault}, nil
+	}
+	return Home{Name: name, Dir: Dir(root, name), Source: SourceSwitch}, nil
+}
+
+// BaseDir returns the directory of the selected home, as Select without a
+// flag does
+func BaseDir() (string, error) {
+	root, err := Root()
+	if err != nil {
+		return "", err
+	}
+	h, err := Select(root, "")
+	if err != nil {
+		return "", err
+	}
+	return h.Dir, nil
+}
+
+func fromSelector(root, selector, source string) (Home, error) {
+	if !isPath(selector) {
+		if err := ValidateName(selector); err != nil {
+			return Home{}, err
+		}
+		return Home{Name: selector, Dir: Dir(root, selector), Source: source}, nil
+	}
+	dir := selector
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		usrHome, err := os.UserHomeDir()
+		if err != nil {
+			return Home{}, err
+		}
+		dir = filepath.Join(usrHome, dir[1:])
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return Home{}, err
+	}
+	h := Home{Dir: dir, Source: source}
+	// a directory of a named home is selected by name
+	if dir == root {
+		h.Name = DefaultName
+	} else if filepath.Dir(dir) == filepath.Join(root, HomesDir) {
+		h.Name = filepath.Base(dir)
+	}
+	return h, nil
+}
+
+func isPath(selector string) bool {
+	return strings.ContainsRune(selector, filepath.Separator) || strings.ContainsRune(selector, '/') ||
+		strings.HasPrefix(selector, "~") || strings.HasPrefix(selector, ".")
+}
+
+// Current returns the name of the home switched to, the default one if none
+func Current(root string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(root, CurrentFile)) //nolint:gosec // G304: Reading from app's data directory
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultName, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	name := strings.TrimSpace(string(data))
+	if name == "" {
+		return DefaultName, nil
+	}
+	if err := ValidateName(name); err != nil {
+		return "", fmt.Errorf("invalid %s: %w", filepath.Join(root, CurrentFile), err)
+	}
+	return name, nil
+}
+
+// Switch makes name the home of the commands run without --home or
+// LUX_HOME. The home must exist.
+func Switch(root, name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if name == DefaultName {
+		err := os.Remove(filepath.Join(root, CurrentFile))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if !Exists(root, name) {
+		return fmt.Errorf("home %q doesn't exist, create it with 'lux home create %s'", name, name)
+	}
+	return os.WriteFile(filepath.Join(root, CurrentFile), []byte(name+"\n"), 0o600)
+}
+
+// Exists tells if the home name exists in root
+func Exists(root, name string) bool {
+	info, err := os.Stat(Dir(root, name))
+	return err == nil && info.IsDir()
+}
+
+// Create creates the home name in root and returns its directory
+func Create(root, name string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	if Exists(root, name) {
+		return "", fmt.Errorf("home %q already exists", name)
+	}
+	dir := Dir(root, name)
+	return dir, os.MkdirAll(dir, 0o750)
+}
+
+// Remove deletes the home name and everything in it. The default home and
+// the one switched to can't be removed.
+func Remove(root, name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if name == DefaultName {
+		return errors.New("the default home can't be removed")
+	}
+	if !Exists(root, name) {
+		return fmt.Errorf("home %q doesn't exist", name)
+	}
+	current, err := Current(root)
+	if err != nil {
+		return err
+	}
+	if current == name {
+		return fmt.Errorf("home %q is in use, switch to another one first", name)
+	}
+	return os.RemoveAll(Dir(root, name))
+}
+
+// List returns the default home and the named ones, sorted by name
+func List(root string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(root, HomesDir))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	names := []string{}
+	for _, e := range entries {
+		if e.IsDir() && ValidateName(e.Name()) == nil && e.Name() != DefaultName {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return append([]string{DefaultName}, names...), nil
+}
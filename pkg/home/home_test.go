@@ -0,0 +1,78 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package home
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelect(t *testing.T) {
+	require := require.New(t)
+	root := t.TempDir()
+	t.Setenv(EnvVar, "")
+
+	h, err := Select(root, "")
+	require.NoError(err)
+	require.Equal(Home{Name: DefaultName, Dir: root, Source: SourceDefault}, h)
+
+	_, err = Create(root, "proj-a")
+	require.NoError(err)
+	require.NoError(Switch(root, "proj-a"))
+	h, err = Select(root, "")
+	require.NoError(err)
+	require.Equal(Home{Name: "proj-a", Dir: filepath.Join(root, HomesDir, "proj-a"), Source: SourceSwitch}, h)
+
+	// LUX_HOME wins over the switch, and --home over both
+	t.Setenv(EnvVar, "proj-b")
+	h, err = Select(root, "")
+	require.NoError(err)
+	require.Equal("proj-b", h.Name)
+	require.Equal(SourceEnv, h.Source)
+	h, err = Select(root, DefaultName)
+	require.NoError(err)
+	require.Equal(root, h.Dir)
+	require.Equal(SourceFlag, h.Source)
+
+	// a directory is taken as is, and named when it is a home of root
+	dir := t.TempDir()
+	h, err = Select(root, dir)
+	require.NoError(err)
+	require.Equal(Home{Dir: dir, Source: SourceFlag}, h)
+	h, err = Select(root, filepath.Join(root, HomesDir, "proj-a"))
+	require.NoError(err)
+	require.Equal("proj-a", h.Name)
+
+	_, err = Select(root, "bad name")
+	require.ErrorContains(err, "invalid home name")
+}
+
+func TestSwitchRemoveList(t *testing.T) {
+	require := require.New(t)
+	root := t.TempDir()
+
+	require.ErrorContains(Switch(root, "missing"), "doesn't exist")
+	_, err := Create(root, "b")
+	require.NoError(err)
+	_, err = Create(root, "a")
+	require.NoError(err)
+	_, err = Create(root, "a")
+	require.ErrorContains(err, "already exists")
+
+	names, err := List(root)
+	require.NoError(err)
+	require.Equal([]string{DefaultName, "a", "b"}, names)
+
+	require.NoError(Switch(root, "a"))
+	require.ErrorContains(Remove(root, "a"), "is in use")
+	require.NoError(Switch(root, DefaultName))
+	current, err := Current(root)
+	require.NoError(err)
+	require.Equal(DefaultName, current)
+	require.NoError(Remove(root, "a"))
+	require.False(Exists(root, "a"))
+	require.ErrorContains(Remove(root, DefaultName), "can't be removed")
+}
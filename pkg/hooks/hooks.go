@@ -0,0 +1,231 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package hooks runs the shell commands and webhooks users register in the
+// CLI config to run before and after lifecycle operations, such as deploys
+// and network starts.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/configschema"
+)
+
+const (
+	// ConfigKey is the section of the CLI config file hooks are read from
+	ConfigKey = "hooks"
+
+	// DefaultTimeout bounds a hook without a timeout
+	DefaultTimeout = 5 * time.Minute
+)
+
+// Operations hooks run around. Hooks register for the event "pre-<op>" or
+// "post-<op>".
+const (
+	Deploy          = "deploy"
+	NetworkStart    = "network-start"
+	NetworkStop     = "network-stop"
+	SnapshotCreate  = "snapshot-create"
+	SnapshotRestore = "snapshot-restore"
+)
+
+// Operations are the operations hooks run around
+var Operations = []string{Deploy, NetworkStart, NetworkStop, SnapshotCreate, SnapshotRestore}
+
+// Statuses of the operation passed to post hooks
+const (
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+)
+
+// Hook is a shell command or a webhook
+type Hook struct {
+	// Run is a shell command, run with sh -c. The context is given as JSON on
+	// its stdin and as LUX_HOOK_* environment variables.
+	Run string `json:"run,omitempty"`
+	// URL receives the context as a JSON POST
+	URL string `json:"url,omitempty"`
+	// Headers are added to the webhook request. $VAR references are expanded
+	// from the environment, so tokens need not be stored in the config.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Timeout is a duration such as 30s, DefaultTimeout if unset
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// Config holds the hooks of each event, run in order
+type Config map[string][]Hook
+
+// Context describes the operation a hook runs around
+type Context struct {
+	Event     string    `json:"event"`
+	Operation string    `json:"operation"`
+	Network   string    `json:"network,omitempty"`
+	Chain     string    `json:"chain,omitempty"`
+	Snapshot  string    `json:"snapshot,omitempty"`
+	Args      []string  `json:"args,omitempty"`
+	Time      time.Time `json:"time"`
+	// Status and Error tell post hooks how the operation went
+	Status string `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Load reads the hooks of the config file at configPath. A missing file or
+// section yields no hooks.
+func Load(configPath string) (Config, error) {
+	data, err := os.ReadFile(configPath) //nolint:gosec // G304: Reading the CLI config file
+	if errors.Is(err, os.ErrNotExist) || len(data) == 0 {
+		return Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", configPath, configschema.CLI().Explain(data, "", err))
+	}
+	c := Config{}
+	section, ok := raw[ConfigKey]
+	if !ok {
+		return c, nil
+	}
+	if err := json.Unmarshal(section, &c); err != nil {
+		return nil, fmt.Errorf("invalid %q in %s: %w", ConfigKey, configPath, configschema.CLI().Explain(data, ConfigKey, err))
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %q in %s: %w", ConfigKey, configPath, err)
+	}
+	return c, nil
+}
+
+// Validate checks the events and hooks of c
+func (c Config) Validate() error {
+	for event, hooks := range c {
+		phase, op, _ := strings.Cut(event, "-")
+		if (phase != "pre" && phase != "post") || !slices.Contains(Operations, op) {
+			return fmt.Errorf("unknown event %q: must be pre-<op> or post-<op>, <op> one of %s",
+				event, strings.Join(Operations, ", "))
+		}
+		for i, h := range hooks {
+			if (h.Run == "") == (h.URL == "") {
+				return fmt.Errorf("%s hook %d: set one of run or url", event, i+1)
+			}
+			if h.Timeout != "" {
+				if _, err := time.ParseDuration(h.Timeout); err != nil {
+					return fmt.Errorf("%s hook %d: invalid timeout: %w", event, i+1, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Wrap runs op between the hooks of operation. A failing pre hook aborts
+// op. Post hooks run whether op failed or not, and their failure is
+// returned when op succeeded.
+func (c Config) Wrap(ctx context.Context, operation string, hc Context, op func() error) error {
+	hc.Operation = operation
+	if err := c.Run(ctx, "pre-"+operation, hc); err != nil {
+		return fmt.Errorf("%s aborted: %w", operation, err)
+	}
+	opErr := op()
+	hc.Status = StatusSuccess
+	if opErr != nil {
+		hc.Status = StatusFailure
+		hc.Error = opErr.Error()
+	}
+	if err := c.Run(ctx, "post-"+operation, hc); err != nil {
+		if opErr != nil {
+			return opErr
+		}
+		return fmt.Errorf("%s succeeded but %w", operation, err)
+	}
+	return opErr
+}
+
+// Run runs the hooks of event in order, stopping at the first failure
+func (c Config) Run(ctx context.Context, event string, hc Context) error {
+	hc.Event = event
+	if hc.Time.IsZero() {
+		hc.Time = time.Now().UTC()
+	}
+	payload, err := json.Marshal(hc)
+	if err != nil {
+		return err
+	}
+	for i, h := range c[event] {
+		timeout := DefaultTimeout
+		if h.Timeout != "" {
+			if timeout, err = time.ParseDuration(h.Timeout); err != nil {
+				return err
+			}
+		}
+		hookCtx, cancel := context.WithTimeout(ctx, timeout)
+		if h.Run != "" {
+			err = runShell(hookCtx, h, hc, payload)
+		} else {
+			err = postWebhook(hookCtx, h, payload)
+		}
+		cancel()
+		if err != nil {
+			return fmt.Errorf("%s hook %d failed: %w", event, i+1, err)
+		}
+	}
+	return nil
+}
+
+func runShell(ctx context.Context, h Hook, hc Context, payload []byte) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", h.Run) //nolint:gosec // G204: Running the user's configured hook
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"LUX_HOOK_EVENT="+hc.Event,
+		"LUX_HOOK_OPERATION="+hc.Operation,
+		"LUX_HOOK_NETWORK="+hc.Network,
+		"LUX_HOOK_CHAIN="+hc.Chain,
+		"LUX_HOOK_SNAPSHOT="+hc.Snapshot,
+		"LUX_HOOK_STATUS="+hc.Status,
+		"LUX_HOOK_ERROR="+hc.Error,
+		"LUX_HOOK_CONTEXT="+string(payload),
+	)
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("%q timed out", h.Run)
+		}
+		return fmt.Errorf("%q: %w", h.Run, err)
+	}
+	return nil
+}
+
+func postWebhook(ctx context.Context, h Hook, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range h.Headers {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s returned %s: %s", h.URL, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
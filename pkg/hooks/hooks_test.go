@@ -0,0 +1,129 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cli.json")
+	c, err := Load(path)
+	require.NoError(t, err)
+	require.Empty(t, c)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{
+  "metricsEnabled": true,
+  "hooks": {
+    "pre-deploy": [{"run": "./validate.sh", "timeout": "30s"}],
+    "post-network-start": [{"url": "https://hooks.example.com/lux"}]
+  }
+}`), 0o600))
+	c, err = Load(path)
+	require.NoError(t, err)
+	require.Equal(t, "./validate.sh", c["pre-deploy"][0].Run)
+	require.Equal(t, "https://hooks.example.com/lux", c["post-network-start"][0].URL)
+
+	for _, invalid := range []string{
+		`{"hooks": {"pre-launch": [{"run": "true"}]}}`,
+		`{"hooks": {"during-deploy": [{"run": "true"}]}}`,
+		`{"hooks": {"pre-deploy": [{}]}}`,
+		`{"hooks": {"pre-deploy": [{"run": "true", "url": "http://x"}]}}`,
+		`{"hooks": {"pre-deploy": [{"run": "true", "timeout": "soon"}]}}`,
+	} {
+		require.NoError(t, os.WriteFile(path, []byte(invalid), 0o600))
+		_, err := Load(path)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestWrapShell(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	c := Config{
+		"pre-deploy":  {{Run: `echo "$LUX_HOOK_EVENT $LUX_HOOK_CHAIN" >> ` + out}},
+		"post-deploy": {{Run: `echo "$LUX_HOOK_EVENT $LUX_HOOK_STATUS $(cat)" >> ` + out}},
+	}
+	ran := false
+	err := c.Wrap(context.Background(), Deploy, Context{Chain: "mychain"}, func() error {
+		ran = true
+		return nil
+	})
+	require.NoError(t, err)
+	require.True(t, ran)
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	require.Equal(t, "pre-deploy mychain", lines[0])
+	require.True(t, strings.HasPrefix(lines[1], "post-deploy success {"))
+	var hc Context
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[1], "post-deploy success ")), &hc))
+	require.Equal(t, Deploy, hc.Operation)
+	require.Equal(t, "mychain", hc.Chain)
+}
+
+func TestWrapFailures(t *testing.T) {
+	ran := false
+	op := func() error {
+		ran = true
+		return nil
+	}
+
+	// a failing pre hook aborts the operation
+	c := Config{"pre-network-start": {{Run: "exit 3"}}}
+	err := c.Wrap(context.Background(), NetworkStart, Context{}, op)
+	require.ErrorContains(t, err, "network-start aborted")
+	require.False(t, ran)
+
+	// a failing post hook fails a successful operation
+	c = Config{"post-network-start": {{Run: "exit 1"}}}
+	err = c.Wrap(context.Background(), NetworkStart, Context{}, op)
+	require.ErrorContains(t, err, "network-start succeeded but post-network-start hook 1 failed")
+	require.True(t, ran)
+
+	// the error of the operation wins over the post hook's
+	opErr := errors.New("boom")
+	err = c.Wrap(context.Background(), NetworkStart, Context{}, func() error { return opErr })
+	require.ErrorIs(t, err, opErr)
+
+	c = Config{"pre-deploy": {{Run: "exec sleep 5", Timeout: "100ms"}}}
+	err = c.Run(context.Background(), "pre-deploy", Context{})
+	require.ErrorContains(t, err, "timed out")
+}
+
+func TestWebhook(t *testing.T) {
+	var got Context
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer server.Close()
+	t.Setenv("TEST_HOOK_TOKEN", "secret")
+
+	c := Config{"post-snapshot-restore": {{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer $TEST_HOOK_TOKEN"}}}}
+	err := c.Wrap(context.Background(), SnapshotRestore, Context{Network: "mainnet"}, func() error {
+		return errors.New("restore failed")
+	})
+	require.EqualError(t, err, "restore failed")
+	require.Equal(t, "post-snapshot-restore", got.Event)
+	require.Equal(t, StatusFailure, got.Status)
+	require.Equal(t, "restore failed", got.Error)
+	require.Equal(t, "mainnet", got.Network)
+
+	c["post-snapshot-restore"][0].Headers = nil
+	require.ErrorContains(t, c.Run(context.Background(), "post-snapshot-restore", Context{}), "401")
+}
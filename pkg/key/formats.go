@@ -0,0 +1,182 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/crypto/secp256k1"
+	"github.com/luxfi/geth/accounts/keystore"
+	"github.com/luxfi/geth/common"
+)
+
+// Formats a secp256k1 private key can be exported to and imported from
+const (
+	FormatHex      = "hex"      // 64 hex characters, optionally 0x prefixed
+	FormatKeystore = "keystore" // Ethereum keystore v3 JSON, password protected
+	FormatPEM      = "pem"      // SEC 1 EC PRIVATE KEY, as written by openssl
+	FormatMnemonic = "mnemonic" // BIP-39 mnemonic, derived along a BIP-44 path
+)
+
+const (
+	pemTypeEC    = "EC PRIVATE KEY"
+	pemTypePKCS8 = "PRIVATE KEY"
+)
+
+var (
+	// oidSecp256k1 names the secp256k1 curve (SEC 2)
+	oidSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+	// oidECPublicKey is the PKCS#8 algorithm of EC keys (RFC 5480)
+	oidECPublicKey = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+
+	ErrNotSecp256k1 = errors.New("key is not a secp256k1 key")
+)
+
+// ecPrivateKey is the SEC 1 ECPrivateKey structure (RFC 5915)
+type ecPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// pkcs8 is the PKCS#8 PrivateKeyInfo structure (RFC 5208)
+type pkcs8 struct {
+	Version    int
+	Algorithm  pkcs8Algorithm
+	PrivateKey []byte
+}
+
+type pkcs8Algorithm struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.ObjectIdentifier
+}
+
+// EncodeHex encodes a private key as 0x prefixed hex, the form wallets such
+// as MetaMask accept
+func EncodeHex(privKey []byte) string {
+	return "0x" + hex.EncodeToString(privKey)
+}
+
+// DecodeHex decodes a hex private key, with or without 0x prefix
+func DecodeHex(s string) ([]byte, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "0x"), "0X")
+	if len(s) != 2*secp256k1.PrivateKeyLen {
+		return nil, ErrInvalidPrivateKeyLen
+	}
+	privKey, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPrivateKeyEncoding, err)
+	}
+	return privKey, nil
+}
+
+// EncodeKeystore encrypts a private key into keystore v3 JSON. scryptN and
+// scryptP are the scrypt cost, keystore.StandardScryptN and StandardScryptP
+// for keys meant to be kept.
+func EncodeKeystore(privKey []byte, password string, scryptN, scryptP int) ([]byte, error) {
+	ecdsaKey, err := crypto.ToECDSA(privKey)
+	if err != nil {
+		return nil, err
+	}
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+	return keystore.EncryptKey(&keystore.Key{
+		Id:         id,
+		Address:    common.Address(crypto.PubkeyToAddress(ecdsaKey.PublicKey)),
+		PrivateKey: ecdsaKey,
+	}, password, scryptN, scryptP)
+}
+
+// DecodeKeystore decrypts keystore v3 JSON
+func DecodeKeystore(data []byte, password string) ([]byte, error) {
+	k, err := keystore.DecryptKey(data, password)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.FromECDSA(k.PrivateKey), nil
+}
+
+// EncodePEM encodes a private key as a SEC 1 "EC PRIVATE KEY" PEM block on
+// the secp256k1 curve
+func EncodePEM(privKey []byte) ([]byte, error) {
+	ecdsaKey, err := crypto.ToECDSA(privKey)
+	if err != nil {
+		return nil, err
+	}
+	der, err := asn1.Marshal(ecPrivateKey{
+		Version:       1,
+		PrivateKey:    paddedBytes(ecdsaKey.D, secp256k1.PrivateKeyLen),
+		NamedCurveOID: oidSecp256k1,
+		PublicKey:     asn1.BitString{Bytes: crypto.FromECDSAPub(&ecdsaKey.PublicKey)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypeEC, Bytes: der}), nil
+}
+
+// DecodePEM decodes a secp256k1 private key from a SEC 1 "EC PRIVATE KEY"
+// or PKCS#8 "PRIVATE KEY" PEM block
+func DecodePEM(data []byte) ([]byte, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	switch block.Type {
+	case pemTypeEC:
+		return parseECPrivateKey(block.Bytes, nil)
+	case pemTypePKCS8:
+		var info pkcs8
+		if _, err := asn1.Unmarshal(block.Bytes, &info); err != nil {
+			return nil, fmt.Errorf("invalid PKCS#8 key: %w", err)
+		}
+		if !info.Algorithm.Algorithm.Equal(oidECPublicKey) {
+			return nil, ErrNotSecp256k1
+		}
+		return parseECPrivateKey(info.PrivateKey, info.Algorithm.Parameters)
+	}
+	return nil, fmt.Errorf("unsupported PEM block %q: expected %s or %s", block.Type, pemTypeEC, pemTypePKCS8)
+}
+
+// parseECPrivateKey parses a SEC 1 key. curve is the curve named outside of
+// the key, by PKCS#8.
+func parseECPrivateKey(der []byte, curve asn1.ObjectIdentifier) ([]byte, error) {
+	var k ecPrivateKey
+	if _, err := asn1.Unmarshal(der, &k); err != nil {
+		return nil, fmt.Errorf("invalid EC private key: %w", err)
+	}
+	if len(k.NamedCurveOID) > 0 {
+		curve = k.NamedCurveOID
+	}
+	if !curve.Equal(oidSecp256k1) {
+		return nil, ErrNotSecp256k1
+	}
+	if len(k.PrivateKey) > secp256k1.PrivateKeyLen {
+		return nil, ErrInvalidPrivateKeyLen
+	}
+	return paddedBytes(new(big.Int).SetBytes(k.PrivateKey), secp256k1.PrivateKeyLen), nil
+}
+
+// DeriveMnemonicPrivateKey derives the private key at m/44'/coinType'/0'/0/index
+// of a BIP-39 mnemonic. EthCoinType gives the accounts MetaMask and hardware
+// wallets show, LuxCoinType those of the Lux wallet.
+func DeriveMnemonicPrivateKey(mnemonic string, coinType, index uint32) ([]byte, error) {
+	return deriveMnemonicKeyWithCoinType(strings.Join(strings.Fields(mnemonic), " "), coinType, index)
+}
+
+func paddedBytes(n *big.Int, size int) []byte {
+	b := make([]byte, size)
+	return n.FillBytes(b)
+}
@@ -16,6 +16,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/luxfi/cli/pkg/home"
 	"github.com/luxfi/constants"
 	"github.com/luxfi/crypto/bls"
 	"github.com/luxfi/crypto/bls/signer/localsigner"
@@ -244,13 +245,14 @@ func deriveMLDSAKeys(seed []byte) ([]byte, []byte, error) {
 	return privKey.Bytes(), privKey.PublicKey.Bytes(), nil
 }
 
-// GetKeysDir returns the base directory for all keys
+// GetKeysDir returns the base directory for all keys, in the selected CLI
+// home
 func GetKeysDir() (string, error) {
-	home, err := os.UserHomeDir()
+	baseDir, err := home.BaseDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, constants.BaseDirName, constants.KeyDir), nil
+	return filepath.Join(baseDir, constants.KeyDir), nil
 }
 
 // SaveKeySet saves key set through the encrypted backend - never stores plaintext secrets
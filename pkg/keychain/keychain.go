@@ -7,11 +7,13 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/luxfi/address"
 	"github.com/luxfi/cli/cmd/flags"
 	"github.com/luxfi/cli/pkg/application"
 	"github.com/luxfi/cli/pkg/key"
+	"github.com/luxfi/cli/pkg/kms"
 	"github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/cli/pkg/ux"
 	"github.com/luxfi/constants"
@@ -157,7 +159,7 @@ func GetKeychainFromCmdLineFlags(
 	case models.Mainnet:
 		if keyName == "" && !useLocalKey {
 			useLedger = true
-		} else {
+		} else if !IsKMSKey(keyName) && !IsMPCKey(keyName) {
 			ux.Logger.PrintToUser("")
 			ux.Logger.PrintToUser("%s", luxlog.Red.Wrap("WARNING: Storing keys locally in plain text is insecure. A hardware wallet is recommended for Mainnet."))
 			ux.Logger.PrintToUser("")
@@ -176,6 +178,26 @@ func GetKeychain(
 	keyName string,
 	network models.Network,
 	requiredFunds uint64,
+) (*Keychain, error) {
+	kc, err := getKeychain(app, useLocalKey, useLedger, ledgerAddresses, keyName, network, requiredFunds)
+	if err != nil {
+		return nil, err
+	}
+	// receipts of mainnet operations name the keys they signed with
+	if addresses, err := kc.PChainFormattedStrAddresses(); err == nil {
+		app.AddSigningKeys(addresses...)
+	}
+	return kc, nil
+}
+
+func getKeychain(
+	app *application.Lux,
+	useLocalKey bool,
+	useLedger bool,
+	ledgerAddresses []string,
+	keyName string,
+	network models.Network,
+	requiredFunds uint64,
 ) (*Keychain, error) {
 	// Check for MNEMONIC environment variable first
 	// This allows automated deployment without interactive key selection
@@ -248,6 +270,42 @@ func GetKeychain(
 		wrappedKc := WrapSecp256k1fxKeychain(kc)
 		return NewKeychain(network, wrappedKc, nil, nil), nil
 	}
+	if IsKMSKey(keyName) {
+		ctx, cancel := utils.GetAPIContext()
+		defer cancel()
+		kc, err := NewKMSKeychain(ctx, keyName)
+		if err != nil {
+			return nil, err
+		}
+		addr, err := address.Format("P", key.GetHRP(network.ID()), kc.Signer.Address().Bytes())
+		if err != nil {
+			return nil, err
+		}
+		ux.Logger.PrintToUser("Signing with KMS key %s (%s)", strings.TrimPrefix(keyName, KMSKeyPrefix), addr)
+		return NewKeychain(network, kc, nil, nil), nil
+	}
+	if IsMPCKey(keyName) {
+		ctx, cancel := utils.GetAPIContext()
+		defer cancel()
+		kc, err := NewMPCKeychain(ctx, keyName)
+		if err != nil {
+			return nil, err
+		}
+		addr, err := address.Format("P", key.GetHRP(network.ID()), kc.Signer.Address().Bytes())
+		if err != nil {
+			return nil, err
+		}
+		wallet := kc.Signer.Wallet()
+		ux.Logger.PrintToUser("Signing with MPC wallet %s (%s), %d of %d parties", wallet.Name, addr, wallet.Threshold, wallet.TotalParties)
+		kc.Signer.OnRequest = func(request *kms.MPCSigningRequest) {
+			ux.Logger.PrintToUser("MPC signing request %s needs %d approvals, approve it with:", request.ID, request.RequiredSigs)
+			ux.Logger.PrintToUser("  lux mpc sign approve %s --share <share file>", request.ID)
+		}
+		kc.Signer.OnProgress = func(request *kms.MPCSigningRequest) {
+			ux.Logger.PrintToUser("  %d of %d approvals", request.CollectedSigs, request.RequiredSigs)
+		}
+		return NewKeychain(network, kc, nil, nil), nil
+	}
 	keyPath := app.GetKeyPath(keyName)
 	sf, err := key.LoadSoft(network.ID(), keyPath)
 	if err != nil {
@@ -0,0 +1,269 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keychain
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/luxfi/crypto/secp256k1"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/keychain"
+	"github.com/luxfi/math/set"
+)
+
+// KMSKeyPrefix selects a secp256k1 key of a KMS server in --key, as
+// kms://<keyId>. Its private key never leaves the server.
+const KMSKeyPrefix = "kms://"
+
+const (
+	// KMSAddrEnvVar is the address of the KMS server signing with kms:// keys
+	KMSAddrEnvVar = "LUX_KMS_ADDR"
+	// KMSAPIKeyEnvVar is the API key of the KMS server, if it requires one
+	KMSAPIKeyEnvVar = "LUX_KMS_API_KEY"
+
+	defaultKMSAddr = "http://localhost:8200"
+	kmsTimeout     = 30 * time.Second
+	kmsPublicKey   = "SECP256K1 PUBLIC KEY"
+)
+
+// IsKMSKey tells if keyName is a kms:// key
+func IsKMSKey(keyName string) bool {
+	return strings.HasPrefix(keyName, KMSKeyPrefix)
+}
+
+// KMSClient signs with the keys of a KMS server, as served by 'lux kms
+// server start'
+type KMSClient struct {
+	URL    string
+	APIKey string
+	Client *http.Client
+}
+
+// NewKMSClientFromEnv returns the client of the KMS server of
+// LUX_KMS_ADDR, http://localhost:8200 by default
+func NewKMSClientFromEnv() *KMSClient {
+	addr := os.Getenv(KMSAddrEnvVar)
+	if addr == "" {
+		addr = defaultKMSAddr
+	}
+	return &KMSClient{
+		URL:    strings.TrimSuffix(addr, "/"),
+		APIKey: os.Getenv(KMSAPIKeyEnvVar),
+		Client: &http.Client{Timeout: kmsTimeout},
+	}
+}
+
+// PublicKey returns the public key of the secp256k1 key keyID
+func (c *KMSClient) PublicKey(ctx context.Context, keyID string) (*secp256k1.PublicKey, error) {
+	var resp struct {
+		PublicKey string `json:"publicKey"`
+	}
+	if err := c.do(ctx, http.MethodGet, keyID, "public-key", nil, &resp); err != nil {
+		return nil, err
+	}
+	pemBytes, err := base64.StdEncoding.DecodeString(resp.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key of KMS key %s: %w", keyID, err)
+	}
+	block, _ := pem.Decode(pemBytes)
+	if block == nil || block.Type != kmsPublicKey {
+		return nil, fmt.Errorf("KMS key %s is not a secp256k1 key", keyID)
+	}
+	return secp256k1.ToPublicKey(block.Bytes)
+}
+
+// SignDigest returns the [r || s || v] signature of digest by keyID
+func (c *KMSClient) SignDigest(ctx context.Context, keyID string, digest []byte) ([]byte, error) {
+	req := map[string]interface{}{
+		"data":     base64.StdEncoding.EncodeToString(digest),
+		"isDigest": true,
+	}
+	var resp struct {
+		Signature string `json:"signature"`
+	}
+	if err := c.do(ctx, http.MethodPost, keyID, "sign", req, &resp); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Signature)
+}
+
+// Encrypt returns plaintext encrypted with the key keyID, which only the
+// KMS server can decrypt
+func (c *KMSClient) Encrypt(ctx context.Context, keyID string, plaintext []byte) ([]byte, error) {
+	req := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(plaintext)}
+	var resp struct {
+		Ciphertext string `json:"ciphertext"`
+	}
+	if err := c.do(ctx, http.MethodPost, keyID, "encrypt", req, &resp); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Ciphertext)
+}
+
+// Decrypt returns the plaintext of ciphertext, encrypted with the key keyID
+func (c *KMSClient) Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error) {
+	req := map[string]string{"ciphertext": base64.StdEncoding.EncodeToString(ciphertext)}
+	var resp struct {
+		Plaintext string `json:"plaintext"`
+	}
+	if err := c.do(ctx, http.MethodPost, keyID, "decrypt", req, &resp); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Plaintext)
+}
+
+func (c *KMSClient) do(ctx context.Context, method, keyID, action string, body, result interface{}) error {
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			return err
+		}
+	}
+	endpoint := fmt.Sprintf("%s/v1/kms/keys/%s/%s", c.URL, url.PathEscape(keyID), action)
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, &reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("X-API-Key", c.APIKey)
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the KMS server at %s: %w", c.URL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		return fmt.Errorf("KMS key %s %s: %s (status %d)", keyID, action, errResp.Error, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// KMSSigner signs with a secp256k1 key of a KMS server. It implements
+// keychain.Signer, so it signs P-Chain, X-Chain and C-Chain atomic txs as a
+// soft key would, and it signs EVM txs with SignEVMTx.
+type KMSSigner struct {
+	client *KMSClient
+	keyID  string
+	pub    *secp256k1.PublicKey
+}
+
+// NewKMSSigner returns the signer of keyID, fetching its public key
+func NewKMSSigner(ctx context.Context, client *KMSClient, keyID string) (*KMSSigner, error) {
+	pub, err := client.PublicKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	return &KMSSigner{client: client, keyID: keyID, pub: pub}, nil
+}
+
+// SignHash signs hash remotely. The signature is checked to recover the
+// key's address, so a misbehaving server can't make a tx fail on chain.
+func (s *KMSSigner) SignHash(hash []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+	sig, err := s.client.SignDigest(ctx, s.keyID, hash)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := secp256k1.RecoverPublicKeyFromHash(hash, sig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature from KMS key %s: %w", s.keyID, err)
+	}
+	if pub.Address() != s.Address() {
+		return nil, fmt.Errorf("KMS key %s signed with another key", s.keyID)
+	}
+	return sig, nil
+}
+
+// Sign signs the sha256 hash of msg, as secp256k1.PrivateKey.Sign does
+func (s *KMSSigner) Sign(msg []byte) ([]byte, error) {
+	hash := sha256.Sum256(msg)
+	return s.SignHash(hash[:])
+}
+
+// Address returns the P-Chain and X-Chain address of the key
+func (s *KMSSigner) Address() ids.ShortID {
+	return s.pub.Address()
+}
+
+// EthAddress returns the EVM address of the key
+func (s *KMSSigner) EthAddress() common.Address {
+	return common.Address(s.pub.EthAddress())
+}
+
+// SignEVMTx signs tx for the EVM chain of chainID
+func (s *KMSSigner) SignEVMTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signer := types.LatestSignerForChainID(chainID)
+	hash := signer.Hash(tx)
+	sig, err := s.SignHash(hash[:])
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(signer, sig)
+}
+
+// KMSKeychain is the keychain of a single KMS key, for the UTXOs of its
+// address and the C-Chain exports of its EVM address
+type KMSKeychain struct {
+	Signer *KMSSigner
+}
+
+// NewKMSKeychain returns the keychain of keyName, a kms:// key of the KMS
+// server of LUX_KMS_ADDR
+func NewKMSKeychain(ctx context.Context, keyName string) (*KMSKeychain, error) {
+	keyID := strings.TrimPrefix(keyName, KMSKeyPrefix)
+	if keyID == "" || strings.Contains(keyID, "/") {
+		return nil, fmt.Errorf("invalid KMS key %q, expected %s<keyId>", keyName, KMSKeyPrefix)
+	}
+	signer, err := NewKMSSigner(ctx, NewKMSClientFromEnv(), keyID)
+	if err != nil {
+		return nil, err
+	}
+	return &KMSKeychain{Signer: signer}, nil
+}
+
+// Get returns the signer of addr
+func (kc *KMSKeychain) Get(addr ids.ShortID) (keychain.Signer, bool) {
+	if addr != kc.Signer.Address() {
+		return nil, false
+	}
+	return kc.Signer, true
+}
+
+// Addresses returns the address of the key
+func (kc *KMSKeychain) Addresses() set.Set[ids.ShortID] {
+	return set.Of(kc.Signer.Address())
+}
+
+// GetEth returns the signer of the EVM address addr
+func (kc *KMSKeychain) GetEth(addr common.Address) (keychain.Signer, bool) {
+	if addr != kc.Signer.EthAddress() {
+		return nil, false
+	}
+	return kc.Signer, true
+}
+
+// EthAddresses returns the EVM address of the key
+func (kc *KMSKeychain) EthAddresses() set.Set[common.Address] {
+	return set.Of(kc.Signer.EthAddress())
+}
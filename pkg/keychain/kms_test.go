@@ -0,0 +1,139 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keychain
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/luxfi/cli/pkg/kms"
+	"github.com/luxfi/cli/pkg/secretref"
+	"github.com/luxfi/crypto/secp256k1"
+	"github.com/luxfi/geth/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKMSKeychain(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	rootKey := make([]byte, 32)
+	_, err := rand.Read(rootKey)
+	require.NoError(err)
+	k, err := kms.New(&kms.Config{RootKey: rootKey, InMemory: true})
+	require.NoError(err)
+	defer func() { _ = k.Close() }()
+	cfg := kms.DefaultServerConfig()
+	cfg.APIKey = "secret"
+	server := httptest.NewServer(kms.NewServer(k, cfg).Handler())
+	defer server.Close()
+
+	key, err := k.GenerateKey(ctx, "treasury", kms.KeyTypeSecp256k1, kms.KeyUsageSignVerify, nil)
+	require.NoError(err)
+
+	t.Setenv(KMSAddrEnvVar, server.URL)
+	t.Setenv(KMSAPIKeyEnvVar, "secret")
+	require.True(IsKMSKey("kms://" + key.ID))
+	kc, err := NewKMSKeychain(ctx, "kms://"+key.ID)
+	require.NoError(err)
+
+	addr := kc.Signer.Address()
+	require.True(kc.Addresses().Contains(addr))
+	signer, ok := kc.Get(addr)
+	require.True(ok)
+	_, ok = kc.GetEth(kc.Signer.EthAddress())
+	require.True(ok)
+
+	// signatures recover to the key's address, as for a soft key
+	sig, err := signer.Sign([]byte("unsigned tx"))
+	require.NoError(err)
+	hash := sha256.Sum256([]byte("unsigned tx"))
+	pub, err := secp256k1.RecoverPublicKeyFromHash(hash[:], sig)
+	require.NoError(err)
+	require.Equal(addr, pub.Address())
+
+	chainID := big.NewInt(96369)
+	tx := types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 1, Gas: 21000, GasFeeCap: big.NewInt(1), GasTipCap: big.NewInt(1)})
+	signed, err := kc.Signer.SignEVMTx(tx, chainID)
+	require.NoError(err)
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	require.NoError(err)
+	require.Equal(kc.Signer.EthAddress(), from)
+
+	// a wrong API key or a non secp256k1 key is refused
+	t.Setenv(KMSAPIKeyEnvVar, "wrong")
+	_, err = NewKMSKeychain(ctx, "kms://"+key.ID)
+	require.ErrorContains(err, "status 401")
+	t.Setenv(KMSAPIKeyEnvVar, "secret")
+	ed, err := k.GenerateKey(ctx, "ed", kms.KeyTypeEdDSA, kms.KeyUsageSignVerify, nil)
+	require.NoError(err)
+	_, err = NewKMSKeychain(ctx, "kms://"+ed.ID)
+	require.ErrorContains(err, "is not a secp256k1 key")
+	_, err = NewKMSKeychain(ctx, "kms://")
+	require.ErrorContains(err, "invalid KMS key")
+}
+
+func TestKMSEncrypt(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	rootKey := make([]byte, 32)
+	_, err := rand.Read(rootKey)
+	require.NoError(err)
+	k, err := kms.New(&kms.Config{RootKey: rootKey, InMemory: true})
+	require.NoError(err)
+	defer func() { _ = k.Close() }()
+	server := httptest.NewServer(kms.NewServer(k, kms.DefaultServerConfig()).Handler())
+	defer server.Close()
+	client := &KMSClient{URL: server.URL, Client: server.Client()}
+
+	key, err := k.GenerateKey(ctx, "exports", kms.KeyTypeAES256, kms.KeyUsageEncryptDecrypt, nil)
+	require.NoError(err)
+	ciphertext, err := client.Encrypt(ctx, key.ID, []byte("cluster archive"))
+	require.NoError(err)
+	require.NotContains(string(ciphertext), "cluster archive")
+	plaintext, err := client.Decrypt(ctx, key.ID, ciphertext)
+	require.NoError(err)
+	require.Equal("cluster archive", string(plaintext))
+
+	// signing keys don't encrypt
+	signing, err := k.GenerateKey(ctx, "treasury", kms.KeyTypeSecp256k1, kms.KeyUsageSignVerify, nil)
+	require.NoError(err)
+	_, err = client.Encrypt(ctx, signing.ID, []byte("cluster archive"))
+	require.ErrorContains(err, "cannot be used for encryption")
+}
+
+func TestKMSSecrets(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	rootKey := make([]byte, 32)
+	_, err := rand.Read(rootKey)
+	require.NoError(err)
+	k, err := kms.New(&kms.Config{RootKey: rootKey, InMemory: true})
+	require.NoError(err)
+	defer func() { _ = k.Close() }()
+	server := httptest.NewServer(kms.NewServer(k, kms.DefaultServerConfig()).Handler())
+	defer server.Close()
+	client := &KMSClient{URL: server.URL, Client: server.Client()}
+
+	ref := secretref.Ref{Path: "/relayer", Name: "destination-blockchains.0.account-private-key"}
+	_, err = client.ResolveSecret(ctx, ref)
+	require.ErrorContains(err, "secret not found")
+
+	// storing creates the secret, then replaces its value
+	require.NoError(client.StoreSecret(ctx, ref, []byte("0x5628")))
+	require.NoError(client.StoreSecret(ctx, ref, []byte("0x9a1c")))
+	secrets, err := k.ListSecrets(ctx, "", "/relayer")
+	require.NoError(err)
+	require.Len(secrets, 1)
+
+	rendered, err := secretref.Render(ctx, []byte(`{"account-private-key": "`+ref.String()+`"}`), client)
+	require.NoError(err)
+	require.Equal(`{"account-private-key": "0x9a1c"}`, string(rendered))
+}
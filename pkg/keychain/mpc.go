@@ -0,0 +1,442 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keychain
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/luxfi/cli/pkg/kms"
+	"github.com/luxfi/cli/pkg/threshold"
+	"github.com/luxfi/crypto/secp256k1"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/keychain"
+	"github.com/luxfi/math/set"
+)
+
+// MPCKeyPrefix selects an MPC wallet of a KMS server in --key, as
+// mpc://<walletId>. Its txs are signed by a threshold of its parties.
+const MPCKeyPrefix = "mpc://"
+
+// MPCSharesEnvVar lists share files of MPC wallets, separated as in PATH,
+// that approve signing requests from this machine
+const MPCSharesEnvVar = "LUX_MPC_SHARES"
+
+// MPCPollInterval is how often a pending signing request is checked
+var MPCPollInterval = 2 * time.Second
+
+// IsMPCKey tells if keyName is an mpc:// key
+func IsMPCKey(keyName string) bool {
+	return strings.HasPrefix(keyName, MPCKeyPrefix)
+}
+
+// RegisterMPCNode registers a party of MPC wallets
+func (c *KMSClient) RegisterMPCNode(ctx context.Context, name string) (*kms.MPCNode, error) {
+	var resp struct {
+		Node *kms.MPCNode `json:"node"`
+	}
+	if err := c.doMPC(ctx, http.MethodPost, "nodes", map[string]string{"name": name}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Node, nil
+}
+
+// MPCNodes returns the registered parties of MPC wallets
+func (c *KMSClient) MPCNodes(ctx context.Context) ([]*kms.MPCNode, error) {
+	var resp struct {
+		Nodes []*kms.MPCNode `json:"nodes"`
+	}
+	if err := c.doMPC(ctx, http.MethodGet, "nodes", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Nodes, nil
+}
+
+// CreateMPCWallet creates a pending ECDSA wallet of the parties participantIDs
+func (c *KMSClient) CreateMPCWallet(ctx context.Context, name string, required int, participantIDs []string) (*kms.MPCWallet, error) {
+	req := map[string]interface{}{
+		"name":           name,
+		"keyType":        kms.MPCKeyTypeECDSA,
+		"threshold":      required,
+		"totalParties":   len(participantIDs),
+		"participantIds": participantIDs,
+	}
+	var resp struct {
+		Wallet *kms.MPCWallet `json:"wallet"`
+	}
+	if err := c.doMPC(ctx, http.MethodPost, "wallets", req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Wallet, nil
+}
+
+// ActivateMPCWallet sets the public key of a pending wallet and the number
+// of presignatures dealt with it
+func (c *KMSClient) ActivateMPCWallet(ctx context.Context, walletID string, publicKey []byte, presignatures int) (*kms.MPCWallet, error) {
+	req := map[string]interface{}{
+		"publicKey":     base64.StdEncoding.EncodeToString(publicKey),
+		"presignatures": presignatures,
+	}
+	var resp struct {
+		Wallet *kms.MPCWallet `json:"wallet"`
+	}
+	if err := c.doMPC(ctx, http.MethodPost, "wallets/"+url.PathEscape(walletID)+"/activate", req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Wallet, nil
+}
+
+// MPCWallets returns the MPC wallets
+func (c *KMSClient) MPCWallets(ctx context.Context) ([]*kms.MPCWallet, error) {
+	var resp struct {
+		Wallets []*kms.MPCWallet `json:"wallets"`
+	}
+	if err := c.doMPC(ctx, http.MethodGet, "wallets", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Wallets, nil
+}
+
+// MPCWallet returns the MPC wallet walletID
+func (c *KMSClient) MPCWallet(ctx context.Context, walletID string) (*kms.MPCWallet, error) {
+	var resp struct {
+		Wallet *kms.MPCWallet `json:"wallet"`
+	}
+	if err := c.doMPC(ctx, http.MethodGet, "wallets/"+url.PathEscape(walletID), nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Wallet, nil
+}
+
+// RequestMPCSignature opens a request for the parties of walletID to sign
+// digest, the hash of rawTx
+func (c *KMSClient) RequestMPCSignature(ctx context.Context, walletID string, digest, rawTx []byte, metadata map[string]string) (*kms.MPCSigningRequest, error) {
+	req := map[string]interface{}{
+		"walletId":       walletID,
+		"chain":          kms.MPCChainLux,
+		"rawTransaction": base64.StdEncoding.EncodeToString(rawTx),
+		"message":        base64.StdEncoding.EncodeToString(digest),
+		"metadata":       metadata,
+	}
+	var resp struct {
+		SigningRequest *kms.MPCSigningRequest `json:"signingRequest"`
+	}
+	if err := c.doMPC(ctx, http.MethodPost, "sign", req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.SigningRequest, nil
+}
+
+// MPCSigningRequest returns the signing request requestID
+func (c *KMSClient) MPCSigningRequest(ctx context.Context, requestID string) (*kms.MPCSigningRequest, error) {
+	var resp struct {
+		SigningRequest *kms.MPCSigningRequest `json:"signingRequest"`
+	}
+	if err := c.doMPC(ctx, http.MethodGet, "signing/"+url.PathEscape(requestID), nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.SigningRequest, nil
+}
+
+// SubmitMPCPartial submits the partial signature of party nodeID
+func (c *KMSClient) SubmitMPCPartial(ctx context.Context, requestID, nodeID string, partial []byte) (*kms.MPCSigningRequest, error) {
+	req := map[string]string{
+		"nodeId":           nodeID,
+		"partialSignature": base64.StdEncoding.EncodeToString(partial),
+	}
+	var resp struct {
+		SigningRequest *kms.MPCSigningRequest `json:"signingRequest"`
+	}
+	if err := c.doMPC(ctx, http.MethodPost, "signing/"+url.PathEscape(requestID)+"/signature", req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.SigningRequest, nil
+}
+
+// SetMPCSignature records the combined signature of requestID
+func (c *KMSClient) SetMPCSignature(ctx context.Context, requestID string, sig []byte) error {
+	req := map[string]string{"signature": base64.StdEncoding.EncodeToString(sig)}
+	return c.doMPC(ctx, http.MethodPost, "signing/"+url.PathEscape(requestID)+"/final", req, &struct{}{})
+}
+
+func (c *KMSClient) doMPC(ctx context.Context, method, path string, body, result interface{}) error {
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.URL+"/v1/mpc/"+path, &reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("X-API-Key", c.APIKey)
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the KMS server at %s: %w", c.URL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		return fmt.Errorf("KMS MPC %s %s: %s (status %d)", method, path, errResp.Error, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// LoadMPCShare reads the share file of a party of an MPC wallet
+func LoadMPCShare(path string) (*threshold.Share, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: Reading the share the user gave
+	if err != nil {
+		return nil, err
+	}
+	share := &threshold.Share{}
+	if err := json.Unmarshal(data, share); err != nil {
+		return nil, fmt.Errorf("invalid MPC share %s: %w", path, err)
+	}
+	if share.WalletID == "" || share.NodeID == "" {
+		return nil, fmt.Errorf("invalid MPC share %s: no wallet or node", path)
+	}
+	return share, nil
+}
+
+// ApproveMPCRequest signs the signing request with share and submits the
+// partial signature
+func ApproveMPCRequest(ctx context.Context, client *KMSClient, share *threshold.Share, request *kms.MPCSigningRequest) (*kms.MPCSigningRequest, error) {
+	if request.WalletID != share.WalletID {
+		return nil, fmt.Errorf("signing request %s is for wallet %s, not %s", request.ID, request.WalletID, share.WalletID)
+	}
+	partial, err := share.Sign(request.Presignature, request.Message)
+	if err != nil {
+		return nil, err
+	}
+	return client.SubmitMPCPartial(ctx, request.ID, share.NodeID, partial)
+}
+
+// MPCSigner signs with an MPC wallet of a KMS server: each signature is a
+// signing request approved by a threshold of the wallet's parties, whose
+// partial signatures are combined here. It implements keychain.Signer, as
+// KMSSigner does.
+type MPCSigner struct {
+	client *KMSClient
+	wallet *kms.MPCWallet
+	pub    *secp256k1.PublicKey
+
+	// Shares are the shares of the wallet on this machine, which approve
+	// each request as it is made
+	Shares []*threshold.Share
+	// OnRequest is called with each signing request, to tell the parties
+	OnRequest func(*kms.MPCSigningRequest)
+	// OnProgress is called as partial signatures are collected
+	OnProgress func(*kms.MPCSigningRequest)
+
+	mu     sync.Mutex
+	signed map[string][]byte
+}
+
+// NewMPCSigner returns the signer of walletID, which must be an active
+// ECDSA wallet
+func NewMPCSigner(ctx context.Context, client *KMSClient, walletID string) (*MPCSigner, error) {
+	wallet, err := client.MPCWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.KeyType != kms.MPCKeyTypeECDSA {
+		return nil, fmt.Errorf("MPC wallet %s is not an ECDSA wallet", walletID)
+	}
+	if wallet.Status != kms.KeyStatusActive {
+		return nil, fmt.Errorf("MPC wallet %s is not active", walletID)
+	}
+	pub, err := secp256k1.ToPublicKey(wallet.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key of MPC wallet %s: %w", walletID, err)
+	}
+	return &MPCSigner{client: client, wallet: wallet, pub: pub, signed: map[string][]byte{}}, nil
+}
+
+// Wallet returns the wallet of the signer
+func (s *MPCSigner) Wallet() *kms.MPCWallet {
+	return s.wallet
+}
+
+// SignHash opens a signing request for hash and waits for a threshold of
+// the parties to approve it, until it expires. A hash signed before is not
+// requested again, so a tx with several inputs of the wallet takes one
+// request.
+func (s *MPCSigner) SignHash(hash []byte) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sig, ok := s.signed[string(hash)]; ok {
+		return sig, nil
+	}
+
+	ctx := context.Background()
+	request, err := s.client.RequestMPCSignature(ctx, s.wallet.ID, hash, hash, nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.OnRequest != nil {
+		s.OnRequest(request)
+	}
+	for _, share := range s.Shares {
+		if share.WalletID != s.wallet.ID {
+			continue
+		}
+		approved, err := ApproveMPCRequest(ctx, s.client, share, request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to approve signing request %s with the share of %s: %w", request.ID, share.NodeID, err)
+		}
+		request = approved
+	}
+	request, err = s.wait(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	partials := map[int][]byte{}
+	for i, nodeID := range s.wallet.ParticipantIDs {
+		if partial, ok := request.Signatures[nodeID]; ok {
+			partials[i+1] = partial
+		}
+	}
+	sig, err := threshold.Combine(s.wallet.PublicKey, hash, s.wallet.Threshold, partials)
+	if err != nil {
+		return nil, fmt.Errorf("signing request %s: %w", request.ID, err)
+	}
+	if err := s.client.SetMPCSignature(ctx, request.ID, sig); err != nil {
+		return nil, err
+	}
+	s.signed[string(hash)] = sig
+	return sig, nil
+}
+
+// wait polls request until a threshold of the parties approved it
+func (s *MPCSigner) wait(ctx context.Context, request *kms.MPCSigningRequest) (*kms.MPCSigningRequest, error) {
+	collected := -1
+	for {
+		if request.CollectedSigs != collected {
+			collected = request.CollectedSigs
+			if s.OnProgress != nil {
+				s.OnProgress(request)
+			}
+		}
+		if request.CollectedSigs >= request.RequiredSigs {
+			return request, nil
+		}
+		if request.Status == kms.SigningStatusFailed || request.Status == kms.SigningStatusExpired || time.Now().After(request.ExpiresAt) {
+			return nil, fmt.Errorf("signing request %s expired with %d of %d approvals", request.ID, request.CollectedSigs, request.RequiredSigs)
+		}
+		time.Sleep(MPCPollInterval)
+		next, err := s.client.MPCSigningRequest(ctx, request.ID)
+		if err != nil {
+			return nil, err
+		}
+		request = next
+	}
+}
+
+// Sign signs the sha256 hash of msg, as secp256k1.PrivateKey.Sign does
+func (s *MPCSigner) Sign(msg []byte) ([]byte, error) {
+	hash := sha256.Sum256(msg)
+	return s.SignHash(hash[:])
+}
+
+// Address returns the P-Chain and X-Chain address of the wallet
+func (s *MPCSigner) Address() ids.ShortID {
+	return s.pub.Address()
+}
+
+// EthAddress returns the EVM address of the wallet
+func (s *MPCSigner) EthAddress() common.Address {
+	return common.Address(s.pub.EthAddress())
+}
+
+// SignEVMTx signs tx for the EVM chain of chainID
+func (s *MPCSigner) SignEVMTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signer := types.LatestSignerForChainID(chainID)
+	hash := signer.Hash(tx)
+	sig, err := s.SignHash(hash[:])
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(signer, sig)
+}
+
+// MPCKeychain is the keychain of a single MPC wallet, for the UTXOs of its
+// address and the C-Chain exports of its EVM address
+type MPCKeychain struct {
+	Signer *MPCSigner
+}
+
+// NewMPCKeychain returns the keychain of keyName, an mpc:// wallet of the
+// KMS server of LUX_KMS_ADDR. The shares of LUX_MPC_SHARES for the wallet
+// approve its signing requests from this machine.
+func NewMPCKeychain(ctx context.Context, keyName string) (*MPCKeychain, error) {
+	walletID := strings.TrimPrefix(keyName, MPCKeyPrefix)
+	if walletID == "" || strings.Contains(walletID, "/") {
+		return nil, fmt.Errorf("invalid MPC wallet %q, expected %s<walletId>", keyName, MPCKeyPrefix)
+	}
+	signer, err := NewMPCSigner(ctx, NewKMSClientFromEnv(), walletID)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range filepath.SplitList(os.Getenv(MPCSharesEnvVar)) {
+		if path == "" {
+			continue
+		}
+		share, err := LoadMPCShare(path)
+		if err != nil {
+			return nil, err
+		}
+		if share.WalletID == walletID {
+			signer.Shares = append(signer.Shares, share)
+		}
+	}
+	return &MPCKeychain{Signer: signer}, nil
+}
+
+// Get returns the signer of addr
+func (kc *MPCKeychain) Get(addr ids.ShortID) (keychain.Signer, bool) {
+	if addr != kc.Signer.Address() {
+		return nil, false
+	}
+	return kc.Signer, true
+}
+
+// Addresses returns the address of the wallet
+func (kc *MPCKeychain) Addresses() set.Set[ids.ShortID] {
+	return set.Of(kc.Signer.Address())
+}
+
+// GetEth returns the signer of the EVM address addr
+func (kc *MPCKeychain) GetEth(addr common.Address) (keychain.Signer, bool) {
+	if addr != kc.Signer.EthAddress() {
+		return nil, false
+	}
+	return kc.Signer, true
+}
+
+// EthAddresses returns the EVM address of the wallet
+func (kc *MPCKeychain) EthAddresses() set.Set[common.Address] {
+	return set.Of(kc.Signer.EthAddress())
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keychain
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/luxfi/cli/pkg/kms"
+	"github.com/luxfi/cli/pkg/threshold"
+	"github.com/luxfi/crypto/secp256k1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMPCKeychain(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	rootKey := make([]byte, 32)
+	_, err := rand.Read(rootKey)
+	require.NoError(err)
+	k, err := kms.New(&kms.Config{RootKey: rootKey, InMemory: true})
+	require.NoError(err)
+	defer func() { _ = k.Close() }()
+	cfg := kms.DefaultServerConfig()
+	cfg.APIKey = "secret"
+	server := httptest.NewServer(kms.NewServer(k, cfg).Handler())
+	defer server.Close()
+	t.Setenv(KMSAddrEnvVar, server.URL)
+	t.Setenv(KMSAPIKeyEnvVar, "secret")
+	client := NewKMSClientFromEnv()
+
+	// a 2-of-3 wallet with 2 presignatures
+	var participants []string
+	for _, name := range []string{"alice", "bob", "carol"} {
+		node, err := client.RegisterMPCNode(ctx, name)
+		require.NoError(err)
+		participants = append(participants, node.ID)
+	}
+	wallet, err := client.CreateMPCWallet(ctx, "treasury", 2, participants)
+	require.NoError(err)
+	_, err = NewMPCKeychain(ctx, MPCKeyPrefix+wallet.ID)
+	require.ErrorContains(err, "is not active")
+	shares, err := threshold.Deal(2, 3, 2)
+	require.NoError(err)
+	dir := t.TempDir()
+	var paths []string
+	for i, share := range shares {
+		share.WalletID = wallet.ID
+		share.NodeID = participants[i]
+		data, err := json.Marshal(share)
+		require.NoError(err)
+		path := filepath.Join(dir, share.NodeID+".json")
+		require.NoError(os.WriteFile(path, data, 0o600))
+		paths = append(paths, path)
+	}
+	_, err = client.ActivateMPCWallet(ctx, wallet.ID, shares[0].PublicKey, 2)
+	require.NoError(err)
+	_, err = client.ActivateMPCWallet(ctx, wallet.ID, shares[0].PublicKey, 2)
+	require.ErrorContains(err, "already has a public key")
+
+	// alice approves from this machine, carol from another one
+	MPCPollInterval = 10 * time.Millisecond
+	t.Setenv(MPCSharesEnvVar, paths[0])
+	require.True(IsMPCKey(MPCKeyPrefix + wallet.ID))
+	kc, err := NewMPCKeychain(ctx, MPCKeyPrefix+wallet.ID)
+	require.NoError(err)
+	require.Len(kc.Signer.Shares, 1)
+	kc.Signer.OnRequest = func(request *kms.MPCSigningRequest) {
+		go func() {
+			carol, err := LoadMPCShare(paths[2])
+			if err == nil {
+				_, err = ApproveMPCRequest(ctx, client, carol, request)
+			}
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	addr := kc.Signer.Address()
+	require.True(kc.Addresses().Contains(addr))
+	signer, ok := kc.Get(addr)
+	require.True(ok)
+	sig, err := signer.Sign([]byte("unsigned tx"))
+	require.NoError(err)
+	hash := sha256.Sum256([]byte("unsigned tx"))
+	pub, err := secp256k1.RecoverPublicKeyFromHash(hash[:], sig)
+	require.NoError(err)
+	require.Equal(addr, pub.Address())
+
+	// a hash signed before takes no presignature
+	again, err := signer.Sign([]byte("unsigned tx"))
+	require.NoError(err)
+	require.Equal(sig, again)
+	wallet, err = client.MPCWallet(ctx, wallet.ID)
+	require.NoError(err)
+	require.Equal(1, wallet.PresignaturesUsed)
+
+	// the last presignature is used, then signing stops
+	_, err = signer.Sign([]byte("another tx"))
+	require.NoError(err)
+	_, err = signer.Sign([]byte("a third tx"))
+	require.ErrorContains(err, "used all its 2 presignatures")
+
+	_, err = NewMPCKeychain(ctx, "mpc://")
+	require.ErrorContains(err, "invalid MPC wallet")
+}
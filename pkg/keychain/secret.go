@@ -0,0 +1,80 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keychain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/luxfi/cli/pkg/secretref"
+)
+
+// errSecretNotFound is returned by the KMS server for a missing secret
+var errSecretNotFound = errors.New("secret not found")
+
+// ResolveSecret returns the value of the secret ref, so that the client
+// renders the kms://secret/ references of config templates
+func (c *KMSClient) ResolveSecret(ctx context.Context, ref secretref.Ref) ([]byte, error) {
+	var resp struct {
+		Secret struct {
+			SecretValue string `json:"secretValue"`
+		} `json:"secret"`
+	}
+	if err := c.doSecret(ctx, http.MethodGet, ref, "/value", nil, &resp); err != nil {
+		return nil, err
+	}
+	return []byte(resp.Secret.SecretValue), nil
+}
+
+// StoreSecret sets the value of the secret ref, creating it if missing
+func (c *KMSClient) StoreSecret(ctx context.Context, ref secretref.Ref, value []byte) error {
+	req := map[string]string{"secretPath": ref.Path, "secretValue": string(value)}
+	err := c.doSecret(ctx, http.MethodPatch, ref, "", req, &struct{}{})
+	if errors.Is(err, errSecretNotFound) {
+		err = c.doSecret(ctx, http.MethodPost, ref, "", req, &struct{}{})
+	}
+	return err
+}
+
+func (c *KMSClient) doSecret(ctx context.Context, method string, ref secretref.Ref, suffix string, body, result interface{}) error {
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			return err
+		}
+	}
+	endpoint := fmt.Sprintf("%s/v3/secrets/raw/%s%s", c.URL, url.PathEscape(ref.Name), suffix)
+	if method == http.MethodGet {
+		endpoint += "?secretPath=" + url.QueryEscape(ref.Path)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, &reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("X-API-Key", c.APIKey)
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the KMS server at %s: %w", c.URL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("KMS %s: %w", ref, errSecretNotFound)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		return fmt.Errorf("KMS %s %s: %s (status %d)", method, ref, errResp.Error, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,252 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package kms
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// AuditFileName is the audit log file name inside the KMS data directory.
+const AuditFileName = "audit.jsonl"
+
+// AuditOperation identifies the KMS operation recorded in an audit event.
+type AuditOperation string
+
+const (
+	AuditOpKeyGenerate     AuditOperation = "key.generate"
+	AuditOpKeyDelete       AuditOperation = "key.delete"
+	AuditOpKeyRotate       AuditOperation = "key.rotate"
+	AuditOpEncrypt         AuditOperation = "encrypt"
+	AuditOpDecrypt         AuditOperation = "decrypt"
+	AuditOpSign            AuditOperation = "sign"
+	AuditOpSecretCreate    AuditOperation = "secret.create"
+	AuditOpSecretRead      AuditOperation = "secret.read"
+	AuditOpSecretUpdate    AuditOperation = "secret.update"
+	AuditOpSecretDelete    AuditOperation = "secret.delete"
+	AuditOpSecretReencrypt AuditOperation = "secret.reencrypt"
+)
+
+// ErrAuditChainBroken is returned when the audit log hash chain does not verify.
+var ErrAuditChainBroken = errors.New("audit log hash chain broken")
+
+// AuditEvent is a single entry of the audit log. Each event stores the hash of
+// the previous one, so removing or editing an entry breaks the chain.
+type AuditEvent struct {
+	Seq       uint64         `json:"seq"`
+	Time      time.Time      `json:"time"`
+	Operation AuditOperation `json:"operation"`
+	KeyID     string         `json:"keyId,omitempty"`
+	SecretID  string         `json:"secretId,omitempty"`
+	Success   bool           `json:"success"`
+	Error     string         `json:"error,omitempty"`
+	PrevHash  string         `json:"prevHash"`
+	Hash      string         `json:"hash"`
+}
+
+// computeHash returns the hex SHA-256 of the event with its Hash field cleared.
+func (e AuditEvent) computeHash() (string, error) {
+	e.Hash = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// AuditFilter selects audit events. Zero values match everything.
+type AuditFilter struct {
+	KeyID     string
+	Operation AuditOperation
+	Since     time.Time
+	Until     time.Time
+	Limit     int // most recent N events; 0 means no limit
+}
+
+func (f AuditFilter) matches(e *AuditEvent) bool {
+	if f.KeyID != "" && e.KeyID != f.KeyID {
+		return false
+	}
+	if f.Operation != "" && e.Operation != f.Operation {
+		return false
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && e.Time.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// AuditLog is an append-only, hash-chained JSONL audit log. With an empty
+// path the log is kept in memory only.
+type AuditLog struct {
+	mu       sync.Mutex
+	path     string
+	events   []AuditEvent // only populated for in-memory logs
+	seq      uint64
+	lastHash string
+}
+
+// OpenAuditLog opens the audit log at path, verifying the existing chain so
+// new events continue from its last entry.
+func OpenAuditLog(path string) (*AuditLog, error) {
+	l := &AuditLog{path: path}
+	if path == "" {
+		return l, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	events, err := ReadAuditLog(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyAuditChain(events); err != nil {
+		return nil, err
+	}
+	if n := len(events); n > 0 {
+		l.seq = events[n-1].Seq
+		l.lastHash = events[n-1].Hash
+	}
+	return l, nil
+}
+
+// Record appends an event for operation. opErr is the operation's outcome.
+func (l *AuditLog) Record(op AuditOperation, keyID, secretID string, opErr error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	event := AuditEvent{
+		Seq:       l.seq + 1,
+		Time:      time.Now().UTC(),
+		Operation: op,
+		KeyID:     keyID,
+		SecretID:  secretID,
+		Success:   opErr == nil,
+		PrevHash:  l.lastHash,
+	}
+	if opErr != nil {
+		event.Error = opErr.Error()
+	}
+	hash, err := event.computeHash()
+	if err != nil {
+		return err
+	}
+	event.Hash = hash
+
+	if l.path == "" {
+		l.events = append(l.events, event)
+	} else {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to open audit log: %w", err)
+		}
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to write audit log: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+
+	l.seq = event.Seq
+	l.lastHash = event.Hash
+	return nil
+}
+
+// Query returns the events matching filter, oldest first.
+func (l *AuditLog) Query(filter AuditFilter) ([]AuditEvent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	events := l.events
+	if l.path != "" {
+		var err error
+		events, err = ReadAuditLog(l.path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return FilterAuditEvents(events, filter), nil
+}
+
+// ReadAuditLog reads all events from a JSONL audit log file. A missing file
+// yields no events.
+func ReadAuditLog(path string) ([]AuditEvent, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: KMS data directory
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var events []AuditEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("invalid audit log entry at line %d: %w", line, err)
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// FilterAuditEvents applies filter to events, keeping their order.
+func FilterAuditEvents(events []AuditEvent, filter AuditFilter) []AuditEvent {
+	result := make([]AuditEvent, 0, len(events))
+	for i := range events {
+		if filter.matches(&events[i]) {
+			result = append(result, events[i])
+		}
+	}
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[len(result)-filter.Limit:]
+	}
+	return result
+}
+
+// VerifyAuditChain checks sequence numbers, hashes and links of events.
+func VerifyAuditChain(events []AuditEvent) error {
+	prevHash := ""
+	for i, event := range events {
+		if event.Seq != uint64(i+1) {
+			return fmt.Errorf("%w: expected seq %d, found %d", ErrAuditChainBroken, i+1, event.Seq)
+		}
+		if event.PrevHash != prevHash {
+			return fmt.Errorf("%w: seq %d does not link to its predecessor", ErrAuditChainBroken, event.Seq)
+		}
+		hash, err := event.computeHash()
+		if err != nil {
+			return err
+		}
+		if hash != event.Hash {
+			return fmt.Errorf("%w: seq %d has been modified", ErrAuditChainBroken, event.Seq)
+		}
+		prevHash = event.Hash
+	}
+	return nil
+}
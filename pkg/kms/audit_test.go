@@ -0,0 +1,87 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package kms
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogChain(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), AuditFileName)
+
+	log, err := OpenAuditLog(path)
+	require.NoError(err)
+	require.NoError(log.Record(AuditOpKeyGenerate, "k1", "", nil))
+	require.NoError(log.Record(AuditOpSign, "k1", "", nil))
+	require.NoError(log.Record(AuditOpDecrypt, "k2", "", errors.New("boom")))
+
+	// Reopening continues the chain
+	log, err = OpenAuditLog(path)
+	require.NoError(err)
+	require.NoError(log.Record(AuditOpSecretRead, "k2", "s1", nil))
+
+	events, err := ReadAuditLog(path)
+	require.NoError(err)
+	require.Len(events, 4)
+	require.NoError(VerifyAuditChain(events))
+	require.Equal(uint64(4), events[3].Seq)
+	require.False(events[2].Success)
+	require.Equal("boom", events[2].Error)
+
+	// Tampering with an entry breaks the chain
+	data, err := os.ReadFile(path)
+	require.NoError(err)
+	tampered := strings.Replace(string(data), `"operation":"sign"`, `"operation":"encrypt"`, 1)
+	require.NoError(os.WriteFile(path, []byte(tampered), 0o600))
+	_, err = OpenAuditLog(path)
+	require.ErrorIs(err, ErrAuditChainBroken)
+}
+
+func TestAuditLogQuery(t *testing.T) {
+	require := require.New(t)
+
+	log, err := OpenAuditLog("")
+	require.NoError(err)
+	require.NoError(log.Record(AuditOpKeyGenerate, "k1", "", nil))
+	require.NoError(log.Record(AuditOpSign, "k1", "", nil))
+	require.NoError(log.Record(AuditOpSign, "k2", "", nil))
+
+	events, err := log.Query(AuditFilter{KeyID: "k1"})
+	require.NoError(err)
+	require.Len(events, 2)
+
+	events, err = log.Query(AuditFilter{Operation: AuditOpSign, Limit: 1})
+	require.NoError(err)
+	require.Len(events, 1)
+	require.Equal("k2", events[0].KeyID)
+
+	events, err = log.Query(AuditFilter{Since: time.Now().Add(time.Hour)})
+	require.NoError(err)
+	require.Empty(events)
+}
+
+func TestAuditFailureReported(t *testing.T) {
+	require := require.New(t)
+	rootKey := make([]byte, 32)
+	path := filepath.Join(t.TempDir(), AuditFileName)
+	var auditErrs strings.Builder
+	k, err := New(&Config{RootKey: rootKey, InMemory: true, AuditLogPath: path, AuditErrors: &auditErrs})
+	require.NoError(err)
+	t.Cleanup(func() { _ = k.Close() })
+
+	// a directory in place of the log file makes every write fail
+	require.NoError(os.Mkdir(path, 0o700))
+	_, err = k.GenerateKey(context.Background(), "data", KeyTypeAES256, KeyUsageEncryptDecrypt, nil)
+	require.NoError(err)
+	require.Contains(auditErrs.String(), "failed to record audit event")
+}
@@ -20,8 +20,12 @@ import (
 	"encoding/pem"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/luxfi/crypto/secp256k1"
 )
 
 // KeyType represents the type of cryptographic key.
@@ -34,6 +38,9 @@ const (
 	KeyTypeECDSAP256 KeyType = "ecdsa-p256"
 	KeyTypeECDSAP384 KeyType = "ecdsa-p384"
 	KeyTypeEdDSA     KeyType = "ed25519"
+	// KeyTypeSecp256k1 keys sign Lux and EVM transactions, with recoverable
+	// [r || s || v] signatures
+	KeyTypeSecp256k1 KeyType = "secp256k1"
 )
 
 // KeyUsage represents what a key can be used for.
@@ -111,6 +118,8 @@ type KMS struct {
 	store      StorageBackend
 	rootKey    []byte // 32-byte root encryption key
 	rootCipher cipher.AEAD
+	audit      *AuditLog
+	auditErrs  io.Writer
 	mu         sync.RWMutex
 }
 
@@ -121,6 +130,12 @@ type Config struct {
 	DataDir     string // Only used if Store is nil (creates BadgerStore)
 	InMemory    bool
 	Compression bool
+	// AuditLogPath is the audit log file. Defaults to DataDir/audit.jsonl,
+	// or an in-memory log when InMemory is set.
+	AuditLogPath string
+	// AuditErrors receives the audit events that could not be recorded.
+	// Defaults to stderr.
+	AuditErrors io.Writer
 }
 
 // New creates a new KMS instance.
@@ -155,13 +170,43 @@ func New(cfg *Config) (*KMS, error) {
 		return nil, fmt.Errorf("failed to create GCM: %w", err)
 	}
 
+	auditPath := cfg.AuditLogPath
+	if auditPath == "" && !cfg.InMemory && cfg.DataDir != "" {
+		auditPath = filepath.Join(cfg.DataDir, AuditFileName)
+	}
+	audit, err := OpenAuditLog(auditPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	auditErrs := cfg.AuditErrors
+	if auditErrs == nil {
+		auditErrs = os.Stderr
+	}
+
 	return &KMS{
 		store:      store,
 		rootKey:    cfg.RootKey,
 		rootCipher: rootCipher,
+		audit:      audit,
+		auditErrs:  auditErrs,
 	}, nil
 }
 
+// Audit returns the KMS audit log.
+func (k *KMS) Audit() *AuditLog {
+	return k.audit
+}
+
+// recordAudit appends an audit event. Audit failures are reported on
+// Config.AuditErrors rather than failing the operation that has already
+// happened.
+func (k *KMS) recordAudit(op AuditOperation, keyID, secretID string, opErr error) {
+	if err := k.audit.Record(op, keyID, secretID, opErr); err != nil {
+		fmt.Fprintf(k.auditErrs, "kms: failed to record audit event %s: %v\n", op, err)
+	}
+}
+
 // Key prefix constants
 const (
 	keyPrefix         = "kms/key/"
@@ -170,11 +215,12 @@ const (
 )
 
 // GenerateKey generates a new cryptographic key.
-func (k *KMS) GenerateKey(ctx context.Context, name string, keyType KeyType, usage KeyUsage, opts *KeyOptions) (*Key, error) {
+func (k *KMS) GenerateKey(ctx context.Context, name string, keyType KeyType, usage KeyUsage, opts *KeyOptions) (_ *Key, err error) {
 	k.mu.Lock()
 	defer k.mu.Unlock()
 
 	keyID := generateID(16)
+	defer func() { k.recordAudit(AuditOpKeyGenerate, keyID, "", err) }()
 	now := time.Now()
 
 	key := &Key{
@@ -200,7 +246,7 @@ func (k *KMS) GenerateKey(ctx context.Context, name string, keyType KeyType, usa
 	}
 
 	// Generate key material
-	material, err := k.generateKeyMaterial(keyID, keyType)
+	material, err := k.generateKeyMaterial(keyID, keyType, 1)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate key material: %w", err)
 	}
@@ -227,11 +273,11 @@ type KeyOptions struct {
 	ExpiresIn   time.Duration
 }
 
-// generateKeyMaterial creates and encrypts key material.
-func (k *KMS) generateKeyMaterial(keyID string, keyType KeyType) (*KeyMaterial, error) {
+// generateKeyMaterial creates and encrypts a version of the key material.
+func (k *KMS) generateKeyMaterial(keyID string, keyType KeyType, version int) (*KeyMaterial, error) {
 	material := &KeyMaterial{
 		KeyID:   keyID,
-		Version: 1,
+		Version: version,
 		Created: time.Now(),
 	}
 
@@ -304,6 +350,14 @@ func (k *KMS) generateKeyMaterial(keyID string, keyType KeyType) (*KeyMaterial,
 		privateBytes = priv
 		publicBytes = pub
 
+	case KeyTypeSecp256k1:
+		key, err := secp256k1.NewPrivateKey()
+		if err != nil {
+			return nil, err
+		}
+		privateBytes = key.Bytes()
+		publicBytes = key.PublicKey().CompressedBytes()
+
 	default:
 		return nil, fmt.Errorf("unsupported key type: %s", keyType)
 	}
@@ -363,9 +417,10 @@ func (k *KMS) ListKeys(ctx context.Context, prefix string) ([]*Key, error) {
 }
 
 // DeleteKey soft-deletes a key.
-func (k *KMS) DeleteKey(ctx context.Context, keyID string) error {
+func (k *KMS) DeleteKey(ctx context.Context, keyID string) (err error) {
 	k.mu.Lock()
 	defer k.mu.Unlock()
+	defer func() { k.recordAudit(AuditOpKeyDelete, keyID, "", err) }()
 
 	key, err := k.GetKey(ctx, keyID)
 	if err != nil {
@@ -378,8 +433,46 @@ func (k *KMS) DeleteKey(ctx context.Context, keyID string) error {
 	return SetJSON(ctx, k.store, keyPrefix+keyID, key)
 }
 
+// RotateKey creates a new version of an encryption key. New encryptions use the
+// new version; the old versions are kept so existing ciphertexts still decrypt.
+// Secrets are re-encrypted lazily when read, or with ReencryptSecrets.
+func (k *KMS) RotateKey(ctx context.Context, keyID string) (_ *Key, err error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	defer func() { k.recordAudit(AuditOpKeyRotate, keyID, "", err) }()
+
+	key, err := k.GetKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if key.Status != KeyStatusActive {
+		return nil, fmt.Errorf("key %s is not active", keyID)
+	}
+	// Signatures verify against the current version only, so only encryption
+	// keys keep working for their existing data after a rotation
+	if key.Usage != KeyUsageEncryptDecrypt {
+		return nil, fmt.Errorf("key %s is a %s key, only encrypt-decrypt keys can be rotated", keyID, key.Usage)
+	}
+
+	material, err := k.generateKeyMaterial(keyID, key.Type, key.Version+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key material: %w", err)
+	}
+	if err := SetJSON(ctx, k.store, fmt.Sprintf("%s%s/%d", keyMaterialPrefix, keyID, material.Version), material); err != nil {
+		return nil, fmt.Errorf("failed to save key material: %w", err)
+	}
+	key.Version = material.Version
+	key.Updated = time.Now()
+	if err := SetJSON(ctx, k.store, keyPrefix+keyID, key); err != nil {
+		return nil, fmt.Errorf("failed to save key: %w", err)
+	}
+	return key, nil
+}
+
 // Encrypt encrypts data using the specified key.
-func (k *KMS) Encrypt(ctx context.Context, keyID string, plaintext []byte) ([]byte, error) {
+func (k *KMS) Encrypt(ctx context.Context, keyID string, plaintext []byte) (_ []byte, err error) {
+	defer func() { k.recordAudit(AuditOpEncrypt, keyID, "", err) }()
+
 	key, err := k.GetKey(ctx, keyID)
 	if err != nil {
 		return nil, err
@@ -443,8 +536,10 @@ type EncryptedData struct {
 }
 
 // Decrypt decrypts data.
-func (k *KMS) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+func (k *KMS) Decrypt(ctx context.Context, ciphertext []byte) (_ []byte, err error) {
 	var encrypted EncryptedData
+	defer func() { k.recordAudit(AuditOpDecrypt, encrypted.KeyID, "", err) }()
+
 	if err := json.Unmarshal(ciphertext, &encrypted); err != nil {
 		return nil, fmt.Errorf("invalid ciphertext format: %w", err)
 	}
@@ -507,34 +602,26 @@ func (k *KMS) GetPublicKey(ctx context.Context, keyID string) ([]byte, error) {
 	}
 
 	// Return PEM-encoded public key
+	blockType := "PUBLIC KEY"
+	if key.Type == KeyTypeSecp256k1 {
+		// the compressed point, as secp256k1 has no PKIX encoding in x509
+		blockType = "SECP256K1 PUBLIC KEY"
+	}
 	return pem.EncodeToMemory(&pem.Block{
-		Type:  "PUBLIC KEY",
+		Type:  blockType,
 		Bytes: material.PublicKey,
 	}), nil
 }
 
 // Sign signs data using an asymmetric key.
-func (k *KMS) Sign(ctx context.Context, keyID string, data []byte) ([]byte, error) {
-	key, err := k.GetKey(ctx, keyID)
-	if err != nil {
-		return nil, err
-	}
+func (k *KMS) Sign(ctx context.Context, keyID string, data []byte) (_ []byte, err error) {
+	defer func() { k.recordAudit(AuditOpSign, keyID, "", err) }()
 
-	if key.Usage != KeyUsageSignVerify {
-		return nil, fmt.Errorf("key %s cannot be used for signing", keyID)
-	}
-
-	material, err := k.getKeyMaterial(ctx, keyID, key.Version)
+	key, privateBytes, err := k.signingKey(ctx, keyID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Decrypt private key
-	privateBytes, err := k.rootCipher.Open(nil, material.Nonce, material.EncryptedPrivate, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decrypt private key: %w", err)
-	}
-
 	hash := sha256.Sum256(data)
 
 	switch key.Type {
@@ -555,11 +642,75 @@ func (k *KMS) Sign(ctx context.Context, keyID string, data []byte) ([]byte, erro
 	case KeyTypeEdDSA:
 		return ed25519.Sign(privateBytes, data), nil
 
+	case KeyTypeSecp256k1:
+		privateKey, err := secp256k1.ToPrivateKey(privateBytes)
+		if err != nil {
+			return nil, err
+		}
+		return privateKey.SignHash(hash[:])
+
 	default:
 		return nil, fmt.Errorf("unsupported key type for signing: %s", key.Type)
 	}
 }
 
+// SignDigest signs a digest the caller already hashed, such as the keccak256
+// hash of an EVM transaction, using an ECDSA or secp256k1 key.
+func (k *KMS) SignDigest(ctx context.Context, keyID string, digest []byte) (_ []byte, err error) {
+	defer func() { k.recordAudit(AuditOpSign, keyID, "", err) }()
+
+	key, privateBytes, err := k.signingKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch key.Type {
+	case KeyTypeECDSAP256, KeyTypeECDSAP384:
+		privateKey, err := x509.ParseECPrivateKey(privateBytes)
+		if err != nil {
+			return nil, err
+		}
+		return ecdsa.SignASN1(rand.Reader, privateKey, digest)
+
+	case KeyTypeSecp256k1:
+		if len(digest) != sha256.Size {
+			return nil, fmt.Errorf("invalid digest length %d, expected %d", len(digest), sha256.Size)
+		}
+		privateKey, err := secp256k1.ToPrivateKey(privateBytes)
+		if err != nil {
+			return nil, err
+		}
+		return privateKey.SignHash(digest)
+
+	default:
+		return nil, fmt.Errorf("key type %s cannot sign digests", key.Type)
+	}
+}
+
+// signingKey returns the key and the decrypted private key of a signing key
+func (k *KMS) signingKey(ctx context.Context, keyID string) (*Key, []byte, error) {
+	key, err := k.GetKey(ctx, keyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if key.Usage != KeyUsageSignVerify {
+		return nil, nil, fmt.Errorf("key %s cannot be used for signing", keyID)
+	}
+
+	material, err := k.getKeyMaterial(ctx, keyID, key.Version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Decrypt private key
+	privateBytes, err := k.rootCipher.Open(nil, material.Nonce, material.EncryptedPrivate, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt private key: %w", err)
+	}
+	return key, privateBytes, nil
+}
+
 // Verify verifies a signature.
 func (k *KMS) Verify(ctx context.Context, keyID string, data, signature []byte) (bool, error) {
 	key, err := k.GetKey(ctx, keyID)
@@ -595,6 +746,13 @@ func (k *KMS) Verify(ctx context.Context, keyID string, data, signature []byte)
 	case KeyTypeEdDSA:
 		return ed25519.Verify(material.PublicKey, data, signature), nil
 
+	case KeyTypeSecp256k1:
+		pub, err := secp256k1.ToPublicKey(material.PublicKey)
+		if err != nil {
+			return false, err
+		}
+		return pub.VerifyHash(hash[:], signature), nil
+
 	default:
 		return false, fmt.Errorf("unsupported key type for verification: %s", key.Type)
 	}
@@ -603,7 +761,7 @@ func (k *KMS) Verify(ctx context.Context, keyID string, data, signature []byte)
 // Secret Management
 
 // CreateSecret creates a new secret.
-func (k *KMS) CreateSecret(ctx context.Context, name string, value []byte, opts *SecretOptions) (*Secret, error) {
+func (k *KMS) CreateSecret(ctx context.Context, name string, value []byte, opts *SecretOptions) (_ *Secret, err error) {
 	k.mu.Lock()
 	defer k.mu.Unlock()
 
@@ -612,6 +770,7 @@ func (k *KMS) CreateSecret(ctx context.Context, name string, value []byte, opts
 
 	// Get or create encryption key
 	keyID := ""
+	defer func() { k.recordAudit(AuditOpSecretCreate, keyID, secretID, err) }()
 	if opts != nil && opts.KeyID != "" {
 		keyID = opts.KeyID
 	} else {
@@ -675,13 +834,93 @@ func (k *KMS) GetSecret(ctx context.Context, secretID string) (*Secret, error) {
 }
 
 // GetSecretValue retrieves and decrypts a secret value.
-func (k *KMS) GetSecretValue(ctx context.Context, secretID string) ([]byte, error) {
+func (k *KMS) GetSecretValue(ctx context.Context, secretID string) (_ []byte, err error) {
+	keyID := ""
+	defer func() { k.recordAudit(AuditOpSecretRead, keyID, secretID, err) }()
+
 	secret, err := k.GetSecret(ctx, secretID)
 	if err != nil {
 		return nil, err
 	}
+	keyID = secret.KeyID
 
-	return k.Decrypt(ctx, secret.Value)
+	value, err := k.Decrypt(ctx, secret.Value)
+	if err != nil {
+		return nil, err
+	}
+	// Move the secret to the current key version after a rotation. The read
+	// already succeeded, a failure is only recorded in the audit log.
+	if stale, _ := k.secretIsStale(ctx, secret); stale {
+		_, _ = k.reencryptSecret(ctx, secretID)
+	}
+	return value, nil
+}
+
+// ReencryptSecrets re-encrypts the secrets of keyID that are encrypted with an
+// older version of the key, returning how many were re-encrypted.
+func (k *KMS) ReencryptSecrets(ctx context.Context, keyID string) (int, error) {
+	secrets, err := k.ListSecrets(ctx, "", "")
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, secret := range secrets {
+		if secret.KeyID != keyID {
+			continue
+		}
+		done, err := k.reencryptSecret(ctx, secret.ID)
+		if err != nil {
+			return count, fmt.Errorf("failed to re-encrypt secret %s: %w", secret.ID, err)
+		}
+		if done {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// secretIsStale reports whether secret is encrypted with an older version of
+// its key.
+func (k *KMS) secretIsStale(ctx context.Context, secret *Secret) (bool, error) {
+	var encrypted EncryptedData
+	if err := json.Unmarshal(secret.Value, &encrypted); err != nil {
+		return false, fmt.Errorf("invalid ciphertext format: %w", err)
+	}
+	key, err := k.GetKey(ctx, secret.KeyID)
+	if err != nil {
+		return false, err
+	}
+	return encrypted.KeyVersion < key.Version, nil
+}
+
+// reencryptSecret re-encrypts the value of secretID with the current version
+// of its key, if it is stale. The secret version is unchanged as its value is.
+func (k *KMS) reencryptSecret(ctx context.Context, secretID string) (_ bool, err error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	secret, err := k.GetSecret(ctx, secretID)
+	if err != nil {
+		return false, err
+	}
+	stale, err := k.secretIsStale(ctx, secret)
+	if err != nil || !stale {
+		return false, err
+	}
+	defer func() { k.recordAudit(AuditOpSecretReencrypt, secret.KeyID, secretID, err) }()
+
+	value, err := k.Decrypt(ctx, secret.Value)
+	if err != nil {
+		return false, err
+	}
+	if secret.Value, err = k.Encrypt(ctx, secret.KeyID, value); err != nil {
+		return false, err
+	}
+	secret.Updated = time.Now()
+	if err := SetJSON(ctx, k.store, secretPrefix+secretID, secret); err != nil {
+		return false, fmt.Errorf("failed to save secret: %w", err)
+	}
+	return true, nil
 }
 
 // ListSecrets lists secrets, optionally filtered by environment or path.
@@ -701,9 +940,10 @@ func (k *KMS) ListSecrets(ctx context.Context, env, path string) ([]*Secret, err
 }
 
 // UpdateSecret updates a secret's value.
-func (k *KMS) UpdateSecret(ctx context.Context, secretID string, newValue []byte) (*Secret, error) {
+func (k *KMS) UpdateSecret(ctx context.Context, secretID string, newValue []byte) (_ *Secret, err error) {
 	k.mu.Lock()
 	defer k.mu.Unlock()
+	defer func() { k.recordAudit(AuditOpSecretUpdate, "", secretID, err) }()
 
 	secret, err := k.GetSecret(ctx, secretID)
 	if err != nil {
@@ -728,7 +968,8 @@ func (k *KMS) UpdateSecret(ctx context.Context, secretID string, newValue []byte
 }
 
 // DeleteSecret deletes a secret.
-func (k *KMS) DeleteSecret(ctx context.Context, secretID string) error {
+func (k *KMS) DeleteSecret(ctx context.Context, secretID string) (err error) {
+	defer func() { k.recordAudit(AuditOpSecretDelete, "", secretID, err) }()
 	return k.store.Delete(ctx, secretPrefix+secretID)
 }
 
@@ -0,0 +1,154 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package kms
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/luxfi/crypto/secp256k1"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKMS(t *testing.T) *KMS {
+	rootKey := make([]byte, 32)
+	_, err := rand.Read(rootKey)
+	require.NoError(t, err)
+	k, err := New(&Config{RootKey: rootKey, InMemory: true})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = k.Close() })
+	return k
+}
+
+// secretKeyVersion returns the key version the stored secret is encrypted with
+func secretKeyVersion(t *testing.T, k *KMS, secretID string) int {
+	secret, err := k.GetSecret(context.Background(), secretID)
+	require.NoError(t, err)
+	var encrypted EncryptedData
+	require.NoError(t, json.Unmarshal(secret.Value, &encrypted))
+	return encrypted.KeyVersion
+}
+
+func TestRotateKey(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	k := newTestKMS(t)
+
+	key, err := k.GenerateKey(ctx, "data", KeyTypeAES256, KeyUsageEncryptDecrypt, nil)
+	require.NoError(err)
+	old, err := k.Encrypt(ctx, key.ID, []byte("before"))
+	require.NoError(err)
+	lazy, err := k.CreateSecret(ctx, "lazy", []byte("lazy value"), &SecretOptions{KeyID: key.ID})
+	require.NoError(err)
+	batch, err := k.CreateSecret(ctx, "batch", []byte("batch value"), &SecretOptions{KeyID: key.ID})
+	require.NoError(err)
+
+	rotated, err := k.RotateKey(ctx, key.ID)
+	require.NoError(err)
+	require.Equal(2, rotated.Version)
+
+	// old ciphertexts still decrypt, new ones use the new version
+	plaintext, err := k.Decrypt(ctx, old)
+	require.NoError(err)
+	require.Equal("before", string(plaintext))
+	ciphertext, err := k.Encrypt(ctx, key.ID, []byte("after"))
+	require.NoError(err)
+	var encrypted EncryptedData
+	require.NoError(json.Unmarshal(ciphertext, &encrypted))
+	require.Equal(2, encrypted.KeyVersion)
+
+	// reading a secret re-encrypts it
+	value, err := k.GetSecretValue(ctx, lazy.ID)
+	require.NoError(err)
+	require.Equal("lazy value", string(value))
+	require.Equal(2, secretKeyVersion(t, k, lazy.ID))
+	require.Equal(1, secretKeyVersion(t, k, batch.ID))
+
+	// the batch re-encrypts the remaining stale secrets only
+	n, err := k.ReencryptSecrets(ctx, key.ID)
+	require.NoError(err)
+	require.Equal(1, n)
+	require.Equal(2, secretKeyVersion(t, k, batch.ID))
+	value, err = k.GetSecretValue(ctx, batch.ID)
+	require.NoError(err)
+	require.Equal("batch value", string(value))
+
+	events, err := k.Audit().Query(AuditFilter{Operation: AuditOpKeyRotate})
+	require.NoError(err)
+	require.Len(events, 1)
+	require.Equal(key.ID, events[0].KeyID)
+	require.True(events[0].Success)
+	events, err = k.Audit().Query(AuditFilter{Operation: AuditOpSecretReencrypt})
+	require.NoError(err)
+	require.Len(events, 2)
+}
+
+func TestRotateKeyRejected(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	k := newTestKMS(t)
+
+	signing, err := k.GenerateKey(ctx, "signing", KeyTypeEdDSA, KeyUsageSignVerify, nil)
+	require.NoError(err)
+	_, err = k.RotateKey(ctx, signing.ID)
+	require.ErrorContains(err, "only encrypt-decrypt keys can be rotated")
+
+	deleted, err := k.GenerateKey(ctx, "deleted", KeyTypeAES256, KeyUsageEncryptDecrypt, nil)
+	require.NoError(err)
+	require.NoError(k.DeleteKey(ctx, deleted.ID))
+	_, err = k.RotateKey(ctx, deleted.ID)
+	require.ErrorContains(err, "is not active")
+
+	_, err = k.RotateKey(ctx, "missing")
+	require.ErrorIs(err, ErrKeyNotFound)
+
+	events, err := k.Audit().Query(AuditFilter{Operation: AuditOpKeyRotate})
+	require.NoError(err)
+	require.Len(events, 3)
+	for _, event := range events {
+		require.False(event.Success)
+	}
+}
+
+func TestSecp256k1Sign(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	k := newTestKMS(t)
+
+	key, err := k.GenerateKey(ctx, "treasury", KeyTypeSecp256k1, KeyUsageSignVerify, nil)
+	require.NoError(err)
+	pemBytes, err := k.GetPublicKey(ctx, key.ID)
+	require.NoError(err)
+	block, _ := pem.Decode(pemBytes)
+	require.Equal("SECP256K1 PUBLIC KEY", block.Type)
+	pub, err := secp256k1.ToPublicKey(block.Bytes)
+	require.NoError(err)
+
+	// data is hashed with sha256, as the P-Chain and X-Chain do
+	sig, err := k.Sign(ctx, key.ID, []byte("unsigned tx"))
+	require.NoError(err)
+	require.Len(sig, secp256k1.SignatureLen)
+	valid, err := k.Verify(ctx, key.ID, []byte("unsigned tx"), sig)
+	require.NoError(err)
+	require.True(valid)
+
+	// a digest is signed as is, as EVM txs need
+	digest := sha256.Sum256([]byte("evm tx"))
+	sig, err = k.SignDigest(ctx, key.ID, digest[:])
+	require.NoError(err)
+	recovered, err := secp256k1.RecoverPublicKeyFromHash(digest[:], sig)
+	require.NoError(err)
+	require.Equal(pub.Address(), recovered.Address())
+
+	_, err = k.SignDigest(ctx, key.ID, []byte("short"))
+	require.ErrorContains(err, "invalid digest length")
+	ed, err := k.GenerateKey(ctx, "ed", KeyTypeEdDSA, KeyUsageSignVerify, nil)
+	require.NoError(err)
+	_, err = k.SignDigest(ctx, ed.ID, digest[:])
+	require.ErrorContains(err, "cannot sign digests")
+}
@@ -4,10 +4,14 @@
 package kms
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
+
+	"github.com/luxfi/crypto/secp256k1"
 )
 
 // MPC key types for threshold signing
@@ -45,11 +49,16 @@ type MPCWallet struct {
 	PublicKey      []byte              `json:"publicKey"`
 	ChainAddresses map[MPCChain]string `json:"chainAddresses"`
 	Status         KeyStatus           `json:"status"`
-	OrgID          string              `json:"orgId,omitempty"`
-	ProjectID      string              `json:"projectId,omitempty"`
-	Metadata       map[string]string   `json:"metadata,omitempty"`
-	Created        time.Time           `json:"created"`
-	Updated        time.Time           `json:"updated"`
+	// Presignatures is the number of presignatures dealt with the key, each
+	// of which signs a single request, and PresignaturesUsed those handed
+	// out. Zero when the key doesn't use presignatures.
+	Presignatures     int               `json:"presignatures,omitempty"`
+	PresignaturesUsed int               `json:"presignaturesUsed,omitempty"`
+	OrgID             string            `json:"orgId,omitempty"`
+	ProjectID         string            `json:"projectId,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+	Created           time.Time         `json:"created"`
+	Updated           time.Time         `json:"updated"`
 }
 
 // MPCNode represents a participant node in MPC operations.
@@ -73,6 +82,7 @@ type MPCSigningRequest struct {
 	Chain          MPCChain          `json:"chain"`
 	RawTransaction []byte            `json:"rawTransaction"`
 	Message        []byte            `json:"message,omitempty"` // For message signing
+	Presignature   int               `json:"presignature"`      // Presignature the parties sign with
 	Status         SigningStatus     `json:"status"`
 	Signatures     map[string][]byte `json:"signatures"` // nodeID -> partial signature
 	FinalSignature []byte            `json:"finalSignature,omitempty"`
@@ -106,6 +116,9 @@ const (
 type MPCManager struct {
 	kms   *KMS
 	store StorageBackend
+	// mu serializes updates of wallets and signing requests, so partial
+	// signatures aren't lost and no presignature is handed out twice
+	mu sync.Mutex
 }
 
 // NewMPCManager creates a new MPC manager.
@@ -273,15 +286,21 @@ func (m *MPCManager) ListWallets(ctx context.Context) ([]*MPCWallet, error) {
 	return wallets, err
 }
 
-// SetWalletPublicKey sets the public key after MPC key generation completes.
-func (m *MPCManager) SetWalletPublicKey(ctx context.Context, walletID string, publicKey []byte, chainAddresses map[MPCChain]string) error {
+// SetWalletPublicKey sets the public key after MPC key generation completes,
+// with the number of presignatures dealt with it, if any.
+func (m *MPCManager) SetWalletPublicKey(ctx context.Context, walletID string, publicKey []byte, chainAddresses map[MPCChain]string, presignatures int) error {
 	wallet, err := m.GetWallet(ctx, walletID)
 	if err != nil {
 		return err
 	}
 
+	if wallet.Status == KeyStatusActive {
+		return fmt.Errorf("wallet %s already has a public key", walletID)
+	}
+
 	wallet.PublicKey = publicKey
 	wallet.ChainAddresses = chainAddresses
+	wallet.Presignatures = presignatures
 	wallet.Status = KeyStatusActive
 	wallet.Updated = time.Now()
 
@@ -303,6 +322,9 @@ func (m *MPCManager) SetWalletPublicKey(ctx context.Context, walletID string, pu
 
 // CreateSigningRequest creates a new signing request.
 func (m *MPCManager) CreateSigningRequest(ctx context.Context, walletID string, chain MPCChain, rawTransaction []byte, opts *SigningOptions) (*MPCSigningRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	wallet, err := m.GetWallet(ctx, walletID)
 	if err != nil {
 		return nil, err
@@ -312,6 +334,21 @@ func (m *MPCManager) CreateSigningRequest(ctx context.Context, walletID string,
 		return nil, fmt.Errorf("wallet %s is not active", walletID)
 	}
 
+	// Hand out the next presignature, saved as used before any party signs
+	// with it
+	presignature := 0
+	if wallet.Presignatures > 0 {
+		if wallet.PresignaturesUsed >= wallet.Presignatures {
+			return nil, fmt.Errorf("wallet %s has used all its %d presignatures", walletID, wallet.Presignatures)
+		}
+		presignature = wallet.PresignaturesUsed
+		wallet.PresignaturesUsed++
+		wallet.Updated = time.Now()
+		if err := SetJSON(ctx, m.store, mpcWalletPrefix+walletID, wallet); err != nil {
+			return nil, fmt.Errorf("failed to save wallet: %w", err)
+		}
+	}
+
 	requestID := generateID(16)
 	now := time.Now()
 	expiresAt := now.Add(5 * time.Minute) // Default 5 minute expiry
@@ -325,6 +362,7 @@ func (m *MPCManager) CreateSigningRequest(ctx context.Context, walletID string,
 		WalletID:       walletID,
 		Chain:          chain,
 		RawTransaction: rawTransaction,
+		Presignature:   presignature,
 		Status:         SigningStatusPending,
 		Signatures:     make(map[string][]byte),
 		RequiredSigs:   wallet.Threshold,
@@ -359,6 +397,9 @@ func (m *MPCManager) GetSigningRequest(ctx context.Context, requestID string) (*
 
 // SubmitPartialSignature submits a partial signature from a node.
 func (m *MPCManager) SubmitPartialSignature(ctx context.Context, requestID, nodeID string, partialSig []byte) (*MPCSigningRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	request, err := m.GetSigningRequest(ctx, requestID)
 	if err != nil {
 		return nil, err
@@ -401,8 +442,8 @@ func (m *MPCManager) SubmitPartialSignature(ctx context.Context, requestID, node
 	request.Status = SigningStatusCollecting
 
 	if request.CollectedSigs >= request.RequiredSigs {
-		// In a real implementation, we would combine the partial signatures here
-		// For now, we just mark it as complete
+		// The requester combines the partial signatures and sets the final
+		// signature
 		request.Status = SigningStatusComplete
 	}
 
@@ -413,13 +454,32 @@ func (m *MPCManager) SubmitPartialSignature(ctx context.Context, requestID, node
 	return request, nil
 }
 
-// SetFinalSignature sets the combined final signature.
+// SetFinalSignature sets the combined final signature. Signatures of a
+// message digest must recover the wallet's public key.
 func (m *MPCManager) SetFinalSignature(ctx context.Context, requestID string, finalSig []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	request, err := m.GetSigningRequest(ctx, requestID)
 	if err != nil {
 		return err
 	}
 
+	if request.FinalSignature != nil {
+		return fmt.Errorf("signing request %s already has a final signature", requestID)
+	}
+
+	if len(request.Message) == 32 {
+		wallet, err := m.GetWallet(ctx, request.WalletID)
+		if err != nil {
+			return err
+		}
+		pub, err := secp256k1.RecoverPublicKeyFromHash(request.Message, finalSig)
+		if err != nil || !bytes.Equal(pub.Bytes(), wallet.PublicKey) {
+			return fmt.Errorf("final signature is not a signature of wallet %s", request.WalletID)
+		}
+	}
+
 	request.FinalSignature = finalSig
 	request.Status = SigningStatusComplete
 
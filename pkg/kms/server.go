@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -33,6 +34,12 @@ type ServerConfig struct {
 	APIKey         string // Simple API key authentication
 	EnableMPC      bool
 	EnableSecrets  bool
+
+	// TLS settings. When TLSCertFile and TLSKeyFile are set the server serves
+	// HTTPS; ClientCAFile additionally requires client certificates (mTLS).
+	TLSCertFile  string
+	TLSKeyFile   string
+	ClientCAFile string
 }
 
 // DefaultServerConfig returns default server configuration.
@@ -81,6 +88,9 @@ func NewServer(kms *KMS, cfg *ServerConfig) *Server {
 	mux.HandleFunc("/v1/sign", s.handleLegacySign)
 	mux.HandleFunc("/v1/verify", s.handleLegacyVerify)
 
+	// Audit log
+	mux.HandleFunc("/v1/audit", s.handleAudit)
+
 	// Secrets - v3 API compatible with kms-go SDK
 	if cfg.EnableSecrets {
 		mux.HandleFunc("/v3/secrets/raw", s.handleSecretsV3)
@@ -112,9 +122,27 @@ func NewServer(kms *KMS, cfg *ServerConfig) *Server {
 	return s
 }
 
-// Start starts the HTTP server.
+// Handler returns the handler of the API, for serving it in process.
+func (s *Server) Handler() http.Handler {
+	return s.server.Handler
+}
+
+// Start starts the HTTP server, or the HTTPS server if TLS is configured.
 func (s *Server) Start() error {
-	return s.server.ListenAndServe()
+	if !s.config.TLSEnabled() {
+		if s.config.ClientCAFile != "" {
+			return fmt.Errorf("client CA requires a TLS certificate and key")
+		}
+		return s.server.ListenAndServe()
+	}
+
+	tlsConfig, err := s.config.buildTLSConfig()
+	if err != nil {
+		return err
+	}
+	s.server.TLSConfig = tlsConfig
+	// Certificates are already loaded into TLSConfig
+	return s.server.ListenAndServeTLS("", "")
 }
 
 // Stop gracefully shuts down the server.
@@ -194,6 +222,46 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleAudit returns audit events filtered by the keyId, operation, since,
+// until (RFC3339) and limit query parameters.
+func (s *Server) handleAudit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	filter := AuditFilter{
+		KeyID:     q.Get("keyId"),
+		Operation: AuditOperation(q.Get("operation")),
+	}
+	for name, dst := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if v := q.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				s.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid %s: %v", name, err))
+				return
+			}
+			*dst = t
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			s.writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	events, err := s.kms.Audit().Query(filter)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.writeJSON(w, http.StatusOK, map[string]interface{}{"events": events})
+}
+
 // KmsKey matches kms-go SDK KmsKey struct
 type KmsKey struct {
 	ID                  string `json:"id"`
@@ -285,6 +353,8 @@ func (s *Server) handleKmsKeys(w http.ResponseWriter, r *http.Request) {
 			keyType = KeyTypeRSA4096
 		case "ecc-nist-p256":
 			keyType = KeyTypeECDSAP256
+		case "secp256k1":
+			keyType = KeyTypeSecp256k1
 		default:
 			keyType = KeyTypeAES256
 		}
@@ -365,6 +435,8 @@ func (s *Server) handleKmsKey(w http.ResponseWriter, r *http.Request) {
 		s.handleKeyPublicKey(w, r, keyID)
 	case "signing-algorithms":
 		s.handleKeySigningAlgorithms(w, r, keyID)
+	case "rotate":
+		s.handleKeyRotate(w, r, keyID)
 	case "":
 		// Direct key operations
 		switch r.Method {
@@ -438,6 +510,47 @@ func (s *Server) handleGetKeyByName(w http.ResponseWriter, r *http.Request, keyN
 	s.writeError(w, http.StatusNotFound, "key not found")
 }
 
+func (s *Server) handleKeyRotate(w http.ResponseWriter, r *http.Request, keyID string) {
+	if r.Method != "POST" {
+		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	ctx := r.Context()
+
+	// The body is optional, without it secrets are re-encrypted lazily
+	var req struct {
+		ReencryptNow bool `json:"reencryptNow"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	key, err := s.kms.RotateKey(ctx, keyID)
+	if err == ErrKeyNotFound {
+		s.writeError(w, http.StatusNotFound, "key not found")
+		return
+	}
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reencrypted := 0
+	if req.ReencryptNow {
+		if reencrypted, err = s.kms.ReencryptSecrets(ctx, keyID); err != nil {
+			s.writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	s.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"key":         keyToKmsKey(key),
+		"reencrypted": reencrypted,
+	})
+}
+
 func (s *Server) handleKeyEncrypt(w http.ResponseWriter, r *http.Request, keyID string) {
 	if r.Method != "POST" {
 		s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
@@ -536,7 +649,12 @@ func (s *Server) handleKeySign(w http.ResponseWriter, r *http.Request, keyID str
 		return
 	}
 
-	signature, err := s.kms.Sign(ctx, keyID, data)
+	var signature []byte
+	if req.IsDigest {
+		signature, err = s.kms.SignDigest(ctx, keyID, data)
+	} else {
+		signature, err = s.kms.Sign(ctx, keyID, data)
+	}
 	if err != nil {
 		s.writeError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -658,6 +776,8 @@ func (s *Server) handleKeySigningAlgorithms(w http.ResponseWriter, r *http.Reque
 		algorithms = []string{"ECDSA_SHA_384"}
 	case KeyTypeEdDSA:
 		algorithms = []string{"EDDSA"}
+	case KeyTypeSecp256k1:
+		algorithms = []string{"ECDSA_SECP256K1_SHA_256"}
 	default:
 		algorithms = []string{}
 	}
@@ -1172,6 +1292,44 @@ func (s *Server) handleMPCWallet(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	walletID := strings.TrimPrefix(r.URL.Path, "/v1/mpc/wallets/")
 
+	// Check for /activate suffix
+	if strings.HasSuffix(walletID, "/activate") {
+		if r.Method != "POST" {
+			s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		var req struct {
+			PublicKey      string              `json:"publicKey"` // Base64
+			ChainAddresses map[MPCChain]string `json:"chainAddresses,omitempty"`
+			Presignatures  int                 `json:"presignatures,omitempty"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		publicKey, err := DecodeBase64(req.PublicKey)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid public key encoding")
+			return
+		}
+
+		walletID = strings.TrimSuffix(walletID, "/activate")
+		if err := s.mpc.SetWalletPublicKey(ctx, walletID, publicKey, req.ChainAddresses, req.Presignatures); err != nil {
+			s.writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		wallet, err := s.mpc.GetWallet(ctx, walletID)
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		s.writeJSON(w, http.StatusOK, map[string]interface{}{"wallet": wallet})
+		return
+	}
+
 	switch r.Method {
 	case "GET":
 		wallet, err := s.mpc.GetWallet(ctx, walletID)
@@ -1273,6 +1431,42 @@ func (s *Server) handleMPCSigning(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Check for /final suffix
+	if strings.HasSuffix(path, "/final") {
+		if r.Method != "POST" {
+			s.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		var req struct {
+			Signature string `json:"signature"` // Base64
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		sig, err := DecodeBase64(req.Signature)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid signature encoding")
+			return
+		}
+
+		requestID := strings.TrimSuffix(path, "/final")
+		if err := s.mpc.SetFinalSignature(ctx, requestID, sig); err != nil {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		sigReq, err := s.mpc.GetSigningRequest(ctx, requestID)
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		s.writeJSON(w, http.StatusOK, map[string]interface{}{"signingRequest": sigReq})
+		return
+	}
+
 	requestID := path
 
 	switch r.Method {
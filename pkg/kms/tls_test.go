@@ -0,0 +1,54 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package kms
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsureLocalhostCert(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	certPath, keyPath, err := EnsureLocalhostCert(dir)
+	require.NoError(err)
+
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	require.NoError(err)
+	require.NoError(cert.Leaf.VerifyHostname("localhost"))
+	require.NoError(cert.Leaf.VerifyHostname("127.0.0.1"))
+
+	// A second call reuses the existing certificate
+	certPath2, keyPath2, err := EnsureLocalhostCert(dir)
+	require.NoError(err)
+	cert2, err := tls.LoadX509KeyPair(certPath2, keyPath2)
+	require.NoError(err)
+	require.Equal(cert.Leaf.SerialNumber, cert2.Leaf.SerialNumber)
+}
+
+func TestBuildTLSConfigWithClientCA(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	certPath, keyPath, err := EnsureLocalhostCert(dir)
+	require.NoError(err)
+
+	cfg := &ServerConfig{TLSCertFile: certPath, TLSKeyFile: keyPath}
+	require.True(cfg.TLSEnabled())
+	tlsConfig, err := cfg.buildTLSConfig()
+	require.NoError(err)
+	require.Equal(tls.NoClientCert, tlsConfig.ClientAuth)
+
+	cfg.ClientCAFile = certPath
+	tlsConfig, err = cfg.buildTLSConfig()
+	require.NoError(err)
+	require.Equal(tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth)
+
+	cfg.ClientCAFile = keyPath
+	_, err = cfg.buildTLSConfig()
+	require.Error(err)
+}
@@ -0,0 +1,291 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package launchcheck evaluates whether a chain is ready to launch on a
+// public network: the checks operators otherwise go through by hand before
+// a testnet or mainnet deploy.
+package launchcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/vm"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/evm/commontype"
+)
+
+// EwoqAddress is the EVM address of the well-known local development key,
+// whose private key is public
+const EwoqAddress = "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"
+
+const (
+	// MinMainnetValidators is the smallest validator set launched on mainnet
+	MinMainnetValidators = 5
+	// MinMainnetRegions is the number of regions mainnet validators spread
+	// over, so a regional outage doesn't halt the chain
+	MinMainnetRegions = 2
+)
+
+// KnownChainIDs are EVM chain IDs already used by well-known networks and
+// development tools
+var KnownChainIDs = map[uint64]string{
+	1:                                "Ethereum",
+	uint64(constants.MainnetChainID): "the Lux C-Chain",
+	uint64(constants.TestnetChainID): "the Lux testnet C-Chain",
+	uint64(constants.DevnetChainID):  "the Lux devnet C-Chain",
+	uint64(constants.LocalChainID):   "local development networks",
+	1337:                             "local development networks",
+	43114:                            "the Avalanche C-Chain",
+	43113:                            "the Avalanche Fuji C-Chain",
+}
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	Pass Status = "pass"
+	// Warn is a failed check that does not block the deploy
+	Warn Status = "warn"
+	Fail Status = "fail"
+	// Skip is a check that does not apply, or has nothing to check yet
+	Skip Status = "skip"
+)
+
+// Result is the outcome of one check
+type Result struct {
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	Detail string `json:"detail"`
+	// Fix tells how to pass a failed check
+	Fix string `json:"fix,omitempty"`
+}
+
+// Validator is a node planned to validate the chain
+type Validator struct {
+	NodeID string
+	Region string
+}
+
+// Plan is what is known of a launch before the deploy
+type Plan struct {
+	Chain string
+	// Mainnet makes the checks meant for mainnet launches blocking; on
+	// other networks they only warn
+	Mainnet bool
+	// Genesis is the EVM genesis of the chain, nil for other VMs
+	Genesis []byte
+	// ChainIDs maps the EVM chain IDs of the other local chains to their
+	// name
+	ChainIDs map[uint64]string
+	// ControlKeys and Threshold will own the chain, when known
+	ControlKeys []string
+	Threshold   uint32
+	// Validators are the nodes of the clusters tracking the chain
+	Validators []Validator
+	// Monitored tells whether a monitoring instance watches the validators
+	Monitored bool
+}
+
+// evmGenesis is the part of an EVM genesis the checks read
+type evmGenesis struct {
+	Config struct {
+		ChainID    *big.Int              `json:"chainId"`
+		FeeConfig  *commontype.FeeConfig `json:"feeConfig"`
+		WarpConfig json.RawMessage       `json:"warpConfig"`
+	} `json:"config"`
+	Alloc map[string]json.RawMessage `json:"alloc"`
+}
+
+// Evaluate runs every check against p
+func Evaluate(p Plan) ([]Result, error) {
+	var genesis *evmGenesis
+	if p.Genesis != nil {
+		genesis = &evmGenesis{}
+		if err := json.Unmarshal(p.Genesis, genesis); err != nil {
+			return nil, fmt.Errorf("invalid genesis of %s: %w", p.Chain, err)
+		}
+	}
+	return []Result{
+		checkAllocations(p, genesis),
+		checkChainID(p, genesis),
+		checkControlKeys(p),
+		checkValidators(p),
+		checkWarp(p, genesis),
+		checkMonitoring(p),
+		checkFeeConfig(genesis),
+	}, nil
+}
+
+// Blocking returns the failed results
+func Blocking(results []Result) []Result {
+	var failed []Result
+	for _, r := range results {
+		if r.Status == Fail {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// mainnetOnly fails r on mainnet and only warns elsewhere
+func mainnetOnly(p Plan, r Result) Result {
+	if !p.Mainnet {
+		r.Status = Warn
+	}
+	return r
+}
+
+func checkAllocations(p Plan, genesis *evmGenesis) Result {
+	r := Result{Name: "non-ewoq allocations"}
+	if genesis == nil {
+		r.Status, r.Detail = Skip, "not an EVM chain"
+		return r
+	}
+	ewoq := strings.ToLower(strings.TrimPrefix(EwoqAddress, "0x"))
+	for addr := range genesis.Alloc {
+		if strings.ToLower(strings.TrimPrefix(addr, "0x")) == ewoq {
+			r.Status = Fail
+			r.Detail = "the genesis funds the ewoq key, whose private key is public"
+			r.Fix = "remove " + EwoqAddress + " from the genesis alloc"
+			return r
+		}
+	}
+	if strings.Contains(strings.ToLower(string(p.Genesis)), ewoq) {
+		r.Status = Fail
+		r.Detail = "the genesis makes the ewoq key, whose private key is public, a precompile admin"
+		r.Fix = "replace " + EwoqAddress + " in the precompile configs"
+		return r
+	}
+	r.Status, r.Detail = Pass, fmt.Sprintf("%d allocations, none to the ewoq key", len(genesis.Alloc))
+	return r
+}
+
+func checkChainID(p Plan, genesis *evmGenesis) Result {
+	r := Result{Name: "unique chain ID"}
+	if genesis == nil {
+		r.Status, r.Detail = Skip, "not an EVM chain"
+		return r
+	}
+	if genesis.Config.ChainID == nil || !genesis.Config.ChainID.IsUint64() {
+		r.Status, r.Detail = Fail, "the genesis has no valid EVM chain ID"
+		r.Fix = "set config.chainId in the genesis"
+		return r
+	}
+	chainID := genesis.Config.ChainID.Uint64()
+	if name, ok := KnownChainIDs[chainID]; ok {
+		r.Status, r.Detail = Fail, fmt.Sprintf("chain ID %d is used by %s, wallets would confuse the chains", chainID, name)
+		r.Fix = "pick an unused chain ID, see chainlist.org"
+		return r
+	}
+	if name, ok := p.ChainIDs[chainID]; ok {
+		r.Status, r.Detail = Fail, fmt.Sprintf("chain ID %d is also used by chain %s", chainID, name)
+		r.Fix = "give one of the chains another chain ID"
+		return r
+	}
+	r.Status, r.Detail = Pass, fmt.Sprintf("chain ID %d", chainID)
+	return r
+}
+
+func checkControlKeys(p Plan) Result {
+	r := Result{Name: "control key threshold"}
+	if len(p.ControlKeys) == 0 {
+		r.Status, r.Detail = Skip, "the control keys are those of the deploy key, checked on deploy"
+		return r
+	}
+	if p.Threshold > 1 {
+		r.Status, r.Detail = Pass, fmt.Sprintf("%d of %d control keys", p.Threshold, len(p.ControlKeys))
+		return r
+	}
+	return mainnetOnly(p, Result{
+		Name:   r.Name,
+		Status: Fail,
+		Detail: fmt.Sprintf("a single key (%s) controls the chain", strings.Join(p.ControlKeys, ", ")),
+		Fix:    "deploy with a multisig of control keys and a threshold of 2 or more",
+	})
+}
+
+func checkValidators(p Plan) Result {
+	r := Result{Name: "validator count and regions"}
+	regions := map[string]bool{}
+	for _, v := range p.Validators {
+		if v.Region != "" {
+			regions[v.Region] = true
+		}
+	}
+	names := make([]string, 0, len(regions))
+	for region := range regions {
+		names = append(names, region)
+	}
+	sort.Strings(names)
+	r.Detail = fmt.Sprintf("%d validators in %d regions", len(p.Validators), len(regions))
+	if len(names) > 0 {
+		r.Detail += " (" + strings.Join(names, ", ") + ")"
+	}
+	switch {
+	case len(p.Validators) < MinMainnetValidators:
+		r.Status = Fail
+		r.Fix = fmt.Sprintf("run at least %d validators for the chain, see 'lux node'", MinMainnetValidators)
+	case len(regions) < MinMainnetRegions:
+		r.Status = Fail
+		r.Fix = fmt.Sprintf("spread the validators over at least %d regions", MinMainnetRegions)
+	default:
+		r.Status = Pass
+		return r
+	}
+	return mainnetOnly(p, r)
+}
+
+func checkWarp(p Plan, genesis *evmGenesis) Result {
+	r := Result{Name: "warp enabled"}
+	if genesis == nil {
+		r.Status, r.Detail = Skip, "not an EVM chain"
+		return r
+	}
+	if len(genesis.Config.WarpConfig) == 0 || string(genesis.Config.WarpConfig) == "null" {
+		return mainnetOnly(p, Result{
+			Name:   r.Name,
+			Status: Fail,
+			Detail: "the genesis does not activate the Warp precompile, so no messenger can be deployed",
+			Fix:    "add a warpConfig to the genesis config",
+		})
+	}
+	r.Status, r.Detail = Pass, "the Warp precompile is active at genesis, its messenger is deployed on deploy"
+	return r
+}
+
+func checkMonitoring(p Plan) Result {
+	r := Result{Name: "monitoring"}
+	if p.Monitored {
+		r.Status, r.Detail = Pass, "a monitoring instance watches the validators"
+		return r
+	}
+	r.Status, r.Detail = Fail, "no monitoring is set up for the validators"
+	r.Fix = "set up monitoring for the cluster of the validators"
+	return mainnetOnly(p, r)
+}
+
+func checkFeeConfig(genesis *evmGenesis) Result {
+	r := Result{Name: "fee config"}
+	if genesis == nil {
+		r.Status, r.Detail = Skip, "not an EVM chain"
+		return r
+	}
+	if genesis.Config.FeeConfig == nil {
+		r.Status, r.Detail = Fail, "the genesis has no fee config"
+		r.Fix = "set config.feeConfig in the genesis"
+		return r
+	}
+	if err := vm.CheckFeeConfig(genesis.Config.FeeConfig); err != nil {
+		r.Status, r.Detail = Fail, err.Error()
+		r.Fix = "fix config.feeConfig in the genesis, see 'lux chain genesis wizard'"
+		return r
+	}
+	r.Status = Pass
+	r.Detail = fmt.Sprintf("gas limit %s, target gas %s, min base fee %s",
+		genesis.Config.FeeConfig.GasLimit, genesis.Config.FeeConfig.TargetGas, genesis.Config.FeeConfig.MinBaseFee)
+	return r
+}
@@ -0,0 +1,140 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package launchcheck
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testGenesis = `{
+	"config": {
+		"chainId": 200200,
+		"feeConfig": {
+			"gasLimit": 8000000,
+			"targetBlockRate": 2,
+			"minBaseFee": 25000000000,
+			"targetGas": 15000000,
+			"baseFeeChangeDenominator": 36,
+			"minBlockGasCost": 0,
+			"maxBlockGasCost": 1000000,
+			"blockGasCostStep": 200000
+		},
+		"warpConfig": {"blockTimestamp": 0}
+	},
+	"alloc": {"0x1111111111111111111111111111111111111111": {"balance": "0x1"}}
+}`
+
+func testPlan() Plan {
+	return Plan{
+		Chain:       "mychain",
+		Mainnet:     true,
+		Genesis:     []byte(testGenesis),
+		ControlKeys: []string{"P-lux1a", "P-lux1b"},
+		Threshold:   2,
+		Validators: []Validator{
+			{NodeID: "i-1", Region: "us-east-1"},
+			{NodeID: "i-2", Region: "us-east-1"},
+			{NodeID: "i-3", Region: "eu-west-1"},
+			{NodeID: "i-4", Region: "eu-west-1"},
+			{NodeID: "i-5", Region: "ap-south-1"},
+		},
+		Monitored: true,
+	}
+}
+
+func statuses(t *testing.T, p Plan) map[string]Status {
+	results, err := Evaluate(p)
+	require.NoError(t, err)
+	got := map[string]Status{}
+	for _, r := range results {
+		got[r.Name] = r.Status
+	}
+	return got
+}
+
+func TestEvaluateReady(t *testing.T) {
+	results, err := Evaluate(testPlan())
+	require.NoError(t, err)
+	require.Len(t, results, 7)
+	for _, r := range results {
+		require.Equal(t, Pass, r.Status, r.Name)
+	}
+	require.Empty(t, Blocking(results))
+}
+
+func TestEvaluateFailures(t *testing.T) {
+	require := require.New(t)
+
+	p := testPlan()
+	p.Genesis = []byte(strings.Replace(testGenesis, "0x1111111111111111111111111111111111111111", EwoqAddress, 1))
+	require.Equal(Fail, statuses(t, p)["non-ewoq allocations"])
+
+	p = testPlan()
+	p.Genesis = []byte(strings.Replace(testGenesis, "200200", "96369", 1))
+	require.Equal(Fail, statuses(t, p)["unique chain ID"])
+
+	p = testPlan()
+	p.ChainIDs = map[uint64]string{200200: "otherchain"}
+	require.Equal(Fail, statuses(t, p)["unique chain ID"])
+
+	p = testPlan()
+	p.ControlKeys, p.Threshold = []string{"P-lux1a"}, 1
+	require.Equal(Fail, statuses(t, p)["control key threshold"])
+	p.ControlKeys = nil
+	require.Equal(Skip, statuses(t, p)["control key threshold"])
+
+	p = testPlan()
+	p.Validators = p.Validators[:4]
+	require.Equal(Fail, statuses(t, p)["validator count and regions"])
+	p = testPlan()
+	for i := range p.Validators {
+		p.Validators[i].Region = "us-east-1"
+	}
+	require.Equal(Fail, statuses(t, p)["validator count and regions"])
+
+	p = testPlan()
+	p.Genesis = []byte(strings.Replace(testGenesis, `"warpConfig": {"blockTimestamp": 0}`, `"warpConfig": null`, 1))
+	require.Equal(Fail, statuses(t, p)["warp enabled"])
+
+	p = testPlan()
+	p.Monitored = false
+	require.Equal(Fail, statuses(t, p)["monitoring"])
+
+	p = testPlan()
+	p.Genesis = []byte(strings.Replace(testGenesis, `"baseFeeChangeDenominator": 36`, `"baseFeeChangeDenominator": 0`, 1))
+	require.Equal(Fail, statuses(t, p)["fee config"])
+}
+
+func TestEvaluateTestnetWarns(t *testing.T) {
+	require := require.New(t)
+	p := testPlan()
+	p.Mainnet = false
+	p.ControlKeys, p.Threshold = []string{"P-lux1a"}, 1
+	p.Validators = nil
+	p.Monitored = false
+	p.Genesis = []byte(strings.Replace(testGenesis, "0x1111111111111111111111111111111111111111", EwoqAddress, 1))
+
+	got := statuses(t, p)
+	require.Equal(Warn, got["control key threshold"])
+	require.Equal(Warn, got["validator count and regions"])
+	require.Equal(Warn, got["monitoring"])
+	// Funding the ewoq key blocks every public launch
+	require.Equal(Fail, got["non-ewoq allocations"])
+
+	results, err := Evaluate(p)
+	require.NoError(err)
+	require.Len(Blocking(results), 1)
+}
+
+func TestEvaluateNonEVM(t *testing.T) {
+	p := testPlan()
+	p.Genesis = nil
+	got := statuses(t, p)
+	require.Equal(t, Skip, got["non-ewoq allocations"])
+	require.Equal(t, Skip, got["fee config"])
+	require.Equal(t, Pass, got["validator count and regions"])
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/luxfi/cli/pkg/application"
 	"github.com/luxfi/cli/pkg/binutils"
+	"github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/constants"
 	"github.com/luxfi/netrunner/client"
 	"github.com/luxfi/netrunner/server"
@@ -778,7 +779,7 @@ func getPortBaseForNetwork(netType string) int {
 
 // checkEndpointHealth checks if an HTTP endpoint is reachable
 func checkEndpointHealth(endpoint string) bool {
-	client := &http.Client{Timeout: 2 * time.Second}
+	client := utils.DefaultRPCClient().WithTimeout(2 * time.Second).WithPolicy(utils.ProbeRetryPolicy())
 	resp, err := client.Get(endpoint + "/ext/health")
 	if err != nil {
 		return false
@@ -7,7 +7,11 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/luxfi/cli/pkg/application"
 	"github.com/luxfi/cli/pkg/utils"
@@ -26,6 +30,81 @@ var (
 	sent              = false
 )
 
+const (
+	// EnvVar overrides the metrics preference of the config, for one command
+	// or a whole environment
+	EnvVar = "LUX_METRICS"
+	// DisabledCommandsKey is the config key of the commands never tracked,
+	// not even locally
+	DisabledCommandsKey = "metrics-disabled-commands"
+	// legacyEnabledKey is the key the first run prompt stores the preference
+	// under
+	legacyEnabledKey = "metricsEnabled"
+)
+
+// Consent is the metrics preference of the user
+type Consent string
+
+const (
+	// Unset users have events recorded locally only, until they opt in
+	Unset Consent = "unset"
+	// Enabled users have events recorded locally and sent
+	Enabled Consent = "enabled"
+	// Disabled users have no event recorded nor sent
+	Disabled Consent = "disabled"
+)
+
+// GetConsent returns the metrics preference of the user, and where it is set
+func GetConsent(app *application.Lux) (Consent, string) {
+	if v, err := strconv.ParseBool(os.Getenv(EnvVar)); err == nil {
+		return consentOf(v), "$" + EnvVar
+	}
+	if app.Conf == nil || !app.Conf.ConfigFileExists() {
+		return Unset, "default"
+	}
+	for _, key := range []string{constants.ConfigMetricsEnabledKey, legacyEnabledKey} {
+		if app.Conf.ConfigValueIsSet(key) {
+			return consentOf(app.Conf.GetConfigBoolValue(key)), app.Conf.GetConfigPath()
+		}
+	}
+	return Unset, "default"
+}
+
+func consentOf(enabled bool) Consent {
+	if enabled {
+		return Enabled
+	}
+	return Disabled
+}
+
+// SetEnabled stores in the config whether events are sent
+func SetEnabled(app *application.Lux, enabled bool) error {
+	return app.Conf.SetConfigValue(constants.ConfigMetricsEnabledKey, enabled)
+}
+
+// DisabledCommands returns the commands the user opted out of tracking
+func DisabledCommands(app *application.Lux) []string {
+	if app.Conf == nil {
+		return nil
+	}
+	return app.Conf.GetConfigStringSliceValue(DisabledCommandsKey)
+}
+
+// SetCommandDisabled opts command, and its subcommands, out of tracking, or
+// back in
+func SetCommandDisabled(app *application.Lux, command string, disabled bool) error {
+	name := strings.Join(commandFields(command), " ")
+	if name == "" {
+		return fmt.Errorf("invalid command %q", command)
+	}
+	commands := slices.DeleteFunc(DisabledCommands(app), func(c string) bool { return c == name })
+	if disabled {
+		commands = append(commands, name)
+		sort.Strings(commands)
+	}
+	return app.Conf.SetConfigValue(DisabledCommandsKey, commands)
+}
+
 func getMetricsUserID(app *application.Lux) string {
 	if !app.Conf.ConfigFileExists() || !app.Conf.ConfigValueIsSet(constants.ConfigMetricsUserIDKey) {
 		userID := utils.RandomString(20)
@@ -37,14 +116,9 @@ func getMetricsUserID(app *application.Lux) string {
 	return app.Conf.GetConfigStringValue(constants.ConfigMetricsUserIDKey)
 }
 
-func notInitialized(app *application.Lux) bool {
-	return !app.Conf.ConfigFileExists() || !app.Conf.ConfigValueIsSet(constants.ConfigMetricsEnabledKey)
-}
-
-func userIsOptedIn(app *application.Lux) bool {
-	return app.Conf.ConfigFileExists() && app.Conf.GetConfigBoolValue(constants.ConfigMetricsEnabledKey)
-}
-
+// HandleTracking records the run of the command of app in the local store,
+// and sends it when the user opted in. Users who opted out, globally or for
+// the command, have nothing recorded.
 func HandleTracking(
 	app *application.Lux,
 	flags map[string]string,
@@ -59,19 +133,20 @@ func HandleTracking(
 		// command called with no arguments at all
 		return
 	}
-	if notInitialized(app) {
-		if err := app.Conf.SetConfigValue(constants.ConfigMetricsEnabledKey, true); err != nil {
-			ux.Logger.PrintToUser(luxlog.Red.Wrap("failure initializing metrics default: %s"), err)
-		}
-		_ = getMetricsUserID(app)
+	cmd, ok := app.Cmd.(*cobra.Command)
+	if !ok || cmd.HasSubCommands() || !CheckCommandIsNotCompletion(cmd.CommandPath()) {
+		return
 	}
-	if !userIsOptedIn(app) {
+	consent, _ := GetConsent(app)
+	if consent == Disabled || CommandDisabled(cmd.CommandPath(), DisabledCommands(app)) {
 		return
 	}
-	if cmd, ok := app.Cmd.(*cobra.Command); ok {
-		if !cmd.HasSubCommands() && CheckCommandIsNotCompletion(cmd.CommandPath()) {
-			trackMetrics(app, flags, err)
-		}
+	event := newEvent(app, cmd.CommandPath(), flags, err)
+	if consent == Enabled {
+		event.Sent = trackMetrics(app, event)
+	}
+	if err := NewStore(app.GetBaseDir()).Record(event); err != nil {
+		app.Log.Warn(fmt.Sprintf("failure recording metrics locally: %s", err))
 	}
 }
 
@@ -83,7 +158,27 @@ func CheckCommandIsNotCompletion(commandPath string) bool {
 	return true
 }
 
-func trackMetrics(app *application.Lux, flags map[string]string, cmdErr error) {
+func newEvent(app *application.Lux, commandPath string, flags map[string]string, cmdErr error) Event {
+	event := Event{
+		Time:        time.Now().UTC(),
+		Command:     commandPath,
+		Version:     app.GetVersion(),
+		OS:          runtime.GOOS,
+		Environment: "local",
+		Successful:  cmdErr == nil,
+		Flags:       flags,
+	}
+	if cmdErr != nil {
+		event.Error = cmdErr.Error()
+	}
+	if utils.InsideCodespace() {
+		event.Environment = "codespace"
+	}
+	return event
+}
+
+// trackMetrics sends event, telling if it was
+func trackMetrics(app *application.Lux, event Event) bool {
 	if telemetryToken == "" {
 		telemetryToken = os.Getenv(constants.MetricsAPITokenEnvVarName)
 	}
@@ -91,47 +186,29 @@ func trackMetrics(app *application.Lux, flags map[string]string, cmdErr error) {
 		app.Log.Warn("no token is configured for sending metrics")
 	}
 	if telemetryToken == "" || utils.IsE2E() {
-		return
+		return false
 	}
 	client, err := insights.NewWithConfig(telemetryToken, insights.Config{Endpoint: telemetryInstance})
 	if err != nil {
 		app.Log.Warn(fmt.Sprintf("failure creating metrics client: %s", err))
+		return false
 	}
 
-	version := app.GetVersion()
-
-	userID := getMetricsUserID(app)
-
-	telemetryProperties := make(map[string]interface{})
-	commandPath := ""
-	if cmd, ok := app.Cmd.(*cobra.Command); ok {
-		commandPath = cmd.CommandPath()
-	}
-	telemetryProperties["command"] = commandPath
-	telemetryProperties["cli_version"] = version
-	telemetryProperties["os"] = runtime.GOOS
-	telemetryProperties["was_successful"] = true
-	telemetryProperties["error_msg"] = ""
-	if cmdErr != nil {
-		telemetryProperties["was_successful"] = false
-		telemetryProperties["error_msg"] = cmdErr.Error()
-	}
-	telemetryProperties["environment"] = "local"
-	if utils.InsideCodespace() {
-		telemetryProperties["environment"] = "codespace"
-	}
-	for propertyKey, propertyValue := range flags {
-		telemetryProperties[propertyKey] = propertyValue
-	}
-	event := insights.Capture{
-		DistinctId: userID,
+	telemetryProperties := event.Properties()
+	capture := insights.Capture{
+		DistinctId: getMetricsUserID(app),
 		Event:      "cli-command",
+		Timestamp:  event.Time,
 		Properties: telemetryProperties,
 	}
-	if err := client.Enqueue(event); err != nil {
+	ok := true
+	if err := client.Enqueue(capture); err != nil {
 		app.Log.Warn(fmt.Sprintf("failure sending metrics %#v: %s", telemetryProperties, err))
+		ok = false
 	}
 	if err := client.Close(); err != nil {
 		app.Log.Warn(fmt.Sprintf("failure closing metrics client %#v: %s", telemetryProperties, err))
+		ok = false
 	}
+	return ok
 }
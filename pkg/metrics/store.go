@@ -0,0 +1,210 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metrics
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// StoreDir is the directory of the CLI base dir holding the local store
+	StoreDir  = "metrics"
+	storeFile = "events.jsonl"
+	// maxEvents bounds the local store, dropping the oldest events
+	maxEvents = 1000
+)
+
+// Event is a command run, as recorded locally and, when the user opted in,
+// sent
+type Event struct {
+	Time        time.Time         `json:"time"`
+	Command     string            `json:"command"`
+	Version     string            `json:"cliVersion"`
+	OS          string            `json:"os"`
+	Environment string            `json:"environment"`
+	Successful  bool              `json:"successful"`
+	Error       string            `json:"error,omitempty"`
+	Flags       map[string]string `json:"flags,omitempty"`
+	// Sent tells if the event was sent, not only recorded
+	Sent bool `json:"sent"`
+}
+
+// Properties returns the properties the event is sent with
+func (e Event) Properties() map[string]interface{} {
+	properties := map[string]interface{}{
+		"command":        e.Command,
+		"cli_version":    e.Version,
+		"os":             e.OS,
+		"environment":    e.Environment,
+		"was_successful": e.Successful,
+		"error_msg":      e.Error,
+	}
+	for k, v := range e.Flags {
+		properties[k] = v
+	}
+	return properties
+}
+
+// Store keeps the events of the CLI on disk, so users see what is collected
+// before anything is sent
+type Store struct {
+	path string
+}
+
+// NewStore returns the store of the CLI base dir baseDir
+func NewStore(baseDir string) *Store {
+	return &Store{path: filepath.Join(baseDir, StoreDir, storeFile)}
+}
+
+// Path returns the file of the store
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Record appends e to the store
+func (s *Store) Record(e Event) error {
+	events, err := s.Events()
+	if err != nil {
+		return err
+	}
+	if len(events) >= maxEvents {
+		return s.write(append(events[len(events)-maxEvents+1:], e))
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Events returns the events of the store, oldest first. Lines that don't
+// parse are skipped.
+func (s *Store) Events() ([]Event, error) {
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err == nil {
+			events = append(events, e)
+		}
+	}
+	return events, scanner.Err()
+}
+
+// Clear deletes the events of the store
+func (s *Store) Clear() error {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *Store) write(events []Event) error {
+	var b strings.Builder
+	for _, e := range events {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Summary is what the store holds about a command
+type Summary struct {
+	Command  string
+	Runs     int
+	Failures int
+	Sent     int
+	Last     time.Time
+}
+
+// Summarize returns the summary of each command of events, most run first
+func Summarize(events []Event) []Summary {
+	byCommand := map[string]*Summary{}
+	for _, e := range events {
+		s, ok := byCommand[e.Command]
+		if !ok {
+			s = &Summary{Command: e.Command}
+			byCommand[e.Command] = s
+		}
+		s.Runs++
+		if !e.Successful {
+			s.Failures++
+		}
+		if e.Sent {
+			s.Sent++
+		}
+		if e.Time.After(s.Last) {
+			s.Last = e.Time
+		}
+	}
+	summaries := make([]Summary, 0, len(byCommand))
+	for _, s := range byCommand {
+		summaries = append(summaries, *s)
+	}
+	sort.Slice(summaries, func(a, b int) bool {
+		if summaries[a].Runs != summaries[b].Runs {
+			return summaries[a].Runs > summaries[b].Runs
+		}
+		return summaries[a].Command < summaries[b].Command
+	})
+	return summaries
+}
+
+// CommandDisabled tells if commandPath is one of the commands of disabled,
+// or one of their subcommands. The root command name is optional in both.
+func CommandDisabled(commandPath string, disabled []string) bool {
+	command := commandFields(commandPath)
+	for _, d := range disabled {
+		prefix := commandFields(d)
+		if len(prefix) > 0 && len(prefix) <= len(command) && slices.Equal(prefix, command[:len(prefix)]) {
+			return true
+		}
+	}
+	return false
+}
+
+func commandFields(commandPath string) []string {
+	fields := strings.Fields(commandPath)
+	if len(fields) > 0 && fields[0] == "lux" {
+		fields = fields[1:]
+	}
+	return fields
+}
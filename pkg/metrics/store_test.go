@@ -0,0 +1,65 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metrics
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	require := require.New(t)
+	store := NewStore(t.TempDir())
+
+	events, err := store.Events()
+	require.NoError(err)
+	require.Empty(events)
+
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(store.Record(Event{Time: start, Command: "lux key list", Successful: true}))
+	require.NoError(store.Record(Event{Time: start.Add(time.Minute), Command: "lux chain deploy", Error: "boom", Sent: true}))
+	require.NoError(store.Record(Event{Time: start.Add(2 * time.Minute), Command: "lux key list", Successful: true}))
+	info, err := os.Stat(store.Path())
+	require.NoError(err)
+	require.Equal(os.FileMode(0o600), info.Mode().Perm())
+
+	events, err = store.Events()
+	require.NoError(err)
+	require.Len(events, 3)
+	require.Equal("boom", events[1].Properties()["error_msg"])
+	require.Equal([]Summary{
+		{Command: "lux key list", Runs: 2, Last: start.Add(2 * time.Minute)},
+		{Command: "lux chain deploy", Runs: 1, Failures: 1, Sent: 1, Last: start.Add(time.Minute)},
+	}, Summarize(events))
+
+	require.NoError(store.Clear())
+	events, err = store.Events()
+	require.NoError(err)
+	require.Empty(events)
+}
+
+func TestStoreBounded(t *testing.T) {
+	require := require.New(t)
+	store := NewStore(t.TempDir())
+	for i := 0; i < maxEvents+5; i++ {
+		require.NoError(store.Record(Event{Command: "lux network status", Flags: map[string]string{"run": string(rune('a' + i%26))}}))
+	}
+	events, err := store.Events()
+	require.NoError(err)
+	require.Len(events, maxEvents)
+	require.Equal(string(rune('a'+(maxEvents+4)%26)), events[len(events)-1].Flags["run"])
+}
+
+func TestCommandDisabled(t *testing.T) {
+	require := require.New(t)
+	disabled := []string{"key", "lux chain deploy"}
+	require.True(CommandDisabled("lux key list", disabled))
+	require.True(CommandDisabled("lux chain deploy", disabled))
+	require.False(CommandDisabled("lux chain create", disabled))
+	require.False(CommandDisabled("lux keys", disabled))
+	require.False(CommandDisabled("lux key list", nil))
+}
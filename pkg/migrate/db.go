@@ -0,0 +1,310 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/luxfi/database"
+)
+
+// DefaultBatchSize is the number of bytes buffered before a batch is written
+// to the target and the checkpoint is saved.
+const DefaultBatchSize = 4 * 1024 * 1024
+
+// DefaultPrefixLen is the number of leading key bytes used to group keys for
+// progress reporting and verification.
+const DefaultPrefixLen = 1
+
+var errCheckpointMismatch = errors.New("checkpoint belongs to a different migration")
+
+// Checkpoint records how far a migration got so it can be resumed. Keys are
+// copied in order, so the last written key is enough to continue.
+type Checkpoint struct {
+	Source     string            `json:"source"`
+	Target     string            `json:"target"`
+	SourceType string            `json:"source_type"`
+	TargetType string            `json:"target_type"`
+	PrefixLen  int               `json:"prefix_len"`
+	LastKey    string            `json:"last_key,omitempty"` // hex
+	Copied     uint64            `json:"copied"`
+	Prefixes   map[string]uint64 `json:"prefixes,omitempty"` // hex prefix -> keys copied
+	UpdatedAt  string            `json:"updated_at"`
+}
+
+// LoadCheckpoint reads the checkpoint at path. It returns nil without error
+// if there is no checkpoint.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+	}
+	return &cp, nil
+}
+
+// Save writes the checkpoint atomically.
+func (cp *Checkpoint) Save(path string) error {
+	cp.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Matches reports whether cp was written for the same source and target.
+func (cp *Checkpoint) Matches(other *Checkpoint) error {
+	if cp.Source != other.Source || cp.Target != other.Target ||
+		cp.SourceType != other.SourceType || cp.TargetType != other.TargetType ||
+		cp.PrefixLen != other.PrefixLen {
+		return fmt.Errorf("%w: %s (%s) -> %s (%s)", errCheckpointMismatch, cp.Source, cp.SourceType, cp.Target, cp.TargetType)
+	}
+	return nil
+}
+
+// PrefixCount is the number of keys under a key prefix.
+type PrefixCount struct {
+	Prefix []byte
+	Count  uint64
+}
+
+// PrefixHex returns the prefix as hex, or "(empty)" for the empty prefix.
+func (p PrefixCount) PrefixHex() string {
+	if len(p.Prefix) == 0 {
+		return "(empty)"
+	}
+	return hex.EncodeToString(p.Prefix)
+}
+
+// Progress receives copy progress. Start is called each time the copy moves
+// to a new prefix, with the number of keys in that prefix still to copy.
+type Progress interface {
+	Start(prefix PrefixCount, remaining uint64)
+	Add(n int)
+	Finish()
+}
+
+type noProgress struct{}
+
+func (noProgress) Start(PrefixCount, uint64) {}
+func (noProgress) Add(int)                   {}
+func (noProgress) Finish()                   {}
+
+func keyPrefix(key []byte, prefixLen int) []byte {
+	if len(key) < prefixLen {
+		return key
+	}
+	return key[:prefixLen]
+}
+
+// CountPrefixes counts the keys in db grouped by their first prefixLen bytes,
+// in key order.
+func CountPrefixes(ctx context.Context, db database.Database, prefixLen int) ([]PrefixCount, error) {
+	it := db.NewIterator()
+	defer it.Release()
+
+	var counts []PrefixCount
+	for i := 0; it.Next(); i++ {
+		if i%10000 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		prefix := keyPrefix(it.Key(), prefixLen)
+		if n := len(counts); n > 0 && bytes.Equal(counts[n-1].Prefix, prefix) {
+			counts[n-1].Count++
+			continue
+		}
+		counts = append(counts, PrefixCount{Prefix: bytes.Clone(prefix), Count: 1})
+	}
+	return counts, it.Error()
+}
+
+// Copy copies every key from src to dst in key order, resuming after
+// cp.LastKey. The checkpoint is saved to checkpointPath after every batch, so
+// an interrupted copy (including ctx cancellation) can be resumed with the
+// same checkpoint. counts must come from CountPrefixes on src.
+func Copy(
+	ctx context.Context,
+	src database.Database,
+	dst database.Database,
+	counts []PrefixCount,
+	cp *Checkpoint,
+	checkpointPath string,
+	batchSize int,
+	progress Progress,
+) error {
+	if progress == nil {
+		progress = noProgress{}
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	if cp.Prefixes == nil {
+		cp.Prefixes = map[string]uint64{}
+	}
+
+	var start []byte
+	if cp.LastKey != "" {
+		var err error
+		start, err = hex.DecodeString(cp.LastKey)
+		if err != nil {
+			return fmt.Errorf("invalid checkpoint key: %w", err)
+		}
+	}
+
+	totals := make(map[string]PrefixCount, len(counts))
+	for _, c := range counts {
+		totals[string(c.Prefix)] = c
+	}
+
+	it := src.NewIteratorWithStart(start)
+	defer it.Release()
+
+	batch := dst.NewBatch()
+	pending := 0
+	var lastKey []byte
+	var current []byte
+	started := false
+
+	flush := func() error {
+		if pending == 0 {
+			return nil
+		}
+		if err := batch.Write(); err != nil {
+			return fmt.Errorf("failed to write batch: %w", err)
+		}
+		batch.Reset()
+		cp.LastKey = hex.EncodeToString(lastKey)
+		cp.Copied += uint64(pending)
+		progress.Add(pending)
+		pending = 0
+		return cp.Save(checkpointPath)
+	}
+
+	for it.Next() {
+		key := it.Key()
+		if start != nil && bytes.Equal(key, start) {
+			continue
+		}
+
+		prefix := keyPrefix(key, cp.PrefixLen)
+		if !started || !bytes.Equal(prefix, current) {
+			if err := flush(); err != nil {
+				return err
+			}
+			if started {
+				progress.Finish()
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			current = bytes.Clone(prefix)
+			started = true
+			total := totals[string(prefix)]
+			remaining := total.Count
+			if done := cp.Prefixes[hex.EncodeToString(prefix)]; done < remaining {
+				remaining -= done
+			} else {
+				remaining = 0
+			}
+			progress.Start(total, remaining)
+		}
+
+		if err := batch.Put(key, it.Value()); err != nil {
+			return err
+		}
+		lastKey = append(lastKey[:0], key...)
+		cp.Prefixes[hex.EncodeToString(prefix)]++
+		pending++
+
+		if batch.Size() >= batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	if started {
+		progress.Finish()
+	}
+	return nil
+}
+
+// Mismatch describes a prefix whose key count differs between source and
+// target.
+type Mismatch struct {
+	Prefix string
+	Source uint64
+	Target uint64
+}
+
+// Verify compares per-prefix key counts of src and dst and returns every
+// prefix that differs.
+func Verify(ctx context.Context, src database.Database, dst database.Database, prefixLen int) ([]Mismatch, error) {
+	srcCounts, err := CountPrefixes(ctx, src, prefixLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count source keys: %w", err)
+	}
+	dstCounts, err := CountPrefixes(ctx, dst, prefixLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count target keys: %w", err)
+	}
+
+	byPrefix := map[string]*Mismatch{}
+	var order []string
+	for _, c := range srcCounts {
+		p := c.PrefixHex()
+		byPrefix[p] = &Mismatch{Prefix: p, Source: c.Count}
+		order = append(order, p)
+	}
+	for _, c := range dstCounts {
+		p := c.PrefixHex()
+		m, ok := byPrefix[p]
+		if !ok {
+			m = &Mismatch{Prefix: p}
+			byPrefix[p] = m
+			order = append(order, p)
+		}
+		m.Target = c.Count
+	}
+
+	var mismatches []Mismatch
+	for _, p := range order {
+		if m := byPrefix[p]; m.Source != m.Target {
+			mismatches = append(mismatches, *m)
+		}
+	}
+	return mismatches, nil
+}
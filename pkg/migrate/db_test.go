@@ -0,0 +1,139 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/luxfi/database"
+	"github.com/luxfi/database/memdb"
+)
+
+func populate(t *testing.T, db database.Database) {
+	t.Helper()
+	for p := 0; p < 3; p++ {
+		for i := 0; i < 100; i++ {
+			key := append([]byte{byte(p)}, []byte(fmt.Sprintf("key-%03d", i))...)
+			if err := db.Put(key, []byte(fmt.Sprintf("value-%d-%d", p, i))); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+// cancelAfter cancels the context once n keys were reported.
+type cancelAfter struct {
+	n      int
+	cancel context.CancelFunc
+}
+
+func (c *cancelAfter) Start(PrefixCount, uint64) {}
+func (c *cancelAfter) Finish()                   {}
+func (c *cancelAfter) Add(n int) {
+	c.n -= n
+	if c.n <= 0 {
+		c.cancel()
+	}
+}
+
+func TestCountPrefixes(t *testing.T) {
+	src := memdb.New()
+	populate(t, src)
+
+	counts, err := CountPrefixes(context.Background(), src, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(counts) != 3 {
+		t.Fatalf("expected 3 prefixes, got %d", len(counts))
+	}
+	for i, c := range counts {
+		if c.Count != 100 {
+			t.Errorf("prefix %s: expected 100 keys, got %d", c.PrefixHex(), c.Count)
+		}
+		if c.PrefixHex() != fmt.Sprintf("%02x", i) {
+			t.Errorf("unexpected prefix order: %s at %d", c.PrefixHex(), i)
+		}
+	}
+}
+
+func TestCopyResume(t *testing.T) {
+	src := memdb.New()
+	dst := memdb.New()
+	populate(t, src)
+	checkpointPath := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	counts, err := CountPrefixes(context.Background(), src, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Interrupt after the first few batches
+	ctx, cancel := context.WithCancel(context.Background())
+	cp := &Checkpoint{PrefixLen: 1}
+	err = Copy(ctx, src, dst, counts, cp, checkpointPath, 64, &cancelAfter{n: 120, cancel: cancel})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	saved, err := LoadCheckpoint(checkpointPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved == nil || saved.Copied == 0 || saved.Copied >= 300 {
+		t.Fatalf("expected a partial checkpoint, got %+v", saved)
+	}
+
+	if err := Copy(context.Background(), src, dst, counts, saved, checkpointPath, 64, nil); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Copied != 300 {
+		t.Errorf("expected 300 keys copied in total, got %d", saved.Copied)
+	}
+
+	mismatches, err := Verify(context.Background(), src, dst, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mismatches) != 0 {
+		t.Errorf("expected no mismatches, got %+v", mismatches)
+	}
+}
+
+func TestVerifyMismatch(t *testing.T) {
+	src := memdb.New()
+	dst := memdb.New()
+	populate(t, src)
+	if err := dst.Put([]byte{0x00, 'x'}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	mismatches, err := Verify(context.Background(), src, dst, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mismatches) != 3 {
+		t.Fatalf("expected 3 mismatching prefixes, got %+v", mismatches)
+	}
+	if mismatches[0].Prefix != "00" || mismatches[0].Source != 100 || mismatches[0].Target != 1 {
+		t.Errorf("unexpected mismatch: %+v", mismatches[0])
+	}
+}
+
+func TestCheckpointMatches(t *testing.T) {
+	cp := &Checkpoint{Source: "/a", Target: "/b", SourceType: "leveldb", TargetType: "badgerdb", PrefixLen: 1}
+	same := *cp
+	if err := cp.Matches(&same); err != nil {
+		t.Fatal(err)
+	}
+	other := *cp
+	other.Target = "/c"
+	if err := cp.Matches(&other); !errors.Is(err, errCheckpointMismatch) {
+		t.Fatalf("expected errCheckpointMismatch, got %v", err)
+	}
+}
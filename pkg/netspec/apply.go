@@ -0,0 +1,167 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package netspec
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ActionKind is a step taken to reconcile the environment with a spec
+type ActionKind string
+
+const (
+	StartNetwork ActionKind = "start-network"
+	CreateChain  ActionKind = "create-chain"
+	UpdateChain  ActionKind = "update-chain"
+	DeployChain  ActionKind = "deploy-chain"
+	AddValidator ActionKind = "add-validator"
+)
+
+// Action is a single reconcile step
+type Action struct {
+	Kind    ActionKind
+	Network string
+	// Chain is set for chain and validator actions
+	Chain *Chain
+	// Validator is set for AddValidator
+	Validator *Validator
+}
+
+func (a Action) String() string {
+	switch a.Kind {
+	case StartNetwork:
+		return fmt.Sprintf("start network %s", a.Network)
+	case CreateChain:
+		return fmt.Sprintf("create chain %s", a.Chain.Name)
+	case UpdateChain:
+		return fmt.Sprintf("update warp settings of chain %s", a.Chain.Name)
+	case DeployChain:
+		return fmt.Sprintf("deploy chain %s to %s", a.Chain.Name, a.Network)
+	case AddValidator:
+		return fmt.Sprintf("add validator %s (weight %d) to chain %s on %s", a.Validator.NodeID, a.Validator.Weight, a.Chain.Name, a.Network)
+	}
+	return string(a.Kind)
+}
+
+// State reports the current state of the environment
+type State interface {
+	NetworkRunning(network string) (bool, error)
+	ChainExists(chain string) bool
+	ChainWarp(chain string) (Warp, error)
+	ChainDeployed(chain, network string) (bool, error)
+	// ChainValidators returns the NodeIDs validating a deployed chain
+	ChainValidators(chain, network string) ([]string, error)
+}
+
+// Executor changes the environment
+type Executor interface {
+	State
+	StartNetwork(network string) error
+	CreateChain(chain Chain) error
+	UpdateChain(chain Chain) error
+	DeployChain(chain, network string) error
+	AddValidator(chain, network string, validator Validator) error
+}
+
+// Plan returns the actions needed to bring state to spec, in the order they
+// must be applied. Existing resources are never deleted or recreated.
+func Plan(spec *Spec, state State) ([]Action, error) {
+	var actions []Action
+	for _, n := range spec.Networks {
+		running, err := state.NetworkRunning(n.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check network %s: %w", n.Name, err)
+		}
+		if !running {
+			if !n.Start {
+				return nil, fmt.Errorf("network %s is not running: start it or set 'start: true' in the spec", n.Name)
+			}
+			actions = append(actions, Action{Kind: StartNetwork, Network: n.Name})
+		}
+
+		for i := range n.Chains {
+			c := &n.Chains[i]
+			chainActions, err := planChain(state, n.Name, c, running)
+			if err != nil {
+				return nil, fmt.Errorf("chain %s: %w", c.Name, err)
+			}
+			actions = append(actions, chainActions...)
+		}
+	}
+	return actions, nil
+}
+
+func planChain(state State, network string, c *Chain, running bool) ([]Action, error) {
+	var actions []Action
+	exists := state.ChainExists(c.Name)
+	if !exists {
+		actions = append(actions, Action{Kind: CreateChain, Network: network, Chain: c})
+	} else {
+		warp, err := state.ChainWarp(c.Name)
+		if err != nil {
+			return nil, err
+		}
+		if warp != c.Warp {
+			actions = append(actions, Action{Kind: UpdateChain, Network: network, Chain: c})
+		}
+	}
+
+	deployed := false
+	if exists {
+		var err error
+		if deployed, err = state.ChainDeployed(c.Name, network); err != nil {
+			return nil, err
+		}
+	}
+	if !deployed {
+		actions = append(actions, Action{Kind: DeployChain, Network: network, Chain: c})
+	}
+
+	// Validators of a chain that is not deployed yet, or of a network that
+	// is not running, can't be queried: they all have to be added
+	var current []string
+	if deployed && running && len(c.Validators) > 0 {
+		var err error
+		if current, err = state.ChainValidators(c.Name, network); err != nil {
+			return nil, err
+		}
+	}
+	for i := range c.Validators {
+		v := &c.Validators[i]
+		if !slices.Contains(current, v.NodeID) {
+			actions = append(actions, Action{Kind: AddValidator, Network: network, Chain: c, Validator: v})
+		}
+	}
+	return actions, nil
+}
+
+// Apply runs actions in order. report is called before each action. It
+// stops at the first failure, the spec can be applied again to resume.
+func Apply(actions []Action, exec Executor, report func(Action)) error {
+	for _, a := range actions {
+		if report != nil {
+			report(a)
+		}
+		var err error
+		switch a.Kind {
+		case StartNetwork:
+			err = exec.StartNetwork(a.Network)
+		case CreateChain:
+			err = exec.CreateChain(*a.Chain)
+		case UpdateChain:
+			err = exec.UpdateChain(*a.Chain)
+		case DeployChain:
+			err = exec.DeployChain(a.Chain.Name, a.Network)
+		case AddValidator:
+			err = exec.AddValidator(a.Chain.Name, a.Network, *a.Validator)
+		default:
+			err = fmt.Errorf("unknown action %s", a.Kind)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s: %w", a, err)
+		}
+	}
+	return nil
+}
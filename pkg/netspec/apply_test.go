@@ -0,0 +1,165 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package netspec
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `
+networks:
+  - name: local
+    start: true
+    chains:
+      - name: alpha
+        type: l1
+        genesis: genesis.json
+        warp: {enabled: true, relayer: true}
+        validators:
+          - {nodeID: NodeID-A, weight: 20}
+          - {nodeID: NodeID-B, weight: 20, duration: 48h}
+      - name: beta
+        allocations:
+          - {address: "0x9011E888251AB053B7bD1cdB598Db4f9DEd94714", balance: "0x3635c9adc5dea00000"}
+`
+
+// fakeEnv records the state and the applied changes
+type fakeEnv struct {
+	running    map[string]bool
+	chains     map[string]Warp
+	deployed   map[string]bool
+	validators map[string][]string
+	applied    []string
+}
+
+func newFakeEnv() *fakeEnv {
+	return &fakeEnv{
+		running:    map[string]bool{},
+		chains:     map[string]Warp{},
+		deployed:   map[string]bool{},
+		validators: map[string][]string{},
+	}
+}
+
+func (f *fakeEnv) NetworkRunning(n string) (bool, error) { return f.running[n], nil }
+func (f *fakeEnv) ChainExists(c string) bool             { _, ok := f.chains[c]; return ok }
+func (f *fakeEnv) ChainWarp(c string) (Warp, error)      { return f.chains[c], nil }
+func (f *fakeEnv) ChainDeployed(c, n string) (bool, error) {
+	return f.deployed[c+"/"+n], nil
+}
+
+func (f *fakeEnv) ChainValidators(c, n string) ([]string, error) {
+	if !f.running[n] {
+		return nil, errors.New("network not running")
+	}
+	return f.validators[c+"/"+n], nil
+}
+
+func (f *fakeEnv) StartNetwork(n string) error {
+	f.applied = append(f.applied, "start "+n)
+	f.running[n] = true
+	return nil
+}
+
+func (f *fakeEnv) CreateChain(c Chain) error {
+	f.applied = append(f.applied, "create "+c.Name)
+	f.chains[c.Name] = c.Warp
+	return nil
+}
+
+func (f *fakeEnv) UpdateChain(c Chain) error {
+	f.applied = append(f.applied, "update "+c.Name)
+	f.chains[c.Name] = c.Warp
+	return nil
+}
+
+func (f *fakeEnv) DeployChain(c, n string) error {
+	f.applied = append(f.applied, "deploy "+c)
+	f.deployed[c+"/"+n] = true
+	return nil
+}
+
+func (f *fakeEnv) AddValidator(c, n string, v Validator) error {
+	f.applied = append(f.applied, "validator "+v.NodeID)
+	f.validators[c+"/"+n] = append(f.validators[c+"/"+n], v.NodeID)
+	return nil
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "network.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testSpec), 0o600))
+
+	spec, err := Load(path)
+	require.NoError(t, err)
+	require.Len(t, spec.Networks, 1)
+	alpha := spec.Networks[0].Chains[0]
+	require.Equal(t, filepath.Join(dir, "genesis.json"), alpha.Genesis)
+	require.Equal(t, DefaultValidatorDuration, alpha.Validators[0].StakingDuration())
+	require.Equal(t, "48h0m0s", alpha.Validators[1].StakingDuration().String())
+}
+
+func TestParseInvalid(t *testing.T) {
+	for name, spec := range map[string]string{
+		"no networks":      `networks: []`,
+		"unknown network":  `networks: [{name: moonnet}]`,
+		"unknown field":    `networks: [{name: local, chians: []}]`,
+		"start mainnet":    `networks: [{name: mainnet, start: true}]`,
+		"duplicate chain":  `networks: [{name: local, chains: [{name: a}]}, {name: devnet, chains: [{name: a}]}]`,
+		"bad address":      `networks: [{name: local, chains: [{name: a, allocations: [{address: "0x12", balance: "1"}]}]}]`,
+		"zero balance":     `networks: [{name: local, chains: [{name: a, allocations: [{address: "9011E888251AB053B7bD1cdB598Db4f9DEd94714", balance: "0"}]}]}]`,
+		"zero weight":      `networks: [{name: local, chains: [{name: a, validators: [{nodeID: NodeID-A}]}]}]`,
+		"custom vm no bin": `networks: [{name: local, chains: [{name: a, vm: custom}]}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(spec))
+			require.Error(t, err)
+		})
+	}
+
+	// JSON specs are accepted
+	_, err := Parse([]byte(`{"networks": [{"name": "local", "chains": [{"name": "a"}]}]}`))
+	require.NoError(t, err)
+}
+
+func TestPlanAndApply(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	require.NoError(t, err)
+	env := newFakeEnv()
+
+	actions, err := Plan(spec, env)
+	require.NoError(t, err)
+	require.NoError(t, Apply(actions, env, nil))
+	require.Equal(t, []string{
+		"start local",
+		"create alpha", "deploy alpha", "validator NodeID-A", "validator NodeID-B",
+		"create beta", "deploy beta",
+	}, env.applied)
+
+	// Applying again is a no-op
+	actions, err = Plan(spec, env)
+	require.NoError(t, err)
+	require.Empty(t, actions)
+
+	// Drift is reconciled without recreating anything
+	env.chains["alpha"] = Warp{}
+	env.validators["alpha/local"] = []string{"NodeID-A"}
+	actions, err = Plan(spec, env)
+	require.NoError(t, err)
+	require.Len(t, actions, 2)
+	require.Equal(t, "update warp settings of chain alpha", actions[0].String())
+	require.Equal(t, "add validator NodeID-B (weight 20) to chain alpha on local", actions[1].String())
+}
+
+func TestPlanNetworkNotRunning(t *testing.T) {
+	spec, err := Parse([]byte(`networks: [{name: devnet, chains: [{name: a}]}]`))
+	require.NoError(t, err)
+	_, err = Plan(spec, newFakeEnv())
+	require.ErrorContains(t, err, "network devnet is not running")
+}
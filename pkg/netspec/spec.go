@@ -0,0 +1,261 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package netspec reads declarative network spec files and reconciles the
+// current environment toward them (lux network apply).
+package netspec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Networks a spec can target
+var supportedNetworks = []string{"mainnet", "testnet", "devnet", "local"}
+
+var (
+	chainNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
+	addressRegex   = regexp.MustCompile(`^(0x)?[0-9a-fA-F]{40}$`)
+)
+
+// Spec declares the desired state of one or more networks.
+//
+// Example:
+//
+//	networks:
+//	  - name: local
+//	    start: true
+//	    chains:
+//	      - name: mychain
+//	        type: l1
+//	        chainId: 200200
+//	        token: {name: My Token, symbol: MYT}
+//	        allocations:
+//	          - {address: "0x9011E888251AB053B7bD1cdB598Db4f9DEd94714", balance: "1000000000000000000000"}
+//	        warp: {enabled: true, relayer: true}
+//	        validators:
+//	          - {nodeID: NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg, weight: 20}
+type Spec struct {
+	Networks []Network `yaml:"networks"`
+}
+
+// Network is the desired state of a network
+type Network struct {
+	// Name is one of mainnet, testnet, devnet or local
+	Name string `yaml:"name"`
+	// Start the network when it is not running (local and devnet)
+	Start  bool    `yaml:"start"`
+	Chains []Chain `yaml:"chains"`
+}
+
+// Chain is a blockchain that must exist and be deployed to its network
+type Chain struct {
+	Name string `yaml:"name"`
+	// Type is l1, l2 or l3
+	Type string `yaml:"type"`
+	// VM is evm (default), pars or custom
+	VM string `yaml:"vm"`
+	// VMBinary is the custom VM binary, relative to the spec file
+	VMBinary string `yaml:"vmBinary"`
+	// Genesis is a genesis file, relative to the spec file. Generated when empty.
+	Genesis     string       `yaml:"genesis"`
+	ChainID     uint64       `yaml:"chainId"`
+	Token       Token        `yaml:"token"`
+	Allocations []Allocation `yaml:"allocations"`
+	Warp        Warp         `yaml:"warp"`
+	Validators  []Validator  `yaml:"validators"`
+}
+
+// Token is the native token of a chain
+type Token struct {
+	Name   string `yaml:"name"`
+	Symbol string `yaml:"symbol"`
+}
+
+// Allocation funds an address in the chain genesis
+type Allocation struct {
+	Address string `yaml:"address"`
+	// Balance in wei, decimal or 0x prefixed hex
+	Balance string `yaml:"balance"`
+}
+
+// Warp holds the cross-chain messaging settings of a chain
+type Warp struct {
+	Enabled bool `yaml:"enabled"`
+	Relayer bool `yaml:"relayer"`
+	// Key funds the relayer
+	Key string `yaml:"key"`
+}
+
+// Validator is a node that must validate a chain
+type Validator struct {
+	NodeID string `yaml:"nodeID"`
+	Weight uint64 `yaml:"weight"`
+	// Duration of the validation, e.g. 720h. Defaults to 14 days.
+	Duration string `yaml:"duration"`
+	// Key pays for the transaction, defaults to the deploy key
+	Key string `yaml:"key"`
+}
+
+// DefaultValidatorDuration is used when a validator sets no duration
+const DefaultValidatorDuration = 14 * 24 * time.Hour
+
+// StakingDuration returns the parsed validation duration
+func (v Validator) StakingDuration() time.Duration {
+	d, err := time.ParseDuration(v.Duration)
+	if err != nil || d == 0 {
+		return DefaultValidatorDuration
+	}
+	return d
+}
+
+// Load reads a YAML or JSON spec file. Relative paths in the spec are resolved
+// against the directory of the file.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: User-specified spec file
+	if err != nil {
+		return nil, err
+	}
+	spec, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	dir := filepath.Dir(path)
+	for i := range spec.Networks {
+		for j := range spec.Networks[i].Chains {
+			c := &spec.Networks[i].Chains[j]
+			c.Genesis = resolvePath(dir, c.Genesis)
+			c.VMBinary = resolvePath(dir, c.VMBinary)
+		}
+	}
+	return spec, nil
+}
+
+// Parse decodes and validates a spec. JSON is accepted as it is valid YAML.
+func Parse(data []byte) (*Spec, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	spec := &Spec{}
+	if err := decoder.Decode(spec); err != nil {
+		return nil, fmt.Errorf("invalid spec: %w", err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+func resolvePath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// Validate checks the spec is complete and consistent
+func (s *Spec) Validate() error {
+	if len(s.Networks) == 0 {
+		return errors.New("spec declares no networks")
+	}
+	var errs []error
+	seenNetworks := map[string]bool{}
+	seenChains := map[string]string{}
+	for _, n := range s.Networks {
+		if !slices.Contains(supportedNetworks, n.Name) {
+			errs = append(errs, fmt.Errorf("network %q: must be one of %s", n.Name, strings.Join(supportedNetworks, ", ")))
+			continue
+		}
+		if seenNetworks[n.Name] {
+			errs = append(errs, fmt.Errorf("network %q declared twice", n.Name))
+		}
+		seenNetworks[n.Name] = true
+		if n.Start && (n.Name == "mainnet" || n.Name == "testnet") {
+			errs = append(errs, fmt.Errorf("network %q: start is only supported for local and devnet", n.Name))
+		}
+		for _, c := range n.Chains {
+			// Chain configs are shared between networks, a chain must be
+			// declared once so its settings can't conflict
+			if other, ok := seenChains[c.Name]; ok {
+				errs = append(errs, fmt.Errorf("chain %q declared in %s and %s", c.Name, other, n.Name))
+			}
+			seenChains[c.Name] = n.Name
+			if err := c.validate(); err != nil {
+				errs = append(errs, fmt.Errorf("network %q: chain %q: %w", n.Name, c.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c Chain) validate() error {
+	if !chainNameRegex.MatchString(c.Name) {
+		return errors.New("name must start with a letter and contain only letters, digits, '_' and '-'")
+	}
+	if c.Type != "" && !slices.Contains([]string{"l1", "l2", "l3"}, c.Type) {
+		return fmt.Errorf("type %q: must be l1, l2 or l3", c.Type)
+	}
+	if c.VM != "" && !slices.Contains([]string{"evm", "pars", "custom"}, c.VM) {
+		return fmt.Errorf("vm %q: must be evm, pars or custom", c.VM)
+	}
+	if c.VM == "custom" && c.VMBinary == "" {
+		return errors.New("vmBinary is required for custom VMs")
+	}
+	if len(c.Allocations) > 0 && c.Genesis != "" {
+		return errors.New("allocations can't be combined with a genesis file, add them to the genesis instead")
+	}
+	if len(c.Allocations) > 0 && c.VM != "" && c.VM != "evm" {
+		return errors.New("allocations are only supported for evm chains")
+	}
+	for _, a := range c.Allocations {
+		if !addressRegex.MatchString(a.Address) {
+			return fmt.Errorf("allocation address %q is not a hex address", a.Address)
+		}
+		if _, err := ParseBalance(a.Balance); err != nil {
+			return fmt.Errorf("allocation to %s: %w", a.Address, err)
+		}
+	}
+	seenValidators := map[string]bool{}
+	for _, v := range c.Validators {
+		if !strings.HasPrefix(v.NodeID, "NodeID-") {
+			return fmt.Errorf("validator %q: nodeID must start with NodeID-", v.NodeID)
+		}
+		if seenValidators[v.NodeID] {
+			return fmt.Errorf("validator %s declared twice", v.NodeID)
+		}
+		seenValidators[v.NodeID] = true
+		if v.Weight == 0 {
+			return fmt.Errorf("validator %s: weight must be positive", v.NodeID)
+		}
+		if v.Duration != "" {
+			if _, err := time.ParseDuration(v.Duration); err != nil {
+				return fmt.Errorf("validator %s: invalid duration: %w", v.NodeID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ParseBalance parses a wei balance given as decimal or 0x prefixed hex
+func ParseBalance(balance string) (*big.Int, error) {
+	n := new(big.Int)
+	var ok bool
+	if hex, isHex := strings.CutPrefix(strings.ToLower(balance), "0x"); isHex {
+		_, ok = n.SetString(hex, 16)
+	} else {
+		_, ok = n.SetString(balance, 10)
+	}
+	if !ok || n.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid balance %q: expected a positive amount of wei", balance)
+	}
+	return n, nil
+}
@@ -0,0 +1,358 @@
+// Copyright (C) 2022-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/luxfi/cli/pkg/ansible"
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/keychain"
+	"github.com/luxfi/cli/pkg/remoteconfig"
+	"github.com/luxfi/cli/pkg/secretref"
+	"github.com/luxfi/cli/pkg/ssh"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+)
+
+// ConfigFile is a config file of a chain tracked by a cluster, as kept
+// locally and uploaded to the cluster's hosts
+type ConfigFile struct {
+	Chain string
+	// Kind is one of "node", "chain", "blockchain" or "upgrade"
+	Kind       string
+	RemotePath string
+	Content    []byte
+	// Partial is set when Content only holds some of the settings of the
+	// remote file: the chain node config, merged into the node config
+	Partial bool
+	// Secrets are the secrets Content held in plain text, replaced by their
+	// kms://secret/ references, to store in the KMS before uploading it
+	Secrets map[secretref.Ref][]byte
+}
+
+// ConfigDrift is how the copy of a config file on a host differs from the
+// local one
+type ConfigDrift struct {
+	Host    *models.Host
+	File    ConfigFile
+	Missing bool
+	Changes []string
+}
+
+// ChainConfigFiles returns the config files of chainName on network that
+// ssh.RunSSHSyncChainData uploads to a host, as templates referencing their
+// secrets
+func ChainConfigFiles(app *application.Lux, network models.Network, chainName string) ([]ConfigFile, error) {
+	sc, err := app.LoadSidecar(chainName)
+	if err != nil {
+		return nil, err
+	}
+	chainID := sc.Networks[network.String()].ChainID
+	if chainID == ids.Empty {
+		return nil, fmt.Errorf("chain %s is not deployed to %s", chainName, network.Name())
+	}
+	blockchainID := sc.Networks[network.String()].BlockchainID
+	var files []ConfigFile
+	chainNodeConfigPath := app.GetLuxdNodeConfigPath(chainName)
+	if utils.FileExists(chainNodeConfigPath) {
+		content, err := os.ReadFile(chainNodeConfigPath) //nolint:gosec // G304: Reading node config from app's directory
+		if err != nil {
+			return nil, fmt.Errorf("error reading node config: %w", err)
+		}
+		file := ConfigFile{
+			Chain:      chainName,
+			Kind:       "node",
+			RemotePath: remoteconfig.GetRemoteLuxNodeConfig(),
+			Content:    content,
+			Partial:    true,
+		}
+		if err := addConfigFile(&files, file); err != nil {
+			return nil, err
+		}
+	}
+	if app.ChainConfigExists(chainName) {
+		content, err := app.GetSDKApp().LoadRawLuxdChainConfig(chainName)
+		if err != nil {
+			return nil, fmt.Errorf("error loading blockchain config: %w", err)
+		}
+		file := ConfigFile{
+			Chain:      chainName,
+			Kind:       "chain",
+			RemotePath: remoteconfig.GetRemoteLuxChainConfig(chainID.String()),
+			Content:    content,
+		}
+		if err := addConfigFile(&files, file); err != nil {
+			return nil, err
+		}
+		if blockchainID != ids.Empty {
+			content, err := app.GetSDKApp().LoadRawChainConfig(chainName)
+			if err != nil {
+				return nil, fmt.Errorf("error loading chain config: %w", err)
+			}
+			file := ConfigFile{
+				Chain:      chainName,
+				Kind:       "blockchain",
+				RemotePath: remoteconfig.GetRemoteLuxBlockchainConfig(blockchainID.String()),
+				Content:    content,
+			}
+			if err := addConfigFile(&files, file); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if app.NetworkUpgradeExists(chainName) {
+		content, err := app.GetSDKApp().LoadRawNetworkUpgrades(chainName)
+		if err != nil {
+			return nil, fmt.Errorf("error loading network upgrades: %w", err)
+		}
+		files = append(files, ConfigFile{
+			Chain:      chainName,
+			Kind:       "upgrade",
+			RemotePath: remoteconfig.GetRemoteLuxNetworkUpgrades(blockchainID.String()),
+			Content:    content,
+		})
+	}
+	return files, nil
+}
+
+// addConfigFile appends f to files, with its secrets taken out
+func addConfigFile(files *[]ConfigFile, f ConfigFile) error {
+	template, secrets, err := ssh.ChainConfigTemplate(f.Content, f.Chain, f.Kind)
+	if err != nil {
+		return err
+	}
+	f.Content, f.Secrets = template, secrets
+	*files = append(*files, f)
+	return nil
+}
+
+// storeConfigSecrets stores the secrets of files in kms, before the files
+// are uploaded to hosts
+func storeConfigSecrets(files []ConfigFile, kms secretref.Store) error {
+	ctx, cancel := context.WithTimeout(context.Background(), constants.APIRequestLargeTimeout)
+	defer cancel()
+	for _, f := range files {
+		if err := secretref.StoreAll(ctx, kms, f.Secrets); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DiffClusterConfig compares the config files of the chains tracked by
+// clusterName on every host with the local ones
+func DiffClusterConfig(app *application.Lux, clusterName string) ([]ConfigDrift, error) {
+	_, files, err := clusterConfigFiles(app, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return nil, err
+	}
+	defer DisconnectHosts(hosts)
+	return diffHostsConfig(hosts, files)
+}
+
+// SyncClusterConfig uploads the local config files of the chains tracked by
+// clusterName to the hosts their copy drifted on, and restarts the node of
+// those hosts. It returns the drifts it fixed. The secrets of the config
+// files are stored in the KMS server of LUX_KMS_ADDR, the hosts only get
+// them rendered in tmpfs.
+func SyncClusterConfig(app *application.Lux, clusterName string) ([]ConfigDrift, error) {
+	network, files, err := clusterConfigFiles(app, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return nil, err
+	}
+	defer DisconnectHosts(hosts)
+	drifts, err := diffHostsConfig(hosts, files)
+	if err != nil {
+		return nil, err
+	}
+	hostChains := map[*models.Host][]string{}
+	for _, d := range drifts {
+		hostChains[d.Host] = utils.Unique(append(hostChains[d.Host], d.File.Chain))
+	}
+	kms := keychain.NewKMSClientFromEnv()
+	if len(hostChains) > 0 {
+		if err := storeConfigSecrets(files, kms); err != nil {
+			return nil, err
+		}
+	}
+	wg := sync.WaitGroup{}
+	wgResults := models.NodeResults{}
+	for host, chains := range hostChains {
+		wg.Add(1)
+		go func(nodeResults *models.NodeResults, host *models.Host, chains []string) {
+			defer wg.Done()
+			for _, chainName := range chains {
+				if err := ssh.RunSSHSyncChainData(app, host, network, chainName, kms); err != nil {
+					nodeResults.AddResult(host.NodeID, nil, err)
+					return
+				}
+			}
+			if err := ssh.RunSSHRestartNode(host); err != nil {
+				nodeResults.AddResult(host.NodeID, nil, err)
+			}
+		}(&wgResults, host, chains)
+	}
+	wg.Wait()
+	if wgResults.HasErrors() {
+		return nil, fmt.Errorf("failed to sync the config of node(s) %s", wgResults.GetErrorHostMap())
+	}
+	return drifts, nil
+}
+
+// clusterConfigFiles returns the network of clusterName and the config files
+// of the chains it tracks
+func clusterConfigFiles(app *application.Lux, clusterName string) (models.Network, []ConfigFile, error) {
+	if err := CheckCluster(app, clusterName); err != nil {
+		return models.UndefinedNetwork, nil, err
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return models.UndefinedNetwork, nil, err
+	}
+	networkStr, _ := clusterConfig["network"].(string)
+	network := models.NetworkFromString(networkStr)
+	var files []ConfigFile
+	for _, chainName := range stringSlice(clusterConfig["chains"]) {
+		chainFiles, err := ChainConfigFiles(app, network, chainName)
+		if err != nil {
+			return models.UndefinedNetwork, nil, err
+		}
+		files = append(files, chainFiles...)
+	}
+	return network, files, nil
+}
+
+// diffHostsConfig compares files with their copy on every host
+func diffHostsConfig(hosts []*models.Host, files []ConfigFile) ([]ConfigDrift, error) {
+	wg := sync.WaitGroup{}
+	wgResults := models.NodeResults{}
+	hostDrifts := make([][]ConfigDrift, len(hosts))
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(nodeResults *models.NodeResults, i int, host *models.Host) {
+			defer wg.Done()
+			for _, f := range files {
+				d, err := diffHostConfig(host, f)
+				if err != nil {
+					nodeResults.AddResult(host.NodeID, nil, err)
+					return
+				}
+				if d.Missing || len(d.Changes) > 0 {
+					hostDrifts[i] = append(hostDrifts[i], d)
+				}
+			}
+		}(&wgResults, i, host)
+	}
+	wg.Wait()
+	if wgResults.HasErrors() {
+		return nil, fmt.Errorf("failed to read the config of node(s) %s", wgResults.GetErrorHostMap())
+	}
+	var drifts []ConfigDrift
+	for _, d := range hostDrifts {
+		drifts = append(drifts, d...)
+	}
+	return drifts, nil
+}
+
+// diffHostConfig compares f with its copy on host. A template referencing
+// secrets is compared with the template kept on host, and is missing when
+// its rendering is, as after a reboot.
+func diffHostConfig(host *models.Host, f ConfigFile) (ConfigDrift, error) {
+	d := ConfigDrift{Host: host, File: f}
+	remotePath := f.RemotePath
+	paths := []string{remotePath}
+	if len(secretref.Refs(f.Content)) > 0 {
+		remotePath = ssh.TemplatePath(f.RemotePath)
+		paths = append(paths, remotePath)
+	}
+	for _, p := range paths {
+		exists, err := host.FileExists(p)
+		if err != nil {
+			return d, err
+		}
+		if !exists {
+			d.Missing = true
+			return d, nil
+		}
+	}
+	remote, err := host.ReadFileBytes(remotePath, constants.SSHFileOpsTimeout)
+	if err != nil {
+		return d, fmt.Errorf("error reading %s: %w", remotePath, err)
+	}
+	d.Changes = DiffConfig(f.Content, remote, f.Partial)
+	return d, nil
+}
+
+// DiffConfig returns the settings of the JSON config remote that differ
+// from the canonical one, by dotted key. When partial is set, the settings
+// canonical doesn't have are not compared. Configs that aren't JSON objects
+// are compared as a whole.
+func DiffConfig(canonical, remote []byte, partial bool) []string {
+	var want, got map[string]interface{}
+	if json.Unmarshal(canonical, &want) != nil || json.Unmarshal(remote, &got) != nil {
+		if bytes.Equal(bytes.TrimSpace(canonical), bytes.TrimSpace(remote)) {
+			return nil
+		}
+		return []string{"content differs"}
+	}
+	changes := diffConfigMaps("", want, got, partial)
+	sort.Strings(changes)
+	return changes
+}
+
+func diffConfigMaps(prefix string, want, got map[string]interface{}, partial bool) []string {
+	var changes []string
+	for key, w := range want {
+		g, ok := got[key]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("%s%s: missing, want %s", prefix, key, formatConfigValue(w)))
+		case reflect.DeepEqual(w, g):
+		default:
+			wm, wok := w.(map[string]interface{})
+			gm, gok := g.(map[string]interface{})
+			if wok && gok {
+				changes = append(changes, diffConfigMaps(prefix+key+".", wm, gm, partial)...)
+				continue
+			}
+			changes = append(changes, fmt.Sprintf("%s%s: %s, want %s", prefix, key, formatConfigValue(g), formatConfigValue(w)))
+		}
+	}
+	if partial {
+		return changes
+	}
+	for key, g := range got {
+		if _, ok := want[key]; !ok {
+			changes = append(changes, fmt.Sprintf("%s%s: %s, want unset", prefix, key, formatConfigValue(g)))
+		}
+	}
+	return changes
+}
+
+// formatConfigValue formats a decoded JSON value for display
+func formatConfigValue(v interface{}) string {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(raw)
+}
@@ -0,0 +1,29 @@
+// Copyright (C) 2022-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffConfig(t *testing.T) {
+	require := require.New(t)
+	canonical := []byte(`{"log-level": "info", "warp-api-enabled": true, "pruning": {"enabled": true, "depth": 128}}`)
+
+	require.Empty(DiffConfig(canonical, []byte(`{"warp-api-enabled":true,"log-level":"info","pruning":{"depth":128,"enabled":true}}`), false))
+	require.Equal([]string{
+		`debug: true, want unset`,
+		`log-level: "debug", want "info"`,
+		`pruning.depth: missing, want 128`,
+		`warp-api-enabled: missing, want true`,
+	}, DiffConfig(canonical, []byte(`{"log-level": "debug", "debug": true, "pruning": {"enabled": true}}`), false))
+
+	// a partial config only sets some of the remote settings
+	require.Empty(DiffConfig(canonical, []byte(`{"log-level": "info", "warp-api-enabled": true, "pruning": {"enabled": true, "depth": 128}, "http-port": 9630}`), true))
+
+	require.Equal([]string{"content differs"}, DiffConfig([]byte("a=1"), []byte("a=2"), false))
+	require.Empty(DiffConfig([]byte("a=1\n"), []byte("a=1"), false))
+}
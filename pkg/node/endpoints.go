@@ -0,0 +1,81 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"slices"
+
+	"github.com/luxfi/cli/pkg/ansible"
+	"github.com/luxfi/cli/pkg/application"
+)
+
+// ClusterEndpoint is a node of a cluster and where its API is reachable
+type ClusterEndpoint struct {
+	CloudID string
+	NodeID  string
+	IP      string
+	API     bool // node is an API node, not a validator
+}
+
+// URL returns the luxd API endpoint of the node
+func (e ClusterEndpoint) URL() string {
+	return GetLuxdEndpoint(e.IP)
+}
+
+// ClusterEndpoints describes where the nodes of a cluster are reachable
+type ClusterEndpoints struct {
+	Network string
+	Local   bool // local clusters have no inventory, use the local network status
+	Nodes   []ClusterEndpoint
+}
+
+// GetClusterEndpoints returns the nodes of clusterName with their public IPs
+func GetClusterEndpoints(app *application.Lux, clusterName string) (*ClusterEndpoints, error) {
+	if err := CheckCluster(app, clusterName); err != nil {
+		return nil, err
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	result := &ClusterEndpoints{}
+	result.Network, _ = clusterConfig["network"].(string)
+	result.Local, _ = clusterConfig["local"].(bool)
+	if result.Local {
+		return result, nil
+	}
+
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return nil, err
+	}
+	apiNodes := stringSlice(clusterConfig["apiNodes"])
+	for _, host := range hosts {
+		cloudID := host.GetCloudID()
+		result.Nodes = append(result.Nodes, ClusterEndpoint{
+			CloudID: cloudID,
+			NodeID:  host.NodeID,
+			IP:      host.IP,
+			API:     slices.Contains(apiNodes, cloudID),
+		})
+	}
+	return result, nil
+}
+
+// stringSlice converts a JSON decoded list to strings
+func stringSlice(v interface{}) []string {
+	switch list := v.(type) {
+	case []string:
+		return list
+	case []interface{}:
+		out := make([]string, 0, len(list))
+		for _, e := range list {
+			if s, ok := e.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
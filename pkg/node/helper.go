@@ -5,6 +5,7 @@ package node
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -16,6 +17,7 @@ import (
 	"github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/cli/pkg/ux"
 	"github.com/luxfi/constants"
+	"github.com/luxfi/ids"
 	"github.com/luxfi/sdk/models"
 	sdkutils "github.com/luxfi/utils"
 )
@@ -202,6 +204,35 @@ func ParseLuxdOutput(byteValue []byte) (string, uint32, error) {
 	return nodeVersionReply.VMVersions["platform"], uint32(nodeVersionReply.RPCProtocolVersion), nil
 }
 
+// ParseLuxdVersion returns the node version, e.g. luxd/1.23.5, of an
+// info.getNodeVersion reply
+func ParseLuxdVersion(byteValue []byte) (string, error) {
+	var reply struct {
+		Result apiinfo.GetNodeVersionReply `json:"result"`
+	}
+	if err := json.Unmarshal(byteValue, &reply); err != nil {
+		return "", err
+	}
+	if reply.Result.Version == "" {
+		return "", errors.New("unable to parse node version")
+	}
+	return reply.Result.Version, nil
+}
+
+// ParseNodeID returns the NodeID of an info.getNodeID reply
+func ParseNodeID(byteValue []byte) (ids.NodeID, error) {
+	var reply struct {
+		Result apiinfo.GetNodeIDReply `json:"result"`
+	}
+	if err := json.Unmarshal(byteValue, &reply); err != nil {
+		return ids.EmptyNodeID, err
+	}
+	if reply.Result.NodeID == ids.EmptyNodeID {
+		return ids.EmptyNodeID, errors.New("unable to parse node ID")
+	}
+	return reply.Result.NodeID, nil
+}
+
 func DisconnectHosts(hosts []*models.Host) {
 	for _, host := range hosts {
 		_ = host.Disconnect()
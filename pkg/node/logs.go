@@ -0,0 +1,92 @@
+// Copyright (C) 2022-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/ansible"
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/nodelogs"
+	"github.com/luxfi/sdk/models"
+)
+
+// RemoteLogsDir is where luxd writes its logs on the nodes of a cloud
+// cluster
+const RemoteLogsDir = "/home/ubuntu/.luxd/logs"
+
+// ClusterLogSources returns the network of clusterName and the log sources
+// of its nodes, read over ssh. disconnect closes the ssh connections.
+func ClusterLogSources(app *application.Lux, clusterName string) (models.Network, []nodelogs.Source, func(), error) {
+	if err := CheckCluster(app, clusterName); err != nil {
+		return models.UndefinedNetwork, nil, nil, err
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return models.UndefinedNetwork, nil, nil, err
+	}
+	networkStr, _ := clusterConfig["network"].(string)
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return models.UndefinedNetwork, nil, nil, err
+	}
+	sources := make([]nodelogs.Source, 0, len(hosts))
+	for _, host := range hosts {
+		sources = append(sources, nodelogs.Source{
+			Node:     host.GetCloudID(),
+			Location: time.UTC,
+			Tail:     remoteTail(host),
+		})
+	}
+	return models.NetworkFromString(networkStr), sources, func() { DisconnectHosts(hosts) }, nil
+}
+
+// remoteTail tails the logs of host by running tail over ssh
+func remoteTail(host *models.Host) nodelogs.Tail {
+	return func(ctx context.Context, files []string, lines int, follow bool, w io.Writer) error {
+		command, err := nodelogs.TailCommand(RemoteLogsDir, files, lines, follow)
+		if err != nil {
+			return err
+		}
+		return streamCommand(ctx, host, command, w)
+	}
+}
+
+// streamCommand runs command on host, copying its output to w as it comes
+// until it exits or ctx is done
+func streamCommand(ctx context.Context, host *models.Host, command string, w io.Writer) error {
+	if !host.Connected() {
+		if err := host.Connect(0); err != nil {
+			return err
+		}
+	}
+	session, err := host.Connection.NewSession()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = session.Close() }()
+	var stderr bytes.Buffer
+	session.Stdout = w
+	session.Stderr = &stderr
+	if err := session.Start(command); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- session.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return nil
+	case <-ctx.Done():
+		// closing the session hangs up the remote tail
+		return nil
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/luxfi/cli/pkg/ansible"
 	"github.com/luxfi/cli/pkg/application"
 	"github.com/luxfi/cli/pkg/chain"
+	"github.com/luxfi/cli/pkg/keychain"
 	"github.com/luxfi/cli/pkg/ssh"
 	"github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/cli/pkg/ux"
@@ -108,6 +109,16 @@ func trackChain(
 	}
 	blockchainID := sc.Networks[network.Name()].BlockchainID
 
+	// the hosts only get references to the secrets of the chain configs
+	files, err := ChainConfigFiles(app, network, blockchainName)
+	if err != nil {
+		return err
+	}
+	kms := keychain.NewKMSClientFromEnv()
+	if err := storeConfigSecrets(files, kms); err != nil {
+		return err
+	}
+
 	wg := sync.WaitGroup{}
 	wgResults := models.NodeResults{}
 	for _, host := range hosts {
@@ -144,7 +155,7 @@ func trackChain(
 			); err != nil {
 				nodeResults.AddResult(host.NodeID, nil, err)
 			}
-			if err := ssh.RunSSHSyncChainData(app, host, network, blockchainName); err != nil {
+			if err := ssh.RunSSHSyncChainData(app, host, network, blockchainName, kms); err != nil {
 				nodeResults.AddResult(host.NodeID, nil, err)
 			}
 			if err := ssh.RunSSHStartNode(host); err != nil {
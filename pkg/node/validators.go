@@ -0,0 +1,80 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/sdk/models"
+)
+
+// ChainValidator is a validator node of a cluster tracking a chain
+type ChainValidator struct {
+	Cluster string
+	CloudID string
+	Region  string
+}
+
+// GetChainValidators returns the validator nodes of the clusters of network
+// tracking chainName, and whether every one of these clusters is monitored
+func GetChainValidators(app *application.Lux, network models.Network, chainName string) ([]ChainValidator, bool, error) {
+	if !app.ClustersConfigExists() {
+		return nil, false, nil
+	}
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return nil, false, err
+	}
+	clusters, _ := clustersConfig["clusters"].(map[string]interface{})
+	names := make([]string, 0, len(clusters))
+	for name := range clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var validators []ChainValidator
+	tracking, unmonitored := 0, 0
+	for _, clusterName := range names {
+		clusterConfig, err := app.GetClusterConfig(clusterName)
+		if err != nil {
+			continue
+		}
+		networkStr, _ := clusterConfig["network"].(string)
+		if models.NetworkFromString(networkStr) != network || !slices.Contains(stringSlice(clusterConfig["chains"]), chainName) {
+			continue
+		}
+		tracking++
+		cloudIDs, err := GetClusterNodes(app, clusterName)
+		if err != nil {
+			return nil, false, err
+		}
+		apiNodes := stringSlice(clusterConfig["apiNodes"])
+		for _, cloudID := range cloudIDs {
+			if slices.Contains(apiNodes, cloudID) {
+				continue
+			}
+			v := ChainValidator{Cluster: clusterName, CloudID: cloudID}
+			if nodeConfig, err := app.LoadClusterNodeConfig(clusterName, cloudID); err == nil {
+				v.Region, _ = nodeConfig["region"].(string)
+			}
+			validators = append(validators, v)
+		}
+		monitoringInstance, _ := clusterConfig["monitoringInstance"].(string)
+		if monitoringInstance == "" && !clusterMonitoringConfigured(app, clusterName) {
+			unmonitored++
+		}
+	}
+	return validators, tracking > 0 && unmonitored == 0, nil
+}
+
+// clusterMonitoringConfigured tells whether the monitoring of clusterName
+// was set up locally (~/.lux/monitoring/<cluster>)
+func clusterMonitoringConfigured(app *application.Lux, clusterName string) bool {
+	_, err := os.Stat(filepath.Join(app.GetBaseDir(), "monitoring", clusterName, "config.json"))
+	return err == nil
+}
@@ -0,0 +1,181 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package nodeidentity backs up the staking identity of nodes: the TLS cert
+// and key their NodeID derives from, and the BLS key they sign with. Losing
+// them loses the NodeID, and with it the stake of the node.
+package nodeidentity
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/ids"
+)
+
+// Format marks identity backups
+const Format = "lux-node-identity"
+
+// Files are the names of the staking files of a node, as luxd and the cloud
+// nodes of the CLI name them
+type Files struct {
+	Cert   string
+	Key    string
+	BLSKey string
+}
+
+// LocalFiles are the staking files of a luxd run on this machine, in
+// ~/.luxd/staking
+var LocalFiles = Files{Cert: "staker.crt", Key: "staker.key", BLSKey: "signer.key"}
+
+var ErrNotFound = errors.New("identity not found in backup")
+
+// Identity is the staking identity of a node
+type Identity struct {
+	NodeID ids.NodeID `json:"nodeID"`
+	// Node is the name of the node the identity was backed up from
+	Node       string `json:"node"`
+	StakerCert []byte `json:"stakerCert"`
+	StakerKey  []byte `json:"stakerKey"`
+	BLSKey     []byte `json:"blsKey"`
+}
+
+// New returns the identity of node, checking the key is that of the cert
+// and the BLS key is valid
+func New(node string, cert, key, blsKey []byte) (*Identity, error) {
+	i := &Identity{Node: node, StakerCert: cert, StakerKey: key, BLSKey: blsKey}
+	nodeID, err := i.derive()
+	if err != nil {
+		return nil, err
+	}
+	i.NodeID = nodeID
+	return i, nil
+}
+
+// Read returns the identity of node from the staking files of dir
+func Read(node, dir string, files Files) (*Identity, error) {
+	contents := make([][]byte, 0, 3)
+	for _, name := range []string{files.Cert, files.Key, files.BLSKey} {
+		b, err := os.ReadFile(filepath.Join(dir, name)) //nolint:gosec // G304: Reading the staking files of the node
+		if err != nil {
+			return nil, err
+		}
+		contents = append(contents, b)
+	}
+	return New(node, contents[0], contents[1], contents[2])
+}
+
+// Write writes the staking files of i to dir, readable only by their owner
+func (i *Identity) Write(dir string, files Files) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	for name, b := range map[string][]byte{files.Cert: i.StakerCert, files.Key: i.StakerKey, files.BLSKey: i.BLSKey} {
+		if err := os.WriteFile(filepath.Join(dir, name), b, 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Verify checks the files of i still derive its NodeID
+func (i *Identity) Verify() error {
+	nodeID, err := i.derive()
+	if err != nil {
+		return err
+	}
+	if nodeID != i.NodeID {
+		return fmt.Errorf("identity of %s derives NodeID %s, not %s", i.Node, nodeID, i.NodeID)
+	}
+	return nil
+}
+
+func (i *Identity) derive() (ids.NodeID, error) {
+	nodeID, err := utils.ToNodeID(i.StakerCert)
+	if err != nil {
+		return ids.EmptyNodeID, fmt.Errorf("invalid staking cert of %s: %w", i.Node, err)
+	}
+	if _, err := tls.X509KeyPair(i.StakerCert, i.StakerKey); err != nil {
+		return ids.EmptyNodeID, fmt.Errorf("staking key of %s doesn't match its cert: %w", i.Node, err)
+	}
+	if _, _, err := utils.ToBLSPoP(i.BLSKey); err != nil {
+		return ids.EmptyNodeID, fmt.Errorf("invalid BLS key of %s: %w", i.Node, err)
+	}
+	return nodeID, nil
+}
+
+// Backup is the identities of the nodes of a cluster, or of the local node
+type Backup struct {
+	Format  string    `json:"format"`
+	Created time.Time `json:"created"`
+	Host    string    `json:"host"`
+	// Source is the cluster backed up, or local
+	Source     string     `json:"source"`
+	Identities []Identity `json:"identities"`
+}
+
+// Marshal returns the backup of identities of source
+func Marshal(source string, identities []Identity) ([]byte, error) {
+	host, _ := os.Hostname()
+	sorted := append([]Identity(nil), identities...)
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a].Node < sorted[b].Node })
+	return json.MarshalIndent(Backup{
+		Format:     Format,
+		Created:    time.Now().UTC(),
+		Host:       host,
+		Source:     source,
+		Identities: sorted,
+	}, "", "  ")
+}
+
+// Unmarshal returns the backup of data, checking each identity derives its
+// NodeID
+func Unmarshal(data []byte) (*Backup, error) {
+	var b Backup
+	if err := json.Unmarshal(data, &b); err != nil || b.Format != Format {
+		return nil, errors.New("not a node identity backup")
+	}
+	if len(b.Identities) == 0 {
+		return nil, errors.New("node identity backup holds no identity")
+	}
+	for i := range b.Identities {
+		if err := b.Identities[i].Verify(); err != nil {
+			return nil, err
+		}
+	}
+	return &b, nil
+}
+
+// Find returns the identity of nodeID, or the only identity of b when
+// nodeID is empty
+func (b *Backup) Find(nodeID string) (*Identity, error) {
+	if nodeID == "" {
+		if len(b.Identities) > 1 {
+			return nil, fmt.Errorf("backup holds %d identities, pick one of %s", len(b.Identities), strings.Join(b.NodeIDs(), ", "))
+		}
+		return &b.Identities[0], nil
+	}
+	for i := range b.Identities {
+		if b.Identities[i].NodeID.String() == nodeID {
+			return &b.Identities[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, nodeID)
+}
+
+// NodeIDs returns the NodeIDs of the identities of b
+func (b *Backup) NodeIDs() []string {
+	nodeIDs := make([]string, 0, len(b.Identities))
+	for _, i := range b.Identities {
+		nodeIDs = append(nodeIDs, i.NodeID.String())
+	}
+	return nodeIDs
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nodeidentity
+
+import (
+	"testing"
+
+	"github.com/luxfi/cli/pkg/utils"
+	luxtls "github.com/luxfi/tls"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIdentity(t *testing.T, node string) *Identity {
+	cert, key, err := luxtls.NewCertAndKeyBytes()
+	require.NoError(t, err)
+	blsKey, err := utils.NewBlsSecretKeyBytes()
+	require.NoError(t, err)
+	i, err := New(node, cert, key, blsKey)
+	require.NoError(t, err)
+	return i
+}
+
+func TestIdentity(t *testing.T) {
+	require := require.New(t)
+	i := newTestIdentity(t, "node1")
+	nodeID, err := utils.ToNodeID(i.StakerCert)
+	require.NoError(err)
+	require.Equal(nodeID, i.NodeID)
+
+	// written and read back, the files derive the same NodeID
+	dir := t.TempDir()
+	require.NoError(i.Write(dir, LocalFiles))
+	read, err := Read("node1", dir, LocalFiles)
+	require.NoError(err)
+	require.Equal(i, read)
+
+	// the key of another identity doesn't match the cert
+	other := newTestIdentity(t, "node2")
+	_, err = New("node1", i.StakerCert, other.StakerKey, i.BLSKey)
+	require.ErrorContains(err, "doesn't match its cert")
+	_, err = New("node1", i.StakerCert, i.StakerKey, []byte("bad"))
+	require.ErrorContains(err, "invalid BLS key")
+
+	swapped := *i
+	swapped.StakerCert, swapped.StakerKey = other.StakerCert, other.StakerKey
+	require.ErrorContains(swapped.Verify(), "not "+i.NodeID.String())
+}
+
+func TestBackup(t *testing.T) {
+	require := require.New(t)
+	node1 := newTestIdentity(t, "node1")
+	node2 := newTestIdentity(t, "node2")
+	data, err := Marshal("my-cluster", []Identity{*node2, *node1})
+	require.NoError(err)
+
+	b, err := Unmarshal(data)
+	require.NoError(err)
+	require.Equal("my-cluster", b.Source)
+	require.Equal([]string{node1.NodeID.String(), node2.NodeID.String()}, b.NodeIDs())
+	found, err := b.Find(node2.NodeID.String())
+	require.NoError(err)
+	require.Equal(node2, found)
+	_, err = b.Find("")
+	require.ErrorContains(err, "holds 2 identities")
+	_, err = b.Find("NodeID-111111111111111111116DBWJs")
+	require.ErrorIs(err, ErrNotFound)
+
+	single, err := Marshal("local", []Identity{*node1})
+	require.NoError(err)
+	b, err = Unmarshal(single)
+	require.NoError(err)
+	found, err = b.Find("")
+	require.NoError(err)
+	require.Equal(node1.NodeID, found.NodeID)
+
+	_, err = Unmarshal([]byte(`{"format":"other"}`))
+	require.Error(err)
+}
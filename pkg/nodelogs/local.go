@@ -0,0 +1,153 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nodelogs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// pollInterval is how often followed local files are checked for new lines
+const pollInterval = 250 * time.Millisecond
+
+// LocalTail tails the log files in dir, the logs directory of a local node
+func LocalTail(dir string) Tail {
+	return func(ctx context.Context, files []string, lines int, follow bool, w io.Writer) error {
+		paths, err := glob(dir, files)
+		if err != nil {
+			return err
+		}
+		if len(paths) == 0 && !follow {
+			return fmt.Errorf("no log files in %s", dir)
+		}
+		offsets := map[string]int64{}
+		current := ""
+		for _, p := range paths {
+			data, err := os.ReadFile(p) //nolint:gosec // G304: Reading the logs of a local node
+			if err != nil {
+				return err
+			}
+			// a partial last line is read once complete
+			data = data[:bytes.LastIndexByte(data, '\n')+1]
+			offsets[p] = int64(len(data))
+			if err := writeFile(w, p, &current, lastLines(data, lines)); err != nil {
+				return err
+			}
+		}
+		if !follow {
+			return nil
+		}
+
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+			// files created since, e.g. by a new chain or a rotation, are
+			// read from their start
+			if paths, err = glob(dir, files); err != nil {
+				return err
+			}
+			for _, p := range paths {
+				data, offset, err := readFrom(p, offsets[p])
+				if err != nil {
+					continue
+				}
+				offsets[p] = offset
+				if err := writeFile(w, p, &current, data); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// writeFile writes data of the file p to w, preceded by a tail -v header
+// when the file differs from the current one
+func writeFile(w io.Writer, p string, current *string, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if p != *current {
+		if _, err := fmt.Fprintf(w, "==> %s <==\n", p); err != nil {
+			return err
+		}
+		*current = p
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// readFrom returns the complete lines of p after offset and the offset
+// after them, reading from the start when p was truncated
+func readFrom(p string, offset int64) ([]byte, int64, error) {
+	f, err := os.Open(p) //nolint:gosec // G304: Reading the logs of a local node
+	if err != nil {
+		return nil, offset, err
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, offset, err
+	}
+	if info.Size() < offset {
+		offset = 0
+	}
+	if info.Size() == offset {
+		return nil, offset, nil
+	}
+	data := make([]byte, info.Size()-offset)
+	n, err := f.ReadAt(data, offset)
+	if err != nil && err != io.EOF {
+		return nil, offset, err
+	}
+	data = data[:bytes.LastIndexByte(data[:n], '\n')+1]
+	return data, offset + int64(len(data)), nil
+}
+
+// lastLines returns the last n lines of data, all of them when n is 0
+func lastLines(data []byte, n int) []byte {
+	if n <= 0 {
+		return data
+	}
+	end := len(data)
+	for i := len(data) - 2; i >= 0; i-- {
+		if data[i] == '\n' {
+			n--
+			if n == 0 {
+				return data[i+1 : end]
+			}
+		}
+	}
+	return data
+}
+
+// glob returns the paths of the files matching patterns in dir
+func glob(dir string, patterns []string) ([]string, error) {
+	seen := map[string]bool{}
+	var paths []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			if !seen[m] {
+				seen[m] = true
+				paths = append(paths, m)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
@@ -0,0 +1,236 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package nodelogs streams and merges the luxd and chain logs of several
+// nodes, local or remote. A node's logs are read as the output of tail -v,
+// which either runs on the node over ssh or is emulated for local files.
+package nodelogs
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLines is how many recent lines of each log file are shown
+const DefaultLines = 100
+
+// AllFiles selects every log file of a node
+const AllFiles = "*.log"
+
+var (
+	headerRegEx = regexp.MustCompile(`^==> (.+) <==$`)
+	// luxd stamps lines as [01-02|15:04:05.000], without a year
+	luxdTimeRegEx = regexp.MustCompile(`\[(\d{2}-\d{2}\|\d{2}:\d{2}:\d{2}\.\d{3})\]`)
+	isoTimeRegEx  = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`)
+	fileRegEx     = regexp.MustCompile(`^[A-Za-z0-9._*-]+$`)
+)
+
+// timePrefix is how far into a line its timestamp is looked for
+const timePrefix = 64
+
+// Tail writes the last lines of the files of a node's logs to w in the
+// format of tail -v, then follows them until ctx is done when follow is set.
+// files are base names or globs; lines 0 writes the files in full.
+type Tail func(ctx context.Context, files []string, lines int, follow bool, w io.Writer) error
+
+// Source is the logs of a node
+type Source struct {
+	Node string
+	// Location is the time zone of the timestamps of the logs
+	Location *time.Location
+	Tail     Tail
+}
+
+// Line is a line of the logs of a node
+type Line struct {
+	Node string
+	// File is the base name of the log file, e.g. C.log
+	File string
+	// Time is the timestamp of the line, or of the last stamped line before
+	// it; zero when unknown
+	Time time.Time
+	Text string
+}
+
+// Options select the lines streamed
+type Options struct {
+	// Files are the base names or globs of the log files, AllFiles when empty
+	Files []string
+	// Lines is how many recent lines of each file are read, all when 0
+	Lines  int
+	Follow bool
+	// Since drops the lines stamped before it when set
+	Since time.Time
+	// Match drops the lines it doesn't match when set
+	Match *regexp.Regexp
+}
+
+func (o Options) keep(l Line) bool {
+	if !o.Since.IsZero() && !l.Time.IsZero() && l.Time.Before(o.Since) {
+		return false
+	}
+	return o.Match == nil || o.Match.MatchString(l.Text)
+}
+
+// Stream reads the logs of sources concurrently and calls emit with the
+// lines kept by opts. Without Follow the lines of all nodes are merged in
+// the order of their timestamps once read; with Follow they are emitted as
+// they arrive until ctx is done. The failure of a node doesn't stop the
+// others and is returned at the end.
+func Stream(ctx context.Context, sources []Source, opts Options, emit func(Line)) error {
+	files := opts.Files
+	if len(files) == 0 {
+		files = []string{AllFiles}
+	}
+	var (
+		mu    sync.Mutex
+		lines []Line
+		errs  = make([]error, len(sources))
+		wg    sync.WaitGroup
+	)
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, source Source) {
+			defer wg.Done()
+			r, w := io.Pipe()
+			go func() {
+				_ = w.CloseWithError(source.Tail(ctx, files, opts.Lines, opts.Follow, w))
+			}()
+			errs[i] = Parse(r, source.Node, source.Location, time.Now(), func(l Line) {
+				if !opts.keep(l) {
+					return
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				if opts.Follow {
+					emit(l)
+				} else {
+					lines = append(lines, l)
+				}
+			})
+			// unblock the tail when parsing stopped early
+			_ = r.Close()
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("%s: %w", source.Node, errs[i])
+			}
+		}(i, source)
+	}
+	wg.Wait()
+	if !opts.Follow {
+		Merge(lines)
+		for _, l := range lines {
+			emit(l)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Merge sorts lines by timestamp, keeping the order of the lines of a same
+// time and of the lines without one
+func Merge(lines []Line) {
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Time.Before(lines[j].Time)
+	})
+}
+
+// Parse reads the output of tail -v of the logs of node from r and calls
+// emit with each of its lines. Timestamps without a year are placed in the
+// year before now when they would be more than a day after it.
+func Parse(r io.Reader, node string, loc *time.Location, now time.Time, emit func(Line)) error {
+	if loc == nil {
+		loc = time.Local
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	file := ""
+	var last time.Time
+	for scanner.Scan() {
+		text := scanner.Text()
+		if text == "" {
+			continue
+		}
+		if m := headerRegEx.FindStringSubmatch(text); m != nil {
+			file = path.Base(filepath.ToSlash(m[1]))
+			last = time.Time{}
+			continue
+		}
+		if t, ok := ParseTime(text, loc, now); ok {
+			last = t
+		}
+		emit(Line{Node: node, File: file, Time: last, Text: text})
+	}
+	return scanner.Err()
+}
+
+// ParseTime returns the timestamp near the start of a log line
+func ParseTime(line string, loc *time.Location, now time.Time) (time.Time, bool) {
+	if len(line) > timePrefix {
+		line = line[:timePrefix]
+	}
+	if m := luxdTimeRegEx.FindStringSubmatch(line); m != nil {
+		t, err := time.ParseInLocation("01-02|15:04:05.000", m[1], loc)
+		if err != nil {
+			return time.Time{}, false
+		}
+		t = time.Date(now.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+		if t.After(now.Add(24 * time.Hour)) {
+			t = t.AddDate(-1, 0, 0)
+		}
+		return t, true
+	}
+	if m := isoTimeRegEx.FindString(line); m != "" {
+		t, err := time.Parse(time.RFC3339Nano, m)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+// ParseSince parses a --since value: a duration before now, e.g. 10m, or
+// an RFC 3339 time
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("invalid since %q: negative duration", s)
+		}
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since %q: expected a duration such as 10m or an RFC 3339 time", s)
+	}
+	return t, nil
+}
+
+// TailCommand returns the shell command tailing files in dir on a node
+func TailCommand(dir string, files []string, lines int, follow bool) (string, error) {
+	if len(files) == 0 {
+		files = []string{AllFiles}
+	}
+	for _, f := range files {
+		if !fileRegEx.MatchString(f) {
+			return "", fmt.Errorf("invalid log file name %q", f)
+		}
+	}
+	n := strconv.Itoa(lines)
+	if lines == 0 {
+		n = "+1"
+	}
+	flags := "-v -n " + n
+	if follow {
+		flags += " -F"
+	}
+	// missing files are skipped rather than failing the others
+	return fmt.Sprintf("cd '%s' && { tail %s -- %s 2>/dev/null || true; }",
+		strings.ReplaceAll(dir, "'", `'\''`), flags, strings.Join(files, " ")), nil
+}
@@ -0,0 +1,161 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nodelogs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTime(t *testing.T) {
+	require := require.New(t)
+	now := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	ts, ok := ParseTime("[01-02|11:59:58.250] INFO <C Chain> block accepted", time.UTC, now)
+	require.True(ok)
+	require.Equal(time.Date(2025, 1, 2, 11, 59, 58, 250_000_000, time.UTC), ts)
+
+	// a December line read in January is from the year before
+	ts, ok = ParseTime("INFO [12-31|23:00:00.000] msg", time.UTC, now)
+	require.True(ok)
+	require.Equal(2024, ts.Year())
+
+	ts, ok = ParseTime(`{"level":"info","ts":"2025-01-02T10:00:00.5Z","msg":"x"}`, time.UTC, now)
+	require.True(ok)
+	require.Equal(time.Date(2025, 1, 2, 10, 0, 0, 500_000_000, time.UTC), ts)
+
+	_, ok = ParseTime("goroutine 1 [running]:", time.UTC, now)
+	require.False(ok)
+
+	since, err := ParseSince("10m", now)
+	require.NoError(err)
+	require.Equal(now.Add(-10*time.Minute), since)
+	_, err = ParseSince("yesterday", now)
+	require.Error(err)
+}
+
+func TestParse(t *testing.T) {
+	require := require.New(t)
+	now := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	out := `==> /logs/main.log <==
+[01-02|10:00:00.000] INFO starting
+
+==> C.log <==
+[01-02|10:00:01.000] ERROR failed
+goroutine 1 [running]:
+`
+	var lines []Line
+	require.NoError(Parse(strings.NewReader(out), "node1", time.UTC, now, func(l Line) {
+		lines = append(lines, l)
+	}))
+	require.Len(lines, 3)
+	require.Equal("main.log", lines[0].File)
+	require.Equal("C.log", lines[2].File)
+	// an unstamped line takes the time of the line before
+	require.Equal(lines[1].Time, lines[2].Time)
+	require.Equal("node1", lines[2].Node)
+}
+
+func TestTailCommand(t *testing.T) {
+	require := require.New(t)
+	cmd, err := TailCommand("/home/ubuntu/.luxd/logs", []string{"main.log", "C.log"}, 50, true)
+	require.NoError(err)
+	require.Equal("cd '/home/ubuntu/.luxd/logs' && { tail -v -n 50 -F -- main.log C.log 2>/dev/null || true; }", cmd)
+
+	cmd, err = TailCommand("/logs", nil, 0, false)
+	require.NoError(err)
+	require.Contains(cmd, "tail -v -n +1 -- *.log")
+
+	_, err = TailCommand("/logs", []string{"x; rm -rf /"}, 1, false)
+	require.ErrorContains(err, "invalid log file name")
+}
+
+func writeLog(t *testing.T, path string, lines ...string) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(strings.Join(lines, "\n") + "\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
+
+func TestStream(t *testing.T) {
+	require := require.New(t)
+	dir1, dir2 := t.TempDir(), t.TempDir()
+	writeLog(t, filepath.Join(dir1, "main.log"),
+		"[01-02|10:00:00.000] INFO a",
+		"[01-02|10:00:02.000] INFO c",
+		"[01-02|10:00:04.000] WARN e",
+	)
+	writeLog(t, filepath.Join(dir2, "C.log"),
+		"[01-02|10:00:01.000] INFO b",
+		"[01-02|10:00:03.000] WARN d",
+	)
+	sources := []Source{
+		{Node: "node1", Location: time.UTC, Tail: LocalTail(dir1)},
+		{Node: "node2", Location: time.UTC, Tail: LocalTail(dir2)},
+	}
+
+	var texts []string
+	require.NoError(Stream(context.Background(), sources, Options{}, func(l Line) {
+		texts = append(texts, l.Node+" "+l.Text[len(l.Text)-1:])
+	}))
+	require.Equal([]string{"node1 a", "node2 b", "node1 c", "node2 d", "node1 e"}, texts)
+
+	texts = nil
+	require.NoError(Stream(context.Background(), sources, Options{Lines: 1, Match: regexp.MustCompile("WARN")}, func(l Line) {
+		texts = append(texts, l.Text)
+	}))
+	require.Equal([]string{"[01-02|10:00:03.000] WARN d", "[01-02|10:00:04.000] WARN e"}, texts)
+
+	sources = append(sources, Source{Node: "node3", Tail: LocalTail(t.TempDir())})
+	err := Stream(context.Background(), sources, Options{}, func(Line) {})
+	require.ErrorContains(err, "node3: no log files")
+}
+
+func TestStreamFollow(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	log := filepath.Join(dir, "main.log")
+	writeLog(t, log, "[01-02|10:00:00.000] INFO old")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var (
+		mu    sync.Mutex
+		texts []string
+	)
+	done := make(chan error, 1)
+	go func() {
+		done <- Stream(ctx, []Source{{Node: "node1", Tail: LocalTail(dir)}}, Options{Follow: true}, func(l Line) {
+			mu.Lock()
+			defer mu.Unlock()
+			texts = append(texts, l.File+" "+l.Text)
+		})
+	}()
+	count := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(texts)
+	}
+	require.Eventually(func() bool { return count() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	writeLog(t, log, "[01-02|10:00:05.000] INFO new")
+	require.Eventually(func() bool { return count() == 2 }, 5*time.Second, 10*time.Millisecond)
+	writeLog(t, filepath.Join(dir, "C.log"), "[01-02|10:00:06.000] INFO chain")
+	require.Eventually(func() bool { return count() == 3 }, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	require.NoError(<-done)
+	require.Equal([]string{
+		"main.log [01-02|10:00:00.000] INFO old",
+		"main.log [01-02|10:00:05.000] INFO new",
+		"C.log [01-02|10:00:06.000] INFO chain",
+	}, texts)
+}
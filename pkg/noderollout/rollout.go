@@ -0,0 +1,222 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package noderollout upgrades the luxd nodes of a cluster a few at a time:
+// each node is drained, optionally has its OS patched, is upgraded to the
+// target luxd version and must be bootstrapped and healthy again before the
+// next nodes are touched, so the cluster keeps enough nodes validating
+// throughout.
+package noderollout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrTooManyUnavailable = errors.New("more nodes are unavailable than allowed")
+
+// Status is what a node reports about itself
+type Status struct {
+	Bootstrapped bool
+	Healthy      bool
+	// Version is the luxd version the node runs, e.g. luxd/1.23.5
+	Version string
+}
+
+// Available reports whether the node is serving
+func (s Status) Available() bool {
+	return s.Bootstrapped && s.Healthy
+}
+
+// Node is a node of the cluster being upgraded
+type Node interface {
+	Name() string
+	// Drain stops the node gracefully, so it leaves validation cleanly
+	Drain(ctx context.Context) error
+	// Patch applies the pending OS updates of the drained node
+	Patch(ctx context.Context) error
+	// Upgrade installs version on the drained node and starts it
+	Upgrade(ctx context.Context, version string) error
+	Status(ctx context.Context) (Status, error)
+}
+
+// Options tune a rollout
+type Options struct {
+	// Version is the luxd version to upgrade to
+	Version string
+	// MaxUnavailable is how many nodes may be down at once
+	MaxUnavailable int
+	// PatchOS applies OS updates to every node, even the ones already
+	// running Version
+	PatchOS bool
+	// HealthTimeout bounds the wait for an upgraded node to be bootstrapped
+	// and healthy
+	HealthTimeout time.Duration
+	// PollInterval is the time between status checks
+	PollInterval time.Duration
+	// Progress, when set, reports each step of the rollout. It is called
+	// concurrently for the nodes upgraded together.
+	Progress func(node, step string)
+}
+
+// Result is how the rollout went for a node
+type Result struct {
+	Node string
+	// From is the version the node ran before
+	From    string
+	Version string
+	// Skipped is set for nodes already running the target version
+	Skipped  bool
+	Duration time.Duration
+}
+
+// Plan returns the nodes to upgrade, the unavailable ones first, and the
+// results of the ones that are already done
+func Plan(ctx context.Context, nodes []Node, opts Options) ([]Node, []Result, error) {
+	if opts.MaxUnavailable < 1 {
+		return nil, nil, fmt.Errorf("max unavailable must be at least 1, got %d", opts.MaxUnavailable)
+	}
+	var (
+		unavailable, available []Node
+		done                   []Result
+	)
+	for _, node := range nodes {
+		s, err := node.Status(ctx)
+		if err != nil || !s.Available() {
+			unavailable = append(unavailable, node)
+			continue
+		}
+		if !opts.PatchOS && SameVersion(s.Version, opts.Version) {
+			done = append(done, Result{Node: node.Name(), From: s.Version, Version: s.Version, Skipped: true})
+			continue
+		}
+		available = append(available, node)
+	}
+	if len(unavailable) > opts.MaxUnavailable {
+		names := make([]string, len(unavailable))
+		for i, node := range unavailable {
+			names[i] = node.Name()
+		}
+		return nil, nil, fmt.Errorf("%w: %s are down, at most %d may be", ErrTooManyUnavailable, strings.Join(names, ", "), opts.MaxUnavailable)
+	}
+	return append(unavailable, available...), done, nil
+}
+
+// Rollout upgrades nodes to opts.Version, opts.MaxUnavailable at a time. Nodes
+// already running it are skipped, and nodes that are down are upgraded
+// first, counting against opts.MaxUnavailable. It stops after the first
+// batch with a failing node, leaving the remaining nodes untouched.
+func Rollout(ctx context.Context, nodes []Node, opts Options) ([]Result, error) {
+	todo, results, err := Plan(ctx, nodes, opts)
+	if err != nil {
+		return nil, err
+	}
+	progress := opts.Progress
+	if progress == nil {
+		progress = func(string, string) {}
+	}
+	for len(todo) > 0 {
+		batch := todo[:min(opts.MaxUnavailable, len(todo))]
+		todo = todo[len(batch):]
+
+		var (
+			wg   sync.WaitGroup
+			mu   sync.Mutex
+			errs []error
+		)
+		for _, node := range batch {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := upgrade(ctx, node, opts, progress)
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", node.Name(), err))
+					return
+				}
+				results = append(results, result)
+			}()
+		}
+		wg.Wait()
+		if len(errs) > 0 {
+			return results, fmt.Errorf("rollout halted with %d node(s) left: %w", len(todo), errors.Join(errs...))
+		}
+	}
+	return results, nil
+}
+
+// upgrade takes a single node through the rollout steps
+func upgrade(ctx context.Context, node Node, opts Options, progress func(node, step string)) (Result, error) {
+	start := time.Now()
+	result := Result{Node: node.Name()}
+	if s, err := node.Status(ctx); err == nil {
+		result.From = s.Version
+	}
+	progress(node.Name(), "draining")
+	if err := node.Drain(ctx); err != nil {
+		return result, fmt.Errorf("failed to drain: %w", err)
+	}
+	if opts.PatchOS {
+		progress(node.Name(), "patching OS")
+		if err := node.Patch(ctx); err != nil {
+			return result, fmt.Errorf("failed to patch OS: %w", err)
+		}
+	}
+	progress(node.Name(), "upgrading luxd to "+opts.Version)
+	if err := node.Upgrade(ctx, opts.Version); err != nil {
+		return result, fmt.Errorf("failed to upgrade: %w", err)
+	}
+	progress(node.Name(), "waiting for bootstrap and health")
+	s, err := waitAvailable(ctx, node, opts)
+	if err != nil {
+		return result, err
+	}
+	if !SameVersion(s.Version, opts.Version) {
+		return result, fmt.Errorf("runs %s after the upgrade, not %s", s.Version, opts.Version)
+	}
+	result.Version = s.Version
+	result.Duration = time.Since(start)
+	progress(node.Name(), "healthy on "+s.Version)
+	return result, nil
+}
+
+// waitAvailable waits for node to be bootstrapped and healthy
+func waitAvailable(ctx context.Context, node Node, opts Options) (Status, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.HealthTimeout)
+	defer cancel()
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+	var lastErr error
+	for {
+		s, err := node.Status(ctx)
+		switch {
+		case err != nil:
+			lastErr = err
+		case !s.Bootstrapped:
+			lastErr = errors.New("not bootstrapped")
+		case !s.Healthy:
+			lastErr = errors.New("not healthy")
+		default:
+			return s, nil
+		}
+		select {
+		case <-ctx.Done():
+			return Status{}, fmt.Errorf("%w: %w", ctx.Err(), lastErr)
+		case <-ticker.C:
+		}
+	}
+}
+
+// SameVersion reports whether the node version a, e.g. luxd/1.23.5, and the
+// release b, e.g. v1.23.5, are the same
+func SameVersion(a, b string) bool {
+	normalize := func(v string) string {
+		return strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(v), "luxd/"), "v")
+	}
+	return a != "" && normalize(a) == normalize(b)
+}
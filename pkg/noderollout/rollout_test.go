@@ -0,0 +1,168 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package noderollout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// cluster tracks how many of its nodes are down at once
+type cluster struct {
+	mu          sync.Mutex
+	down        int
+	maxDown     int
+	upgradeErrs map[string]error
+}
+
+type fakeNode struct {
+	name    string
+	cluster *cluster
+	mu      sync.Mutex
+	status  Status
+	patched bool
+	drained bool
+}
+
+func (n *fakeNode) Name() string {
+	return n.name
+}
+
+func (n *fakeNode) Drain(context.Context) error {
+	n.cluster.mu.Lock()
+	defer n.cluster.mu.Unlock()
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.status.Available() {
+		n.cluster.down++
+		n.cluster.maxDown = max(n.cluster.maxDown, n.cluster.down)
+	}
+	n.drained = true
+	n.status = Status{}
+	return nil
+}
+
+func (n *fakeNode) Patch(context.Context) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.patched = true
+	return nil
+}
+
+func (n *fakeNode) Upgrade(_ context.Context, version string) error {
+	n.cluster.mu.Lock()
+	defer n.cluster.mu.Unlock()
+	if err := n.cluster.upgradeErrs[n.name]; err != nil {
+		return err
+	}
+	n.cluster.down--
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.status = Status{Bootstrapped: true, Healthy: true, Version: "luxd/" + version[1:]}
+	return nil
+}
+
+func (n *fakeNode) Status(context.Context) (Status, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.status, nil
+}
+
+func newCluster(versions ...string) (*cluster, []Node) {
+	c := &cluster{upgradeErrs: map[string]error{}}
+	nodes := make([]Node, len(versions))
+	for i, version := range versions {
+		status := Status{Bootstrapped: true, Healthy: true, Version: version}
+		if version == "" {
+			status = Status{}
+		}
+		nodes[i] = &fakeNode{name: fmt.Sprintf("node%d", i+1), cluster: c, status: status}
+	}
+	return c, nodes
+}
+
+func testOptions(maxUnavailable int) Options {
+	return Options{
+		Version:        "v1.23.6",
+		MaxUnavailable: maxUnavailable,
+		HealthTimeout:  time.Second,
+		PollInterval:   10 * time.Millisecond,
+	}
+}
+
+func TestRollout(t *testing.T) {
+	require := require.New(t)
+	c, nodes := newCluster("luxd/1.23.5", "luxd/1.23.5", "luxd/1.23.6", "luxd/1.23.5", "luxd/1.23.5")
+
+	results, err := Rollout(context.Background(), nodes, testOptions(2))
+	require.NoError(err)
+	require.Len(results, 5)
+	require.LessOrEqual(c.maxDown, 2)
+	for _, r := range results {
+		require.Equal("luxd/1.23.6", r.Version)
+		require.Equal(r.Node == "node3", r.Skipped)
+	}
+	require.False(nodes[2].(*fakeNode).drained)
+
+	// patching the OS takes every node through the rollout
+	c, nodes = newCluster("luxd/1.23.6", "luxd/1.23.6")
+	opts := testOptions(1)
+	opts.PatchOS = true
+	results, err = Rollout(context.Background(), nodes, opts)
+	require.NoError(err)
+	require.Len(results, 2)
+	require.LessOrEqual(c.maxDown, 1)
+	for _, node := range nodes {
+		require.True(node.(*fakeNode).patched)
+	}
+}
+
+func TestRolloutHalts(t *testing.T) {
+	require := require.New(t)
+	c, nodes := newCluster("luxd/1.23.5", "luxd/1.23.5", "luxd/1.23.5")
+	c.upgradeErrs["node1"] = errors.New("image not found")
+
+	results, err := Rollout(context.Background(), nodes, testOptions(1))
+	require.ErrorContains(err, "node1: failed to upgrade: image not found")
+	require.ErrorContains(err, "2 node(s) left")
+	require.Empty(results)
+	require.False(nodes[1].(*fakeNode).drained)
+	require.False(nodes[2].(*fakeNode).drained)
+}
+
+func TestPlan(t *testing.T) {
+	require := require.New(t)
+	_, nodes := newCluster("luxd/1.23.5", "", "luxd/1.23.6", "")
+
+	_, _, err := Plan(context.Background(), nodes, testOptions(1))
+	require.ErrorIs(err, ErrTooManyUnavailable)
+	require.ErrorContains(err, "node2, node4")
+
+	todo, done, err := Plan(context.Background(), nodes, testOptions(2))
+	require.NoError(err)
+	require.Len(done, 1)
+	require.Equal("node3", done[0].Node)
+	names := make([]string, len(todo))
+	for i, node := range todo {
+		names[i] = node.Name()
+	}
+	require.Equal([]string{"node2", "node4", "node1"}, names)
+
+	_, _, err = Plan(context.Background(), nodes, testOptions(0))
+	require.ErrorContains(err, "at least 1")
+}
+
+func TestSameVersion(t *testing.T) {
+	require := require.New(t)
+	require.True(SameVersion("luxd/1.23.6", "v1.23.6"))
+	require.True(SameVersion("v1.23.6", "1.23.6"))
+	require.False(SameVersion("luxd/1.23.5", "v1.23.6"))
+	require.False(SameVersion("", ""))
+}
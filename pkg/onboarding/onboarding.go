@@ -0,0 +1,363 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package onboarding packages what a third-party operator needs to run
+// validators of an L1 into an invite bundle: the node version, the chain
+// configs and genesis, the bootstrap peers, instructions, and a registration
+// template the operator fills with the NodeIDs and BLS proofs of possession
+// of their nodes and returns, for the L1 owner to register them.
+package onboarding
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/protocol/p/signer"
+)
+
+const (
+	// InviteName is the entry of a bundle describing the invite
+	InviteName = "INVITE.json"
+	// RegistrationName is the entry of a bundle the operator fills and
+	// returns
+	RegistrationName = "registration.json"
+	// InstructionsName is the entry of a bundle explaining the operator what
+	// to do
+	InstructionsName = "README.md"
+	// NodeConfigName is the entry of a bundle with the luxd config of the
+	// nodes
+	NodeConfigName = "node.json"
+	// ChainsDir is the directory of a bundle with the chain configs, by
+	// blockchain ID, as luxd reads them from its --chain-config-dir
+	ChainsDir = "chains"
+
+	formatVersion = 1
+)
+
+// ErrInvalidRegistration is returned for a registration that does not answer
+// its invite
+var ErrInvalidRegistration = errors.New("invalid validator registration")
+
+// Invite is an invitation to run nodes validating an L1
+type Invite struct {
+	Version int    `json:"version"`
+	ID      string `json:"id"`
+	L1      string `json:"l1"`
+	Network string `json:"network"`
+	// NetworkID is the ID luxd is started with, as --network-id
+	NetworkID    uint32   `json:"networkID"`
+	ChainID      string   `json:"chainID"`
+	BlockchainID string   `json:"blockchainID"`
+	VMID         string   `json:"vmID"`
+	VMVersion    string   `json:"vmVersion,omitempty"`
+	LuxdVersion  string   `json:"luxdVersion,omitempty"`
+	RPCVersion   int      `json:"rpcVersion,omitempty"`
+	BootstrapIPs []string `json:"bootstrapIPs,omitempty"`
+	BootstrapIDs []string `json:"bootstrapIDs,omitempty"`
+	// NodeCount is how many nodes the invite registers at most
+	NodeCount int `json:"nodeCount"`
+	// Weight is the weight each node is registered with
+	Weight uint64 `json:"weight"`
+	// Balance is the P-Chain balance in nLUX each node is registered with,
+	// paying its continuous fee
+	Balance uint64    `json:"balance"`
+	Created time.Time `json:"created"`
+	Expires time.Time `json:"expires"`
+
+	// Accepted are the NodeIDs registered through the invite, as recorded
+	// by the L1 owner, and not in the bundle
+	Accepted []string `json:"accepted,omitempty"`
+}
+
+// Remaining returns how many more nodes the invite registers
+func (inv *Invite) Remaining() int {
+	return inv.NodeCount - len(inv.Accepted)
+}
+
+// NodePOP is the BLS key of a node and its proof of possession, hex encoded,
+// as the info.getNodeID API of the node returns them
+type NodePOP struct {
+	PublicKey         string `json:"publicKey"`
+	ProofOfPossession string `json:"proofOfPossession"`
+}
+
+// NodeRegistration is a node of the operator to register
+type NodeRegistration struct {
+	NodeID  string  `json:"nodeID"`
+	NodePOP NodePOP `json:"nodePOP"`
+}
+
+// Registration is what the operator returns: the nodes to register for an
+// invite
+type Registration struct {
+	Invite   string             `json:"invite"`
+	L1       string             `json:"l1"`
+	Network  string             `json:"network"`
+	Operator string             `json:"operator"`
+	Nodes    []NodeRegistration `json:"nodes"`
+}
+
+// Validator is a node of a registration, with its checked BLS key
+type Validator struct {
+	NodeID ids.NodeID
+	Signer *signer.ProofOfPossession
+}
+
+// Template returns the registration the operator fills for inv, with an
+// entry per node to register
+func Template(inv *Invite) ([]byte, error) {
+	r := Registration{
+		Invite:  inv.ID,
+		L1:      inv.L1,
+		Network: inv.Network,
+		Nodes:   make([]NodeRegistration, inv.Remaining()),
+	}
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// NodeConfig returns the luxd config of the nodes of inv: the network,
+// the L1 to track and the peers to bootstrap from
+func NodeConfig(inv *Invite) ([]byte, error) {
+	config := map[string]any{
+		"network-id":   inv.NetworkID,
+		"track-chains": inv.ChainID,
+	}
+	if len(inv.BootstrapIPs) > 0 {
+		config["bootstrap-ips"] = strings.Join(inv.BootstrapIPs, ",")
+		config["bootstrap-ids"] = strings.Join(inv.BootstrapIDs, ",")
+	}
+	return json.MarshalIndent(config, "", "  ")
+}
+
+var instructions = template.Must(template.New(InstructionsName).Parse(`# Validating {{.L1}} on {{.Network}}
+
+You are invited to run {{.NodeCount}} validator node(s) of the L1 {{.L1}}.
+This invite expires on {{.Expires.Format "2006-01-02 15:04 MST"}}.
+
+    Chain ID:        {{.ChainID}}
+    Blockchain ID:   {{.BlockchainID}}
+    VM ID:           {{.VMID}}{{if .VMVersion}} (version {{.VMVersion}}){{end}}
+    luxd:            {{if .LuxdVersion}}{{.LuxdVersion}}{{else}}a release supporting RPC protocol version {{.RPCVersion}}{{end}}
+    Weight per node: {{.Weight}}
+
+## 1. Install the node
+
+Install luxd {{if .LuxdVersion}}{{.LuxdVersion}}{{else}}for RPC protocol version {{.RPCVersion}}{{end}}, and the VM binary
+as plugins/{{.VMID}} in the luxd plugin directory.
+
+## 2. Configure it
+
+Copy the chains/ directory of this bundle to the luxd chain config
+directory (--chain-config-dir, ~/.luxd/chains by default), and
+start luxd with the settings of node.json (--config-file=node.json), which
+tracks the L1{{if .BootstrapIPs}} and bootstraps from its validators{{end}}.
+genesis.json is the genesis of the chain, for reference: the node gets it
+from the P-Chain.
+
+## 3. Return your nodes
+
+Once each node is bootstrapped, get its NodeID and BLS proof of possession:
+
+    curl -s -X POST -H 'content-type: application/json' \
+      -d '{"jsonrpc":"2.0","id":1,"method":"info.getNodeID"}' \
+      http://127.0.0.1:9630/ext/info
+
+Fill registration.json with the nodeID and nodePOP of each node, one entry
+per node (remove the entries you don't use), and your contact as operator.
+Send the filled registration.json back. The L1 owner registers the nodes
+with:
+
+    lux validator accept registration.json
+
+The nodes validate {{.L1}} from then on. Keep them running: their balance on
+the P-Chain pays a continuous fee.
+`))
+
+// Instructions returns the README of the bundle of inv
+func Instructions(inv *Invite) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := instructions.Execute(&buf, inv); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Create returns the bundle of inv: a gzipped tar of the invite, the
+// instructions, the node config and the registration template, with files,
+// by name, such as the genesis and the chain configs
+func Create(inv *Invite, files map[string][]byte) ([]byte, error) {
+	inv.Version = formatVersion
+	inv.Accepted = nil
+	entries := map[string][]byte{}
+	for name, data := range files {
+		entries[name] = data
+	}
+	for name, build := range map[string]func(*Invite) ([]byte, error){
+		InviteName:       func(inv *Invite) ([]byte, error) { return json.MarshalIndent(inv, "", "  ") },
+		InstructionsName: Instructions,
+		NodeConfigName:   NodeConfig,
+		RegistrationName: Template,
+	} {
+		if _, ok := files[name]; ok {
+			return nil, fmt.Errorf("%s is reserved", name)
+		}
+		data, err := build(inv)
+		if err != nil {
+			return nil, err
+		}
+		entries[name] = data
+	}
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, name := range names {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(entries[name])),
+			ModTime:  inv.Created,
+			Typeflag: tar.TypeReg,
+		}); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(entries[name]); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ReadRegistration reads the registration an operator returned: the filled
+// registration.json, the bundle directory holding it, or a bundle archive
+func ReadRegistration(path string) (*Registration, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		path = filepath.Join(path, RegistrationName)
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: Reading the user given registration
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		if data, err = readEntry(data, RegistrationName); err != nil {
+			return nil, err
+		}
+	}
+	r := &Registration{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRegistration, err)
+	}
+	return r, nil
+}
+
+// readEntry returns the entry name of a gzipped tar
+func readEntry(archive []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = gz.Close() }()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: the bundle has no %s", ErrInvalidRegistration, name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if filepath.Clean(hdr.Name) == name {
+			return io.ReadAll(tr)
+		}
+	}
+}
+
+// Validators checks r answers inv, before it expires at now, and returns
+// its nodes. Entries left empty are skipped. Each node must have a valid
+// BLS proof of possession, and not be registered yet.
+func (r *Registration) Validators(inv *Invite, now time.Time) ([]Validator, error) {
+	if r.Invite != inv.ID || r.L1 != inv.L1 || r.Network != inv.Network {
+		return nil, fmt.Errorf("%w: it answers invite %s for %s on %s, not %s for %s on %s",
+			ErrInvalidRegistration, r.Invite, r.L1, r.Network, inv.ID, inv.L1, inv.Network)
+	}
+	if now.After(inv.Expires) {
+		return nil, fmt.Errorf("%w: invite %s expired on %s", ErrInvalidRegistration, inv.ID, inv.Expires.Format(time.RFC3339))
+	}
+	seen := map[string]bool{}
+	for _, nodeID := range inv.Accepted {
+		seen[nodeID] = true
+	}
+	var validators []Validator
+	for i, node := range r.Nodes {
+		if node == (NodeRegistration{}) {
+			continue
+		}
+		nodeID, err := ids.NodeIDFromString(strings.TrimSpace(node.NodeID))
+		if err != nil {
+			return nil, fmt.Errorf("%w: node %d: invalid NodeID %q: %w", ErrInvalidRegistration, i+1, node.NodeID, err)
+		}
+		if seen[nodeID.String()] {
+			return nil, fmt.Errorf("%w: node %s is already registered", ErrInvalidRegistration, nodeID)
+		}
+		seen[nodeID.String()] = true
+		pop := &signer.ProofOfPossession{}
+		if err := decodeHex(node.NodePOP.PublicKey, pop.PublicKey[:]); err != nil {
+			return nil, fmt.Errorf("%w: node %s: invalid BLS public key: %w", ErrInvalidRegistration, nodeID, err)
+		}
+		if err := decodeHex(node.NodePOP.ProofOfPossession, pop.ProofOfPossession[:]); err != nil {
+			return nil, fmt.Errorf("%w: node %s: invalid BLS proof of possession: %w", ErrInvalidRegistration, nodeID, err)
+		}
+		if err := pop.Verify(); err != nil {
+			return nil, fmt.Errorf("%w: node %s: %w", ErrInvalidRegistration, nodeID, err)
+		}
+		validators = append(validators, Validator{NodeID: nodeID, Signer: pop})
+	}
+	if len(validators) == 0 {
+		return nil, fmt.Errorf("%w: no node to register", ErrInvalidRegistration)
+	}
+	if len(validators) > inv.Remaining() {
+		return nil, fmt.Errorf("%w: %d nodes, while the invite registers %d more", ErrInvalidRegistration, len(validators), inv.Remaining())
+	}
+	return validators, nil
+}
+
+// decodeHex decodes s, with or without 0x prefix, to exactly fill out
+func decodeHex(s string, out []byte) error {
+	b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(s), "0x"))
+	if err != nil {
+		return err
+	}
+	if len(b) != len(out) {
+		return fmt.Errorf("expected %d bytes, got %d", len(out), len(b))
+	}
+	copy(out, b)
+	return nil
+}
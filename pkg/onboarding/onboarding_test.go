@@ -0,0 +1,135 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package onboarding
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+func testInvite() *Invite {
+	return &Invite{
+		ID:           "3f2a9c1e",
+		L1:           "mychain",
+		Network:      "Testnet",
+		NetworkID:    2,
+		ChainID:      "2Xyz",
+		BlockchainID: "2Abc",
+		VMID:         "srEXiWaHuhNyGwPUi444Tu47ZEDwxTWrbQiuD7FmgSAQ6X7Dy",
+		LuxdVersion:  "v1.23.1",
+		BootstrapIPs: []string{"203.0.113.1:9631"},
+		BootstrapIDs: []string{"NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg"},
+		NodeCount:    2,
+		Weight:       20,
+		Created:      now,
+		Expires:      now.Add(7 * 24 * time.Hour),
+	}
+}
+
+// testNode returns a node of a registration with a valid proof of
+// possession
+func testNode(t *testing.T, seed byte) NodeRegistration {
+	sk, err := bls.NewSecretKey()
+	require.NoError(t, err)
+	pk := bls.PublicKeyToCompressedBytes(sk.PublicKey())
+	pop, err := sk.SignProofOfPossession(pk)
+	require.NoError(t, err)
+	return NodeRegistration{
+		NodeID: ids.BuildTestNodeID([]byte{seed}).String(),
+		NodePOP: NodePOP{
+			PublicKey:         "0x" + hex.EncodeToString(pk),
+			ProofOfPossession: "0x" + hex.EncodeToString(bls.SignatureToBytes(pop)),
+		},
+	}
+}
+
+func TestCreateReadRegistration(t *testing.T) {
+	require := require.New(t)
+	inv := testInvite()
+	bundle, err := Create(inv, map[string][]byte{
+		"genesis.json":            []byte(`{"config":{}}`),
+		"chains/2Abc/config.json": []byte(`{}`),
+	})
+	require.NoError(err)
+
+	// the operator fills the template of the bundle and returns it
+	template, err := readEntry(bundle, RegistrationName)
+	require.NoError(err)
+	r := &Registration{}
+	require.NoError(json.Unmarshal(template, r))
+	require.Equal("3f2a9c1e", r.Invite)
+	require.Len(r.Nodes, 2)
+	config, err := readEntry(bundle, NodeConfigName)
+	require.NoError(err)
+	require.Contains(string(config), `"track-chains": "2Xyz"`)
+	readme, err := readEntry(bundle, InstructionsName)
+	require.NoError(err)
+	require.Contains(string(readme), "luxd v1.23.1")
+
+	r.Nodes[0] = testNode(t, 1)
+	r.Operator = "ops@example.com"
+	filled, err := json.Marshal(r)
+	require.NoError(err)
+	dir := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(dir, RegistrationName), filled, 0o600))
+	for _, path := range []string{dir, filepath.Join(dir, RegistrationName)} {
+		read, err := ReadRegistration(path)
+		require.NoError(err)
+		require.Equal("ops@example.com", read.Operator)
+	}
+	bundlePath := filepath.Join(dir, "bundle.tar.gz")
+	require.NoError(os.WriteFile(bundlePath, bundle, 0o600))
+	read, err := ReadRegistration(bundlePath)
+	require.NoError(err)
+	require.Equal(NodeRegistration{}, read.Nodes[0])
+
+	_, err = Create(inv, map[string][]byte{InviteName: nil})
+	require.ErrorContains(err, "reserved")
+}
+
+func TestValidators(t *testing.T) {
+	require := require.New(t)
+	inv := testInvite()
+	first, second := testNode(t, 1), testNode(t, 2)
+	r := &Registration{Invite: inv.ID, L1: inv.L1, Network: inv.Network, Nodes: []NodeRegistration{first, {}}}
+
+	validators, err := r.Validators(inv, now)
+	require.NoError(err)
+	require.Len(validators, 1)
+	require.Equal(first.NodeID, validators[0].NodeID.String())
+
+	// accepted nodes don't register again, and count against the invite
+	inv.Accepted = []string{first.NodeID}
+	_, err = r.Validators(inv, now)
+	require.ErrorContains(err, "already registered")
+	r.Nodes = []NodeRegistration{second, testNode(t, 3)}
+	_, err = r.Validators(inv, now)
+	require.ErrorContains(err, "registers 1 more")
+
+	r.Nodes = []NodeRegistration{second}
+	_, err = r.Validators(inv, inv.Expires.Add(time.Minute))
+	require.ErrorContains(err, "expired")
+
+	// a proof of possession of another key is refused
+	forged := second
+	forged.NodePOP.ProofOfPossession = testNode(t, 2).NodePOP.ProofOfPossession
+	r.Nodes = []NodeRegistration{forged}
+	_, err = r.Validators(inv, now)
+	require.ErrorIs(err, ErrInvalidRegistration)
+
+	r.Nodes = []NodeRegistration{second}
+	r.Invite = "other"
+	_, err = r.Validators(inv, now)
+	require.ErrorContains(err, "answers invite other")
+}
@@ -0,0 +1,225 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package portal serves a small web page describing a cluster: its status,
+// RPC URLs with QR codes and how to join it as a validator.
+package portal
+
+import (
+	"context"
+	"embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/status"
+	"github.com/luxfi/constants"
+	"github.com/skip2/go-qrcode"
+)
+
+//go:embed templates/index.html
+var templates embed.FS
+
+var indexTemplate = template.Must(template.ParseFS(templates, "templates/index.html"))
+
+const qrSize = 192
+
+// Endpoint is a URL shown on the portal with its QR code
+type Endpoint struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// Node is the status of a cluster node
+type Node struct {
+	NodeID    string `json:"nodeID"`
+	URL       string `json:"url"`
+	OK        bool   `json:"ok"`
+	Version   string `json:"version,omitempty"`
+	PeerCount int    `json:"peers"`
+	Uptime    string `json:"uptime,omitempty"`
+	LatencyMS int    `json:"latencyMs"`
+	LastError string `json:"error,omitempty"`
+}
+
+// Chain is the status of a chain of the cluster
+type Chain struct {
+	Alias  string `json:"alias"`
+	Kind   string `json:"kind"`
+	Height uint64 `json:"height"`
+	OK     bool   `json:"ok"`
+}
+
+// Info is everything the portal shows
+type Info struct {
+	Cluster   string     `json:"cluster"`
+	Network   string     `json:"network"`
+	Status    string     `json:"status"` // up, degraded or down
+	Nodes     []Node     `json:"nodes"`
+	Chains    []Chain    `json:"chains"`
+	Endpoints []Endpoint `json:"endpoints"`
+	Join      []string   `json:"join"`
+	Updated   time.Time  `json:"updated"`
+}
+
+// Source returns the current portal info
+type Source func(ctx context.Context) (*Info, error)
+
+// NewInfo builds the portal info of cluster from a probed network
+func NewInfo(cluster, network string, probed *status.Network) *Info {
+	info := &Info{
+		Cluster: cluster,
+		Network: network,
+		Updated: time.Now(),
+	}
+	healthy := 0
+	for _, n := range probed.Nodes {
+		if n.OK {
+			healthy++
+		}
+		info.Nodes = append(info.Nodes, Node{
+			NodeID:    n.NodeID,
+			URL:       n.HTTPURL,
+			OK:        n.OK,
+			Version:   n.Version,
+			PeerCount: n.PeerCount,
+			Uptime:    n.Uptime,
+			LatencyMS: n.LatencyMS,
+			LastError: n.LastError,
+		})
+	}
+	switch {
+	case len(probed.Nodes) > 0 && healthy == len(probed.Nodes):
+		info.Status = "up"
+	case healthy > 0:
+		info.Status = "degraded"
+	default:
+		info.Status = "down"
+	}
+	for _, c := range probed.Chains {
+		info.Chains = append(info.Chains, Chain{Alias: c.Alias, Kind: c.Kind, Height: c.Height, OK: c.RPC_OK})
+	}
+
+	// Point users at a healthy node when there is one
+	baseURL := ""
+	for _, n := range probed.Nodes {
+		if baseURL == "" || n.OK {
+			baseURL = n.HTTPURL
+		}
+		if n.OK {
+			break
+		}
+	}
+	if baseURL != "" {
+		info.Endpoints = []Endpoint{
+			{Label: "C-Chain RPC", URL: baseURL + "/ext/bc/C/rpc"},
+			{Label: "C-Chain WebSocket", URL: wsURL(baseURL) + "/ext/bc/C/ws"},
+			{Label: "Node API", URL: baseURL},
+		}
+	}
+	info.Join = joinInstructions(network, probed.Nodes)
+	return info
+}
+
+// joinInstructions explains how to connect a new validator to the cluster
+func joinInstructions(network string, nodes []status.Node) []string {
+	var ips, ids []string
+	for _, n := range nodes {
+		if !n.OK || n.NodeID == "" {
+			continue
+		}
+		host := strings.TrimPrefix(strings.TrimPrefix(n.HTTPURL, "http://"), "https://")
+		host, _, _ = strings.Cut(host, ":")
+		ips = append(ips, fmt.Sprintf("%s:%d", host, constants.LuxdP2PPort))
+		ids = append(ids, n.NodeID)
+	}
+	networkFlag := "--" + network
+	if network == "" {
+		networkFlag = ""
+	}
+	steps := []string{
+		"Install the node: lux node link --auto",
+	}
+	if len(ips) > 0 {
+		steps = append(steps, fmt.Sprintf("Start luxd with --bootstrap-ips=%s --bootstrap-ids=%s",
+			strings.Join(ips, ","), strings.Join(ids, ",")))
+	} else {
+		steps = append(steps, "Start luxd once the cluster is up, bootstrap nodes are listed here when healthy")
+	}
+	steps = append(steps,
+		fmt.Sprintf("Wait until the node is bootstrapped: curl -s http://127.0.0.1:%d/ext/health", constants.LuxdAPIPort),
+		strings.TrimSpace(fmt.Sprintf("Register it as a validator: lux primary addValidator %s --nodeID <your NodeID>", networkFlag)),
+	)
+	return steps
+}
+
+func wsURL(httpURL string) string {
+	if rest, ok := strings.CutPrefix(httpURL, "https://"); ok {
+		return "wss://" + rest
+	}
+	return "ws://" + strings.TrimPrefix(httpURL, "http://")
+}
+
+// qrDataURL renders content as an inline PNG QR code
+func qrDataURL(content string) (template.URL, error) {
+	png, err := qrcode.Encode(content, qrcode.Medium, qrSize)
+	if err != nil {
+		return "", err
+	}
+	return template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(png)), nil //nolint:gosec // G203: Generated PNG data
+}
+
+type pageEndpoint struct {
+	Endpoint
+	QR template.URL
+}
+
+type page struct {
+	*Info
+	Endpoints []pageEndpoint
+	Refresh   int
+	Error     string
+}
+
+// NewHandler serves the portal page on / and its data as JSON on /api/info.
+// The page reloads itself every refresh.
+func NewHandler(source Source, refresh time.Duration) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		p := page{Info: &Info{}, Refresh: int(refresh.Seconds())}
+		info, err := source(r.Context())
+		if err != nil {
+			p.Error = err.Error()
+		} else {
+			p.Info = info
+			for _, e := range info.Endpoints {
+				qr, err := qrDataURL(e.URL)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				p.Endpoints = append(p.Endpoints, pageEndpoint{Endpoint: e, QR: qr})
+			}
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = indexTemplate.Execute(w, p)
+	})
+	mux.HandleFunc("/api/info", func(w http.ResponseWriter, r *http.Request) {
+		info, err := source(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(info)
+	})
+	return mux
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package portal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/luxfi/cli/pkg/status"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInfo(t *testing.T) {
+	probed := &status.Network{
+		Nodes: []status.Node{
+			{HTTPURL: "http://10.0.0.1:9630", NodeID: "NodeID-A", OK: false},
+			{HTTPURL: "http://10.0.0.2:9630", NodeID: "NodeID-B", OK: true},
+		},
+		Chains: []status.ChainStatus{{Alias: "c", Kind: "evm", Height: 7, RPC_OK: true}},
+	}
+
+	info := NewInfo("demo", "testnet", probed)
+	require.Equal(t, "degraded", info.Status)
+	require.Len(t, info.Nodes, 2)
+	require.Equal(t, []Chain{{Alias: "c", Kind: "evm", Height: 7, OK: true}}, info.Chains)
+	// Endpoints point at the healthy node
+	require.Equal(t, "http://10.0.0.2:9630/ext/bc/C/rpc", info.Endpoints[0].URL)
+	require.Equal(t, "ws://10.0.0.2:9630/ext/bc/C/ws", info.Endpoints[1].URL)
+	require.Contains(t, info.Join[1], "--bootstrap-ips=10.0.0.2:9651 --bootstrap-ids=NodeID-B")
+	require.Contains(t, info.Join[3], "addValidator --testnet")
+}
+
+func TestHandler(t *testing.T) {
+	info := NewInfo("demo", "devnet", &status.Network{
+		Nodes: []status.Node{{HTTPURL: "http://10.0.0.1:9630", NodeID: "NodeID-A", OK: true}},
+	})
+	handler := NewHandler(func(context.Context) (*Info, error) { return info, nil }, 10*time.Second)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	require.Contains(t, body, "http://10.0.0.1:9630/ext/bc/C/rpc")
+	require.Contains(t, body, `src="data:image/png;base64,`)
+	require.Contains(t, body, `content="10"`)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/info", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var decoded Info
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &decoded))
+	require.Equal(t, "up", decoded.Status)
+
+	failing := NewHandler(func(context.Context) (*Info, error) { return nil, errors.New("cluster unreachable") }, 0)
+	rec = httptest.NewRecorder()
+	failing.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Contains(t, rec.Body.String(), "cluster unreachable")
+}
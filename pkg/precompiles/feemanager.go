@@ -0,0 +1,78 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompiles
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/evm/commontype"
+	"github.com/luxfi/sdk/contract"
+)
+
+const feeConfigTypes = "(uint256,uint256,uint256,uint256,uint256,uint256,uint256,uint256)"
+
+// FeeManagerGetFeeConfig returns the fee config currently set on the
+// FeeManager precompile of the chain at rpcURL
+func FeeManagerGetFeeConfig(rpcURL string) (*commontype.FeeConfig, error) {
+	out, err := contract.CallToMethod(
+		rpcURL,
+		FeeManagerPrecompile,
+		"getFeeConfig()->"+feeConfigTypes,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) != 8 {
+		return nil, fmt.Errorf("error at getFeeConfig call: expected 8 return values, got %d", len(out))
+	}
+	values := make([]*big.Int, len(out))
+	for i := range out {
+		value, ok := out[i].(*big.Int)
+		if !ok {
+			return nil, fmt.Errorf("error at getFeeConfig call, expected *big.Int, got %T", out[i])
+		}
+		values[i] = value
+	}
+	return &commontype.FeeConfig{
+		GasLimit:                 values[0],
+		TargetBlockRate:          values[1].Uint64(),
+		MinBaseFee:               values[2],
+		TargetGas:                values[3],
+		BaseFeeChangeDenominator: values[4],
+		MinBlockGasCost:          values[5],
+		MaxBlockGasCost:          values[6],
+		BlockGasCostStep:         values[7],
+	}, nil
+}
+
+// FeeManagerSetFeeConfig sets feeConfig on the FeeManager precompile of the
+// chain at rpcURL. privateKey must be enabled on the precompile allow list.
+func FeeManagerSetFeeConfig(
+	rpcURL string,
+	privateKey string,
+	feeConfig *commontype.FeeConfig,
+) error {
+	_, _, err := contract.TxToMethod(
+		rpcURL,
+		false,
+		crypto.Address{},
+		privateKey,
+		FeeManagerPrecompile,
+		nil,
+		"set fee config",
+		nil,
+		"setFeeConfig"+feeConfigTypes,
+		feeConfig.GasLimit,
+		new(big.Int).SetUint64(feeConfig.TargetBlockRate),
+		feeConfig.MinBaseFee,
+		feeConfig.TargetGas,
+		feeConfig.BaseFeeChangeDenominator,
+		feeConfig.MinBlockGasCost,
+		feeConfig.MaxBlockGasCost,
+		feeConfig.BlockGasCostStep,
+	)
+	return err
+}
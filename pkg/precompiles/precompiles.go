@@ -5,11 +5,13 @@ package precompiles
 
 import (
 	"github.com/luxfi/crypto/common"
+	"github.com/luxfi/evm/precompile/contracts/feemanager"
 	"github.com/luxfi/evm/precompile/contracts/nativeminter"
 	"github.com/luxfi/evm/precompile/contracts/warp"
 )
 
 var (
+	FeeManagerPrecompile   = common.BytesToAddress(feemanager.ContractAddress.Bytes())
 	NativeMinterPrecompile = common.BytesToAddress(nativeminter.ContractAddress.Bytes())
 	WarpPrecompile         = common.BytesToAddress(warp.ContractAddress.Bytes())
 )
@@ -0,0 +1,113 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package receipt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// KMSCountersigner countersigns receipts with a key of a Lux KMS server
+// ('lux kms server start'), which must be a sign-verify key
+type KMSCountersigner struct {
+	URL    string
+	KeyID  string
+	APIKey string
+	Client *http.Client
+}
+
+// NewKMSCountersigner returns a countersigner using keyID of the KMS at
+// kmsURL
+func NewKMSCountersigner(kmsURL, keyID, apiKey string) *KMSCountersigner {
+	return &KMSCountersigner{
+		URL:    strings.TrimSuffix(kmsURL, "/"),
+		KeyID:  keyID,
+		APIKey: apiKey,
+		Client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Countersign has the KMS sign r, which must already be signed
+func (k *KMSCountersigner) Countersign(ctx context.Context, r *Receipt) error {
+	payload, err := r.Payload()
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Signature string `json:"signature"`
+	}
+	if err := k.call(ctx, "sign", map[string]string{
+		"data": base64.StdEncoding.EncodeToString(payload),
+	}, &resp); err != nil {
+		return err
+	}
+	if resp.Signature == "" {
+		return fmt.Errorf("KMS %s returned no signature", k.URL)
+	}
+	r.Countersignature = &Countersignature{
+		KMS:      k.URL,
+		KeyID:    k.KeyID,
+		Value:    resp.Signature,
+		SignedAt: time.Now().UTC(),
+	}
+	return nil
+}
+
+// VerifyCountersignature has the KMS check the countersignature of r
+func (k *KMSCountersigner) VerifyCountersignature(ctx context.Context, r *Receipt) error {
+	if r.Countersignature == nil {
+		return fmt.Errorf("%w: receipt %s is not countersigned", ErrInvalidSignature, r.ID)
+	}
+	payload, err := r.Payload()
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		SignatureValid bool `json:"signatureValid"`
+	}
+	if err := k.call(ctx, "verify", map[string]string{
+		"data":      base64.StdEncoding.EncodeToString(payload),
+		"signature": r.Countersignature.Value,
+	}, &resp); err != nil {
+		return err
+	}
+	if !resp.SignatureValid {
+		return fmt.Errorf("%w: KMS countersignature of receipt %s does not match", ErrInvalidSignature, r.ID)
+	}
+	return nil
+}
+
+func (k *KMSCountersigner) call(ctx context.Context, action string, body any, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/v1/kms/keys/%s/%s", k.URL, url.PathEscape(k.KeyID), action)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if k.APIKey != "" {
+		req.Header.Set("X-API-Key", k.APIKey)
+	}
+	resp, err := k.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("KMS %s: %w", k.URL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("KMS %s %s returned %s: %s", k.URL, action, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
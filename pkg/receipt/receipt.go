@@ -0,0 +1,284 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package receipt issues signed receipts of mainnet operations: what ran,
+// with which parameters and keys, and which IDs it produced, signed by the
+// operator machine and optionally countersigned by a KMS, for change
+// management and compliance records.
+package receipt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/history"
+)
+
+const (
+	// DirName is the receipts directory in the base dir (~/.lux/receipts)
+	DirName = "receipts"
+
+	// SigningKeyFileName is the ed25519 key of this machine receipts are
+	// signed with, kept in the receipts directory
+	SigningKeyFileName = "signing.key"
+
+	// Algorithm is the signature algorithm of receipts
+	Algorithm = "ed25519"
+
+	formatVersion = 1
+)
+
+// Config settings of audit mode
+const (
+	// ConfigKey turns audit mode on: mainnet operations issue receipts
+	ConfigKey = "audit"
+	// KMSURLConfigKey is the KMS countersigning receipts, if any
+	KMSURLConfigKey = "audit-kms-url"
+	// KMSKeyConfigKey is the KMS key receipts are countersigned with
+	KMSKeyConfigKey = "audit-kms-key"
+	// KMSAPIKeyEnvVar holds the API key of the KMS, never stored
+	KMSAPIKeyEnvVar = "LUX_KMS_API_KEY"
+)
+
+// ErrInvalidSignature is returned for a receipt whose signature doesn't
+// match its content
+var ErrInvalidSignature = errors.New("invalid receipt signature")
+
+// Receipt is the signed record of an operation
+type Receipt struct {
+	Version   int               `json:"version"`
+	ID        string            `json:"id"`
+	Operation string            `json:"operation"`
+	Command   string            `json:"command"`
+	Network   string            `json:"network,omitempty"`
+	Chain     string            `json:"chain,omitempty"`
+	Params    map[string]string `json:"params,omitempty"`
+	// TxIDs are the transaction and resulting IDs of the operation, such as
+	// the blockchain ID of a deploy
+	TxIDs map[string]string `json:"txIDs,omitempty"`
+	// KeyFingerprints are the addresses of the keys the operation signed
+	// with
+	KeyFingerprints []string  `json:"keyFingerprints,omitempty"`
+	User            string    `json:"user"`
+	Host            string    `json:"host"`
+	OperationTime   time.Time `json:"operationTime"`
+	IssuedAt        time.Time `json:"issuedAt"`
+
+	Signature        *Signature        `json:"signature,omitempty"`
+	Countersignature *Countersignature `json:"countersignature,omitempty"`
+}
+
+// Signature is the signature of a receipt by the machine that issued it
+type Signature struct {
+	Algorithm      string `json:"algorithm"`
+	PublicKey      string `json:"publicKey"`
+	KeyFingerprint string `json:"keyFingerprint"`
+	Value          string `json:"value"`
+}
+
+// Countersignature is the signature of a receipt by a KMS key
+type Countersignature struct {
+	KMS      string    `json:"kms"`
+	KeyID    string    `json:"keyId"`
+	Value    string    `json:"value"`
+	SignedAt time.Time `json:"signedAt"`
+}
+
+// FromEntry returns the unsigned receipt of the changelog entry e, which
+// signed with keys
+func FromEntry(e history.Entry, keys []string) *Receipt {
+	return &Receipt{
+		Version:         formatVersion,
+		Operation:       e.Operation,
+		Command:         e.Command,
+		Network:         e.Network,
+		Chain:           e.Chain,
+		Params:          e.Params,
+		TxIDs:           e.Result,
+		KeyFingerprints: keys,
+		User:            e.User,
+		Host:            e.Host,
+		OperationTime:   e.Time,
+	}
+}
+
+// Payload returns the content of r that is signed: r without its
+// signatures, as JSON
+func (r *Receipt) Payload() ([]byte, error) {
+	unsigned := *r
+	unsigned.Signature = nil
+	unsigned.Countersignature = nil
+	return json.Marshal(unsigned)
+}
+
+// Sign sets the ID and issue time of r and signs it with key
+func (r *Receipt) Sign(key ed25519.PrivateKey) error {
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	r.IssuedAt = time.Now().UTC()
+	r.ID = r.IssuedAt.Format("20060102T150405Z") + "-" + hex.EncodeToString(id)
+	payload, err := r.Payload()
+	if err != nil {
+		return err
+	}
+	pub, ok := key.Public().(ed25519.PublicKey)
+	if !ok {
+		return errors.New("invalid signing key")
+	}
+	r.Signature = &Signature{
+		Algorithm:      Algorithm,
+		PublicKey:      base64.StdEncoding.EncodeToString(pub),
+		KeyFingerprint: Fingerprint(pub),
+		Value:          base64.StdEncoding.EncodeToString(ed25519.Sign(key, payload)),
+	}
+	return nil
+}
+
+// Verify checks the signature of r against the public key it carries.
+// Whether that key is trusted is up to the caller, by its fingerprint.
+func (r *Receipt) Verify() error {
+	if r.Signature == nil {
+		return fmt.Errorf("%w: receipt is not signed", ErrInvalidSignature)
+	}
+	if r.Signature.Algorithm != Algorithm {
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidSignature, r.Signature.Algorithm)
+	}
+	pub, err := base64.StdEncoding.DecodeString(r.Signature.PublicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("%w: invalid public key", ErrInvalidSignature)
+	}
+	if Fingerprint(pub) != r.Signature.KeyFingerprint {
+		return fmt.Errorf("%w: key fingerprint does not match the public key", ErrInvalidSignature)
+	}
+	sig, err := base64.StdEncoding.DecodeString(r.Signature.Value)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	payload, err := r.Payload()
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(pub, payload, sig) {
+		return fmt.Errorf("%w: receipt %s has been modified", ErrInvalidSignature, r.ID)
+	}
+	return nil
+}
+
+// Fingerprint returns the SHA-256 fingerprint of a public key
+func Fingerprint(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return "SHA256:" + hex.EncodeToString(sum[:])
+}
+
+// LoadOrCreateSigningKey returns the signing key at path, generating it on
+// first use
+func LoadOrCreateSigningKey(path string) (ed25519.PrivateKey, error) {
+	key, err := LoadSigningKey(path)
+	if errors.Is(err, os.ErrNotExist) {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return nil, err
+		}
+		pemData := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+		if err := os.WriteFile(path, pemData, 0o600); err != nil {
+			return nil, err
+		}
+		return key, nil
+	}
+	return key, err
+}
+
+// LoadSigningKey returns the signing key at path
+func LoadSigningKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: Reading the app's receipt signing key
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("invalid signing key %s", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key %s: %w", path, err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s is not an ed25519 key", path)
+	}
+	return key, nil
+}
+
+// Save writes r to dir as <id>.json and returns its path
+func Save(dir string, r *Receipt) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, r.ID+".json")
+	return path, os.WriteFile(path, data, 0o600)
+}
+
+// Load reads the receipt at path
+func Load(path string) (*Receipt, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: Reading a receipt the user names
+	if err != nil {
+		return nil, err
+	}
+	r := &Receipt{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("invalid receipt %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// List returns the receipts stored in dir, oldest first
+func List(dir string) ([]*Receipt, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	receipts := []*Receipt{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		r, err := Load(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, r)
+	}
+	sort.SliceStable(receipts, func(i, j int) bool {
+		return receipts[i].IssuedAt.Before(receipts[j].IssuedAt)
+	})
+	return receipts, nil
+}
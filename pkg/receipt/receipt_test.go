@@ -0,0 +1,137 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package receipt
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/stretchr/testify/require"
+)
+
+func testEntry() history.Entry {
+	return history.Entry{
+		Time:      time.Date(2026, time.March, 1, 10, 0, 0, 0, time.UTC),
+		User:      "ops",
+		Host:      "bastion",
+		Command:   "lux chain deploy mychain --mainnet",
+		Operation: "chain deploy",
+		Network:   "Mainnet",
+		Chain:     "mychain",
+		Params:    map[string]string{"genesisSHA256": "abc"},
+		Result:    map[string]string{"blockchainID": "2Xyz"},
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, SigningKeyFileName)
+
+	key, err := LoadOrCreateSigningKey(keyPath)
+	require.NoError(err)
+	again, err := LoadOrCreateSigningKey(keyPath)
+	require.NoError(err)
+	require.True(key.Equal(again))
+
+	r := FromEntry(testEntry(), []string{"P-lux1abc"})
+	require.NoError(r.Sign(key))
+	require.NotEmpty(r.ID)
+	require.Equal(Fingerprint(key.Public().(ed25519.PublicKey)), r.Signature.KeyFingerprint)
+	require.NoError(r.Verify())
+
+	path, err := Save(dir, r)
+	require.NoError(err)
+	loaded, err := Load(path)
+	require.NoError(err)
+	require.NoError(loaded.Verify())
+	require.Equal("2Xyz", loaded.TxIDs["blockchainID"])
+	require.Equal([]string{"P-lux1abc"}, loaded.KeyFingerprints)
+
+	receipts, err := List(dir)
+	require.NoError(err)
+	require.Len(receipts, 1)
+	require.Equal(r.ID, receipts[0].ID)
+
+	// any change to the content breaks the signature
+	loaded.TxIDs["blockchainID"] = "2Other"
+	require.ErrorIs(loaded.Verify(), ErrInvalidSignature)
+
+	// so does swapping the key for another one
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(err)
+	forged := FromEntry(testEntry(), nil)
+	require.NoError(forged.Sign(otherKey))
+	forged.Signature.KeyFingerprint = r.Signature.KeyFingerprint
+	require.ErrorIs(forged.Verify(), ErrInvalidSignature)
+}
+
+// fakeKMS signs and verifies with an ed25519 key, like a Lux KMS
+// sign-verify key
+func fakeKMS(t *testing.T, apiKey string) *httptest.Server {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != apiKey {
+			http.Error(w, `{"error":"invalid API key"}`, http.StatusUnauthorized)
+			return
+		}
+		var req struct {
+			Data      string `json:"data"`
+			Signature string `json:"signature"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data, _ := base64.StdEncoding.DecodeString(req.Data)
+		switch r.URL.Path {
+		case "/v1/kms/keys/receipts/sign":
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"signature": base64.StdEncoding.EncodeToString(ed25519.Sign(key, data)),
+			})
+		case "/v1/kms/keys/receipts/verify":
+			sig, _ := base64.StdEncoding.DecodeString(req.Signature)
+			_ = json.NewEncoder(w).Encode(map[string]bool{
+				"signatureValid": ed25519.Verify(key.Public().(ed25519.PublicKey), data, sig),
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestKMSCountersign(t *testing.T) {
+	require := require.New(t)
+	server := fakeKMS(t, "secret")
+	defer server.Close()
+	ctx := context.Background()
+
+	key, err := LoadOrCreateSigningKey(filepath.Join(t.TempDir(), SigningKeyFileName))
+	require.NoError(err)
+	r := FromEntry(testEntry(), nil)
+	require.NoError(r.Sign(key))
+
+	kms := NewKMSCountersigner(server.URL+"/", "receipts", "secret")
+	require.NoError(kms.Countersign(ctx, r))
+	require.Equal(server.URL, r.Countersignature.KMS)
+	require.Equal("receipts", r.Countersignature.KeyID)
+	require.NoError(r.Verify())
+	require.NoError(kms.VerifyCountersignature(ctx, r))
+
+	r.Chain = "otherchain"
+	require.ErrorIs(kms.VerifyCountersignature(ctx, r), ErrInvalidSignature)
+
+	unauthorized := NewKMSCountersigner(server.URL, "receipts", "wrong")
+	require.ErrorContains(unauthorized.Countersign(ctx, r), "401")
+}
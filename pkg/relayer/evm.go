@@ -0,0 +1,185 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/luxfi/crypto"
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/accounts/abi"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/ethclient"
+)
+
+// forwarderABI is the part of the OpenZeppelin ERC2771Forwarder ABI the
+// relayer calls
+const forwarderABI = `[
+	{"inputs":[{"components":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"gas","type":"uint256"},{"name":"deadline","type":"uint48"},{"name":"data","type":"bytes"},{"name":"signature","type":"bytes"}],"name":"request","type":"tuple"}],"name":"verify","outputs":[{"name":"","type":"bool"}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"components":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"gas","type":"uint256"},{"name":"deadline","type":"uint48"},{"name":"data","type":"bytes"},{"name":"signature","type":"bytes"}],"name":"request","type":"tuple"}],"name":"execute","outputs":[],"stateMutability":"payable","type":"function"}
+]`
+
+// forwardRequestData is the ABI tuple of a ForwardRequest
+type forwardRequestData struct {
+	From      common.Address
+	To        common.Address
+	Value     *big.Int
+	Gas       *big.Int
+	Deadline  *big.Int
+	Data      []byte
+	Signature []byte
+}
+
+// EVMForwarder is a trusted forwarder deployed to an EVM chain, to which
+// requests are executed with transactions signed by the relayer key
+type EVMForwarder struct {
+	// mu orders the transactions so their nonces don't collide
+	mu      sync.Mutex
+	client  *ethclient.Client
+	abi     abi.ABI
+	chainID *big.Int
+	address common.Address
+	key     *ecdsa.PrivateKey
+}
+
+// NewEVMForwarder returns the forwarder at address on the chain served at
+// rpcURL, executing requests with transactions signed by privateKey, in hex
+func NewEVMForwarder(ctx context.Context, rpcURL, address, privateKey string) (*EVMForwarder, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid forwarder address %q", address)
+	}
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(privateKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid relayer key: %w", err)
+	}
+	parsed, err := abi.JSON(strings.NewReader(forwarderABI))
+	if err != nil {
+		return nil, err
+	}
+	client, err := ethclient.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", rpcURL, err)
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	return &EVMForwarder{
+		client:  client,
+		abi:     parsed,
+		chainID: chainID,
+		address: common.HexToAddress(address),
+		key:     key,
+	}, nil
+}
+
+// Close closes the connection to the chain
+func (f *EVMForwarder) Close() {
+	f.client.Close()
+}
+
+// Address returns the address of the forwarder
+func (f *EVMForwarder) Address() string {
+	return f.address.Hex()
+}
+
+// Relayer returns the address paying for the relayed transactions
+func (f *EVMForwarder) Relayer() string {
+	return common.Address(crypto.PubkeyToAddress(f.key.PublicKey)).Hex()
+}
+
+// Verify calls the verify method of the forwarder with req
+func (f *EVMForwarder) Verify(ctx context.Context, req ForwardRequest) (bool, error) {
+	data, err := f.pack("verify", req)
+	if err != nil {
+		return false, err
+	}
+	out, err := f.client.CallContract(ctx, ethereum.CallMsg{To: &f.address, Data: data}, nil)
+	if err != nil {
+		return false, err
+	}
+	result, err := f.abi.Unpack("verify", out)
+	if err != nil {
+		return false, err
+	}
+	ok, _ := result[0].(bool)
+	return ok, nil
+}
+
+// Execute sends a transaction calling the execute method of the forwarder
+// with req
+func (f *EVMForwarder) Execute(ctx context.Context, req ForwardRequest) (string, error) {
+	data, err := f.pack("execute", req)
+	if err != nil {
+		return "", err
+	}
+	from := common.Address(crypto.PubkeyToAddress(f.key.PublicKey))
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	gas, err := f.client.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &f.address, Data: data})
+	if err != nil {
+		return "", fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	nonce, err := f.client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return "", fmt.Errorf("failed to get nonce: %w", err)
+	}
+	tipCap, err := f.client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to suggest gas tip: %w", err)
+	}
+	header, err := f.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get latest header: %w", err)
+	}
+	if header.BaseFee == nil {
+		return "", errors.New("the chain has no base fee")
+	}
+	feeCap := new(big.Int).Add(tipCap, new(big.Int).Mul(header.BaseFee, big.NewInt(2)))
+	tx, err := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+		ChainID:   f.chainID,
+		Nonce:     nonce,
+		To:        &f.address,
+		Gas:       gas,
+		GasFeeCap: feeCap,
+		GasTipCap: tipCap,
+		Data:      data,
+	}), types.LatestSignerForChainID(f.chainID), f.key)
+	if err != nil {
+		return "", err
+	}
+	if err := f.client.SendTransaction(ctx, tx); err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return tx.Hash().Hex(), nil
+}
+
+func (f *EVMForwarder) pack(method string, req ForwardRequest) ([]byte, error) {
+	data, err := decodeHex(req.Data)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := decodeHex(req.Signature)
+	if err != nil {
+		return nil, err
+	}
+	return f.abi.Pack(method, forwardRequestData{
+		From:      common.HexToAddress(req.From),
+		To:        common.HexToAddress(req.To),
+		Value:     new(big.Int),
+		Gas:       new(big.Int).SetUint64(req.Gas),
+		Deadline:  new(big.Int).SetUint64(req.Deadline),
+		Data:      data,
+		Signature: signature,
+	})
+}
@@ -0,0 +1,191 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package relayer relays meta-transactions to an ERC-2771 trusted forwarder,
+// paying for their gas so the users signing them need no tokens. Requests
+// are checked against a gas cap and, optionally, a list of allowed targets
+// before they are relayed.
+package relayer
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luxfi/geth/common"
+)
+
+// DefaultMaxGas is the largest gas of a relayed request
+const DefaultMaxGas = 1_000_000
+
+var (
+	ErrInvalidRequest   = errors.New("invalid forward request")
+	ErrTargetNotAllowed = errors.New("target not allowed")
+	ErrRelayFailed      = errors.New("failed to relay")
+)
+
+// ForwardRequest is a call signed by From for the forwarder to make to To,
+// as the ForwardRequestData of the OpenZeppelin ERC2771Forwarder
+type ForwardRequest struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Value is in wei, in decimal. The relayer pays no value, so it must
+	// be 0 or empty.
+	Value string `json:"value,omitempty"`
+	Gas   uint64 `json:"gas"`
+	// Deadline is the unix time after which the request expires
+	Deadline  uint64 `json:"deadline"`
+	Data      string `json:"data"`
+	Signature string `json:"signature"`
+}
+
+// Forwarder submits forward requests to a trusted forwarder
+type Forwarder interface {
+	// Address returns the address of the forwarder
+	Address() string
+	// Verify tells if the forwarder would execute req: its signature
+	// matches its sender and nonce, and it has not expired
+	Verify(ctx context.Context, req ForwardRequest) (bool, error)
+	// Execute sends a transaction executing req and returns its hash
+	Execute(ctx context.Context, req ForwardRequest) (string, error)
+}
+
+// Relayer relays the forward requests it accepts to its forwarder
+type Relayer struct {
+	forwarder Forwarder
+	maxGas    uint64
+	// targets are the contracts requests may call, any when empty
+	targets map[common.Address]bool
+	now     func() time.Time
+}
+
+// New returns a relayer to forwarder of the requests of at most maxGas,
+// calling one of targets, or any contract when targets is empty
+func New(forwarder Forwarder, maxGas uint64, targets []string) (*Relayer, error) {
+	r := &Relayer{forwarder: forwarder, maxGas: maxGas, targets: map[common.Address]bool{}, now: time.Now}
+	for _, target := range targets {
+		if !common.IsHexAddress(target) {
+			return nil, fmt.Errorf("invalid target address %q", target)
+		}
+		r.targets[common.HexToAddress(target)] = true
+	}
+	return r, nil
+}
+
+// Targets returns the contracts requests may call, sorted, empty when any
+func (r *Relayer) Targets() []string {
+	targets := make([]string, 0, len(r.targets))
+	for target := range r.targets {
+		targets = append(targets, target.Hex())
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// Check returns an error wrapping ErrInvalidRequest or ErrTargetNotAllowed
+// when req is not to be relayed
+func (r *Relayer) Check(req ForwardRequest) error {
+	for field, address := range map[string]string{"from": req.From, "to": req.To} {
+		if !common.IsHexAddress(address) {
+			return fmt.Errorf("%w: invalid %s address %q", ErrInvalidRequest, field, address)
+		}
+	}
+	if req.Value != "" {
+		if value, ok := new(big.Int).SetString(req.Value, 10); !ok || value.Sign() != 0 {
+			return fmt.Errorf("%w: the relayer pays no value", ErrInvalidRequest)
+		}
+	}
+	if req.Gas == 0 || req.Gas > r.maxGas {
+		return fmt.Errorf("%w: gas must be between 1 and %d", ErrInvalidRequest, r.maxGas)
+	}
+	if req.Deadline <= uint64(r.now().Unix()) {
+		return fmt.Errorf("%w: deadline passed", ErrInvalidRequest)
+	}
+	for field, value := range map[string]string{"data": req.Data, "signature": req.Signature} {
+		if _, err := decodeHex(value); err != nil {
+			return fmt.Errorf("%w: invalid %s: %w", ErrInvalidRequest, field, err)
+		}
+	}
+	if len(r.targets) > 0 && !r.targets[common.HexToAddress(req.To)] {
+		return fmt.Errorf("%w: %s", ErrTargetNotAllowed, req.To)
+	}
+	return nil
+}
+
+// Relay checks req, verifies it with the forwarder and executes it,
+// returning the hash of the transaction
+func (r *Relayer) Relay(ctx context.Context, req ForwardRequest) (string, error) {
+	if err := r.Check(req); err != nil {
+		return "", err
+	}
+	ok, err := r.forwarder.Verify(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrRelayFailed, err)
+	}
+	if !ok {
+		return "", fmt.Errorf("%w: the signature does not match the sender and nonce, or the request expired", ErrInvalidRequest)
+	}
+	txHash, err := r.forwarder.Execute(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrRelayFailed, err)
+	}
+	return txHash, nil
+}
+
+// ServeHTTP serves GET /forwarder, describing the forwarder and the
+// requests relayed, and POST /relay, relaying the request of the JSON body
+func (r *Relayer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch {
+	case req.URL.Path == "/forwarder" && req.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"forwarder": r.forwarder.Address(),
+			"maxGas":    r.maxGas,
+			"targets":   r.Targets(),
+		})
+	case req.URL.Path == "/relay" && req.Method == http.MethodPost:
+		var forward ForwardRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, 1<<20)).Decode(&forward); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		txHash, err := r.Relay(req.Context(), forward)
+		switch {
+		case errors.Is(err, ErrInvalidRequest):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, ErrTargetNotAllowed):
+			writeError(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, ErrRelayFailed):
+			writeError(w, http.StatusBadGateway, err.Error())
+		case err != nil:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		default:
+			writeJSON(w, http.StatusOK, map[string]string{"txHash": txHash})
+		}
+	default:
+		writeError(w, http.StatusNotFound, "use GET /forwarder or POST /relay")
+	}
+}
+
+func decodeHex(s string) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return nil, errors.New("expected 0x prefixed hex")
+	}
+	return hex.DecodeString(s[2:])
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
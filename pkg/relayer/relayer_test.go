@@ -0,0 +1,121 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSender = "0x9011E888251AB053B7bD1cdB598Db4f9DEd94714"
+	testTarget = "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"
+)
+
+type fakeForwarder struct {
+	valid    bool
+	err      error
+	executed []ForwardRequest
+}
+
+func (*fakeForwarder) Address() string {
+	return "0x0000000000000000000000000000000000000F0F"
+}
+
+func (f *fakeForwarder) Verify(context.Context, ForwardRequest) (bool, error) {
+	return f.valid, nil
+}
+
+func (f *fakeForwarder) Execute(_ context.Context, req ForwardRequest) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	f.executed = append(f.executed, req)
+	return "0xtx", nil
+}
+
+func testRequest(now time.Time) ForwardRequest {
+	return ForwardRequest{
+		From:      testSender,
+		To:        testTarget,
+		Gas:       100_000,
+		Deadline:  uint64(now.Add(time.Minute).Unix()),
+		Data:      "0xa9059cbb",
+		Signature: "0x" + strings.Repeat("11", 65),
+	}
+}
+
+func TestCheck(t *testing.T) {
+	require := require.New(t)
+	now := time.Unix(1_700_000_000, 0)
+	r, err := New(&fakeForwarder{}, 200_000, []string{strings.ToLower(testTarget)})
+	require.NoError(err)
+	r.now = func() time.Time { return now }
+	require.Equal([]string{testTarget}, r.Targets())
+	require.NoError(r.Check(testRequest(now)))
+
+	for name, edit := range map[string]func(*ForwardRequest){
+		"bad sender": func(req *ForwardRequest) { req.From = "0x1234" },
+		"value":      func(req *ForwardRequest) { req.Value = "1" },
+		"no gas":     func(req *ForwardRequest) { req.Gas = 0 },
+		"gas":        func(req *ForwardRequest) { req.Gas = 200_001 },
+		"expired":    func(req *ForwardRequest) { req.Deadline = uint64(now.Unix()) },
+		"data":       func(req *ForwardRequest) { req.Data = "a9059cbb" },
+		"signature":  func(req *ForwardRequest) { req.Signature = "0xzz" },
+	} {
+		req := testRequest(now)
+		edit(&req)
+		require.ErrorIs(r.Check(req), ErrInvalidRequest, name)
+	}
+	req := testRequest(now)
+	req.To = testSender
+	require.ErrorIs(r.Check(req), ErrTargetNotAllowed)
+
+	_, err = New(&fakeForwarder{}, 1, []string{"target"})
+	require.Error(err)
+}
+
+func TestServeRelay(t *testing.T) {
+	require := require.New(t)
+	forwarder := &fakeForwarder{valid: true}
+	r, err := New(forwarder, DefaultMaxGas, nil)
+	require.NoError(err)
+	server := httptest.NewServer(r)
+	defer server.Close()
+	body := func(req ForwardRequest) string {
+		raw, err := json.Marshal(req)
+		require.NoError(err)
+		return string(raw)
+	}
+	post := func(payload string) int {
+		resp, err := http.Post(server.URL+"/relay", "application/json", strings.NewReader(payload))
+		require.NoError(err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	req := testRequest(time.Now())
+	require.Equal(http.StatusOK, post(body(req)))
+	require.Len(forwarder.executed, 1)
+	require.Equal(http.StatusBadRequest, post("{"))
+
+	forwarder.valid = false
+	require.Equal(http.StatusBadRequest, post(body(req)))
+	forwarder.valid = true
+	forwarder.err = errors.New("reverted")
+	require.Equal(http.StatusBadGateway, post(body(req)))
+
+	resp, err := http.Get(server.URL + "/forwarder")
+	require.NoError(err)
+	resp.Body.Close()
+	require.Equal(http.StatusOK, resp.StatusCode)
+}
@@ -5,7 +5,7 @@ package remoteconfig
 
 import (
 	"bytes"
-	"path/filepath"
+	"path"
 	"strings"
 	"text/template"
 
@@ -96,23 +96,39 @@ func RenderLuxAliasesConfig(config LuxConfigInputs) ([]byte, error) {
 }
 
 func GetRemoteLuxNodeConfig() string {
-	return filepath.Join(constants.CloudNodeConfigPath, constants.NodeFileName)
+	return path.Join(constants.CloudNodeConfigPath, constants.NodeFileName)
 }
 
 func GetRemoteLuxCChainConfig() string {
-	return filepath.Join(constants.CloudNodeConfigPath, "chains", "C", "config.json")
+	return path.Join(constants.CloudNodeConfigPath, "chains", "C", "config.json")
 }
 
 func GetRemoteLuxGenesis() string {
-	return filepath.Join(constants.CloudNodeConfigPath, constants.GenesisFileName)
+	return path.Join(constants.CloudNodeConfigPath, constants.GenesisFileName)
 }
 
 func GetRemoteLuxUpgrade() string {
-	return filepath.Join(constants.CloudNodeConfigPath, constants.UpgradeFileName)
+	return path.Join(constants.CloudNodeConfigPath, constants.UpgradeFileName)
+}
+
+func GetRemoteLuxChainConfig(chainID string) string {
+	return path.Join(constants.CloudNodeConfigPath, "chains", chainID+".json")
+}
+
+func GetRemoteLuxBlockchainConfig(blockchainID string) string {
+	return path.Join(constants.CloudNodeConfigPath, "chains", blockchainID, "config.json")
+}
+
+func GetRemoteLuxNetworkUpgrades(blockchainID string) string {
+	return path.Join(constants.CloudNodeConfigPath, "chains", "chains", blockchainID, "upgrade.json")
 }
 
 func GetRemoteLuxAliasesConfig() string {
-	return filepath.Join(constants.CloudNodeConfigPath, "chains", constants.AliasesFileName)
+	return path.Join(constants.CloudNodeConfigPath, "chains", constants.AliasesFileName)
+}
+
+func GetRemoteLuxPluginDir() string {
+	return "/home/ubuntu/.luxd/plugins"
 }
 
 func LuxFolderToCreate() []string {
@@ -123,7 +139,7 @@ func LuxFolderToCreate() []string {
 		"/home/ubuntu/.luxd/configs/chains/",
 		"/home/ubuntu/.luxd/configs/chains/C",
 		"/home/ubuntu/.luxd/staking",
-		"/home/ubuntu/.luxd/plugins",
+		GetRemoteLuxPluginDir(),
 		"/home/ubuntu/.lux-cli/services/warp-relayer",
 	}
 }
@@ -0,0 +1,138 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package remotestate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+// gitStore keeps remote state in a branch of a git repository, through a
+// checkout the git CLI manages so the operator's own credentials and ssh
+// config apply
+type gitStore struct {
+	remote string
+	branch string
+	dir    string
+}
+
+func newGitStore(remote, branch, dir string) *gitStore {
+	return &gitStore{remote: remote, branch: branch, dir: dir}
+}
+
+func (g *gitStore) git(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", g.dir}, args...)...) //nolint:gosec // G204: Running git with CLI-built arguments
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(out), fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+// Refresh resets the checkout to the remote branch, discarding anything
+// not published
+func (g *gitStore) Refresh(ctx context.Context) error {
+	if _, err := os.Stat(filepath.Join(g.dir, ".git")); errors.Is(err, os.ErrNotExist) {
+		if err := os.MkdirAll(g.dir, 0o750); err != nil {
+			return err
+		}
+		if _, err := g.git(ctx, "init", "--quiet"); err != nil {
+			return err
+		}
+		if _, err := g.git(ctx, "remote", "add", "origin", g.remote); err != nil {
+			return err
+		}
+	}
+	if _, err := g.git(ctx, "fetch", "--quiet", "origin"); err != nil {
+		return err
+	}
+	ref := "refs/remotes/origin/" + g.branch
+	if _, err := g.git(ctx, "rev-parse", "--verify", "--quiet", ref); err != nil {
+		// New branch: start from an empty tree
+		if _, err := g.git(ctx, "symbolic-ref", "HEAD", "refs/heads/"+g.branch); err != nil {
+			return err
+		}
+		if _, err := g.git(ctx, "rm", "-r", "--quiet", "--cached", "--ignore-unmatch", "."); err != nil {
+			return err
+		}
+		_, err := g.git(ctx, "clean", "-fdxq")
+		return err
+	}
+	if _, err := g.git(ctx, "checkout", "--quiet", "-B", g.branch, ref); err != nil {
+		return err
+	}
+	if _, err := g.git(ctx, "reset", "--quiet", "--hard", ref); err != nil {
+		return err
+	}
+	_, err := g.git(ctx, "clean", "-fdxq")
+	return err
+}
+
+func (g *gitStore) Read(_ context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(g.dir, filepath.FromSlash(key))) //nolint:gosec // G304: Reading from the state checkout
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+func (g *gitStore) Write(_ context.Context, key string, data []byte) error {
+	p := filepath.Join(g.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(p, data, 0o600)
+}
+
+func (g *gitStore) Remove(_ context.Context, key string) error {
+	err := os.Remove(filepath.Join(g.dir, filepath.FromSlash(key)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Commit commits the checkout and pushes it. A rejected push means someone
+// else published first; the checkout is reset and ErrConflict returned.
+func (g *gitStore) Commit(ctx context.Context, message string) error {
+	if _, err := g.git(ctx, "add", "--all"); err != nil {
+		return err
+	}
+	if _, err := g.git(ctx, "diff", "--cached", "--quiet"); err == nil {
+		return nil
+	}
+	args := []string{"commit", "--quiet", "-m", message}
+	if out, _ := g.git(ctx, "config", "user.email"); strings.TrimSpace(out) == "" {
+		name := "lux"
+		if u, err := user.Current(); err == nil {
+			name = u.Username
+		}
+		host, _ := os.Hostname()
+		args = append([]string{"-c", "user.name=" + name, "-c", "user.email=" + name + "@" + host}, args...)
+	}
+	if _, err := g.git(ctx, args...); err != nil {
+		return err
+	}
+	if out, err := g.git(ctx, "push", "--quiet", "origin", "HEAD:refs/heads/"+g.branch); err != nil {
+		if strings.Contains(out, "rejected") || strings.Contains(out, "fetch first") || strings.Contains(out, "non-fast-forward") {
+			if refreshErr := g.Refresh(ctx); refreshErr != nil {
+				return refreshErr
+			}
+			return fmt.Errorf("%w: %s changed while publishing, try again", ErrConflict, g.remote)
+		}
+		return err
+	}
+	return nil
+}
+
+func (g *gitStore) String() string {
+	return g.remote + " (" + g.branch + ")"
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package remotestate
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitStore(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	require := require.New(t)
+	ctx := context.Background()
+
+	remote := filepath.Join(t.TempDir(), "state.git")
+	require.NoError(exec.Command("git", "init", "--quiet", "--bare", remote).Run())
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	alice := &Syncer{BaseDir: t.TempDir()}
+	bob := &Syncer{BaseDir: t.TempDir()}
+	for _, s := range []*Syncer{alice, bob} {
+		store, err := Open(ctx, s.BaseDir, &Config{URL: "git+" + remote})
+		require.NoError(err)
+		s.Store = store
+	}
+
+	writeFile(t, alice.BaseDir, "chains/mychain/sidecar.json", `{"name":"mychain"}`)
+	result, err := alice.Push(ctx)
+	require.NoError(err)
+	require.Equal(1, result.Revision)
+
+	_, err = bob.Pull(ctx)
+	require.NoError(err)
+	require.JSONEq(`{"name":"mychain"}`, readFile(t, bob.BaseDir, "chains/mychain/sidecar.json"))
+
+	lock, err := ReadLock(ctx, bob.Store)
+	require.NoError(err)
+	require.Nil(lock)
+
+	// A push racing ahead of bob's checkout is rejected
+	bobGit := bob.Store.(*gitStore)
+	require.NoError(bobGit.Write(ctx, "stale", []byte("x")))
+	writeFile(t, alice.BaseDir, "clusters.json", `{}`)
+	_, err = alice.Push(ctx)
+	require.NoError(err)
+	require.ErrorIs(bobGit.Commit(ctx, "stale"), ErrConflict)
+
+	_, err = bob.Pull(ctx)
+	require.NoError(err)
+	require.JSONEq(`{}`, readFile(t, bob.BaseDir, "clusters.json"))
+}
@@ -0,0 +1,153 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package remotestate
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+)
+
+const (
+	lockKey = "lock.json"
+
+	// DefaultLockTTL is how long a lock is held before others may take it
+	// over, in case its holder died without releasing it
+	DefaultLockTTL = 10 * time.Minute
+)
+
+// Lock is held on the remote for the duration of a pull or push
+type Lock struct {
+	ID        string    `json:"id"`
+	Owner     string    `json:"owner"`
+	Operation string    `json:"operation"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// LockedError is returned when someone else holds the lock
+type LockedError struct {
+	Lock *Lock
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("remote state is locked by %s (%s) since %s, until %s",
+		e.Lock.Owner, e.Lock.Operation,
+		e.Lock.CreatedAt.Local().Format(time.RFC3339), e.Lock.ExpiresAt.Local().Format(time.RFC3339))
+}
+
+// Owner names the operator running the CLI, as user@host
+func Owner() string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	host, _ := os.Hostname()
+	return name + "@" + host
+}
+
+// ReadLock returns the lock on s, or nil when it's not locked
+func ReadLock(ctx context.Context, s Store) (*Lock, error) {
+	data, err := s.Read(ctx, lockKey)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var l Lock
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("invalid remote state lock: %w", err)
+	}
+	return &l, nil
+}
+
+// AcquireLock locks s for operation, failing with a LockedError while
+// someone else holds an unexpired lock. Object stores have no
+// compare-and-swap, so the lock is read back after writing it to catch a
+// racing writer.
+func AcquireLock(ctx context.Context, s Store, operation string, ttl time.Duration) (*Lock, error) {
+	if err := s.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	held, err := ReadLock(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if held != nil && now.Before(held.ExpiresAt) {
+		return nil, &LockedError{Lock: held}
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	l := &Lock{
+		ID:        hex.EncodeToString(id),
+		Owner:     Owner(),
+		Operation: operation,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Write(ctx, lockKey, data); err != nil {
+		return nil, err
+	}
+	if err := s.Commit(ctx, fmt.Sprintf("lock: %s by %s", operation, l.Owner)); err != nil {
+		if errors.Is(err, ErrConflict) {
+			if held, readErr := ReadLock(ctx, s); readErr == nil && held != nil {
+				return nil, &LockedError{Lock: held}
+			}
+		}
+		return nil, err
+	}
+	held, err = ReadLock(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	if held == nil || held.ID != l.ID {
+		if held == nil {
+			return nil, fmt.Errorf("%w: lock disappeared while taking it", ErrConflict)
+		}
+		return nil, &LockedError{Lock: held}
+	}
+	return l, nil
+}
+
+// Release removes l from s if it still holds the lock
+func (l *Lock) Release(ctx context.Context, s Store) error {
+	held, err := ReadLock(ctx, s)
+	if err != nil || held == nil || held.ID != l.ID {
+		return err
+	}
+	if err := s.Remove(ctx, lockKey); err != nil {
+		return err
+	}
+	return s.Commit(ctx, "unlock: "+l.Operation+" by "+l.Owner)
+}
+
+// BreakLock removes whatever lock s holds, for locks left behind by an
+// operator whose CLI died
+func BreakLock(ctx context.Context, s Store) (*Lock, error) {
+	if err := s.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	held, err := ReadLock(ctx, s)
+	if err != nil || held == nil {
+		return nil, err
+	}
+	if err := s.Remove(ctx, lockKey); err != nil {
+		return nil, err
+	}
+	return held, s.Commit(ctx, "unlock: broken by "+Owner())
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package remotestate
+
+import (
+	"bytes"
+	"context"
+	"path"
+
+	"github.com/luxfi/cli/pkg/cloud/storage"
+)
+
+// objectStore keeps remote state under a prefix of a storage bucket
+type objectStore struct {
+	storage storage.Storage
+	prefix  string
+	url     string
+}
+
+func newObjectStore(ctx context.Context, c *Config) (*objectStore, error) {
+	cfg, prefix, err := storage.ParseURI(c.URL)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Region = c.Region
+	cfg.Endpoint = c.Endpoint
+	cfg.AWSProfile = c.Profile
+	cfg.PathStyle = c.Endpoint != ""
+	s, err := storage.New(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &objectStore{storage: s, prefix: prefix, url: c.URL}, nil
+}
+
+func (o *objectStore) key(key string) string {
+	return path.Join(o.prefix, key)
+}
+
+func (*objectStore) Refresh(context.Context) error {
+	return nil
+}
+
+func (o *objectStore) Read(ctx context.Context, key string) ([]byte, error) {
+	exists, err := o.storage.Exists(ctx, o.key(key))
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+	var buf bytes.Buffer
+	if err := o.storage.Download(ctx, o.key(key), &buf, nil); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (o *objectStore) Write(ctx context.Context, key string, data []byte) error {
+	return o.storage.Upload(ctx, o.key(key), bytes.NewReader(data), int64(len(data)), nil)
+}
+
+func (o *objectStore) Remove(ctx context.Context, key string) error {
+	exists, err := o.storage.Exists(ctx, o.key(key))
+	if err != nil || !exists {
+		return err
+	}
+	return o.storage.Delete(ctx, o.key(key))
+}
+
+func (*objectStore) Commit(context.Context, string) error {
+	return nil
+}
+
+func (o *objectStore) String() string {
+	return o.url
+}
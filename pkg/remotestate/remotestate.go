@@ -0,0 +1,151 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package remotestate shares the CLI state a team operates on together
+// (chain sidecars and genesis, cluster configs and the history ledger)
+// through a remote backend, an S3 bucket or a git repository, so operators
+// of the same devnet work from the same state rather than each from their
+// own ~/.lux.
+//
+// The remote holds a copy of every shared file under files/, a manifest
+// naming the SHA-256 of each, and a lock taken for the duration of a pull
+// or push. The manifest last synced is kept locally, which makes every
+// sync a three-way comparison: a file changed on one side only is copied
+// over, a file changed on both is a conflict (except the append-only
+// history ledger, whose lines are merged).
+package remotestate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// Dir is the directory in the CLI base dir holding the backend config,
+	// the manifest last synced and, for git, the repository checkout
+	Dir = "remote-state"
+
+	configFileName = "config.json"
+	baseFileName   = "base.json"
+	gitDirName     = "git"
+
+	// DefaultBranch is the branch of git backends unless configured
+	DefaultBranch = "main"
+)
+
+var (
+	// ErrNotConfigured is returned when no remote state backend is set up
+	ErrNotConfigured = errors.New("no remote state backend configured: use 'lux state init <url>'")
+	// ErrNotFound is returned by Store.Read for missing keys
+	ErrNotFound = errors.New("not found in remote state")
+	// ErrConflict is returned when local and remote changed the same file,
+	// or the remote changed while publishing
+	ErrConflict = errors.New("remote state conflict")
+)
+
+// Config is the remote state backend
+type Config struct {
+	// URL is s3://bucket/prefix (or another storage URI such as gs:// or
+	// file://), or a git remote: git+ssh://, git+https://, git@host:repo or
+	// any URL ending in .git
+	URL string `json:"url"`
+	// Branch of git backends
+	Branch string `json:"branch,omitempty"`
+	// Region, Endpoint and Profile configure S3 backends. Endpoint selects
+	// S3-compatible stores such as MinIO or R2.
+	Region   string `json:"region,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+	Profile  string `json:"profile,omitempty"`
+}
+
+// IsGit reports whether the backend is a git repository
+func (c *Config) IsGit() bool {
+	return strings.HasPrefix(c.URL, "git+") ||
+		strings.HasPrefix(c.URL, "git@") ||
+		strings.HasSuffix(strings.TrimSuffix(c.URL, "/"), ".git")
+}
+
+// LoadConfig loads the backend configured in baseDir, or returns
+// ErrNotConfigured
+func LoadConfig(baseDir string) (*Config, error) {
+	data, err := os.ReadFile(filepath.Join(baseDir, Dir, configFileName)) //nolint:gosec // G304: Reading from app's data directory
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotConfigured
+		}
+		return nil, err
+	}
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid remote state config: %w", err)
+	}
+	if c.URL == "" {
+		return nil, ErrNotConfigured
+	}
+	return &c, nil
+}
+
+// SaveConfig configures the backend of baseDir. Switching backends forgets
+// the manifest last synced, so the next sync compares against the new
+// remote from scratch.
+func SaveConfig(baseDir string, c *Config) error {
+	dir := filepath.Join(baseDir, Dir)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	if old, err := LoadConfig(baseDir); err == nil && *old != *c {
+		if err := os.RemoveAll(filepath.Join(dir, gitDirName)); err != nil {
+			return err
+		}
+		if err := os.Remove(filepath.Join(dir, baseFileName)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, configFileName), data, 0o600)
+}
+
+// RemoveConfig stops using a remote state backend
+func RemoveConfig(baseDir string) error {
+	return os.RemoveAll(filepath.Join(baseDir, Dir))
+}
+
+// Store is where remote state lives
+type Store interface {
+	// Refresh brings the store's view of the remote up to date
+	Refresh(ctx context.Context) error
+	// Read returns the content of key, or ErrNotFound
+	Read(ctx context.Context, key string) ([]byte, error)
+	Write(ctx context.Context, key string, data []byte) error
+	// Remove deletes key. Removing a missing key is not an error.
+	Remove(ctx context.Context, key string) error
+	// Commit publishes the writes made since Refresh. Object stores
+	// publish each write as it is made.
+	Commit(ctx context.Context, message string) error
+	// String describes the remote
+	String() string
+}
+
+// Open opens the store of c. baseDir holds the git checkout.
+func Open(ctx context.Context, baseDir string, c *Config) (Store, error) {
+	if c.IsGit() {
+		branch := c.Branch
+		if branch == "" {
+			branch = DefaultBranch
+		}
+		return newGitStore(strings.TrimPrefix(c.URL, "git+"), branch, filepath.Join(baseDir, Dir, gitDirName)), nil
+	}
+	store, err := newObjectStore(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
@@ -0,0 +1,500 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package remotestate
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/luxfi/cli/pkg/statefile"
+	"github.com/luxfi/constants"
+)
+
+const (
+	manifestKey = "manifest.json"
+	filesPrefix = "files/"
+)
+
+// Manifest lists the shared files and their SHA-256
+type Manifest struct {
+	Revision  int               `json:"revision"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+	UpdatedBy string            `json:"updatedBy"`
+	Files     map[string]string `json:"files"`
+}
+
+// Action is what a sync does with a file
+type Action string
+
+const (
+	// ActionPull takes the remote change
+	ActionPull Action = "pull"
+	// ActionPush publishes the local change
+	ActionPush Action = "push"
+	// ActionMerge merges local and remote changes of the history ledger
+	ActionMerge Action = "merge"
+	// ActionConflict needs --force to pick a side
+	ActionConflict Action = "conflict"
+)
+
+// Change is a shared file differing between local and remote state.
+// Hashes are empty for missing files.
+type Change struct {
+	Path   string
+	Action Action
+	Local  string
+	Remote string
+}
+
+// Describe says what changed on which side
+func (c Change) Describe() string {
+	switch c.Action {
+	case ActionPull:
+		return describe("remote", c.Remote)
+	case ActionPush:
+		return describe("local", c.Local)
+	default:
+		return describe("local", c.Local) + ", " + describe("remote", c.Remote)
+	}
+}
+
+func describe(side, hash string) string {
+	if hash == "" {
+		return side + " deleted"
+	}
+	return side + " changed"
+}
+
+// Result of a sync
+type Result struct {
+	Revision int
+	Changes  []Change
+}
+
+// Syncer syncs the shared files of a CLI base dir with a store
+type Syncer struct {
+	BaseDir string
+	Store   Store
+	// Force resolves conflicts in the direction of the sync: a forced pull
+	// overwrites local changes, a forced push remote ones
+	Force bool
+	// LockTTL defaults to DefaultLockTTL
+	LockTTL time.Duration
+}
+
+// SharedPaths are the files and directories of the CLI base dir shared
+// through remote state
+func SharedPaths() []string {
+	return []string{constants.ChainsDir, constants.ClustersConfigFileName, history.FileName}
+}
+
+// IsShared reports whether the slash separated path p is, or is under, one
+// of the shared paths. Anything else in a manifest, such as keys or plugin
+// binaries, is never pulled.
+func IsShared(p string) bool {
+	if p == "" || path.Clean(p) != p || path.IsAbs(p) {
+		return false
+	}
+	for _, shared := range SharedPaths() {
+		if p == shared || strings.HasPrefix(p, shared+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// sharedOnly drops the paths of files that aren't shared
+func sharedOnly(files map[string]string) map[string]string {
+	for p := range files {
+		if !IsShared(p) {
+			delete(files, p)
+		}
+	}
+	return files
+}
+
+// LocalFiles hashes the shared files of baseDir by slash separated path
+func LocalFiles(baseDir string) (map[string]string, error) {
+	files := map[string]string{}
+	for _, shared := range SharedPaths() {
+		err := filepath.WalkDir(filepath.Join(baseDir, shared), func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			// backups and temporary files of state files stay local
+			if !d.Type().IsRegular() || statefile.IsAuxiliary(p) {
+				return nil
+			}
+			data, err := os.ReadFile(p) //nolint:gosec // G304: Reading from app's data directory
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(baseDir, p)
+			if err != nil {
+				return err
+			}
+			files[filepath.ToSlash(rel)] = hash(data)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// Diff compares local and remote files against those last synced. Files
+// changed on one side only are taken from that side; files changed on both
+// conflict, except the history ledger whose lines merge.
+func Diff(base, local, remote map[string]string) []Change {
+	paths := map[string]struct{}{}
+	for _, m := range []map[string]string{base, local, remote} {
+		for p := range m {
+			paths[p] = struct{}{}
+		}
+	}
+	var changes []Change
+	for p := range paths {
+		l, r, b := local[p], remote[p], base[p]
+		c := Change{Path: p, Local: l, Remote: r}
+		switch {
+		case l == r:
+			continue
+		case l == b:
+			c.Action = ActionPull
+		case r == b:
+			c.Action = ActionPush
+		case p == history.FileName && l != "" && r != "":
+			c.Action = ActionMerge
+		default:
+			c.Action = ActionConflict
+		}
+		changes = append(changes, c)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// Status compares local and remote state without locking or changing
+// either
+func (s *Syncer) Status(ctx context.Context) (*Manifest, []Change, error) {
+	if err := s.Store.Refresh(ctx); err != nil {
+		return nil, nil, err
+	}
+	remote, base, local, err := s.load(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return remote, Diff(base, local, remote.Files), nil
+}
+
+// Pull takes remote changes into the base dir
+func (s *Syncer) Pull(ctx context.Context) (*Result, error) {
+	return s.locked(ctx, "pull", s.pull)
+}
+
+// Push publishes local changes to the remote
+func (s *Syncer) Push(ctx context.Context) (*Result, error) {
+	return s.locked(ctx, "push", s.push)
+}
+
+func (s *Syncer) locked(ctx context.Context, operation string, sync func(context.Context) (*Result, error)) (*Result, error) {
+	ttl := s.LockTTL
+	if ttl == 0 {
+		ttl = DefaultLockTTL
+	}
+	lock, err := AcquireLock(ctx, s.Store, operation, ttl)
+	if err != nil {
+		return nil, err
+	}
+	result, err := sync(ctx)
+	if releaseErr := lock.Release(ctx, s.Store); err == nil && releaseErr != nil {
+		err = fmt.Errorf("failed to release lock: %w", releaseErr)
+	}
+	return result, err
+}
+
+func (s *Syncer) pull(ctx context.Context) (*Result, error) {
+	remote, base, local, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	changes := Diff(base, local, remote.Files)
+	if err := s.checkConflicts(changes, "pull"); err != nil {
+		return nil, err
+	}
+	var applied []Change
+	for _, c := range changes {
+		switch c.Action {
+		case ActionPull, ActionConflict:
+			if err := s.download(ctx, c.Path, c.Remote); err != nil {
+				return nil, err
+			}
+		case ActionMerge:
+			if err := s.mergeLocal(ctx, c.Path); err != nil {
+				return nil, err
+			}
+		default:
+			continue
+		}
+		applied = append(applied, c)
+	}
+	// Local changes left to push still differ from the remote, which is
+	// what the base records for them
+	if err := s.saveBase(remote.Files); err != nil {
+		return nil, err
+	}
+	return &Result{Revision: remote.Revision, Changes: applied}, nil
+}
+
+func (s *Syncer) push(ctx context.Context) (*Result, error) {
+	remote, base, local, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	changes := Diff(base, local, remote.Files)
+	if err := s.checkConflicts(changes, "push"); err != nil {
+		return nil, err
+	}
+	files := make(map[string]string, len(remote.Files))
+	for p, h := range remote.Files {
+		files[p] = h
+	}
+	// Remote changes not pulled keep their old base so they're still
+	// pulled later
+	pending := map[string]string{}
+	var applied []Change
+	for _, c := range changes {
+		switch c.Action {
+		case ActionPush, ActionConflict:
+			if err := s.upload(ctx, c.Path, c.Local); err != nil {
+				return nil, err
+			}
+			setHash(files, c.Path, c.Local)
+		case ActionMerge:
+			if err := s.mergeLocal(ctx, c.Path); err != nil {
+				return nil, err
+			}
+			data, err := os.ReadFile(s.localPath(c.Path)) //nolint:gosec // G304: Reading from app's data directory
+			if err != nil {
+				return nil, err
+			}
+			if err := s.Store.Write(ctx, filesPrefix+c.Path, data); err != nil {
+				return nil, err
+			}
+			setHash(files, c.Path, hash(data))
+		default:
+			pending[c.Path] = base[c.Path]
+			continue
+		}
+		applied = append(applied, c)
+	}
+	if len(applied) == 0 {
+		return &Result{Revision: remote.Revision}, nil
+	}
+	newBase := make(map[string]string, len(files))
+	for p, h := range files {
+		newBase[p] = h
+	}
+	for p, h := range pending {
+		setHash(newBase, p, h)
+	}
+
+	manifest := Manifest{
+		Revision:  remote.Revision + 1,
+		UpdatedAt: time.Now().UTC(),
+		UpdatedBy: Owner(),
+		Files:     files,
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Store.Write(ctx, manifestKey, data); err != nil {
+		return nil, err
+	}
+	if err := s.Store.Commit(ctx, fmt.Sprintf("push: revision %d by %s", manifest.Revision, manifest.UpdatedBy)); err != nil {
+		return nil, err
+	}
+	if err := s.saveBase(newBase); err != nil {
+		return nil, err
+	}
+	return &Result{Revision: manifest.Revision, Changes: applied}, nil
+}
+
+func (s *Syncer) checkConflicts(changes []Change, operation string) error {
+	if s.Force {
+		return nil
+	}
+	var conflicts []string
+	for _, c := range changes {
+		if c.Action == ActionConflict {
+			conflicts = append(conflicts, c.Path)
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%w: %s changed both locally and remotely, use %s --force to keep the %s version",
+			ErrConflict, strings.Join(conflicts, ", "), operation, map[string]string{"pull": "remote", "push": "local"}[operation])
+	}
+	return nil
+}
+
+// load reads the remote manifest, the manifest last synced and the local
+// files. Entries of the manifests outside of the shared paths are ignored,
+// so a compromised remote can't write or delete other files of the base dir.
+func (s *Syncer) load(ctx context.Context) (*Manifest, map[string]string, map[string]string, error) {
+	remote := &Manifest{Files: map[string]string{}}
+	data, err := s.Store.Read(ctx, manifestKey)
+	switch {
+	case errors.Is(err, ErrNotFound):
+	case err != nil:
+		return nil, nil, nil, err
+	default:
+		if err := json.Unmarshal(data, remote); err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid remote state manifest: %w", err)
+		}
+		if remote.Files == nil {
+			remote.Files = map[string]string{}
+		}
+		sharedOnly(remote.Files)
+	}
+	base, err := s.loadBase()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sharedOnly(base)
+	local, err := LocalFiles(s.BaseDir)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return remote, base, local, nil
+}
+
+func (s *Syncer) loadBase() (map[string]string, error) {
+	data, err := os.ReadFile(filepath.Join(s.BaseDir, Dir, baseFileName)) //nolint:gosec // G304: Reading from app's data directory
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	base := map[string]string{}
+	if err := json.Unmarshal(data, &base); err != nil {
+		return nil, fmt.Errorf("invalid remote state base: %w", err)
+	}
+	return base, nil
+}
+
+func (s *Syncer) saveBase(base map[string]string) error {
+	data, err := json.MarshalIndent(base, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(s.BaseDir, Dir), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.BaseDir, Dir, baseFileName), data, 0o600)
+}
+
+func (s *Syncer) localPath(p string) string {
+	return filepath.Join(s.BaseDir, filepath.FromSlash(path.Clean("/"+p)))
+}
+
+// download replaces the local file p with the remote one, whose hash is
+// want, or deletes it when want is empty
+func (s *Syncer) download(ctx context.Context, p, want string) error {
+	if want == "" {
+		if err := os.Remove(s.localPath(p)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	data, err := s.Store.Read(ctx, filesPrefix+p)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", p, err)
+	}
+	if hash(data) != want {
+		return fmt.Errorf("%w: %s doesn't match the remote manifest", ErrConflict, p)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.localPath(p)), 0o750); err != nil {
+		return err
+	}
+	return statefile.WriteFile(s.localPath(p), data, 0o600)
+}
+
+// upload publishes the local file p, or deletes it remotely when hash is
+// empty
+func (s *Syncer) upload(ctx context.Context, p, hash string) error {
+	if hash == "" {
+		return s.Store.Remove(ctx, filesPrefix+p)
+	}
+	data, err := os.ReadFile(s.localPath(p)) //nolint:gosec // G304: Reading from app's data directory
+	if err != nil {
+		return err
+	}
+	return s.Store.Write(ctx, filesPrefix+p, data)
+}
+
+// mergeLocal merges the remote lines of p into the local file
+func (s *Syncer) mergeLocal(ctx context.Context, p string) error {
+	remote, err := s.Store.Read(ctx, filesPrefix+p)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", p, err)
+	}
+	local, err := os.ReadFile(s.localPath(p)) //nolint:gosec // G304: Reading from app's data directory
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.localPath(p), MergeLines(local, remote), 0o600)
+}
+
+// MergeLines merges two versions of an append-only file: the remote lines
+// followed by the local lines the remote doesn't have
+func MergeLines(local, remote []byte) []byte {
+	var out bytes.Buffer
+	seen := map[string]struct{}{}
+	for _, data := range [][]byte{remote, local} {
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if _, ok := seen[line]; ok {
+				continue
+			}
+			seen[line] = struct{}{}
+			out.WriteString(line)
+			out.WriteByte('\n')
+		}
+	}
+	return out.Bytes()
+}
+
+func setHash(files map[string]string, p, h string) {
+	if h == "" {
+		delete(files, p)
+		return
+	}
+	files[p] = h
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
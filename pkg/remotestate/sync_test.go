@@ -0,0 +1,290 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package remotestate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/luxfi/cli/pkg/history"
+	"github.com/stretchr/testify/require"
+)
+
+// memStore is an object store in memory, shared by the operators of a test
+type memStore struct {
+	objects map[string][]byte
+}
+
+func newMemStore() *memStore {
+	return &memStore{objects: map[string][]byte{}}
+}
+
+func (*memStore) Refresh(context.Context) error { return nil }
+
+func (m *memStore) Read(_ context.Context, key string) ([]byte, error) {
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return data, nil
+}
+
+func (m *memStore) Write(_ context.Context, key string, data []byte) error {
+	m.objects[key] = append([]byte(nil), data...)
+	return nil
+}
+
+func (m *memStore) Remove(_ context.Context, key string) error {
+	delete(m.objects, key)
+	return nil
+}
+
+func (*memStore) Commit(context.Context, string) error { return nil }
+
+func (*memStore) String() string { return "mem" }
+
+func writeFile(t *testing.T, baseDir, p, content string) {
+	t.Helper()
+	full := filepath.Join(baseDir, filepath.FromSlash(p))
+	require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o750))
+	require.NoError(t, os.WriteFile(full, []byte(content), 0o600))
+}
+
+func readFile(t *testing.T, baseDir, p string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(baseDir, filepath.FromSlash(p)))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestDiff(t *testing.T) {
+	base := map[string]string{"a": "1", "b": "1", "c": "1", "d": "1", history.FileName: "1"}
+	local := map[string]string{"a": "1", "b": "2", "c": "2", "e": "1", history.FileName: "2"}
+	remote := map[string]string{"a": "1", "b": "1", "c": "3", "d": "2", history.FileName: "3"}
+	require.Equal(t, []Change{
+		{Path: "b", Action: ActionPush, Local: "2", Remote: "1"},
+		{Path: "c", Action: ActionConflict, Local: "2", Remote: "3"},
+		{Path: "d", Action: ActionConflict, Local: "", Remote: "2"},
+		{Path: "e", Action: ActionPush, Local: "1", Remote: ""},
+		{Path: history.FileName, Action: ActionMerge, Local: "2", Remote: "3"},
+	}, Diff(base, local, remote))
+}
+
+func TestPushPull(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	store := newMemStore()
+	alice := &Syncer{BaseDir: t.TempDir(), Store: store}
+	bob := &Syncer{BaseDir: t.TempDir(), Store: store}
+
+	writeFile(t, alice.BaseDir, "chains/mychain/sidecar.json", `{"name":"mychain"}`)
+	writeFile(t, alice.BaseDir, "clusters.json", `{}`)
+	writeFile(t, alice.BaseDir, "keys/alice.pk", "secret")
+	result, err := alice.Push(ctx)
+	require.NoError(err)
+	require.Equal(1, result.Revision)
+	require.Len(result.Changes, 2)
+	require.NotContains(store.objects, filesPrefix+"keys/alice.pk")
+
+	result, err = bob.Pull(ctx)
+	require.NoError(err)
+	require.Len(result.Changes, 2)
+	require.JSONEq(`{"name":"mychain"}`, readFile(t, bob.BaseDir, "chains/mychain/sidecar.json"))
+
+	// Nothing left to sync
+	_, changes, err := bob.Status(ctx)
+	require.NoError(err)
+	require.Empty(changes)
+
+	// Bob deletes a file, alice pulls the deletion
+	require.NoError(os.Remove(filepath.Join(bob.BaseDir, "clusters.json")))
+	result, err = bob.Push(ctx)
+	require.NoError(err)
+	require.Equal(2, result.Revision)
+	_, err = alice.Pull(ctx)
+	require.NoError(err)
+	require.NoFileExists(filepath.Join(alice.BaseDir, "clusters.json"))
+
+	// Bob's push of one file leaves alice's unpulled change to another alone
+	writeFile(t, alice.BaseDir, "chains/other/sidecar.json", `{"name":"other"}`)
+	_, err = alice.Push(ctx)
+	require.NoError(err)
+	writeFile(t, bob.BaseDir, "chains/mychain/genesis.json", `{}`)
+	_, err = bob.Push(ctx)
+	require.NoError(err)
+	_, changes, err = bob.Status(ctx)
+	require.NoError(err)
+	require.Equal([]Change{{Path: "chains/other/sidecar.json", Action: ActionPull, Remote: changes[0].Remote}}, changes)
+}
+
+func TestConflict(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	store := newMemStore()
+	alice := &Syncer{BaseDir: t.TempDir(), Store: store}
+	bob := &Syncer{BaseDir: t.TempDir(), Store: store}
+
+	writeFile(t, alice.BaseDir, "chains/mychain/sidecar.json", `{"version":1}`)
+	_, err := alice.Push(ctx)
+	require.NoError(err)
+	_, err = bob.Pull(ctx)
+	require.NoError(err)
+
+	writeFile(t, alice.BaseDir, "chains/mychain/sidecar.json", `{"version":2}`)
+	_, err = alice.Push(ctx)
+	require.NoError(err)
+	writeFile(t, bob.BaseDir, "chains/mychain/sidecar.json", `{"version":3}`)
+
+	_, err = bob.Push(ctx)
+	require.ErrorIs(err, ErrConflict)
+	_, err = bob.Pull(ctx)
+	require.ErrorIs(err, ErrConflict)
+	require.Equal(`{"version":3}`, readFile(t, bob.BaseDir, "chains/mychain/sidecar.json"))
+
+	// The lock was released despite the failures
+	lock, err := ReadLock(ctx, store)
+	require.NoError(err)
+	require.Nil(lock)
+
+	bob.Force = true
+	_, err = bob.Pull(ctx)
+	require.NoError(err)
+	require.Equal(`{"version":2}`, readFile(t, bob.BaseDir, "chains/mychain/sidecar.json"))
+}
+
+func TestHistoryMerge(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	store := newMemStore()
+	alice := &Syncer{BaseDir: t.TempDir(), Store: store}
+	bob := &Syncer{BaseDir: t.TempDir(), Store: store}
+
+	writeFile(t, alice.BaseDir, history.FileName, "{\"operation\":\"a1\"}\n")
+	_, err := alice.Push(ctx)
+	require.NoError(err)
+	_, err = bob.Pull(ctx)
+	require.NoError(err)
+
+	writeFile(t, alice.BaseDir, history.FileName, "{\"operation\":\"a1\"}\n{\"operation\":\"a2\"}\n")
+	_, err = alice.Push(ctx)
+	require.NoError(err)
+	writeFile(t, bob.BaseDir, history.FileName, "{\"operation\":\"a1\"}\n{\"operation\":\"b1\"}\n")
+
+	result, err := bob.Push(ctx)
+	require.NoError(err)
+	require.Equal(ActionMerge, result.Changes[0].Action)
+	merged := "{\"operation\":\"a1\"}\n{\"operation\":\"a2\"}\n{\"operation\":\"b1\"}\n"
+	require.Equal(merged, readFile(t, bob.BaseDir, history.FileName))
+
+	_, err = alice.Pull(ctx)
+	require.NoError(err)
+	require.Equal(merged, readFile(t, alice.BaseDir, history.FileName))
+}
+
+func TestLock(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	store := newMemStore()
+
+	lock, err := AcquireLock(ctx, store, "push", time.Minute)
+	require.NoError(err)
+
+	_, err = AcquireLock(ctx, store, "pull", time.Minute)
+	var locked *LockedError
+	require.ErrorAs(err, &locked)
+	require.Equal(lock.ID, locked.Lock.ID)
+
+	syncer := &Syncer{BaseDir: t.TempDir(), Store: store}
+	_, err = syncer.Pull(ctx)
+	require.ErrorAs(err, &locked)
+
+	require.NoError(lock.Release(ctx, store))
+	lock, err = AcquireLock(ctx, store, "pull", -time.Second)
+	require.NoError(err)
+
+	// An expired lock is taken over
+	_, err = AcquireLock(ctx, store, "push", time.Minute)
+	require.NoError(err)
+
+	broken, err := BreakLock(ctx, store)
+	require.NoError(err)
+	require.NotEqual(lock.ID, broken.ID)
+	held, err := ReadLock(ctx, store)
+	require.NoError(err)
+	require.Nil(held)
+}
+
+func TestConfig(t *testing.T) {
+	require := require.New(t)
+	baseDir := t.TempDir()
+
+	_, err := LoadConfig(baseDir)
+	require.ErrorIs(err, ErrNotConfigured)
+
+	c := &Config{URL: "s3://team-state/devnet", Region: "us-east-1"}
+	require.NoError(SaveConfig(baseDir, c))
+	loaded, err := LoadConfig(baseDir)
+	require.NoError(err)
+	require.Equal(c, loaded)
+	require.False(loaded.IsGit())
+
+	for _, url := range []string{"git+ssh://git@github.com/team/state", "git@github.com:team/state.git", "https://github.com/team/state.git"} {
+		require.True((&Config{URL: url}).IsGit(), url)
+	}
+
+	require.NoError(RemoveConfig(baseDir))
+	_, err = LoadConfig(baseDir)
+	require.ErrorIs(err, ErrNotConfigured)
+}
+
+func TestMergeLines(t *testing.T) {
+	require.Equal(t, "r1\nshared\nl1\n", string(MergeLines([]byte("shared\nl1\n"), []byte("r1\nshared"))))
+}
+
+func TestPullIgnoresUnsharedPaths(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	store := newMemStore()
+	s := &Syncer{BaseDir: t.TempDir(), Store: store}
+	writeFile(t, s.BaseDir, "keys/mine.pk", "secret")
+
+	plugin := []byte("#!/bin/sh\n")
+	manifest := `{"revision":1,"files":{
+		"chains/mychain/sidecar.json":"` + hash([]byte(`{}`)) + `",
+		"plugins/srEXiWaHuhNyGwPUi444Tu47ZEDwxTWrbQiuD7FmgSAQ6X7Dy":"` + hash(plugin) + `",
+		"chains/../keys/mine.pk":"` + hash(plugin) + `",
+		"/keys/mine.pk":"` + hash(plugin) + `",
+		"keys/mine.pk":""
+	}}`
+	store.objects[manifestKey] = []byte(manifest)
+	store.objects[filesPrefix+"chains/mychain/sidecar.json"] = []byte(`{}`)
+	store.objects[filesPrefix+"plugins/srEXiWaHuhNyGwPUi444Tu47ZEDwxTWrbQiuD7FmgSAQ6X7Dy"] = plugin
+	store.objects[filesPrefix+"chains/../keys/mine.pk"] = plugin
+	store.objects[filesPrefix+"/keys/mine.pk"] = plugin
+
+	result, err := s.Pull(ctx)
+	require.NoError(err)
+	require.Equal([]Change{{Path: "chains/mychain/sidecar.json", Action: ActionPull, Remote: hash([]byte(`{}`))}}, result.Changes)
+	require.Equal("secret", readFile(t, s.BaseDir, "keys/mine.pk"))
+	require.NoDirExists(filepath.Join(s.BaseDir, "plugins"))
+}
+
+func TestIsShared(t *testing.T) {
+	for p, want := range map[string]bool{
+		"chains/mychain/sidecar.json": true,
+		"clusters.json":               true,
+		history.FileName:              true,
+		"chains":                      true,
+		"chainsx/sidecar.json":        false,
+		"keys/mine.pk":                false,
+		"chains/../keys/mine.pk":      false,
+		"/chains/mychain/genesis":     false,
+		"":                            false,
+	} {
+		require.Equal(t, want, IsShared(p), p)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcgateway
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+)
+
+// ConfigFile is the name of the gateway config in the CLI base directory
+const ConfigFile = "gateway.json"
+
+const (
+	DefaultRate  = 10
+	DefaultBurst = 20
+)
+
+var (
+	// DefaultMethods are the methods a key may call when it lists none: the
+	// read and transaction APIs, without admin, debug, personal or txpool
+	DefaultMethods = []string{"eth_*", "net_*", "web3_*", "info.*", "health.*", "platform.get*", "avm.get*"}
+	// DefaultPaths are the node API paths the gateway forwards, leaving out
+	// the admin, keystore and metrics APIs
+	DefaultPaths = []string{"/ext/bc/", "/ext/info", "/ext/health"}
+
+	ErrKeyExists   = errors.New("key already exists")
+	ErrKeyNotFound = errors.New("key not found")
+)
+
+// Key is an API key handed to a partner
+type Key struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+	// Rate is the number of JSON-RPC calls per second the key may make
+	Rate float64 `json:"rate"`
+	// Burst is the number of calls the key may make at once
+	Burst int `json:"burst"`
+	// Methods are the method patterns the key may call, e.g. eth_* or
+	// platform.get*. DefaultMethods apply when empty.
+	Methods []string `json:"methods,omitempty"`
+}
+
+// Allowed reports whether the key may call method
+func (k Key) Allowed(method string) bool {
+	patterns := k.Methods
+	if len(patterns) == 0 {
+		patterns = DefaultMethods
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, method); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Config holds the keys of the gateway
+type Config struct {
+	Keys []Key `json:"keys"`
+	// Paths are the path prefixes forwarded upstream. DefaultPaths apply when
+	// empty.
+	Paths []string `json:"paths,omitempty"`
+}
+
+// LoadConfig reads the config at path, returning an empty one when there is
+// no such file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid gateway config %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Save writes the config to path, readable only by its owner since it holds
+// the keys
+func (c *Config) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// AddKey adds a key named name, generating its secret
+func (c *Config) AddKey(name string, rate float64, burst int, methods []string) (Key, error) {
+	if name == "" {
+		return Key{}, errors.New("key name can't be empty")
+	}
+	if rate <= 0 || burst <= 0 {
+		return Key{}, fmt.Errorf("rate and burst must be positive, got %g and %d", rate, burst)
+	}
+	for _, pattern := range methods {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return Key{}, fmt.Errorf("invalid method pattern %q: %w", pattern, err)
+		}
+	}
+	if _, ok := c.key(name); ok {
+		return Key{}, fmt.Errorf("%w: %s", ErrKeyExists, name)
+	}
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return Key{}, err
+	}
+	k := Key{Name: name, Key: "lux_" + hex.EncodeToString(secret), Rate: rate, Burst: burst, Methods: methods}
+	c.Keys = append(c.Keys, k)
+	return k, nil
+}
+
+// RemoveKey removes the key named name
+func (c *Config) RemoveKey(name string) error {
+	i := slices.IndexFunc(c.Keys, func(k Key) bool { return k.Name == name })
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrKeyNotFound, name)
+	}
+	c.Keys = slices.Delete(c.Keys, i, i+1)
+	return nil
+}
+
+func (c *Config) key(name string) (Key, bool) {
+	for _, k := range c.Keys {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
@@ -0,0 +1,286 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package rpcgateway fronts node RPC endpoints for external users: every
+// request needs an API key, each key is rate limited and may only call the
+// JSON-RPC methods it is allowed, and the node admin APIs are never
+// forwarded. Every request is written to an access log.
+package rpcgateway
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// maxBodySize bounds the JSON-RPC requests read by the gateway
+const maxBodySize = 5 << 20
+
+// JSON-RPC error codes returned by the gateway
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeForbidden      = -32601
+	codeRateLimited    = -32005
+	codeUnauthorized   = -32001
+)
+
+// AccessEntry is a line of the access log
+type AccessEntry struct {
+	Time     time.Time `json:"time"`
+	Key      string    `json:"key,omitempty"`
+	Remote   string    `json:"remote"`
+	Path     string    `json:"path"`
+	Methods  []string  `json:"methods,omitempty"`
+	Status   int       `json:"status"`
+	Duration float64   `json:"durationMs"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Gateway is an http.Handler proxying authorized requests to the upstreams
+type Gateway struct {
+	keys      []Key
+	limiters  map[string]*rate.Limiter
+	paths     []string
+	upstreams []*url.URL
+	next      atomic.Uint64
+	proxy     *httputil.ReverseProxy
+
+	logMu     sync.Mutex
+	accessLog io.Writer
+}
+
+// New returns a gateway forwarding to upstreams in turn, writing its access
+// log as JSON lines to accessLog when not nil
+func New(cfg *Config, upstreams []*url.URL, accessLog io.Writer) (*Gateway, error) {
+	if len(upstreams) == 0 {
+		return nil, errors.New("no upstream to forward to")
+	}
+	if len(cfg.Keys) == 0 {
+		return nil, errors.New("no API key configured")
+	}
+	g := &Gateway{
+		keys:      cfg.Keys,
+		limiters:  make(map[string]*rate.Limiter, len(cfg.Keys)),
+		paths:     cfg.Paths,
+		upstreams: upstreams,
+		accessLog: accessLog,
+	}
+	if len(g.paths) == 0 {
+		g.paths = DefaultPaths
+	}
+	for _, k := range cfg.Keys {
+		g.limiters[k.Name] = rate.NewLimiter(rate.Limit(k.Rate), k.Burst)
+	}
+	g.proxy = &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			upstream := g.upstreams[(g.next.Add(1)-1)%uint64(len(g.upstreams))]
+			r.SetURL(upstream)
+			r.SetXForwarded()
+			// the key is for the gateway only
+			r.Out.Header.Del("Authorization")
+			r.Out.Header.Del("X-API-Key")
+			query := r.Out.URL.Query()
+			query.Del("api-key")
+			r.Out.URL.RawQuery = query.Encode()
+		},
+		ErrorHandler: func(w http.ResponseWriter, _ *http.Request, err error) {
+			writeError(w, http.StatusBadGateway, codeInvalidRequest, "upstream unavailable: "+err.Error())
+		},
+	}
+	return g, nil
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	entry := AccessEntry{Time: start, Remote: remoteHost(r), Path: r.URL.Path}
+	defer func() {
+		entry.Status = rec.status
+		entry.Duration = float64(time.Since(start).Microseconds()) / 1000
+		g.log(entry)
+	}()
+
+	key, ok := g.authenticate(r)
+	if !ok {
+		entry.Error = "unauthorized"
+		rec.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(rec, http.StatusUnauthorized, codeUnauthorized, "missing or invalid API key")
+		return
+	}
+	entry.Key = key.Name
+
+	if !g.pathAllowed(r.URL.Path) {
+		entry.Error = "path not allowed"
+		writeError(rec, http.StatusForbidden, codeForbidden, "path not allowed: "+r.URL.Path)
+		return
+	}
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		entry.Error = "websocket not supported"
+		writeError(rec, http.StatusBadRequest, codeInvalidRequest, "websocket connections are not supported by the gateway")
+		return
+	}
+
+	calls := 1
+	if r.Method == http.MethodPost {
+		body, err := io.ReadAll(http.MaxBytesReader(rec, r.Body, maxBodySize))
+		if err != nil {
+			entry.Error = err.Error()
+			writeError(rec, http.StatusRequestEntityTooLarge, codeInvalidRequest, "request body too large")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		methods, err := parseMethods(body)
+		if err != nil {
+			entry.Error = err.Error()
+			writeError(rec, http.StatusBadRequest, codeParseError, err.Error())
+			return
+		}
+		entry.Methods = methods
+		for _, method := range methods {
+			if !key.Allowed(method) {
+				entry.Error = "method not allowed"
+				writeError(rec, http.StatusForbidden, codeForbidden, "method not allowed: "+method)
+				return
+			}
+		}
+		calls = max(len(methods), 1)
+	}
+
+	if !g.limiters[key.Name].AllowN(time.Now(), calls) {
+		entry.Error = "rate limited"
+		rec.Header().Set("Retry-After", "1")
+		writeError(rec, http.StatusTooManyRequests, codeRateLimited, fmt.Sprintf("rate limit of %g calls/s exceeded", key.Rate))
+		return
+	}
+	g.proxy.ServeHTTP(rec, r)
+}
+
+// authenticate finds the key of the request, given as a bearer token, an
+// X-API-Key header or an api-key query parameter
+func (g *Gateway) authenticate(r *http.Request) (Key, bool) {
+	secret := r.Header.Get("X-API-Key")
+	if auth := r.Header.Get("Authorization"); secret == "" && auth != "" {
+		secret, _ = strings.CutPrefix(auth, "Bearer ")
+	}
+	if secret == "" {
+		secret = r.URL.Query().Get("api-key")
+	}
+	if secret == "" {
+		return Key{}, false
+	}
+	for _, k := range g.keys {
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(k.Key)) == 1 {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
+
+func (g *Gateway) pathAllowed(p string) bool {
+	// no dot segments that would climb out of an allowed prefix
+	if strings.Contains(p, "..") || strings.Contains(p, "//") {
+		return false
+	}
+	for _, prefix := range g.paths {
+		if strings.HasPrefix(p, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Gateway) log(entry AccessEntry) {
+	if g.accessLog == nil {
+		return
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	g.logMu.Lock()
+	defer g.logMu.Unlock()
+	_, _ = g.accessLog.Write(append(line, '\n'))
+}
+
+// parseMethods returns the methods called by a JSON-RPC request or batch
+func parseMethods(body []byte) ([]string, error) {
+	type call struct {
+		Method string `json:"method"`
+	}
+	body = bytes.TrimSpace(body)
+	var calls []call
+	switch {
+	case len(body) == 0:
+		return nil, errors.New("empty request")
+	case body[0] == '[':
+		if err := json.Unmarshal(body, &calls); err != nil {
+			return nil, fmt.Errorf("invalid JSON-RPC batch: %w", err)
+		}
+		if len(calls) == 0 {
+			return nil, errors.New("empty JSON-RPC batch")
+		}
+	default:
+		var c call
+		if err := json.Unmarshal(body, &c); err != nil {
+			return nil, fmt.Errorf("invalid JSON-RPC request: %w", err)
+		}
+		calls = []call{c}
+	}
+	methods := make([]string, len(calls))
+	for i, c := range calls {
+		if c.Method == "" {
+			return nil, errors.New("JSON-RPC request without method")
+		}
+		methods[i] = c.Method
+	}
+	return methods, nil
+}
+
+func writeError(w http.ResponseWriter, status, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      nil,
+		"error":   map[string]any{"code": code, "message": message},
+	})
+}
+
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// statusRecorder keeps the status code written for the access log
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
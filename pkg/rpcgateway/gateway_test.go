@@ -0,0 +1,174 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcgateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// upstream records the requests reaching the node
+type upstream struct {
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (u *upstream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.requests = append(u.requests, r)
+	_, _ = io.WriteString(w, `{"jsonrpc":"2.0","id":1,"result":"0x1"}`)
+}
+
+func newTestGateway(t *testing.T, keys ...Key) (*Gateway, *upstream, *bytes.Buffer) {
+	node := &upstream{}
+	server := httptest.NewServer(node)
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	var accessLog bytes.Buffer
+	g, err := New(&Config{Keys: keys}, []*url.URL{u}, &accessLog)
+	require.NoError(t, err)
+	return g, node, &accessLog
+}
+
+func call(g *Gateway, path, key, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if key != "" {
+		r.Header.Set("Authorization", "Bearer "+key)
+	}
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, r)
+	return w
+}
+
+func TestGateway(t *testing.T) {
+	require := require.New(t)
+	partner := Key{Name: "partner", Key: "secret", Rate: 100, Burst: 100}
+	g, node, accessLog := newTestGateway(t, partner)
+
+	w := call(g, "/ext/bc/C/rpc", "secret", `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"}`)
+	require.Equal(http.StatusOK, w.Code)
+	require.Len(node.requests, 1)
+	require.Equal("/ext/bc/C/rpc", node.requests[0].URL.Path)
+	require.Empty(node.requests[0].Header.Get("Authorization"))
+
+	// the key is also accepted as a query parameter, which is not forwarded
+	r := httptest.NewRequest(http.MethodPost, "/ext/bc/C/rpc?api-key=secret", strings.NewReader(`{"method":"eth_chainId"}`))
+	w = httptest.NewRecorder()
+	g.ServeHTTP(w, r)
+	require.Equal(http.StatusOK, w.Code)
+	require.Empty(node.requests[1].URL.RawQuery)
+
+	w = call(g, "/ext/bc/C/rpc", "", `{"method":"eth_blockNumber"}`)
+	require.Equal(http.StatusUnauthorized, w.Code)
+	w = call(g, "/ext/bc/C/rpc", "wrong", `{"method":"eth_blockNumber"}`)
+	require.Equal(http.StatusUnauthorized, w.Code)
+
+	// admin APIs are not forwarded
+	w = call(g, "/ext/admin", "secret", `{"method":"admin.stopCPUProfiler"}`)
+	require.Equal(http.StatusForbidden, w.Code)
+	w = call(g, "/ext/bc/../admin", "secret", `{"method":"admin.stopCPUProfiler"}`)
+	require.Equal(http.StatusForbidden, w.Code)
+
+	// neither are the methods outside the defaults, even in a batch
+	w = call(g, "/ext/bc/C/rpc", "secret", `[{"method":"eth_blockNumber"},{"method":"debug_traceTransaction"}]`)
+	require.Equal(http.StatusForbidden, w.Code)
+	require.Contains(w.Body.String(), "debug_traceTransaction")
+
+	w = call(g, "/ext/bc/C/rpc", "secret", `not json`)
+	require.Equal(http.StatusBadRequest, w.Code)
+	require.Len(node.requests, 2)
+
+	var entries []AccessEntry
+	for _, line := range strings.Split(strings.TrimSpace(accessLog.String()), "\n") {
+		var entry AccessEntry
+		require.NoError(json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	require.Len(entries, 8)
+	require.Equal("partner", entries[0].Key)
+	require.Equal([]string{"eth_blockNumber"}, entries[0].Methods)
+	require.Equal(http.StatusOK, entries[0].Status)
+	require.Equal("unauthorized", entries[2].Error)
+	require.Equal(http.StatusForbidden, entries[6].Status)
+}
+
+func TestGatewayRateLimit(t *testing.T) {
+	require := require.New(t)
+	g, node, _ := newTestGateway(t,
+		Key{Name: "slow", Key: "slow", Rate: 0.001, Burst: 3},
+		Key{Name: "fast", Key: "fast", Rate: 100, Burst: 100},
+	)
+
+	// a batch counts each of its calls
+	w := call(g, "/ext/bc/C/rpc", "slow", `[{"method":"eth_blockNumber"},{"method":"eth_chainId"}]`)
+	require.Equal(http.StatusOK, w.Code)
+	w = call(g, "/ext/bc/C/rpc", "slow", `{"method":"eth_blockNumber"}`)
+	require.Equal(http.StatusOK, w.Code)
+	w = call(g, "/ext/bc/C/rpc", "slow", `{"method":"eth_blockNumber"}`)
+	require.Equal(http.StatusTooManyRequests, w.Code)
+	require.Equal("1", w.Header().Get("Retry-After"))
+
+	// limits are per key
+	w = call(g, "/ext/bc/C/rpc", "fast", `{"method":"eth_blockNumber"}`)
+	require.Equal(http.StatusOK, w.Code)
+	require.Len(node.requests, 3)
+}
+
+func TestKeyAllowed(t *testing.T) {
+	require := require.New(t)
+	k := Key{}
+	require.True(k.Allowed("eth_getBalance"))
+	require.True(k.Allowed("platform.getCurrentValidators"))
+	require.False(k.Allowed("platform.issueTx"))
+	require.False(k.Allowed("admin_addPeer"))
+	require.False(k.Allowed("personal_unlockAccount"))
+
+	k.Methods = []string{"eth_call", "eth_get*"}
+	require.True(k.Allowed("eth_call"))
+	require.True(k.Allowed("eth_getLogs"))
+	require.False(k.Allowed("eth_sendRawTransaction"))
+}
+
+func TestConfig(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), ConfigFile)
+
+	c, err := LoadConfig(path)
+	require.NoError(err)
+	require.Empty(c.Keys)
+
+	k, err := c.AddKey("partner", DefaultRate, DefaultBurst, []string{"eth_*"})
+	require.NoError(err)
+	require.True(strings.HasPrefix(k.Key, "lux_"))
+	_, err = c.AddKey("partner", DefaultRate, DefaultBurst, nil)
+	require.ErrorIs(err, ErrKeyExists)
+	_, err = c.AddKey("other", 0, DefaultBurst, nil)
+	require.ErrorContains(err, "must be positive")
+	_, err = c.AddKey("other", DefaultRate, DefaultBurst, []string{"eth_["})
+	require.ErrorContains(err, "invalid method pattern")
+	_, err = c.AddKey("other", DefaultRate, DefaultBurst, nil)
+	require.NoError(err)
+	require.NoError(c.Save(path))
+
+	c, err = LoadConfig(path)
+	require.NoError(err)
+	require.Len(c.Keys, 2)
+	require.Equal(k, c.Keys[0])
+
+	require.NoError(c.RemoveKey("partner"))
+	require.ErrorIs(c.RemoveKey("partner"), ErrKeyNotFound)
+	require.Len(c.Keys, 1)
+}
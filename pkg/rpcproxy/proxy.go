@@ -0,0 +1,315 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package rpcproxy serves the nodes of a local network behind one stable
+// endpoint. Requests are spread round-robin over the healthy nodes and fail
+// over to the next node when one is down, and /ext/bc/<alias> paths naming
+// a chain are rewritten to its current blockchain ID, so endpoints survive
+// the node ports and blockchain IDs changing between runs.
+package rpcproxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// maxBodySize bounds the requests buffered to be replayed on failover
+	maxBodySize = 5 << 20
+
+	// HealthPath is the node API telling if the node is alive
+	HealthPath = "/ext/health/liveness"
+
+	// DefaultHealthInterval is how often the nodes are checked
+	DefaultHealthInterval = 5 * time.Second
+
+	healthTimeout = 2 * time.Second
+
+	// cChainRPCPath serves requests to the root, as Ethereum tooling
+	// expects the RPC there
+	cChainRPCPath = "/ext/bc/C/rpc"
+)
+
+var errNoNodes = errors.New("no node to forward to")
+
+// Target is what the proxy forwards to
+type Target struct {
+	// Nodes are the API URLs of the nodes
+	Nodes []*url.URL
+	// Aliases maps chain names to their blockchain IDs
+	Aliases map[string]string
+}
+
+// Resolver returns the current target, as it changes when the network is
+// restarted
+type Resolver func() (Target, error)
+
+// NodeStatus is the health of a node as last checked
+type NodeStatus struct {
+	URL     string `json:"url"`
+	Healthy bool   `json:"healthy"`
+}
+
+// Proxy is an http.Handler forwarding requests to the nodes of its target
+type Proxy struct {
+	resolve   Resolver
+	transport http.RoundTripper
+	client    *http.Client
+	proxy     *httputil.ReverseProxy
+	next      atomic.Uint64
+
+	mu      sync.RWMutex
+	target  Target
+	healthy map[string]bool
+
+	// OnHealthChange, when set, is called when a node goes down or comes
+	// back up
+	OnHealthChange func(node string, healthy bool)
+}
+
+// New returns a proxy forwarding to the target resolve returns. Call
+// Refresh before serving, and Run to keep the target and health current.
+func New(resolve Resolver) *Proxy {
+	p := &Proxy{
+		resolve:   resolve,
+		transport: http.DefaultTransport,
+		client:    &http.Client{Timeout: healthTimeout},
+		healthy:   map[string]bool{},
+	}
+	p.proxy = &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.Out.URL.Path = p.rewritePath(r.In.URL.Path)
+			r.Out.URL.RawPath = ""
+			r.SetXForwarded()
+		},
+		Transport: failover{p},
+		ErrorHandler: func(w http.ResponseWriter, _ *http.Request, err error) {
+			writeError(w, http.StatusBadGateway, "no node available: "+err.Error())
+		},
+	}
+	return p
+}
+
+// Refresh resolves the target again and checks the health of its nodes
+func (p *Proxy) Refresh(ctx context.Context) error {
+	target, err := p.resolve()
+	if err != nil {
+		return err
+	}
+	if len(target.Nodes) == 0 {
+		return errNoNodes
+	}
+	healthy := make(map[string]bool, len(target.Nodes))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for _, node := range target.Nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok := p.check(ctx, node)
+			mu.Lock()
+			healthy[node.Host] = ok
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	p.mu.Lock()
+	var changes []NodeStatus
+	for _, node := range target.Nodes {
+		// report the nodes changing health, and the new ones found down
+		was, known := p.healthy[node.Host]
+		if now := healthy[node.Host]; (known && was != now) || (!known && !now) {
+			changes = append(changes, NodeStatus{URL: node.String(), Healthy: now})
+		}
+	}
+	p.target = target
+	p.healthy = healthy
+	p.mu.Unlock()
+
+	if p.OnHealthChange != nil {
+		for _, c := range changes {
+			p.OnHealthChange(c.URL, c.Healthy)
+		}
+	}
+	return nil
+}
+
+// Run refreshes the target every interval until ctx is done. Failed
+// refreshes keep the last target.
+func (p *Proxy) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = p.Refresh(ctx)
+		}
+	}
+}
+
+// Status returns the nodes of the target and their health
+func (p *Proxy) Status() []NodeStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	status := make([]NodeStatus, 0, len(p.target.Nodes))
+	for _, node := range p.target.Nodes {
+		status = append(status, NodeStatus{URL: node.String(), Healthy: p.healthy[node.Host]})
+	}
+	return status
+}
+
+// Aliases returns the chain names the proxy rewrites, to their blockchain
+// IDs
+func (p *Proxy) Aliases() map[string]string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.target.Aliases
+}
+
+func (p *Proxy) check(ctx context.Context, node *url.URL) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, node.JoinPath(HealthPath).String(), nil)
+	if err != nil {
+		return false
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return false
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/" {
+		r.URL.Path = cChainRPCPath
+	}
+	if r.Body != nil && r.Body != http.NoBody {
+		// buffer the body to replay it on the next node
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+		if err != nil {
+			writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	p.proxy.ServeHTTP(w, r)
+}
+
+// rewritePath replaces a chain name following /ext/bc/ with its blockchain
+// ID
+func (p *Proxy) rewritePath(path string) string {
+	rest, ok := strings.CutPrefix(path, "/ext/bc/")
+	if !ok {
+		return path
+	}
+	alias, tail, _ := strings.Cut(rest, "/")
+	p.mu.RLock()
+	id, ok := p.target.Aliases[alias]
+	p.mu.RUnlock()
+	if !ok {
+		return path
+	}
+	if tail == "" && !strings.HasSuffix(rest, "/") {
+		return "/ext/bc/" + id
+	}
+	return "/ext/bc/" + id + "/" + tail
+}
+
+// order returns the nodes to try for a request: the healthy ones, starting
+// from the next in turn, then the others
+func (p *Proxy) order() []*url.URL {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	nodes := p.target.Nodes
+	if len(nodes) == 0 {
+		return nil
+	}
+	start := int((p.next.Add(1) - 1) % uint64(len(nodes)))
+	up := make([]*url.URL, 0, len(nodes))
+	var down []*url.URL
+	for i := range nodes {
+		node := nodes[(start+i)%len(nodes)]
+		if p.healthy[node.Host] {
+			up = append(up, node)
+		} else {
+			down = append(down, node)
+		}
+	}
+	return append(up, down...)
+}
+
+func (p *Proxy) markDown(node *url.URL) {
+	p.mu.Lock()
+	changed := p.healthy[node.Host]
+	p.healthy[node.Host] = false
+	p.mu.Unlock()
+	if changed && p.OnHealthChange != nil {
+		p.OnHealthChange(node.String(), false)
+	}
+}
+
+// failover sends requests to the nodes in order, moving to the next one
+// when a node can't be connected to. Requests reaching a node are not
+// retried, as they may have had effects.
+type failover struct {
+	p *Proxy
+}
+
+func (f failover) RoundTrip(req *http.Request) (*http.Response, error) {
+	nodes := f.p.order()
+	if len(nodes) == 0 {
+		return nil, errNoNodes
+	}
+	var lastErr error
+	for i, node := range nodes {
+		out := req.Clone(req.Context())
+		if i > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			out.Body = body
+		}
+		out.URL.Scheme = node.Scheme
+		out.URL.Host = node.Host
+		out.Host = node.Host
+		resp, err := f.p.transport.RoundTrip(out)
+		if err == nil {
+			return resp, nil
+		}
+		var opErr *net.OpError
+		if req.Context().Err() != nil || !errors.As(err, &opErr) || opErr.Op != "dial" {
+			return nil, err
+		}
+		f.p.markDown(node)
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      nil,
+		"error":   map[string]any{"code": -32603, "message": message},
+	})
+}
@@ -0,0 +1,126 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcproxy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// node records the paths and bodies of the RPC requests reaching it
+type node struct {
+	mu     sync.Mutex
+	paths  []string
+	bodies []string
+}
+
+func (n *node) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == HealthPath {
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.paths = append(n.paths, r.URL.Path)
+	n.bodies = append(n.bodies, string(body))
+	_, _ = io.WriteString(w, `{"jsonrpc":"2.0","id":1,"result":"0x1"}`)
+}
+
+func (n *node) requests() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.paths)
+}
+
+func newTestNode(t *testing.T) (*node, *httptest.Server, *url.URL) {
+	n := &node{}
+	server := httptest.NewServer(n)
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	return n, server, u
+}
+
+func post(p *Proxy, path string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"}`))
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, r)
+	return w
+}
+
+func TestProxy(t *testing.T) {
+	require := require.New(t)
+	first, _, firstURL := newTestNode(t)
+	second, secondServer, secondURL := newTestNode(t)
+	const blockchainID = "2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM"
+	p := New(func() (Target, error) {
+		return Target{
+			Nodes:   []*url.URL{firstURL, secondURL},
+			Aliases: map[string]string{"mychain": blockchainID},
+		}, nil
+	})
+	var changes []NodeStatus
+	p.OnHealthChange = func(node string, healthy bool) {
+		changes = append(changes, NodeStatus{URL: node, Healthy: healthy})
+	}
+	require.NoError(p.Refresh(context.Background()))
+	require.Empty(changes)
+	for _, s := range p.Status() {
+		require.True(s.Healthy)
+	}
+
+	// requests are spread over the nodes, chain names rewritten
+	require.Equal(http.StatusOK, post(p, "/ext/bc/mychain/rpc").Code)
+	require.Equal(http.StatusOK, post(p, "/ext/bc/C/rpc").Code)
+	require.Equal(http.StatusOK, post(p, "/").Code)
+	require.Equal([]string{"/ext/bc/" + blockchainID + "/rpc", "/ext/bc/C/rpc"}, first.paths)
+	require.Equal([]string{"/ext/bc/C/rpc"}, second.paths)
+
+	// a node going down is failed over, with the request body replayed
+	secondServer.Close()
+	for range 4 {
+		require.Equal(http.StatusOK, post(p, "/ext/bc/C/rpc").Code)
+	}
+	require.Equal(6, first.requests())
+	require.Contains(first.bodies[5], "eth_blockNumber")
+	require.Equal([]NodeStatus{{URL: secondURL.String(), Healthy: false}}, changes)
+
+	require.NoError(p.Refresh(context.Background()))
+	require.Equal([]NodeStatus{{URL: firstURL.String(), Healthy: true}, {URL: secondURL.String(), Healthy: false}}, p.Status())
+	require.Len(changes, 1)
+}
+
+func TestProxyNoNode(t *testing.T) {
+	require := require.New(t)
+	_, server, u := newTestNode(t)
+	p := New(func() (Target, error) { return Target{Nodes: []*url.URL{u}}, nil })
+	require.NoError(p.Refresh(context.Background()))
+	server.Close()
+	w := post(p, "/ext/bc/C/rpc")
+	require.Equal(http.StatusBadGateway, w.Code)
+	require.Contains(w.Body.String(), "no node available")
+
+	p = New(func() (Target, error) { return Target{}, nil })
+	require.ErrorIs(p.Refresh(context.Background()), errNoNodes)
+}
+
+func TestRewritePath(t *testing.T) {
+	require := require.New(t)
+	p := New(nil)
+	p.target.Aliases = map[string]string{"mychain": "id"}
+	require.Equal("/ext/bc/id/rpc", p.rewritePath("/ext/bc/mychain/rpc"))
+	require.Equal("/ext/bc/id/ws", p.rewritePath("/ext/bc/mychain/ws"))
+	require.Equal("/ext/bc/id", p.rewritePath("/ext/bc/mychain"))
+	require.Equal("/ext/bc/C/rpc", p.rewritePath("/ext/bc/C/rpc"))
+	require.Equal("/ext/bc/other/rpc", p.rewritePath("/ext/bc/other/rpc"))
+	require.Equal("/ext/info", p.rewritePath("/ext/info"))
+}
@@ -0,0 +1,206 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package secretref renders JSON config templates that reference secrets of
+// a KMS server as kms://secret/<path>/<name>, so that configs pushed to
+// remote hosts never hold raw keys.
+package secretref
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Prefix starts the reference to a secret in a config string value
+const Prefix = "kms://secret/"
+
+// refPattern matches a JSON string holding a reference
+var refPattern = regexp.MustCompile(`"kms://secret/[^"\\]+"`)
+
+// secretKeys are the substrings of the config keys holding secrets
+var secretKeys = []string{"private-key", "privatekey", "key-file-content", "password", "secret", "mnemonic", "api-key"}
+
+// Ref is a secret of a KMS server, by folder path and name
+type Ref struct {
+	Path string
+	Name string
+}
+
+// Parse reads a kms://secret/<path>/<name> reference. The path may be
+// empty, for secrets at the root folder.
+func Parse(s string) (Ref, error) {
+	rest, ok := strings.CutPrefix(s, Prefix)
+	if !ok {
+		return Ref{}, fmt.Errorf("invalid secret reference %q, expected %s<path>/<name>", s, Prefix)
+	}
+	dir, name := path.Split(rest)
+	if name == "" {
+		return Ref{}, fmt.Errorf("invalid secret reference %q, missing the secret name", s)
+	}
+	return Ref{Path: path.Clean("/" + dir), Name: name}, nil
+}
+
+// String returns the reference to r, as found in configs
+func (r Ref) String() string {
+	return Prefix + strings.TrimPrefix(path.Join(r.Path, r.Name), "/")
+}
+
+// IsRef tells if s is a secret reference
+func IsRef(s string) bool {
+	return strings.HasPrefix(s, Prefix)
+}
+
+// Resolver returns the value of referenced secrets
+type Resolver interface {
+	ResolveSecret(ctx context.Context, ref Ref) ([]byte, error)
+}
+
+// Store keeps the value of secrets, replacing the previous one
+type Store interface {
+	StoreSecret(ctx context.Context, ref Ref, value []byte) error
+}
+
+// KMS resolves and stores secrets, as the KMS server does
+type KMS interface {
+	Resolver
+	Store
+}
+
+// ConfigDir is the folder of the secrets taken out of the config of kind
+// of chainName, such as "chain" or "node"
+func ConfigDir(chainName, kind string) string {
+	return path.Join("/chains", chainName, kind)
+}
+
+// Refs returns the secrets referenced by data, sorted
+func Refs(data []byte) []Ref {
+	seen := map[Ref]bool{}
+	var refs []Ref
+	for _, quoted := range refPattern.FindAll(data, -1) {
+		ref, err := Parse(string(quoted[1 : len(quoted)-1]))
+		if err != nil || seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].String() < refs[j].String() })
+	return refs
+}
+
+// Render returns data with each string value referencing a secret replaced
+// by the value of that secret. The rest of data is left as is.
+func Render(ctx context.Context, data []byte, r Resolver) ([]byte, error) {
+	refs := Refs(data)
+	if len(refs) == 0 {
+		return data, nil
+	}
+	if r == nil {
+		return nil, fmt.Errorf("config references %s, but no KMS server was given to resolve it", refs[0])
+	}
+	values := map[string][]byte{}
+	for _, ref := range refs {
+		value, err := r.ResolveSecret(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", ref, err)
+		}
+		quoted, err := json.Marshal(string(value))
+		if err != nil {
+			return nil, err
+		}
+		values[ref.String()] = quoted
+	}
+	return refPattern.ReplaceAllFunc(data, func(quoted []byte) []byte {
+		if value, ok := values[string(quoted[1:len(quoted)-1])]; ok {
+			return value
+		}
+		return quoted
+	}), nil
+}
+
+// IsSecretKey tells if a config key holds a secret, such as
+// account-private-key or staking-tls-key-file-content
+func IsSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range secretKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Extract takes the secrets held in plain text out of the JSON config data:
+// the string values of the keys IsSecretKey tells about. It returns the
+// config with references in their place, named after the dotted key of each
+// secret in the dir folder, and the secrets to store by reference. Data
+// without such secrets is returned as is.
+func Extract(data []byte, dir string) ([]byte, map[Ref][]byte, error) {
+	var config interface{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		// not a JSON config, so no keyed secret to take out
+		return data, nil, nil
+	}
+	secrets := map[Ref][]byte{}
+	config = extract(config, "", dir, false, secrets)
+	if len(secrets) == 0 {
+		return data, nil, nil
+	}
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(config); err != nil {
+		return nil, nil, err
+	}
+	return bytes.TrimSuffix(out.Bytes(), []byte("\n")), secrets, nil
+}
+
+func extract(v interface{}, key, dir string, secret bool, secrets map[Ref][]byte) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			v[k] = extract(child, joinKey(key, k), dir, IsSecretKey(k), secrets)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = extract(child, joinKey(key, strconv.Itoa(i)), dir, secret, secrets)
+		}
+	case string:
+		if secret && v != "" && !IsRef(v) {
+			ref := Ref{Path: path.Clean("/" + dir), Name: key}
+			secrets[ref] = []byte(v)
+			return ref.String()
+		}
+	}
+	return v
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// StoreAll stores secrets, as returned by Extract
+func StoreAll(ctx context.Context, s Store, secrets map[Ref][]byte) error {
+	refs := make([]Ref, 0, len(secrets))
+	for ref := range secrets {
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].String() < refs[j].String() })
+	for _, ref := range refs {
+		if err := s.StoreSecret(ctx, ref, secrets[ref]); err != nil {
+			return fmt.Errorf("failed to store %s: %w", ref, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package secretref
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// memStore keeps secrets by reference
+type memStore map[Ref][]byte
+
+func (s memStore) ResolveSecret(_ context.Context, ref Ref) ([]byte, error) {
+	value, ok := s[ref]
+	if !ok {
+		return nil, errors.New("secret not found")
+	}
+	return value, nil
+}
+
+func (s memStore) StoreSecret(_ context.Context, ref Ref, value []byte) error {
+	s[ref] = value
+	return nil
+}
+
+func TestParse(t *testing.T) {
+	require := require.New(t)
+	ref, err := Parse("kms://secret/relayer/prod/account-key")
+	require.NoError(err)
+	require.Equal(Ref{Path: "/relayer/prod", Name: "account-key"}, ref)
+	require.Equal("kms://secret/relayer/prod/account-key", ref.String())
+
+	ref, err = Parse("kms://secret/api-key")
+	require.NoError(err)
+	require.Equal(Ref{Path: "/", Name: "api-key"}, ref)
+	require.Equal("kms://secret/api-key", ref.String())
+
+	_, err = Parse("kms://secret/relayer/")
+	require.ErrorContains(err, "missing the secret name")
+	_, err = Parse("kms://3f2a")
+	require.ErrorContains(err, "expected kms://secret/<path>/<name>")
+}
+
+func TestRender(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	store := memStore{
+		{Path: "/relayer", Name: "account-key"}: []byte("0x5628"),
+		{Path: "/", Name: "password"}:           []byte(`p"ss`),
+	}
+	template := []byte(`{
+  "destination-blockchains": [
+    {"blockchain-id": "zoo", "account-private-key": "kms://secret/relayer/account-key"},
+    {"blockchain-id": "hanzo", "account-private-key": "kms://secret/relayer/account-key"}
+  ],
+  "db-password": "kms://secret/password",
+  "docs": "see kms://secret/relayer/account-key"
+}`)
+	require.Equal([]Ref{{Path: "/", Name: "password"}, {Path: "/relayer", Name: "account-key"}}, Refs(template))
+
+	rendered, err := Render(ctx, template, store)
+	require.NoError(err)
+	require.Equal(`{
+  "destination-blockchains": [
+    {"blockchain-id": "zoo", "account-private-key": "0x5628"},
+    {"blockchain-id": "hanzo", "account-private-key": "0x5628"}
+  ],
+  "db-password": "p\"ss",
+  "docs": "see kms://secret/relayer/account-key"
+}`, string(rendered))
+
+	plain := []byte(`{"log-level": "info"}`)
+	rendered, err = Render(ctx, plain, nil)
+	require.NoError(err)
+	require.Equal(plain, rendered)
+
+	_, err = Render(ctx, template, nil)
+	require.ErrorContains(err, "no KMS server")
+	_, err = Render(ctx, []byte(`{"key": "kms://secret/missing"}`), store)
+	require.ErrorContains(err, "failed to resolve kms://secret/missing: secret not found")
+}
+
+func TestExtract(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	config := []byte(`{
+  "destination-blockchains": [
+    {"blockchain-id": "zoo", "account-private-key": "0x5628"},
+    {"blockchain-id": "hanzo", "account-private-key": "kms://secret/relayer/account-key"}
+  ],
+  "staking-signer-key-file-content": "c2lnbmVy",
+  "api-admin-password": "",
+  "log-level": "info"
+}`)
+	template, secrets, err := Extract(config, ConfigDir("zoo", "node"))
+	require.NoError(err)
+	require.Equal(map[Ref][]byte{
+		{Path: "/chains/zoo/node", Name: "destination-blockchains.0.account-private-key"}: []byte("0x5628"),
+		{Path: "/chains/zoo/node", Name: "staking-signer-key-file-content"}:               []byte("c2lnbmVy"),
+	}, secrets)
+
+	var got map[string]interface{}
+	require.NoError(json.Unmarshal(template, &got))
+	require.Equal("kms://secret/chains/zoo/node/staking-signer-key-file-content", got["staking-signer-key-file-content"])
+	require.Equal("", got["api-admin-password"])
+	destinations := got["destination-blockchains"].([]interface{})
+	require.Equal("kms://secret/chains/zoo/node/destination-blockchains.0.account-private-key", destinations[0].(map[string]interface{})["account-private-key"])
+	require.Equal("kms://secret/relayer/account-key", destinations[1].(map[string]interface{})["account-private-key"])
+
+	// the template renders back to the config
+	store := memStore{{Path: "/relayer", Name: "account-key"}: []byte("0x9a1c")}
+	require.NoError(StoreAll(ctx, store, secrets))
+	rendered, err := Render(ctx, template, store)
+	require.NoError(err)
+	var want map[string]interface{}
+	require.NoError(json.Unmarshal(config, &want))
+	want["destination-blockchains"].([]interface{})[1].(map[string]interface{})["account-private-key"] = "0x9a1c"
+	require.NoError(json.Unmarshal(rendered, &got))
+	require.Equal(want, got)
+
+	// configs without plain text secrets are left as is
+	for _, data := range []string{`{"log-level": "info"}`, `not json`} {
+		template, secrets, err = Extract([]byte(data), "/")
+		require.NoError(err)
+		require.Equal(data, string(template))
+		require.Empty(secrets)
+	}
+}
@@ -0,0 +1,267 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package sigagg aggregates the BLS signatures of a validator set over Warp
+// messages. It is meant to run as a long-running local service, so the
+// validator connections and the signatures it collected outlive a single
+// command: repeating an operation over the same message signs it from the
+// cache instead of asking every validator again.
+package sigagg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/warp"
+)
+
+const (
+	// DefaultQuorumPercentage is the stake share that must sign a message
+	// when the request sets none
+	DefaultQuorumPercentage = 67
+	// DefaultFetchTimeout bounds each request for a validator signature
+	DefaultFetchTimeout = 10 * time.Second
+	// DefaultRetries is how many times a failed signature request is retried
+	DefaultRetries = 2
+)
+
+var (
+	ErrQuorum           = errors.New("not enough stake signed the message")
+	errInvalidSignature = errors.New("invalid signature")
+)
+
+// ValidatorSource returns the validators of a subnet
+type ValidatorSource interface {
+	Validators(ctx context.Context, subnetID ids.ID) ([]*warp.Validator, error)
+}
+
+// SignatureSource asks a validator for its signature over a message
+type SignatureSource interface {
+	Signature(ctx context.Context, nodeID ids.NodeID, msg *warp.UnsignedMessage, justification []byte) ([]byte, error)
+}
+
+// Rerouter is a SignatureSource that can look for another way to reach a
+// validator whose request failed
+type Rerouter interface {
+	// Reroute finds another API endpoint of nodeID, and tells whether the
+	// next request goes through it
+	Reroute(ctx context.Context, nodeID ids.NodeID) bool
+}
+
+// PeerAdder is a SignatureSource that can reach more validators through
+// extra node APIs
+type PeerAdder interface {
+	// AddPeers reaches the nodes at uris, and returns the errors of the
+	// URIs that failed
+	AddPeers(ctx context.Context, uris []string) map[string]error
+}
+
+// Stats counts where the signatures of the aggregations came from
+type Stats struct {
+	Aggregations uint64 `json:"aggregations"`
+	CacheHits    uint64 `json:"cacheHits"`
+	Fetched      uint64 `json:"fetched"`
+	Failed       uint64 `json:"failed"`
+	// CachedMessages is how many messages have cached signatures
+	CachedMessages int `json:"cachedMessages"`
+}
+
+// Aggregator collects validator signatures into signed Warp messages
+type Aggregator struct {
+	// FetchTimeout bounds each request for a validator signature
+	FetchTimeout time.Duration
+	// Retries is how many times a failed request is retried, through
+	// another endpoint of the validator when the source is a Rerouter
+	Retries int
+
+	validators ValidatorSource
+	signatures SignatureSource
+	cache      *Cache
+	progress   *progressLog
+
+	aggregations atomic.Uint64
+	cacheHits    atomic.Uint64
+	fetched      atomic.Uint64
+	failed       atomic.Uint64
+}
+
+// New returns an aggregator that caches the signatures of up to cacheSize
+// messages
+func New(validators ValidatorSource, signatures SignatureSource, cacheSize int) *Aggregator {
+	return &Aggregator{
+		FetchTimeout: DefaultFetchTimeout,
+		Retries:      DefaultRetries,
+		validators:   validators,
+		signatures:   signatures,
+		cache:        NewCache(cacheSize),
+		progress:     &progressLog{},
+	}
+}
+
+// AddPeers reaches more validators through the node APIs at uris, and
+// returns the errors of the URIs that failed
+func (a *Aggregator) AddPeers(ctx context.Context, uris []string) (map[string]error, error) {
+	adder, ok := a.signatures.(PeerAdder)
+	if !ok {
+		return nil, errors.New("the signature source takes no extra peers")
+	}
+	return adder.AddPeers(ctx, uris), nil
+}
+
+// Stats returns the counters of the aggregator
+func (a *Aggregator) Stats() Stats {
+	return Stats{
+		Aggregations:   a.aggregations.Load(),
+		CacheHits:      a.cacheHits.Load(),
+		Fetched:        a.fetched.Load(),
+		Failed:         a.failed.Load(),
+		CachedMessages: a.cache.Len(),
+	}
+}
+
+type signatureResult struct {
+	index int
+	sig   *bls.Signature
+	err   error
+}
+
+// Aggregate signs msg with the validators of subnetID until quorumPercentage
+// of their stake signed. Cached signatures are used first, the missing ones
+// are asked for concurrently and stop being waited for once the quorum is
+// reached.
+func (a *Aggregator) Aggregate(
+	ctx context.Context,
+	msg *warp.UnsignedMessage,
+	justification []byte,
+	subnetID ids.ID,
+	quorumPercentage uint64,
+) (*warp.Message, error) {
+	if quorumPercentage == 0 {
+		quorumPercentage = DefaultQuorumPercentage
+	}
+	if quorumPercentage > 100 {
+		return nil, fmt.Errorf("quorum percentage %d is greater than 100", quorumPercentage)
+	}
+	validators, err := a.validators.Validators(ctx, subnetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the validators of %s: %w", subnetID, err)
+	}
+	set, err := warp.NewCanonicalValidatorSet(validators)
+	if err != nil {
+		return nil, err
+	}
+	validators = set.Validators()
+	a.aggregations.Add(1)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	msgBytes := msg.Bytes()
+	msgID := msg.ID()
+	p := a.progress.start(msgID, subnetID, quorumPercentage, set.TotalWeight(), validators)
+	defer a.progress.finish(p)
+	cached := a.cache.Get(msgID)
+	results := make(chan signatureResult, len(validators))
+	for i, v := range validators {
+		if sigBytes, ok := cached[v.NodeID]; ok {
+			a.cacheHits.Add(1)
+			a.progress.update(p, i, StatusCached, nil)
+			sig, err := bls.SignatureFromBytes(sigBytes)
+			results <- signatureResult{index: i, sig: sig, err: err}
+			continue
+		}
+		go func() {
+			sig, err := a.fetchWithRetries(ctx, p, i, v, msg, msgBytes, justification)
+			if err == nil {
+				a.fetched.Add(1)
+				a.cache.Add(msgID, v.NodeID, bls.SignatureToBytes(sig))
+			} else if ctx.Err() == nil {
+				a.failed.Add(1)
+			}
+			results <- signatureResult{index: i, sig: sig, err: err}
+		}()
+	}
+
+	signers := warp.NewBitSet()
+	var (
+		sigs         []*bls.Signature
+		signedWeight uint64
+		errs         []error
+	)
+	for range validators {
+		r := <-results
+		if r.err != nil {
+			a.progress.update(p, r.index, StatusFailed, r.err)
+			errs = append(errs, fmt.Errorf("%s: %w", validators[r.index].NodeID, r.err))
+			continue
+		}
+		a.progress.signed(p, r.index, validators[r.index].Weight)
+		signers.Add(r.index)
+		sigs = append(sigs, r.sig)
+		signedWeight += validators[r.index].Weight
+		if warp.VerifyWeight(signedWeight, set.TotalWeight(), quorumPercentage, 100) == nil {
+			break
+		}
+	}
+	if warp.VerifyWeight(signedWeight, set.TotalWeight(), quorumPercentage, 100) != nil {
+		err := fmt.Errorf("%w: weight %d of %d, %d%% needed", ErrQuorum, signedWeight, set.TotalWeight(), quorumPercentage)
+		return nil, errors.Join(append([]error{err}, errs...)...)
+	}
+	aggregated, err := warp.AggregateSignatures(sigs)
+	if err != nil {
+		return nil, err
+	}
+	var signature [bls.SignatureLen]byte
+	copy(signature[:], bls.SignatureToBytes(aggregated))
+	return warp.NewMessage(msg, warp.NewBitSetSignature(signers, signature))
+}
+
+// fetchWithRetries asks validator v, the i-th of the aggregation p, for its
+// signature over msg. Failed requests are retried, through another endpoint
+// of v when one is found.
+func (a *Aggregator) fetchWithRetries(
+	ctx context.Context,
+	p *Progress,
+	i int,
+	v *warp.Validator,
+	msg *warp.UnsignedMessage,
+	msgBytes, justification []byte,
+) (*bls.Signature, error) {
+	rerouter, _ := a.signatures.(Rerouter)
+	for attempt := 0; ; attempt++ {
+		sig, err := a.fetch(ctx, v, msg, msgBytes, justification)
+		if err == nil || ctx.Err() != nil || attempt >= a.Retries || errors.Is(err, errInvalidSignature) {
+			return sig, err
+		}
+		a.progress.update(p, i, StatusRetrying, err)
+		if rerouter != nil {
+			rerouter.Reroute(ctx, v.NodeID)
+		}
+	}
+}
+
+// fetch asks validator v for its signature over msg, within the fetch
+// timeout, and checks it
+func (a *Aggregator) fetch(ctx context.Context, v *warp.Validator, msg *warp.UnsignedMessage, msgBytes, justification []byte) (*bls.Signature, error) {
+	if a.FetchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.FetchTimeout)
+		defer cancel()
+	}
+	sigBytes, err := a.signatures.Signature(ctx, v.NodeID, msg, justification)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := bls.SignatureFromBytes(sigBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidSignature, err)
+	}
+	if !bls.Verify(v.PublicKey, sig, msgBytes) {
+		return nil, errInvalidSignature
+	}
+	return sig, nil
+}
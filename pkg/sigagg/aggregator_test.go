@@ -0,0 +1,254 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sigagg
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	sdkwarp "github.com/luxfi/sdk/warp"
+	"github.com/luxfi/warp"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeValidators []*warp.Validator
+
+func (v fakeValidators) Validators(context.Context, ids.ID) ([]*warp.Validator, error) {
+	return v, nil
+}
+
+// fakeSigners signs with the keys of the validators, counting the requests
+type fakeSigners struct {
+	mu       sync.Mutex
+	keys     map[ids.NodeID]*bls.SecretKey
+	down     map[ids.NodeID]bool
+	requests int
+}
+
+func (s *fakeSigners) Signature(_ context.Context, nodeID ids.NodeID, msg *warp.UnsignedMessage, _ []byte) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	if s.down[nodeID] {
+		return nil, errors.New("unreachable")
+	}
+	sig, err := s.keys[nodeID].Sign(msg.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return bls.SignatureToBytes(sig), nil
+}
+
+func newValidatorSet(t *testing.T, weights ...uint64) (fakeValidators, *fakeSigners) {
+	t.Helper()
+	signers := &fakeSigners{keys: map[ids.NodeID]*bls.SecretKey{}, down: map[ids.NodeID]bool{}}
+	var validators fakeValidators
+	for i, weight := range weights {
+		sk, err := bls.NewSecretKey()
+		require.NoError(t, err)
+		pk := sk.PublicKey()
+		nodeID := ids.BuildTestNodeID([]byte{byte(i + 1)})
+		validators = append(validators, warp.NewValidator(pk, bls.PublicKeyToCompressedBytes(pk), weight, nodeID))
+		signers.keys[nodeID] = sk
+	}
+	return validators, signers
+}
+
+func TestAggregate(t *testing.T) {
+	require := require.New(t)
+	validators, signers := newValidatorSet(t, 10, 20, 10)
+	msg, err := warp.NewUnsignedMessage(1337, ids.GenerateTestID(), []byte("hello"))
+	require.NoError(err)
+	a := New(validators, signers, 8)
+
+	signed, err := a.Aggregate(context.Background(), msg, nil, ids.Empty, 100)
+	require.NoError(err)
+	canonical, err := warp.NewCanonicalValidatorSet(validators)
+	require.NoError(err)
+	require.NoError(signed.Signature.Verify(msg.Bytes(), canonical.Validators()))
+	weight, err := signed.Signature.GetSignedWeight(canonical.Validators())
+	require.NoError(err)
+	require.Equal(uint64(40), weight)
+	require.Equal(3, signers.requests)
+
+	// the repeat signs from the cache, even with a validator down
+	signers.down[validators[0].NodeID] = true
+	_, err = a.Aggregate(context.Background(), msg, nil, ids.Empty, 100)
+	require.NoError(err)
+	require.Equal(3, signers.requests)
+	stats := a.Stats()
+	require.Equal(uint64(2), stats.Aggregations)
+	require.Equal(uint64(3), stats.CacheHits)
+	require.Equal(1, stats.CachedMessages)
+
+	// a new message falls short of the quorum without that validator
+	other, err := warp.NewUnsignedMessage(1337, ids.GenerateTestID(), []byte("other"))
+	require.NoError(err)
+	_, err = a.Aggregate(context.Background(), other, nil, ids.Empty, 100)
+	require.ErrorIs(err, ErrQuorum)
+	require.ErrorContains(err, "unreachable")
+	signed, err = a.Aggregate(context.Background(), other, nil, ids.Empty, 0)
+	require.NoError(err)
+	weight, err = signed.Signature.GetSignedWeight(canonical.Validators())
+	require.NoError(err)
+	require.Equal(uint64(30), weight)
+
+	_, err = a.Aggregate(context.Background(), msg, nil, ids.Empty, 101)
+	require.ErrorContains(err, "greater than 100")
+}
+
+func TestAggregateRejectsBadSignature(t *testing.T) {
+	require := require.New(t)
+	validators, signers := newValidatorSet(t, 10, 10)
+	// the second validator signs with the key of the first
+	signers.keys[validators[1].NodeID] = signers.keys[validators[0].NodeID]
+	msg, err := warp.NewUnsignedMessage(1337, ids.GenerateTestID(), []byte("hello"))
+	require.NoError(err)
+	a := New(validators, signers, 8)
+
+	_, err = a.Aggregate(context.Background(), msg, nil, ids.Empty, 100)
+	require.ErrorIs(err, errInvalidSignature)
+	require.Equal(uint64(1), a.Stats().Failed)
+}
+
+// reroutingSigners brings a down validator back up when rerouted
+type reroutingSigners struct {
+	*fakeSigners
+	rerouted []ids.NodeID
+}
+
+func (s *reroutingSigners) Reroute(_ context.Context, nodeID ids.NodeID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rerouted = append(s.rerouted, nodeID)
+	delete(s.down, nodeID)
+	return true
+}
+
+func TestAggregateRetries(t *testing.T) {
+	require := require.New(t)
+	validators, signers := newValidatorSet(t, 10, 10)
+	signers.down[validators[1].NodeID] = true
+	msg, err := warp.NewUnsignedMessage(1337, ids.GenerateTestID(), []byte("hello"))
+	require.NoError(err)
+
+	// without a way around, the validator is retried and fails
+	a := New(validators, signers, 8)
+	a.Retries = 1
+	_, err = a.Aggregate(context.Background(), msg, nil, ids.Empty, 100)
+	require.ErrorIs(err, ErrQuorum)
+	require.Equal(3, signers.requests)
+	progress := a.Progress()
+	require.Len(progress, 1)
+	require.True(progress[0].Done)
+	require.Equal(uint64(10), progress[0].SignedWeight)
+	require.Equal(StatusSigned, validatorProgress(progress[0], validators[0].NodeID).Status)
+	failed := validatorProgress(progress[0], validators[1].NodeID)
+	require.Equal(StatusFailed, failed.Status)
+	require.Equal(2, failed.Attempts)
+	require.Equal("unreachable", failed.Error)
+
+	// the retry goes through the rerouted endpoint
+	rerouting := &reroutingSigners{fakeSigners: signers}
+	a = New(validators, rerouting, 8)
+	_, err = a.Aggregate(context.Background(), msg, nil, ids.Empty, 100)
+	require.NoError(err)
+	require.Equal([]ids.NodeID{validators[1].NodeID}, rerouting.rerouted)
+	progress = a.Progress()
+	require.Equal(uint64(20), progress[0].SignedWeight)
+	rerouted := validatorProgress(progress[0], validators[1].NodeID)
+	require.Equal(StatusSigned, rerouted.Status)
+	require.Equal(1, rerouted.Attempts)
+
+	// the repeat is served from the cache
+	_, err = a.Aggregate(context.Background(), msg, nil, ids.Empty, 100)
+	require.NoError(err)
+	progress = a.Progress()
+	require.Len(progress, 2)
+	for _, v := range progress[1].Validators {
+		require.Equal(StatusCached, v.Status)
+	}
+}
+
+func validatorProgress(p Progress, nodeID ids.NodeID) ValidatorProgress {
+	for _, v := range p.Validators {
+		if v.NodeID == nodeID {
+			return v
+		}
+	}
+	return ValidatorProgress{}
+}
+
+func TestCache(t *testing.T) {
+	require := require.New(t)
+	c := NewCache(2)
+	a, b, d := ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	c.Add(a, nodeID, []byte{1})
+	c.Add(b, nodeID, []byte{2})
+	require.NotNil(c.Get(a))
+	c.Add(d, nodeID, []byte{3})
+	require.Nil(c.Get(b))
+	require.Equal([]byte{1}, c.Get(a)[nodeID])
+	require.Equal(2, c.Len())
+}
+
+func TestHandler(t *testing.T) {
+	require := require.New(t)
+	validators, signers := newValidatorSet(t, 10)
+	server := httptest.NewServer(Handler(New(validators, signers, 8)))
+	defer server.Close()
+	msg, err := warp.NewUnsignedMessage(1337, ids.GenerateTestID(), []byte("hello"))
+	require.NoError(err)
+
+	post := func(req sdkwarp.AggregateSignatureRequest) *http.Response {
+		body, err := json.Marshal(req)
+		require.NoError(err)
+		resp, err := http.Post(server.URL+AggregatePath, "application/json", bytes.NewReader(body)) //nolint:noctx // test server
+		require.NoError(err)
+		return resp
+	}
+
+	resp := post(sdkwarp.AggregateSignatureRequest{Message: hex.EncodeToString(msg.Bytes()), QuorumPercentage: 67})
+	defer resp.Body.Close()
+	require.Equal(http.StatusOK, resp.StatusCode)
+	var reply sdkwarp.AggregateSignatureResponse
+	require.NoError(json.NewDecoder(resp.Body).Decode(&reply))
+	require.NotEmpty(reply.SignedMessage)
+
+	resp = post(sdkwarp.AggregateSignatureRequest{Message: "zz"})
+	defer resp.Body.Close()
+	require.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "/stats") //nolint:noctx // test server
+	require.NoError(err)
+	defer resp.Body.Close()
+	var stats Stats
+	require.NoError(json.NewDecoder(resp.Body).Decode(&stats))
+	require.Equal(uint64(1), stats.Fetched)
+
+	resp, err = http.Get(server.URL + "/progress") //nolint:noctx // test server
+	require.NoError(err)
+	defer resp.Body.Close()
+	var progress []Progress
+	require.NoError(json.NewDecoder(resp.Body).Decode(&progress))
+	require.Len(progress, 1)
+	require.Equal(msg.ID(), progress[0].MessageID)
+	require.Equal(StatusSigned, progress[0].Validators[0].Status)
+
+	// the fake signers take no extra peers
+	resp, err = http.Post(server.URL+"/peers", "application/json", bytes.NewReader([]byte(`{"uris":["http://127.0.0.1:9630"]}`))) //nolint:noctx // test server
+	require.NoError(err)
+	defer resp.Body.Close()
+	require.Equal(http.StatusNotImplemented, resp.StatusCode)
+}
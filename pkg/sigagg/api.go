@@ -0,0 +1,127 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sigagg
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/luxfi/ids"
+	sdkwarp "github.com/luxfi/sdk/warp"
+	"github.com/luxfi/warp"
+)
+
+// AggregatePath is where the service takes aggregation requests
+const AggregatePath = "/aggregate-signatures"
+
+// Handler serves the API of the aggregator:
+//
+//	POST /aggregate-signatures   sign a message, as sdk/warp.SignMessage asks
+//	GET  /health                 whether the service is up
+//	GET  /stats                  cache and signature counters
+//	GET  /progress               per-validator progress of the aggregations
+//	POST /peers                  reach more validators: {"uris": [...]}
+func Handler(a *Aggregator) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+AggregatePath, func(w http.ResponseWriter, r *http.Request) {
+		var req sdkwarp.AggregateSignatureRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+			return
+		}
+		msg, justification, subnetID, err := parseRequest(req)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		signed, err := a.Aggregate(r.Context(), msg, justification, subnetID, req.QuorumPercentage)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, ErrQuorum) {
+				status = http.StatusServiceUnavailable
+			}
+			writeError(w, status, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, sdkwarp.AggregateSignatureResponse{SignedMessage: hex.EncodeToString(signed.Bytes())})
+	})
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]bool{"healthy": true})
+	})
+	mux.HandleFunc("GET /stats", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, a.Stats())
+	})
+	mux.HandleFunc("GET /progress", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, a.Progress())
+	})
+	mux.HandleFunc("POST /peers", func(w http.ResponseWriter, r *http.Request) {
+		var req PeersRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+			return
+		}
+		failed, err := a.AddPeers(r.Context(), req.URIs)
+		if err != nil {
+			writeError(w, http.StatusNotImplemented, err)
+			return
+		}
+		resp := PeersResponse{Failed: map[string]string{}}
+		for _, uri := range req.URIs {
+			if err, ok := failed[strings.TrimSuffix(uri, "/")]; ok {
+				resp.Failed[uri] = err.Error()
+			} else {
+				resp.Added++
+			}
+		}
+		writeJSON(w, http.StatusOK, resp)
+	})
+	return mux
+}
+
+// PeersRequest is the body of POST /peers
+type PeersRequest struct {
+	URIs []string `json:"uris"`
+}
+
+// PeersResponse is the reply of POST /peers
+type PeersResponse struct {
+	Added  int               `json:"added"`
+	Failed map[string]string `json:"failed,omitempty"`
+}
+
+func parseRequest(req sdkwarp.AggregateSignatureRequest) (*warp.UnsignedMessage, []byte, ids.ID, error) {
+	msgBytes, err := hex.DecodeString(strings.TrimPrefix(req.Message, "0x"))
+	if err != nil {
+		return nil, nil, ids.Empty, fmt.Errorf("invalid message hex: %w", err)
+	}
+	msg, err := warp.ParseUnsignedMessage(msgBytes)
+	if err != nil {
+		return nil, nil, ids.Empty, err
+	}
+	justification, err := hex.DecodeString(strings.TrimPrefix(req.Justification, "0x"))
+	if err != nil {
+		return nil, nil, ids.Empty, fmt.Errorf("invalid justification hex: %w", err)
+	}
+	subnetID := ids.Empty
+	if req.SigningChainID != "" {
+		if subnetID, err = ids.FromString(req.SigningChainID); err != nil {
+			return nil, nil, ids.Empty, fmt.Errorf("invalid signing chain ID: %w", err)
+		}
+	}
+	return msg, justification, subnetID, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
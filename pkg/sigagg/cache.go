@@ -0,0 +1,79 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sigagg
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/luxfi/ids"
+)
+
+// DefaultCacheSize is how many messages the cache keeps signatures for
+const DefaultCacheSize = 1024
+
+// Cache keeps the validator signatures of the most recently aggregated
+// messages, keyed by message hash
+type Cache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List // of ids.ID, most recently used first
+	byID  map[ids.ID]*cacheEntry
+}
+
+type cacheEntry struct {
+	elem       *list.Element
+	signatures map[ids.NodeID][]byte
+}
+
+// NewCache returns a cache of the signatures of up to size messages
+func NewCache(size int) *Cache {
+	return &Cache{
+		size:  max(size, 1),
+		order: list.New(),
+		byID:  map[ids.ID]*cacheEntry{},
+	}
+}
+
+// Get returns a copy of the signatures cached for message id
+func (c *Cache) Get(id ids.ID) map[ids.NodeID][]byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.byID[id]
+	if !ok {
+		return nil
+	}
+	c.order.MoveToFront(entry.elem)
+	signatures := make(map[ids.NodeID][]byte, len(entry.signatures))
+	for nodeID, sig := range entry.signatures {
+		signatures[nodeID] = sig
+	}
+	return signatures
+}
+
+// Add caches the signature of nodeID over message id, evicting the least
+// recently used message when full
+func (c *Cache) Add(id ids.ID, nodeID ids.NodeID, signature []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.byID[id]
+	if !ok {
+		if c.order.Len() >= c.size {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.byID, oldest.Value.(ids.ID))
+		}
+		entry = &cacheEntry{elem: c.order.PushFront(id), signatures: map[ids.NodeID][]byte{}}
+		c.byID[id] = entry
+	}
+	c.order.MoveToFront(entry.elem)
+	entry.signatures[nodeID] = signature
+}
+
+// Len returns how many messages have cached signatures
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
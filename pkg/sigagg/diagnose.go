@@ -0,0 +1,167 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sigagg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/warp"
+)
+
+// Prober reaches the validators of a signing set
+type Prober interface {
+	SignatureSource
+	// PublicKey returns the BLS key the info API of nodeID reports
+	PublicKey(ctx context.Context, nodeID ids.NodeID) ([]byte, error)
+}
+
+// NodeDiagnosis is the state of one validator of a signing set
+type NodeDiagnosis struct {
+	NodeID ids.NodeID `json:"nodeID"`
+	Weight uint64     `json:"weight"`
+	// Responded tells whether the info API of the node answered
+	Responded bool `json:"responded"`
+	// KeyMatches tells whether the node runs with the BLS key registered
+	// on the P-Chain
+	KeyMatches bool `json:"keyMatches"`
+	// Signed tells whether the node signed the message, when one was given
+	Signed  bool   `json:"signed"`
+	Problem string `json:"problem,omitempty"`
+	// Fix suggests how to get the node signing
+	Fix string `json:"fix,omitempty"`
+}
+
+// OK tells whether the node contributes its weight
+func (n NodeDiagnosis) OK() bool {
+	return n.Problem == ""
+}
+
+// Diagnosis tells which validators of a signing set can sign, and whether
+// their stake reaches the quorum
+type Diagnosis struct {
+	QuorumPercentage uint64 `json:"quorumPercentage"`
+	TotalWeight      uint64 `json:"totalWeight"`
+	RequiredWeight   uint64 `json:"requiredWeight"`
+	// CollectedWeight is the stake of the validators that responded with
+	// their registered key, or that signed the message when one was given
+	CollectedWeight uint64 `json:"collectedWeight"`
+	// MessageChecked tells whether the validators were asked to sign
+	MessageChecked bool `json:"messageChecked"`
+	// Nodes are the validators, heaviest first
+	Nodes []NodeDiagnosis `json:"nodes"`
+}
+
+// QuorumReached tells whether the collected stake is enough to sign
+func (d *Diagnosis) QuorumReached() bool {
+	return d.CollectedWeight > 0 && d.CollectedWeight >= d.RequiredWeight
+}
+
+// ToFix returns the failing validators whose stake closes the gap to the
+// quorum, heaviest first
+func (d *Diagnosis) ToFix() []NodeDiagnosis {
+	var toFix []NodeDiagnosis
+	weight := d.CollectedWeight
+	for _, n := range d.Nodes {
+		if weight >= d.RequiredWeight && weight > 0 {
+			break
+		}
+		if !n.OK() {
+			toFix = append(toFix, n)
+			weight += n.Weight
+		}
+	}
+	return toFix
+}
+
+// Diagnose asks every validator for its BLS key and, when msg is given, for
+// its signature over msg, and reports how much stake is collected against
+// the quorumPercentage needed
+func Diagnose(
+	ctx context.Context,
+	validators []*warp.Validator,
+	prober Prober,
+	msg *warp.UnsignedMessage,
+	quorumPercentage uint64,
+) (*Diagnosis, error) {
+	if quorumPercentage == 0 {
+		quorumPercentage = DefaultQuorumPercentage
+	}
+	if quorumPercentage > 100 {
+		return nil, fmt.Errorf("quorum percentage %d is greater than 100", quorumPercentage)
+	}
+	if len(validators) == 0 {
+		return nil, errors.New("the signing set has no validators with a BLS key")
+	}
+	d := &Diagnosis{
+		QuorumPercentage: quorumPercentage,
+		MessageChecked:   msg != nil,
+		Nodes:            make([]NodeDiagnosis, len(validators)),
+	}
+	var wg sync.WaitGroup
+	for i, v := range validators {
+		d.TotalWeight += v.Weight
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.Nodes[i] = diagnoseNode(ctx, v, prober, msg)
+		}()
+	}
+	wg.Wait()
+
+	required := new(big.Int).SetUint64(d.TotalWeight)
+	required.Mul(required, new(big.Int).SetUint64(quorumPercentage))
+	required.Add(required, big.NewInt(99))
+	d.RequiredWeight = required.Div(required, big.NewInt(100)).Uint64()
+	for _, n := range d.Nodes {
+		if n.OK() {
+			d.CollectedWeight += n.Weight
+		}
+	}
+	sort.SliceStable(d.Nodes, func(i, j int) bool {
+		return d.Nodes[i].Weight > d.Nodes[j].Weight
+	})
+	return d, nil
+}
+
+func diagnoseNode(ctx context.Context, v *warp.Validator, prober Prober, msg *warp.UnsignedMessage) NodeDiagnosis {
+	n := NodeDiagnosis{NodeID: v.NodeID, Weight: v.Weight}
+	publicKey, err := prober.PublicKey(ctx, v.NodeID)
+	if err != nil {
+		n.Problem = err.Error()
+		n.Fix = "make its info API reachable, or give its API URI with --peer-uri"
+		return n
+	}
+	n.Responded = true
+	if !bytes.Equal(publicKey, v.PublicKeyBytes) {
+		n.Problem = "runs with a BLS key other than the one registered on the P-Chain"
+		n.Fix = "restart it with its registered signer key, or register its current key"
+		return n
+	}
+	n.KeyMatches = true
+	if msg == nil {
+		return n
+	}
+	sigBytes, err := prober.Signature(ctx, v.NodeID, msg, nil)
+	if err != nil {
+		n.Problem = err.Error()
+		n.Fix = fmt.Sprintf("check it tracks chain %s and is bootstrapped", msg.SourceChainID)
+		return n
+	}
+	sig, err := bls.SignatureFromBytes(sigBytes)
+	if err != nil || !bls.Verify(v.PublicKey, sig, msg.Bytes()) {
+		n.Problem = errInvalidSignature.Error()
+		n.Fix = "check the signer key it runs with"
+		return n
+	}
+	n.Signed = true
+	return n
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sigagg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/warp"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProber reports the keys of the signers, except for the nodes down
+type fakeProber struct {
+	*fakeSigners
+}
+
+func (p fakeProber) PublicKey(_ context.Context, nodeID ids.NodeID) ([]byte, error) {
+	if p.down[nodeID] {
+		return nil, errors.New("unreachable")
+	}
+	return bls.PublicKeyToCompressedBytes(p.keys[nodeID].PublicKey()), nil
+}
+
+func TestDiagnose(t *testing.T) {
+	require := require.New(t)
+	validators, signers := newValidatorSet(t, 10, 40, 20, 30)
+	prober := fakeProber{signers}
+
+	d, err := Diagnose(context.Background(), validators, prober, nil, 0)
+	require.NoError(err)
+	require.Equal(uint64(100), d.TotalWeight)
+	require.Equal(uint64(67), d.RequiredWeight)
+	require.Equal(uint64(100), d.CollectedWeight)
+	require.True(d.QuorumReached())
+	require.Empty(d.ToFix())
+	require.Equal(uint64(40), d.Nodes[0].Weight)
+
+	// the heaviest node is down and another runs with the wrong key
+	signers.down[validators[1].NodeID] = true
+	other, err := bls.NewSecretKey()
+	require.NoError(err)
+	signers.keys[validators[2].NodeID] = other
+	msg, err := warp.NewUnsignedMessage(1337, ids.GenerateTestID(), []byte("hello"))
+	require.NoError(err)
+	d, err = Diagnose(context.Background(), validators, prober, msg, 67)
+	require.NoError(err)
+	require.True(d.MessageChecked)
+	require.Equal(uint64(40), d.CollectedWeight)
+	require.False(d.QuorumReached())
+	for _, n := range d.Nodes {
+		switch n.NodeID {
+		case validators[1].NodeID:
+			require.False(n.Responded)
+		case validators[2].NodeID:
+			require.True(n.Responded)
+			require.False(n.KeyMatches)
+		default:
+			require.True(n.Signed)
+		}
+	}
+	toFix := d.ToFix()
+	require.Len(toFix, 1)
+	require.Equal(validators[1].NodeID, toFix[0].NodeID)
+
+	_, err = Diagnose(context.Background(), nil, prober, nil, 0)
+	require.ErrorContains(err, "no validators")
+}
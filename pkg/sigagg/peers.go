@@ -0,0 +1,377 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sigagg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/geth/common/hexutil"
+	"github.com/luxfi/geth/rpc"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/warp"
+)
+
+const (
+	// discoverTimeout bounds reaching a validator found through the peers,
+	// whose API is often not public
+	discoverTimeout = 5 * time.Second
+	// defaultAPIPort is the API port of nodes when no endpoint tells it
+	defaultAPIPort = 9630
+)
+
+var errUnknownPeer = errors.New("no API endpoint known for validator")
+
+// Peers reaches validators over their node APIs. It keeps one client per
+// node and chain for its lifetime, so repeated requests reuse the
+// connections.
+type Peers struct {
+	mu         sync.Mutex
+	uris       map[ids.NodeID]string
+	publicKeys map[ids.NodeID][]byte
+	clients    map[string]*rpc.Client
+}
+
+// NewPeers returns the peers serving their API at uris, asking each node
+// for its ID
+func NewPeers(ctx context.Context, uris []string) (*Peers, error) {
+	p, failed := ConnectPeers(ctx, uris)
+	for _, uri := range uris {
+		if err, ok := failed[strings.TrimSuffix(uri, "/")]; ok {
+			p.Close()
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// ConnectPeers returns the peers among uris that answered, and the errors
+// of the others by URI
+func ConnectPeers(ctx context.Context, uris []string) (*Peers, map[string]error) {
+	p := &Peers{uris: map[ids.NodeID]string{}, publicKeys: map[ids.NodeID][]byte{}, clients: map[string]*rpc.Client{}}
+	failed := map[string]error{}
+	for _, uri := range uris {
+		uri = strings.TrimSuffix(uri, "/")
+		if err := p.add(ctx, uri); err != nil {
+			failed[uri] = err
+		}
+	}
+	return p, failed
+}
+
+// AddPeers reaches the nodes at uris as well, and returns the errors of the
+// URIs that failed
+func (p *Peers) AddPeers(ctx context.Context, uris []string) map[string]error {
+	failed := map[string]error{}
+	for _, uri := range uris {
+		uri = strings.TrimSuffix(uri, "/")
+		if err := p.add(ctx, uri); err != nil {
+			failed[uri] = err
+		}
+	}
+	return failed
+}
+
+// add asks the node at uri for its ID and BLS key
+func (p *Peers) add(ctx context.Context, uri string) error {
+	client, err := p.client(ctx, uri+"/ext/info")
+	if err != nil {
+		return err
+	}
+	var reply struct {
+		NodeID  ids.NodeID `json:"nodeID"`
+		NodePOP *struct {
+			PublicKey string `json:"publicKey"`
+		} `json:"nodePOP"`
+	}
+	if err := client.CallContext(ctx, &reply, "info.getNodeID", struct{}{}); err != nil {
+		return fmt.Errorf("failed to get the node ID of %s: %w", uri, err)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.uris[reply.NodeID] = uri
+	if reply.NodePOP != nil {
+		if publicKey, err := hexutil.Decode(reply.NodePOP.PublicKey); err == nil {
+			p.publicKeys[reply.NodeID] = publicKey
+		}
+	}
+	return nil
+}
+
+// Discover looks up the nodeIDs not reached yet among the peers of the
+// reached nodes, and reaches them at their public IP on apiPort
+func (p *Peers) Discover(ctx context.Context, nodeIDs []ids.NodeID, apiPort int) {
+	missing := map[ids.NodeID]bool{}
+	for _, nodeID := range nodeIDs {
+		if _, ok := p.URI(nodeID); !ok {
+			missing[nodeID] = true
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	for _, uri := range p.URIs() {
+		client, err := p.client(ctx, uri+"/ext/info")
+		if err != nil {
+			continue
+		}
+		var reply struct {
+			Peers []struct {
+				IP     string     `json:"ip"`
+				NodeID ids.NodeID `json:"nodeID"`
+			} `json:"peers"`
+		}
+		if err := client.CallContext(ctx, &reply, "info.peers", struct{}{}); err != nil {
+			continue
+		}
+		for _, peer := range reply.Peers {
+			if !missing[peer.NodeID] {
+				continue
+			}
+			host, _, err := net.SplitHostPort(peer.IP)
+			if err != nil {
+				continue
+			}
+			addCtx, cancel := context.WithTimeout(ctx, discoverTimeout)
+			if p.add(addCtx, "http://"+net.JoinHostPort(host, strconv.Itoa(apiPort))) == nil {
+				delete(missing, peer.NodeID)
+			}
+			cancel()
+		}
+		return
+	}
+}
+
+// Reroute looks for nodeID among the peers of the other reached nodes, and
+// reaches it at the public IP they know it by, on the API port of the
+// failed endpoint. It tells whether nodeID is now reached at another
+// endpoint.
+func (p *Peers) Reroute(ctx context.Context, nodeID ids.NodeID) bool {
+	current, _ := p.URI(nodeID)
+	apiPort := defaultAPIPort
+	for _, uri := range append([]string{current}, p.URIs()...) {
+		if u, err := url.Parse(uri); err == nil && u.Port() != "" {
+			if port, err := strconv.Atoi(u.Port()); err == nil {
+				apiPort = port
+				break
+			}
+		}
+	}
+	for _, uri := range p.URIs() {
+		if uri == current {
+			continue
+		}
+		client, err := p.client(ctx, uri+"/ext/info")
+		if err != nil {
+			continue
+		}
+		var reply struct {
+			Peers []struct {
+				IP     string     `json:"ip"`
+				NodeID ids.NodeID `json:"nodeID"`
+			} `json:"peers"`
+		}
+		if err := client.CallContext(ctx, &reply, "info.peers", map[string][]ids.NodeID{"nodeIDs": {nodeID}}); err != nil {
+			continue
+		}
+		for _, peer := range reply.Peers {
+			if peer.NodeID != nodeID {
+				continue
+			}
+			host, _, err := net.SplitHostPort(peer.IP)
+			if err != nil {
+				continue
+			}
+			candidate := "http://" + net.JoinHostPort(host, strconv.Itoa(apiPort))
+			if candidate == current {
+				continue
+			}
+			addCtx, cancel := context.WithTimeout(ctx, discoverTimeout)
+			err = p.add(addCtx, candidate)
+			cancel()
+			if reached, _ := p.URI(nodeID); err == nil && reached == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// URI returns the API endpoint of nodeID, if reached
+func (p *Peers) URI(nodeID ids.NodeID) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	uri, ok := p.uris[nodeID]
+	return uri, ok
+}
+
+// URIs returns the API endpoints of the reached nodes
+func (p *Peers) URIs() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	uris := make([]string, 0, len(p.uris))
+	for _, uri := range p.uris {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	return uris
+}
+
+// PublicKey returns the BLS key nodeID reported through its info API
+func (p *Peers) PublicKey(_ context.Context, nodeID ids.NodeID) ([]byte, error) {
+	if _, ok := p.URI(nodeID); !ok {
+		return nil, fmt.Errorf("%w %s", errUnknownPeer, nodeID)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	publicKey, ok := p.publicKeys[nodeID]
+	if !ok {
+		return nil, fmt.Errorf("%s reports no BLS key: it is not configured to sign", nodeID)
+	}
+	return publicKey, nil
+}
+
+// NodeIDs returns the nodes the peers reach
+func (p *Peers) NodeIDs() []ids.NodeID {
+	nodeIDs := make([]ids.NodeID, 0, len(p.uris))
+	for nodeID := range p.uris {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	return nodeIDs
+}
+
+// Signature asks the source chain of msg on nodeID for its signature over
+// msg. The chain signs the messages it sent, so the justification is not
+// needed.
+func (p *Peers) Signature(ctx context.Context, nodeID ids.NodeID, msg *warp.UnsignedMessage, _ []byte) ([]byte, error) {
+	uri, ok := p.URI(nodeID)
+	if !ok {
+		return nil, fmt.Errorf("%w %s", errUnknownPeer, nodeID)
+	}
+	client, err := p.client(ctx, fmt.Sprintf("%s/ext/bc/%s/rpc", uri, msg.SourceChainID))
+	if err != nil {
+		return nil, err
+	}
+	var sig hexutil.Bytes
+	if err := client.CallContext(ctx, &sig, "warp_getMessageSignature", msg.ID()); err != nil {
+		return nil, fmt.Errorf("failed to get signature: %w", err)
+	}
+	return sig, nil
+}
+
+// client returns the client of url, dialing it on first use
+func (p *Peers) client(ctx context.Context, url string) (*rpc.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if client, ok := p.clients[url]; ok {
+		return client, nil
+	}
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", url, err)
+	}
+	p.clients[url] = client
+	return client, nil
+}
+
+// Close closes the connections to the peers
+func (p *Peers) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for url, client := range p.clients {
+		client.Close()
+		delete(p.clients, url)
+	}
+}
+
+// PChainValidators reads validator sets from the P-Chain API of a node
+type PChainValidators struct {
+	client *rpc.Client
+}
+
+// NewPChainValidators returns the validator source of the node at uri
+func NewPChainValidators(ctx context.Context, uri string) (*PChainValidators, error) {
+	client, err := rpc.DialContext(ctx, strings.TrimSuffix(uri, "/")+"/ext/bc/P")
+	if err != nil {
+		return nil, err
+	}
+	return &PChainValidators{client: client}, nil
+}
+
+// apiUint64 is a number the node API sends as a string
+type apiUint64 uint64
+
+func (n *apiUint64) UnmarshalJSON(b []byte) error {
+	v, err := strconv.ParseUint(strings.Trim(string(b), `"`), 10, 64)
+	*n = apiUint64(v)
+	return err
+}
+
+// Validators returns the validators of subnetID with a BLS key, at the
+// current P-Chain height
+func (v *PChainValidators) Validators(ctx context.Context, subnetID ids.ID) ([]*warp.Validator, error) {
+	var height struct {
+		Height apiUint64 `json:"height"`
+	}
+	if err := v.client.CallContext(ctx, &height, "platform.getHeight", struct{}{}); err != nil {
+		return nil, fmt.Errorf("failed to get P-Chain height: %w", err)
+	}
+	var reply struct {
+		Validators map[ids.NodeID]struct {
+			PublicKey string    `json:"publicKey"`
+			Weight    apiUint64 `json:"weight"`
+		} `json:"validators"`
+	}
+	args := map[string]string{"subnetID": subnetID.String(), "height": strconv.FormatUint(uint64(height.Height), 10)}
+	if err := v.client.CallContext(ctx, &reply, "platform.getValidatorsAt", args); err != nil {
+		return nil, fmt.Errorf("failed to get validators: %w", err)
+	}
+	validators := make([]*warp.Validator, 0, len(reply.Validators))
+	for nodeID, vdr := range reply.Validators {
+		if vdr.PublicKey == "" {
+			continue
+		}
+		pkBytes, err := hexutil.Decode(vdr.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key of %s: %w", nodeID, err)
+		}
+		pk, err := bls.PublicKeyFromCompressedBytes(pkBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key of %s: %w", nodeID, err)
+		}
+		validators = append(validators, warp.NewValidator(pk, pkBytes, uint64(vdr.Weight), nodeID))
+	}
+	return validators, nil
+}
+
+// L1Validator is a validator registered on the P-Chain by its validation ID
+type L1Validator struct {
+	SubnetID ids.ID     `json:"subnetID"`
+	NodeID   ids.NodeID `json:"nodeID"`
+	Weight   apiUint64  `json:"weight"`
+}
+
+// L1Validator returns the validator registered with validationID
+func (v *PChainValidators) L1Validator(ctx context.Context, validationID ids.ID) (*L1Validator, error) {
+	var reply L1Validator
+	args := map[string]string{"validationID": validationID.String()}
+	if err := v.client.CallContext(ctx, &reply, "platform.getL1Validator", args); err != nil {
+		return nil, fmt.Errorf("failed to get validator %s: %w", validationID, err)
+	}
+	return &reply, nil
+}
+
+// Close closes the connection to the node
+func (v *PChainValidators) Close() {
+	v.client.Close()
+}
@@ -0,0 +1,126 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sigagg
+
+import (
+	"sync"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/warp"
+)
+
+// maxFinished is how many finished aggregations the progress keeps
+const maxFinished = 16
+
+// ValidatorStatus is where the signature of a validator stands
+type ValidatorStatus string
+
+const (
+	StatusPending  ValidatorStatus = "pending"
+	StatusCached   ValidatorStatus = "cached"
+	StatusSigned   ValidatorStatus = "signed"
+	StatusRetrying ValidatorStatus = "retrying"
+	StatusFailed   ValidatorStatus = "failed"
+)
+
+// ValidatorProgress is the signature collection of one validator
+type ValidatorProgress struct {
+	NodeID ids.NodeID      `json:"nodeID"`
+	Weight uint64          `json:"weight"`
+	Status ValidatorStatus `json:"status"`
+	// Attempts counts the failed requests
+	Attempts int    `json:"attempts,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Progress is the signature collection of one aggregation
+type Progress struct {
+	MessageID    ids.ID              `json:"messageID"`
+	SubnetID     ids.ID              `json:"subnetID"`
+	Started      time.Time           `json:"started"`
+	Done         bool                `json:"done"`
+	Quorum       uint64              `json:"quorumPercentage"`
+	TotalWeight  uint64              `json:"totalWeight"`
+	SignedWeight uint64              `json:"signedWeight"`
+	Validators   []ValidatorProgress `json:"validators"`
+}
+
+// progressLog tracks the running aggregations and the last finished ones
+type progressLog struct {
+	mu      sync.Mutex
+	entries []*Progress
+}
+
+func (l *progressLog) start(msgID, subnetID ids.ID, quorum, totalWeight uint64, validators []*warp.Validator) *Progress {
+	p := &Progress{
+		MessageID:   msgID,
+		SubnetID:    subnetID,
+		Started:     time.Now(),
+		Quorum:      quorum,
+		TotalWeight: totalWeight,
+		Validators:  make([]ValidatorProgress, len(validators)),
+	}
+	for i, v := range validators {
+		p.Validators[i] = ValidatorProgress{NodeID: v.NodeID, Weight: v.Weight, Status: StatusPending}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, p)
+	return p
+}
+
+func (l *progressLog) update(p *Progress, i int, status ValidatorStatus, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	v := &p.Validators[i]
+	v.Status = status
+	if err != nil {
+		v.Attempts++
+		v.Error = err.Error()
+	}
+}
+
+func (l *progressLog) signed(p *Progress, i int, weight uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if p.Validators[i].Status != StatusCached {
+		p.Validators[i].Status = StatusSigned
+	}
+	p.SignedWeight += weight
+}
+
+// finish marks p done, and drops the oldest finished aggregations
+func (l *progressLog) finish(p *Progress) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	p.Done = true
+	finished := 0
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		if !l.entries[i].Done {
+			continue
+		}
+		if finished++; finished > maxFinished {
+			l.entries = append(l.entries[:i], l.entries[i+1:]...)
+		}
+	}
+}
+
+// snapshot returns a copy of the tracked aggregations, oldest first
+func (l *progressLog) snapshot() []Progress {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make([]Progress, len(l.entries))
+	for i, p := range l.entries {
+		out[i] = *p
+		out[i].Validators = append([]ValidatorProgress(nil), p.Validators...)
+	}
+	return out
+}
+
+// Progress returns the running aggregations and the last finished ones,
+// oldest first
+func (a *Aggregator) Progress() []Progress {
+	return a.progress.snapshot()
+}
@@ -0,0 +1,233 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package signatureaggregator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/sigagg"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+)
+
+const (
+	serviceHealthTimeout = 2 * time.Second
+	// servicePeersTimeout bounds reaching the extra peers, a few seconds
+	// each
+	servicePeersTimeout = time.Minute
+	// progressPollInterval is how often WatchAggregation asks the service
+	// for the progress
+	progressPollInterval = time.Second
+)
+
+// ServiceRunFile records the aggregator service running for a network
+type ServiceRunFile struct {
+	Pid  int `json:"pid"`
+	Port int `json:"port"`
+}
+
+// WriteServiceRunFile records that this process serves the aggregator of
+// network on port
+func WriteServiceRunFile(app *application.Lux, network models.Network, port int) error {
+	runBytes, err := json.Marshal(ServiceRunFile{Pid: os.Getpid(), Port: port})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(app.GetRunDir(), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(app.GetAggregatorRunPath(network.Name()), runBytes, application.WriteReadReadPerms)
+}
+
+// RemoveServiceRunFile removes the run file of the aggregator of network
+func RemoveServiceRunFile(app *application.Lux, network models.Network) {
+	_ = os.Remove(app.GetAggregatorRunPath(network.Name()))
+}
+
+// serviceURL returns the base URL of the aggregator service of network,
+// on the port its run file records or the default one
+func serviceURL(app *application.Lux, network models.Network) string {
+	port := DefaultSignatureAggregatorPort
+	if runBytes, err := os.ReadFile(app.GetAggregatorRunPath(network.Name())); err == nil {
+		var rf ServiceRunFile
+		if json.Unmarshal(runBytes, &rf) == nil && rf.Port != 0 {
+			port = rf.Port
+		}
+	}
+	return fmt.Sprintf("http://localhost:%d", port)
+}
+
+// checkService fails when no aggregator service answers at url
+func checkService(url string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), serviceHealthTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+"/health", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check returned %s", resp.Status)
+	}
+	return nil
+}
+
+// AddServicePeers has the aggregator service of network reach the
+// validators at the node APIs uris too. It returns the URIs that failed,
+// with their error.
+func AddServicePeers(app *application.Lux, network models.Network, uris []string) (map[string]string, error) {
+	if len(uris) == 0 {
+		return nil, nil
+	}
+	body, err := json.Marshal(sigagg.PeersRequest{URIs: uris})
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), servicePeersTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, serviceURL(app, network)+"/peers", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the signature aggregator refused the peers: %s", resp.Status)
+	}
+	var reply sigagg.PeersResponse
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return nil, err
+	}
+	return reply.Failed, nil
+}
+
+// WatchAggregation runs fn, which has the aggregator service of network
+// sign messages, and prints the signatures the service collects for it,
+// validator by validator. It fails when fn has not returned within timeout,
+// leaving fn running.
+func WatchAggregation(app *application.Lux, network models.Network, timeout time.Duration, fn func() error) error {
+	url := serviceURL(app, network)
+	w := &progressWatcher{since: time.Now(), seen: map[ids.ID]*sigagg.Progress{}}
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	ticker := time.NewTicker(progressPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-done:
+			w.poll(url)
+			return err
+		case <-ticker.C:
+			w.poll(url)
+		case <-expired:
+			return fmt.Errorf("signature aggregation did not finish within %s%s: check the validators with 'lux warp sig-agg diagnose'", timeout, w.stalled())
+		}
+	}
+}
+
+// progressWatcher prints the changes in the aggregations started since
+type progressWatcher struct {
+	since time.Time
+	seen  map[ids.ID]*sigagg.Progress
+}
+
+func (w *progressWatcher) poll(url string) {
+	progress, err := fetchProgress(url)
+	if err != nil {
+		return
+	}
+	for i := range progress {
+		p := &progress[i]
+		if p.Started.Before(w.since) {
+			continue
+		}
+		last, ok := w.seen[p.MessageID]
+		if !ok {
+			ux.Logger.PrintToUser("Collecting signatures on message %s from %d validators, %d%% of the weight needed",
+				p.MessageID, len(p.Validators), p.Quorum)
+			last = &sigagg.Progress{}
+		}
+		previous := map[ids.NodeID]sigagg.ValidatorStatus{}
+		for _, v := range last.Validators {
+			previous[v.NodeID] = v.Status
+		}
+		for _, v := range p.Validators {
+			if v.Status == sigagg.StatusPending || v.Status == previous[v.NodeID] {
+				continue
+			}
+			line := fmt.Sprintf("  %s (weight %d): %s", v.NodeID, v.Weight, v.Status)
+			if v.Error != "" && (v.Status == sigagg.StatusRetrying || v.Status == sigagg.StatusFailed) {
+				line += ": " + v.Error
+			}
+			ux.Logger.PrintToUser("%s", line)
+		}
+		if p.Done && !last.Done {
+			ux.Logger.PrintToUser("  signed weight %d of %d", p.SignedWeight, p.TotalWeight)
+		}
+		w.seen[p.MessageID] = p
+	}
+}
+
+// stalled tells which validators the running aggregations still wait for
+func (w *progressWatcher) stalled() string {
+	waiting := 0
+	for _, p := range w.seen {
+		if p.Done {
+			continue
+		}
+		for _, v := range p.Validators {
+			if v.Status == sigagg.StatusPending || v.Status == sigagg.StatusRetrying {
+				waiting++
+			}
+		}
+	}
+	if waiting == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", waiting on %d validator(s)", waiting)
+}
+
+// fetchProgress returns the aggregations the service at url tracks
+func fetchProgress(url string) ([]sigagg.Progress, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), serviceHealthTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+"/progress", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("progress returned %s", resp.Status)
+	}
+	var progress []sigagg.Progress
+	return progress, json.NewDecoder(resp.Body).Decode(&progress)
+}
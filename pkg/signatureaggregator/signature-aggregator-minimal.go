@@ -4,7 +4,11 @@
 package signatureaggregator
 
 import (
+	"fmt"
+
 	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/sigagg"
+	"github.com/luxfi/cli/pkg/ux"
 	luxlog "github.com/luxfi/log"
 	"github.com/luxfi/log/level"
 	"github.com/luxfi/sdk/models"
@@ -61,42 +65,48 @@ func GetLatestSignatureAggregatorReleaseVersion() (string, error) {
 	return "v1.16.44", nil
 }
 
-// UpdateSignatureAggregatorPeers updates the peers for the signature aggregator.
+// UpdateSignatureAggregatorPeers has the signature aggregator service of the network
+// reach the validators at the node APIs extraAggregatorPeers too, warning about the
+// ones it can't reach.
 func UpdateSignatureAggregatorPeers(
 	app *application.Lux,
 	network models.Network,
 	extraAggregatorPeers []string,
 	logger luxlog.Logger,
 ) error {
-	// Peer management is handled by the SDK's warp package internally
-	// This function exists for compatibility but peers are managed automatically
+	failed, err := AddServicePeers(app, network, extraAggregatorPeers)
+	if err != nil {
+		return err
+	}
+	for uri, reason := range failed {
+		ux.Logger.PrintToUser("Warning: the signature aggregator can't reach %s: %s", uri, reason)
+	}
 	logger.Info("Signature aggregator peers updated",
 		zap.Strings("extra_peers", extraAggregatorPeers),
+		zap.Int("failed", len(failed)),
 		zap.String("network", network.Name()),
 	)
 	return nil
 }
 
 // GetSignatureAggregatorEndpoint returns the signature aggregator endpoint for the given network.
+// The endpoint is served by 'lux warp sig-agg serve', on the port its run file records.
 func GetSignatureAggregatorEndpoint(app *application.Lux, network models.Network) (string, error) {
-	// For local networks, use localhost
-	if network.Kind() == models.Local || network.Kind() == models.Devnet {
-		return "http://localhost:8090/aggregate-signatures", nil
-	}
-
-	// For other networks, use the default mainnet/testnet endpoint
-	// The actual endpoint would be provided by network configuration
-	return "http://localhost:8090/aggregate-signatures", nil
+	return serviceURL(app, network) + sigagg.AggregatePath, nil
 }
 
-// CreateSignatureAggregatorInstance creates an instance of the signature aggregator.
-// This initializes the aggregator for the specified chain and network.
+// CreateSignatureAggregatorInstance checks that the signature aggregator service of the
+// network is running, so the operations needing signatures reuse its connections and cache.
 func CreateSignatureAggregatorInstance(app *application.Lux, chainID string, network models.Network, extraPeers []interface{}, logger luxlog.Logger, version string) error {
-	// The signature aggregator is now managed by the SDK's warp package
-	// No explicit instance creation is needed - the SDK handles this internally
+	url := serviceURL(app, network)
+	if err := checkService(url); err != nil {
+		return fmt.Errorf("no signature aggregator at %s (%w): start one with 'lux warp sig-agg serve'", url, err)
+	}
 	logger.Info("Signature aggregator instance ready",
 		zap.String("chain_id", chainID),
 		zap.String("network", network.Name()),
+		zap.String("endpoint", url),
+		zap.Int("extra_peers", len(extraPeers)),
 		zap.String("version", version),
 	)
 	return nil
@@ -0,0 +1,167 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package smoke
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/luxfi/cli/pkg/contract"
+	"github.com/luxfi/cli/pkg/precompiles"
+	"github.com/luxfi/cli/pkg/warp"
+	luxcommon "github.com/luxfi/crypto/common"
+	"github.com/luxfi/sdk/evm"
+)
+
+// Names of the EVM checks
+const (
+	CheckRPC   = "rpc"
+	CheckTxs   = "send txs"
+	CheckERC20 = "deploy ERC-20"
+	CheckWarp  = "warp round-trip"
+)
+
+var (
+	// txAmount is sent by each tx of the send txs check
+	txAmount = big.NewInt(1_000_000_000)
+	// tokenSupply is minted by the ERC-20 of the deploy check
+	tokenSupply = new(big.Int).Mul(big.NewInt(1_000_000), big.NewInt(1e18))
+)
+
+// EVMTarget is an EVM chain under test
+type EVMTarget struct {
+	RPCURL string
+	// ChainID is the EVM chain ID of the genesis, nil to accept any
+	ChainID *big.Int
+	// PrivateKey is the hex key of a funded account, which pays for the
+	// checks
+	PrivateKey string
+	// Txs is the number of txs of the send txs check
+	Txs int
+	// Warp tells whether the genesis activates the Warp precompile
+	Warp bool
+}
+
+// EVMChecks returns the smoke tests of an EVM chain: the RPC answers for
+// the right chain, txs and a contract deploy are accepted, and a Warp
+// message the chain sends is signed by its validators
+func EVMChecks(t EVMTarget) []Check {
+	return []Check{
+		{Name: CheckRPC, Run: t.checkRPC},
+		{Name: CheckTxs, Needs: []string{CheckRPC}, Run: t.checkTxs},
+		{Name: CheckERC20, Needs: []string{CheckRPC}, Run: t.checkERC20},
+		{Name: CheckWarp, Needs: []string{CheckRPC}, Run: t.checkWarp},
+	}
+}
+
+func (t EVMTarget) checkRPC(_ context.Context) (string, error) {
+	client, err := evm.GetClient(t.RPCURL)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+	chainID, err := client.GetChainID()
+	if err != nil {
+		return "", err
+	}
+	if t.ChainID != nil && chainID.Cmp(t.ChainID) != 0 {
+		return "", fmt.Errorf("%s serves chain ID %s, the genesis has %s", t.RPCURL, chainID, t.ChainID)
+	}
+	height, err := client.BlockNumber()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("chain ID %s at block %d", chainID, height), nil
+}
+
+func (t EVMTarget) checkTxs(_ context.Context) (string, error) {
+	client, err := evm.GetClient(t.RPCURL)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+	var to luxcommon.Address
+	if _, err := rand.Read(to[:]); err != nil {
+		return "", err
+	}
+	start := time.Now()
+	for i := 0; i < t.Txs; i++ {
+		if _, err := client.FundAddress(t.PrivateKey, to.Hex(), txAmount); err != nil {
+			return "", fmt.Errorf("tx %d of %d: %w", i+1, t.Txs, err)
+		}
+	}
+	elapsed := time.Since(start)
+	balance, err := client.GetAddressBalance(to.Hex())
+	if err != nil {
+		return "", err
+	}
+	want := new(big.Int).Mul(txAmount, big.NewInt(int64(t.Txs)))
+	if balance.Cmp(want) != 0 {
+		return "", fmt.Errorf("%s holds %s after %d txs, expected %s", to.Hex(), balance, t.Txs, want)
+	}
+	return fmt.Sprintf("%d txs accepted in %s", t.Txs, elapsed.Round(time.Millisecond)), nil
+}
+
+func (t EVMTarget) checkERC20(_ context.Context) (string, error) {
+	owner, err := evm.PrivateKeyToAddress(t.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+	token, err := contract.DeployERC20(t.RPCURL, t.PrivateKey, "SMOKE", owner, tokenSupply)
+	if err != nil {
+		return "", err
+	}
+	out, err := contract.CallToMethod(t.RPCURL, token, "balanceOf(address)->(uint256)", owner)
+	if err != nil {
+		return "", err
+	}
+	balance, err := contract.GetSmartContractCallResult[*big.Int]("balanceOf", out)
+	if err != nil {
+		return "", err
+	}
+	if balance.Cmp(tokenSupply) != 0 {
+		return "", fmt.Errorf("the deployer holds %s of the token at %s, expected %s", balance, token.Hex(), tokenSupply)
+	}
+	return "token deployed at " + token.Hex(), nil
+}
+
+func (t EVMTarget) checkWarp(ctx context.Context) (string, error) {
+	if !t.Warp {
+		return "", ErrSkip("the genesis does not activate Warp")
+	}
+	from, err := evm.PrivateKeyToAddress(t.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+	_, receipt, err := contract.TxToMethod(
+		t.RPCURL,
+		false,
+		from,
+		t.PrivateKey,
+		luxcommon.BytesToAddress(precompiles.WarpPrecompile.Bytes()),
+		nil,
+		"send warp message",
+		nil,
+		"sendWarpMessage(bytes)",
+		[]byte("lux smoke test"),
+	)
+	if err != nil {
+		return "", err
+	}
+	msg, err := evm.ExtractWarpMessageFromReceipt(receipt)
+	if err != nil {
+		return "", err
+	}
+	signed, err := warp.GetAggregateSignature(ctx, t.RPCURL, msg.ID())
+	if err != nil {
+		return "", err
+	}
+	if signed.ID() != msg.ID() {
+		return "", fmt.Errorf("the validators signed message %s instead of %s", signed.ID(), msg.ID())
+	}
+	return fmt.Sprintf("message %s sent and signed by the validators", msg.ID()), nil
+}
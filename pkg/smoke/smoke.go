@@ -0,0 +1,164 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package smoke runs end-to-end smoke tests against a deployed chain: a
+// short list of checks, each exercising the chain the way its users will,
+// whose pass/fail report CI can gate on.
+package smoke
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	Pass Status = "pass"
+	Fail Status = "fail"
+	// Skip is a check that does not apply to the chain, or whose
+	// prerequisites failed
+	Skip Status = "skip"
+)
+
+// ErrSkip is returned by a check that does not apply to the chain
+type ErrSkip string
+
+func (e ErrSkip) Error() string {
+	return string(e)
+}
+
+// Check is one smoke test
+type Check struct {
+	Name string
+	// Needs are the checks that must pass before this one runs
+	Needs []string
+	// Run exercises the chain and returns what it saw
+	Run func(ctx context.Context) (string, error)
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Name     string        `json:"name"`
+	Status   Status        `json:"status"`
+	Detail   string        `json:"detail"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Run runs checks in order, each within timeout, and calls progress with
+// each result as it is known. A check whose needs did not pass is skipped.
+func Run(ctx context.Context, checks []Check, timeout time.Duration, progress func(Result)) []Result {
+	status := map[string]Status{}
+	results := make([]Result, 0, len(checks))
+	for _, check := range checks {
+		r := run(ctx, check, status, timeout)
+		status[check.Name] = r.Status
+		results = append(results, r)
+		if progress != nil {
+			progress(r)
+		}
+	}
+	return results
+}
+
+func run(ctx context.Context, check Check, status map[string]Status, timeout time.Duration) Result {
+	r := Result{Name: check.Name}
+	for _, need := range check.Needs {
+		if status[need] != Pass {
+			r.Status = Skip
+			r.Detail = fmt.Sprintf("needs %s to pass", need)
+			return r
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	detail, err := check.Run(ctx)
+	r.Duration = time.Since(start)
+	var skip ErrSkip
+	switch {
+	case err == nil:
+		r.Status = Pass
+		r.Detail = detail
+	case errors.As(err, &skip):
+		r.Status = Skip
+		r.Detail = skip.Error()
+	default:
+		r.Status = Fail
+		r.Detail = err.Error()
+	}
+	return r
+}
+
+// Failed returns the failed results
+func Failed(results []Result) []Result {
+	var failed []Result
+	for _, r := range results {
+		if r.Status == Fail {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+type junitSuite struct {
+	XMLName  xml.Name    `xml:"testsuite"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name    string        `xml:"name,attr"`
+	Class   string        `xml:"classname,attr"`
+	Time    string        `xml:"time,attr"`
+	Failure *junitMessage `xml:"failure,omitempty"`
+	Skipped *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+}
+
+// WriteJUnit writes results as the JUnit XML test suite name, the report
+// format CI systems display
+func WriteJUnit(w io.Writer, name string, results []Result) error {
+	suite := junitSuite{Name: name, Tests: len(results)}
+	var total time.Duration
+	for _, r := range results {
+		c := junitCase{Name: r.Name, Class: name, Time: seconds(r.Duration)}
+		switch r.Status {
+		case Fail:
+			suite.Failures++
+			c.Failure = &junitMessage{Message: r.Detail}
+		case Skip:
+			suite.Skipped++
+			c.Skipped = &junitMessage{Message: r.Detail}
+		}
+		total += r.Duration
+		suite.Cases = append(suite.Cases, c)
+	}
+	suite.Time = seconds(total)
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suite); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,66 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package smoke
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	require := require.New(t)
+	var ran []string
+	check := func(name string, err error, needs ...string) Check {
+		return Check{Name: name, Needs: needs, Run: func(ctx context.Context) (string, error) {
+			ran = append(ran, name)
+			if name == "slow" {
+				<-ctx.Done()
+				return "", ctx.Err()
+			}
+			return name + " ok", err
+		}}
+	}
+	var progress []Status
+	results := Run(context.Background(), []Check{
+		check("rpc", nil),
+		check("txs", errors.New("tx rejected"), "rpc"),
+		check("token", nil, "txs"),
+		check("warp", ErrSkip("warp is not active"), "rpc"),
+		check("slow", nil),
+	}, 20*time.Millisecond, func(r Result) { progress = append(progress, r.Status) })
+
+	// a check whose needs failed doesn't run
+	require.Equal([]string{"rpc", "txs", "warp", "slow"}, ran)
+	require.Equal([]Status{Pass, Fail, Skip, Skip, Fail}, progress)
+	require.Equal("rpc ok", results[0].Detail)
+	require.Equal("tx rejected", results[1].Detail)
+	require.Equal("needs txs to pass", results[2].Detail)
+	require.Equal("warp is not active", results[3].Detail)
+	require.Contains(results[4].Detail, "deadline exceeded")
+
+	failed := Failed(results)
+	require.Len(failed, 2)
+	require.Equal("txs", failed[0].Name)
+	require.Equal("slow", failed[1].Name)
+}
+
+func TestWriteJUnit(t *testing.T) {
+	require := require.New(t)
+	var buf bytes.Buffer
+	require.NoError(WriteJUnit(&buf, "mychain", []Result{
+		{Name: "rpc", Status: Pass, Detail: "chain ID 200200", Duration: 1500 * time.Millisecond},
+		{Name: "send txs", Status: Fail, Detail: `nonce "too low"`, Duration: time.Second},
+		{Name: "warp round-trip", Status: Skip, Detail: "warp is not active"},
+	}))
+	out := buf.String()
+	require.Contains(out, `<testsuite name="mychain" tests="3" failures="1" skipped="1" time="2.500">`)
+	require.Contains(out, `<testcase name="rpc" classname="mychain" time="1.500"></testcase>`)
+	require.Contains(out, `<failure message="nonce &#34;too low&#34;"></failure>`)
+	require.Contains(out, `<skipped message="warp is not active"></skipped>`)
+}
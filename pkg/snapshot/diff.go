@@ -0,0 +1,351 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/luxfi/database"
+)
+
+// Key namespaces reported by Diff. Keys are classified by the prefixes of
+// the EVM database schema; keys of other databases, such as the node main
+// DB, fall in NamespaceOther.
+const (
+	NamespaceBlocks   = "blocks"
+	NamespaceReceipts = "receipts"
+	NamespaceState    = "state"
+	NamespaceOther    = "other"
+)
+
+// Namespaces are the key namespaces of a diff, in report order
+var Namespaces = []string{NamespaceBlocks, NamespaceReceipts, NamespaceState, NamespaceOther}
+
+// NamespaceDiff counts the keys of a namespace that changed between two
+// snapshots
+type NamespaceDiff struct {
+	Namespace string `json:"namespace"`
+	Added     uint64 `json:"added"`
+	Modified  uint64 `json:"modified"`
+	Deleted   uint64 `json:"deleted"`
+}
+
+// EntryDiff is the diff of a database found in either snapshot: a node main
+// DB (chain_<node>) or a chain DB (chaindata_<node>_<chain>)
+type EntryDiff struct {
+	Network string `json:"network"`
+	Entry   string `json:"entry"`
+	// OnlyIn is the snapshot the database is found in, when not in both
+	OnlyIn     string          `json:"onlyIn,omitempty"`
+	Namespaces []NamespaceDiff `json:"namespaces"`
+}
+
+// Changed tells if any key changed
+func (d EntryDiff) Changed() bool {
+	for _, ns := range d.Namespaces {
+		if ns.Added+ns.Modified+ns.Deleted > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// keyIterator iterates the key/value pairs of a snapshot entry in key order
+type keyIterator interface {
+	// Next returns the next key/value pair, or io.EOF after the last
+	Next() ([]byte, []byte, error)
+	Close() error
+}
+
+// Diff streams the databases of the snapshots a and b and counts, per key
+// namespace, the keys added, modified and deleted from a to b. BadgerDB
+// entries are restored to temporary databases to be iterated in key order.
+func (sm *SnapshotManager) Diff(a, b string) ([]EntryDiff, error) {
+	rootA, err := sm.snapshotRoot(a)
+	if err != nil {
+		return nil, err
+	}
+	rootB, err := sm.snapshotRoot(b)
+	if err != nil {
+		return nil, err
+	}
+	entriesA, err := snapshotEntries(rootA)
+	if err != nil {
+		return nil, err
+	}
+	entriesB, err := snapshotEntries(rootB)
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]bool{}
+	for name := range entriesA {
+		names[name] = true
+	}
+	for name := range entriesB {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	diffs := make([]EntryDiff, 0, len(sorted))
+	for _, name := range sorted {
+		manifestA, inA := entriesA[name]
+		manifestB, inB := entriesB[name]
+		diff := EntryDiff{Network: filepath.Dir(name), Entry: filepath.Base(name)}
+		switch {
+		case !inA:
+			diff.OnlyIn = b
+		case !inB:
+			diff.OnlyIn = a
+		}
+		itA, err := openEntry(filepath.Join(rootA, name), manifestA, inA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s of %s: %w", name, a, err)
+		}
+		itB, err := openEntry(filepath.Join(rootB, name), manifestB, inB)
+		if err != nil {
+			_ = itA.Close()
+			return nil, fmt.Errorf("failed to read %s of %s: %w", name, b, err)
+		}
+		diff.Namespaces, err = diffKeys(itA, itB)
+		_ = itA.Close()
+		_ = itB.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to diff %s: %w", name, err)
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// snapshotRoot returns the directory of the snapshot name, which may be
+// given without its lux-snapshot- prefix
+func (sm *SnapshotManager) snapshotRoot(name string) (string, error) {
+	for _, dir := range []string{name, "lux-snapshot-" + name} {
+		root := filepath.Join(sm.baseDir, "snapshots", dir)
+		if _, err := os.Stat(root); err == nil {
+			return root, nil
+		}
+	}
+	return "", fmt.Errorf("snapshot not found: %s", name)
+}
+
+// snapshotEntries returns the manifests of the databases of the snapshot at
+// root, by <network>/<entry>
+func snapshotEntries(root string) (map[string]*SnapshotManifest, error) {
+	paths, err := filepath.Glob(filepath.Join(root, "*", "*", "manifest.json"))
+	if err != nil {
+		return nil, err
+	}
+	entries := map[string]*SnapshotManifest{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path) //nolint:gosec // G304: Reading from app's snapshot directory
+		if err != nil {
+			return nil, err
+		}
+		var manifest SnapshotManifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+		}
+		dir := filepath.Dir(path)
+		entries[filepath.Join(filepath.Base(filepath.Dir(dir)), filepath.Base(dir))] = &manifest
+	}
+	return entries, nil
+}
+
+// openEntry returns an iterator of the database of manifest, in dir, or of
+// no keys when the snapshot has no such database
+func openEntry(dir string, manifest *SnapshotManifest, exists bool) (keyIterator, error) {
+	if !exists {
+		return emptyIterator{}, nil
+	}
+	dbType, err := ParseDBType(manifest.DBType)
+	if err != nil {
+		return nil, err
+	}
+	chunksDir := filepath.Join(dir, "chunks")
+	if dbType != BadgerDB {
+		// key/value streams are full snapshots already in key order
+		r, err := openParts(chunksDir, manifest.Base.Parts)
+		if err != nil {
+			return nil, err
+		}
+		stream, err := newKVStreamReader(r)
+		if err != nil {
+			_ = r.Close()
+			return nil, err
+		}
+		return &streamIterator{kvStreamReader: stream, r: r}, nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "lux-snapshot-diff-")
+	if err != nil {
+		return nil, err
+	}
+	db, err := OpenDB(BadgerDB, tmpDir)
+	if err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return nil, err
+	}
+	it := &dbIterator{db: db, dir: tmpDir}
+	for _, entry := range append([]SnapshotEntry{manifest.Base}, manifest.Incrementals...) {
+		if len(entry.Parts) == 0 {
+			continue
+		}
+		r, err := openParts(chunksDir, entry.Parts)
+		if err != nil {
+			_ = it.Close()
+			return nil, err
+		}
+		err = db.Load(r)
+		_ = r.Close()
+		if err != nil {
+			_ = it.Close()
+			return nil, err
+		}
+	}
+	it.it = db.NewIterator()
+	return it, nil
+}
+
+// diffKeys merges the key ordered iterators a and b and counts the keys
+// added, modified and deleted from a to b per namespace
+func diffKeys(a, b keyIterator) ([]NamespaceDiff, error) {
+	counts := map[string]*NamespaceDiff{}
+	for _, ns := range Namespaces {
+		counts[ns] = &NamespaceDiff{Namespace: ns}
+	}
+	keyA, valueA, errA := a.Next()
+	keyB, valueB, errB := b.Next()
+	for {
+		if errA != nil && !errors.Is(errA, io.EOF) {
+			return nil, errA
+		}
+		if errB != nil && !errors.Is(errB, io.EOF) {
+			return nil, errB
+		}
+		endA, endB := errA != nil, errB != nil
+		switch {
+		case endA && endB:
+			diffs := make([]NamespaceDiff, 0, len(Namespaces))
+			for _, ns := range Namespaces {
+				diffs = append(diffs, *counts[ns])
+			}
+			return diffs, nil
+		case endB || (!endA && bytes.Compare(keyA, keyB) < 0):
+			counts[keyNamespace(keyA)].Deleted++
+			keyA, valueA, errA = a.Next()
+		case endA || bytes.Compare(keyA, keyB) > 0:
+			counts[keyNamespace(keyB)].Added++
+			keyB, valueB, errB = b.Next()
+		default:
+			if !bytes.Equal(valueA, valueB) {
+				counts[keyNamespace(keyA)].Modified++
+			}
+			keyA, valueA, errA = a.Next()
+			keyB, valueB, errB = b.Next()
+		}
+	}
+}
+
+// keyNamespace classifies key by the EVM database schema: headers, bodies,
+// canonical hashes and tx lookups are blocks, trie nodes, snapshot entries
+// and code are state
+func keyNamespace(key []byte) string {
+	if len(key) == 32 {
+		// hash scheme trie node
+		return NamespaceState
+	}
+	if len(key) == 0 {
+		return NamespaceOther
+	}
+	switch n := len(key); key[0] {
+	case 'h':
+		if n >= 9 {
+			return NamespaceBlocks
+		}
+	case 'H', 'l':
+		if n == 33 {
+			return NamespaceBlocks
+		}
+	case 'b':
+		if n == 41 {
+			return NamespaceBlocks
+		}
+	case 'r':
+		if n == 41 {
+			return NamespaceReceipts
+		}
+	case 'a', 'c':
+		if n == 33 {
+			return NamespaceState
+		}
+	case 'o':
+		if n == 65 {
+			return NamespaceState
+		}
+	case 'A':
+		return NamespaceState
+	case 'O':
+		if n >= 33 {
+			return NamespaceState
+		}
+	}
+	return NamespaceOther
+}
+
+type emptyIterator struct{}
+
+func (emptyIterator) Next() ([]byte, []byte, error) {
+	return nil, nil, io.EOF
+}
+
+func (emptyIterator) Close() error {
+	return nil
+}
+
+// streamIterator iterates a key/value stream
+type streamIterator struct {
+	*kvStreamReader
+	r io.Closer
+}
+
+func (it *streamIterator) Close() error {
+	return it.r.Close()
+}
+
+// dbIterator iterates a temporary database, removed on Close
+type dbIterator struct {
+	db  database.Database
+	it  database.Iterator
+	dir string
+}
+
+func (it *dbIterator) Next() ([]byte, []byte, error) {
+	if it.it.Next() {
+		return bytes.Clone(it.it.Key()), bytes.Clone(it.it.Value()), nil
+	}
+	if err := it.it.Error(); err != nil {
+		return nil, nil, err
+	}
+	return nil, nil, io.EOF
+}
+
+func (it *dbIterator) Close() error {
+	if it.it != nil {
+		it.it.Release()
+	}
+	_ = it.db.Close()
+	return os.RemoveAll(it.dir)
+}
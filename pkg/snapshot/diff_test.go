@@ -0,0 +1,121 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/luxfi/database/memdb"
+)
+
+// writeKVSnapshot writes the entry network/entry of the snapshot name as a
+// key/value stream of kvs
+func writeKVSnapshot(t *testing.T, sm *SnapshotManager, name, entry string, kvs map[string]string) {
+	t.Helper()
+	db := &kvStreamDB{Database: memdb.New(), dbType: PebbleDB}
+	for k, v := range kvs {
+		if err := db.Put([]byte(k), []byte(v)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dir := filepath.Join(sm.baseDir, "snapshots", name, "local", entry)
+	chunksDir := filepath.Join(dir, "chunks")
+	if err := os.MkdirAll(chunksDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cw, err := newChunkWriter(chunksDir, "base_0", 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw, err := zstd.NewWriter(cw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Backup(zw, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	parts, err := cw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := &SnapshotManifest{Network: "local", Base: SnapshotEntry{Parts: parts}, DBType: string(PebbleDB)}
+	if err := sm.writeManifest(dir, manifest); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	sm := NewSnapshotManager(t.TempDir())
+	hash := string(bytes.Repeat([]byte{1}, 32))
+	number := string(bytes.Repeat([]byte{0}, 8))
+	writeKVSnapshot(t, sm, "a", "chaindata_1_abc", map[string]string{
+		"h" + number + hash: "header",
+		"r" + number + hash: "receipts",
+		hash:                "trie node",
+		"LastBlock":         "1",
+	})
+	writeKVSnapshot(t, sm, "b", "chaindata_1_abc", map[string]string{
+		"h" + number + hash: "header",
+		"b" + number + hash: "body",
+		hash:                "new trie node",
+		"LastBlock":         "2",
+	})
+	writeKVSnapshot(t, sm, "b", "chain_1", map[string]string{"k1": "v", "k2": "v"})
+
+	diffs, err := sm.Diff("a", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(diffs))
+	}
+	if diffs[0].Entry != "chain_1" || diffs[0].OnlyIn != "b" || diffs[0].Network != "local" {
+		t.Errorf("unexpected entry %+v", diffs[0])
+	}
+	if other := diffs[0].Namespaces[3]; other.Added != 2 {
+		t.Errorf("expected 2 keys added to other, got %+v", other)
+	}
+	expected := []NamespaceDiff{
+		{Namespace: NamespaceBlocks, Added: 1},
+		{Namespace: NamespaceReceipts, Deleted: 1},
+		{Namespace: NamespaceState, Modified: 1},
+		{Namespace: NamespaceOther, Modified: 1},
+	}
+	for i, ns := range diffs[1].Namespaces {
+		if ns != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], ns)
+		}
+	}
+
+	if _, err := sm.Diff("a", "missing"); err == nil {
+		t.Fatal("expected error for a missing snapshot")
+	}
+}
+
+func TestKeyNamespace(t *testing.T) {
+	hash := string(bytes.Repeat([]byte{2}, 32))
+	for key, expected := range map[string]string{
+		"h" + hash[:8] + "n":  NamespaceBlocks,
+		"H" + hash:            NamespaceBlocks,
+		"l" + hash:            NamespaceBlocks,
+		"r" + hash[:8] + hash: NamespaceReceipts,
+		"c" + hash:            NamespaceState,
+		"o" + hash + hash:     NamespaceState,
+		"A" + "\x01\x02":      NamespaceState,
+		hash:                  NamespaceState,
+		"LastHeader":          NamespaceOther,
+		"r":                   NamespaceOther,
+	} {
+		if got := keyNamespace([]byte(key)); got != expected {
+			t.Errorf("keyNamespace(%q) = %s, expected %s", key, got, expected)
+		}
+	}
+}
@@ -11,6 +11,16 @@
 //   - Automatic chunking into 99MB pieces for GitHub upload
 //   - Checksum verification and metadata management
 //   - Parallel snapshot creation for minimal downtime
+//   - Hot snapshots of running nodes through their admin API, started once
+//     all nodes agree on the P-Chain height
+//   - Publishing to IPFS, pinned and addressed by CID, and restoring from any
+//     gateway with per-file checksum verification
+//   - Restoring into another network or node layout, remapping the nodes of
+//     the snapshot onto those of the target run
+//
+// The database engine is detected from the files on disk. BadgerDB is always
+// available and supports incremental snapshots; PebbleDB and LevelDB take full
+// key/value snapshots and require building with the pebbledb and leveldb tags.
 //
 // Usage:
 //
@@ -0,0 +1,257 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/luxfi/database"
+	"github.com/luxfi/database/badgerdb"
+)
+
+// DBType identifies the storage engine backing a node database. The values
+// match the directory names the node uses under chainData/<chain>/db.
+type DBType string
+
+const (
+	BadgerDB DBType = "badgerdb"
+	PebbleDB DBType = "pebbledb"
+	LevelDB  DBType = "leveldb"
+)
+
+// kvStreamMagic prefixes every key/value stream so a restore into the wrong
+// engine fails fast instead of loading garbage.
+const kvStreamMagic = "LUXKV1\n"
+
+// kvStreamBatchSize is the batch size at which Load flushes writes.
+const kvStreamBatchSize = 4 * 1024 * 1024
+
+var (
+	errUnknownDBType          = errors.New("unable to detect database type")
+	errIncrementalUnsupported = errors.New("incremental backups are only supported for badgerdb")
+	errInvalidKVStream        = errors.New("not a key/value snapshot stream")
+)
+
+// dbOpeners holds the engines compiled into this binary. PebbleDB and LevelDB
+// register themselves when built with the pebbledb and leveldb tags, matching
+// the tags github.com/luxfi/database uses for those engines.
+var dbOpeners = map[DBType]func(path string) (database.Database, error){
+	BadgerDB: func(path string) (database.Database, error) {
+		return badgerdb.New(path, nil, "", nil)
+	},
+}
+
+// ParseDBType returns the DBType for name, accepting the short "pebble" and
+// "badger" aliases. An empty name is treated as BadgerDB, which is what
+// manifests written before the db_type field was added contain.
+func ParseDBType(name string) (DBType, error) {
+	switch strings.ToLower(name) {
+	case "", "badger", string(BadgerDB):
+		return BadgerDB, nil
+	case "pebble", string(PebbleDB):
+		return PebbleDB, nil
+	case string(LevelDB):
+		return LevelDB, nil
+	default:
+		return "", fmt.Errorf("unknown database type %q", name)
+	}
+}
+
+// DetectDBType inspects the files in dir to determine which engine wrote it.
+//
+// BadgerDB keeps a plain MANIFEST alongside KEYREGISTRY and *.vlog files.
+// PebbleDB and LevelDB both keep a CURRENT file pointing at MANIFEST-<n>, but
+// only PebbleDB writes OPTIONS-<n> and format-version marker files. If dir is
+// empty, its name is used as a hint.
+func DetectDBType(dir string) (DBType, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	var hasCurrent, hasPlainManifest, hasBadgerFiles, hasPebbleFiles bool
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case name == "CURRENT":
+			hasCurrent = true
+		case name == "MANIFEST":
+			hasPlainManifest = true
+		case name == "KEYREGISTRY", strings.HasSuffix(name, ".vlog"):
+			hasBadgerFiles = true
+		case strings.HasPrefix(name, "OPTIONS-"), strings.HasPrefix(name, "marker.format-version"):
+			hasPebbleFiles = true
+		}
+	}
+
+	switch {
+	case hasBadgerFiles || (hasPlainManifest && !hasCurrent):
+		return BadgerDB, nil
+	case hasCurrent && hasPebbleFiles:
+		return PebbleDB, nil
+	case hasCurrent:
+		return LevelDB, nil
+	}
+
+	if len(entries) == 0 {
+		if dbType, err := ParseDBType(filepath.Base(dir)); err == nil {
+			return dbType, nil
+		}
+	}
+	return "", fmt.Errorf("%w in %s", errUnknownDBType, dir)
+}
+
+// OpenDB opens the database at path with the given engine. Engines without
+// native streaming backup are wrapped so Backup and Load use the key/value
+// stream format.
+func OpenDB(dbType DBType, path string) (database.Database, error) {
+	open, ok := dbOpeners[dbType]
+	if !ok {
+		return nil, fmt.Errorf("%s support is not compiled in, rebuild with -tags %s", dbType, dbType)
+	}
+	db, err := open(path)
+	if err != nil {
+		return nil, err
+	}
+	if dbType == BadgerDB {
+		return db, nil
+	}
+	return &kvStreamDB{Database: db, dbType: dbType}, nil
+}
+
+// dbTypeOf returns the engine of a database returned by OpenDB.
+func dbTypeOf(db database.Database) DBType {
+	if kv, ok := db.(*kvStreamDB); ok {
+		return kv.dbType
+	}
+	return BadgerDB
+}
+
+// kvStreamDB implements Backup and Load for engines that lack them by
+// streaming every key/value pair. Streams are always full backups.
+type kvStreamDB struct {
+	database.Database
+	dbType DBType
+}
+
+// Backup writes the magic header followed by length-prefixed key/value pairs
+// in key order.
+func (db *kvStreamDB) Backup(w io.Writer, since uint64) (uint64, error) {
+	if since != 0 {
+		return 0, errIncrementalUnsupported
+	}
+
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString(kvStreamMagic); err != nil {
+		return 0, err
+	}
+
+	it := db.NewIterator()
+	defer it.Release()
+
+	var lenBuf [binary.MaxVarintLen64]byte
+	writeField := func(b []byte) error {
+		n := binary.PutUvarint(lenBuf[:], uint64(len(b)))
+		if _, err := bw.Write(lenBuf[:n]); err != nil {
+			return err
+		}
+		_, err := bw.Write(b)
+		return err
+	}
+	for it.Next() {
+		if err := writeField(it.Key()); err != nil {
+			return 0, err
+		}
+		if err := writeField(it.Value()); err != nil {
+			return 0, err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return 0, err
+	}
+	return 0, bw.Flush()
+}
+
+// Load reads a stream written by Backup and writes it in batches.
+func (db *kvStreamDB) Load(r io.Reader) error {
+	stream, err := newKVStreamReader(r)
+	if err != nil {
+		return err
+	}
+
+	batch := db.NewBatch()
+	for {
+		key, value, err := stream.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(key, value); err != nil {
+			return err
+		}
+		if batch.Size() >= kvStreamBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	return batch.Write()
+}
+
+// kvStreamReader reads the key/value pairs of a stream written by Backup,
+// in key order.
+type kvStreamReader struct {
+	r *bufio.Reader
+}
+
+// newKVStreamReader checks the magic header of the stream r.
+func newKVStreamReader(r io.Reader) (*kvStreamReader, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(kvStreamMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != kvStreamMagic {
+		return nil, errInvalidKVStream
+	}
+	return &kvStreamReader{r: br}, nil
+}
+
+// Next returns the next key/value pair, or io.EOF at the end of the stream.
+func (s *kvStreamReader) Next() ([]byte, []byte, error) {
+	key, err := s.readField()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, io.EOF
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	value, err := s.readField()
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read value: %w", err)
+	}
+	return key, value, nil
+}
+
+func (s *kvStreamReader) readField() ([]byte, error) {
+	n, err := binary.ReadUvarint(s.r)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(s.r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
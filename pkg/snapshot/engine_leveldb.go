@@ -0,0 +1,18 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build leveldb
+
+package snapshot
+
+import (
+	"github.com/luxfi/database"
+	"github.com/luxfi/database/leveldb"
+)
+
+func init() {
+	dbOpeners[LevelDB] = func(path string) (database.Database, error) {
+		// Same cache sizes the database factory uses for leveldb
+		return leveldb.New(path, 12*1024*1024, 4*1024*1024, 1024)
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build pebbledb
+
+package snapshot
+
+import (
+	"github.com/luxfi/database"
+	"github.com/luxfi/database/pebbledb"
+)
+
+func init() {
+	dbOpeners[PebbleDB] = func(path string) (database.Database, error) {
+		// 16 MB block cache, 1024 open file handles
+		return pebbledb.New(path, 16, 1024, "", false)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/luxfi/database/memdb"
+)
+
+func TestDetectDBType(t *testing.T) {
+	tests := []struct {
+		name     string
+		dirName  string
+		files    []string
+		expected DBType
+		wantErr  bool
+	}{
+		{"badger", "db", []string{"MANIFEST", "KEYREGISTRY", "000001.vlog", "000002.sst"}, BadgerDB, false},
+		{"pebble", "db", []string{"CURRENT", "MANIFEST-000001", "OPTIONS-000003", "000002.sst"}, PebbleDB, false},
+		{"leveldb", "db", []string{"CURRENT", "MANIFEST-000001", "LOG", "000002.ldb"}, LevelDB, false},
+		{"empty named dir", "pebbledb", nil, PebbleDB, false},
+		{"empty unnamed dir", "db", nil, "", true},
+		{"unknown files", "db", []string{"data.bin"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), tt.dirName)
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			for _, f := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, f), nil, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := DetectDBType(dir)
+			if tt.wantErr {
+				if !errors.Is(err, errUnknownDBType) {
+					t.Fatalf("expected errUnknownDBType, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseDBType(t *testing.T) {
+	for name, expected := range map[string]DBType{
+		"":         BadgerDB,
+		"badger":   BadgerDB,
+		"pebble":   PebbleDB,
+		"PebbleDB": PebbleDB,
+		"leveldb":  LevelDB,
+	} {
+		got, err := ParseDBType(name)
+		if err != nil {
+			t.Fatalf("ParseDBType(%q): %v", name, err)
+		}
+		if got != expected {
+			t.Errorf("ParseDBType(%q) = %s, expected %s", name, got, expected)
+		}
+	}
+	if _, err := ParseDBType("rocksdb"); err == nil {
+		t.Error("expected error for unknown type")
+	}
+}
+
+func TestKVStreamRoundTrip(t *testing.T) {
+	src := &kvStreamDB{Database: memdb.New(), dbType: PebbleDB}
+	for i := 0; i < 1000; i++ {
+		key := []byte(fmt.Sprintf("key-%04d", i))
+		value := bytes.Repeat([]byte{byte(i)}, i%64)
+		if err := src.Put(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := src.Backup(&buf, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := src.Backup(&bytes.Buffer{}, 1); !errors.Is(err, errIncrementalUnsupported) {
+		t.Fatalf("expected errIncrementalUnsupported, got %v", err)
+	}
+
+	dst := &kvStreamDB{Database: memdb.New(), dbType: LevelDB}
+	if err := dst.Load(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		key := []byte(fmt.Sprintf("key-%04d", i))
+		got, err := dst.Get(key)
+		if err != nil {
+			t.Fatalf("missing %s: %v", key, err)
+		}
+		if !bytes.Equal(got, bytes.Repeat([]byte{byte(i)}, i%64)) {
+			t.Errorf("value mismatch for %s", key)
+		}
+	}
+
+	if err := dst.Load(bytes.NewReader([]byte("not a stream"))); !errors.Is(err, errInvalidKVStream) {
+		t.Fatalf("expected errInvalidKVStream, got %v", err)
+	}
+	if err := dst.Load(bytes.NewReader(buf.Bytes()[:buf.Len()-1])); err == nil {
+		t.Fatal("expected error for truncated stream")
+	}
+}
+
+func TestOpenDBNotCompiledIn(t *testing.T) {
+	if _, ok := dbOpeners[LevelDB]; ok {
+		t.Skip("leveldb support compiled in")
+	}
+	if _, err := OpenDB(LevelDB, t.TempDir()); err == nil {
+		t.Fatal("expected error when leveldb is not compiled in")
+	}
+}
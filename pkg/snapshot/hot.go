@@ -0,0 +1,220 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luxfi/sdk/admin"
+	"github.com/luxfi/sdk/platformvm"
+)
+
+const (
+	// HeightSyncTimeout bounds how long a hot snapshot waits for the nodes
+	// of a network to agree on the accepted P-Chain height.
+	HeightSyncTimeout = 30 * time.Second
+
+	// HotBackupTimeout bounds a single node streaming its backup.
+	HotBackupTimeout = 30 * time.Minute
+
+	// heightPollInterval is how often node heights are polled while waiting
+	// for them to converge.
+	heightPollInterval = 250 * time.Millisecond
+
+	// hotStreamName is the file the node writes its backup to before it is
+	// split into parts. The .zst suffix makes the node compress the stream.
+	hotStreamName = "hot_backup.stream.zst"
+)
+
+var errHeightsDiverged = errors.New("nodes did not reach a consistent height")
+
+// NodeAdmin is the API of a running node used to back up its database
+// without stopping it. The node holds the database lock, so the backup is
+// taken by the node itself through its admin API.
+type NodeAdmin interface {
+	// Height returns the accepted P-Chain height of the node.
+	Height(ctx context.Context) (uint64, error)
+	// Snapshot makes the node write a zstd compressed backup of its database
+	// to path, on the host of the node, and returns the backup version.
+	Snapshot(ctx context.Context, path string, since uint64) (uint64, error)
+}
+
+type nodeAdmin struct {
+	admin    *admin.Client
+	platform *platformvm.Client
+}
+
+// NewNodeAdmin returns the NodeAdmin of the node serving its API at uri
+func NewNodeAdmin(uri string) NodeAdmin {
+	uri = strings.TrimSuffix(uri, "/")
+	return &nodeAdmin{
+		admin:    admin.NewClient(uri),
+		platform: platformvm.NewClient(uri),
+	}
+}
+
+func (n *nodeAdmin) Height(ctx context.Context) (uint64, error) {
+	return n.platform.GetHeight(ctx)
+}
+
+func (n *nodeAdmin) Snapshot(ctx context.Context, path string, since uint64) (uint64, error) {
+	return n.admin.Snapshot(ctx, path, since)
+}
+
+// nodeURI returns the API endpoint recorded in the process.json of a running
+// node, or "" when the node has none.
+func nodeURI(nodeDir string) string {
+	data, err := os.ReadFile(filepath.Join(nodeDir, "process.json"))
+	if err != nil {
+		return ""
+	}
+	var proc struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(data, &proc); err != nil {
+		return ""
+	}
+	return proc.URI
+}
+
+// WaitForConsistentHeight polls the nodes until they all report the same
+// accepted P-Chain height and returns it. Backups started right after all
+// nodes agree capture the same chain state, so the snapshot restores as a
+// consistent network. It fails if the nodes have not converged when ctx is
+// done.
+func WaitForConsistentHeight(ctx context.Context, nodes map[string]NodeAdmin) (uint64, error) {
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ticker := time.NewTicker(heightPollInterval)
+	defer ticker.Stop()
+	var last string
+	for {
+		heights := make(map[uint64][]string)
+		var height uint64
+		var pollErr error
+		for _, name := range names {
+			h, err := nodes[name].Height(ctx)
+			if err != nil {
+				pollErr = fmt.Errorf("failed to get the height of %s: %w", name, err)
+				break
+			}
+			heights[h] = append(heights[h], name)
+			height = h
+		}
+		if pollErr == nil && len(heights) <= 1 {
+			return height, nil
+		}
+		if pollErr != nil {
+			last = pollErr.Error()
+		} else {
+			last = describeHeights(heights)
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, fmt.Errorf("%w: %s", errHeightsDiverged, last)
+		case <-ticker.C:
+		}
+	}
+}
+
+// describeHeights lists which nodes are at which height, lowest first
+func describeHeights(heights map[uint64][]string) string {
+	hs := make([]uint64, 0, len(heights))
+	for h := range heights {
+		hs = append(hs, h)
+	}
+	sort.Slice(hs, func(i, j int) bool { return hs[i] < hs[j] })
+	desc := make([]string, 0, len(hs))
+	for _, h := range hs {
+		desc = append(desc, fmt.Sprintf("%s at %d", strings.Join(heights[h], ", "), h))
+	}
+	return strings.Join(desc, "; ")
+}
+
+// CreateHotSnapshot creates a base snapshot of the main database of a running
+// node. The node streams the backup to a file next to the chunks, which is
+// then split into parts like any other snapshot. height is the P-Chain height
+// the nodes of the network agreed on before the backup started.
+func (sm *SnapshotManager) CreateHotSnapshot(
+	ctx context.Context,
+	network string,
+	nodeID uint64,
+	node NodeAdmin,
+	height uint64,
+	snapshotID string,
+) (*SnapshotManifest, error) {
+	if snapshotID == "" {
+		snapshotID = time.Now().Format("2006-01-02")
+	}
+	snapshotDir := filepath.Join(sm.baseDir, "snapshots", snapshotID, network, fmt.Sprintf("chain_%d", nodeID))
+	chunksDir := filepath.Join(snapshotDir, "chunks")
+
+	if err := os.MkdirAll(chunksDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create chunks directory: %w", err)
+	}
+
+	streamPath, err := filepath.Abs(filepath.Join(snapshotDir, hotStreamName))
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(streamPath)
+
+	lastVersion, err := node.Snapshot(ctx, streamPath, 0)
+	if err != nil {
+		return nil, fmt.Errorf("node backup failed: %w", err)
+	}
+
+	stream, err := os.Open(streamPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open node backup: %w", err)
+	}
+	defer stream.Close()
+
+	chunkWriter, err := newChunkWriter(chunksDir, fmt.Sprintf("base_%d", height), ChunkSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create chunk writer: %w", err)
+	}
+	if _, err := io.Copy(chunkWriter, stream); err != nil {
+		chunkWriter.Close()
+		return nil, fmt.Errorf("failed to split node backup: %w", err)
+	}
+	parts, err := chunkWriter.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to close chunk writer: %w", err)
+	}
+
+	manifest := &SnapshotManifest{
+		Network: network,
+		ChainID: nodeID,
+		Base: SnapshotEntry{
+			Height: height,
+			Since:  0,
+			Parts:  parts,
+		},
+		Incrementals: []SnapshotEntry{},
+		CreatedAt:    time.Now().UTC().Format(time.RFC3339),
+		LastVersion:  lastVersion,
+		DBType:       string(BadgerDB),
+	}
+
+	if err := sm.writeManifest(snapshotDir, manifest); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
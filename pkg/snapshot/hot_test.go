@@ -0,0 +1,113 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNode reports the next of heights on each poll, staying on the last one,
+// and writes backup to the requested path.
+type fakeNode struct {
+	mu      sync.Mutex
+	heights []uint64
+	backup  []byte
+	path    string
+}
+
+func (n *fakeNode) Height(context.Context) (uint64, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	h := n.heights[0]
+	if len(n.heights) > 1 {
+		n.heights = n.heights[1:]
+	}
+	return h, nil
+}
+
+func (n *fakeNode) Snapshot(_ context.Context, path string, since uint64) (uint64, error) {
+	if since != 0 {
+		return 0, errors.New("unexpected incremental backup")
+	}
+	n.path = path
+	return 42, os.WriteFile(path, n.backup, 0o644)
+}
+
+func TestWaitForConsistentHeight(t *testing.T) {
+	nodes := map[string]NodeAdmin{
+		"node1": &fakeNode{heights: []uint64{10, 11, 12}},
+		"node2": &fakeNode{heights: []uint64{12}},
+		"node3": &fakeNode{heights: []uint64{11, 12}},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	height, err := WaitForConsistentHeight(ctx, nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height != 12 {
+		t.Errorf("expected height 12, got %d", height)
+	}
+}
+
+func TestWaitForConsistentHeightDiverged(t *testing.T) {
+	nodes := map[string]NodeAdmin{
+		"node1": &fakeNode{heights: []uint64{10}},
+		"node2": &fakeNode{heights: []uint64{12}},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*heightPollInterval)
+	defer cancel()
+	_, err := WaitForConsistentHeight(ctx, nodes)
+	if !errors.Is(err, errHeightsDiverged) {
+		t.Fatalf("expected errHeightsDiverged, got %v", err)
+	}
+	if want := "node1 at 10; node2 at 12"; !bytes.Contains([]byte(err.Error()), []byte(want)) {
+		t.Errorf("expected %q in %q", want, err)
+	}
+}
+
+func TestCreateHotSnapshot(t *testing.T) {
+	baseDir := t.TempDir()
+	backup := bytes.Repeat([]byte("hot backup stream "), 1000)
+	node := &fakeNode{backup: backup}
+
+	sm := NewSnapshotManager(baseDir)
+	manifest, err := sm.CreateHotSnapshot(context.Background(), "mainnet", 2, node, 12, "hot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Base.Height != 12 || manifest.LastVersion != 42 || manifest.DBType != string(BadgerDB) {
+		t.Errorf("unexpected manifest %+v", manifest)
+	}
+
+	snapshotDir := filepath.Join(baseDir, "snapshots", "hot", "mainnet", "chain_2")
+	if filepath.Dir(node.path) != snapshotDir {
+		t.Errorf("node wrote its backup to %s, expected a file in %s", node.path, snapshotDir)
+	}
+	if _, err := os.Stat(node.path); !os.IsNotExist(err) {
+		t.Errorf("expected the node backup to be removed, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(snapshotDir, "manifest.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []byte
+	for _, part := range manifest.Base.Parts {
+		data, err := os.ReadFile(filepath.Join(snapshotDir, "chunks", part.Name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, data...)
+	}
+	if !bytes.Equal(got, backup) {
+		t.Error("parts do not match the node backup")
+	}
+}
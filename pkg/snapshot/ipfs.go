@@ -0,0 +1,184 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultIPFSAPI is the RPC API of a local IPFS (Kubo) node
+	DefaultIPFSAPI = "http://127.0.0.1:5001"
+	// DefaultIPFSGateway is the public gateway snapshots are fetched from
+	DefaultIPFSGateway = "https://ipfs.io"
+)
+
+// IPFSPublisher publishes snapshots to IPFS through the RPC API of a Kubo
+// node, which adds and pins them, and fetches them by CID from a gateway
+type IPFSPublisher struct {
+	// APIURL is the RPC API of the node publishing
+	APIURL string
+	// Gateway is the HTTP gateway fetching
+	Gateway string
+	Client  *http.Client
+}
+
+// NewIPFSPublisher returns a publisher adding to the node at apiURL and
+// fetching from gateway
+func NewIPFSPublisher(apiURL, gateway string) *IPFSPublisher {
+	return &IPFSPublisher{
+		APIURL:  strings.TrimSuffix(apiURL, "/"),
+		Gateway: strings.TrimSuffix(gateway, "/"),
+		Client:  http.DefaultClient,
+	}
+}
+
+// Publish adds and pins the files of index and the index, wrapped in a
+// directory, and returns the CID of the directory
+func (p *IPFSPublisher) Publish(ctx context.Context, dir string, index PublishIndex) (string, error) {
+	rawIndex, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	body, w := io.Pipe()
+	mw := multipart.NewWriter(w)
+	go func() {
+		w.CloseWithError(writeIPFSAddBody(mw, dir, index, rawIndex))
+	}()
+
+	query := url.Values{
+		"pin":                 {"true"},
+		"wrap-with-directory": {"true"},
+		"cid-version":         {"1"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.APIURL+"/api/v0/add?"+query.Encode(), body)
+	if err != nil {
+		_ = body.Close()
+		return "", err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		_ = body.Close()
+		return "", fmt.Errorf("failed to reach the IPFS API at %s: %w", p.APIURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("IPFS add failed: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	// the response has a line per added file and directory, the wrapping
+	// directory last, with an empty name
+	cid := ""
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var added struct {
+			Name string
+			Hash string
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &added); err != nil {
+			return "", fmt.Errorf("invalid IPFS add response: %w", err)
+		}
+		if added.Name == "" {
+			cid = added.Hash
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	if cid == "" {
+		return "", errors.New("IPFS add returned no directory CID")
+	}
+	return cid, nil
+}
+
+// writeIPFSAddBody writes the directories and files of index, then the
+// index, as the multipart body of an add
+func writeIPFSAddBody(mw *multipart.Writer, dir string, index PublishIndex, rawIndex []byte) error {
+	dirs := map[string]bool{}
+	for _, file := range index.Files {
+		for d := path.Dir(file.Path); d != "."; d = path.Dir(d) {
+			dirs[d] = true
+		}
+	}
+	sortedDirs := make([]string, 0, len(dirs))
+	for d := range dirs {
+		sortedDirs = append(sortedDirs, d)
+	}
+	// parents sort before their children
+	sort.Strings(sortedDirs)
+	for _, d := range sortedDirs {
+		if _, err := mw.CreatePart(ipfsPartHeader(d, "application/x-directory")); err != nil {
+			return err
+		}
+	}
+	for _, file := range index.Files {
+		part, err := mw.CreatePart(ipfsPartHeader(file.Path, "application/octet-stream"))
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(filepath.Join(dir, filepath.FromSlash(file.Path))) //nolint:gosec // G304: Reading from app's snapshot directory
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(part, f)
+		_ = f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	part, err := mw.CreatePart(ipfsPartHeader(PublishIndexFile, "application/octet-stream"))
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(rawIndex); err != nil {
+		return err
+	}
+	return mw.Close()
+}
+
+func ipfsPartHeader(name, contentType string) textproto.MIMEHeader {
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, url.QueryEscape(name)))
+	h.Set("Content-Type", contentType)
+	return h
+}
+
+// Fetch writes the file at p of the directory cid, from the gateway, to w
+func (p *IPFSPublisher) Fetch(ctx context.Context, cid, filePath string, w io.Writer) error {
+	segments := strings.Split(filePath, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	u := p.Gateway + "/ipfs/" + url.PathEscape(cid) + "/" + strings.Join(segments, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the IPFS gateway %s: %w", p.Gateway, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("IPFS gateway returned %s for %s", resp.Status, u)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
@@ -0,0 +1,147 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// PublishIndexFile is the file listing the files of a published snapshot,
+// published at its root
+const PublishIndexFile = "index.json"
+
+var errChecksumMismatch = errors.New("checksum mismatch")
+
+// PublishedFile is a file of a published snapshot
+type PublishedFile struct {
+	// Path is relative to the snapshot root, slash separated
+	Path   string `json:"path"`
+	Bytes  int64  `json:"bytes"`
+	SHA256 string `json:"sha256"`
+}
+
+// PublishIndex lists the files of a published snapshot
+type PublishIndex struct {
+	Snapshot string          `json:"snapshot"`
+	Files    []PublishedFile `json:"files"`
+}
+
+// Publisher is a backend snapshots are published to, for nodes to bootstrap
+// from
+type Publisher interface {
+	// Publish publishes the files of index, read from dir, and the index
+	// itself as PublishIndexFile, and returns the reference to fetch them by
+	Publish(ctx context.Context, dir string, index PublishIndex) (string, error)
+	// Fetch writes the file at path of the snapshot published as ref to w
+	Fetch(ctx context.Context, ref, path string, w io.Writer) error
+}
+
+// Publish publishes the snapshot snapshotName with p and returns its
+// reference
+func (sm *SnapshotManager) Publish(ctx context.Context, p Publisher, snapshotName string) (string, error) {
+	root, err := sm.snapshotRoot(snapshotName)
+	if err != nil {
+		return "", err
+	}
+	index := PublishIndex{Snapshot: snapshotName}
+	err = filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(p) //nolint:gosec // G304: Reading from app's snapshot directory
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		h := sha256.New()
+		n, err := io.Copy(h, f)
+		if err != nil {
+			return err
+		}
+		index.Files = append(index.Files, PublishedFile{
+			Path:   filepath.ToSlash(rel),
+			Bytes:  n,
+			SHA256: hex.EncodeToString(h.Sum(nil)),
+		})
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(index.Files) == 0 {
+		return "", fmt.Errorf("snapshot %s is empty", snapshotName)
+	}
+	return p.Publish(ctx, root, index)
+}
+
+// FetchSnapshot fetches the snapshot published as ref with p to the local
+// snapshot snapshotName, checking the checksum of each file, so it can be
+// restored
+func (sm *SnapshotManager) FetchSnapshot(ctx context.Context, p Publisher, ref, snapshotName string) (*PublishIndex, error) {
+	root := filepath.Join(sm.baseDir, "snapshots", snapshotName)
+	if _, err := os.Stat(root); err == nil {
+		return nil, fmt.Errorf("snapshot %s already exists", snapshotName)
+	}
+	var raw strings.Builder
+	if err := p.Fetch(ctx, ref, PublishIndexFile, &raw); err != nil {
+		return nil, fmt.Errorf("failed to fetch the snapshot index: %w", err)
+	}
+	var index PublishIndex
+	if err := json.Unmarshal([]byte(raw.String()), &index); err != nil {
+		return nil, fmt.Errorf("invalid snapshot index: %w", err)
+	}
+
+	tmpRoot := root + ".fetching"
+	if err := os.RemoveAll(tmpRoot); err != nil {
+		return nil, err
+	}
+	for _, file := range index.Files {
+		if err := fetchFile(ctx, p, ref, tmpRoot, file); err != nil {
+			_ = os.RemoveAll(tmpRoot)
+			return nil, fmt.Errorf("failed to fetch %s: %w", file.Path, err)
+		}
+	}
+	if err := os.Rename(tmpRoot, root); err != nil {
+		return nil, err
+	}
+	return &index, nil
+}
+
+func fetchFile(ctx context.Context, p Publisher, ref, root string, file PublishedFile) error {
+	clean := path.Clean(file.Path)
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("invalid path %q", file.Path)
+	}
+	dst := filepath.Join(root, filepath.FromSlash(clean))
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(dst) //nolint:gosec // G304: Writing to app's snapshot directory
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if err := p.Fetch(ctx, ref, clean, io.MultiWriter(f, h)); err != nil {
+		return err
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != file.SHA256 {
+		return fmt.Errorf("%w: got %s, expected %s", errChecksumMismatch, sum, file.SHA256)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const testCID = "bafytestcid"
+
+// fakeIPFS emulates the add RPC of a Kubo node and a gateway serving what
+// was added
+type fakeIPFS struct {
+	mu    sync.Mutex
+	files map[string][]byte
+	dirs  []string
+}
+
+func (f *fakeIPFS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v0/add":
+		if r.URL.Query().Get("wrap-with-directory") != "true" || r.URL.Query().Get("pin") != "true" {
+			http.Error(w, "expected a pinned directory", http.StatusBadRequest)
+			return
+		}
+		mr, err := r.MultipartReader()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		enc := json.NewEncoder(w)
+		for {
+			part, err := mr.NextPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			name, err := url.QueryUnescape(part.FileName())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if part.Header.Get("Content-Type") == "application/x-directory" {
+				f.dirs = append(f.dirs, name)
+			} else {
+				data, err := io.ReadAll(part)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				f.files[name] = data
+			}
+			_ = enc.Encode(map[string]string{"Name": name, "Hash": "bafy" + name})
+		}
+		_ = enc.Encode(map[string]string{"Name": "", "Hash": testCID})
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/ipfs/"+testCID+"/"):
+		data, ok := f.files[strings.TrimPrefix(r.URL.Path, "/ipfs/"+testCID+"/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestPublishIPFS(t *testing.T) {
+	ipfs := &fakeIPFS{files: map[string][]byte{}}
+	server := httptest.NewServer(ipfs)
+	defer server.Close()
+	p := NewIPFSPublisher(server.URL, server.URL+"/")
+
+	sm := NewSnapshotManager(t.TempDir())
+	writeKVSnapshot(t, sm, "a", "chain_1", map[string]string{"k1": "v1", "k2": "v2"})
+	ctx := context.Background()
+	cid, err := sm.Publish(ctx, p, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cid != testCID {
+		t.Fatalf("expected CID %s, got %s", testCID, cid)
+	}
+	if _, ok := ipfs.files[PublishIndexFile]; !ok {
+		t.Fatal("index was not published")
+	}
+	if len(ipfs.dirs) == 0 || ipfs.dirs[0] != "local" {
+		t.Fatalf("expected directories to be added parents first, got %v", ipfs.dirs)
+	}
+
+	fetched := NewSnapshotManager(t.TempDir())
+	index, err := fetched.FetchSnapshot(ctx, p, cid, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index.Snapshot != "a" {
+		t.Errorf("expected snapshot a, got %s", index.Snapshot)
+	}
+	for _, file := range index.Files {
+		want, err := os.ReadFile(filepath.Join(sm.baseDir, "snapshots", "a", filepath.FromSlash(file.Path)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(filepath.Join(fetched.baseDir, "snapshots", "b", filepath.FromSlash(file.Path)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s differs after fetch", file.Path)
+		}
+	}
+	diffs, err := fetched.Diff("b", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 1 || diffs[0].Changed() {
+		t.Errorf("unexpected diff of the fetched snapshot %+v", diffs)
+	}
+
+	if _, err := fetched.FetchSnapshot(ctx, p, cid, "b"); err == nil {
+		t.Fatal("expected error fetching over an existing snapshot")
+	}
+
+	// a tampered file fails its checksum and leaves nothing behind
+	ipfs.files["local/chain_1/manifest.json"] = []byte("{}")
+	if _, err := fetched.FetchSnapshot(ctx, p, cid, "c"); !errors.Is(err, errChecksumMismatch) {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+	for _, name := range []string{"c", "c.fetching"} {
+		if _, err := os.Stat(filepath.Join(fetched.baseDir, "snapshots", name)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed", name)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RestoreOptions remap a snapshot onto a network or node layout other than
+// the one it was taken from, e.g. the node of a 5 node mainnet snapshot onto
+// a 1 node devnet
+type RestoreOptions struct {
+	// SourceNetwork restores only that network of the snapshot
+	SourceNetwork string
+	// Network is the network the snapshot restores into, rather than the one
+	// it was taken from
+	Network string
+	// NodeMap maps the nodes of the snapshot to the nodes they restore into,
+	// by node number. When set, nodes not mapped are not restored.
+	NodeMap map[uint64]uint64
+}
+
+// ParseNodeMap parses node mappings of the form node3=node1, or 3=1
+func ParseNodeMap(specs []string) (map[uint64]uint64, error) {
+	nodeMap := make(map[uint64]uint64, len(specs))
+	targets := make(map[uint64]uint64, len(specs))
+	for _, spec := range specs {
+		from, to, ok := strings.Cut(spec, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid node mapping %q, expected <snapshot node>=<target node>, e.g. node3=node1", spec)
+		}
+		source, err := parseNodeName(from)
+		if err != nil {
+			return nil, err
+		}
+		target, err := parseNodeName(to)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := nodeMap[source]; ok {
+			return nil, fmt.Errorf("node%d is mapped twice", source)
+		}
+		if other, ok := targets[target]; ok {
+			return nil, fmt.Errorf("node%d and node%d both map to node%d", other, source, target)
+		}
+		nodeMap[source] = target
+		targets[target] = source
+	}
+	return nodeMap, nil
+}
+
+// parseNodeName returns the number of the node named nodeN, or N
+func parseNodeName(name string) (uint64, error) {
+	n, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimSpace(name), "node"), 10, 64)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("invalid node %q, expected nodeN or N", name)
+	}
+	return n, nil
+}
+
+// targetNetworks returns the network each network of the snapshot restores
+// into, for the networks restored
+func (o RestoreOptions) targetNetworks(networks []string) (map[string]string, error) {
+	selected := networks
+	if o.SourceNetwork != "" {
+		if !slices.Contains(networks, o.SourceNetwork) {
+			return nil, fmt.Errorf("snapshot has no network %s, only %s", o.SourceNetwork, strings.Join(networks, ", "))
+		}
+		selected = []string{o.SourceNetwork}
+	}
+	if o.Network != "" && len(selected) > 1 {
+		return nil, fmt.Errorf("snapshot holds networks %s, pick the one to restore into %s", strings.Join(selected, ", "), o.Network)
+	}
+	targets := make(map[string]string, len(selected))
+	for _, network := range selected {
+		targets[network] = network
+		if o.Network != "" {
+			targets[network] = o.Network
+		}
+	}
+	return targets, nil
+}
+
+// targetNode returns the node the node nodeID of the snapshot restores into,
+// and whether it is restored
+func (o RestoreOptions) targetNode(nodeID uint64) (uint64, bool) {
+	if len(o.NodeMap) == 0 {
+		return nodeID, true
+	}
+	target, ok := o.NodeMap[nodeID]
+	return target, ok
+}
+
+// unmappedNodes returns the nodes of NodeMap the snapshot doesn't hold
+func (o RestoreOptions) unmappedNodes(nodes map[uint64]bool) []string {
+	var missing []string
+	for source := range o.NodeMap {
+		if !nodes[source] {
+			missing = append(missing, fmt.Sprintf("node%d", source))
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,100 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNodeMap(t *testing.T) {
+	require := require.New(t)
+	nodeMap, err := ParseNodeMap([]string{"node3=node1", "5=2"})
+	require.NoError(err)
+	require.Equal(map[uint64]uint64{3: 1, 5: 2}, nodeMap)
+
+	nodeMap, err = ParseNodeMap(nil)
+	require.NoError(err)
+	require.Empty(nodeMap)
+
+	for spec, message := range map[string]string{
+		"node3":       "expected <snapshot node>=<target node>",
+		"nodeX=node1": `invalid node "nodeX"`,
+		"node3=node0": `invalid node "node0"`,
+	} {
+		_, err := ParseNodeMap([]string{spec})
+		require.ErrorContains(err, message, spec)
+	}
+	_, err = ParseNodeMap([]string{"node3=node1", "node3=node2"})
+	require.ErrorContains(err, "node3 is mapped twice")
+	_, err = ParseNodeMap([]string{"node3=node1", "node4=node1"})
+	require.ErrorContains(err, "node3 and node4 both map to node1")
+}
+
+func TestRestoreOptionsNetworks(t *testing.T) {
+	require := require.New(t)
+	networks := []string{"devnet", "mainnet"}
+
+	targets, err := RestoreOptions{}.targetNetworks(networks)
+	require.NoError(err)
+	require.Equal(map[string]string{"devnet": "devnet", "mainnet": "mainnet"}, targets)
+
+	targets, err = RestoreOptions{SourceNetwork: "mainnet", Network: "forensics"}.targetNetworks(networks)
+	require.NoError(err)
+	require.Equal(map[string]string{"mainnet": "forensics"}, targets)
+
+	targets, err = RestoreOptions{Network: "devnet"}.targetNetworks([]string{"mainnet"})
+	require.NoError(err)
+	require.Equal(map[string]string{"mainnet": "devnet"}, targets)
+
+	_, err = RestoreOptions{Network: "forensics"}.targetNetworks(networks)
+	require.ErrorContains(err, "snapshot holds networks devnet, mainnet")
+	_, err = RestoreOptions{SourceNetwork: "testnet"}.targetNetworks(networks)
+	require.ErrorContains(err, "snapshot has no network testnet")
+}
+
+func TestRestoreOptionsNodes(t *testing.T) {
+	require := require.New(t)
+
+	nodeID, ok := RestoreOptions{}.targetNode(4)
+	require.True(ok)
+	require.Equal(uint64(4), nodeID)
+
+	opts := RestoreOptions{NodeMap: map[uint64]uint64{3: 1, 7: 2}}
+	nodeID, ok = opts.targetNode(3)
+	require.True(ok)
+	require.Equal(uint64(1), nodeID)
+	_, ok = opts.targetNode(1)
+	require.False(ok)
+
+	require.Equal([]string{"node7"}, opts.unmappedNodes(map[uint64]bool{1: true, 2: true, 3: true, 4: true, 5: true}))
+}
+
+func TestSnapshotRestoreEntries(t *testing.T) {
+	require := require.New(t)
+	sm := NewSnapshotManager(t.TempDir())
+	root := filepath.Join(sm.baseDir, "snapshots", "mainnet-2026-01-19")
+	for dir, manifest := range map[string]*SnapshotManifest{
+		"mainnet/chain_3":                {Network: "mainnet"},
+		"mainnet/chaindata_3_abcdefghij": {Network: "mainnet", NodeID: 3, ChainDataID: "abcdefghij"},
+		"mainnet/unknown":                {Network: "mainnet"},
+		"devnet/chain_1":                 {Network: "devnet"},
+	} {
+		require.NoError(os.MkdirAll(filepath.Join(root, dir), 0o755))
+		require.NoError(sm.writeManifest(filepath.Join(root, dir), manifest))
+	}
+
+	networks, entries, err := snapshotRestoreEntries(root)
+	require.NoError(err)
+	require.Equal([]string{"devnet", "mainnet"}, networks)
+	require.Len(entries, 3)
+	require.Equal("devnet/node1 main DB", entries[0].label(entries[0].network, entries[0].nodeID))
+	require.Equal("devnet/node1 main DB", entries[1].label("devnet", 1))
+	require.Equal(uint64(3), entries[1].nodeID)
+	require.Equal("chaindata_3_abcdefghij", entries[2].name)
+	require.Equal("devnet/node1 chain abcdefgh", entries[2].label("devnet", 1))
+}
@@ -0,0 +1,341 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SchedulesFile lists the snapshot schedules, in the snapshots directory
+const SchedulesFile = "schedules.json"
+
+// ErrScheduleNotFound is returned for a schedule ID that is not scheduled
+var ErrScheduleNotFound = errors.New("snapshot schedule not found")
+
+// cronDescriptors are the @ shorthands of cron expressions
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronSearchLimit bounds how far ahead Next looks for a matching minute, as
+// expressions like "0 0 30 2 *" never match
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// CronSchedule is a parsed cron expression: minute, hour, day of month,
+// month and day of week
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// as in cron, a day matches either day field when both are restricted,
+	// and the restricted one when only one is
+	domAny, dowAny bool
+}
+
+// ParseCron parses a standard five field cron expression, with lists,
+// ranges and steps, or one of the @hourly, @daily, @weekly, @monthly and
+// @yearly shorthands. Day of week 0 and 7 are Sunday.
+func ParseCron(expr string) (*CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if d, ok := cronDescriptors[expr]; ok {
+		expr = d
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields (minute hour day-of-month month day-of-week), got %d", expr, len(fields))
+	}
+	c := &CronSchedule{}
+	var err error
+	if c.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("invalid minute in %q: %w", expr, err)
+	}
+	if c.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("invalid hour in %q: %w", expr, err)
+	}
+	if c.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("invalid day of month in %q: %w", expr, err)
+	}
+	if c.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("invalid month in %q: %w", expr, err)
+	}
+	if c.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("invalid day of week in %q: %w", expr, err)
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	c.domAny = strings.HasPrefix(fields[2], "*")
+	c.dowAny = strings.HasPrefix(fields[4], "*")
+	return c, nil
+}
+
+// parseCronField returns the values field lists between lower and upper
+// as a bit set
+func parseCronField(field string, lower, upper int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+		}
+		first, last := lower, upper
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			from, to, _ := strings.Cut(rangePart, "-")
+			var err error
+			if first, err = cronValue(from, lower, upper); err != nil {
+				return 0, err
+			}
+			if last, err = cronValue(to, lower, upper); err != nil {
+				return 0, err
+			}
+			if first > last {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			var err error
+			if first, err = cronValue(rangePart, lower, upper); err != nil {
+				return 0, err
+			}
+			// a single value with a step starts a range, as in "5/15"
+			if !hasStep {
+				last = first
+			}
+		}
+		for v := first; v <= last; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func cronValue(s string, lower, upper int) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < lower || v > upper {
+		return 0, fmt.Errorf("value %d out of range [%d-%d]", v, lower, upper)
+	}
+	return v, nil
+}
+
+// Matches tells if the minute of t is one the schedule runs at
+func (c *CronSchedule) Matches(t time.Time) bool {
+	return c.minute&(1<<uint(t.Minute())) != 0 &&
+		c.hour&(1<<uint(t.Hour())) != 0 &&
+		c.month&(1<<uint(t.Month())) != 0 &&
+		c.dayMatches(t)
+}
+
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// Next returns the first minute after t the schedule runs at, or the zero
+// time when it never does
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+	for t.Before(limit) {
+		y, m, d := t.Date()
+		switch {
+		case c.month&(1<<uint(m)) == 0:
+			t = time.Date(y, m+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.dayMatches(t):
+			t = time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(y, m, d, t.Hour()+1, 0, 0, 0, t.Location())
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Schedule takes snapshots at the times of a cron expression, and records
+// how its last runs went
+type Schedule struct {
+	ID   string `json:"id"`
+	Cron string `json:"cron"`
+	// Network is the network snapshotted, or empty for the one running
+	Network     string    `json:"network,omitempty"`
+	Incremental bool      `json:"incremental"`
+	CreatedAt   time.Time `json:"createdAt"`
+
+	LastRun      time.Time `json:"lastRun"`
+	LastSuccess  time.Time `json:"lastSuccess"`
+	LastSnapshot string    `json:"lastSnapshot,omitempty"`
+	LastFailure  time.Time `json:"lastFailure"`
+	LastError    string    `json:"lastError,omitempty"`
+}
+
+// Next returns when the schedule runs next after t, or the zero time when
+// it never does or its expression is invalid
+func (s *Schedule) Next(t time.Time) time.Time {
+	c, err := ParseCron(s.Cron)
+	if err != nil {
+		return time.Time{}
+	}
+	return c.Next(t)
+}
+
+// ScheduleRunner takes the snapshot of schedule falling due at, and returns
+// its name
+type ScheduleRunner func(schedule *Schedule, at time.Time) (string, error)
+
+func (sm *SnapshotManager) schedulesPath() string {
+	return filepath.Join(sm.baseDir, "snapshots", SchedulesFile)
+}
+
+// Schedules returns the snapshot schedules, by ID
+func (sm *SnapshotManager) Schedules() ([]*Schedule, error) {
+	data, err := os.ReadFile(sm.schedulesPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var schedules []*Schedule
+	if err := json.Unmarshal(data, &schedules); err != nil {
+		return nil, fmt.Errorf("invalid snapshot schedules %s: %w", sm.schedulesPath(), err)
+	}
+	return schedules, nil
+}
+
+// saveSchedules writes schedules through a rename, so the scheduler never
+// reads a partial file
+func (sm *SnapshotManager) saveSchedules(schedules []*Schedule) error {
+	data, err := json.MarshalIndent(schedules, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := sm.schedulesPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// AddSchedule schedules snapshots of network, or the running one when
+// empty, at the times of the cron expression
+func (sm *SnapshotManager) AddSchedule(cron, network string, incremental bool) (*Schedule, error) {
+	if _, err := ParseCron(cron); err != nil {
+		return nil, err
+	}
+	schedules, err := sm.Schedules()
+	if err != nil {
+		return nil, err
+	}
+	id := 1
+	for _, s := range schedules {
+		if n, err := strconv.Atoi(s.ID); err == nil && n >= id {
+			id = n + 1
+		}
+	}
+	s := &Schedule{
+		ID:          strconv.Itoa(id),
+		Cron:        strings.TrimSpace(cron),
+		Network:     network,
+		Incremental: incremental,
+		CreatedAt:   time.Now(),
+	}
+	if err := sm.saveSchedules(append(schedules, s)); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// RemoveSchedule removes the schedule id
+func (sm *SnapshotManager) RemoveSchedule(id string) error {
+	schedules, err := sm.Schedules()
+	if err != nil {
+		return err
+	}
+	for i, s := range schedules {
+		if s.ID == id {
+			return sm.saveSchedules(append(schedules[:i], schedules[i+1:]...))
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrScheduleNotFound, id)
+}
+
+// RecordScheduleRun records that the run of schedule id falling due at
+// took snapshot name, or failed with runErr. Runs of schedules removed
+// meanwhile are not recorded.
+func (sm *SnapshotManager) RecordScheduleRun(id string, at time.Time, name string, runErr error) error {
+	schedules, err := sm.Schedules()
+	if err != nil {
+		return err
+	}
+	for _, s := range schedules {
+		if s.ID != id {
+			continue
+		}
+		s.LastRun = at
+		if runErr != nil {
+			s.LastFailure = at
+			s.LastError = runErr.Error()
+		} else {
+			s.LastSuccess = at
+			s.LastSnapshot = name
+		}
+		return sm.saveSchedules(schedules)
+	}
+	return nil
+}
+
+// RunDueSchedules runs the schedules falling due at, the start of a minute,
+// one after the other, and records how they went
+func (sm *SnapshotManager) RunDueSchedules(at time.Time, run ScheduleRunner) error {
+	schedules, err := sm.Schedules()
+	if err != nil {
+		return err
+	}
+	for _, s := range schedules {
+		c, err := ParseCron(s.Cron)
+		if err != nil || !c.Matches(at) {
+			continue
+		}
+		name, runErr := run(s, at)
+		if err := sm.RecordScheduleRun(s.ID, at, name, runErr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
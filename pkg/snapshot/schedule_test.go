@@ -0,0 +1,127 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	from := time.Date(2026, 1, 14, 13, 7, 30, 0, time.UTC) // a Wednesday
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"0 */6 * * *", time.Date(2026, 1, 14, 18, 0, 0, 0, time.UTC)},
+		{"* * * * *", time.Date(2026, 1, 14, 13, 8, 0, 0, time.UTC)},
+		{"5/15 * * * *", time.Date(2026, 1, 14, 13, 20, 0, 0, time.UTC)},
+		{"30 2 * * 1-5", time.Date(2026, 1, 15, 2, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)},
+		// both day fields restricted: either matches
+		{"0 0 20 * 5", time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+		}
+		got := c.Next(from)
+		if !got.Equal(tt.want) {
+			t.Errorf("%q: Next = %v, want %v", tt.expr, got, tt.want)
+		}
+		if !got.IsZero() && !c.Matches(got) {
+			t.Errorf("%q: does not match its next run %v", tt.expr, got)
+		}
+	}
+}
+
+func TestSchedules(t *testing.T) {
+	sm := NewSnapshotManager(t.TempDir())
+	if _, err := sm.AddSchedule("0 25 * * *", "", true); err == nil {
+		t.Fatal("expected an invalid cron expression to be rejected")
+	}
+	every6h, err := sm.AddSchedule("0 */6 * * *", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	daily, err := sm.AddSchedule("@daily", "mainnet", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if every6h.ID != "1" || daily.ID != "2" {
+		t.Fatalf("IDs = %s, %s, want 1, 2", every6h.ID, daily.ID)
+	}
+
+	var ran []string
+	run := func(s *Schedule, at time.Time) (string, error) {
+		ran = append(ran, s.ID)
+		if s.Network == "mainnet" {
+			return "", errors.New("no network running")
+		}
+		return "devnet-" + at.Format("2006-01-02-1504"), nil
+	}
+	midnight := time.Date(2026, 1, 14, 0, 0, 0, 0, time.UTC)
+	if err := sm.RunDueSchedules(midnight.Add(time.Hour), run); err != nil {
+		t.Fatal(err)
+	}
+	if len(ran) != 0 {
+		t.Fatalf("ran %v at 01:00, want none", ran)
+	}
+	if err := sm.RunDueSchedules(midnight, run); err != nil {
+		t.Fatal(err)
+	}
+	if len(ran) != 2 {
+		t.Fatalf("ran %v at midnight, want both", ran)
+	}
+
+	schedules, err := sm.Schedules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := schedules[0]; !s.LastSuccess.Equal(midnight) || s.LastSnapshot != "devnet-2026-01-14-0000" || !s.LastFailure.IsZero() {
+		t.Errorf("schedule 1 = %+v, want a success at midnight", s)
+	}
+	if s := schedules[1]; !s.LastFailure.Equal(midnight) || s.LastError != "no network running" || !s.LastSuccess.IsZero() {
+		t.Errorf("schedule 2 = %+v, want a failure at midnight", s)
+	}
+
+	if err := sm.RemoveSchedule("1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.RemoveSchedule("1"); !errors.Is(err, ErrScheduleNotFound) {
+		t.Fatalf("removing a removed schedule: %v", err)
+	}
+	next, err := sm.AddSchedule("@hourly", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.ID != "3" {
+		t.Errorf("ID = %s, want 3", next.ID)
+	}
+}
@@ -4,6 +4,7 @@
 package snapshot
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -19,9 +20,9 @@ import (
 	"time"
 
 	"github.com/klauspost/compress/zstd"
+	"github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/cli/pkg/ux"
 	"github.com/luxfi/database"
-	"github.com/luxfi/database/badgerdb"
 )
 
 // ChunkSize is the maximum size for a backup chunk (99MB to fit GitHub limits)
@@ -39,6 +40,7 @@ type SnapshotManifest struct {
 	CreatedAt          string          `json:"created_at"`
 	LastVersion        uint64          `json:"last_version"`
 	PrevManifestSHA256 string          `json:"prev_manifest_sha256,omitempty"`
+	DBType             string          `json:"db_type,omitempty"` // Empty means badgerdb
 }
 
 // SnapshotEntry represents a backup entry (base or incremental)
@@ -168,14 +170,32 @@ type snapshotTask struct {
 	nodeID      uint64
 	dbPath      string
 	chainDataID string // empty for main DB, set for chainData
+	dbType      DBType
 	incremental bool
+	uri         string // API endpoint of the node, used when the DB is locked
 }
 
 // snapshotResult represents the result of a snapshot operation
 type snapshotResult struct {
 	task snapshotTask
 	err  error
-	mode string // "base", "incremental", or "skipped"
+	mode string // "base", "incremental", "hot", or "skipped"
+}
+
+// label names the DB of the task, as network/node main DB or chain
+func (t snapshotTask) label() string {
+	if t.chainDataID == "" {
+		return fmt.Sprintf("%s/%s main DB", t.network, t.nodeName)
+	}
+	return fmt.Sprintf("%s/%s chain %s", t.network, t.nodeName, t.chainDataID[:8])
+}
+
+// status is the snapshot mode of the result, or its error
+func (r snapshotResult) status() string {
+	if r.err != nil {
+		return "failed: " + r.err.Error()
+	}
+	return r.mode
 }
 
 // CreateSnapshot creates a snapshot of all discovered local networks and nodes
@@ -205,8 +225,8 @@ func (sm *SnapshotManager) CreateSnapshot(snapshotName string, incremental bool)
 		netDir := filepath.Join(runsDir, networkName)
 		currentLink := filepath.Join(netDir, "current")
 		runDir := ""
-		if target, err := os.Readlink(currentLink); err == nil {
-			runDir = filepath.Join(netDir, target)
+		if target, err := utils.ReadDirLink(currentLink); err == nil {
+			runDir = target
 		} else {
 			runEntries, _ := os.ReadDir(netDir)
 			for _, re := range runEntries {
@@ -239,20 +259,34 @@ func (sm *SnapshotManager) CreateSnapshot(snapshotName string, incremental bool)
 				dbMatches, _ = filepath.Glob(filepath.Join(runDir, nodeName, "db"))
 			}
 			if len(dbMatches) > 0 {
-				tasks = append(tasks, snapshotTask{
-					network:     networkName,
-					nodeName:    nodeName,
-					nodeID:      nodeID,
-					dbPath:      dbMatches[0],
-					chainDataID: "",
-					incremental: incremental,
-				})
+				dbType, err := DetectDBType(dbMatches[0])
+				if err != nil {
+					ux.Logger.PrintToUser("Skipping %s/%s main DB: %v", networkName, nodeName, err)
+				} else {
+					tasks = append(tasks, snapshotTask{
+						network:     networkName,
+						nodeName:    nodeName,
+						nodeID:      nodeID,
+						dbPath:      dbMatches[0],
+						chainDataID: "",
+						dbType:      dbType,
+						incremental: incremental,
+						uri:         nodeURI(filepath.Join(runDir, nodeName)),
+					})
+				}
 			}
 
-			// ChainData tasks
-			chainDataPattern := filepath.Join(runDir, nodeName, "chainData", "network-*", "*", "db", "badgerdb")
+			// ChainData tasks: the engine directory is named after the engine
+			chainDataPattern := filepath.Join(runDir, nodeName, "chainData", "network-*", "*", "db", "*")
 			chainDBMatches, _ := filepath.Glob(chainDataPattern)
 			for _, chainDBPath := range chainDBMatches {
+				dbType, err := ParseDBType(filepath.Base(chainDBPath))
+				if err != nil {
+					continue
+				}
+				if detected, err := DetectDBType(chainDBPath); err == nil {
+					dbType = detected
+				}
 				parts := strings.Split(chainDBPath, string(os.PathSeparator))
 				var chainDataID string
 				for i, p := range parts {
@@ -270,6 +304,7 @@ func (sm *SnapshotManager) CreateSnapshot(snapshotName string, incremental bool)
 					nodeID:      nodeID,
 					dbPath:      chainDBPath,
 					chainDataID: chainDataID,
+					dbType:      dbType,
 					incremental: incremental,
 				})
 			}
@@ -277,6 +312,7 @@ func (sm *SnapshotManager) CreateSnapshot(snapshotName string, incremental bool)
 	}
 
 	// Execute tasks in parallel
+	progress := ux.Progress.Start("snapshot create", len(tasks))
 	var wg sync.WaitGroup
 	results := make(chan snapshotResult, len(tasks))
 
@@ -295,13 +331,35 @@ func (sm *SnapshotManager) CreateSnapshot(snapshotName string, incremental bool)
 		close(results)
 	}()
 
-	// Collect and report results
+	var done []snapshotResult
+	locked := make(map[string][]snapshotTask)
 	for result := range results {
+		progress.Step(result.task.label(), "%s", result.status())
+		if result.mode == "skipped" && result.task.chainDataID == "" && result.task.uri != "" && result.task.dbType == BadgerDB {
+			locked[result.task.network] = append(locked[result.task.network], result.task)
+			continue
+		}
+		done = append(done, result)
+	}
+
+	// Main DBs locked by running nodes are backed up by the nodes themselves
+	networks := make([]string, 0, len(locked))
+	for network := range locked {
+		networks = append(networks, network)
+	}
+	sort.Strings(networks)
+	for _, network := range networks {
+		progress.Step(network, "Backing up the %s main DBs locked by running nodes", network)
+		done = append(done, sm.hotSnapshotNetwork(network, locked[network], snapshotName)...)
+	}
+
+	// Report results
+	for _, result := range done {
 		if result.mode == "skipped" {
 			if result.task.chainDataID == "" {
 				ux.Logger.PrintToUser("Skipping %s/%s main DB: locked", result.task.network, result.task.nodeName)
 			} else {
-				ux.Logger.PrintToUser("Skipping %s/%s chain %s: locked", result.task.network, result.task.nodeName, result.task.chainDataID[:8])
+				ux.Logger.PrintToUser("Skipping %s/%s chain %s: locked, stop the network to include it", result.task.network, result.task.nodeName, result.task.chainDataID[:8])
 			}
 		} else if result.err != nil {
 			if result.task.chainDataID == "" {
@@ -318,17 +376,59 @@ func (sm *SnapshotManager) CreateSnapshot(snapshotName string, incremental bool)
 		}
 	}
 
+	progress.Done("Snapshot %s created", snapshotName)
 	return nil
 }
 
+// hotSnapshotNetwork backs up the locked main DBs of the running nodes of a
+// network through their admin API. The backups start together once the nodes
+// agree on the P-Chain height, so the snapshot is consistent across nodes.
+func (sm *SnapshotManager) hotSnapshotNetwork(network string, tasks []snapshotTask, snapshotName string) []snapshotResult {
+	nodes := make(map[string]NodeAdmin, len(tasks))
+	for _, task := range tasks {
+		nodes[task.nodeName] = NewNodeAdmin(task.uri)
+	}
+
+	ux.Logger.PrintToUser("%s is running, waiting for %d nodes to agree on a height for a hot snapshot...", network, len(tasks))
+	ctx, cancel := context.WithTimeout(context.Background(), HeightSyncTimeout)
+	height, err := WaitForConsistentHeight(ctx, nodes)
+	cancel()
+	results := make([]snapshotResult, len(tasks))
+	if err != nil {
+		for i, task := range tasks {
+			results[i] = snapshotResult{task: task, err: fmt.Errorf("hot snapshot: %w", err), mode: "hot"}
+		}
+		return results
+	}
+
+	var wg sync.WaitGroup
+	for i, task := range tasks {
+		wg.Add(1)
+		go func(i int, t snapshotTask) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), HotBackupTimeout)
+			defer cancel()
+			_, err := sm.CreateHotSnapshot(ctx, t.network, t.nodeID, nodes[t.nodeName], height, snapshotName)
+			results[i] = snapshotResult{task: t, err: err, mode: fmt.Sprintf("hot at height %d", height)}
+		}(i, task)
+	}
+	wg.Wait()
+	return results
+}
+
 // executeSnapshotTask executes a single snapshot task
 func (sm *SnapshotManager) executeSnapshotTask(task snapshotTask, snapshotName string) snapshotResult {
-	db, err := badgerdb.New(task.dbPath, nil, "", nil)
+	db, err := OpenDB(task.dbType, task.dbPath)
 	if err != nil {
 		return snapshotResult{task: task, mode: "skipped"}
 	}
 	defer db.Close()
 
+	// Only badgerdb tracks versions, other engines always take a base snapshot
+	if task.dbType != BadgerDB {
+		task.incremental = false
+	}
+
 	if task.chainDataID == "" {
 		// Main DB snapshot
 		var parentManifest *SnapshotManifest
@@ -427,6 +527,7 @@ func (sm *SnapshotManager) CreateBaseSnapshot(
 		StateRoot:    stateRoot,
 		CreatedAt:    time.Now().UTC().Format(time.RFC3339),
 		LastVersion:  lastVersion,
+		DBType:       string(dbTypeOf(db)),
 	}
 
 	if err := sm.writeManifest(snapshotDir, manifest); err != nil {
@@ -537,6 +638,7 @@ func (sm *SnapshotManager) CreateIncrementalSnapshot(
 		StateRoot:   parent.StateRoot,
 		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
 		LastVersion: newVersion,
+		DBType:      parent.DBType,
 	}
 
 	if err := sm.writeManifest(snapshotDir, manifest); err != nil {
@@ -609,6 +711,7 @@ func (sm *SnapshotManager) CreateChainDataSnapshot(
 		Incrementals: []SnapshotEntry{},
 		CreatedAt:    time.Now().UTC().Format(time.RFC3339),
 		LastVersion:  lastVersion,
+		DBType:       string(dbTypeOf(db)),
 	}
 
 	if err := sm.writeManifest(snapshotDir, manifest); err != nil {
@@ -708,6 +811,7 @@ func (sm *SnapshotManager) CreateIncrementalChainDataSnapshot(
 		}),
 		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
 		LastVersion: newVersion,
+		DBType:      parent.DBType,
 	}
 
 	if err := sm.writeManifest(snapshotDir, manifest); err != nil {
@@ -774,7 +878,12 @@ func (sm *SnapshotManager) RestoreChainSnapshot(
 	snapshotID string,
 ) error {
 
-	// Clear existing database - BadgerDB Load requires empty database
+	dbType, err := ParseDBType(manifest.DBType)
+	if err != nil {
+		return err
+	}
+
+	// Clear existing database - Load requires an empty database
 	if _, err := os.Stat(dbDir); err == nil {
 		if err := os.RemoveAll(dbDir); err != nil {
 			return fmt.Errorf("failed to clear existing db: %w", err)
@@ -785,9 +894,9 @@ func (sm *SnapshotManager) RestoreChainSnapshot(
 		return fmt.Errorf("failed to create db directory: %w", err)
 	}
 
-	db, err := badgerdb.New(dbDir, nil, "", nil)
+	db, err := OpenDB(dbType, dbDir)
 	if err != nil {
-		return fmt.Errorf("failed to open badger db: %w", err)
+		return fmt.Errorf("failed to open %s: %w", dbType, err)
 	}
 	defer db.Close()
 
@@ -821,6 +930,36 @@ func (sm *SnapshotManager) loadFromParts(db database.Database, chunksDir string,
 		return nil
 	}
 
+	ux.Logger.PrintToUser("📥 Restoring from %s (%d parts)", parts[0].Name, len(parts))
+
+	r, err := openParts(chunksDir, parts)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	if err := db.Load(r); err != nil {
+		return fmt.Errorf("db load failed: %w", err)
+	}
+	return nil
+}
+
+// partsReader is the decompressed stream of the parts of a snapshot entry
+type partsReader struct {
+	*zstd.Decoder
+	files []*os.File
+}
+
+func (r *partsReader) Close() error {
+	r.Decoder.Close()
+	for _, f := range r.files {
+		_ = f.Close()
+	}
+	return nil
+}
+
+// openParts returns the decompressed stream of parts, in chunksDir
+func openParts(chunksDir string, parts []Part) (io.ReadCloser, error) {
 	partPaths := make([]string, len(parts))
 	for i, part := range parts {
 		partPaths[i] = filepath.Join(chunksDir, part.Name)
@@ -829,38 +968,29 @@ func (sm *SnapshotManager) loadFromParts(db database.Database, chunksDir string,
 	// Sort by name ensures correct order (assuming part%05d naming)
 	sort.Strings(partPaths)
 
-	ux.Logger.PrintToUser("📥 Restoring from %s (%d parts)", parts[0].Name, len(parts))
-
-	files := make([]*os.File, 0, len(partPaths))
+	r := &partsReader{files: make([]*os.File, 0, len(partPaths))}
 	readers := make([]io.Reader, 0, len(partPaths))
 	for _, p := range partPaths {
 		f, err := os.Open(p)
 		if err != nil {
-			for _, ff := range files {
+			for _, ff := range r.files {
 				_ = ff.Close()
 			}
-			return err
+			return nil, err
 		}
-		files = append(files, f)
+		r.files = append(r.files, f)
 		readers = append(readers, f)
 	}
-	defer func() {
-		for _, f := range files {
-			_ = f.Close()
-		}
-	}()
 
-	compressed := io.MultiReader(readers...)
-	zr, err := zstd.NewReader(compressed)
+	zr, err := zstd.NewReader(io.MultiReader(readers...))
 	if err != nil {
-		return err
-	}
-	defer zr.Close()
-
-	if err := db.Load(zr); err != nil {
-		return fmt.Errorf("db load failed: %w", err)
+		for _, f := range r.files {
+			_ = f.Close()
+		}
+		return nil, err
 	}
-	return nil
+	r.Decoder = zr
+	return r, nil
 }
 
 // Squash combines base + incrementals into a new base
@@ -892,7 +1022,11 @@ func (sm *SnapshotManager) Squash(network string, chainID uint64, snapshotName s
 	}
 	defer os.RemoveAll(tempDir)
 
-	db, err := badgerdb.New(tempDir, nil, "", nil)
+	dbType, err := ParseDBType(manifest.DBType)
+	if err != nil {
+		return err
+	}
+	db, err := OpenDB(dbType, tempDir)
 	if err != nil {
 		return fmt.Errorf("failed to open temp db: %w", err)
 	}
@@ -1035,107 +1169,195 @@ func copyFile(src, dst string) error {
 // RestoreSnapshot restores a full snapshot (all networks/nodes)
 // Handles both main DB (chain_*) and chainData (chaindata_*) directories
 func (sm *SnapshotManager) RestoreSnapshot(snapshotName string) error {
-	ux.Logger.PrintToUser("Restoring snapshot '%s'...", snapshotName)
-	snapshotRoot := filepath.Join(sm.baseDir, "snapshots", snapshotName)
-	if _, err := os.Stat(snapshotRoot); os.IsNotExist(err) {
-		return fmt.Errorf("snapshot not found: %s", snapshotName)
+	return sm.RestoreSnapshotWithOptions(snapshotName, RestoreOptions{})
+}
+
+// restoreEntry is a DB of a snapshot to restore
+type restoreEntry struct {
+	network  string
+	name     string
+	nodeID   uint64
+	manifest SnapshotManifest
+}
+
+// label names the DB of the entry, as network/node main DB or chain
+func (e restoreEntry) label(network string, nodeID uint64) string {
+	if e.manifest.ChainDataID == "" {
+		return fmt.Sprintf("%s/node%d main DB", network, nodeID)
 	}
+	return fmt.Sprintf("%s/node%d chain %s", network, nodeID, e.manifest.ChainDataID[:8])
+}
+
+// snapshotRestoreEntries returns the main DB (chain_<nodeID>) and chainData
+// (chaindata_<nodeID>_<chainID>) entries of the networks of a snapshot
+func snapshotRestoreEntries(snapshotRoot string) ([]string, []restoreEntry, error) {
 	netEntries, err := os.ReadDir(snapshotRoot)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
+	var (
+		networks []string
+		entries  []restoreEntry
+	)
 	for _, netEntry := range netEntries {
 		if !netEntry.IsDir() {
 			continue
 		}
 		networkName := netEntry.Name()
-		netDir := filepath.Join(snapshotRoot, networkName)
-
-		// Find current run directory (shared by all restores)
-		runsDir := filepath.Join(sm.baseDir, "runs", networkName)
-		currentLink := filepath.Join(runsDir, "current")
-		runDir := ""
-		if target, err := os.Readlink(currentLink); err == nil {
-			runDir = filepath.Join(runsDir, target)
-		} else {
-			runEntries, _ := os.ReadDir(runsDir)
-			for _, re := range runEntries {
-				if re.IsDir() && strings.HasPrefix(re.Name(), "run_") {
-					runDir = filepath.Join(runsDir, re.Name())
-				}
-			}
-		}
-		if runDir == "" {
-			ux.Logger.PrintToUser("Skipping %s: no run directory found", networkName)
-			continue
-		}
-
-		entries, _ := os.ReadDir(netDir)
-		for _, entry := range entries {
+		networks = append(networks, networkName)
+		dirEntries, _ := os.ReadDir(filepath.Join(snapshotRoot, networkName))
+		for _, entry := range dirEntries {
 			if !entry.IsDir() {
 				continue
 			}
-			entryName := entry.Name()
-
-			manifestPath := filepath.Join(netDir, entryName, "manifest.json")
-			data, err := os.ReadFile(manifestPath)
+			data, err := os.ReadFile(filepath.Join(snapshotRoot, networkName, entry.Name(), "manifest.json"))
 			if err != nil {
 				continue
 			}
-			var manifest SnapshotManifest
-			if err := json.Unmarshal(data, &manifest); err != nil {
+			e := restoreEntry{network: networkName, name: entry.Name()}
+			if err := json.Unmarshal(data, &e.manifest); err != nil {
 				continue
 			}
-
-			// === Restore Main DB (chain_<nodeID>) ===
-			if strings.HasPrefix(entryName, "chain_") {
-				nodeIDStr := strings.TrimPrefix(entryName, "chain_")
-				nodeID, _ := strconv.ParseUint(nodeIDStr, 10, 64)
-
-				targetNodeDir := filepath.Join(runDir, fmt.Sprintf("node%d", nodeID))
-				targetDBPath := filepath.Join(targetNodeDir, "db", networkName, "db")
-				dbPattern := filepath.Join(targetNodeDir, "db", "*", "db")
-				matches, _ := filepath.Glob(dbPattern)
-				if len(matches) > 0 {
-					targetDBPath = matches[0]
-				}
-
-				if err := sm.RestoreChainSnapshot(networkName, nodeID, &manifest, targetDBPath, snapshotName); err != nil {
-					return fmt.Errorf("failed to restore %s/node%d main DB: %w", networkName, nodeID, err)
-				}
-				ux.Logger.PrintToUser("✓ Restored %s/node%d main DB", networkName, nodeID)
+			switch {
+			case strings.HasPrefix(e.name, "chain_"):
+				e.nodeID, _ = strconv.ParseUint(strings.TrimPrefix(e.name, "chain_"), 10, 64)
+				e.manifest.ChainDataID = ""
+			case strings.HasPrefix(e.name, "chaindata_") && e.manifest.ChainDataID != "":
+				e.nodeID = e.manifest.NodeID
+			default:
+				continue
 			}
+			entries = append(entries, e)
+		}
+	}
+	return networks, entries, nil
+}
 
-			// === Restore ChainData (chaindata_<nodeID>_<chainID>) ===
-			if strings.HasPrefix(entryName, "chaindata_") && manifest.ChainDataID != "" {
-				nodeID := manifest.NodeID
-				chainDataID := manifest.ChainDataID
+// RestoreSnapshotWithOptions restores a snapshot, remapping its networks and
+// nodes onto those of opts. Nodes the target run lacks are skipped.
+func (sm *SnapshotManager) RestoreSnapshotWithOptions(snapshotName string, opts RestoreOptions) error {
+	ux.Logger.PrintToUser("Restoring snapshot '%s'...", snapshotName)
+	snapshotRoot := filepath.Join(sm.baseDir, "snapshots", snapshotName)
+	if _, err := os.Stat(snapshotRoot); os.IsNotExist(err) {
+		return fmt.Errorf("snapshot not found: %s", snapshotName)
+	}
+	networks, entries, err := snapshotRestoreEntries(snapshotRoot)
+	if err != nil {
+		return err
+	}
+	targetNetworks, err := opts.targetNetworks(networks)
+	if err != nil {
+		return err
+	}
+	snapshotNodes := make(map[uint64]bool)
+	for _, e := range entries {
+		if _, ok := targetNetworks[e.network]; ok {
+			snapshotNodes[e.nodeID] = true
+		}
+	}
+	if missing := opts.unmappedNodes(snapshotNodes); len(missing) > 0 {
+		return fmt.Errorf("snapshot %s has no %s", snapshotName, strings.Join(missing, ", "))
+	}
 
-				// Target: runs/<net>/run_*/node<N>/chainData/network-<N>/<chainID>/db/badgerdb
-				targetNodeDir := filepath.Join(runDir, fmt.Sprintf("node%d", nodeID))
+	// Find the current run directory of each target network
+	runDirs := make(map[string]string, len(targetNetworks))
+	for _, source := range networks {
+		target, ok := targetNetworks[source]
+		if !ok {
+			continue
+		}
+		runDir := sm.currentRunDir(target)
+		if runDir == "" {
+			ux.Logger.PrintToUser("Skipping %s: no run directory found for %s", source, target)
+			continue
+		}
+		if source != target {
+			ux.Logger.PrintToUser("Restoring %s of the snapshot into %s", source, target)
+		}
+		runDirs[source] = runDir
+	}
 
-				// Find network-* subdirectory
-				chainDataBase := filepath.Join(targetNodeDir, "chainData")
-				networkDirs, _ := filepath.Glob(filepath.Join(chainDataBase, "network-*"))
-				if len(networkDirs) == 0 {
-					ux.Logger.PrintToUser("Skipping chaindata %s: no network-* dir", chainDataID[:8])
-					continue
-				}
+	progress := ux.Progress.Start("snapshot restore", len(entries))
+	for _, e := range entries {
+		runDir, ok := runDirs[e.network]
+		if !ok {
+			continue
+		}
+		targetNetwork := targetNetworks[e.network]
+		nodeID, ok := opts.targetNode(e.nodeID)
+		if !ok {
+			continue
+		}
+		label := e.label(targetNetwork, nodeID)
+		if targetNetwork != e.network || nodeID != e.nodeID {
+			label = fmt.Sprintf("%s from %s/node%d", label, e.network, e.nodeID)
+		}
+		targetNodeDir := filepath.Join(runDir, fmt.Sprintf("node%d", nodeID))
+		if _, err := os.Stat(targetNodeDir); err != nil {
+			ux.Logger.PrintToUser("Skipping %s: %s has no node%d, map it onto another node with --map node%d=nodeN", label, targetNetwork, nodeID, e.nodeID)
+			continue
+		}
 
-				// Use first network dir (should only be one)
-				networkDir := networkDirs[0]
-				targetDBPath := filepath.Join(networkDir, chainDataID, "db", "badgerdb")
+		// === Restore Main DB (chain_<nodeID>) ===
+		if e.manifest.ChainDataID == "" {
+			targetDBPath := filepath.Join(targetNodeDir, "db", targetNetwork, "db")
+			matches, _ := filepath.Glob(filepath.Join(targetNodeDir, "db", "*", "db"))
+			if len(matches) > 0 {
+				targetDBPath = matches[0]
+			}
 
-				if err := sm.RestoreChainDataSnapshot(&manifest, targetDBPath, snapshotName, entryName); err != nil {
-					return fmt.Errorf("failed to restore chaindata %s: %w", chainDataID[:8], err)
-				}
-				ux.Logger.PrintToUser("✓ Restored %s/node%d chain %s", networkName, nodeID, chainDataID[:8])
+			progress.Step(label, "Restoring %s", label)
+			if err := sm.RestoreChainSnapshot(e.network, e.nodeID, &e.manifest, targetDBPath, snapshotName); err != nil {
+				return progress.Fail(fmt.Errorf("failed to restore %s: %w", label, err))
 			}
+			ux.Logger.PrintToUser("✓ Restored %s", label)
+			continue
+		}
+
+		// === Restore ChainData (chaindata_<nodeID>_<chainID>) ===
+		// Target: runs/<net>/run_*/node<N>/chainData/network-<N>/<chainID>/db/<dbType>
+		chainDataID := e.manifest.ChainDataID
+		networkDirs, _ := filepath.Glob(filepath.Join(targetNodeDir, "chainData", "network-*"))
+		if len(networkDirs) == 0 {
+			ux.Logger.PrintToUser("Skipping chaindata %s: no network-* dir", chainDataID[:8])
+			continue
 		}
+
+		// Use first network dir (should only be one)
+		networkDir := networkDirs[0]
+		dbType, err := ParseDBType(e.manifest.DBType)
+		if err != nil {
+			return progress.Fail(fmt.Errorf("failed to restore chaindata %s: %w", chainDataID[:8], err))
+		}
+		targetDBPath := filepath.Join(networkDir, chainDataID, "db", string(dbType))
+
+		progress.Step(label, "Restoring %s", label)
+		if err := sm.RestoreChainDataSnapshot(&e.manifest, targetDBPath, snapshotName, e.name); err != nil {
+			return progress.Fail(fmt.Errorf("failed to restore chaindata %s: %w", chainDataID[:8], err))
+		}
+		ux.Logger.PrintToUser("✓ Restored %s", label)
 	}
+	progress.Done("Snapshot %s restored", snapshotName)
 	return nil
 }
 
+// currentRunDir returns the current run directory of network, or empty when
+// it has none
+func (sm *SnapshotManager) currentRunDir(network string) string {
+	runsDir := filepath.Join(sm.baseDir, "runs", network)
+	if target, err := utils.ReadDirLink(filepath.Join(runsDir, "current")); err == nil {
+		return target
+	}
+	runDir := ""
+	runEntries, _ := os.ReadDir(runsDir)
+	for _, re := range runEntries {
+		if re.IsDir() && strings.HasPrefix(re.Name(), "run_") {
+			runDir = filepath.Join(runsDir, re.Name())
+		}
+	}
+	return runDir
+}
+
 // RestoreChainDataSnapshot restores a chainData snapshot
 func (sm *SnapshotManager) RestoreChainDataSnapshot(
 	manifest *SnapshotManifest,
@@ -1143,6 +1365,11 @@ func (sm *SnapshotManager) RestoreChainDataSnapshot(
 	snapshotID string,
 	entryName string,
 ) error {
+	dbType, err := ParseDBType(manifest.DBType)
+	if err != nil {
+		return err
+	}
+
 	// Clear existing database
 	if _, err := os.Stat(dbDir); err == nil {
 		if err := os.RemoveAll(dbDir); err != nil {
@@ -1154,9 +1381,9 @@ func (sm *SnapshotManager) RestoreChainDataSnapshot(
 		return fmt.Errorf("failed to create db directory: %w", err)
 	}
 
-	db, err := badgerdb.New(dbDir, nil, "", nil)
+	db, err := OpenDB(dbType, dbDir)
 	if err != nil {
-		return fmt.Errorf("failed to open badger db: %w", err)
+		return fmt.Errorf("failed to open %s: %w", dbType, err)
 	}
 	defer db.Close()
 
@@ -7,6 +7,7 @@ package ssh
 import (
 	"strings"
 
+	"github.com/luxfi/cli/pkg/binutils"
 	"github.com/luxfi/constants"
 	"github.com/luxfi/sdk/models"
 )
@@ -21,15 +22,20 @@ func NewHostInstaller(host *models.Host) *HostInstaller {
 	return &HostInstaller{Host: host}
 }
 
-// GetArch returns the architecture and OS of the remote host.
+// GetArch returns the architecture and OS of the remote host, using GOARCH
+// names (amd64, arm64) so Graviton and Ampere hosts get arm64 binaries.
 func (h *HostInstaller) GetArch() (string, string) {
 	goArhBytes, err := h.Host.Command("dpkg --print-architecture", nil, constants.SSHScriptTimeout)
 	if err != nil {
-		return "", ""
+		// Not a Debian based image (e.g. Amazon Linux), uname reports x86_64 or aarch64
+		goArhBytes, err = h.Host.Command("uname -m", nil, constants.SSHScriptTimeout)
+		if err != nil {
+			return "", ""
+		}
 	}
 	goOSBytes, err := h.Host.Command("uname -s", nil, constants.SSHScriptTimeout)
 	if err != nil {
 		return "", ""
 	}
-	return strings.TrimSpace(string(goArhBytes)), strings.TrimSpace(strings.ToLower(string(goOSBytes)))
+	return binutils.NormalizeArch(string(goArhBytes)), strings.TrimSpace(strings.ToLower(string(goOSBytes)))
 }
@@ -0,0 +1,91 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/luxfi/cli/pkg/secretref"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/sdk/models"
+)
+
+// SecretsDir is the tmpfs folder of a host that configs referencing
+// secrets are rendered to, so that their secrets never reach the host disk.
+// Services mount it read-only at the same path.
+const SecretsDir = "/dev/shm/lux-cli"
+
+// TemplatePath is where a config referencing secrets is kept on a host, with
+// its kms://secret/ references, while its path links to its rendering
+func TemplatePath(remotePath string) string {
+	return remotePath + ".tmpl"
+}
+
+// RunSSHUploadConfig uploads the config content to remotePath on host. When
+// content references secrets, it is kept as a template beside remotePath,
+// rendered with resolver into SecretsDir, and remotePath links to the
+// rendering. A host reboot empties SecretsDir, leaving the link dangling
+// until the config is uploaded again.
+func RunSSHUploadConfig(host *models.Host, content []byte, remotePath string, resolver secretref.Resolver) error {
+	if err := host.MkdirAll(path.Dir(remotePath), constants.SSHDirOpsTimeout); err != nil {
+		return err
+	}
+	// drop the rendering of a previous upload, so the config isn't written
+	// through its link
+	rendered := path.Join(SecretsDir, remotePath)
+	if _, err := host.Command(
+		fmt.Sprintf("if [ -L %[1]s ]; then rm -f %[1]s; fi; rm -f %[2]s %[3]s", remotePath, TemplatePath(remotePath), rendered),
+		nil,
+		constants.SSHFileOpsTimeout,
+	); err != nil {
+		return err
+	}
+	if len(secretref.Refs(content)) == 0 {
+		return host.UploadBytes(content, remotePath, constants.SSHFileOpsTimeout)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.APIRequestLargeTimeout)
+	defer cancel()
+	data, err := secretref.Render(ctx, content, resolver)
+	if err != nil {
+		return fmt.Errorf("error rendering %s: %w", remotePath, err)
+	}
+	if err := host.UploadBytes(content, TemplatePath(remotePath), constants.SSHFileOpsTimeout); err != nil {
+		return err
+	}
+	// the rendering is created private before its secrets are written
+	if _, err := host.Command(
+		fmt.Sprintf(`sudo install -d -o "$(id -u)" -g "$(id -g)" -m 700 %[1]s && mkdir -p %[2]s && install -m 600 /dev/null %[3]s`,
+			SecretsDir, path.Dir(rendered), rendered),
+		nil,
+		constants.SSHFileOpsTimeout,
+	); err != nil {
+		return err
+	}
+	if err := host.UploadBytes(data, rendered, constants.SSHFileOpsTimeout); err != nil {
+		return err
+	}
+	_, err = host.Command(fmt.Sprintf("ln -sfn %s %s", rendered, remotePath), nil, constants.SSHFileOpsTimeout)
+	return err
+}
+
+// RunSSHUploadSecretConfig takes the secrets held in plain text out of the
+// config content, into the dir folder of kms, and uploads the resulting
+// template to remotePath on host
+func RunSSHUploadSecretConfig(host *models.Host, content []byte, remotePath, dir string, kms secretref.KMS) error {
+	template, secrets, err := secretref.Extract(content, dir)
+	if err != nil {
+		return err
+	}
+	if len(secrets) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), constants.APIRequestLargeTimeout)
+		defer cancel()
+		if err := secretref.StoreAll(ctx, kms, secrets); err != nil {
+			return err
+		}
+	}
+	return RunSSHUploadConfig(host, template, remotePath, kms)
+}
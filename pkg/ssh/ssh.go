@@ -12,6 +12,7 @@ import (
 	"maps"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -25,6 +26,7 @@ import (
 	"github.com/luxfi/cli/pkg/docker"
 	"github.com/luxfi/cli/pkg/monitoring"
 	"github.com/luxfi/cli/pkg/remoteconfig"
+	"github.com/luxfi/cli/pkg/secretref"
 	"github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/cli/pkg/ux"
 	"github.com/luxfi/constants"
@@ -124,10 +126,10 @@ func RunSSHSetupNode(host *models.Host, configPath string) error {
 		return err
 	}
 	// name: copy metrics config to cloud server
-	ux.Logger.Info("Uploading config %s to server %s: %s", configPath, host.NodeID, filepath.Join(constants.CloudNodeCLIConfigBasePath, filepath.Base(configPath)))
+	ux.Logger.Info("Uploading config %s to server %s: %s", configPath, host.NodeID, path.Join(constants.CloudNodeCLIConfigBasePath, filepath.Base(configPath)))
 	if err := host.Upload(
 		configPath,
-		filepath.Join(constants.CloudNodeCLIConfigBasePath, filepath.Base(configPath)),
+		path.Join(constants.CloudNodeCLIConfigBasePath, filepath.Base(configPath)),
 		constants.SSHFileOpsTimeout,
 	); err != nil {
 		return err
@@ -271,7 +273,7 @@ func RunSSHUpdateMonitoringDashboards(host *models.Host, monitoringDashboardPath
 	}
 	if err := host.Upload(
 		filepath.Join(monitoringDashboardPath, constants.CustomGrafanaDashboardJSON),
-		filepath.Join(remoteDashboardsPath, constants.CustomGrafanaDashboardJSON),
+		path.Join(remoteDashboardsPath, constants.CustomGrafanaDashboardJSON),
 		constants.SSHFileOpsTimeout,
 	); err != nil {
 		return err
@@ -305,7 +307,7 @@ func RunSSHCopyMonitoringDashboards(host *models.Host, monitoringDashboardPath s
 		for _, dashboard := range dashboards {
 			if err := host.Upload(
 				filepath.Join(monitoringDashboardPath, dashboard.Name()),
-				filepath.Join(remoteDashboardsPath, dashboard.Name()),
+				path.Join(remoteDashboardsPath, dashboard.Name()),
 				constants.SSHFileOpsTimeout,
 			); err != nil {
 				return err
@@ -438,15 +440,18 @@ func RunSSHDownloadNodePrometheusConfig(host *models.Host, nodeInstanceDirPath s
 }
 
 // RunSSHUploadNodeWarpRelayerConfig uploads warp relayer config to the remote host.
-func RunSSHUploadNodeWarpRelayerConfig(host *models.Host, nodeInstanceDirPath string) error {
-	cloudWarpRelayerConfigDir := filepath.Join(constants.CloudNodeCLIConfigBasePath, constants.ServicesDir, constants.WarpRelayerInstallDir)
-	if err := host.MkdirAll(cloudWarpRelayerConfigDir, constants.SSHDirOpsTimeout); err != nil {
+// Its relayer keys are moved to kms, the host only gets references to them.
+func RunSSHUploadNodeWarpRelayerConfig(host *models.Host, nodeInstanceDirPath string, kms secretref.KMS) error {
+	content, err := os.ReadFile(filepath.Join(nodeInstanceDirPath, constants.ServicesDir, constants.WarpRelayerInstallDir, constants.WarpRelayerConfigFilename)) //nolint:gosec // G304: Reading relayer config from app's directory
+	if err != nil {
 		return err
 	}
-	return host.Upload(
-		filepath.Join(nodeInstanceDirPath, constants.ServicesDir, constants.WarpRelayerInstallDir, constants.WarpRelayerConfigFilename),
-		filepath.Join(cloudWarpRelayerConfigDir, constants.WarpRelayerConfigFilename),
-		constants.SSHFileOpsTimeout,
+	return RunSSHUploadSecretConfig(
+		host,
+		content,
+		path.Join(constants.CloudNodeCLIConfigBasePath, constants.ServicesDir, constants.WarpRelayerInstallDir, constants.WarpRelayerConfigFilename),
+		path.Join("/nodes", host.GetCloudID(), constants.WarpRelayerInstallDir),
+		kms,
 	)
 }
 
@@ -518,21 +523,21 @@ func RunSSHUploadStakingFiles(host *models.Host, nodeInstanceDirPath string) err
 	}
 	if err := host.Upload(
 		filepath.Join(nodeInstanceDirPath, constants.StakerCertFileName),
-		filepath.Join(constants.CloudNodeStakingPath, constants.StakerCertFileName),
+		path.Join(constants.CloudNodeStakingPath, constants.StakerCertFileName),
 		constants.SSHFileOpsTimeout,
 	); err != nil {
 		return err
 	}
 	if err := host.Upload(
 		filepath.Join(nodeInstanceDirPath, constants.StakerKeyFileName),
-		filepath.Join(constants.CloudNodeStakingPath, constants.StakerKeyFileName),
+		path.Join(constants.CloudNodeStakingPath, constants.StakerKeyFileName),
 		constants.SSHFileOpsTimeout,
 	); err != nil {
 		return err
 	}
 	return host.Upload(
 		filepath.Join(nodeInstanceDirPath, constants.BLSKeyFileName),
-		filepath.Join(constants.CloudNodeStakingPath, constants.BLSKeyFileName),
+		path.Join(constants.CloudNodeStakingPath, constants.BLSKeyFileName),
 		constants.SSHFileOpsTimeout,
 	)
 }
@@ -606,10 +611,10 @@ func RunSSHRenderLuxNodeConfig(
 	if nodeConfigFileExists(host) {
 		// make sure that genesis and bootstrap data is preserved
 		if genesisFileExists(host) {
-			luxdConf.GenesisPath = filepath.Join(constants.DockerNodeConfigPath, constants.GenesisFileName)
+			luxdConf.GenesisPath = path.Join(constants.DockerNodeConfigPath, constants.GenesisFileName)
 		}
 		if upgradeFileExists(host) {
-			luxdConf.UpgradePath = filepath.Join(constants.DockerNodeConfigPath, constants.UpgradeFileName)
+			luxdConf.UpgradePath = path.Join(constants.DockerNodeConfigPath, constants.UpgradeFileName)
 		}
 		if network.Kind() == models.Local || network.Kind() == models.Devnet || isAPIHost {
 			luxdConf.HTTPHost = "0.0.0.0"
@@ -643,7 +648,7 @@ func RunSSHRenderLuxNodeConfig(
 	if err != nil {
 		return err
 	}
-	return host.UploadBytes(nodeConf, remoteconfig.GetRemoteLuxNodeConfig(), constants.SSHFileOpsTimeout)
+	return RunSSHUploadConfig(host, nodeConf, remoteconfig.GetRemoteLuxNodeConfig(), nil)
 }
 
 // RunSSHCreatePlugin runs script to create plugin
@@ -708,12 +713,15 @@ func RunSSHCreatePlugin(host *models.Host, sc models.Sidecar) error {
 	return nil
 }
 
-// RunSSHMergeChainNodeConfig merges chain node config to the node config on the remote host
-func mergeChainNodeConfig(host *models.Host, chainNodeConfigPath string) error {
-	if chainNodeConfigPath == "" {
-		return fmt.Errorf("node config path is empty")
+// mergeChainNodeConfig merges the chain node config template to the node
+// config on the remote host. The template of the node config is merged to
+// when there is one, so that its secrets stay references.
+func mergeChainNodeConfig(host *models.Host, chainNodeConfigBytes []byte, resolver secretref.Resolver) error {
+	remoteNodeConfigPath := remoteconfig.GetRemoteLuxNodeConfig()
+	if exists, _ := host.FileExists(TemplatePath(remoteNodeConfigPath)); exists {
+		remoteNodeConfigPath = TemplatePath(remoteNodeConfigPath)
 	}
-	remoteNodeConfigBytes, err := host.ReadFileBytes(remoteconfig.GetRemoteLuxNodeConfig(), constants.SSHFileOpsTimeout)
+	remoteNodeConfigBytes, err := host.ReadFileBytes(remoteNodeConfigPath, constants.SSHFileOpsTimeout)
 	if err != nil {
 		return fmt.Errorf("error reading remote node config: %w", err)
 	}
@@ -721,10 +729,6 @@ func mergeChainNodeConfig(host *models.Host, chainNodeConfigPath string) error {
 	if err := json.Unmarshal(remoteNodeConfigBytes, &remoteNodeConfig); err != nil {
 		return fmt.Errorf("error unmarshalling remote node config: %w", err)
 	}
-	chainNodeConfigBytes, err := os.ReadFile(chainNodeConfigPath) //nolint:gosec // G304: Reading node config from app's directory
-	if err != nil {
-		return fmt.Errorf("error reading node config: %w", err)
-	}
 	var chainNodeConfig map[string]interface{}
 	if err := json.Unmarshal(chainNodeConfigBytes, &chainNodeConfig); err != nil {
 		return fmt.Errorf("error unmarshalling node config: %w", err)
@@ -734,11 +738,20 @@ func mergeChainNodeConfig(host *models.Host, chainNodeConfigPath string) error {
 	if err != nil {
 		return fmt.Errorf("error creating merged node config: %w", err)
 	}
-	return host.UploadBytes(mergedNodeConfigBytes, remoteconfig.GetRemoteLuxNodeConfig(), constants.SSHFileOpsTimeout)
+	return RunSSHUploadConfig(host, mergedNodeConfigBytes, remoteconfig.GetRemoteLuxNodeConfig(), resolver)
+}
+
+// ChainConfigTemplate takes the secrets held in plain text out of the config
+// of kind of chainName, as RunSSHSyncChainData uploads it. The secrets are
+// to be stored in the KMS before.
+func ChainConfigTemplate(content []byte, chainName, kind string) ([]byte, map[secretref.Ref][]byte, error) {
+	return secretref.Extract(content, secretref.ConfigDir(chainName, kind))
 }
 
-// RunSSHSyncChainData syncs chain data required
-func RunSSHSyncChainData(app *application.Lux, host *models.Host, network models.Network, chainName string) error {
+// RunSSHSyncChainData syncs chain data required. The secrets held in plain
+// text by the configs are not uploaded, but rendered from the KMS with
+// resolver; see ChainConfigTemplate.
+func RunSSHSyncChainData(app *application.Lux, host *models.Host, network models.Network, chainName string, resolver secretref.Resolver) error {
 	sc, err := app.LoadSidecar(chainName)
 	if err != nil {
 		return err
@@ -760,7 +773,15 @@ func RunSSHSyncChainData(app *application.Lux, host *models.Host, network models
 	// chain node config
 	chainNodeConfigPath := app.GetLuxdNodeConfigPath(chainName)
 	if utils.FileExists(chainNodeConfigPath) {
-		if err := mergeChainNodeConfig(host, chainNodeConfigPath); err != nil {
+		chainNodeConfig, err := os.ReadFile(chainNodeConfigPath) //nolint:gosec // G304: Reading node config from app's directory
+		if err != nil {
+			return fmt.Errorf("error reading node config: %w", err)
+		}
+		template, _, err := ChainConfigTemplate(chainNodeConfig, chainName, "node")
+		if err != nil {
+			return err
+		}
+		if err := mergeChainNodeConfig(host, template, resolver); err != nil {
 			return err
 		}
 	}
@@ -770,11 +791,12 @@ func RunSSHSyncChainData(app *application.Lux, host *models.Host, network models
 		if err != nil {
 			return fmt.Errorf("error loading blockchain config: %w", err)
 		}
-		chainConfigPath := filepath.Join(constants.CloudNodeConfigPath, "chains", chainIDStr+".json")
-		if err := host.MkdirAll(filepath.Dir(chainConfigPath), constants.SSHDirOpsTimeout); err != nil {
+		template, _, err := ChainConfigTemplate(chainConfig, chainName, "chain")
+		if err != nil {
 			return err
 		}
-		if err := host.UploadBytes(chainConfig, chainConfigPath, constants.SSHFileOpsTimeout); err != nil {
+		chainConfigPath := remoteconfig.GetRemoteLuxChainConfig(chainIDStr)
+		if err := RunSSHUploadConfig(host, template, chainConfigPath, resolver); err != nil {
 			return fmt.Errorf("error uploading blockchain config to %s: %w", chainConfigPath, err)
 		}
 	}
@@ -786,11 +808,12 @@ func RunSSHSyncChainData(app *application.Lux, host *models.Host, network models
 		if err != nil {
 			return fmt.Errorf("error loading chain config: %w", err)
 		}
-		chainConfigPath := filepath.Join(constants.CloudNodeConfigPath, "chains", blockchainID.String(), "config.json")
-		if err := host.MkdirAll(filepath.Dir(chainConfigPath), constants.SSHDirOpsTimeout); err != nil {
+		template, _, err := ChainConfigTemplate(chainConfig, chainName, "blockchain")
+		if err != nil {
 			return err
 		}
-		if err := host.UploadBytes(chainConfig, chainConfigPath, constants.SSHFileOpsTimeout); err != nil {
+		chainConfigPath := remoteconfig.GetRemoteLuxBlockchainConfig(blockchainID.String())
+		if err := RunSSHUploadConfig(host, template, chainConfigPath, resolver); err != nil {
 			return fmt.Errorf("error uploading chain config to %s: %w", chainConfigPath, err)
 		}
 	}
@@ -802,8 +825,8 @@ func RunSSHSyncChainData(app *application.Lux, host *models.Host, network models
 		if err != nil {
 			return fmt.Errorf("error loading network upgrades: %w", err)
 		}
-		networkUpgradesPath := filepath.Join(constants.CloudNodeConfigPath, "chains", "chains", blockchainID.String(), "upgrade.json")
-		if err := host.MkdirAll(filepath.Dir(networkUpgradesPath), constants.SSHDirOpsTimeout); err != nil {
+		networkUpgradesPath := remoteconfig.GetRemoteLuxNetworkUpgrades(blockchainID.String())
+		if err := host.MkdirAll(path.Dir(networkUpgradesPath), constants.SSHDirOpsTimeout); err != nil {
 			return err
 		}
 		if err := host.UploadBytes(networkUpgrades, networkUpgradesPath, constants.SSHFileOpsTimeout); err != nil {
@@ -965,12 +988,12 @@ func composeFileExists(host *models.Host) bool {
 }
 
 func genesisFileExists(host *models.Host) bool {
-	genesisFileExists, _ := host.FileExists(filepath.Join(constants.CloudNodeConfigPath, constants.GenesisFileName))
+	genesisFileExists, _ := host.FileExists(path.Join(constants.CloudNodeConfigPath, constants.GenesisFileName))
 	return genesisFileExists
 }
 
 func upgradeFileExists(host *models.Host) bool {
-	upgradeFileExists, _ := host.FileExists(filepath.Join(constants.CloudNodeConfigPath, constants.UpgradeFileName))
+	upgradeFileExists, _ := host.FileExists(path.Join(constants.CloudNodeConfigPath, constants.UpgradeFileName))
 	return upgradeFileExists
 }
 
@@ -986,7 +1009,7 @@ func aliasConfigFileExists(host *models.Host) bool {
 
 func getLuxdConfigData(host *models.Host) (map[string]interface{}, error) {
 	// get remote node.json file
-	nodeJSONPath := filepath.Join(constants.CloudNodeConfigPath, constants.NodeConfigJSONFile)
+	nodeJSONPath := path.Join(constants.CloudNodeConfigPath, constants.NodeConfigJSONFile)
 	// parse node.json file
 	nodeJSON, err := host.ReadFileBytes(nodeJSONPath, constants.SSHFileOpsTimeout)
 	if err != nil {
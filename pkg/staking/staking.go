@@ -0,0 +1,149 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package staking estimates primary network staking rewards and orders the
+// stakes of validators by end time.
+package staking
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/luxfi/genesis/pkg/genesis"
+	"github.com/luxfi/protocol/p/reward"
+)
+
+// Year is the duration rewards are annualized over
+const Year = 365 * 24 * time.Hour
+
+var (
+	ErrStakeAmount   = errors.New("invalid stake amount")
+	ErrStakeDuration = errors.New("invalid stake duration")
+)
+
+// Params are the staking parameters of a network, amounts in the P-Chain
+// unit
+type Params struct {
+	MinValidatorStake uint64
+	MaxValidatorStake uint64
+	MinStakeDuration  time.Duration
+	MaxStakeDuration  time.Duration
+	Reward            reward.Config
+}
+
+// NetworkParams returns the genesis staking parameters of networkID
+func NetworkParams(networkID uint32) Params {
+	p := genesis.GetParams(networkID)
+	return Params{
+		MinValidatorStake: p.MinValidatorStake,
+		MaxValidatorStake: p.MaxValidatorStake,
+		MinStakeDuration:  time.Duration(p.MinStakeDuration) * time.Second,
+		MaxStakeDuration:  time.Duration(p.MaxStakeDuration) * time.Second,
+		Reward: reward.Config{
+			MaxConsumptionRate: p.RewardConfig.MaxConsumptionRate,
+			MinConsumptionRate: p.RewardConfig.MinConsumptionRate,
+			MintingPeriod:      time.Duration(p.RewardConfig.MintingPeriod) * time.Second,
+			SupplyCap:          p.RewardConfig.SupplyCap,
+		},
+	}
+}
+
+// Estimate is the expected reward of a validator stake
+type Estimate struct {
+	Amount        uint64
+	Duration      time.Duration
+	CurrentSupply uint64
+	Reward        uint64
+	// APR is the reward per year, in percent of the stake
+	APR float64
+}
+
+// EstimateReward returns the reward of staking amount for duration when the
+// supply is currentSupply, as the P-Chain computes it, assuming the uptime
+// requirement is met
+func EstimateReward(p Params, amount, currentSupply uint64, duration time.Duration) (Estimate, error) {
+	if amount < p.MinValidatorStake || amount > p.MaxValidatorStake {
+		return Estimate{}, fmt.Errorf("%w: a validator stakes between %d and %d", ErrStakeAmount, p.MinValidatorStake, p.MaxValidatorStake)
+	}
+	if duration < p.MinStakeDuration || duration > p.MaxStakeDuration {
+		return Estimate{}, fmt.Errorf("%w: a validator stakes between %s and %s", ErrStakeDuration, p.MinStakeDuration, p.MaxStakeDuration)
+	}
+	if currentSupply == 0 || currentSupply > p.Reward.SupplyCap {
+		return Estimate{}, fmt.Errorf("invalid current supply %d, the supply cap is %d", currentSupply, p.Reward.SupplyCap)
+	}
+	r := reward.NewCalculator(p.Reward).Calculate(duration, amount, currentSupply)
+	return Estimate{
+		Amount:        amount,
+		Duration:      duration,
+		CurrentSupply: currentSupply,
+		Reward:        r,
+		APR:           float64(r) / float64(amount) * float64(Year) / float64(duration) * 100,
+	}, nil
+}
+
+// Stake is the current stake of a validator. A node that is not validating
+// has a zero End.
+type Stake struct {
+	NodeID string
+	// Sources are where the node is known from, e.g. a cluster or a chain
+	Sources         []string
+	Start           time.Time
+	End             time.Time
+	Weight          uint64
+	PotentialReward uint64
+}
+
+// Validating reports whether the node has a current stake
+func (s Stake) Validating() bool {
+	return !s.End.IsZero()
+}
+
+// KnownNodes are the nodes known locally, with where they are known from
+type KnownNodes map[string][]string
+
+// Add records that nodeID is known from source
+func (k KnownNodes) Add(nodeID, source string) {
+	if nodeID == "" || slices.Contains(k[nodeID], source) {
+		return
+	}
+	k[nodeID] = append(k[nodeID], source)
+}
+
+// NodeIDs returns the known nodes, sorted
+func (k KnownNodes) NodeIDs() []string {
+	nodeIDs := make([]string, 0, len(k))
+	for nodeID := range k {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Strings(nodeIDs)
+	return nodeIDs
+}
+
+// Schedule returns the stakes of the known nodes, the next ending first,
+// followed by the nodes that are not validating
+func Schedule(known KnownNodes, current []Stake) []Stake {
+	byNodeID := make(map[string]Stake, len(current))
+	for _, s := range current {
+		byNodeID[s.NodeID] = s
+	}
+	schedule := make([]Stake, 0, len(known))
+	for _, nodeID := range known.NodeIDs() {
+		s, ok := byNodeID[nodeID]
+		if !ok {
+			s = Stake{NodeID: nodeID}
+		}
+		s.Sources = known[nodeID]
+		schedule = append(schedule, s)
+	}
+	sort.SliceStable(schedule, func(i, j int) bool {
+		a, b := schedule[i], schedule[j]
+		if a.Validating() != b.Validating() {
+			return a.Validating()
+		}
+		return a.End.Before(b.End)
+	})
+	return schedule
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luxfi/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateReward(t *testing.T) {
+	require := require.New(t)
+	p := NetworkParams(constants.MainnetID)
+	require.Equal(14*24*time.Hour, p.MinStakeDuration)
+	require.Equal(Year, p.Reward.MintingPeriod)
+
+	// with half of the cap minted, a year long stake earns the max
+	// consumption rate of the remaining supply, pro rata
+	supply := p.Reward.SupplyCap / 2
+	e, err := EstimateReward(p, 2000*constants.Lux, supply, Year)
+	require.NoError(err)
+	require.Equal(240*constants.Lux, e.Reward)
+	require.InDelta(12.0, e.APR, 0.0001)
+
+	// shorter stakes earn a lower rate
+	short, err := EstimateReward(p, 2000*constants.Lux, supply, Year/2)
+	require.NoError(err)
+	require.Less(short.APR, e.APR)
+	require.Greater(short.APR, 10.0)
+
+	_, err = EstimateReward(p, constants.Lux, supply, Year)
+	require.ErrorIs(err, ErrStakeAmount)
+	_, err = EstimateReward(p, 2000*constants.Lux, supply, time.Hour)
+	require.ErrorIs(err, ErrStakeDuration)
+	_, err = EstimateReward(p, 2000*constants.Lux, 0, Year)
+	require.ErrorContains(err, "invalid current supply")
+}
+
+func TestSchedule(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+	known := KnownNodes{}
+	known.Add("NodeID-a", "local")
+	known.Add("NodeID-b", "cluster my-devnet")
+	known.Add("NodeID-b", "chain mychain")
+	known.Add("NodeID-b", "chain mychain")
+	known.Add("NodeID-c", "local")
+	known.Add("", "local")
+
+	schedule := Schedule(known, []Stake{
+		{NodeID: "NodeID-a", Start: now, End: now.Add(48 * time.Hour)},
+		{NodeID: "NodeID-b", Start: now, End: now.Add(time.Hour)},
+		{NodeID: "NodeID-unknown", Start: now, End: now.Add(time.Minute)},
+	})
+	require.Len(schedule, 3)
+	require.Equal("NodeID-b", schedule[0].NodeID)
+	require.Equal([]string{"cluster my-devnet", "chain mychain"}, schedule[0].Sources)
+	require.Equal("NodeID-a", schedule[1].NodeID)
+	require.Equal("NodeID-c", schedule[2].NodeID)
+	require.False(schedule[2].Validating())
+}
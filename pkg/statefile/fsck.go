@@ -0,0 +1,136 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statefile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// leftoverAge is how old a temporary file is before it is considered left
+// over by a crash rather than being written
+const leftoverAge = time.Minute
+
+// Status is the condition of a state file found by Check
+type Status string
+
+const (
+	StatusOK         Status = "ok"
+	StatusLegacy     Status = "legacy"
+	StatusCorrupt    Status = "corrupt"
+	StatusNewer      Status = "newer"
+	StatusUnreadable Status = "unreadable"
+	StatusLeftover   Status = "leftover"
+)
+
+// Temporary is the kind of the temporary files left over by a crash
+var Temporary = Kind{Name: "temporary file"}
+
+// File is a state file and its kind
+type File struct {
+	Path string
+	Kind Kind
+}
+
+// Finding is the condition of a file
+type Finding struct {
+	File
+	Status  Status
+	Version int
+	Err     error
+}
+
+// Check returns the condition of the state file f
+func Check(f File) Finding {
+	finding := Finding{File: f}
+	data, err := os.ReadFile(f.Path) //nolint:gosec // G304: Reading a state file
+	if err != nil {
+		finding.Status, finding.Err = StatusUnreadable, err
+		return finding
+	}
+	finding.Version, finding.Err = Version(data)
+	switch {
+	case finding.Err != nil:
+		finding.Status = StatusCorrupt
+	case finding.Version > f.Kind.Version:
+		finding.Status = StatusNewer
+	case finding.Version < f.Kind.Version:
+		finding.Status = StatusLegacy
+	default:
+		finding.Status = StatusOK
+	}
+	return finding
+}
+
+// Leftovers returns the temporary files of dir left over by writes that
+// crashed before their rename
+func Leftovers(dir string) ([]Finding, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+tempInfix+"*"))
+	if err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil || !info.Mode().IsRegular() || time.Since(info.ModTime()) < leftoverAge {
+			continue
+		}
+		findings = append(findings, Finding{File: File{Path: match, Kind: Temporary}, Status: StatusLeftover})
+	}
+	return findings, nil
+}
+
+// Repair fixes the file of f, returning what it did, empty when there was
+// nothing to do. Legacy files are stamped with the version of their
+// schema, their content being compatible. Corrupted files are restored from
+// their backup when it is valid, otherwise set aside with CorruptSuffix so
+// that the CLI recreates them. Left over temporary files are removed. Files
+// of a newer schema, or unreadable, are left alone.
+func Repair(f Finding) (string, error) {
+	switch f.Status {
+	case StatusLegacy:
+		var obj map[string]json.RawMessage
+		if _, err := ReadJSON(f.Path, f.Kind, &obj); err != nil {
+			return "", err
+		}
+		if err := WriteJSON(f.Path, f.Kind, obj, perm(f.Path)); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("upgraded to schema %d", f.Kind.Version), nil
+	case StatusCorrupt:
+		backup := f.Path + BackupSuffix
+		if data, err := os.ReadFile(backup); err == nil { //nolint:gosec // G304: Reading a state file backup
+			if version, err := Version(data); err == nil && version <= f.Kind.Version {
+				if err := WriteFile(f.Path, data, perm(f.Path)); err != nil {
+					return "", err
+				}
+				return "restored from " + filepath.Base(backup), nil
+			}
+		}
+		aside := f.Path + CorruptSuffix
+		if err := os.Rename(f.Path, aside); err != nil {
+			return "", err
+		}
+		return "moved to " + filepath.Base(aside), nil
+	case StatusLeftover:
+		if err := os.Remove(f.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		return "removed", nil
+	}
+	return "", nil
+}
+
+// perm returns the permissions of the file at path, 0o644 if unknown
+func perm(path string) os.FileMode {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0o644
+	}
+	return info.Mode().Perm()
+}
@@ -0,0 +1,226 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package statefile writes the JSON state files of the CLI, such as
+// sidecars, cluster configs and network states, so that a crash or a full
+// disk never leaves one truncated: each file is written to a temporary file
+// of its directory, synced, then renamed in place. The previous content is
+// kept as a backup, and each file records the version of its schema.
+package statefile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/configschema"
+)
+
+const (
+	// VersionKey is the top level key of a state file holding the version
+	// of its schema. Files written before it existed are version 0.
+	VersionKey = "schemaVersion"
+
+	// BackupSuffix is appended to the path of a state file to name the
+	// copy of its previous content
+	BackupSuffix = ".bak"
+
+	// CorruptSuffix is appended to the path of a corrupted state file set
+	// aside by Repair
+	CorruptSuffix = ".corrupt"
+
+	// tempInfix names the temporary files written before a rename
+	tempInfix = ".tmp-"
+)
+
+var (
+	ErrCorrupt     = errors.New("corrupted state file")
+	ErrNewerSchema = errors.New("state file written by a newer version of the CLI")
+)
+
+// Kind is a kind of state file, and the version of its schema written
+type Kind struct {
+	Name    string
+	Version int
+}
+
+var (
+	Sidecar        = Kind{Name: "sidecar", Version: 1}
+	ClusterConfig  = Kind{Name: "cluster config", Version: 1}
+	ClustersConfig = Kind{Name: "clusters config", Version: 1}
+	NetworkState   = Kind{Name: "network state", Version: 1}
+)
+
+// WriteFile writes data to path atomically: readers see either the previous
+// content or data, never a part of it
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+tempInfix+"*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	// a no-op once renamed
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// syncDir persists the entries of dir, such as a rename
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		// directories can't be opened for syncing, renames are durable
+		return nil
+	}
+	d, err := os.Open(dir) //nolint:gosec // G304: Opening the directory of a state file
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// Marshal returns v as the indented JSON object of a state file of kind.
+// v must marshal to a JSON object.
+func Marshal(kind Kind, v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("a %s is a JSON object: %w", kind.Name, err)
+	}
+	if obj == nil {
+		obj = map[string]json.RawMessage{}
+	}
+	obj[VersionKey] = json.RawMessage(fmt.Sprint(kind.Version))
+	return json.MarshalIndent(obj, "", "  ")
+}
+
+// WriteJSON atomically writes v to path as a state file of kind, keeping
+// the previous content, when valid, as its backup
+func WriteJSON(path string, kind Kind, v interface{}, perm os.FileMode) error {
+	data, err := Marshal(kind, v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", kind.Name, err)
+	}
+	keepBackup(path)
+	if err := WriteFile(path, data, perm); err != nil {
+		return fmt.Errorf("failed to write %s %s: %w", kind.Name, path, err)
+	}
+	return nil
+}
+
+// keepBackup links the backup of path to its content, unless it is
+// corrupted and would replace a valid backup. It is best effort: a state
+// file is written even when its backup can't be kept.
+func keepBackup(path string) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: Reading a state file
+	if err != nil || !json.Valid(data) {
+		return
+	}
+	backup := path + BackupSuffix
+	_ = os.Remove(backup)
+	if err := os.Link(path, backup); err != nil {
+		_ = os.WriteFile(backup, data, 0o600)
+	}
+}
+
+// ReadJSON reads the state file of kind at path into v, returning the
+// version of its schema. Truncated or malformed files return ErrCorrupt,
+// listing the fields not matching the schema of v, files of a schema newer
+// than kind ErrNewerSchema.
+func ReadJSON(path string, kind Kind, v interface{}) (int, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: Reading a state file
+	if err != nil {
+		return 0, err
+	}
+	version, err := Version(data)
+	if err != nil {
+		return 0, fmt.Errorf("%s %s: %w", kind.Name, path, explain(data, v, err))
+	}
+	if version > kind.Version {
+		return version, fmt.Errorf("%s %s: %w (schema %d, supported up to %d)",
+			kind.Name, path, ErrNewerSchema, version, kind.Version)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return version, fmt.Errorf("%s %s: %w", kind.Name, path, explain(data, v, fmt.Errorf("%w: %w", ErrCorrupt, err)))
+	}
+	return version, nil
+}
+
+// Schema returns the schema of a state file read into v: the schema of
+// its type, and the version of the file
+func Schema(v interface{}) *configschema.Schema {
+	s := configschema.FromValue(v)
+	if s.Properties != nil {
+		s.Properties[VersionKey] = &configschema.Schema{
+			Types:   []string{configschema.TypeInteger},
+			Minimum: new(float64),
+		}
+	}
+	return s
+}
+
+// explain returns ErrCorrupt with the fields of data not matching the
+// schema of v in place of err, the error reading data into v, when there
+// are any
+func explain(data []byte, v interface{}, err error) error {
+	if issues := configschema.Errors(Schema(v).Validate(data)); len(issues) > 0 {
+		return fmt.Errorf("%w: %w", ErrCorrupt, &configschema.ValidationError{Issues: issues})
+	}
+	return err
+}
+
+// Version returns the schema version of the state file data, 0 for a
+// legacy file, or ErrCorrupt when data isn't a JSON object
+func Version(data []byte) (int, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrCorrupt, err)
+	}
+	if obj == nil {
+		return 0, fmt.Errorf("%w: not a JSON object", ErrCorrupt)
+	}
+	raw, ok := obj[VersionKey]
+	if !ok {
+		return 0, nil
+	}
+	var version int
+	if err := json.Unmarshal(raw, &version); err != nil || version < 0 {
+		return 0, fmt.Errorf("%w: invalid %s %s", ErrCorrupt, VersionKey, raw)
+	}
+	return version, nil
+}
+
+// IsAuxiliary reports whether name is a file statefile writes beside a
+// state file: a backup, a set aside corrupted file, or a temporary file
+func IsAuxiliary(name string) bool {
+	base := filepath.Base(name)
+	return strings.HasSuffix(base, BackupSuffix) ||
+		strings.HasSuffix(base, CorruptSuffix) ||
+		strings.Contains(base, tempInfix)
+}
@@ -0,0 +1,135 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statefile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/luxfi/cli/pkg/configschema"
+	"github.com/stretchr/testify/require"
+)
+
+type state struct {
+	Name    string `json:"name"`
+	Running bool   `json:"running"`
+}
+
+func TestWriteReadJSON(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	require.NoError(WriteJSON(path, NetworkState, state{Name: "a"}, 0o644))
+	require.NoFileExists(path + BackupSuffix)
+	require.NoError(WriteJSON(path, NetworkState, state{Name: "b", Running: true}, 0o644))
+
+	var got state
+	version, err := ReadJSON(path, NetworkState, &got)
+	require.NoError(err)
+	require.Equal(NetworkState.Version, version)
+	require.Equal(state{Name: "b", Running: true}, got)
+
+	var backup state
+	_, err = ReadJSON(path+BackupSuffix, NetworkState, &backup)
+	require.NoError(err)
+	require.Equal(state{Name: "a"}, backup)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(err)
+	require.Len(entries, 2)
+
+	_, err = ReadJSON(path, Kind{Name: "old", Version: 0}, &got)
+	require.ErrorIs(err, ErrNewerSchema)
+}
+
+func TestReadJSONCorrupt(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), "state.json")
+	for _, content := range []string{"", `{"name": "trunc`, "[]", `{"schemaVersion": "x"}`} {
+		require.NoError(os.WriteFile(path, []byte(content), 0o600))
+		_, err := ReadJSON(path, NetworkState, &state{})
+		require.ErrorIs(err, ErrCorrupt, content)
+	}
+
+	require.NoError(os.WriteFile(path, []byte(`{"name": "a", "running": "yes", "schemaVersion": 1}`), 0o600))
+	_, err := ReadJSON(path, NetworkState, &state{})
+	require.ErrorIs(err, ErrCorrupt)
+	require.ErrorIs(err, configschema.ErrInvalid)
+	require.ErrorContains(err, `running: expected boolean, got string "yes"`)
+
+	require.NoError(os.WriteFile(path, []byte("{\n  \"name\": \"a\",\n}"), 0o600))
+	_, err = ReadJSON(path, NetworkState, &state{})
+	require.ErrorIs(err, ErrCorrupt)
+	require.ErrorContains(err, "invalid JSON at line 3, column 1")
+	require.ErrorContains(err, "remove the trailing comma")
+}
+
+func TestCheckRepair(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	legacy := filepath.Join(dir, "legacy.json")
+	require.NoError(os.WriteFile(legacy, []byte(`{"name": "legacy"}`), 0o600))
+	finding := Check(File{Path: legacy, Kind: Sidecar})
+	require.Equal(StatusLegacy, finding.Status)
+	action, err := Repair(finding)
+	require.NoError(err)
+	require.Contains(action, "upgraded")
+	require.Equal(StatusOK, Check(File{Path: legacy, Kind: Sidecar}).Status)
+	var got state
+	_, err = ReadJSON(legacy, Sidecar, &got)
+	require.NoError(err)
+	require.Equal("legacy", got.Name)
+
+	// truncated after a valid write: restored from the backup
+	restored := filepath.Join(dir, "restored.json")
+	require.NoError(WriteJSON(restored, Sidecar, state{Name: "good"}, 0o644))
+	require.NoError(WriteJSON(restored, Sidecar, state{Name: "next"}, 0o644))
+	require.NoError(os.WriteFile(restored, []byte(`{"na`), 0o600))
+	finding = Check(File{Path: restored, Kind: Sidecar})
+	require.Equal(StatusCorrupt, finding.Status)
+	action, err = Repair(finding)
+	require.NoError(err)
+	require.Contains(action, "restored")
+	_, err = ReadJSON(restored, Sidecar, &got)
+	require.NoError(err)
+	require.Equal("good", got.Name)
+
+	// no backup: set aside
+	aside := filepath.Join(dir, "aside.json")
+	require.NoError(os.WriteFile(aside, nil, 0o600))
+	action, err = Repair(Check(File{Path: aside, Kind: Sidecar}))
+	require.NoError(err)
+	require.Contains(action, "moved")
+	require.NoFileExists(aside)
+	require.FileExists(aside + CorruptSuffix)
+
+	newer := filepath.Join(dir, "newer.json")
+	require.NoError(os.WriteFile(newer, []byte(`{"schemaVersion": 99}`), 0o600))
+	finding = Check(File{Path: newer, Kind: Sidecar})
+	require.Equal(StatusNewer, finding.Status)
+	action, err = Repair(finding)
+	require.NoError(err)
+	require.Empty(action)
+
+	tmp := filepath.Join(dir, "state.json"+tempInfix+"123")
+	fresh := filepath.Join(dir, "other.json"+tempInfix+"456")
+	require.NoError(os.WriteFile(tmp, []byte("{"), 0o600))
+	require.NoError(os.WriteFile(fresh, []byte("{"), 0o600))
+	old := time.Now().Add(-2 * leftoverAge)
+	require.NoError(os.Chtimes(tmp, old, old))
+	leftovers, err := Leftovers(dir)
+	require.NoError(err)
+	require.Len(leftovers, 1)
+	require.Equal(tmp, leftovers[0].Path)
+	_, err = Repair(leftovers[0])
+	require.NoError(err)
+	require.NoFileExists(tmp)
+
+	require.True(IsAuxiliary(tmp))
+	require.True(IsAuxiliary(restored + BackupSuffix))
+	require.False(IsAuxiliary(restored))
+}
@@ -7,7 +7,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -69,13 +71,14 @@ func (f *StatusFormatter) FormatNetworkStatus(result *StatusResult) {
 			status = "up"
 		}
 
-		fmt.Fprintf(f.writer, "status  %-8s %-8s  grpc=%d  nodes=%d  vms=%d  controller=%s\n",
+		fmt.Fprintf(f.writer, "status  %-8s %-8s  grpc=%d  nodes=%d  vms=%d  controller=%s%s\n",
 			network.Name,
 			status,
 			network.Metadata.GRPCPort,
 			network.Metadata.NodesCount,
 			network.Metadata.VMsCount,
-			network.Metadata.Controller)
+			network.Metadata.Controller,
+			gpuField(network))
 	}
 
 	// Format node details for each network
@@ -234,6 +237,34 @@ func (f *StatusFormatter) FormatNetworkStatus(result *StatusResult) {
 		}
 	}
 
+	// Format the last WebSocket subscription checks for each network
+	for _, network := range result.Networks {
+		if len(network.Subscriptions) > 0 {
+			fmt.Fprintf(f.writer, "\n%s websocket subscriptions\n", network.Name)
+			fmt.Fprintf(f.writer, "chain       topic     ok   events  missed  drop    latency_avg  latency_max  checked\n")
+
+			for _, sub := range network.Subscriptions {
+				ok := "yes"
+				if !sub.OK() {
+					ok = "no"
+				}
+				fmt.Fprintf(f.writer, "%-10s  %-8s  %-3s  %-6d  %-6d  %5.1f%%  %-11s  %-11s  %s\n",
+					sub.Chain,
+					sub.Topic,
+					ok,
+					sub.Events,
+					sub.Missed,
+					sub.DropRate()*100,
+					sub.LatencyAvg.Round(time.Millisecond),
+					sub.LatencyMax.Round(time.Millisecond),
+					sub.Start.Format("2006-01-02 15:04:05"))
+				if sub.Error != "" {
+					fmt.Fprintf(f.writer, "            error: %s\n", sub.Error)
+				}
+			}
+		}
+	}
+
 	// Format L1 EVM chains (Zoo, Hanzo, SPC)
 	if len(result.TrackedEVMs) > 0 {
 		fmt.Fprintf(f.writer, "\nl1 chains (zoo, hanzo, spc)\n")
@@ -344,6 +375,15 @@ func (f *StatusFormatter) FormatNetworkStatus(result *StatusResult) {
 	}
 }
 
+// gpuField returns the count of GPU nodes of network for its status line,
+// empty without GPUs
+func gpuField(network Network) string {
+	if !network.GPU.Available {
+		return ""
+	}
+	return fmt.Sprintf("  gpu=%d/%d", network.GPU.Nodes, len(network.Nodes))
+}
+
 // FormatStatusSummary provides a compact summary format
 func (f *StatusFormatter) FormatStatusSummary(result *StatusResult) {
 	for _, network := range result.Networks {
@@ -352,13 +392,14 @@ func (f *StatusFormatter) FormatStatusSummary(result *StatusResult) {
 			status = "up"
 		}
 
-		fmt.Fprintf(f.writer, "status  %-8s %-8s  grpc=%d  nodes=%d  vms=%d  controller=%s\n",
+		fmt.Fprintf(f.writer, "status  %-8s %-8s  grpc=%d  nodes=%d  vms=%d  controller=%s%s\n",
 			network.Name,
 			status,
 			network.Metadata.GRPCPort,
 			network.Metadata.NodesCount,
 			network.Metadata.VMsCount,
-			network.Metadata.Controller)
+			network.Metadata.Controller,
+			gpuField(network))
 	}
 }
 
@@ -410,6 +451,38 @@ func (f *StatusFormatter) FormatNodeStatus(result *StatusResult) {
 	}
 }
 
+// FormatWatchdogs lists the watchdogs of local bootstrap validators with
+// their open alerts
+func (f *StatusFormatter) FormatWatchdogs(result *StatusResult) {
+	if len(result.Watchdogs) == 0 {
+		return
+	}
+	fmt.Fprintf(f.writer, "\nvalidator watchdogs\n")
+	fmt.Fprintf(f.writer, "name          network   luxd     restarts  synced  balance (nLUX)  alerts\n")
+	for _, w := range result.Watchdogs {
+		luxd := "down"
+		switch {
+		case w.Stopped:
+			luxd = "stopped"
+		case w.Running:
+			luxd = "up"
+		}
+		synced := "no"
+		if w.Bootstrapped && w.ChainBootstrapped {
+			synced = "yes"
+		}
+		balance := "-"
+		if w.Balance != nil {
+			balance = strconv.FormatUint(*w.Balance, 10)
+		}
+		fmt.Fprintf(f.writer, "%-12s  %-8s  %-7s  %-8d  %-6s  %-14s  %d\n",
+			w.Name, w.Network, luxd, w.Restarts, synced, balance, len(w.Alerts))
+		for _, a := range w.Alerts {
+			fmt.Fprintf(f.writer, "  ! %-7s %s (since %s)\n", a.Kind, a.Message, a.Since.Format("2006-01-02 15:04:05"))
+		}
+	}
+}
+
 // FormatJSON outputs the status as JSON
 func (f *StatusFormatter) FormatJSON(result *StatusResult) error {
 	encoder := json.NewEncoder(f.writer)
@@ -423,3 +496,60 @@ func (f *StatusFormatter) FormatYAML(result *StatusResult) error {
 	encoder.SetIndent(2)
 	return encoder.Encode(result)
 }
+
+// FormatWatch renders one refresh of the watch dashboard: chain heights with
+// the blocks produced since the previous refresh, and node peers and latency.
+func (f *StatusFormatter) FormatWatch(result *StatusResult, progress []ChainProgress) {
+	type chainKey struct{ network, chain string }
+	byChain := make(map[chainKey]ChainProgress, len(progress))
+	for _, p := range progress {
+		byChain[chainKey{p.Network, p.Chain}] = p
+	}
+
+	for _, network := range result.Networks {
+		if network.Metadata.Status != "up" {
+			fmt.Fprintf(f.writer, "%s  %s\n\n", network.Name, network.Metadata.Status)
+			continue
+		}
+		fmt.Fprintf(f.writer, "%s  up  nodes=%d\n", network.Name, network.Metadata.NodesCount)
+
+		if len(network.Chains) > 0 {
+			fmt.Fprintf(f.writer, "chain  kind    height      +blocks  rpc_ok  latency  stalled\n")
+			for _, chain := range network.Chains {
+				p := byChain[chainKey{network.Name, chain.Alias}]
+				rpcOK := "no"
+				if chain.RPC_OK {
+					rpcOK = "yes"
+				}
+				stalled := "-"
+				if p.Stalled > 0 {
+					stalled = fmt.Sprintf("%d", p.Stalled)
+				}
+				fmt.Fprintf(f.writer, "%-5s  %-6s  %-10d  %-7d  %-6s  %-7s  %s\n",
+					chain.Alias,
+					chain.Kind,
+					chain.Height,
+					p.Advanced,
+					rpcOK,
+					fmt.Sprintf("%dms", chain.LatencyMS),
+					stalled)
+			}
+		}
+
+		if len(network.Nodes) > 0 {
+			fmt.Fprintf(f.writer, "node  peers  latency  ok\n")
+			for _, node := range network.Nodes {
+				okStr := "no"
+				if node.OK {
+					okStr = "yes"
+				}
+				fmt.Fprintf(f.writer, "%-4s  %-5d  %-7s  %s\n",
+					node.ID,
+					node.PeerCount,
+					fmt.Sprintf("%dms", node.LatencyMS),
+					okStr)
+			}
+		}
+		fmt.Fprintln(f.writer)
+	}
+}
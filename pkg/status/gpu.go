@@ -0,0 +1,281 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package status
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gpuCheck is the health check of luxd describing its GPU
+const gpuCheck = "gpu"
+
+// GPUSummary sums up the GPU acceleration of a network, so that schedulers
+// can tell where to place GPU work from the status JSON
+type GPUSummary struct {
+	// Available is true when a node of the network has a GPU
+	Available bool
+	// AllNodes is true when every node of the network has a GPU
+	AllNodes bool
+	// Healthy is true when the GPUs of all nodes pass their health check
+	Healthy bool
+	// Nodes is the number of nodes with a GPU
+	Nodes int
+	// MaxUtilization is the utilization in percent of the busiest GPU,
+	// nil when no node reports it
+	MaxUtilization *float64
+	// Chains are the chains accelerated on a node, by alias or ID
+	Chains []string
+}
+
+// GPUReport is the GPU acceleration of the nodes of running networks
+type GPUReport struct {
+	Networks  []NetworkGPU
+	Timestamp time.Time
+}
+
+// NetworkGPU is the GPU acceleration of the nodes of a network
+type NetworkGPU struct {
+	Name  string
+	GPU   GPUSummary
+	Nodes []NodeGPU
+}
+
+// NodeGPU is the GPU of a node, Device empty without one
+type NodeGPU struct {
+	ID          string
+	NodeID      string
+	HTTPURL     string
+	Device      string
+	Driver      string
+	Backend     string
+	Utilization *float64 // Percent
+	MemoryUsed  uint64   // Bytes
+	MemoryTotal uint64   // Bytes
+	Healthy     bool
+	Error       string
+	Chains      []string
+}
+
+// NewGPUReport returns the GPUs of the nodes of the networks of result
+// with nodes
+func NewGPUReport(result *StatusResult) GPUReport {
+	report := GPUReport{Networks: []NetworkGPU{}, Timestamp: result.Timestamp}
+	for _, network := range result.Networks {
+		if len(network.Nodes) == 0 {
+			continue
+		}
+		n := NetworkGPU{Name: network.Name, GPU: network.GPU}
+		for _, node := range network.Nodes {
+			n.Nodes = append(n.Nodes, NodeGPU{
+				ID:          node.ID,
+				NodeID:      node.NodeID,
+				HTTPURL:     node.HTTPURL,
+				Device:      node.GPUDevice,
+				Driver:      node.GPUDriverVersion,
+				Backend:     node.GPUBackend,
+				Utilization: node.GPUUtilization,
+				MemoryUsed:  node.GPUMemoryUsed,
+				MemoryTotal: node.GPUMemoryTotal,
+				Healthy:     node.GPUHealthy,
+				Error:       node.GPUError,
+				Chains:      node.GPUChains,
+			})
+		}
+		report.Networks = append(report.Networks, n)
+	}
+	return report
+}
+
+// parseGPUHealth reads the GPU of node from the checks of its health API.
+// The gpu check describes the device, and lists the chains it accelerates;
+// chain checks may also flag themselves with gpuAccelerated.
+func parseGPUHealth(checks map[string]interface{}, node *Node) {
+	chains := map[string]bool{}
+	for name, c := range checks {
+		check, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		msg, _ := check["message"].(map[string]interface{})
+		if name != gpuCheck {
+			if accelerated, _ := msg["gpuAccelerated"].(bool); accelerated {
+				chains[name] = true
+			}
+			continue
+		}
+
+		if device, ok := msg["device"].(string); ok && device != "" {
+			node.GPUDevice = device
+			node.GPUAccelerated = true
+		}
+		if driver, ok := msg["driver"].(string); ok {
+			node.GPUDriverVersion = driver
+		}
+		if backend, ok := msg["backend"].(string); ok {
+			node.GPUBackend = backend
+		}
+		if utilization, ok := parsePercent(msg["utilization"]); ok {
+			node.GPUUtilization = &utilization
+		}
+		node.GPUMemoryUsed = parseBytes(msg["memoryUsed"])
+		node.GPUMemoryTotal = parseBytes(msg["memoryTotal"])
+		if list, ok := msg["chains"].([]interface{}); ok {
+			for _, chain := range list {
+				if chain, ok := chain.(string); ok && chain != "" {
+					chains[chain] = true
+				}
+			}
+		}
+		if errMsg, ok := check["error"].(string); ok && errMsg != "" {
+			node.GPUError = errMsg
+		}
+	}
+	node.GPUHealthy = node.GPUAccelerated && node.GPUError == ""
+	node.GPUChains = sortedKeys(chains)
+}
+
+// parsePercent reads a utilization reported as a number, or a string such
+// as "37.5%"
+func parsePercent(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(v, "%")), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func parseBytes(v interface{}) uint64 {
+	if f, ok := v.(float64); ok && f > 0 {
+		return uint64(f)
+	}
+	return 0
+}
+
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SummarizeGPU sums up the GPUs of the nodes of a network
+func SummarizeGPU(nodes []Node) GPUSummary {
+	summary := GPUSummary{Healthy: true}
+	chains := map[string]bool{}
+	for _, node := range nodes {
+		if !node.GPUAccelerated {
+			continue
+		}
+		summary.Nodes++
+		if !node.GPUHealthy {
+			summary.Healthy = false
+		}
+		if u := node.GPUUtilization; u != nil && (summary.MaxUtilization == nil || *u > *summary.MaxUtilization) {
+			summary.MaxUtilization = u
+		}
+		for _, chain := range node.GPUChains {
+			chains[chain] = true
+		}
+	}
+	summary.Available = summary.Nodes > 0
+	summary.AllNodes = summary.Available && summary.Nodes == len(nodes)
+	summary.Healthy = summary.Healthy && summary.Available
+	summary.Chains = sortedKeys(chains)
+	return summary
+}
+
+// markGPUChains flags the chains of a network accelerated on a node, named
+// by alias or blockchain ID
+func markGPUChains(chains []ChainStatus, accelerated []string) {
+	for i := range chains {
+		for _, name := range accelerated {
+			if strings.EqualFold(name, chains[i].Alias) ||
+				(chains[i].BlockchainID != "" && name == chains[i].BlockchainID) {
+				chains[i].GPUAccelerated = true
+				break
+			}
+		}
+	}
+}
+
+// FormatGPU lists the GPU of each node, and the chains they accelerate
+func (f *StatusFormatter) FormatGPU(result *StatusResult) {
+	for _, network := range result.Networks {
+		if len(network.Nodes) == 0 {
+			continue
+		}
+		gpu := network.GPU
+		fmt.Fprintf(f.writer, "\n%s gpu  nodes=%d/%d  healthy=%s  chains=%s\n",
+			network.Name, gpu.Nodes, len(network.Nodes), yesNo(gpu.Healthy), orDash(strings.Join(gpu.Chains, ",")))
+		fmt.Fprintf(f.writer, "node  device                    driver        backend  util    memory           chains           ok\n")
+		for _, node := range network.Nodes {
+			if !node.GPUAccelerated {
+				fmt.Fprintf(f.writer, "%-4s  %-24s  %-12s  %-7s  %-6s  %-15s  %-15s  %s\n",
+					node.ID, "-", "-", "-", "-", "-", "-", "-")
+				continue
+			}
+			ok := yesNo(node.GPUHealthy)
+			if node.GPUError != "" {
+				ok += " (" + node.GPUError + ")"
+			}
+			fmt.Fprintf(f.writer, "%-4s  %-24s  %-12s  %-7s  %-6s  %-15s  %-15s  %s\n",
+				node.ID,
+				truncate(node.GPUDevice, 24),
+				orDash(node.GPUDriverVersion),
+				orDash(node.GPUBackend),
+				formatUtilization(node.GPUUtilization),
+				formatGPUMemory(node.GPUMemoryUsed, node.GPUMemoryTotal),
+				orDash(strings.Join(node.GPUChains, ",")),
+				ok)
+		}
+	}
+}
+
+func formatUtilization(u *float64) string {
+	if u == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", *u)
+}
+
+func formatGPUMemory(used, total uint64) string {
+	if total == 0 {
+		return "-"
+	}
+	const gib = 1 << 30
+	return fmt.Sprintf("%.1f/%.1fGiB", float64(used)/gib, float64(total)/gib)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package status
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const gpuHealth = `{
+  "healthy": true,
+  "checks": {
+    "gpu": {
+      "message": {
+        "device": "NVIDIA H100 80GB HBM3",
+        "driver": "550.54.15",
+        "backend": "cuda",
+        "utilization": "37.5%",
+        "memoryUsed": 21474836480,
+        "memoryTotal": 85899345920,
+        "chains": ["C"]
+      }
+    },
+    "zoo": {"message": {"gpuAccelerated": true}},
+    "P": {"message": {"engine": {}}},
+    "network": {"message": {"connectedPeers": 4}}
+  }
+}`
+
+func parseHealth(t *testing.T, body string) Node {
+	var r map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(body), &r))
+	node := Node{ID: "1"}
+	parseGPUHealth(r["checks"].(map[string]interface{}), &node)
+	return node
+}
+
+func TestParseGPUHealth(t *testing.T) {
+	require := require.New(t)
+	node := parseHealth(t, gpuHealth)
+	require.True(node.GPUAccelerated)
+	require.True(node.GPUHealthy)
+	require.Equal("NVIDIA H100 80GB HBM3", node.GPUDevice)
+	require.Equal("550.54.15", node.GPUDriverVersion)
+	require.Equal("cuda", node.GPUBackend)
+	require.Equal(37.5, *node.GPUUtilization)
+	require.Equal(uint64(20<<30), node.GPUMemoryUsed)
+	require.Equal(uint64(80<<30), node.GPUMemoryTotal)
+	require.Equal([]string{"C", "zoo"}, node.GPUChains)
+
+	failing := parseHealth(t, `{"checks": {"gpu": {"message": {"device": "Apple M3 Max", "utilization": 99}, "error": "GPU memory exhausted"}}}`)
+	require.True(failing.GPUAccelerated)
+	require.False(failing.GPUHealthy)
+	require.Equal("GPU memory exhausted", failing.GPUError)
+	require.Equal(99.0, *failing.GPUUtilization)
+
+	none := parseHealth(t, `{"checks": {"network": {"message": {}}}}`)
+	require.False(none.GPUAccelerated)
+	require.False(none.GPUHealthy)
+	require.Nil(none.GPUUtilization)
+	require.Empty(none.GPUChains)
+}
+
+func TestSummarizeGPU(t *testing.T) {
+	require := require.New(t)
+	gpuNode := parseHealth(t, gpuHealth)
+	busy := 90.0
+	otherNode := Node{ID: "2", GPUAccelerated: true, GPUHealthy: true, GPUUtilization: &busy, GPUChains: []string{"hanzo"}}
+	plainNode := Node{ID: "3"}
+
+	summary := SummarizeGPU([]Node{gpuNode, otherNode, plainNode})
+	require.True(summary.Available)
+	require.False(summary.AllNodes)
+	require.True(summary.Healthy)
+	require.Equal(2, summary.Nodes)
+	require.Equal(90.0, *summary.MaxUtilization)
+	require.Equal([]string{"C", "hanzo", "zoo"}, summary.Chains)
+
+	summary = SummarizeGPU([]Node{gpuNode, {ID: "2", GPUAccelerated: true, GPUError: "driver mismatch"}})
+	require.True(summary.AllNodes)
+	require.False(summary.Healthy)
+
+	summary = SummarizeGPU([]Node{plainNode})
+	require.Equal(GPUSummary{}, summary)
+
+	chains := []ChainStatus{{Alias: "c"}, {Alias: "p"}, {Alias: "zoo"}}
+	markGPUChains(chains, []string{"C", "zoo"})
+	require.True(chains[0].GPUAccelerated)
+	require.False(chains[1].GPUAccelerated)
+	require.True(chains[2].GPUAccelerated)
+}
+
+func TestFormatGPU(t *testing.T) {
+	require := require.New(t)
+	nodes := []Node{parseHealth(t, gpuHealth), {ID: "2"}}
+	result := &StatusResult{Networks: []Network{
+		{Name: "devnet", Nodes: nodes, GPU: SummarizeGPU(nodes)},
+		{Name: "mainnet"},
+	}}
+
+	var out bytes.Buffer
+	NewStatusFormatter(&out).FormatGPU(result)
+	require.Contains(out.String(), "devnet gpu  nodes=1/2  healthy=yes  chains=C,zoo")
+	require.Contains(out.String(), "1     NVIDIA H100 80GB HBM3     550.54.15     cuda     38%     20.0/80.0GiB     C,zoo            yes")
+	require.NotContains(out.String(), "mainnet")
+
+	report := NewGPUReport(result)
+	require.Len(report.Networks, 1)
+	require.Equal("NVIDIA H100 80GB HBM3", report.Networks[0].Nodes[0].Device)
+	require.Empty(report.Networks[0].Nodes[1].Device)
+}
@@ -0,0 +1,208 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package status
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	networkUpDesc = prometheus.NewDesc(
+		"lux_network_up", "Whether the network is running (1) or not (0).",
+		[]string{"network"}, nil)
+	chainHeightDesc = prometheus.NewDesc(
+		"lux_chain_height", "Latest accepted block height of the chain.",
+		[]string{"network", "chain", "kind"}, nil)
+	chainRPCUpDesc = prometheus.NewDesc(
+		"lux_chain_rpc_up", "Whether the chain RPC endpoint answered the last probe.",
+		[]string{"network", "chain"}, nil)
+	chainLatencyDesc = prometheus.NewDesc(
+		"lux_chain_rpc_latency_seconds", "Latency of the last chain RPC probe.",
+		[]string{"network", "chain"}, nil)
+	chainWSUpDesc = prometheus.NewDesc(
+		"lux_chain_ws_subscription_up", "Whether the last WebSocket subscription check kept the subscription up without missing events.",
+		[]string{"network", "chain", "topic"}, nil)
+	chainWSDropDesc = prometheus.NewDesc(
+		"lux_chain_ws_subscription_drop_ratio", "Share of the events missed by the last WebSocket subscription check.",
+		[]string{"network", "chain", "topic"}, nil)
+	chainWSLatencyDesc = prometheus.NewDesc(
+		"lux_chain_ws_subscription_latency_seconds", "Average delay from block time to delivery in the last WebSocket subscription check.",
+		[]string{"network", "chain", "topic"}, nil)
+	nodeUpDesc = prometheus.NewDesc(
+		"lux_node_up", "Whether the node answered the last probe.",
+		[]string{"network", "node", "node_id"}, nil)
+	nodePeersDesc = prometheus.NewDesc(
+		"lux_node_peers", "Number of peers the node is connected to.",
+		[]string{"network", "node", "node_id"}, nil)
+	nodeUptimeDesc = prometheus.NewDesc(
+		"lux_node_uptime_ratio", "Uptime of the node as observed by its peers, between 0 and 1.",
+		[]string{"network", "node", "node_id"}, nil)
+	nodeLatencyDesc = prometheus.NewDesc(
+		"lux_node_latency_seconds", "Latency of the last node API probe.",
+		[]string{"network", "node", "node_id"}, nil)
+	validatorBalanceDesc = prometheus.NewDesc(
+		"lux_validator_balance_lux", "Balance of the validator account in LUX.",
+		[]string{"network", "node_id", "chain"}, nil)
+	trackedEVMHeightDesc = prometheus.NewDesc(
+		"lux_tracked_evm_height", "Latest block height of a tracked EVM chain.",
+		[]string{"network", "name"}, nil)
+	probeDurationDesc = prometheus.NewDesc(
+		"lux_status_probe_duration_seconds", "Time taken to probe all networks.",
+		nil, nil)
+	probeSuccessDesc = prometheus.NewDesc(
+		"lux_status_probe_success", "Whether the last status probe succeeded.",
+		nil, nil)
+)
+
+// Collector exposes the results of StatusService.GetStatus as Prometheus
+// metrics. Networks are probed on every scrape, so the values are as fresh as
+// the scrape interval.
+type Collector struct {
+	service *StatusService
+	timeout time.Duration
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector creates a collector probing with service. Each probe is
+// bounded by timeout.
+func NewCollector(service *StatusService, timeout time.Duration) *Collector {
+	return &Collector{
+		service: service,
+		timeout: timeout,
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		networkUpDesc, chainHeightDesc, chainRPCUpDesc, chainLatencyDesc,
+		chainWSUpDesc, chainWSDropDesc, chainWSLatencyDesc,
+		nodeUpDesc, nodePeersDesc, nodeUptimeDesc, nodeLatencyDesc,
+		validatorBalanceDesc, trackedEVMHeightDesc, probeDurationDesc, probeSuccessDesc,
+	} {
+		ch <- desc
+	}
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	result, err := c.service.GetStatus(ctx)
+	ch <- prometheus.MustNewConstMetric(probeDurationDesc, prometheus.GaugeValue, time.Since(start).Seconds())
+	if err != nil {
+		// No network running is a valid state, not a failed probe
+		ch <- prometheus.MustNewConstMetric(probeSuccessDesc, prometheus.GaugeValue, boolGauge(errors.Is(err, ErrNoNetwork)))
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(probeSuccessDesc, prometheus.GaugeValue, 1)
+	collectResult(ch, result)
+}
+
+// collectResult converts a status result into metrics
+func collectResult(ch chan<- prometheus.Metric, result *StatusResult) {
+	for _, network := range result.Networks {
+		up := network.Metadata.Status == "up"
+		ch <- prometheus.MustNewConstMetric(networkUpDesc, prometheus.GaugeValue, boolGauge(up), network.Name)
+		if !up {
+			continue
+		}
+
+		seen := map[string]bool{}
+		for _, chain := range network.Chains {
+			// Prometheus rejects duplicate series, keep the first chain per alias
+			if seen[chain.Alias] {
+				continue
+			}
+			seen[chain.Alias] = true
+			ch <- prometheus.MustNewConstMetric(chainRPCUpDesc, prometheus.GaugeValue, boolGauge(chain.RPC_OK), network.Name, chain.Alias)
+			ch <- prometheus.MustNewConstMetric(chainLatencyDesc, prometheus.GaugeValue, msToSeconds(chain.LatencyMS), network.Name, chain.Alias)
+			if chain.RPC_OK {
+				ch <- prometheus.MustNewConstMetric(chainHeightDesc, prometheus.GaugeValue, float64(chain.Height), network.Name, chain.Alias, chain.Kind)
+			}
+		}
+
+		for _, sub := range network.Subscriptions {
+			labels := []string{network.Name, sub.Chain, sub.Topic}
+			ch <- prometheus.MustNewConstMetric(chainWSUpDesc, prometheus.GaugeValue, boolGauge(sub.OK()), labels...)
+			ch <- prometheus.MustNewConstMetric(chainWSDropDesc, prometheus.GaugeValue, sub.DropRate(), labels...)
+			ch <- prometheus.MustNewConstMetric(chainWSLatencyDesc, prometheus.GaugeValue, sub.LatencyAvg.Seconds(), labels...)
+		}
+
+		for _, node := range network.Nodes {
+			labels := []string{network.Name, node.ID, node.NodeID}
+			ch <- prometheus.MustNewConstMetric(nodeUpDesc, prometheus.GaugeValue, boolGauge(node.OK), labels...)
+			ch <- prometheus.MustNewConstMetric(nodeLatencyDesc, prometheus.GaugeValue, msToSeconds(node.LatencyMS), labels...)
+			if !node.OK {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(nodePeersDesc, prometheus.GaugeValue, float64(node.PeerCount), labels...)
+			if uptime, ok := parseUptimeRatio(node.Uptime); ok {
+				ch <- prometheus.MustNewConstMetric(nodeUptimeDesc, prometheus.GaugeValue, uptime, labels...)
+			}
+		}
+
+		for _, v := range network.Validators {
+			ch <- prometheus.MustNewConstMetric(validatorBalanceDesc, prometheus.GaugeValue, nLUXToLUX(v.PChainBalance), network.Name, v.NodeID, "p")
+			ch <- prometheus.MustNewConstMetric(validatorBalanceDesc, prometheus.GaugeValue, nLUXToLUX(v.XChainBalance), network.Name, v.NodeID, "x")
+			if balance, ok := weiHexToLUX(v.CChainBalance); ok {
+				ch <- prometheus.MustNewConstMetric(validatorBalanceDesc, prometheus.GaugeValue, balance, network.Name, v.NodeID, "c")
+			}
+		}
+	}
+
+	for _, evm := range result.TrackedEVMs {
+		if evm.Height > 0 {
+			ch <- prometheus.MustNewConstMetric(trackedEVMHeightDesc, prometheus.GaugeValue, float64(evm.Height), evm.Network, evm.Name)
+		}
+	}
+}
+
+func boolGauge(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func msToSeconds(ms int) float64 {
+	return float64(ms) / 1000
+}
+
+// parseUptimeRatio parses the "99.5%" uptime reported by probeNode
+func parseUptimeRatio(uptime string) (float64, bool) {
+	pct, err := strconv.ParseFloat(strings.TrimSuffix(uptime, "%"), 64)
+	if err != nil {
+		return 0, false
+	}
+	return pct / 100, true
+}
+
+func nLUXToLUX(nLUX uint64) float64 {
+	return float64(nLUX) / 1e9
+}
+
+// weiHexToLUX converts a hex wei balance to LUX
+func weiHexToLUX(weiHex string) (float64, bool) {
+	weiHex = strings.TrimPrefix(weiHex, "0x")
+	if weiHex == "" {
+		return 0, false
+	}
+	wei, ok := new(big.Int).SetString(weiHex, 16)
+	if !ok {
+		return 0, false
+	}
+	lux, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e18)).Float64()
+	return lux, true
+}
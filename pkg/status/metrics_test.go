@@ -0,0 +1,85 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package status
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/luxfi/cli/pkg/wsprobe"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// resultCollector collects a fixed status result
+type resultCollector struct {
+	result *StatusResult
+}
+
+func (c resultCollector) Describe(ch chan<- *prometheus.Desc) {
+	(&Collector{}).Describe(ch)
+}
+
+func (c resultCollector) Collect(ch chan<- prometheus.Metric) {
+	collectResult(ch, c.result)
+}
+
+func TestCollectResult(t *testing.T) {
+	result := &StatusResult{
+		Networks: []Network{
+			{
+				Name:     "devnet",
+				Metadata: NetworkMetadata{Status: "up"},
+				Chains: []ChainStatus{
+					{Alias: "c", Kind: "evm", Height: 42, RPC_OK: true, LatencyMS: 15},
+					{Alias: "p", Kind: "pchain", RPC_OK: false},
+				},
+				Nodes: []Node{
+					{ID: "1", NodeID: "NodeID-A", OK: true, PeerCount: 4, Uptime: "99.5%"},
+					{ID: "2", NodeID: "NodeID-B", OK: false},
+				},
+				Validators: []ValidatorAccount{
+					{NodeID: "NodeID-A", PChainBalance: 2_500_000_000, CChainBalance: "0xde0b6b3a7640000"},
+				},
+				Subscriptions: []wsprobe.Result{
+					{Network: "devnet", Chain: "c", Topic: wsprobe.TopicNewHeads, Events: 3, Missed: 1},
+				},
+			},
+			{Name: "mainnet", Metadata: NetworkMetadata{Status: "stopped"}},
+		},
+	}
+
+	expected := `
+# HELP lux_chain_height Latest accepted block height of the chain.
+# TYPE lux_chain_height gauge
+lux_chain_height{chain="c",kind="evm",network="devnet"} 42
+# HELP lux_chain_ws_subscription_drop_ratio Share of the events missed by the last WebSocket subscription check.
+# TYPE lux_chain_ws_subscription_drop_ratio gauge
+lux_chain_ws_subscription_drop_ratio{chain="c",network="devnet",topic="newHeads"} 0.25
+# HELP lux_chain_ws_subscription_up Whether the last WebSocket subscription check kept the subscription up without missing events.
+# TYPE lux_chain_ws_subscription_up gauge
+lux_chain_ws_subscription_up{chain="c",network="devnet",topic="newHeads"} 0
+# HELP lux_network_up Whether the network is running (1) or not (0).
+# TYPE lux_network_up gauge
+lux_network_up{network="devnet"} 1
+lux_network_up{network="mainnet"} 0
+# HELP lux_node_peers Number of peers the node is connected to.
+# TYPE lux_node_peers gauge
+lux_node_peers{network="devnet",node="1",node_id="NodeID-A"} 4
+# HELP lux_node_uptime_ratio Uptime of the node as observed by its peers, between 0 and 1.
+# TYPE lux_node_uptime_ratio gauge
+lux_node_uptime_ratio{network="devnet",node="1",node_id="NodeID-A"} 0.995
+# HELP lux_validator_balance_lux Balance of the validator account in LUX.
+# TYPE lux_validator_balance_lux gauge
+lux_validator_balance_lux{chain="c",network="devnet",node_id="NodeID-A"} 1
+lux_validator_balance_lux{chain="p",network="devnet",node_id="NodeID-A"} 2.5
+lux_validator_balance_lux{chain="x",network="devnet",node_id="NodeID-A"} 0
+`
+	require.NoError(t, testutil.CollectAndCompare(
+		resultCollector{result},
+		strings.NewReader(expected),
+		"lux_chain_height", "lux_chain_ws_subscription_drop_ratio", "lux_chain_ws_subscription_up", "lux_network_up", "lux_node_peers", "lux_node_uptime_ratio", "lux_validator_balance_lux",
+	))
+}
@@ -5,6 +5,9 @@ package status
 
 import (
 	"time"
+
+	"github.com/luxfi/cli/pkg/watchdog"
+	"github.com/luxfi/cli/pkg/wsprobe"
 )
 
 // Network represents a Lux network (mainnet, testnet, devnet, custom)
@@ -16,6 +19,8 @@ type Network struct {
 	Metadata      NetworkMetadata
 	Validators    []ValidatorAccount // Validator accounts with addresses and balances
 	ActiveAccount *ActiveAccount     // Currently active account for operations
+	Subscriptions []wsprobe.Result   // Last WebSocket subscription checks
+	GPU           GPUSummary         // GPU acceleration of the nodes
 }
 
 // NetworkMetadata contains additional network information
@@ -45,6 +50,13 @@ type Node struct {
 	GPUAccelerated   bool
 	GPUDriverVersion string
 	GPUDevice        string
+	GPUBackend       string   // "cuda", "metal"
+	GPUUtilization   *float64 // Percent, nil when not reported
+	GPUMemoryUsed    uint64   // Bytes
+	GPUMemoryTotal   uint64   // Bytes
+	GPUHealthy       bool
+	GPUError         string
+	GPUChains        []string // Chains accelerated on the node
 	PChainAddress    string
 	XChainAddress    string
 	CChainAddress    string
@@ -96,6 +108,8 @@ type ChainStatus struct {
 	PluginName    string // For custom chains
 	BlockchainID  string // For custom chains
 	VMID          string // For custom chains
+	// GPUAccelerated is true when a node runs the chain on its GPU
+	GPUAccelerated bool
 }
 
 // EndpointStatus represents the status of an RPC endpoint
@@ -135,6 +149,7 @@ type EVMStatus struct {
 type StatusResult struct {
 	Networks    []Network
 	TrackedEVMs []EVMStatus
+	Watchdogs   []watchdog.State // Supervised local bootstrap validators
 	Timestamp   time.Time
 	DurationMS  int
 }
@@ -18,6 +18,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/wsprobe"
 	"github.com/luxfi/constants"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/semaphore"
@@ -103,6 +105,9 @@ func (s *StatusService) GetStatus(ctx context.Context) (*StatusResult, error) {
 	trackedEVMs := s.probeTrackedEVMs(ctx, result.Networks)
 	result.TrackedEVMs = trackedEVMs
 
+	// Attach the last WebSocket subscription checks (lux rpc subscribe)
+	s.attachSubscriptions(result.Networks)
+
 	// Calculate duration
 	durationMS := int(time.Since(startTime).Milliseconds())
 	result.Timestamp = time.Now()
@@ -111,6 +116,38 @@ func (s *StatusService) GetStatus(ctx context.Context) (*StatusResult, error) {
 	return &result, nil
 }
 
+// attachSubscriptions adds to each network the last results of the
+// WebSocket subscription checks run on it
+func (s *StatusService) attachSubscriptions(networks []Network) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return
+	}
+	results, err := wsprobe.Load(filepath.Join(home, ".lux", wsprobe.ResultsFile))
+	if err != nil {
+		return
+	}
+	for i := range networks {
+		networks[i].Subscriptions = wsprobe.ForNetwork(results, networks[i].Name)
+	}
+}
+
+// ProbeEndpoints probes the nodes serving the given API base URLs as one
+// network, e.g. the nodes of a remote cluster that is not tracked locally.
+func (s *StatusService) ProbeEndpoints(ctx context.Context, name string, baseURLs []string) (*Network, error) {
+	if len(baseURLs) == 0 {
+		return nil, ErrNoNetwork
+	}
+	network := Network{
+		Name:     name,
+		Metadata: NetworkMetadata{Status: "up", NodesCount: len(baseURLs)},
+	}
+	for i, url := range baseURLs {
+		network.Nodes = append(network.Nodes, Node{ID: fmt.Sprintf("%d", i+1), HTTPURL: url})
+	}
+	return s.probeNetwork(ctx, network)
+}
+
 // getL1ChainConfig returns the L1 chain configurations for Zoo, Hanzo, SPC
 func (s *StatusService) getL1ChainConfig() []TrackedEVM {
 	return []TrackedEVM{
@@ -182,9 +219,20 @@ func (s *StatusService) probeTrackedEVMs(ctx context.Context, networks []Network
 	return results
 }
 
-// getBlockchainsFromNode retrieves the list of blockchains from a node
+// nodeBlockchainsCache holds platform.getBlockchains results per node URL
+var nodeBlockchainsCache = utils.NewTTLCache[string, []map[string]interface{}](utils.PChainCacheTTL)
+
+// getBlockchainsFromNode retrieves the list of blockchains from a node.
+// Results are cached briefly since one status run asks the same node repeatedly,
+// so the returned maps are shared and must not be modified.
 func (s *StatusService) getBlockchainsFromNode(ctx context.Context, baseURL string) ([]map[string]interface{}, error) {
-	client := &http.Client{Timeout: 3 * time.Second}
+	return nodeBlockchainsCache.GetOrLoad(baseURL, func() ([]map[string]interface{}, error) {
+		return s.fetchBlockchainsFromNode(ctx, baseURL)
+	})
+}
+
+func (s *StatusService) fetchBlockchainsFromNode(ctx context.Context, baseURL string) ([]map[string]interface{}, error) {
+	client := utils.DefaultRPCClient().WithTimeout(3 * time.Second)
 
 	requestURL := fmt.Sprintf("%s/ext/bc/P", baseURL)
 	requestBody := map[string]interface{}{
@@ -312,6 +360,8 @@ func (s *StatusService) probeNetwork(ctx context.Context, network Network) (*Net
 		return nil, fmt.Errorf("failed to probe chains: %w", err)
 	}
 	network.Chains = probedChains
+	network.GPU = SummarizeGPU(network.Nodes)
+	markGPUChains(network.Chains, network.GPU.Chains)
 
 	// Query balances for validators if we have any
 	if len(network.Validators) > 0 && len(network.Nodes) > 0 {
@@ -383,7 +433,7 @@ func (s *StatusService) probeNode(ctx context.Context, node Node) (*Node, error)
 	}
 	vJson, _ := json.Marshal(versionBody)
 
-	client := &http.Client{Timeout: 2 * time.Second}
+	client := utils.DefaultRPCClient().WithTimeout(2 * time.Second).WithPolicy(utils.ProbeRetryPolicy())
 	req, _ := http.NewRequestWithContext(ctx, "POST", versionURL, bytes.NewBuffer(vJson))
 	req.Header.Set("Content-Type", "application/json")
 
@@ -468,26 +518,15 @@ func (s *StatusService) probeNode(ctx context.Context, node Node) (*Node, error)
 		}
 	}
 
-	// 5. Check GPU acceleration (via health check or custom endpoint)
+	// 5. Check GPU acceleration (via health check)
 	healthURL := fmt.Sprintf("%s/ext/health", node.HTTPURL)
 	healthReq, _ := http.NewRequestWithContext(ctx, "GET", healthURL, nil)
 	if healthResp, err := client.Do(healthReq); err == nil {
 		defer healthResp.Body.Close()
 		var r map[string]interface{}
 		if err := json.NewDecoder(healthResp.Body).Decode(&r); err == nil {
-			// Check for GPU-related info in health response
 			if checks, ok := r["checks"].(map[string]interface{}); ok {
-				if gpuCheck, ok := checks["gpu"].(map[string]interface{}); ok {
-					if msg, ok := gpuCheck["message"].(map[string]interface{}); ok {
-						if device, ok := msg["device"].(string); ok {
-							node.GPUDevice = device
-							node.GPUAccelerated = true
-						}
-						if driver, ok := msg["driver"].(string); ok {
-							node.GPUDriverVersion = driver
-						}
-					}
-				}
+				parseGPUHealth(checks, &node)
 			}
 		}
 	}
@@ -918,9 +957,15 @@ func (s *StatusService) getChainEndpoints(network Network) ([]EndpointStatus, er
 }
 
 // getAllNativeChainEndpoints returns endpoints for all native Lux chains
+func (s *StatusService) getAllNativeChainEndpoints(baseURL string) []EndpointStatus {
+	return NativeChainEndpoints(baseURL)
+}
+
+// NativeChainEndpoints returns the endpoints of all native Lux chains served
+// at baseURL.
 // P-chain and X-chain use JSON-RPC directly (no /rpc suffix)
 // EVM chains (C, Q, A, B, T, Z, G, K, D) use /rpc suffix
-func (s *StatusService) getAllNativeChainEndpoints(baseURL string) []EndpointStatus {
+func NativeChainEndpoints(baseURL string) []EndpointStatus {
 	return []EndpointStatus{
 		{ChainAlias: "p", URL: fmt.Sprintf("%s/ext/bc/P", baseURL)},     // Platform chain (JSON-RPC)
 		{ChainAlias: "x", URL: fmt.Sprintf("%s/ext/bc/X", baseURL)},     // Exchange chain (JSON-RPC)
@@ -939,9 +984,7 @@ func (s *StatusService) getAllNativeChainEndpoints(baseURL string) []EndpointSta
 // discoverChainEndpointsFromNode attempts to discover all available chain endpoints
 func (s *StatusService) discoverChainEndpointsFromNode(baseURL string) ([]EndpointStatus, error) {
 	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 3 * time.Second,
-	}
+	client := utils.DefaultRPCClient().WithTimeout(3 * time.Second)
 
 	// Build the request URL for platform.getBlockchains
 	requestURL := fmt.Sprintf("%s/ext/bc/P/rpc", baseURL)
@@ -1052,7 +1095,7 @@ func (s *StatusService) mapBlockchainIDToAlias(blockchainID string) string {
 
 // QueryPChainBalance queries the P-chain balance for an address
 func (s *StatusService) QueryPChainBalance(ctx context.Context, baseURL, address string) (uint64, error) {
-	client := &http.Client{Timeout: 3 * time.Second}
+	client := utils.DefaultRPCClient().WithTimeout(3 * time.Second)
 
 	requestURL := fmt.Sprintf("%s/ext/bc/P", baseURL)
 	requestBody := map[string]interface{}{
@@ -1101,7 +1144,7 @@ func (s *StatusService) QueryPChainBalance(ctx context.Context, baseURL, address
 
 // QueryXChainBalance queries the X-chain balance for an address
 func (s *StatusService) QueryXChainBalance(ctx context.Context, baseURL, address string) (uint64, error) {
-	client := &http.Client{Timeout: 3 * time.Second}
+	client := utils.DefaultRPCClient().WithTimeout(3 * time.Second)
 
 	requestURL := fmt.Sprintf("%s/ext/bc/X", baseURL)
 	requestBody := map[string]interface{}{
@@ -1151,7 +1194,7 @@ func (s *StatusService) QueryXChainBalance(ctx context.Context, baseURL, address
 
 // QueryCChainBalance queries the C-chain balance for an address (0x format)
 func (s *StatusService) QueryCChainBalance(ctx context.Context, baseURL, address string) (string, error) {
-	client := &http.Client{Timeout: 3 * time.Second}
+	client := utils.DefaultRPCClient().WithTimeout(3 * time.Second)
 
 	requestURL := fmt.Sprintf("%s/ext/bc/C/rpc", baseURL)
 	requestBody := map[string]interface{}{
@@ -0,0 +1,79 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package status
+
+// ChainProgress is the height progress of one chain between two probes
+type ChainProgress struct {
+	Network string
+	Chain   string
+	Height  uint64
+	// Advanced is the number of blocks produced since the previous probe
+	Advanced uint64
+	// Stalled is the number of consecutive probes without a new block
+	Stalled int
+}
+
+// HeightTracker follows chain heights across successive status probes and
+// counts how long each chain has gone without producing a block.
+type HeightTracker struct {
+	heights map[string]uint64
+	stalled map[string]int
+}
+
+// NewHeightTracker creates a tracker with no history
+func NewHeightTracker() *HeightTracker {
+	return &HeightTracker{
+		heights: map[string]uint64{},
+		stalled: map[string]int{},
+	}
+}
+
+// Observe records the heights in result and returns the progress of every
+// chain of every running network. A chain that cannot be reached counts as
+// not advancing.
+func (t *HeightTracker) Observe(result *StatusResult) []ChainProgress {
+	var progress []ChainProgress
+	for _, network := range result.Networks {
+		if network.Metadata.Status != "up" {
+			continue
+		}
+		for _, chain := range network.Chains {
+			key := network.Name + "/" + chain.Alias
+			p := ChainProgress{
+				Network: network.Name,
+				Chain:   chain.Alias,
+				Height:  chain.Height,
+			}
+			last, seen := t.heights[key]
+			switch {
+			case !seen:
+			case chain.RPC_OK && chain.Height > last:
+				p.Advanced = chain.Height - last
+				t.stalled[key] = 0
+			default:
+				t.stalled[key]++
+			}
+			if chain.RPC_OK || !seen {
+				t.heights[key] = max(chain.Height, last)
+			}
+			p.Stalled = t.stalled[key]
+			progress = append(progress, p)
+		}
+	}
+	return progress
+}
+
+// Stalled returns the chains that have not advanced for at least limit probes
+func Stalled(progress []ChainProgress, limit int) []ChainProgress {
+	if limit <= 0 {
+		return nil
+	}
+	var stalled []ChainProgress
+	for _, p := range progress {
+		if p.Stalled >= limit {
+			stalled = append(stalled, p)
+		}
+	}
+	return stalled
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package status
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func watchResult(cHeight uint64, cOK bool, pHeight uint64) *StatusResult {
+	return &StatusResult{
+		Networks: []Network{
+			{
+				Name:     "devnet",
+				Metadata: NetworkMetadata{Status: "up"},
+				Chains: []ChainStatus{
+					{Alias: "c", Height: cHeight, RPC_OK: cOK},
+					{Alias: "p", Height: pHeight, RPC_OK: true},
+				},
+			},
+			{Name: "mainnet", Metadata: NetworkMetadata{Status: "stopped"}},
+		},
+	}
+}
+
+func TestHeightTracker(t *testing.T) {
+	require := require.New(t)
+	tracker := NewHeightTracker()
+
+	progress := tracker.Observe(watchResult(10, true, 5))
+	require.Equal([]ChainProgress{
+		{Network: "devnet", Chain: "c", Height: 10},
+		{Network: "devnet", Chain: "p", Height: 5},
+	}, progress)
+
+	progress = tracker.Observe(watchResult(13, true, 5))
+	require.Equal(uint64(3), progress[0].Advanced)
+	require.Zero(progress[0].Stalled)
+	require.Equal(1, progress[1].Stalled)
+
+	// An unreachable chain does not advance
+	progress = tracker.Observe(watchResult(0, false, 5))
+	require.Equal(1, progress[0].Stalled)
+	require.Equal(2, progress[1].Stalled)
+	require.Equal([]ChainProgress{{Network: "devnet", Chain: "p", Height: 5, Stalled: 2}}, Stalled(progress, 2))
+	require.Empty(Stalled(progress, 0))
+
+	// Recovery resets the count, measured against the last reachable height
+	progress = tracker.Observe(watchResult(14, true, 6))
+	require.Equal(uint64(1), progress[0].Advanced)
+	require.Zero(progress[0].Stalled)
+	require.Zero(progress[1].Stalled)
+}
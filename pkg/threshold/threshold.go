@@ -0,0 +1,271 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package threshold signs with secp256k1 keys split among n parties, any t
+// of which sign together, producing the same [r || s || v] signatures as a
+// single key.
+//
+// Keys are dealt: the dealer generates the key and a set of presignatures
+// (a nonce k with R = kG) and Shamir-shares k⁻¹ and k⁻¹x among the parties,
+// then forgets them. To sign digest m with presignature j, each party sends
+// its share of s = k⁻¹(m + rx), which is linear in the shares, and any t of
+// them interpolate to s. The key itself is never shared, but t parties
+// together could recover it, as with any t-of-n scheme. Each presignature
+// signs a single digest: reusing one for another digest reveals the key, so
+// the signing service hands each out once.
+package threshold
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+const (
+	// PartialLen is the length of a partial signature: the compressed R of
+	// its presignature and the party's share of s
+	PartialLen = 33 + 32
+	// SignatureLen is the length of a combined [r || s || v] signature
+	SignatureLen = 65
+
+	digestLen = 32
+)
+
+// Share is the part of a wallet held by one party
+type Share struct {
+	WalletID  string `json:"walletId"`
+	NodeID    string `json:"nodeId"`
+	Index     int    `json:"index"`
+	Threshold int    `json:"threshold"`
+	Parties   int    `json:"parties"`
+	// PublicKey is the compressed public key of the wallet
+	PublicKey     []byte              `json:"publicKey"`
+	Presignatures []PresignatureShare `json:"presignatures"`
+}
+
+// PresignatureShare is the share of a party of one presignature
+type PresignatureShare struct {
+	// R is the compressed nonce point of the presignature
+	R []byte `json:"r"`
+	// KInv and KInvX are the party's shares of k⁻¹ and k⁻¹x
+	KInv  []byte `json:"kInv"`
+	KInvX []byte `json:"kInvX"`
+}
+
+// Deal generates a key with presignatures presignatures and splits it into
+// parties shares, any threshold of which sign. Shares are indexed from 1 and
+// have no wallet or node IDs yet.
+func Deal(threshold, parties, presignatures int) ([]*Share, error) {
+	if threshold < 1 || threshold > parties {
+		return nil, fmt.Errorf("invalid threshold %d of %d parties", threshold, parties)
+	}
+	if presignatures < 1 {
+		return nil, fmt.Errorf("invalid number of presignatures %d", presignatures)
+	}
+	x, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	defer x.Zero()
+	pub := x.PubKey().SerializeCompressed()
+
+	shares := make([]*Share, parties)
+	for i := range shares {
+		shares[i] = &Share{
+			Index:         i + 1,
+			Threshold:     threshold,
+			Parties:       parties,
+			PublicKey:     pub,
+			Presignatures: make([]PresignatureShare, presignatures),
+		}
+	}
+	for j := 0; j < presignatures; j++ {
+		k, err := secp256k1.GeneratePrivateKey()
+		if err != nil {
+			return nil, err
+		}
+		r := k.PubKey().SerializeCompressed()
+		kInv := new(secp256k1.ModNScalar).InverseValNonConst(&k.Key)
+		kInvX := new(secp256k1.ModNScalar).Mul2(kInv, &x.Key)
+		k.Zero()
+		kInvShares, err := split(kInv, threshold, parties)
+		if err != nil {
+			return nil, err
+		}
+		kInvXShares, err := split(kInvX, threshold, parties)
+		if err != nil {
+			return nil, err
+		}
+		kInv.Zero()
+		kInvX.Zero()
+		for i, share := range shares {
+			kInvBytes := kInvShares[i].Bytes()
+			kInvXBytes := kInvXShares[i].Bytes()
+			share.Presignatures[j] = PresignatureShare{R: r, KInv: kInvBytes[:], KInvX: kInvXBytes[:]}
+		}
+	}
+	return shares, nil
+}
+
+// split returns the values at 1..parties of a random polynomial of degree
+// threshold-1 whose value at 0 is secret
+func split(secret *secp256k1.ModNScalar, threshold, parties int) ([]*secp256k1.ModNScalar, error) {
+	coeffs := make([]secp256k1.ModNScalar, threshold)
+	coeffs[0].Set(secret)
+	for c := 1; c < threshold; c++ {
+		k, err := secp256k1.GeneratePrivateKey()
+		if err != nil {
+			return nil, err
+		}
+		coeffs[c].Set(&k.Key)
+		k.Zero()
+	}
+	values := make([]*secp256k1.ModNScalar, parties)
+	for i := range values {
+		var at, v secp256k1.ModNScalar
+		at.SetInt(uint32(i + 1))
+		// Horner's rule from the highest coefficient
+		for c := threshold - 1; c >= 0; c-- {
+			v.Mul(&at).Add(&coeffs[c])
+		}
+		values[i] = &v
+	}
+	for c := range coeffs {
+		coeffs[c].Zero()
+	}
+	return values, nil
+}
+
+// Sign returns the partial signature of digest with presignature j
+func (s *Share) Sign(j int, digest []byte) ([]byte, error) {
+	if len(digest) != digestLen {
+		return nil, fmt.Errorf("invalid digest length %d", len(digest))
+	}
+	if j < 0 || j >= len(s.Presignatures) {
+		return nil, fmt.Errorf("share %d of wallet %s has no presignature %d", s.Index, s.WalletID, j)
+	}
+	p := s.Presignatures[j]
+	rPoint, err := secp256k1.ParsePubKey(p.R)
+	if err != nil {
+		return nil, fmt.Errorf("invalid presignature %d: %w", j, err)
+	}
+	kInv, err := scalar(p.KInv)
+	if err != nil {
+		return nil, fmt.Errorf("invalid presignature %d: %w", j, err)
+	}
+	kInvX, err := scalar(p.KInvX)
+	if err != nil {
+		return nil, fmt.Errorf("invalid presignature %d: %w", j, err)
+	}
+	var m, r secp256k1.ModNScalar
+	m.SetByteSlice(digest)
+	r.SetByteSlice(rPoint.X().Bytes())
+	// s_i = m·k⁻¹_i + r·(k⁻¹x)_i
+	si := new(secp256k1.ModNScalar).Mul2(&m, kInv).Add(kInvX.Mul(&r))
+	siBytes := si.Bytes()
+	return append(append([]byte{}, p.R...), siBytes[:]...), nil
+}
+
+// Combine returns the [r || s || v] signature of digest by the wallet of
+// publicKey from the partial signatures of at least threshold parties, by
+// party index. The signature is checked to recover publicKey.
+func Combine(publicKey []byte, digest []byte, threshold int, partials map[int][]byte) ([]byte, error) {
+	if len(digest) != digestLen {
+		return nil, fmt.Errorf("invalid digest length %d", len(digest))
+	}
+	if len(partials) < threshold {
+		return nil, fmt.Errorf("%d partial signatures, %d required", len(partials), threshold)
+	}
+	indices := make([]int, 0, len(partials))
+	for i := range partials {
+		if i < 1 {
+			return nil, fmt.Errorf("invalid party index %d", i)
+		}
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	indices = indices[:threshold]
+
+	var rBytes []byte
+	var s secp256k1.ModNScalar
+	for _, i := range indices {
+		partial := partials[i]
+		if len(partial) != PartialLen {
+			return nil, fmt.Errorf("invalid partial signature of party %d", i)
+		}
+		if rBytes == nil {
+			rBytes = partial[:33]
+		} else if string(rBytes) != string(partial[:33]) {
+			return nil, fmt.Errorf("partial signature of party %d is for another presignature", i)
+		}
+		si, err := scalar(partial[33:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid partial signature of party %d: %w", i, err)
+		}
+		s.Add(si.Mul(lagrange(i, indices)))
+	}
+	if s.IsZero() {
+		return nil, errors.New("partial signatures combine to an invalid signature")
+	}
+	rPoint, err := secp256k1.ParsePubKey(rBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid presignature: %w", err)
+	}
+	var r secp256k1.ModNScalar
+	recovery := byte(0)
+	if r.SetByteSlice(rPoint.X().Bytes()) {
+		recovery |= 2
+	}
+	if rPoint.Y().Bit(0) == 1 {
+		recovery |= 1
+	}
+	// signatures are canonical, with the low s
+	if s.IsOverHalfOrder() {
+		s.Negate()
+		recovery ^= 1
+	}
+
+	rOut, sOut := r.Bytes(), s.Bytes()
+	compact := make([]byte, 0, SignatureLen)
+	compact = append(compact, 27+4+recovery)
+	compact = append(compact, rOut[:]...)
+	compact = append(compact, sOut[:]...)
+	pub, _, err := ecdsa.RecoverCompact(compact, digest)
+	if err != nil || string(pub.SerializeCompressed()) != string(publicKey) {
+		return nil, errors.New("partial signatures don't combine to a signature of the wallet")
+	}
+	return append(compact[1:], recovery), nil
+}
+
+// lagrange returns the Lagrange coefficient at 0 of party i among indices
+func lagrange(i int, indices []int) *secp256k1.ModNScalar {
+	var num, den secp256k1.ModNScalar
+	num.SetInt(1)
+	den.SetInt(1)
+	for _, j := range indices {
+		if j == i {
+			continue
+		}
+		var xj, diff secp256k1.ModNScalar
+		xj.SetInt(uint32(j))
+		num.Mul(&xj)
+		// j - i
+		diff.SetInt(uint32(i)).Negate().Add(&xj)
+		den.Mul(&diff)
+	}
+	return num.Mul(den.InverseNonConst())
+}
+
+func scalar(b []byte) (*secp256k1.ModNScalar, error) {
+	if len(b) != 32 {
+		return nil, errors.New("invalid scalar length")
+	}
+	var v secp256k1.ModNScalar
+	if v.SetByteSlice(b) {
+		return nil, errors.New("scalar out of range")
+	}
+	return &v, nil
+}
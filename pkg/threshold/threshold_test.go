@@ -0,0 +1,86 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package threshold
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDealSignCombine(t *testing.T) {
+	require := require.New(t)
+	shares, err := Deal(2, 3, 4)
+	require.NoError(err)
+	require.Len(shares, 3)
+	pub := shares[0].PublicKey
+
+	for j := 0; j < 4; j++ {
+		digest := sha256.Sum256([]byte{byte(j)})
+		partials := map[int][]byte{}
+		for _, share := range shares {
+			partial, err := share.Sign(j, digest[:])
+			require.NoError(err)
+			require.Len(partial, PartialLen)
+			partials[share.Index] = partial
+		}
+		// any two of the three parties sign
+		for _, pair := range [][2]int{{1, 2}, {1, 3}, {2, 3}} {
+			sig, err := Combine(pub, digest[:], 2, map[int][]byte{pair[0]: partials[pair[0]], pair[1]: partials[pair[1]]})
+			require.NoError(err)
+			require.Len(sig, SignatureLen)
+
+			// a canonical [r || s || v] signature of the wallet key
+			var r, s secp256k1.ModNScalar
+			r.SetByteSlice(sig[:32])
+			s.SetByteSlice(sig[32:64])
+			require.False(s.IsOverHalfOrder())
+			key, err := secp256k1.ParsePubKey(pub)
+			require.NoError(err)
+			require.True(ecdsa.NewSignature(&r, &s).Verify(digest[:], key))
+			compact := append([]byte{27 + 4 + sig[64]}, sig[:64]...)
+			recovered, _, err := ecdsa.RecoverCompact(compact, digest[:])
+			require.NoError(err)
+			require.Equal(pub, recovered.SerializeCompressed())
+		}
+	}
+}
+
+func TestCombineRefusesBadPartials(t *testing.T) {
+	require := require.New(t)
+	shares, err := Deal(2, 3, 2)
+	require.NoError(err)
+	pub := shares[0].PublicKey
+	digest := sha256.Sum256([]byte("tx"))
+
+	p1, err := shares[0].Sign(0, digest[:])
+	require.NoError(err)
+	p2, err := shares[1].Sign(0, digest[:])
+	require.NoError(err)
+	_, err = Combine(pub, digest[:], 2, map[int][]byte{1: p1})
+	require.ErrorContains(err, "2 required")
+
+	// a partial of another presignature or another digest doesn't combine
+	other, err := shares[1].Sign(1, digest[:])
+	require.NoError(err)
+	_, err = Combine(pub, digest[:], 2, map[int][]byte{1: p1, 2: other})
+	require.ErrorContains(err, "another presignature")
+	otherDigest := sha256.Sum256([]byte("other tx"))
+	other, err = shares[1].Sign(0, otherDigest[:])
+	require.NoError(err)
+	_, err = Combine(pub, digest[:], 2, map[int][]byte{1: p1, 2: other})
+	require.ErrorContains(err, "don't combine")
+
+	// nor do the partials of the wrong parties
+	_, err = Combine(pub, digest[:], 2, map[int][]byte{1: p1, 3: p2})
+	require.ErrorContains(err, "don't combine")
+
+	_, err = shares[0].Sign(2, digest[:])
+	require.ErrorContains(err, "no presignature 2")
+	_, err = Deal(4, 3, 1)
+	require.ErrorContains(err, "invalid threshold")
+}
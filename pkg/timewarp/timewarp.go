@@ -0,0 +1,143 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package timewarp advances the block time of the EVM chains of a local
+// network through the dev namespace of their nodes (evm_increaseTime, as in
+// Anvil and Hardhat), so time-locked contract logic such as vesting or
+// auction ends can be tested without waiting.
+package timewarp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotAdvanced is returned for a chain whose VM accepted the time offset
+// but kept timestamping blocks with the clock of its node
+var ErrNotAdvanced = errors.New("block time did not advance, the VM of this chain ignores time offsets")
+
+// Chain is an EVM chain to advance
+type Chain struct {
+	Name string
+	// URL is the RPC endpoint of the chain, e.g.
+	// http://127.0.0.1:9650/ext/bc/C/rpc
+	URL string
+}
+
+// Result is the block time of a chain before and after the warp. Err is set
+// when the chain could not be advanced.
+type Result struct {
+	Chain  Chain
+	Before time.Time
+	After  time.Time
+	Err    error
+}
+
+// Advance moves the block time of each chain forward by offset
+// concurrently, then mines a block so that the next transactions execute
+// at the new time. The results are in the order of chains.
+func Advance(ctx context.Context, client *http.Client, chains []Chain, offset time.Duration) []Result {
+	results := make([]Result, len(chains))
+	var wg sync.WaitGroup
+	for i, c := range chains {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = advance(ctx, client, c, offset)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func advance(ctx context.Context, client *http.Client, c Chain, offset time.Duration) Result {
+	result := Result{Chain: c}
+	before, err := blockTime(ctx, client, c.URL)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Before = before
+	seconds := uint64(offset / time.Second)
+	var newTime json.RawMessage
+	if err := call(ctx, client, c.URL, "evm_increaseTime", []interface{}{"0x" + strconv.FormatUint(seconds, 16)}, &newTime); err != nil {
+		result.Err = err
+		return result
+	}
+	var hash json.RawMessage
+	if err := call(ctx, client, c.URL, "evm_mine", []interface{}{}, &hash); err != nil {
+		result.Err = err
+		return result
+	}
+	if result.After, result.Err = blockTime(ctx, client, c.URL); result.Err != nil {
+		return result
+	}
+	if result.After.Before(before.Add(offset)) {
+		result.Err = ErrNotAdvanced
+	}
+	return result
+}
+
+// blockTime returns the timestamp of the last block of the chain at url
+func blockTime(ctx context.Context, client *http.Client, url string) (time.Time, error) {
+	var block struct {
+		Timestamp string `json:"timestamp"`
+	}
+	if err := call(ctx, client, url, "eth_getBlockByNumber", []interface{}{"latest", false}, &block); err != nil {
+		return time.Time{}, err
+	}
+	ts, err := strconv.ParseUint(strings.TrimPrefix(block.Timestamp, "0x"), 16, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid block timestamp %q", block.Timestamp)
+	}
+	return time.Unix(int64(ts), 0), nil //nolint:gosec // G115: block timestamps fit in int64
+}
+
+func call(ctx context.Context, client *http.Client, url, method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: status %d", method, resp.StatusCode)
+	}
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", method, err)
+	}
+	if response.Error != nil {
+		return fmt.Errorf("%s: %s", method, response.Error.Message)
+	}
+	if len(response.Result) == 0 {
+		return errors.New(method + ": empty result")
+	}
+	return json.Unmarshal(response.Result, result)
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timewarp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// devNode serves the block time of a chain, shifted by evm_increaseTime
+// unless it ignores offsets
+type devNode struct {
+	mu            sync.Mutex
+	time          uint64
+	offset        uint64
+	ignoreOffsets bool
+}
+
+func (n *devNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var req struct {
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var result interface{}
+	switch req.Method {
+	case "eth_getBlockByNumber":
+		result = map[string]string{"timestamp": "0x" + strconv.FormatUint(n.time, 16)}
+	case "evm_increaseTime":
+		var seconds string
+		_ = json.Unmarshal(req.Params[0], &seconds)
+		offset, _ := strconv.ParseUint(strings.TrimPrefix(seconds, "0x"), 16, 64)
+		if !n.ignoreOffsets {
+			n.offset += offset
+		}
+		result = "0x" + strconv.FormatUint(n.time+n.offset, 16)
+	case "evm_mine":
+		n.time += n.offset + 1
+		n.offset = 0
+		result = "0x01"
+	default:
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]string{"message": "method not found"}})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+}
+
+func TestAdvance(t *testing.T) {
+	require := require.New(t)
+	start := uint64(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Unix())
+	honoring := httptest.NewServer(&devNode{time: start})
+	defer honoring.Close()
+	ignoring := httptest.NewServer(&devNode{time: start, ignoreOffsets: true})
+	defer ignoring.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	results := Advance(context.Background(), http.DefaultClient, []Chain{
+		{Name: "c", URL: honoring.URL},
+		{Name: "l1", URL: ignoring.URL},
+		{Name: "down", URL: down.URL},
+	}, 72*time.Hour)
+	require.Len(results, 3)
+
+	require.NoError(results[0].Err)
+	require.Equal(int64(start), results[0].Before.Unix())
+	require.GreaterOrEqual(results[0].After.Sub(results[0].Before), 72*time.Hour)
+
+	require.ErrorIs(results[1].Err, ErrNotAdvanced)
+	require.Equal("l1", results[1].Chain.Name)
+
+	require.Error(results[2].Err)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/luxfi/address"
 	"github.com/luxfi/cli/pkg/key"
+	"github.com/luxfi/cli/pkg/utils"
 	"github.com/luxfi/constants"
 	"github.com/luxfi/ids"
 	"github.com/luxfi/protocol/p/txs"
@@ -67,8 +68,19 @@ type ChainOwners struct {
 	Threshold      uint32
 }
 
-// GetChainOwners retrieves ownership information for a chain
+// chainOwnersCache holds chain ownership lookups per network and chain
+var chainOwnersCache = utils.NewTTLCache[string, *ChainOwners](utils.PChainCacheTTL)
+
+// GetChainOwners retrieves ownership information for a chain.
+// Results are cached for a short time as signing flows repeat this lookup, so
+// the returned owners are shared and must not be modified.
 func GetChainOwners(network models.Network, chainID ids.ID) (*ChainOwners, error) {
+	return chainOwnersCache.GetOrLoad(network.String()+"/"+chainID.String(), func() (*ChainOwners, error) {
+		return getChainOwners(network, chainID)
+	})
+}
+
+func getChainOwners(network models.Network, chainID ids.ID) (*ChainOwners, error) {
 	pClient, err := getPlatformClient(network)
 	if err != nil {
 		return nil, err
@@ -13,16 +13,9 @@ import (
 
 // SaveToDisk saves a given tx to the specified path.
 func SaveToDisk(tx *txs.Tx, txPath string, forceOverwrite bool) error {
-	// Serialize the signed tx
-	txBytes, err := txs.Codec.Marshal(txs.CodecVersion, tx)
+	txStr, err := encodeTx(tx)
 	if err != nil {
-		return fmt.Errorf("couldn't marshal signed tx: %w", err)
-	}
-
-	// Get the encoded (in hex + checksum) signed tx
-	txStr, err := formatting.Encode(formatting.Hex, txBytes)
-	if err != nil {
-		return fmt.Errorf("couldn't encode signed tx: %w", err)
+		return err
 	}
 	// save
 	if _, err := os.Stat(txPath); err == nil && !forceOverwrite {
@@ -40,13 +33,42 @@ func SaveToDisk(tx *txs.Tx, txPath string, forceOverwrite bool) error {
 	return nil
 }
 
-// LoadFromDisk loads a tx from the specified path.
+// LoadFromDisk loads a tx from the specified path. The file can also be an
+// offline tx, as saved by SaveOfflineTx.
 func LoadFromDisk(txPath string) (*txs.Tx, error) {
 	txEncodedBytes, err := os.ReadFile(txPath) //nolint:gosec // G304: Reading from user-specified path
 	if err != nil {
 		return nil, err
 	}
-	txBytes, err := formatting.Decode(formatting.Hex, string(txEncodedBytes))
+	if isOfflineTx(txEncodedBytes) {
+		offline, err := LoadOfflineTx(txPath)
+		if err != nil {
+			return nil, err
+		}
+		return offline.GetTx()
+	}
+	return decodeTx(string(txEncodedBytes))
+}
+
+// encodeTx serializes a tx in hex with checksum, as saved to disk
+func encodeTx(tx *txs.Tx) (string, error) {
+	// Serialize the signed tx
+	txBytes, err := txs.Codec.Marshal(txs.CodecVersion, tx)
+	if err != nil {
+		return "", fmt.Errorf("couldn't marshal signed tx: %w", err)
+	}
+
+	// Get the encoded (in hex + checksum) signed tx
+	txStr, err := formatting.Encode(formatting.Hex, txBytes)
+	if err != nil {
+		return "", fmt.Errorf("couldn't encode signed tx: %w", err)
+	}
+	return txStr, nil
+}
+
+// decodeTx parses a tx encoded by encodeTx
+func decodeTx(txStr string) (*txs.Tx, error) {
+	txBytes, err := formatting.Decode(formatting.Hex, txStr)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't decode signed tx: %w", err)
 	}
@@ -0,0 +1,208 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package txutils
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/key"
+	"github.com/luxfi/crypto/hash"
+	"github.com/luxfi/crypto/secp256k1"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/protocol/p/txs"
+	"github.com/luxfi/utxo/secp256k1fx"
+)
+
+// ErrNoOfflineSigner is returned when a key is not one of the remaining
+// signers of an offline tx
+var ErrNoOfflineSigner = errors.New("key is not a remaining signer of the transaction")
+
+// OfflineTx is a tx packaged to be signed on a machine without network
+// access. Everything the P-Chain is asked for while signing (the chain control
+// keys and the signature slots they fill) is resolved when it is built.
+type OfflineTx struct {
+	Network   string `json:"network"`
+	ChainName string `json:"chainName"`
+	ChainID   ids.ID `json:"chainID"`
+	TxType    string `json:"txType"`
+	// SigningHash is the hex SHA-256 of the unsigned tx, the payload every
+	// signer signs
+	SigningHash string          `json:"signingHash"`
+	Signers     []OfflineSigner `json:"signers"`
+	// Tx is the tx encoded as by SaveToDisk
+	Tx string `json:"tx"`
+}
+
+// OfflineSigner is a signature slot of an offline tx
+type OfflineSigner struct {
+	Address   string `json:"address"`
+	CredIndex int    `json:"credIndex"`
+	SigIndex  int    `json:"sigIndex"`
+	Signed    bool   `json:"signed"`
+}
+
+// NewOfflineTx packages tx for offline signing by the chain auth keys among
+// controlKeys, which must be in the order returned by GetOwners
+func NewOfflineTx(tx *txs.Tx, chainName string, chainID ids.ID, controlKeys []string) (*OfflineTx, error) {
+	network, err := GetNetwork(tx)
+	if err != nil {
+		return nil, err
+	}
+	authSigners, remaining, err := GetRemainingSigners(tx, controlKeys)
+	if err != nil {
+		return nil, err
+	}
+	if len(remaining) == 0 {
+		return nil, errors.New("transaction is already fully signed")
+	}
+	offline := &OfflineTx{
+		Network:   network.String(),
+		ChainName: chainName,
+		ChainID:   chainID,
+		TxType:    strings.TrimPrefix(fmt.Sprintf("%T", tx.Unsigned), "*txs."),
+	}
+	// the chain auth signatures are the last credential, as checked by
+	// GetRemainingSigners
+	credIndex := len(tx.Creds) - 1
+	cred := tx.Creds[credIndex].(*secp256k1fx.Credential)
+	for i, addr := range authSigners {
+		offline.Signers = append(offline.Signers, OfflineSigner{
+			Address:   addr,
+			CredIndex: credIndex,
+			SigIndex:  i,
+			Signed:    cred.Sigs[i] != [secp256k1.SignatureLen]byte{},
+		})
+	}
+	if err := offline.setTx(tx); err != nil {
+		return nil, err
+	}
+	return offline, nil
+}
+
+// Remaining returns the addresses that have not signed yet
+func (o *OfflineTx) Remaining() []string {
+	remaining := []string{}
+	for _, signer := range o.Signers {
+		if !signer.Signed {
+			remaining = append(remaining, signer.Address)
+		}
+	}
+	return remaining
+}
+
+// GetTx decodes the tx, checking it still matches the signing hash
+func (o *OfflineTx) GetTx() (*txs.Tx, error) {
+	tx, err := decodeTx(o.Tx)
+	if err != nil {
+		return nil, err
+	}
+	if got := hex.EncodeToString(hash.ComputeHash256(tx.Unsigned.Bytes())); got != o.SigningHash {
+		return nil, fmt.Errorf("transaction does not match its signing hash %s (got %s)", o.SigningHash, got)
+	}
+	return tx, nil
+}
+
+// Sign fills the signature slots of privKey, without network access, returning
+// how many were signed
+func (o *OfflineTx) Sign(privKey *secp256k1.PrivateKey) (int, error) {
+	tx, err := o.GetTx()
+	if err != nil {
+		return 0, err
+	}
+	network, err := GetNetwork(tx)
+	if err != nil {
+		return 0, err
+	}
+	networkID, err := network.NetworkID()
+	if err != nil {
+		return 0, err
+	}
+	signer, err := key.NewSoft(networkID, key.WithPrivateKey(privKey))
+	if err != nil {
+		return 0, err
+	}
+	addr := signer.P()[0]
+	signingHash, err := hex.DecodeString(o.SigningHash)
+	if err != nil {
+		return 0, fmt.Errorf("invalid signing hash: %w", err)
+	}
+
+	signed := 0
+	for i := range o.Signers {
+		slot := &o.Signers[i]
+		if slot.Address != addr || slot.Signed {
+			continue
+		}
+		if slot.CredIndex >= len(tx.Creds) {
+			return 0, fmt.Errorf("credential %d out of range", slot.CredIndex)
+		}
+		cred, ok := tx.Creds[slot.CredIndex].(*secp256k1fx.Credential)
+		if !ok || slot.SigIndex >= len(cred.Sigs) {
+			return 0, fmt.Errorf("signature %d of credential %d out of range", slot.SigIndex, slot.CredIndex)
+		}
+		sig, err := signer.Key().SignHash(signingHash)
+		if err != nil {
+			return 0, fmt.Errorf("failed to sign: %w", err)
+		}
+		copy(cred.Sigs[slot.SigIndex][:], sig)
+		slot.Signed = true
+		signed++
+	}
+	if signed == 0 {
+		return 0, fmt.Errorf("%w: %s", ErrNoOfflineSigner, addr)
+	}
+	if err := tx.Initialize(txs.Codec); err != nil {
+		return 0, fmt.Errorf("error initializing signed tx: %w", err)
+	}
+	return signed, o.setTx(tx)
+}
+
+func (o *OfflineTx) setTx(tx *txs.Tx) error {
+	txStr, err := encodeTx(tx)
+	if err != nil {
+		return err
+	}
+	o.Tx = txStr
+	o.SigningHash = hex.EncodeToString(hash.ComputeHash256(tx.Unsigned.Bytes()))
+	return nil
+}
+
+// SaveOfflineTx saves an offline tx as JSON to the specified path
+func SaveOfflineTx(offline *OfflineTx, txPath string, forceOverwrite bool) error {
+	if _, err := os.Stat(txPath); err == nil && !forceOverwrite {
+		return fmt.Errorf("couldn't create file to write tx to: file exists")
+	}
+	data, err := json.MarshalIndent(offline, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(txPath, append(data, '\n'), 0o600)
+}
+
+// LoadOfflineTx loads an offline tx from the specified path
+func LoadOfflineTx(txPath string) (*OfflineTx, error) {
+	data, err := os.ReadFile(txPath) //nolint:gosec // G304: Reading from user-specified path
+	if err != nil {
+		return nil, err
+	}
+	if !isOfflineTx(data) {
+		return nil, fmt.Errorf("%s is not an offline transaction, create one with: lux transaction build", txPath)
+	}
+	var offline OfflineTx
+	if err := json.Unmarshal(data, &offline); err != nil {
+		return nil, fmt.Errorf("invalid offline transaction: %w", err)
+	}
+	return &offline, nil
+}
+
+// isOfflineTx tells an offline tx file from a hex encoded tx file
+func isOfflineTx(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
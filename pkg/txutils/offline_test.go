@@ -0,0 +1,113 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package txutils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/luxfi/cli/pkg/key"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/crypto/secp256k1"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/protocol/p/txs"
+	lux "github.com/luxfi/utxo"
+	"github.com/luxfi/utxo/secp256k1fx"
+	"github.com/luxfi/vm/components/verify"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSigner returns a key and its P-Chain address on the testnet
+func newTestSigner(t *testing.T) (*secp256k1.PrivateKey, string) {
+	sk, err := key.NewSoft(constants.TestnetID)
+	require.NoError(t, err)
+	return sk.Key(), sk.P()[0]
+}
+
+// newTestCreateChainTx returns a funded CreateChainTx awaiting both chain
+// auth signatures
+func newTestCreateChainTx(t *testing.T) *txs.Tx {
+	tx := &txs.Tx{
+		Unsigned: &txs.CreateChainTx{
+			BaseTx: txs.BaseTx{BaseTx: lux.BaseTx{
+				NetworkID:    constants.TestnetID,
+				BlockchainID: ids.Empty,
+			}},
+			ValidateNetworkID: ids.GenerateTestID(),
+			BlockchainName:    "offline",
+			VMID:              ids.GenerateTestID(),
+			GenesisData:       []byte("{}"),
+			ChainAuth:         &secp256k1fx.Input{SigIndices: []uint32{0, 1}},
+		},
+		Creds: []verify.Verifiable{
+			&secp256k1fx.Credential{Sigs: [][secp256k1.SignatureLen]byte{{1}}},
+			&secp256k1fx.Credential{Sigs: make([][secp256k1.SignatureLen]byte, 2)},
+		},
+	}
+	require.NoError(t, tx.Initialize(txs.Codec))
+	return tx
+}
+
+func TestOfflineTxSign(t *testing.T) {
+	require := require.New(t)
+	key1, addr1 := newTestSigner(t)
+	key2, addr2 := newTestSigner(t)
+	outsider, _ := newTestSigner(t)
+
+	chainID := ids.GenerateTestID()
+	offline, err := NewOfflineTx(newTestCreateChainTx(t), "mychain", chainID, []string{addr1, addr2})
+	require.NoError(err)
+	require.Equal("CreateChainTx", offline.TxType)
+	require.Equal([]string{addr1, addr2}, offline.Remaining())
+	signingHash := offline.SigningHash
+
+	// round trip through the file moved to the offline machine
+	path := filepath.Join(t.TempDir(), "tx.json")
+	require.NoError(SaveOfflineTx(offline, path, false))
+	require.ErrorContains(SaveOfflineTx(offline, path, false), "file exists")
+	offline, err = LoadOfflineTx(path)
+	require.NoError(err)
+
+	_, err = offline.Sign(outsider)
+	require.ErrorIs(err, ErrNoOfflineSigner)
+
+	signed, err := offline.Sign(key1)
+	require.NoError(err)
+	require.Equal(1, signed)
+	require.Equal([]string{addr2}, offline.Remaining())
+	_, err = offline.Sign(key1)
+	require.ErrorIs(err, ErrNoOfflineSigner)
+
+	signed, err = offline.Sign(key2)
+	require.NoError(err)
+	require.Equal(1, signed)
+	require.Empty(offline.Remaining())
+	// signatures leave the signed payload unchanged
+	require.Equal(signingHash, offline.SigningHash)
+
+	require.NoError(SaveOfflineTx(offline, path, true))
+	tx, err := LoadFromDisk(path)
+	require.NoError(err)
+	_, remaining, err := GetRemainingSigners(tx, []string{addr1, addr2})
+	require.NoError(err)
+	require.Empty(remaining)
+}
+
+func TestOfflineTxTampered(t *testing.T) {
+	require := require.New(t)
+	key1, addr1 := newTestSigner(t)
+
+	offline, err := NewOfflineTx(newTestCreateChainTx(t), "mychain", ids.GenerateTestID(), []string{addr1, addr1})
+	require.NoError(err)
+	offline.SigningHash = strings.Repeat("0", len(offline.SigningHash))
+	_, err = offline.Sign(key1)
+	require.ErrorContains(err, "does not match its signing hash")
+
+	path := filepath.Join(t.TempDir(), "tx.hex")
+	require.NoError(os.WriteFile(path, []byte(offline.Tx), 0o600))
+	_, err = LoadOfflineTx(path)
+	require.ErrorContains(err, "is not an offline transaction")
+}
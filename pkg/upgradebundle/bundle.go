@@ -0,0 +1,240 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package upgradebundle reads upgrade bundles: network upgrade activation
+// times and chain upgrade files applied together to every node of a local
+// network (lux network start --upgrade-bundle).
+package upgradebundle
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luxfi/upgrade"
+)
+
+const (
+	// Genesis activates an upgrade from the first block
+	Genesis = "genesis"
+	// Never leaves an upgrade unscheduled
+	Never = "never"
+)
+
+// Bundle is a set of upgrades applied consistently across all nodes.
+//
+// Example:
+//
+//	{
+//	  "network": {"etna": "genesis", "fortuna": "+10m", "granite": "never"},
+//	  "chains": {
+//	    "mychain": {"precompileUpgrades": [{"feeManagerConfig": {"blockTimestamp": 1767225600}}]}
+//	  }
+//	}
+type Bundle struct {
+	// Network maps upgrade names (etna or etnaTime) to activation times:
+	// genesis, never, RFC3339, unix seconds or +<duration> from network start
+	Network map[string]string `json:"network"`
+	// Chains maps chain names to the upgrade file of the chain
+	Chains map[string]json.RawMessage `json:"chains"`
+}
+
+// Activation is a network upgrade and the time it activates
+type Activation struct {
+	Name string
+	Time time.Time
+}
+
+// Scheduled reports whether the activation has a time set
+func (a Activation) Scheduled() bool {
+	return a.Time.Before(upgrade.UnscheduledActivationTime)
+}
+
+// Live reports whether the upgrade is active at t
+func (a Activation) Live(t time.Time) bool {
+	return !t.Before(a.Time)
+}
+
+// Status describes the activation at t: live, pending (in <duration>) or
+// unscheduled
+func (a Activation) Status(t time.Time) string {
+	switch {
+	case !a.Scheduled():
+		return "unscheduled"
+	case a.Live(t):
+		return "live"
+	}
+	return fmt.Sprintf("pending (in %s)", a.Time.Sub(t).Round(time.Second))
+}
+
+// upgradeTime points at the activation time of an upgrade in a config
+type upgradeTime struct {
+	name string
+	time *time.Time
+}
+
+// activationTimes returns the network upgrades of c in activation order
+func activationTimes(c *upgrade.Config) []upgradeTime {
+	return []upgradeTime{
+		{"apricotPhase1", &c.ApricotPhase1Time},
+		{"apricotPhase2", &c.ApricotPhase2Time},
+		{"apricotPhase3", &c.ApricotPhase3Time},
+		{"apricotPhase4", &c.ApricotPhase4Time},
+		{"apricotPhase5", &c.ApricotPhase5Time},
+		{"apricotPhasePre6", &c.ApricotPhasePre6Time},
+		{"apricotPhase6", &c.ApricotPhase6Time},
+		{"apricotPhasePost6", &c.ApricotPhasePost6Time},
+		{"banff", &c.BanffTime},
+		{"cortina", &c.CortinaTime},
+		{"durango", &c.DurangoTime},
+		{"etna", &c.EtnaTime},
+		{"fortuna", &c.FortunaTime},
+		{"granite", &c.GraniteTime},
+	}
+}
+
+// Activations returns the network upgrades of c in activation order
+func Activations(c upgrade.Config) []Activation {
+	times := activationTimes(&c)
+	activations := make([]Activation, 0, len(times))
+	for _, t := range times {
+		activations = append(activations, Activation{Name: t.name, Time: *t.time})
+	}
+	return activations
+}
+
+// Load reads a bundle file
+func Load(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: User-specified bundle file
+	if err != nil {
+		return nil, err
+	}
+	b, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return b, nil
+}
+
+// Parse decodes and validates a bundle
+func Parse(data []byte) (*Bundle, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	b := &Bundle{}
+	if err := decoder.Decode(b); err != nil {
+		return nil, fmt.Errorf("invalid upgrade bundle: %w", err)
+	}
+	if len(b.Network) == 0 && len(b.Chains) == 0 {
+		return nil, errors.New("upgrade bundle declares no upgrades")
+	}
+	// Validate against a fixed time, relative activations are resolved on start
+	if _, err := b.NetworkUpgrades(time.Now()); err != nil {
+		return nil, err
+	}
+	for name, raw := range b.Chains {
+		var file map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &file); err != nil {
+			return nil, fmt.Errorf("chain %s: upgrade file must be a JSON object: %w", name, err)
+		}
+	}
+	return b, nil
+}
+
+// NetworkUpgrades returns the default local upgrade schedule with the bundle
+// activations applied, relative times counting from now. Upgrades the bundle
+// doesn't set are moved so the schedule stays in order: scheduling etna in
+// the future also delays the upgrades that follow it.
+func (b *Bundle) NetworkUpgrades(now time.Time) (upgrade.Config, error) {
+	config := upgrade.Default
+	times := activationTimes(&config)
+	explicit := make([]bool, len(times))
+	for key, value := range b.Network {
+		name := strings.TrimSuffix(key, "Time")
+		i := indexOf(times, name)
+		if i < 0 {
+			return upgrade.Config{}, fmt.Errorf("unknown network upgrade %q", key)
+		}
+		t, err := ParseActivation(value, now)
+		if err != nil {
+			return upgrade.Config{}, fmt.Errorf("network upgrade %s: %w", key, err)
+		}
+		*times[i].time = t
+		explicit[i] = true
+	}
+
+	// Upgrades after a scheduled one can't activate before it
+	var floor time.Time
+	for i, t := range times {
+		if !explicit[i] && t.time.Before(floor) {
+			*t.time = floor
+		}
+		if t.time.After(floor) {
+			floor = *t.time
+		}
+	}
+	// Upgrades before a scheduled one can't activate after it
+	ceiling := upgrade.UnscheduledActivationTime
+	for i := len(times) - 1; i >= 0; i-- {
+		t := times[i]
+		if !explicit[i] && t.time.After(ceiling) {
+			*t.time = ceiling
+		}
+		if t.time.Before(ceiling) {
+			ceiling = *t.time
+		}
+	}
+	if err := config.Validate(); err != nil {
+		return upgrade.Config{}, err
+	}
+	return config, nil
+}
+
+func indexOf(times []upgradeTime, name string) int {
+	for i, t := range times {
+		if t.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// ParseActivation parses an activation time: genesis, never, RFC3339, unix
+// seconds or +<duration> relative to now
+func ParseActivation(value string, now time.Time) (time.Time, error) {
+	switch {
+	case value == Genesis:
+		return upgrade.InitiallyActiveTime, nil
+	case value == Never:
+		return upgrade.UnscheduledActivationTime, nil
+	case strings.HasPrefix(value, "+"):
+		d, err := time.ParseDuration(value[1:])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid relative time %q: %w", value, err)
+		}
+		return now.Add(d).UTC().Truncate(time.Second), nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: expected genesis, never, RFC3339, unix seconds or +<duration>", value)
+	}
+	return t.UTC(), nil
+}
+
+// EncodeNetworkUpgrades encodes an upgrade schedule for the node
+// upgrade-file-content flag
+func EncodeNetworkUpgrades(config upgrade.Config) (string, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package upgradebundle
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/luxfi/upgrade"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkUpgrades(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC)
+	b, err := Parse([]byte(`{
+		"network": {"durango": "genesis", "etnaTime": "+10m", "granite": "never"},
+		"chains": {"mychain": {"precompileUpgrades": []}}
+	}`))
+	require.NoError(t, err)
+
+	config, err := b.NetworkUpgrades(now)
+	require.NoError(t, err)
+	require.Equal(t, upgrade.InitiallyActiveTime, config.DurangoTime)
+	require.Equal(t, now.Add(10*time.Minute), config.EtnaTime)
+	// Fortuna follows etna so it can't activate before it
+	require.Equal(t, config.EtnaTime, config.FortunaTime)
+	require.Equal(t, upgrade.UnscheduledActivationTime, config.GraniteTime)
+
+	activations := Activations(config)
+	require.Len(t, activations, 14)
+	etna := activations[11]
+	require.Equal(t, "etna", etna.Name)
+	require.False(t, etna.Live(now))
+	require.True(t, etna.Live(now.Add(10*time.Minute)))
+	require.True(t, etna.Scheduled())
+	require.Equal(t, "pending (in 10m0s)", etna.Status(now))
+	require.Equal(t, "live", etna.Status(now.Add(time.Hour)))
+	require.Equal(t, "unscheduled", activations[13].Status(now))
+
+	encoded, err := EncodeNetworkUpgrades(config)
+	require.NoError(t, err)
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	require.NoError(t, err)
+	var decoded upgrade.Config
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, config.EtnaTime, decoded.EtnaTime)
+}
+
+func TestParseInvalid(t *testing.T) {
+	for name, bundle := range map[string]string{
+		"empty":           `{}`,
+		"unknown field":   `{"netwrok": {"etna": "genesis"}}`,
+		"unknown upgrade": `{"network": {"moon": "genesis"}}`,
+		"bad time":        `{"network": {"etna": "tomorrow"}}`,
+		"out of order":    `{"network": {"etna": "+1h", "fortuna": "+10m"}}`,
+		"chain not json":  `{"chains": {"mychain": 12}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(bundle))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseActivation(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	for value, expected := range map[string]time.Time{
+		"genesis":              upgrade.InitiallyActiveTime,
+		"never":                upgrade.UnscheduledActivationTime,
+		"+90s":                 now.Add(90 * time.Second).UTC(),
+		"1767225600":           time.Unix(1767225600, 0).UTC(),
+		"2026-01-01T00:00:00Z": time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		got, err := ParseActivation(value, now)
+		require.NoError(t, err, value)
+		require.Equal(t, expected, got, value)
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package upgraderollout rolls chain upgrade files (upgrade.json) out to
+// running nodes: it schedules the activation of new precompile upgrades,
+// restarts the nodes one at a time and checks over RPC that every node
+// loaded and activated them.
+package upgraderollout
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/luxfi/geth/common/hexutil"
+	"github.com/luxfi/geth/rpc"
+)
+
+const (
+	precompileUpgradesKey = "precompileUpgrades"
+	blockTimestampKey     = "blockTimestamp"
+	disableKey            = "disable"
+)
+
+var errNoUpgrades = errors.New("no precompile upgrades in upgrade file")
+
+// Upgrade is a precompile upgrade of an upgrade file
+type Upgrade struct {
+	// Key is the config key of the precompile, e.g. feeManagerConfig
+	Key       string
+	Timestamp uint64
+	Disabled  bool
+}
+
+func (u Upgrade) String() string {
+	action := "enable"
+	if u.Disabled {
+		action = "disable"
+	}
+	return fmt.Sprintf("%s %s at %s", action, u.Key, time.Unix(int64(u.Timestamp), 0).UTC().Format(time.RFC3339)) //nolint:gosec // G115: timestamps fit in int64
+}
+
+// ParseUpgrades returns the precompile upgrades of an upgrade file
+func ParseUpgrades(file []byte) ([]Upgrade, error) {
+	var config struct {
+		PrecompileUpgrades []json.RawMessage `json:"precompileUpgrades"`
+	}
+	if err := json.Unmarshal(file, &config); err != nil {
+		return nil, fmt.Errorf("invalid upgrade file: %w", err)
+	}
+	return parseUpgrades(config.PrecompileUpgrades)
+}
+
+func parseUpgrades(entries []json.RawMessage) ([]Upgrade, error) {
+	upgrades := make([]Upgrade, 0, len(entries))
+	for i, entry := range entries {
+		var byKey map[string]struct {
+			BlockTimestamp *uint64 `json:"blockTimestamp"`
+			Disable        bool    `json:"disable"`
+		}
+		if err := json.Unmarshal(entry, &byKey); err != nil {
+			return nil, fmt.Errorf("invalid precompile upgrade %d: %w", i, err)
+		}
+		if len(byKey) != 1 {
+			return nil, fmt.Errorf("precompile upgrade %d must configure exactly one precompile", i)
+		}
+		for key, config := range byKey {
+			if config.BlockTimestamp == nil {
+				return nil, fmt.Errorf("precompile upgrade %d (%s) has no %s", i, key, blockTimestampKey)
+			}
+			upgrades = append(upgrades, Upgrade{Key: key, Timestamp: *config.BlockTimestamp, Disabled: config.Disable})
+		}
+	}
+	return upgrades, nil
+}
+
+// Schedule sets the activation time of the precompile upgrades of file to
+// at, skipping the first locked ones, which the chain already applied
+func Schedule(file []byte, locked int, at time.Time) ([]byte, error) {
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal(file, &config); err != nil {
+		return nil, fmt.Errorf("invalid upgrade file: %w", err)
+	}
+	var entries []map[string]map[string]json.RawMessage
+	if raw, ok := config[precompileUpgradesKey]; ok {
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", precompileUpgradesKey, err)
+		}
+	}
+	if locked >= len(entries) {
+		return nil, errNoUpgrades
+	}
+	timestamp, err := json.Marshal(at.Unix())
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries[locked:] {
+		for _, precompile := range entry {
+			precompile[blockTimestampKey] = timestamp
+		}
+	}
+	if config[precompileUpgradesKey], err = json.Marshal(entries); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(config, "", "  ")
+}
+
+// NodeStatus is how far a node got with the upgrades of a file
+type NodeStatus struct {
+	// Missing upgrades are not in the chain config of the node, or did not
+	// take effect once due
+	Missing []Upgrade
+	// Active upgrades are loaded and in effect at the last accepted block
+	Active []Upgrade
+	// Pending upgrades are loaded and activate later
+	Pending []Upgrade
+}
+
+// Loaded reports whether the node loaded every upgrade
+func (s NodeStatus) Loaded() bool {
+	return len(s.Missing) == 0
+}
+
+// Check asks the chain RPC at rpcURL which of upgrades it loaded and
+// activated
+func Check(ctx context.Context, rpcURL string, upgrades []Upgrade) (NodeStatus, error) {
+	client, err := rpc.DialContext(ctx, rpcURL)
+	if err != nil {
+		return NodeStatus{}, err
+	}
+	defer client.Close()
+
+	var chainConfig struct {
+		Upgrades struct {
+			PrecompileUpgrades []json.RawMessage `json:"precompileUpgrades"`
+		} `json:"upgrades"`
+	}
+	if err := client.CallContext(ctx, &chainConfig, "eth_getChainConfig"); err != nil {
+		return NodeStatus{}, fmt.Errorf("failed to get chain config: %w", err)
+	}
+	loaded, err := parseUpgrades(chainConfig.Upgrades.PrecompileUpgrades)
+	if err != nil {
+		return NodeStatus{}, err
+	}
+	var head struct {
+		Time hexutil.Uint64 `json:"timestamp"`
+	}
+	if err := client.CallContext(ctx, &head, "eth_getBlockByNumber", "latest", false); err != nil {
+		return NodeStatus{}, fmt.Errorf("failed to get last block: %w", err)
+	}
+	var active map[string]json.RawMessage
+	if err := client.CallContext(ctx, &active, "eth_getActivePrecompilesAt", nil); err != nil {
+		return NodeStatus{}, fmt.Errorf("failed to get active precompiles: %w", err)
+	}
+	return status(upgrades, loaded, uint64(head.Time), active), nil
+}
+
+func status(upgrades, loaded []Upgrade, headTime uint64, active map[string]json.RawMessage) NodeStatus {
+	// only the last due upgrade of a precompile decides whether it is active
+	last := map[string]int{}
+	for i, u := range upgrades {
+		if u.Timestamp <= headTime {
+			last[u.Key] = i
+		}
+	}
+	var s NodeStatus
+	for i, u := range upgrades {
+		switch {
+		case !contains(loaded, u):
+			s.Missing = append(s.Missing, u)
+		case u.Timestamp > headTime:
+			s.Pending = append(s.Pending, u)
+		case last[u.Key] == i && u.Disabled == isActive(active, u.Key):
+			s.Missing = append(s.Missing, u)
+		default:
+			s.Active = append(s.Active, u)
+		}
+	}
+	return s
+}
+
+func contains(upgrades []Upgrade, u Upgrade) bool {
+	for _, loaded := range upgrades {
+		if loaded == u {
+			return true
+		}
+	}
+	return false
+}
+
+func isActive(active map[string]json.RawMessage, key string) bool {
+	_, ok := active[key]
+	return ok
+}
+
+// Node is a node to roll an upgrade file out to
+type Node interface {
+	Name() string
+	// Install writes the upgrade file where the node reads it on start
+	Install(file []byte) error
+	Restart(ctx context.Context) error
+	Healthy(ctx context.Context) (bool, error)
+	// RPCURL is the RPC endpoint of the chain on the node
+	RPCURL() string
+}
+
+// Options tune a rollout
+type Options struct {
+	// HealthTimeout bounds the wait for a restarted node to be healthy and
+	// to have loaded the upgrades
+	HealthTimeout time.Duration
+	// PollInterval is the time between health checks
+	PollInterval time.Duration
+	// Progress, when set, reports each step of the rollout
+	Progress func(node, step string)
+}
+
+// Rollout installs file on every node, then restarts the nodes one at a
+// time, waiting for each to be healthy and to have loaded the upgrades
+// before restarting the next, so the chain keeps validating throughout. It
+// stops at the first node that fails, leaving the others untouched.
+func Rollout(ctx context.Context, nodes []Node, file []byte, opts Options) (map[string]NodeStatus, error) {
+	upgrades, err := ParseUpgrades(file)
+	if err != nil {
+		return nil, err
+	}
+	if len(upgrades) == 0 {
+		return nil, errNoUpgrades
+	}
+	progress := opts.Progress
+	if progress == nil {
+		progress = func(string, string) {}
+	}
+	for _, node := range nodes {
+		progress(node.Name(), "installing upgrade file")
+		if err := node.Install(file); err != nil {
+			return nil, fmt.Errorf("failed to install upgrade file on %s: %w", node.Name(), err)
+		}
+	}
+	statuses := make(map[string]NodeStatus, len(nodes))
+	for _, node := range nodes {
+		progress(node.Name(), "restarting")
+		if err := node.Restart(ctx); err != nil {
+			return statuses, fmt.Errorf("failed to restart %s: %w", node.Name(), err)
+		}
+		progress(node.Name(), "waiting for the node to be healthy")
+		s, err := waitLoaded(ctx, node, upgrades, opts)
+		if err != nil {
+			return statuses, fmt.Errorf("rollout halted at %s: %w", node.Name(), err)
+		}
+		statuses[node.Name()] = s
+		progress(node.Name(), "upgrades loaded")
+	}
+	return statuses, nil
+}
+
+// waitLoaded waits for node to be healthy with every upgrade loaded
+func waitLoaded(ctx context.Context, node Node, upgrades []Upgrade, opts Options) (NodeStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.HealthTimeout)
+	defer cancel()
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+	var lastErr error
+	for {
+		healthy, err := node.Healthy(ctx)
+		switch {
+		case err != nil:
+			lastErr = err
+		case !healthy:
+			lastErr = errors.New("not healthy")
+		default:
+			s, err := Check(ctx, node.RPCURL(), upgrades)
+			switch {
+			case err != nil:
+				lastErr = err
+			case !s.Loaded():
+				return s, fmt.Errorf("upgrades not loaded: %s", joinUpgrades(s.Missing))
+			default:
+				return s, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return NodeStatus{}, fmt.Errorf("%w: %w", ctx.Err(), lastErr)
+		case <-ticker.C:
+		}
+	}
+}
+
+func joinUpgrades(upgrades []Upgrade) string {
+	s := make([]string, len(upgrades))
+	for i, u := range upgrades {
+		s[i] = u.String()
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,208 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package upgraderollout
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/luxfi/geth/common/hexutil"
+	"github.com/luxfi/geth/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+const upgradeFile = `{
+  "precompileUpgrades": [
+    {"txAllowListConfig": {"blockTimestamp": 100, "adminAddresses": ["0xb794F5eA0ba39494cE839613fffBA74279579268"]}},
+    {"txAllowListConfig": {"blockTimestamp": 200, "disable": true}},
+    {"feeManagerConfig": {"blockTimestamp": 300, "initialFeeConfig": {}}}
+  ],
+  "stateUpgrades": []
+}`
+
+func TestParseUpgrades(t *testing.T) {
+	require := require.New(t)
+	upgrades, err := ParseUpgrades([]byte(upgradeFile))
+	require.NoError(err)
+	require.Equal([]Upgrade{
+		{Key: "txAllowListConfig", Timestamp: 100},
+		{Key: "txAllowListConfig", Timestamp: 200, Disabled: true},
+		{Key: "feeManagerConfig", Timestamp: 300},
+	}, upgrades)
+
+	_, err = ParseUpgrades([]byte(`{"precompileUpgrades": [{"feeManagerConfig": {}}]}`))
+	require.ErrorContains(err, "has no blockTimestamp")
+	_, err = ParseUpgrades([]byte(`{"precompileUpgrades": [{}]}`))
+	require.ErrorContains(err, "exactly one precompile")
+}
+
+func TestSchedule(t *testing.T) {
+	require := require.New(t)
+	at := time.Unix(1767225600, 0)
+	scheduled, err := Schedule([]byte(upgradeFile), 1, at)
+	require.NoError(err)
+
+	upgrades, err := ParseUpgrades(scheduled)
+	require.NoError(err)
+	require.Equal([]Upgrade{
+		{Key: "txAllowListConfig", Timestamp: 100},
+		{Key: "txAllowListConfig", Timestamp: 1767225600, Disabled: true},
+		{Key: "feeManagerConfig", Timestamp: 1767225600},
+	}, upgrades)
+	require.Contains(string(scheduled), `"stateUpgrades"`)
+	require.Contains(string(scheduled), `"adminAddresses"`)
+
+	_, err = Schedule([]byte(upgradeFile), 3, at)
+	require.ErrorIs(err, errNoUpgrades)
+}
+
+// fakeChain serves the eth RPC methods Check calls
+type fakeChain struct {
+	upgrades []json.RawMessage
+	headTime uint64
+	active   map[string]json.RawMessage
+}
+
+func (c *fakeChain) GetChainConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"chainId":  1,
+		"upgrades": map[string]interface{}{"precompileUpgrades": c.upgrades},
+	}
+}
+
+func (c *fakeChain) GetBlockByNumber(string, bool) map[string]interface{} {
+	return map[string]interface{}{"timestamp": hexutil.Uint64(c.headTime)}
+}
+
+func (c *fakeChain) GetActivePrecompilesAt(*uint64) map[string]json.RawMessage {
+	return c.active
+}
+
+func serveChain(t *testing.T, chain *fakeChain) string {
+	t.Helper()
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", chain))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	return httpServer.URL
+}
+
+func rawUpgrades(t *testing.T, file string) []json.RawMessage {
+	t.Helper()
+	var config struct {
+		PrecompileUpgrades []json.RawMessage `json:"precompileUpgrades"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(file), &config))
+	return config.PrecompileUpgrades
+}
+
+func TestCheck(t *testing.T) {
+	require := require.New(t)
+	upgrades, err := ParseUpgrades([]byte(upgradeFile))
+	require.NoError(err)
+
+	// allow list enabled then disabled, fee manager pending
+	chain := &fakeChain{
+		upgrades: rawUpgrades(t, upgradeFile),
+		headTime: 250,
+		active:   map[string]json.RawMessage{},
+	}
+	url := serveChain(t, chain)
+	s, err := Check(context.Background(), url, upgrades)
+	require.NoError(err)
+	require.True(s.Loaded())
+	require.Equal(upgrades[:2], s.Active)
+	require.Equal(upgrades[2:], s.Pending)
+
+	// the disable is due but the precompile is still active
+	chain.active = map[string]json.RawMessage{"txAllowListConfig": json.RawMessage(`{}`)}
+	s, err = Check(context.Background(), url, upgrades)
+	require.NoError(err)
+	require.Equal(upgrades[1:2], s.Missing)
+
+	// the node runs without the upgrade file
+	chain.upgrades = nil
+	s, err = Check(context.Background(), url, upgrades)
+	require.NoError(err)
+	require.False(s.Loaded())
+	require.Equal(upgrades, s.Missing)
+}
+
+// fakeNode loads the upgrade file into its chain when restarted
+type fakeNode struct {
+	name      string
+	chain     *fakeChain
+	url       string
+	installed []byte
+	restarts  int
+	log       *[]string
+	loads     bool
+}
+
+func (n *fakeNode) Name() string   { return n.name }
+func (n *fakeNode) RPCURL() string { return n.url }
+
+func (n *fakeNode) Install(file []byte) error {
+	n.installed = file
+	*n.log = append(*n.log, "install "+n.name)
+	return nil
+}
+
+func (n *fakeNode) Restart(context.Context) error {
+	n.restarts++
+	*n.log = append(*n.log, "restart "+n.name)
+	if n.loads {
+		var config struct {
+			PrecompileUpgrades []json.RawMessage `json:"precompileUpgrades"`
+		}
+		if err := json.Unmarshal(n.installed, &config); err != nil {
+			return err
+		}
+		n.chain.upgrades = config.PrecompileUpgrades
+	}
+	return nil
+}
+
+func (n *fakeNode) Healthy(context.Context) (bool, error) {
+	if n.restarts == 0 {
+		return false, errors.New("not restarted")
+	}
+	return true, nil
+}
+
+func TestRollout(t *testing.T) {
+	require := require.New(t)
+	var log []string
+	newNode := func(i int, loads bool) *fakeNode {
+		chain := &fakeChain{headTime: 50}
+		return &fakeNode{name: fmt.Sprintf("node%d", i), chain: chain, url: serveChain(t, chain), log: &log, loads: loads}
+	}
+	opts := Options{HealthTimeout: time.Second, PollInterval: 10 * time.Millisecond}
+
+	nodes := []Node{newNode(1, true), newNode(2, true)}
+	statuses, err := Rollout(context.Background(), nodes, []byte(upgradeFile), opts)
+	require.NoError(err)
+	require.Equal([]string{"install node1", "install node2", "restart node1", "restart node2"}, log)
+	require.Len(statuses, 2)
+	require.Len(statuses["node2"].Pending, 3)
+
+	// a node that comes back without the upgrades halts the rollout
+	log = nil
+	nodes = []Node{newNode(1, false), newNode(2, true)}
+	statuses, err = Rollout(context.Background(), nodes, []byte(upgradeFile), opts)
+	require.ErrorContains(err, "rollout halted at node1: upgrades not loaded")
+	require.Empty(statuses)
+	require.Equal([]string{"install node1", "install node2", "restart node1"}, log)
+
+	_, err = Rollout(context.Background(), nodes, []byte(`{"precompileUpgrades": []}`), opts)
+	require.ErrorIs(err, errNoUpgrades)
+}
@@ -20,7 +20,6 @@ import (
 	"slices"
 	"strconv"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/luxfi/constants"
@@ -397,11 +396,23 @@ func GetChainID(endpoint string, chainName string) (ids.ID, error) {
 	return client.GetBlockchainID(ctx, chainName)
 }
 
+// blockchainsCache holds platform.getBlockchains results per endpoint
+var blockchainsCache = NewTTLCache[string, []platformvm.APIBlockchain](PChainCacheTTL)
+
+// GetBlockchains returns the blockchains known to the P-Chain at endpoint.
+// Results are cached for PChainCacheTTL, each caller gets its own copy.
+func GetBlockchains(endpoint string) ([]platformvm.APIBlockchain, error) {
+	blockchains, err := blockchainsCache.GetOrLoad(endpoint, func() ([]platformvm.APIBlockchain, error) {
+		pClient := platformvm.NewClient(endpoint)
+		ctx, cancel := GetAPIContext()
+		defer cancel()
+		return pClient.GetBlockchains(ctx)
+	})
+	return slices.Clone(blockchains), err
+}
+
 func GetChainIDs(endpoint string, chainName string) (string, string, error) {
-	pClient := platformvm.NewClient(endpoint)
-	ctx, cancel := GetAPIContext()
-	defer cancel()
-	blockChains, err := pClient.GetBlockchains(ctx)
+	blockChains, err := GetBlockchains(endpoint)
 	if err != nil {
 		return "", "", err
 	}
@@ -599,9 +610,9 @@ func GetProcess(pid int) (*os.Process, error) {
 	if err != nil {
 		return nil, err
 	}
-	if err := proc.Signal(syscall.Signal(0)); err != nil {
-		// sometimes FindProcess returns without error, but Signal 0 will surely fail if the process doesn't exist
-		return nil, err
+	if !IsProcessRunning(pid) {
+		// FindProcess succeeds for any PID on Unix, check the process actually exists
+		return nil, fmt.Errorf("process %d is not running", pid)
 	}
 	return proc, nil
 }
@@ -0,0 +1,58 @@
+// Copyright (C) 2022-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteDirLink atomically points linkPath at target, a directory given
+// relative to the link's parent or as an absolute path. It creates a symlink
+// where possible; on systems where that fails (Windows without developer mode
+// or admin rights) it writes a plain file holding the target instead.
+// ReadDirLink understands both forms.
+func WriteDirLink(linkPath, target string) error {
+	tmpLink := filepath.Join(filepath.Dir(linkPath), "."+filepath.Base(linkPath)+"_tmp")
+	_ = os.Remove(tmpLink)
+
+	if err := os.Symlink(target, tmpLink); err != nil {
+		if err := os.WriteFile(tmpLink, []byte(target+"\n"), 0o600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", tmpLink, err)
+		}
+	}
+
+	// Renaming over a symlinked directory fails on Windows, remove it first
+	_ = os.Remove(linkPath)
+	if err := os.Rename(tmpLink, linkPath); err != nil {
+		return fmt.Errorf("failed to update %s: %w", linkPath, err)
+	}
+	return nil
+}
+
+// ReadDirLink returns the absolute directory linkPath points to, whether it
+// was written as a symlink or as a link file by WriteDirLink.
+func ReadDirLink(linkPath string) (string, error) {
+	target, err := os.Readlink(linkPath)
+	if err != nil {
+		info, statErr := os.Lstat(linkPath)
+		if statErr != nil || !info.Mode().IsRegular() {
+			return "", err
+		}
+		data, readErr := os.ReadFile(linkPath) //nolint:gosec // G304: Reading link files from app's directory
+		if readErr != nil {
+			return "", readErr
+		}
+		target = strings.TrimSpace(string(data))
+		if target == "" {
+			return "", fmt.Errorf("empty link file %s", linkPath)
+		}
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(linkPath), target)
+	}
+	return target, nil
+}
@@ -6,6 +6,7 @@ package utils
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -168,13 +169,13 @@ func SizeInKB(path string) (int64, error) {
 
 // RemoteComposeFile returns the path to the remote docker-compose file
 func GetRemoteComposeFile() string {
-	return filepath.Join(constants.CloudNodeCLIConfigBasePath, "services", "docker-compose.yml")
+	return path.Join(constants.CloudNodeCLIConfigBasePath, "services", "docker-compose.yml")
 }
 
 // GetRemoteComposeServicePath returns the path to the remote service directory
 func GetRemoteComposeServicePath(serviceName string, dirs ...string) string {
-	servicePrefix := filepath.Join(constants.CloudNodeCLIConfigBasePath, "services", serviceName)
-	return filepath.Join(append([]string{servicePrefix}, dirs...)...)
+	servicePrefix := path.Join(constants.CloudNodeCLIConfigBasePath, "services", serviceName)
+	return path.Join(append([]string{servicePrefix}, dirs...)...)
 }
 
 // ReadGoVersion reads the Go version from the go.mod file
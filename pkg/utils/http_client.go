@@ -0,0 +1,284 @@
+// Copyright (C) 2022-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// RPCRetriesEnvVar overrides the number of attempts made by RPC clients
+	RPCRetriesEnvVar = "LUX_RPC_RETRIES"
+
+	defaultRPCTimeout        = 5 * time.Second
+	defaultRPCAttempts       = 3
+	defaultRPCInitialBackoff = 250 * time.Millisecond
+	defaultRPCMaxBackoff     = 4 * time.Second
+	defaultBreakerThreshold  = 5
+	defaultBreakerCooldown   = 15 * time.Second
+)
+
+// ErrCircuitOpen is returned when a host has failed repeatedly and requests
+// to it are short-circuited until the cooldown expires.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// RetryPolicy controls how an RPCClient retries failed requests.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry; it doubles on every retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries
+	MaxBackoff time.Duration
+	// BreakerThreshold is the number of consecutive failures after which a host
+	// is short-circuited. Zero disables circuit breaking.
+	BreakerThreshold int
+	// BreakerCooldown is how long a tripped breaker stays open
+	BreakerCooldown time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used by the shared RPC client.
+// The number of attempts can be overridden with LUX_RPC_RETRIES.
+func DefaultRetryPolicy() RetryPolicy {
+	policy := RetryPolicy{
+		MaxAttempts:      defaultRPCAttempts,
+		InitialBackoff:   defaultRPCInitialBackoff,
+		MaxBackoff:       defaultRPCMaxBackoff,
+		BreakerThreshold: defaultBreakerThreshold,
+		BreakerCooldown:  defaultBreakerCooldown,
+	}
+	if v, err := strconv.Atoi(os.Getenv(RPCRetriesEnvVar)); err == nil && v > 0 {
+		policy.MaxAttempts = v
+	}
+	return policy
+}
+
+// ProbeRetryPolicy returns a policy for liveness probes, where a down node is an
+// expected answer and should not stall the caller for long.
+func ProbeRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = min(policy.MaxAttempts, 2)
+	policy.MaxBackoff = time.Second
+	return policy
+}
+
+// PollRetryPolicy returns a policy for requests made inside polling loops.
+// The loop already repeats the request, so breaking the circuit would only
+// hide a node that is still starting up.
+func PollRetryPolicy() RetryPolicy {
+	policy := ProbeRetryPolicy()
+	policy.BreakerThreshold = 0
+	return policy
+}
+
+// backoff returns the jittered delay before retry number attempt (1-based).
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff << (attempt - 1)
+	if d <= 0 || d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	// Full jitter in [d/2, d) avoids synchronized retries across nodes
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + time.Duration(rand.Int63n(int64(half))) //nolint:gosec // jitter does not need crypto rand
+}
+
+// circuitBreaker tracks consecutive failures for a single host.
+type circuitBreaker struct {
+	failures  int
+	openUntil time.Time
+}
+
+// breakerSet holds the circuit breakers of all hosts seen by a client.
+type breakerSet struct {
+	mu    sync.Mutex
+	hosts map[string]*circuitBreaker
+}
+
+// RPCClient is an HTTP client for node RPC calls that retries transient
+// failures with jittered exponential backoff and short-circuits hosts that
+// keep failing.
+type RPCClient struct {
+	client   *http.Client
+	policy   RetryPolicy
+	breakers *breakerSet
+}
+
+// NewRPCClient creates an RPCClient with the given per-attempt timeout and policy.
+func NewRPCClient(timeout time.Duration, policy RetryPolicy) *RPCClient {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	return &RPCClient{
+		client:   &http.Client{Timeout: timeout},
+		policy:   policy,
+		breakers: &breakerSet{hosts: make(map[string]*circuitBreaker)},
+	}
+}
+
+var (
+	sharedRPCClient     *RPCClient
+	sharedRPCClientOnce sync.Once
+)
+
+// DefaultRPCClient returns the process-wide RPC client. Sharing it lets the
+// circuit breaker state carry across calls to the same node.
+func DefaultRPCClient() *RPCClient {
+	sharedRPCClientOnce.Do(func() {
+		sharedRPCClient = NewRPCClient(defaultRPCTimeout, DefaultRetryPolicy())
+	})
+	return sharedRPCClient
+}
+
+// WithTimeout returns a client sharing this client's policy and breaker state
+// but using a different per-attempt timeout.
+func (c *RPCClient) WithTimeout(timeout time.Duration) *RPCClient {
+	return &RPCClient{
+		client:   &http.Client{Timeout: timeout, Transport: c.client.Transport},
+		policy:   c.policy,
+		breakers: c.breakers,
+	}
+}
+
+// WithTransport returns a client sharing this client's policy and breaker
+// state but sending requests through the given transport.
+func (c *RPCClient) WithTransport(transport http.RoundTripper) *RPCClient {
+	return &RPCClient{
+		client:   &http.Client{Timeout: c.client.Timeout, Transport: transport},
+		policy:   c.policy,
+		breakers: c.breakers,
+	}
+}
+
+// WithPolicy returns a client sharing this client's breaker state but using a
+// different retry policy.
+func (c *RPCClient) WithPolicy(policy RetryPolicy) *RPCClient {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	return &RPCClient{
+		client:   c.client,
+		policy:   policy,
+		breakers: c.breakers,
+	}
+}
+
+// Do sends the request, retrying on network errors, 429 and 5xx responses.
+// Requests with a body must be replayable (req.GetBody set), which is the case
+// for bodies created from bytes.Buffer, bytes.Reader or strings.Reader.
+func (c *RPCClient) Do(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	if err := c.allow(host); err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= c.policy.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			if req.Body != nil && req.GetBody == nil {
+				break
+			}
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				req.Body = body
+			}
+			select {
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			case <-time.After(c.policy.backoff(attempt - 1)):
+			}
+		}
+
+		resp, err := c.client.Do(req)
+		if err == nil && !retryableStatus(resp.StatusCode) {
+			c.recordSuccess(host)
+			return resp, nil
+		}
+		if err == nil {
+			lastErr = fmt.Errorf("%s returned %s", req.URL.Redacted(), resp.Status)
+			if attempt == c.policy.MaxAttempts {
+				c.recordFailure(host)
+				return resp, nil
+			}
+			_ = resp.Body.Close()
+		} else {
+			lastErr = err
+			if req.Context().Err() != nil {
+				return nil, err
+			}
+		}
+	}
+
+	c.recordFailure(host)
+	return nil, lastErr
+}
+
+// Get issues a GET request with retries.
+func (c *RPCClient) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+func (c *RPCClient) allow(host string) error {
+	if c.policy.BreakerThreshold <= 0 {
+		return nil
+	}
+	c.breakers.mu.Lock()
+	defer c.breakers.mu.Unlock()
+	b, ok := c.breakers.hosts[host]
+	if !ok || b.failures < c.policy.BreakerThreshold {
+		return nil
+	}
+	if time.Now().Before(b.openUntil) {
+		return fmt.Errorf("%w for %s", ErrCircuitOpen, host)
+	}
+	// Cooldown elapsed: let a single trial request through (half-open)
+	b.failures = c.policy.BreakerThreshold - 1
+	return nil
+}
+
+func (c *RPCClient) recordSuccess(host string) {
+	if c.policy.BreakerThreshold <= 0 {
+		return
+	}
+	c.breakers.mu.Lock()
+	defer c.breakers.mu.Unlock()
+	delete(c.breakers.hosts, host)
+}
+
+func (c *RPCClient) recordFailure(host string) {
+	if c.policy.BreakerThreshold <= 0 {
+		return
+	}
+	c.breakers.mu.Lock()
+	defer c.breakers.mu.Unlock()
+	b, ok := c.breakers.hosts[host]
+	if !ok {
+		b = &circuitBreaker{}
+		c.breakers.hosts[host] = b
+	}
+	b.failures++
+	if b.failures >= c.policy.BreakerThreshold {
+		b.openUntil = time.Now().Add(c.policy.BreakerCooldown)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright (C) 2022-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testRetryPolicy retries quickly and never opens the breaker
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+	}
+}
+
+func TestRPCClientRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		responses    []int
+		wantAttempts int32
+		wantStatus   int
+		wantErr      string
+	}{
+		{
+			name:         "success",
+			responses:    []int{http.StatusOK},
+			wantAttempts: 1,
+			wantStatus:   http.StatusOK,
+		},
+		{
+			name:         "retry 5xx until success",
+			responses:    []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK},
+			wantAttempts: 3,
+			wantStatus:   http.StatusOK,
+		},
+		{
+			name:         "retry 429",
+			responses:    []int{http.StatusTooManyRequests, http.StatusOK},
+			wantAttempts: 2,
+			wantStatus:   http.StatusOK,
+		},
+		{
+			name:         "last 5xx is returned",
+			responses:    []int{http.StatusServiceUnavailable},
+			wantAttempts: 3,
+			wantStatus:   http.StatusServiceUnavailable,
+		},
+		{
+			name:         "no retry on 4xx",
+			responses:    []int{http.StatusNotFound, http.StatusOK},
+			wantAttempts: 1,
+			wantStatus:   http.StatusNotFound,
+		},
+		{
+			// a status of 0 makes the handler stall past the client timeout
+			name:         "retry timeout",
+			responses:    []int{0, http.StatusOK},
+			wantAttempts: 2,
+			wantStatus:   http.StatusOK,
+		},
+		{
+			name:         "timeouts exhaust attempts",
+			responses:    []int{0},
+			wantAttempts: 3,
+			wantErr:      "Client.Timeout",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(attempts.Add(1))
+				status := tt.responses[min(n, len(tt.responses))-1]
+				if status == 0 {
+					select {
+					case <-r.Context().Done():
+					case <-time.After(time.Second):
+					}
+					return
+				}
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			client := NewRPCClient(50*time.Millisecond, testRetryPolicy())
+			resp, err := client.Get(server.URL)
+			if tt.wantErr != "" {
+				require.ErrorContains(err, tt.wantErr)
+			} else {
+				require.NoError(err)
+				defer resp.Body.Close()
+				require.Equal(tt.wantStatus, resp.StatusCode)
+			}
+			require.Equal(tt.wantAttempts, attempts.Load())
+		})
+	}
+}
+
+func TestRPCClientReplaysBody(t *testing.T) {
+	require := require.New(t)
+	var attempts atomic.Int32
+	bodies := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- string(body)
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"id":1}`))
+	require.NoError(err)
+	resp, err := NewRPCClient(time.Second, testRetryPolicy()).Do(req)
+	require.NoError(err)
+	defer resp.Body.Close()
+	require.Equal(http.StatusOK, resp.StatusCode)
+	require.Equal(`{"id":1}`, <-bodies)
+	require.Equal(`{"id":1}`, <-bodies)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	tests := []struct {
+		attempt int
+		ceiling time.Duration
+	}{
+		{attempt: 1, ceiling: 100 * time.Millisecond},
+		{attempt: 2, ceiling: 200 * time.Millisecond},
+		{attempt: 4, ceiling: 800 * time.Millisecond},
+		{attempt: 5, ceiling: time.Second},
+		// the shift overflows
+		{attempt: 80, ceiling: time.Second},
+	}
+	for _, tt := range tests {
+		for range 100 {
+			d := policy.backoff(tt.attempt)
+			require.GreaterOrEqual(t, d, tt.ceiling/2, "attempt %d", tt.attempt)
+			require.Less(t, d, tt.ceiling, "attempt %d", tt.attempt)
+		}
+	}
+}
+
+func TestRPCClientBreaker(t *testing.T) {
+	require := require.New(t)
+	var attempts atomic.Int32
+	var status atomic.Int32
+	status.Store(http.StatusInternalServerError)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+
+	policy := testRetryPolicy()
+	policy.MaxAttempts = 1
+	policy.BreakerThreshold = 2
+	policy.BreakerCooldown = 50 * time.Millisecond
+	client := NewRPCClient(time.Second, policy)
+	get := func() error {
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	// two failures open the breaker
+	require.NoError(get())
+	require.NoError(get())
+	require.ErrorIs(get(), ErrCircuitOpen)
+	require.Equal(int32(2), attempts.Load())
+
+	// after the cooldown a single trial goes through, its failure reopens it
+	time.Sleep(60 * time.Millisecond)
+	require.NoError(get())
+	require.Equal(int32(3), attempts.Load())
+	require.ErrorIs(get(), ErrCircuitOpen)
+
+	// a successful trial closes it
+	status.Store(http.StatusOK)
+	time.Sleep(60 * time.Millisecond)
+	require.NoError(get())
+	require.NoError(get())
+	require.Equal(int32(5), attempts.Load())
+
+	// a zero threshold disables the breaker
+	status.Store(http.StatusInternalServerError)
+	client = client.WithPolicy(testRetryPolicy())
+	for range 3 {
+		require.NoError(get())
+	}
+}
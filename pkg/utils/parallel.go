@@ -0,0 +1,38 @@
+// Copyright (C) 2022-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DefaultParallelism is how many chains commands work on at once
+const DefaultParallelism = 8
+
+// ForEachChain runs work on each of chains, at most parallelism at once (one
+// at a time when lower than 1), and waits for all of them. work gets the
+// index of its chain, to fill results in order. The failures are joined in
+// the order of chains, each prefixed with the name of its chain.
+func ForEachChain[T any](chains []T, parallelism int, name func(T) string, work func(int, T) error) error {
+	errs := make([]error, len(chains))
+	sem := make(chan struct{}, max(parallelism, 1))
+	var wg sync.WaitGroup
+	for i, c := range chains {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := work(i, c); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", name(c), err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
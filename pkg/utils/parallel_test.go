@@ -0,0 +1,43 @@
+// Copyright (C) 2022-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestForEachChain(t *testing.T) {
+	require := require.New(t)
+	chains := []string{"a", "b", "c", "d", "e"}
+	errFailed := errors.New("failed")
+	var running, peak atomic.Int32
+	results := make([]string, len(chains))
+	err := ForEachChain(chains, 2, func(c string) string { return c }, func(i int, c string) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		results[i] = c + c
+		if c == "b" || c == "d" {
+			return errFailed
+		}
+		return nil
+	})
+	require.ErrorIs(err, errFailed)
+	require.Equal("b: failed\nd: failed", err.Error())
+	require.Equal([]string{"aa", "bb", "cc", "dd", "ee"}, results)
+	require.LessOrEqual(peak.Load(), int32(2))
+
+	require.NoError(ForEachChain(chains, 0, func(c string) string { return c }, func(int, string) error { return nil }))
+}
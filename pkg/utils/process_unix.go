@@ -0,0 +1,35 @@
+// Copyright (C) 2022-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build !windows
+
+package utils
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// IsProcessRunning checks if a process with the given PID is running
+func IsProcessRunning(pid int) bool {
+	// Sending signal 0 checks if we can signal the process
+	// without actually sending a signal
+	return syscall.Kill(pid, 0) == nil
+}
+
+// DetachProcess starts cmd in its own process group, so signals sent to the
+// CLI (e.g. Ctrl-C) do not reach it.
+func DetachProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// InterruptProcess asks proc to shut down gracefully with SIGINT.
+func InterruptProcess(proc *os.Process) error {
+	return proc.Signal(os.Interrupt)
+}
+
+// TerminateProcess asks proc to shut down gracefully with SIGTERM.
+func TerminateProcess(proc *os.Process) error {
+	return proc.Signal(syscall.SIGTERM)
+}
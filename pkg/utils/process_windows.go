@@ -0,0 +1,50 @@
+// Copyright (C) 2022-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build windows
+
+package utils
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// stillActive is the exit code GetExitCodeProcess reports for a running process
+const stillActive = 259
+
+// IsProcessRunning checks if a process with the given PID is running
+func IsProcessRunning(pid int) bool {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(handle) //nolint:errcheck
+
+	var exitCode uint32
+	if err := windows.GetExitCodeProcess(handle, &exitCode); err != nil {
+		return false
+	}
+	return exitCode == stillActive
+}
+
+// DetachProcess starts cmd in its own process group, so console Ctrl-C
+// events sent to the CLI do not reach it.
+func DetachProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// InterruptProcess stops proc. Windows cannot deliver os.Interrupt to another
+// process, so the process is killed.
+func InterruptProcess(proc *os.Process) error {
+	return proc.Kill()
+}
+
+// TerminateProcess stops proc. Windows has no SIGTERM, so the process is
+// killed.
+func TerminateProcess(proc *os.Process) error {
+	return proc.Kill()
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2022-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// DisableRPCCacheEnvVar turns off caching of P-Chain query results
+	DisableRPCCacheEnvVar = "LUX_DISABLE_RPC_CACHE"
+
+	// PChainCacheTTL is how long P-Chain query results are reused. It is short
+	// on purpose: long enough to dedupe repeated lookups within one command,
+	// short enough that state changed by that command is seen soon after.
+	PChainCacheTTL = 10 * time.Second
+)
+
+// errLoadPanicked is returned to the callers waiting on a load that panicked
+var errLoadPanicked = errors.New("cache load panicked")
+
+var (
+	registeredCachesMu sync.Mutex
+	registeredCaches   []interface{ Purge() }
+)
+
+// PurgeRPCCaches drops every entry of all caches created with NewTTLCache.
+// Commands call it after issuing transactions that change P-Chain state.
+func PurgeRPCCaches() {
+	registeredCachesMu.Lock()
+	defer registeredCachesMu.Unlock()
+	for _, c := range registeredCaches {
+		c.Purge()
+	}
+}
+
+type ttlEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// TTLCache is a concurrency-safe cache whose entries expire after a fixed TTL.
+// Concurrent loads of the same missing key are collapsed into one.
+//
+// Cached values are shared by every caller that gets them, so slices, maps
+// and pointers must be treated as read-only, or copied before modifying.
+type TTLCache[K comparable, V any] struct {
+	ttl      time.Duration
+	mu       sync.Mutex
+	entries  map[K]ttlEntry[V]
+	inflight map[K]*ttlLoad[V]
+}
+
+type ttlLoad[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// NewTTLCache creates a cache with the given TTL and registers it with
+// PurgeRPCCaches.
+func NewTTLCache[K comparable, V any](ttl time.Duration) *TTLCache[K, V] {
+	c := &TTLCache[K, V]{
+		ttl:      ttl,
+		entries:  make(map[K]ttlEntry[V]),
+		inflight: make(map[K]*ttlLoad[V]),
+	}
+	registeredCachesMu.Lock()
+	registeredCaches = append(registeredCaches, c)
+	registeredCachesMu.Unlock()
+	return c
+}
+
+// Get returns the cached value for key if present and not expired.
+func (c *TTLCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set stores value for key.
+func (c *TTLCache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = ttlEntry[V]{value: value, expires: time.Now().Add(c.ttl)}
+}
+
+// GetOrLoad returns the cached value for key, calling load on a miss. Errors
+// are returned to the caller and not cached. If load panics, the callers
+// waiting on it get an error and the next call loads again. Caching is
+// bypassed entirely when LUX_DISABLE_RPC_CACHE is set.
+func (c *TTLCache[K, V]) GetOrLoad(key K, load func() (V, error)) (V, error) {
+	if os.Getenv(DisableRPCCacheEnvVar) != "" {
+		return load()
+	}
+
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok && time.Now().Before(e.expires) {
+		c.mu.Unlock()
+		return e.value, nil
+	}
+	if l, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		<-l.done
+		return l.value, l.err
+	}
+	l := &ttlLoad[V]{done: make(chan struct{}), err: errLoadPanicked}
+	c.inflight[key] = l
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.inflight, key)
+		if l.err == nil {
+			c.entries[key] = ttlEntry[V]{value: l.value, expires: time.Now().Add(c.ttl)}
+		}
+		c.mu.Unlock()
+		close(l.done)
+	}()
+	l.value, l.err = load()
+	return l.value, l.err
+}
+
+// Invalidate removes key from the cache.
+func (c *TTLCache[K, V]) Invalidate(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// Purge removes all entries from the cache.
+func (c *TTLCache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[K]ttlEntry[V])
+}
@@ -0,0 +1,122 @@
+// Copyright (C) 2022-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTTLCacheExpiry(t *testing.T) {
+	require := require.New(t)
+	c := NewTTLCache[string, int](50 * time.Millisecond)
+	loads := 0
+	load := func() (int, error) {
+		loads++
+		return loads, nil
+	}
+
+	v, err := c.GetOrLoad("k", load)
+	require.NoError(err)
+	require.Equal(1, v)
+	v, err = c.GetOrLoad("k", load)
+	require.NoError(err)
+	require.Equal(1, v)
+
+	time.Sleep(60 * time.Millisecond)
+	_, ok := c.Get("k")
+	require.False(ok)
+	v, err = c.GetOrLoad("k", load)
+	require.NoError(err)
+	require.Equal(2, v)
+
+	PurgeRPCCaches()
+	v, err = c.GetOrLoad("k", load)
+	require.NoError(err)
+	require.Equal(3, v)
+}
+
+func TestTTLCacheSingleFlight(t *testing.T) {
+	require := require.New(t)
+	c := NewTTLCache[string, int](time.Minute)
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func() (int, error) {
+		loads.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	values := make([]int, 10)
+	for i := range values {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], _ = c.GetOrLoad("k", load)
+		}()
+	}
+	// let the callers queue up on the first load
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.Equal(int32(1), loads.Load())
+	for _, v := range values {
+		require.Equal(42, v)
+	}
+}
+
+func TestTTLCacheLoadError(t *testing.T) {
+	require := require.New(t)
+	c := NewTTLCache[string, int](time.Minute)
+	errLoad := errors.New("unreachable")
+
+	_, err := c.GetOrLoad("k", func() (int, error) { return 0, errLoad })
+	require.ErrorIs(err, errLoad)
+	_, ok := c.Get("k")
+	require.False(ok)
+
+	v, err := c.GetOrLoad("k", func() (int, error) { return 7, nil })
+	require.NoError(err)
+	require.Equal(7, v)
+}
+
+func TestTTLCacheLoadPanic(t *testing.T) {
+	require := require.New(t)
+	c := NewTTLCache[string, int](time.Minute)
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	panicked := make(chan any)
+	go func() {
+		defer func() { panicked <- recover() }()
+		_, _ = c.GetOrLoad("k", func() (int, error) {
+			close(started)
+			<-release
+			panic("boom")
+		})
+	}()
+	<-started
+
+	waiterErr := make(chan error)
+	go func() {
+		_, err := c.GetOrLoad("k", func() (int, error) { return 1, nil })
+		waiterErr <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	require.Equal("boom", <-panicked)
+	require.ErrorIs(<-waiterErr, errLoadPanicked)
+
+	v, err := c.GetOrLoad("k", func() (int, error) { return 2, nil })
+	require.NoError(err)
+	require.Equal(2, v)
+}
@@ -34,6 +34,17 @@ func NewUserLog(log luxlog.Logger, userwriter io.Writer) {
 	}
 }
 
+// SetWriter sends the messages printed to the user to w, until the returned
+// function restores the previous writer. Full screen views use it to show the
+// output of the operations they run without it drawing over them.
+func (ul *UserLog) SetWriter(w io.Writer) (restore func()) {
+	previous := ul.writer
+	ul.writer = w
+	return func() {
+		ul.writer = previous
+	}
+}
+
 // PrintToUser prints msg directly to stdout (command output)
 // Does NOT log to avoid duplication - logs should go to stderr separately
 func (ul *UserLog) PrintToUser(msg string, args ...interface{}) {
@@ -0,0 +1,167 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ux
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+// ProgressEventType is the kind of a ProgressEvent
+type ProgressEventType string
+
+const (
+	// ProgressStart is published when an operation starts
+	ProgressStart ProgressEventType = "start"
+	// ProgressStep is published when an operation reaches a step
+	ProgressStep ProgressEventType = "step"
+	// ProgressDone is published when an operation completes
+	ProgressDone ProgressEventType = "done"
+	// ProgressFailed is published when an operation fails
+	ProgressFailed ProgressEventType = "failed"
+)
+
+// ProgressEvent reports the progress of a long operation, such as a deploy
+// or a snapshot
+type ProgressEvent struct {
+	Type      ProgressEventType `json:"type"`
+	Operation string            `json:"operation"`
+	Step      string            `json:"step,omitempty"`
+	// Percent of the steps of the operation completed, from 0 to 100
+	Percent float64   `json:"percent"`
+	Message string    `json:"message,omitempty"`
+	Error   string    `json:"error,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// ProgressBus delivers the progress events of operations to subscribers.
+// Publishing without subscribers is a no-op, so operations report their
+// progress whether or not anything renders it.
+type ProgressBus struct {
+	mutex       sync.RWMutex
+	next        int
+	subscribers map[int]func(ProgressEvent)
+}
+
+// Progress is the bus the progress of the CLI operations is published on
+var Progress = NewProgressBus()
+
+func NewProgressBus() *ProgressBus {
+	return &ProgressBus{subscribers: map[int]func(ProgressEvent){}}
+}
+
+// Subscribe calls fn with every event published, until the returned cancel
+// function is called. fn is called from the publishing goroutine.
+func (b *ProgressBus) Subscribe(fn func(ProgressEvent)) (cancel func()) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	id := b.next
+	b.next++
+	b.subscribers[id] = fn
+	return func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		delete(b.subscribers, id)
+	}
+}
+
+// Publish delivers e to the subscribers
+func (b *ProgressBus) Publish(e ProgressEvent) {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	for _, fn := range b.subscribers {
+		fn(e)
+	}
+}
+
+// ProgressOperation publishes the progress of an operation of a known
+// number of steps
+type ProgressOperation struct {
+	bus   *ProgressBus
+	name  string
+	steps int
+	mutex sync.Mutex
+	// reached is the number of steps reached, all complete but the last
+	reached int
+}
+
+// Start publishes the start of the operation name, of the given number of
+// steps
+func (b *ProgressBus) Start(name string, steps int) *ProgressOperation {
+	op := &ProgressOperation{bus: b, name: name, steps: steps}
+	b.Publish(ProgressEvent{Type: ProgressStart, Operation: name})
+	return op
+}
+
+// Step publishes that the operation reached step, counting the previous
+// steps as complete. Steps past the expected number hold the percent below
+// 100 until Done.
+func (o *ProgressOperation) Step(step string, msg string, args ...interface{}) {
+	o.mutex.Lock()
+	percent := o.percent(o.reached)
+	o.reached++
+	o.mutex.Unlock()
+	o.bus.Publish(ProgressEvent{
+		Type:      ProgressStep,
+		Operation: o.name,
+		Step:      step,
+		Percent:   percent,
+		Message:   fmt.Sprintf(msg, args...),
+	})
+}
+
+// Done publishes the completion of the operation
+func (o *ProgressOperation) Done(msg string, args ...interface{}) {
+	o.bus.Publish(ProgressEvent{
+		Type:      ProgressDone,
+		Operation: o.name,
+		Percent:   100,
+		Message:   fmt.Sprintf(msg, args...),
+	})
+}
+
+// Fail publishes the failure of the operation with err, and returns err
+func (o *ProgressOperation) Fail(err error) error {
+	o.mutex.Lock()
+	percent := o.percent(o.reached - 1)
+	o.mutex.Unlock()
+	o.bus.Publish(ProgressEvent{
+		Type:      ProgressFailed,
+		Operation: o.name,
+		Percent:   percent,
+		Error:     err.Error(),
+	})
+	return err
+}
+
+// percent returns the percent of the steps of the operation that complete
+// steps are, rounded to a tenth
+func (o *ProgressOperation) percent(complete int) float64 {
+	if o.steps <= 0 || complete <= 0 {
+		return 0
+	}
+	if complete >= o.steps {
+		return 99
+	}
+	return math.Round(1000*float64(complete)/float64(o.steps)) / 10
+}
+
+// NewJSONProgressWriter returns a subscriber writing the events to w as
+// newline delimited JSON
+func NewJSONProgressWriter(w io.Writer) func(ProgressEvent) {
+	var mutex sync.Mutex
+	encoder := json.NewEncoder(w)
+	return func(e ProgressEvent) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		_ = encoder.Encode(e)
+	}
+}
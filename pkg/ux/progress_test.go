@@ -0,0 +1,82 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ux
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressOperation(t *testing.T) {
+	require := require.New(t)
+	bus := NewProgressBus()
+	var events []ProgressEvent
+	cancel := bus.Subscribe(func(e ProgressEvent) { events = append(events, e) })
+
+	op := bus.Start("chain deploy", 4)
+	op.Step("vm", "Setting up the VM")
+	op.Step("compatibility", "Checking the node version")
+	op.Step("chain", "Creating chain %s", "mychain")
+	op.Done("Deployed %s", "mychain")
+
+	require.Len(events, 5)
+	require.Equal(ProgressStart, events[0].Type)
+	require.Equal("chain deploy", events[0].Operation)
+	require.False(events[0].Time.IsZero())
+	percents := []float64{}
+	for _, e := range events[1:] {
+		percents = append(percents, e.Percent)
+	}
+	require.Equal([]float64{0, 25, 50, 100}, percents)
+	require.Equal("Creating chain mychain", events[3].Message)
+	require.Equal(ProgressDone, events[4].Type)
+
+	// steps past the expected number don't reach 100 before Done
+	op = bus.Start("snapshot create", 3)
+	for i := 0; i < 5; i++ {
+		op.Step("db", "")
+	}
+	require.Equal(99.0, events[len(events)-1].Percent)
+	require.ErrorIs(op.Fail(errNotFound), errNotFound)
+	require.Equal(ProgressFailed, events[len(events)-1].Type)
+	require.Equal("not found", events[len(events)-1].Error)
+
+	cancel()
+	bus.Start("chain deploy", 1)
+	require.Equal(ProgressFailed, events[len(events)-1].Type)
+}
+
+var errNotFound = errors.New("not found")
+
+func TestJSONProgressWriter(t *testing.T) {
+	require := require.New(t)
+	bus := NewProgressBus()
+	var out bytes.Buffer
+	bus.Subscribe(NewJSONProgressWriter(&out))
+
+	op := bus.Start("snapshot restore", 3)
+	op.Step("node1", "Restored node1")
+	op.Step("node2", "Restored node2")
+	op.Done("")
+
+	scanner := bufio.NewScanner(&out)
+	var lines []map[string]interface{}
+	for scanner.Scan() {
+		line := map[string]interface{}{}
+		require.NoError(json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.Len(lines, 4)
+	require.Equal("step", lines[2]["type"])
+	require.Equal("node2", lines[2]["step"])
+	require.InDelta(33.3, lines[2]["percent"], 0.001)
+	require.Equal("Restored node2", lines[2]["message"])
+	require.NotContains(lines[3], "message")
+	require.NotContains(lines[3], "error")
+}
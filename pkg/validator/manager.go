@@ -0,0 +1,245 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/accounts/abi"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/ids"
+)
+
+// validatorManagerABIJSON is the part of the ACP-99 ValidatorManager (v2)
+// read to inspect its state
+const validatorManagerABIJSON = `[
+	{"type":"function","name":"subnetID","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"bytes32"}]},
+	{"type":"function","name":"l1TotalWeight","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint64"}]},
+	{"type":"function","name":"getChurnTracker","stateMutability":"view","inputs":[],"outputs":[
+		{"name":"churnPeriodSeconds","type":"uint64"},
+		{"name":"maximumChurnPercentage","type":"uint8"},
+		{"name":"churnPeriod","type":"tuple","components":[
+			{"name":"startTime","type":"uint256"},
+			{"name":"initialWeight","type":"uint64"},
+			{"name":"totalWeight","type":"uint64"},
+			{"name":"churnAmount","type":"uint64"}
+		]}
+	]},
+	{"type":"function","name":"getValidator","stateMutability":"view","inputs":[{"name":"validationID","type":"bytes32"}],"outputs":[
+		{"name":"","type":"tuple","components":[
+			{"name":"status","type":"uint8"},
+			{"name":"nodeID","type":"bytes"},
+			{"name":"startingWeight","type":"uint64"},
+			{"name":"sentNonce","type":"uint64"},
+			{"name":"receivedNonce","type":"uint64"},
+			{"name":"weight","type":"uint64"},
+			{"name":"startTime","type":"uint64"},
+			{"name":"endTime","type":"uint64"}
+		]}
+	]},
+	{"type":"event","name":"RegisteredInitialValidator","anonymous":false,"inputs":[
+		{"name":"validationID","type":"bytes32","indexed":true},
+		{"name":"nodeID","type":"bytes20","indexed":true},
+		{"name":"weight","type":"uint64","indexed":false}
+	]},
+	{"type":"event","name":"InitiatedValidatorRegistration","anonymous":false,"inputs":[
+		{"name":"validationID","type":"bytes32","indexed":true},
+		{"name":"nodeID","type":"bytes20","indexed":true},
+		{"name":"registrationMessageID","type":"bytes32","indexed":false},
+		{"name":"registrationExpiry","type":"uint64","indexed":false},
+		{"name":"weight","type":"uint64","indexed":false}
+	]}
+]`
+
+var validatorManagerABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(validatorManagerABIJSON))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// ManagerValidatorStatus is the status of a validator in the validator
+// manager contract
+type ManagerValidatorStatus uint8
+
+const (
+	UnknownValidatorStatus ManagerValidatorStatus = iota
+	PendingAddedValidatorStatus
+	ActiveValidatorStatus
+	PendingRemovedValidatorStatus
+	CompletedValidatorStatus
+	InvalidatedValidatorStatus
+)
+
+func (s ManagerValidatorStatus) String() string {
+	switch s {
+	case PendingAddedValidatorStatus:
+		return "PendingAdded"
+	case ActiveValidatorStatus:
+		return "Active"
+	case PendingRemovedValidatorStatus:
+		return "PendingRemoved"
+	case CompletedValidatorStatus:
+		return "Completed"
+	case InvalidatedValidatorStatus:
+		return "Invalidated"
+	default:
+		return "Unknown"
+	}
+}
+
+// ManagerValidator is a validator as tracked by the validator manager
+// contract
+type ManagerValidator struct {
+	ValidationID ids.ID
+	NodeID       ids.NodeID
+	Status       ManagerValidatorStatus
+	Weight       uint64
+}
+
+// ManagerState is the state of the validator manager contract of an L1
+type ManagerState struct {
+	// ChainID is the chain (subnet) the contract manages
+	ChainID                ids.ID
+	TotalWeight            uint64
+	ChurnPeriodSeconds     uint64
+	MaximumChurnPercentage uint8
+	// ChurnPeriodStart is the unix time the current churn period started
+	ChurnPeriodStart uint64
+	// ChurnAmount is the weight churned in the current churn period
+	ChurnAmount uint64
+	// Pending are the validators whose registration or removal is not
+	// acknowledged by the P-Chain yet
+	Pending []ManagerValidator
+}
+
+// ManagerClient is the part of an EVM RPC client reading a validator
+// manager contract, as implemented by ethclient.Client
+type ManagerClient interface {
+	CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+}
+
+// churnTracker is the getChurnTracker result
+type churnTracker struct {
+	ChurnPeriodSeconds     uint64
+	MaximumChurnPercentage uint8
+	ChurnPeriod            struct {
+		StartTime     *big.Int
+		InitialWeight uint64
+		TotalWeight   uint64
+		ChurnAmount   uint64
+	}
+}
+
+// managerValidator is the getValidator result
+type managerValidator struct {
+	Status         uint8
+	NodeID         []byte
+	StartingWeight uint64
+	SentNonce      uint64
+	ReceivedNonce  uint64
+	Weight         uint64
+	StartTime      uint64
+	EndTime        uint64
+}
+
+// GetManagerState reads the state of the validator manager contract at
+// manager, which must be the ValidatorManager itself, not a staking manager
+// built on it
+func GetManagerState(ctx context.Context, client ManagerClient, manager common.Address) (*ManagerState, error) {
+	var (
+		state       ManagerState
+		chainID     [32]byte
+		totalWeight uint64
+		tracker     churnTracker
+	)
+	if err := callManager(ctx, client, manager, &chainID, "subnetID"); err != nil {
+		return nil, err
+	}
+	if err := callManager(ctx, client, manager, &totalWeight, "l1TotalWeight"); err != nil {
+		return nil, err
+	}
+	if err := callManager(ctx, client, manager, &tracker, "getChurnTracker"); err != nil {
+		return nil, err
+	}
+	state.ChainID = ids.ID(chainID)
+	state.TotalWeight = totalWeight
+	state.ChurnPeriodSeconds = tracker.ChurnPeriodSeconds
+	state.MaximumChurnPercentage = tracker.MaximumChurnPercentage
+	if tracker.ChurnPeriod.StartTime != nil {
+		state.ChurnPeriodStart = tracker.ChurnPeriod.StartTime.Uint64()
+	}
+	state.ChurnAmount = tracker.ChurnPeriod.ChurnAmount
+
+	logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: big.NewInt(0),
+		Addresses: []common.Address{manager},
+		Topics: [][]common.Hash{{
+			validatorManagerABI.Events["RegisteredInitialValidator"].ID,
+			validatorManagerABI.Events["InitiatedValidatorRegistration"].ID,
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the validator registrations: %w", err)
+	}
+	seen := map[common.Hash]bool{}
+	for _, log := range logs {
+		if len(log.Topics) < 2 || seen[log.Topics[1]] {
+			continue
+		}
+		seen[log.Topics[1]] = true
+		v, err := GetManagerValidator(ctx, client, manager, ids.ID(log.Topics[1]))
+		if err != nil {
+			return nil, err
+		}
+		if v.Status == PendingAddedValidatorStatus || v.Status == PendingRemovedValidatorStatus {
+			state.Pending = append(state.Pending, v)
+		}
+	}
+	return &state, nil
+}
+
+// GetManagerValidator returns the validator validationID as tracked by the
+// validator manager contract at manager
+func GetManagerValidator(ctx context.Context, client ManagerClient, manager common.Address, validationID ids.ID) (ManagerValidator, error) {
+	// a single tuple result unpacks into the first field of a struct
+	var out struct{ Validator managerValidator }
+	if err := callManager(ctx, client, manager, &out, "getValidator", [32]byte(validationID)); err != nil {
+		return ManagerValidator{}, err
+	}
+	v := out.Validator
+	validator := ManagerValidator{
+		ValidationID: validationID,
+		Status:       ManagerValidatorStatus(v.Status),
+		Weight:       v.Weight,
+	}
+	if len(v.NodeID) == ids.NodeIDLen {
+		copy(validator.NodeID[:], v.NodeID)
+	}
+	return validator, nil
+}
+
+// callManager calls method of the validator manager contract at manager,
+// unpacking its result into out
+func callManager(ctx context.Context, client ManagerClient, manager common.Address, out interface{}, method string, args ...interface{}) error {
+	input, err := validatorManagerABI.Pack(method, args...)
+	if err != nil {
+		return err
+	}
+	output, err := client.CallContract(ctx, ethereum.CallMsg{To: &manager, Data: input}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to call %s on validator manager %s: %w", method, manager.Hex(), err)
+	}
+	if err := validatorManagerABI.UnpackIntoInterface(out, method, output); err != nil {
+		return fmt.Errorf("failed to unpack %s of validator manager %s: %w", method, manager.Hex(), err)
+	}
+	return nil
+}
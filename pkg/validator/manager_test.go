@@ -0,0 +1,92 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validator
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeManager answers validator manager calls with packed results
+type fakeManager struct {
+	results    map[string][]interface{}
+	validators map[ids.ID]managerValidator
+	logs       []types.Log
+}
+
+func (f *fakeManager) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	method, err := validatorManagerABI.MethodById(call.Data)
+	if err != nil {
+		return nil, err
+	}
+	if method.Name == "getValidator" {
+		args, err := method.Inputs.Unpack(call.Data[4:])
+		if err != nil {
+			return nil, err
+		}
+		v, ok := f.validators[ids.ID(args[0].([32]byte))]
+		if !ok {
+			return nil, errors.New("unknown validator")
+		}
+		return method.Outputs.Pack(v)
+	}
+	return method.Outputs.Pack(f.results[method.Name]...)
+}
+
+func (f *fakeManager) FilterLogs(context.Context, ethereum.FilterQuery) ([]types.Log, error) {
+	return f.logs, nil
+}
+
+func TestGetManagerState(t *testing.T) {
+	require := require.New(t)
+	chainID := ids.GenerateTestID()
+	active := ids.GenerateTestID()
+	pending := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	registered := validatorManagerABI.Events["InitiatedValidatorRegistration"].ID
+	f := &fakeManager{
+		results: map[string][]interface{}{
+			"subnetID":      {[32]byte(chainID)},
+			"l1TotalWeight": {uint64(300)},
+			"getChurnTracker": {uint64(3600), uint8(20), struct {
+				StartTime     *big.Int
+				InitialWeight uint64
+				TotalWeight   uint64
+				ChurnAmount   uint64
+			}{big.NewInt(1700000000), 200, 300, 100}},
+		},
+		validators: map[ids.ID]managerValidator{
+			active:  {Status: uint8(ActiveValidatorStatus), NodeID: ids.GenerateTestNodeID().Bytes(), Weight: 200},
+			pending: {Status: uint8(PendingAddedValidatorStatus), NodeID: nodeID.Bytes(), Weight: 100},
+		},
+		logs: []types.Log{
+			{Topics: []common.Hash{validatorManagerABI.Events["RegisteredInitialValidator"].ID, common.Hash(active)}},
+			{Topics: []common.Hash{registered, common.Hash(pending)}},
+			{Topics: []common.Hash{registered, common.Hash(pending)}},
+		},
+	}
+
+	state, err := GetManagerState(context.Background(), f, common.HexToAddress("0x0FEEDC0DE0000000000000000000000000000000"))
+	require.NoError(err)
+	require.Equal(chainID, state.ChainID)
+	require.Equal(uint64(300), state.TotalWeight)
+	require.Equal(uint64(3600), state.ChurnPeriodSeconds)
+	require.Equal(uint8(20), state.MaximumChurnPercentage)
+	require.Equal(uint64(1700000000), state.ChurnPeriodStart)
+	require.Equal(uint64(100), state.ChurnAmount)
+	require.Equal([]ManagerValidator{{
+		ValidationID: pending,
+		NodeID:       nodeID,
+		Status:       PendingAddedValidatorStatus,
+		Weight:       100,
+	}}, state.Pending)
+}
@@ -0,0 +1,281 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/accounts/abi"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/ids"
+)
+
+// stakingManagerABIJSON is the part of the ACP-99 StakingManager (v2), with
+// the ERC20 token it may stake, read to inspect delegations
+const stakingManagerABIJSON = `[
+	{"type":"function","name":"getDelegatorInfo","stateMutability":"view","inputs":[{"name":"delegationID","type":"bytes32"}],"outputs":[
+		{"name":"","type":"tuple","components":[
+			{"name":"status","type":"uint8"},
+			{"name":"owner","type":"address"},
+			{"name":"validationID","type":"bytes32"},
+			{"name":"weight","type":"uint64"},
+			{"name":"startTime","type":"uint64"},
+			{"name":"startingNonce","type":"uint64"},
+			{"name":"endingNonce","type":"uint64"}
+		]}
+	]},
+	{"type":"function","name":"getDelegatorRewardInfo","stateMutability":"view","inputs":[{"name":"delegationID","type":"bytes32"}],"outputs":[
+		{"name":"","type":"address"},
+		{"name":"","type":"uint256"}
+	]},
+	{"type":"function","name":"weightToValue","stateMutability":"view","inputs":[{"name":"weight","type":"uint64"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"erc20","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]},
+	{"type":"function","name":"allowance","stateMutability":"view","inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"event","name":"InitiatedDelegatorRegistration","anonymous":false,"inputs":[
+		{"name":"delegationID","type":"bytes32","indexed":true},
+		{"name":"validationID","type":"bytes32","indexed":true},
+		{"name":"delegatorAddress","type":"address","indexed":true},
+		{"name":"nonce","type":"uint64","indexed":false},
+		{"name":"validatorWeight","type":"uint64","indexed":false},
+		{"name":"delegatorWeight","type":"uint64","indexed":false},
+		{"name":"setWeightMessageID","type":"bytes32","indexed":false},
+		{"name":"rewardRecipient","type":"address","indexed":false}
+	]}
+]`
+
+var stakingManagerABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(stakingManagerABIJSON))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// NativeTokenDecimals are the decimals of the native token of EVM L1s
+const NativeTokenDecimals = 18
+
+// ErrNoDelegation is returned for a delegation unknown to the staking
+// manager
+var ErrNoDelegation = errors.New("delegation not found")
+
+// DelegatorStatus is the status of a delegation in the staking manager
+// contract
+type DelegatorStatus uint8
+
+const (
+	UnknownDelegatorStatus DelegatorStatus = iota
+	PendingAddedDelegatorStatus
+	ActiveDelegatorStatus
+	PendingRemovedDelegatorStatus
+)
+
+func (s DelegatorStatus) String() string {
+	switch s {
+	case PendingAddedDelegatorStatus:
+		return "PendingAdded"
+	case ActiveDelegatorStatus:
+		return "Active"
+	case PendingRemovedDelegatorStatus:
+		return "PendingRemoved"
+	default:
+		return "Unknown"
+	}
+}
+
+// Delegation is a delegation as tracked by the staking manager contract
+type Delegation struct {
+	DelegationID ids.ID
+	ValidationID ids.ID
+	Owner        common.Address
+	Status       DelegatorStatus
+	Weight       uint64
+	// StartTime is the unix time the delegation started, 0 while pending
+	StartTime       uint64
+	RewardRecipient common.Address
+	// Reward is what the delegation earns on completing its removal. The
+	// contract computes it when the removal is initiated, so it is zero
+	// before.
+	Reward *big.Int
+}
+
+// delegator is the getDelegatorInfo result
+type delegator struct {
+	Status        uint8
+	Owner         common.Address
+	ValidationID  [32]byte
+	Weight        uint64
+	StartTime     uint64
+	StartingNonce uint64
+	EndingNonce   uint64
+}
+
+// StakingToken returns the ERC20 token the staking manager at manager
+// stakes, or false when it stakes the native token
+func StakingToken(ctx context.Context, client ManagerClient, manager common.Address) (common.Address, bool, error) {
+	var token common.Address
+	if err := callStaking(ctx, client, manager, &token, "erc20"); err != nil {
+		// native token staking managers have no erc20 method
+		return common.Address{}, false, nil
+	}
+	return token, true, nil
+}
+
+// TokenDecimals returns the decimals of the ERC20 token
+func TokenDecimals(ctx context.Context, client ManagerClient, token common.Address) (uint8, error) {
+	var decimals uint8
+	err := callStaking(ctx, client, token, &decimals, "decimals")
+	return decimals, err
+}
+
+// TokenAllowance returns how much of the ERC20 token owner lets spender
+// transfer
+func TokenAllowance(ctx context.Context, client ManagerClient, token, owner, spender common.Address) (*big.Int, error) {
+	allowance := new(big.Int)
+	err := callStaking(ctx, client, token, &allowance, "allowance", owner, spender)
+	return allowance, err
+}
+
+// WeightToValue returns the stake of weight, in the smallest unit of the
+// staking token
+func WeightToValue(ctx context.Context, client ManagerClient, manager common.Address, weight uint64) (*big.Int, error) {
+	value := new(big.Int)
+	err := callStaking(ctx, client, manager, &value, "weightToValue", weight)
+	return value, err
+}
+
+// GetDelegation returns the delegation delegationID as tracked by the staking
+// manager contract at manager
+func GetDelegation(ctx context.Context, client ManagerClient, manager common.Address, delegationID ids.ID) (Delegation, error) {
+	var out struct{ Delegator delegator }
+	if err := callStaking(ctx, client, manager, &out, "getDelegatorInfo", [32]byte(delegationID)); err != nil {
+		return Delegation{}, err
+	}
+	d := out.Delegator
+	if DelegatorStatus(d.Status) == UnknownDelegatorStatus {
+		return Delegation{}, fmt.Errorf("%w: %s", ErrNoDelegation, delegationID)
+	}
+	output, err := packAndCall(ctx, client, manager, "getDelegatorRewardInfo", [32]byte(delegationID))
+	if err != nil {
+		return Delegation{}, err
+	}
+	values, err := stakingManagerABI.Unpack("getDelegatorRewardInfo", output)
+	if err != nil {
+		return Delegation{}, fmt.Errorf("failed to unpack getDelegatorRewardInfo of %s: %w", manager.Hex(), err)
+	}
+	recipient, _ := values[0].(common.Address)
+	reward, _ := values[1].(*big.Int)
+	if reward == nil {
+		reward = new(big.Int)
+	}
+	return Delegation{
+		DelegationID:    delegationID,
+		ValidationID:    ids.ID(d.ValidationID),
+		Owner:           d.Owner,
+		Status:          DelegatorStatus(d.Status),
+		Weight:          d.Weight,
+		StartTime:       d.StartTime,
+		RewardRecipient: recipient,
+		Reward:          reward,
+	}, nil
+}
+
+// GetDelegations returns the delegations of owner with the staking manager
+// at manager that are not completed, found from their registration events
+func GetDelegations(ctx context.Context, client ManagerClient, manager, owner common.Address) ([]Delegation, error) {
+	logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: big.NewInt(0),
+		Addresses: []common.Address{manager},
+		Topics: [][]common.Hash{
+			{stakingManagerABI.Events["InitiatedDelegatorRegistration"].ID},
+			nil,
+			nil,
+			{common.BytesToHash(owner.Bytes())},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the delegator registrations: %w", err)
+	}
+	delegations := []Delegation{}
+	seen := map[common.Hash]bool{}
+	for _, log := range logs {
+		if len(log.Topics) < 2 || seen[log.Topics[1]] {
+			continue
+		}
+		seen[log.Topics[1]] = true
+		d, err := GetDelegation(ctx, client, manager, ids.ID(log.Topics[1]))
+		if errors.Is(err, ErrNoDelegation) {
+			// completed delegations are deleted
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		delegations = append(delegations, d)
+	}
+	return delegations, nil
+}
+
+// DelegationIDFromLogs returns the delegation registered by the logs of a
+// transaction initiating a delegation
+func DelegationIDFromLogs(logs []*types.Log) (ids.ID, error) {
+	event := stakingManagerABI.Events["InitiatedDelegatorRegistration"].ID
+	for _, log := range logs {
+		if len(log.Topics) > 1 && log.Topics[0] == event {
+			return ids.ID(log.Topics[1]), nil
+		}
+	}
+	return ids.Empty, errors.New("no delegator registration in the transaction logs")
+}
+
+// FormatTokenAmount formats amount, in the smallest unit of a token with
+// decimals, in whole tokens
+func FormatTokenAmount(amount *big.Int, decimals uint8) string {
+	if amount == nil {
+		return "0"
+	}
+	s := new(big.Int).Abs(amount).String()
+	if len(s) <= int(decimals) {
+		s = strings.Repeat("0", int(decimals)-len(s)+1) + s
+	}
+	whole, frac := s[:len(s)-int(decimals)], strings.TrimRight(s[len(s)-int(decimals):], "0")
+	if amount.Sign() < 0 {
+		whole = "-" + whole
+	}
+	if frac == "" {
+		return whole
+	}
+	return whole + "." + frac
+}
+
+// callStaking calls method of the staking manager, or ERC20 token, at
+// address, unpacking its result into out
+func callStaking(ctx context.Context, client ManagerClient, address common.Address, out interface{}, method string, args ...interface{}) error {
+	output, err := packAndCall(ctx, client, address, method, args...)
+	if err != nil {
+		return err
+	}
+	if err := stakingManagerABI.UnpackIntoInterface(out, method, output); err != nil {
+		return fmt.Errorf("failed to unpack %s of %s: %w", method, address.Hex(), err)
+	}
+	return nil
+}
+
+func packAndCall(ctx context.Context, client ManagerClient, address common.Address, method string, args ...interface{}) ([]byte, error) {
+	input, err := stakingManagerABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	output, err := client.CallContract(ctx, ethereum.CallMsg{To: &address, Data: input}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s on %s: %w", method, address.Hex(), err)
+	}
+	return output, nil
+}
@@ -0,0 +1,131 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validator
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStaking answers staking manager calls with packed results
+type fakeStaking struct {
+	delegators map[ids.ID]delegator
+	rewards    map[ids.ID]*big.Int
+	erc20      *common.Address
+	logs       []types.Log
+	query      ethereum.FilterQuery
+}
+
+func (f *fakeStaking) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	method, err := stakingManagerABI.MethodById(call.Data)
+	if err != nil {
+		return nil, err
+	}
+	args, err := method.Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	switch method.Name {
+	case "getDelegatorInfo":
+		return method.Outputs.Pack(f.delegators[ids.ID(args[0].([32]byte))])
+	case "getDelegatorRewardInfo":
+		reward, ok := f.rewards[ids.ID(args[0].([32]byte))]
+		if !ok {
+			reward = new(big.Int)
+		}
+		return method.Outputs.Pack(common.HexToAddress("0x0100"), reward)
+	case "erc20":
+		if f.erc20 == nil {
+			return nil, errors.New("execution reverted")
+		}
+		return method.Outputs.Pack(*f.erc20)
+	case "weightToValue":
+		return method.Outputs.Pack(new(big.Int).Mul(new(big.Int).SetUint64(args[0].(uint64)), big.NewInt(1e12)))
+	}
+	return nil, errors.New("unexpected call")
+}
+
+func (f *fakeStaking) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	f.query = q
+	return f.logs, nil
+}
+
+func TestGetDelegations(t *testing.T) {
+	require := require.New(t)
+	owner := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	validationID := ids.GenerateTestID()
+	active := ids.GenerateTestID()
+	removing := ids.GenerateTestID()
+	completed := ids.GenerateTestID()
+	event := stakingManagerABI.Events["InitiatedDelegatorRegistration"].ID
+	registration := func(delegationID ids.ID) types.Log {
+		return types.Log{Topics: []common.Hash{event, common.Hash(delegationID), common.Hash(validationID), common.BytesToHash(owner.Bytes())}}
+	}
+	f := &fakeStaking{
+		delegators: map[ids.ID]delegator{
+			active:   {Status: uint8(ActiveDelegatorStatus), Owner: owner, ValidationID: validationID, Weight: 20, StartTime: 1700000000},
+			removing: {Status: uint8(PendingRemovedDelegatorStatus), Owner: owner, ValidationID: validationID, Weight: 5},
+		},
+		rewards: map[ids.ID]*big.Int{removing: big.NewInt(1_500_000_000_000_000_000)},
+		logs:    []types.Log{registration(active), registration(removing), registration(completed), registration(active)},
+	}
+	manager := common.HexToAddress("0x0FEEDC0DE0000000000000000000000000000000")
+
+	delegations, err := GetDelegations(context.Background(), f, manager, owner)
+	require.NoError(err)
+	require.Equal(common.BytesToHash(owner.Bytes()), f.query.Topics[3][0])
+	require.Len(delegations, 2)
+	require.Equal(active, delegations[0].DelegationID)
+	require.Equal(ActiveDelegatorStatus, delegations[0].Status)
+	require.Equal(uint64(20), delegations[0].Weight)
+	require.Zero(delegations[0].Reward.Sign())
+	require.Equal(removing, delegations[1].DelegationID)
+	require.Equal("1.5", FormatTokenAmount(delegations[1].Reward, NativeTokenDecimals))
+
+	_, err = GetDelegation(context.Background(), f, manager, completed)
+	require.ErrorIs(err, ErrNoDelegation)
+
+	delegationID, err := DelegationIDFromLogs([]*types.Log{{Topics: []common.Hash{common.HexToHash("0x01")}}, &f.logs[1]})
+	require.NoError(err)
+	require.Equal(removing, delegationID)
+	_, err = DelegationIDFromLogs(nil)
+	require.Error(err)
+}
+
+func TestStakingToken(t *testing.T) {
+	require := require.New(t)
+	manager := common.HexToAddress("0x0FEEDC0DE0000000000000000000000000000000")
+	f := &fakeStaking{}
+	_, isERC20, err := StakingToken(context.Background(), f, manager)
+	require.NoError(err)
+	require.False(isERC20)
+
+	token := common.HexToAddress("0x0200")
+	f.erc20 = &token
+	got, isERC20, err := StakingToken(context.Background(), f, manager)
+	require.NoError(err)
+	require.True(isERC20)
+	require.Equal(token, got)
+
+	value, err := WeightToValue(context.Background(), f, manager, 3)
+	require.NoError(err)
+	require.Equal(big.NewInt(3e12), value)
+}
+
+func TestFormatTokenAmount(t *testing.T) {
+	require := require.New(t)
+	require.Equal("0", FormatTokenAmount(nil, 18))
+	require.Equal("0", FormatTokenAmount(big.NewInt(0), 18))
+	require.Equal("0.000000000000000001", FormatTokenAmount(big.NewInt(1), 18))
+	require.Equal("12.5", FormatTokenAmount(big.NewInt(12_500), 3))
+	require.Equal("-2", FormatTokenAmount(big.NewInt(-2), 0))
+}
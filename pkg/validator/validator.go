@@ -6,7 +6,9 @@ package validator
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 
+	cliutils "github.com/luxfi/cli/pkg/utils"
 	luxdjson "github.com/luxfi/codec/jsonrpc"
 	"github.com/luxfi/ids"
 	"github.com/luxfi/rpc"
@@ -90,22 +92,9 @@ func GetValidationID(
 	managerAddress crypto.Address,
 	nodeID ids.NodeID,
 ) (ids.ID, error) {
-	// if specialized, need to retrieve underlying manager
-	// needs to directly access the manager, does not work with a proxy
 	out, err := contract.CallToMethod(
 		rpcURL,
-		managerAddress,
-		"getStakingManagerSettings()->(address,uint256,uint256,uint64,uint16,uint8,uint256,address,bytes32)",
-	)
-	if err == nil && len(out) == 9 {
-		validatorManager, ok := out[0].(crypto.Address)
-		if ok {
-			managerAddress = validatorManager
-		}
-	}
-	out, err = contract.CallToMethod(
-		rpcURL,
-		managerAddress,
+		GetUnderlyingValidatorManager(rpcURL, managerAddress),
 		"registeredValidators(bytes)->(bytes32)",
 		nodeID[:],
 	)
@@ -115,6 +104,26 @@ func GetValidationID(
 	return contract.GetSmartContractCallResult[[32]byte]("registeredValidators", out)
 }
 
+// GetUnderlyingValidatorManager returns the validator manager a specialized
+// (staking) manager is built on, or managerAddress itself
+func GetUnderlyingValidatorManager(
+	rpcURL string,
+	managerAddress crypto.Address,
+) crypto.Address {
+	// needs to directly access the manager, does not work with a proxy
+	out, err := contract.CallToMethod(
+		rpcURL,
+		managerAddress,
+		"getStakingManagerSettings()->(address,uint256,uint256,uint64,uint16,uint8,uint256,address,bytes32)",
+	)
+	if err == nil && len(out) == 9 {
+		if validatorManager, ok := out[0].(crypto.Address); ok {
+			return validatorManager
+		}
+	}
+	return managerAddress
+}
+
 func GetValidatorKind(
 	network models.Network,
 	chainID ids.ID,
@@ -138,8 +147,22 @@ func GetValidatorKind(
 	return NonValidator, nil
 }
 
-// Enables querying the validation IDs from P-Chain
+// currentValidatorsCache holds platform.getCurrentValidators results per
+// endpoint and chain
+var currentValidatorsCache = cliutils.NewTTLCache[string, []CurrentValidatorInfo](cliutils.PChainCacheTTL)
+
+// Enables querying the validation IDs from P-Chain.
+// Results are cached for a short time as deploy and status flows repeat this query,
+// each caller gets its own copy.
 func GetCurrentValidators(network models.Network, chainID ids.ID) ([]CurrentValidatorInfo, error) {
+	key := network.Endpoint() + "/" + chainID.String()
+	validators, err := currentValidatorsCache.GetOrLoad(key, func() ([]CurrentValidatorInfo, error) {
+		return getCurrentValidators(network, chainID)
+	})
+	return slices.Clone(validators), err
+}
+
+func getCurrentValidators(network models.Network, chainID ids.ID) ([]CurrentValidatorInfo, error) {
 	ctx, cancel := utils.GetAPIContext()
 	defer cancel()
 	requester := rpc.NewEndpointRequester(network.Endpoint() + "/ext/P")
@@ -0,0 +1,74 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/luxfi/evm/commontype"
+)
+
+const (
+	// FeeWindowSeconds is the rolling window the EVM measures gas usage
+	// against the target gas in
+	FeeWindowSeconds = 10
+
+	// LargeFeeChangeFactor is the factor a fee config parameter has to be
+	// raised or lowered by for the change to be considered large
+	LargeFeeChangeFactor = 2
+)
+
+// CheckFeeConfig verifies feeConfig, and that its target gas can be reached
+// at its target block rate
+func CheckFeeConfig(feeConfig *commontype.FeeConfig) error {
+	if err := feeConfig.Verify(); err != nil {
+		return err
+	}
+	if feeConfig.TargetBlockRate > FeeWindowSeconds {
+		return fmt.Errorf("target block rate of %ds is longer than the %ds fee window", feeConfig.TargetBlockRate, FeeWindowSeconds)
+	}
+	// the gas that fits in the fee window at one full block per target rate
+	capacity := new(big.Int).Mul(feeConfig.GasLimit, big.NewInt(int64(FeeWindowSeconds/feeConfig.TargetBlockRate))) //nolint:gosec // G115: at most FeeWindowSeconds
+	if feeConfig.TargetGas.Cmp(capacity) > 0 {
+		return fmt.Errorf(
+			"target gas %s is more than the %s gas of full blocks every %ds in the %ds fee window, the base fee would never rise",
+			feeConfig.TargetGas, capacity, feeConfig.TargetBlockRate, FeeWindowSeconds,
+		)
+	}
+	return nil
+}
+
+// LargeFeeConfigChanges describes the parameters of updated raised or lowered
+// from current by LargeFeeChangeFactor or more
+func LargeFeeConfigChanges(current, updated *commontype.FeeConfig) []string {
+	changes := []string{}
+	params := []struct {
+		name             string
+		current, updated *big.Int
+	}{
+		{"gasLimit", current.GasLimit, updated.GasLimit},
+		{"targetBlockRate", new(big.Int).SetUint64(current.TargetBlockRate), new(big.Int).SetUint64(updated.TargetBlockRate)},
+		{"minBaseFee", current.MinBaseFee, updated.MinBaseFee},
+		{"targetGas", current.TargetGas, updated.TargetGas},
+		{"baseFeeChangeDenominator", current.BaseFeeChangeDenominator, updated.BaseFeeChangeDenominator},
+		{"minBlockGasCost", current.MinBlockGasCost, updated.MinBlockGasCost},
+		{"maxBlockGasCost", current.MaxBlockGasCost, updated.MaxBlockGasCost},
+		{"blockGasCostStep", current.BlockGasCostStep, updated.BlockGasCostStep},
+	}
+	factor := big.NewInt(LargeFeeChangeFactor)
+	for _, p := range params {
+		if p.current == nil || p.updated == nil || p.current.Cmp(p.updated) == 0 {
+			continue
+		}
+		low, high, direction := p.current, p.updated, "raised"
+		if p.updated.Cmp(p.current) < 0 {
+			low, high, direction = p.updated, p.current, "lowered"
+		}
+		if high.Cmp(new(big.Int).Mul(low, factor)) >= 0 {
+			changes = append(changes, fmt.Sprintf("%s %s from %s to %s", p.name, direction, p.current, p.updated))
+		}
+	}
+	return changes
+}
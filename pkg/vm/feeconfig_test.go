@@ -0,0 +1,62 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/evm/commontype"
+	"github.com/stretchr/testify/require"
+)
+
+func testFeeConfig() *commontype.FeeConfig {
+	return &commontype.FeeConfig{
+		GasLimit:                 big.NewInt(8_000_000),
+		TargetBlockRate:          2,
+		MinBaseFee:               big.NewInt(25_000_000_000),
+		TargetGas:                big.NewInt(15_000_000),
+		BaseFeeChangeDenominator: big.NewInt(36),
+		MinBlockGasCost:          big.NewInt(0),
+		MaxBlockGasCost:          big.NewInt(1_000_000),
+		BlockGasCostStep:         big.NewInt(200_000),
+	}
+}
+
+func TestCheckFeeConfig(t *testing.T) {
+	require := require.New(t)
+	require.NoError(CheckFeeConfig(testFeeConfig()))
+
+	feeConfig := testFeeConfig()
+	feeConfig.BaseFeeChangeDenominator = big.NewInt(0)
+	require.ErrorContains(CheckFeeConfig(feeConfig), "baseFeeChangeDenominator")
+
+	feeConfig = testFeeConfig()
+	feeConfig.TargetBlockRate = 12
+	require.ErrorContains(CheckFeeConfig(feeConfig), "longer than the 10s fee window")
+
+	// 5 blocks of 8M gas fit in the window
+	feeConfig = testFeeConfig()
+	feeConfig.TargetGas = big.NewInt(40_000_000)
+	require.NoError(CheckFeeConfig(feeConfig))
+	feeConfig.TargetGas = big.NewInt(40_000_001)
+	require.ErrorContains(CheckFeeConfig(feeConfig), "the base fee would never rise")
+}
+
+func TestLargeFeeConfigChanges(t *testing.T) {
+	require := require.New(t)
+	current := testFeeConfig()
+	require.Empty(LargeFeeConfigChanges(current, testFeeConfig()))
+
+	updated := testFeeConfig()
+	updated.GasLimit = big.NewInt(15_000_000)
+	updated.MinBaseFee = big.NewInt(50_000_000_000)
+	updated.TargetBlockRate = 1
+	updated.MinBlockGasCost = big.NewInt(1)
+	require.Equal([]string{
+		"targetBlockRate lowered from 2 to 1",
+		"minBaseFee raised from 25000000000 to 50000000000",
+		"minBlockGasCost raised from 0 to 1",
+	}, LargeFeeConfigChanges(current, updated))
+}
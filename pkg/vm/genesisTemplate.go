@@ -0,0 +1,257 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/luxfi/constants"
+	"github.com/luxfi/evm/commontype"
+	"github.com/luxfi/evm/precompile/contracts/feemanager"
+	"github.com/luxfi/evm/precompile/contracts/nativeminter"
+	"github.com/luxfi/evm/precompile/contracts/txallowlist"
+	"github.com/luxfi/geth/common"
+)
+
+// GenesisTemplatesDir is the directory of the base dir holding the genesis
+// templates
+const GenesisTemplatesDir = "templates"
+
+// GenesisTemplate is a reusable set of EVM genesis settings
+type GenesisTemplate struct {
+	ChainID   uint64               `json:"chainId"`
+	FeeConfig commontype.FeeConfig `json:"feeConfig"`
+	// Allocations are balances in wei, in decimal
+	Allocations        []AllocationEntry `json:"allocations,omitempty"`
+	NativeMinterAdmins []string          `json:"nativeMinterAdmins,omitempty"`
+	TxAllowListAdmins  []string          `json:"txAllowListAdmins,omitempty"`
+	FeeManagerAdmins   []string          `json:"feeManagerAdmins,omitempty"`
+}
+
+// DefaultGenesisTemplate returns the settings of the default EVM genesis
+func DefaultGenesisTemplate() GenesisTemplate {
+	return GenesisTemplate{
+		ChainID: 200200,
+		FeeConfig: commontype.FeeConfig{
+			GasLimit:                 big.NewInt(8_000_000),
+			TargetBlockRate:          2,
+			MinBaseFee:               big.NewInt(25_000_000_000),
+			TargetGas:                big.NewInt(15_000_000),
+			BaseFeeChangeDenominator: big.NewInt(36),
+			MinBlockGasCost:          big.NewInt(0),
+			MaxBlockGasCost:          big.NewInt(1_000_000),
+			BlockGasCostStep:         big.NewInt(200_000),
+		},
+	}
+}
+
+// Verify checks the fee config, allocations and precompile admins of t
+func (t GenesisTemplate) Verify() error {
+	if t.ChainID == 0 {
+		return errors.New("chain ID must be set")
+	}
+	if err := CheckFeeConfig(&t.FeeConfig); err != nil {
+		return fmt.Errorf("invalid fee config: %w", err)
+	}
+	seen := map[common.Address]bool{}
+	for _, a := range t.Allocations {
+		if !common.IsHexAddress(a.Address) {
+			return fmt.Errorf("invalid allocation address %q", a.Address)
+		}
+		if seen[common.HexToAddress(a.Address)] {
+			return fmt.Errorf("duplicate allocation for %s", a.Address)
+		}
+		seen[common.HexToAddress(a.Address)] = true
+		if balance, ok := new(big.Int).SetString(a.Balance, 10); !ok || balance.Sign() < 0 {
+			return fmt.Errorf("invalid allocation balance %q for %s", a.Balance, a.Address)
+		}
+	}
+	for name, admins := range map[string][]string{
+		"native minter": t.NativeMinterAdmins,
+		"tx allow list": t.TxAllowListAdmins,
+		"fee manager":   t.FeeManagerAdmins,
+	} {
+		for _, admin := range admins {
+			if !common.IsHexAddress(admin) {
+				return fmt.Errorf("invalid %s admin %q", name, admin)
+			}
+		}
+	}
+	return nil
+}
+
+// Genesis returns the EVM genesis of t
+func (t GenesisTemplate) Genesis() ([]byte, error) {
+	if err := t.Verify(); err != nil {
+		return nil, err
+	}
+	config := map[string]interface{}{
+		"chainId":             t.ChainID,
+		"homesteadBlock":      0,
+		"eip150Block":         0,
+		"eip155Block":         0,
+		"eip158Block":         0,
+		"byzantiumBlock":      0,
+		"constantinopleBlock": 0,
+		"petersburgBlock":     0,
+		"istanbulBlock":       0,
+		"muirGlacierBlock":    0,
+		"evmTimestamp":        0,
+		"feeConfig":           t.FeeConfig,
+		"allowFeeRecipients":  true,
+	}
+	for key, admins := range map[string][]string{
+		nativeminter.ConfigKey: t.NativeMinterAdmins,
+		txallowlist.ConfigKey:  t.TxAllowListAdmins,
+		feemanager.ConfigKey:   t.FeeManagerAdmins,
+	} {
+		if len(admins) > 0 {
+			config[key] = map[string]interface{}{
+				"blockTimestamp": 0,
+				"adminAddresses": checksummed(admins),
+			}
+		}
+	}
+	alloc := map[string]interface{}{}
+	for _, a := range t.Allocations {
+		balance, _ := new(big.Int).SetString(a.Balance, 10)
+		alloc[strings.TrimPrefix(common.HexToAddress(a.Address).Hex(), "0x")] = map[string]interface{}{
+			"balance": "0x" + balance.Text(16),
+		}
+	}
+	genesis := map[string]interface{}{
+		"config":     config,
+		"alloc":      alloc,
+		"nonce":      "0x0",
+		"timestamp":  "0x0",
+		"extraData":  "0x",
+		"gasLimit":   "0x" + t.FeeConfig.GasLimit.Text(16),
+		"difficulty": "0x0",
+		"mixHash":    common.Hash{}.Hex(),
+		"coinbase":   common.Address{}.Hex(),
+	}
+	return json.MarshalIndent(genesis, "", "  ")
+}
+
+// ParseAllocationsCSV reads allocations from address,amount lines, the
+// amounts in tokens of 18 decimals. A header line is skipped.
+func ParseAllocationsCSV(r io.Reader) ([]AllocationEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	var allocations []AllocationEntry
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return allocations, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 && !common.IsHexAddress(record[0]) {
+			continue
+		}
+		if !common.IsHexAddress(record[0]) {
+			return nil, fmt.Errorf("line %d: invalid address %q", line, record[0])
+		}
+		balance, err := ParseTokenAmount(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		allocations = append(allocations, AllocationEntry{
+			Address: common.HexToAddress(record[0]).Hex(),
+			Balance: balance.String(),
+		})
+	}
+}
+
+// ParseTokenAmount converts an amount of tokens of 18 decimals, such as
+// "1.5", to wei
+func ParseTokenAmount(amount string) (*big.Int, error) {
+	whole, fraction, _ := strings.Cut(strings.TrimSpace(amount), ".")
+	if len(fraction) > 18 {
+		return nil, fmt.Errorf("amount %q has more than 18 decimals", amount)
+	}
+	wei, ok := new(big.Int).SetString(whole+fraction+strings.Repeat("0", 18-len(fraction)), 10)
+	if !ok || wei.Sign() < 0 || whole == "" {
+		return nil, fmt.Errorf("invalid amount %q", amount)
+	}
+	return wei, nil
+}
+
+// GenesisTemplatePath returns the path of the genesis template name
+func GenesisTemplatePath(baseDir, name string) string {
+	return filepath.Join(baseDir, GenesisTemplatesDir, name+".json")
+}
+
+// SaveGenesisTemplate saves t as the genesis template name
+func SaveGenesisTemplate(baseDir, name string, t GenesisTemplate) error {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid template name %q", name)
+	}
+	if err := t.Verify(); err != nil {
+		return err
+	}
+	raw, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := GenesisTemplatePath(baseDir, name)
+	if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, raw, constants.WriteReadReadPerms)
+}
+
+// LoadGenesisTemplate loads the genesis template name
+func LoadGenesisTemplate(baseDir, name string) (GenesisTemplate, error) {
+	var t GenesisTemplate
+	raw, err := os.ReadFile(GenesisTemplatePath(baseDir, name)) //nolint:gosec // G304: Reading from app's data directory
+	if errors.Is(err, os.ErrNotExist) {
+		return t, fmt.Errorf("genesis template %s not found", name)
+	}
+	if err != nil {
+		return t, err
+	}
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return t, fmt.Errorf("invalid genesis template %s: %w", name, err)
+	}
+	return t, t.Verify()
+}
+
+// ListGenesisTemplates returns the names of the genesis templates
+func ListGenesisTemplates(baseDir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(baseDir, GenesisTemplatesDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".json"); ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func checksummed(addresses []string) []string {
+	out := make([]string, 0, len(addresses))
+	for _, a := range addresses {
+		out = append(out, common.HexToAddress(a).Hex())
+	}
+	return out
+}
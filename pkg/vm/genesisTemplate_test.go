@@ -0,0 +1,90 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testAllocAddress = "0x9011E888251AB053B7bD1cdB598Db4f9DEd94714"
+
+func TestGenesisTemplateGenesis(t *testing.T) {
+	require := require.New(t)
+	template := DefaultGenesisTemplate()
+	template.ChainID = 12345
+	template.FeeConfig.GasLimit = big.NewInt(15_000_000)
+	template.Allocations = []AllocationEntry{{Address: testAllocAddress, Balance: "1000000000000000000"}}
+	template.NativeMinterAdmins = []string{strings.ToLower(testAllocAddress)}
+
+	raw, err := template.Genesis()
+	require.NoError(err)
+	var genesis map[string]interface{}
+	require.NoError(json.Unmarshal(raw, &genesis))
+	require.Equal("0xe4e1c0", genesis["gasLimit"])
+	config := genesis["config"].(map[string]interface{})
+	require.Equal(float64(12345), config["chainId"])
+	require.Equal(float64(15_000_000), config["feeConfig"].(map[string]interface{})["gasLimit"])
+	require.Equal([]interface{}{testAllocAddress}, config["contractNativeMinterConfig"].(map[string]interface{})["adminAddresses"])
+	require.NotContains(config, "txAllowListConfig")
+	alloc := genesis["alloc"].(map[string]interface{})
+	require.Equal(map[string]interface{}{"balance": "0xde0b6b3a7640000"}, alloc[strings.TrimPrefix(testAllocAddress, "0x")])
+
+	template.Allocations = append(template.Allocations, AllocationEntry{Address: strings.ToLower(testAllocAddress), Balance: "1"})
+	_, err = template.Genesis()
+	require.ErrorContains(err, "duplicate allocation")
+}
+
+func TestParseAllocationsCSV(t *testing.T) {
+	require := require.New(t)
+	allocations, err := ParseAllocationsCSV(strings.NewReader("address,amount\n" + testAllocAddress + ", 1.5\n"))
+	require.NoError(err)
+	require.Equal([]AllocationEntry{{Address: testAllocAddress, Balance: "1500000000000000000"}}, allocations)
+
+	_, err = ParseAllocationsCSV(strings.NewReader(testAllocAddress + ",1\nnot-an-address,1\n"))
+	require.ErrorContains(err, "line 2")
+}
+
+func TestParseTokenAmount(t *testing.T) {
+	require := require.New(t)
+	for amount, wei := range map[string]string{
+		"1":        "1000000000000000000",
+		"0.000001": "1000000000000",
+		"250000":   "250000000000000000000000",
+	} {
+		parsed, err := ParseTokenAmount(amount)
+		require.NoError(err, amount)
+		require.Equal(wei, parsed.String(), amount)
+	}
+	for _, amount := range []string{"", ".5", "-1", "1.2.3", "0.0000000000000000001"} {
+		_, err := ParseTokenAmount(amount)
+		require.Error(err, amount)
+	}
+}
+
+func TestGenesisTemplates(t *testing.T) {
+	require := require.New(t)
+	baseDir := t.TempDir()
+	names, err := ListGenesisTemplates(baseDir)
+	require.NoError(err)
+	require.Empty(names)
+
+	template := DefaultGenesisTemplate()
+	template.FeeManagerAdmins = []string{testAllocAddress}
+	require.NoError(SaveGenesisTemplate(baseDir, "gaming", template))
+	require.Error(SaveGenesisTemplate(baseDir, "../gaming", template))
+
+	loaded, err := LoadGenesisTemplate(baseDir, "gaming")
+	require.NoError(err)
+	require.Equal(template, loaded)
+	names, err = ListGenesisTemplates(baseDir)
+	require.NoError(err)
+	require.Equal([]string{"gaming"}, names)
+	_, err = LoadGenesisTemplate(baseDir, "defi")
+	require.ErrorContains(err, "not found")
+}
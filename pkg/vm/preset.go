@@ -0,0 +1,296 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/luxfi/constants"
+	"github.com/luxfi/evm/commontype"
+)
+
+// PresetsDir is the directory of the base dir holding the user presets, one
+// directory per preset with a file per version
+const PresetsDir = "presets"
+
+// Preset is a named, versioned EVM chain setup: the genesis settings and
+// the chain config.json of the chains created from it
+type Preset struct {
+	Name        string `json:"name"`
+	Version     int    `json:"version"`
+	Description string `json:"description,omitempty"`
+	// Genesis is the genesis of the chains, its chain ID replaced by the
+	// one they are created with
+	Genesis GenesisTemplate `json:"genesis"`
+	// ChainConfig is the EVM chain config, as the config.json of the chain
+	ChainConfig map[string]interface{} `json:"chainConfig,omitempty"`
+	// BuiltIn tells whether the preset ships with the CLI
+	BuiltIn bool `json:"-"`
+}
+
+// Ref returns the reference of p, name@v<version>
+func (p Preset) Ref() string {
+	return fmt.Sprintf("%s@v%d", p.Name, p.Version)
+}
+
+// Verify checks the name and the genesis settings of p
+func (p Preset) Verify() error {
+	if err := checkPresetName(p.Name); err != nil {
+		return err
+	}
+	if p.Version < 1 {
+		return fmt.Errorf("invalid version %d of preset %s", p.Version, p.Name)
+	}
+	if err := p.Genesis.Verify(); err != nil {
+		return fmt.Errorf("invalid genesis of preset %s: %w", p.Name, err)
+	}
+	return nil
+}
+
+// ChainConfigJSON returns the chain config.json of p, nil when p has no
+// chain config
+func (p Preset) ChainConfigJSON() ([]byte, error) {
+	if len(p.ChainConfig) == 0 {
+		return nil, nil
+	}
+	return json.MarshalIndent(p.ChainConfig, "", "  ")
+}
+
+// BuiltinPresets returns the presets shipped with the CLI
+func BuiltinPresets() []Preset {
+	withFees := func(gasLimit, targetGas, minBaseFee int64, targetBlockRate uint64, maxBlockGasCost, blockGasCostStep int64) GenesisTemplate {
+		t := DefaultGenesisTemplate()
+		t.FeeConfig = commontype.FeeConfig{
+			GasLimit:                 big.NewInt(gasLimit),
+			TargetBlockRate:          targetBlockRate,
+			MinBaseFee:               big.NewInt(minBaseFee),
+			TargetGas:                big.NewInt(targetGas),
+			BaseFeeChangeDenominator: big.NewInt(36),
+			MinBlockGasCost:          big.NewInt(0),
+			MaxBlockGasCost:          big.NewInt(maxBlockGasCost),
+			BlockGasCostStep:         big.NewInt(blockGasCostStep),
+		}
+		return t
+	}
+	return []Preset{
+		{
+			Name:        "high-throughput",
+			Version:     1,
+			Description: "30M gas blocks every second, large tx pool and caches, pruned state",
+			Genesis:     withFees(30_000_000, 150_000_000, 1_000_000_000, 1, 1_000_000, 100_000),
+			ChainConfig: map[string]interface{}{
+				"pruning-enabled":       true,
+				"tx-pool-account-slots": 64,
+				"tx-pool-global-slots":  16384,
+				"tx-pool-global-queue":  4096,
+				"accepted-queue-limit":  256,
+				"trie-clean-cache":      1024,
+				"trie-dirty-cache":      1024,
+				"snapshot-cache":        512,
+			},
+		},
+		{
+			Name:        "low-latency",
+			Version:     1,
+			Description: "blocks every second without block gas cost, fast tx gossip",
+			Genesis:     withFees(15_000_000, 75_000_000, 25_000_000_000, 1, 0, 0),
+			ChainConfig: map[string]interface{}{
+				"pruning-enabled":           true,
+				"push-gossip-frequency":     "25ms",
+				"pull-gossip-frequency":     "250ms",
+				"push-gossip-num-peers":     16,
+				"push-gossip-percent-stake": 0.9,
+			},
+		},
+		{
+			Name:        "archival",
+			Version:     1,
+			Description: "default fees, full history: no pruning or state sync, all txs indexed, debug APIs",
+			Genesis:     DefaultGenesisTemplate(),
+			ChainConfig: map[string]interface{}{
+				"pruning-enabled":    false,
+				"state-sync-enabled": false,
+				"tx-lookup-limit":    0,
+				"skip-tx-indexing":   false,
+				"eth-apis": []string{
+					"eth", "eth-filter", "net", "web3",
+					"internal-eth", "internal-blockchain", "internal-transaction",
+					"internal-debug", "debug",
+				},
+			},
+		},
+		{
+			Name:        "gaming",
+			Version:     1,
+			Description: "near-free gas and 1s blocks, many pending txs per account for game servers",
+			Genesis:     withFees(20_000_000, 100_000_000, 1_000_000, 1, 100_000, 10_000),
+			ChainConfig: map[string]interface{}{
+				"pruning-enabled":       true,
+				"tx-pool-price-limit":   1,
+				"tx-pool-account-slots": 1024,
+				"tx-pool-account-queue": 1024,
+				"tx-pool-global-slots":  16384,
+			},
+		},
+	}
+}
+
+// ParsePresetRef splits a preset reference, name or name@v<version>, with
+// version 0 for the latest
+func ParsePresetRef(ref string) (string, int, error) {
+	name, version, ok := strings.Cut(ref, "@")
+	if !ok {
+		return name, 0, nil
+	}
+	v, err := strconv.Atoi(strings.TrimPrefix(version, "v"))
+	if err != nil || v < 1 {
+		return "", 0, fmt.Errorf("invalid preset version %q in %s, expected v1, v2...", version, ref)
+	}
+	return name, v, nil
+}
+
+// PresetPath returns the path of version of the user preset name
+func PresetPath(baseDir, name string, version int) string {
+	return filepath.Join(baseDir, PresetsDir, name, fmt.Sprintf("v%d.json", version))
+}
+
+// SavePreset saves p as the next version of the preset p.Name, after the
+// saved and built-in ones, and sets p.Version to it
+func SavePreset(baseDir string, p *Preset) error {
+	if err := checkPresetName(p.Name); err != nil {
+		return err
+	}
+	versions, err := ListPresets(baseDir)
+	if err != nil {
+		return err
+	}
+	p.Version = 1
+	for _, other := range versions {
+		if other.Name == p.Name && other.Version >= p.Version {
+			p.Version = other.Version + 1
+		}
+	}
+	if err := p.Verify(); err != nil {
+		return err
+	}
+	raw, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := PresetPath(baseDir, p.Name, p.Version)
+	if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, raw, constants.WriteReadReadPerms)
+}
+
+// LoadPreset returns the preset of ref, name or name@v<version>, the latest
+// version of name when no version is given
+func LoadPreset(baseDir, ref string) (Preset, error) {
+	name, version, err := ParsePresetRef(ref)
+	if err != nil {
+		return Preset{}, err
+	}
+	presets, err := ListPresets(baseDir)
+	if err != nil {
+		return Preset{}, err
+	}
+	var (
+		found    Preset
+		versions []string
+	)
+	for _, p := range presets {
+		if p.Name != name {
+			continue
+		}
+		versions = append(versions, fmt.Sprintf("v%d", p.Version))
+		if version == 0 || p.Version == version {
+			found = p
+		}
+	}
+	switch {
+	case len(versions) == 0:
+		return Preset{}, fmt.Errorf("preset %s not found", name)
+	case found.Name == "":
+		return Preset{}, fmt.Errorf("preset %s has no version v%d, only %s", name, version, strings.Join(versions, ", "))
+	}
+	return found, nil
+}
+
+// ListPresets returns every version of the built-in and user presets,
+// sorted by name then version
+func ListPresets(baseDir string) ([]Preset, error) {
+	presets := BuiltinPresets()
+	for i := range presets {
+		presets[i].BuiltIn = true
+	}
+	dirs, err := os.ReadDir(filepath.Join(baseDir, PresetsDir))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(baseDir, PresetsDir, dir.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			version, ok := strings.CutSuffix(strings.TrimPrefix(file.Name(), "v"), ".json")
+			if !ok || file.IsDir() {
+				continue
+			}
+			v, err := strconv.Atoi(version)
+			if err != nil {
+				continue
+			}
+			p, err := loadPresetFile(PresetPath(baseDir, dir.Name(), v))
+			if err != nil {
+				return nil, err
+			}
+			if p.Name != dir.Name() || p.Version != v {
+				return nil, fmt.Errorf("preset file %s holds %s", PresetPath(baseDir, dir.Name(), v), p.Ref())
+			}
+			presets = append(presets, p)
+		}
+	}
+	sort.SliceStable(presets, func(i, j int) bool {
+		if presets[i].Name != presets[j].Name {
+			return presets[i].Name < presets[j].Name
+		}
+		return presets[i].Version < presets[j].Version
+	})
+	return presets, nil
+}
+
+func loadPresetFile(path string) (Preset, error) {
+	var p Preset
+	raw, err := os.ReadFile(path) //nolint:gosec // G304: Reading from app's data directory
+	if err != nil {
+		return p, err
+	}
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return p, fmt.Errorf("invalid preset %s: %w", path, err)
+	}
+	if err := p.Verify(); err != nil {
+		return p, fmt.Errorf("invalid preset %s: %w", path, err)
+	}
+	return p, nil
+}
+
+func checkPresetName(name string) error {
+	if name == "" || strings.ContainsAny(name, `/\@`) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid preset name %q", name)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinPresets(t *testing.T) {
+	require := require.New(t)
+	for _, p := range BuiltinPresets() {
+		require.NoError(p.Verify(), p.Name)
+		_, err := p.Genesis.Genesis()
+		require.NoError(err, p.Name)
+	}
+}
+
+func TestPresetVersions(t *testing.T) {
+	require := require.New(t)
+	baseDir := t.TempDir()
+
+	p, err := LoadPreset(baseDir, "gaming")
+	require.NoError(err)
+	require.True(p.BuiltIn)
+	require.Equal("gaming@v1", p.Ref())
+
+	// saving a built-in name adds a version, which becomes the default
+	p.ChainConfig = map[string]interface{}{"pruning-enabled": false}
+	require.NoError(SavePreset(baseDir, &p))
+	require.Equal(2, p.Version)
+	require.FileExists(PresetPath(baseDir, "gaming", 2))
+	latest, err := LoadPreset(baseDir, "gaming")
+	require.NoError(err)
+	require.False(latest.BuiltIn)
+	require.Equal(2, latest.Version)
+	raw, err := latest.ChainConfigJSON()
+	require.NoError(err)
+	var config map[string]interface{}
+	require.NoError(json.Unmarshal(raw, &config))
+	require.Equal(false, config["pruning-enabled"])
+
+	first, err := LoadPreset(baseDir, "gaming@v1")
+	require.NoError(err)
+	require.True(first.BuiltIn)
+	_, err = LoadPreset(baseDir, "gaming@v3")
+	require.ErrorContains(err, "only v1, v2")
+	_, err = LoadPreset(baseDir, "gaming@latest")
+	require.ErrorContains(err, "invalid preset version")
+	_, err = LoadPreset(baseDir, "missing")
+	require.ErrorContains(err, "not found")
+
+	mine := Preset{Name: "mine", Genesis: DefaultGenesisTemplate()}
+	require.NoError(SavePreset(baseDir, &mine))
+	require.Equal(1, mine.Version)
+	presets, err := ListPresets(baseDir)
+	require.NoError(err)
+	var refs []string
+	for _, p := range presets {
+		refs = append(refs, p.Ref())
+	}
+	require.Equal([]string{"archival@v1", "gaming@v1", "gaming@v2", "high-throughput@v1", "low-latency@v1", "mine@v1"}, refs)
+
+	require.ErrorContains(SavePreset(baseDir, &Preset{Name: "a@b", Genesis: DefaultGenesisTemplate()}), "invalid preset name")
+}
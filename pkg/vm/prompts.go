@@ -11,8 +11,8 @@ import (
 
 // AllocationEntry represents an allocation entry in genesis
 type AllocationEntry struct {
-	Address string
-	Balance string
+	Address string `json:"address"`
+	Balance string `json:"balance"`
 }
 
 // EVMGenesisParams contains parameters for Chain EVM genesis
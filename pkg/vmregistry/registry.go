@@ -0,0 +1,295 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package vmregistry manages the VM binaries installed in ~/.lux/vms, one
+// directory per name and version holding the binary and its metadata, so
+// chains can be created from a VM installed once.
+package vmregistry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+)
+
+const (
+	// MetadataFileName is the metadata kept next to each binary
+	MetadataFileName = "vm.json"
+
+	// LocalVersion is the version of local builds installed without one
+	LocalVersion = "local"
+)
+
+// ErrNotInstalled is returned for a VM, or a version of it, that is not in
+// the registry
+var ErrNotInstalled = errors.New("VM not installed")
+
+// VM is a VM binary installed in the registry
+type VM struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	VMID    string `json:"vmid"`
+	// RPCProtocol is the rpcchainvm protocol version the binary speaks
+	RPCProtocol int `json:"rpcProtocol"`
+	// Source is where the binary came from: a release package, URL or path
+	Source      string    `json:"source"`
+	Binary      string    `json:"binary"`
+	SHA256      string    `json:"sha256"`
+	InstalledAt time.Time `json:"installedAt"`
+
+	dir string
+}
+
+// BinaryPath returns the path of the installed binary
+func (v *VM) BinaryPath() string {
+	return filepath.Join(v.dir, v.Binary)
+}
+
+// Ref returns name@version
+func (v *VM) Ref() string {
+	return v.Name + "@" + v.Version
+}
+
+// Registry is a VM registry rooted at a directory
+type Registry struct {
+	dir string
+}
+
+// New returns the registry rooted at dir, created on first install
+func New(dir string) *Registry {
+	return &Registry{dir: dir}
+}
+
+// Dir returns the root of r
+func (r *Registry) Dir() string {
+	return r.dir
+}
+
+// ParseRef splits name[@version], the version being empty when not given
+func ParseRef(ref string) (string, string) {
+	name, version, _ := strings.Cut(ref, "@")
+	return name, version
+}
+
+// Install copies the binary at binaryPath into r as vm.Name at vm.Version
+// (LocalVersion if unset), replacing the same version if installed, and
+// returns the installed VM. The checksum, binary name and install time are
+// set by Install.
+func (r *Registry) Install(binaryPath string, vm VM) (*VM, error) {
+	if vm.Version == "" {
+		vm.Version = LocalVersion
+	}
+	if err := checkPathElement("name", vm.Name); err != nil {
+		return nil, err
+	}
+	if err := checkPathElement("version", vm.Version); err != nil {
+		return nil, err
+	}
+	vm.dir = filepath.Join(r.dir, vm.Name, vm.Version)
+	vm.Binary = vm.Name
+	vm.InstalledAt = time.Now().UTC()
+
+	// the version is staged aside then renamed in place, so a failed install
+	// leaves the installed one untouched
+	if err := os.MkdirAll(filepath.Join(r.dir, vm.Name), 0o750); err != nil {
+		return nil, err
+	}
+	stageDir, err := os.MkdirTemp(filepath.Join(r.dir, vm.Name), ".install-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(stageDir) }()
+	if vm.SHA256, err = copyBinary(binaryPath, filepath.Join(stageDir, vm.Binary)); err != nil {
+		return nil, fmt.Errorf("failed to copy %s: %w", binaryPath, err)
+	}
+	data, err := json.MarshalIndent(vm, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(stageDir, MetadataFileName), data, 0o600); err != nil {
+		return nil, err
+	}
+	if err := os.RemoveAll(vm.dir); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(stageDir, vm.dir); err != nil {
+		return nil, err
+	}
+	return &vm, nil
+}
+
+// List returns the installed VMs by name, newest version first
+func (r *Registry) List() ([]*VM, error) {
+	names, err := os.ReadDir(r.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	vms := []*VM{}
+	for _, name := range names {
+		if !name.IsDir() || strings.HasPrefix(name.Name(), ".") {
+			continue
+		}
+		versions, err := r.versions(name.Name())
+		if err != nil {
+			return nil, err
+		}
+		vms = append(vms, versions...)
+	}
+	return vms, nil
+}
+
+// Get returns version of the VM name, or its newest version if version is
+// empty
+func (r *Registry) Get(name, version string) (*VM, error) {
+	if err := checkPathElement("name", name); err != nil {
+		return nil, err
+	}
+	if version != "" {
+		if err := checkPathElement("version", version); err != nil {
+			return nil, err
+		}
+		vm, err := readVM(filepath.Join(r.dir, name, version))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s@%s", ErrNotInstalled, name, version)
+		}
+		return vm, err
+	}
+	versions, err := r.versions(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotInstalled, name)
+	}
+	return versions[0], nil
+}
+
+// Uninstall removes version of the VM name, or all its versions if version
+// is empty, and returns the VMs removed
+func (r *Registry) Uninstall(name, version string) ([]*VM, error) {
+	var removed []*VM
+	if version != "" {
+		vm, err := r.Get(name, version)
+		if err != nil {
+			return nil, err
+		}
+		removed = []*VM{vm}
+	} else {
+		versions, err := r.versions(name)
+		if err != nil {
+			return nil, err
+		}
+		if len(versions) == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrNotInstalled, name)
+		}
+		removed = versions
+	}
+	for _, vm := range removed {
+		if err := os.RemoveAll(vm.dir); err != nil {
+			return nil, err
+		}
+	}
+	// drop the name once its last version is gone
+	nameDir := filepath.Join(r.dir, name)
+	if entries, err := os.ReadDir(nameDir); err == nil && len(entries) == 0 {
+		_ = os.Remove(nameDir)
+	}
+	return removed, nil
+}
+
+// versions returns the installed versions of name, newest first: semantic
+// versions by precedence, then other versions by install time
+func (r *Registry) versions(name string) ([]*VM, error) {
+	if err := checkPathElement("name", name); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(filepath.Join(r.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	vms := []*VM{}
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		vm, err := readVM(filepath.Join(r.dir, name, entry.Name()))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		vms = append(vms, vm)
+	}
+	sort.Slice(vms, func(i, j int) bool {
+		vi, vj := semver.IsValid(vms[i].Version), semver.IsValid(vms[j].Version)
+		if vi && vj {
+			return semver.Compare(vms[i].Version, vms[j].Version) > 0
+		}
+		if vi != vj {
+			return vi
+		}
+		return vms[i].InstalledAt.After(vms[j].InstalledAt)
+	})
+	return vms, nil
+}
+
+func readVM(dir string) (*VM, error) {
+	data, err := os.ReadFile(filepath.Join(dir, MetadataFileName)) //nolint:gosec // G304: Reading the registry's metadata
+	if err != nil {
+		return nil, err
+	}
+	vm := &VM{dir: dir}
+	if err := json.Unmarshal(data, vm); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", filepath.Join(dir, MetadataFileName), err)
+	}
+	return vm, nil
+}
+
+// copyBinary copies src to an executable dst and returns its sha256
+func copyBinary(src, dst string) (string, error) {
+	in, err := os.Open(src) //nolint:gosec // G304: Copying the binary the user installs
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = in.Close() }()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o755) //nolint:gosec // G302: VM binaries are executables
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(out, h), in); err != nil {
+		_ = out.Close()
+		return "", err
+	}
+	if err := out.Close(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// checkPathElement rejects names and versions that aren't a single path
+// element of the registry
+func checkPathElement(kind, s string) error {
+	if s == "" || s == "." || s == ".." || strings.HasPrefix(s, ".") ||
+		strings.ContainsAny(s, `/\@`) {
+		return fmt.Errorf("invalid VM %s %q", kind, s)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vmregistry
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeBinary(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "build")
+	if err := os.WriteFile(path, []byte(content), 0o755); err != nil { //nolint:gosec // test binary
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestInstallGetUninstall(t *testing.T) {
+	r := New(filepath.Join(t.TempDir(), "vms"))
+
+	vms, err := r.List()
+	if err != nil || len(vms) != 0 {
+		t.Fatalf("empty registry: got %v, %v", vms, err)
+	}
+
+	for _, v := range []VM{
+		{Name: "myvm", Version: "v1.2.0", RPCProtocol: 42, Source: "org/myvm@v1.2.0"},
+		{Name: "myvm", Version: "v1.10.0", RPCProtocol: 42, Source: "org/myvm@v1.10.0"},
+		{Name: "myvm", Source: "./build/myvm"},
+		{Name: "othervm", Version: "v0.1.0"},
+	} {
+		if _, err := r.Install(writeBinary(t, v.Source), v); err != nil {
+			t.Fatalf("install %s: %v", v.Name, err)
+		}
+	}
+
+	vms, err = r.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var refs []string
+	for _, vm := range vms {
+		refs = append(refs, vm.Ref())
+	}
+	want := []string{"myvm@v1.10.0", "myvm@v1.2.0", "myvm@local", "othervm@v0.1.0"}
+	if len(refs) != len(want) {
+		t.Fatalf("listed %v, want %v", refs, want)
+	}
+	for i := range want {
+		if refs[i] != want[i] {
+			t.Fatalf("listed %v, want %v", refs, want)
+		}
+	}
+
+	latest, err := r.Get("myvm", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.Version != "v1.10.0" || latest.RPCProtocol != 42 || latest.SHA256 == "" {
+		t.Fatalf("unexpected latest %+v", latest)
+	}
+	data, err := os.ReadFile(latest.BinaryPath())
+	if err != nil || string(data) != "org/myvm@v1.10.0" {
+		t.Fatalf("binary %q, %v", data, err)
+	}
+	info, err := os.Stat(latest.BinaryPath())
+	if err != nil || info.Mode()&0o111 == 0 {
+		t.Fatalf("binary not executable: %v", err)
+	}
+
+	if _, err := r.Get("myvm", "v9.9.9"); !errors.Is(err, ErrNotInstalled) {
+		t.Fatalf("expected ErrNotInstalled, got %v", err)
+	}
+	if _, err := r.Get("../escape", ""); err == nil {
+		t.Fatal("expected an invalid name error")
+	}
+
+	removed, err := r.Uninstall("myvm", "v1.10.0")
+	if err != nil || len(removed) != 1 {
+		t.Fatalf("uninstall version: %v, %v", removed, err)
+	}
+	if latest, err = r.Get("myvm", ""); err != nil || latest.Version != "v1.2.0" {
+		t.Fatalf("latest after uninstall: %+v, %v", latest, err)
+	}
+	if removed, err = r.Uninstall("myvm", ""); err != nil || len(removed) != 2 {
+		t.Fatalf("uninstall all: %v, %v", removed, err)
+	}
+	if _, err := os.Stat(filepath.Join(r.Dir(), "myvm")); !os.IsNotExist(err) {
+		t.Fatalf("name directory left behind: %v", err)
+	}
+	if _, err := r.Uninstall("myvm", ""); !errors.Is(err, ErrNotInstalled) {
+		t.Fatalf("expected ErrNotInstalled, got %v", err)
+	}
+}
+
+func TestInstallReplacesVersion(t *testing.T) {
+	r := New(t.TempDir())
+	if _, err := r.Install(writeBinary(t, "first"), VM{Name: "myvm"}); err != nil {
+		t.Fatal(err)
+	}
+	first, err := r.Get("myvm", LocalVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Install(writeBinary(t, "second"), VM{Name: "myvm"}); err != nil {
+		t.Fatal(err)
+	}
+	second, err := r.Get("myvm", LocalVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.SHA256 == second.SHA256 {
+		t.Fatal("expected the binary to be replaced")
+	}
+
+	// a failed install keeps the installed version
+	if _, err := r.Install(filepath.Join(t.TempDir(), "missing"), VM{Name: "myvm"}); err == nil {
+		t.Fatal("expected an error for a missing binary")
+	}
+	kept, err := r.Get("myvm", LocalVersion)
+	if err != nil || kept.SHA256 != second.SHA256 {
+		t.Fatalf("install failure replaced the VM: %+v, %v", kept, err)
+	}
+	vms, err := r.List()
+	if err != nil || len(vms) != 1 {
+		t.Fatalf("staging left behind: %v, %v", vms, err)
+	}
+}
+
+func TestParseRef(t *testing.T) {
+	for ref, want := range map[string][2]string{
+		"myvm":        {"myvm", ""},
+		"myvm@v1.0.0": {"myvm", "v1.0.0"},
+		"Lux EVM@dev": {"Lux EVM", "dev"},
+	} {
+		name, version := ParseRef(ref)
+		if name != want[0] || version != want[1] {
+			t.Errorf("ParseRef(%q) = %q, %q, want %q, %q", ref, name, version, want[0], want[1])
+		}
+	}
+}
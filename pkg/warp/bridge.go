@@ -0,0 +1,122 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/luxfi/constants"
+)
+
+// BridgesFileName is the file of a chain directory recording the token
+// bridges the chain is an endpoint of, by network
+const BridgesFileName = "bridges.json"
+
+// NativeToken is the token of a bridge moving the native token of its home
+// chain
+const NativeToken = "native"
+
+// BridgeEndpoint is the token home or remote contract of a bridge on a chain
+type BridgeEndpoint struct {
+	Chain        string `json:"chain"`
+	BlockchainID string `json:"blockchainId"`
+	RPCURL       string `json:"rpcUrl"`
+	Address      string `json:"address"`
+}
+
+// Bridge moves a token between its home chain, which holds it as
+// collateral, and a remote chain, which mints its ERC-20 representation
+type Bridge struct {
+	// Token is the ERC-20 bridged, or NativeToken
+	Token string `json:"token"`
+	// WrappedToken is the ERC-20 wrapping the native token of a native
+	// bridge
+	WrappedToken string         `json:"wrappedToken,omitempty"`
+	Symbol       string         `json:"symbol"`
+	Decimals     uint8          `json:"decimals"`
+	Home         BridgeEndpoint `json:"home"`
+	Remote       BridgeEndpoint `json:"remote"`
+	// Relayer is the account delivering the messages of the bridge
+	Relayer string `json:"relayer,omitempty"`
+}
+
+// Route returns the endpoints a transfer from the chain from to the chain
+// to goes through, and whether b links them
+func (b Bridge) Route(from, to string) (BridgeEndpoint, BridgeEndpoint, bool) {
+	switch {
+	case b.Home.Chain == from && b.Remote.Chain == to:
+		return b.Home, b.Remote, true
+	case b.Remote.Chain == from && b.Home.Chain == to:
+		return b.Remote, b.Home, true
+	}
+	return BridgeEndpoint{}, BridgeEndpoint{}, false
+}
+
+// BridgesPath returns the bridges file of the chain directory chainDir
+func BridgesPath(chainDir string) string {
+	return filepath.Join(chainDir, BridgesFileName)
+}
+
+// LoadBridges returns the bridges recorded at path, by network
+func LoadBridges(path string) (map[string][]Bridge, error) {
+	bridges := map[string][]Bridge{}
+	raw, err := os.ReadFile(path) //nolint:gosec // G304: Reading from app's data directory
+	if errors.Is(err, os.ErrNotExist) {
+		return bridges, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &bridges); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return bridges, nil
+}
+
+// AddBridge records bridge on network at path, replacing the bridge of
+// the same token between the same chains
+func AddBridge(path string, network string, bridge Bridge) error {
+	bridges, err := LoadBridges(path)
+	if err != nil {
+		return err
+	}
+	networkBridges := bridges[network]
+	replaced := false
+	for i, b := range networkBridges {
+		if strings.EqualFold(b.Token, bridge.Token) && b.Home.Chain == bridge.Home.Chain && b.Remote.Chain == bridge.Remote.Chain {
+			networkBridges[i] = bridge
+			replaced = true
+		}
+	}
+	if !replaced {
+		networkBridges = append(networkBridges, bridge)
+	}
+	bridges[network] = networkBridges
+	raw, err := json.MarshalIndent(bridges, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, raw, constants.WriteReadReadPerms)
+}
+
+// FindBridges returns the bridges of bridges linking the chains from and
+// to, of token when given
+func FindBridges(bridges []Bridge, from, to, token string) []Bridge {
+	var found []Bridge
+	for _, b := range bridges {
+		if _, _, ok := b.Route(from, to); !ok {
+			continue
+		}
+		if token != "" && !strings.EqualFold(token, b.Token) && token != b.Symbol {
+			continue
+		}
+		found = append(found, b)
+	}
+	return found
+}
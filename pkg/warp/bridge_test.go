@@ -0,0 +1,55 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBridges(t *testing.T) {
+	require := require.New(t)
+	path := BridgesPath(t.TempDir())
+	bridges, err := LoadBridges(path)
+	require.NoError(err)
+	require.Empty(bridges)
+
+	token := "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+	erc20 := Bridge{
+		Token:    token,
+		Symbol:   "TOK",
+		Decimals: 18,
+		Home:     BridgeEndpoint{Chain: "a", Address: "0x01"},
+		Remote:   BridgeEndpoint{Chain: "b", Address: "0x02"},
+	}
+	native := Bridge{
+		Token:  NativeToken,
+		Symbol: "WLUX",
+		Home:   BridgeEndpoint{Chain: "b", Address: "0x03"},
+		Remote: BridgeEndpoint{Chain: "a", Address: "0x04"},
+	}
+	require.NoError(AddBridge(path, "Local Network", erc20))
+	require.NoError(AddBridge(path, "Local Network", native))
+	redeployed := erc20
+	redeployed.Remote.Address = "0x05"
+	require.NoError(AddBridge(path, "Local Network", redeployed))
+
+	bridges, err = LoadBridges(path)
+	require.NoError(err)
+	require.Equal([]Bridge{redeployed, native}, bridges["Local Network"])
+
+	from, to, ok := native.Route("a", "b")
+	require.True(ok)
+	require.Equal(native.Remote, from)
+	require.Equal(native.Home, to)
+	_, _, ok = native.Route("a", "c")
+	require.False(ok)
+
+	require.Len(FindBridges(bridges["Local Network"], "b", "a", ""), 2)
+	require.Equal([]Bridge{native}, FindBridges(bridges["Local Network"], "b", "a", "WLUX"))
+	require.Equal([]Bridge{redeployed}, FindBridges(bridges["Local Network"], "a", "b", "0x5fbdb2315678afecb367f032d93f642f64180aa3"))
+	require.Empty(FindBridges(bridges["Local Network"], "a", "c", ""))
+
+}
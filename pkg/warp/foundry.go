@@ -18,6 +18,7 @@ var (
 	foundryVersion   = "v0.2.1"
 	foundryupPath    = utils.ExpandHome("~/.foundry/bin/foundryup")
 	defaultForgePath = utils.ExpandHome("~/.foundry/bin/forge")
+	defaultAnvilPath = utils.ExpandHome("~/.foundry/bin/anvil")
 )
 
 func FoundryIsInstalled() bool {
@@ -36,6 +37,17 @@ func GetForgePath() (string, error) {
 	return "", fmt.Errorf("forge is not installed")
 }
 
+// GetAnvilPath returns the path of anvil, the local EVM node of Foundry
+func GetAnvilPath() (string, error) {
+	if utils.FileExists(defaultAnvilPath) {
+		return defaultAnvilPath, nil
+	}
+	if path, err := exec.LookPath("anvil"); err == nil {
+		return path, nil
+	}
+	return "", fmt.Errorf("anvil is not installed")
+}
+
 func InstallFoundry() error {
 	ux.Logger.PrintToUser("Installing Foundry")
 	downloadCmd := exec.Command( //nolint:gosec // G204: Running curl with known URL
@@ -0,0 +1,65 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/warp/relayer"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/ethclient"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+)
+
+// MessengerEndpoints dials the chains deployed to network that have a Warp
+// messenger. The returned function closes their clients.
+func MessengerEndpoints(app *application.Lux, network models.Network) ([]relayer.Endpoint, func(), error) {
+	entries, err := os.ReadDir(app.GetChainsDir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+	var (
+		endpoints []relayer.Endpoint
+		clients   []*ethclient.Client
+	)
+	closeClients := func() {
+		for _, client := range clients {
+			client.Close()
+		}
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || !app.SidecarExists(entry.Name()) {
+			continue
+		}
+		sc, err := app.LoadSidecar(entry.Name())
+		if err != nil {
+			continue
+		}
+		networkData := sc.Networks[network.Name()]
+		if networkData.BlockchainID == ids.Empty || !common.IsHexAddress(networkData.TeleporterMessengerAddress) {
+			continue
+		}
+		rpcURL := models.GetRPCEndpoint(network.Endpoint(), networkData.BlockchainID.String())
+		if len(networkData.RPCEndpoints) > 0 {
+			rpcURL = networkData.RPCEndpoints[0]
+		}
+		client, err := ethclient.Dial(rpcURL)
+		if err != nil {
+			closeClients()
+			return nil, nil, fmt.Errorf("failed to connect to %s at %s: %w", entry.Name(), rpcURL, err)
+		}
+		clients = append(clients, client)
+		endpoints = append(endpoints, relayer.Endpoint{
+			Name:         entry.Name(),
+			BlockchainID: common.Hash(networkData.BlockchainID),
+			Messenger:    common.HexToAddress(networkData.TeleporterMessengerAddress),
+			Client:       client,
+		})
+	}
+	return endpoints, closeClients, nil
+}
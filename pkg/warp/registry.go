@@ -0,0 +1,239 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common/hexutil"
+	"github.com/luxfi/geth/rpc"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/contract"
+	luxWarp "github.com/luxfi/warp"
+	"github.com/luxfi/warp/payload"
+)
+
+// registryQuorumNumerator is the percentage of the chain's stake that must
+// sign a registry update
+const registryQuorumNumerator = 67
+
+// RegistryEntry is a messenger version registered in a Warp registry
+type RegistryEntry struct {
+	Version   uint64
+	Messenger crypto.Address
+}
+
+// RegistryChain is a chain of a network with a Warp registry
+type RegistryChain struct {
+	Name         string
+	BlockchainID ids.ID
+	RPCURL       string
+	Registry     crypto.Address
+	Messenger    crypto.Address
+	// Entries are the messenger versions the registry knows, by version
+	Entries []RegistryEntry
+}
+
+// latestVersion returns the latest version registered on the chain
+func (c RegistryChain) latestVersion() uint64 {
+	latest := uint64(0)
+	for _, e := range c.Entries {
+		if e.Version > latest {
+			latest = e.Version
+		}
+	}
+	return latest
+}
+
+// knows reports whether messenger is registered on the chain
+func (c RegistryChain) knows(messenger crypto.Address) bool {
+	for _, e := range c.Entries {
+		if e.Messenger == messenger {
+			return true
+		}
+	}
+	return false
+}
+
+// RegistrySync is what a chain's registry misses of the messengers of its
+// network
+type RegistrySync struct {
+	Chain RegistryChain
+	// Register are the entries to add to the registry, in order
+	Register []RegistryEntry
+	// Undeployed are the messengers the registry misses that have no code on
+	// the chain, so can't be registered there
+	Undeployed []crypto.Address
+}
+
+// PlanRegistrySync returns, for every chain, the messengers used or
+// registered on the other chains that its registry misses. hasCode reports
+// whether a messenger is deployed to a chain. Missing messengers are given
+// the next versions of the registry, older messengers (the ones with lower
+// versions elsewhere) first.
+func PlanRegistrySync(
+	chains []RegistryChain,
+	hasCode func(chain RegistryChain, messenger crypto.Address) bool,
+) []RegistrySync {
+	// the highest version each messenger has in any registry, messengers
+	// registered nowhere sorting last
+	rank := map[crypto.Address]uint64{}
+	for _, c := range chains {
+		for _, e := range c.Entries {
+			if r, ok := rank[e.Messenger]; !ok || e.Version > r {
+				rank[e.Messenger] = e.Version
+			}
+		}
+	}
+	for _, c := range chains {
+		if _, ok := rank[c.Messenger]; !ok && c.Messenger != (crypto.Address{}) {
+			rank[c.Messenger] = ^uint64(0)
+		}
+	}
+	messengers := make([]crypto.Address, 0, len(rank))
+	for m := range rank {
+		messengers = append(messengers, m)
+	}
+	sort.Slice(messengers, func(i, j int) bool {
+		if rank[messengers[i]] != rank[messengers[j]] {
+			return rank[messengers[i]] < rank[messengers[j]]
+		}
+		return bytes.Compare(messengers[i].Bytes(), messengers[j].Bytes()) < 0
+	})
+
+	syncs := make([]RegistrySync, 0, len(chains))
+	for _, c := range chains {
+		s := RegistrySync{Chain: c}
+		next := c.latestVersion() + 1
+		for _, m := range messengers {
+			if c.knows(m) {
+				continue
+			}
+			if !hasCode(c, m) {
+				s.Undeployed = append(s.Undeployed, m)
+				continue
+			}
+			s.Register = append(s.Register, RegistryEntry{Version: next, Messenger: m})
+			next++
+		}
+		syncs = append(syncs, s)
+	}
+	return syncs
+}
+
+// GetRegistryEntries returns the messenger versions registered in the
+// registry, by version
+func GetRegistryEntries(rpcURL string, registry crypto.Address) ([]RegistryEntry, error) {
+	out, err := contract.CallToMethod(rpcURL, registry, "latestVersion()->(uint256)")
+	if err != nil {
+		return nil, err
+	}
+	latest, err := contract.GetSmartContractCallResult[*big.Int]("latestVersion", out)
+	if err != nil {
+		return nil, err
+	}
+	if !latest.IsUint64() {
+		return nil, fmt.Errorf("invalid latest version %s of registry %s", latest, registry.Hex())
+	}
+	entries := make([]RegistryEntry, 0, latest.Uint64())
+	for version := uint64(1); version <= latest.Uint64(); version++ {
+		out, err := contract.CallToMethod(
+			rpcURL,
+			registry,
+			"getAddressFromVersion(uint256)->(address)",
+			new(big.Int).SetUint64(version),
+		)
+		if err != nil {
+			return nil, err
+		}
+		messenger, err := contract.GetSmartContractCallResult[crypto.Address]("getAddressFromVersion", out)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, RegistryEntry{Version: version, Messenger: messenger})
+	}
+	return entries, nil
+}
+
+// NewRegistryEntryMessage returns the Warp message adding entry to the
+// registry of blockchainID. The message originates from the validators of
+// the chain itself, so it must be signed as an off-chain message.
+func NewRegistryEntryMessage(
+	networkID uint32,
+	blockchainID ids.ID,
+	registry crypto.Address,
+	entry RegistryEntry,
+) (*luxWarp.UnsignedMessage, error) {
+	// abi.encode(ProtocolRegistryEntry(version, protocolAddress), registry)
+	call := make([]byte, 96)
+	new(big.Int).SetUint64(entry.Version).FillBytes(call[:32])
+	copy(call[44:64], entry.Messenger.Bytes())
+	copy(call[76:96], registry.Bytes())
+	addressedCall, err := payload.NewAddressedCall(crypto.Address{}.Bytes(), call)
+	if err != nil {
+		return nil, err
+	}
+	return luxWarp.NewUnsignedMessage(networkID, blockchainID, addressedCall.Bytes())
+}
+
+// GetAggregateSignature asks the chain at rpcURL to aggregate the signatures
+// of its validators on the off-chain message messageID
+func GetAggregateSignature(ctx context.Context, rpcURL string, messageID ids.ID) (*luxWarp.Message, error) {
+	client, err := rpc.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	var signed hexutil.Bytes
+	if err := client.CallContext(
+		ctx,
+		&signed,
+		"warp_getMessageAggregateSignature",
+		messageID,
+		registryQuorumNumerator,
+		"",
+	); err != nil {
+		return nil, fmt.Errorf("failed to aggregate the signatures of message %s: %w", messageID, err)
+	}
+	return luxWarp.ParseMessage(signed)
+}
+
+// AddProtocolVersion registers the messenger version of the signed
+// registry entry message in registry
+func AddProtocolVersion(
+	rpcURL string,
+	privateKey string,
+	registry crypto.Address,
+	message *luxWarp.Message,
+) error {
+	_, _, err := contract.TxToMethodWithWarpMessage(
+		rpcURL,
+		false,
+		crypto.Address{},
+		privateKey,
+		registry,
+		message,
+		big.NewInt(0),
+		"add protocol version to registry",
+		nil,
+		"addProtocolVersion(uint32)",
+		uint32(0),
+	)
+	return err
+}
+
+// formatAddresses joins addresses for display
+func formatAddresses(addresses []crypto.Address) string {
+	hexes := make([]string, 0, len(addresses))
+	for _, a := range addresses {
+		hexes = append(hexes, a.Hex())
+	}
+	return strings.Join(hexes, ", ")
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/ids"
+	luxWarp "github.com/luxfi/warp"
+	"github.com/luxfi/warp/payload"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	messengerV1 = crypto.HexToAddress("0x253b2784c75e510dD0fF1da844684a1aC0aa5fcf")
+	messengerV2 = crypto.HexToAddress("0x0FEEDC0DE0000000000000000000000000000000")
+	registry    = crypto.HexToAddress("0xF86Cb19Ad8405AEFa7d09C778215D2Cb6eBfB228")
+)
+
+func TestPlanRegistrySync(t *testing.T) {
+	require := require.New(t)
+	old := RegistryChain{
+		Name:      "old",
+		Registry:  registry,
+		Messenger: messengerV1,
+		Entries:   []RegistryEntry{{Version: 1, Messenger: messengerV1}},
+	}
+	upgraded := RegistryChain{
+		Name:      "upgraded",
+		Registry:  registry,
+		Messenger: messengerV2,
+		Entries:   []RegistryEntry{{Version: 1, Messenger: messengerV1}, {Version: 2, Messenger: messengerV2}},
+	}
+	added := RegistryChain{
+		Name:      "added",
+		Registry:  registry,
+		Messenger: messengerV2,
+	}
+	deployed := func(c RegistryChain, messenger crypto.Address) bool {
+		return c.Name != "old" || messenger == messengerV1
+	}
+
+	syncs := PlanRegistrySync([]RegistryChain{old, upgraded, added}, deployed)
+	require.Len(syncs, 3)
+	require.Empty(syncs[0].Register)
+	require.Equal([]crypto.Address{messengerV2}, syncs[0].Undeployed)
+	require.Empty(syncs[1].Register)
+	require.Empty(syncs[1].Undeployed)
+	require.Equal([]RegistryEntry{{Version: 1, Messenger: messengerV1}, {Version: 2, Messenger: messengerV2}}, syncs[2].Register)
+	require.Empty(syncs[2].Undeployed)
+}
+
+func TestNewRegistryEntryMessage(t *testing.T) {
+	require := require.New(t)
+	blockchainID := ids.GenerateTestID()
+	message, err := NewRegistryEntryMessage(1337, blockchainID, registry, RegistryEntry{Version: 2, Messenger: messengerV2})
+	require.NoError(err)
+	require.Equal(uint32(1337), message.NetworkID)
+	require.Equal(blockchainID, message.SourceChainID)
+
+	call, err := payload.ParseAddressedCall(message.Payload)
+	require.NoError(err)
+	require.Equal(crypto.Address{}.Bytes(), call.SourceAddress)
+	require.Len(call.Payload, 96)
+	require.Equal(byte(2), call.Payload[31])
+	require.Equal(messengerV2.Bytes(), call.Payload[44:64])
+	require.Equal(registry.Bytes(), call.Payload[76:96])
+}
+
+func TestStageOffChainMessages(t *testing.T) {
+	require := require.New(t)
+	configPath := filepath.Join(t.TempDir(), "chain-config.json")
+	require.NoError(os.WriteFile(configPath, []byte(`{"log-level": "info"}`), 0o600))
+	message, err := luxWarp.NewUnsignedMessage(1337, ids.GenerateTestID(), []byte("registry entry"))
+	require.NoError(err)
+
+	staged, err := stageOffChainMessages(configPath, []*luxWarp.UnsignedMessage{message})
+	require.NoError(err)
+	require.True(staged)
+	staged, err = stageOffChainMessages(configPath, []*luxWarp.UnsignedMessage{message})
+	require.NoError(err)
+	require.False(staged)
+
+	raw, err := os.ReadFile(configPath)
+	require.NoError(err)
+	var config map[string]interface{}
+	require.NoError(json.Unmarshal(raw, &config))
+	require.Equal("info", config["log-level"])
+	require.Len(config[offChainMessagesKey], 1)
+}
@@ -0,0 +1,174 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/luxfi/constants"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/ids"
+)
+
+// ConfigChain is a chain the relayer relays messages from and to
+type ConfigChain struct {
+	ChainID      ids.ID
+	BlockchainID ids.ID
+	RPCURL       string
+	// Messengers are the messenger versions deployed to the chain
+	Messengers []common.Address
+}
+
+// SyncConfig adds to the relayer config at path the chains it does not
+// relay from or to yet, and the messengers its sources do not listen to. New
+// entries copy the settings, such as the relayer key, of the existing ones.
+// It reports whether the config changed; a missing config is left alone.
+func SyncConfig(path string, chains []ConfigChain) (bool, error) {
+	raw, err := os.ReadFile(path) //nolint:gosec // G304: Reading from app's data directory
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return false, fmt.Errorf("failed unmarshalling relayer config at %s: %w", path, err)
+	}
+	changed, err := syncConfig(config, chains)
+	if err != nil || !changed {
+		return false, err
+	}
+	raw, err = json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, raw, constants.WriteReadReadPerms)
+}
+
+// AccountKey returns the private key the relayer at the config at path
+// pays for delivering messages to blockchainID with, empty when the config
+// is missing or does not deliver to it
+func AccountKey(path string, blockchainID ids.ID) (string, error) {
+	raw, err := os.ReadFile(path) //nolint:gosec // G304: Reading from app's data directory
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return "", fmt.Errorf("failed unmarshalling relayer config at %s: %w", path, err)
+	}
+	destinations, _ := config["destination-blockchains"].([]interface{})
+	destination := findConfigBlockchain(destinations, blockchainID)
+	if destination == nil {
+		return "", nil
+	}
+	key, _ := destination["account-private-key"].(string)
+	return key, nil
+}
+
+func syncConfig(config map[string]interface{}, chains []ConfigChain) (bool, error) {
+	sources, _ := config["source-blockchains"].([]interface{})
+	destinations, _ := config["destination-blockchains"].([]interface{})
+	changed := false
+	for _, c := range chains {
+		source := findConfigBlockchain(sources, c.BlockchainID)
+		if source == nil {
+			source = map[string]interface{}{"vm": "evm"}
+			if len(sources) > 0 {
+				source = cloneConfig(sources[0])
+				delete(source, "ws-endpoint")
+			}
+			source["subnet-id"] = c.ChainID.String()
+			source["blockchain-id"] = c.BlockchainID.String()
+			source["rpc-endpoint"] = map[string]interface{}{"base-url": c.RPCURL}
+			source["message-contracts"] = map[string]interface{}{}
+			sources = append(sources, source)
+			changed = true
+		}
+		contracts, ok := source["message-contracts"].(map[string]interface{})
+		if !ok {
+			contracts = map[string]interface{}{}
+			source["message-contracts"] = contracts
+		}
+		for _, messenger := range c.Messengers {
+			if hasMessageContract(contracts, messenger) {
+				continue
+			}
+			contracts[messenger.Hex()] = messageContractSettings(sources)
+			changed = true
+		}
+
+		if findConfigBlockchain(destinations, c.BlockchainID) != nil {
+			continue
+		}
+		if len(destinations) == 0 {
+			return false, errors.New("the relayer config has no destination to copy the relayer key from")
+		}
+		destination := cloneConfig(destinations[0])
+		destination["subnet-id"] = c.ChainID.String()
+		destination["blockchain-id"] = c.BlockchainID.String()
+		destination["rpc-endpoint"] = map[string]interface{}{"base-url": c.RPCURL}
+		destinations = append(destinations, destination)
+		changed = true
+	}
+	config["source-blockchains"] = sources
+	config["destination-blockchains"] = destinations
+	return changed, nil
+}
+
+// findConfigBlockchain returns the entry of blockchains for blockchainID
+func findConfigBlockchain(blockchains []interface{}, blockchainID ids.ID) map[string]interface{} {
+	for _, b := range blockchains {
+		if entry, ok := b.(map[string]interface{}); ok && entry["blockchain-id"] == blockchainID.String() {
+			return entry
+		}
+	}
+	return nil
+}
+
+func hasMessageContract(contracts map[string]interface{}, messenger common.Address) bool {
+	for address := range contracts {
+		if strings.EqualFold(address, messenger.Hex()) {
+			return true
+		}
+	}
+	return false
+}
+
+// messageContractSettings returns the settings of a new message contract:
+// those of an existing one, or the messenger defaults
+func messageContractSettings(sources []interface{}) interface{} {
+	for _, s := range sources {
+		source, _ := s.(map[string]interface{})
+		contracts, _ := source["message-contracts"].(map[string]interface{})
+		for _, settings := range contracts {
+			return cloneConfig(settings)
+		}
+	}
+	return map[string]interface{}{
+		"message-format": "teleporter",
+		"settings": map[string]interface{}{
+			"reward-address": common.Address{}.Hex(),
+		},
+	}
+}
+
+// cloneConfig deep copies a decoded JSON object
+func cloneConfig(v interface{}) map[string]interface{} {
+	raw, _ := json.Marshal(v)
+	var clone map[string]interface{}
+	_ = json.Unmarshal(raw, &clone)
+	if clone == nil {
+		clone = map[string]interface{}{}
+	}
+	return clone
+}
@@ -0,0 +1,100 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/ids"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncConfig(t *testing.T) {
+	require := require.New(t)
+	existing := ConfigChain{
+		ChainID:      ids.GenerateTestID(),
+		BlockchainID: ids.GenerateTestID(),
+		RPCURL:       "http://127.0.0.1:9630/ext/bc/a/rpc",
+		Messengers:   []common.Address{messenger},
+	}
+	newMessenger := common.HexToAddress("0x0FEEDC0DE0000000000000000000000000000000")
+	added := ConfigChain{
+		ChainID:      ids.GenerateTestID(),
+		BlockchainID: ids.GenerateTestID(),
+		RPCURL:       "http://127.0.0.1:9630/ext/bc/b/rpc",
+		Messengers:   []common.Address{messenger, newMessenger},
+	}
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(os.WriteFile(path, []byte(`{
+		"log-level": "info",
+		"source-blockchains": [{
+			"subnet-id": "`+existing.ChainID.String()+`",
+			"blockchain-id": "`+existing.BlockchainID.String()+`",
+			"vm": "evm",
+			"rpc-endpoint": {"base-url": "`+existing.RPCURL+`"},
+			"ws-endpoint": {"base-url": "ws://127.0.0.1:9630/ext/bc/a/ws"},
+			"message-contracts": {
+				"`+messenger.Hex()+`": {"message-format": "teleporter", "settings": {"reward-address": "0x9011E888251AB053B7bD1cdB598Db4f9DEd94714"}}
+			}
+		}],
+		"destination-blockchains": [{
+			"subnet-id": "`+existing.ChainID.String()+`",
+			"blockchain-id": "`+existing.BlockchainID.String()+`",
+			"vm": "evm",
+			"rpc-endpoint": {"base-url": "`+existing.RPCURL+`"},
+			"account-private-key": "0x56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027"
+		}]
+	}`), 0o600))
+
+	changed, err := SyncConfig(path, []ConfigChain{existing, added})
+	require.NoError(err)
+	require.True(changed)
+
+	raw, err := os.ReadFile(path)
+	require.NoError(err)
+	var config struct {
+		LogLevel          string `json:"log-level"`
+		SourceBlockchains []struct {
+			BlockchainID     string                     `json:"blockchain-id"`
+			RPCEndpoint      map[string]string          `json:"rpc-endpoint"`
+			WSEndpoint       map[string]string          `json:"ws-endpoint"`
+			MessageContracts map[string]json.RawMessage `json:"message-contracts"`
+		} `json:"source-blockchains"`
+		DestinationBlockchains []struct {
+			BlockchainID      string `json:"blockchain-id"`
+			AccountPrivateKey string `json:"account-private-key"`
+		} `json:"destination-blockchains"`
+	}
+	require.NoError(json.Unmarshal(raw, &config))
+	require.Equal("info", config.LogLevel)
+	require.Len(config.SourceBlockchains, 2)
+	source := config.SourceBlockchains[1]
+	require.Equal(added.BlockchainID.String(), source.BlockchainID)
+	require.Equal(added.RPCURL, source.RPCEndpoint["base-url"])
+	require.Nil(source.WSEndpoint)
+	require.Len(source.MessageContracts, 2)
+	require.JSONEq(string(config.SourceBlockchains[0].MessageContracts[messenger.Hex()]), string(source.MessageContracts[newMessenger.Hex()]))
+	require.Len(config.DestinationBlockchains, 2)
+	require.Equal(added.BlockchainID.String(), config.DestinationBlockchains[1].BlockchainID)
+	require.Equal(config.DestinationBlockchains[0].AccountPrivateKey, config.DestinationBlockchains[1].AccountPrivateKey)
+
+	changed, err = SyncConfig(path, []ConfigChain{existing, added})
+	require.NoError(err)
+	require.False(changed)
+
+	changed, err = SyncConfig(filepath.Join(t.TempDir(), "missing.json"), []ConfigChain{added})
+	require.NoError(err)
+	require.False(changed)
+
+	key, err := AccountKey(path, added.BlockchainID)
+	require.NoError(err)
+	require.Equal("0x56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027", key)
+	key, err = AccountKey(path, ids.GenerateTestID())
+	require.NoError(err)
+	require.Empty(key)
+}
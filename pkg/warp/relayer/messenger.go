@@ -0,0 +1,188 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/luxfi/cli/pkg/precompiles"
+	"github.com/luxfi/crypto"
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+)
+
+// teleporterMessage is the ABI tuple of a TeleporterMessage
+const teleporterMessage = "(uint256,address,bytes32,address,uint256,address[],(uint256,address)[],bytes)"
+
+// Topics of the Warp messenger events
+var (
+	SendTopic            = eventTopic("SendCrossChainMessage(bytes32,bytes32," + teleporterMessage + ",(address,uint256))")
+	ReceiveTopic         = eventTopic("ReceiveCrossChainMessage(bytes32,bytes32,address,address," + teleporterMessage + ")")
+	ExecutedTopic        = eventTopic("MessageExecuted(bytes32,bytes32)")
+	ExecutionFailedTopic = eventTopic("MessageExecutionFailed(bytes32,bytes32," + teleporterMessage + ")")
+)
+
+// warpPrecompile is the address of the Warp precompile, which emits the
+// Warp messages the messengers send
+var warpPrecompile = common.Address(precompiles.WarpPrecompile)
+
+// messageReceivedSelector selects messageReceived(bytes32)->(bool)
+var messageReceivedSelector = crypto.Keccak256([]byte("messageReceived(bytes32)"))[:4]
+
+var errInvalidEvent = errors.New("invalid messenger event")
+
+func eventTopic(signature string) common.Hash {
+	return common.Hash(crypto.Keccak256Hash([]byte(signature)))
+}
+
+// Client is the part of an ethclient.Client the relayer checks use
+type Client interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// Message is a message a chain sent through its messenger
+type Message struct {
+	ID                      common.Hash
+	DestinationBlockchainID common.Hash
+	Nonce                   uint64
+	TxHash                  common.Hash
+	BlockNumber             uint64
+}
+
+// Delivery is the receipt of a message on its destination chain
+type Delivery struct {
+	TxHash             common.Hash
+	BlockNumber        uint64
+	SourceBlockchainID common.Hash
+	Deliverer          common.Address
+	// Executed is false when the message was received but its execution
+	// failed, and can be retried
+	Executed bool
+}
+
+// parseSend decodes a SendCrossChainMessage log
+func parseSend(log types.Log) (Message, error) {
+	if len(log.Topics) != 3 || log.Topics[0] != SendTopic {
+		return Message{}, errInvalidEvent
+	}
+	// the message tuple is the first, dynamic, argument
+	nonce, err := tupleNonce(log.Data, 0)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{
+		ID:                      log.Topics[1],
+		DestinationBlockchainID: log.Topics[2],
+		Nonce:                   nonce,
+		TxHash:                  log.TxHash,
+		BlockNumber:             log.BlockNumber,
+	}, nil
+}
+
+// parseReceive decodes a ReceiveCrossChainMessage log
+func parseReceive(log types.Log) (Delivery, uint64, error) {
+	if len(log.Topics) != 4 || log.Topics[0] != ReceiveTopic {
+		return Delivery{}, 0, errInvalidEvent
+	}
+	// the message tuple follows the reward redeemer
+	nonce, err := tupleNonce(log.Data, 1)
+	if err != nil {
+		return Delivery{}, 0, err
+	}
+	return Delivery{
+		TxHash:             log.TxHash,
+		BlockNumber:        log.BlockNumber,
+		SourceBlockchainID: log.Topics[2],
+		Deliverer:          common.BytesToAddress(log.Topics[3].Bytes()),
+	}, nonce, nil
+}
+
+// tupleNonce returns the nonce, first field, of the message tuple whose
+// offset is the word at index arg of data
+func tupleNonce(data []byte, arg int) (uint64, error) {
+	head := arg * common.HashLength
+	if len(data) < head+common.HashLength {
+		return 0, fmt.Errorf("%w: data too short", errInvalidEvent)
+	}
+	offset := new(big.Int).SetBytes(data[head : head+common.HashLength])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(data)-common.HashLength) {
+		return 0, fmt.Errorf("%w: message offset out of range", errInvalidEvent)
+	}
+	start := offset.Uint64()
+	nonce := new(big.Int).SetBytes(data[start : start+common.HashLength])
+	if !nonce.IsUint64() {
+		return 0, fmt.Errorf("%w: nonce out of range", errInvalidEvent)
+	}
+	return nonce.Uint64(), nil
+}
+
+// SentMessages returns the messages receipt sent through messenger
+func SentMessages(receipt *types.Receipt, messenger common.Address) []Message {
+	var messages []Message
+	for _, log := range receipt.Logs {
+		if log.Address != messenger || len(log.Topics) == 0 || log.Topics[0] != SendTopic {
+			continue
+		}
+		if m, err := parseSend(*log); err == nil {
+			messages = append(messages, m)
+		}
+	}
+	return messages
+}
+
+// MessageReceived asks messenger whether it received message id
+func MessageReceived(ctx context.Context, client Client, messenger common.Address, id common.Hash) (bool, error) {
+	out, err := client.CallContract(ctx, ethereum.CallMsg{
+		To:   &messenger,
+		Data: append(append([]byte{}, messageReceivedSelector...), id.Bytes()...),
+	}, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to call messageReceived: %w", err)
+	}
+	if len(out) != common.HashLength {
+		return false, fmt.Errorf("unexpected messageReceived result %x", out)
+	}
+	return out[common.HashLength-1] == 1, nil
+}
+
+// FindMessage returns message id as source sent it
+func FindMessage(ctx context.Context, source Endpoint, id common.Hash) (Message, error) {
+	logs, err := source.Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: big.NewInt(0),
+		Addresses: []common.Address{source.Messenger},
+		Topics:    [][]common.Hash{{SendTopic}, {id}},
+	})
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to get messages sent by %s: %w", source.Name, err)
+	}
+	if len(logs) == 0 {
+		return Message{}, fmt.Errorf("%w: %s sent no message %s", ethereum.NotFound, source.Name, id.Hex())
+	}
+	return parseSend(logs[0])
+}
+
+// WarpLog returns the Warp precompile log of receipt carrying message id,
+// the last one before the messenger event of its send
+func WarpLog(receipt *types.Receipt, messenger common.Address, id common.Hash) (*types.Log, error) {
+	var warpLog *types.Log
+	for _, log := range receipt.Logs {
+		switch {
+		case log.Address == warpPrecompile:
+			warpLog = log
+		case log.Address == messenger && len(log.Topics) > 1 && log.Topics[0] == SendTopic && log.Topics[1] == id:
+			if warpLog == nil {
+				return nil, fmt.Errorf("transaction %s has no Warp message for %s", receipt.TxHash.Hex(), id.Hex())
+			}
+			return warpLog, nil
+		}
+	}
+	return nil, fmt.Errorf("transaction %s did not send message %s", receipt.TxHash.Hex(), id.Hex())
+}
@@ -5,8 +5,13 @@
 package relayer
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/shirou/gopsutil/process"
 )
 
 // Cleanup cleans up relayer state files.
@@ -29,3 +34,25 @@ func Cleanup(runPath, logPath, storageDir string) error {
 
 	return nil
 }
+
+// Running reports whether the relayer recorded in the run file at runPath
+// is alive
+func Running(runPath string) (bool, error) {
+	run, err := os.ReadFile(runPath) //nolint:gosec // G304: Reading from app's data directory
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	var rf struct {
+		Pid int `json:"pid"`
+	}
+	if err := json.Unmarshal(run, &rf); err != nil {
+		return false, fmt.Errorf("failed unmarshalling relayer run file at %s: %w", runPath, err)
+	}
+	if rf.Pid == 0 {
+		return false, nil
+	}
+	return process.PidExists(int32(rf.Pid)) //nolint:gosec // G115: PID values are within int32 range
+}
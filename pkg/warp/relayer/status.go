@@ -0,0 +1,115 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/common"
+)
+
+// Health is how a route keeps up with its messages
+type Health string
+
+const (
+	// Healthy routes have no message waiting longer than the stall window
+	Healthy Health = "healthy"
+	// Idle routes sent no messages in the scanned blocks
+	Idle Health = "idle"
+	// Stalled routes have a message waiting longer than the stall window
+	Stalled Health = "stalled"
+)
+
+// Endpoint is a chain with a Warp messenger
+type Endpoint struct {
+	Name         string
+	BlockchainID common.Hash
+	Messenger    common.Address
+	Client       Client
+}
+
+// Route is the direction messages flow from one chain to another
+type Route struct {
+	Source      Endpoint
+	Destination Endpoint
+}
+
+// StatusOptions tune a route check
+type StatusOptions struct {
+	// Blocks is how many source blocks back to look for sent messages
+	Blocks uint64
+	// StallBlocks is how many source blocks a message may wait for delivery
+	// before the route counts as stalled
+	StallBlocks uint64
+}
+
+// RouteStatus is the delivery state of the messages a route sent in the
+// scanned blocks
+type RouteStatus struct {
+	Health    Health
+	FromBlock uint64
+	ToBlock   uint64
+	Sent      int
+	Delivered int
+	Pending   int
+	// LastDeliveredNonce is the highest nonce delivered, valid when
+	// Delivered is not zero
+	LastDeliveredNonce uint64
+	// OldestPending is the first message still waiting, if any
+	OldestPending *Message
+}
+
+// CheckRoute scans the source of route for the messages it sent to the
+// destination and asks the destination which it received
+func CheckRoute(ctx context.Context, route Route, opts StatusOptions) (RouteStatus, error) {
+	head, err := route.Source.Client.BlockNumber(ctx)
+	if err != nil {
+		return RouteStatus{}, fmt.Errorf("failed to get %s height: %w", route.Source.Name, err)
+	}
+	s := RouteStatus{ToBlock: head}
+	if head > opts.Blocks {
+		s.FromBlock = head - opts.Blocks
+	}
+	logs, err := route.Source.Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(s.FromBlock),
+		ToBlock:   new(big.Int).SetUint64(head),
+		Addresses: []common.Address{route.Source.Messenger},
+		Topics:    [][]common.Hash{{SendTopic}, nil, {route.Destination.BlockchainID}},
+	})
+	if err != nil {
+		return RouteStatus{}, fmt.Errorf("failed to get messages sent by %s: %w", route.Source.Name, err)
+	}
+	for _, log := range logs {
+		m, err := parseSend(log)
+		if err != nil {
+			return RouteStatus{}, err
+		}
+		s.Sent++
+		received, err := MessageReceived(ctx, route.Destination.Client, route.Destination.Messenger, m.ID)
+		if err != nil {
+			return RouteStatus{}, fmt.Errorf("failed to check delivery on %s: %w", route.Destination.Name, err)
+		}
+		if !received {
+			s.Pending++
+			if s.OldestPending == nil {
+				s.OldestPending = &m
+			}
+			continue
+		}
+		s.Delivered++
+		s.LastDeliveredNonce = max(s.LastDeliveredNonce, m.Nonce)
+	}
+	switch {
+	case s.Sent == 0:
+		s.Health = Idle
+	case s.OldestPending != nil && head-s.OldestPending.BlockNumber > opts.StallBlocks:
+		s.Health = Stalled
+	default:
+		s.Health = Healthy
+	}
+	return s, nil
+}
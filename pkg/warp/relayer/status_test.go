@@ -0,0 +1,176 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"slices"
+	"testing"
+
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+var messenger = common.HexToAddress("0x253b2784c75e510dD0fF1da844684a1aC0aa5fcf")
+
+// fakeChain serves the calls of Client from memory
+type fakeChain struct {
+	head     uint64
+	logs     []types.Log
+	received map[common.Hash]bool
+	receipts map[common.Hash]*types.Receipt
+}
+
+func newFakeChain(head uint64) *fakeChain {
+	return &fakeChain{head: head, received: map[common.Hash]bool{}, receipts: map[common.Hash]*types.Receipt{}}
+}
+
+func (c *fakeChain) BlockNumber(context.Context) (uint64, error) {
+	return c.head, nil
+}
+
+func (c *fakeChain) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	var logs []types.Log
+	for _, log := range c.logs {
+		if len(q.Addresses) > 0 && !slices.Contains(q.Addresses, log.Address) {
+			continue
+		}
+		if q.FromBlock != nil && log.BlockNumber < q.FromBlock.Uint64() {
+			continue
+		}
+		if q.ToBlock != nil && log.BlockNumber > q.ToBlock.Uint64() {
+			continue
+		}
+		if matchTopics(log.Topics, q.Topics) {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+func matchTopics(topics []common.Hash, filter [][]common.Hash) bool {
+	for i, want := range filter {
+		if len(want) == 0 {
+			continue
+		}
+		if i >= len(topics) || !slices.Contains(want, topics[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *fakeChain) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	if !bytes.Equal(msg.Data[:4], messageReceivedSelector) {
+		return nil, ethereum.NotFound
+	}
+	out := make([]byte, common.HashLength)
+	if c.received[common.BytesToHash(msg.Data[4:])] {
+		out[common.HashLength-1] = 1
+	}
+	return out, nil
+}
+
+func (c *fakeChain) TransactionReceipt(_ context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipt, ok := c.receipts[txHash]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return receipt, nil
+}
+
+func word(n uint64) []byte {
+	return common.BigToHash(new(big.Int).SetUint64(n)).Bytes()
+}
+
+// send records a message sent to dest at block
+func (c *fakeChain) send(id, dest common.Hash, nonce, block uint64) types.Log {
+	// message offset and fee info, then the message starting with its nonce
+	data := slices.Concat(word(3*common.HashLength), word(0), word(0), word(nonce))
+	log := types.Log{
+		Address:     messenger,
+		Topics:      []common.Hash{SendTopic, id, dest},
+		Data:        data,
+		BlockNumber: block,
+		TxHash:      common.BytesToHash(append([]byte("tx"), id.Bytes()[:4]...)),
+	}
+	c.logs = append(c.logs, log)
+	return log
+}
+
+// deliver records the delivery of a message from source at block
+func (c *fakeChain) deliver(id, source common.Hash, nonce, block uint64, executed bool) {
+	deliverer := common.HexToAddress("0x00000000000000000000000000000000000000de")
+	c.received[id] = true
+	txHash := common.BytesToHash(append([]byte("rx"), id.Bytes()[:4]...))
+	c.logs = append(c.logs, types.Log{
+		Address:     messenger,
+		Topics:      []common.Hash{ReceiveTopic, id, source, common.BytesToHash(deliverer.Bytes())},
+		Data:        slices.Concat(word(0), word(2*common.HashLength), word(nonce)),
+		BlockNumber: block,
+		TxHash:      txHash,
+	})
+	outcome := ExecutedTopic
+	if !executed {
+		outcome = ExecutionFailedTopic
+	}
+	c.logs = append(c.logs, types.Log{
+		Address:     messenger,
+		Topics:      []common.Hash{outcome, id, source},
+		BlockNumber: block,
+		TxHash:      txHash,
+	})
+}
+
+func TestCheckRoute(t *testing.T) {
+	require := require.New(t)
+	sourceID, destID, otherID := common.Hash{0xa}, common.Hash{0xb}, common.Hash{0xc}
+	source, dest := newFakeChain(100), newFakeChain(50)
+	route := Route{
+		Source:      Endpoint{Name: "A", BlockchainID: sourceID, Messenger: messenger, Client: source},
+		Destination: Endpoint{Name: "B", BlockchainID: destID, Messenger: messenger, Client: dest},
+	}
+	opts := StatusOptions{Blocks: 50, StallBlocks: 10}
+
+	s, err := CheckRoute(context.Background(), route, opts)
+	require.NoError(err)
+	require.Equal(Idle, s.Health)
+	require.Equal(uint64(50), s.FromBlock)
+
+	source.send(common.Hash{1}, destID, 1, 40) // before the scanned blocks
+	source.send(common.Hash{2}, destID, 2, 60)
+	source.send(common.Hash{3}, otherID, 3, 70)
+	source.send(common.Hash{4}, destID, 4, 95)
+	dest.deliver(common.Hash{2}, sourceID, 2, 30, true)
+
+	s, err = CheckRoute(context.Background(), route, opts)
+	require.NoError(err)
+	require.Equal(Healthy, s.Health)
+	require.Equal(2, s.Sent)
+	require.Equal(1, s.Delivered)
+	require.Equal(1, s.Pending)
+	require.Equal(uint64(2), s.LastDeliveredNonce)
+	require.Equal(common.Hash{4}, s.OldestPending.ID)
+
+	source.head = 120
+	s, err = CheckRoute(context.Background(), route, opts)
+	require.NoError(err)
+	require.Equal(Stalled, s.Health)
+}
+
+func TestTupleNonce(t *testing.T) {
+	require := require.New(t)
+	nonce, err := tupleNonce(slices.Concat(word(common.HashLength), word(7)), 0)
+	require.NoError(err)
+	require.Equal(uint64(7), nonce)
+
+	_, err = tupleNonce(word(common.HashLength), 0)
+	require.ErrorIs(err, errInvalidEvent)
+	_, err = tupleNonce(nil, 1)
+	require.ErrorIs(err, errInvalidEvent)
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/common"
+)
+
+// maxLogLines bounds the relayer log lines kept per message
+const maxLogLines = 20
+
+// MessageTrace follows a message from its source to its destination
+type MessageTrace struct {
+	Message
+	// Destination names the destination chain, empty when it is not known
+	Destination string
+	// RelayerLog holds the last relayer log lines mentioning the message
+	RelayerLog []string
+	// Delivery is nil while the message is not delivered
+	Delivery *Delivery
+}
+
+// Trace follows the messages transaction txHash sent on source: it reads
+// them from the receipt, looks them up in the relayer log at logPath and
+// checks their delivery on the destination chains lookup knows
+func Trace(
+	ctx context.Context,
+	source Endpoint,
+	txHash common.Hash,
+	lookup func(blockchainID common.Hash) (Endpoint, bool),
+	logPath string,
+) ([]MessageTrace, error) {
+	receipt, err := source.Client.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipt of %s on %s: %w", txHash.Hex(), source.Name, err)
+	}
+	messages := SentMessages(receipt, source.Messenger)
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("transaction %s sent no Warp message through %s", txHash.Hex(), source.Messenger.Hex())
+	}
+	traces := make([]MessageTrace, len(messages))
+	for i, m := range messages {
+		traces[i].Message = m
+		if traces[i].RelayerLog, err = LogLines(logPath, maxLogLines, m.ID.Hex()[2:], txHash.Hex()[2:]); err != nil {
+			return nil, err
+		}
+		dest, ok := lookup(m.DestinationBlockchainID)
+		if !ok {
+			continue
+		}
+		traces[i].Destination = dest.Name
+		if traces[i].Delivery, err = FindDelivery(ctx, dest, m.ID); err != nil {
+			return nil, err
+		}
+	}
+	return traces, nil
+}
+
+// FindDelivery returns the delivery of message id on dest, nil when dest
+// did not receive it yet
+func FindDelivery(ctx context.Context, dest Endpoint, id common.Hash) (*Delivery, error) {
+	received, err := MessageReceived(ctx, dest.Client, dest.Messenger, id)
+	if err != nil || !received {
+		return nil, err
+	}
+	query := ethereum.FilterQuery{
+		FromBlock: big.NewInt(0),
+		Addresses: []common.Address{dest.Messenger},
+		Topics:    [][]common.Hash{{ReceiveTopic}, {id}},
+	}
+	logs, err := dest.Client.FilterLogs(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delivery on %s: %w", dest.Name, err)
+	}
+	if len(logs) == 0 {
+		return nil, fmt.Errorf("%s received message %s but has no delivery event for it", dest.Name, id.Hex())
+	}
+	delivery, _, err := parseReceive(logs[len(logs)-1])
+	if err != nil {
+		return nil, err
+	}
+	// a failed execution can be retried, the last outcome decides
+	query.Topics = [][]common.Hash{{ExecutedTopic, ExecutionFailedTopic}, {id}}
+	if logs, err = dest.Client.FilterLogs(ctx, query); err != nil {
+		return nil, fmt.Errorf("failed to get execution on %s: %w", dest.Name, err)
+	}
+	if len(logs) > 0 {
+		delivery.Executed = logs[len(logs)-1].Topics[0] == ExecutedTopic
+	}
+	return &delivery, nil
+}
+
+// LogLines returns the last limit lines of the log at path containing any
+// of needles, ignoring case. A missing log has no lines.
+func LogLines(path string, limit int, needles ...string) ([]string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	for i := range needles {
+		needles[i] = strings.ToLower(needles[i])
+	}
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.ToLower(scanner.Text())
+		for _, needle := range needles {
+			if strings.Contains(line, needle) {
+				lines = append(lines, scanner.Text())
+				break
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read relayer log: %w", err)
+	}
+	if len(lines) > limit {
+		lines = lines[len(lines)-limit:]
+	}
+	return lines, nil
+}
@@ -0,0 +1,107 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrace(t *testing.T) {
+	require := require.New(t)
+	sourceID, destID, otherID := common.Hash{0xa}, common.Hash{0xb}, common.Hash{0xc}
+	source, dest := newFakeChain(100), newFakeChain(50)
+	sent := source.send(common.Hash{1}, destID, 1, 90)
+	unknown := source.send(common.Hash{2}, otherID, 2, 90)
+	unknown.TxHash = sent.TxHash
+	txHash := sent.TxHash
+	source.receipts[txHash] = &types.Receipt{TxHash: txHash, Logs: []*types.Log{&sent, &unknown}}
+
+	logPath := filepath.Join(t.TempDir(), "relayer.log")
+	require.NoError(os.WriteFile(logPath, []byte(strings.Join([]string{
+		`{"msg":"processing block","height":90}`,
+		`{"msg":"relaying message","messageID":"` + strings.ToUpper(common.Hash{1}.Hex()[2:]) + `"}`,
+		`{"msg":"unrelated"}`,
+	}, "\n")), 0o600))
+
+	sourceEndpoint := Endpoint{Name: "A", BlockchainID: sourceID, Messenger: messenger, Client: source}
+	lookup := func(id common.Hash) (Endpoint, bool) {
+		if id != destID {
+			return Endpoint{}, false
+		}
+		return Endpoint{Name: "B", BlockchainID: destID, Messenger: messenger, Client: dest}, true
+	}
+
+	traces, err := Trace(context.Background(), sourceEndpoint, txHash, lookup, logPath)
+	require.NoError(err)
+	require.Len(traces, 2)
+	require.Equal("B", traces[0].Destination)
+	require.Equal(uint64(1), traces[0].Nonce)
+	require.Len(traces[0].RelayerLog, 1)
+	require.Nil(traces[0].Delivery)
+	require.Empty(traces[1].Destination)
+
+	dest.deliver(common.Hash{1}, sourceID, 1, 40, false)
+	traces, err = Trace(context.Background(), sourceEndpoint, txHash, lookup, logPath)
+	require.NoError(err)
+	delivery := traces[0].Delivery
+	require.NotNil(delivery)
+	require.Equal(uint64(40), delivery.BlockNumber)
+	require.Equal(sourceID, delivery.SourceBlockchainID)
+	require.False(delivery.Executed)
+
+	// a retried execution succeeded
+	dest.logs = append(dest.logs, types.Log{Address: messenger, Topics: []common.Hash{ExecutedTopic, {1}, sourceID}, BlockNumber: 45})
+	delivery, err = FindDelivery(context.Background(), Endpoint{Name: "B", Messenger: messenger, Client: dest}, common.Hash{1})
+	require.NoError(err)
+	require.True(delivery.Executed)
+
+	_, err = Trace(context.Background(), sourceEndpoint, common.Hash{9}, lookup, logPath)
+	require.ErrorContains(err, "failed to get receipt")
+
+	lines, err := LogLines(filepath.Join(t.TempDir(), "missing.log"), maxLogLines, "x")
+	require.NoError(err)
+	require.Empty(lines)
+}
+
+func TestFindMessage(t *testing.T) {
+	require := require.New(t)
+	destID := common.Hash{0xb}
+	source := newFakeChain(100)
+	sent := source.send(common.Hash{1}, destID, 7, 90)
+	endpoint := Endpoint{Name: "A", Messenger: messenger, Client: source}
+
+	m, err := FindMessage(context.Background(), endpoint, common.Hash{1})
+	require.NoError(err)
+	require.Equal(uint64(7), m.Nonce)
+	require.Equal(destID, m.DestinationBlockchainID)
+	require.Equal(sent.TxHash, m.TxHash)
+
+	_, err = FindMessage(context.Background(), endpoint, common.Hash{2})
+	require.ErrorIs(err, ethereum.NotFound)
+
+	// the Warp message of a send precedes its messenger event
+	first := &types.Log{Address: warpPrecompile, Data: []byte{1}}
+	second := &types.Log{Address: warpPrecompile, Data: []byte{2}}
+	other := source.send(common.Hash{3}, destID, 8, 90)
+	receipt := &types.Receipt{Logs: []*types.Log{first, &sent, second, &other}}
+	log, err := WarpLog(receipt, messenger, common.Hash{1})
+	require.NoError(err)
+	require.Equal(first, log)
+	log, err = WarpLog(receipt, messenger, common.Hash{3})
+	require.NoError(err)
+	require.Equal(second, log)
+	_, err = WarpLog(&types.Receipt{Logs: []*types.Log{&sent}}, messenger, common.Hash{1})
+	require.ErrorContains(err, "has no Warp message")
+	_, err = WarpLog(receipt, messenger, common.Hash{4})
+	require.ErrorContains(err, "did not send message")
+}
@@ -0,0 +1,356 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/luxfi/cli/pkg/application"
+	"github.com/luxfi/cli/pkg/utils"
+	"github.com/luxfi/cli/pkg/ux"
+	"github.com/luxfi/cli/pkg/warp/genesis"
+	"github.com/luxfi/cli/pkg/warp/relayer"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/common/hexutil"
+	"github.com/luxfi/geth/ethclient"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/models"
+	luxWarp "github.com/luxfi/warp"
+)
+
+// offChainMessagesKey is the chain config setting of the Warp messages the
+// validators of a chain sign without them being sent on chain
+const offChainMessagesKey = "warp-off-chain-messages"
+
+// SyncOptions configures SyncRegistries
+type SyncOptions struct {
+	// PrivateKey returns the key paying for the registrations on a chain.
+	// Without it, registrations are only staged.
+	PrivateKey func(chainName string) (string, error)
+	// RelayerConfigPath is the relayer config to add the chains and
+	// messengers to, when it exists
+	RelayerConfigPath string
+	// Parallelism is how many chains are dialed and synced at once,
+	// utils.DefaultParallelism when 0
+	Parallelism int
+}
+
+func (o SyncOptions) parallelism() int {
+	if o.Parallelism == 0 {
+		return utils.DefaultParallelism
+	}
+	return o.Parallelism
+}
+
+// syncChain is a chain deployed to the network being synced
+type syncChain struct {
+	RegistryChain
+	chainID ids.ID
+	client  *ethclient.Client
+}
+
+// SyncRegistries registers every messenger version used on a chain of
+// network in the registries of the other chains it is deployed to, and adds
+// the chains and messengers to the relayer config, so that chains deployed
+// after others can exchange messages with them.
+//
+// A registry only accepts versions signed by its own chain's validators, as
+// off-chain Warp messages. The messages are first staged in the chain
+// config, then, once the chain's nodes are restarted with it, signed and
+// sent to the registry by a later sync.
+func SyncRegistries(app *application.Lux, network models.Network, opts SyncOptions) error {
+	chains, err := loadSyncChains(app, network, opts.parallelism())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, c := range chains {
+			c.client.Close()
+		}
+	}()
+	if len(chains) < 2 {
+		ux.Logger.PrintToUser("Warp registries on %s: fewer than two chains with a Warp registry, nothing to sync", network.Name())
+		return syncRelayerConfig(chains, nil, opts.RelayerConfigPath)
+	}
+
+	ctx, cancel := utils.GetAPILargeContext()
+	defer cancel()
+	clients := map[ids.ID]*ethclient.Client{}
+	registryChains := make([]RegistryChain, 0, len(chains))
+	for _, c := range chains {
+		clients[c.BlockchainID] = c.client
+		registryChains = append(registryChains, c.RegistryChain)
+	}
+	deployed := func(c RegistryChain, messenger crypto.Address) bool {
+		code, err := clients[c.BlockchainID].CodeAt(ctx, common.Address(messenger), nil)
+		return err == nil && len(code) > 0
+	}
+	syncs := PlanRegistrySync(registryChains, deployed)
+
+	var outOfSync []RegistrySync
+	for _, s := range syncs {
+		if len(s.Undeployed) > 0 {
+			ux.Logger.PrintToUser("%s: messengers %s are not deployed to the chain, deploy them to relay their messages",
+				s.Chain.Name, formatAddresses(s.Undeployed))
+		}
+		if len(s.Register) == 0 {
+			ux.Logger.PrintToUser("%s: registry %s is in sync", s.Chain.Name, s.Chain.Registry.Hex())
+			continue
+		}
+		outOfSync = append(outOfSync, s)
+	}
+	syncErr := utils.ForEachChain(outOfSync, opts.parallelism(),
+		func(s RegistrySync) string { return s.Chain.Name },
+		func(_ int, s RegistrySync) error { return syncRegistry(ctx, app, network, s, opts) },
+	)
+	return errors.Join(syncErr, syncRelayerConfig(chains, syncs, opts.RelayerConfigPath))
+}
+
+// syncRegistry adds the missing entries of s to the registry of its chain,
+// staging their messages in the chain config first
+func syncRegistry(ctx context.Context, app *application.Lux, network models.Network, s RegistrySync, opts SyncOptions) error {
+	messages := make([]*luxWarp.UnsignedMessage, 0, len(s.Register))
+	for _, entry := range s.Register {
+		message, err := NewRegistryEntryMessage(network.ID(), s.Chain.BlockchainID, s.Chain.Registry, entry)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, message)
+	}
+	configPath := app.GetChainConfigPath(s.Chain.Name)
+	staged, err := stageOffChainMessages(configPath, messages)
+	if err != nil {
+		return err
+	}
+	if staged {
+		ux.Logger.PrintToUser("%s: staged the registration of messengers %s in %s",
+			s.Chain.Name, formatEntries(s.Register), configPath)
+		ux.Logger.PrintToUser("  restart the chain's nodes with it, then run 'lux warp sync-registries' to register them")
+		return nil
+	}
+	if opts.PrivateKey == nil {
+		ux.Logger.PrintToUser("%s: registry %s misses messengers %s, run 'lux warp sync-registries' to register them",
+			s.Chain.Name, s.Chain.Registry.Hex(), formatEntries(s.Register))
+		return nil
+	}
+	privateKey, err := opts.PrivateKey(s.Chain.Name)
+	if err != nil {
+		return err
+	}
+	for i, entry := range s.Register {
+		signed, err := GetAggregateSignature(ctx, s.Chain.RPCURL, messages[i].ID())
+		if err != nil {
+			return err
+		}
+		if err := AddProtocolVersion(s.Chain.RPCURL, privateKey, s.Chain.Registry, signed); err != nil {
+			return fmt.Errorf("failed to register messenger %s as version %d: %w", entry.Messenger.Hex(), entry.Version, err)
+		}
+		ux.Logger.GreenCheckmarkToUser("%s: registered messenger %s as version %d", s.Chain.Name, entry.Messenger.Hex(), entry.Version)
+	}
+	return nil
+}
+
+// stageOffChainMessages adds the messages missing from the off-chain
+// messages of the chain config at configPath. It reports whether any was
+// missing.
+func stageOffChainMessages(configPath string, messages []*luxWarp.UnsignedMessage) (bool, error) {
+	config := map[string]interface{}{}
+	raw, err := os.ReadFile(configPath) //nolint:gosec // G304: Reading from app's data directory
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(raw, &config); err != nil {
+			return false, fmt.Errorf("failed unmarshalling chain config at %s: %w", configPath, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return false, err
+	}
+	staged, _ := config[offChainMessagesKey].([]interface{})
+	added := false
+	for _, message := range messages {
+		encoded := hexutil.Encode(message.Bytes())
+		found := false
+		for _, s := range staged {
+			if str, ok := s.(string); ok && strings.EqualFold(str, encoded) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			staged = append(staged, encoded)
+			added = true
+		}
+	}
+	if !added {
+		return false, nil
+	}
+	config[offChainMessagesKey] = staged
+	raw, err = json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(configPath, raw, constants.WriteReadReadPerms)
+}
+
+// syncRelayerConfig adds the chains and the messengers deployed to them to
+// the relayer config at path
+func syncRelayerConfig(chains []syncChain, syncs []RegistrySync, path string) error {
+	if path == "" || len(chains) == 0 {
+		return nil
+	}
+	configChains := make([]relayer.ConfigChain, 0, len(chains))
+	for i, c := range chains {
+		messengers := []common.Address{common.Address(c.Messenger)}
+		for _, e := range c.Entries {
+			if e.Messenger != c.Messenger {
+				messengers = append(messengers, common.Address(e.Messenger))
+			}
+		}
+		if i < len(syncs) {
+			for _, e := range syncs[i].Register {
+				messengers = append(messengers, common.Address(e.Messenger))
+			}
+		}
+		configChains = append(configChains, relayer.ConfigChain{
+			ChainID:      c.chainID,
+			BlockchainID: c.BlockchainID,
+			RPCURL:       c.RPCURL,
+			Messengers:   messengers,
+		})
+	}
+	changed, err := relayer.SyncConfig(path, configChains)
+	if err != nil {
+		return fmt.Errorf("failed to update the relayer config at %s: %w", path, err)
+	}
+	if changed {
+		ux.Logger.PrintToUser("Updated the relayer config at %s, restart the relayer to apply it", path)
+	}
+	return nil
+}
+
+// loadSyncChains dials the chains deployed to network with a Warp messenger
+// and registry, at most parallelism at once, recording in their sidecar the
+// genesis predeployed ones found on chains deployed since the last sync
+func loadSyncChains(app *application.Lux, network models.Network, parallelism int) ([]syncChain, error) {
+	entries, err := os.ReadDir(app.GetChainsDir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	type deployedChain struct {
+		name string
+		sc   models.Sidecar
+	}
+	var deployed []deployedChain
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || !app.SidecarExists(entry.Name()) {
+			continue
+		}
+		sc, err := app.LoadSidecar(entry.Name())
+		if err != nil || sc.Networks[network.Name()].BlockchainID == ids.Empty {
+			continue
+		}
+		deployed = append(deployed, deployedChain{name: entry.Name(), sc: sc})
+	}
+
+	loaded := make([]*syncChain, len(deployed))
+	err = utils.ForEachChain(deployed, parallelism,
+		func(d deployedChain) string { return d.name },
+		func(i int, d deployedChain) error {
+			var err error
+			loaded[i], err = loadSyncChain(app, network, d.name, d.sc)
+			return err
+		},
+	)
+	var chains []syncChain
+	for _, c := range loaded {
+		if c != nil {
+			chains = append(chains, *c)
+		}
+	}
+	if err != nil {
+		for _, c := range chains {
+			c.client.Close()
+		}
+		return nil, err
+	}
+	return chains, nil
+}
+
+// loadSyncChain dials the chain name, of sidecar sc, returning nil when it
+// has no Warp messenger and registry
+func loadSyncChain(app *application.Lux, network models.Network, name string, sc models.Sidecar) (*syncChain, error) {
+	networkData := sc.Networks[network.Name()]
+	rpcURL := models.GetRPCEndpoint(network.Endpoint(), networkData.BlockchainID.String())
+	if len(networkData.RPCEndpoints) > 0 {
+		rpcURL = networkData.RPCEndpoints[0]
+	}
+	client, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", rpcURL, err)
+	}
+	if networkData.TeleporterMessengerAddress == "" || networkData.TeleporterRegistryAddress == "" {
+		messenger, registry, err := predeployedWarpContracts(client)
+		if err != nil || messenger == "" || registry == "" {
+			client.Close()
+			return nil, nil
+		}
+		networkData.TeleporterMessengerAddress = messenger
+		networkData.TeleporterRegistryAddress = registry
+		sc.Networks[network.Name()] = networkData
+		if err := app.UpdateSidecar(&sc); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	c := &syncChain{
+		RegistryChain: RegistryChain{
+			Name:         name,
+			BlockchainID: networkData.BlockchainID,
+			RPCURL:       rpcURL,
+			Registry:     crypto.HexToAddress(networkData.TeleporterRegistryAddress),
+			Messenger:    crypto.HexToAddress(networkData.TeleporterMessengerAddress),
+		},
+		chainID: networkData.ChainID,
+		client:  client,
+	}
+	c.Entries, err = GetRegistryEntries(rpcURL, c.Registry)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to read the registry: %w", err)
+	}
+	return c, nil
+}
+
+// predeployedWarpContracts returns the addresses of the messenger and
+// registry predeployed in the chain's genesis, if they have code
+func predeployedWarpContracts(client *ethclient.Client) (string, string, error) {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	addresses := []string{genesis.MessengerContractAddress, genesis.RegistryContractAddress}
+	for i, address := range addresses {
+		code, err := client.CodeAt(ctx, common.HexToAddress(address), nil)
+		if err != nil {
+			return "", "", err
+		}
+		if len(code) == 0 {
+			addresses[i] = ""
+		}
+	}
+	return addresses[0], addresses[1], nil
+}
+
+// formatEntries formats registry entries for display
+func formatEntries(entries []RegistryEntry) string {
+	formatted := make([]string, 0, len(entries))
+	for _, e := range entries {
+		formatted = append(formatted, fmt.Sprintf("%s (v%d)", e.Messenger.Hex(), e.Version))
+	}
+	return strings.Join(formatted, ", ")
+}
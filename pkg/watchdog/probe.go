@@ -0,0 +1,118 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package watchdog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RPCProber probes a node through the info API of NodeURL and reads
+// validator balances from the platform API of PChainURL
+type RPCProber struct {
+	NodeURL   string
+	PChainURL string
+	Client    *http.Client
+}
+
+// NewRPCProber returns a prober of the node at nodeURL
+func NewRPCProber(nodeURL, pChainURL string) *RPCProber {
+	if pChainURL == "" {
+		pChainURL = nodeURL
+	}
+	return &RPCProber{
+		NodeURL:   strings.TrimSuffix(nodeURL, "/"),
+		PChainURL: strings.TrimSuffix(pChainURL, "/"),
+		Client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// NodeID returns the NodeID of the node
+func (p *RPCProber) NodeID(ctx context.Context) (string, error) {
+	var result struct {
+		NodeID string `json:"nodeID"`
+	}
+	if err := call(ctx, p.Client, p.NodeURL+"/ext/info", "info.getNodeID", struct{}{}, &result); err != nil {
+		return "", err
+	}
+	return result.NodeID, nil
+}
+
+// Bootstrapped reports whether the node finished bootstrapping chain. It
+// fails for a chain the node does not track.
+func (p *RPCProber) Bootstrapped(ctx context.Context, chain string) (bool, error) {
+	var result struct {
+		IsBootstrapped bool `json:"isBootstrapped"`
+	}
+	params := map[string]string{"chain": chain}
+	if err := call(ctx, p.Client, p.NodeURL+"/ext/info", "info.isBootstrapped", params, &result); err != nil {
+		return false, err
+	}
+	return result.IsBootstrapped, nil
+}
+
+// ValidatorBalance returns the remaining fee balance of an L1 validator
+func (p *RPCProber) ValidatorBalance(ctx context.Context, validationID string) (uint64, error) {
+	var result struct {
+		// uint64 values of the platform API are JSON strings
+		Balance json.RawMessage `json:"balance"`
+	}
+	params := map[string]string{"validationID": validationID}
+	if err := call(ctx, p.Client, p.PChainURL+"/ext/bc/P", "platform.getL1Validator", params, &result); err != nil {
+		return 0, err
+	}
+	balance, err := strconv.ParseUint(strings.Trim(string(result.Balance), `"`), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("platform.getL1Validator: invalid balance %s", result.Balance)
+	}
+	return balance, nil
+}
+
+func call(ctx context.Context, client *http.Client, url, method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: status %d", method, resp.StatusCode)
+	}
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", method, err)
+	}
+	if response.Error != nil {
+		return fmt.Errorf("%s: %s", method, response.Error.Message)
+	}
+	if len(response.Result) == 0 {
+		return errors.New(method + ": empty result")
+	}
+	return json.Unmarshal(response.Result, result)
+}
@@ -0,0 +1,497 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package watchdog supervises a luxd process that runs on this machine as a
+// bootstrap validator of an L1. The supervisor restarts luxd when it exits,
+// always restarting it with the L1 tracked, and raises alerts when the node
+// falls out of sync or the fee balance of its validator runs low. The state
+// of each watchdog is written to a file so that `lux status` can show it.
+package watchdog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Alert kinds
+const (
+	AlertCrash   = "crash"
+	AlertSync    = "sync"
+	AlertTrack   = "track"
+	AlertBalance = "balance"
+)
+
+const (
+	// DefaultInterval is the time between two health checks of the node
+	DefaultInterval = 30 * time.Second
+	// SyncGracePeriod is how long a (re)started node may bootstrap before
+	// an unsynced node is reported
+	SyncGracePeriod = 10 * time.Minute
+	// CrashWindow is how long a restart is reported as a crash alert
+	CrashWindow = 10 * time.Minute
+
+	minBackoff = time.Second
+	maxBackoff = time.Minute
+
+	trackChainsFlag = "--track-chains"
+	configSuffix    = ".config.json"
+	stateSuffix     = ".state.json"
+)
+
+// ErrNotFound is returned when no watchdog with the given name exists
+var ErrNotFound = errors.New("watchdog not found")
+
+// Config describes the node a watchdog supervises
+type Config struct {
+	Name    string `json:"name"`
+	Chain   string `json:"chain"`
+	Network string `json:"network"`
+	// LuxdPath is the luxd binary, LuxdArgs the flags it is started with
+	LuxdPath string   `json:"luxdPath"`
+	LuxdArgs []string `json:"luxdArgs,omitempty"`
+	// NodeURL is the API endpoint of the supervised node, e.g.
+	// http://127.0.0.1:9630
+	NodeURL string `json:"nodeURL"`
+	// PChainURL is the API endpoint used to read validator balances, so
+	// they are still known while the supervised node is down
+	PChainURL string `json:"pChainURL"`
+	// ChainID is the validator set of the L1, BlockchainID its chain
+	ChainID      string `json:"chainID"`
+	BlockchainID string `json:"blockchainID"`
+	// ValidationIDs maps the NodeIDs of the bootstrap validators to their
+	// validation IDs
+	ValidationIDs map[string]string `json:"validationIDs,omitempty"`
+	// MinBalance is the fee balance, in nLUX, below which an alert is raised
+	MinBalance uint64        `json:"minBalance"`
+	Interval   time.Duration `json:"interval"`
+}
+
+// Args returns the flags luxd is started with: LuxdArgs with the validator
+// set of the L1 added to --track-chains, so that a restarted node always
+// tracks the blockchain again.
+func (c Config) Args() []string {
+	args := make([]string, 0, len(c.LuxdArgs)+1)
+	tracked := []string{}
+	for i := 0; i < len(c.LuxdArgs); i++ {
+		arg := c.LuxdArgs[i]
+		switch {
+		case strings.HasPrefix(arg, trackChainsFlag+"="):
+			tracked = append(tracked, splitList(strings.TrimPrefix(arg, trackChainsFlag+"="))...)
+		case arg == trackChainsFlag && i+1 < len(c.LuxdArgs):
+			i++
+			tracked = append(tracked, splitList(c.LuxdArgs[i])...)
+		default:
+			args = append(args, arg)
+		}
+	}
+	if c.ChainID != "" && !contains(tracked, c.ChainID) {
+		tracked = append(tracked, c.ChainID)
+	}
+	if len(tracked) > 0 {
+		args = append(args, trackChainsFlag+"="+strings.Join(tracked, ","))
+	}
+	return args
+}
+
+// Alert is a degraded condition of the supervised node, open since Since
+type Alert struct {
+	Kind    string    `json:"kind"`
+	Message string    `json:"message"`
+	Since   time.Time `json:"since"`
+}
+
+// State is the last known state of a watchdog and its node
+type State struct {
+	Name    string `json:"name"`
+	Chain   string `json:"chain"`
+	Network string `json:"network"`
+	// PID is the watchdog process, NodePID the luxd process it supervises
+	PID     int    `json:"pid"`
+	NodePID int    `json:"nodePID,omitempty"`
+	NodeID  string `json:"nodeID,omitempty"`
+	Running bool   `json:"running"`
+	// Stopped is set once the watchdog itself has shut down
+	Stopped     bool      `json:"stopped"`
+	StartedAt   time.Time `json:"startedAt"`
+	Restarts    int       `json:"restarts"`
+	LastExit    string    `json:"lastExit,omitempty"`
+	LastRestart time.Time `json:"lastRestart,omitempty"`
+	// Bootstrapped reports the P-Chain, ChainBootstrapped the L1 blockchain
+	Bootstrapped      bool   `json:"bootstrapped"`
+	ChainBootstrapped bool   `json:"chainBootstrapped"`
+	ProbeError        string `json:"probeError,omitempty"`
+	TrackError        string `json:"trackError,omitempty"`
+	// Balance is the fee balance of the validator, in nLUX, nil while unknown
+	Balance    *uint64   `json:"balance,omitempty"`
+	MinBalance uint64    `json:"minBalance"`
+	Alerts     []Alert   `json:"alerts,omitempty"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// Evaluate returns the alerts raised by s at now. Alerts that were already
+// open in s keep their Since time.
+func Evaluate(s State, now time.Time) []Alert {
+	since := map[string]time.Time{}
+	for _, a := range s.Alerts {
+		since[a.Kind] = a.Since
+	}
+	alerts := []Alert{}
+	raise := func(kind, format string, args ...interface{}) {
+		t, ok := since[kind]
+		if !ok {
+			t = now
+		}
+		alerts = append(alerts, Alert{Kind: kind, Message: fmt.Sprintf(format, args...), Since: t})
+	}
+
+	switch {
+	case !s.Running && !s.Stopped:
+		raise(AlertCrash, "luxd is down (%s), restarting", s.LastExit)
+	case s.Restarts > 0 && now.Sub(s.LastRestart) < CrashWindow:
+		raise(AlertCrash, "luxd restarted %d times, last after %s", s.Restarts, s.LastExit)
+	}
+	if s.Running && now.Sub(s.lastStart()) > SyncGracePeriod {
+		switch {
+		case s.ProbeError != "":
+			raise(AlertSync, "node API unreachable: %s", s.ProbeError)
+		case !s.Bootstrapped:
+			raise(AlertSync, "P-Chain is not bootstrapped")
+		case s.TrackError != "":
+			raise(AlertTrack, "blockchain is not tracked: %s", s.TrackError)
+		case !s.ChainBootstrapped:
+			raise(AlertSync, "blockchain of %s is not bootstrapped", s.Chain)
+		}
+	}
+	if s.Balance != nil && *s.Balance < s.MinBalance {
+		raise(AlertBalance, "validator balance %d nLUX is below %d nLUX", *s.Balance, s.MinBalance)
+	}
+	return alerts
+}
+
+func (s State) lastStart() time.Time {
+	if s.LastRestart.After(s.StartedAt) {
+		return s.LastRestart
+	}
+	return s.StartedAt
+}
+
+// Process is a started luxd
+type Process interface {
+	PID() int
+	// Wait blocks until the process exits
+	Wait() error
+	// Stop asks the process to shut down gracefully
+	Stop() error
+}
+
+// StartFunc starts luxd with args
+type StartFunc func(args []string) (Process, error)
+
+// Prober queries the supervised node and the P-Chain
+type Prober interface {
+	NodeID(ctx context.Context) (string, error)
+	// Bootstrapped reports whether the node finished bootstrapping chain,
+	// an alias or a blockchain ID
+	Bootstrapped(ctx context.Context, chain string) (bool, error)
+	// ValidatorBalance returns the fee balance of an L1 validator, in nLUX
+	ValidatorBalance(ctx context.Context, validationID string) (uint64, error)
+}
+
+// Supervisor runs and watches the node of a watchdog
+type Supervisor struct {
+	Config    Config
+	StatePath string
+	Start     StartFunc
+	Prober    Prober
+	// Log receives restarts and alerts
+	Log io.Writer
+	Now func() time.Time
+
+	state State
+}
+
+// Run starts luxd, restarts it with exponential backoff whenever it exits,
+// and checks it every Config.Interval, until ctx is done. luxd is then
+// stopped gracefully.
+func (s *Supervisor) Run(ctx context.Context) error {
+	if s.Now == nil {
+		s.Now = time.Now
+	}
+	if s.Log == nil {
+		s.Log = io.Discard
+	}
+	interval := s.Config.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	s.state = State{
+		Name:       s.Config.Name,
+		Chain:      s.Config.Chain,
+		Network:    s.Config.Network,
+		PID:        os.Getpid(),
+		StartedAt:  s.Now(),
+		MinBalance: s.Config.MinBalance,
+	}
+
+	backoff := minBackoff
+	for {
+		startedAt := s.Now()
+		proc, err := s.Start(s.Config.Args())
+		if err != nil {
+			s.state.LastExit = "start failed: " + err.Error()
+			s.save()
+		} else {
+			s.state.NodePID = proc.PID()
+			s.state.Running = true
+			s.save()
+			stopped, exitErr := s.watch(ctx, proc, interval)
+			if stopped {
+				s.state.Running = false
+				s.state.Stopped = true
+				s.save()
+				return nil
+			}
+			s.state.Running = false
+			s.state.LastExit = exitReason(exitErr)
+			s.save()
+			// a node that ran for a while crashed on its own, start over
+			if s.Now().Sub(startedAt) > maxBackoff {
+				backoff = minBackoff
+			}
+		}
+		fmt.Fprintf(s.Log, "%s luxd exited (%s), restarting in %s\n", s.Now().Format(time.RFC3339), s.state.LastExit, backoff)
+
+		select {
+		case <-ctx.Done():
+			s.state.Stopped = true
+			s.save()
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+		s.state.Restarts++
+		s.state.LastRestart = s.Now()
+	}
+}
+
+// watch checks proc until it exits, returning its exit error, or until ctx
+// is done, in which case proc is stopped and stopped is true
+func (s *Supervisor) watch(ctx context.Context, proc Process, interval time.Duration) (stopped bool, exitErr error) {
+	exited := make(chan error, 1)
+	go func() { exited <- proc.Wait() }()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	s.check(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			if err := proc.Stop(); err != nil {
+				fmt.Fprintf(s.Log, "failed to stop luxd: %s\n", err)
+			}
+			<-exited
+			return true, nil
+		case err := <-exited:
+			return false, err
+		case <-ticker.C:
+			s.check(ctx)
+		}
+	}
+}
+
+// check probes the node and the validator balance, and records the alerts
+func (s *Supervisor) check(ctx context.Context) {
+	if s.Prober != nil {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		s.probe(ctx)
+	}
+	s.save()
+}
+
+func (s *Supervisor) probe(ctx context.Context) {
+	s.state.ProbeError = ""
+	s.state.TrackError = ""
+	nodeID, err := s.Prober.NodeID(ctx)
+	if err != nil {
+		s.state.ProbeError = err.Error()
+		s.state.Bootstrapped = false
+		s.state.ChainBootstrapped = false
+	} else {
+		s.state.NodeID = nodeID
+		s.state.Bootstrapped, err = s.Prober.Bootstrapped(ctx, "P")
+		if err != nil {
+			s.state.ProbeError = err.Error()
+		}
+		if s.Config.BlockchainID != "" {
+			s.state.ChainBootstrapped, err = s.Prober.Bootstrapped(ctx, s.Config.BlockchainID)
+			if err != nil {
+				s.state.TrackError = err.Error()
+			}
+		}
+	}
+	validationID, ok := s.Config.ValidationIDs[s.state.NodeID]
+	if !ok {
+		return
+	}
+	balance, err := s.Prober.ValidatorBalance(ctx, validationID)
+	if err != nil {
+		fmt.Fprintf(s.Log, "failed to read the balance of validator %s: %s\n", validationID, err)
+		return
+	}
+	s.state.Balance = &balance
+}
+
+// save records the alerts of the current state, logs the new ones and
+// writes the state file
+func (s *Supervisor) save() {
+	now := s.Now()
+	alerts := Evaluate(s.state, now)
+	open := map[string]bool{}
+	for _, a := range s.state.Alerts {
+		open[a.Kind] = true
+	}
+	for _, a := range alerts {
+		if !open[a.Kind] {
+			fmt.Fprintf(s.Log, "%s ALERT %s: %s\n", now.Format(time.RFC3339), a.Kind, a.Message)
+		}
+	}
+	s.state.Alerts = alerts
+	s.state.UpdatedAt = now
+	if s.StatePath == "" {
+		return
+	}
+	if err := writeJSON(s.StatePath, s.state); err != nil {
+		fmt.Fprintf(s.Log, "failed to write %s: %s\n", s.StatePath, err)
+	}
+}
+
+// State returns the current state of the supervisor
+func (s *Supervisor) State() State {
+	return s.state
+}
+
+func exitReason(err error) string {
+	if err == nil {
+		return "exit status 0"
+	}
+	return err.Error()
+}
+
+// ConfigPath returns the config file of the watchdog name in dir
+func ConfigPath(dir, name string) string {
+	return filepath.Join(dir, name+configSuffix)
+}
+
+// StatePath returns the state file of the watchdog name in dir
+func StatePath(dir, name string) string {
+	return filepath.Join(dir, name+stateSuffix)
+}
+
+// SaveConfig writes the config of a watchdog to dir
+func SaveConfig(dir string, c Config) error {
+	return writeJSON(ConfigPath(dir, c.Name), c)
+}
+
+// LoadConfig reads the config of the watchdog name from dir
+func LoadConfig(dir, name string) (Config, error) {
+	var c Config
+	if err := readJSON(ConfigPath(dir, name), &c); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+// LoadState reads the state of the watchdog name from dir
+func LoadState(dir, name string) (State, error) {
+	var s State
+	if err := readJSON(StatePath(dir, name), &s); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// ListStates returns the states of the watchdogs in dir, sorted by name.
+// A missing dir has no watchdogs.
+func ListStates(dir string) ([]State, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	states := []State{}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), stateSuffix) {
+			continue
+		}
+		s, err := LoadState(dir, strings.TrimSuffix(e.Name(), stateSuffix))
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, s)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+	return states, nil
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the watchdog dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrNotFound, strings.TrimSuffix(strings.TrimSuffix(filepath.Base(path), configSuffix), stateSuffix))
+		}
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeJSON writes v to path through a temporary file, so that readers
+// never see a partial file
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func splitList(s string) []string {
+	items := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package watchdog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigArgs(t *testing.T) {
+	require := require.New(t)
+	c := Config{ChainID: "chainA", LuxdArgs: []string{"--http-port=9630", "--track-chains", "chainB"}}
+	require.Equal([]string{"--http-port=9630", "--track-chains=chainB,chainA"}, c.Args())
+
+	c.LuxdArgs = []string{"--track-chains=chainA,chainB"}
+	require.Equal([]string{"--track-chains=chainA,chainB"}, c.Args())
+
+	c.LuxdArgs = nil
+	require.Equal([]string{"--track-chains=chainA"}, c.Args())
+}
+
+func TestEvaluate(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+	low := uint64(5)
+	s := State{
+		Chain:        "mychain",
+		Running:      true,
+		StartedAt:    now.Add(-time.Hour),
+		Bootstrapped: true,
+		Balance:      &low,
+		MinBalance:   10,
+	}
+	alerts := Evaluate(s, now)
+	require.Len(alerts, 2)
+	require.Equal(AlertSync, alerts[0].Kind)
+	require.Contains(alerts[0].Message, "mychain")
+	require.Equal(AlertBalance, alerts[1].Kind)
+
+	// open alerts keep their start time
+	s.Alerts = alerts
+	later := Evaluate(s, now.Add(time.Minute))
+	require.Equal(now, later[0].Since)
+
+	// a node still bootstrapping after a restart is not reported
+	s.Restarts, s.LastRestart, s.LastExit = 1, now, "signal: killed"
+	s.Balance = nil
+	alerts = Evaluate(s, now.Add(time.Minute))
+	require.Len(alerts, 1)
+	require.Equal(AlertCrash, alerts[0].Kind)
+
+	s.Running = false
+	alerts = Evaluate(s, now.Add(time.Minute))
+	require.Len(alerts, 1)
+	require.Contains(alerts[0].Message, "down")
+
+	s.Stopped = true
+	s.LastRestart = now.Add(-time.Hour)
+	require.Empty(Evaluate(s, now))
+}
+
+type fakeProcess struct {
+	pid  int
+	exit chan error
+}
+
+func (p *fakeProcess) PID() int    { return p.pid }
+func (p *fakeProcess) Wait() error { return <-p.exit }
+func (p *fakeProcess) Stop() error {
+	p.exit <- nil
+	return nil
+}
+
+type fakeProber struct{}
+
+func (fakeProber) NodeID(context.Context) (string, error) { return "NodeID-1", nil }
+func (fakeProber) Bootstrapped(_ context.Context, chain string) (bool, error) {
+	if chain == "P" {
+		return true, nil
+	}
+	return false, errors.New("there is no chain with alias/ID " + chain)
+}
+
+func (fakeProber) ValidatorBalance(context.Context, string) (uint64, error) { return 42, nil }
+
+func TestSupervisorRestarts(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	var (
+		mu    sync.Mutex
+		procs []*fakeProcess
+		args  [][]string
+	)
+	started := make(chan struct{}, 2)
+	s := &Supervisor{
+		Config: Config{
+			Name:          "mychain",
+			ChainID:       "chainA",
+			BlockchainID:  "blockchainA",
+			ValidationIDs: map[string]string{"NodeID-1": "validation1"},
+			MinBalance:    100,
+			Interval:      time.Hour,
+		},
+		StatePath: StatePath(dir, "mychain"),
+		Start: func(a []string) (Process, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			p := &fakeProcess{pid: len(procs) + 1, exit: make(chan error, 1)}
+			procs = append(procs, p)
+			args = append(args, a)
+			started <- struct{}{}
+			return p, nil
+		},
+		Prober: fakeProber{},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+
+	<-started
+	mu.Lock()
+	procs[0].exit <- errors.New("signal: killed")
+	mu.Unlock()
+	<-started
+
+	var state State
+	require.Eventually(func() bool {
+		var err error
+		state, err = LoadState(dir, "mychain")
+		return err == nil && state.NodePID == 2 && state.Balance != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.True(state.Running)
+	require.Equal(1, state.Restarts)
+	require.Equal("signal: killed", state.LastExit)
+	require.Equal("NodeID-1", state.NodeID)
+	require.NotEmpty(state.TrackError)
+	require.Equal(uint64(42), *state.Balance)
+	kinds := []string{}
+	for _, a := range state.Alerts {
+		kinds = append(kinds, a.Kind)
+	}
+	require.Equal([]string{AlertCrash, AlertBalance}, kinds)
+
+	cancel()
+	require.NoError(<-done)
+	state, err := LoadState(dir, "mychain")
+	require.NoError(err)
+	require.True(state.Stopped)
+	require.False(state.Running)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal([]string{"--track-chains=chainA"}, args[1])
+}
+
+func TestListStates(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	states, err := ListStates(filepath.Join(dir, "missing"))
+	require.NoError(err)
+	require.Empty(states)
+
+	require.NoError(SaveConfig(dir, Config{Name: "b"}))
+	require.NoError(writeJSON(StatePath(dir, "b"), State{Name: "b"}))
+	require.NoError(writeJSON(StatePath(dir, "a"), State{Name: "a"}))
+	states, err = ListStates(dir)
+	require.NoError(err)
+	require.Len(states, 2)
+	require.Equal("a", states[0].Name)
+
+	c, err := LoadConfig(dir, "b")
+	require.NoError(err)
+	require.Equal("b", c.Name)
+	_, err = LoadConfig(dir, "c")
+	require.ErrorIs(err, ErrNotFound)
+}
+
+func TestRPCProber(t *testing.T) {
+	require := require.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string            `json:"method"`
+			Params map[string]string `json:"params"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		var result interface{}
+		switch req.Method {
+		case "info.getNodeID":
+			result = map[string]string{"nodeID": "NodeID-1"}
+		case "info.isBootstrapped":
+			result = map[string]bool{"isBootstrapped": req.Params["chain"] == "P"}
+		case "platform.getL1Validator":
+			require.Equal("/ext/bc/P", r.URL.Path)
+			result = map[string]string{"balance": "1000"}
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]string{"message": "method not found"}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+	defer server.Close()
+
+	p := NewRPCProber(server.URL, "")
+	ctx := context.Background()
+	nodeID, err := p.NodeID(ctx)
+	require.NoError(err)
+	require.Equal("NodeID-1", nodeID)
+	ok, err := p.Bootstrapped(ctx, "P")
+	require.NoError(err)
+	require.True(ok)
+	ok, err = p.Bootstrapped(ctx, "blockchainA")
+	require.NoError(err)
+	require.False(ok)
+	balance, err := p.ValidatorBalance(ctx, "validation1")
+	require.NoError(err)
+	require.Equal(uint64(1000), balance)
+}
@@ -0,0 +1,255 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package wsprobe checks the WebSocket endpoints of EVM chains by holding a
+// subscription open and measuring what it delivers: how long events take to
+// arrive after their block was produced, and how many of the events the
+// chain produced never arrived.
+package wsprobe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/ethclient"
+)
+
+const (
+	TopicNewHeads = "newHeads"
+	TopicLogs     = "logs"
+)
+
+// Topics are the subscriptions that can be probed
+var Topics = []string{TopicNewHeads, TopicLogs}
+
+var ErrTopic = errors.New("unknown subscription topic")
+
+// Client is the part of ethclient.Client a probe uses
+type Client interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+	SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
+}
+
+// Options configures a probe
+type Options struct {
+	Topic string
+	// Duration is how long the subscription is held open
+	Duration time.Duration
+	// Addresses restricts a logs subscription to these contracts
+	Addresses []common.Address
+	// Interval is how often Progress is called, never when zero
+	Interval time.Duration
+	Progress func(Result)
+}
+
+// Result is what a probe observed on one subscription
+type Result struct {
+	Network string    `json:"network"`
+	Chain   string    `json:"chain"`
+	URL     string    `json:"url"`
+	Topic   string    `json:"topic"`
+	Start   time.Time `json:"start"`
+	// Duration is how long the subscription was held open
+	Duration time.Duration `json:"duration"`
+	// SubscribeLatency is how long the endpoint took to accept the subscription
+	SubscribeLatency time.Duration `json:"subscribeLatency"`
+	Events           int           `json:"events"`
+	// Missed counts the events the chain produced that were never delivered
+	Missed     int           `json:"missed"`
+	LatencyAvg time.Duration `json:"latencyAvg"`
+	LatencyMax time.Duration `json:"latencyMax"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// OK reports whether the subscription stayed up without losing events
+func (r Result) OK() bool {
+	return r.Error == "" && r.Missed == 0
+}
+
+// DropRate is the share of the produced events that were not delivered
+func (r Result) DropRate() float64 {
+	if r.Events+r.Missed == 0 {
+		return 0
+	}
+	return float64(r.Missed) / float64(r.Events+r.Missed)
+}
+
+// WSURL turns the http(s) URL of a node API into its ws(s) equivalent
+func WSURL(url string) string {
+	switch {
+	case strings.HasPrefix(url, "https://"):
+		return "wss://" + strings.TrimPrefix(url, "https://")
+	case strings.HasPrefix(url, "http://"):
+		return "ws://" + strings.TrimPrefix(url, "http://")
+	}
+	return url
+}
+
+// Dial connects to the WebSocket endpoint at url
+func Dial(ctx context.Context, url string) (*ethclient.Client, error) {
+	if !strings.HasPrefix(url, "ws://") && !strings.HasPrefix(url, "wss://") {
+		return nil, fmt.Errorf("%s is not a WebSocket URL", url)
+	}
+	client, err := ethclient.DialContext(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", url, err)
+	}
+	return client, nil
+}
+
+// Probe subscribes to opts.Topic on client and holds the subscription for
+// opts.Duration, or until ctx is done. Failing to subscribe is returned as an
+// error; the subscription failing later is recorded in the result.
+func Probe(ctx context.Context, client Client, opts Options) (Result, error) {
+	if opts.Topic != TopicNewHeads && opts.Topic != TopicLogs {
+		return Result{}, fmt.Errorf("%w %q: use one of %s", ErrTopic, opts.Topic, strings.Join(Topics, ", "))
+	}
+	result := Result{Topic: opts.Topic, Start: time.Now()}
+	startBlock, err := client.BlockNumber(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	var (
+		heads = make(chan *types.Header, 64)
+		logs  = make(chan types.Log, 256)
+		sub   ethereum.Subscription
+	)
+	if opts.Topic == TopicNewHeads {
+		sub, err = client.SubscribeNewHead(ctx, heads)
+	} else {
+		sub, err = client.SubscribeFilterLogs(ctx, ethereum.FilterQuery{Addresses: opts.Addresses}, logs)
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to subscribe to %s: %w", opts.Topic, err)
+	}
+	defer sub.Unsubscribe()
+	result.SubscribeLatency = time.Since(result.Start)
+
+	var (
+		tick       <-chan time.Time
+		total      time.Duration
+		seenHeads  = map[uint64]bool{}
+		seenLogs   = map[logKey]bool{}
+		blockTimes = map[uint64]uint64{}
+	)
+	if opts.Interval > 0 && opts.Progress != nil {
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	timer := time.NewTimer(opts.Duration)
+	defer timer.Stop()
+	record := func(blockTime uint64) {
+		latency := max(time.Since(time.Unix(int64(blockTime), 0)), 0)
+		result.Events++
+		total += latency
+		result.LatencyMax = max(result.LatencyMax, latency)
+	}
+	summarize := func() {
+		result.Duration = time.Since(result.Start)
+		if result.Events > 0 {
+			result.LatencyAvg = total / time.Duration(result.Events)
+		}
+	}
+
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-timer.C:
+			break loop
+		case err := <-sub.Err():
+			if err != nil {
+				result.Error = err.Error()
+			}
+			break loop
+		case <-tick:
+			summarize()
+			opts.Progress(result)
+		case head := <-heads:
+			seenHeads[head.Number.Uint64()] = true
+			record(head.Time)
+		case log := <-logs:
+			blockTime, ok := blockTimes[log.BlockNumber]
+			if !ok {
+				header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(log.BlockNumber))
+				if err != nil {
+					continue
+				}
+				blockTime = header.Time
+				blockTimes[log.BlockNumber] = blockTime
+			}
+			seenLogs[logKey{log.TxHash, log.Index}] = true
+			record(blockTime)
+		}
+	}
+	summarize()
+	// events already delivered but not yet read are not missed
+	for drained := false; !drained; {
+		select {
+		case head := <-heads:
+			seenHeads[head.Number.Uint64()] = true
+		case log := <-logs:
+			seenLogs[logKey{log.TxHash, log.Index}] = true
+		default:
+			drained = true
+		}
+	}
+
+	// the delivered events are compared against what the chain produced
+	// meanwhile, which needs the endpoint to still answer
+	checkCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	endBlock, err := client.BlockNumber(checkCtx)
+	if err != nil {
+		if result.Error == "" {
+			result.Error = err.Error()
+		}
+		return result, nil
+	}
+	if endBlock <= startBlock {
+		return result, nil
+	}
+	if opts.Topic == TopicNewHeads {
+		for n := startBlock + 1; n <= endBlock; n++ {
+			if !seenHeads[n] {
+				result.Missed++
+			}
+		}
+		return result, nil
+	}
+	produced, err := client.FilterLogs(checkCtx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(startBlock + 1),
+		ToBlock:   new(big.Int).SetUint64(endBlock),
+		Addresses: opts.Addresses,
+	})
+	if err != nil {
+		if result.Error == "" {
+			result.Error = fmt.Sprintf("failed to get the logs of blocks %d-%d: %v", startBlock+1, endBlock, err)
+		}
+		return result, nil
+	}
+	for _, log := range produced {
+		if !seenLogs[logKey{log.TxHash, log.Index}] {
+			result.Missed++
+		}
+	}
+	return result, nil
+}
+
+type logKey struct {
+	txHash common.Hash
+	index  uint
+}
@@ -0,0 +1,154 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wsprobe
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/event"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient delivers heads and logs on subscription, and answers the block
+// number with the next of blockNumbers
+type fakeClient struct {
+	blockNumbers []uint64
+	heads        []*types.Header
+	logs         []types.Log
+	produced     []types.Log
+	subErr       error
+}
+
+func (c *fakeClient) BlockNumber(context.Context) (uint64, error) {
+	n := c.blockNumbers[0]
+	if len(c.blockNumbers) > 1 {
+		c.blockNumbers = c.blockNumbers[1:]
+	}
+	return n, nil
+}
+
+func (c *fakeClient) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: number, Time: uint64(time.Now().Unix())}, nil
+}
+
+func (c *fakeClient) FilterLogs(context.Context, ethereum.FilterQuery) ([]types.Log, error) {
+	return c.produced, nil
+}
+
+func (c *fakeClient) SubscribeNewHead(_ context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		for _, head := range c.heads {
+			ch <- head
+		}
+		if c.subErr != nil {
+			return c.subErr
+		}
+		<-quit
+		return nil
+	}), nil
+}
+
+func (c *fakeClient) SubscribeFilterLogs(_ context.Context, _ ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		for _, log := range c.logs {
+			ch <- log
+		}
+		<-quit
+		return nil
+	}), nil
+}
+
+func head(number uint64) *types.Header {
+	return &types.Header{Number: new(big.Int).SetUint64(number), Time: uint64(time.Now().Unix())}
+}
+
+func TestProbeNewHeads(t *testing.T) {
+	require := require.New(t)
+	client := &fakeClient{
+		blockNumbers: []uint64{10, 14},
+		heads:        []*types.Header{head(11), head(12), head(14)},
+	}
+	result, err := Probe(context.Background(), client, Options{Topic: TopicNewHeads, Duration: 100 * time.Millisecond})
+	require.NoError(err)
+	require.Equal(3, result.Events)
+	require.Equal(1, result.Missed)
+	require.InDelta(0.25, result.DropRate(), 0.001)
+	require.False(result.OK())
+	require.Empty(result.Error)
+}
+
+func TestProbeLogs(t *testing.T) {
+	require := require.New(t)
+	a := types.Log{TxHash: common.HexToHash("0xa"), BlockNumber: 6}
+	b := types.Log{TxHash: common.HexToHash("0xa"), Index: 1, BlockNumber: 6}
+	c := types.Log{TxHash: common.HexToHash("0xc"), BlockNumber: 7}
+	client := &fakeClient{
+		blockNumbers: []uint64{5, 7},
+		logs:         []types.Log{a, b, c},
+		produced:     []types.Log{a, b, c},
+	}
+	result, err := Probe(context.Background(), client, Options{Topic: TopicLogs, Duration: 100 * time.Millisecond})
+	require.NoError(err)
+	require.Equal(3, result.Events)
+	require.Zero(result.Missed)
+	require.True(result.OK())
+
+	// a log the chain produced but the subscription never delivered
+	client.blockNumbers = []uint64{5, 7}
+	client.logs = []types.Log{a, c}
+	result, err = Probe(context.Background(), client, Options{Topic: TopicLogs, Duration: 100 * time.Millisecond})
+	require.NoError(err)
+	require.Equal(2, result.Events)
+	require.Equal(1, result.Missed)
+}
+
+func TestProbeSubscriptionFailure(t *testing.T) {
+	require := require.New(t)
+	client := &fakeClient{
+		blockNumbers: []uint64{10, 13},
+		heads:        []*types.Header{head(11)},
+		subErr:       errors.New("connection reset"),
+	}
+	result, err := Probe(context.Background(), client, Options{Topic: TopicNewHeads, Duration: time.Minute})
+	require.NoError(err)
+	require.Equal("connection reset", result.Error)
+	require.Equal(2, result.Missed)
+
+	_, err = Probe(context.Background(), client, Options{Topic: "pendingTransactions"})
+	require.ErrorIs(err, ErrTopic)
+}
+
+func TestSaveLoad(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), ResultsFile)
+	results, err := Load(path)
+	require.NoError(err)
+	require.Empty(results)
+
+	require.NoError(Save(path, Result{Network: "custom", Chain: "c", Topic: TopicNewHeads, Events: 1}))
+	require.NoError(Save(path, Result{Network: "custom", Chain: "c", Topic: TopicLogs, Events: 2}))
+	require.NoError(Save(path, Result{Network: "testnet", Chain: "c", Topic: TopicNewHeads, Events: 3}))
+	require.NoError(Save(path, Result{Network: "custom", Chain: "c", Topic: TopicNewHeads, Events: 4}))
+
+	results, err = Load(path)
+	require.NoError(err)
+	require.Len(results, 3)
+	custom := ForNetwork(results, "custom")
+	require.Len(custom, 2)
+	require.Equal(TopicLogs, custom[0].Topic)
+	require.Equal(4, custom[1].Events)
+}
+
+func TestWSURL(t *testing.T) {
+	require.Equal(t, "ws://127.0.0.1:9630/ext/bc/C/ws", WSURL("http://127.0.0.1:9630/ext/bc/C/ws"))
+	require.Equal(t, "wss://api.lux.network/ext/bc/C/ws", WSURL("https://api.lux.network/ext/bc/C/ws"))
+}
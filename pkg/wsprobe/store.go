@@ -0,0 +1,81 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wsprobe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ResultsFile is the file, in the CLI base directory, keeping the last
+// result of every probed subscription
+const ResultsFile = "ws-subscriptions.json"
+
+// Save records r as the last result of its network, chain and topic
+func Save(path string, r Result) error {
+	results, err := Load(path)
+	if err != nil {
+		return err
+	}
+	replaced := false
+	for i, prev := range results {
+		if prev.Network == r.Network && prev.Chain == r.Chain && prev.Topic == r.Topic {
+			results[i] = r
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		results = append(results, r)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Network != results[j].Network {
+			return results[i].Network < results[j].Network
+		}
+		if results[i].Chain != results[j].Chain {
+			return results[i].Chain < results[j].Chain
+		}
+		return results[i].Topic < results[j].Topic
+	})
+	resultsBytes, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, resultsBytes, 0o644)
+}
+
+// Load returns the last results recorded at path, none when there is no
+// such file
+func Load(path string) ([]Result, error) {
+	resultsBytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var results []Result
+	if err := json.Unmarshal(resultsBytes, &results); err != nil {
+		return nil, fmt.Errorf("invalid subscription results in %s: %w", path, err)
+	}
+	return results, nil
+}
+
+// ForNetwork returns the results of network
+func ForNetwork(results []Result, network string) []Result {
+	var filtered []Result
+	for _, r := range results {
+		if r.Network == network {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
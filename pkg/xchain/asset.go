@@ -0,0 +1,180 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package xchain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math/set"
+	"github.com/luxfi/protocol/x/txs"
+	"github.com/luxfi/sdk/wallet/chain/x/builder"
+	"github.com/luxfi/sdk/wallet/primary/common"
+	lux "github.com/luxfi/utxo"
+	"github.com/luxfi/utxo/nftfx"
+	"github.com/luxfi/utxo/secp256k1fx"
+	"github.com/luxfi/vm/components/verify"
+)
+
+// AssetKind is the kind of an X-Chain asset
+type AssetKind string
+
+const (
+	// FixedCap assets have their whole supply created with the asset
+	FixedCap AssetKind = "fixed-cap"
+	// VariableCap assets can be minted by their minters after creation
+	VariableCap AssetKind = "variable-cap"
+	// NFT assets are groups of non-fungible tokens minted by their minters
+	NFT AssetKind = "nft"
+)
+
+// Limits of the X-Chain on asset definitions
+const (
+	MaxNameLen      = 128
+	MaxSymbolLen    = 4
+	MaxDenomination = 32
+	MaxNFTGroups    = 64
+)
+
+var (
+	ErrInvalidAsset  = errors.New("invalid asset")
+	ErrInvalidAmount = errors.New("invalid amount")
+	ErrNoNFT         = errors.New("no NFT of the asset owned")
+)
+
+// ParseAssetKind returns the AssetKind named s
+func ParseAssetKind(s string) (AssetKind, error) {
+	switch k := AssetKind(strings.ToLower(s)); k {
+	case FixedCap, VariableCap, NFT:
+		return k, nil
+	default:
+		return "", fmt.Errorf("%w: unknown kind %q, use %s, %s or %s", ErrInvalidAsset, s, FixedCap, VariableCap, NFT)
+	}
+}
+
+// ValidateAsset checks an asset definition against the X-Chain limits. NFTs
+// are not divisible, so they have a zero denomination.
+func ValidateAsset(kind AssetKind, name, symbol string, denomination byte) error {
+	switch {
+	case name == "" || len(name) > MaxNameLen:
+		return fmt.Errorf("%w: the name must be 1 to %d characters", ErrInvalidAsset, MaxNameLen)
+	case len(symbol) > MaxSymbolLen:
+		return fmt.Errorf("%w: the symbol must be at most %d characters", ErrInvalidAsset, MaxSymbolLen)
+	case denomination > MaxDenomination:
+		return fmt.Errorf("%w: the denomination must be at most %d", ErrInvalidAsset, MaxDenomination)
+	case kind == NFT && denomination != 0:
+		return fmt.Errorf("%w: NFTs have no denomination", ErrInvalidAsset)
+	}
+	for _, r := range name + symbol {
+		if r > 127 {
+			return fmt.Errorf("%w: the name and symbol must be ASCII", ErrInvalidAsset)
+		}
+	}
+	return nil
+}
+
+// InitialState returns the initial state of a new asset of kind owned by
+// owner: the supply of a fixed-cap asset, the minter and optional initial
+// supply of a variable-cap asset, or the minters of groups NFT groups.
+func InitialState(kind AssetKind, owner *secp256k1fx.OutputOwners, supply uint64, groups uint32) (map[uint32][]verify.State, error) {
+	switch kind {
+	case FixedCap:
+		if supply == 0 {
+			return nil, fmt.Errorf("%w: a fixed-cap asset needs a supply", ErrInvalidAmount)
+		}
+		return map[uint32][]verify.State{
+			builder.SECP256K1FxIndex: {&secp256k1fx.TransferOutput{Amt: supply, OutputOwners: *owner}},
+		}, nil
+	case VariableCap:
+		state := []verify.State{&secp256k1fx.MintOutput{OutputOwners: *owner}}
+		if supply > 0 {
+			state = append(state, &secp256k1fx.TransferOutput{Amt: supply, OutputOwners: *owner})
+		}
+		return map[uint32][]verify.State{builder.SECP256K1FxIndex: state}, nil
+	case NFT:
+		if groups == 0 || groups > MaxNFTGroups {
+			return nil, fmt.Errorf("%w: an NFT asset has 1 to %d groups", ErrInvalidAsset, MaxNFTGroups)
+		}
+		state := make([]verify.State, 0, groups)
+		for g := uint32(0); g < groups; g++ {
+			state = append(state, &nftfx.MintOutput{GroupID: g, OutputOwners: *owner})
+		}
+		return map[uint32][]verify.State{builder.NFTFxIndex: state}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown kind %q", ErrInvalidAsset, kind)
+	}
+}
+
+// ParseAmount parses a decimal amount of an asset with denomination decimals
+// into its smallest unit
+func ParseAmount(s string, denomination byte) (uint64, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || r.Sign() <= 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(denomination)), nil)))
+	if !r.IsInt() {
+		return 0, fmt.Errorf("%w: %q has more than %d decimals", ErrInvalidAmount, s, denomination)
+	}
+	if !r.Num().IsUint64() {
+		return 0, fmt.Errorf("%w: %q is too large", ErrInvalidAmount, s)
+	}
+	return r.Num().Uint64(), nil
+}
+
+// FormatAmount formats an amount in the smallest unit of an asset with
+// denomination decimals
+func FormatAmount(amount uint64, denomination byte) string {
+	s := fmt.Sprintf("%0*d", int(denomination)+1, amount)
+	if denomination == 0 {
+		return s
+	}
+	whole, frac := s[:len(s)-int(denomination)], strings.TrimRight(s[len(s)-int(denomination):], "0")
+	if frac == "" {
+		return whole
+	}
+	return whole + "." + frac
+}
+
+// NFTTransferOperation returns the operation sending an NFT of group of
+// assetID, among utxos and owned by addrs, to owner
+func NFTTransferOperation(
+	utxos []*lux.UTXO,
+	assetID ids.ID,
+	group uint32,
+	addrs set.Set[ids.ShortID],
+	owner *secp256k1fx.OutputOwners,
+	now uint64,
+) (*txs.Operation, error) {
+	for _, utxo := range utxos {
+		if utxo.AssetID() != assetID {
+			continue
+		}
+		out, ok := utxo.Out.(*nftfx.TransferOutput)
+		if !ok || out.GroupID != group {
+			continue
+		}
+		sigIndices, ok := common.MatchOwners(&out.OutputOwners, addrs, now)
+		if !ok {
+			continue
+		}
+		return &txs.Operation{
+			Asset:   lux.Asset{ID: assetID},
+			UTXOIDs: []*lux.UTXOID{&utxo.UTXOID},
+			FxID:    nftfx.ID,
+			Op: &nftfx.TransferOperation{
+				Input: secp256k1fx.Input{SigIndices: sigIndices},
+				Output: nftfx.TransferOutput{
+					GroupID:      out.GroupID,
+					Payload:      out.Payload,
+					OutputOwners: *owner,
+				},
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s group %d", ErrNoNFT, assetID, group)
+}
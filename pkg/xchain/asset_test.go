@@ -0,0 +1,108 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package xchain
+
+import (
+	"testing"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/math/set"
+	"github.com/luxfi/sdk/wallet/chain/x/builder"
+	lux "github.com/luxfi/utxo"
+	"github.com/luxfi/utxo/nftfx"
+	"github.com/luxfi/utxo/secp256k1fx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitialState(t *testing.T) {
+	require := require.New(t)
+	owner := &secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{ids.GenerateTestShortID()}}
+
+	state, err := InitialState(FixedCap, owner, 1000, 0)
+	require.NoError(err)
+	require.Len(state[builder.SECP256K1FxIndex], 1)
+	require.Equal(uint64(1000), state[builder.SECP256K1FxIndex][0].(*secp256k1fx.TransferOutput).Amt)
+	_, err = InitialState(FixedCap, owner, 0, 0)
+	require.ErrorIs(err, ErrInvalidAmount)
+
+	state, err = InitialState(VariableCap, owner, 0, 0)
+	require.NoError(err)
+	require.Len(state[builder.SECP256K1FxIndex], 1)
+	require.IsType(&secp256k1fx.MintOutput{}, state[builder.SECP256K1FxIndex][0])
+	state, err = InitialState(VariableCap, owner, 5, 0)
+	require.NoError(err)
+	require.Len(state[builder.SECP256K1FxIndex], 2)
+
+	state, err = InitialState(NFT, owner, 0, 3)
+	require.NoError(err)
+	require.Len(state[builder.NFTFxIndex], 3)
+	require.Equal(uint32(2), state[builder.NFTFxIndex][2].(*nftfx.MintOutput).GroupID)
+	_, err = InitialState(NFT, owner, 0, 0)
+	require.ErrorIs(err, ErrInvalidAsset)
+}
+
+func TestValidateAsset(t *testing.T) {
+	require := require.New(t)
+	require.NoError(ValidateAsset(FixedCap, "Test Token", "TST", 9))
+	require.ErrorIs(ValidateAsset(FixedCap, "", "TST", 9), ErrInvalidAsset)
+	require.ErrorIs(ValidateAsset(FixedCap, "Test Token", "TOOLONG", 9), ErrInvalidAsset)
+	require.ErrorIs(ValidateAsset(FixedCap, "Test Token", "TST", 33), ErrInvalidAsset)
+	require.ErrorIs(ValidateAsset(NFT, "Art", "ART", 2), ErrInvalidAsset)
+	require.ErrorIs(ValidateAsset(FixedCap, "Tëst", "TST", 0), ErrInvalidAsset)
+
+	kind, err := ParseAssetKind("NFT")
+	require.NoError(err)
+	require.Equal(NFT, kind)
+	_, err = ParseAssetKind("capped")
+	require.ErrorIs(err, ErrInvalidAsset)
+}
+
+func TestAmounts(t *testing.T) {
+	require := require.New(t)
+	amount, err := ParseAmount("1.5", 9)
+	require.NoError(err)
+	require.Equal(uint64(1_500_000_000), amount)
+	amount, err = ParseAmount("42", 0)
+	require.NoError(err)
+	require.Equal(uint64(42), amount)
+	_, err = ParseAmount("0.001", 2)
+	require.ErrorIs(err, ErrInvalidAmount)
+	_, err = ParseAmount("-1", 2)
+	require.ErrorIs(err, ErrInvalidAmount)
+	_, err = ParseAmount("100000000000", 9)
+	require.ErrorIs(err, ErrInvalidAmount)
+
+	require.Equal("1.5", FormatAmount(1_500_000_000, 9))
+	require.Equal("0.000000001", FormatAmount(1, 9))
+	require.Equal("3", FormatAmount(3_000, 3))
+	require.Equal("42", FormatAmount(42, 0))
+}
+
+func TestNFTTransferOperation(t *testing.T) {
+	require := require.New(t)
+	assetID := ids.GenerateTestID()
+	mine := ids.GenerateTestShortID()
+	owned := secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{mine}}
+	nft := func(group uint32, owners secp256k1fx.OutputOwners) *lux.UTXO {
+		return &lux.UTXO{
+			UTXOID: lux.UTXOID{TxID: ids.GenerateTestID()},
+			Asset:  lux.Asset{ID: assetID},
+			Out:    &nftfx.TransferOutput{GroupID: group, Payload: []byte("art"), OutputOwners: owners},
+		}
+	}
+	others := secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{ids.GenerateTestShortID()}}
+	utxos := []*lux.UTXO{nft(1, others), nft(0, owned), nft(1, owned)}
+	to := &secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{ids.GenerateTestShortID()}}
+
+	op, err := NFTTransferOperation(utxos, assetID, 1, set.Of(mine), to, 0)
+	require.NoError(err)
+	require.Equal(utxos[2].UTXOID, *op.UTXOIDs[0])
+	transfer := op.Op.(*nftfx.TransferOperation)
+	require.Equal([]uint32{0}, transfer.Input.SigIndices)
+	require.Equal([]byte("art"), []byte(transfer.Output.Payload))
+	require.Equal(*to, transfer.Output.OutputOwners)
+
+	_, err = NFTTransferOperation(utxos, assetID, 2, set.Of(mine), to, 0)
+	require.ErrorIs(err, ErrNoNFT)
+}
@@ -0,0 +1,262 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package xchain issues X-Chain transactions. The wallet of the SDK builds
+// and signs them, while the UTXOs of the keychain are fetched and the signed
+// transactions submitted through the xvm API of a node.
+package xchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/luxfi/address"
+	apitypes "github.com/luxfi/api/types"
+	"github.com/luxfi/constants"
+	"github.com/luxfi/formatting"
+	"github.com/luxfi/genesis/pkg/genesis"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/keychain"
+	"github.com/luxfi/protocol/x/txs"
+	"github.com/luxfi/rpc"
+	sdkinfo "github.com/luxfi/sdk/info"
+	"github.com/luxfi/sdk/platformvm"
+	"github.com/luxfi/sdk/wallet/chain/x"
+	"github.com/luxfi/sdk/wallet/chain/x/builder"
+	"github.com/luxfi/sdk/wallet/chain/x/signer"
+	"github.com/luxfi/sdk/wallet/primary/common"
+	lux "github.com/luxfi/utxo"
+)
+
+const (
+	// utxoPageSize is the number of UTXOs fetched per xvm.getUTXOs call
+	utxoPageSize = 1024
+
+	// pollInterval is how often the status of an issued tx is checked
+	pollInterval = 500 * time.Millisecond
+)
+
+var ErrTxNotAccepted = errors.New("transaction not accepted")
+
+// Client calls the xvm API of a node
+type Client struct {
+	requester rpc.EndpointRequester
+}
+
+// NewClient returns a client of the X-Chain served at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{requester: rpc.NewEndpointRequester(strings.TrimSuffix(baseURL, "/") + "/ext/bc/" + builder.Alias)}
+}
+
+// GetUTXOs returns the UTXOs of the X-Chain owned by addrs, formatted
+// addresses with the X- prefix
+func (c *Client) GetUTXOs(ctx context.Context, addrs []string) ([]*lux.UTXO, error) {
+	return c.GetAtomicUTXOs(ctx, addrs, "")
+}
+
+// GetAtomicUTXOs returns the UTXOs owned by addrs exported to the X-Chain
+// by sourceChain, or the UTXOs of the X-Chain when sourceChain is empty
+func (c *Client) GetAtomicUTXOs(ctx context.Context, addrs []string, sourceChain string) ([]*lux.UTXO, error) {
+	var (
+		utxos []*lux.UTXO
+		start apitypes.Index
+	)
+	for {
+		reply := &apitypes.GetUTXOsReply{}
+		if err := c.requester.SendRequest(ctx, "xvm.getUTXOs", &apitypes.GetUTXOsArgs{
+			Addresses:   addrs,
+			SourceChain: sourceChain,
+			Limit:       utxoPageSize,
+			StartIndex:  start,
+			Encoding:    formatting.Hex,
+		}, reply); err != nil {
+			return nil, err
+		}
+		for _, s := range reply.UTXOs {
+			b, err := formatting.Decode(formatting.Hex, s)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode UTXO: %w", err)
+			}
+			utxo := &lux.UTXO{}
+			if _, err := builder.Parser.Codec().Unmarshal(b, utxo); err != nil {
+				return nil, fmt.Errorf("failed to parse UTXO: %w", err)
+			}
+			utxos = append(utxos, utxo)
+		}
+		if len(reply.UTXOs) < utxoPageSize {
+			return utxos, nil
+		}
+		start = reply.EndIndex
+	}
+}
+
+// IssueTx submits a signed tx and returns its ID
+func (c *Client) IssueTx(ctx context.Context, txBytes []byte) (ids.ID, error) {
+	encoded, err := formatting.Encode(formatting.Hex, txBytes)
+	if err != nil {
+		return ids.Empty, err
+	}
+	reply := &apitypes.JSONTxID{}
+	err = c.requester.SendRequest(ctx, "xvm.issueTx", &apitypes.FormattedTx{
+		Tx:       encoded,
+		Encoding: formatting.Hex,
+	}, reply)
+	return reply.TxID, err
+}
+
+// GetTxStatus returns the status of a tx, "Accepted" once it is
+func (c *Client) GetTxStatus(ctx context.Context, txID ids.ID) (string, error) {
+	reply := &struct {
+		Status string `json:"status"`
+	}{}
+	err := c.requester.SendRequest(ctx, "xvm.getTxStatus", &apitypes.JSONTxID{TxID: txID}, reply)
+	return reply.Status, err
+}
+
+// AwaitTxAccepted polls the status of txID until it is accepted, rejected or
+// ctx is done
+func (c *Client) AwaitTxAccepted(ctx context.Context, txID ids.ID) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		status, err := c.GetTxStatus(ctx, txID)
+		if err == nil {
+			switch status {
+			case "Accepted":
+				return nil
+			case "Rejected":
+				return fmt.Errorf("%w: %s is %s", ErrTxNotAccepted, txID, status)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("%w: %s: %w", ErrTxNotAccepted, txID, err)
+			}
+			return fmt.Errorf("%w: %s is %s", ErrTxNotAccepted, txID, status)
+		case <-ticker.C:
+		}
+	}
+}
+
+// AssetDescription describes an X-Chain asset
+type AssetDescription struct {
+	AssetID      ids.ID `json:"assetID"`
+	Name         string `json:"name"`
+	Symbol       string `json:"symbol"`
+	Denomination byte   `json:"denomination"`
+}
+
+// GetAssetDescription returns the description of an asset, by ID or alias
+func (c *Client) GetAssetDescription(ctx context.Context, asset string) (AssetDescription, error) {
+	reply := AssetDescription{}
+	err := c.requester.SendRequest(ctx, "xvm.getAssetDescription", &struct {
+		AssetID string `json:"assetID"`
+	}{AssetID: asset}, &reply)
+	return reply, err
+}
+
+// Wallet builds, signs and issues X-Chain txs spending the UTXOs of a
+// keychain
+type Wallet struct {
+	Client  *Client
+	Builder builder.Builder
+	HRP     string
+
+	signer  signer.Signer
+	backend x.Backend
+	utxos   common.UTXOs
+	addrs   []string
+}
+
+// NewWallet returns a wallet of kc on the X-Chain served at baseURL, loaded
+// with the current UTXOs of kc
+func NewWallet(ctx context.Context, baseURL string, kc keychain.Keychain) (*Wallet, error) {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	networkID, err := sdkinfo.NewClient(baseURL).GetNetworkID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the network ID: %w", err)
+	}
+	luxAssetID, err := platformvm.NewClient(baseURL).GetStakingAssetID(ctx, constants.PrimaryNetworkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the LUX asset ID: %w", err)
+	}
+	params := genesis.GetParams(networkID)
+	xctx, err := x.NewContextFromClients(ctx, sdkinfo.NewClient(baseURL), luxAssetID, params.TxFee, params.CreateAssetTxFee)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the X-Chain context: %w", err)
+	}
+
+	hrp := constants.GetHRP(networkID)
+	addrs := kc.Addresses()
+	formatted := make([]string, 0, addrs.Len())
+	for addr := range addrs {
+		s, err := address.Format(builder.Alias, hrp, addr.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		formatted = append(formatted, s)
+	}
+	client := NewClient(baseURL)
+	fetched, err := client.GetUTXOs(ctx, formatted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the X-Chain UTXOs: %w", err)
+	}
+	utxos := common.NewUTXOs()
+	for _, utxo := range fetched {
+		if err := utxos.AddUTXO(ctx, xctx.BlockchainID, xctx.BlockchainID, utxo); err != nil {
+			return nil, err
+		}
+	}
+
+	backend := x.NewBackend(xctx, common.NewChainUTXOs(xctx.BlockchainID, utxos))
+	return &Wallet{
+		Client:  client,
+		Builder: builder.New(addrs, xctx, backend),
+		HRP:     hrp,
+		signer:  signer.New(kc, backend),
+		backend: backend,
+		utxos:   utxos,
+		addrs:   formatted,
+	}, nil
+}
+
+// ImportUTXOs loads the UTXOs of the keychain exported to the X-Chain by
+// sourceChainID, so that Builder.NewImportTx spends them
+func (w *Wallet) ImportUTXOs(ctx context.Context, sourceChainID ids.ID) error {
+	fetched, err := w.Client.GetAtomicUTXOs(ctx, w.addrs, sourceChainID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get the UTXOs exported to the X-Chain: %w", err)
+	}
+	for _, utxo := range fetched {
+		if err := w.utxos.AddUTXO(ctx, sourceChainID, w.Builder.Context().BlockchainID, utxo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UTXOs returns the UTXOs the wallet can spend
+func (w *Wallet) UTXOs(ctx context.Context) ([]*lux.UTXO, error) {
+	return w.backend.UTXOs(ctx, w.Builder.Context().BlockchainID)
+}
+
+// Issue signs utx, issues it and waits for it to be accepted. The UTXOs of
+// the wallet are updated so further txs can be built right away.
+func (w *Wallet) Issue(ctx context.Context, utx txs.UnsignedTx) (*txs.Tx, error) {
+	tx, err := signer.SignUnsigned(ctx, w.signer, utx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign tx: %w", err)
+	}
+	txID, err := w.Client.IssueTx(ctx, tx.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue tx: %w", err)
+	}
+	if err := w.Client.AwaitTxAccepted(ctx, txID); err != nil {
+		return tx, err
+	}
+	return tx, w.backend.AcceptTx(ctx, tx)
+}
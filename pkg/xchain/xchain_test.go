@@ -0,0 +1,132 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package xchain
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/luxfi/formatting"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/wallet/chain/x/builder"
+	lux "github.com/luxfi/utxo"
+	"github.com/luxfi/utxo/secp256k1fx"
+	"github.com/stretchr/testify/require"
+)
+
+// newXVM serves the xvm methods of handlers on /ext/bc/X
+func newXVM(t *testing.T, handlers map[string]func(params json.RawMessage) interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/ext/bc/X", r.URL.Path)
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		handler, ok := handlers[req.Method]
+		require.True(t, ok, "unexpected method %s", req.Method)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": handler(req.Params)})
+	}))
+}
+
+func TestGetUTXOs(t *testing.T) {
+	require := require.New(t)
+	utxo := &lux.UTXO{
+		UTXOID: lux.UTXOID{TxID: ids.GenerateTestID(), OutputIndex: 1},
+		Asset:  lux.Asset{ID: ids.GenerateTestID()},
+		Out: &secp256k1fx.TransferOutput{
+			Amt:          5,
+			OutputOwners: secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{ids.GenerateTestShortID()}},
+		},
+	}
+	b, err := builder.Parser.Codec().Marshal(0, utxo)
+	require.NoError(err)
+	encoded, err := formatting.Encode(formatting.Hex, b)
+	require.NoError(err)
+
+	sourceChain := ""
+	server := newXVM(t, map[string]func(json.RawMessage) interface{}{
+		"xvm.getUTXOs": func(params json.RawMessage) interface{} {
+			var args struct {
+				Addresses   []string `json:"addresses"`
+				SourceChain string   `json:"sourceChain"`
+			}
+			require.NoError(json.Unmarshal(params, &args))
+			require.Equal([]string{"X-local1test"}, args.Addresses)
+			require.Equal(sourceChain, args.SourceChain)
+			return map[string]interface{}{"numFetched": "1", "utxos": []string{encoded}, "encoding": "hex"}
+		},
+	})
+	defer server.Close()
+
+	utxos, err := NewClient(server.URL+"/").GetUTXOs(context.Background(), []string{"X-local1test"})
+	require.NoError(err)
+	require.Len(utxos, 1)
+	require.Equal(utxo.InputID(), utxos[0].InputID())
+	require.Equal(uint64(5), utxos[0].Out.(*secp256k1fx.TransferOutput).Amt)
+
+	sourceChain = "P"
+	utxos, err = NewClient(server.URL).GetAtomicUTXOs(context.Background(), []string{"X-local1test"}, sourceChain)
+	require.NoError(err)
+	require.Len(utxos, 1)
+}
+
+func TestIssueTx(t *testing.T) {
+	require := require.New(t)
+	txID := ids.GenerateTestID()
+	polls := 0
+	server := newXVM(t, map[string]func(json.RawMessage) interface{}{
+		"xvm.issueTx": func(json.RawMessage) interface{} {
+			return map[string]interface{}{"txID": txID}
+		},
+		"xvm.getTxStatus": func(json.RawMessage) interface{} {
+			polls++
+			if polls < 2 {
+				return map[string]interface{}{"status": "Unknown"}
+			}
+			return map[string]interface{}{"status": "Accepted"}
+		},
+	})
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	issued, err := client.IssueTx(context.Background(), []byte{1, 2, 3})
+	require.NoError(err)
+	require.Equal(txID, issued)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(client.AwaitTxAccepted(ctx, txID))
+	require.Equal(2, polls)
+
+	polls = -100
+	ctx, cancel = context.WithTimeout(context.Background(), 2*pollInterval)
+	defer cancel()
+	require.ErrorIs(client.AwaitTxAccepted(ctx, txID), ErrTxNotAccepted)
+}
+
+func TestAwaitTxRejected(t *testing.T) {
+	require := require.New(t)
+	polls := 0
+	server := newXVM(t, map[string]func(json.RawMessage) interface{}{
+		"xvm.getTxStatus": func(json.RawMessage) interface{} {
+			polls++
+			return map[string]interface{}{"status": "Rejected"}
+		},
+	})
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	err := NewClient(server.URL).AwaitTxAccepted(ctx, ids.GenerateTestID())
+	require.ErrorIs(err, ErrTxNotAccepted)
+	require.ErrorContains(err, "Rejected")
+	require.Equal(1, polls)
+	require.NoError(ctx.Err())
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package commands
+
+import (
+	"os"
+
+	"github.com/luxfi/cli/tests/e2e/utils"
+	"github.com/onsi/gomega"
+)
+
+const (
+	// RecordFixturesEnvVar makes fixture checks record the current output
+	// instead of comparing against it. Set it on a known-good run.
+	RecordFixturesEnvVar = "LUX_E2E_RECORD_FIXTURES"
+	// FixturesDirEnvVar overrides the directory fixtures are read from and
+	// recorded to.
+	FixturesDirEnvVar = "LUX_E2E_FIXTURES_DIR"
+
+	defaultFixturesDir = "tests/e2e/assets/fixtures"
+)
+
+func fixturesDir() string {
+	if dir := os.Getenv(FixturesDirEnvVar); dir != "" {
+		return dir
+	}
+	return defaultFixturesDir
+}
+
+func recordingFixtures() bool {
+	return os.Getenv(RecordFixturesEnvVar) != ""
+}
+
+// ExpectOutputFixture checks CLI output against the golden fixture called name
+// and returns the values captured by its placeholders (e.g. "rpc-url").
+//
+// With LUX_E2E_RECORD_FIXTURES set, the normalized output is recorded as the
+// new fixture. Otherwise a missing fixture fails the check. Recorded fixtures
+// can be edited to elide varying parts of the output with utils.ElisionLine.
+func ExpectOutputFixture(name string, output string) utils.FixtureCaptures {
+	path := utils.FixturePath(fixturesDir(), name, utils.FixtureExt)
+	if recordingFixtures() {
+		normalized, captures := utils.NormalizeOutput(output)
+		gomega.Expect(utils.WriteFixture(path, []byte(normalized))).Should(gomega.Succeed())
+		return captures
+	}
+
+	fixture := readFixture(path)
+	captures, err := utils.CompareFixture(string(fixture), output)
+	gomega.Expect(err).Should(gomega.BeNil(), "fixture %s, output:\n%s", path, output)
+	return captures
+}
+
+// ExpectJSONFixture is ExpectOutputFixture for commands run with --json.
+func ExpectJSONFixture(name string, output []byte) utils.FixtureCaptures {
+	path := utils.FixturePath(fixturesDir(), name, utils.JSONFixtureExt)
+	if recordingFixtures() {
+		normalized, captures, err := utils.NormalizeJSON(output)
+		gomega.Expect(err).Should(gomega.BeNil())
+		gomega.Expect(utils.WriteFixture(path, normalized)).Should(gomega.Succeed())
+		return captures
+	}
+
+	fixture := readFixture(path)
+	captures, err := utils.CompareJSONFixture(fixture, output)
+	gomega.Expect(err).Should(gomega.BeNil(), "fixture %s, output:\n%s", path, output)
+	return captures
+}
+
+// readFixture reads the fixture at path, failing when there is none
+func readFixture(path string) []byte {
+	fixture, ok, err := utils.ReadFixture(path)
+	gomega.Expect(err).Should(gomega.BeNil())
+	gomega.Expect(ok).Should(gomega.BeTrue(), "no fixture recorded at %s, set %s to record one", path, RecordFixturesEnvVar)
+	return fixture
+}
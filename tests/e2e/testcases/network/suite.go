@@ -26,15 +26,11 @@ var _ = ginkgo.Describe("[Network]", ginkgo.Ordered, func() {
 	ginkgo.It("can stop and restart a deployed chain", func() {
 		commands.CreateEVMConfig(chainName, utils.EVMGenesisPath)
 		deployOutput := commands.DeployChainLocally(chainName)
-		rpcs, err := utils.ParseRPCsFromOutput(deployOutput)
-		if err != nil {
-			fmt.Println(deployOutput)
-		}
-		gomega.Expect(err).Should(gomega.BeNil())
+		rpcs := commands.ExpectOutputFixture("network/deploy-local", deployOutput).Unique("rpc-url")
 		gomega.Expect(rpcs).Should(gomega.HaveLen(1))
 		rpc := rpcs[0]
 
-		err = utils.SetHardhatRPC(rpc)
+		err := utils.SetHardhatRPC(rpc)
 		gomega.Expect(err).Should(gomega.BeNil())
 
 		// Deploy greeter contract
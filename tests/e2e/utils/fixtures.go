@@ -0,0 +1,248 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// FixtureExt is the extension of golden text fixtures
+	FixtureExt = ".golden"
+	// JSONFixtureExt is the extension of golden JSON fixtures
+	JSONFixtureExt = ".golden.json"
+	// ElisionLine is a text fixture line matching any number of output lines,
+	// for parts of the output that vary in length, e.g. a table per node
+	ElisionLine = "{{...}}"
+)
+
+// fixtureRule replaces values that change between runs with a named placeholder.
+type fixtureRule struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// fixtureRules are applied in order, so more specific patterns come first.
+// An RPC URL must be replaced before the generic URL and ID rules consume it.
+var fixtureRules = []fixtureRule{
+	{"rpc-url", regexp.MustCompile(`https?://[^\s/|]+/ext/bc/[A-Za-z0-9]+/rpc`)},
+	{"url", regexp.MustCompile(`https?://[^\s|"']+`)},
+	{"node-id", regexp.MustCompile(`NodeID-[1-9A-HJ-NP-Za-km-z]{20,}`)},
+	{"timestamp", regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`)},
+	{"hex", regexp.MustCompile(`0x[0-9a-fA-F]{64,}`)},
+	{"eth-address", regexp.MustCompile(`0x[0-9a-fA-F]{40}\b`)},
+	{"address", regexp.MustCompile(`\b[PXC]-[a-z]+1[02-9ac-hj-np-z]{38,}\b`)},
+	{"cb58-id", regexp.MustCompile(`\b[1-9A-HJ-NP-Za-km-z]{48,50}\b`)},
+	{"duration", regexp.MustCompile(`\b\d+(\.\d+)?(ns|µs|ms|s|m|h)\b`)},
+}
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+// FixtureCaptures holds the original values replaced by each placeholder, in
+// the order they appeared in the output.
+type FixtureCaptures map[string][]string
+
+// Unique returns the distinct values captured for name, keeping first-seen order.
+func (c FixtureCaptures) Unique(name string) []string {
+	seen := map[string]struct{}{}
+	values := []string{}
+	for _, v := range c[name] {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		values = append(values, v)
+	}
+	return values
+}
+
+func (c FixtureCaptures) merge(other FixtureCaptures) {
+	for name, values := range other {
+		c[name] = append(c[name], values...)
+	}
+}
+
+// normalizeString replaces volatile values in s with {{name}} placeholders.
+func normalizeString(s string, home string) (string, FixtureCaptures) {
+	captures := FixtureCaptures{}
+	if home != "" {
+		s = strings.ReplaceAll(s, home, "{{home}}")
+	}
+	for _, rule := range fixtureRules {
+		s = rule.re.ReplaceAllStringFunc(s, func(match string) string {
+			captures[rule.name] = append(captures[rule.name], match)
+			return "{{" + rule.name + "}}"
+		})
+	}
+	return s, captures
+}
+
+// NormalizeOutput strips color codes and trailing whitespace from CLI output
+// and replaces values that change between runs (URLs, IDs, addresses,
+// timestamps, durations, the home directory) with placeholders, so the result
+// can be compared against a recorded fixture.
+func NormalizeOutput(output string) (string, FixtureCaptures) {
+	home, _ := os.UserHomeDir()
+	output = ansiEscape.ReplaceAllString(output, "")
+	output = strings.ReplaceAll(output, "\r\n", "\n")
+
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	output = strings.TrimSpace(strings.Join(lines, "\n"))
+
+	return normalizeString(output, home)
+}
+
+// NormalizeJSON normalizes every string value of a JSON document the same way
+// NormalizeOutput does and re-encodes it with sorted keys.
+func NormalizeJSON(data []byte) ([]byte, FixtureCaptures, error) {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("invalid JSON output: %w", err)
+	}
+	home, _ := os.UserHomeDir()
+	captures := FixtureCaptures{}
+	doc = normalizeJSONValue(doc, home, captures)
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, captures, nil
+}
+
+func normalizeJSONValue(v interface{}, home string, captures FixtureCaptures) interface{} {
+	switch t := v.(type) {
+	case string:
+		s, c := normalizeString(t, home)
+		captures.merge(c)
+		return s
+	case []interface{}:
+		for i := range t {
+			t[i] = normalizeJSONValue(t[i], home, captures)
+		}
+		return t
+	case map[string]interface{}:
+		for k := range t {
+			t[k] = normalizeJSONValue(t[k], home, captures)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// CompareFixture compares normalized output against a recorded fixture and
+// returns the captured values. The error points at the first differing line.
+func CompareFixture(fixture string, output string) (FixtureCaptures, error) {
+	normalized, captures := NormalizeOutput(output)
+	if err := diffFixture(strings.TrimSpace(fixture), normalized); err != nil {
+		return captures, err
+	}
+	return captures, nil
+}
+
+// CompareJSONFixture compares normalized JSON output against a recorded fixture.
+func CompareJSONFixture(fixture []byte, output []byte) (FixtureCaptures, error) {
+	normalized, captures, err := NormalizeJSON(output)
+	if err != nil {
+		return nil, err
+	}
+	expected, _, err := NormalizeJSON(fixture)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fixture: %w", err)
+	}
+	if err := diffFixture(string(expected), string(normalized)); err != nil {
+		return captures, err
+	}
+	return captures, nil
+}
+
+func diffFixture(expected string, actual string) error {
+	if expected == actual {
+		return nil
+	}
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+	i, j, ok := matchLines(expectedLines, actualLines)
+	if ok {
+		return nil
+	}
+	var e, a string
+	if i < len(expectedLines) {
+		e = expectedLines[i]
+	}
+	if j < len(actualLines) {
+		a = actualLines[j]
+	}
+	return fmt.Errorf("output differs from fixture at line %d:\n  expected: %q\n  actual:   %q", j+1, e, a)
+}
+
+// matchLines matches actual against the expected lines, where ElisionLine
+// matches any number of lines. When they don't match, it returns the
+// expected and actual lines where matching got the furthest.
+func matchLines(expected []string, actual []string) (int, int, bool) {
+	furthestExpected, furthestActual := 0, 0
+	mismatch := func(i, j int) bool {
+		if i > furthestExpected || i == furthestExpected && j > furthestActual {
+			furthestExpected, furthestActual = i, j
+		}
+		return false
+	}
+	var match func(i, j int) bool
+	match = func(i, j int) bool {
+		for ; i < len(expected); i, j = i+1, j+1 {
+			if expected[i] == ElisionLine {
+				for k := j; k <= len(actual); k++ {
+					if match(i+1, k) {
+						return true
+					}
+				}
+				return false
+			}
+			if j >= len(actual) || expected[i] != actual[j] {
+				return mismatch(i, j)
+			}
+		}
+		return j == len(actual) || mismatch(i, j)
+	}
+	if match(0, 0) {
+		return 0, 0, true
+	}
+	return furthestExpected, furthestActual, false
+}
+
+// FixturePath returns the path of the fixture called name inside dir.
+func FixturePath(dir string, name string, ext string) string {
+	return filepath.Join(dir, filepath.FromSlash(name)+ext)
+}
+
+// ReadFixture reads a recorded fixture. The boolean is false if it does not exist.
+func ReadFixture(path string) ([]byte, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// WriteFixture records a fixture, creating parent directories as needed.
+func WriteFixture(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if len(data) == 0 || data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,102 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const deployOutput = "\x1b[32mBlockchain ready to use\x1b[0m   \n" +
+	"+-------+----------------------------------------------------------------------------+\n" +
+	"| RPC   | http://127.0.0.1:9630/ext/bc/2bcEQ7iT3PjxcZbEPnRtKmh8cNRJoJmWHEpWTxHBgSQf5MKX1c/rpc |\n" +
+	"| Node  | NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg                                   |\n" +
+	"| Time  | 2025-01-02T03:04:05Z                                                       |\n" +
+	"+-------+----------------------------------------------------------------------------+\n"
+
+func TestNormalizeOutput(t *testing.T) {
+	require := require.New(t)
+
+	normalized, captures := NormalizeOutput(deployOutput)
+	require.NotContains(normalized, "\x1b[")
+	require.Contains(normalized, "| RPC   | {{rpc-url}} |")
+	require.Contains(normalized, "| Node  | {{node-id}}")
+	require.Contains(normalized, "| Time  | {{timestamp}}")
+	require.Equal(
+		[]string{"http://127.0.0.1:9630/ext/bc/2bcEQ7iT3PjxcZbEPnRtKmh8cNRJoJmWHEpWTxHBgSQf5MKX1c/rpc"},
+		captures.Unique("rpc-url"),
+	)
+	require.Empty(captures["url"])
+}
+
+func TestCompareFixture(t *testing.T) {
+	require := require.New(t)
+
+	fixture, _ := NormalizeOutput(deployOutput)
+
+	other := "Blockchain ready to use\n" +
+		"+-------+----------------------------------------------------------------------------+\n" +
+		"| RPC   | http://127.0.0.1:9650/ext/bc/2vVAQXDk3V5wmCqm4T5xNpoHqxWSazD2EGmQn7cCf3SBLRXkGx/rpc |\n" +
+		"| Node  | NodeID-P7oB2McjBGgW2NXXWVYjV8JEDFoW9xDE5                                   |\n" +
+		"| Time  | 2025-06-07T08:09:10Z                                                       |\n" +
+		"+-------+----------------------------------------------------------------------------+\n"
+	captures, err := CompareFixture(fixture, other)
+	require.NoError(err)
+	require.Equal(
+		[]string{"http://127.0.0.1:9650/ext/bc/2vVAQXDk3V5wmCqm4T5xNpoHqxWSazD2EGmQn7cCf3SBLRXkGx/rpc"},
+		captures.Unique("rpc-url"),
+	)
+
+	_, err = CompareFixture(fixture, "Blockchain failed to deploy\n")
+	require.ErrorContains(err, "line 1")
+}
+
+func TestCompareFixtureElision(t *testing.T) {
+	require := require.New(t)
+
+	fixture := "Deploying mychain\n" + ElisionLine + "\n✓ Blockchain deployed successfully\n" + ElisionLine
+	output := "Deploying mychain\nConnecting...\n✓ Connecting (0.1s)\n✓ Blockchain deployed successfully\n| node1 | http://127.0.0.1:9630 |\n"
+	_, err := CompareFixture(fixture, output)
+	require.NoError(err)
+	_, err = CompareFixture(fixture, "Deploying mychain\n✓ Blockchain deployed successfully\n")
+	require.NoError(err)
+
+	_, err = CompareFixture(fixture, "Deploying mychain\nConnecting...\n✗ Connecting (0.1s) - FAILED\n")
+	require.ErrorContains(err, "line 4")
+	require.ErrorContains(err, "Blockchain deployed successfully")
+	_, err = CompareFixture("Deploying mychain\n"+ElisionLine+"\nDone", "Deploying mychain\nDone\nextra\n")
+	require.ErrorContains(err, "line 3")
+}
+
+func TestCompareJSONFixture(t *testing.T) {
+	require := require.New(t)
+
+	fixture := []byte(`{"name":"chain","rpc":"{{url}}","validators":["{{node-id}}"]}`)
+	output := []byte(`{"validators":["NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg"],"rpc":"http://127.0.0.1:9630","name":"chain"}`)
+
+	captures, err := CompareJSONFixture(fixture, output)
+	require.NoError(err)
+	require.Equal([]string{"http://127.0.0.1:9630"}, captures["url"])
+
+	_, err = CompareJSONFixture(fixture, []byte(`{"name":"other","rpc":"http://x","validators":[]}`))
+	require.Error(err)
+}
+
+func TestReadWriteFixture(t *testing.T) {
+	require := require.New(t)
+
+	path := FixturePath(t.TempDir(), "network/deploy", FixtureExt)
+	_, ok, err := ReadFixture(path)
+	require.NoError(err)
+	require.False(ok)
+
+	require.NoError(WriteFixture(path, []byte("output")))
+	data, ok, err := ReadFixture(path)
+	require.NoError(err)
+	require.True(ok)
+	require.Equal("output\n", string(data))
+	require.Equal("deploy"+FixtureExt, filepath.Base(path))
+}